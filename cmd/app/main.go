@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"smlaicloudplatform/internal/apikeyservice"
+	"smlaicloudplatform/internal/audit"
 	"smlaicloudplatform/internal/authentication"
 	"smlaicloudplatform/internal/channel/salechannel"
 	"smlaicloudplatform/internal/channel/transportchannel"
@@ -19,8 +20,10 @@ import (
 	"smlaicloudplatform/internal/debtaccount/debtorgroup"
 	"smlaicloudplatform/internal/dimension"
 	"smlaicloudplatform/internal/documentwarehouse/documentimage"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	"smlaicloudplatform/internal/filestatus"
 	"smlaicloudplatform/internal/images"
+	"smlaicloudplatform/internal/loginguard"
 	"smlaicloudplatform/internal/masterexpense"
 	"smlaicloudplatform/internal/masterincome"
 	"smlaicloudplatform/internal/mastersync"
@@ -49,6 +52,7 @@ import (
 	"smlaicloudplatform/internal/productsection/sectionbranch"
 	"smlaicloudplatform/internal/productsection/sectionbusinesstype"
 	"smlaicloudplatform/internal/productsection/sectiondepartment"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/internal/report/reportquerym"
 	"smlaicloudplatform/internal/slipimage"
 	"smlaicloudplatform/internal/stockbalanceimport"
@@ -118,6 +122,13 @@ import (
 func main() {
 
 	cfg := config.NewConfig()
+
+	// debtor, qr payment, webhook and two factor store secrets encrypted by the master key
+	if err := datakey.ValidateMasterKey(cfg.EncryptionConfig()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	ms, err := microservice.NewMicroservice(cfg)
 	if err != nil {
 		panic(err)
@@ -130,6 +141,7 @@ func main() {
 	cacher := ms.Cacher(cfg.CacherConfig())
 	// jwtService := microservice.NewJwtService(cacher, cfg.JwtSecretKey(), 24*3)
 	authService := microservice.NewAuthService(cacher, 24*3*time.Hour, 24*30*time.Hour)
+	authService.SetApiKeyResolver(apikeyservice.NewApiKeyResolver(ms, cfg))
 
 	publicPath := []string{
 		"/slip/*",
@@ -174,6 +186,14 @@ func main() {
 		"/list-shop",
 		"/select-shop",
 		"/create-shop",
+		"/2fa",
+		"/2fa/enroll",
+		"/2fa/enable",
+		"/2fa/disable",
+		"/2fa/recovery-codes",
+		"/sessions",
+		"/sessions/revoke",
+		"/sessions/revoke-all",
 	}
 
 	ms.HttpPreRemoveTrailingSlash()
@@ -189,6 +209,9 @@ func main() {
 		authentication.NewAuthenticationHttp(ms, cfg),
 		shop.NewShopHttp(ms, cfg),
 		shop.NewShopMemberHttp(ms, cfg),
+		rbac.NewRbacHttp(ms, cfg),
+		audit.NewAuditHttp(ms, cfg),
+		loginguard.NewSecurityEventHttp(ms, cfg),
 		member.NewMemberHttp(ms, cfg),
 		employee.NewEmployeeHttp(ms, cfg),
 
@@ -218,6 +241,7 @@ func main() {
 
 		paymentmaster.NewPaymentMasterHttp(ms, cfg),
 		apikeyservice.NewApiKeyServiceHttp(ms, cfg),
+		apikeyservice.NewApiKeyHttp(ms, cfg),
 
 		smstransaction.NewSmsTransactionHttp(ms, cfg),
 		smspatterns.NewSmsPatternsHttp(ms, cfg),
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"os"
 	"smlaicloudplatform/internal/authentication"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
@@ -10,6 +13,13 @@ import (
 func main() {
 
 	cfg := config.NewConfig()
+
+	// two factor secrets are encrypted by the master key
+	if err := datakey.ValidateMasterKey(cfg.EncryptionConfig()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	ms, err := microservice.NewMicroservice(cfg)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	auditmodels "smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/config"
+	debtorModels "smlaicloudplatform/internal/debtaccount/debtor/models"
+	"smlaicloudplatform/internal/encrypt/datakey"
+	qrpaymentModels "smlaicloudplatform/internal/payment/qrpayment/models"
+	webhookModels "smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+var (
+	shopID = flag.String("shopid", "", "encrypt documents of the shop only, all shops when it is empty")
+	dryRun = flag.Bool("dryrun", false, "count documents which have plain secrets without updating them")
+)
+
+// encryptsecrets encrypt secrets of documents which are written before ENCRYPTION_MASTER_KEY is configured
+// and redact the secrets in their audit logs, it can be run again, encrypted and redacted values are skipped
+func main() {
+	flag.Parse()
+
+	cfg := config.NewConfig()
+	if err := datakey.ValidateMasterKey(cfg.EncryptionConfig()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ms, err := microservice.NewMicroservice(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	encryptor := datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig())
+
+	collections := []struct {
+		name  string
+		model microservice.MongoModel
+	}{
+		{"qr payment", &qrpaymentModels.QrPaymentDoc{}},
+		{"debtor", &debtorModels.DebtorDoc{}},
+		{"webhook", &webhookModels.WebhookDoc{}},
+	}
+
+	for _, collection := range collections {
+		fields := auditmodels.SecretPaths(collection.model)
+
+		count, err := datakey.EncryptCollectionSecrets(context.Background(), pst, encryptor, collection.model, fields, *shopID, *dryRun)
+		if err != nil {
+			fmt.Printf("Encrypt %s error :: %s\n", collection.name, err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf("Encrypt %s :: %d documents\n", collection.name, count)
+
+		count, err = datakey.ScrubAuditSecrets(context.Background(), pst, collection.model.CollectionName(), fields, *shopID, *dryRun)
+		if err != nil {
+			fmt.Printf("Scrub audit logs of %s error :: %s\n", collection.name, err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf("Scrub audit logs of %s :: %d audit logs\n", collection.name, count)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/images"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
@@ -23,6 +24,7 @@ func main() {
 	cacher := ms.Cacher(cfg.CacherConfig())
 	authService := microservice.NewAuthService(cacher, 24*3*time.Hour, 24*30*time.Hour)
 	ms.HttpMiddleware(authService.MWFuncWithRedis(cacher, publicPath...))
+	rbac.InitPermissionService(ms, cfg)
 
 	filePersister := microservice.NewPersisterFile(config.NewStorageFileConfig())
 	imagePersister := microservice.NewPersisterImage(filePersister)
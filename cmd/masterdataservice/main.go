@@ -4,6 +4,7 @@ import (
 	"smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/member"
 	"smlaicloudplatform/internal/product/productcategory"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
@@ -19,6 +20,7 @@ func main() {
 	cacher := ms.Cacher(cfg.CacherConfig())
 	authService := microservice.NewAuthService(cacher, 24*3*time.Hour, 24*30*time.Hour)
 	ms.HttpMiddleware(authService.MWFuncWithRedis(cacher))
+	rbac.InitPermissionService(ms, cfg)
 
 	categoryHttp := productcategory.NewProductCategoryHttp(ms, cfg)
 	categoryHttp.RegisterHttp()
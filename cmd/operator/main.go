@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/systemadmin/operatoradmin"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+var (
+	action   = flag.String("action", "create", "create, password, enable or disable")
+	username = flag.String("username", "", "username of the operator")
+	name     = flag.String("name", "", "name of the operator when it is created")
+)
+
+// operator manage system administrator accounts which login to /systemadm routes,
+// password is read from OPERATOR_PASSWORD so it is not kept in shell history
+func main() {
+	flag.Parse()
+
+	if *username == "" {
+		fmt.Println("username is required")
+		os.Exit(1)
+	}
+
+	password := os.Getenv("OPERATOR_PASSWORD")
+	if (*action == "create" || *action == "password") && password == "" {
+		fmt.Println("OPERATOR_PASSWORD is not configured")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	ms, err := microservice.NewMicroservice(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	err = operatoradmin.MigrationDatabase(ms, cfg)
+	if err != nil {
+		fmt.Printf("Migrate operator error :: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	svc := operatoradmin.InitOperatorService(ms, cfg)
+
+	switch *action {
+	case "create":
+		err = svc.CreateOperator(context.Background(), *username, *name, password)
+	case "password":
+		err = svc.SetPassword(context.Background(), *username, password)
+	case "enable":
+		err = svc.SetActive(context.Background(), *username, true)
+	case "disable":
+		err = svc.SetActive(context.Background(), *username, false)
+	default:
+		fmt.Printf("action %s is invalid\n", *action)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Printf("Operator %s error :: %s\n", *action, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Operator %s :: %s\n", *action, *username)
+}
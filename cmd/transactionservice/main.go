@@ -2,6 +2,7 @@ package main
 
 import (
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/internal/transaction/purchase"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
@@ -17,6 +18,7 @@ func main() {
 	cacher := ms.Cacher(cfg.CacherConfig())
 	authService := microservice.NewAuthService(cacher, 24*3*time.Hour, 24*30*time.Hour)
 	ms.HttpMiddleware(authService.MWFuncWithRedis(cacher))
+	rbac.InitPermissionService(ms, cfg)
 
 	purchase := purchase.NewPurchaseHttp(ms, cfg)
 	purchase.RegisterHttp()
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
 	"smlaicloudplatform/internal/authentication"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	"smlaicloudplatform/internal/vfgl/journal"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
@@ -14,6 +17,13 @@ import (
 func main() {
 
 	cfg := config.NewConfig()
+
+	// two factor secrets are encrypted by the master key
+	if err := datakey.ValidateMasterKey(cfg.EncryptionConfig()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	ms, err := microservice.NewMicroservice(cfg)
 	if err != nil {
 		panic(err)
@@ -16,6 +16,7 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/jellydator/ttlcache/v3 v3.0.1
 	github.com/opensearch-project/opensearch-go v1.1.0
+	github.com/prometheus/client_golang v1.15.0
 	github.com/shopspring/decimal v1.3.1
 	github.com/smlsoft/mongopagination v0.0.2
 	github.com/stretchr/testify v1.8.2
@@ -79,7 +80,6 @@ require (
 	github.com/pierrec/lz4/v4 v4.1.17 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/prometheus/client_golang v1.15.0 // indirect
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.42.0 // indirect
 	github.com/prometheus/procfs v0.9.0 // indirect
@@ -0,0 +1,228 @@
+package apikeyservice
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	"smlaicloudplatform/internal/apikeyservice/repositories"
+	"smlaicloudplatform/internal/apikeyservice/services"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/memorycache"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// NewApiKeyResolver return resolver of managed api keys for AuthService.SetApiKeyResolver
+func NewApiKeyResolver(ms *microservice.Microservice, cfg config.IConfig) *services.ApiKeyResolver {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	return services.NewApiKeyResolver(repositories.NewApiKeyRepository(pst), memorycache.NewMemoryCache(), ms.Logger, ms.TimeNow)
+}
+
+type IApiKeyHttp interface{}
+
+type ApiKeyHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.IApiKeyHttpService
+}
+
+func NewApiKeyHttp(ms *microservice.Microservice, cfg config.IConfig) ApiKeyHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	repo := repositories.NewApiKeyRepository(pst)
+	svc := services.NewApiKeyHttpService(repo, rbac.InitPermissionService(ms, cfg), ms.TimeNow)
+
+	return ApiKeyHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h ApiKeyHttp) RegisterHttp() {
+	apiKeyRead := h.ms.RequirePermission(rbacmodels.PermissionApiKeyRead)
+	apiKeyUpdate := h.ms.RequirePermission(rbacmodels.PermissionApiKeyUpdate)
+
+	h.ms.GET("/apikey", h.SearchApiKeyPage, apiKeyRead)
+	h.ms.POST("/apikey", h.CreateApiKey, apiKeyUpdate)
+	h.ms.GET("/apikey/:id", h.InfoApiKey, apiKeyRead)
+	h.ms.POST("/apikey/:id/rotate", h.RotateApiKey, apiKeyUpdate)
+	h.ms.DELETE("/apikey/:id", h.RevokeApiKey, apiKeyUpdate)
+}
+
+// Create Api Key godoc
+// @Description Create api key of the shop, the key is returned only once
+// @Tags		ApiKey
+// @Param		ApiKey  body      models.ApiKey  true  "ApiKey"
+// @Accept 		json
+// @Success		201	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /apikey [post]
+func (h ApiKeyHttp) CreateApiKey(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	docReq := &models.ApiKey{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	doc, err := h.svc.CreateApiKey(ctx.Context(), userInfo.ShopID, userInfo.Username, *docReq)
+
+	if errors.Is(err, services.ErrApiKeyScopeDenied) {
+		ctx.ResponseError(http.StatusForbidden, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      doc.GuidFixed,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Rotate Api Key godoc
+// @Description Replace the key, the old key stop working and the new key is returned only once
+// @Tags		ApiKey
+// @Param		id  path      string  true  "Api Key ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /apikey/{id}/rotate [post]
+func (h ApiKeyHttp) RotateApiKey(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	doc, err := h.svc.RotateApiKey(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err == services.ErrApiKeyNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      doc.GuidFixed,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Revoke Api Key godoc
+// @Description Revoke Api Key
+// @Tags		ApiKey
+// @Param		id  path      string  true  "Api Key ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /apikey/{id} [delete]
+func (h ApiKeyHttp) RevokeApiKey(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.RevokeApiKey(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err == services.ErrApiKeyNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Get Api Key godoc
+// @Description get Api Key info by guidfixed
+// @Tags		ApiKey
+// @Param		id  path      string  true  "Api Key ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /apikey/{id} [get]
+func (h ApiKeyHttp) InfoApiKey(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoApiKey(ctx.Context(), shopID, id)
+
+	if err == services.ErrApiKeyNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %s: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, "document not found")
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List Api Key godoc
+// @Description List Api Keys of the shop
+// @Tags		ApiKey
+// @Param		q		query	string		false  "Search Value"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /apikey [get]
+func (h ApiKeyHttp) SearchApiKeyPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchApiKey(ctx.Context(), shopID, map[string]interface{}{}, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
@@ -0,0 +1,34 @@
+package apikeyservice
+
+import (
+	"context"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create unique index of key hash which authenticate request and index to list keys of the shop
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.ApiKeyDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "keyhash", Value: 1}},
+			Options: options.Index().SetName("apikey_keyhash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "guidfixed", Value: 1}},
+			Options: options.Index().SetName("apikey_shopid_guidfixed"),
+		},
+	})
+	return err
+}
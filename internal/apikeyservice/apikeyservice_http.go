@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/apikeyservice/services"
 	"smlaicloudplatform/internal/config"
 	common "smlaicloudplatform/internal/models"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
@@ -35,8 +36,10 @@ func NewApiKeyServiceHttp(ms *microservice.Microservice, cfg config.IConfig) Api
 
 func (h ApiKeyServiceHttp) RegisterHttp() {
 
-	h.ms.POST("/apikeyservice", h.CreateApiKey)
-	h.ms.DELETE("/apikeyservice", h.RemoveApiKey)
+	apiKeyUpdate := h.ms.RequirePermission(rbacmodels.PermissionApiKeyUpdate)
+
+	h.ms.POST("/apikeyservice", h.CreateApiKey, apiKeyUpdate)
+	h.ms.DELETE("/apikeyservice", h.RemoveApiKey, apiKeyUpdate)
 }
 
 // X Api key generate
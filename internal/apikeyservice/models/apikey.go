@@ -0,0 +1,70 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const apiKeyCollectionName = "apiKeys"
+
+// ApiKey is managed api key of machine integration, it is sent in x-api-key header
+type ApiKey struct {
+	Name string `json:"name" bson:"name" validate:"required,min=1,max=255"`
+	// ExpiredAt is nil when the key never expire
+	ExpiredAt *time.Time `json:"expiredat" bson:"expiredat,omitempty"`
+	// AllowedIPs is ip or cidr e.g. 203.0.113.10 or 10.0.0.0/8, empty allow every ip
+	AllowedIPs []string `json:"allowedips" bson:"allowedips"`
+	// Scopes is permissions of the key e.g. transaction.saleinvoice:read or transaction.*:*
+	Scopes []string `json:"scopes" bson:"scopes" validate:"required,min=1"`
+}
+
+type ApiKeyInfo struct {
+	models.DocIdentity `bson:"inline"`
+	ApiKey             `bson:"inline"`
+	// KeyPrefix is first characters of the key to tell keys apart, the key is shown only when it is created or rotated
+	KeyPrefix  string     `json:"keyprefix" bson:"keyprefix"`
+	LastUsedAt *time.Time `json:"lastusedat" bson:"lastusedat,omitempty"`
+	RevokedAt  *time.Time `json:"revokedat" bson:"revokedat,omitempty"`
+	RevokedBy  string     `json:"revokedby" bson:"revokedby,omitempty"`
+	CreatedBy  string     `json:"createdby" bson:"createdby"`
+	CreatedAt  time.Time  `json:"createdat" bson:"createdat"`
+}
+
+func (ApiKeyInfo) CollectionName() string {
+	return apiKeyCollectionName
+}
+
+type ApiKeyData struct {
+	models.ShopIdentity `bson:"inline"`
+	ApiKeyInfo          `bson:"inline"`
+}
+
+type ApiKeyDoc struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ApiKeyData `bson:"inline"`
+	// KeyHash is sha256 of the key, the key itself is not stored
+	KeyHash   string    `json:"-" bson:"keyhash"`
+	UpdatedBy string    `json:"-" bson:"updatedby,omitempty"`
+	UpdatedAt time.Time `json:"-" bson:"updatedat,omitempty"`
+}
+
+func (ApiKeyDoc) CollectionName() string {
+	return apiKeyCollectionName
+}
+
+// IsActive return false when the key is revoked or expired
+func (doc ApiKeyDoc) IsActive(now time.Time) bool {
+	if doc.RevokedAt != nil {
+		return false
+	}
+
+	return doc.ExpiredAt == nil || now.Before(*doc.ExpiredAt)
+}
+
+// ApiKeyCreated is response of create and rotate, ApiKey is not able to be read again
+type ApiKeyCreated struct {
+	GuidFixed string `json:"guidfixed"`
+	ApiKey    string `json:"apikey"`
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IApiKeyRepository interface {
+	Create(ctx context.Context, doc models.ApiKeyDoc) (string, error)
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.ApiKeyDoc, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.ApiKeyInfo, mongopagination.PaginationData, error)
+	FindByKeyHash(ctx context.Context, keyHash string) (models.ApiKeyDoc, error)
+	UpdateKeyHash(ctx context.Context, shopID string, guid string, keyHash string, keyPrefix string, username string, updatedAt time.Time) error
+	Revoke(ctx context.Context, shopID string, guid string, username string, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, keyHash string, lastUsedAt time.Time) error
+}
+
+type ApiKeyRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.CrudRepository[models.ApiKeyDoc]
+	repositories.SearchRepository[models.ApiKeyInfo]
+}
+
+func NewApiKeyRepository(pst microservice.IPersisterMongo) *ApiKeyRepository {
+
+	insRepo := &ApiKeyRepository{
+		pst: pst,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.ApiKeyDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.ApiKeyInfo](pst)
+
+	return insRepo
+}
+
+// FindByKeyHash find the key of any shop, it is used to authenticate request
+func (repo ApiKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (models.ApiKeyDoc, error) {
+	doc := models.ApiKeyDoc{}
+	err := repo.pst.FindOne(microservice.WithSystemAdmin(ctx), &models.ApiKeyDoc{}, bson.M{"keyhash": keyHash}, &doc)
+	if err != nil {
+		return models.ApiKeyDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo ApiKeyRepository) UpdateKeyHash(ctx context.Context, shopID string, guid string, keyHash string, keyPrefix string, username string, updatedAt time.Time) error {
+	return repo.pst.Update(ctx, &models.ApiKeyDoc{}, bson.M{"shopid": shopID, "guidfixed": guid}, bson.M{
+		"$set": bson.M{
+			"keyhash":   keyHash,
+			"keyprefix": keyPrefix,
+			"updatedby": username,
+			"updatedat": updatedAt,
+		},
+	})
+}
+
+func (repo ApiKeyRepository) Revoke(ctx context.Context, shopID string, guid string, username string, revokedAt time.Time) error {
+	return repo.pst.Update(ctx, &models.ApiKeyDoc{}, bson.M{"shopid": shopID, "guidfixed": guid}, bson.M{
+		"$set": bson.M{
+			"revokedby": username,
+			"revokedat": revokedAt,
+		},
+	})
+}
+
+func (repo ApiKeyRepository) UpdateLastUsed(ctx context.Context, keyHash string, lastUsedAt time.Time) error {
+	return repo.pst.Update(microservice.WithSystemAdmin(ctx), &models.ApiKeyDoc{}, bson.M{"keyhash": keyHash}, bson.M{
+		"$set": bson.M{"lastusedat": lastUsedAt},
+	})
+}
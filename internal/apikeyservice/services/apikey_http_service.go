@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	"smlaicloudplatform/internal/apikeyservice/repositories"
+	"smlaicloudplatform/internal/encrypt"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"strings"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApiKeyPrefix mark managed api key, x-api-key without it is looked up as token in cache
+const ApiKeyPrefix = "smk_"
+
+const apiKeyDisplayLength = 12
+
+var (
+	ErrApiKeyNotFound    = errors.New("api key not found")
+	ErrApiKeyRevoked     = errors.New("api key is revoked")
+	ErrApiKeyExpired     = errors.New("api key is expired")
+	ErrApiKeyIPDenied    = errors.New("api key is not allowed from this ip")
+	ErrApiKeyScopeDenied = errors.New("api key scope is more than permissions of the user")
+)
+
+type IApiKeyHttpService interface {
+	CreateApiKey(ctx context.Context, shopID string, authUsername string, doc models.ApiKey) (models.ApiKeyCreated, error)
+	RotateApiKey(ctx context.Context, shopID string, guid string, authUsername string) (models.ApiKeyCreated, error)
+	RevokeApiKey(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoApiKey(ctx context.Context, shopID string, guid string) (models.ApiKeyInfo, error)
+	SearchApiKey(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ApiKeyInfo, mongopagination.PaginationData, error)
+}
+
+type ApiKeyHttpService struct {
+	repo           repositories.IApiKeyRepository
+	permission     microservice.IPermissionChecker
+	encrypt        *encrypt.Encrypt
+	timeNow        func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewApiKeyHttpService(repo repositories.IApiKeyRepository, permission microservice.IPermissionChecker, timeNow func() time.Time) *ApiKeyHttpService {
+	return &ApiKeyHttpService{
+		repo:           repo,
+		permission:     permission,
+		encrypt:        encrypt.NewEncrypt(),
+		timeNow:        timeNow,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc ApiKeyHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// CreateApiKey create the key of the shop, the key act as authUsername limited by scopes
+// so authUsername must have every permission in scopes
+func (svc ApiKeyHttpService) CreateApiKey(ctx context.Context, shopID string, authUsername string, doc models.ApiKey) (models.ApiKeyCreated, error) {
+
+	err := validateAllowedIPs(doc.AllowedIPs)
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	if doc.ExpiredAt != nil && !doc.ExpiredAt.After(svc.timeNow()) {
+		return models.ApiKeyCreated{}, errors.New("expiredat must be in the future")
+	}
+
+	err = svc.validateScopes(ctx, shopID, authUsername, doc.Scopes)
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	apiKey, err := generateApiKey()
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	docData := models.ApiKeyDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.ApiKey = doc
+	docData.KeyHash = svc.encrypt.GenerateSHA256Hash(apiKey)
+	docData.KeyPrefix = apiKey[:apiKeyDisplayLength]
+
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = svc.timeNow()
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	_, err = svc.repo.Create(ctx, docData)
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	return models.ApiKeyCreated{GuidFixed: newGuidFixed, ApiKey: apiKey}, nil
+}
+
+// RotateApiKey replace the key and keep name, scopes and allowed ips, replica which has resolved the old key
+// keep accepting it until cache expire of ApiKeyResolver (10 seconds)
+func (svc ApiKeyHttpService) RotateApiKey(ctx context.Context, shopID string, guid string, authUsername string) (models.ApiKeyCreated, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.findActive(ctx, shopID, guid)
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	apiKey, err := generateApiKey()
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	err = svc.repo.UpdateKeyHash(ctx, shopID, findDoc.GuidFixed, svc.encrypt.GenerateSHA256Hash(apiKey), apiKey[:apiKeyDisplayLength], authUsername, svc.timeNow())
+	if err != nil {
+		return models.ApiKeyCreated{}, err
+	}
+
+	return models.ApiKeyCreated{GuidFixed: findDoc.GuidFixed, ApiKey: apiKey}, nil
+}
+
+// RevokeApiKey revoke the key, replica which has resolved the key keep accepting it until cache expire of ApiKeyResolver
+func (svc ApiKeyHttpService) RevokeApiKey(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrApiKeyNotFound
+	}
+
+	if findDoc.RevokedAt != nil {
+		return nil
+	}
+
+	return svc.repo.Revoke(ctx, shopID, guid, authUsername, svc.timeNow())
+}
+
+func (svc ApiKeyHttpService) InfoApiKey(ctx context.Context, shopID string, guid string) (models.ApiKeyInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return models.ApiKeyInfo{}, err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return models.ApiKeyInfo{}, ErrApiKeyNotFound
+	}
+
+	return findDoc.ApiKeyInfo, nil
+}
+
+func (svc ApiKeyHttpService) SearchApiKey(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ApiKeyInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"name",
+		"keyprefix",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+	if err != nil {
+		return []models.ApiKeyInfo{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+func (svc ApiKeyHttpService) findActive(ctx context.Context, shopID string, guid string) (models.ApiKeyDoc, error) {
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return models.ApiKeyDoc{}, err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return models.ApiKeyDoc{}, ErrApiKeyNotFound
+	}
+
+	if findDoc.RevokedAt != nil {
+		return models.ApiKeyDoc{}, ErrApiKeyRevoked
+	}
+
+	return findDoc, nil
+}
+
+func (svc ApiKeyHttpService) validateScopes(ctx context.Context, shopID string, authUsername string, scopes []string) error {
+	userInfo := micromodels.UserInfo{ShopID: shopID, Username: authUsername}
+
+	for _, scope := range scopes {
+		_, err := rbacmodels.ParsePermission(scope)
+		if err != nil {
+			return err
+		}
+
+		allowed, err := svc.permission.HasPermission(ctx, userInfo, scope)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return fmt.Errorf("%w: %s", ErrApiKeyScopeDenied, scope)
+		}
+	}
+
+	return nil
+}
+
+func validateAllowedIPs(allowedIPs []string) error {
+	for _, allowedIP := range allowedIPs {
+		if strings.Contains(allowedIP, "/") {
+			if _, _, err := net.ParseCIDR(allowedIP); err != nil {
+				return fmt.Errorf("allowed ip %s is invalid", allowedIP)
+			}
+			continue
+		}
+
+		if net.ParseIP(allowedIP) == nil {
+			return fmt.Errorf("allowed ip %s is invalid", allowedIP)
+		}
+	}
+	return nil
+}
+
+// IsAllowedIP return true when ip is in allowed ips, empty allowed ips allow every ip
+func IsAllowedIP(allowedIPs []string, ip string) bool {
+	if len(allowedIPs) == 0 {
+		return true
+	}
+
+	remoteIP := net.ParseIP(ip)
+	if remoteIP == nil {
+		return false
+	}
+
+	for _, allowedIP := range allowedIPs {
+		if strings.Contains(allowedIP, "/") {
+			_, ipNet, err := net.ParseCIDR(allowedIP)
+			if err == nil && ipNet.Contains(remoteIP) {
+				return true
+			}
+			continue
+		}
+
+		if allowed := net.ParseIP(allowedIP); allowed != nil && allowed.Equal(remoteIP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func generateApiKey() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return ApiKeyPrefix + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	"smlaicloudplatform/internal/apikeyservice/repositories"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/memorycache"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"strings"
+	"time"
+)
+
+var errApiKeyInvalid = errors.New("Token Invalid.")
+
+// ApiKeyResolver resolve managed api key for AuthService, found key is cached in this replica for cache expire
+// so revoke and rotate on other replica take effect within it
+type ApiKeyResolver struct {
+	repo           repositories.IApiKeyRepository
+	cache          memorycache.IMemoryCache
+	encrypt        *encrypt.Encrypt
+	logger         logger.ILogger
+	timeNow        func() time.Time
+	cacheExpire    time.Duration
+	lastUsedPeriod time.Duration
+	contextTimeout time.Duration
+}
+
+func NewApiKeyResolver(repo repositories.IApiKeyRepository, cache memorycache.IMemoryCache, logger logger.ILogger, timeNow func() time.Time) *ApiKeyResolver {
+	return &ApiKeyResolver{
+		repo:           repo,
+		cache:          cache,
+		encrypt:        encrypt.NewEncrypt(),
+		logger:         logger,
+		timeNow:        timeNow,
+		cacheExpire:    10 * time.Second,
+		lastUsedPeriod: time.Minute,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+// ResolveApiKey return user info of the user who create the key with scopes of the key,
+// last used time is recorded at most once per lastUsedPeriod
+func (r *ApiKeyResolver) ResolveApiKey(apiKey string, remoteIP string) (micromodels.UserInfo, bool, error) {
+	if !strings.HasPrefix(apiKey, ApiKeyPrefix) {
+		return micromodels.UserInfo{}, false, nil
+	}
+
+	keyHash := r.encrypt.GenerateSHA256Hash(apiKey)
+
+	doc, err := r.findByKeyHash(keyHash)
+	if err != nil {
+		r.logger.Errorf("Api key cannot be resolved: %v", err)
+		return micromodels.UserInfo{}, true, errApiKeyInvalid
+	}
+
+	if doc.KeyHash == "" {
+		return micromodels.UserInfo{}, true, errApiKeyInvalid
+	}
+
+	now := r.timeNow()
+
+	if doc.RevokedAt != nil {
+		return micromodels.UserInfo{}, true, ErrApiKeyRevoked
+	}
+
+	if !doc.IsActive(now) {
+		return micromodels.UserInfo{}, true, ErrApiKeyExpired
+	}
+
+	if !IsAllowedIP(doc.AllowedIPs, remoteIP) {
+		return micromodels.UserInfo{}, true, ErrApiKeyIPDenied
+	}
+
+	if doc.LastUsedAt == nil || now.Sub(*doc.LastUsedAt) >= r.lastUsedPeriod {
+		doc.LastUsedAt = &now
+		r.cache.Set(keyHash, doc, r.cacheExpire)
+
+		go r.updateLastUsed(keyHash, now)
+	}
+
+	return micromodels.UserInfo{
+		Username: doc.CreatedBy,
+		Name:     doc.Name,
+		ShopID:   doc.ShopID,
+		ApiKeyID: doc.GuidFixed,
+		Scopes:   doc.Scopes,
+	}, true, nil
+}
+
+func (r *ApiKeyResolver) findByKeyHash(keyHash string) (models.ApiKeyDoc, error) {
+	if cached, ok := r.cache.Get(keyHash); ok {
+		return cached.(models.ApiKeyDoc), nil
+	}
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), r.contextTimeout)
+	defer ctxCancel()
+
+	doc, err := r.repo.FindByKeyHash(ctx, keyHash)
+	if err != nil {
+		return models.ApiKeyDoc{}, err
+	}
+
+	if doc.KeyHash != "" {
+		r.cache.Set(keyHash, doc, r.cacheExpire)
+	}
+
+	return doc, nil
+}
+
+func (r *ApiKeyResolver) updateLastUsed(keyHash string, lastUsedAt time.Time) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), r.contextTimeout)
+	defer ctxCancel()
+
+	err := r.repo.UpdateLastUsed(ctx, keyHash, lastUsedAt)
+	if err != nil {
+		r.logger.Errorf("Cannot update last used of api key: %v", err)
+	}
+}
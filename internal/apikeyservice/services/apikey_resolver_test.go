@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"smlaicloudplatform/internal/apikeyservice/models"
+	"smlaicloudplatform/internal/apikeyservice/repositories"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/memorycache"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryApiKeyRepository struct {
+	repositories.IApiKeyRepository
+	mutex    sync.Mutex
+	docs     map[string]models.ApiKeyDoc
+	lastUsed chan time.Time
+}
+
+func (repo *memoryApiKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (models.ApiKeyDoc, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	return repo.docs[keyHash], nil
+}
+
+func (repo *memoryApiKeyRepository) UpdateLastUsed(ctx context.Context, keyHash string, lastUsedAt time.Time) error {
+	repo.mutex.Lock()
+	doc := repo.docs[keyHash]
+	doc.LastUsedAt = &lastUsedAt
+	repo.docs[keyHash] = doc
+	repo.mutex.Unlock()
+
+	repo.lastUsed <- lastUsedAt
+	return nil
+}
+
+func newTestApiKey(apiKey string, mutate func(doc *models.ApiKeyDoc)) (string, models.ApiKeyDoc) {
+	doc := models.ApiKeyDoc{ID: primitive.NewObjectID()}
+	doc.ShopID = "SHOP01"
+	doc.GuidFixed = "KEY01"
+	doc.Name = "accounting exporter"
+	doc.Scopes = []string{"transaction.saleinvoice:read"}
+	doc.CreatedBy = "owner"
+	doc.KeyHash = encrypt.NewEncrypt().GenerateSHA256Hash(apiKey)
+	if mutate != nil {
+		mutate(&doc)
+	}
+	return doc.KeyHash, doc
+}
+
+func TestApiKeyResolver(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+
+	repo := &memoryApiKeyRepository{docs: map[string]models.ApiKeyDoc{}, lastUsed: make(chan time.Time, 10)}
+	for apiKey, mutate := range map[string]func(doc *models.ApiKeyDoc){
+		"smk_active":  func(doc *models.ApiKeyDoc) { doc.AllowedIPs = []string{"10.0.0.0/8", "203.0.113.10"} },
+		"smk_revoked": func(doc *models.ApiKeyDoc) { doc.RevokedAt = &past },
+		"smk_expired": func(doc *models.ApiKeyDoc) { doc.ExpiredAt = &past },
+	} {
+		keyHash, doc := newTestApiKey(apiKey, mutate)
+		repo.docs[keyHash] = doc
+	}
+
+	resolver := NewApiKeyResolver(repo, memorycache.NewMemoryCache(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+
+	userInfo, found, err := resolver.ResolveApiKey("smk_active", "10.1.2.3")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "owner", userInfo.Username)
+	assert.Equal(t, "SHOP01", userInfo.ShopID)
+	assert.Equal(t, "KEY01", userInfo.ApiKeyID)
+	assert.Equal(t, []string{"transaction.saleinvoice:read"}, userInfo.Scopes)
+	assert.Equal(t, now, <-repo.lastUsed)
+
+	// last used is recorded once per period
+	_, _, err = resolver.ResolveApiKey("smk_active", "203.0.113.10")
+	assert.Nil(t, err)
+	assert.Len(t, repo.lastUsed, 0)
+
+	_, found, err = resolver.ResolveApiKey("smk_active", "192.168.1.1")
+	assert.True(t, found)
+	assert.Equal(t, ErrApiKeyIPDenied, err)
+
+	_, _, err = resolver.ResolveApiKey("smk_revoked", "10.1.2.3")
+	assert.Equal(t, ErrApiKeyRevoked, err)
+
+	_, _, err = resolver.ResolveApiKey("smk_expired", "10.1.2.3")
+	assert.Equal(t, ErrApiKeyExpired, err)
+
+	_, found, err = resolver.ResolveApiKey("smk_unknown", "10.1.2.3")
+	assert.True(t, found)
+	assert.NotNil(t, err)
+
+	_, found, err = resolver.ResolveApiKey("0f1e2d3c", "10.1.2.3")
+	assert.False(t, found, "x-api-key token in cache is not managed key")
+	assert.Nil(t, err)
+}
+
+func TestIsAllowedIP(t *testing.T) {
+	assert.True(t, IsAllowedIP(nil, "198.51.100.1"))
+	assert.True(t, IsAllowedIP([]string{"198.51.100.0/24"}, "198.51.100.1"))
+	assert.False(t, IsAllowedIP([]string{"198.51.100.0/24"}, "198.51.101.1"))
+	assert.True(t, IsAllowedIP([]string{"2001:db8::1"}, "2001:db8::1"))
+	assert.False(t, IsAllowedIP([]string{"198.51.100.1"}, "invalid"))
+
+	assert.Nil(t, validateAllowedIPs([]string{"198.51.100.0/24", "198.51.100.1"}))
+	assert.NotNil(t, validateAllowedIPs([]string{"198.51.100.0/33"}))
+}
@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"net/http"
+	"smlaicloudplatform/internal/audit/repositories"
+	"smlaicloudplatform/internal/audit/services"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type IAuditHttp interface{}
+
+type AuditHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.IAuditHttpService
+}
+
+func NewAuditHttp(ms *microservice.Microservice, cfg config.IConfig) AuditHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	svc := services.NewAuditHttpService(repositories.NewAuditLogRepository(pst))
+
+	rbac.InitPermissionService(ms, cfg)
+
+	return AuditHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h AuditHttp) RegisterHttp() {
+	auditRead := h.ms.RequirePermission(rbacmodels.PermissionAuditRead)
+
+	h.ms.GET("/audit", h.SearchAuditLogPage, auditRead)
+	h.ms.GET("/audit/:collection/:id", h.DocumentHistory, auditRead)
+}
+
+// Document History godoc
+// @Description get changes of the document, newest first
+// @Tags		Audit
+// @Param		collection  path      string  true  "Collection e.g. productBarcodes"
+// @Param		id  path      string  true  "Document guidfixed"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /audit/{collection}/{id} [get]
+func (h AuditHttp) DocumentHistory(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	collection := ctx.Param("collection")
+	id := ctx.Param("id")
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.DocumentHistory(ctx.Context(), shopID, collection, id, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Search Audit Log godoc
+// @Description search changes of documents in the shop, newest first
+// @Tags		Audit
+// @Param		q		query	string		false  "Search guidfixed or actor"
+// @Param		collection		query	string		false  "Collection"
+// @Param		action		query	string		false  "create, update or delete"
+// @Param		actor		query	string		false  "Username"
+// @Param		authtype		query	string		false  "bearer, websocket, xapikey or apikey"
+// @Param		fromdate		query	string		false  "From date (yyyy-mm-dd)"
+// @Param		todate		query	string		false  "To date (yyyy-mm-dd)"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /audit [get]
+func (h AuditHttp) SearchAuditLogPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "collection",
+			Field: "collection",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "action",
+			Field: "action",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "actor",
+			Field: "actor",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "authtype",
+			Field: "authtype",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "createdat",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+	})
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchAuditLog(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"context"
+	"smlaicloudplatform/internal/audit/models"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of document history and shop wide audit search
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.AuditLogDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "collection", Value: 1}, {Key: "guidfixed", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("auditlog_shopid_collection_guidfixed_createdat"),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("auditlog_shopid_createdat"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const auditLogCollectionName = "auditLogs"
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// FieldChange is change of one field, field of nested document is dot path e.g. names.0.name
+type FieldChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before" bson:"before"`
+	After  interface{} `json:"after" bson:"after"`
+}
+
+// AuditLog is one mutation of document, it is never updated or deleted, only secrets of logs which are written
+// before secrets are redacted are scrubbed by cmd/encryptsecrets
+type AuditLog struct {
+	ShopID     string `json:"shopid" bson:"shopid"`
+	Collection string `json:"collection" bson:"collection"`
+	GuidFixed  string `json:"guidfixed" bson:"guidfixed"`
+	Action     string `json:"action" bson:"action"`
+
+	Actor    string `json:"actor" bson:"actor"`
+	IP       string `json:"ip" bson:"ip,omitempty"`
+	AuthType string `json:"authtype" bson:"authtype,omitempty"`
+	ApiKeyID string `json:"apikeyid" bson:"apikeyid,omitempty"`
+
+	Before  bson.M        `json:"before" bson:"before,omitempty"`
+	After   bson.M        `json:"after" bson:"after,omitempty"`
+	Changes []FieldChange `json:"changes" bson:"changes"`
+
+	CreatedAt time.Time `json:"createdat" bson:"createdat"`
+}
+
+func (AuditLog) CollectionName() string {
+	return auditLogCollectionName
+}
+
+type AuditLogDoc struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	AuditLog `bson:"inline"`
+}
+
+func (AuditLogDoc) CollectionName() string {
+	return auditLogCollectionName
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// auditIgnoreFields are recorded as actor and time of audit log so they are not listed as changes
+var auditIgnoreFields = map[string]struct{}{
+	"_id":       {},
+	"createdby": {},
+	"createdat": {},
+	"updatedby": {},
+	"updatedat": {},
+	"deletedby": {},
+	"deletedat": {},
+}
+
+// ToSnapshot convert document to bson document which is stored as before or after of audit log
+func ToSnapshot(doc interface{}) (bson.M, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	if snapshot, ok := doc.(bson.M); ok {
+		return snapshot, nil
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := bson.M{}
+	err = bson.Unmarshal(raw, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// DiffSnapshot return changed fields between before and after sorted by field,
+// nested document and array are compared field by field
+func DiffSnapshot(before bson.M, after bson.M) []FieldChange {
+	beforeFields := map[string]interface{}{}
+	afterFields := map[string]interface{}{}
+
+	flattenSnapshot("", before, beforeFields)
+	flattenSnapshot("", after, afterFields)
+
+	changes := []FieldChange{}
+	for field, beforeValue := range beforeFields {
+		afterValue, ok := afterFields[field]
+		if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			changes = append(changes, FieldChange{Field: field, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	for field, afterValue := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Before: nil, After: afterValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+func flattenSnapshot(prefix string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case bson.M:
+		for key, child := range v {
+			if prefix == "" {
+				if _, ignore := auditIgnoreFields[key]; ignore {
+					continue
+				}
+			}
+			flattenSnapshot(joinField(prefix, key), child, fields)
+		}
+	case bson.D:
+		for _, e := range v {
+			flattenSnapshot(joinField(prefix, e.Key), e.Value, fields)
+		}
+	case bson.A:
+		if len(v) == 0 {
+			fields[prefix] = bson.A{}
+		}
+		for i, child := range v {
+			flattenSnapshot(joinField(prefix, strconv.Itoa(i)), child, fields)
+		}
+	case primitive.DateTime:
+		fields[prefix] = v.Time().UTC()
+	default:
+		if prefix != "" {
+			fields[prefix] = v
+		}
+	}
+}
+
+func joinField(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type diffTestName struct {
+	Code string `bson:"code"`
+	Name string `bson:"name"`
+}
+
+type diffTestDoc struct {
+	ShopID    string         `bson:"shopid"`
+	GuidFixed string         `bson:"guidfixed"`
+	Price     float64        `bson:"price"`
+	CreditDay int            `bson:"creditday"`
+	Names     []diffTestName `bson:"names"`
+	UpdatedAt time.Time      `bson:"updatedat"`
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	before, err := ToSnapshot(diffTestDoc{
+		ShopID:    "SHOP01",
+		GuidFixed: "DOC01",
+		Price:     100,
+		CreditDay: 30,
+		Names:     []diffTestName{{Code: "th", Name: "ก"}},
+		UpdatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+
+	after, err := ToSnapshot(diffTestDoc{
+		ShopID:    "SHOP01",
+		GuidFixed: "DOC01",
+		Price:     120,
+		CreditDay: 30,
+		Names:     []diffTestName{{Code: "th", Name: "ข"}, {Code: "en", Name: "B"}},
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+
+	changes := DiffSnapshot(before, after)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "names.0.name", Before: "ก", After: "ข"},
+		{Field: "names.1.code", Before: nil, After: "en"},
+		{Field: "names.1.name", Before: nil, After: "B"},
+		{Field: "price", Before: 100.0, After: 120.0},
+	}, changes, "updatedat is not listed as change")
+}
+
+func TestDiffSnapshotCreateAndDelete(t *testing.T) {
+	doc := bson.M{"shopid": "SHOP01", "creditday": int32(30)}
+
+	assert.Equal(t, []FieldChange{
+		{Field: "creditday", Before: nil, After: int32(30)},
+		{Field: "shopid", Before: nil, After: "SHOP01"},
+	}, DiffSnapshot(nil, doc))
+
+	assert.Equal(t, []FieldChange{
+		{Field: "creditday", Before: int32(30), After: nil},
+		{Field: "shopid", Before: "SHOP01", After: nil},
+	}, DiffSnapshot(doc, nil))
+}
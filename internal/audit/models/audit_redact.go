@@ -0,0 +1,131 @@
+package models
+
+import (
+	"reflect"
+	"smlaicloudplatform/internal/encrypt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const secretPathMarker = "\x00secret-path-"
+
+// secretDoc is document which has secret fields, e.g. credential of payment provider
+type secretDoc interface {
+	SecretFields() []*string
+}
+
+// SecretPaths return dot paths of secret fields of the model, e.g. auth.password, nil when the model has no secret.
+// Secret fields of new document of the model are set to markers so their paths are found in its snapshot
+func SecretPaths(model interface{}) []string {
+	modelType := reflect.TypeOf(model)
+	if modelType == nil || modelType.Kind() != reflect.Ptr {
+		return nil
+	}
+
+	doc, ok := reflect.New(modelType.Elem()).Interface().(secretDoc)
+	if !ok {
+		return nil
+	}
+
+	markers := map[string]struct{}{}
+	for i, field := range doc.SecretFields() {
+		if field == nil {
+			continue
+		}
+		*field = secretPathMarker + strconv.Itoa(i)
+		markers[*field] = struct{}{}
+	}
+
+	snapshot, err := ToSnapshot(doc)
+	if err != nil {
+		return nil
+	}
+
+	fields := map[string]interface{}{}
+	flattenSnapshot("", snapshot, fields)
+
+	paths := []string{}
+	for path, value := range fields {
+		if str, ok := value.(string); ok {
+			if _, ok := markers[str]; ok {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// RedactSnapshot replace values of secret paths which are not empty by redacted value in place,
+// it return true when a value is replaced
+func RedactSnapshot(snapshot bson.M, paths []string) bool {
+	redacted := false
+	for _, path := range paths {
+		if redactPath(snapshot, strings.Split(path, ".")) {
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+func redactPath(value interface{}, keys []string) bool {
+	switch v := value.(type) {
+	case bson.M:
+		child, ok := v[keys[0]]
+		if !ok {
+			return false
+		}
+		if len(keys) > 1 {
+			return redactPath(child, keys[1:])
+		}
+		if isSecretValue(child) {
+			v[keys[0]] = encrypt.RedactedValue
+			return true
+		}
+	case bson.D:
+		for i := range v {
+			if v[i].Key != keys[0] {
+				continue
+			}
+			if len(keys) > 1 {
+				return redactPath(v[i].Value, keys[1:])
+			}
+			if isSecretValue(v[i].Value) {
+				v[i].Value = encrypt.RedactedValue
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RedactChanges replace values of changes of secret paths in place so change of the secret is recorded without it,
+// it return true when a value is replaced
+func RedactChanges(changes []FieldChange, paths []string) bool {
+	redacted := false
+	for i := range changes {
+		for _, path := range paths {
+			if changes[i].Field != path {
+				continue
+			}
+			if isSecretValue(changes[i].Before) {
+				changes[i].Before = encrypt.RedactedValue
+				redacted = true
+			}
+			if isSecretValue(changes[i].After) {
+				changes[i].After = encrypt.RedactedValue
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+func isSecretValue(value interface{}) bool {
+	str, ok := value.(string)
+	return ok && str != "" && str != encrypt.RedactedValue
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+)
+
+// IAuditLogRepository only read audit logs, they are appended by CrudRepository
+type IAuditLogRepository interface {
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.AuditLogDoc, mongopagination.PaginationData, error)
+}
+
+type AuditLogRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.SearchRepository[models.AuditLogDoc]
+}
+
+func NewAuditLogRepository(pst microservice.IPersisterMongo) *AuditLogRepository {
+
+	insRepo := &AuditLogRepository{
+		pst: pst,
+	}
+
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.AuditLogDoc](pst)
+
+	return insRepo
+}
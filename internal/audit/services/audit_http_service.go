@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/audit/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+)
+
+type IAuditHttpService interface {
+	DocumentHistory(ctx context.Context, shopID string, collection string, guid string, pageable micromodels.Pageable) ([]models.AuditLogDoc, mongopagination.PaginationData, error)
+	SearchAuditLog(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.AuditLogDoc, mongopagination.PaginationData, error)
+}
+
+type AuditHttpService struct {
+	repo           repositories.IAuditLogRepository
+	contextTimeout time.Duration
+}
+
+func NewAuditHttpService(repo repositories.IAuditLogRepository) *AuditHttpService {
+	return &AuditHttpService{
+		repo:           repo,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc AuditHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// DocumentHistory return audit logs of the document, newest first
+func (svc AuditHttpService) DocumentHistory(ctx context.Context, shopID string, collection string, guid string, pageable micromodels.Pageable) ([]models.AuditLogDoc, mongopagination.PaginationData, error) {
+	return svc.SearchAuditLog(ctx, shopID, map[string]interface{}{
+		"collection": collection,
+		"guidfixed":  guid,
+	}, pageable)
+}
+
+// SearchAuditLog return audit logs of the shop, newest first when sort is not requested
+func (svc AuditHttpService) SearchAuditLog(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.AuditLogDoc, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if len(pageable.Sorts) < 1 {
+		pageable.Sorts = append(pageable.Sorts, micromodels.KeyInt{Key: "createdat", Value: -1})
+	}
+
+	searchInFields := []string{
+		"guidfixed",
+		"actor",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.AuditLogDoc{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
@@ -2,15 +2,21 @@ package authentication
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"smlaicloudplatform/internal/authentication/models"
 	"smlaicloudplatform/internal/authentication/repositories"
 	"smlaicloudplatform/internal/authentication/services"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	"smlaicloudplatform/internal/firebase"
+	"smlaicloudplatform/internal/loginguard"
+	guardservices "smlaicloudplatform/internal/loginguard/services"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/internal/shop"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/passwordpolicy"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
@@ -28,6 +34,8 @@ type AuthenticationHttp struct {
 	cfg                   config.IConfig
 	authService           *microservice.AuthService
 	authenticationService services.IAuthenticationService
+	twoFactorService      services.ITwoFactorService
+	sessionService        services.ISessionService
 	shopService           shop.IShopService
 	shopUserService       shop.IShopUserService
 }
@@ -46,11 +54,16 @@ func NewAuthenticationHttp(ms *microservice.Microservice, cfg config.IConfig) Au
 	authRepo := repositories.NewAuthenticationMongoCacheRepository(pst, cache)
 	smsRepo := repositories.NewAuthenticationSMSRepository(cache)
 	firebaseAdapter := firebase.NewFirebaseAdapter()
+	twoFactorService := services.NewTwoFactorService(repositories.NewUserTwoFactorRepository(pst), datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()), ms.TimeNow)
 	authenticationService := services.NewAuthenticationService(
 		authRepo,
 		shopUserRepo,
 		shopUserAccessLogRepo,
+		shopRepo,
 		smsRepo,
+		twoFactorService,
+		loginguard.NewLoginGuard(ms, cfg),
+		passwordpolicy.NewPolicy(cfg.PasswordPolicyConfig()),
 		authService,
 		utils.RandStringBytesMaskImprSrcUnsafe,
 		utils.RandNumber,
@@ -61,12 +74,16 @@ func NewAuthenticationHttp(ms *microservice.Microservice, cfg config.IConfig) Au
 		firebaseAdapter)
 
 	shopService := shop.NewShopService(shopRepo, shopUserRepo, utils.NewGUID, ms.TimeNow)
-	shopUserService := shop.NewShopUserService(shopUserRepo)
+	permissionService := rbac.InitPermissionService(ms, cfg)
+	shopUserService := shop.NewShopUserService(shopUserRepo, permissionService)
+	sessionService := services.NewSessionService(authService, shopUserRepo, permissionService)
 	return AuthenticationHttp{
 		ms:                    ms,
 		cfg:                   cfg,
 		authService:           authService,
 		authenticationService: authenticationService,
+		twoFactorService:      twoFactorService,
+		sessionService:        sessionService,
 		shopUserService:       shopUserService,
 		shopService:           shopService,
 	}
@@ -79,7 +96,7 @@ func (h AuthenticationHttp) RegisterHttp() {
 	h.ms.POST("/login/email", h.LoginEmail)
 	h.ms.POST("/login/phone-number", h.LoginWithPhoneNumber)
 	h.ms.POST("/tokenlogin", h.TokenLogin)
-	h.ms.POST("/logout", h.Logout)
+	h.ms.POST("/logout", h.Logout, h.ms.SkipPermission())
 	h.ms.POST("/refresh", h.RefreshToken)
 	h.ms.POST("/register", h.Register)
 	h.ms.POST("/send-phonenumber-otp", h.SendPhoneNumberOTP)
@@ -88,19 +105,25 @@ func (h AuthenticationHttp) RegisterHttp() {
 	h.ms.POST("/register/exists-phonenumber", h.RegisterCheckExistPhonenumber)
 	h.ms.POST("/register/exists-username", h.RegisterCheckExistUsername)
 
-	h.ms.GET("/profile", h.Profile)
-	h.ms.GET("/profileshop", h.ProfileShop)
+	// routes of the user account are not checked by permission of the selected shop
+	account := h.ms.SkipPermission()
 
-	h.ms.PUT("/profile", h.Update)
-	h.ms.PUT("/profile/password", h.UpdatePassword)
+	h.ms.GET("/profile", h.Profile, account)
+	h.ms.GET("/profileshop", h.ProfileShop, account)
+
+	h.ms.PUT("/profile", h.Update, account)
+	h.ms.PUT("/profile/password", h.UpdatePassword, account)
+
+	h.registerTwoFactorHttp()
+	h.registerSessionHttp()
 
 	middlewareShop := h.authService.MWFuncWithShop(h.ms.Cacher(h.cfg.CacherConfig()))
-	h.ms.GET("/list-shop", h.ListShopCanAccess, middlewareShop)
-	h.ms.POST("/select-shop", h.SelectShop, middlewareShop)
-	h.ms.PUT("/favorite-shop", h.UpdateShopFavorite, middlewareShop)
+	h.ms.GET("/list-shop", h.ListShopCanAccess, middlewareShop, account)
+	h.ms.POST("/select-shop", h.SelectShop, middlewareShop, account)
+	h.ms.PUT("/favorite-shop", h.UpdateShopFavorite, middlewareShop, account)
 
 	shopHttp := shop.NewShopHttp(h.ms, h.cfg)
-	h.ms.POST("/create-shop", shopHttp.CreateShop, middlewareShop)
+	h.ms.POST("/create-shop", shopHttp.CreateShop, middlewareShop, account)
 }
 
 // Login with phone number
@@ -111,6 +134,35 @@ func (h AuthenticationHttp) RegisterHttp() {
 // @Success		200	{object}	common.AuthResponse
 // @Failure		400 {object}	common.AuthResponseFailed
 // @Router /login [post]
+// newAuthenticationContext name device of login session by device name of the request or user agent
+func newAuthenticationContext(ctx microservice.IContext, deviceName string) models.AuthenticationContext {
+	if deviceName == "" {
+		deviceName = ctx.Header("User-Agent")
+	}
+
+	return models.AuthenticationContext{
+		Ip:         ctx.RealIp(),
+		DeviceName: deviceName,
+	}
+}
+
+// loginErrorStatus is 429 when attempts are throttled so client can wait before retry
+func loginErrorStatus(err error) int {
+	if errors.Is(err, guardservices.ErrTooManyAttempts) || errors.Is(err, guardservices.ErrTooManyOTPSend) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadRequest
+}
+
+// responseLoginError respond retry time of throttled login, other errors are responded with the message
+func responseLoginError(ctx microservice.IContext, err error, message string) {
+	status := loginErrorStatus(err)
+	if status == http.StatusTooManyRequests {
+		message = err.Error()
+	}
+	ctx.ResponseError(status, message)
+}
+
 func (h AuthenticationHttp) LoginWithPhoneNumber(ctx microservice.IContext) error {
 
 	input := ctx.ReadInput()
@@ -128,14 +180,12 @@ func (h AuthenticationHttp) LoginWithPhoneNumber(ctx microservice.IContext) erro
 		return err
 	}
 
-	authContext := models.AuthenticationContext{
-		Ip: ctx.RealIp(),
-	}
+	authContext := newAuthenticationContext(ctx, userReq.DeviceName)
 
 	result, err := h.authenticationService.LoginWithPhoneNumber(userReq, authContext)
 
 	if err != nil {
-		ctx.ResponseError(400, err.Error())
+		responseLoginError(ctx, err, err.Error())
 		return err
 	}
 
@@ -173,14 +223,12 @@ func (h AuthenticationHttp) Login(ctx microservice.IContext) error {
 		return err
 	}
 
-	authContext := models.AuthenticationContext{
-		Ip: ctx.RealIp(),
-	}
+	authContext := newAuthenticationContext(ctx, userReq.DeviceName)
 
 	result, err := h.authenticationService.Login(userReq, authContext)
 
 	if err != nil {
-		ctx.ResponseError(400, "login failed.")
+		responseLoginError(ctx, err, "login failed.")
 		return err
 	}
 
@@ -218,14 +266,12 @@ func (h AuthenticationHttp) Poslogin(ctx microservice.IContext) error {
 		return err
 	}
 
-	authContext := models.AuthenticationContext{
-		Ip: ctx.RealIp(),
-	}
+	authContext := newAuthenticationContext(ctx, userReq.DeviceName)
 
 	result, err := h.authenticationService.Poslogin(userReq, authContext)
 
 	if err != nil {
-		ctx.ResponseError(400, "login failed.")
+		responseLoginError(ctx, err, "login failed.")
 		return err
 	}
 
@@ -263,14 +309,12 @@ func (h AuthenticationHttp) LoginEmail(ctx microservice.IContext) error {
 		return err
 	}
 
-	authContext := models.AuthenticationContext{
-		Ip: ctx.RealIp(),
-	}
+	authContext := newAuthenticationContext(ctx, userReq.DeviceName)
 
 	result, err := h.authenticationService.LoginEmail(userReq, authContext)
 
 	if err != nil {
-		ctx.ResponseError(400, "login failed.")
+		responseLoginError(ctx, err, "login failed.")
 		return err
 	}
 
@@ -343,7 +387,7 @@ func (h AuthenticationHttp) TokenLogin(ctx microservice.IContext) error {
 		return err
 	}
 
-	tokenString, err := h.authenticationService.LoginWithFirebaseToken(tokenReq.Token)
+	tokenString, err := h.authenticationService.LoginWithFirebaseToken(tokenReq.Token, newAuthenticationContext(ctx, ""))
 
 	if err != nil {
 		ctx.ResponseError(400, "login failed.")
@@ -428,10 +472,10 @@ func (h AuthenticationHttp) SendPhoneNumberOTP(ctx microservice.IContext) error
 		return err
 	}
 
-	result, err := h.authenticationService.SendPhonenumberOTP(payload)
+	result, err := h.authenticationService.SendPhonenumberOTP(payload, ctx.RealIp())
 
 	if err != nil {
-		ctx.Response(http.StatusBadRequest, common.ApiResponse{
+		ctx.Response(loginErrorStatus(err), common.ApiResponse{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -689,7 +733,7 @@ func (h AuthenticationHttp) UpdatePassword(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.authenticationService.UpdatePassword(authUsername, userPwdReq.CurrentPassword, userPwdReq.NewPassword)
+	err = h.authenticationService.UpdatePassword(authUsername, userPwdReq.CurrentPassword, userPwdReq.NewPassword, ctx.Header("Authorization"))
 
 	if err != nil {
 		ctx.Response(http.StatusBadRequest, common.ApiResponse{
@@ -718,7 +762,7 @@ func (h AuthenticationHttp) Logout(ctx microservice.IContext) error {
 
 	authorizationHeader := ctx.Header("Authorization")
 
-	err := h.authenticationService.Logout(authorizationHeader)
+	err := h.authenticationService.Logout(ctx.UserInfo().Username, authorizationHeader)
 
 	if err != nil {
 		ctx.Response(http.StatusBadRequest, common.ApiResponse{
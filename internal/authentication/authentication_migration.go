@@ -0,0 +1,28 @@
+package authentication
+
+import (
+	"context"
+	"smlaicloudplatform/internal/authentication/models"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create unique index of two factor authentication of the user
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(context.Background(), &models.UserTwoFactorDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}},
+		Options: options.Index().SetName("usertwofactor_username").SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,174 @@
+package authentication
+
+import (
+	"encoding/json"
+	"net/http"
+	"smlaicloudplatform/internal/authentication/models"
+	common "smlaicloudplatform/internal/models"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+func (h AuthenticationHttp) registerSessionHttp() {
+	account := h.ms.SkipPermission()
+
+	h.ms.GET("/sessions", h.ListSessions, account)
+	h.ms.POST("/sessions/revoke", h.RevokeSession, account)
+	h.ms.POST("/sessions/revoke-all", h.RevokeAllSessions, account)
+
+	h.ms.GET("/shop/sessions/:username", h.ListShopUserSessions, h.ms.RequirePermission(rbacmodels.PermissionShopUserRead))
+	h.ms.DELETE("/shop/sessions/:username", h.RevokeShopUserSessions, h.ms.RequirePermission(rbacmodels.PermissionShopUserDelete))
+}
+
+// List Sessions godoc
+// @Description List active login sessions of current user, last seen first
+// @Tags		Authentication
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /sessions [get]
+func (h AuthenticationHttp) ListSessions(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	sessions, err := h.sessionService.ListSessions(ctx.UserInfo().Username, ctx.Header("Authorization"))
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    sessions,
+	})
+
+	return nil
+}
+
+// Revoke Session godoc
+// @Description Revoke login session of current user, tokens of the session are no longer valid
+// @Tags		Authentication
+// @Param		SessionRevokeRequest  body      models.SessionRevokeRequest  true  "Session"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /sessions/revoke [post]
+func (h AuthenticationHttp) RevokeSession(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	input := ctx.ReadInput()
+
+	revokeReq := models.SessionRevokeRequest{}
+	err := json.Unmarshal([]byte(input), &revokeReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, "payload invalid")
+		return err
+	}
+
+	if err = ctx.Validate(revokeReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.sessionService.RevokeSession(ctx.UserInfo().Username, revokeReq.SessionID)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// Revoke All Sessions godoc
+// @Description Revoke every login session of current user including current session
+// @Tags		Authentication
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /sessions/revoke-all [post]
+func (h AuthenticationHttp) RevokeAllSessions(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	err := h.sessionService.RevokeAllSessions(ctx.UserInfo().Username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// List Shop User Sessions godoc
+// @Description List login sessions of user of the shop which select the shop
+// @Tags		Shop
+// @Param		username  path      string  true  "Username"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /shop/sessions/{username} [get]
+func (h AuthenticationHttp) ListShopUserSessions(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	username := ctx.Param("username")
+
+	sessions, err := h.sessionService.ListShopUserSessions(shopID, username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    sessions,
+	})
+
+	return nil
+}
+
+// Revoke Shop User Sessions godoc
+// @Description Revoke login sessions of user of the shop which select the shop, e.g. POS of the employee who leaves
+// @Tags		Shop
+// @Param		username  path      string  true  "Username"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /shop/sessions/{username} [delete]
+func (h AuthenticationHttp) RevokeShopUserSessions(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	username := ctx.Param("username")
+
+	err := h.sessionService.RevokeShopUserSessions(userInfo.ShopID, userInfo.Username, username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package authentication
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/logger"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+func (h AuthenticationHttp) registerTwoFactorHttp() {
+	account := h.ms.SkipPermission()
+
+	h.ms.GET("/2fa", h.TwoFactorStatus, account)
+	h.ms.POST("/2fa/enroll", h.TwoFactorEnroll, account)
+	h.ms.POST("/2fa/enable", h.TwoFactorEnable, account)
+	h.ms.POST("/2fa/verify", h.TwoFactorVerify, account)
+	h.ms.POST("/2fa/disable", h.TwoFactorDisable, account)
+	h.ms.POST("/2fa/recovery-codes", h.TwoFactorRecoveryCodes, account)
+}
+
+// two factor authentication and sessions are managed by the user login only, not by api key of the shop
+func (h AuthenticationHttp) denyApiKey(ctx microservice.IContext) error {
+	if ctx.UserInfo().ApiKeyID != "" {
+		ctx.ResponseError(http.StatusForbidden, "api key is not allowed, user login is required")
+		return errors.New("api key is not allowed, user login is required")
+	}
+	return nil
+}
+
+func (h AuthenticationHttp) readTwoFactorCode(ctx microservice.IContext) (models.TwoFactorCodeRequest, error) {
+	input := ctx.ReadInput()
+
+	codeReq := models.TwoFactorCodeRequest{}
+	err := json.Unmarshal([]byte(input), &codeReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, "payload invalid")
+		return codeReq, err
+	}
+
+	if err = ctx.Validate(codeReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return codeReq, err
+	}
+
+	return codeReq, nil
+}
+
+// Two Factor Status godoc
+// @Description Get two factor authentication status of current user
+// @Tags		Authentication
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa [get]
+func (h AuthenticationHttp) TwoFactorStatus(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	status, err := h.twoFactorService.Status(ctx.Context(), ctx.UserInfo().Username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    status,
+	})
+
+	return nil
+}
+
+// Two Factor Enroll godoc
+// @Description Generate totp secret and provisioning uri for QR code, the secret is used after it is enabled by code
+// @Tags		Authentication
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa/enroll [post]
+func (h AuthenticationHttp) TwoFactorEnroll(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	result, err := h.twoFactorService.Enroll(ctx.Context(), ctx.UserInfo().Username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    result,
+	})
+
+	return nil
+}
+
+// Two Factor Enable godoc
+// @Description Enable two factor authentication by code of authenticator app, recovery codes are shown only once and current token is marked as verified
+// @Tags		Authentication
+// @Param		TwoFactorCodeRequest  body      models.TwoFactorCodeRequest  true  "Code of authenticator app"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa/enable [post]
+func (h AuthenticationHttp) TwoFactorEnable(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	codeReq, err := h.readTwoFactorCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.twoFactorService.Enable(ctx.Context(), ctx.UserInfo().Username, codeReq.Code)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	tokenStr, err := h.authService.GetTokenFromAuthorizationHeader(microservice.AUTHTYPE_BEARER, ctx.Header("Authorization"))
+	if err == nil {
+		err = h.authService.SetTwoFactorVerified(microservice.AUTHTYPE_BEARER, tokenStr)
+	}
+
+	if err != nil {
+		logger.GetLogger().Errorf("Two factor verify current token: %v", err)
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    result,
+	})
+
+	return nil
+}
+
+// Two Factor Verify godoc
+// @Description Verify current token by code of authenticator app or recovery code, it is required by login without code such as email and firebase login before selecting shop
+// @Tags		Authentication
+// @Param		TwoFactorCodeRequest  body      models.TwoFactorCodeRequest  true  "Code of authenticator app or recovery code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa/verify [post]
+func (h AuthenticationHttp) TwoFactorVerify(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	codeReq, err := h.readTwoFactorCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	authContext := models.AuthenticationContext{
+		Ip: ctx.RealIp(),
+	}
+
+	err = h.authenticationService.VerifyTwoFactor(ctx.UserInfo().Username, ctx.Header("Authorization"), codeReq.Code, authContext)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// Two Factor Disable godoc
+// @Description Disable two factor authentication by code of authenticator app or recovery code
+// @Tags		Authentication
+// @Param		TwoFactorCodeRequest  body      models.TwoFactorCodeRequest  true  "Code of authenticator app or recovery code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa/disable [post]
+func (h AuthenticationHttp) TwoFactorDisable(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	codeReq, err := h.readTwoFactorCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.twoFactorService.Disable(ctx.Context(), ctx.UserInfo().Username, codeReq.Code)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// Two Factor Recovery Codes godoc
+// @Description Regenerate recovery codes, codes which are generated before are no longer valid
+// @Tags		Authentication
+// @Param		TwoFactorCodeRequest  body      models.TwoFactorCodeRequest  true  "Code of authenticator app or recovery code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /2fa/recovery-codes [post]
+func (h AuthenticationHttp) TwoFactorRecoveryCodes(ctx microservice.IContext) error {
+	if err := h.denyApiKey(ctx); err != nil {
+		return err
+	}
+
+	codeReq, err := h.readTwoFactorCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.twoFactorService.RegenerateRecoveryCodes(ctx.Context(), ctx.UserInfo().Username, codeReq.Code)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    result,
+	})
+
+	return nil
+}
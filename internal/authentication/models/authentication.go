@@ -1,9 +1,40 @@
 package models
 
-import "time"
+import (
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+)
 
 type AuthenticationContext struct {
-	Ip string
+	Ip         string
+	DeviceName string
+	AuthType   string
+}
+
+// auth type of login session and login attempt
+const (
+	LoginTypePassword    = "password"
+	LoginTypePhoneNumber = "phonenumber"
+	LoginTypePhoneOTP    = "phoneotp"
+	LoginTypePOS         = "pos"
+	LoginTypeEmail       = "email"
+	LoginTypeFirebase    = "firebase"
+	LoginTypeTwoFactor   = "twofactor"
+)
+
+// DeviceNameField name the device of login session, user agent is used when it is empty
+type DeviceNameField struct {
+	DeviceName string `json:"devicename,omitempty" bson:"-"`
+}
+
+type SessionRevokeRequest struct {
+	SessionID string `json:"sessionid" validate:"required"`
+}
+
+// UserSessionInfo is session of the user, IsCurrent is session of the request token
+type UserSessionInfo struct {
+	micromodels.UserSession
+	IsCurrent bool `json:"iscurrent"`
 }
 
 type ShopFavoriteRequest struct {
@@ -33,6 +64,8 @@ type PhoneNumberOTPRequest struct {
 	PhoneNumber string `json:"phonenumber" bson:"phonenumber" validate:"required,max=233"`
 	RefCode     string `json:"refcode"`
 	OTP         string `json:"otp" bson:"otp" validate:"required,max=20"`
+	TwoFactorCodeField
+	DeviceNameField
 }
 
 type PhoneOTP struct {
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userTwoFactorCollectionName = "userTwoFactors"
+
+// TwoFactorCodeField is code of authenticator app or one of recovery codes
+type TwoFactorCodeField struct {
+	TwoFactorCode string `json:"twofactorcode,omitempty" bson:"-"`
+}
+
+// UserTwoFactor is totp of the user, it is kept apart from user document so it is never returned with profile
+type UserTwoFactor struct {
+	Username      string    `json:"username" bson:"username"`
+	Secret        string    `json:"-" bson:"secret"`
+	PendingSecret string    `json:"-" bson:"pendingsecret"`
+	Enabled       bool      `json:"enabled" bson:"enabled"`
+	EnabledAt     time.Time `json:"enabledat" bson:"enabledat"`
+	RecoveryCodes []string  `json:"-" bson:"recoverycodes"`
+	LastUsedStep  int64     `json:"-" bson:"lastusedstep"`
+	UpdatedAt     time.Time `json:"-" bson:"updatedat"`
+}
+
+type UserTwoFactorDoc struct {
+	ID            primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserTwoFactor `bson:"inline"`
+}
+
+func (UserTwoFactorDoc) CollectionName() string {
+	return userTwoFactorCollectionName
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool      `json:"enabled"`
+	EnabledAt              time.Time `json:"enabledat"`
+	RecoveryCodesRemaining int       `json:"recoverycodesremaining"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioninguri"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoverycodes"`
+}
@@ -98,20 +98,26 @@ func (*UserRequest) CollectionName() string {
 }
 
 type UserLoginRequest struct {
-	UsernameField `bson:"inline"`
-	UserPassword  `bson:"inline"`
-	ShopID        string `json:"shopid,omitempty"`
+	UsernameField      `bson:"inline"`
+	UserPassword       `bson:"inline"`
+	TwoFactorCodeField `bson:"inline"`
+	DeviceNameField    `bson:"inline"`
+	ShopID             string `json:"shopid,omitempty"`
 }
 
 type PosLoginRequest struct {
-	UsernameField `bson:"inline"`
-	ShopID        string `json:"shopid,omitempty"`
+	UsernameField      `bson:"inline"`
+	TwoFactorCodeField `bson:"inline"`
+	DeviceNameField    `bson:"inline"`
+	ShopID             string `json:"shopid,omitempty"`
 }
 
 type UserLoginPhoneNumberRequest struct {
-	PhoneNumberField `bson:"inline"`
-	UserPassword     `bson:"inline"`
-	ShopID           string `json:"shopid,omitempty"`
+	PhoneNumberField   `bson:"inline"`
+	UserPassword       `bson:"inline"`
+	TwoFactorCodeField `bson:"inline"`
+	DeviceNameField    `bson:"inline"`
+	ShopID             string `json:"shopid,omitempty"`
 }
 
 type UserProfile struct {
@@ -157,6 +163,7 @@ type ShopUserBase struct {
 	Username string   `json:"username" bson:"username"`
 	ShopID   string   `json:"shopid" bson:"shopid"`
 	Role     UserRole `json:"role" bson:"role"`
+	Roles    []string `json:"roles" bson:"roles,omitempty"`
 }
 
 type ShopUser struct {
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IUserTwoFactorRepository interface {
+	FindByUsername(ctx context.Context, username string) (models.UserTwoFactorDoc, error)
+	Create(ctx context.Context, doc models.UserTwoFactorDoc) (primitive.ObjectID, error)
+	Update(ctx context.Context, username string, doc models.UserTwoFactorDoc) error
+	// UseStep return false when step of totp is not after the last used step, it is used by other login already
+	UseStep(ctx context.Context, username string, step int64, updatedAt time.Time) (bool, error)
+	// UseRecoveryCode return false when recovery code is removed by other login already
+	UseRecoveryCode(ctx context.Context, username string, codeHash string, updatedAt time.Time) (bool, error)
+	UpdateRecoveryCodes(ctx context.Context, username string, codeHashes []string, updatedAt time.Time) error
+}
+
+type UserTwoFactorRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewUserTwoFactorRepository(pst microservice.IPersisterMongo) UserTwoFactorRepository {
+	return UserTwoFactorRepository{
+		pst: pst,
+	}
+}
+
+func (r UserTwoFactorRepository) FindByUsername(ctx context.Context, username string) (models.UserTwoFactorDoc, error) {
+
+	doc := models.UserTwoFactorDoc{}
+	err := r.pst.FindOne(ctx, &models.UserTwoFactorDoc{}, bson.M{"username": username}, &doc)
+
+	if err != nil {
+		return models.UserTwoFactorDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (r UserTwoFactorRepository) Create(ctx context.Context, doc models.UserTwoFactorDoc) (primitive.ObjectID, error) {
+
+	idx, err := r.pst.Create(ctx, &models.UserTwoFactorDoc{}, doc)
+
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return idx, nil
+}
+
+func (r UserTwoFactorRepository) Update(ctx context.Context, username string, doc models.UserTwoFactorDoc) error {
+
+	filterDoc := map[string]interface{}{
+		"username": username,
+	}
+
+	err := r.pst.UpdateOne(ctx, &models.UserTwoFactorDoc{}, filterDoc, doc)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r UserTwoFactorRepository) UseStep(ctx context.Context, username string, step int64, updatedAt time.Time) (bool, error) {
+	filter := bson.M{
+		"username":     username,
+		"enabled":      true,
+		"lastusedstep": bson.M{"$lt": step},
+	}
+
+	return r.updateMatched(ctx, filter, bson.M{"$set": bson.M{"lastusedstep": step, "updatedat": updatedAt}})
+}
+
+func (r UserTwoFactorRepository) UseRecoveryCode(ctx context.Context, username string, codeHash string, updatedAt time.Time) (bool, error) {
+	filter := bson.M{
+		"username":      username,
+		"enabled":       true,
+		"recoverycodes": codeHash,
+	}
+
+	return r.updateMatched(ctx, filter, bson.M{
+		"$pull": bson.M{"recoverycodes": codeHash},
+		"$set":  bson.M{"updatedat": updatedAt},
+	})
+}
+
+func (r UserTwoFactorRepository) UpdateRecoveryCodes(ctx context.Context, username string, codeHashes []string, updatedAt time.Time) error {
+
+	filterDoc := map[string]interface{}{
+		"username": username,
+	}
+
+	return r.pst.UpdateOne(ctx, &models.UserTwoFactorDoc{}, filterDoc, bson.M{"recoverycodes": codeHashes, "updatedat": updatedAt})
+}
+
+func (r UserTwoFactorRepository) updateMatched(ctx context.Context, filter bson.M, update bson.M) (bool, error) {
+	collection, err := r.pst.Exec(ctx, &models.UserTwoFactorDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
@@ -9,8 +9,12 @@ import (
 	"smlaicloudplatform/internal/authentication/repositories"
 	"smlaicloudplatform/internal/firebase"
 	"smlaicloudplatform/internal/logger"
+	guardmodels "smlaicloudplatform/internal/loginguard/models"
+	guardservices "smlaicloudplatform/internal/loginguard/services"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/internal/shop"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/passwordpolicy"
 	"smlaicloudplatform/pkg/microservice"
 	"strings"
 	"time"
@@ -29,17 +33,18 @@ type IAuthenticationService interface {
 	Register(userRequest auth_models.RegisterEmailRequest) (string, error)
 	ForgotPasswordByPhonenumber(userRequest auth_models.ForgotPasswordPhoneNumberRequest) error
 	Update(username string, userRequest auth_models.UserProfileRequest) error
-	UpdatePassword(username string, currentPassword string, newPassword string) error
-	Logout(authorizationHeader string) error
+	UpdatePassword(username string, currentPassword string, newPassword string, authorizationHeader string) error
+	Logout(username string, authorizationHeader string) error
 	Profile(username string) (auth_models.UserProfile, error)
 	AccessShop(shopID string, username string, authorizationHeader string, authContext models.AuthenticationContext) error
+	VerifyTwoFactor(username string, authorizationHeader string, code string, authContext models.AuthenticationContext) error
 	UpdateFavoriteShop(shopID string, username string, isFavorite bool) error
-	LoginWithFirebaseToken(token string) (string, error)
+	LoginWithFirebaseToken(token string, authContext models.AuthenticationContext) (string, error)
 	RefreshToken(tokenRequest models.TokenLoginRequest) (models.TokenLoginResponse, error)
 
 	CheckExistsUsername(username string) (bool, error)
 	CheckExistsPhonenumber(phoneNumber string) (bool, error)
-	SendPhonenumberOTP(otpRequest auth_models.OTPRequest) (auth_models.OTPResponse, error)
+	SendPhonenumberOTP(otpRequest auth_models.OTPRequest, ip string) (auth_models.OTPResponse, error)
 	RegisterByPhonenumber(userRequest auth_models.RegisterPhoneNumberRequest) (string, error)
 }
 
@@ -48,7 +53,11 @@ type AuthenticationService struct {
 	authRepo              repositories.IAuthenticationMongoCacheRepository
 	shopUserRepo          shop.IShopUserRepository
 	shopUserAccessLogRepo shop.IShopUserAccessLogRepository
+	shopRepo              shop.IShopRepository
 	smsRepo               repositories.IAuthenticationSMSRepository
+	twoFactorSvc          ITwoFactorService
+	loginGuard            guardservices.ILoginGuard
+	passwordPolicy        passwordpolicy.Policy
 	randdomString         func(int) string
 	randdomNumber         func(int) string
 	generateGUID          func() string
@@ -62,7 +71,11 @@ func NewAuthenticationService(
 	authRepo repositories.IAuthenticationRepository,
 	shopUserRepo shop.IShopUserRepository,
 	shopUserAccessLogRepo shop.IShopUserAccessLogRepository,
+	shopRepo shop.IShopRepository,
 	smsRepo repositories.IAuthenticationSMSRepository,
+	twoFactorSvc ITwoFactorService,
+	loginGuard guardservices.ILoginGuard,
+	passwordPolicy passwordpolicy.Policy,
 	authService microservice.IAuthService,
 	randdomString func(int) string,
 	randdomNumber func(int) string,
@@ -76,7 +89,11 @@ func NewAuthenticationService(
 		authService:           authService,
 		shopUserRepo:          shopUserRepo,
 		shopUserAccessLogRepo: shopUserAccessLogRepo,
+		shopRepo:              shopRepo,
 		smsRepo:               smsRepo,
+		twoFactorSvc:          twoFactorSvc,
+		loginGuard:            loginGuard,
+		passwordPolicy:        passwordPolicy,
 		randdomString:         randdomString,
 		randdomNumber:         randdomNumber,
 		generateGUID:          generateGUID,
@@ -93,6 +110,13 @@ func (svc AuthenticationService) ValidateOTP(refCode, OTP string) (bool, error)
 
 func (svc AuthenticationService) LoginWithPhoneNumberOTP(userLoginReq *auth_models.PhoneNumberOTPRequest, authContext models.AuthenticationContext) (models.TokenLoginResponse, error) {
 
+	authContext.AuthType = models.LoginTypePhoneOTP
+	attempt := newLoginAttempt(userLoginReq.PhoneNumber, "", authContext)
+
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
 	isOTPPassed, err := svc.ValidateOTP(userLoginReq.RefCode, userLoginReq.OTP)
 
 	if err != nil {
@@ -100,6 +124,7 @@ func (svc AuthenticationService) LoginWithPhoneNumberOTP(userLoginReq *auth_mode
 	}
 
 	if !isOTPPassed {
+		svc.loginGuard.Failed(attempt, "otp invalid")
 		return models.TokenLoginResponse{}, errors.New("OTP invalid")
 	}
 
@@ -110,30 +135,28 @@ func (svc AuthenticationService) LoginWithPhoneNumberOTP(userLoginReq *auth_mode
 	}
 
 	if len(findUser.PhoneNumber) < 1 {
+		svc.loginGuard.Failed(attempt, "user not found")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
-	tokenString, err := svc.authService.GenerateTokenWithRedis(microservice.AUTHTYPE_BEARER, micromodel.UserInfo{Username: findUser.Username, Name: findUser.Name})
-
-	if err != nil {
-		return models.TokenLoginResponse{}, errors.New("login failed")
-	}
-
-	refreshTokenString, err := svc.authService.GenerateTokenWithRedis(microservice.AUTHTYPE_REFRESH, micromodel.UserInfo{Username: findUser.Username, Name: findUser.Name})
+	resultLogin, err := svc.guardedUserLogin(attempt, *findUser, "", userLoginReq.TwoFactorCode, authContext)
 
 	if err != nil {
-		svc.authService.DeleteToken(microservice.AUTHTYPE_BEARER, tokenString)
-		return models.TokenLoginResponse{}, errors.New("login failed")
+		return models.TokenLoginResponse{}, err
 	}
 
-	return models.TokenLoginResponse{
-		Token:   tokenString,
-		Refresh: refreshTokenString,
-	}, nil
+	return resultLogin, nil
 }
 
 func (svc AuthenticationService) LoginWithPhoneNumber(userLoginReq *auth_models.UserLoginPhoneNumberRequest, authContext models.AuthenticationContext) (models.TokenLoginResponse, error) {
 
+	authContext.AuthType = models.LoginTypePhoneNumber
+	attempt := newLoginAttempt(userLoginReq.CountryCode+userLoginReq.PhoneNumber, userLoginReq.ShopID, authContext)
+
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
 	findUser, err := svc.authRepo.FindByPhonenumber(context.Background(), userLoginReq.PhoneNumberField)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
@@ -141,16 +164,18 @@ func (svc AuthenticationService) LoginWithPhoneNumber(userLoginReq *auth_models.
 	}
 
 	if len(findUser.PhoneNumber) < 1 {
+		svc.loginGuard.Failed(attempt, "user not found")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
 	passwordInvalid := !svc.checkHashPassword(userLoginReq.Password, findUser.Password)
 
 	if passwordInvalid {
+		svc.loginGuard.Failed(attempt, "password invalid")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
-	resultLogin, err := svc.processUserLogin(*findUser, userLoginReq.ShopID, authContext)
+	resultLogin, err := svc.guardedUserLogin(attempt, *findUser, userLoginReq.ShopID, userLoginReq.TwoFactorCode, authContext)
 
 	if err != nil {
 		return models.TokenLoginResponse{}, err
@@ -166,6 +191,13 @@ func (svc AuthenticationService) Login(userLoginReq *auth_models.UserLoginReques
 	userLoginReq.Username = strings.TrimSpace(userLoginReq.Username)
 	userLoginReq.ShopID = strings.TrimSpace(userLoginReq.ShopID)
 
+	authContext.AuthType = models.LoginTypePassword
+	attempt := newLoginAttempt(userLoginReq.Username, userLoginReq.ShopID, authContext)
+
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
 	findUser, err := svc.authRepo.FindUser(context.Background(), userLoginReq.Username)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
@@ -174,16 +206,18 @@ func (svc AuthenticationService) Login(userLoginReq *auth_models.UserLoginReques
 	}
 
 	if len(findUser.Username) < 1 {
+		svc.loginGuard.Failed(attempt, "user not found")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
 	passwordInvalid := !svc.checkHashPassword(userLoginReq.Password, findUser.Password)
 
 	if passwordInvalid {
+		svc.loginGuard.Failed(attempt, "password invalid")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
-	resultLogin, err := svc.processUserLogin(*findUser, userLoginReq.ShopID, authContext)
+	resultLogin, err := svc.guardedUserLogin(attempt, *findUser, userLoginReq.ShopID, userLoginReq.TwoFactorCode, authContext)
 
 	if err != nil {
 		return models.TokenLoginResponse{}, err
@@ -199,6 +233,13 @@ func (svc AuthenticationService) Poslogin(userLoginReq *auth_models.PosLoginRequ
 	userLoginReq.Username = strings.TrimSpace(userLoginReq.Username)
 	userLoginReq.ShopID = strings.TrimSpace(userLoginReq.ShopID)
 
+	authContext.AuthType = models.LoginTypePOS
+	attempt := newLoginAttempt(userLoginReq.Username, userLoginReq.ShopID, authContext)
+
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
 	findUser, err := svc.authRepo.FindUser(context.Background(), userLoginReq.Username)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
@@ -207,6 +248,7 @@ func (svc AuthenticationService) Poslogin(userLoginReq *auth_models.PosLoginRequ
 	}
 
 	if len(findUser.Username) < 1 {
+		svc.loginGuard.Failed(attempt, "user not found")
 		return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	}
 
@@ -216,7 +258,7 @@ func (svc AuthenticationService) Poslogin(userLoginReq *auth_models.PosLoginRequ
 	// 	return models.TokenLoginResponse{}, errors.New("username or password is invalid")
 	// }
 
-	resultLogin, err := svc.processUserLogin(*findUser, userLoginReq.ShopID, authContext)
+	resultLogin, err := svc.guardedUserLogin(attempt, *findUser, userLoginReq.ShopID, userLoginReq.TwoFactorCode, authContext)
 
 	if err != nil {
 		return models.TokenLoginResponse{}, err
@@ -232,6 +274,12 @@ func (svc AuthenticationService) LoginEmail(userLoginReq *auth_models.PosLoginRe
 	userLoginReq.Username = strings.TrimSpace(userLoginReq.Username)
 	userLoginReq.ShopID = strings.TrimSpace(userLoginReq.ShopID)
 
+	// locked out user cannot login by email either
+	authContext.AuthType = models.LoginTypeEmail
+	if err := svc.loginGuard.Check(newLoginAttempt(userLoginReq.Username, userLoginReq.ShopID, authContext)); err != nil {
+		return "", err
+	}
+
 	findUser, err := svc.authRepo.FindUser(context.Background(), userLoginReq.Username)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
@@ -262,10 +310,50 @@ func (svc AuthenticationService) LoginEmail(userLoginReq *auth_models.PosLoginRe
 	if err != nil {
 		return "", errors.New("generate token error")
 	}
+
+	err = svc.createSession(findUser.Username, tokenString, "", authContext)
+
+	if err != nil {
+		return "", errors.New("generate token error")
+	}
+
 	return tokenString, nil
 }
 
-func (svc *AuthenticationService) processUserLogin(findUser auth_models.UserDoc, shopID string, authContext models.AuthenticationContext) (models.TokenLoginResponse, error) {
+func newLoginAttempt(username string, shopID string, authContext models.AuthenticationContext) guardmodels.LoginAttempt {
+	return guardmodels.LoginAttempt{
+		Scope:    guardmodels.ScopeUser,
+		Username: username,
+		IP:       authContext.Ip,
+		ShopID:   shopID,
+		AuthType: authContext.AuthType,
+	}
+}
+
+// guardedUserLogin login the user which pass password or otp check, invalid two factor code is counted as failed attempt
+func (svc *AuthenticationService) guardedUserLogin(attempt guardmodels.LoginAttempt, findUser auth_models.UserDoc, shopID string, twoFactorCode string, authContext models.AuthenticationContext) (models.TokenLoginResponse, error) {
+	resultLogin, err := svc.processUserLogin(findUser, shopID, twoFactorCode, authContext)
+
+	if errors.Is(err, ErrTwoFactorCodeInvalid) {
+		svc.loginGuard.Failed(attempt, "two factor code invalid")
+	}
+
+	if err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
+	svc.loginGuard.Succeeded(attempt)
+
+	return resultLogin, nil
+}
+
+func (svc *AuthenticationService) processUserLogin(findUser auth_models.UserDoc, shopID string, twoFactorCode string, authContext models.AuthenticationContext) (models.TokenLoginResponse, error) {
+	twoFactorVerified, err := svc.twoFactorSvc.VerifyLogin(context.Background(), findUser.Username, twoFactorCode)
+
+	if err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
 	tokenString, err := svc.authService.GenerateTokenWithRedis(microservice.AUTHTYPE_BEARER, micromodel.UserInfo{Username: findUser.Username, Name: findUser.Name})
 
 	if err != nil {
@@ -279,20 +367,46 @@ func (svc *AuthenticationService) processUserLogin(findUser auth_models.UserDoc,
 		return models.TokenLoginResponse{}, errors.New("login failed")
 	}
 
+	if twoFactorVerified {
+		err = svc.markTwoFactorVerified(tokenString, refreshTokenString)
+
+		if err != nil {
+			svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
+			return models.TokenLoginResponse{}, errors.New("login failed")
+		}
+	}
+
+	err = svc.createSession(findUser.Username, tokenString, refreshTokenString, authContext)
+
+	if err != nil {
+		svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
+		return models.TokenLoginResponse{}, errors.New("login failed")
+	}
+
 	if len(shopID) > 0 {
 		shopUser, err := svc.shopUserRepo.FindByShopIDAndUsername(context.Background(), shopID, findUser.Username)
 
 		if err != nil {
+			svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
 			return models.TokenLoginResponse{}, err
 		}
 
 		if shopUser.ID == primitive.NilObjectID {
+			svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
 			return models.TokenLoginResponse{}, errors.New("shop invalid")
 		}
 
+		err = svc.checkShopTwoFactor(shopUser, twoFactorVerified)
+
+		if err != nil {
+			svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
+			return models.TokenLoginResponse{}, err
+		}
+
 		err = svc.authService.SelectShop(microservice.AUTHTYPE_BEARER, tokenString, shopID, shopUser.Role)
 
 		if err != nil {
+			svc.discardLogin(findUser.Username, tokenString, refreshTokenString)
 			return models.TokenLoginResponse{}, errors.New("failed shop select")
 		}
 
@@ -318,8 +432,113 @@ func (svc *AuthenticationService) processUserLogin(findUser auth_models.UserDoc,
 	return models.TokenLoginResponse{Token: tokenString, Refresh: refreshTokenString}, nil
 }
 
+// createSession register tokens of the login so the user can see and revoke it
+func (svc AuthenticationService) createSession(username string, tokenString string, refreshTokenString string, authContext models.AuthenticationContext) error {
+	_, err := svc.authService.CreateSession(tokenString, refreshTokenString, micromodel.UserSession{
+		Username:   username,
+		DeviceName: authContext.DeviceName,
+		IP:         authContext.Ip,
+		AuthType:   authContext.AuthType,
+		CreatedAt:  svc.timeNow(),
+	})
+
+	return err
+}
+
+// discardLogin delete tokens and session of the login which is not returned to the user
+func (svc AuthenticationService) discardLogin(username string, tokenString string, refreshTokenString string) {
+	sessionID, err := svc.authService.GetTokenSessionID(microservice.AUTHTYPE_BEARER, tokenString)
+	if err == nil && sessionID != "" {
+		err = svc.authService.RevokeSession(username, sessionID)
+		if err == nil {
+			return
+		}
+	}
+
+	if err != nil {
+		logger.GetLogger().Errorf("Discard login of %s: %v", username, err)
+	}
+
+	// tokens which are not registered to session are deleted one by one
+	err = svc.authService.DeleteToken(microservice.AUTHTYPE_BEARER, tokenString)
+	if err != nil {
+		logger.GetLogger().Errorf("Discard login of %s: %v", username, err)
+	}
+
+	err = svc.authService.DeleteToken(microservice.AUTHTYPE_REFRESH, refreshTokenString)
+	if err != nil {
+		logger.GetLogger().Errorf("Discard login of %s: %v", username, err)
+	}
+}
+
+func (svc AuthenticationService) markTwoFactorVerified(tokenString string, refreshTokenString string) error {
+	err := svc.authService.SetTwoFactorVerified(microservice.AUTHTYPE_BEARER, tokenString)
+
+	if err != nil {
+		return err
+	}
+
+	return svc.authService.SetTwoFactorVerified(microservice.AUTHTYPE_REFRESH, refreshTokenString)
+}
+
+// checkShopTwoFactor deny shop of token which is not verified by two factor code when the user enables it,
+// or when the shop requires it for owner and admin
+func (svc AuthenticationService) checkShopTwoFactor(shopUser auth_models.ShopUser, twoFactorVerified bool) error {
+	if twoFactorVerified {
+		return nil
+	}
+
+	twoFactorEnabled, err := svc.twoFactorSvc.IsEnabled(context.Background(), shopUser.Username)
+
+	if err != nil {
+		return err
+	}
+
+	if twoFactorEnabled {
+		return ErrTwoFactorRequired
+	}
+
+	if !rbacmodels.HasShopUserRole(shopUser, rbacmodels.RoleOwner, rbacmodels.RoleAdmin) {
+		return nil
+	}
+
+	findShop, err := svc.shopRepo.FindByGuid(context.Background(), shopUser.ShopID)
+
+	if err != nil {
+		return err
+	}
+
+	if findShop.Settings.RequireTwoFactor {
+		return errors.New("shop requires two factor authentication for owner and admin")
+	}
+
+	return nil
+}
+
 func (svc AuthenticationService) RefreshToken(tokenRequest models.TokenLoginRequest) (models.TokenLoginResponse, error) {
 
+	username, twoFactorVerified, err := svc.authService.GetTokenTwoFactor(microservice.AUTHTYPE_REFRESH, tokenRequest.Token)
+
+	if err != nil {
+		return models.TokenLoginResponse{}, err
+	}
+
+	if username == "" {
+		return models.TokenLoginResponse{}, errors.New("refresh token invalid")
+	}
+
+	if !twoFactorVerified {
+		twoFactorEnabled, err := svc.twoFactorSvc.IsEnabled(context.Background(), username)
+
+		if err != nil {
+			return models.TokenLoginResponse{}, err
+		}
+
+		if twoFactorEnabled {
+			return models.TokenLoginResponse{}, ErrTwoFactorRequired
+		}
+	}
+
 	token, refreshToken, err := svc.authService.RefreshToken(tokenRequest.Token)
 
 	if err != nil {
@@ -345,6 +564,10 @@ func (svc AuthenticationService) Register(userEmailRequest auth_models.RegisterE
 		return "", errors.New("username is exists")
 	}
 
+	if err := svc.passwordPolicy.Validate(userEmailRequest.Password); err != nil {
+		return "", err
+	}
+
 	hashPassword, err := svc.passwordEncoder(userEmailRequest.Password)
 
 	if err != nil {
@@ -402,11 +625,16 @@ func (svc AuthenticationService) CheckExistsPhonenumber(phoneNumber string) (boo
 	return false, nil
 }
 
-func (svc AuthenticationService) SendPhonenumberOTP(otpRequest auth_models.OTPRequest) (auth_models.OTPResponse, error) {
+func (svc AuthenticationService) SendPhonenumberOTP(otpRequest auth_models.OTPRequest, ip string) (auth_models.OTPResponse, error) {
 
 	otpRequest.PhoneNumber = utils.NormalizePhonenumber(otpRequest.PhoneNumber)
 
 	fullPhoneNumber := fmt.Sprintf("%s%s", otpRequest.CountryCode, otpRequest.PhoneNumber)
+
+	if err := svc.loginGuard.AllowSendOTP(fullPhoneNumber, ip); err != nil {
+		return auth_models.OTPResponse{}, err
+	}
+
 	result, err := svc.smsRepo.SendOTPViaLink(fullPhoneNumber)
 
 	if err != nil {
@@ -442,6 +670,10 @@ func (svc AuthenticationService) RegisterByPhonenumber(userRequest auth_models.R
 		return "", errors.New("phonenumber is exists")
 	}
 
+	if err := svc.passwordPolicy.Validate(userRequest.Password); err != nil {
+		return "", err
+	}
+
 	hashPassword, err := svc.passwordEncoder(userRequest.Password)
 
 	if err != nil {
@@ -499,6 +731,10 @@ func (svc AuthenticationService) ForgotPasswordByPhonenumber(userRequest auth_mo
 		return errors.New("phone number is not exists")
 	}
 
+	if err := svc.passwordPolicy.Validate(userRequest.Password); err != nil {
+		return err
+	}
+
 	hashPassword, err := svc.passwordEncoder(userRequest.Password)
 
 	if err != nil {
@@ -513,7 +749,7 @@ func (svc AuthenticationService) ForgotPasswordByPhonenumber(userRequest auth_mo
 		return err
 	}
 
-	return nil
+	return svc.authService.RevokeSessions(userFind.Username, "")
 }
 
 func (svc AuthenticationService) Update(username string, userRequest auth_models.UserProfileRequest) error {
@@ -543,7 +779,7 @@ func (svc AuthenticationService) Update(username string, userRequest auth_models
 	return nil
 }
 
-func (svc AuthenticationService) UpdatePassword(username string, currentPassword string, newPassword string) error {
+func (svc AuthenticationService) UpdatePassword(username string, currentPassword string, newPassword string, authorizationHeader string) error {
 
 	if username == "" {
 		return errors.New("username invalid")
@@ -564,6 +800,10 @@ func (svc AuthenticationService) UpdatePassword(username string, currentPassword
 		return errors.New("current password invalid")
 	}
 
+	if err := svc.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
 	hashPassword, err := svc.passwordEncoder(newPassword)
 
 	if err != nil {
@@ -579,10 +819,39 @@ func (svc AuthenticationService) UpdatePassword(username string, currentPassword
 		return err
 	}
 
-	return nil
+	// other devices have to login with the new password
+	currentSessionID := ""
+	tokenStr, err := svc.authService.GetTokenFromAuthorizationHeader(microservice.AUTHTYPE_BEARER, authorizationHeader)
+
+	if err == nil {
+		currentSessionID, err = svc.authService.GetTokenSessionID(microservice.AUTHTYPE_BEARER, tokenStr)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return svc.authService.RevokeSessions(username, currentSessionID)
 }
 
-func (svc AuthenticationService) Logout(authorizationHeader string) error {
+func (svc AuthenticationService) Logout(username string, authorizationHeader string) error {
+	tokenStr, err := svc.authService.GetTokenFromAuthorizationHeader(microservice.AUTHTYPE_BEARER, authorizationHeader)
+
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := svc.authService.GetTokenSessionID(microservice.AUTHTYPE_BEARER, tokenStr)
+
+	if err != nil {
+		return err
+	}
+
+	// refresh token of the session is revoked with the token
+	if username != "" && sessionID != "" {
+		return svc.authService.RevokeSession(username, sessionID)
+	}
+
 	return svc.authService.ExpireToken(microservice.AUTHTYPE_BEARER, authorizationHeader)
 }
 
@@ -629,6 +898,18 @@ func (svc AuthenticationService) AccessShop(shopID string, username string, auth
 		return errors.New("shop invalid")
 	}
 
+	_, twoFactorVerified, err := svc.authService.GetTokenTwoFactor(microservice.AUTHTYPE_BEARER, tokenStr)
+
+	if err != nil {
+		return err
+	}
+
+	err = svc.checkShopTwoFactor(shopUser, twoFactorVerified)
+
+	if err != nil {
+		return err
+	}
+
 	err = svc.authService.SelectShop(microservice.AUTHTYPE_BEARER, tokenStr, shopID, shopUser.Role)
 
 	if err != nil {
@@ -657,6 +938,55 @@ func (svc AuthenticationService) AccessShop(shopID string, username string, auth
 	return nil
 }
 
+// VerifyTwoFactor mark the token as verified by two factor code, it is used by logins which do not take the code
+// such as email and firebase login so the user can select shop which requires two factor authentication
+func (svc AuthenticationService) VerifyTwoFactor(username string, authorizationHeader string, code string, authContext models.AuthenticationContext) error {
+	if username == "" {
+		return errors.New("username invalid")
+	}
+
+	tokenStr, err := svc.authService.GetTokenFromAuthorizationHeader(microservice.AUTHTYPE_BEARER, authorizationHeader)
+
+	if err != nil {
+		return err
+	}
+
+	if len(tokenStr) < 1 {
+		return errors.New("token invalid")
+	}
+
+	authContext.AuthType = models.LoginTypeTwoFactor
+	attempt := newLoginAttempt(username, "", authContext)
+
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
+		return err
+	}
+
+	twoFactorEnabled, err := svc.twoFactorSvc.VerifyLogin(context.Background(), username, code)
+
+	if errors.Is(err, ErrTwoFactorCodeInvalid) {
+		svc.loginGuard.Failed(attempt, "two factor code invalid")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !twoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	err = svc.authService.SetTwoFactorVerified(microservice.AUTHTYPE_BEARER, tokenStr)
+
+	if err != nil {
+		return err
+	}
+
+	svc.loginGuard.Succeeded(attempt)
+
+	return nil
+}
+
 func (svc AuthenticationService) UpdateFavoriteShop(shopID string, username string, isFavorite bool) error {
 
 	if shopID == "" {
@@ -685,7 +1015,7 @@ func (svc AuthenticationService) UpdateFavoriteShop(shopID string, username stri
 	return nil
 }
 
-func (svc AuthenticationService) LoginWithFirebaseToken(token string) (string, error) {
+func (svc AuthenticationService) LoginWithFirebaseToken(token string, authContext models.AuthenticationContext) (string, error) {
 
 	userInfo, err := svc.firebaseAdapter.ValidateToken(token)
 	if err != nil {
@@ -723,5 +1053,12 @@ func (svc AuthenticationService) LoginWithFirebaseToken(token string) (string, e
 		return "", errors.New("generate token error")
 	}
 
+	authContext.AuthType = models.LoginTypeFirebase
+	err = svc.createSession(userFind.Username, tokenString, "", authContext)
+
+	if err != nil {
+		return "", errors.New("generate token error")
+	}
+
 	return tokenString, nil
 }
@@ -6,6 +6,9 @@ import (
 	"smlaicloudplatform/internal/authentication/models"
 	"smlaicloudplatform/internal/authentication/services"
 	"smlaicloudplatform/internal/firebase"
+	guardmodels "smlaicloudplatform/internal/loginguard/models"
+	shopmodels "smlaicloudplatform/internal/shop/models"
+	"smlaicloudplatform/internal/utils/passwordpolicy"
 	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"testing"
@@ -24,6 +27,7 @@ func mockLoginData(authRepo *AuthenticationRepositoryMock, shopUserRepo *ShopUse
 	role := uint8(2)
 
 	tokenMock := "TOKEN_MOCK"
+	refreshTokenMock := "REFRESH_TOKEN_MOCK"
 	//authRepo FindUser
 	userDoc1 := models.UserDoc{}
 	userDoc1.Username = "tester1"
@@ -35,31 +39,42 @@ func mockLoginData(authRepo *AuthenticationRepositoryMock, shopUserRepo *ShopUse
 	authRepo.On("FindUser", "tester2").Return(&models.UserDoc{}, nil)
 
 	authRepo.On("FindUser", "user_register").Return(&models.UserDoc{}, nil)
+	authRepo.On("FindByIdentity", "email", "user_register").Return(&models.UserDoc{}, nil)
+	authRepo.On("FindByIdentity", "email", userDoc1.Username).Return(&userDoc1, nil)
 
 	//authRepo CreateUser
 	userDoc2 := models.UserDoc{}
 	userDoc2.Username = "user_register"
 	userDoc2.Password = "register_password_success"
 	userDoc2.Name = "user_register"
+	userDoc2.UID = MockGUID()
+	userDoc2.Email = "user_register"
 	userDoc2.CreatedAt = MockTime()
 
 	authRepo.On("CreateUser", userDoc2).Return(MockObjectID(), nil)
 
 	//microAuth
-	microAuthServiceMock.On("GenerateTokenWithRedis", micromodels.UserInfo{
+	userInfo1 := micromodels.UserInfo{
 		Username: userDoc1.Username,
 		Name:     userDoc1.Name,
-	}).Return(tokenMock, nil)
+	}
+	microAuthServiceMock.On("GenerateTokenWithRedis", microservice.AUTHTYPE_BEARER, userInfo1).Return(tokenMock, nil)
+	microAuthServiceMock.On("GenerateTokenWithRedis", microservice.AUTHTYPE_REFRESH, userInfo1).Return(refreshTokenMock, nil)
+	microAuthServiceMock.On("CreateSession", tokenMock, refreshTokenMock, mock.Anything).Return("SESSION_MOCK", nil)
+	microAuthServiceMock.On("GetTokenSessionID", microservice.AUTHTYPE_BEARER, tokenMock).Return("SESSION_MOCK", nil)
+	microAuthServiceMock.On("RevokeSession", userDoc1.Username, "SESSION_MOCK").Return(nil)
 
-	microAuthServiceMock.On("SelectShop", tokenMock, shopID, role).Return(nil)
+	microAuthServiceMock.On("SelectShop", microservice.AUTHTYPE_BEARER, tokenMock, shopID, role).Return(nil)
 
 	shopUser := models.ShopUser{}
+	shopUser.ID = MockObjectID()
 	shopUser.Username = userDoc1.Username
 	shopUser.ShopID = shopID
 	shopUser.Role = role
 
 	//shopUser
 	shopUserRepo.On("FindByShopIDAndUsername", shopID, userDoc1.Username).Return(shopUser, nil)
+	shopUserRepo.On("UpdateLastAccess", shopID, userDoc1.Username, MockTime()).Return(nil)
 
 	shopUserRepo.On("FindByShopIDAndUsername", "SHOP_ID_INVALID", userDoc1.Username).Return(models.ShopUser{}, nil)
 }
@@ -71,9 +86,13 @@ func TestAuthService_Login(t *testing.T) {
 	shopUserRepo := new(ShopUserRepositoryMock)
 	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
 	smsRepo := new(SMSRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	twoFactorSvc := newTwoFactorServiceMock()
 	microAuthServiceMock := &AuthServiceMock{}
 
 	mockLoginData(authRepo, shopUserRepo, microAuthServiceMock)
+	shopRepo.On("FindByGuid", shopID).Return(shopmodels.ShopDoc{}, nil)
+	shopUserAccessLogRepo.On("Create", mock.Anything).Return(nil)
 
 	type args struct {
 		username string
@@ -152,7 +171,11 @@ func TestAuthService_Login(t *testing.T) {
 		authRepo,
 		shopUserRepo,
 		shopUserAccessLogRepo,
+		shopRepo,
 		smsRepo,
+		twoFactorSvc,
+		newLoginGuardMock(),
+		passwordpolicy.Policy{},
 		microAuthServiceMock,
 		MockRandomString,
 		MockRandomNumber,
@@ -181,7 +204,7 @@ func TestAuthService_Login(t *testing.T) {
 			} else {
 				assert.Nil(t, err)
 				assert.NotEmpty(t, tokenResult)
-				assert.EqualValues(t, tt.wantData, tokenResult)
+				assert.EqualValues(t, tt.wantData, tokenResult.Token)
 			}
 		})
 	}
@@ -192,6 +215,8 @@ func TestAuthService_Register(t *testing.T) {
 	shopUserRepo := new(ShopUserRepositoryMock)
 	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
 	smsRepo := new(SMSRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	twoFactorSvc := newTwoFactorServiceMock()
 	microAuthServiceMock := &AuthServiceMock{}
 
 	mockLoginData(authRepo, shopUserRepo, microAuthServiceMock)
@@ -236,7 +261,11 @@ func TestAuthService_Register(t *testing.T) {
 				authRepo,
 				shopUserRepo,
 				shopUserAccessLogRepo,
+				shopRepo,
 				smsRepo,
+				twoFactorSvc,
+				newLoginGuardMock(),
+				passwordpolicy.Policy{},
 				microAuthServiceMock,
 				MockRandomString,
 				MockRandomNumber,
@@ -270,6 +299,8 @@ func TestAuthService_Update(t *testing.T) {
 	shopUserRepo := new(ShopUserRepositoryMock)
 	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
 	smsRepo := new(SMSRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	twoFactorSvc := newTwoFactorServiceMock()
 	microAuthServiceMock := &AuthServiceMock{}
 
 	userDoc := &models.UserDoc{}
@@ -313,7 +344,11 @@ func TestAuthService_Update(t *testing.T) {
 				authRepo,
 				shopUserRepo,
 				shopUserAccessLogRepo,
+				shopRepo,
 				smsRepo,
+				twoFactorSvc,
+				newLoginGuardMock(),
+				passwordpolicy.Policy{},
 				microAuthServiceMock,
 				MockRandomString,
 				MockRandomNumber,
@@ -343,6 +378,8 @@ func TestAuthService_UpdatePassword(t *testing.T) {
 	shopUserRepo := new(ShopUserRepositoryMock)
 	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
 	smsRepo := new(SMSRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	twoFactorSvc := newTwoFactorServiceMock()
 	microAuthServiceMock := &AuthServiceMock{}
 
 	userDoc := &models.UserDoc{}
@@ -360,6 +397,10 @@ func TestAuthService_UpdatePassword(t *testing.T) {
 
 	authRepo.On("UpdateUser", "user_update", userDocUpdate).Return(nil)
 
+	microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, "Bearer token_update").Return("token_update", nil)
+	microAuthServiceMock.On("GetTokenSessionID", microservice.AUTHTYPE_BEARER, "token_update").Return("session_update", nil)
+	microAuthServiceMock.On("RevokeSessions", "user_update", "session_update").Return(nil)
+
 	type args struct {
 		username        string
 		currentPassword string
@@ -397,7 +438,11 @@ func TestAuthService_UpdatePassword(t *testing.T) {
 				authRepo,
 				shopUserRepo,
 				shopUserAccessLogRepo,
+				shopRepo,
 				smsRepo,
+				twoFactorSvc,
+				newLoginGuardMock(),
+				passwordpolicy.Policy{},
 				microAuthServiceMock,
 				MockRandomString,
 				MockRandomNumber,
@@ -406,7 +451,7 @@ func TestAuthService_UpdatePassword(t *testing.T) {
 				MockCheckPasswordHash,
 				MockTime, MockFirebaseAdapter())
 
-			err := authService.UpdatePassword(tt.args.username, tt.args.currentPassword, tt.args.newPassword)
+			err := authService.UpdatePassword(tt.args.username, tt.args.currentPassword, tt.args.newPassword, "Bearer token_update")
 
 			if tt.wantErr {
 				assert.NotNil(t, err)
@@ -423,10 +468,13 @@ func TestAuthService_AccessShop(t *testing.T) {
 	shopUserRepo := new(ShopUserRepositoryMock)
 	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
 	smsRepo := new(SMSRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	twoFactorSvc := newTwoFactorServiceMock()
 	microAuthServiceMock := &AuthServiceMock{}
 
-	microAuthServiceMock.On("GetTokenFromAuthorizationHeader", "authorization_header_valid").Return("valid_token", nil)
-	microAuthServiceMock.On("GetTokenFromAuthorizationHeader", "").Return("", errors.New("authorization is not empty"))
+	microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, "authorization_header_valid").Return("valid_token", nil)
+	microAuthServiceMock.On("GetTokenTwoFactor", microservice.AUTHTYPE_BEARER, "valid_token").Return("user_access_shop", false, nil)
+	microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, "").Return("", errors.New("authorization is not empty"))
 
 	shopUser := models.ShopUser{}
 	shopUser.ID = MockObjectID()
@@ -437,9 +485,11 @@ func TestAuthService_AccessShop(t *testing.T) {
 	shopUserRepo.On("FindByShopIDAndUsername", "shop_test", "user_access_shop").Return(shopUser, nil)
 
 	shopUserRepo.On("FindByShopIDAndUsername", "shop_test_invalid", "user_access_shop").Return(models.ShopUser{}, nil)
+	shopUserRepo.On("UpdateLastAccess", "shop_test", "user_access_shop", MockTime()).Return(nil)
+	shopUserAccessLogRepo.On("Create", mock.Anything).Return(nil)
 
-	microAuthServiceMock.On("SelectShop", "valid_token", "shop_test", uint8(0)).Return(nil)
-	microAuthServiceMock.On("SelectShop", "valid_token_invalid", "shop_test_invalid", uint8(0)).Return(errors.New("select shop failed"))
+	microAuthServiceMock.On("SelectShop", microservice.AUTHTYPE_BEARER, "valid_token", "shop_test", uint8(0)).Return(nil)
+	microAuthServiceMock.On("SelectShop", microservice.AUTHTYPE_BEARER, "valid_token_invalid", "shop_test_invalid", uint8(0)).Return(errors.New("select shop failed"))
 
 	type args struct {
 		shopID              string
@@ -500,7 +550,11 @@ func TestAuthService_AccessShop(t *testing.T) {
 				authRepo,
 				shopUserRepo,
 				shopUserAccessLogRepo,
+				shopRepo,
 				smsRepo,
+				twoFactorSvc,
+				newLoginGuardMock(),
+				passwordpolicy.Policy{},
 				microAuthServiceMock,
 				MockRandomString,
 				MockRandomNumber,
@@ -521,6 +575,156 @@ func TestAuthService_AccessShop(t *testing.T) {
 	}
 }
 
+func TestAuthService_TwoFactorPolicy(t *testing.T) {
+	shopUserRepo := new(ShopUserRepositoryMock)
+	shopUserAccessLogRepo := new(ShopUserAccessLogRepositoryMock)
+	shopRepo := new(ShopRepositoryMock)
+	microAuthServiceMock := &AuthServiceMock{}
+
+	twoFactorSvc := &TwoFactorServiceMock{}
+	twoFactorSvc.On("IsEnabled", "user_2fa").Return(true, nil)
+	twoFactorSvc.On("IsEnabled", mock.Anything).Return(false, nil)
+
+	shopDoc := shopmodels.ShopDoc{}
+	shopDoc.GuidFixed = "shop_2fa"
+	shopDoc.Settings.RequireTwoFactor = true
+	shopRepo.On("FindByGuid", "shop_2fa").Return(shopDoc, nil)
+	shopRepo.On("FindByGuid", "shop_test").Return(shopmodels.ShopDoc{}, nil)
+
+	shopUsers := map[string]models.ShopUser{}
+	for _, shopUser := range []models.ShopUser{
+		{ID: MockObjectID(), ShopUserBase: models.ShopUserBase{ShopID: "shop_2fa", Username: "owner", Role: models.ROLE_OWNER}},
+		{ID: MockObjectID(), ShopUserBase: models.ShopUserBase{ShopID: "shop_2fa", Username: "user", Role: models.ROLE_USER}},
+		{ID: MockObjectID(), ShopUserBase: models.ShopUserBase{ShopID: "shop_2fa", Username: "user_admin_role", Role: models.ROLE_USER, Roles: []string{"ADMIN"}}},
+		{ID: MockObjectID(), ShopUserBase: models.ShopUserBase{ShopID: "shop_test", Username: "user_2fa", Role: models.ROLE_USER}},
+	} {
+		shopUsers[shopUser.Username] = shopUser
+		shopUserRepo.On("FindByShopIDAndUsername", shopUser.ShopID, shopUser.Username).Return(shopUser, nil)
+		shopUserRepo.On("UpdateLastAccess", shopUser.ShopID, shopUser.Username, MockTime()).Return(nil)
+		microAuthServiceMock.On("SelectShop", microservice.AUTHTYPE_BEARER, "token_"+shopUser.Username, shopUser.ShopID, shopUser.Role).Return(nil)
+		microAuthServiceMock.On("SelectShop", microservice.AUTHTYPE_BEARER, "verified_token_"+shopUser.Username, shopUser.ShopID, shopUser.Role).Return(nil)
+	}
+	shopUserAccessLogRepo.On("Create", mock.Anything).Return(nil)
+
+	ownerDoc := models.UserDoc{}
+	ownerDoc.Username = "owner"
+	ownerDoc.Password = "owner"
+	ownerDoc.Name = "owner"
+	authRepo := new(AuthenticationRepositoryMock)
+	authRepo.On("FindUser", "owner").Return(&ownerDoc, nil)
+
+	twoFactorSvc.On("VerifyLogin", "owner", "").Return(false, nil)
+	twoFactorSvc.On("VerifyLogin", "user_2fa", "123456").Return(true, nil)
+	twoFactorSvc.On("VerifyLogin", "user_2fa", "000000").Return(true, services.ErrTwoFactorCodeInvalid)
+	twoFactorSvc.On("VerifyLogin", "user", "123456").Return(false, nil)
+
+	authService := services.NewAuthenticationService(
+		authRepo,
+		shopUserRepo,
+		shopUserAccessLogRepo,
+		shopRepo,
+		new(SMSRepositoryMock),
+		twoFactorSvc,
+		newLoginGuardMock(),
+		passwordpolicy.Policy{},
+		microAuthServiceMock,
+		MockRandomString,
+		MockRandomNumber,
+		MockGUID,
+		MockHashPassword,
+		MockCheckPasswordHash,
+		MockTime,
+		MockFirebaseAdapter())
+
+	cases := []struct {
+		name     string
+		username string
+		verified bool
+		wantErr  bool
+	}{
+		{name: "owner is required two factor by shop", username: "owner", wantErr: true},
+		{name: "owner with verified token", username: "owner", verified: true, wantErr: false},
+		{name: "user is not required two factor by shop", username: "user", wantErr: false},
+		{name: "admin role of rbac is required two factor by shop", username: "user_admin_role", wantErr: true},
+		{name: "user who enables two factor", username: "user_2fa", wantErr: true},
+		{name: "user who enables two factor with verified token", username: "user_2fa", verified: true, wantErr: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenStr := "token_" + tt.username
+			if tt.verified {
+				tokenStr = "verified_" + tokenStr
+			}
+
+			authorizationHeader := "Bearer " + tokenStr
+			microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, authorizationHeader).Return(tokenStr, nil)
+			microAuthServiceMock.On("GetTokenTwoFactor", microservice.AUTHTYPE_BEARER, tokenStr).Return(tt.username, tt.verified, nil)
+
+			shopID := shopUsers[tt.username].ShopID
+			err := authService.AccessShop(shopID, tt.username, authorizationHeader, models.AuthenticationContext{Ip: "localhost"})
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+
+	t.Run("refresh token of user who enables two factor", func(t *testing.T) {
+		microAuthServiceMock.On("GetTokenTwoFactor", microservice.AUTHTYPE_REFRESH, "refresh_user_2fa").Return("user_2fa", false, nil)
+		microAuthServiceMock.On("GetTokenTwoFactor", microservice.AUTHTYPE_REFRESH, "verified_refresh_user_2fa").Return("user_2fa", true, nil)
+		microAuthServiceMock.On("RefreshToken", "verified_refresh_user_2fa").Return("new_token", "new_refresh", nil)
+
+		_, err := authService.RefreshToken(models.TokenLoginRequest{Token: "refresh_user_2fa"})
+		assert.Equal(t, services.ErrTwoFactorRequired, err)
+
+		result, err := authService.RefreshToken(models.TokenLoginRequest{Token: "verified_refresh_user_2fa"})
+		assert.Nil(t, err)
+		assert.Equal(t, "new_token", result.Token)
+	})
+
+	t.Run("verify two factor of token which is issued without code", func(t *testing.T) {
+		microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, "Bearer email_token_user_2fa").Return("email_token_user_2fa", nil)
+		microAuthServiceMock.On("SetTwoFactorVerified", microservice.AUTHTYPE_BEARER, "email_token_user_2fa").Return(nil)
+
+		err := authService.VerifyTwoFactor("user_2fa", "Bearer email_token_user_2fa", "000000", models.AuthenticationContext{Ip: "localhost"})
+		assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+		microAuthServiceMock.AssertNotCalled(t, "SetTwoFactorVerified", microservice.AUTHTYPE_BEARER, "email_token_user_2fa")
+
+		err = authService.VerifyTwoFactor("user_2fa", "Bearer email_token_user_2fa", "123456", models.AuthenticationContext{Ip: "localhost"})
+		assert.Nil(t, err)
+		microAuthServiceMock.AssertCalled(t, "SetTwoFactorVerified", microservice.AUTHTYPE_BEARER, "email_token_user_2fa")
+	})
+
+	t.Run("verify two factor of user who does not enable it", func(t *testing.T) {
+		microAuthServiceMock.On("GetTokenFromAuthorizationHeader", microservice.AUTHTYPE_BEARER, "Bearer email_token_user").Return("email_token_user", nil)
+
+		err := authService.VerifyTwoFactor("user", "Bearer email_token_user", "123456", models.AuthenticationContext{Ip: "localhost"})
+		assert.Equal(t, services.ErrTwoFactorNotEnabled, err)
+	})
+
+	t.Run("login to shop which requires two factor discards token and session", func(t *testing.T) {
+		ownerInfo := micromodels.UserInfo{Username: "owner", Name: "owner"}
+		microAuthServiceMock.On("GenerateTokenWithRedis", microservice.AUTHTYPE_BEARER, ownerInfo).Return("login_token_owner", nil)
+		microAuthServiceMock.On("GenerateTokenWithRedis", microservice.AUTHTYPE_REFRESH, ownerInfo).Return("login_refresh_owner", nil)
+		microAuthServiceMock.On("CreateSession", "login_token_owner", "login_refresh_owner", mock.Anything).Return("session_owner", nil)
+		microAuthServiceMock.On("GetTokenSessionID", microservice.AUTHTYPE_BEARER, "login_token_owner").Return("session_owner", nil)
+		microAuthServiceMock.On("RevokeSession", "owner", "session_owner").Return(nil)
+
+		userReq := &models.UserLoginRequest{}
+		userReq.Username = "owner"
+		userReq.Password = "owner"
+		userReq.ShopID = "shop_2fa"
+
+		result, err := authService.Login(userReq, models.AuthenticationContext{Ip: "localhost"})
+		assert.NotNil(t, err)
+		assert.Empty(t, result)
+		microAuthServiceMock.AssertCalled(t, "RevokeSession", "owner", "session_owner")
+	})
+}
+
 type AuthenticationRepositoryMock struct {
 	mock.Mock
 }
@@ -709,6 +913,155 @@ func (m *AuthServiceMock) RefreshToken(token string) (string, string, error) {
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+func (m *AuthServiceMock) SetTwoFactorVerified(tokenType microservice.TokenType, tokenStr string) error {
+	args := m.Called(tokenType, tokenStr)
+	return args.Error(0)
+}
+
+func (m *AuthServiceMock) GetTokenTwoFactor(tokenType microservice.TokenType, tokenStr string) (string, bool, error) {
+	args := m.Called(tokenType, tokenStr)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (m *AuthServiceMock) CreateSession(tokenStr string, refreshTokenStr string, session micromodels.UserSession) (string, error) {
+	args := m.Called(tokenStr, refreshTokenStr, session)
+	return args.String(0), args.Error(1)
+}
+
+func (m *AuthServiceMock) GetTokenSessionID(tokenType microservice.TokenType, tokenStr string) (string, error) {
+	args := m.Called(tokenType, tokenStr)
+	return args.String(0), args.Error(1)
+}
+
+func (m *AuthServiceMock) ListSessions(username string) ([]micromodels.UserSession, error) {
+	args := m.Called(username)
+	return args.Get(0).([]micromodels.UserSession), args.Error(1)
+}
+
+func (m *AuthServiceMock) RevokeSession(username string, sessionID string) error {
+	args := m.Called(username, sessionID)
+	return args.Error(0)
+}
+
+func (m *AuthServiceMock) RevokeSessions(username string, exceptSessionID string) error {
+	args := m.Called(username, exceptSessionID)
+	return args.Error(0)
+}
+
+type ShopRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ShopRepositoryMock) Create(ctx context.Context, shop shopmodels.ShopDoc) (string, error) {
+	args := m.Called(shop)
+	return args.String(0), args.Error(1)
+}
+
+func (m *ShopRepositoryMock) Update(ctx context.Context, guid string, shop shopmodels.ShopDoc) error {
+	args := m.Called(guid, shop)
+	return args.Error(0)
+}
+
+func (m *ShopRepositoryMock) FindByGuid(ctx context.Context, guid string) (shopmodels.ShopDoc, error) {
+	args := m.Called(guid)
+	return args.Get(0).(shopmodels.ShopDoc), args.Error(1)
+}
+
+func (m *ShopRepositoryMock) FindPage(ctx context.Context, pageable micromodels.Pageable) ([]shopmodels.ShopInfo, mongopagination.PaginationData, error) {
+	args := m.Called(pageable)
+	return args.Get(0).([]shopmodels.ShopInfo), args.Get(1).(mongopagination.PaginationData), args.Error(2)
+}
+
+func (m *ShopRepositoryMock) Delete(ctx context.Context, guid string, username string) error {
+	args := m.Called(guid, username)
+	return args.Error(0)
+}
+
+type LoginGuardMock struct {
+	mock.Mock
+}
+
+// newLoginGuardMock return mock which allow every attempt
+func newLoginGuardMock() *LoginGuardMock {
+	m := &LoginGuardMock{}
+	m.On("Check", mock.Anything).Return(nil)
+	m.On("Reserve", mock.Anything).Return(nil)
+	m.On("Failed", mock.Anything, mock.Anything).Return()
+	m.On("Succeeded", mock.Anything).Return()
+	m.On("AllowSendOTP", mock.Anything, mock.Anything).Return(nil)
+	return m
+}
+
+func (m *LoginGuardMock) Check(attempt guardmodels.LoginAttempt) error {
+	args := m.Called(attempt)
+	return args.Error(0)
+}
+
+func (m *LoginGuardMock) Reserve(attempt guardmodels.LoginAttempt) error {
+	args := m.Called(attempt)
+	return args.Error(0)
+}
+
+func (m *LoginGuardMock) Failed(attempt guardmodels.LoginAttempt, reason string) {
+	m.Called(attempt, reason)
+}
+
+func (m *LoginGuardMock) Succeeded(attempt guardmodels.LoginAttempt) {
+	m.Called(attempt)
+}
+
+func (m *LoginGuardMock) AllowSendOTP(phoneNumber string, ip string) error {
+	args := m.Called(phoneNumber, ip)
+	return args.Error(0)
+}
+
+type TwoFactorServiceMock struct {
+	mock.Mock
+}
+
+// newTwoFactorServiceMock return mock of users who do not enable two factor authentication
+func newTwoFactorServiceMock() *TwoFactorServiceMock {
+	m := &TwoFactorServiceMock{}
+	m.On("VerifyLogin", mock.Anything, mock.Anything).Return(false, nil)
+	m.On("IsEnabled", mock.Anything).Return(false, nil)
+	return m
+}
+
+func (m *TwoFactorServiceMock) Status(ctx context.Context, username string) (models.TwoFactorStatus, error) {
+	args := m.Called(username)
+	return args.Get(0).(models.TwoFactorStatus), args.Error(1)
+}
+
+func (m *TwoFactorServiceMock) Enroll(ctx context.Context, username string) (models.TwoFactorEnrollResponse, error) {
+	args := m.Called(username)
+	return args.Get(0).(models.TwoFactorEnrollResponse), args.Error(1)
+}
+
+func (m *TwoFactorServiceMock) Enable(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	args := m.Called(username, code)
+	return args.Get(0).(models.TwoFactorRecoveryCodesResponse), args.Error(1)
+}
+
+func (m *TwoFactorServiceMock) Disable(ctx context.Context, username string, code string) error {
+	args := m.Called(username, code)
+	return args.Error(0)
+}
+
+func (m *TwoFactorServiceMock) RegenerateRecoveryCodes(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	args := m.Called(username, code)
+	return args.Get(0).(models.TwoFactorRecoveryCodesResponse), args.Error(1)
+}
+
+func (m *TwoFactorServiceMock) IsEnabled(ctx context.Context, username string) (bool, error) {
+	args := m.Called(username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TwoFactorServiceMock) VerifyLogin(ctx context.Context, username string, code string) (bool, error) {
+	args := m.Called(username, code)
+	return args.Bool(0), args.Error(1)
+}
+
 type SMSRepositoryMock struct {
 	mock.Mock
 }
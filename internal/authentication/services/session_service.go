@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/authentication/models"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/shop"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ISessionService interface {
+	ListSessions(username string, authorizationHeader string) ([]models.UserSessionInfo, error)
+	RevokeSession(username string, sessionID string) error
+	RevokeAllSessions(username string) error
+	ListShopUserSessions(shopID string, username string) ([]models.UserSessionInfo, error)
+	RevokeShopUserSessions(shopID string, authUsername string, username string) error
+}
+
+type SessionService struct {
+	authService  microservice.IAuthService
+	shopUserRepo shop.IShopUserRepository
+	permission   microservice.IPermissionChecker
+}
+
+func NewSessionService(authService microservice.IAuthService, shopUserRepo shop.IShopUserRepository, permission microservice.IPermissionChecker) SessionService {
+	return SessionService{
+		authService:  authService,
+		shopUserRepo: shopUserRepo,
+		permission:   permission,
+	}
+}
+
+// ListSessions return sessions of the user, session of the authorization header is marked as current
+func (svc SessionService) ListSessions(username string, authorizationHeader string) ([]models.UserSessionInfo, error) {
+	sessions, err := svc.authService.ListSessions(username)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSessionID := ""
+	tokenStr, err := svc.authService.GetTokenFromAuthorizationHeader(microservice.AUTHTYPE_BEARER, authorizationHeader)
+	if err == nil {
+		currentSessionID, err = svc.authService.GetTokenSessionID(microservice.AUTHTYPE_BEARER, tokenStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sessionInfos := []models.UserSessionInfo{}
+	for _, session := range sessions {
+		sessionInfos = append(sessionInfos, models.UserSessionInfo{
+			UserSession: session,
+			IsCurrent:   currentSessionID != "" && session.SessionID == currentSessionID,
+		})
+	}
+
+	return sessionInfos, nil
+}
+
+func (svc SessionService) RevokeSession(username string, sessionID string) error {
+	return svc.authService.RevokeSession(username, sessionID)
+}
+
+func (svc SessionService) RevokeAllSessions(username string) error {
+	return svc.authService.RevokeSessions(username, "")
+}
+
+// ListShopUserSessions return sessions of the shop user which select the shop
+func (svc SessionService) ListShopUserSessions(shopID string, username string) ([]models.UserSessionInfo, error) {
+	_, err := svc.findShopUser(shopID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := svc.shopSessions(shopID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionInfos := []models.UserSessionInfo{}
+	for _, session := range sessions {
+		sessionInfos = append(sessionInfos, models.UserSessionInfo{UserSession: session})
+	}
+
+	return sessionInfos, nil
+}
+
+// RevokeShopUserSessions revoke sessions of the shop user which select the shop,
+// sessions of the user in other shops are not revoked
+func (svc SessionService) RevokeShopUserSessions(shopID string, authUsername string, username string) error {
+	shopUser, err := svc.findShopUser(shopID, username)
+	if err != nil {
+		return err
+	}
+
+	if authUsername != username && rbacmodels.HasShopUserRole(shopUser, rbacmodels.RoleOwner) {
+		allowed, err := svc.permission.HasPermission(context.Background(), micromodels.UserInfo{ShopID: shopID, Username: authUsername}, rbacmodels.PermissionShopOwnerUpdate)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return errors.New("permission denied")
+		}
+	}
+
+	sessions, err := svc.shopSessions(shopID, username)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err = svc.authService.RevokeSession(username, session.SessionID)
+		if err != nil && err != microservice.ErrSessionNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc SessionService) findShopUser(shopID string, username string) (models.ShopUser, error) {
+	shopUser, err := svc.shopUserRepo.FindByShopIDAndUsername(context.Background(), shopID, username)
+	if err != nil {
+		return models.ShopUser{}, err
+	}
+
+	if shopUser.ID == primitive.NilObjectID {
+		return models.ShopUser{}, errors.New("user not found in shop")
+	}
+
+	return shopUser, nil
+}
+
+func (svc SessionService) shopSessions(shopID string, username string) ([]micromodels.UserSession, error) {
+	sessions, err := svc.authService.ListSessions(username)
+	if err != nil {
+		return nil, err
+	}
+
+	shopSessions := []micromodels.UserSession{}
+	for _, session := range sessions {
+		if session.ShopID == shopID {
+			shopSessions = append(shopSessions, session)
+		}
+	}
+
+	return shopSessions, nil
+}
@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/authentication/services"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PermissionCheckerMock struct {
+	mock.Mock
+}
+
+func (m *PermissionCheckerMock) HasPermission(ctx context.Context, userInfo micromodels.UserInfo, permission string) (bool, error) {
+	args := m.Called(userInfo, permission)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestSessionService_RevokeShopUserSessions(t *testing.T) {
+	shopUserRepo := new(ShopUserRepositoryMock)
+	microAuthServiceMock := new(AuthServiceMock)
+	permission := new(PermissionCheckerMock)
+
+	newShopUser := func(username string, role models.UserRole) models.ShopUser {
+		shopUser := models.ShopUser{}
+		shopUser.ID = primitive.NewObjectID()
+		shopUser.ShopID = "shop01"
+		shopUser.Username = username
+		shopUser.Role = role
+		return shopUser
+	}
+
+	shopUserRepo.On("FindByShopIDAndUsername", "shop01", "staff").Return(newShopUser("staff", models.ROLE_USER), nil)
+	shopUserRepo.On("FindByShopIDAndUsername", "shop01", "owner").Return(newShopUser("owner", models.ROLE_OWNER), nil)
+	shopUserRepo.On("FindByShopIDAndUsername", "shop01", "other").Return(models.ShopUser{}, nil)
+
+	microAuthServiceMock.On("ListSessions", "staff").Return([]micromodels.UserSession{
+		{SessionID: "pos", Username: "staff", ShopID: "shop01"},
+		{SessionID: "web", Username: "staff", ShopID: "shop02"},
+	}, nil)
+	microAuthServiceMock.On("ListSessions", "owner").Return([]micromodels.UserSession{
+		{SessionID: "owner_web", Username: "owner", ShopID: "shop01"},
+	}, nil)
+	microAuthServiceMock.On("RevokeSession", mock.Anything, mock.Anything).Return(nil)
+
+	permission.On("HasPermission", micromodels.UserInfo{ShopID: "shop01", Username: "admin"}, rbacmodels.PermissionShopOwnerUpdate).Return(false, nil)
+
+	svc := services.NewSessionService(microAuthServiceMock, shopUserRepo, permission)
+
+	// only session which select the shop is revoked
+	err := svc.RevokeShopUserSessions("shop01", "admin", "staff")
+	assert.Nil(t, err)
+	microAuthServiceMock.AssertCalled(t, "RevokeSession", "staff", "pos")
+	microAuthServiceMock.AssertNotCalled(t, "RevokeSession", "staff", "web")
+
+	err = svc.RevokeShopUserSessions("shop01", "admin", "owner")
+	assert.EqualError(t, err, "permission denied")
+	microAuthServiceMock.AssertNotCalled(t, "RevokeSession", "owner", "owner_web")
+
+	err = svc.RevokeShopUserSessions("shop01", "admin", "other")
+	assert.EqualError(t, err, "user not found in shop")
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/authentication/repositories"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/utils/totp"
+	"smlaicloudplatform/pkg/microservice"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	twoFactorIssuer            = "SML Cloud"
+	twoFactorSkew              = 1
+	twoFactorRecoveryCodeCount = 10
+	// secrets of all users are encrypted by one data key, two factor is not owned by shop
+	twoFactorKeyID = "usertwofactor"
+)
+
+var (
+	ErrTwoFactorRequired    = errors.New("two factor code is required")
+	ErrTwoFactorCodeInvalid = errors.New("two factor code invalid")
+	ErrTwoFactorNotEnabled  = errors.New("two factor authentication is not enabled")
+)
+
+type ITwoFactorService interface {
+	Status(ctx context.Context, username string) (models.TwoFactorStatus, error)
+	Enroll(ctx context.Context, username string) (models.TwoFactorEnrollResponse, error)
+	Enable(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error)
+	Disable(ctx context.Context, username string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error)
+	IsEnabled(ctx context.Context, username string) (bool, error)
+	VerifyLogin(ctx context.Context, username string, code string) (bool, error)
+}
+
+type TwoFactorService struct {
+	repo           repositories.IUserTwoFactorRepository
+	fieldEncryptor encrypt.IFieldEncryptor
+	encrypt        *encrypt.Encrypt
+	timeNow        func() time.Time
+}
+
+func NewTwoFactorService(repo repositories.IUserTwoFactorRepository, fieldEncryptor encrypt.IFieldEncryptor, timeNow func() time.Time) TwoFactorService {
+	return TwoFactorService{
+		repo:           repo,
+		fieldEncryptor: fieldEncryptor,
+		encrypt:        encrypt.NewEncrypt(),
+		timeNow:        timeNow,
+	}
+}
+
+func (svc TwoFactorService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), 15*time.Second)
+}
+
+func (svc TwoFactorService) Status(ctx context.Context, username string) (models.TwoFactorStatus, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return models.TwoFactorStatus{}, err
+	}
+
+	return models.TwoFactorStatus{
+		Enabled:                doc.Enabled,
+		EnabledAt:              doc.EnabledAt,
+		RecoveryCodesRemaining: len(doc.RecoveryCodes),
+	}, nil
+}
+
+// Enroll generate new secret which is pending until it is confirmed by code of authenticator app
+func (svc TwoFactorService) Enroll(ctx context.Context, username string) (models.TwoFactorEnrollResponse, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return models.TwoFactorEnrollResponse{}, err
+	}
+
+	if doc.Enabled {
+		return models.TwoFactorEnrollResponse{}, errors.New("two factor authentication is enabled already")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return models.TwoFactorEnrollResponse{}, err
+	}
+
+	doc.Username = username
+	doc.PendingSecret = secret
+
+	err = svc.fieldEncryptor.EncryptFields(ctx, twoFactorKeyID, &doc.PendingSecret)
+	if err != nil {
+		return models.TwoFactorEnrollResponse{}, err
+	}
+
+	err = svc.save(ctx, doc)
+	if err != nil {
+		return models.TwoFactorEnrollResponse{}, err
+	}
+
+	return models.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(twoFactorIssuer, username, secret),
+	}, nil
+}
+
+// Enable confirm pending secret, recovery codes are returned only once
+func (svc TwoFactorService) Enable(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	if doc.Enabled {
+		return models.TwoFactorRecoveryCodesResponse{}, errors.New("two factor authentication is enabled already")
+	}
+
+	if doc.PendingSecret == "" {
+		return models.TwoFactorRecoveryCodesResponse{}, errors.New("two factor enrollment not found")
+	}
+
+	pendingSecret := doc.PendingSecret
+	err = svc.fieldEncryptor.DecryptFields(ctx, &pendingSecret)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	step, ok := totp.Validate(pendingSecret, code, svc.timeNow(), twoFactorSkew)
+	if !ok {
+		return models.TwoFactorRecoveryCodesResponse{}, ErrTwoFactorCodeInvalid
+	}
+
+	recoveryCodes, err := svc.generateRecoveryCodes(&doc)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	doc.Secret = doc.PendingSecret
+	doc.PendingSecret = ""
+	doc.Enabled = true
+	doc.EnabledAt = svc.timeNow()
+	doc.LastUsedStep = step
+
+	err = svc.save(ctx, doc)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	return models.TwoFactorRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (svc TwoFactorService) Disable(ctx context.Context, username string, code string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findEnabled(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	err = svc.verifyCode(ctx, &doc, code)
+	if err != nil {
+		return err
+	}
+
+	doc.Enabled = false
+	doc.EnabledAt = time.Time{}
+	doc.Secret = ""
+	doc.PendingSecret = ""
+	doc.RecoveryCodes = []string{}
+
+	return svc.save(ctx, doc)
+}
+
+// RegenerateRecoveryCodes replace all recovery codes, codes which are not used are no longer valid
+func (svc TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, username string, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findEnabled(ctx, username)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	err = svc.verifyCode(ctx, &doc, code)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	recoveryCodes, err := svc.generateRecoveryCodes(&doc)
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	// only recovery codes are replaced, last used step is kept as it is updated by verifyCode
+	err = svc.repo.UpdateRecoveryCodes(ctx, username, doc.RecoveryCodes, svc.timeNow())
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	return models.TwoFactorRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (svc TwoFactorService) IsEnabled(ctx context.Context, username string) (bool, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	return doc.Enabled, nil
+}
+
+// VerifyLogin return false when the user does not enable two factor authentication,
+// otherwise code must be valid totp or unused recovery code
+func (svc TwoFactorService) VerifyLogin(ctx context.Context, username string, code string) (bool, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	if !doc.Enabled {
+		return false, nil
+	}
+
+	err = svc.verifyCode(ctx, &doc, code)
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (svc TwoFactorService) findEnabled(ctx context.Context, username string) (models.UserTwoFactorDoc, error) {
+	doc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return models.UserTwoFactorDoc{}, err
+	}
+
+	if !doc.Enabled {
+		return models.UserTwoFactorDoc{}, ErrTwoFactorNotEnabled
+	}
+
+	return doc, nil
+}
+
+// verifyCode accept totp which step is after the last used step so the same code can not be replayed,
+// or recovery code which is removed after use. The code is used by conditional update so it is
+// rejected when other request uses it at the same time
+func (svc TwoFactorService) verifyCode(ctx context.Context, doc *models.UserTwoFactorDoc, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrTwoFactorRequired
+	}
+
+	secret := doc.Secret
+	err := svc.fieldEncryptor.DecryptFields(ctx, &secret)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := totp.Validate(secret, code, svc.timeNow(), twoFactorSkew); ok {
+		if step <= doc.LastUsedStep {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		used, err := svc.repo.UseStep(ctx, doc.Username, step, svc.timeNow())
+		if err != nil {
+			return err
+		}
+
+		if !used {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		doc.LastUsedStep = step
+		return nil
+	}
+
+	codeHash := svc.encrypt.GenerateSHA256Hash(normalizeRecoveryCode(code))
+	for i, recoveryCode := range doc.RecoveryCodes {
+		if recoveryCode == codeHash {
+			used, err := svc.repo.UseRecoveryCode(ctx, doc.Username, codeHash, svc.timeNow())
+			if err != nil {
+				return err
+			}
+
+			if !used {
+				return ErrTwoFactorCodeInvalid
+			}
+
+			doc.RecoveryCodes = append(doc.RecoveryCodes[:i:i], doc.RecoveryCodes[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrTwoFactorCodeInvalid
+}
+
+func (svc TwoFactorService) generateRecoveryCodes(doc *models.UserTwoFactorDoc) ([]string, error) {
+	recoveryCodes := make([]string, 0, twoFactorRecoveryCodeCount)
+	codeHashes := make([]string, 0, twoFactorRecoveryCodeCount)
+
+	for i := 0; i < twoFactorRecoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		_, err := rand.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		code := hex.EncodeToString(buf)
+		recoveryCodes = append(recoveryCodes, code[:5]+"-"+code[5:])
+		codeHashes = append(codeHashes, svc.encrypt.GenerateSHA256Hash(code))
+	}
+
+	doc.RecoveryCodes = codeHashes
+	return recoveryCodes, nil
+}
+
+func (svc TwoFactorService) save(ctx context.Context, doc models.UserTwoFactorDoc) error {
+	doc.UpdatedAt = svc.timeNow()
+
+	if doc.ID == primitive.NilObjectID {
+		_, err := svc.repo.Create(ctx, doc)
+		return err
+	}
+
+	return svc.repo.Update(ctx, doc.Username, doc)
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/authentication/repositories"
+	"smlaicloudplatform/internal/authentication/services"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/utils/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// userTwoFactorRepositoryMemory keep two factor of users in memory
+type userTwoFactorRepositoryMemory struct {
+	repositories.IUserTwoFactorRepository
+	docs map[string]models.UserTwoFactorDoc
+}
+
+func (r *userTwoFactorRepositoryMemory) FindByUsername(ctx context.Context, username string) (models.UserTwoFactorDoc, error) {
+	return r.docs[username], nil
+}
+
+func (r *userTwoFactorRepositoryMemory) Create(ctx context.Context, doc models.UserTwoFactorDoc) (primitive.ObjectID, error) {
+	doc.ID = primitive.NewObjectID()
+	r.docs[doc.Username] = doc
+	return doc.ID, nil
+}
+
+func (r *userTwoFactorRepositoryMemory) Update(ctx context.Context, username string, doc models.UserTwoFactorDoc) error {
+	r.docs[username] = doc
+	return nil
+}
+
+func (r *userTwoFactorRepositoryMemory) UseStep(ctx context.Context, username string, step int64, updatedAt time.Time) (bool, error) {
+	doc := r.docs[username]
+	if !doc.Enabled || doc.LastUsedStep >= step {
+		return false, nil
+	}
+
+	doc.LastUsedStep = step
+	r.docs[username] = doc
+	return true, nil
+}
+
+func (r *userTwoFactorRepositoryMemory) UseRecoveryCode(ctx context.Context, username string, codeHash string, updatedAt time.Time) (bool, error) {
+	doc := r.docs[username]
+	for i, recoveryCode := range doc.RecoveryCodes {
+		if doc.Enabled && recoveryCode == codeHash {
+			doc.RecoveryCodes = append(doc.RecoveryCodes[:i:i], doc.RecoveryCodes[i+1:]...)
+			r.docs[username] = doc
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *userTwoFactorRepositoryMemory) UpdateRecoveryCodes(ctx context.Context, username string, codeHashes []string, updatedAt time.Time) error {
+	doc := r.docs[username]
+	doc.RecoveryCodes = codeHashes
+	r.docs[username] = doc
+	return nil
+}
+
+// dataKeyRepositoryMemory keep data keys in memory
+type dataKeyRepositoryMemory struct {
+	docs map[string]encrypt.DataKeyDoc
+}
+
+func (r *dataKeyRepositoryMemory) FindByKeyID(ctx context.Context, keyID string) (encrypt.DataKeyDoc, error) {
+	return r.docs[keyID], nil
+}
+
+func (r *dataKeyRepositoryMemory) Create(ctx context.Context, doc encrypt.DataKeyDoc) (bool, error) {
+	r.docs[doc.KeyID] = doc
+	return true, nil
+}
+
+func newFieldEncryptor(t *testing.T) encrypt.IFieldEncryptor {
+	fieldEncryptor, err := encrypt.NewFieldEncryptor([]byte(strings.Repeat("k", 32)), &dataKeyRepositoryMemory{docs: map[string]encrypt.DataKeyDoc{}}, time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fieldEncryptor
+}
+
+func TestTwoFactorService(t *testing.T) {
+	now := MockTime()
+	repo := &userTwoFactorRepositoryMemory{docs: map[string]models.UserTwoFactorDoc{}}
+	svc := services.NewTwoFactorService(repo, newFieldEncryptor(t), func() time.Time { return now })
+
+	enabled, err := svc.VerifyLogin(context.Background(), "user01", "")
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	enroll, err := svc.Enroll(context.Background(), "user01")
+	assert.Nil(t, err)
+	assert.Contains(t, enroll.ProvisioningURI, "secret="+enroll.Secret)
+	assert.True(t, encrypt.IsEncrypted(repo.docs["user01"].PendingSecret))
+
+	code, _ := totp.Code(enroll.Secret, totp.Step(now))
+
+	_, err = svc.Enable(context.Background(), "user01", "000000")
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+
+	result, err := svc.Enable(context.Background(), "user01", code)
+	assert.Nil(t, err)
+	assert.Len(t, result.RecoveryCodes, 10)
+
+	status, _ := svc.Status(context.Background(), "user01")
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 10, status.RecoveryCodesRemaining)
+	assert.True(t, encrypt.IsEncrypted(repo.docs["user01"].Secret))
+	assert.Empty(t, repo.docs["user01"].PendingSecret)
+
+	// code which enables two factor can not be used again
+	enabled, err = svc.VerifyLogin(context.Background(), "user01", code)
+	assert.True(t, enabled)
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+
+	_, err = svc.VerifyLogin(context.Background(), "user01", "")
+	assert.Equal(t, services.ErrTwoFactorRequired, err)
+
+	now = now.Add(totp.Period * time.Second)
+	code, _ = totp.Code(enroll.Secret, totp.Step(now))
+
+	_, err = svc.VerifyLogin(context.Background(), "user01", code)
+	assert.Nil(t, err)
+
+	// recovery code is used once
+	_, err = svc.VerifyLogin(context.Background(), "user01", result.RecoveryCodes[0])
+	assert.Nil(t, err)
+
+	_, err = svc.VerifyLogin(context.Background(), "user01", result.RecoveryCodes[0])
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+
+	status, _ = svc.Status(context.Background(), "user01")
+	assert.Equal(t, 9, status.RecoveryCodesRemaining)
+
+	regenerated, err := svc.RegenerateRecoveryCodes(context.Background(), "user01", result.RecoveryCodes[1])
+	assert.Nil(t, err)
+
+	_, err = svc.VerifyLogin(context.Background(), "user01", result.RecoveryCodes[2])
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+
+	err = svc.Disable(context.Background(), "user01", regenerated.RecoveryCodes[0])
+	assert.Nil(t, err)
+
+	enabled, err = svc.IsEnabled(context.Background(), "user01")
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	err = svc.Disable(context.Background(), "user01", code)
+	assert.Equal(t, services.ErrTwoFactorNotEnabled, err)
+}
+
+// codeUsedByOtherRepository return the document before the code is used by other login at the same time
+type codeUsedByOtherRepository struct {
+	*userTwoFactorRepositoryMemory
+}
+
+func (r codeUsedByOtherRepository) FindByUsername(ctx context.Context, username string) (models.UserTwoFactorDoc, error) {
+	doc := r.docs[username]
+
+	used := doc
+	used.LastUsedStep = doc.LastUsedStep + 10
+	used.RecoveryCodes = []string{}
+	r.docs[username] = used
+
+	return doc, nil
+}
+
+func TestTwoFactorServiceVerifyLoginCodeUsedByOther(t *testing.T) {
+	now := MockTime()
+	repo := &userTwoFactorRepositoryMemory{docs: map[string]models.UserTwoFactorDoc{}}
+	fieldEncryptor := newFieldEncryptor(t)
+	svc := services.NewTwoFactorService(repo, fieldEncryptor, func() time.Time { return now })
+
+	enroll, err := svc.Enroll(context.Background(), "user01")
+	assert.Nil(t, err)
+
+	code, _ := totp.Code(enroll.Secret, totp.Step(now))
+	result, err := svc.Enable(context.Background(), "user01", code)
+	assert.Nil(t, err)
+
+	now = now.Add(totp.Period * time.Second)
+	code, _ = totp.Code(enroll.Secret, totp.Step(now))
+
+	enabledDoc := repo.docs["user01"]
+	racingSvc := services.NewTwoFactorService(codeUsedByOtherRepository{repo}, fieldEncryptor, func() time.Time { return now })
+
+	enabled, err := racingSvc.VerifyLogin(context.Background(), "user01", code)
+	assert.True(t, enabled)
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+
+	repo.docs["user01"] = enabledDoc
+	_, err = racingSvc.VerifyLogin(context.Background(), "user01", result.RecoveryCodes[0])
+	assert.Equal(t, services.ErrTwoFactorCodeInvalid, err)
+}
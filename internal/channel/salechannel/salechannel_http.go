@@ -78,7 +78,7 @@ func (h SaleChannelHttp) CreateSaleChannel(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateSaleChannel(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateSaleChannel(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h SaleChannelHttp) UpdateSaleChannel(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateSaleChannel(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateSaleChannel(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h SaleChannelHttp) DeleteSaleChannel(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSaleChannel(shopID, id, authUsername)
+	err := h.svc.DeleteSaleChannel(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h SaleChannelHttp) DeleteSaleChannelByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteSaleChannelByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSaleChannelByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h SaleChannelHttp) InfoSaleChannel(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SaleChannel %v", id)
-	doc, err := h.svc.InfoSaleChannel(shopID, id)
+	doc, err := h.svc.InfoSaleChannel(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h SaleChannelHttp) InfoSaleChannelByCode(ctx microservice.IContext) error
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSaleChannelByCode(shopID, code)
+	doc, err := h.svc.InfoSaleChannelByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h SaleChannelHttp) SearchSaleChannelPage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSaleChannel(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchSaleChannel(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -319,7 +319,7 @@ func (h SaleChannelHttp) SearchSaleChannelStep(ctx microservice.IContext) error
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchSaleChannelStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchSaleChannelStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h SaleChannelHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type ISaleChannelHttpService interface {
-	CreateSaleChannel(shopID string, authUsername string, doc models.SaleChannel) (string, error)
-	UpdateSaleChannel(shopID string, guid string, authUsername string, doc models.SaleChannel) error
-	DeleteSaleChannel(shopID string, guid string, authUsername string) error
-	DeleteSaleChannelByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSaleChannel(shopID string, guid string) (models.SaleChannelInfo, error)
-	InfoSaleChannelByCode(shopID string, code string) (models.SaleChannelInfo, error)
-	SearchSaleChannel(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleChannelInfo, mongopagination.PaginationData, error)
-	SearchSaleChannelStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SaleChannelInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SaleChannel) (common.BulkImport, error)
+	CreateSaleChannel(ctx context.Context, shopID string, authUsername string, doc models.SaleChannel) (string, error)
+	UpdateSaleChannel(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleChannel) error
+	DeleteSaleChannel(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSaleChannelByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSaleChannel(ctx context.Context, shopID string, guid string) (models.SaleChannelInfo, error)
+	InfoSaleChannelByCode(ctx context.Context, shopID string, code string) (models.SaleChannelInfo, error)
+	SearchSaleChannel(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleChannelInfo, mongopagination.PaginationData, error)
+	SearchSaleChannelStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SaleChannelInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleChannel) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -37,13 +38,15 @@ type SaleChannelHttpService struct {
 
 	syncCacheRepo mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.SaleChannelActivity, models.SaleChannelDeleteActivity]
+	contextTimeout time.Duration
 }
 
 func NewSaleChannelHttpService(repo repositories.ISaleChannelRepository, syncCacheRepo mastersync.IMasterSyncCacheRepository) *SaleChannelHttpService {
 
 	insSvc := &SaleChannelHttpService{
-		repo:          repo,
-		syncCacheRepo: syncCacheRepo,
+		repo:           repo,
+		syncCacheRepo:  syncCacheRepo,
+		contextTimeout: time.Duration(15) * time.Second,
 	}
 
 	insSvc.ActivityService = services.NewActivityService[models.SaleChannelActivity, models.SaleChannelDeleteActivity](repo)
@@ -51,9 +54,16 @@ func NewSaleChannelHttpService(repo repositories.ISaleChannelRepository, syncCac
 	return insSvc
 }
 
-func (svc SaleChannelHttpService) CreateSaleChannel(shopID string, authUsername string, doc models.SaleChannel) (string, error) {
+func (svc SaleChannelHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc SaleChannelHttpService) CreateSaleChannel(ctx context.Context, shopID string, authUsername string, doc models.SaleChannel) (string, error) {
 
-	findDoc, err := svc.repo.FindByDocIndentityGuid(context.Background(), shopID, "code", doc.Code)
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
 
 	if err != nil {
 		return "", err
@@ -73,7 +83,7 @@ func (svc SaleChannelHttpService) CreateSaleChannel(shopID string, authUsername
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(context.Background(), docData)
+	_, err = svc.repo.Create(ctx, docData)
 
 	if err != nil {
 		return "", err
@@ -84,9 +94,12 @@ func (svc SaleChannelHttpService) CreateSaleChannel(shopID string, authUsername
 	return newGuidFixed, nil
 }
 
-func (svc SaleChannelHttpService) UpdateSaleChannel(shopID string, guid string, authUsername string, doc models.SaleChannel) error {
+func (svc SaleChannelHttpService) UpdateSaleChannel(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleChannel) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
 
-	findDoc, err := svc.repo.FindByGuid(context.Background(), shopID, guid)
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
 
 	if err != nil {
 		return err
@@ -101,7 +114,7 @@ func (svc SaleChannelHttpService) UpdateSaleChannel(shopID string, guid string,
 	findDoc.UpdatedBy = authUsername
 	findDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(context.Background(), shopID, guid, findDoc)
+	err = svc.repo.Update(ctx, shopID, guid, findDoc)
 
 	if err != nil {
 		return err
@@ -112,9 +125,12 @@ func (svc SaleChannelHttpService) UpdateSaleChannel(shopID string, guid string,
 	return nil
 }
 
-func (svc SaleChannelHttpService) DeleteSaleChannel(shopID string, guid string, authUsername string) error {
+func (svc SaleChannelHttpService) DeleteSaleChannel(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	findDoc, err := svc.repo.FindByGuid(context.Background(), shopID, guid)
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
 
 	if err != nil {
 		return err
@@ -124,7 +140,7 @@ func (svc SaleChannelHttpService) DeleteSaleChannel(shopID string, guid string,
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(context.Background(), shopID, guid, authUsername)
+	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
 	if err != nil {
 		return err
 	}
@@ -134,13 +150,16 @@ func (svc SaleChannelHttpService) DeleteSaleChannel(shopID string, guid string,
 	return nil
 }
 
-func (svc SaleChannelHttpService) DeleteSaleChannelByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SaleChannelHttpService) DeleteSaleChannelByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(context.Background(), shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
 	if err != nil {
 		return err
 	}
@@ -148,9 +167,12 @@ func (svc SaleChannelHttpService) DeleteSaleChannelByGUIDs(shopID string, authUs
 	return nil
 }
 
-func (svc SaleChannelHttpService) InfoSaleChannel(shopID string, guid string) (models.SaleChannelInfo, error) {
+func (svc SaleChannelHttpService) InfoSaleChannel(ctx context.Context, shopID string, guid string) (models.SaleChannelInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
 
-	findDoc, err := svc.repo.FindByGuid(context.Background(), shopID, guid)
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
 
 	if err != nil {
 		return models.SaleChannelInfo{}, err
@@ -163,9 +185,12 @@ func (svc SaleChannelHttpService) InfoSaleChannel(shopID string, guid string) (m
 	return findDoc.SaleChannelInfo, nil
 }
 
-func (svc SaleChannelHttpService) InfoSaleChannelByCode(shopID string, code string) (models.SaleChannelInfo, error) {
+func (svc SaleChannelHttpService) InfoSaleChannelByCode(ctx context.Context, shopID string, code string) (models.SaleChannelInfo, error) {
 
-	findDoc, err := svc.repo.FindByDocIndentityGuid(context.Background(), shopID, "code", code)
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
 
 	if err != nil {
 		return models.SaleChannelInfo{}, err
@@ -178,13 +203,17 @@ func (svc SaleChannelHttpService) InfoSaleChannelByCode(shopID string, code stri
 	return findDoc.SaleChannelInfo, nil
 }
 
-func (svc SaleChannelHttpService) SearchSaleChannel(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleChannelInfo, mongopagination.PaginationData, error) {
+func (svc SaleChannelHttpService) SearchSaleChannel(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleChannelInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
 	searchInFields := []string{
 		"code",
 		"names.name",
 	}
 
-	docList, pagination, err := svc.repo.FindPageFilter(context.Background(), shopID, filters, searchInFields, pageable)
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
 
 	if err != nil {
 		return []models.SaleChannelInfo{}, pagination, err
@@ -193,7 +222,11 @@ func (svc SaleChannelHttpService) SearchSaleChannel(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc SaleChannelHttpService) SearchSaleChannelStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SaleChannelInfo, int, error) {
+func (svc SaleChannelHttpService) SearchSaleChannelStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SaleChannelInfo, int, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
 	searchInFields := []string{
 		"code",
 		"names.name",
@@ -201,7 +234,7 @@ func (svc SaleChannelHttpService) SearchSaleChannelStep(shopID string, langCode
 
 	selectFields := map[string]interface{}{}
 
-	docList, total, err := svc.repo.FindStep(context.Background(), shopID, map[string]interface{}{}, searchInFields, selectFields, pageableStep)
+	docList, total, err := svc.repo.FindStep(ctx, shopID, map[string]interface{}{}, searchInFields, selectFields, pageableStep)
 
 	if err != nil {
 		return []models.SaleChannelInfo{}, 0, err
@@ -210,7 +243,10 @@ func (svc SaleChannelHttpService) SearchSaleChannelStep(shopID string, langCode
 	return docList, total, nil
 }
 
-func (svc SaleChannelHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.SaleChannel) (common.BulkImport, error) {
+func (svc SaleChannelHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleChannel) (common.BulkImport, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SaleChannel](dataList, svc.getDocIDKey)
 
@@ -219,7 +255,7 @@ func (svc SaleChannelHttpService) SaveInBatch(shopID string, authUsername string
 		itemCodeGuidList = append(itemCodeGuidList, doc.Code)
 	}
 
-	findItemGuid, err := svc.repo.FindInItemGuid(context.Background(), shopID, "code", itemCodeGuidList)
+	findItemGuid, err := svc.repo.FindInItemGuid(ctx, shopID, "code", itemCodeGuidList)
 
 	if err != nil {
 		return common.BulkImport{}, err
@@ -258,7 +294,7 @@ func (svc SaleChannelHttpService) SaveInBatch(shopID string, authUsername string
 		duplicateDataList,
 		svc.getDocIDKey,
 		func(shopID string, guid string) (models.SaleChannelDoc, error) {
-			return svc.repo.FindByDocIndentityGuid(context.Background(), shopID, "code", guid)
+			return svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", guid)
 		},
 		func(doc models.SaleChannelDoc) bool {
 			return doc.Code != ""
@@ -269,7 +305,7 @@ func (svc SaleChannelHttpService) SaveInBatch(shopID string, authUsername string
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
-			err = svc.repo.Update(context.Background(), shopID, doc.GuidFixed, doc)
+			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			if err != nil {
 				return nil
 			}
@@ -278,7 +314,7 @@ func (svc SaleChannelHttpService) SaveInBatch(shopID string, authUsername string
 	)
 
 	if len(createDataList) > 0 {
-		err = svc.repo.CreateInBatch(context.Background(), createDataList)
+		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
 			return common.BulkImport{}, err
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type ITransportChannelHttpService interface {
-	CreateTransportChannel(shopID string, authUsername string, doc models.TransportChannel) (string, error)
-	UpdateTransportChannel(shopID string, guid string, authUsername string, doc models.TransportChannel) error
-	DeleteTransportChannel(shopID string, guid string, authUsername string) error
-	DeleteTransportChannelByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoTransportChannel(shopID string, guid string) (models.TransportChannelInfo, error)
-	InfoTransportChannelByCode(shopID string, code string) (models.TransportChannelInfo, error)
-	SearchTransportChannel(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TransportChannelInfo, mongopagination.PaginationData, error)
-	SearchTransportChannelStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TransportChannelInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.TransportChannel) (common.BulkImport, error)
+	CreateTransportChannel(ctx context.Context, shopID string, authUsername string, doc models.TransportChannel) (string, error)
+	UpdateTransportChannel(ctx context.Context, shopID string, guid string, authUsername string, doc models.TransportChannel) error
+	DeleteTransportChannel(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteTransportChannelByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoTransportChannel(ctx context.Context, shopID string, guid string) (models.TransportChannelInfo, error)
+	InfoTransportChannelByCode(ctx context.Context, shopID string, code string) (models.TransportChannelInfo, error)
+	SearchTransportChannel(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TransportChannelInfo, mongopagination.PaginationData, error)
+	SearchTransportChannelStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TransportChannelInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.TransportChannel) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -53,13 +54,13 @@ func NewTransportChannelHttpService(repo repositories.ITransportChannelRepositor
 	return insSvc
 }
 
-func (svc TransportChannelHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc TransportChannelHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc TransportChannelHttpService) CreateTransportChannel(shopID string, authUsername string, doc models.TransportChannel) (string, error) {
+func (svc TransportChannelHttpService) CreateTransportChannel(ctx context.Context, shopID string, authUsername string, doc models.TransportChannel) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -93,9 +94,9 @@ func (svc TransportChannelHttpService) CreateTransportChannel(shopID string, aut
 	return newGuidFixed, nil
 }
 
-func (svc TransportChannelHttpService) UpdateTransportChannel(shopID string, guid string, authUsername string, doc models.TransportChannel) error {
+func (svc TransportChannelHttpService) UpdateTransportChannel(ctx context.Context, shopID string, guid string, authUsername string, doc models.TransportChannel) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -124,9 +125,9 @@ func (svc TransportChannelHttpService) UpdateTransportChannel(shopID string, gui
 	return nil
 }
 
-func (svc TransportChannelHttpService) DeleteTransportChannel(shopID string, guid string, authUsername string) error {
+func (svc TransportChannelHttpService) DeleteTransportChannel(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -149,9 +150,9 @@ func (svc TransportChannelHttpService) DeleteTransportChannel(shopID string, gui
 	return nil
 }
 
-func (svc TransportChannelHttpService) DeleteTransportChannelByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc TransportChannelHttpService) DeleteTransportChannelByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -166,8 +167,8 @@ func (svc TransportChannelHttpService) DeleteTransportChannelByGUIDs(shopID stri
 	return nil
 }
 
-func (svc TransportChannelHttpService) InfoTransportChannel(shopID string, guid string) (models.TransportChannelInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc TransportChannelHttpService) InfoTransportChannel(ctx context.Context, shopID string, guid string) (models.TransportChannelInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -183,8 +184,8 @@ func (svc TransportChannelHttpService) InfoTransportChannel(shopID string, guid
 	return findDoc.TransportChannelInfo, nil
 }
 
-func (svc TransportChannelHttpService) InfoTransportChannelByCode(shopID string, code string) (models.TransportChannelInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc TransportChannelHttpService) InfoTransportChannelByCode(ctx context.Context, shopID string, code string) (models.TransportChannelInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -200,9 +201,9 @@ func (svc TransportChannelHttpService) InfoTransportChannelByCode(shopID string,
 	return findDoc.TransportChannelInfo, nil
 }
 
-func (svc TransportChannelHttpService) SearchTransportChannel(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TransportChannelInfo, mongopagination.PaginationData, error) {
+func (svc TransportChannelHttpService) SearchTransportChannel(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TransportChannelInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -219,9 +220,9 @@ func (svc TransportChannelHttpService) SearchTransportChannel(shopID string, fil
 	return docList, pagination, nil
 }
 
-func (svc TransportChannelHttpService) SearchTransportChannelStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TransportChannelInfo, int, error) {
+func (svc TransportChannelHttpService) SearchTransportChannelStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TransportChannelInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -240,9 +241,9 @@ func (svc TransportChannelHttpService) SearchTransportChannelStep(shopID string,
 	return docList, total, nil
 }
 
-func (svc TransportChannelHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.TransportChannel) (common.BulkImport, error) {
+func (svc TransportChannelHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.TransportChannel) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.TransportChannel](dataList, svc.getDocIDKey)
@@ -78,7 +78,7 @@ func (h TransportChannelHttp) CreateTransportChannel(ctx microservice.IContext)
 		return err
 	}
 
-	idx, err := h.svc.CreateTransportChannel(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateTransportChannel(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h TransportChannelHttp) UpdateTransportChannel(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.UpdateTransportChannel(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateTransportChannel(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h TransportChannelHttp) DeleteTransportChannel(ctx microservice.IContext)
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteTransportChannel(shopID, id, authUsername)
+	err := h.svc.DeleteTransportChannel(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h TransportChannelHttp) DeleteTransportChannelByGUIDs(ctx microservice.ICo
 		return err
 	}
 
-	err = h.svc.DeleteTransportChannelByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteTransportChannelByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h TransportChannelHttp) InfoTransportChannel(ctx microservice.IContext) er
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get TransportChannel %v", id)
-	doc, err := h.svc.InfoTransportChannel(shopID, id)
+	doc, err := h.svc.InfoTransportChannel(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h TransportChannelHttp) InfoTransportChannelByCode(ctx microservice.IConte
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoTransportChannelByCode(shopID, code)
+	doc, err := h.svc.InfoTransportChannelByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h TransportChannelHttp) SearchTransportChannelPage(ctx microservice.IConte
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchTransportChannel(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchTransportChannel(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -319,7 +319,7 @@ func (h TransportChannelHttp) SearchTransportChannelStep(ctx microservice.IConte
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchTransportChannelStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchTransportChannelStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h TransportChannelHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
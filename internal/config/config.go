@@ -20,6 +20,17 @@ type IConfig interface {
 	OpenSearchPersisterConfig() IPersisterOpenSearchConfig
 	CacherConfig() ICacherConfig
 	MQConfig() IMQConfig
+	ConsumerRetryConfig() IConsumerRetryConfig
+	ConsumerWorkerConfig() IConsumerWorkerConfig
+	OutboxConfig() IOutboxConfig
+	JobConfig() IJobConfig
+	EncryptionConfig() IEncryptionConfig
+	LoginGuardConfig() ILoginGuardConfig
+	PasswordPolicyConfig() IPasswordPolicyConfig
+	TenantConfig() ITenantConfig
+	WebhookConfig() IWebhookConfig
+	OperatorConfig() IOperatorConfig
+	DocSequenceConfig() IDocSequenceConfig
 	TopicName() string
 	HttpCORS() []string
 
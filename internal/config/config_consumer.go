@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// IConsumerRetryConfig is retry and dead letter configuration for message queue consumers
+type IConsumerRetryConfig interface {
+	MaxRetries() int
+	InitialBackoff() time.Duration
+	MaxBackoff() time.Duration
+	BackoffMultiplier() float64
+	DeadLetterEnabled() bool
+}
+
+type ConsumerRetryConfig struct{}
+
+func NewConsumerRetryConfig() *ConsumerRetryConfig {
+	return &ConsumerRetryConfig{}
+}
+
+func (cfg *ConsumerRetryConfig) MaxRetries() int {
+	return getEnvInt("CONSUMER_MAX_RETRIES", 3)
+}
+
+func (cfg *ConsumerRetryConfig) InitialBackoff() time.Duration {
+	return time.Duration(getEnvInt("CONSUMER_RETRY_INITIAL_BACKOFF_MS", 500)) * time.Millisecond
+}
+
+func (cfg *ConsumerRetryConfig) MaxBackoff() time.Duration {
+	return time.Duration(getEnvInt("CONSUMER_RETRY_MAX_BACKOFF_MS", 30000)) * time.Millisecond
+}
+
+func (cfg *ConsumerRetryConfig) BackoffMultiplier() float64 {
+	multiplier, err := strconv.ParseFloat(getEnv("CONSUMER_RETRY_BACKOFF_MULTIPLIER", "2"), 64)
+	if err != nil || multiplier < 1 {
+		return 2
+	}
+	return multiplier
+}
+
+func (cfg *ConsumerRetryConfig) DeadLetterEnabled() bool {
+	return getEnv("CONSUMER_DEAD_LETTER_ENABLE", "true") == "true"
+}
+
+func (*Config) ConsumerRetryConfig() IConsumerRetryConfig {
+	return NewConsumerRetryConfig()
+}
+
+// IConsumerWorkerConfig is size of worker pool used by consumers which process messages in parallel
+type IConsumerWorkerConfig interface {
+	Workers() int
+	QueueSize() int
+}
+
+type ConsumerWorkerConfig struct{}
+
+func NewConsumerWorkerConfig() *ConsumerWorkerConfig {
+	return &ConsumerWorkerConfig{}
+}
+
+func (cfg *ConsumerWorkerConfig) Workers() int {
+	return getEnvInt("CONSUMER_WORKERS", 8)
+}
+
+// QueueSize is number of waiting tasks per worker, producer of the task is blocked when the queue is full
+func (cfg *ConsumerWorkerConfig) QueueSize() int {
+	return getEnvInt("CONSUMER_WORKER_QUEUE_SIZE", 100)
+}
+
+func (*Config) ConsumerWorkerConfig() IConsumerWorkerConfig {
+	return NewConsumerWorkerConfig()
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// IDocSequenceConfig is configuration of doc no sequencer of transaction documents
+type IDocSequenceConfig interface {
+	ReserveExpire() time.Duration
+	UsedReservationRetention() time.Duration
+}
+
+type DocSequenceConfig struct{}
+
+func NewDocSequenceConfig() *DocSequenceConfig {
+	return &DocSequenceConfig{}
+}
+
+// ReserveExpire is how long reserved doc no is held, reserved number of gap-free format is given again after it expire
+func (cfg *DocSequenceConfig) ReserveExpire() time.Duration {
+	return time.Duration(getEnvInt("DOC_SEQUENCE_RESERVE_EXPIRE_MINUTES", 30)) * time.Minute
+}
+
+// UsedReservationRetention is how long reservation of doc no which is used by a document is kept
+func (cfg *DocSequenceConfig) UsedReservationRetention() time.Duration {
+	return time.Duration(getEnvInt("DOC_SEQUENCE_USED_RETENTION_DAYS", 7)) * 24 * time.Hour
+}
+
+func (*Config) DocSequenceConfig() IDocSequenceConfig {
+	return NewDocSequenceConfig()
+}
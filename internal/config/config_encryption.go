@@ -0,0 +1,22 @@
+package config
+
+// IEncryptionConfig is configuration for field level encryption of secrets at rest
+type IEncryptionConfig interface {
+	MasterKey() string
+}
+
+type EncryptionConfig struct{}
+
+func NewEncryptionConfig() *EncryptionConfig {
+	return &EncryptionConfig{}
+}
+
+// MasterKey is base64 of 32 bytes key which wrap data key of each shop,
+// service which store secrets does not start when it is empty
+func (cfg *EncryptionConfig) MasterKey() string {
+	return getEnv("ENCRYPTION_MASTER_KEY", "")
+}
+
+func (*Config) EncryptionConfig() IEncryptionConfig {
+	return NewEncryptionConfig()
+}
@@ -9,6 +9,7 @@ type IHttpConfig interface {
 	PathPrefix() string
 	IgnoreLogUrls() []string
 	CORS() []string
+	TrustedProxies() []string
 }
 
 type HttpConfig struct{}
@@ -51,3 +52,13 @@ func (c *HttpConfig) CORS() []string {
 	rawCORS := getEnv("HTTP_CORS", "*")
 	return strings.Split(rawCORS, " ")
 }
+
+// TrustedProxies is CIDR of proxies which X-Forwarded-For is trusted from, client ip is the remote address when it is empty
+func (c *HttpConfig) TrustedProxies() []string {
+	rawProxies := getEnv("HTTP_TRUSTED_PROXIES", "")
+	if rawProxies == "" {
+		return []string{}
+	}
+
+	return strings.Split(rawProxies, " ")
+}
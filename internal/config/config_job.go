@@ -0,0 +1,46 @@
+package config
+
+import "time"
+
+// IJobConfig is configuration for background jobs which are started by http and polled by job id
+type IJobConfig interface {
+	Workers() int
+	QueueSize() int
+	Retention() time.Duration
+	HeartbeatInterval() time.Duration
+	QueuedTimeout() time.Duration
+}
+
+type JobConfig struct{}
+
+func NewJobConfig() *JobConfig {
+	return &JobConfig{}
+}
+
+func (cfg *JobConfig) Workers() int {
+	return getEnvInt("JOB_WORKERS", 4)
+}
+
+// QueueSize is number of waiting jobs per worker, the request which start a job is rejected when the queue is full
+func (cfg *JobConfig) QueueSize() int {
+	return getEnvInt("JOB_QUEUE_SIZE", 100)
+}
+
+// Retention is how long finished job is kept before it is removed by mongo TTL index
+func (cfg *JobConfig) Retention() time.Duration {
+	return time.Duration(getEnvInt("JOB_RETENTION_HOURS", 72)) * time.Hour
+}
+
+// HeartbeatInterval is how often running job is touched and checked for cancellation
+func (cfg *JobConfig) HeartbeatInterval() time.Duration {
+	return time.Duration(getEnvInt("JOB_HEARTBEAT_INTERVAL_MS", 2000)) * time.Millisecond
+}
+
+// QueuedTimeout is how long a job can wait in the queue, queued job which is not started is lost with its replica
+func (cfg *JobConfig) QueuedTimeout() time.Duration {
+	return time.Duration(getEnvInt("JOB_QUEUED_TIMEOUT_MINUTES", 360)) * time.Minute
+}
+
+func (*Config) JobConfig() IJobConfig {
+	return NewJobConfig()
+}
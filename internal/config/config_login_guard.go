@@ -0,0 +1,78 @@
+package config
+
+import "time"
+
+// ILoginGuardConfig is configuration for throttling and lockout of failed logins and otp sending
+type ILoginGuardConfig interface {
+	FailureWindow() time.Duration
+	DelayAfterFailures() int
+	BaseDelay() time.Duration
+	MaxDelay() time.Duration
+	MaxFailures() int
+	IPMaxFailures() int
+	LockoutDuration() time.Duration
+	OTPSendInterval() time.Duration
+	OTPMaxPerHour() int
+	OTPIPMaxPerHour() int
+	SecurityEventRetention() time.Duration
+}
+
+type LoginGuardConfig struct{}
+
+func NewLoginGuardConfig() *LoginGuardConfig {
+	return &LoginGuardConfig{}
+}
+
+// FailureWindow is how long failed attempts are counted after the last failure
+func (cfg *LoginGuardConfig) FailureWindow() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute
+}
+
+// DelayAfterFailures is number of failures before next attempt has to wait, the wait is doubled on each failure
+func (cfg *LoginGuardConfig) DelayAfterFailures() int {
+	return getEnvInt("LOGIN_DELAY_AFTER_FAILURES", 3)
+}
+
+func (cfg *LoginGuardConfig) BaseDelay() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_BASE_DELAY_MS", 1000)) * time.Millisecond
+}
+
+func (cfg *LoginGuardConfig) MaxDelay() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_MAX_DELAY_MS", 30000)) * time.Millisecond
+}
+
+// MaxFailures is number of failures of the username before it is locked out
+func (cfg *LoginGuardConfig) MaxFailures() int {
+	return getEnvInt("LOGIN_MAX_FAILURES", 10)
+}
+
+// IPMaxFailures is number of failures from the ip on any username before the ip is locked out
+func (cfg *LoginGuardConfig) IPMaxFailures() int {
+	return getEnvInt("LOGIN_IP_MAX_FAILURES", 50)
+}
+
+func (cfg *LoginGuardConfig) LockoutDuration() time.Duration {
+	return time.Duration(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+}
+
+// OTPSendInterval is minimum interval between otp which is sent to the same phone number
+func (cfg *LoginGuardConfig) OTPSendInterval() time.Duration {
+	return time.Duration(getEnvInt("OTP_SEND_INTERVAL_SECONDS", 60)) * time.Second
+}
+
+func (cfg *LoginGuardConfig) OTPMaxPerHour() int {
+	return getEnvInt("OTP_MAX_PER_HOUR", 5)
+}
+
+func (cfg *LoginGuardConfig) OTPIPMaxPerHour() int {
+	return getEnvInt("OTP_IP_MAX_PER_HOUR", 20)
+}
+
+// SecurityEventRetention is how long security events are kept before they are removed by ttl index
+func (cfg *LoginGuardConfig) SecurityEventRetention() time.Duration {
+	return time.Duration(getEnvInt("SECURITY_EVENT_RETENTION_DAYS", 90)) * 24 * time.Hour
+}
+
+func (*Config) LoginGuardConfig() ILoginGuardConfig {
+	return NewLoginGuardConfig()
+}
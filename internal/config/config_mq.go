@@ -7,6 +7,7 @@ type IMQConfig interface {
 	SSLKeyFile() string
 	SSLCAFile() string
 	SSLCertFile() string
+	Driver() string
 }
 
 // MQ and Producer configuration
@@ -36,6 +37,11 @@ func (MQConfig) SSLCertFile() string {
 	return getEnv("KAFKA_SSL_CERT_FILE", "") // /path/to/cert
 }
 
+// Driver return message queue backend, kafka or memory (in-process queue for test and single node install)
+func (MQConfig) Driver() string {
+	return getEnv("MQ_DRIVER", "kafka")
+}
+
 func (*Config) MQConfig() IMQConfig {
 	return NewMQConfig()
 }
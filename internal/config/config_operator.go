@@ -0,0 +1,52 @@
+package config
+
+import "time"
+
+// IOperatorConfig is configuration for login and guard of system administrator (operator) routes
+type IOperatorConfig interface {
+	TokenExpire() time.Duration
+	MaxLoginFailures() int
+	LockoutDuration() time.Duration
+	ReasonMinLength() int
+	RequireApproval() bool
+	ApprovalExpire() time.Duration
+}
+
+type OperatorConfig struct{}
+
+func NewOperatorConfig() *OperatorConfig {
+	return &OperatorConfig{}
+}
+
+// TokenExpire is how long operator token is valid after the last request
+func (cfg *OperatorConfig) TokenExpire() time.Duration {
+	return time.Duration(getEnvInt("OPERATOR_TOKEN_EXPIRE_MINUTES", 60)) * time.Minute
+}
+
+// MaxLoginFailures is number of failed logins of the operator before it is locked out
+func (cfg *OperatorConfig) MaxLoginFailures() int {
+	return getEnvInt("OPERATOR_MAX_LOGIN_FAILURES", 5)
+}
+
+func (cfg *OperatorConfig) LockoutDuration() time.Duration {
+	return time.Duration(getEnvInt("OPERATOR_LOCKOUT_MINUTES", 30)) * time.Minute
+}
+
+// ReasonMinLength is minimum length of reason which is required on every operator request that change data
+func (cfg *OperatorConfig) ReasonMinLength() int {
+	return getEnvInt("OPERATOR_REASON_MIN_LENGTH", 10)
+}
+
+// RequireApproval enable two-person approval, destructive operation is run only after another operator approve it
+func (cfg *OperatorConfig) RequireApproval() bool {
+	return getEnv("OPERATOR_REQUIRE_APPROVAL", "false") == "true"
+}
+
+// ApprovalExpire is how long approval request can be approved and used
+func (cfg *OperatorConfig) ApprovalExpire() time.Duration {
+	return time.Duration(getEnvInt("OPERATOR_APPROVAL_EXPIRE_MINUTES", 30)) * time.Minute
+}
+
+func (*Config) OperatorConfig() IOperatorConfig {
+	return NewOperatorConfig()
+}
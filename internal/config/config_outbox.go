@@ -0,0 +1,56 @@
+package config
+
+import "time"
+
+// IOutboxConfig is configuration for outbox relay which publish outbox events to message queue
+type IOutboxConfig interface {
+	PollInterval() time.Duration
+	BatchSize() int
+	ClaimTimeout() time.Duration
+	MaxBackoff() time.Duration
+	MaxAttempts() int
+	SentRetention() time.Duration
+	PurgeSchedule() string
+}
+
+type OutboxConfig struct{}
+
+func NewOutboxConfig() *OutboxConfig {
+	return &OutboxConfig{}
+}
+
+func (cfg *OutboxConfig) PollInterval() time.Duration {
+	return time.Duration(getEnvInt("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond
+}
+
+func (cfg *OutboxConfig) BatchSize() int {
+	return getEnvInt("OUTBOX_BATCH_SIZE", 100)
+}
+
+// ClaimTimeout is how long a claimed event is hidden from other relays before it can be claimed again
+func (cfg *OutboxConfig) ClaimTimeout() time.Duration {
+	return time.Duration(getEnvInt("OUTBOX_CLAIM_TIMEOUT_MS", 60000)) * time.Millisecond
+}
+
+func (cfg *OutboxConfig) MaxBackoff() time.Duration {
+	return time.Duration(getEnvInt("OUTBOX_MAX_BACKOFF_MS", 300000)) * time.Millisecond
+}
+
+// MaxAttempts is number of attempts to publish an event before it is parked
+func (cfg *OutboxConfig) MaxAttempts() int {
+	return getEnvInt("OUTBOX_MAX_ATTEMPTS", 20)
+}
+
+// SentRetention is how long sent events are kept before they are purged
+func (cfg *OutboxConfig) SentRetention() time.Duration {
+	return time.Duration(getEnvInt("OUTBOX_SENT_RETENTION_HOURS", 168)) * time.Hour
+}
+
+// PurgeSchedule is cron expression of the job which purge sent events
+func (cfg *OutboxConfig) PurgeSchedule() string {
+	return getEnv("OUTBOX_PURGE_SCHEDULE", "0 3 * * *")
+}
+
+func (*Config) OutboxConfig() IOutboxConfig {
+	return NewOutboxConfig()
+}
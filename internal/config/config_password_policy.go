@@ -0,0 +1,40 @@
+package config
+
+// IPasswordPolicyConfig is strength rule of user password on register and password change
+type IPasswordPolicyConfig interface {
+	MinLength() int
+	RequireUpper() bool
+	RequireLower() bool
+	RequireDigit() bool
+	RequireSymbol() bool
+}
+
+type PasswordPolicyConfig struct{}
+
+func NewPasswordPolicyConfig() *PasswordPolicyConfig {
+	return &PasswordPolicyConfig{}
+}
+
+func (cfg *PasswordPolicyConfig) MinLength() int {
+	return getEnvInt("PASSWORD_MIN_LENGTH", 8)
+}
+
+func (cfg *PasswordPolicyConfig) RequireUpper() bool {
+	return getEnv("PASSWORD_REQUIRE_UPPER", "false") == "true"
+}
+
+func (cfg *PasswordPolicyConfig) RequireLower() bool {
+	return getEnv("PASSWORD_REQUIRE_LOWER", "false") == "true"
+}
+
+func (cfg *PasswordPolicyConfig) RequireDigit() bool {
+	return getEnv("PASSWORD_REQUIRE_DIGIT", "true") == "true"
+}
+
+func (cfg *PasswordPolicyConfig) RequireSymbol() bool {
+	return getEnv("PASSWORD_REQUIRE_SYMBOL", "false") == "true"
+}
+
+func (*Config) PasswordPolicyConfig() IPasswordPolicyConfig {
+	return NewPasswordPolicyConfig()
+}
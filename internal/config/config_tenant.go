@@ -0,0 +1,22 @@
+package config
+
+// ITenantConfig is configuration for tenant isolation of persisters
+type ITenantConfig interface {
+	IsolationMode() string
+}
+
+type TenantConfig struct{}
+
+func NewTenantConfig() *TenantConfig {
+	return &TenantConfig{}
+}
+
+// IsolationMode is off, report or enforce, query which is not scoped to a shop is logged on report
+// and refused on enforce, report is the default until every query across shops opt in by WithSystemAdmin
+func (cfg *TenantConfig) IsolationMode() string {
+	return getEnv("TENANT_ISOLATION_MODE", "report")
+}
+
+func (*Config) TenantConfig() ITenantConfig {
+	return NewTenantConfig()
+}
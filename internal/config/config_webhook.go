@@ -0,0 +1,73 @@
+package config
+
+import "time"
+
+// IWebhookConfig is configuration for delivery of webhooks to urls of shop subscriptions
+type IWebhookConfig interface {
+	ConsumerGroup() string
+	PollInterval() time.Duration
+	BatchSize() int
+	ClaimTimeout() time.Duration
+	RequestTimeout() time.Duration
+	MaxAttempts() int
+	BaseBackoff() time.Duration
+	MaxBackoff() time.Duration
+	DeliveryRetention() time.Duration
+	AllowPrivateNetwork() bool
+}
+
+type WebhookConfig struct{}
+
+func NewWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{}
+}
+
+func (cfg *WebhookConfig) ConsumerGroup() string {
+	return getEnv("WEBHOOK_CONSUMER_GROUP", "webhook-consumer-group-01")
+}
+
+func (cfg *WebhookConfig) PollInterval() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_POLL_INTERVAL_MS", 1000)) * time.Millisecond
+}
+
+func (cfg *WebhookConfig) BatchSize() int {
+	return getEnvInt("WEBHOOK_BATCH_SIZE", 50)
+}
+
+// ClaimTimeout is how long a claimed delivery is hidden from other dispatchers before it can be claimed again
+func (cfg *WebhookConfig) ClaimTimeout() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_CLAIM_TIMEOUT_MS", 60000)) * time.Millisecond
+}
+
+// RequestTimeout is how long to wait for response of the subscriber url
+func (cfg *WebhookConfig) RequestTimeout() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_REQUEST_TIMEOUT_MS", 10000)) * time.Millisecond
+}
+
+// MaxAttempts is number of attempts before delivery is marked failed, failed delivery can be redelivered manually
+func (cfg *WebhookConfig) MaxAttempts() int {
+	return getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8)
+}
+
+// BaseBackoff is wait before the second attempt, it is doubled every attempt up to max backoff
+func (cfg *WebhookConfig) BaseBackoff() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_BASE_BACKOFF_MS", 30000)) * time.Millisecond
+}
+
+func (cfg *WebhookConfig) MaxBackoff() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_MAX_BACKOFF_MS", 3600000)) * time.Millisecond
+}
+
+// DeliveryRetention is how long delivery log is kept
+func (cfg *WebhookConfig) DeliveryRetention() time.Duration {
+	return time.Duration(getEnvInt("WEBHOOK_DELIVERY_RETENTION_DAYS", 30)) * 24 * time.Hour
+}
+
+// AllowPrivateNetwork allow webhook url of loopback, private and link-local address, it is for local development only
+func (cfg *WebhookConfig) AllowPrivateNetwork() bool {
+	return getEnv("WEBHOOK_ALLOW_PRIVATE_NETWORK", "false") == "true"
+}
+
+func (*Config) WebhookConfig() IWebhookConfig {
+	return NewWebhookConfig()
+}
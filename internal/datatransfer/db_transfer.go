@@ -122,6 +122,14 @@ func (db *DBTransfer) BeginTransfer(shopID string, targetShopID string) {
 		panic(err)
 	}
 
+	// data key of encrypted secrets
+	fmt.Println("Start transfer Data Key")
+	dataKeyDataTransfer := NewDataKeyDataTransfer(connection)
+	err = dataKeyDataTransfer.StartTransfer(todo, shopID, targetShopID)
+	if err != nil {
+		panic(err)
+	}
+
 	// qr payment
 	fmt.Println("Start transfer QR Payment")
 	qrPaymentDataTransfer := NewQRPaymentDataTransfer(connection)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"smlaicloudplatform/internal/debtaccount/debtor/models"
 	debtorRepository "smlaicloudplatform/internal/debtaccount/debtor/repositories"
+	"smlaicloudplatform/internal/encrypt"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/pkg/microservice"
 	msModels "smlaicloudplatform/pkg/microservice/models"
@@ -55,7 +56,8 @@ func NewDebtorDataTransfer(transferConnection IDataTransferConnection) IDataTran
 func (pdt *DebtorDataTransfer) StartTransfer(ctx context.Context, shopID string, targetShopID string) error {
 
 	sourceRepository := NewDebtorDataTransferRepository(pdt.transferConnection.GetSourceConnection())
-	targetRepository := debtorRepository.NewDebtorRepository(pdt.transferConnection.GetTargetConnection())
+	// documents are copied as they are stored, encrypted secrets keep data key of the source shop
+	targetRepository := debtorRepository.NewDebtorRepository(pdt.transferConnection.GetTargetConnection(), encrypt.NewPlainFieldEncryptor())
 
 	pageRequest := msModels.Pageable{
 		Limit: 100,
@@ -0,0 +1,39 @@
+package datatransfer
+
+import (
+	"context"
+	"smlaicloudplatform/internal/encrypt/datakey"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataKeyDataTransfer copy data key of the shop so encrypted secrets which are copied as they are stored
+// can be decrypted in target database, both databases have to use the same master key
+type DataKeyDataTransfer struct {
+	transferConnection IDataTransferConnection
+}
+
+func NewDataKeyDataTransfer(transferConnection IDataTransferConnection) IDataTransfer {
+	return &DataKeyDataTransfer{
+		transferConnection: transferConnection,
+	}
+}
+
+func (pdt *DataKeyDataTransfer) StartTransfer(ctx context.Context, shopID string, targetShopID string) error {
+
+	sourceRepository := datakey.NewDataKeyRepository(pdt.transferConnection.GetSourceConnection())
+	targetRepository := datakey.NewDataKeyRepository(pdt.transferConnection.GetTargetConnection())
+
+	doc, err := sourceRepository.FindByKeyID(ctx, shopID)
+	if err != nil {
+		return err
+	}
+
+	if doc.KeyID == "" {
+		return nil
+	}
+
+	doc.ID = primitive.NilObjectID
+	_, err = targetRepository.Create(ctx, doc)
+	return err
+}
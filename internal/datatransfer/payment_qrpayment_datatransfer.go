@@ -2,6 +2,7 @@ package datatransfer
 
 import (
 	"context"
+	"smlaicloudplatform/internal/encrypt"
 	"smlaicloudplatform/internal/payment/qrpayment/models"
 	qrPaymentRepository "smlaicloudplatform/internal/payment/qrpayment/repositories"
 	"smlaicloudplatform/internal/repositories"
@@ -55,7 +56,8 @@ func NewQRPaymentDataTransfer(transferConnection IDataTransferConnection) IDataT
 func (pdt *QRPaymentDataTransfer) StartTransfer(ctx context.Context, shopID string, targetShopID string) error {
 
 	sourceRepository := NewQRPaymentDataTransferRepository(pdt.transferConnection.GetSourceConnection())
-	targetRepository := qrPaymentRepository.NewQrPaymentRepository(pdt.transferConnection.GetTargetConnection())
+	// documents are copied as they are stored, encrypted secrets keep data key of the source shop
+	targetRepository := qrPaymentRepository.NewQrPaymentRepository(pdt.transferConnection.GetTargetConnection(), encrypt.NewPlainFieldEncryptor())
 
 	pageRequest := msModels.Pageable{
 		Limit: 100,
@@ -83,7 +83,7 @@ func (h CreditorHttp) CreateCreditor(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateCreditor(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateCreditor(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -128,7 +128,7 @@ func (h CreditorHttp) UpdateCreditor(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateCreditor(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateCreditor(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -159,7 +159,7 @@ func (h CreditorHttp) DeleteCreditor(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteCreditor(shopID, id, authUsername)
+	err := h.svc.DeleteCreditor(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -198,7 +198,7 @@ func (h CreditorHttp) DeleteCreditorByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteCreditorByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteCreditorByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -228,7 +228,7 @@ func (h CreditorHttp) InfoCreditor(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Creditor %v", id)
-	doc, err := h.svc.InfoCreditor(shopID, id)
+	doc, err := h.svc.InfoCreditor(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -258,7 +258,7 @@ func (h CreditorHttp) InfoCreditorByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoCreditorByCode(shopID, code)
+	doc, err := h.svc.InfoCreditorByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -298,7 +298,7 @@ func (h CreditorHttp) SearchCreditorPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchCreditor(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchCreditor(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -342,7 +342,7 @@ func (h CreditorHttp) SearchCreditorStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchCreditorStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchCreditorStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -382,7 +382,7 @@ func (h CreditorHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -14,6 +14,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -24,15 +25,15 @@ import (
 )
 
 type ICreditorHttpService interface {
-	CreateCreditor(shopID string, authUsername string, doc models.CreditorRequest) (string, error)
-	UpdateCreditor(shopID string, guid string, authUsername string, doc models.CreditorRequest) error
-	DeleteCreditor(shopID string, guid string, authUsername string) error
-	DeleteCreditorByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoCreditor(shopID string, guid string) (models.CreditorInfo, error)
-	InfoCreditorByCode(shopID string, code string) (models.CreditorInfo, error)
-	SearchCreditor(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorInfo, mongopagination.PaginationData, error)
-	SearchCreditorStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CreditorInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.CreditorRequest) (common.BulkImport, error)
+	CreateCreditor(ctx context.Context, shopID string, authUsername string, doc models.CreditorRequest) (string, error)
+	UpdateCreditor(ctx context.Context, shopID string, guid string, authUsername string, doc models.CreditorRequest) error
+	DeleteCreditor(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteCreditorByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoCreditor(ctx context.Context, shopID string, guid string) (models.CreditorInfo, error)
+	InfoCreditorByCode(ctx context.Context, shopID string, code string) (models.CreditorInfo, error)
+	SearchCreditor(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorInfo, mongopagination.PaginationData, error)
+	SearchCreditorStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CreditorInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CreditorRequest) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -66,13 +67,13 @@ func NewCreditorHttpService(
 	return insSvc
 }
 
-func (svc CreditorHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc CreditorHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc CreditorHttpService) CreateCreditor(shopID string, authUsername string, doc models.CreditorRequest) (string, error) {
+func (svc CreditorHttpService) CreateCreditor(ctx context.Context, shopID string, authUsername string, doc models.CreditorRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -113,9 +114,9 @@ func (svc CreditorHttpService) CreateCreditor(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc CreditorHttpService) UpdateCreditor(shopID string, guid string, authUsername string, doc models.CreditorRequest) error {
+func (svc CreditorHttpService) UpdateCreditor(ctx context.Context, shopID string, guid string, authUsername string, doc models.CreditorRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -153,8 +154,8 @@ func (svc CreditorHttpService) UpdateCreditor(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc CreditorHttpService) DeleteCreditor(shopID string, guid string, authUsername string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) DeleteCreditor(ctx context.Context, shopID string, guid string, authUsername string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -183,8 +184,8 @@ func (svc CreditorHttpService) DeleteCreditor(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc CreditorHttpService) DeleteCreditorByGUIDs(shopID string, authUsername string, GUIDs []string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) DeleteCreditorByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
@@ -212,8 +213,8 @@ func (svc CreditorHttpService) DeleteCreditorByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc CreditorHttpService) InfoCreditor(shopID string, guid string) (models.CreditorInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) InfoCreditor(ctx context.Context, shopID string, guid string) (models.CreditorInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// Find the document by guid
@@ -251,8 +252,8 @@ func (svc CreditorHttpService) InfoCreditor(shopID string, guid string) (models.
 	return findDoc.CreditorInfo, nil
 }
 
-func (svc CreditorHttpService) InfoCreditorByCode(shopID string, code string) (models.CreditorInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) InfoCreditorByCode(ctx context.Context, shopID string, code string) (models.CreditorInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -286,8 +287,8 @@ func (svc CreditorHttpService) InfoCreditorByCode(shopID string, code string) (m
 
 }
 
-func (svc CreditorHttpService) SearchCreditor(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorInfo, mongopagination.PaginationData, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) SearchCreditor(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorInfo, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -326,8 +327,8 @@ func (svc CreditorHttpService) SearchCreditor(shopID string, filters map[string]
 	return docList, pagination, nil
 }
 
-func (svc CreditorHttpService) SearchCreditorStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CreditorInfo, int, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) SearchCreditorStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CreditorInfo, int, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -368,8 +369,8 @@ func (svc CreditorHttpService) SearchCreditorStep(shopID string, langCode string
 	return docList, total, nil
 }
 
-func (svc CreditorHttpService) SaveInBatch(shopID string, authUsername string, dataListParam []models.CreditorRequest) (common.BulkImport, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataListParam []models.CreditorRequest) (common.BulkImport, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	dataList := []models.Creditor{}
@@ -77,7 +77,7 @@ func (h CreditorGroupHttp) CreateCreditorGroup(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.svc.CreateCreditorGroup(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateCreditorGroup(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h CreditorGroupHttp) UpdateCreditorGroup(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdateCreditorGroup(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateCreditorGroup(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h CreditorGroupHttp) DeleteCreditorGroup(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteCreditorGroup(shopID, id, authUsername)
+	err := h.svc.DeleteCreditorGroup(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h CreditorGroupHttp) DeleteCreditorGroupByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeleteCreditorGroupByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteCreditorGroupByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h CreditorGroupHttp) InfoCreditorGroup(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get CreditorGroup %v", id)
-	doc, err := h.svc.InfoCreditorGroup(shopID, id)
+	doc, err := h.svc.InfoCreditorGroup(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h CreditorGroupHttp) SearchCreditorGroupPage(ctx microservice.IContext) er
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchCreditorGroup(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchCreditorGroup(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h CreditorGroupHttp) SearchCreditorGroupStep(ctx microservice.IContext) er
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchCreditorGroupStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchCreditorGroupStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +329,7 @@ func (h CreditorGroupHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type ICreditorGroupHttpService interface {
-	CreateCreditorGroup(shopID string, authUsername string, doc models.CreditorGroup) (string, error)
-	UpdateCreditorGroup(shopID string, guid string, authUsername string, doc models.CreditorGroup) error
-	DeleteCreditorGroup(shopID string, guid string, authUsername string) error
-	DeleteCreditorGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoCreditorGroup(shopID string, guid string) (models.CreditorGroupInfo, error)
-	SearchCreditorGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorGroupInfo, mongopagination.PaginationData, error)
-	SearchCreditorGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CreditorGroupInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.CreditorGroup) (common.BulkImport, error)
+	CreateCreditorGroup(ctx context.Context, shopID string, authUsername string, doc models.CreditorGroup) (string, error)
+	UpdateCreditorGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.CreditorGroup) error
+	DeleteCreditorGroup(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteCreditorGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoCreditorGroup(ctx context.Context, shopID string, guid string) (models.CreditorGroupInfo, error)
+	SearchCreditorGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorGroupInfo, mongopagination.PaginationData, error)
+	SearchCreditorGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CreditorGroupInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CreditorGroup) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -53,12 +54,12 @@ func NewCreditorGroupHttpService(repo repositories.ICreditorGroupRepository, syn
 	return insSvc
 }
 
-func (svc CreditorGroupHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc CreditorGroupHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc CreditorGroupHttpService) CreateCreditorGroup(shopID string, authUsername string, doc models.CreditorGroup) (string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) CreateCreditorGroup(ctx context.Context, shopID string, authUsername string, doc models.CreditorGroup) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "groupcode", doc.GroupCode)
@@ -92,8 +93,8 @@ func (svc CreditorGroupHttpService) CreateCreditorGroup(shopID string, authUsern
 	return newGuidFixed, nil
 }
 
-func (svc CreditorGroupHttpService) UpdateCreditorGroup(shopID string, guid string, authUsername string, doc models.CreditorGroup) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) UpdateCreditorGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.CreditorGroup) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -122,8 +123,8 @@ func (svc CreditorGroupHttpService) UpdateCreditorGroup(shopID string, guid stri
 	return nil
 }
 
-func (svc CreditorGroupHttpService) DeleteCreditorGroup(shopID string, guid string, authUsername string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) DeleteCreditorGroup(ctx context.Context, shopID string, guid string, authUsername string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
 
@@ -145,8 +146,8 @@ func (svc CreditorGroupHttpService) DeleteCreditorGroup(shopID string, guid stri
 	return nil
 }
 
-func (svc CreditorGroupHttpService) DeleteCreditorGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) DeleteCreditorGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -161,8 +162,8 @@ func (svc CreditorGroupHttpService) DeleteCreditorGroupByGUIDs(shopID string, au
 	return nil
 }
 
-func (svc CreditorGroupHttpService) InfoCreditorGroup(shopID string, guid string) (models.CreditorGroupInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) InfoCreditorGroup(ctx context.Context, shopID string, guid string) (models.CreditorGroupInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -179,8 +180,8 @@ func (svc CreditorGroupHttpService) InfoCreditorGroup(shopID string, guid string
 
 }
 
-func (svc CreditorGroupHttpService) SearchCreditorGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorGroupInfo, mongopagination.PaginationData, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) SearchCreditorGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CreditorGroupInfo, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -197,8 +198,8 @@ func (svc CreditorGroupHttpService) SearchCreditorGroup(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc CreditorGroupHttpService) SearchCreditorGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CreditorGroupInfo, int, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) SearchCreditorGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CreditorGroupInfo, int, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -217,8 +218,8 @@ func (svc CreditorGroupHttpService) SearchCreditorGroupStep(shopID string, langC
 	return docList, total, nil
 }
 
-func (svc CreditorGroupHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.CreditorGroup) (common.BulkImport, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CreditorGroupHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CreditorGroup) (common.BulkImport, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.CreditorGroup](dataList, svc.getDocIDKey)
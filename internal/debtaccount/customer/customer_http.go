@@ -80,7 +80,7 @@ func (h CustomerHttp) CreateCustomer(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateCustomer(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateCustomer(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -125,7 +125,7 @@ func (h CustomerHttp) UpdateCustomer(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateCustomer(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateCustomer(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -156,7 +156,7 @@ func (h CustomerHttp) DeleteCustomer(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteCustomer(shopID, id, authUsername)
+	err := h.svc.DeleteCustomer(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -195,7 +195,7 @@ func (h CustomerHttp) DeleteCustomerByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteCustomerByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteCustomerByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -225,7 +225,7 @@ func (h CustomerHttp) InfoCustomer(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Customer %v", id)
-	doc, err := h.svc.InfoCustomer(shopID, id)
+	doc, err := h.svc.InfoCustomer(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -255,7 +255,7 @@ func (h CustomerHttp) InfoCustomerByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoCustomerByCode(shopID, code)
+	doc, err := h.svc.InfoCustomerByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -300,7 +300,7 @@ func (h CustomerHttp) SearchCustomerPage(ctx microservice.IContext) error {
 		filters["isdebtor"] = isdebtor == "true" || isdebtor == "1"
 	}
 
-	docList, pagination, err := h.svc.SearchCustomer(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchCustomer(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -349,7 +349,7 @@ func (h CustomerHttp) SearchCustomerStep(ctx microservice.IContext) error {
 		filters["isdebtor"] = isdebtor == "true" || isdebtor == "1"
 	}
 
-	docList, total, err := h.svc.SearchCustomerStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchCustomerStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -389,7 +389,7 @@ func (h CustomerHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -23,15 +24,15 @@ import (
 )
 
 type ICustomerHttpService interface {
-	CreateCustomer(shopID string, authUsername string, doc models.CustomerRequest) (string, error)
-	UpdateCustomer(shopID string, guid string, authUsername string, doc models.CustomerRequest) error
-	DeleteCustomer(shopID string, guid string, authUsername string) error
-	DeleteCustomerByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoCustomer(shopID string, guid string) (models.CustomerInfo, error)
-	InfoCustomerByCode(shopID string, code string) (models.CustomerInfo, error)
-	SearchCustomer(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerInfo, mongopagination.PaginationData, error)
-	SearchCustomerStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CustomerInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.CustomerRequest) (common.BulkImport, error)
+	CreateCustomer(ctx context.Context, shopID string, authUsername string, doc models.CustomerRequest) (string, error)
+	UpdateCustomer(ctx context.Context, shopID string, guid string, authUsername string, doc models.CustomerRequest) error
+	DeleteCustomer(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteCustomerByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoCustomer(ctx context.Context, shopID string, guid string) (models.CustomerInfo, error)
+	InfoCustomerByCode(ctx context.Context, shopID string, code string) (models.CustomerInfo, error)
+	SearchCustomer(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerInfo, mongopagination.PaginationData, error)
+	SearchCustomerStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CustomerInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CustomerRequest) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -60,13 +61,13 @@ func NewCustomerHttpService(repo repositories.ICustomerRepository, repoGroup gro
 	return insSvc
 }
 
-func (svc CustomerHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc CustomerHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc CustomerHttpService) CreateCustomer(shopID string, authUsername string, doc models.CustomerRequest) (string, error) {
+func (svc CustomerHttpService) CreateCustomer(ctx context.Context, shopID string, authUsername string, doc models.CustomerRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -105,9 +106,9 @@ func (svc CustomerHttpService) CreateCustomer(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc CustomerHttpService) UpdateCustomer(shopID string, guid string, authUsername string, doc models.CustomerRequest) error {
+func (svc CustomerHttpService) UpdateCustomer(ctx context.Context, shopID string, guid string, authUsername string, doc models.CustomerRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -141,9 +142,9 @@ func (svc CustomerHttpService) UpdateCustomer(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc CustomerHttpService) DeleteCustomer(shopID string, guid string, authUsername string) error {
+func (svc CustomerHttpService) DeleteCustomer(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -166,9 +167,9 @@ func (svc CustomerHttpService) DeleteCustomer(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc CustomerHttpService) DeleteCustomerByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc CustomerHttpService) DeleteCustomerByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -183,9 +184,9 @@ func (svc CustomerHttpService) DeleteCustomerByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc CustomerHttpService) InfoCustomer(shopID string, guid string) (models.CustomerInfo, error) {
+func (svc CustomerHttpService) InfoCustomer(ctx context.Context, shopID string, guid string) (models.CustomerInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -217,8 +218,8 @@ func (svc CustomerHttpService) InfoCustomer(shopID string, guid string) (models.
 	return findDoc.CustomerInfo, nil
 }
 
-func (svc CustomerHttpService) InfoCustomerByCode(shopID string, code string) (models.CustomerInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerHttpService) InfoCustomerByCode(ctx context.Context, shopID string, code string) (models.CustomerInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -250,9 +251,9 @@ func (svc CustomerHttpService) InfoCustomerByCode(shopID string, code string) (m
 	return findDoc.CustomerInfo, nil
 }
 
-func (svc CustomerHttpService) SearchCustomer(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerInfo, mongopagination.PaginationData, error) {
+func (svc CustomerHttpService) SearchCustomer(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -288,9 +289,9 @@ func (svc CustomerHttpService) SearchCustomer(shopID string, filters map[string]
 	return docList, pagination, nil
 }
 
-func (svc CustomerHttpService) SearchCustomerStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CustomerInfo, int, error) {
+func (svc CustomerHttpService) SearchCustomerStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CustomerInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -328,9 +329,9 @@ func (svc CustomerHttpService) SearchCustomerStep(shopID string, langCode string
 	return docList, total, nil
 }
 
-func (svc CustomerHttpService) SaveInBatch(shopID string, authUsername string, dataListParam []models.CustomerRequest) (common.BulkImport, error) {
+func (svc CustomerHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataListParam []models.CustomerRequest) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	dataList := []models.Customer{}
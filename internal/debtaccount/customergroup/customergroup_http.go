@@ -77,7 +77,7 @@ func (h CustomerGroupHttp) CreateCustomerGroup(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.svc.CreateCustomerGroup(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateCustomerGroup(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h CustomerGroupHttp) UpdateCustomerGroup(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdateCustomerGroup(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateCustomerGroup(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h CustomerGroupHttp) DeleteCustomerGroup(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteCustomerGroup(shopID, id, authUsername)
+	err := h.svc.DeleteCustomerGroup(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h CustomerGroupHttp) DeleteCustomerGroupByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeleteCustomerGroupByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteCustomerGroupByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h CustomerGroupHttp) InfoCustomerGroup(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get CustomerGroup %v", id)
-	doc, err := h.svc.InfoCustomerGroup(shopID, id)
+	doc, err := h.svc.InfoCustomerGroup(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h CustomerGroupHttp) SearchCustomerGroupPage(ctx microservice.IContext) er
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchCustomerGroup(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchCustomerGroup(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h CustomerGroupHttp) SearchCustomerGroupStep(ctx microservice.IContext) er
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchCustomerGroupStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchCustomerGroupStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +329,7 @@ func (h CustomerGroupHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type ICustomerGroupHttpService interface {
-	CreateCustomerGroup(shopID string, authUsername string, doc models.CustomerGroup) (string, error)
-	UpdateCustomerGroup(shopID string, guid string, authUsername string, doc models.CustomerGroup) error
-	DeleteCustomerGroup(shopID string, guid string, authUsername string) error
-	DeleteCustomerGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoCustomerGroup(shopID string, guid string) (models.CustomerGroupInfo, error)
-	SearchCustomerGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerGroupInfo, mongopagination.PaginationData, error)
-	SearchCustomerGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CustomerGroupInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.CustomerGroup) (common.BulkImport, error)
+	CreateCustomerGroup(ctx context.Context, shopID string, authUsername string, doc models.CustomerGroup) (string, error)
+	UpdateCustomerGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.CustomerGroup) error
+	DeleteCustomerGroup(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteCustomerGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoCustomerGroup(ctx context.Context, shopID string, guid string) (models.CustomerGroupInfo, error)
+	SearchCustomerGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerGroupInfo, mongopagination.PaginationData, error)
+	SearchCustomerGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CustomerGroupInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CustomerGroup) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewCustomerGroupHttpService(repo repositories.ICustomerGroupRepository, syn
 	return insSvc
 }
 
-func (svc CustomerGroupHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc CustomerGroupHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc CustomerGroupHttpService) CreateCustomerGroup(shopID string, authUsername string, doc models.CustomerGroup) (string, error) {
+func (svc CustomerGroupHttpService) CreateCustomerGroup(ctx context.Context, shopID string, authUsername string, doc models.CustomerGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "groupcode", doc.GroupCode)
@@ -94,9 +95,9 @@ func (svc CustomerGroupHttpService) CreateCustomerGroup(shopID string, authUsern
 	return newGuidFixed, nil
 }
 
-func (svc CustomerGroupHttpService) UpdateCustomerGroup(shopID string, guid string, authUsername string, doc models.CustomerGroup) error {
+func (svc CustomerGroupHttpService) UpdateCustomerGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.CustomerGroup) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,8 +126,8 @@ func (svc CustomerGroupHttpService) UpdateCustomerGroup(shopID string, guid stri
 	return nil
 }
 
-func (svc CustomerGroupHttpService) DeleteCustomerGroup(shopID string, guid string, authUsername string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) DeleteCustomerGroup(ctx context.Context, shopID string, guid string, authUsername string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -149,8 +150,8 @@ func (svc CustomerGroupHttpService) DeleteCustomerGroup(shopID string, guid stri
 	return nil
 }
 
-func (svc CustomerGroupHttpService) DeleteCustomerGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) DeleteCustomerGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -165,8 +166,8 @@ func (svc CustomerGroupHttpService) DeleteCustomerGroupByGUIDs(shopID string, au
 	return nil
 }
 
-func (svc CustomerGroupHttpService) InfoCustomerGroup(shopID string, guid string) (models.CustomerGroupInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) InfoCustomerGroup(ctx context.Context, shopID string, guid string) (models.CustomerGroupInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -183,8 +184,8 @@ func (svc CustomerGroupHttpService) InfoCustomerGroup(shopID string, guid string
 
 }
 
-func (svc CustomerGroupHttpService) SearchCustomerGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerGroupInfo, mongopagination.PaginationData, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) SearchCustomerGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CustomerGroupInfo, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -201,8 +202,8 @@ func (svc CustomerGroupHttpService) SearchCustomerGroup(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc CustomerGroupHttpService) SearchCustomerGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CustomerGroupInfo, int, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) SearchCustomerGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.CustomerGroupInfo, int, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -221,8 +222,8 @@ func (svc CustomerGroupHttpService) SearchCustomerGroupStep(shopID string, langC
 	return docList, total, nil
 }
 
-func (svc CustomerGroupHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.CustomerGroup) (common.BulkImport, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc CustomerGroupHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.CustomerGroup) (common.BulkImport, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.CustomerGroup](dataList, svc.getDocIDKey)
@@ -2,12 +2,16 @@ package debtor
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/debtaccount/debtor/models"
 	"smlaicloudplatform/internal/debtaccount/debtor/repositories"
 	"smlaicloudplatform/internal/debtaccount/debtor/services"
 	groupRepositories "smlaicloudplatform/internal/debtaccount/debtorgroup/repositories"
+	"smlaicloudplatform/internal/encrypt/datakey"
+	"smlaicloudplatform/internal/loginguard"
+	guardservices "smlaicloudplatform/internal/loginguard/services"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/utils"
@@ -28,7 +32,7 @@ func NewDebtorHttp(ms *microservice.Microservice, cfg config.IConfig) DebtorHttp
 	cache := ms.Cacher(cfg.CacherConfig())
 	prod := ms.Producer(cfg.MQConfig())
 
-	repo := repositories.NewDebtorRepository(pst)
+	repo := repositories.NewDebtorRepository(pst, datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()))
 	repoMq := repositories.NewDebtorMessageQueueRepository(prod)
 	repoGroup := groupRepositories.NewDebtorGroupRepository(pst)
 
@@ -40,6 +44,7 @@ func NewDebtorHttp(ms *microservice.Microservice, cfg config.IConfig) DebtorHttp
 		masterSyncCacheRepo,
 		utils.HashPassword,
 		utils.CheckHashPassword,
+		loginguard.NewLoginGuard(ms, cfg),
 	)
 
 	return DebtorHttp{
@@ -91,7 +96,12 @@ func (h DebtorHttp) AuthDebtor(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.InfoAuthDebtor(shopID, payload.Username, payload.Password)
+	idx, err := h.svc.InfoAuthDebtor(ctx.Context(), shopID, payload.Username, payload.Password, ctx.RealIp())
+
+	if errors.Is(err, guardservices.ErrTooManyAttempts) {
+		ctx.ResponseError(http.StatusTooManyRequests, err.Error())
+		return err
+	}
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -132,7 +142,7 @@ func (h DebtorHttp) CreateDebtor(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDebtor(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDebtor(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -177,7 +187,7 @@ func (h DebtorHttp) UpdateDebtor(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDebtor(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDebtor(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -208,7 +218,7 @@ func (h DebtorHttp) DeleteDebtor(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDebtor(shopID, id, authUsername)
+	err := h.svc.DeleteDebtor(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -247,7 +257,7 @@ func (h DebtorHttp) DeleteDebtorByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteDebtorByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDebtorByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -277,7 +287,7 @@ func (h DebtorHttp) InfoDebtor(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Debtor %v", id)
-	doc, err := h.svc.InfoDebtor(shopID, id)
+	doc, err := h.svc.InfoDebtor(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -307,7 +317,7 @@ func (h DebtorHttp) InfoDebtorByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoDebtorByCode(shopID, code)
+	doc, err := h.svc.InfoDebtorByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -346,7 +356,7 @@ func (h DebtorHttp) SearchDebtorPage(ctx microservice.IContext) error {
 			Type:  requestfilter.FieldTypeString,
 		},
 	})
-	docList, pagination, err := h.svc.SearchDebtor(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchDebtor(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -390,7 +400,7 @@ func (h DebtorHttp) SearchDebtorStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchDebtorStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchDebtorStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -430,7 +440,7 @@ func (h DebtorHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -42,6 +42,11 @@ type DebtorAuth struct {
 	Password string `json:"password" bson:"password"`
 }
 
+// SecretFields are encrypted at rest and redacted in api response
+func (doc *Debtor) SecretFields() []*string {
+	return []*string{&doc.Auth.Password}
+}
+
 type Address struct {
 	GUID            string          `json:"guid" bson:"guid"`
 	Address         *[]string       `json:"address" bson:"address"`
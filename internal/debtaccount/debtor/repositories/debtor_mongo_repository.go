@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"smlaicloudplatform/internal/debtaccount/debtor/models"
+	"smlaicloudplatform/internal/encrypt"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
@@ -36,18 +37,21 @@ type IDebtorRepository interface {
 	FindAuthByUsername(ctx context.Context, shopID string, username string) (models.DebtorDoc, error)
 }
 
+// DebtorRepository encrypt secret fields before they are written and decrypt them after they are read
 type DebtorRepository struct {
-	pst microservice.IPersisterMongo
+	pst       microservice.IPersisterMongo
+	encryptor encrypt.IFieldEncryptor
 	repositories.CrudRepository[models.DebtorDoc]
 	repositories.SearchRepository[models.DebtorInfo]
 	repositories.GuidRepository[models.DebtorItemGuid]
 	repositories.ActivityRepository[models.DebtorActivity, models.DebtorDeleteActivity]
 }
 
-func NewDebtorRepository(pst microservice.IPersisterMongo) *DebtorRepository {
+func NewDebtorRepository(pst microservice.IPersisterMongo, encryptor encrypt.IFieldEncryptor) *DebtorRepository {
 
 	insRepo := &DebtorRepository{
-		pst: pst,
+		pst:       pst,
+		encryptor: encryptor,
 	}
 
 	insRepo.CrudRepository = repositories.NewCrudRepository[models.DebtorDoc](pst)
@@ -73,5 +77,150 @@ func (repo DebtorRepository) FindAuthByUsername(ctx context.Context, shopID stri
 		return doc, err
 	}
 
-	return doc, err
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo DebtorRepository) Create(ctx context.Context, doc models.DebtorDoc) (string, error) {
+	err := repo.encryptor.EncryptFields(ctx, doc.ShopID, doc.SecretFields()...)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.CrudRepository.Create(ctx, doc)
+}
+
+func (repo DebtorRepository) CreateInBatch(ctx context.Context, docList []models.DebtorDoc) error {
+	encryptedDocList := make([]models.DebtorDoc, len(docList))
+	for i, doc := range docList {
+		err := repo.encryptor.EncryptFields(ctx, doc.ShopID, doc.SecretFields()...)
+		if err != nil {
+			return err
+		}
+		encryptedDocList[i] = doc
+	}
+
+	return repo.CrudRepository.CreateInBatch(ctx, encryptedDocList)
+}
+
+func (repo DebtorRepository) Update(ctx context.Context, shopID string, guid string, doc models.DebtorDoc) error {
+	err := repo.encryptor.EncryptFields(ctx, shopID, doc.SecretFields()...)
+	if err != nil {
+		return err
+	}
+
+	return repo.CrudRepository.Update(ctx, shopID, guid, doc)
+}
+
+func (repo DebtorRepository) FindByGuid(ctx context.Context, shopID string, guid string) (models.DebtorDoc, error) {
+	doc, err := repo.CrudRepository.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo DebtorRepository) FindByGuids(ctx context.Context, shopID string, guids []string) ([]models.DebtorDoc, error) {
+	docList, err := repo.CrudRepository.FindByGuids(ctx, shopID, guids)
+	if err != nil {
+		return docList, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docList, nil
+}
+
+func (repo DebtorRepository) FindByDocIndentityGuid(ctx context.Context, shopID string, indentityField string, indentityValue interface{}) (models.DebtorDoc, error) {
+	doc, err := repo.CrudRepository.FindByDocIndentityGuid(ctx, shopID, indentityField, indentityValue)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo DebtorRepository) FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.SearchRepository.FindPage(ctx, shopID, searchInFields, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+func (repo DebtorRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.SearchRepository.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+func (repo DebtorRepository) FindStep(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, projects map[string]interface{}, pageableLimit micromodels.PageableStep) ([]models.DebtorInfo, int, error) {
+	docList, total, err := repo.SearchRepository.FindStep(ctx, shopID, filters, searchInFields, projects, pageableLimit)
+	if err != nil {
+		return docList, total, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, total, err
+		}
+	}
+
+	return docList, total, nil
+}
+
+func (repo DebtorRepository) FindCreatedOrUpdatedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorActivity, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.ActivityRepository.FindCreatedOrUpdatedPage(ctx, shopID, lastUpdatedDate, filters, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+func (repo DebtorRepository) FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DebtorActivity, error) {
+	docList, err := repo.ActivityRepository.FindCreatedOrUpdatedStep(ctx, shopID, lastUpdatedDate, filters, pageableStep)
+	if err != nil {
+		return docList, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docList, nil
 }
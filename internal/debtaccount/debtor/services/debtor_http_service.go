@@ -8,12 +8,16 @@ import (
 	"smlaicloudplatform/internal/debtaccount/debtor/repositories"
 	groupModels "smlaicloudplatform/internal/debtaccount/debtorgroup/models"
 	groupRepositories "smlaicloudplatform/internal/debtaccount/debtorgroup/repositories"
+	"smlaicloudplatform/internal/encrypt"
 	"smlaicloudplatform/internal/logger"
+	guardmodels "smlaicloudplatform/internal/loginguard/models"
+	guardservices "smlaicloudplatform/internal/loginguard/services"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -24,16 +28,16 @@ import (
 )
 
 type IDebtorHttpService interface {
-	CreateDebtor(shopID string, authUsername string, doc models.DebtorRequest) (string, error)
-	UpdateDebtor(shopID string, guid string, authUsername string, doc models.DebtorRequest) error
-	DeleteDebtor(shopID string, guid string, authUsername string) error
-	DeleteDebtorByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDebtor(shopID string, guid string) (models.DebtorInfo, error)
-	InfoDebtorByCode(shopID string, code string) (models.DebtorInfo, error)
-	SearchDebtor(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error)
-	SearchDebtorStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DebtorInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.DebtorRequest) (common.BulkImport, error)
-	InfoAuthDebtor(shopID string, username string, password string) (models.DebtorInfo, error)
+	CreateDebtor(ctx context.Context, shopID string, authUsername string, doc models.DebtorRequest) (string, error)
+	UpdateDebtor(ctx context.Context, shopID string, guid string, authUsername string, doc models.DebtorRequest) error
+	DeleteDebtor(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDebtorByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDebtor(ctx context.Context, shopID string, guid string) (models.DebtorInfo, error)
+	InfoDebtorByCode(ctx context.Context, shopID string, code string) (models.DebtorInfo, error)
+	SearchDebtor(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error)
+	SearchDebtorStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DebtorInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.DebtorRequest) (common.BulkImport, error)
+	InfoAuthDebtor(ctx context.Context, shopID string, username string, password string, ip string) (models.DebtorInfo, error)
 
 	GetModuleName() string
 }
@@ -46,6 +50,7 @@ type DebtorHttpService struct {
 	services.ActivityService[models.DebtorActivity, models.DebtorDeleteActivity]
 	hashPassword      func(password string) (string, error)
 	checkHashPassword func(password, hash string) bool
+	loginGuard        guardservices.ILoginGuard
 	contextTimeout    time.Duration
 }
 
@@ -56,6 +61,7 @@ func NewDebtorHttpService(
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
 	hashPassword func(password string) (string, error),
 	checkHashPassword func(password, hash string) bool,
+	loginGuard guardservices.ILoginGuard,
 ) *DebtorHttpService {
 	contextTimeout := time.Duration(15) * time.Second
 
@@ -66,6 +72,7 @@ func NewDebtorHttpService(
 		syncCacheRepo:     syncCacheRepo,
 		hashPassword:      hashPassword,
 		checkHashPassword: checkHashPassword,
+		loginGuard:        loginGuard,
 		contextTimeout:    contextTimeout,
 	}
 
@@ -74,51 +81,58 @@ func NewDebtorHttpService(
 	return insSvc
 }
 
-func (svc DebtorHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DebtorHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DebtorHttpService) InfoAuthDebtor(shopID string, username string, password string) (models.DebtorInfo, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc DebtorHttpService) InfoAuthDebtor(ctx context.Context, shopID string, username string, password string, ip string) (models.DebtorInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if username == "" || password == "" {
 		return models.DebtorInfo{}, errors.New("username or password incorrect")
 	}
 
-	findDoc, err := svc.repo.FindAuthByUsername(ctx, shopID, username)
+	attempt := guardmodels.LoginAttempt{
+		Scope:    guardmodels.ScopeDebtor(shopID),
+		Username: username,
+		IP:       ip,
+		ShopID:   shopID,
+		AuthType: "debtor",
+	}
 
-	if err != nil {
+	if err := svc.loginGuard.Reserve(attempt); err != nil {
 		return models.DebtorInfo{}, err
 	}
 
-	if findDoc.Auth.Username == "" {
-		return models.DebtorInfo{}, errors.New("username or password incorrect")
-	}
+	findDoc, err := svc.repo.FindAuthByUsername(ctx, shopID, username)
 
-	if findDoc.ID == primitive.NilObjectID {
-		return models.DebtorInfo{}, errors.New("username or password incorrect")
+	if err != nil {
+		return models.DebtorInfo{}, err
 	}
 
-	if findDoc.Auth.Password == "" {
+	if findDoc.ID == primitive.NilObjectID || findDoc.Auth.Username == "" || findDoc.Auth.Password == "" {
+		svc.loginGuard.Failed(attempt, "user not found")
 		return models.DebtorInfo{}, errors.New("username or password incorrect")
 	}
 
 	invalidPassword := !svc.checkHashPassword(password, findDoc.Auth.Password)
 
 	if invalidPassword {
+		svc.loginGuard.Failed(attempt, "password invalid")
 		return models.DebtorInfo{}, errors.New("username or password incorrect")
 	}
 
-	findDoc.Auth.Password = ""
+	svc.loginGuard.Succeeded(attempt)
 
-	return findDoc.DebtorInfo, nil
+	encrypt.RedactFields(findDoc.SecretFields()...)
 
+	return findDoc.DebtorInfo, nil
 }
 
-func (svc DebtorHttpService) CreateDebtor(shopID string, authUsername string, doc models.DebtorRequest) (string, error) {
+func (svc DebtorHttpService) CreateDebtor(ctx context.Context, shopID string, authUsername string, doc models.DebtorRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -179,9 +193,9 @@ func (svc DebtorHttpService) CreateDebtor(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc DebtorHttpService) UpdateDebtor(shopID string, guid string, authUsername string, doc models.DebtorRequest) error {
+func (svc DebtorHttpService) UpdateDebtor(ctx context.Context, shopID string, guid string, authUsername string, doc models.DebtorRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -215,7 +229,10 @@ func (svc DebtorHttpService) UpdateDebtor(shopID string, guid string, authUserna
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	if doc.Auth.Password != "" {
+	// password is kept when it is not changed, it is redacted in api response
+	if doc.Auth.Password == "" || doc.Auth.Password == encrypt.RedactedValue {
+		dataDoc.Auth.Password = findDoc.Auth.Password
+	} else {
 		hashedPassword, err := svc.hashPassword(doc.Auth.Password)
 		if err != nil {
 			return err
@@ -240,8 +257,8 @@ func (svc DebtorHttpService) UpdateDebtor(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DebtorHttpService) DeleteDebtor(shopID string, guid string, authUsername string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc DebtorHttpService) DeleteDebtor(ctx context.Context, shopID string, guid string, authUsername string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -270,8 +287,8 @@ func (svc DebtorHttpService) DeleteDebtor(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DebtorHttpService) DeleteDebtorByGUIDs(shopID string, authUsername string, GUIDs []string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc DebtorHttpService) DeleteDebtorByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
@@ -300,9 +317,9 @@ func (svc DebtorHttpService) DeleteDebtorByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc DebtorHttpService) InfoDebtor(shopID string, guid string) (models.DebtorInfo, error) {
+func (svc DebtorHttpService) InfoDebtor(ctx context.Context, shopID string, guid string) (models.DebtorInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -330,15 +347,15 @@ func (svc DebtorHttpService) InfoDebtor(shopID string, guid string) (models.Debt
 	findDoc.DebtorInfo.Groups = &custGroupInfo
 
 	docInfo := findDoc.DebtorInfo
-	docInfo.Auth.Password = ""
+	encrypt.RedactFields(docInfo.SecretFields()...)
 
 	return docInfo, nil
 
 }
 
-func (svc DebtorHttpService) InfoDebtorByCode(shopID string, code string) (models.DebtorInfo, error) {
+func (svc DebtorHttpService) InfoDebtorByCode(ctx context.Context, shopID string, code string) (models.DebtorInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -366,15 +383,15 @@ func (svc DebtorHttpService) InfoDebtorByCode(shopID string, code string) (model
 	findDoc.DebtorInfo.Groups = &custGroupInfo
 
 	docInfo := findDoc.DebtorInfo
-	docInfo.Auth.Password = ""
+	encrypt.RedactFields(docInfo.SecretFields()...)
 
 	return docInfo, nil
 
 }
 
-func (svc DebtorHttpService) SearchDebtor(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error) {
+func (svc DebtorHttpService) SearchDebtor(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -411,15 +428,15 @@ func (svc DebtorHttpService) SearchDebtor(shopID string, filters map[string]inte
 	}
 
 	for i := 0; i < len(docList); i++ {
-		docList[i].Auth.Password = ""
+		encrypt.RedactFields(docList[i].SecretFields()...)
 	}
 
 	return docList, pagination, nil
 }
 
-func (svc DebtorHttpService) SearchDebtorStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DebtorInfo, int, error) {
+func (svc DebtorHttpService) SearchDebtorStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DebtorInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -458,15 +475,15 @@ func (svc DebtorHttpService) SearchDebtorStep(shopID string, langCode string, fi
 	}
 
 	for i := 0; i < len(docList); i++ {
-		docList[i].Auth.Password = ""
+		encrypt.RedactFields(docList[i].SecretFields()...)
 	}
 
 	return docList, total, nil
 }
 
-func (svc DebtorHttpService) SaveInBatch(shopID string, authUsername string, dataListReq []models.DebtorRequest) (common.BulkImport, error) {
+func (svc DebtorHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataListReq []models.DebtorRequest) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	dataList := []models.Debtor{}
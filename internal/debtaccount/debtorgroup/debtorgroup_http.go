@@ -77,7 +77,7 @@ func (h DebtorGroupHttp) CreateDebtorGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDebtorGroup(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDebtorGroup(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h DebtorGroupHttp) UpdateDebtorGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDebtorGroup(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDebtorGroup(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h DebtorGroupHttp) DeleteDebtorGroup(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDebtorGroup(shopID, id, authUsername)
+	err := h.svc.DeleteDebtorGroup(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h DebtorGroupHttp) DeleteDebtorGroupByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteDebtorGroupByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDebtorGroupByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h DebtorGroupHttp) InfoDebtorGroup(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get DebtorGroup %v", id)
-	doc, err := h.svc.InfoDebtorGroup(shopID, id)
+	doc, err := h.svc.InfoDebtorGroup(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h DebtorGroupHttp) SearchDebtorGroupPage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchDebtorGroup(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchDebtorGroup(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h DebtorGroupHttp) SearchDebtorGroupStep(ctx microservice.IContext) error
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchDebtorGroupStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchDebtorGroupStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +329,7 @@ func (h DebtorGroupHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IDebtorGroupHttpService interface {
-	CreateDebtorGroup(shopID string, authUsername string, doc models.DebtorGroup) (string, error)
-	UpdateDebtorGroup(shopID string, guid string, authUsername string, doc models.DebtorGroup) error
-	DeleteDebtorGroup(shopID string, guid string, authUsername string) error
-	DeleteDebtorGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDebtorGroup(shopID string, guid string) (models.DebtorGroupInfo, error)
-	SearchDebtorGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorGroupInfo, mongopagination.PaginationData, error)
-	SearchDebtorGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DebtorGroupInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.DebtorGroup) (common.BulkImport, error)
+	CreateDebtorGroup(ctx context.Context, shopID string, authUsername string, doc models.DebtorGroup) (string, error)
+	UpdateDebtorGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.DebtorGroup) error
+	DeleteDebtorGroup(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDebtorGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDebtorGroup(ctx context.Context, shopID string, guid string) (models.DebtorGroupInfo, error)
+	SearchDebtorGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorGroupInfo, mongopagination.PaginationData, error)
+	SearchDebtorGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DebtorGroupInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.DebtorGroup) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewDebtorGroupHttpService(repo repositories.IDebtorGroupRepository, syncCac
 	return insSvc
 }
 
-func (svc DebtorGroupHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DebtorGroupHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DebtorGroupHttpService) CreateDebtorGroup(shopID string, authUsername string, doc models.DebtorGroup) (string, error) {
+func (svc DebtorGroupHttpService) CreateDebtorGroup(ctx context.Context, shopID string, authUsername string, doc models.DebtorGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "groupcode", doc.GroupCode)
@@ -94,9 +95,9 @@ func (svc DebtorGroupHttpService) CreateDebtorGroup(shopID string, authUsername
 	return newGuidFixed, nil
 }
 
-func (svc DebtorGroupHttpService) UpdateDebtorGroup(shopID string, guid string, authUsername string, doc models.DebtorGroup) error {
+func (svc DebtorGroupHttpService) UpdateDebtorGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.DebtorGroup) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,9 +126,9 @@ func (svc DebtorGroupHttpService) UpdateDebtorGroup(shopID string, guid string,
 	return nil
 }
 
-func (svc DebtorGroupHttpService) DeleteDebtorGroup(shopID string, guid string, authUsername string) error {
+func (svc DebtorGroupHttpService) DeleteDebtorGroup(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -150,9 +151,9 @@ func (svc DebtorGroupHttpService) DeleteDebtorGroup(shopID string, guid string,
 	return nil
 }
 
-func (svc DebtorGroupHttpService) DeleteDebtorGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DebtorGroupHttpService) DeleteDebtorGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -167,9 +168,9 @@ func (svc DebtorGroupHttpService) DeleteDebtorGroupByGUIDs(shopID string, authUs
 	return nil
 }
 
-func (svc DebtorGroupHttpService) InfoDebtorGroup(shopID string, guid string) (models.DebtorGroupInfo, error) {
+func (svc DebtorGroupHttpService) InfoDebtorGroup(ctx context.Context, shopID string, guid string) (models.DebtorGroupInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -186,9 +187,9 @@ func (svc DebtorGroupHttpService) InfoDebtorGroup(shopID string, guid string) (m
 
 }
 
-func (svc DebtorGroupHttpService) SearchDebtorGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorGroupInfo, mongopagination.PaginationData, error) {
+func (svc DebtorGroupHttpService) SearchDebtorGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DebtorGroupInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -205,9 +206,9 @@ func (svc DebtorGroupHttpService) SearchDebtorGroup(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc DebtorGroupHttpService) SearchDebtorGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DebtorGroupInfo, int, error) {
+func (svc DebtorGroupHttpService) SearchDebtorGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DebtorGroupInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -226,9 +227,9 @@ func (svc DebtorGroupHttpService) SearchDebtorGroupStep(shopID string, langCode
 	return docList, total, nil
 }
 
-func (svc DebtorGroupHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.DebtorGroup) (common.BulkImport, error) {
+func (svc DebtorGroupHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.DebtorGroup) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.DebtorGroup](dataList, svc.getDocIDKey)
@@ -77,7 +77,7 @@ func (h DimensionHttp) CreateDimension(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDimension(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDimension(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h DimensionHttp) UpdateDimension(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDimension(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDimension(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h DimensionHttp) DeleteDimension(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDimension(shopID, id, authUsername)
+	err := h.svc.DeleteDimension(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h DimensionHttp) DeleteDimensionByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteDimensionByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDimensionByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h DimensionHttp) InfoDimension(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Dimension %v", id)
-	doc, err := h.svc.InfoDimension(shopID, id)
+	doc, err := h.svc.InfoDimension(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -263,7 +263,7 @@ func (h DimensionHttp) SearchDimensionPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchDimension(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchDimension(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -307,7 +307,7 @@ func (h DimensionHttp) SearchDimensionStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchDimensionStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchDimensionStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
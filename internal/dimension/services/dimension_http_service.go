@@ -9,6 +9,7 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,13 +18,13 @@ import (
 )
 
 type IDimensionHttpService interface {
-	CreateDimension(shopID string, authUsername string, doc models.Dimension) (string, error)
-	UpdateDimension(shopID string, guid string, authUsername string, doc models.Dimension) error
-	DeleteDimension(shopID string, guid string, authUsername string) error
-	DeleteDimensionByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDimension(shopID string, guid string) (models.DimensionInfo, error)
-	SearchDimension(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DimensionInfo, mongopagination.PaginationData, error)
-	SearchDimensionStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DimensionInfo, int, error)
+	CreateDimension(ctx context.Context, shopID string, authUsername string, doc models.Dimension) (string, error)
+	UpdateDimension(ctx context.Context, shopID string, guid string, authUsername string, doc models.Dimension) error
+	DeleteDimension(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDimensionByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDimension(ctx context.Context, shopID string, guid string) (models.DimensionInfo, error)
+	SearchDimension(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DimensionInfo, mongopagination.PaginationData, error)
+	SearchDimensionStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DimensionInfo, int, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewDimensionHttpService(
 	return insSvc
 }
 
-func (svc DimensionHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DimensionHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DimensionHttpService) CreateDimension(shopID string, authUsername string, doc models.Dimension) (string, error) {
+func (svc DimensionHttpService) CreateDimension(ctx context.Context, shopID string, authUsername string, doc models.Dimension) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -91,9 +92,9 @@ func (svc DimensionHttpService) CreateDimension(shopID string, authUsername stri
 	return newGuidFixed, nil
 }
 
-func (svc DimensionHttpService) UpdateDimension(shopID string, guid string, authUsername string, doc models.Dimension) error {
+func (svc DimensionHttpService) UpdateDimension(ctx context.Context, shopID string, guid string, authUsername string, doc models.Dimension) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -147,9 +148,9 @@ func (svc DimensionHttpService) UpdateDimension(shopID string, guid string, auth
 	return nil
 }
 
-func (svc DimensionHttpService) DeleteDimension(shopID string, guid string, authUsername string) error {
+func (svc DimensionHttpService) DeleteDimension(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -174,9 +175,9 @@ func (svc DimensionHttpService) DeleteDimension(shopID string, guid string, auth
 	return nil
 }
 
-func (svc DimensionHttpService) DeleteDimensionByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DimensionHttpService) DeleteDimensionByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -195,9 +196,9 @@ func (svc DimensionHttpService) DeleteDimensionByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc DimensionHttpService) InfoDimension(shopID string, guid string) (models.DimensionInfo, error) {
+func (svc DimensionHttpService) InfoDimension(ctx context.Context, shopID string, guid string) (models.DimensionInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -213,9 +214,9 @@ func (svc DimensionHttpService) InfoDimension(shopID string, guid string) (model
 	return findDoc.DimensionInfo, nil
 }
 
-func (svc DimensionHttpService) SearchDimension(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DimensionInfo, mongopagination.PaginationData, error) {
+func (svc DimensionHttpService) SearchDimension(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DimensionInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -232,9 +233,9 @@ func (svc DimensionHttpService) SearchDimension(shopID string, filters map[strin
 	return docList, pagination, nil
 }
 
-func (svc DimensionHttpService) SearchDimensionStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DimensionInfo, int, error) {
+func (svc DimensionHttpService) SearchDimensionStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DimensionInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -56,7 +56,7 @@ func NewDocumentImageHttp(ms *microservice.Microservice, cfg config.IConfig) *Do
 	docImageRepo := repositories.NewDocumentImageRepository(pst)
 
 	cacheRepo := journalRepo.NewJournalCacheRepository(cache)
-	svcWsJournal := journalSvc.NewJournalWebsocketService(docImageRepo, cacheRepo, time.Duration(30)*time.Minute)
+	svcWsJournal := journalSvc.NewJournalWebsocketService(docImageRepo, cacheRepo, ms.WebsocketHub(cfg.CacherConfig()), ms.Locker(cfg.CacherConfig()), time.Duration(30)*time.Minute)
 
 	return &DocumentImageHttp{
 		Module:       "GL",
@@ -101,7 +101,7 @@ func (h DocumentImageHttp) RegisterHttp() {
 
 func (h DocumentImageHttp) DocumentImageSpecial(ctx microservice.IContext) error {
 
-	err := h.service.UpdateDocumentImageReferenceGroup()
+	err := h.service.UpdateDocumentImageReferenceGroup(ctx.Context())
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -204,7 +204,7 @@ func (h DocumentImageHttp) SearchDocumentImage(ctx microservice.IContext) error
 		matchFilters["documentref"] = documentRef
 	}
 
-	docList, pagination, err := h.service.SearchDocumentImage(shopID, matchFilters, pageable)
+	docList, pagination, err := h.service.SearchDocumentImage(ctx.Context(), shopID, matchFilters, pageable)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -235,7 +235,7 @@ func (h DocumentImageHttp) GetDocumentImageInfo(ctx microservice.IContext) error
 	shopID := userInfo.ShopID
 
 	id := ctx.Param("guid")
-	doc, err := h.service.InfoDocumentImage(shopID, id)
+	doc, err := h.service.InfoDocumentImage(ctx.Context(), shopID, id)
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -272,7 +272,7 @@ func (h DocumentImageHttp) CreateDocumentImage(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, imageGroupGUID, err := h.service.CreateDocumentImage(shopID, authUsername, *docReq)
+	idx, imageGroupGUID, err := h.service.CreateDocumentImage(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -318,7 +318,7 @@ func (h DocumentImageHttp) CreateDocumentImageEdit(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.service.CreateImageEdit(shopID, authUsername, docImageGUID, *docReq)
+	err = h.service.CreateImageEdit(ctx.Context(), shopID, authUsername, docImageGUID, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -362,7 +362,7 @@ func (h DocumentImageHttp) CreateDocumentImageComment(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.service.CreateImageComment(shopID, authUsername, docImageGUID, *docReq)
+	err = h.service.CreateImageComment(ctx.Context(), shopID, authUsername, docImageGUID, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -403,7 +403,7 @@ func (h DocumentImageHttp) BulkCreateDocumentImage(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.service.BulkCreateDocumentImage(shopID, authUsername, *docReq)
+	err = h.service.BulkCreateDocumentImage(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -526,7 +526,7 @@ func (h DocumentImageHttp) UploadDocumentImage(ctx microservice.IContext) error
 	authUsername := userInfo.Username
 	shopID := userInfo.ShopID
 
-	idx, err := h.service.UploadDocumentImage(shopID, authUsername, fileHeader)
+	idx, err := h.service.UploadDocumentImage(ctx.Context(), shopID, authUsername, fileHeader)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -637,7 +637,7 @@ func (h DocumentImageHttp) ListDocumentImageGroup(ctx microservice.IContext) err
 		matchFilters["taskguid"] = folder
 	}
 
-	docList, pagination, err := h.service.ListDocumentImageGroup(shopID, matchFilters, pageable)
+	docList, pagination, err := h.service.ListDocumentImageGroup(ctx.Context(), shopID, matchFilters, pageable)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -668,7 +668,7 @@ func (h DocumentImageHttp) GetDocumentImageGroup(ctx microservice.IContext) erro
 
 	docImageGroupGUID := ctx.Param("guid")
 
-	doc, err := h.service.GetDocumentImageDocRefGroup(shopID, docImageGroupGUID)
+	doc, err := h.service.GetDocumentImageDocRefGroup(ctx.Context(), shopID, docImageGroupGUID)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -707,7 +707,7 @@ func (h DocumentImageHttp) CreateDocumentImageGroup(ctx microservice.IContext) e
 		return err
 	}
 
-	idx, err := h.service.CreateDocumentImageGroup(shopID, authUsername, *docImageGroup)
+	idx, err := h.service.CreateDocumentImageGroup(ctx.Context(), shopID, authUsername, *docImageGroup)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -747,7 +747,7 @@ func (h DocumentImageHttp) UpdateDocumentImageGroup(ctx microservice.IContext) e
 		return err
 	}
 
-	err = h.service.UpdateDocumentImageGroup(userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImageGroup)
+	err = h.service.UpdateDocumentImageGroup(ctx.Context(), userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImageGroup)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -786,7 +786,7 @@ func (h DocumentImageHttp) UpdateImageReferenceByDocumentImageGroup(ctx microser
 		return err
 	}
 
-	err = h.service.UpdateImageReferenceByDocumentImageGroup(userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImages)
+	err = h.service.UpdateImageReferenceByDocumentImageGroup(ctx.Context(), userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImages)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -825,7 +825,7 @@ func (h DocumentImageHttp) UpdateReferenceByDocumentImageGroup(ctx microservice.
 		return err
 	}
 
-	err = h.service.UpdateReferenceByDocumentImageGroup(userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImages)
+	err = h.service.UpdateReferenceByDocumentImageGroup(ctx.Context(), userInfo.ShopID, userInfo.Username, docImageGroupGUID, *docImages)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -853,7 +853,7 @@ func (h DocumentImageHttp) UngroupDocumentImageGroup(ctx microservice.IContext)
 
 	docImageGroupGUID := ctx.Param("guid")
 
-	guids, err := h.service.UnGroupDocumentImageGroup(userInfo.ShopID, userInfo.Username, docImageGroupGUID)
+	guids, err := h.service.UnGroupDocumentImageGroup(ctx.Context(), userInfo.ShopID, userInfo.Username, docImageGroupGUID)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -895,7 +895,7 @@ func (h DocumentImageHttp) UpdateStatusDocumentImageGroup(ctx microservice.ICont
 		return err
 	}
 
-	err = h.service.UpdateStatusDocumentImageGroup(shopID, authUsername, docImageGroupGUID, docReq.Status)
+	err = h.service.UpdateStatusDocumentImageGroup(ctx.Context(), shopID, authUsername, docImageGroupGUID, docReq.Status)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -936,7 +936,7 @@ func (h DocumentImageHttp) UpdateStatusDocumentImageGroupByTask(ctx microservice
 		return err
 	}
 
-	err = h.service.UpdateStatusDocumentImageGroupByTask(shopID, authUsername, taskGUID, docReq.Status)
+	err = h.service.UpdateStatusDocumentImageGroupByTask(ctx.Context(), shopID, authUsername, taskGUID, docReq.Status)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -966,7 +966,7 @@ func (h DocumentImageHttp) ReCountStatusDocumentImageGroupByTask(ctx microservic
 
 	docImageGroupGUID := ctx.Param("taskguid")
 
-	err := h.service.ReCountStatusDocumentImageGroupByTask(shopID, authUsername, docImageGroupGUID)
+	err := h.service.ReCountStatusDocumentImageGroupByTask(ctx.Context(), shopID, authUsername, docImageGroupGUID)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -1012,7 +1012,7 @@ func (h DocumentImageHttp) UpdateTagsDocumentImageGroup(ctx microservice.IContex
 		return err
 	}
 
-	err = h.service.UpdateTagsInDocumentImageGroup(shopID, authUsername, docImageGroupGUID, tags)
+	err = h.service.UpdateTagsInDocumentImageGroup(ctx.Context(), shopID, authUsername, docImageGroupGUID, tags)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -1041,7 +1041,7 @@ func (h DocumentImageHttp) GetDocumentImageGroupByDocRefInfo(ctx microservice.IC
 	shopID := userInfo.ShopID
 
 	docRef := ctx.Param("docref")
-	doc, err := h.service.GetDocumentImageGroupByDocRef(shopID, docRef)
+	doc, err := h.service.GetDocumentImageGroupByDocRef(ctx.Context(), shopID, docRef)
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", docRef, err)
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -1073,7 +1073,7 @@ func (h DocumentImageHttp) DeleteDocumentImageGroup(ctx microservice.IContext) e
 
 	id := ctx.Param("guid")
 
-	err := h.service.DeleteDocumentImageGroupByGuid(shopID, authUsername, id)
+	err := h.service.DeleteDocumentImageGroupByGuid(ctx.Context(), shopID, authUsername, id)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -1112,7 +1112,7 @@ func (h DocumentImageHttp) DeleteDocumentImageGroups(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.service.DeleteDocumentImageGroupByGuids(shopID, authUsername, guids)
+	err = h.service.DeleteDocumentImageGroupByGuids(ctx.Context(), shopID, authUsername, guids)
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
@@ -18,32 +18,32 @@ import (
 )
 
 type IDocumentImageService interface {
-	CreateDocumentImage(shopID string, authUsername string, doc models.DocumentImageRequest) (string, string, error)
-	BulkCreateDocumentImage(shopID string, authUsername string, docs []models.DocumentImageRequest) error
-	InfoDocumentImage(shopID string, guid string) (models.DocumentImageInfo, error)
-	SearchDocumentImage(shopID string, matchFilters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageInfo, mongopagination.PaginationData, error)
-	UploadDocumentImage(shopID string, authUsername string, fh *multipart.FileHeader) (*models.DocumentImageInfo, error)
-	CreateImageEdit(shopID string, authUsername string, docImageGUID string, docRequest models.ImageEditRequest) error
-	CreateImageComment(shopID string, authUsername string, docImageGUID string, docRequest models.CommentRequest) error
-
-	CreateDocumentImageGroup(shopID string, authUsername string, docImageGroup models.DocumentImageGroup) (string, error)
-	GetDocumentImageDocRefGroup(shopID string, docImageGroupGUID string) (models.DocumentImageGroupInfo, error)
-	GetDocumentImageGroupByDocRef(shopID string, docRef string) (models.DocumentImageGroupInfo, error)
-	UpdateDocumentImageGroup(shopID string, authUsername string, groupGUID string, docImageGroup models.DocumentImageGroup) error
-	UpdateImageReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docImages []models.ImageReferenceBody) error
-	UpdateReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docRef models.Reference) error
-	UpdateTagsInDocumentImageGroup(shopID string, authUsername string, groupGUID string, tags []string) error
-	UpdateStatusDocumentImageGroup(shopID string, authUsername string, groupGUID string, status int8) error
-	UnGroupDocumentImageGroup(shopID string, authUsername string, groupGUID string) ([]string, error)
-	ListDocumentImageGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageGroupInfo, mongopagination.PaginationData, error)
-	DeleteReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docRef models.Reference) error
-	DeleteDocumentImageGroupByGuid(shopID string, authUsername string, DocumentImageGroupGuidFixed string) error
-	DeleteDocumentImageGroupByGuids(shopID string, authUsername string, documentImageGroupGuidFixeds []string) error
+	CreateDocumentImage(ctx context.Context, shopID string, authUsername string, doc models.DocumentImageRequest) (string, string, error)
+	BulkCreateDocumentImage(ctx context.Context, shopID string, authUsername string, docs []models.DocumentImageRequest) error
+	InfoDocumentImage(ctx context.Context, shopID string, guid string) (models.DocumentImageInfo, error)
+	SearchDocumentImage(ctx context.Context, shopID string, matchFilters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageInfo, mongopagination.PaginationData, error)
+	UploadDocumentImage(ctx context.Context, shopID string, authUsername string, fh *multipart.FileHeader) (*models.DocumentImageInfo, error)
+	CreateImageEdit(ctx context.Context, shopID string, authUsername string, docImageGUID string, docRequest models.ImageEditRequest) error
+	CreateImageComment(ctx context.Context, shopID string, authUsername string, docImageGUID string, docRequest models.CommentRequest) error
+
+	CreateDocumentImageGroup(ctx context.Context, shopID string, authUsername string, docImageGroup models.DocumentImageGroup) (string, error)
+	GetDocumentImageDocRefGroup(ctx context.Context, shopID string, docImageGroupGUID string) (models.DocumentImageGroupInfo, error)
+	GetDocumentImageGroupByDocRef(ctx context.Context, shopID string, docRef string) (models.DocumentImageGroupInfo, error)
+	UpdateDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docImageGroup models.DocumentImageGroup) error
+	UpdateImageReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docImages []models.ImageReferenceBody) error
+	UpdateReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docRef models.Reference) error
+	UpdateTagsInDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, tags []string) error
+	UpdateStatusDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, status int8) error
+	UnGroupDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string) ([]string, error)
+	ListDocumentImageGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageGroupInfo, mongopagination.PaginationData, error)
+	DeleteReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docRef models.Reference) error
+	DeleteDocumentImageGroupByGuid(ctx context.Context, shopID string, authUsername string, DocumentImageGroupGuidFixed string) error
+	DeleteDocumentImageGroupByGuids(ctx context.Context, shopID string, authUsername string, documentImageGroupGuidFixeds []string) error
 	XSortsUpdate(ctx context.Context, shopID string, authUsername string, taskGUID string, xsorts []models.XSortDocumentImageGroupRequest) error
 
-	UpdateDocumentImageReferenceGroup() error
-	UpdateStatusDocumentImageGroupByTask(shopID string, authUsername string, taskGUID string, status int8) error
-	ReCountStatusDocumentImageGroupByTask(shopID string, authUsername string, taskGUID string) error
+	UpdateDocumentImageReferenceGroup(ctx context.Context) error
+	UpdateStatusDocumentImageGroupByTask(ctx context.Context, shopID string, authUsername string, taskGUID string, status int8) error
+	ReCountStatusDocumentImageGroupByTask(ctx context.Context, shopID string, authUsername string, taskGUID string) error
 }
 
 type DocumentImageService struct {
@@ -77,13 +77,13 @@ func NewDocumentImageService(repo repositories.IDocumentImageRepository, repoIma
 	}
 }
 
-func (svc DocumentImageService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DocumentImageService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DocumentImageService) CreateDocumentImage(shopID string, authUsername string, docRequest models.DocumentImageRequest) (string, string, error) {
+func (svc DocumentImageService) CreateDocumentImage(ctx context.Context, shopID string, authUsername string, docRequest models.DocumentImageRequest) (string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocImgGroup := models.DocumentImageGroupDoc{}
@@ -163,8 +163,8 @@ func (svc DocumentImageService) CreateDocumentImage(shopID string, authUsername
 	return documentImageGUID, imageGroupGUID, nil
 }
 
-func (svc DocumentImageService) CreateDocumentImageWithTask(shopID string, authUsername string, docRequest models.DocumentImageRequest) (string, string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc DocumentImageService) CreateDocumentImageWithTask(ctx context.Context, shopID string, authUsername string, docRequest models.DocumentImageRequest) (string, string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// do upload first
@@ -231,9 +231,9 @@ func (svc DocumentImageService) CreateDocumentImageWithTask(shopID string, authU
 	return documentImageGUID, imageGroupGUID, nil
 }
 
-func (svc DocumentImageService) CreateImageEdit(shopID string, authUsername string, docImageGUID string, docRequest models.ImageEditRequest) error {
+func (svc DocumentImageService) CreateImageEdit(ctx context.Context, shopID string, authUsername string, docImageGUID string, docRequest models.ImageEditRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImage.FindByGuid(ctx, shopID, docImageGUID)
@@ -268,9 +268,9 @@ func (svc DocumentImageService) CreateImageEdit(shopID string, authUsername stri
 	return nil
 }
 
-func (svc DocumentImageService) CreateImageComment(shopID string, authUsername string, docImageGUID string, docRequest models.CommentRequest) error {
+func (svc DocumentImageService) CreateImageComment(ctx context.Context, shopID string, authUsername string, docImageGUID string, docRequest models.CommentRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImage.FindByGuid(ctx, shopID, docImageGUID)
@@ -305,9 +305,9 @@ func (svc DocumentImageService) CreateImageComment(shopID string, authUsername s
 	return nil
 }
 
-func (svc DocumentImageService) BulkCreateDocumentImage(shopID string, authUsername string, docs []models.DocumentImageRequest) error {
+func (svc DocumentImageService) BulkCreateDocumentImage(ctx context.Context, shopID string, authUsername string, docs []models.DocumentImageRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// do upload first
@@ -404,9 +404,9 @@ func (svc DocumentImageService) BulkCreateDocumentImage(shopID string, authUsern
 	return nil
 }
 
-func (svc DocumentImageService) InfoDocumentImage(shopID string, guid string) (models.DocumentImageInfo, error) {
+func (svc DocumentImageService) InfoDocumentImage(ctx context.Context, shopID string, guid string) (models.DocumentImageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImage.FindByGuid(ctx, shopID, guid)
@@ -422,9 +422,9 @@ func (svc DocumentImageService) InfoDocumentImage(shopID string, guid string) (m
 	return findDoc.DocumentImageInfo, nil
 }
 
-func (svc DocumentImageService) SearchDocumentImage(shopID string, matchFilters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageInfo, mongopagination.PaginationData, error) {
+func (svc DocumentImageService) SearchDocumentImage(ctx context.Context, shopID string, matchFilters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{"guidfixed", "documentref", "module"}
@@ -437,7 +437,7 @@ func (svc DocumentImageService) SearchDocumentImage(shopID string, matchFilters
 	return docList, pagination, nil
 }
 
-func (svc DocumentImageService) UploadDocumentImage(shopID string, authUsername string, fh *multipart.FileHeader) (*models.DocumentImageInfo, error) {
+func (svc DocumentImageService) UploadDocumentImage(ctx context.Context, shopID string, authUsername string, fh *multipart.FileHeader) (*models.DocumentImageInfo, error) {
 
 	if fh.Filename == "" {
 		return nil, errors.New("image file name not found")
@@ -470,7 +470,7 @@ func (svc DocumentImageService) UploadDocumentImage(shopID string, authUsername
 		TaskGUID:      "",
 		PathTask:      "",
 	}
-	_, _, err = svc.CreateDocumentImage(shopID, authUsername, docRequest)
+	_, _, err = svc.CreateDocumentImage(ctx, shopID, authUsername, docRequest)
 
 	if err != nil {
 		return nil, err
@@ -479,9 +479,9 @@ func (svc DocumentImageService) UploadDocumentImage(shopID string, authUsername
 	return &doc.DocumentImageInfo, err
 }
 
-func (svc DocumentImageService) UpdateDocumentImageReferenceGroup() error {
+func (svc DocumentImageService) UpdateDocumentImageReferenceGroup(ctx context.Context) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocList, err := svc.repoImage.FindAll(ctx)
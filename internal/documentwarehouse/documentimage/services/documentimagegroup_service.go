@@ -17,9 +17,9 @@ import (
 
 // Group
 
-func (svc DocumentImageService) getDocumentImageNotReferencedInGroup(shopID string, currentGroupGUID string, docImageRefs []models.ImageReferenceBody) ([]models.ImageReference, []string, error) {
+func (svc DocumentImageService) getDocumentImageNotReferencedInGroup(ctx context.Context, shopID string, currentGroupGUID string, docImageRefs []models.ImageReferenceBody) ([]models.ImageReference, []string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docImageGUIDs := []string{}
@@ -61,9 +61,9 @@ func (svc DocumentImageService) getDocumentImageNotReferencedInGroup(shopID stri
 	return passDocImagesRef, tempDocumentImageGroupGUIDs, nil
 }
 
-func (svc DocumentImageService) CreateDocumentImageGroup(shopID string, authUsername string, docImageGroup models.DocumentImageGroup) (string, error) {
+func (svc DocumentImageService) CreateDocumentImageGroup(ctx context.Context, shopID string, authUsername string, docImageGroup models.DocumentImageGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if docImageGroup.ImageReferences == nil || len(*docImageGroup.ImageReferences) < 1 {
@@ -82,7 +82,7 @@ func (svc DocumentImageService) CreateDocumentImageGroup(shopID string, authUser
 			return temp.ImageReferenceBody
 		})
 
-	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(shopID, "", tempImageRefs)
+	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(ctx, shopID, "", tempImageRefs)
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +127,7 @@ func (svc DocumentImageService) CreateDocumentImageGroup(shopID string, authUser
 
 	}
 
-	err = svc.clearCreateDocumentImageGroupByDocumentGUIDs(shopID, docImageGroupGUIDs, tempGUIDDocumentImages)
+	err = svc.clearCreateDocumentImageGroupByDocumentGUIDs(ctx, shopID, docImageGroupGUIDs, tempGUIDDocumentImages)
 	if err != nil {
 		return "", err
 	}
@@ -141,9 +141,9 @@ func (svc DocumentImageService) CreateDocumentImageGroup(shopID string, authUser
 	return docImageGroupGUIDFixed, nil
 }
 
-func (svc DocumentImageService) UpdateStatusDocumentImageGroup(shopID string, authUsername string, groupGUID string, status int8) error {
+func (svc DocumentImageService) UpdateStatusDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, status int8) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -188,9 +188,9 @@ func (svc DocumentImageService) UpdateStatusDocumentImageGroup(shopID string, au
 	return nil
 }
 
-func (svc DocumentImageService) UpdateStatusDocumentImageGroupByTask(shopID string, authUsername string, taskGUID string, status int8) error {
+func (svc DocumentImageService) UpdateStatusDocumentImageGroupByTask(ctx context.Context, shopID string, authUsername string, taskGUID string, status int8) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if status != models.IMAGE_PENDING && status != models.IMAGE_CHECKED {
@@ -211,9 +211,9 @@ func (svc DocumentImageService) UpdateStatusDocumentImageGroupByTask(shopID stri
 	return nil
 }
 
-func (svc DocumentImageService) ReCountStatusDocumentImageGroupByTask(shopID string, authUsername string, taskGUID string) error {
+func (svc DocumentImageService) ReCountStatusDocumentImageGroupByTask(ctx context.Context, shopID string, authUsername string, taskGUID string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	_, err := svc.messageQueueReCountDocumentImageGroup(ctx, shopID, taskGUID)
@@ -224,9 +224,9 @@ func (svc DocumentImageService) ReCountStatusDocumentImageGroupByTask(shopID str
 	return nil
 }
 
-func (svc DocumentImageService) UpdateDocumentImageGroup(shopID string, authUsername string, groupGUID string, docImageGroup models.DocumentImageGroup) error {
+func (svc DocumentImageService) UpdateDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docImageGroup models.DocumentImageGroup) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if docImageGroup.ImageReferences == nil || len(*docImageGroup.ImageReferences) > svc.maxImageReferences {
@@ -253,7 +253,7 @@ func (svc DocumentImageService) UpdateDocumentImageGroup(shopID string, authUser
 		return temp.ImageReferenceBody
 	})
 
-	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(shopID, groupGUID, tempImageRefs)
+	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(ctx, shopID, groupGUID, tempImageRefs)
 	if err != nil {
 		return err
 	}
@@ -339,7 +339,7 @@ func (svc DocumentImageService) UpdateDocumentImageGroup(shopID string, authUser
 		return err
 	}
 
-	if err = svc.clearUpdateDocumentImageGroupByDocumentGUIDs(shopID, groupGUID, docImageGroupGUIDs, tempDocImageGUIDs); err != nil {
+	if err = svc.clearUpdateDocumentImageGroupByDocumentGUIDs(ctx, shopID, groupGUID, docImageGroupGUIDs, tempDocImageGUIDs); err != nil {
 		return err
 	}
 
@@ -366,9 +366,9 @@ func (svc DocumentImageService) UpdateDocumentImageGroup(shopID string, authUser
 	return nil
 }
 
-func (svc DocumentImageService) UpdateImageReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docImages []models.ImageReferenceBody) error {
+func (svc DocumentImageService) UpdateImageReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docImages []models.ImageReferenceBody) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -402,7 +402,7 @@ func (svc DocumentImageService) UpdateImageReferenceByDocumentImageGroup(shopID
 		return errors.New("document image invalid")
 	}
 
-	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(shopID, groupGUID, docImages)
+	passDocImagesRef, docImageGroupGUIDs, err := svc.getDocumentImageNotReferencedInGroup(ctx, shopID, groupGUID, docImages)
 	if err != nil {
 		return err
 	}
@@ -457,7 +457,7 @@ func (svc DocumentImageService) UpdateImageReferenceByDocumentImageGroup(shopID
 		return err
 	}
 
-	if err = svc.clearUpdateDocumentImageGroupByDocumentGUIDs(shopID, groupGUID, docImageGroupGUIDs, tempGUIDDocumentImages); err != nil {
+	if err = svc.clearUpdateDocumentImageGroupByDocumentGUIDs(ctx, shopID, groupGUID, docImageGroupGUIDs, tempGUIDDocumentImages); err != nil {
 		return err
 	}
 
@@ -484,9 +484,9 @@ func (svc DocumentImageService) UpdateImageReferenceByDocumentImageGroup(shopID
 	return nil
 }
 
-func (svc DocumentImageService) UpdateReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docRef models.Reference) error {
+func (svc DocumentImageService) UpdateReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docRef models.Reference) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -578,9 +578,9 @@ func (svc DocumentImageService) UpdateReferenceByDocumentImageGroup(shopID strin
 	return nil
 }
 
-func (svc DocumentImageService) UpdateTagsInDocumentImageGroup(shopID string, authUsername string, groupGUID string, tags []string) error {
+func (svc DocumentImageService) UpdateTagsInDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, tags []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -609,9 +609,9 @@ func (svc DocumentImageService) UpdateTagsInDocumentImageGroup(shopID string, au
 	return nil
 }
 
-func (svc DocumentImageService) DeleteReferenceByDocumentImageGroup(shopID string, authUsername string, groupGUID string, docRef models.Reference) error {
+func (svc DocumentImageService) DeleteReferenceByDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string, docRef models.Reference) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -663,9 +663,9 @@ func (svc DocumentImageService) DeleteReferenceByDocumentImageGroup(shopID strin
 	return nil
 }
 
-func (svc DocumentImageService) DeleteDocumentImageGroupByGuid(shopID string, authUsername string, documentImageGroupGuidFixed string) error {
+func (svc DocumentImageService) DeleteDocumentImageGroupByGuid(ctx context.Context, shopID string, authUsername string, documentImageGroupGuidFixed string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocGroup, err := svc.repoImageGroup.FindByGuid(ctx, shopID, documentImageGroupGuidFixed)
@@ -711,9 +711,9 @@ func (svc DocumentImageService) DeleteDocumentImageGroupByGuid(shopID string, au
 	return nil
 }
 
-func (svc DocumentImageService) DeleteDocumentImageGroupByGuids(shopID string, authUsername string, documentImageGroupGuidFixeds []string) error {
+func (svc DocumentImageService) DeleteDocumentImageGroupByGuids(ctx context.Context, shopID string, authUsername string, documentImageGroupGuidFixeds []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repoImageGroup.Transaction(ctx, func(ctx context.Context) error {
@@ -755,9 +755,9 @@ func (svc DocumentImageService) DeleteDocumentImageGroupByGuids(shopID string, a
 	return nil
 }
 
-func (svc DocumentImageService) UnGroupDocumentImageGroup(shopID string, authUsername string, groupGUID string) ([]string, error) {
+func (svc DocumentImageService) UnGroupDocumentImageGroup(ctx context.Context, shopID string, authUsername string, groupGUID string) ([]string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocGroup, err := svc.repoImageGroup.FindByGuid(ctx, shopID, groupGUID)
@@ -803,9 +803,9 @@ func (svc DocumentImageService) UnGroupDocumentImageGroup(shopID string, authUse
 	return newImageGroupGUIDs, nil
 }
 
-func (svc DocumentImageService) ListDocumentImageGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageGroupInfo, mongopagination.PaginationData, error) {
+func (svc DocumentImageService) ListDocumentImageGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentImageGroupInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{"title"}
@@ -814,9 +814,9 @@ func (svc DocumentImageService) ListDocumentImageGroup(shopID string, filters ma
 	return docList, pagination, err
 }
 
-func (svc DocumentImageService) GetDocumentImageDocRefGroup(shopID string, docImageGroupGUID string) (models.DocumentImageGroupInfo, error) {
+func (svc DocumentImageService) GetDocumentImageDocRefGroup(ctx context.Context, shopID string, docImageGroupGUID string) (models.DocumentImageGroupInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	doc, err := svc.repoImageGroup.FindByGuid(ctx, shopID, docImageGroupGUID)
@@ -832,9 +832,9 @@ func (svc DocumentImageService) GetDocumentImageDocRefGroup(shopID string, docIm
 	return doc.DocumentImageGroupInfo, nil
 }
 
-func (svc DocumentImageService) GetDocumentImageGroupByDocRef(shopID string, docRef string) (models.DocumentImageGroupInfo, error) {
+func (svc DocumentImageService) GetDocumentImageGroupByDocRef(ctx context.Context, shopID string, docRef string) (models.DocumentImageGroupInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repoImageGroup.FindOne(ctx, shopID, bson.M{"references.docno": docRef})
@@ -914,9 +914,9 @@ func (svc DocumentImageService) createImageGroupByDocumentImage(shopID string, a
 	return docDataImageGroup
 }
 
-func (svc DocumentImageService) clearCreateDocumentImageGroupByDocumentGUIDs(shopID string, docImageGroupGUIDs []string, docImageGUIDs []string) error {
+func (svc DocumentImageService) clearCreateDocumentImageGroupByDocumentGUIDs(ctx context.Context, shopID string, docImageGroupGUIDs []string, docImageGUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repoImageGroup.RemoveDocumentImageByDocumentImageGUIDs(ctx, shopID, docImageGUIDs)
@@ -932,9 +932,9 @@ func (svc DocumentImageService) clearCreateDocumentImageGroupByDocumentGUIDs(sho
 	return nil
 }
 
-func (svc DocumentImageService) clearUpdateDocumentImageGroupByDocumentGUIDs(shopID string, docGroupGUID string, clearDocImageGUIDs []string, docImageGUIDs []string) error {
+func (svc DocumentImageService) clearUpdateDocumentImageGroupByDocumentGUIDs(ctx context.Context, shopID string, docGroupGUID string, clearDocImageGUIDs []string, docImageGUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repoImageGroup.RemoveDocumentImageByDocumentImageGUIDsWithoutDocumentImageGroupGUID(ctx, shopID, docGroupGUID, docImageGUIDs)
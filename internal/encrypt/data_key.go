@@ -0,0 +1,22 @@
+package encrypt
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const dataKeyCollectionName = "shopDataKeys"
+
+// DataKeyDoc is data key of the shop which encrypt secret fields of the shop,
+// the key is stored wrapped by master key and never in plain text
+type DataKeyDoc struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	KeyID      string             `json:"keyid" bson:"keyid"`
+	WrappedKey string             `json:"-" bson:"wrappedkey"`
+	CreatedAt  time.Time          `json:"createdat" bson:"createdat"`
+}
+
+func (DataKeyDoc) CollectionName() string {
+	return dataKeyCollectionName
+}
@@ -0,0 +1,47 @@
+package datakey
+
+import (
+	"encoding/base64"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+// ValidateMasterKey return error when master key in config is missing or invalid,
+// services which store secrets check it at startup and do not start without valid key
+func ValidateMasterKey(cfg config.IEncryptionConfig) error {
+	_, err := newFieldEncryptor(nil, cfg)
+	return err
+}
+
+// NewShopFieldEncryptor create field encryptor of master key in config, data keys of shops are stored in mongo.
+// Master key is checked at startup by ValidateMasterKey, encryptor of invalid key return the error instead of
+// storing secrets in plain text
+func NewShopFieldEncryptor(pst microservice.IPersisterMongo, cfg config.IEncryptionConfig) encrypt.IFieldEncryptor {
+	encryptor, err := newFieldEncryptor(pst, cfg)
+	if err != nil {
+		return encrypt.NewFailedFieldEncryptor(err)
+	}
+
+	return encryptor
+}
+
+func newFieldEncryptor(pst microservice.IPersisterMongo, cfg config.IEncryptionConfig) (*encrypt.FieldEncryptor, error) {
+	if cfg.MasterKey() == "" {
+		return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY: %w", encrypt.ErrMasterKeyNotConfigured)
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(cfg.MasterKey())
+	if err != nil {
+		return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY: decode base64: %w", err)
+	}
+
+	encryptor, err := encrypt.NewFieldEncryptor(masterKey, NewDataKeyRepository(pst), time.Now)
+	if err != nil {
+		return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY: %w", err)
+	}
+
+	return encryptor, nil
+}
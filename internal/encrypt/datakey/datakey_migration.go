@@ -0,0 +1,28 @@
+package datakey
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create unique index of data key so only one data key is created for each shop
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(context.Background(), &encrypt.DataKeyDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "keyid", Value: 1}},
+		Options: options.Index().SetName("datakey_keyid").SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,40 @@
+package datakey
+
+import (
+	"context"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DataKeyRepository keep data keys of shops in mongo
+type DataKeyRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewDataKeyRepository(pst microservice.IPersisterMongo) *DataKeyRepository {
+	return &DataKeyRepository{
+		pst: pst,
+	}
+}
+
+func (repo DataKeyRepository) FindByKeyID(ctx context.Context, keyID string) (encrypt.DataKeyDoc, error) {
+	doc := encrypt.DataKeyDoc{}
+	err := repo.pst.FindOne(ctx, &encrypt.DataKeyDoc{}, bson.M{"keyid": keyID}, &doc)
+	return doc, err
+}
+
+func (repo DataKeyRepository) Create(ctx context.Context, doc encrypt.DataKeyDoc) (bool, error) {
+	_, err := repo.pst.Create(ctx, &encrypt.DataKeyDoc{}, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
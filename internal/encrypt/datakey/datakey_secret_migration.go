@@ -0,0 +1,170 @@
+package datakey
+
+import (
+	"context"
+	auditmodels "smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/pkg/microservice"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const secretMigrationBatchSize = 100
+
+// EncryptCollectionSecrets encrypt plain secret fields of documents which are written before encryption is enabled,
+// fields are dot path e.g. "auth.password", only changed fields are set so updatedat and audit log are not touched.
+// It return number of documents which are encrypted, or which would be encrypted when dryRun is true
+func EncryptCollectionSecrets(ctx context.Context, pst microservice.IPersisterMongo, encryptor encrypt.IFieldEncryptor, model interface{}, fields []string, shopID string, dryRun bool) (int, error) {
+	// documents of every shop are migrated when shop id is empty, they are updated by _id
+	ctx = microservice.WithSystemAdmin(ctx)
+
+	count := 0
+	lastID := primitive.NilObjectID
+
+	for {
+		filter := bson.M{"_id": bson.M{"$gt": lastID}}
+		if shopID != "" {
+			filter["shopid"] = shopID
+		}
+
+		docs := []bson.M{}
+		err := pst.Find(ctx, model, filter, &docs, options.Find().SetSort(bson.M{"_id": 1}).SetLimit(secretMigrationBatchSize))
+		if err != nil {
+			return count, err
+		}
+
+		for _, doc := range docs {
+			lastID = doc["_id"].(primitive.ObjectID)
+
+			docShopID, _ := doc["shopid"].(string)
+
+			// plain value is part of the filter so secret which is changed during migration is not overwritten
+			updateFilter := map[string]interface{}{"_id": lastID}
+			updateFields := bson.M{}
+
+			for _, field := range fields {
+				value, ok := secretValue(doc, field)
+				if !ok || value == "" || encrypt.IsEncrypted(value) {
+					continue
+				}
+
+				updateFilter[field] = value
+
+				err = encryptor.EncryptFields(ctx, docShopID, &value)
+				if err != nil {
+					return count, err
+				}
+				updateFields[field] = value
+			}
+
+			if len(updateFields) == 0 {
+				continue
+			}
+
+			count++
+			if dryRun {
+				continue
+			}
+
+			err = pst.UpdateOne(ctx, model, updateFilter, updateFields)
+			if err != nil {
+				return count, err
+			}
+		}
+
+		if len(docs) < secretMigrationBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// ScrubAuditSecrets redact secret fields of before, after and changes of audit logs of the collection which are
+// written before audit log redact secrets. It return number of audit logs which are redacted,
+// or which would be redacted when dryRun is true
+func ScrubAuditSecrets(ctx context.Context, pst microservice.IPersisterMongo, collection string, fields []string, shopID string, dryRun bool) (int, error) {
+	// documents of every shop are migrated when shop id is empty, they are updated by _id
+	ctx = microservice.WithSystemAdmin(ctx)
+
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	count := 0
+	lastID := primitive.NilObjectID
+
+	secretFilters := bson.A{bson.M{"changes.field": bson.M{"$in": fields}}}
+	for _, field := range fields {
+		secretFilters = append(secretFilters, bson.M{"before." + field: bson.M{"$exists": true}}, bson.M{"after." + field: bson.M{"$exists": true}})
+	}
+
+	for {
+		filter := bson.M{
+			"_id":        bson.M{"$gt": lastID},
+			"collection": collection,
+			"$or":        secretFilters,
+		}
+		if shopID != "" {
+			filter["shopid"] = shopID
+		}
+
+		docs := []auditmodels.AuditLogDoc{}
+		err := pst.Find(ctx, &auditmodels.AuditLogDoc{}, filter, &docs, options.Find().SetSort(bson.M{"_id": 1}).SetLimit(secretMigrationBatchSize))
+		if err != nil {
+			return count, err
+		}
+
+		for _, doc := range docs {
+			lastID = doc.ID
+
+			redactedChanges := auditmodels.RedactChanges(doc.Changes, fields)
+			redactedBefore := auditmodels.RedactSnapshot(doc.Before, fields)
+			redactedAfter := auditmodels.RedactSnapshot(doc.After, fields)
+
+			if !redactedChanges && !redactedBefore && !redactedAfter {
+				continue
+			}
+
+			count++
+			if dryRun {
+				continue
+			}
+
+			updateFields := bson.M{"changes": doc.Changes}
+			if doc.Before != nil {
+				updateFields["before"] = doc.Before
+			}
+			if doc.After != nil {
+				updateFields["after"] = doc.After
+			}
+
+			err = pst.UpdateOne(ctx, &auditmodels.AuditLogDoc{}, map[string]interface{}{"_id": doc.ID}, updateFields)
+			if err != nil {
+				return count, err
+			}
+		}
+
+		if len(docs) < secretMigrationBatchSize {
+			return count, nil
+		}
+	}
+}
+
+func secretValue(doc bson.M, field string) (string, bool) {
+	var value interface{} = doc
+	for _, key := range strings.Split(field, ".") {
+		switch nested := value.(type) {
+		case bson.M:
+			value = nested[key]
+		case bson.D:
+			value = nested.Map()[key]
+		default:
+			return "", false
+		}
+	}
+
+	str, ok := value.(string)
+	return str, ok
+}
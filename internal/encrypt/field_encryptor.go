@@ -0,0 +1,313 @@
+package encrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// encrypted value is "enc:v1:<key id>:<base64 of nonce and sealed value>",
+// key id is recorded in the value so it can be decrypted after the document is copied to other shop
+const encryptedPrefix = "enc:v1:"
+
+// RedactedValue replace secret in api response, secret is kept when it is sent back on update
+const RedactedValue = "********"
+
+const dataKeySize = 32
+
+// unwrapped data keys are kept in memory for the interval so mongo is not read for every document
+const dataKeyCacheExpire = 10 * time.Minute
+
+var ErrMasterKeyNotConfigured = errors.New("encryption master key is not configured")
+
+type IDataKeyRepository interface {
+	FindByKeyID(ctx context.Context, keyID string) (DataKeyDoc, error)
+	// Create return false when data key of the key id is created by other process at the same time
+	Create(ctx context.Context, doc DataKeyDoc) (bool, error)
+}
+
+type IFieldEncryptor interface {
+	// EncryptFields encrypt fields in place by data key of the shop, empty and encrypted values are kept
+	EncryptFields(ctx context.Context, shopID string, fields ...*string) error
+	// DecryptFields decrypt fields in place by data key which is recorded in the value, plain values are kept
+	DecryptFields(ctx context.Context, fields ...*string) error
+}
+
+// FieldEncryptor encrypt secret fields by envelope keys, each shop has data key which is wrapped by master key
+type FieldEncryptor struct {
+	masterKey []byte
+	keyRepo   IDataKeyRepository
+	dataKeys  *cache.Cache
+	timeNow   func() time.Time
+}
+
+func NewFieldEncryptor(masterKey []byte, keyRepo IDataKeyRepository, timeNow func() time.Time) (*FieldEncryptor, error) {
+	if len(masterKey) != dataKeySize {
+		return nil, fmt.Errorf("encryption master key must be %d bytes", dataKeySize)
+	}
+
+	return &FieldEncryptor{
+		masterKey: masterKey,
+		keyRepo:   keyRepo,
+		dataKeys:  cache.New(dataKeyCacheExpire, 2*dataKeyCacheExpire),
+		timeNow:   timeNow,
+	}, nil
+}
+
+// IsEncrypted return true when the value is encrypted by FieldEncryptor
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+func (e *FieldEncryptor) EncryptFields(ctx context.Context, shopID string, fields ...*string) error {
+	var dataKey []byte
+
+	for _, field := range fields {
+		if field == nil || *field == "" || IsEncrypted(*field) {
+			continue
+		}
+
+		if dataKey == nil {
+			if shopID == "" {
+				return errors.New("shop id is required to encrypt")
+			}
+
+			var err error
+			dataKey, err = e.dataKey(ctx, shopID, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		sealed, err := seal(dataKey, []byte(*field), []byte(shopID))
+		if err != nil {
+			return err
+		}
+
+		*field = encryptedPrefix + shopID + ":" + base64.RawStdEncoding.EncodeToString(sealed)
+	}
+
+	return nil
+}
+
+func (e *FieldEncryptor) DecryptFields(ctx context.Context, fields ...*string) error {
+	for _, field := range fields {
+		if field == nil || !IsEncrypted(*field) {
+			continue
+		}
+
+		keyID, payload, err := splitEncrypted(*field)
+		if err != nil {
+			return err
+		}
+
+		dataKey, err := e.dataKey(ctx, keyID, false)
+		if err != nil {
+			return err
+		}
+
+		sealed, err := base64.RawStdEncoding.DecodeString(payload)
+		if err != nil {
+			return fmt.Errorf("decode encrypted value: %w", err)
+		}
+
+		plain, err := open(dataKey, sealed, []byte(keyID))
+		if err != nil {
+			return err
+		}
+
+		*field = string(plain)
+	}
+
+	return nil
+}
+
+// dataKey return unwrapped data key of the key id, it is created when it is not exists and create is true
+func (e *FieldEncryptor) dataKey(ctx context.Context, keyID string, create bool) ([]byte, error) {
+	if dataKey, found := e.dataKeys.Get(keyID); found {
+		return dataKey.([]byte), nil
+	}
+
+	doc, err := e.keyRepo.FindByKeyID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.KeyID == "" {
+		if !create {
+			return nil, fmt.Errorf("data key %s not found", keyID)
+		}
+
+		doc, err = e.createDataKey(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(doc.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode data key %s: %w", keyID, err)
+	}
+
+	dataKey, err := open(e.masterKey, wrappedKey, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key %s: %w", keyID, err)
+	}
+
+	e.dataKeys.Set(keyID, dataKey, cache.DefaultExpiration)
+
+	return dataKey, nil
+}
+
+func (e *FieldEncryptor) createDataKey(ctx context.Context, keyID string) (DataKeyDoc, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return DataKeyDoc{}, err
+	}
+
+	wrappedKey, err := seal(e.masterKey, dataKey, []byte(keyID))
+	if err != nil {
+		return DataKeyDoc{}, err
+	}
+
+	doc := DataKeyDoc{
+		KeyID:      keyID,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		CreatedAt:  e.timeNow(),
+	}
+
+	created, err := e.keyRepo.Create(ctx, doc)
+	if err != nil {
+		return DataKeyDoc{}, err
+	}
+
+	if created {
+		return doc, nil
+	}
+
+	// data key is created by other process, use that key
+	return e.keyRepo.FindByKeyID(ctx, keyID)
+}
+
+func splitEncrypted(value string) (string, string, error) {
+	keyAndPayload := strings.TrimPrefix(value, encryptedPrefix)
+
+	sep := strings.LastIndex(keyAndPayload, ":")
+	if sep <= 0 {
+		return "", "", errors.New("encrypted value invalid")
+	}
+
+	return keyAndPayload[:sep], keyAndPayload[sep+1:], nil
+}
+
+func seal(key []byte, plain []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, additionalData), nil
+}
+
+func open(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value invalid")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// PlainFieldEncryptor keep fields as they are, it is used by data transfer which copy encrypted values without decrypting them
+type PlainFieldEncryptor struct{}
+
+func NewPlainFieldEncryptor() *PlainFieldEncryptor {
+	return &PlainFieldEncryptor{}
+}
+
+func (PlainFieldEncryptor) EncryptFields(ctx context.Context, shopID string, fields ...*string) error {
+	return nil
+}
+
+func (PlainFieldEncryptor) DecryptFields(ctx context.Context, fields ...*string) error {
+	for _, field := range fields {
+		if field != nil && IsEncrypted(*field) {
+			return ErrMasterKeyNotConfigured
+		}
+	}
+	return nil
+}
+
+// FailedFieldEncryptor return the error of encryptor which cannot be created, secrets are neither stored
+// in plain text nor decrypted, documents without secrets are not affected
+type FailedFieldEncryptor struct {
+	err error
+}
+
+func NewFailedFieldEncryptor(err error) *FailedFieldEncryptor {
+	return &FailedFieldEncryptor{err: err}
+}
+
+func (e FailedFieldEncryptor) EncryptFields(ctx context.Context, shopID string, fields ...*string) error {
+	for _, field := range fields {
+		if field != nil && *field != "" && !IsEncrypted(*field) {
+			return e.err
+		}
+	}
+	return nil
+}
+
+func (e FailedFieldEncryptor) DecryptFields(ctx context.Context, fields ...*string) error {
+	for _, field := range fields {
+		if field != nil && IsEncrypted(*field) {
+			return e.err
+		}
+	}
+	return nil
+}
+
+// RedactFields replace non empty secrets with RedactedValue
+func RedactFields(fields ...*string) {
+	for _, field := range fields {
+		if field != nil && *field != "" {
+			*field = RedactedValue
+		}
+	}
+}
+
+// RestoreRedactedFields set secret which is sent back as RedactedValue to the stored secret at the same index
+func RestoreRedactedFields(fields []*string, storedFields []*string) {
+	for i, field := range fields {
+		if i < len(storedFields) && field != nil && *field == RedactedValue {
+			*field = *storedFields[i]
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package encrypt_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/encrypt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dataKeyRepositoryMemory keep data keys in memory
+type dataKeyRepositoryMemory struct {
+	docs map[string]encrypt.DataKeyDoc
+}
+
+func (r *dataKeyRepositoryMemory) FindByKeyID(ctx context.Context, keyID string) (encrypt.DataKeyDoc, error) {
+	return r.docs[keyID], nil
+}
+
+func (r *dataKeyRepositoryMemory) Create(ctx context.Context, doc encrypt.DataKeyDoc) (bool, error) {
+	if _, ok := r.docs[doc.KeyID]; ok {
+		return false, nil
+	}
+	r.docs[doc.KeyID] = doc
+	return true, nil
+}
+
+func TestFieldEncryptor(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte(strings.Repeat("k", 32))
+	keyRepo := &dataKeyRepositoryMemory{docs: map[string]encrypt.DataKeyDoc{}}
+
+	encryptor, err := encrypt.NewFieldEncryptor(masterKey, keyRepo, time.Now)
+	assert.Nil(t, err)
+
+	apiKey, secret, empty := "api-key-01", "secret-01", ""
+	err = encryptor.EncryptFields(ctx, "shop01", &apiKey, &secret, &empty)
+	assert.Nil(t, err)
+
+	assert.True(t, encrypt.IsEncrypted(apiKey))
+	assert.NotContains(t, apiKey, "api-key-01")
+	assert.Equal(t, "", empty)
+	assert.Len(t, keyRepo.docs, 1)
+	assert.NotContains(t, keyRepo.docs["shop01"].WrappedKey, string(masterKey))
+
+	// encrypted value is not encrypted again
+	encryptedApiKey := apiKey
+	err = encryptor.EncryptFields(ctx, "shop02", &apiKey)
+	assert.Nil(t, err)
+	assert.Equal(t, encryptedApiKey, apiKey)
+
+	plain := "plain-01"
+	err = encryptor.DecryptFields(ctx, &apiKey, &secret, &plain)
+	assert.Nil(t, err)
+	assert.Equal(t, "api-key-01", apiKey)
+	assert.Equal(t, "secret-01", secret)
+	assert.Equal(t, "plain-01", plain)
+
+	// other process which load the data key from repository
+	otherEncryptor, _ := encrypt.NewFieldEncryptor(masterKey, keyRepo, time.Now)
+	copied := encryptedApiKey
+	err = otherEncryptor.DecryptFields(ctx, &copied)
+	assert.Nil(t, err)
+	assert.Equal(t, "api-key-01", copied)
+
+	wrongEncryptor, _ := encrypt.NewFieldEncryptor([]byte(strings.Repeat("x", 32)), keyRepo, time.Now)
+	copied = encryptedApiKey
+	err = wrongEncryptor.DecryptFields(ctx, &copied)
+	assert.NotNil(t, err)
+
+	err = encrypt.NewPlainFieldEncryptor().DecryptFields(ctx, &copied)
+	assert.Equal(t, encrypt.ErrMasterKeyNotConfigured, err)
+
+	_, err = encrypt.NewFieldEncryptor([]byte("short"), keyRepo, time.Now)
+	assert.NotNil(t, err)
+}
+
+func TestRedactFields(t *testing.T) {
+	apiKey, secret, empty := "api-key-01", "secret-01", ""
+	encrypt.RedactFields(&apiKey, &secret, &empty)
+
+	assert.Equal(t, encrypt.RedactedValue, apiKey)
+	assert.Equal(t, "", empty)
+
+	storedApiKey, storedSecret := "api-key-01", "secret-01"
+	secret = "secret-02"
+	encrypt.RestoreRedactedFields([]*string{&apiKey, &secret}, []*string{&storedApiKey, &storedSecret})
+
+	assert.Equal(t, "api-key-01", apiKey)
+	assert.Equal(t, "secret-02", secret)
+}
+
+func TestFailedFieldEncryptor(t *testing.T) {
+	ctx := context.Background()
+	failed := encrypt.NewFailedFieldEncryptor(encrypt.ErrMasterKeyNotConfigured)
+
+	empty := ""
+	err := failed.EncryptFields(ctx, "shop1", &empty)
+	assert.Nil(t, err)
+
+	secret := "secret-01"
+	err = failed.EncryptFields(ctx, "shop1", &secret)
+	assert.Equal(t, encrypt.ErrMasterKeyNotConfigured, err)
+	assert.Equal(t, "secret-01", secret)
+
+	err = failed.DecryptFields(ctx, &secret)
+	assert.Nil(t, err)
+
+	encrypted := "enc:v1:shop1:payload"
+	err = failed.DecryptFields(ctx, &encrypted)
+	assert.Equal(t, encrypt.ErrMasterKeyNotConfigured, err)
+}
@@ -74,7 +74,7 @@ func (h FileStatusHttp) CreateFileStatus(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateFileStatus(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateFileStatus(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -119,7 +119,7 @@ func (h FileStatusHttp) UpdateFileStatus(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateFileStatus(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateFileStatus(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -150,7 +150,7 @@ func (h FileStatusHttp) DeleteFileStatus(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteFileStatus(shopID, id, authUsername)
+	err := h.svc.DeleteFileStatus(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -181,7 +181,7 @@ func (h FileStatusHttp) DeleteFileStatusByMenu(ctx microservice.IContext) error
 
 	menu := ctx.Param("menu")
 
-	err := h.svc.DeleteFileStatusByMenu(shopID, authUsername, menu)
+	err := h.svc.DeleteFileStatusByMenu(ctx.Context(), shopID, authUsername, menu)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -219,7 +219,7 @@ func (h FileStatusHttp) DeleteFileStatusByGUIDs(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.DeleteFileStatusByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteFileStatusByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -249,7 +249,7 @@ func (h FileStatusHttp) InfoFileStatus(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get FileStatus %v", id)
-	doc, err := h.svc.InfoFileStatus(shopID, id)
+	doc, err := h.svc.InfoFileStatus(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -297,7 +297,7 @@ func (h FileStatusHttp) SearchFileStatusPage(ctx microservice.IContext) error {
 
 	filters["username"] = authUsername
 
-	docList, pagination, err := h.svc.SearchFileStatus(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchFileStatus(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -348,7 +348,7 @@ func (h FileStatusHttp) SearchFileStatusStep(ctx microservice.IContext) error {
 
 	filters["username"] = authUsername
 
-	docList, total, err := h.svc.SearchFileStatusStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchFileStatusStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
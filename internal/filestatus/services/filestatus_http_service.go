@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/filestatus/repositories"
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -15,14 +16,14 @@ import (
 )
 
 type IFileStatusHttpService interface {
-	CreateFileStatus(shopID string, authUsername string, doc models.FileStatus) (string, error)
-	UpdateFileStatus(shopID string, guid string, authUsername string, doc models.FileStatus) error
-	DeleteFileStatus(shopID string, guid string, authUsername string) error
-	DeleteFileStatusByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	DeleteFileStatusByMenu(shopID string, authUsername string, menu string) error
-	InfoFileStatus(shopID string, guid string) (models.FileStatusInfo, error)
-	SearchFileStatus(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.FileStatusInfo, mongopagination.PaginationData, error)
-	SearchFileStatusStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.FileStatusInfo, int, error)
+	CreateFileStatus(ctx context.Context, shopID string, authUsername string, doc models.FileStatus) (string, error)
+	UpdateFileStatus(ctx context.Context, shopID string, guid string, authUsername string, doc models.FileStatus) error
+	DeleteFileStatus(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteFileStatusByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	DeleteFileStatusByMenu(ctx context.Context, shopID string, authUsername string, menu string) error
+	InfoFileStatus(ctx context.Context, shopID string, guid string) (models.FileStatusInfo, error)
+	SearchFileStatus(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.FileStatusInfo, mongopagination.PaginationData, error)
+	SearchFileStatusStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.FileStatusInfo, int, error)
 }
 
 type FileStatusHttpService struct {
@@ -47,13 +48,13 @@ func NewFileStatusHttpService(
 	return insSvc
 }
 
-func (svc FileStatusHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc FileStatusHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc FileStatusHttpService) CreateFileStatus(shopID string, authUsername string, doc models.FileStatus) (string, error) {
+func (svc FileStatusHttpService) CreateFileStatus(ctx context.Context, shopID string, authUsername string, doc models.FileStatus) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"menu": doc.Menu, "username": authUsername, "jobid": doc.JobID})
@@ -86,9 +87,9 @@ func (svc FileStatusHttpService) CreateFileStatus(shopID string, authUsername st
 	return newGuidFixed, nil
 }
 
-func (svc FileStatusHttpService) UpdateFileStatus(shopID string, guid string, authUsername string, doc models.FileStatus) error {
+func (svc FileStatusHttpService) UpdateFileStatus(ctx context.Context, shopID string, guid string, authUsername string, doc models.FileStatus) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"guidfixed": guid})
@@ -118,9 +119,9 @@ func (svc FileStatusHttpService) UpdateFileStatus(shopID string, guid string, au
 	return nil
 }
 
-func (svc FileStatusHttpService) DeleteFileStatusByMenu(shopID string, authUsername string, menu string) error {
+func (svc FileStatusHttpService) DeleteFileStatusByMenu(ctx context.Context, shopID string, authUsername string, menu string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.Delete(ctx, shopID, authUsername, bson.M{"menu": menu})
@@ -131,9 +132,9 @@ func (svc FileStatusHttpService) DeleteFileStatusByMenu(shopID string, authUsern
 	return nil
 }
 
-func (svc FileStatusHttpService) DeleteFileStatus(shopID string, guid string, authUsername string) error {
+func (svc FileStatusHttpService) DeleteFileStatus(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -154,9 +155,9 @@ func (svc FileStatusHttpService) DeleteFileStatus(shopID string, guid string, au
 	return nil
 }
 
-func (svc FileStatusHttpService) DeleteFileStatusByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc FileStatusHttpService) DeleteFileStatusByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -171,9 +172,9 @@ func (svc FileStatusHttpService) DeleteFileStatusByGUIDs(shopID string, authUser
 	return nil
 }
 
-func (svc FileStatusHttpService) InfoFileStatus(shopID string, guid string) (models.FileStatusInfo, error) {
+func (svc FileStatusHttpService) InfoFileStatus(ctx context.Context, shopID string, guid string) (models.FileStatusInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -189,9 +190,9 @@ func (svc FileStatusHttpService) InfoFileStatus(shopID string, guid string) (mod
 	return findDoc.FileStatusInfo, nil
 }
 
-func (svc FileStatusHttpService) SearchFileStatus(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.FileStatusInfo, mongopagination.PaginationData, error) {
+func (svc FileStatusHttpService) SearchFileStatus(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.FileStatusInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{}
@@ -205,9 +206,9 @@ func (svc FileStatusHttpService) SearchFileStatus(shopID string, filters map[str
 	return docList, pagination, nil
 }
 
-func (svc FileStatusHttpService) SearchFileStatusStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.FileStatusInfo, int, error) {
+func (svc FileStatusHttpService) SearchFileStatusStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.FileStatusInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -93,7 +93,7 @@ func (svc ImagesHttp) GetProductImage(ctx microservice.IContext) error {
 		index = 1
 	}
 
-	fileName, buffer, err := svc.service.GetImageByProductCode(shopId, itemguid, index)
+	fileName, buffer, err := svc.service.GetImageByProductCode(ctx.Context(), shopId, itemguid, index)
 
 	if err != nil {
 		ctx.Response(http.StatusBadRequest, &common.ApiResponse{
@@ -177,7 +177,7 @@ func (svc ImagesHttp) UploadImageToProduct(ctx microservice.IContext) error {
 	userInfo := ctx.UserInfo()
 	shopID := userInfo.ShopID
 	// find
-	err := svc.service.UploadImageToProduct(shopID, fileHeader)
+	err := svc.service.UploadImageToProduct(ctx.Context(), shopID, fileHeader)
 
 	if err != nil {
 		ctx.Response(http.StatusBadRequest, &common.ApiResponse{
@@ -225,7 +225,7 @@ func (svc ImagesHttp) GetSlipImage(ctx microservice.IContext) error {
 		return nil
 	}
 
-	fileName, buffer, err := svc.service.GetSlipImage(shopId, posID, docDateFilter, docNo)
+	fileName, buffer, err := svc.service.GetSlipImage(ctx.Context(), shopId, posID, docDateFilter, docNo)
 
 	if err != nil {
 		ctx.Response(http.StatusNotFound, "")
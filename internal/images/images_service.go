@@ -20,9 +20,9 @@ import (
 
 type IImagesService interface {
 	UploadImage(shopId string, fh *multipart.FileHeader) (*models.Image, error)
-	UploadImageToProduct(shopID string, fh *multipart.FileHeader) error
-	GetImageByProductCode(shopid string, itemguid string, index int) (string, *bytes.Buffer, error)
-	GetSlipImage(shopid string, posID string, docDate time.Time, docNo string) (string, *bytes.Buffer, error)
+	UploadImageToProduct(ctx context.Context, shopID string, fh *multipart.FileHeader) error
+	GetImageByProductCode(ctx context.Context, shopid string, itemguid string, index int) (string, *bytes.Buffer, error)
+	GetSlipImage(ctx context.Context, shopid string, posID string, docDate time.Time, docNo string) (string, *bytes.Buffer, error)
 }
 
 type ImagesService struct {
@@ -50,8 +50,8 @@ func NewImageService(persisterImage *microservice.PersisterImage,
 	}
 }
 
-func (svc ImagesService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ImagesService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
 func (svc ImagesService) UploadImage(shopId string, fh *multipart.FileHeader) (*models.Image, error) {
@@ -74,9 +74,9 @@ func (svc ImagesService) UploadImage(shopId string, fh *multipart.FileHeader) (*
 	return image, nil
 }
 
-func (svc ImagesService) UploadImageToProduct(shopID string, fh *multipart.FileHeader) error {
+func (svc ImagesService) UploadImageToProduct(ctx context.Context, shopID string, fh *multipart.FileHeader) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	fileUploadMetadataSlice := strings.Split(fh.Filename, ".")
@@ -122,13 +122,13 @@ func (svc ImagesService) UploadImageToProduct(shopID string, fh *multipart.FileH
 	})
 
 	// save and return
-	err = svc.invRepo.Update(context.Background(), shopID, dataDoc.GuidFixed, dataDoc)
+	err = svc.invRepo.Update(ctx, shopID, dataDoc.GuidFixed, dataDoc)
 	return err
 }
 
-func (svc ImagesService) GetImageByProductCode(shopid string, itemguid string, index int) (string, *bytes.Buffer, error) {
+func (svc ImagesService) GetImageByProductCode(ctx context.Context, shopid string, itemguid string, index int) (string, *bytes.Buffer, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.invRepo.FindByGuid(ctx, shopid, itemguid)
@@ -157,9 +157,9 @@ func (svc ImagesService) GetImageByProductCode(shopid string, itemguid string, i
 	return imageUri, buffer, nil
 }
 
-func (svc ImagesService) GetSlipImage(shopid string, posID string, docDate time.Time, docNo string) (string, *bytes.Buffer, error) {
+func (svc ImagesService) GetSlipImage(ctx context.Context, shopid string, posID string, docDate time.Time, docNo string) (string, *bytes.Buffer, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.slipimageRepo.FindOne(ctx, shopid, bson.M{"posid": posID, "docdate": docDate, "docno": docNo})
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/internal/job/services"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	"sync"
+	"time"
+)
+
+var (
+	runnersMutex sync.Mutex
+	runners      = map[*microservice.Microservice]*services.JobRunner{}
+)
+
+// InitJobRunner return job runner shared by every module of the microservice,
+// its worker pool is closed by ms.Cleanup after queued jobs are done
+func InitJobRunner(ms *microservice.Microservice, cfg config.IConfig) *services.JobRunner {
+	runnersMutex.Lock()
+	defer runnersMutex.Unlock()
+
+	runner, ok := runners[ms]
+	if !ok {
+		jobCfg := cfg.JobConfig()
+		repo := repositories.NewJobRepository(ms.MongoPersister(cfg.MongoPersisterConfig()))
+		pool := ms.NewKeyedWorkerPool(jobCfg.Workers(), jobCfg.QueueSize())
+
+		runner = services.NewJobRunner(repo, pool, jobCfg, ms.Logger, utils.NewGUID, time.Now)
+		runners[ms] = runner
+	}
+
+	return runner
+}
+
+// ResponseStartError send error of starting a job, full job queue is 503 so the client can start the job again later
+func ResponseStartError(ctx microservice.IContext, err error) {
+	if errors.Is(err, services.ErrJobQueueFull) {
+		ctx.ResponseError(http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	ctx.ResponseError(http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,140 @@
+package job
+
+import (
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/internal/job/services"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+type IJobHttp interface{}
+
+type JobHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.IJobService
+}
+
+func NewJobHttp(ms *microservice.Microservice, cfg config.IConfig) JobHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	repo := repositories.NewJobRepository(pst)
+	svc := services.NewJobService(repo, InitJobRunner(ms, cfg), cfg.JobConfig(), time.Now)
+
+	return JobHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h JobHttp) RegisterHttp() {
+	h.ms.GET("/job", h.SearchJobPage)
+	h.ms.GET("/job/:id", h.InfoJob)
+	h.ms.POST("/job/:id/cancel", h.CancelJob)
+}
+
+// List Job godoc
+// @Description List background jobs of the shop, the latest job first
+// @Tags		Job
+// @Param		kind	query	string		false  "job kind"
+// @Param		status	query	string		false  "queued, running, succeeded, failed, cancelled"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /job [get]
+func (h JobHttp) SearchJobPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"kind", "status"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	docList, pagination, err := h.svc.SearchJob(ctx.Context(), shopID, filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Get Job godoc
+// @Description Get status, progress, log lines and result of the job
+// @Tags		Job
+// @Param		id  path      string  true  "Job ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /job/{id} [get]
+func (h JobHttp) InfoJob(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoJob(ctx.Context(), shopID, id)
+	if err == services.ErrJobNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Cancel Job godoc
+// @Description Cancel queued or running job
+// @Tags		Job
+// @Param		id  path      string  true  "Job ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /job/{id}/cancel [post]
+func (h JobHttp) CancelJob(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	err := h.svc.CancelJob(ctx.Context(), shopID, id)
+	if err == services.ErrJobNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
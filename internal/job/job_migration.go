@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index to find jobs of the shop and TTL index which remove finished jobs after retention
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.JobDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "jobid", Value: 1}},
+			Options: options.Index().SetName("job_jobid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("job_shopid_createdat"),
+		},
+		{
+			Keys:    bson.D{{Key: "expireat", Value: 1}},
+			Options: options.Index().SetName("job_expireat").SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const jobCollectionName = "jobs"
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// JobMaxLogs is number of the latest log lines kept in the job
+const JobMaxLogs = 200
+
+type JobLog struct {
+	Time    time.Time `json:"time" bson:"time"`
+	Message string    `json:"message" bson:"message"`
+}
+
+type Job struct {
+	JobID           string      `json:"jobid" bson:"jobid"`
+	Kind            string      `json:"kind" bson:"kind"`
+	Status          string      `json:"status" bson:"status"`
+	Progress        int         `json:"progress" bson:"progress"`
+	Message         string      `json:"message" bson:"message"`
+	Logs            []JobLog    `json:"logs,omitempty" bson:"logs"`
+	Result          interface{} `json:"result,omitempty" bson:"result,omitempty"`
+	Error           string      `json:"error,omitempty" bson:"error,omitempty"`
+	CancelRequested bool        `json:"cancelrequested" bson:"cancelrequested"`
+	CreatedBy       string      `json:"createdby" bson:"createdby"`
+	CreatedAt       time.Time   `json:"createdat" bson:"createdat"`
+	StartedAt       *time.Time  `json:"startedat,omitempty" bson:"startedat,omitempty"`
+	HeartbeatAt     *time.Time  `json:"heartbeatat,omitempty" bson:"heartbeatat,omitempty"`
+	FinishedAt      *time.Time  `json:"finishedat,omitempty" bson:"finishedat,omitempty"`
+	// ExpireAt is set when the job is finished, mongo TTL index remove the job after it
+	ExpireAt *time.Time `json:"expireat,omitempty" bson:"expireat,omitempty"`
+}
+
+// Finished return true when the job is not queued or running
+func (job Job) Finished() bool {
+	return job.Status != JobQueued && job.Status != JobRunning
+}
+
+type JobInfo struct {
+	models.ShopIdentity `bson:"inline"`
+	Job                 `bson:"inline"`
+}
+
+func (JobInfo) CollectionName() string {
+	return jobCollectionName
+}
+
+type JobDoc struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	JobInfo `bson:"inline"`
+}
+
+func (JobDoc) CollectionName() string {
+	return jobCollectionName
+}
@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/job/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IJobRepository interface {
+	Create(ctx context.Context, doc models.JobDoc) error
+	FindByJobID(ctx context.Context, shopID string, jobID string) (models.JobDoc, error)
+	FindPage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.JobInfo, mongopagination.PaginationData, error)
+	MarkRunning(ctx context.Context, jobID string, startedAt time.Time) (bool, error)
+	Heartbeat(ctx context.Context, jobID string, heartbeatAt time.Time) (bool, error)
+	UpdateProgress(ctx context.Context, jobID string, progress int, message string) error
+	AppendLog(ctx context.Context, jobID string, log models.JobLog) error
+	Finish(ctx context.Context, jobID string, status string, result interface{}, errorMessage string, finishedAt time.Time, expireAt time.Time) error
+	CancelQueued(ctx context.Context, shopID string, jobID string, finishedAt time.Time, expireAt time.Time) (bool, error)
+	RequestCancel(ctx context.Context, shopID string, jobID string) (bool, error)
+	FailStale(ctx context.Context, shopID string, heartbeatBefore time.Time, createdBefore time.Time, finishedAt time.Time, expireAt time.Time) error
+}
+
+// JobRepository keep jobs of shops, updates of the runner address the job by its unique job id
+// and are not scoped to the shop
+type JobRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewJobRepository(pst microservice.IPersisterMongo) *JobRepository {
+	return &JobRepository{
+		pst: pst,
+	}
+}
+
+func (repo JobRepository) Create(ctx context.Context, doc models.JobDoc) error {
+	_, err := repo.pst.Create(ctx, &models.JobDoc{}, doc)
+	return err
+}
+
+func (repo JobRepository) FindByJobID(ctx context.Context, shopID string, jobID string) (models.JobDoc, error) {
+	doc := models.JobDoc{}
+	err := repo.pst.FindOne(ctx, &models.JobDoc{}, bson.M{"shopid": shopID, "jobid": jobID}, &doc)
+	if err != nil {
+		return models.JobDoc{}, err
+	}
+
+	return doc, nil
+}
+
+// FindPage return jobs of the shop without log lines, the latest job first when sort is not requested
+func (repo JobRepository) FindPage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.JobInfo, mongopagination.PaginationData, error) {
+	filterQuery := bson.M{"shopid": shopID}
+	for key, value := range filters {
+		filterQuery[key] = value
+	}
+
+	if len(pageable.Sorts) == 0 {
+		pageable.Sorts = []micromodels.KeyInt{{Key: "createdat", Value: -1}}
+	}
+
+	docList := []models.JobInfo{}
+	pagination, err := repo.pst.FindSelectPage(ctx, &models.JobInfo{}, bson.M{"logs": 0}, filterQuery, pageable, &docList)
+	if err != nil {
+		return []models.JobInfo{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
+
+// MarkRunning move the queued job to running, false is returned when the job is cancelled while it is queued
+func (repo JobRepository) MarkRunning(ctx context.Context, jobID string, startedAt time.Time) (bool, error) {
+	return repo.updateOne(microservice.WithSystemAdmin(ctx), bson.M{"jobid": jobID, "status": models.JobQueued}, bson.M{
+		"$set": bson.M{
+			"status":      models.JobRunning,
+			"startedat":   startedAt,
+			"heartbeatat": startedAt,
+		},
+	})
+}
+
+// Heartbeat touch the running job and return true when cancellation of the job is requested
+func (repo JobRepository) Heartbeat(ctx context.Context, jobID string, heartbeatAt time.Time) (bool, error) {
+	doc := models.JobDoc{}
+	err := repo.pst.FindOneAndUpdate(
+		microservice.WithSystemAdmin(ctx),
+		&models.JobDoc{},
+		bson.M{"jobid": jobID, "status": models.JobRunning},
+		bson.M{"$set": bson.M{"heartbeatat": heartbeatAt}},
+		&doc,
+		options.FindOneAndUpdate().SetProjection(bson.M{"cancelrequested": 1}),
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return doc.CancelRequested, nil
+}
+
+func (repo JobRepository) UpdateProgress(ctx context.Context, jobID string, progress int, message string) error {
+	return repo.pst.Update(microservice.WithSystemAdmin(ctx), &models.JobDoc{}, bson.M{"jobid": jobID}, bson.M{
+		"$set": bson.M{
+			"progress": progress,
+			"message":  message,
+		},
+	})
+}
+
+// AppendLog add log line to the job and keep only the latest JobMaxLogs lines
+func (repo JobRepository) AppendLog(ctx context.Context, jobID string, log models.JobLog) error {
+	return repo.pst.Update(microservice.WithSystemAdmin(ctx), &models.JobDoc{}, bson.M{"jobid": jobID}, bson.M{
+		"$push": bson.M{
+			"logs": bson.M{
+				"$each":  []models.JobLog{log},
+				"$slice": -models.JobMaxLogs,
+			},
+		},
+	})
+}
+
+func (repo JobRepository) Finish(ctx context.Context, jobID string, status string, result interface{}, errorMessage string, finishedAt time.Time, expireAt time.Time) error {
+	set := bson.M{
+		"status":     status,
+		"error":      errorMessage,
+		"finishedat": finishedAt,
+		"expireat":   expireAt,
+	}
+
+	if result != nil {
+		set["result"] = result
+	}
+
+	if status == models.JobSucceeded {
+		set["progress"] = 100
+	}
+
+	return repo.pst.Update(microservice.WithSystemAdmin(ctx), &models.JobDoc{}, bson.M{"jobid": jobID}, bson.M{"$set": set})
+}
+
+// CancelQueued cancel the job which is not started yet
+func (repo JobRepository) CancelQueued(ctx context.Context, shopID string, jobID string, finishedAt time.Time, expireAt time.Time) (bool, error) {
+	return repo.updateOne(microservice.WithCollectionShopID(ctx, shopID), bson.M{"shopid": shopID, "jobid": jobID, "status": models.JobQueued}, bson.M{
+		"$set": bson.M{
+			"status":          models.JobCancelled,
+			"cancelrequested": true,
+			"finishedat":      finishedAt,
+			"expireat":        expireAt,
+		},
+	})
+}
+
+// RequestCancel flag the running job, the runner cancel the job context at the next heartbeat
+func (repo JobRepository) RequestCancel(ctx context.Context, shopID string, jobID string) (bool, error) {
+	return repo.updateOne(microservice.WithCollectionShopID(ctx, shopID), bson.M{"shopid": shopID, "jobid": jobID, "status": models.JobRunning}, bson.M{
+		"$set": bson.M{"cancelrequested": true},
+	})
+}
+
+// FailStale fail running jobs of the shop which are not touched since heartbeatBefore and queued jobs which are
+// created before createdBefore, their runner is stopped
+func (repo JobRepository) FailStale(ctx context.Context, shopID string, heartbeatBefore time.Time, createdBefore time.Time, finishedAt time.Time, expireAt time.Time) error {
+	return repo.pst.Update(
+		ctx,
+		&models.JobDoc{},
+		bson.M{
+			"shopid": shopID,
+			"$or": []bson.M{
+				{"status": models.JobRunning, "heartbeatat": bson.M{"$lt": heartbeatBefore}},
+				{"status": models.JobQueued, "createdat": bson.M{"$lt": createdBefore}},
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status":     models.JobFailed,
+				"error":      "job is lost, the worker is stopped",
+				"finishedat": finishedAt,
+				"expireat":   expireAt,
+			},
+		},
+	)
+}
+
+func (repo JobRepository) updateOne(ctx context.Context, filter interface{}, update interface{}) (bool, error) {
+	collection, err := repo.pst.Exec(ctx, &models.JobDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
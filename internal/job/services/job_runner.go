@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/models"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"sync"
+	"time"
+)
+
+// JobHandler do the work of the job, it should stop when job.Context() is done,
+// the returned result is kept in the job record
+type JobHandler func(job IJobContext) (interface{}, error)
+
+// IJobContext is passed to JobHandler to report progress and check cancellation
+type IJobContext interface {
+	JobID() string
+	ShopID() string
+	// Context is cancelled when cancellation of the job is requested
+	Context() context.Context
+	// Progress set percent of the work which is done, 0 - 100
+	Progress(percent int, message string)
+	Logf(format string, args ...interface{})
+}
+
+type IJobRunner interface {
+	// Start queue the job and return job id, jobs of the same shop run one at a time in started order.
+	// ErrJobQueueFull is returned without waiting when the queue of the worker is full
+	Start(shopID string, username string, kind string, handler JobHandler) (string, error)
+}
+
+// JobRunner run jobs on worker pool of this replica, job record is kept in mongo
+// so it can be polled and cancelled through any replica
+type JobRunner struct {
+	repo    repositories.IJobRepository
+	pool    *microservice.KeyedWorkerPool
+	cfg     config.IJobConfig
+	logger  logger.ILogger
+	newGUID func() string
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+func NewJobRunner(repo repositories.IJobRepository, pool *microservice.KeyedWorkerPool, cfg config.IJobConfig, logger logger.ILogger, newGUID func() string, now func() time.Time) *JobRunner {
+	return &JobRunner{
+		repo:    repo,
+		pool:    pool,
+		cfg:     cfg,
+		logger:  logger,
+		newGUID: newGUID,
+		now:     now,
+		running: map[string]context.CancelFunc{},
+	}
+}
+
+func (r *JobRunner) Start(shopID string, username string, kind string, handler JobHandler) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	jobID := r.newGUID()
+
+	doc := models.JobDoc{}
+	doc.ShopID = shopID
+	doc.JobID = jobID
+	doc.Kind = kind
+	doc.Status = models.JobQueued
+	doc.Logs = []models.JobLog{}
+	doc.CreatedBy = username
+	doc.CreatedAt = r.now()
+
+	err := r.repo.Create(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.pool.TrySubmit(shopID, func() {
+		r.run(jobID, shopID, handler)
+	})
+
+	if errors.Is(err, microservice.ErrWorkerPoolFull) {
+		err = ErrJobQueueFull
+	}
+
+	if err != nil {
+		r.finish(jobID, models.JobFailed, nil, err.Error())
+		return "", err
+	}
+
+	return jobID, nil
+}
+
+func (r *JobRunner) run(jobID string, shopID string, handler JobHandler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updateCtx, updateCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	started, err := r.repo.MarkRunning(updateCtx, jobID, r.now())
+	updateCancel()
+
+	if err != nil {
+		r.logger.Errorf("Job %s cannot be started: %v", jobID, err)
+		r.finish(jobID, models.JobFailed, nil, err.Error())
+		return
+	}
+
+	if !started {
+		// cancelled while it is queued
+		return
+	}
+
+	r.mutex.Lock()
+	r.running[jobID] = cancel
+	r.mutex.Unlock()
+
+	defer func() {
+		r.mutex.Lock()
+		delete(r.running, jobID)
+		r.mutex.Unlock()
+	}()
+
+	heartbeatDone := make(chan struct{})
+	defer close(heartbeatDone)
+	go r.heartbeat(jobID, cancel, heartbeatDone)
+
+	result, err := r.execute(&jobContext{
+		runner: r,
+		jobID:  jobID,
+		shopID: shopID,
+		ctx:    ctx,
+	}, handler)
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		r.finish(jobID, models.JobCancelled, result, err.Error())
+	case err != nil:
+		r.finish(jobID, models.JobFailed, result, err.Error())
+	default:
+		r.finish(jobID, models.JobSucceeded, result, "")
+	}
+}
+
+// execute call the handler, panic of the handler fail the job instead of the worker
+func (r *JobRunner) execute(job *jobContext, handler JobHandler) (result interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.logger.Errorf("Job %s panic: %v", job.jobID, recovered)
+			err = fmt.Errorf("job panic: %v", recovered)
+		}
+	}()
+
+	return handler(job)
+}
+
+// heartbeat touch the job until done and cancel it when cancellation is requested through other replica
+func (r *JobRunner) heartbeat(jobID string, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(r.cfg.HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 15*time.Second)
+			cancelRequested, err := r.repo.Heartbeat(ctx, jobID, r.now())
+			ctxCancel()
+
+			if err != nil {
+				r.logger.Warnf("Job %s heartbeat failed: %v", jobID, err)
+				continue
+			}
+
+			if cancelRequested {
+				cancel()
+			}
+		}
+	}
+}
+
+// cancel stop the job immediately when it is running on this replica
+func (r *JobRunner) cancel(jobID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if cancel, ok := r.running[jobID]; ok {
+		cancel()
+	}
+}
+
+func (r *JobRunner) finish(jobID string, status string, result interface{}, errorMessage string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	finishedAt := r.now()
+	err := r.repo.Finish(ctx, jobID, status, result, errorMessage, finishedAt, finishedAt.Add(r.cfg.Retention()))
+	if err != nil {
+		r.logger.Errorf("Job %s cannot be finished as %s: %v", jobID, status, err)
+	}
+}
+
+type jobContext struct {
+	runner *JobRunner
+	jobID  string
+	shopID string
+	ctx    context.Context
+}
+
+func (job *jobContext) JobID() string {
+	return job.jobID
+}
+
+func (job *jobContext) ShopID() string {
+	return job.shopID
+}
+
+func (job *jobContext) Context() context.Context {
+	return job.ctx
+}
+
+func (job *jobContext) Progress(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	}
+
+	if percent > 100 {
+		percent = 100
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := job.runner.repo.UpdateProgress(ctx, job.jobID, percent, message)
+	if err != nil {
+		job.runner.logger.Warnf("Job %s cannot update progress: %v", job.jobID, err)
+	}
+}
+
+func (job *jobContext) Logf(format string, args ...interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := job.runner.repo.AppendLog(ctx, job.jobID, models.JobLog{
+		Time:    job.runner.now(),
+		Message: fmt.Sprintf(format, args...),
+	})
+	if err != nil {
+		job.runner.logger.Warnf("Job %s cannot append log: %v", job.jobID, err)
+	}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/models"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestJobRunner(repo *JobRepositoryMock, pool *microservice.KeyedWorkerPool) *JobRunner {
+	seq := 0
+	mutex := sync.Mutex{}
+	newGUID := func() string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		seq++
+		return fmt.Sprintf("JOB%02d", seq)
+	}
+
+	return NewJobRunner(repo, pool, config.NewJobConfig(), logger.NewAppLogger(config.NewLoggerConfig()), newGUID, time.Now)
+}
+
+// onFinish send job id to the channel when the job is finished
+func onFinish(finished chan string) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		finished <- args.String(0)
+	}
+}
+
+func waitFinished(t *testing.T, finished chan string, jobID string) {
+	select {
+	case finishedID := <-finished:
+		assert.Equal(t, jobID, finishedID)
+	case <-time.After(time.Second):
+		t.Fatalf("job %s is not finished", jobID)
+	}
+}
+
+func TestJobRunnerSucceeded(t *testing.T) {
+	finished := make(chan string, 1)
+	repo := new(JobRepositoryMock)
+	repo.On("Create", mock.MatchedBy(func(doc models.JobDoc) bool {
+		return doc.JobID == "JOB01" && doc.ShopID == "SHOP01" && doc.CreatedBy == "user01" && doc.Status == models.JobQueued
+	})).Return(nil)
+	repo.On("MarkRunning", "JOB01").Return(true, nil)
+	repo.On("Heartbeat", "JOB01").Return(false, nil).Maybe()
+	repo.On("UpdateProgress", "JOB01", 50, "half").Return(nil)
+	repo.On("AppendLog", "JOB01", mock.MatchedBy(func(log models.JobLog) bool {
+		return log.Message == "saved 10 items"
+	})).Return(nil)
+	repo.On("Finish", "JOB01", models.JobSucceeded, map[string]string{"docno": "SB001"}, "").Return(nil).Run(onFinish(finished))
+
+	pool := microservice.NewKeyedWorkerPool(2, 10)
+	defer pool.Close()
+
+	jobID, err := newTestJobRunner(repo, pool).Start("SHOP01", "user01", "stockbalanceimport", func(job IJobContext) (interface{}, error) {
+		job.Progress(50, "half")
+		job.Logf("saved %d items", 10)
+		return map[string]string{"docno": "SB001"}, nil
+	})
+	assert.Nil(t, err)
+
+	waitFinished(t, finished, jobID)
+	repo.AssertExpectations(t)
+}
+
+func TestJobRunnerFailed(t *testing.T) {
+	finished := make(chan string, 2)
+	repo := new(JobRepositoryMock)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("MarkRunning", mock.Anything).Return(true, nil)
+	repo.On("Heartbeat", mock.Anything).Return(false, nil).Maybe()
+	repo.On("Finish", "JOB01", models.JobFailed, nil, "items barcode exist").Return(nil).Run(onFinish(finished))
+	repo.On("Finish", "JOB02", models.JobFailed, nil, "job panic: nil map").Return(nil).Run(onFinish(finished))
+
+	pool := microservice.NewKeyedWorkerPool(2, 10)
+	defer pool.Close()
+	runner := newTestJobRunner(repo, pool)
+
+	failedID, _ := runner.Start("SHOP01", "user01", "productimport", func(job IJobContext) (interface{}, error) {
+		return nil, errors.New("items barcode exist")
+	})
+	panicID, _ := runner.Start("SHOP01", "user01", "productimport", func(job IJobContext) (interface{}, error) {
+		panic("nil map")
+	})
+
+	waitFinished(t, finished, failedID)
+	waitFinished(t, finished, panicID)
+	repo.AssertExpectations(t)
+}
+
+func TestJobServiceCancel(t *testing.T) {
+	finished := make(chan string, 1)
+	repo := new(JobRepositoryMock)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("MarkRunning", "JOB01").Return(true, nil)
+	repo.On("Heartbeat", "JOB01").Return(false, nil).Maybe()
+	repo.On("Finish", "JOB01", models.JobCancelled, nil, context.Canceled.Error()).Return(nil).Run(onFinish(finished))
+
+	// job of other shop is not found
+	repo.On("CancelQueued", "SHOP02", "JOB01").Return(false, nil)
+	repo.On("RequestCancel", "SHOP02", "JOB01").Return(false, nil)
+	repo.On("FindByJobID", "SHOP02", "JOB01").Return(models.JobDoc{}, nil)
+
+	// queued job is cancelled in mongo and it is not started
+	repo.On("CancelQueued", "SHOP01", "JOB02").Return(true, nil)
+	repo.On("MarkRunning", "JOB02").Return(false, nil)
+
+	// running job is cancelled through its context
+	repo.On("CancelQueued", "SHOP01", "JOB01").Return(false, nil)
+	repo.On("RequestCancel", "SHOP01", "JOB01").Return(true, nil).Once()
+	repo.On("RequestCancel", "SHOP01", "JOB01").Return(false, nil)
+	cancelled := models.JobDoc{ID: primitive.NewObjectID()}
+	cancelled.Status = models.JobCancelled
+	repo.On("FindByJobID", "SHOP01", "JOB01").Return(cancelled, nil)
+
+	pool := microservice.NewKeyedWorkerPool(2, 10)
+	defer pool.Close()
+	runner := newTestJobRunner(repo, pool)
+	svc := NewJobService(repo, runner, config.NewJobConfig(), time.Now)
+
+	started := make(chan struct{})
+	runningID, _ := runner.Start("SHOP01", "user01", "recalcstock", func(job IJobContext) (interface{}, error) {
+		close(started)
+		<-job.Context().Done()
+		return nil, job.Context().Err()
+	})
+
+	// jobs of the same shop run in order so this one wait in the queue
+	queuedRun := false
+	queuedID, _ := runner.Start("SHOP01", "user01", "resyncdebtor", func(job IJobContext) (interface{}, error) {
+		queuedRun = true
+		return nil, nil
+	})
+
+	<-started
+	assert.Equal(t, ErrJobNotFound, svc.CancelJob(context.Background(), "SHOP02", runningID))
+	assert.Nil(t, svc.CancelJob(context.Background(), "SHOP01", queuedID))
+	assert.Nil(t, svc.CancelJob(context.Background(), "SHOP01", runningID))
+
+	waitFinished(t, finished, runningID)
+	pool.Close()
+
+	assert.False(t, queuedRun)
+	assert.Equal(t, ErrJobFinished, svc.CancelJob(context.Background(), "SHOP01", runningID))
+	repo.AssertExpectations(t)
+}
+
+func TestJobRunnerCancelByHeartbeat(t *testing.T) {
+	t.Setenv("JOB_HEARTBEAT_INTERVAL_MS", "10")
+
+	finished := make(chan string, 1)
+	repo := new(JobRepositoryMock)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("MarkRunning", "JOB01").Return(true, nil)
+
+	// cancellation requested through other replica is seen at the next heartbeat
+	repo.On("Heartbeat", "JOB01").Return(true, nil)
+	repo.On("Finish", "JOB01", models.JobCancelled, nil, context.Canceled.Error()).Return(nil).Run(onFinish(finished))
+
+	pool := microservice.NewKeyedWorkerPool(2, 10)
+	defer pool.Close()
+
+	jobID, _ := newTestJobRunner(repo, pool).Start("SHOP01", "user01", "recalcstock", func(job IJobContext) (interface{}, error) {
+		<-job.Context().Done()
+		return nil, job.Context().Err()
+	})
+
+	waitFinished(t, finished, jobID)
+}
+
+func TestJobRunnerQueueFull(t *testing.T) {
+	repo := new(JobRepositoryMock)
+	repo.On("Create", mock.Anything).Return(nil)
+	repo.On("MarkRunning", mock.Anything).Return(true, nil)
+	repo.On("Heartbeat", mock.Anything).Return(false, nil).Maybe()
+	repo.On("Finish", "JOB03", models.JobFailed, nil, ErrJobQueueFull.Error()).Return(nil).Once()
+	repo.On("Finish", mock.Anything, models.JobSucceeded, nil, "").Return(nil).Maybe()
+
+	pool := microservice.NewKeyedWorkerPool(1, 1)
+	defer pool.Close()
+	runner := newTestJobRunner(repo, pool)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runner.Start("SHOP01", "user01", "productimport", func(job IJobContext) (interface{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+	runner.Start("SHOP01", "user01", "productimport", func(job IJobContext) (interface{}, error) {
+		return nil, nil
+	})
+
+	// start is not blocked by full queue
+	jobID, err := runner.Start("SHOP01", "user01", "productimport", func(job IJobContext) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(t, ErrJobQueueFull, err)
+	assert.Empty(t, jobID)
+	close(release)
+	pool.Close()
+	repo.AssertExpectations(t)
+}
+
+func TestJobServiceFailStaleQueued(t *testing.T) {
+	t.Setenv("JOB_HEARTBEAT_INTERVAL_MS", "2000")
+	t.Setenv("JOB_QUEUED_TIMEOUT_MINUTES", "60")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	failed := models.JobDoc{ID: primitive.NewObjectID()}
+	failed.ShopID = "SHOP01"
+	failed.JobID = "JOB01"
+	failed.Status = models.JobFailed
+
+	repo := new(JobRepositoryMock)
+	repo.On("FailStale", "SHOP01", now.Add(-10*time.Second), now.Add(-time.Hour)).Return(nil)
+	repo.On("FindByJobID", "SHOP01", "JOB01").Return(failed, nil)
+	repo.On("FindByJobID", "SHOP01", "JOB02").Return(models.JobDoc{}, nil)
+
+	svc := NewJobService(repo, nil, config.NewJobConfig(), func() time.Time { return now })
+
+	doc, err := svc.InfoJob(context.Background(), "SHOP01", "JOB01")
+	assert.Nil(t, err)
+	assert.Equal(t, models.JobFailed, doc.Status)
+
+	_, err = svc.InfoJob(context.Background(), "SHOP01", "JOB02")
+	assert.Equal(t, ErrJobNotFound, err)
+	repo.AssertExpectations(t)
+}
+
+type JobRepositoryMock struct {
+	repositories.IJobRepository
+	mock.Mock
+}
+
+func (m *JobRepositoryMock) Create(ctx context.Context, doc models.JobDoc) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) FindByJobID(ctx context.Context, shopID string, jobID string) (models.JobDoc, error) {
+	args := m.Called(shopID, jobID)
+	return args.Get(0).(models.JobDoc), args.Error(1)
+}
+
+func (m *JobRepositoryMock) MarkRunning(ctx context.Context, jobID string, startedAt time.Time) (bool, error) {
+	args := m.Called(jobID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JobRepositoryMock) Heartbeat(ctx context.Context, jobID string, heartbeatAt time.Time) (bool, error) {
+	args := m.Called(jobID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JobRepositoryMock) UpdateProgress(ctx context.Context, jobID string, progress int, message string) error {
+	args := m.Called(jobID, progress, message)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) AppendLog(ctx context.Context, jobID string, log models.JobLog) error {
+	args := m.Called(jobID, log)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) Finish(ctx context.Context, jobID string, status string, result interface{}, errorMessage string, finishedAt time.Time, expireAt time.Time) error {
+	args := m.Called(jobID, status, result, errorMessage)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) CancelQueued(ctx context.Context, shopID string, jobID string, finishedAt time.Time, expireAt time.Time) (bool, error) {
+	args := m.Called(shopID, jobID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JobRepositoryMock) RequestCancel(ctx context.Context, shopID string, jobID string) (bool, error) {
+	args := m.Called(shopID, jobID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JobRepositoryMock) FailStale(ctx context.Context, shopID string, heartbeatBefore time.Time, createdBefore time.Time, finishedAt time.Time, expireAt time.Time) error {
+	args := m.Called(shopID, heartbeatBefore, createdBefore)
+	return args.Error(0)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job/models"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job is already finished")
+	// ErrJobQueueFull is returned when too many jobs are waiting, the job can be started again later
+	ErrJobQueueFull = errors.New("job queue is full, try again later")
+)
+
+type IJobService interface {
+	InfoJob(ctx context.Context, shopID string, jobID string) (models.JobInfo, error)
+	SearchJob(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.JobInfo, mongopagination.PaginationData, error)
+	CancelJob(ctx context.Context, shopID string, jobID string) error
+}
+
+type JobService struct {
+	repo           repositories.IJobRepository
+	runner         *JobRunner
+	cfg            config.IJobConfig
+	now            func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewJobService(repo repositories.IJobRepository, runner *JobRunner, cfg config.IJobConfig, now func() time.Time) *JobService {
+	return &JobService{
+		repo:           repo,
+		runner:         runner,
+		cfg:            cfg,
+		now:            now,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc JobService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc JobService) InfoJob(ctx context.Context, shopID string, jobID string) (models.JobInfo, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := svc.failStale(ctx, shopID)
+	if err != nil {
+		return models.JobInfo{}, err
+	}
+
+	doc, err := svc.repo.FindByJobID(ctx, shopID, jobID)
+	if err != nil {
+		return models.JobInfo{}, err
+	}
+
+	if doc.ID.IsZero() {
+		return models.JobInfo{}, ErrJobNotFound
+	}
+
+	return doc.JobInfo, nil
+}
+
+func (svc JobService) SearchJob(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.JobInfo, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := svc.failStale(ctx, shopID)
+	if err != nil {
+		return []models.JobInfo{}, mongopagination.PaginationData{}, err
+	}
+
+	return svc.repo.FindPage(ctx, shopID, filters, pageable)
+}
+
+// CancelJob cancel queued job at once, running job is cancelled through its context
+func (svc JobService) CancelJob(ctx context.Context, shopID string, jobID string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	now := svc.now()
+	cancelled, err := svc.repo.CancelQueued(ctx, shopID, jobID, now, now.Add(svc.cfg.Retention()))
+	if err != nil {
+		return err
+	}
+
+	if cancelled {
+		return nil
+	}
+
+	requested, err := svc.repo.RequestCancel(ctx, shopID, jobID)
+	if err != nil {
+		return err
+	}
+
+	if requested {
+		svc.runner.cancel(jobID)
+		return nil
+	}
+
+	doc, err := svc.repo.FindByJobID(ctx, shopID, jobID)
+	if err != nil {
+		return err
+	}
+
+	if doc.ID.IsZero() {
+		return ErrJobNotFound
+	}
+
+	if doc.Status == models.JobRunning {
+		// cancellation is already requested
+		return nil
+	}
+
+	return ErrJobFinished
+}
+
+// failStale fail running jobs which miss several heartbeats and queued jobs which are not started in queued timeout,
+// their replica is stopped before they are finished
+func (svc JobService) failStale(ctx context.Context, shopID string) error {
+	now := svc.now()
+	return svc.repo.FailStale(ctx, shopID, now.Add(-5*svc.cfg.HeartbeatInterval()), now.Add(-svc.cfg.QueuedTimeout()), now, now.Add(svc.cfg.Retention()))
+}
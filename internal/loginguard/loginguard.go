@@ -0,0 +1,22 @@
+package loginguard
+
+import (
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/loginguard/repositories"
+	"smlaicloudplatform/internal/loginguard/services"
+	"smlaicloudplatform/internal/shop"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// NewLoginGuard return login guard which count failed attempts in cacher and record security events in mongo
+func NewLoginGuard(ms *microservice.Microservice, cfg config.IConfig) services.ILoginGuard {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewLoginGuard(
+		ms.Cacher(cfg.CacherConfig()),
+		cfg.LoginGuardConfig(),
+		repositories.NewSecurityEventRepository(pst),
+		shop.NewShopUserRepository(pst),
+		ms.TimeNow,
+	)
+}
@@ -0,0 +1,98 @@
+package loginguard
+
+import (
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/loginguard/repositories"
+	"smlaicloudplatform/internal/loginguard/services"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type ISecurityEventHttp interface{}
+
+type SecurityEventHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.ISecurityEventHttpService
+}
+
+func NewSecurityEventHttp(ms *microservice.Microservice, cfg config.IConfig) SecurityEventHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	svc := services.NewSecurityEventHttpService(repositories.NewSecurityEventRepository(pst))
+
+	rbac.InitPermissionService(ms, cfg)
+
+	return SecurityEventHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h SecurityEventHttp) RegisterHttp() {
+	h.ms.GET("/security-events", h.SearchSecurityEventPage, h.ms.RequirePermission(rbacmodels.PermissionSecurityEventRead))
+}
+
+// Search Security Event godoc
+// @Description search failed logins, lockouts and otp rate limits of users of the shop, newest first
+// @Tags		SecurityEvent
+// @Param		q		query	string		false  "Search username or ip"
+// @Param		event		query	string		false  "login_failed, lockout, ip_lockout or otp_rate_limited"
+// @Param		username		query	string		false  "Username"
+// @Param		ip		query	string		false  "IP"
+// @Param		fromdate		query	string		false  "From date (yyyy-mm-dd)"
+// @Param		todate		query	string		false  "To date (yyyy-mm-dd)"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /security-events [get]
+func (h SecurityEventHttp) SearchSecurityEventPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "event",
+			Field: "event",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "username",
+			Field: "username",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "ip",
+			Field: "ip",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "createdat",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+	})
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchSecurityEvent(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
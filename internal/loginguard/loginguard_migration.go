@@ -0,0 +1,34 @@
+package loginguard
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/loginguard/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of security event search, events are removed by ttl index after the retention
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(context.Background(), &models.SecurityEventDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopids", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("securityevent_shopids_createdat"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdat", Value: 1}},
+			Options: options.Index().SetName("securityevent_createdat_ttl").SetExpireAfterSeconds(int32(cfg.LoginGuardConfig().SecurityEventRetention().Seconds())),
+		},
+	})
+	return err
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const securityEventCollectionName = "securityEvents"
+
+// scope of failed attempt counters, accounts of different scope with the same username are counted apart
+const (
+	ScopeUser = "user"
+	// ScopeOTP is scope of otp which is sent to phone number before register or login
+	ScopeOTP = "otp"
+)
+
+// ScopeDebtor is scope of debtor login of the shop, debtor username is unique in the shop only
+func ScopeDebtor(shopID string) string {
+	return "debtor:" + shopID
+}
+
+const (
+	SecurityEventLoginFailed    = "login_failed"
+	SecurityEventLockout        = "lockout"
+	SecurityEventIPLockout      = "ip_lockout"
+	SecurityEventLoginBlocked   = "login_blocked"
+	SecurityEventOTPRateLimited = "otp_rate_limited"
+)
+
+// LoginAttempt is one login of username from ip
+type LoginAttempt struct {
+	Scope    string
+	Username string
+	// IP is client ip of the request, X-Forwarded-For is trusted only from HTTP_TRUSTED_PROXIES so the ip
+	// lockout cannot be passed by changing the header
+	IP string
+	// ShopID is shop which is selected on login, shops of the username are added when the event is recorded
+	ShopID   string
+	AuthType string
+}
+
+// SecurityEvent is failed or blocked login, it is visible to owner of every shop of the username
+type SecurityEvent struct {
+	ShopIDs  []string `json:"shopids" bson:"shopids"`
+	Scope    string   `json:"scope" bson:"scope"`
+	Event    string   `json:"event" bson:"event"`
+	Username string   `json:"username" bson:"username"`
+	IP       string   `json:"ip" bson:"ip"`
+	AuthType string   `json:"authtype" bson:"authtype,omitempty"`
+	Reason   string   `json:"reason" bson:"reason,omitempty"`
+	Failures int      `json:"failures" bson:"failures,omitempty"`
+
+	CreatedAt time.Time `json:"createdat" bson:"createdat"`
+}
+
+func (SecurityEvent) CollectionName() string {
+	return securityEventCollectionName
+}
+
+type SecurityEventDoc struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SecurityEvent `bson:"inline"`
+}
+
+func (SecurityEventDoc) CollectionName() string {
+	return securityEventCollectionName
+}
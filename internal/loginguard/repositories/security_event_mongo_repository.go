@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/loginguard/models"
+	"smlaicloudplatform/internal/utils/search"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type ISecurityEventRepository interface {
+	Create(ctx context.Context, doc models.SecurityEvent) error
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.SecurityEventDoc, mongopagination.PaginationData, error)
+}
+
+type SecurityEventRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewSecurityEventRepository(pst microservice.IPersisterMongo) *SecurityEventRepository {
+	return &SecurityEventRepository{
+		pst: pst,
+	}
+}
+
+func (repo SecurityEventRepository) Create(ctx context.Context, doc models.SecurityEvent) error {
+	_, err := repo.pst.Create(ctx, &models.SecurityEventDoc{}, models.SecurityEventDoc{SecurityEvent: doc})
+	return err
+}
+
+// FindPageFilter return events of the shop, event belong to every shop of the username at the time it is recorded
+func (repo SecurityEventRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.SecurityEventDoc, mongopagination.PaginationData, error) {
+
+	queryFilters := bson.M{
+		"shopids": shopID,
+	}
+
+	matchFilterList := []interface{}{}
+	for key, value := range filters {
+		matchFilterList = append(matchFilterList, bson.M{key: value})
+	}
+
+	if len(matchFilterList) > 0 {
+		queryFilters["$and"] = matchFilterList
+	}
+
+	searchFilterQuery := search.CreateTextFilter(searchInFields, pageable.Query)
+	if len(searchFilterQuery) > 0 {
+		queryFilters["$or"] = searchFilterQuery
+	}
+
+	docList := []models.SecurityEventDoc{}
+	pagination, err := repo.pst.FindPage(ctx, &models.SecurityEventDoc{}, queryFilters, pageable, &docList)
+
+	if err != nil {
+		return []models.SecurityEventDoc{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authmodels "smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/loginguard/models"
+	"smlaicloudplatform/internal/loginguard/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+	ErrTooManyOTPSend  = errors.New("too many otp requests")
+)
+
+const (
+	otpCountWindow       = time.Hour
+	securityEventTimeout = 5 * time.Second
+)
+
+type ILoginGuard interface {
+	// Check return ErrTooManyAttempts when the username or ip is locked out or has to wait before next attempt
+	Check(attempt models.LoginAttempt) error
+	// Reserve count the attempt as failed before its credentials are verified, so concurrent attempts cannot pass
+	// the limit, ErrTooManyAttempts is returned like Check or when the attempt is over the limit
+	Reserve(attempt models.LoginAttempt) error
+	// Failed lock out the username or ip of the reserved attempt and record security event
+	Failed(attempt models.LoginAttempt, reason string)
+	// Succeeded reset failed attempts of the username and take back the reserved attempt of the ip,
+	// failures of the ip are kept
+	Succeeded(attempt models.LoginAttempt)
+	// AllowSendOTP return ErrTooManyOTPSend when otp is sent to the phone number or from the ip too often
+	AllowSendOTP(phoneNumber string, ip string) error
+}
+
+// IUserShopRepository find shops of the user so owner of the shops can see security events of the user
+type IUserShopRepository interface {
+	FindByUsername(ctx context.Context, username string) (*[]authmodels.ShopUser, error)
+}
+
+// LoginGuard keep failed attempt counters in cacher so they are shared by every instance,
+// attempt has to wait longer on each failure after DelayAfterFailures and is locked out after MaxFailures
+type LoginGuard struct {
+	cache        microservice.ICacher
+	cfg          config.ILoginGuardConfig
+	eventRepo    repositories.ISecurityEventRepository
+	userShopRepo IUserShopRepository
+	timeNow      func() time.Time
+}
+
+func NewLoginGuard(cache microservice.ICacher, cfg config.ILoginGuardConfig, eventRepo repositories.ISecurityEventRepository, userShopRepo IUserShopRepository, timeNow func() time.Time) *LoginGuard {
+	return &LoginGuard{
+		cache:        cache,
+		cfg:          cfg,
+		eventRepo:    eventRepo,
+		userShopRepo: userShopRepo,
+		timeNow:      timeNow,
+	}
+}
+
+func (g LoginGuard) Check(attempt models.LoginAttempt) error {
+	now := g.timeNow()
+
+	for _, lockKey := range []string{g.lockKey(attempt), g.ipLockKey(attempt.IP)} {
+		until, err := g.getUnixMilli(lockKey)
+		if err != nil {
+			return err
+		}
+
+		if until.After(now) {
+			return blockedError(ErrTooManyAttempts, until.Sub(now))
+		}
+	}
+
+	nextAt, err := g.cache.HGet(g.failureKey(attempt), "nextat")
+	if err != nil {
+		return err
+	}
+
+	if until := parseUnixMilli(nextAt); until.After(now) {
+		return blockedError(ErrTooManyAttempts, until.Sub(now))
+	}
+
+	return nil
+}
+
+func (g LoginGuard) Reserve(attempt models.LoginAttempt) error {
+	err := g.Check(attempt)
+	if err != nil {
+		return err
+	}
+
+	failureKey := g.failureKey(attempt)
+
+	failures, err := g.cache.HIncr(failureKey, "failures")
+	if err != nil {
+		return err
+	}
+
+	err = g.cache.Expire(failureKey, g.cfg.FailureWindow())
+	if err != nil {
+		return err
+	}
+
+	// attempts which are reserved together with the last allowed attempt wait until it is failed or succeeded
+	if failures > g.cfg.MaxFailures() {
+		return blockedError(ErrTooManyAttempts, g.delay(failures))
+	}
+
+	if attempt.IP == "" {
+		return nil
+	}
+
+	ipKey := g.ipFailureKey(attempt.IP)
+	ipFailures, err := g.cache.Incr(ipKey)
+	if err != nil {
+		return err
+	}
+
+	err = g.cache.Expire(ipKey, g.cfg.FailureWindow())
+	if err != nil {
+		return err
+	}
+
+	if ipFailures > g.cfg.IPMaxFailures() {
+		return blockedError(ErrTooManyAttempts, g.delay(ipFailures))
+	}
+
+	return nil
+}
+
+func (g LoginGuard) Failed(attempt models.LoginAttempt, reason string) {
+	now := g.timeNow()
+	failureKey := g.failureKey(attempt)
+
+	// the failure is counted when the attempt is reserved
+	failures, err := g.reservedCount(g.cache.HGet(failureKey, "failures"))
+	if err != nil {
+		logger.GetLogger().Errorf("login guard count failure of %s: %s", attempt.Username, err.Error())
+		return
+	}
+
+	g.recordEvent(attempt, models.SecurityEventLoginFailed, reason, failures)
+
+	if failures >= g.cfg.MaxFailures() {
+		g.lockout(g.lockKey(attempt), failureKey, now)
+		g.recordEvent(attempt, models.SecurityEventLockout, reason, failures)
+	} else if failures >= g.cfg.DelayAfterFailures() {
+		err = g.cache.HMSet(failureKey, map[string]interface{}{
+			"nextat": now.Add(g.delay(failures)).UnixMilli(),
+		})
+		if err != nil {
+			logger.GetLogger().Errorf("login guard delay %s: %s", attempt.Username, err.Error())
+		}
+	}
+
+	if attempt.IP == "" {
+		return
+	}
+
+	ipKey := g.ipFailureKey(attempt.IP)
+	ipFailures, err := g.reservedCount(g.cache.Get(ipKey))
+	if err != nil {
+		logger.GetLogger().Errorf("login guard count failure of ip %s: %s", attempt.IP, err.Error())
+		return
+	}
+
+	if ipFailures >= g.cfg.IPMaxFailures() {
+		g.lockout(g.ipLockKey(attempt.IP), ipKey, now)
+		g.recordEvent(attempt, models.SecurityEventIPLockout, reason, ipFailures)
+	}
+}
+
+func (g LoginGuard) Succeeded(attempt models.LoginAttempt) {
+	err := g.cache.Del(g.failureKey(attempt))
+	if err != nil {
+		logger.GetLogger().Errorf("login guard reset failure of %s: %s", attempt.Username, err.Error())
+	}
+
+	if attempt.IP == "" {
+		return
+	}
+
+	ipKey := g.ipFailureKey(attempt.IP)
+	ipFailures, err := g.cache.Decr(ipKey)
+	if err != nil {
+		logger.GetLogger().Errorf("login guard take back attempt of ip %s: %s", attempt.IP, err.Error())
+		return
+	}
+
+	// counter which is expired or reset by lockout while the attempt is verified is not kept below zero
+	if ipFailures <= 0 {
+		err = g.cache.Del(ipKey)
+		if err != nil {
+			logger.GetLogger().Errorf("login guard reset failure of ip %s: %s", attempt.IP, err.Error())
+		}
+	}
+}
+
+func (g LoginGuard) AllowSendOTP(phoneNumber string, ip string) error {
+	allowed, err := g.cache.SetNX(g.otpIntervalKey(phoneNumber), 1, g.cfg.OTPSendInterval())
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return blockedError(ErrTooManyOTPSend, g.cfg.OTPSendInterval())
+	}
+
+	err = g.countOTP(g.otpCountKey(phoneNumber), g.cfg.OTPMaxPerHour(), phoneNumber, ip)
+	if err != nil {
+		return err
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	return g.countOTP(g.otpIPCountKey(ip), g.cfg.OTPIPMaxPerHour(), phoneNumber, ip)
+}
+
+func (g LoginGuard) countOTP(key string, max int, phoneNumber string, ip string) error {
+	count, err := g.cache.Incr(key)
+	if err != nil {
+		return err
+	}
+
+	if count == 1 {
+		err = g.cache.Expire(key, otpCountWindow)
+		if err != nil {
+			return err
+		}
+	}
+
+	if count <= max {
+		return nil
+	}
+
+	// the event is recorded once when the limit is reached so repeated requests do not flood the log
+	if count == max+1 {
+		g.recordEvent(models.LoginAttempt{Scope: models.ScopeOTP, Username: phoneNumber, IP: ip}, models.SecurityEventOTPRateLimited, "", count)
+	}
+
+	return blockedError(ErrTooManyOTPSend, otpCountWindow)
+}
+
+// delay double on each failure after DelayAfterFailures, it is not more than MaxDelay
+func (g LoginGuard) delay(failures int) time.Duration {
+	delay := g.cfg.BaseDelay()
+	for i := g.cfg.DelayAfterFailures(); i < failures && delay < g.cfg.MaxDelay(); i++ {
+		delay *= 2
+	}
+
+	if delay > g.cfg.MaxDelay() {
+		return g.cfg.MaxDelay()
+	}
+
+	return delay
+}
+
+func (g LoginGuard) lockout(lockKey string, failureKey string, now time.Time) {
+	lockout := g.cfg.LockoutDuration()
+
+	err := g.cache.Set(lockKey, now.Add(lockout).UnixMilli(), lockout)
+	if err != nil {
+		logger.GetLogger().Errorf("login guard lockout %s: %s", lockKey, err.Error())
+		return
+	}
+
+	// counting start again after the lockout
+	err = g.cache.Del(failureKey)
+	if err != nil {
+		logger.GetLogger().Errorf("login guard reset %s: %s", failureKey, err.Error())
+	}
+}
+
+func (g LoginGuard) recordEvent(attempt models.LoginAttempt, event string, reason string, failures int) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), securityEventTimeout)
+	defer ctxCancel()
+
+	shopIDs := []string{}
+	if attempt.ShopID != "" {
+		shopIDs = append(shopIDs, attempt.ShopID)
+	}
+
+	if attempt.Scope == models.ScopeUser && attempt.Username != "" {
+		shopUsers, err := g.userShopRepo.FindByUsername(ctx, attempt.Username)
+		if err != nil {
+			logger.GetLogger().Errorf("security event find shops of %s: %s", attempt.Username, err.Error())
+		} else if shopUsers != nil {
+			for _, shopUser := range *shopUsers {
+				if shopUser.ShopID != attempt.ShopID {
+					shopIDs = append(shopIDs, shopUser.ShopID)
+				}
+			}
+		}
+	}
+
+	err := g.eventRepo.Create(ctx, models.SecurityEvent{
+		ShopIDs:   shopIDs,
+		Scope:     attempt.Scope,
+		Event:     event,
+		Username:  attempt.Username,
+		IP:        attempt.IP,
+		AuthType:  attempt.AuthType,
+		Reason:    reason,
+		Failures:  failures,
+		CreatedAt: g.timeNow(),
+	})
+
+	if err != nil {
+		logger.GetLogger().Errorf("security event %s of %s: %s", event, attempt.Username, err.Error())
+	}
+}
+
+// reservedCount parse counter of reserved attempts, it is at least 1 for the failed attempt
+// whose counter is expired or reset by lockout of a concurrent attempt
+func (LoginGuard) reservedCount(value string, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 1, nil
+	}
+
+	return count, nil
+}
+
+func (g LoginGuard) getUnixMilli(key string) (time.Time, error) {
+	value, err := g.cache.Get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseUnixMilli(value), nil
+}
+
+func (LoginGuard) failureKey(attempt models.LoginAttempt) string {
+	return fmt.Sprintf("loginguard:%s:failure:%s", attempt.Scope, attempt.Username)
+}
+
+func (LoginGuard) lockKey(attempt models.LoginAttempt) string {
+	return fmt.Sprintf("loginguard:%s:lock:%s", attempt.Scope, attempt.Username)
+}
+
+// failures of ip are counted on every scope
+func (LoginGuard) ipFailureKey(ip string) string {
+	return fmt.Sprintf("loginguard:ip:failure:%s", ip)
+}
+
+func (LoginGuard) ipLockKey(ip string) string {
+	return fmt.Sprintf("loginguard:ip:lock:%s", ip)
+}
+
+func (LoginGuard) otpIntervalKey(phoneNumber string) string {
+	return fmt.Sprintf("loginguard:otp:interval:%s", phoneNumber)
+}
+
+func (LoginGuard) otpCountKey(phoneNumber string) string {
+	return fmt.Sprintf("loginguard:otp:count:%s", phoneNumber)
+}
+
+func (LoginGuard) otpIPCountKey(ip string) string {
+	return fmt.Sprintf("loginguard:otp:ip:%s", ip)
+}
+
+func parseUnixMilli(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(ms)
+}
+
+func blockedError(err error, retryAfter time.Duration) error {
+	seconds := int(retryAfter.Round(time.Second).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Errorf("%w, try again in %d seconds", err, seconds)
+}
@@ -0,0 +1,285 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authmodels "smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/loginguard/models"
+	"smlaicloudplatform/internal/loginguard/services"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"testing"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"github.com/stretchr/testify/assert"
+)
+
+// cacherStub keep values in memory and expire them by the clock of the test
+type cacherStub struct {
+	microservice.ICacher
+	now      func() time.Time
+	values   map[string]string
+	hashes   map[string]map[string]string
+	expireAt map[string]time.Time
+}
+
+func newCacherStub(now func() time.Time) *cacherStub {
+	return &cacherStub{
+		now:      now,
+		values:   map[string]string{},
+		hashes:   map[string]map[string]string{},
+		expireAt: map[string]time.Time{},
+	}
+}
+
+func (c *cacherStub) evict(key string) {
+	if at, ok := c.expireAt[key]; ok && !c.now().Before(at) {
+		delete(c.values, key)
+		delete(c.hashes, key)
+		delete(c.expireAt, key)
+	}
+}
+
+func (c *cacherStub) Get(key string) (string, error) {
+	c.evict(key)
+	return c.values[key], nil
+}
+
+func (c *cacherStub) Set(key string, value interface{}, expire time.Duration) error {
+	c.values[key] = fmt.Sprint(value)
+	c.expireAt[key] = c.now().Add(expire)
+	return nil
+}
+
+func (c *cacherStub) SetNX(key string, value interface{}, expire time.Duration) (bool, error) {
+	c.evict(key)
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	return true, c.Set(key, value, expire)
+}
+
+func (c *cacherStub) Incr(key string) (int, error) {
+	c.evict(key)
+	count := 0
+	fmt.Sscan(c.values[key], &count)
+	count++
+	c.values[key] = fmt.Sprint(count)
+	return count, nil
+}
+
+func (c *cacherStub) Decr(key string) (int, error) {
+	c.evict(key)
+	count := 0
+	fmt.Sscan(c.values[key], &count)
+	count--
+	c.values[key] = fmt.Sprint(count)
+	return count, nil
+}
+
+func (c *cacherStub) HIncr(key string, field string) (int, error) {
+	c.evict(key)
+	if c.hashes[key] == nil {
+		c.hashes[key] = map[string]string{}
+	}
+	count := 0
+	fmt.Sscan(c.hashes[key][field], &count)
+	count++
+	c.hashes[key][field] = fmt.Sprint(count)
+	return count, nil
+}
+
+func (c *cacherStub) HMSet(key string, fieldValues map[string]interface{}) error {
+	if c.hashes[key] == nil {
+		c.hashes[key] = map[string]string{}
+	}
+	for field, value := range fieldValues {
+		c.hashes[key][field] = fmt.Sprint(value)
+	}
+	return nil
+}
+
+func (c *cacherStub) HGet(key string, field string) (string, error) {
+	c.evict(key)
+	return c.hashes[key][field], nil
+}
+
+func (c *cacherStub) Expire(key string, expire time.Duration) error {
+	c.expireAt[key] = c.now().Add(expire)
+	return nil
+}
+
+func (c *cacherStub) Del(keys ...string) error {
+	for _, key := range keys {
+		delete(c.values, key)
+		delete(c.hashes, key)
+		delete(c.expireAt, key)
+	}
+	return nil
+}
+
+type loginGuardConfigStub struct{}
+
+func (loginGuardConfigStub) FailureWindow() time.Duration          { return 15 * time.Minute }
+func (loginGuardConfigStub) DelayAfterFailures() int               { return 2 }
+func (loginGuardConfigStub) BaseDelay() time.Duration              { return time.Second }
+func (loginGuardConfigStub) MaxDelay() time.Duration               { return 4 * time.Second }
+func (loginGuardConfigStub) MaxFailures() int                      { return 5 }
+func (loginGuardConfigStub) IPMaxFailures() int                    { return 8 }
+func (loginGuardConfigStub) LockoutDuration() time.Duration        { return 15 * time.Minute }
+func (loginGuardConfigStub) OTPSendInterval() time.Duration        { return time.Minute }
+func (loginGuardConfigStub) OTPMaxPerHour() int                    { return 3 }
+func (loginGuardConfigStub) OTPIPMaxPerHour() int                  { return 10 }
+func (loginGuardConfigStub) SecurityEventRetention() time.Duration { return time.Hour }
+
+type securityEventRepositoryStub struct {
+	events []models.SecurityEvent
+}
+
+func (r *securityEventRepositoryStub) Create(ctx context.Context, doc models.SecurityEvent) error {
+	r.events = append(r.events, doc)
+	return nil
+}
+
+func (r *securityEventRepositoryStub) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.SecurityEventDoc, mongopagination.PaginationData, error) {
+	return nil, mongopagination.PaginationData{}, nil
+}
+
+func (r *securityEventRepositoryStub) count(event string) int {
+	count := 0
+	for _, doc := range r.events {
+		if doc.Event == event {
+			count++
+		}
+	}
+	return count
+}
+
+type userShopRepositoryStub struct{}
+
+func (userShopRepositoryStub) FindByUsername(ctx context.Context, username string) (*[]authmodels.ShopUser, error) {
+	shopUser := authmodels.ShopUser{}
+	shopUser.ShopID = "shop01"
+	shopUser.Username = username
+	return &[]authmodels.ShopUser{shopUser}, nil
+}
+
+func newTestLoginGuard() (*services.LoginGuard, *securityEventRepositoryStub, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow := func() time.Time { return now }
+	eventRepo := &securityEventRepositoryStub{}
+
+	guard := services.NewLoginGuard(newCacherStub(timeNow), loginGuardConfigStub{}, eventRepo, userShopRepositoryStub{}, timeNow)
+	return guard, eventRepo, &now
+}
+
+func TestLoginGuard_ProgressiveDelayAndLockout(t *testing.T) {
+	guard, eventRepo, now := newTestLoginGuard()
+	attempt := models.LoginAttempt{Scope: models.ScopeUser, Username: "user01", IP: "10.0.0.1"}
+
+	failed := func() {
+		assert.Nil(t, guard.Reserve(attempt))
+		guard.Failed(attempt, "password invalid")
+	}
+
+	// failures before DelayAfterFailures do not delay next attempt
+	failed()
+	assert.Nil(t, guard.Check(attempt))
+
+	failed()
+	err := guard.Reserve(attempt)
+	assert.True(t, errors.Is(err, services.ErrTooManyAttempts))
+	assert.EqualError(t, err, "too many failed attempts, try again in 1 seconds")
+
+	*now = now.Add(time.Second)
+	failed()
+	assert.EqualError(t, guard.Check(attempt), "too many failed attempts, try again in 2 seconds")
+
+	// delay is not more than MaxDelay
+	*now = now.Add(2 * time.Second)
+	failed()
+	assert.EqualError(t, guard.Check(attempt), "too many failed attempts, try again in 4 seconds")
+
+	*now = now.Add(4 * time.Second)
+	failed()
+	assert.EqualError(t, guard.Reserve(attempt), "too many failed attempts, try again in 900 seconds")
+	assert.Equal(t, 5, eventRepo.count(models.SecurityEventLoginFailed))
+	assert.Equal(t, 1, eventRepo.count(models.SecurityEventLockout))
+	assert.Equal(t, []string{"shop01"}, eventRepo.events[0].ShopIDs)
+
+	// other scope with the same username is not locked out
+	assert.Nil(t, guard.Check(models.LoginAttempt{Scope: models.ScopeDebtor("shop01"), Username: "user01", IP: "10.0.0.2"}))
+
+	*now = now.Add(15 * time.Minute)
+	assert.Nil(t, guard.Check(attempt))
+}
+
+func TestLoginGuard_ReserveConcurrentAttempts(t *testing.T) {
+	guard, _, _ := newTestLoginGuard()
+	attempt := models.LoginAttempt{Scope: models.ScopeUser, Username: "user01", IP: "10.0.0.1"}
+
+	// attempts which are verified at the same time cannot pass MaxFailures
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, guard.Reserve(attempt))
+	}
+
+	err := guard.Reserve(attempt)
+	assert.True(t, errors.Is(err, services.ErrTooManyAttempts))
+
+	guard.Succeeded(attempt)
+	assert.Nil(t, guard.Reserve(attempt))
+}
+
+func TestLoginGuard_SucceededResetUsernameOnly(t *testing.T) {
+	guard, eventRepo, _ := newTestLoginGuard()
+
+	failed := func(username string) {
+		attempt := models.LoginAttempt{Scope: models.ScopeUser, Username: username, IP: "10.0.0.1"}
+		assert.Nil(t, guard.Reserve(attempt))
+		guard.Failed(attempt, "user not found")
+	}
+
+	for i := 0; i < 7; i++ {
+		failed(fmt.Sprintf("user%02d", i))
+	}
+
+	// succeeded attempt is not counted but failures of the ip are kept
+	attempt := models.LoginAttempt{Scope: models.ScopeUser, Username: "user01", IP: "10.0.0.1"}
+	assert.Nil(t, guard.Reserve(attempt))
+	guard.Succeeded(attempt)
+	assert.Equal(t, 0, eventRepo.count(models.SecurityEventIPLockout))
+
+	// spraying many usernames lock out the ip
+	failed("user07")
+	err := guard.Check(models.LoginAttempt{Scope: models.ScopeUser, Username: "other", IP: "10.0.0.1"})
+	assert.True(t, errors.Is(err, services.ErrTooManyAttempts))
+	assert.Equal(t, 1, eventRepo.count(models.SecurityEventIPLockout))
+
+	assert.Nil(t, guard.Check(models.LoginAttempt{Scope: models.ScopeUser, Username: "user01", IP: "10.0.0.2"}))
+}
+
+func TestLoginGuard_AllowSendOTP(t *testing.T) {
+	guard, eventRepo, now := newTestLoginGuard()
+
+	assert.Nil(t, guard.AllowSendOTP("66812345678", "10.0.0.1"))
+
+	err := guard.AllowSendOTP("66812345678", "10.0.0.1")
+	assert.True(t, errors.Is(err, services.ErrTooManyOTPSend))
+
+	// other number is not limited by the interval
+	assert.Nil(t, guard.AllowSendOTP("66887654321", "10.0.0.1"))
+
+	for i := 0; i < 2; i++ {
+		*now = now.Add(time.Minute)
+		assert.Nil(t, guard.AllowSendOTP("66812345678", "10.0.0.1"))
+	}
+
+	*now = now.Add(time.Minute)
+	assert.EqualError(t, guard.AllowSendOTP("66812345678", "10.0.0.1"), "too many otp requests, try again in 3600 seconds")
+	assert.Equal(t, 1, eventRepo.count(models.SecurityEventOTPRateLimited))
+
+	*now = now.Add(time.Hour)
+	assert.Nil(t, guard.AllowSendOTP("66812345678", "10.0.0.1"))
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"smlaicloudplatform/internal/loginguard/models"
+	"smlaicloudplatform/internal/loginguard/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+)
+
+type ISecurityEventHttpService interface {
+	SearchSecurityEvent(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SecurityEventDoc, mongopagination.PaginationData, error)
+}
+
+type SecurityEventHttpService struct {
+	repo           repositories.ISecurityEventRepository
+	contextTimeout time.Duration
+}
+
+func NewSecurityEventHttpService(repo repositories.ISecurityEventRepository) *SecurityEventHttpService {
+	return &SecurityEventHttpService{
+		repo:           repo,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc SecurityEventHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// SearchSecurityEvent return security events of users of the shop, newest first when sort is not requested
+func (svc SecurityEventHttpService) SearchSecurityEvent(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SecurityEventDoc, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if len(pageable.Sorts) < 1 {
+		pageable.Sorts = append(pageable.Sorts, micromodels.KeyInt{Key: "createdat", Value: -1})
+	}
+
+	searchInFields := []string{
+		"username",
+		"ip",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.SecurityEventDoc{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
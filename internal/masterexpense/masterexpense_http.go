@@ -80,7 +80,7 @@ func (h MasterExpenseHttp) CreateMasterExpense(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.svc.CreateMasterExpense(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateMasterExpense(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -125,7 +125,7 @@ func (h MasterExpenseHttp) UpdateMasterExpense(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdateMasterExpense(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateMasterExpense(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -156,7 +156,7 @@ func (h MasterExpenseHttp) DeleteMasterExpense(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteMasterExpense(shopID, id, authUsername)
+	err := h.svc.DeleteMasterExpense(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -195,7 +195,7 @@ func (h MasterExpenseHttp) DeleteMasterExpenseByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeleteMasterExpenseByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteMasterExpenseByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -225,7 +225,7 @@ func (h MasterExpenseHttp) InfoMasterExpense(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get MasterExpense %v", id)
-	doc, err := h.svc.InfoMasterExpense(shopID, id)
+	doc, err := h.svc.InfoMasterExpense(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -255,7 +255,7 @@ func (h MasterExpenseHttp) InfoMasterExpenseByCode(ctx microservice.IContext) er
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoMasterExpenseByCode(shopID, code)
+	doc, err := h.svc.InfoMasterExpenseByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h MasterExpenseHttp) SearchMasterExpensePage(ctx microservice.IContext) er
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, pagination, err := h.svc.SearchMasterExpense(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchMasterExpense(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -325,7 +325,7 @@ func (h MasterExpenseHttp) SearchMasterExpenseStep(ctx microservice.IContext) er
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, total, err := h.svc.SearchMasterExpenseStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchMasterExpenseStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -365,7 +365,7 @@ func (h MasterExpenseHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
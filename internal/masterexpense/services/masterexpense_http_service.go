@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IMasterExpenseHttpService interface {
-	CreateMasterExpense(shopID string, authUsername string, doc models.MasterExpense) (string, error)
-	UpdateMasterExpense(shopID string, guid string, authUsername string, doc models.MasterExpense) error
-	DeleteMasterExpense(shopID string, guid string, authUsername string) error
-	DeleteMasterExpenseByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoMasterExpense(shopID string, guid string) (models.MasterExpenseInfo, error)
-	InfoMasterExpenseByCode(shopID string, code string) (models.MasterExpenseInfo, error)
-	SearchMasterExpense(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterExpenseInfo, mongopagination.PaginationData, error)
-	SearchMasterExpenseStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterExpenseInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.MasterExpense) (common.BulkImport, error)
+	CreateMasterExpense(ctx context.Context, shopID string, authUsername string, doc models.MasterExpense) (string, error)
+	UpdateMasterExpense(ctx context.Context, shopID string, guid string, authUsername string, doc models.MasterExpense) error
+	DeleteMasterExpense(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteMasterExpenseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoMasterExpense(ctx context.Context, shopID string, guid string) (models.MasterExpenseInfo, error)
+	InfoMasterExpenseByCode(ctx context.Context, shopID string, code string) (models.MasterExpenseInfo, error)
+	SearchMasterExpense(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterExpenseInfo, mongopagination.PaginationData, error)
+	SearchMasterExpenseStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterExpenseInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.MasterExpense) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -59,13 +60,13 @@ func NewMasterExpenseHttpService(
 	return insSvc
 }
 
-func (svc MasterExpenseHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc MasterExpenseHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc MasterExpenseHttpService) CreateMasterExpense(shopID string, authUsername string, doc models.MasterExpense) (string, error) {
+func (svc MasterExpenseHttpService) CreateMasterExpense(ctx context.Context, shopID string, authUsername string, doc models.MasterExpense) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.existsCode(ctx, shopID, doc.Code)
@@ -123,9 +124,9 @@ func (svc MasterExpenseHttpService) existsCode(ctx context.Context, shopID strin
 	return nil
 }
 
-func (svc MasterExpenseHttpService) UpdateMasterExpense(shopID string, guid string, authUsername string, doc models.MasterExpense) error {
+func (svc MasterExpenseHttpService) UpdateMasterExpense(ctx context.Context, shopID string, guid string, authUsername string, doc models.MasterExpense) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -158,9 +159,9 @@ func (svc MasterExpenseHttpService) UpdateMasterExpense(shopID string, guid stri
 	return nil
 }
 
-func (svc MasterExpenseHttpService) DeleteMasterExpense(shopID string, guid string, authUsername string) error {
+func (svc MasterExpenseHttpService) DeleteMasterExpense(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -185,9 +186,9 @@ func (svc MasterExpenseHttpService) DeleteMasterExpense(shopID string, guid stri
 	return nil
 }
 
-func (svc MasterExpenseHttpService) DeleteMasterExpenseByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc MasterExpenseHttpService) DeleteMasterExpenseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -206,9 +207,9 @@ func (svc MasterExpenseHttpService) DeleteMasterExpenseByGUIDs(shopID string, au
 	return nil
 }
 
-func (svc MasterExpenseHttpService) InfoMasterExpense(shopID string, guid string) (models.MasterExpenseInfo, error) {
+func (svc MasterExpenseHttpService) InfoMasterExpense(ctx context.Context, shopID string, guid string) (models.MasterExpenseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -224,9 +225,9 @@ func (svc MasterExpenseHttpService) InfoMasterExpense(shopID string, guid string
 	return findDoc.MasterExpenseInfo, nil
 }
 
-func (svc MasterExpenseHttpService) InfoMasterExpenseByCode(shopID string, code string) (models.MasterExpenseInfo, error) {
+func (svc MasterExpenseHttpService) InfoMasterExpenseByCode(ctx context.Context, shopID string, code string) (models.MasterExpenseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -242,9 +243,9 @@ func (svc MasterExpenseHttpService) InfoMasterExpenseByCode(shopID string, code
 	return findDoc.MasterExpenseInfo, nil
 }
 
-func (svc MasterExpenseHttpService) SearchMasterExpense(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterExpenseInfo, mongopagination.PaginationData, error) {
+func (svc MasterExpenseHttpService) SearchMasterExpense(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterExpenseInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -260,9 +261,9 @@ func (svc MasterExpenseHttpService) SearchMasterExpense(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc MasterExpenseHttpService) SearchMasterExpenseStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterExpenseInfo, int, error) {
+func (svc MasterExpenseHttpService) SearchMasterExpenseStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterExpenseInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -288,9 +289,9 @@ func (svc MasterExpenseHttpService) SearchMasterExpenseStep(shopID string, langC
 	return docList, total, nil
 }
 
-func (svc MasterExpenseHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.MasterExpense) (common.BulkImport, error) {
+func (svc MasterExpenseHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.MasterExpense) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.MasterExpense](dataList, svc.getDocIDKey)
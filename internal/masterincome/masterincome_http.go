@@ -80,7 +80,7 @@ func (h MasterIncomeHttp) CreateMasterIncome(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateMasterIncome(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateMasterIncome(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -125,7 +125,7 @@ func (h MasterIncomeHttp) UpdateMasterIncome(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateMasterIncome(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateMasterIncome(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -156,7 +156,7 @@ func (h MasterIncomeHttp) DeleteMasterIncome(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteMasterIncome(shopID, id, authUsername)
+	err := h.svc.DeleteMasterIncome(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -195,7 +195,7 @@ func (h MasterIncomeHttp) DeleteMasterIncomeByGUIDs(ctx microservice.IContext) e
 		return err
 	}
 
-	err = h.svc.DeleteMasterIncomeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteMasterIncomeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -225,7 +225,7 @@ func (h MasterIncomeHttp) InfoMasterIncome(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get MasterIncome %v", id)
-	doc, err := h.svc.InfoMasterIncome(shopID, id)
+	doc, err := h.svc.InfoMasterIncome(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -255,7 +255,7 @@ func (h MasterIncomeHttp) InfoMasterIncomeByCode(ctx microservice.IContext) erro
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoMasterIncomeByCode(shopID, code)
+	doc, err := h.svc.InfoMasterIncomeByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h MasterIncomeHttp) SearchMasterIncomePage(ctx microservice.IContext) erro
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, pagination, err := h.svc.SearchMasterIncome(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchMasterIncome(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -325,7 +325,7 @@ func (h MasterIncomeHttp) SearchMasterIncomeStep(ctx microservice.IContext) erro
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, total, err := h.svc.SearchMasterIncomeStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchMasterIncomeStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -365,7 +365,7 @@ func (h MasterIncomeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IMasterIncomeHttpService interface {
-	CreateMasterIncome(shopID string, authUsername string, doc models.MasterIncome) (string, error)
-	UpdateMasterIncome(shopID string, guid string, authUsername string, doc models.MasterIncome) error
-	DeleteMasterIncome(shopID string, guid string, authUsername string) error
-	DeleteMasterIncomeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoMasterIncome(shopID string, guid string) (models.MasterIncomeInfo, error)
-	InfoMasterIncomeByCode(shopID string, code string) (models.MasterIncomeInfo, error)
-	SearchMasterIncome(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterIncomeInfo, mongopagination.PaginationData, error)
-	SearchMasterIncomeStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterIncomeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.MasterIncome) (common.BulkImport, error)
+	CreateMasterIncome(ctx context.Context, shopID string, authUsername string, doc models.MasterIncome) (string, error)
+	UpdateMasterIncome(ctx context.Context, shopID string, guid string, authUsername string, doc models.MasterIncome) error
+	DeleteMasterIncome(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteMasterIncomeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoMasterIncome(ctx context.Context, shopID string, guid string) (models.MasterIncomeInfo, error)
+	InfoMasterIncomeByCode(ctx context.Context, shopID string, code string) (models.MasterIncomeInfo, error)
+	SearchMasterIncome(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterIncomeInfo, mongopagination.PaginationData, error)
+	SearchMasterIncomeStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterIncomeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.MasterIncome) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -59,13 +60,13 @@ func NewMasterIncomeHttpService(
 	return insSvc
 }
 
-func (svc MasterIncomeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc MasterIncomeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc MasterIncomeHttpService) CreateMasterIncome(shopID string, authUsername string, doc models.MasterIncome) (string, error) {
+func (svc MasterIncomeHttpService) CreateMasterIncome(ctx context.Context, shopID string, authUsername string, doc models.MasterIncome) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.existsCode(ctx, shopID, doc.Code)
@@ -120,9 +121,9 @@ func (svc MasterIncomeHttpService) existsCode(ctx context.Context, shopID string
 	return nil
 }
 
-func (svc MasterIncomeHttpService) UpdateMasterIncome(shopID string, guid string, authUsername string, doc models.MasterIncome) error {
+func (svc MasterIncomeHttpService) UpdateMasterIncome(ctx context.Context, shopID string, guid string, authUsername string, doc models.MasterIncome) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -155,9 +156,9 @@ func (svc MasterIncomeHttpService) UpdateMasterIncome(shopID string, guid string
 	return nil
 }
 
-func (svc MasterIncomeHttpService) DeleteMasterIncome(shopID string, guid string, authUsername string) error {
+func (svc MasterIncomeHttpService) DeleteMasterIncome(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -182,9 +183,9 @@ func (svc MasterIncomeHttpService) DeleteMasterIncome(shopID string, guid string
 	return nil
 }
 
-func (svc MasterIncomeHttpService) DeleteMasterIncomeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc MasterIncomeHttpService) DeleteMasterIncomeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -203,9 +204,9 @@ func (svc MasterIncomeHttpService) DeleteMasterIncomeByGUIDs(shopID string, auth
 	return nil
 }
 
-func (svc MasterIncomeHttpService) InfoMasterIncome(shopID string, guid string) (models.MasterIncomeInfo, error) {
+func (svc MasterIncomeHttpService) InfoMasterIncome(ctx context.Context, shopID string, guid string) (models.MasterIncomeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -221,9 +222,9 @@ func (svc MasterIncomeHttpService) InfoMasterIncome(shopID string, guid string)
 	return findDoc.MasterIncomeInfo, nil
 }
 
-func (svc MasterIncomeHttpService) InfoMasterIncomeByCode(shopID string, code string) (models.MasterIncomeInfo, error) {
+func (svc MasterIncomeHttpService) InfoMasterIncomeByCode(ctx context.Context, shopID string, code string) (models.MasterIncomeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -239,9 +240,9 @@ func (svc MasterIncomeHttpService) InfoMasterIncomeByCode(shopID string, code st
 	return findDoc.MasterIncomeInfo, nil
 }
 
-func (svc MasterIncomeHttpService) SearchMasterIncome(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterIncomeInfo, mongopagination.PaginationData, error) {
+func (svc MasterIncomeHttpService) SearchMasterIncome(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MasterIncomeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -257,9 +258,9 @@ func (svc MasterIncomeHttpService) SearchMasterIncome(shopID string, filters map
 	return docList, pagination, nil
 }
 
-func (svc MasterIncomeHttpService) SearchMasterIncomeStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterIncomeInfo, int, error) {
+func (svc MasterIncomeHttpService) SearchMasterIncomeStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MasterIncomeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -285,9 +286,9 @@ func (svc MasterIncomeHttpService) SearchMasterIncomeStep(shopID string, langCod
 	return docList, total, nil
 }
 
-func (svc MasterIncomeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.MasterIncome) (common.BulkImport, error) {
+func (svc MasterIncomeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.MasterIncome) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.MasterIncome](dataList, svc.getDocIDKey)
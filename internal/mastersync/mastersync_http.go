@@ -35,6 +35,7 @@ import (
 	bookbankRepo "smlaicloudplatform/internal/payment/bookbank/repositories"
 	bookbankService "smlaicloudplatform/internal/payment/bookbank/services"
 
+	"smlaicloudplatform/internal/encrypt/datakey"
 	qrpaymentRepo "smlaicloudplatform/internal/payment/qrpayment/repositories"
 	qrpaymentService "smlaicloudplatform/internal/payment/qrpayment/services"
 
@@ -141,8 +142,8 @@ func NewMasterSyncHttp(ms *microservice.Microservice, cfg config.IConfig) Master
 	svcBookBank := bookbankService.NewBookBankHttpService(repoBookBank, masterSyncCacheRepo)
 	activityModuleManager.Add(svcBookBank)
 
-	// Qr Payment
-	qrpaymentRepo := qrpaymentRepo.NewQrPaymentRepository(pst)
+	// Qr Payment, devices call payment provider by the credentials so sync data is decrypted and not redacted
+	qrpaymentRepo := qrpaymentRepo.NewQrPaymentRepository(pst, datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()))
 	svcQrPayment := qrpaymentService.NewQrPaymentHttpService(qrpaymentRepo, masterSyncCacheRepo)
 	activityModuleManager.Add(svcQrPayment)
 
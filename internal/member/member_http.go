@@ -56,8 +56,8 @@ func NewMemberHttp(ms *microservice.Microservice, cfg config.IConfig) MemberHttp
 
 func (h MemberHttp) RegisterLineHttp() {
 	h.ms.POST("/member/line", h.MemberAuthLine)
-	h.ms.GET("/member/profile", h.LineProfileInfo)
-	h.ms.PUT("/member/profile", h.UpdateMemberProfileWithLine)
+	h.ms.GET("/member/profile", h.LineProfileInfo, h.ms.SkipPermission())
+	h.ms.PUT("/member/profile", h.UpdateMemberProfileWithLine, h.ms.SkipPermission())
 }
 
 func (h MemberHttp) RegisterHttp() {
@@ -91,7 +91,7 @@ func (h MemberHttp) MemberAuthLine(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.service.AuthWithLine(payload)
+	idx, err := h.service.AuthWithLine(ctx.Context(), payload)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -130,7 +130,7 @@ func (h MemberHttp) UpdateMemberProfileWithLine(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.service.UpdateProfileWithLine(shopID, lineUID, docReq)
+	err = h.service.UpdateProfileWithLine(ctx.Context(), shopID, lineUID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -157,7 +157,7 @@ func (h MemberHttp) LineProfileInfo(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 	lineUID := userInfo.Username
 
-	doc, err := h.service.LineProfileInfo(shopID, lineUID)
+	doc, err := h.service.LineProfileInfo(ctx.Context(), shopID, lineUID)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
 		ctx.ResponseError(400, err.Error())
@@ -203,7 +203,7 @@ func (h MemberHttp) CreateMember(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.service.Create(shopID, authUsername, doc)
+	idx, err := h.service.Create(ctx.Context(), shopID, authUsername, doc)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -244,7 +244,7 @@ func (h MemberHttp) UpdateMember(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.service.Update(shopID, authUsername, guid, *docReq)
+	err = h.service.Update(ctx.Context(), shopID, authUsername, guid, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -273,7 +273,7 @@ func (h MemberHttp) InfoMember(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	doc, err := h.service.Info(shopID, id)
+	doc, err := h.service.Info(ctx.Context(), shopID, id)
 
 	if err != nil && err.Error() != "mongo: no documents in result" {
 		ctx.ResponseError(400, err.Error())
@@ -314,7 +314,7 @@ func (h MemberHttp) SearchMemberPage(ctx microservice.IContext) error {
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, pagination, err := h.service.SearchMemberInfo(shopID, filters, pageable)
+	docList, pagination, err := h.service.SearchMemberInfo(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -351,7 +351,7 @@ func (h MemberHttp) SearchMemberStep(ctx microservice.IContext) error {
 
 	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{})
 
-	docList, total, err := h.service.SearchMemberStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.service.SearchMemberStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
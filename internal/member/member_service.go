@@ -21,15 +21,15 @@ import (
 )
 
 type IMemberService interface {
-	AuthWithLine(lineAuth models.LineAuthRequest) (string, error)
-	UpdateProfileWithLine(shopID string, lineUID string, doc models.Member) error
-	LineProfileInfo(shopID string, lineUID string) (models.MemberInfo, error)
-
-	Create(shopID string, authUsername string, doc models.Member) (string, error)
-	Update(shopID string, username string, guid string, doc models.Member) error
-	Info(shopID string, guid string) (models.MemberInfo, error)
-	SearchMemberInfo(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MemberInfo, mongopagination.PaginationData, error)
-	SearchMemberStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MemberInfo, int, error)
+	AuthWithLine(ctx context.Context, lineAuth models.LineAuthRequest) (string, error)
+	UpdateProfileWithLine(ctx context.Context, shopID string, lineUID string, doc models.Member) error
+	LineProfileInfo(ctx context.Context, shopID string, lineUID string) (models.MemberInfo, error)
+
+	Create(ctx context.Context, shopID string, authUsername string, doc models.Member) (string, error)
+	Update(ctx context.Context, shopID string, username string, guid string, doc models.Member) error
+	Info(ctx context.Context, shopID string, guid string) (models.MemberInfo, error)
+	SearchMemberInfo(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MemberInfo, mongopagination.PaginationData, error)
+	SearchMemberStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MemberInfo, int, error)
 }
 
 type MemberService struct {
@@ -58,13 +58,13 @@ func NewMemberService(repo IMemberRepository, memberPgRepo IMemberPGRepository,
 	return insSvc
 }
 
-func (svc MemberService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc MemberService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc MemberService) UpdateProfileWithLine(shopID string, lineUID string, doc models.Member) error {
+func (svc MemberService) UpdateProfileWithLine(ctx context.Context, shopID string, lineUID string, doc models.Member) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByLineUID(ctx, lineUID)
@@ -113,9 +113,9 @@ func (svc MemberService) UpdateProfileWithLine(shopID string, lineUID string, do
 	return nil
 }
 
-func (svc MemberService) registerWithLine(shopID string, lineProfile models.LineProfile) (string, error) {
+func (svc MemberService) registerWithLine(ctx context.Context, shopID string, lineProfile models.LineProfile) (string, error) {
 
-	idx, err := svc.create(shopID, lineProfile.UserID, models.MemberTypeLine, models.Member{
+	idx, err := svc.create(ctx, shopID, lineProfile.UserID, models.MemberTypeLine, models.Member{
 		LineUID:    lineProfile.UserID,
 		Name:       lineProfile.DisplayName,
 		PictureUrl: lineProfile.PictureUrl,
@@ -139,9 +139,9 @@ func (svc MemberService) registerWithLine(shopID string, lineProfile models.Line
 	return idx, nil
 }
 
-func (svc MemberService) AuthWithLine(lineAuth models.LineAuthRequest) (string, error) {
+func (svc MemberService) AuthWithLine(ctx context.Context, lineAuth models.LineAuthRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	_, lineProfile, err := svc.LineValidator(lineAuth.LineAccessToken)
@@ -160,7 +160,7 @@ func (svc MemberService) AuthWithLine(lineAuth models.LineAuthRequest) (string,
 	memberName := ""
 
 	if findDoc.GuidFixed == "" {
-		_, err = svc.registerWithLine(lineAuth.ShopID, lineProfile)
+		_, err = svc.registerWithLine(ctx, lineAuth.ShopID, lineProfile)
 
 		if err != nil {
 			return "", err
@@ -285,9 +285,9 @@ func (svc MemberService) LineVerify(lineToken string) (models.LineVerify, error)
 	return lineVerify, nil
 }
 
-func (svc MemberService) LineProfileInfo(shopID string, lineUID string) (models.MemberInfo, error) {
+func (svc MemberService) LineProfileInfo(ctx context.Context, shopID string, lineUID string) (models.MemberInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	doc, err := svc.repo.FindByLineUID(ctx, lineUID)
@@ -299,9 +299,9 @@ func (svc MemberService) LineProfileInfo(shopID string, lineUID string) (models.
 	return doc.MemberInfo, nil
 }
 
-func (svc MemberService) create(shopID string, authUsername string, memberType models.MemberType, doc models.Member) (string, error) {
+func (svc MemberService) create(ctx context.Context, shopID string, authUsername string, memberType models.MemberType, doc models.Member) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	dataDoc := models.MemberDoc{}
@@ -333,10 +333,10 @@ func (svc MemberService) create(shopID string, authUsername string, memberType m
 	return newGuid, nil
 }
 
-func (svc MemberService) Create(shopID string, authUsername string, doc models.Member) (string, error) {
+func (svc MemberService) Create(ctx context.Context, shopID string, authUsername string, doc models.Member) (string, error) {
 
 	doc.LineUID = ""
-	idx, err := svc.create(shopID, authUsername, models.MemberTypeCustomer, doc)
+	idx, err := svc.create(ctx, shopID, authUsername, models.MemberTypeCustomer, doc)
 
 	if err != nil {
 		return "", err
@@ -345,9 +345,9 @@ func (svc MemberService) Create(shopID string, authUsername string, doc models.M
 	return idx, nil
 }
 
-func (svc MemberService) Update(shopID string, username string, guid string, doc models.Member) error {
+func (svc MemberService) Update(ctx context.Context, shopID string, username string, guid string, doc models.Member) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -386,9 +386,9 @@ func (svc MemberService) Update(shopID string, username string, guid string, doc
 	return nil
 }
 
-func (svc MemberService) Info(shopID string, guid string) (models.MemberInfo, error) {
+func (svc MemberService) Info(ctx context.Context, shopID string, guid string) (models.MemberInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	doc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -400,9 +400,9 @@ func (svc MemberService) Info(shopID string, guid string) (models.MemberInfo, er
 	return doc.MemberInfo, nil
 }
 
-func (svc MemberService) SearchMemberInfo(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MemberInfo, mongopagination.PaginationData, error) {
+func (svc MemberService) SearchMemberInfo(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MemberInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -420,9 +420,9 @@ func (svc MemberService) SearchMemberInfo(shopID string, filters map[string]inte
 	return docList, pagination, nil
 }
 
-func (svc MemberService) SearchMemberStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MemberInfo, int, error) {
+func (svc MemberService) SearchMemberStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.MemberInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
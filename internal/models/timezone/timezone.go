@@ -1,6 +1,66 @@
 package timezone
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Timezone struct {
 	TimezoneLabel  string `json:"timezonelabel" bson:"timezonelabel"`
 	TimezoneOffset string `json:"timezoneoffset" bson:"timezoneoffset"`
 }
+
+// Location return location of the timezone, label is IANA name e.g. Asia/Bangkok
+// and offset is hours from UTC e.g. +07:00, +0700, 7 or 5.5,
+// fallback is returned when neither can be parsed
+func (tz Timezone) Location(fallback *time.Location) *time.Location {
+	if tz.TimezoneLabel != "" {
+		if loc, err := time.LoadLocation(tz.TimezoneLabel); err == nil {
+			return loc
+		}
+	}
+
+	if tz.TimezoneOffset != "" {
+		if offset, err := ParseOffset(tz.TimezoneOffset); err == nil {
+			return time.FixedZone(tz.TimezoneOffset, offset)
+		}
+	}
+
+	return fallback
+}
+
+// ParseOffset return seconds east of UTC of offset e.g. +07:00, +0700, -3, 5.5
+func ParseOffset(offset string) (int, error) {
+	value := strings.TrimSpace(offset)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "UTC"), "GMT")
+
+	sign := 1
+	if strings.HasPrefix(value, "-") {
+		sign = -1
+	}
+	value = strings.TrimLeft(value, "+-")
+
+	var hours, minutes float64
+	var err error
+	switch {
+	case strings.Contains(value, ":"):
+		parts := strings.SplitN(value, ":", 2)
+		if hours, err = strconv.ParseFloat(parts[0], 64); err == nil {
+			minutes, err = strconv.ParseFloat(parts[1], 64)
+		}
+	case len(value) == 4 && !strings.Contains(value, "."):
+		if hours, err = strconv.ParseFloat(value[:2], 64); err == nil {
+			minutes, err = strconv.ParseFloat(value[2:], 64)
+		}
+	default:
+		hours, err = strconv.ParseFloat(value, 64)
+	}
+
+	if err != nil || hours > 14 || minutes >= 60 {
+		return 0, fmt.Errorf("invalid timezone offset %q", offset)
+	}
+
+	return sign * int(hours*3600+minutes*60), nil
+}
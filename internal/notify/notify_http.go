@@ -78,7 +78,7 @@ func (h NotifyHttp) CreateNotify(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateNotify(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateNotify(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h NotifyHttp) UpdateNotify(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateNotify(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateNotify(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h NotifyHttp) DeleteNotify(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteNotify(shopID, id, authUsername)
+	err := h.svc.DeleteNotify(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h NotifyHttp) DeleteNotifyByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteNotifyByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteNotifyByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h NotifyHttp) InfoNotify(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Notify %v", id)
-	doc, err := h.svc.InfoNotify(shopID, id)
+	doc, err := h.svc.InfoNotify(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h NotifyHttp) InfoNotifyByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoNotifyByCode(shopID, code)
+	doc, err := h.svc.InfoNotifyByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -298,7 +298,7 @@ func (h NotifyHttp) SearchNotifyPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchNotifyInfo(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchNotifyInfo(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -347,7 +347,7 @@ func (h NotifyHttp) SearchNotifyStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchNotifyStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchNotifyStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
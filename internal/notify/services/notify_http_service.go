@@ -9,6 +9,7 @@ import (
 	"smlaicloudplatform/internal/notify/repositories"
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,15 +18,15 @@ import (
 )
 
 type INotifyHttpService interface {
-	CreateNotify(shopID string, authUsername string, doc models.NotifyRequest) (string, error)
-	UpdateNotify(shopID string, guid string, authUsername string, doc models.NotifyRequest) error
-	DeleteNotify(shopID string, guid string, authUsername string) error
-	DeleteNotifyByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoNotify(shopID string, guid string) (models.NotifyInfo, error)
-	InfoNotifyByCode(shopID string, code string) (models.NotifyInfo, error)
-	SearchNotify(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.Notify, mongopagination.PaginationData, error)
-	SearchNotifyInfo(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifyInfo, mongopagination.PaginationData, error)
-	SearchNotifyStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.NotifyInfo, int, error)
+	CreateNotify(ctx context.Context, shopID string, authUsername string, doc models.NotifyRequest) (string, error)
+	UpdateNotify(ctx context.Context, shopID string, guid string, authUsername string, doc models.NotifyRequest) error
+	DeleteNotify(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteNotifyByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoNotify(ctx context.Context, shopID string, guid string) (models.NotifyInfo, error)
+	InfoNotifyByCode(ctx context.Context, shopID string, code string) (models.NotifyInfo, error)
+	SearchNotify(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.Notify, mongopagination.PaginationData, error)
+	SearchNotifyInfo(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifyInfo, mongopagination.PaginationData, error)
+	SearchNotifyStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.NotifyInfo, int, error)
 
 	GetModuleName() string
 }
@@ -57,13 +58,13 @@ func NewNotifyHttpService(
 	return insSvc
 }
 
-func (svc NotifyHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc NotifyHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc NotifyHttpService) CreateNotify(shopID string, authUsername string, doc models.NotifyRequest) (string, error) {
+func (svc NotifyHttpService) CreateNotify(ctx context.Context, shopID string, authUsername string, doc models.NotifyRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -90,9 +91,9 @@ func (svc NotifyHttpService) CreateNotify(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc NotifyHttpService) UpdateNotify(shopID string, guid string, authUsername string, doc models.NotifyRequest) error {
+func (svc NotifyHttpService) UpdateNotify(ctx context.Context, shopID string, guid string, authUsername string, doc models.NotifyRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,9 +126,9 @@ func (svc NotifyHttpService) UpdateNotify(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc NotifyHttpService) DeleteNotify(shopID string, guid string, authUsername string) error {
+func (svc NotifyHttpService) DeleteNotify(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -152,9 +153,9 @@ func (svc NotifyHttpService) DeleteNotify(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc NotifyHttpService) DeleteNotifyByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc NotifyHttpService) DeleteNotifyByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -173,9 +174,9 @@ func (svc NotifyHttpService) DeleteNotifyByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc NotifyHttpService) InfoNotify(shopID string, guid string) (models.NotifyInfo, error) {
+func (svc NotifyHttpService) InfoNotify(ctx context.Context, shopID string, guid string) (models.NotifyInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -191,9 +192,9 @@ func (svc NotifyHttpService) InfoNotify(shopID string, guid string) (models.Noti
 	return findDoc.NotifyInfo, nil
 }
 
-func (svc NotifyHttpService) InfoNotifyByCode(shopID string, code string) (models.NotifyInfo, error) {
+func (svc NotifyHttpService) InfoNotifyByCode(ctx context.Context, shopID string, code string) (models.NotifyInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "uid", code)
@@ -209,9 +210,9 @@ func (svc NotifyHttpService) InfoNotifyByCode(shopID string, code string) (model
 	return findDoc.NotifyInfo, nil
 }
 
-func (svc NotifyHttpService) SearchNotify(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.Notify, mongopagination.PaginationData, error) {
+func (svc NotifyHttpService) SearchNotify(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.Notify, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -232,9 +233,9 @@ func (svc NotifyHttpService) SearchNotify(shopID string, filters map[string]inte
 	return tempResults, pagination, nil
 }
 
-func (svc NotifyHttpService) SearchNotifyInfo(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifyInfo, mongopagination.PaginationData, error) {
+func (svc NotifyHttpService) SearchNotifyInfo(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifyInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -250,9 +251,9 @@ func (svc NotifyHttpService) SearchNotifyInfo(shopID string, filters map[string]
 	return docList, pagination, nil
 }
 
-func (svc NotifyHttpService) SearchNotifyStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.NotifyInfo, int, error) {
+func (svc NotifyHttpService) SearchNotifyStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.NotifyInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -79,7 +79,7 @@ func (h DeviceHttp) CreateDevice(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDevice(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDevice(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -124,7 +124,7 @@ func (h DeviceHttp) UpdateDevice(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDevice(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDevice(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -155,7 +155,7 @@ func (h DeviceHttp) DeleteDevice(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDevice(shopID, id, authUsername)
+	err := h.svc.DeleteDevice(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -194,7 +194,7 @@ func (h DeviceHttp) DeleteDeviceByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteDeviceByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDeviceByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -224,7 +224,7 @@ func (h DeviceHttp) InfoDevice(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Device %v", id)
-	doc, err := h.svc.InfoDevice(shopID, id)
+	doc, err := h.svc.InfoDevice(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h DeviceHttp) InfoDeviceByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoDeviceByCode(shopID, code)
+	doc, err := h.svc.InfoDeviceByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -285,7 +285,7 @@ func (h DeviceHttp) SearchDevicePage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchDevice(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchDevice(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -320,7 +320,7 @@ func (h DeviceHttp) SearchDeviceStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchDeviceStep(shopID, lang, map[string]interface{}{}, pageableStep)
+	docList, total, err := h.svc.SearchDeviceStep(ctx.Context(), shopID, lang, map[string]interface{}{}, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -360,7 +360,7 @@ func (h DeviceHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IDeviceHttpService interface {
-	CreateDevice(shopID string, authUsername string, doc models.OrderDevice) (string, error)
-	UpdateDevice(shopID string, guid string, authUsername string, doc models.OrderDevice) error
-	DeleteDevice(shopID string, guid string, authUsername string) error
-	DeleteDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDevice(shopID string, guid string) (models.OrderDeviceInfo, error)
-	InfoDeviceByCode(shopID string, code string) (models.OrderDeviceInfo, error)
-	SearchDevice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderDeviceInfo, mongopagination.PaginationData, error)
-	SearchDeviceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.OrderDeviceInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.OrderDevice) (common.BulkImport, error)
+	CreateDevice(ctx context.Context, shopID string, authUsername string, doc models.OrderDevice) (string, error)
+	UpdateDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.OrderDevice) error
+	DeleteDevice(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDevice(ctx context.Context, shopID string, guid string) (models.OrderDeviceInfo, error)
+	InfoDeviceByCode(ctx context.Context, shopID string, code string) (models.OrderDeviceInfo, error)
+	SearchDevice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderDeviceInfo, mongopagination.PaginationData, error)
+	SearchDeviceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.OrderDeviceInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OrderDevice) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -59,13 +60,13 @@ func NewDeviceHttpService(
 	return insSvc
 }
 
-func (svc DeviceHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DeviceHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DeviceHttpService) CreateDevice(shopID string, authUsername string, doc models.OrderDevice) (string, error) {
+func (svc DeviceHttpService) CreateDevice(ctx context.Context, shopID string, authUsername string, doc models.OrderDevice) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -101,9 +102,9 @@ func (svc DeviceHttpService) CreateDevice(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc DeviceHttpService) UpdateDevice(shopID string, guid string, authUsername string, doc models.OrderDevice) error {
+func (svc DeviceHttpService) UpdateDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.OrderDevice) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -134,9 +135,9 @@ func (svc DeviceHttpService) UpdateDevice(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DeviceHttpService) DeleteDevice(shopID string, guid string, authUsername string) error {
+func (svc DeviceHttpService) DeleteDevice(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc DeviceHttpService) DeleteDevice(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DeviceHttpService) DeleteDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DeviceHttpService) DeleteDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -182,9 +183,9 @@ func (svc DeviceHttpService) DeleteDeviceByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc DeviceHttpService) InfoDevice(shopID string, guid string) (models.OrderDeviceInfo, error) {
+func (svc DeviceHttpService) InfoDevice(ctx context.Context, shopID string, guid string) (models.OrderDeviceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -200,9 +201,9 @@ func (svc DeviceHttpService) InfoDevice(shopID string, guid string) (models.Orde
 	return findDoc.OrderDeviceInfo, nil
 }
 
-func (svc DeviceHttpService) InfoDeviceByCode(shopID string, code string) (models.OrderDeviceInfo, error) {
+func (svc DeviceHttpService) InfoDeviceByCode(ctx context.Context, shopID string, code string) (models.OrderDeviceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "id", code)
@@ -218,9 +219,9 @@ func (svc DeviceHttpService) InfoDeviceByCode(shopID string, code string) (model
 	return findDoc.OrderDeviceInfo, nil
 }
 
-func (svc DeviceHttpService) SearchDevice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderDeviceInfo, mongopagination.PaginationData, error) {
+func (svc DeviceHttpService) SearchDevice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderDeviceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -236,9 +237,9 @@ func (svc DeviceHttpService) SearchDevice(shopID string, filters map[string]inte
 	return docList, pagination, nil
 }
 
-func (svc DeviceHttpService) SearchDeviceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.OrderDeviceInfo, int, error) {
+func (svc DeviceHttpService) SearchDeviceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.OrderDeviceInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -264,9 +265,9 @@ func (svc DeviceHttpService) SearchDeviceStep(shopID string, langCode string, fi
 	return docList, total, nil
 }
 
-func (svc DeviceHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.OrderDevice) (common.BulkImport, error) {
+func (svc DeviceHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OrderDevice) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.OrderDevice](dataList, svc.getDocIDKey)
@@ -84,7 +84,7 @@ func (h BranchHttp) CreateBranch(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateBranch(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateBranch(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -129,7 +129,7 @@ func (h BranchHttp) UpdateBranch(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateBranch(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateBranch(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -160,7 +160,7 @@ func (h BranchHttp) DeleteBranch(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBranch(shopID, id, authUsername)
+	err := h.svc.DeleteBranch(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -199,7 +199,7 @@ func (h BranchHttp) DeleteBranchByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteBranchByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteBranchByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -229,7 +229,7 @@ func (h BranchHttp) InfoBranch(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Branch %v", id)
-	doc, err := h.svc.InfoBranch(shopID, id)
+	doc, err := h.svc.InfoBranch(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -259,7 +259,7 @@ func (h BranchHttp) InfoBranchByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoBranchByCode(shopID, code)
+	doc, err := h.svc.InfoBranchByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -299,7 +299,7 @@ func (h BranchHttp) SearchBranchPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchBranch(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchBranch(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -343,7 +343,7 @@ func (h BranchHttp) SearchBranchStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchBranchStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchBranchStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -383,7 +383,7 @@ func (h BranchHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type IBranchHttpService interface {
-	CreateBranch(shopID string, authUsername string, doc models.Branch) (string, error)
-	UpdateBranch(shopID string, guid string, authUsername string, doc models.Branch) error
-	DeleteBranch(shopID string, guid string, authUsername string) error
-	DeleteBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoBranch(shopID string, guid string) (models.BranchInfoResponse, error)
-	InfoBranchByCode(shopID string, code string) (models.BranchInfoResponse, error)
-	SearchBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfoResponse, mongopagination.PaginationData, error)
-	SearchBranchStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.BranchInfoResponse, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Branch) (common.BulkImport, error)
+	CreateBranch(ctx context.Context, shopID string, authUsername string, doc models.Branch) (string, error)
+	UpdateBranch(ctx context.Context, shopID string, guid string, authUsername string, doc models.Branch) error
+	DeleteBranch(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoBranch(ctx context.Context, shopID string, guid string) (models.BranchInfoResponse, error)
+	InfoBranchByCode(ctx context.Context, shopID string, code string) (models.BranchInfoResponse, error)
+	SearchBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfoResponse, mongopagination.PaginationData, error)
+	SearchBranchStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.BranchInfoResponse, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Branch) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -61,13 +62,13 @@ func NewBranchHttpService(repo repositories.IBranchRepository, repoDepartment de
 	return insSvc
 }
 
-func (svc BranchHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BranchHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BranchHttpService) CreateBranch(shopID string, authUsername string, doc models.Branch) (string, error) {
+func (svc BranchHttpService) CreateBranch(ctx context.Context, shopID string, authUsername string, doc models.Branch) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneFilter(
@@ -120,9 +121,9 @@ func (svc BranchHttpService) CreateBranch(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc BranchHttpService) UpdateBranch(shopID string, guid string, authUsername string, doc models.Branch) error {
+func (svc BranchHttpService) UpdateBranch(ctx context.Context, shopID string, guid string, authUsername string, doc models.Branch) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -166,9 +167,9 @@ func (svc BranchHttpService) UpdateBranch(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc BranchHttpService) DeleteBranch(shopID string, guid string, authUsername string) error {
+func (svc BranchHttpService) DeleteBranch(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -191,9 +192,9 @@ func (svc BranchHttpService) DeleteBranch(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc BranchHttpService) DeleteBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc BranchHttpService) DeleteBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -208,9 +209,9 @@ func (svc BranchHttpService) DeleteBranchByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc BranchHttpService) InfoBranch(shopID string, guid string) (models.BranchInfoResponse, error) {
+func (svc BranchHttpService) InfoBranch(ctx context.Context, shopID string, guid string) (models.BranchInfoResponse, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -223,7 +224,7 @@ func (svc BranchHttpService) InfoBranch(shopID string, guid string) (models.Bran
 		return models.BranchInfoResponse{}, errors.New("document not found")
 	}
 
-	resultDoc, err := svc.mapBranchInfo(findDoc.BranchInfo, shopID)
+	resultDoc, err := svc.mapBranchInfo(ctx, findDoc.BranchInfo, shopID)
 
 	if err != nil {
 		return models.BranchInfoResponse{}, err
@@ -232,9 +233,9 @@ func (svc BranchHttpService) InfoBranch(shopID string, guid string) (models.Bran
 	return resultDoc, nil
 }
 
-func (svc BranchHttpService) mapBranchInfo(findInfo models.BranchInfo, shopID string) (models.BranchInfoResponse, error) {
+func (svc BranchHttpService) mapBranchInfo(ctx context.Context, findInfo models.BranchInfo, shopID string) (models.BranchInfoResponse, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if findInfo.Branch.Departments == nil {
@@ -275,9 +276,9 @@ func (svc BranchHttpService) mapBranchInfo(findInfo models.BranchInfo, shopID st
 	return resultDoc, nil
 }
 
-func (svc BranchHttpService) mapDepartmentToBranch(shopID string, departmentGUIDs []string) ([]models.Department, error) {
+func (svc BranchHttpService) mapDepartmentToBranch(ctx context.Context, shopID string, departmentGUIDs []string) ([]models.Department, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	departments := []models.Department{}
@@ -300,9 +301,9 @@ func (svc BranchHttpService) mapDepartmentToBranch(shopID string, departmentGUID
 	return departments, nil
 }
 
-func (svc BranchHttpService) InfoBranchByCode(shopID string, code string) (models.BranchInfoResponse, error) {
+func (svc BranchHttpService) InfoBranchByCode(ctx context.Context, shopID string, code string) (models.BranchInfoResponse, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -315,7 +316,7 @@ func (svc BranchHttpService) InfoBranchByCode(shopID string, code string) (model
 		return models.BranchInfoResponse{}, errors.New("document not found")
 	}
 
-	resultDoc, err := svc.mapBranchInfo(findDoc.BranchInfo, shopID)
+	resultDoc, err := svc.mapBranchInfo(ctx, findDoc.BranchInfo, shopID)
 
 	if err != nil {
 		return models.BranchInfoResponse{}, err
@@ -324,9 +325,9 @@ func (svc BranchHttpService) InfoBranchByCode(shopID string, code string) (model
 	return resultDoc, nil
 }
 
-func (svc BranchHttpService) SearchBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfoResponse, mongopagination.PaginationData, error) {
+func (svc BranchHttpService) SearchBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfoResponse, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -343,7 +344,7 @@ func (svc BranchHttpService) SearchBranch(shopID string, filters map[string]inte
 	resultDocs := []models.BranchInfoResponse{}
 	for _, docInfo := range docList {
 
-		resultDoc, err := svc.mapBranchInfo(docInfo, shopID)
+		resultDoc, err := svc.mapBranchInfo(ctx, docInfo, shopID)
 
 		if err != nil {
 			return []models.BranchInfoResponse{}, pagination, err
@@ -355,9 +356,9 @@ func (svc BranchHttpService) SearchBranch(shopID string, filters map[string]inte
 	return resultDocs, pagination, nil
 }
 
-func (svc BranchHttpService) SearchBranchStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.BranchInfoResponse, int, error) {
+func (svc BranchHttpService) SearchBranchStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.BranchInfoResponse, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -376,7 +377,7 @@ func (svc BranchHttpService) SearchBranchStep(shopID string, langCode string, fi
 	resultDocs := []models.BranchInfoResponse{}
 	for _, docInfo := range docList {
 
-		resultDoc, err := svc.mapBranchInfo(docInfo, shopID)
+		resultDoc, err := svc.mapBranchInfo(ctx, docInfo, shopID)
 
 		if err != nil {
 			return []models.BranchInfoResponse{}, 0, err
@@ -388,9 +389,9 @@ func (svc BranchHttpService) SearchBranchStep(shopID string, langCode string, fi
 	return resultDocs, total, nil
 }
 
-func (svc BranchHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Branch) (common.BulkImport, error) {
+func (svc BranchHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Branch) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Branch](dataList, svc.getDocIDKey)
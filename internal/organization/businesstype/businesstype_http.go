@@ -79,7 +79,7 @@ func (h BusinessTypeHttp) CreateBusinessType(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateBusinessType(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateBusinessType(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -124,7 +124,7 @@ func (h BusinessTypeHttp) UpdateBusinessType(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateBusinessType(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateBusinessType(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -155,7 +155,7 @@ func (h BusinessTypeHttp) DeleteBusinessType(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBusinessType(shopID, id, authUsername)
+	err := h.svc.DeleteBusinessType(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -194,7 +194,7 @@ func (h BusinessTypeHttp) DeleteBusinessTypeByGUIDs(ctx microservice.IContext) e
 		return err
 	}
 
-	err = h.svc.DeleteBusinessTypeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteBusinessTypeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -224,7 +224,7 @@ func (h BusinessTypeHttp) InfoBusinessType(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get BusinessType %v", id)
-	doc, err := h.svc.InfoBusinessType(shopID, id)
+	doc, err := h.svc.InfoBusinessType(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -251,7 +251,7 @@ func (h BusinessTypeHttp) InfoBusinessTypeDefault(ctx microservice.IContext) err
 	userInfo := ctx.UserInfo()
 	shopID := userInfo.ShopID
 
-	doc, err := h.svc.InfoBusinessTypeDefault(shopID)
+	doc, err := h.svc.InfoBusinessTypeDefault(ctx.Context(), shopID)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -280,7 +280,7 @@ func (h BusinessTypeHttp) InfoBusinessTypeByCode(ctx microservice.IContext) erro
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoBusinessTypeByCode(shopID, code)
+	doc, err := h.svc.InfoBusinessTypeByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -311,7 +311,7 @@ func (h BusinessTypeHttp) SearchBusinessTypePage(ctx microservice.IContext) erro
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchBusinessType(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchBusinessType(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -346,7 +346,7 @@ func (h BusinessTypeHttp) SearchBusinessTypeStep(ctx microservice.IContext) erro
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchBusinessTypeStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchBusinessTypeStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -386,7 +386,7 @@ func (h BusinessTypeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
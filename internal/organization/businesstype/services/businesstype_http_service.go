@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 
 	"time"
@@ -20,17 +21,17 @@ import (
 )
 
 type IBusinessTypeHttpService interface {
-	CreateBusinessType(shopID string, authUsername string, doc models.BusinessType) (string, error)
-	UpdateBusinessType(shopID string, guid string, authUsername string, doc models.BusinessType) error
-	DeleteBusinessType(shopID string, guid string, authUsername string) error
-	DeleteBusinessTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoBusinessType(shopID string, guid string) (models.BusinessTypeInfo, error)
-	InfoBusinessTypeByCode(shopID string, code string) (models.BusinessTypeInfo, error)
-	SearchBusinessType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BusinessTypeInfo, mongopagination.PaginationData, error)
-	SearchBusinessTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BusinessTypeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.BusinessType) (common.BulkImport, error)
-
-	InfoBusinessTypeDefault(shopID string) (models.BusinessTypeInfo, error)
+	CreateBusinessType(ctx context.Context, shopID string, authUsername string, doc models.BusinessType) (string, error)
+	UpdateBusinessType(ctx context.Context, shopID string, guid string, authUsername string, doc models.BusinessType) error
+	DeleteBusinessType(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteBusinessTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoBusinessType(ctx context.Context, shopID string, guid string) (models.BusinessTypeInfo, error)
+	InfoBusinessTypeByCode(ctx context.Context, shopID string, code string) (models.BusinessTypeInfo, error)
+	SearchBusinessType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BusinessTypeInfo, mongopagination.PaginationData, error)
+	SearchBusinessTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BusinessTypeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BusinessType) (common.BulkImport, error)
+
+	InfoBusinessTypeDefault(ctx context.Context, shopID string) (models.BusinessTypeInfo, error)
 
 	GetModuleName() string
 }
@@ -58,13 +59,13 @@ func NewBusinessTypeHttpService(repo repositories.IBusinessTypeRepository, syncC
 	return insSvc
 }
 
-func (svc BusinessTypeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BusinessTypeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BusinessTypeHttpService) CreateBusinessType(shopID string, authUsername string, doc models.BusinessType) (string, error) {
+func (svc BusinessTypeHttpService) CreateBusinessType(ctx context.Context, shopID string, authUsername string, doc models.BusinessType) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// Check code is exists
@@ -118,9 +119,9 @@ func (svc BusinessTypeHttpService) CreateBusinessType(shopID string, authUsernam
 
 	return newGuidFixed, nil
 }
-func (svc BusinessTypeHttpService) UpdateBusinessType(shopID string, guid string, authUsername string, doc models.BusinessType) error {
+func (svc BusinessTypeHttpService) UpdateBusinessType(ctx context.Context, shopID string, guid string, authUsername string, doc models.BusinessType) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// Find the business type by the given guid.
@@ -167,9 +168,9 @@ func (svc BusinessTypeHttpService) UpdateBusinessType(shopID string, guid string
 	return nil
 }
 
-func (svc BusinessTypeHttpService) DeleteBusinessType(shopID string, guid string, authUsername string) error {
+func (svc BusinessTypeHttpService) DeleteBusinessType(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -193,9 +194,9 @@ func (svc BusinessTypeHttpService) DeleteBusinessType(shopID string, guid string
 	return nil
 }
 
-func (svc BusinessTypeHttpService) DeleteBusinessTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc BusinessTypeHttpService) DeleteBusinessTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -210,9 +211,9 @@ func (svc BusinessTypeHttpService) DeleteBusinessTypeByGUIDs(shopID string, auth
 	return nil
 }
 
-func (svc BusinessTypeHttpService) InfoBusinessTypeDefault(shopID string) (models.BusinessTypeInfo, error) {
+func (svc BusinessTypeHttpService) InfoBusinessTypeDefault(ctx context.Context, shopID string) (models.BusinessTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.GetDefault(ctx, shopID)
@@ -228,9 +229,9 @@ func (svc BusinessTypeHttpService) InfoBusinessTypeDefault(shopID string) (model
 	return findDoc.BusinessTypeInfo, nil
 }
 
-func (svc BusinessTypeHttpService) InfoBusinessType(shopID string, guid string) (models.BusinessTypeInfo, error) {
+func (svc BusinessTypeHttpService) InfoBusinessType(ctx context.Context, shopID string, guid string) (models.BusinessTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -246,9 +247,9 @@ func (svc BusinessTypeHttpService) InfoBusinessType(shopID string, guid string)
 	return findDoc.BusinessTypeInfo, nil
 }
 
-func (svc BusinessTypeHttpService) InfoBusinessTypeByCode(shopID string, code string) (models.BusinessTypeInfo, error) {
+func (svc BusinessTypeHttpService) InfoBusinessTypeByCode(ctx context.Context, shopID string, code string) (models.BusinessTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -264,9 +265,9 @@ func (svc BusinessTypeHttpService) InfoBusinessTypeByCode(shopID string, code st
 	return findDoc.BusinessTypeInfo, nil
 }
 
-func (svc BusinessTypeHttpService) SearchBusinessType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BusinessTypeInfo, mongopagination.PaginationData, error) {
+func (svc BusinessTypeHttpService) SearchBusinessType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BusinessTypeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -282,9 +283,9 @@ func (svc BusinessTypeHttpService) SearchBusinessType(shopID string, filters map
 	return docList, pagination, nil
 }
 
-func (svc BusinessTypeHttpService) SearchBusinessTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BusinessTypeInfo, int, error) {
+func (svc BusinessTypeHttpService) SearchBusinessTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BusinessTypeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -302,9 +303,9 @@ func (svc BusinessTypeHttpService) SearchBusinessTypeStep(shopID string, langCod
 	return docList, total, nil
 }
 
-func (svc BusinessTypeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.BusinessType) (common.BulkImport, error) {
+func (svc BusinessTypeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BusinessType) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.BusinessType](dataList, svc.getDocIDKey)
@@ -84,7 +84,7 @@ func (h CompanyHttp) CreateCompany(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateCompany(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateCompany(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -129,7 +129,7 @@ func (h CompanyHttp) UpdateCompany(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateCompany(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateCompany(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -160,7 +160,7 @@ func (h CompanyHttp) DeleteCompany(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteCompany(shopID, id, authUsername)
+	err := h.svc.DeleteCompany(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -199,7 +199,7 @@ func (h CompanyHttp) DeleteCompanyByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteCompanyByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteCompanyByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -229,7 +229,7 @@ func (h CompanyHttp) InfoCompany(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Company %v", id)
-	doc, err := h.svc.InfoCompany(shopID, id)
+	doc, err := h.svc.InfoCompany(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -259,7 +259,7 @@ func (h CompanyHttp) InfoCompanyByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoCompanyByCode(shopID, code)
+	doc, err := h.svc.InfoCompanyByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -299,7 +299,7 @@ func (h CompanyHttp) SearchCompanyPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchCompany(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchCompany(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -343,7 +343,7 @@ func (h CompanyHttp) SearchCompanyStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchCompanyStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchCompanyStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -383,7 +383,7 @@ func (h CompanyHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
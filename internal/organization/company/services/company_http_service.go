@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type ICompanyHttpService interface {
-	CreateCompany(shopID string, authUsername string, doc models.Company) (string, error)
-	UpdateCompany(shopID string, guid string, authUsername string, doc models.Company) error
-	DeleteCompany(shopID string, guid string, authUsername string) error
-	DeleteCompanyByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoCompany(shopID string, guid string) (models.CompanyInfoResponse, error)
-	InfoCompanyByCode(shopID string, code string) (models.CompanyInfoResponse, error)
-	SearchCompany(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CompanyInfoResponse, mongopagination.PaginationData, error)
-	SearchCompanyStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CompanyInfoResponse, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Company) (common.BulkImport, error)
+	CreateCompany(ctx context.Context, shopID string, authUsername string, doc models.Company) (string, error)
+	UpdateCompany(ctx context.Context, shopID string, guid string, authUsername string, doc models.Company) error
+	DeleteCompany(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteCompanyByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoCompany(ctx context.Context, shopID string, guid string) (models.CompanyInfoResponse, error)
+	InfoCompanyByCode(ctx context.Context, shopID string, code string) (models.CompanyInfoResponse, error)
+	SearchCompany(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CompanyInfoResponse, mongopagination.PaginationData, error)
+	SearchCompanyStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CompanyInfoResponse, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Company) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -61,13 +62,13 @@ func NewCompanyHttpService(repo repositories.ICompanyRepository, repoDepartment
 	return insSvc
 }
 
-func (svc CompanyHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc CompanyHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc CompanyHttpService) CreateCompany(shopID string, authUsername string, doc models.Company) (string, error) {
+func (svc CompanyHttpService) CreateCompany(ctx context.Context, shopID string, authUsername string, doc models.Company) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneFilter(
@@ -106,9 +107,9 @@ func (svc CompanyHttpService) CreateCompany(shopID string, authUsername string,
 	return newGuidFixed, nil
 }
 
-func (svc CompanyHttpService) UpdateCompany(shopID string, guid string, authUsername string, doc models.Company) error {
+func (svc CompanyHttpService) UpdateCompany(ctx context.Context, shopID string, guid string, authUsername string, doc models.Company) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -140,9 +141,9 @@ func (svc CompanyHttpService) UpdateCompany(shopID string, guid string, authUser
 	return nil
 }
 
-func (svc CompanyHttpService) DeleteCompany(shopID string, guid string, authUsername string) error {
+func (svc CompanyHttpService) DeleteCompany(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -165,9 +166,9 @@ func (svc CompanyHttpService) DeleteCompany(shopID string, guid string, authUser
 	return nil
 }
 
-func (svc CompanyHttpService) DeleteCompanyByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc CompanyHttpService) DeleteCompanyByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -182,9 +183,9 @@ func (svc CompanyHttpService) DeleteCompanyByGUIDs(shopID string, authUsername s
 	return nil
 }
 
-func (svc CompanyHttpService) InfoCompany(shopID string, guid string) (models.CompanyInfoResponse, error) {
+func (svc CompanyHttpService) InfoCompany(ctx context.Context, shopID string, guid string) (models.CompanyInfoResponse, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -214,9 +215,9 @@ func (svc CompanyHttpService) mapCompanyInfo(findInfo models.CompanyInfo, shopID
 	return resultDoc, nil
 }
 
-func (svc CompanyHttpService) InfoCompanyByCode(shopID string, code string) (models.CompanyInfoResponse, error) {
+func (svc CompanyHttpService) InfoCompanyByCode(ctx context.Context, shopID string, code string) (models.CompanyInfoResponse, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -238,9 +239,9 @@ func (svc CompanyHttpService) InfoCompanyByCode(shopID string, code string) (mod
 	return resultDoc, nil
 }
 
-func (svc CompanyHttpService) SearchCompany(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CompanyInfoResponse, mongopagination.PaginationData, error) {
+func (svc CompanyHttpService) SearchCompany(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.CompanyInfoResponse, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -269,9 +270,9 @@ func (svc CompanyHttpService) SearchCompany(shopID string, filters map[string]in
 	return resultDocs, pagination, nil
 }
 
-func (svc CompanyHttpService) SearchCompanyStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CompanyInfoResponse, int, error) {
+func (svc CompanyHttpService) SearchCompanyStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.CompanyInfoResponse, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -302,9 +303,9 @@ func (svc CompanyHttpService) SearchCompanyStep(shopID string, langCode string,
 	return resultDocs, total, nil
 }
 
-func (svc CompanyHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Company) (common.BulkImport, error) {
+func (svc CompanyHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Company) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Company](dataList, svc.getDocIDKey)
@@ -78,7 +78,7 @@ func (h DepartmentHttp) CreateDepartment(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDepartment(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDepartment(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h DepartmentHttp) UpdateDepartment(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDepartment(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDepartment(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h DepartmentHttp) DeleteDepartment(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDepartment(shopID, id, authUsername)
+	err := h.svc.DeleteDepartment(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h DepartmentHttp) DeleteDepartmentByGUIDs(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.DeleteDepartmentByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDepartmentByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h DepartmentHttp) InfoDepartment(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Department %v", id)
-	doc, err := h.svc.InfoDepartment(shopID, id)
+	doc, err := h.svc.InfoDepartment(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -255,7 +255,7 @@ func (h DepartmentHttp) InfoDepartmentByCode(ctx microservice.IContext) error {
 	branchCode := ctx.Param("branchCode")
 	departmentCode := ctx.Param("departmentCode")
 
-	doc, err := h.svc.InfoDepartmentByCode(shopID, branchCode, departmentCode)
+	doc, err := h.svc.InfoDepartmentByCode(ctx.Context(), shopID, branchCode, departmentCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -286,7 +286,7 @@ func (h DepartmentHttp) SearchDepartmentPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchDepartment(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchDepartment(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -321,7 +321,7 @@ func (h DepartmentHttp) SearchDepartmentStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchDepartmentStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchDepartmentStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -361,7 +361,7 @@ func (h DepartmentHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IDepartmentHttpService interface {
-	CreateDepartment(shopID string, authUsername string, doc models.Department) (string, error)
-	UpdateDepartment(shopID string, guid string, authUsername string, doc models.Department) error
-	DeleteDepartment(shopID string, guid string, authUsername string) error
-	DeleteDepartmentByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDepartment(shopID string, guid string) (models.DepartmentInfo, error)
-	InfoDepartmentByCode(shopID, branchCode, departmentCode string) (models.DepartmentInfo, error)
-	SearchDepartment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DepartmentInfo, mongopagination.PaginationData, error)
-	SearchDepartmentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DepartmentInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Department) (common.BulkImport, error)
+	CreateDepartment(ctx context.Context, shopID string, authUsername string, doc models.Department) (string, error)
+	UpdateDepartment(ctx context.Context, shopID string, guid string, authUsername string, doc models.Department) error
+	DeleteDepartment(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDepartmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDepartment(ctx context.Context, shopID string, guid string) (models.DepartmentInfo, error)
+	InfoDepartmentByCode(ctx context.Context, shopID, branchCode, departmentCode string) (models.DepartmentInfo, error)
+	SearchDepartment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DepartmentInfo, mongopagination.PaginationData, error)
+	SearchDepartmentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DepartmentInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Department) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewDepartmentHttpService(repo repositories.IDepartmentRepository, syncCache
 	return insSvc
 }
 
-func (svc DepartmentHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DepartmentHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DepartmentHttpService) CreateDepartment(shopID string, authUsername string, doc models.Department) (string, error) {
+func (svc DepartmentHttpService) CreateDepartment(ctx context.Context, shopID string, authUsername string, doc models.Department) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc DepartmentHttpService) CreateDepartment(shopID string, authUsername st
 	return newGuidFixed, nil
 }
 
-func (svc DepartmentHttpService) UpdateDepartment(shopID string, guid string, authUsername string, doc models.Department) error {
+func (svc DepartmentHttpService) UpdateDepartment(ctx context.Context, shopID string, guid string, authUsername string, doc models.Department) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc DepartmentHttpService) UpdateDepartment(shopID string, guid string, au
 	return nil
 }
 
-func (svc DepartmentHttpService) DeleteDepartment(shopID string, guid string, authUsername string) error {
+func (svc DepartmentHttpService) DeleteDepartment(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc DepartmentHttpService) DeleteDepartment(shopID string, guid string, au
 	return nil
 }
 
-func (svc DepartmentHttpService) DeleteDepartmentByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DepartmentHttpService) DeleteDepartmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +169,9 @@ func (svc DepartmentHttpService) DeleteDepartmentByGUIDs(shopID string, authUser
 	return nil
 }
 
-func (svc DepartmentHttpService) InfoDepartment(shopID string, guid string) (models.DepartmentInfo, error) {
+func (svc DepartmentHttpService) InfoDepartment(ctx context.Context, shopID string, guid string) (models.DepartmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -186,9 +187,9 @@ func (svc DepartmentHttpService) InfoDepartment(shopID string, guid string) (mod
 	return findDoc.DepartmentInfo, nil
 }
 
-func (svc DepartmentHttpService) InfoDepartmentByCode(shopID, branchCode, departmentCode string) (models.DepartmentInfo, error) {
+func (svc DepartmentHttpService) InfoDepartmentByCode(ctx context.Context, shopID, branchCode, departmentCode string) (models.DepartmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneByCode(ctx, shopID, branchCode, departmentCode)
@@ -204,9 +205,9 @@ func (svc DepartmentHttpService) InfoDepartmentByCode(shopID, branchCode, depart
 	return findDoc.DepartmentInfo, nil
 }
 
-func (svc DepartmentHttpService) SearchDepartment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DepartmentInfo, mongopagination.PaginationData, error) {
+func (svc DepartmentHttpService) SearchDepartment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DepartmentInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -222,9 +223,9 @@ func (svc DepartmentHttpService) SearchDepartment(shopID string, filters map[str
 	return docList, pagination, nil
 }
 
-func (svc DepartmentHttpService) SearchDepartmentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DepartmentInfo, int, error) {
+func (svc DepartmentHttpService) SearchDepartmentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DepartmentInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -242,9 +243,9 @@ func (svc DepartmentHttpService) SearchDepartmentStep(shopID string, langCode st
 	return docList, total, nil
 }
 
-func (svc DepartmentHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Department) (common.BulkImport, error) {
+func (svc DepartmentHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Department) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Department](dataList, svc.getDocIDKey)
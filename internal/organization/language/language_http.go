@@ -78,7 +78,7 @@ func (h LanguageHttp) CreateLanguage(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateLanguage(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateLanguage(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h LanguageHttp) UpdateLanguage(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateLanguage(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateLanguage(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h LanguageHttp) DeleteLanguage(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteLanguage(shopID, id, authUsername)
+	err := h.svc.DeleteLanguage(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h LanguageHttp) DeleteLanguageByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteLanguageByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteLanguageByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h LanguageHttp) InfoLanguage(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Language %v", id)
-	doc, err := h.svc.InfoLanguage(shopID, id)
+	doc, err := h.svc.InfoLanguage(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h LanguageHttp) InfoLanguageByCode(ctx microservice.IContext) error {
 
 	languageCode := ctx.Param("languageCode")
 
-	doc, err := h.svc.InfoLanguageByCode(shopID, languageCode)
+	doc, err := h.svc.InfoLanguageByCode(ctx.Context(), shopID, languageCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h LanguageHttp) SearchLanguagePage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchLanguage(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchLanguage(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -319,7 +319,7 @@ func (h LanguageHttp) SearchLanguageStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchLanguageStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchLanguageStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h LanguageHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
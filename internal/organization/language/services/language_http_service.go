@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type ILanguageHttpService interface {
-	CreateLanguage(shopID string, authUsername string, doc models.Language) (string, error)
-	UpdateLanguage(shopID string, guid string, authUsername string, doc models.Language) error
-	DeleteLanguage(shopID string, guid string, authUsername string) error
-	DeleteLanguageByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoLanguage(shopID string, guid string) (models.LanguageInfo, error)
-	InfoLanguageByCode(shopID, languageCode string) (models.LanguageInfo, error)
-	SearchLanguage(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.LanguageInfo, mongopagination.PaginationData, error)
-	SearchLanguageStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.LanguageInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Language) (common.BulkImport, error)
+	CreateLanguage(ctx context.Context, shopID string, authUsername string, doc models.Language) (string, error)
+	UpdateLanguage(ctx context.Context, shopID string, guid string, authUsername string, doc models.Language) error
+	DeleteLanguage(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteLanguageByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoLanguage(ctx context.Context, shopID string, guid string) (models.LanguageInfo, error)
+	InfoLanguageByCode(ctx context.Context, shopID, languageCode string) (models.LanguageInfo, error)
+	SearchLanguage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.LanguageInfo, mongopagination.PaginationData, error)
+	SearchLanguageStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.LanguageInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Language) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewLanguageHttpService(repo repositories.ILanguageRepository, syncCacheRepo
 	return insSvc
 }
 
-func (svc LanguageHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc LanguageHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc LanguageHttpService) CreateLanguage(shopID string, authUsername string, doc models.Language) (string, error) {
+func (svc LanguageHttpService) CreateLanguage(ctx context.Context, shopID string, authUsername string, doc models.Language) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc LanguageHttpService) CreateLanguage(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc LanguageHttpService) UpdateLanguage(shopID string, guid string, authUsername string, doc models.Language) error {
+func (svc LanguageHttpService) UpdateLanguage(ctx context.Context, shopID string, guid string, authUsername string, doc models.Language) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc LanguageHttpService) UpdateLanguage(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc LanguageHttpService) DeleteLanguage(shopID string, guid string, authUsername string) error {
+func (svc LanguageHttpService) DeleteLanguage(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc LanguageHttpService) DeleteLanguage(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc LanguageHttpService) DeleteLanguageByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc LanguageHttpService) DeleteLanguageByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +169,9 @@ func (svc LanguageHttpService) DeleteLanguageByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc LanguageHttpService) InfoLanguage(shopID string, guid string) (models.LanguageInfo, error) {
+func (svc LanguageHttpService) InfoLanguage(ctx context.Context, shopID string, guid string) (models.LanguageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -186,9 +187,9 @@ func (svc LanguageHttpService) InfoLanguage(shopID string, guid string) (models.
 	return findDoc.LanguageInfo, nil
 }
 
-func (svc LanguageHttpService) InfoLanguageByCode(shopID, languageCode string) (models.LanguageInfo, error) {
+func (svc LanguageHttpService) InfoLanguageByCode(ctx context.Context, shopID, languageCode string) (models.LanguageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneByCode(ctx, shopID, languageCode)
@@ -204,9 +205,9 @@ func (svc LanguageHttpService) InfoLanguageByCode(shopID, languageCode string) (
 	return findDoc.LanguageInfo, nil
 }
 
-func (svc LanguageHttpService) SearchLanguage(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.LanguageInfo, mongopagination.PaginationData, error) {
+func (svc LanguageHttpService) SearchLanguage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.LanguageInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -222,9 +223,9 @@ func (svc LanguageHttpService) SearchLanguage(shopID string, filters map[string]
 	return docList, pagination, nil
 }
 
-func (svc LanguageHttpService) SearchLanguageStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.LanguageInfo, int, error) {
+func (svc LanguageHttpService) SearchLanguageStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.LanguageInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -242,9 +243,9 @@ func (svc LanguageHttpService) SearchLanguageStep(shopID string, langCode string
 	return docList, total, nil
 }
 
-func (svc LanguageHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Language) (common.BulkImport, error) {
+func (svc LanguageHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Language) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Language](dataList, svc.getDocIDKey)
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const outboxMessageCollectionName = "outboxMessages"
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	// OutboxStatusParked is event which failed max attempts, it is not published any more
+	OutboxStatusParked = "parked"
+)
+
+// OutboxMessage is event written in the same mongo transaction of the document and published by OutboxRelay,
+// events of the same order key are published in created order
+type OutboxMessage struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Topic         string             `json:"topic" bson:"topic"`
+	Key           string             `json:"key" bson:"key"`
+	OrderKey      string             `json:"orderkey" bson:"orderkey"`
+	Payload       string             `json:"payload" bson:"payload"`
+	Status        string             `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastError     string             `json:"lasterror" bson:"lasterror"`
+	CreatedAt     time.Time          `json:"createdat" bson:"createdat"`
+	NextAttemptAt time.Time          `json:"nextattemptat" bson:"nextattemptat"`
+	SentAt        *time.Time         `json:"sentat,omitempty" bson:"sentat,omitempty"`
+	ParkedAt      *time.Time         `json:"parkedat,omitempty" bson:"parkedat,omitempty"`
+}
+
+func (OutboxMessage) CollectionName() string {
+	return outboxMessageCollectionName
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	outboxPendingMessages = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "smlaicloudplatform",
+		Subsystem: "outbox",
+		Name:      "pending_messages",
+		Help:      "Number of outbox events which are not published yet.",
+	})
+
+	outboxLagSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "smlaicloudplatform",
+		Subsystem: "outbox",
+		Name:      "lag_seconds",
+		Help:      "Age in seconds of the oldest outbox event which is not published yet.",
+	})
+
+	outboxPublishedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "smlaicloudplatform",
+		Subsystem: "outbox",
+		Name:      "published_total",
+		Help:      "Number of outbox events published to message queue.",
+	})
+
+	outboxPublishFailedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "smlaicloudplatform",
+		Subsystem: "outbox",
+		Name:      "publish_failed_total",
+		Help:      "Number of failed attempts to publish outbox events.",
+	})
+
+	outboxParkedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "smlaicloudplatform",
+		Subsystem: "outbox",
+		Name:      "parked_total",
+		Help:      "Number of outbox events parked after max attempts.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		outboxPendingMessages,
+		outboxLagSeconds,
+		outboxPublishedTotal,
+		outboxPublishFailedTotal,
+		outboxParkedTotal,
+	)
+}
@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create indexes used by relay to claim the pending event which is due first and to find the
+// oldest pending event of every order key
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(context.Background(), &OutboxMessage{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdat", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("outbox_status_createdat"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextattemptat", Value: 1}, {Key: "createdat", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("outbox_status_nextattemptat"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "orderkey", Value: 1}, {Key: "createdat", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("outbox_status_orderkey_createdat"),
+	})
+	return err
+}
@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// RegisterPurgeSchedule purge sent events on schedule so outbox collection does not grow forever
+func RegisterPurgeSchedule(ms *microservice.Microservice, cfg config.IConfig) error {
+	relay := InitOutboxRelay(ms, cfg)
+	return ms.Schedule(cfg.OutboxConfig().PurgeSchedule(), "outbox-purge-sent", relay.PurgeSent)
+}
+
+// PurgeSent delete events which are sent longer than sent retention
+func (r *OutboxRelay) PurgeSent(ctx context.Context, run microservice.ScheduleRun) error {
+	deleted, err := r.repo.DeleteSent(ctx, r.now().Add(-r.cfg.SentRetention()))
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof("Outbox purged %d sent events", deleted)
+	return nil
+}
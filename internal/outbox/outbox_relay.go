@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+// OutboxRelay publish outbox events to message queue and mark them sent,
+// event is published at least once so a crash after publish can send it again
+type OutboxRelay struct {
+	repo     IOutboxRepository
+	producer microservice.IProducer
+	cfg      config.IOutboxConfig
+	logger   logger.ILogger
+	now      func() time.Time
+}
+
+func NewOutboxRelay(repo IOutboxRepository, producer microservice.IProducer, cfg config.IOutboxConfig, logger logger.ILogger) *OutboxRelay {
+	return &OutboxRelay{
+		repo:     repo,
+		producer: producer,
+		cfg:      cfg,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func InitOutboxRelay(ms *microservice.Microservice, cfg config.IConfig) *OutboxRelay {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	producer := ms.Producer(cfg.MQConfig())
+
+	return NewOutboxRelay(NewOutboxRepository(pst), producer, cfg.OutboxConfig(), ms.Logger)
+}
+
+// RegisterConsumer start relay loop in background
+func (r *OutboxRelay) RegisterConsumer(ms *microservice.Microservice) {
+	go r.Run()
+}
+
+// Run poll outbox and publish pending events forever
+func (r *OutboxRelay) Run() {
+	for {
+		published, err := r.RelayPending()
+		if err != nil {
+			r.logger.Errorf("Outbox relay failed: %v", err)
+		}
+
+		r.updateMetrics()
+
+		if published < r.cfg.BatchSize() {
+			time.Sleep(r.cfg.PollInterval())
+		}
+	}
+}
+
+// RelayPending publish up to batch size events in created order of their order key, event which cannot be published
+// is retried after backoff and parked after max attempts, events of other keys are published meanwhile
+func (r *OutboxRelay) RelayPending() (int, error) {
+	published := 0
+	for claimed := 0; claimed < r.cfg.BatchSize(); claimed++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		msg, ok, err := r.repo.Claim(ctx, r.now(), r.cfg.ClaimTimeout())
+		if err != nil || !ok {
+			cancel()
+			return published, err
+		}
+
+		if msg.Attempts > r.cfg.MaxAttempts() {
+			// relay stopped while publishing the event every time
+			err = r.park(ctx, msg, "claim timeout after max attempts")
+			cancel()
+			if err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		err = r.producer.SendMessage(msg.Topic, msg.Key, json.RawMessage(msg.Payload))
+		if err != nil {
+			outboxPublishFailedTotal.Inc()
+			r.logger.Errorf("Outbox cannot publish event %s to topic %s (attempt %d): %v", msg.ID.Hex(), msg.Topic, msg.Attempts, err)
+
+			if msg.Attempts >= r.cfg.MaxAttempts() {
+				err = r.park(ctx, msg, err.Error())
+			} else {
+				err = r.repo.MarkFailed(ctx, msg.ID, err.Error(), r.now().Add(r.backoff(msg.Attempts)))
+			}
+			cancel()
+			if err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		err = r.repo.MarkSent(ctx, msg.ID, r.now())
+		cancel()
+		if err != nil {
+			// event is published again after claim timeout, consumers must be idempotent
+			return published, err
+		}
+
+		outboxPublishedTotal.Inc()
+		published++
+	}
+
+	return published, nil
+}
+
+func (r *OutboxRelay) park(ctx context.Context, msg OutboxMessage, lastError string) error {
+	outboxParkedTotal.Inc()
+	r.logger.Errorf("Outbox park event %s to topic %s after %d attempts: %s", msg.ID.Hex(), msg.Topic, msg.Attempts, lastError)
+
+	return r.repo.MarkParked(ctx, msg.ID, lastError, r.now())
+}
+
+// backoff return wait duration before next attempt, doubled every attempt from poll interval up to max backoff
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	wait := r.cfg.PollInterval()
+	for i := 1; i < attempts && wait < r.cfg.MaxBackoff(); i++ {
+		wait *= 2
+	}
+
+	if wait > r.cfg.MaxBackoff() {
+		return r.cfg.MaxBackoff()
+	}
+	return wait
+}
+
+func (r *OutboxRelay) updateMetrics() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	count, oldest, err := r.repo.PendingStats(ctx)
+	if err != nil {
+		r.logger.Errorf("Outbox cannot read pending stats: %v", err)
+		return
+	}
+
+	outboxPendingMessages.Set(float64(count))
+	if oldest == nil {
+		outboxLagSeconds.Set(0)
+		return
+	}
+	outboxLagSeconds.Set(r.now().Sub(*oldest).Seconds())
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestMessage(topic string, payload string, attempts int) OutboxMessage {
+	return OutboxMessage{
+		ID:       primitive.NewObjectID(),
+		Topic:    topic,
+		OrderKey: "SHOP01",
+		Payload:  payload,
+		Status:   OutboxStatusPending,
+		Attempts: attempts,
+	}
+}
+
+func newTestRelay(repo *OutboxRepositoryMock, producer *ProducerMock, now time.Time) *OutboxRelay {
+	relay := NewOutboxRelay(repo, producer, config.NewOutboxConfig(), logger.NewAppLogger(config.NewLoggerConfig()))
+	relay.now = func() time.Time { return now }
+	return relay
+}
+
+func TestOutboxRelayPublishInOrder(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := newTestMessage("when-sale-invoice-created", `{"docno":"INV-0001"}`, 1)
+	updated := newTestMessage("when-sale-invoice-updated", `{"docno":"INV-0001"}`, 1)
+
+	repo := new(OutboxRepositoryMock)
+	repo.On("Claim", now).Return(created, true, nil).Once()
+	repo.On("Claim", now).Return(updated, true, nil).Once()
+	repo.On("Claim", now).Return(OutboxMessage{}, false, nil)
+	repo.On("MarkSent", created.ID, now).Return(nil)
+	repo.On("MarkSent", updated.ID, now).Return(nil)
+
+	producer := new(ProducerMock)
+	producer.On("SendMessage", "when-sale-invoice-created", "", json.RawMessage(created.Payload)).Return(nil).Once()
+	producer.On("SendMessage", "when-sale-invoice-updated", "", json.RawMessage(updated.Payload)).Return(nil).Once()
+
+	published, err := newTestRelay(repo, producer, now).RelayPending()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, published)
+	repo.AssertExpectations(t)
+	producer.AssertExpectations(t)
+}
+
+func TestOutboxRelayRetryFailedEvent(t *testing.T) {
+	t.Setenv("OUTBOX_POLL_INTERVAL_MS", "1000")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	msg := newTestMessage("when-sale-invoice-created", `{"docno":"INV-0001"}`, 2)
+
+	repo := new(OutboxRepositoryMock)
+	repo.On("Claim", now).Return(msg, true, nil).Once()
+	repo.On("Claim", now).Return(OutboxMessage{}, false, nil)
+
+	// the event is claimed again after backoff which is doubled every attempt
+	repo.On("MarkFailed", msg.ID, "kafka unavailable", now.Add(2*time.Second)).Return(nil)
+
+	producer := new(ProducerMock)
+	producer.On("SendMessage", msg.Topic, "", json.RawMessage(msg.Payload)).Return(errors.New("kafka unavailable"))
+
+	published, err := newTestRelay(repo, producer, now).RelayPending()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+}
+
+func TestOutboxRelayParkAfterMaxAttempts(t *testing.T) {
+	t.Setenv("OUTBOX_MAX_ATTEMPTS", "3")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := newTestMessage("when-sale-invoice-created", `{"docno":"INV-0000"}`, 3)
+	lost := newTestMessage("when-sale-invoice-updated", `{"docno":"INV-0001"}`, 4)
+	next := newTestMessage("when-sale-invoice-created", `{"docno":"INV-0002"}`, 1)
+
+	repo := new(OutboxRepositoryMock)
+	repo.On("Claim", now).Return(failed, true, nil).Once()
+	repo.On("Claim", now).Return(lost, true, nil).Once()
+	repo.On("Claim", now).Return(next, true, nil).Once()
+	repo.On("Claim", now).Return(OutboxMessage{}, false, nil)
+	repo.On("MarkParked", failed.ID, "message too large", now).Return(nil)
+
+	// relay which stop while publishing the event every time park it without publishing it again
+	repo.On("MarkParked", lost.ID, "claim timeout after max attempts", now).Return(nil)
+
+	// later event is published after the failed event is parked
+	repo.On("MarkSent", next.ID, now).Return(nil)
+
+	producer := new(ProducerMock)
+	producer.On("SendMessage", failed.Topic, "", json.RawMessage(failed.Payload)).Return(errors.New("message too large"))
+	producer.On("SendMessage", next.Topic, "", json.RawMessage(next.Payload)).Return(nil)
+
+	published, err := newTestRelay(repo, producer, now).RelayPending()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+	repo.AssertExpectations(t)
+	producer.AssertNumberOfCalls(t, "SendMessage", 2)
+}
+
+func TestOutboxRelayBackoff(t *testing.T) {
+	t.Setenv("OUTBOX_POLL_INTERVAL_MS", "1000")
+	t.Setenv("OUTBOX_MAX_BACKOFF_MS", "4000")
+	relay := NewOutboxRelay(nil, nil, config.NewOutboxConfig(), logger.NewAppLogger(config.NewLoggerConfig()))
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 4*time.Second, relay.backoff(3))
+	assert.Equal(t, 4*time.Second, relay.backoff(10))
+}
+
+func TestOutboxRelayPurgeSent(t *testing.T) {
+	t.Setenv("OUTBOX_SENT_RETENTION_HOURS", "1")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := new(OutboxRepositoryMock)
+	repo.On("DeleteSent", now.Add(-time.Hour)).Return(int64(1), nil)
+
+	assert.Nil(t, newTestRelay(repo, nil, now).PurgeSent(context.Background(), microservice.ScheduleRun{}))
+	repo.AssertExpectations(t)
+}
+
+type OutboxRepositoryMock struct {
+	IOutboxRepository
+	mock.Mock
+}
+
+func (m *OutboxRepositoryMock) Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (OutboxMessage, bool, error) {
+	args := m.Called(now)
+	return args.Get(0).(OutboxMessage), args.Bool(1), args.Error(2)
+}
+
+func (m *OutboxRepositoryMock) MarkSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) error {
+	args := m.Called(id, sentAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkParked(ctx context.Context, id primitive.ObjectID, lastError string, parkedAt time.Time) error {
+	args := m.Called(id, lastError, parkedAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type ProducerMock struct {
+	mock.Mock
+}
+
+func (m *ProducerMock) SendMessage(topic string, key string, message interface{}) error {
+	args := m.Called(topic, key, message)
+	return args.Error(0)
+}
+
+func (m *ProducerMock) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *ProducerMock) TestConnect() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,247 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IOutboxRepository interface {
+	Add(ctx context.Context, topic string, key string, message interface{}) error
+	Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (OutboxMessage, bool, error)
+	MarkSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error
+	MarkParked(ctx context.Context, id primitive.ObjectID, lastError string, parkedAt time.Time) error
+	PendingStats(ctx context.Context) (int, *time.Time, error)
+	DeleteSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+// OutboxRepository store events written with the document, modules which message queue repository is created with
+// WithOutbox write their events here in the transaction of the document
+type OutboxRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewOutboxRepository(pst microservice.IPersisterMongo) *OutboxRepository {
+	return &OutboxRepository{
+		pst: pst,
+	}
+}
+
+// Add write event into outbox, ctx should be the transaction context of the document write,
+// message is wrapped in envelope here so event id does not change when the relay publish it again
+func (repo OutboxRepository) Add(ctx context.Context, topic string, key string, message interface{}) error {
+	event, err := microservice.NewEventEnvelope(topic, message)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = repo.pst.Create(ctx, &OutboxMessage{}, OutboxMessage{
+		Topic:         topic,
+		Key:           key,
+		OrderKey:      outboxOrderKey(key, event),
+		Payload:       string(payload),
+		Status:        OutboxStatusPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+
+	return err
+}
+
+// maxClaimSkips is number of order keys which first event is not due that a claim skip before it gives up until next poll
+const maxClaimSkips = 100
+
+// Claim return the pending event which is due first and hide it from other relays until claim timeout.
+// The event is taken by one indexed find and update on status and next attempt time, an event which is not the first
+// pending event of its order key is given back and its key is skipped so events of the same key are published in
+// order while events of other keys are not delayed.
+func (repo OutboxRepository) Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (OutboxMessage, bool, error) {
+	collection, err := repo.pst.Exec(ctx, &OutboxMessage{})
+	if err != nil {
+		return OutboxMessage{}, false, err
+	}
+
+	claimedUntil := now.Add(claimTimeout)
+	skipKeys := []string{}
+
+	for len(skipKeys) < maxClaimSkips {
+		filter := bson.M{
+			"status":        OutboxStatusPending,
+			"nextattemptat": bson.M{"$lte": now},
+		}
+
+		if len(skipKeys) > 0 {
+			filter["orderkey"] = bson.M{"$nin": skipKeys}
+		}
+
+		due := OutboxMessage{}
+		err = collection.FindOneAndUpdate(
+			ctx,
+			filter,
+			bson.M{
+				"$set": bson.M{"nextattemptat": claimedUntil},
+				"$inc": bson.M{"attempts": 1},
+			},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "nextattemptat", Value: 1}, {Key: "createdat", Value: 1}, {Key: "_id", Value: 1}}).
+				SetReturnDocument(options.Before),
+		).Decode(&due)
+
+		if err == mongo.ErrNoDocuments {
+			return OutboxMessage{}, false, nil
+		}
+
+		if err != nil {
+			return OutboxMessage{}, false, err
+		}
+
+		first, err := repo.isFirstOfOrderKey(ctx, due)
+		if err != nil {
+			return OutboxMessage{}, false, err
+		}
+
+		if first {
+			claimed := due
+			claimed.Attempts++
+			claimed.NextAttemptAt = claimedUntil
+			return claimed, true, nil
+		}
+
+		// earlier event of the key is waiting for retry or claimed by other relay
+		err = repo.pst.Update(ctx, &OutboxMessage{}, bson.M{"_id": due.ID, "nextattemptat": claimedUntil}, bson.M{
+			"$set": bson.M{"nextattemptat": due.NextAttemptAt},
+			"$inc": bson.M{"attempts": -1},
+		})
+		if err != nil {
+			return OutboxMessage{}, false, err
+		}
+
+		skipKeys = append(skipKeys, due.OrderKey)
+	}
+
+	return OutboxMessage{}, false, nil
+}
+
+// isFirstOfOrderKey return true when no pending event of the order key is created before the event
+func (repo OutboxRepository) isFirstOfOrderKey(ctx context.Context, msg OutboxMessage) (bool, error) {
+	earlier := OutboxMessage{}
+	err := repo.pst.FindOne(
+		ctx,
+		&OutboxMessage{},
+		bson.M{
+			"status":   OutboxStatusPending,
+			"orderkey": msg.OrderKey,
+			"$or": []bson.M{
+				{"createdat": bson.M{"$lt": msg.CreatedAt}},
+				{"createdat": msg.CreatedAt, "_id": bson.M{"$lt": msg.ID}},
+			},
+		},
+		&earlier,
+		options.FindOne().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return earlier.ID.IsZero(), nil
+}
+
+func (repo OutboxRepository) MarkSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) error {
+	return repo.pst.Update(ctx, &OutboxMessage{}, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"status":    OutboxStatusSent,
+			"sentat":    sentAt,
+			"lasterror": "",
+		},
+	})
+}
+
+func (repo OutboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	return repo.pst.Update(ctx, &OutboxMessage{}, bson.M{"_id": id, "status": OutboxStatusPending}, bson.M{
+		"$set": bson.M{
+			"lasterror":     lastError,
+			"nextattemptat": nextAttemptAt,
+		},
+	})
+}
+
+// MarkParked stop publishing the event, later events of its order key are published
+func (repo OutboxRepository) MarkParked(ctx context.Context, id primitive.ObjectID, lastError string, parkedAt time.Time) error {
+	return repo.pst.Update(ctx, &OutboxMessage{}, bson.M{"_id": id, "status": OutboxStatusPending}, bson.M{
+		"$set": bson.M{
+			"status":    OutboxStatusParked,
+			"lasterror": lastError,
+			"parkedat":  parkedAt,
+		},
+	})
+}
+
+// PendingStats return number of pending events and created time of the oldest one
+func (repo OutboxRepository) PendingStats(ctx context.Context) (int, *time.Time, error) {
+	count, err := repo.pst.Count(ctx, &OutboxMessage{}, bson.M{"status": OutboxStatusPending})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if count == 0 {
+		return 0, nil, nil
+	}
+
+	oldest := OutboxMessage{}
+	err = repo.pst.FindOne(
+		ctx,
+		&OutboxMessage{},
+		bson.M{"status": OutboxStatusPending},
+		&oldest,
+		options.FindOne().SetSort(bson.D{{Key: "createdat", Value: 1}}),
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if oldest.ID.IsZero() {
+		return count, nil, nil
+	}
+
+	return count, &oldest.CreatedAt, nil
+}
+
+// DeleteSent remove events which are sent before the time, pending events are kept
+func (repo OutboxRepository) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	collection, err := repo.pst.Exec(ctx, &OutboxMessage{})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{
+		"status": OutboxStatusSent,
+		"sentat": bson.M{"$lt": before},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// outboxOrderKey return key which events are published in order, it is the message queue key or shop of the event
+// when the key is empty so events of one shop do not delay events of other shops
+func outboxOrderKey(key string, event microservice.EventEnvelope) string {
+	if key != "" {
+		return key
+	}
+	return event.ShopID
+}
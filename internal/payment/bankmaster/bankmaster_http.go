@@ -77,7 +77,7 @@ func (h BankMasterHttp) CreateBankMaster(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateBankMaster(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateBankMaster(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h BankMasterHttp) UpdateBankMaster(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateBankMaster(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateBankMaster(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h BankMasterHttp) DeleteBankMaster(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBankMaster(shopID, id, authUsername)
+	err := h.svc.DeleteBankMaster(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h BankMasterHttp) DeleteBankMasterByGUIDs(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.DeleteBankMasterByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteBankMasterByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h BankMasterHttp) InfoBankMaster(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get BankMaster %v", id)
-	doc, err := h.svc.InfoBankMaster(shopID, id)
+	doc, err := h.svc.InfoBankMaster(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h BankMasterHttp) SearchBankMasterPage(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchBankMaster(shopID, pageable)
+	docList, pagination, err := h.svc.SearchBankMaster(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h BankMasterHttp) SearchBankMasterLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchBankMasterStep(shopID, lang, pageStep)
+	docList, total, err := h.svc.SearchBankMasterStep(ctx.Context(), shopID, lang, pageStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -328,7 +328,7 @@ func (h BankMasterHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IBankMasterHttpService interface {
-	CreateBankMaster(shopID string, authUsername string, doc models.BankMaster) (string, error)
-	UpdateBankMaster(shopID string, guid string, authUsername string, doc models.BankMaster) error
-	DeleteBankMaster(shopID string, guid string, authUsername string) error
-	DeleteBankMasterByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoBankMaster(shopID string, guid string) (models.BankMasterInfo, error)
-	SearchBankMaster(shopID string, pageable micromodels.Pageable) ([]models.BankMasterInfo, mongopagination.PaginationData, error)
-	SearchBankMasterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BankMasterInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.BankMaster) (common.BulkImport, error)
+	CreateBankMaster(ctx context.Context, shopID string, authUsername string, doc models.BankMaster) (string, error)
+	UpdateBankMaster(ctx context.Context, shopID string, guid string, authUsername string, doc models.BankMaster) error
+	DeleteBankMaster(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteBankMasterByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoBankMaster(ctx context.Context, shopID string, guid string) (models.BankMasterInfo, error)
+	SearchBankMaster(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.BankMasterInfo, mongopagination.PaginationData, error)
+	SearchBankMasterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BankMasterInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BankMaster) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewBankMasterHttpService(repo repositories.IBankMasterRepository, syncCache
 	return insSvc
 }
 
-func (svc BankMasterHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BankMasterHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BankMasterHttpService) CreateBankMaster(shopID string, authUsername string, doc models.BankMaster) (string, error) {
+func (svc BankMasterHttpService) CreateBankMaster(ctx context.Context, shopID string, authUsername string, doc models.BankMaster) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -94,9 +95,9 @@ func (svc BankMasterHttpService) CreateBankMaster(shopID string, authUsername st
 	return newGuidFixed, nil
 }
 
-func (svc BankMasterHttpService) UpdateBankMaster(shopID string, guid string, authUsername string, doc models.BankMaster) error {
+func (svc BankMasterHttpService) UpdateBankMaster(ctx context.Context, shopID string, guid string, authUsername string, doc models.BankMaster) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,9 +126,9 @@ func (svc BankMasterHttpService) UpdateBankMaster(shopID string, guid string, au
 	return nil
 }
 
-func (svc BankMasterHttpService) DeleteBankMaster(shopID string, guid string, authUsername string) error {
+func (svc BankMasterHttpService) DeleteBankMaster(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -150,9 +151,9 @@ func (svc BankMasterHttpService) DeleteBankMaster(shopID string, guid string, au
 	return nil
 }
 
-func (svc BankMasterHttpService) DeleteBankMasterByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc BankMasterHttpService) DeleteBankMasterByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -167,9 +168,9 @@ func (svc BankMasterHttpService) DeleteBankMasterByGUIDs(shopID string, authUser
 	return nil
 }
 
-func (svc BankMasterHttpService) InfoBankMaster(shopID string, guid string) (models.BankMasterInfo, error) {
+func (svc BankMasterHttpService) InfoBankMaster(ctx context.Context, shopID string, guid string) (models.BankMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -186,9 +187,9 @@ func (svc BankMasterHttpService) InfoBankMaster(shopID string, guid string) (mod
 
 }
 
-func (svc BankMasterHttpService) SearchBankMaster(shopID string, pageable micromodels.Pageable) ([]models.BankMasterInfo, mongopagination.PaginationData, error) {
+func (svc BankMasterHttpService) SearchBankMaster(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.BankMasterInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -205,9 +206,9 @@ func (svc BankMasterHttpService) SearchBankMaster(shopID string, pageable microm
 	return docList, pagination, nil
 }
 
-func (svc BankMasterHttpService) SearchBankMasterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BankMasterInfo, int, error) {
+func (svc BankMasterHttpService) SearchBankMasterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BankMasterInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -236,9 +237,9 @@ func (svc BankMasterHttpService) SearchBankMasterStep(shopID string, langCode st
 	return docList, total, nil
 }
 
-func (svc BankMasterHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.BankMaster) (common.BulkImport, error) {
+func (svc BankMasterHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BankMaster) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.BankMaster](dataList, svc.getDocIDKey)
@@ -77,7 +77,7 @@ func (h BookBankHttp) CreateBookBank(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateBookBank(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateBookBank(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h BookBankHttp) UpdateBookBank(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateBookBank(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateBookBank(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h BookBankHttp) DeleteBookBank(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBookBank(shopID, id, authUsername)
+	err := h.svc.DeleteBookBank(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h BookBankHttp) DeleteBookBankByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteBookBankByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteBookBankByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h BookBankHttp) InfoBookBank(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get BookBank %v", id)
-	doc, err := h.svc.InfoBookBank(shopID, id)
+	doc, err := h.svc.InfoBookBank(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h BookBankHttp) SearchBookBankPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchBookBank(shopID, pageable)
+	docList, pagination, err := h.svc.SearchBookBank(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h BookBankHttp) SearchBookBankLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchBookBankStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchBookBankStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +329,7 @@ func (h BookBankHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IBookBankHttpService interface {
-	CreateBookBank(shopID string, authUsername string, doc models.BookBank) (string, error)
-	UpdateBookBank(shopID string, guid string, authUsername string, doc models.BookBank) error
-	DeleteBookBank(shopID string, guid string, authUsername string) error
-	DeleteBookBankByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoBookBank(shopID string, guid string) (models.BookBankInfo, error)
-	SearchBookBank(shopID string, pageable micromodels.Pageable) ([]models.BookBankInfo, mongopagination.PaginationData, error)
-	SearchBookBankStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BookBankInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.BookBank) (common.BulkImport, error)
+	CreateBookBank(ctx context.Context, shopID string, authUsername string, doc models.BookBank) (string, error)
+	UpdateBookBank(ctx context.Context, shopID string, guid string, authUsername string, doc models.BookBank) error
+	DeleteBookBank(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteBookBankByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoBookBank(ctx context.Context, shopID string, guid string) (models.BookBankInfo, error)
+	SearchBookBank(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.BookBankInfo, mongopagination.PaginationData, error)
+	SearchBookBankStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BookBankInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BookBank) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewBookBankHttpService(repo repositories.IBookBankRepository, syncCacheRepo
 	return insSvc
 }
 
-func (svc BookBankHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BookBankHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BookBankHttpService) CreateBookBank(shopID string, authUsername string, doc models.BookBank) (string, error) {
+func (svc BookBankHttpService) CreateBookBank(ctx context.Context, shopID string, authUsername string, doc models.BookBank) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "passbook", doc.PassBook)
@@ -95,9 +96,9 @@ func (svc BookBankHttpService) CreateBookBank(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc BookBankHttpService) UpdateBookBank(shopID string, guid string, authUsername string, doc models.BookBank) error {
+func (svc BookBankHttpService) UpdateBookBank(ctx context.Context, shopID string, guid string, authUsername string, doc models.BookBank) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc BookBankHttpService) UpdateBookBank(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc BookBankHttpService) DeleteBookBank(shopID string, guid string, authUsername string) error {
+func (svc BookBankHttpService) DeleteBookBank(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc BookBankHttpService) DeleteBookBank(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc BookBankHttpService) DeleteBookBankByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc BookBankHttpService) DeleteBookBankByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +169,9 @@ func (svc BookBankHttpService) DeleteBookBankByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc BookBankHttpService) InfoBookBank(shopID string, guid string) (models.BookBankInfo, error) {
+func (svc BookBankHttpService) InfoBookBank(ctx context.Context, shopID string, guid string) (models.BookBankInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -187,9 +188,9 @@ func (svc BookBankHttpService) InfoBookBank(shopID string, guid string) (models.
 
 }
 
-func (svc BookBankHttpService) SearchBookBank(shopID string, pageable micromodels.Pageable) ([]models.BookBankInfo, mongopagination.PaginationData, error) {
+func (svc BookBankHttpService) SearchBookBank(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.BookBankInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -209,9 +210,9 @@ func (svc BookBankHttpService) SearchBookBank(shopID string, pageable micromodel
 	return docList, pagination, nil
 }
 
-func (svc BookBankHttpService) SearchBookBankStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BookBankInfo, int, error) {
+func (svc BookBankHttpService) SearchBookBankStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BookBankInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -233,9 +234,9 @@ func (svc BookBankHttpService) SearchBookBankStep(shopID string, langCode string
 	return docList, total, nil
 }
 
-func (svc BookBankHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.BookBank) (common.BulkImport, error) {
+func (svc BookBankHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.BookBank) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.BookBank](dataList, svc.getDocIDKey)
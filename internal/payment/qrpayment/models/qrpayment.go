@@ -47,6 +47,11 @@ type QrPayment struct {
 	// BankCode      string          `json:"bankcode" bson:"bankcode"`
 }
 
+// SecretFields are credentials of payment provider, they are encrypted at rest and redacted in api response
+func (doc *QrPayment) SecretFields() []*string {
+	return []*string{&doc.ApiKey, &doc.AccessCode, &doc.Secret, &doc.Token}
+}
+
 type QrPaymentInfo struct {
 	models.DocIdentity `bson:"inline"`
 	QrPayment          `bson:"inline"`
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/payment/qrpayment/models"
@@ -25,7 +26,8 @@ func NewQrPaymentHttp(ms *microservice.Microservice, cfg config.IConfig) QrPayme
 	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
 	cache := ms.Cacher(cfg.CacherConfig())
 
-	repo := repositories.NewQrPaymentRepository(pst)
+	encryptor := datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig())
+	repo := repositories.NewQrPaymentRepository(pst, encryptor)
 
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
 	svc := services.NewQrPaymentHttpService(repo, masterSyncCacheRepo)
@@ -77,7 +79,7 @@ func (h QrPaymentHttp) CreateQrPayment(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateQrPayment(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateQrPayment(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +124,7 @@ func (h QrPaymentHttp) UpdateQrPayment(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateQrPayment(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateQrPayment(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +155,7 @@ func (h QrPaymentHttp) DeleteQrPayment(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteQrPayment(shopID, id, authUsername)
+	err := h.svc.DeleteQrPayment(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +194,7 @@ func (h QrPaymentHttp) DeleteQrPaymentByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteQrPaymentByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteQrPaymentByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +224,7 @@ func (h QrPaymentHttp) InfoQrPayment(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get QrPayment %v", id)
-	doc, err := h.svc.InfoQrPayment(shopID, id)
+	doc, err := h.svc.InfoQrPayment(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +256,7 @@ func (h QrPaymentHttp) SearchQrPaymentPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchQrPayment(shopID, pageable)
+	docList, pagination, err := h.svc.SearchQrPayment(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +291,7 @@ func (h QrPaymentHttp) SearchQrPaymentLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchQrPaymentStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchQrPaymentStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +331,7 @@ func (h QrPaymentHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
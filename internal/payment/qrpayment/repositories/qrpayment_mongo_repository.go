@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"smlaicloudplatform/internal/encrypt"
 	"smlaicloudplatform/internal/payment/qrpayment/models"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/pkg/microservice"
@@ -32,18 +33,21 @@ type IQrPaymentRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, extraFilters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.QrPaymentActivity, error)
 }
 
+// QrPaymentRepository encrypt secret fields before they are written and decrypt them after they are read
 type QrPaymentRepository struct {
-	pst microservice.IPersisterMongo
+	pst       microservice.IPersisterMongo
+	encryptor encrypt.IFieldEncryptor
 	repositories.CrudRepository[models.QrPaymentDoc]
 	repositories.SearchRepository[models.QrPaymentInfo]
 	repositories.GuidRepository[models.QrPaymentItemGuid]
 	repositories.ActivityRepository[models.QrPaymentActivity, models.QrPaymentDeleteActivity]
 }
 
-func NewQrPaymentRepository(pst microservice.IPersisterMongo) *QrPaymentRepository {
+func NewQrPaymentRepository(pst microservice.IPersisterMongo, encryptor encrypt.IFieldEncryptor) *QrPaymentRepository {
 
 	insRepo := &QrPaymentRepository{
-		pst: pst,
+		pst:       pst,
+		encryptor: encryptor,
 	}
 
 	insRepo.CrudRepository = repositories.NewCrudRepository[models.QrPaymentDoc](pst)
@@ -53,3 +57,116 @@ func NewQrPaymentRepository(pst microservice.IPersisterMongo) *QrPaymentReposito
 
 	return insRepo
 }
+
+func (repo QrPaymentRepository) Create(ctx context.Context, doc models.QrPaymentDoc) (string, error) {
+	err := repo.encryptor.EncryptFields(ctx, doc.ShopID, doc.SecretFields()...)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.CrudRepository.Create(ctx, doc)
+}
+
+func (repo QrPaymentRepository) CreateInBatch(ctx context.Context, docList []models.QrPaymentDoc) error {
+	encryptedDocList := make([]models.QrPaymentDoc, len(docList))
+	for i, doc := range docList {
+		err := repo.encryptor.EncryptFields(ctx, doc.ShopID, doc.SecretFields()...)
+		if err != nil {
+			return err
+		}
+		encryptedDocList[i] = doc
+	}
+
+	return repo.CrudRepository.CreateInBatch(ctx, encryptedDocList)
+}
+
+func (repo QrPaymentRepository) Update(ctx context.Context, shopID string, guid string, doc models.QrPaymentDoc) error {
+	err := repo.encryptor.EncryptFields(ctx, shopID, doc.SecretFields()...)
+	if err != nil {
+		return err
+	}
+
+	return repo.CrudRepository.Update(ctx, shopID, guid, doc)
+}
+
+func (repo QrPaymentRepository) FindByGuid(ctx context.Context, shopID string, guid string) (models.QrPaymentDoc, error) {
+	doc, err := repo.CrudRepository.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo QrPaymentRepository) FindByDocIndentityGuid(ctx context.Context, shopID string, indentityField string, indentityValue interface{}) (models.QrPaymentDoc, error) {
+	doc, err := repo.CrudRepository.FindByDocIndentityGuid(ctx, shopID, indentityField, indentityValue)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo QrPaymentRepository) FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.QrPaymentInfo, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.SearchRepository.FindPage(ctx, shopID, searchInFields, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+func (repo QrPaymentRepository) FindStep(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, selectFields map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.QrPaymentInfo, int, error) {
+	docList, total, err := repo.SearchRepository.FindStep(ctx, shopID, filters, searchInFields, selectFields, pageableStep)
+	if err != nil {
+		return docList, total, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, total, err
+		}
+	}
+
+	return docList, total, nil
+}
+
+func (repo QrPaymentRepository) FindCreatedOrUpdatedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, extraFilters map[string]interface{}, pageable micromodels.Pageable) ([]models.QrPaymentActivity, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.ActivityRepository.FindCreatedOrUpdatedPage(ctx, shopID, lastUpdatedDate, extraFilters, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+func (repo QrPaymentRepository) FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, extraFilters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.QrPaymentActivity, error) {
+	docList, err := repo.ActivityRepository.FindCreatedOrUpdatedStep(ctx, shopID, lastUpdatedDate, extraFilters, pageableStep)
+	if err != nil {
+		return docList, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docList, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"smlaicloudplatform/internal/encrypt"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/payment/qrpayment/models"
@@ -11,6 +12,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +22,14 @@ import (
 )
 
 type IQrPaymentHttpService interface {
-	CreateQrPayment(shopID string, authUsername string, doc models.QrPayment) (string, error)
-	UpdateQrPayment(shopID string, guid string, authUsername string, doc models.QrPayment) error
-	DeleteQrPayment(shopID string, guid string, authUsername string) error
-	DeleteQrPaymentByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoQrPayment(shopID string, guid string) (models.QrPaymentInfo, error)
-	SearchQrPayment(shopID string, pageable micromodels.Pageable) ([]models.QrPaymentInfo, mongopagination.PaginationData, error)
-	SearchQrPaymentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.QrPaymentInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.QrPayment) (common.BulkImport, error)
+	CreateQrPayment(ctx context.Context, shopID string, authUsername string, doc models.QrPayment) (string, error)
+	UpdateQrPayment(ctx context.Context, shopID string, guid string, authUsername string, doc models.QrPayment) error
+	DeleteQrPayment(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteQrPaymentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoQrPayment(ctx context.Context, shopID string, guid string) (models.QrPaymentInfo, error)
+	SearchQrPayment(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.QrPaymentInfo, mongopagination.PaginationData, error)
+	SearchQrPaymentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.QrPaymentInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.QrPayment) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +57,13 @@ func NewQrPaymentHttpService(repo repositories.IQrPaymentRepository, syncCacheRe
 	return insSvc
 }
 
-func (svc QrPaymentHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc QrPaymentHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc QrPaymentHttpService) CreateQrPayment(shopID string, authUsername string, doc models.QrPayment) (string, error) {
+func (svc QrPaymentHttpService) CreateQrPayment(ctx context.Context, shopID string, authUsername string, doc models.QrPayment) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +97,9 @@ func (svc QrPaymentHttpService) CreateQrPayment(shopID string, authUsername stri
 	return newGuidFixed, nil
 }
 
-func (svc QrPaymentHttpService) UpdateQrPayment(shopID string, guid string, authUsername string, doc models.QrPayment) error {
+func (svc QrPaymentHttpService) UpdateQrPayment(ctx context.Context, shopID string, guid string, authUsername string, doc models.QrPayment) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -110,6 +112,7 @@ func (svc QrPaymentHttpService) UpdateQrPayment(shopID string, guid string, auth
 		return errors.New("document not found")
 	}
 
+	encrypt.RestoreRedactedFields(doc.SecretFields(), findDoc.SecretFields())
 	findDoc.QrPayment = doc
 
 	findDoc.UpdatedBy = authUsername
@@ -126,9 +129,9 @@ func (svc QrPaymentHttpService) UpdateQrPayment(shopID string, guid string, auth
 	return nil
 }
 
-func (svc QrPaymentHttpService) DeleteQrPayment(shopID string, guid string, authUsername string) error {
+func (svc QrPaymentHttpService) DeleteQrPayment(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +154,9 @@ func (svc QrPaymentHttpService) DeleteQrPayment(shopID string, guid string, auth
 	return nil
 }
 
-func (svc QrPaymentHttpService) DeleteQrPaymentByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc QrPaymentHttpService) DeleteQrPaymentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +171,9 @@ func (svc QrPaymentHttpService) DeleteQrPaymentByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc QrPaymentHttpService) InfoQrPayment(shopID string, guid string) (models.QrPaymentInfo, error) {
+func (svc QrPaymentHttpService) InfoQrPayment(ctx context.Context, shopID string, guid string) (models.QrPaymentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -183,13 +186,15 @@ func (svc QrPaymentHttpService) InfoQrPayment(shopID string, guid string) (model
 		return models.QrPaymentInfo{}, errors.New("document not found")
 	}
 
+	encrypt.RedactFields(findDoc.SecretFields()...)
+
 	return findDoc.QrPaymentInfo, nil
 
 }
 
-func (svc QrPaymentHttpService) SearchQrPayment(shopID string, pageable micromodels.Pageable) ([]models.QrPaymentInfo, mongopagination.PaginationData, error) {
+func (svc QrPaymentHttpService) SearchQrPayment(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.QrPaymentInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -203,12 +208,16 @@ func (svc QrPaymentHttpService) SearchQrPayment(shopID string, pageable micromod
 		return []models.QrPaymentInfo{}, pagination, err
 	}
 
+	for i := range docList {
+		encrypt.RedactFields(docList[i].SecretFields()...)
+	}
+
 	return docList, pagination, nil
 }
 
-func (svc QrPaymentHttpService) SearchQrPaymentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.QrPaymentInfo, int, error) {
+func (svc QrPaymentHttpService) SearchQrPaymentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.QrPaymentInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -224,12 +233,16 @@ func (svc QrPaymentHttpService) SearchQrPaymentStep(shopID string, langCode stri
 		return []models.QrPaymentInfo{}, 0, err
 	}
 
+	for i := range docList {
+		encrypt.RedactFields(docList[i].SecretFields()...)
+	}
+
 	return docList, total, nil
 }
 
-func (svc QrPaymentHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.QrPayment) (common.BulkImport, error) {
+func (svc QrPaymentHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.QrPayment) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.QrPayment](dataList, svc.getDocIDKey)
@@ -285,6 +298,7 @@ func (svc QrPaymentHttpService) SaveInBatch(shopID string, authUsername string,
 		},
 		func(shopID string, authUsername string, data models.QrPayment, doc models.QrPaymentDoc) error {
 
+			encrypt.RestoreRedactedFields(data.SecretFields(), doc.SecretFields())
 			doc.QrPayment = data
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
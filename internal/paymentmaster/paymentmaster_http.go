@@ -68,7 +68,7 @@ func (h PaymentMasterHttp) CreatePaymentMaster(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.svc.CreatePaymentMaster(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreatePaymentMaster(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -109,7 +109,7 @@ func (h PaymentMasterHttp) UpdatePaymentMaster(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdatePaymentMaster(id, shopID, authUsername, *docReq)
+	err = h.svc.UpdatePaymentMaster(ctx.Context(), id, shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -141,7 +141,7 @@ func (h PaymentMasterHttp) DeletePaymentMaster(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePaymentMaster(id, shopID, authUsername)
+	err := h.svc.DeletePaymentMaster(ctx.Context(), id, shopID, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -173,7 +173,7 @@ func (h PaymentMasterHttp) InfoPaymentMaster(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get PaymentMaster %v", id)
-	doc, err := h.svc.InfoPaymentMaster(id, shopID)
+	doc, err := h.svc.InfoPaymentMaster(ctx.Context(), id, shopID)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -205,7 +205,7 @@ func (h PaymentMasterHttp) SearchPaymentMaster(ctx microservice.IContext) error
 	shopID := userInfo.ShopID
 
 	q := ctx.QueryParam("q")
-	docList, err := h.svc.SearchPaymentMaster(shopID, q)
+	docList, err := h.svc.SearchPaymentMaster(ctx.Context(), shopID, q)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -245,7 +245,7 @@ func (h PaymentMasterHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -8,18 +8,19 @@ import (
 	"smlaicloudplatform/internal/paymentmaster/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type IPaymentMasterHttpService interface {
-	CreatePaymentMaster(shopID string, authUsername string, doc models.PaymentMaster) (string, error)
-	UpdatePaymentMaster(guid string, shopID string, authUsername string, doc models.PaymentMaster) error
-	DeletePaymentMaster(guid string, shopID string, authUsername string) error
-	InfoPaymentMaster(guid string, shopID string) (models.PaymentMasterInfo, error)
-	SearchPaymentMaster(shopID string, q string) ([]models.PaymentMasterInfo, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.PaymentMaster) (common.BulkImport, error)
+	CreatePaymentMaster(ctx context.Context, shopID string, authUsername string, doc models.PaymentMaster) (string, error)
+	UpdatePaymentMaster(ctx context.Context, guid string, shopID string, authUsername string, doc models.PaymentMaster) error
+	DeletePaymentMaster(ctx context.Context, guid string, shopID string, authUsername string) error
+	InfoPaymentMaster(ctx context.Context, guid string, shopID string) (models.PaymentMasterInfo, error)
+	SearchPaymentMaster(ctx context.Context, shopID string, q string) ([]models.PaymentMasterInfo, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PaymentMaster) (common.BulkImport, error)
 }
 
 type PaymentMasterHttpService struct {
@@ -37,13 +38,13 @@ func NewPaymentMasterHttpService(repo repositories.PaymentMasterRepository) Paym
 	}
 }
 
-func (svc PaymentMasterHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PaymentMasterHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PaymentMasterHttpService) CreatePaymentMaster(shopID string, authUsername string, doc models.PaymentMaster) (string, error) {
+func (svc PaymentMasterHttpService) CreatePaymentMaster(ctx context.Context, shopID string, authUsername string, doc models.PaymentMaster) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "paymentcode", doc.PaymentCode)
@@ -75,9 +76,9 @@ func (svc PaymentMasterHttpService) CreatePaymentMaster(shopID string, authUsern
 	return newGuidFixed, nil
 }
 
-func (svc PaymentMasterHttpService) UpdatePaymentMaster(guid string, shopID string, authUsername string, doc models.PaymentMaster) error {
+func (svc PaymentMasterHttpService) UpdatePaymentMaster(ctx context.Context, guid string, shopID string, authUsername string, doc models.PaymentMaster) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -104,9 +105,9 @@ func (svc PaymentMasterHttpService) UpdatePaymentMaster(guid string, shopID stri
 	return nil
 }
 
-func (svc PaymentMasterHttpService) DeletePaymentMaster(guid string, shopID string, authUsername string) error {
+func (svc PaymentMasterHttpService) DeletePaymentMaster(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -127,9 +128,9 @@ func (svc PaymentMasterHttpService) DeletePaymentMaster(guid string, shopID stri
 	return nil
 }
 
-func (svc PaymentMasterHttpService) InfoPaymentMaster(guid string, shopID string) (models.PaymentMasterInfo, error) {
+func (svc PaymentMasterHttpService) InfoPaymentMaster(ctx context.Context, guid string, shopID string) (models.PaymentMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -146,9 +147,9 @@ func (svc PaymentMasterHttpService) InfoPaymentMaster(guid string, shopID string
 
 }
 
-func (svc PaymentMasterHttpService) SearchPaymentMaster(shopID string, q string) ([]models.PaymentMasterInfo, error) {
+func (svc PaymentMasterHttpService) SearchPaymentMaster(ctx context.Context, shopID string, q string) ([]models.PaymentMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -165,9 +166,9 @@ func (svc PaymentMasterHttpService) SearchPaymentMaster(shopID string, q string)
 	return docList, nil
 }
 
-func (svc PaymentMasterHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.PaymentMaster) (common.BulkImport, error) {
+func (svc PaymentMasterHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PaymentMaster) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	createDataList := []models.PaymentMasterDoc{}
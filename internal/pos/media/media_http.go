@@ -79,7 +79,7 @@ func (h MediaHttp) CreateMedia(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateMedia(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateMedia(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -124,7 +124,7 @@ func (h MediaHttp) UpdateMedia(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateMedia(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateMedia(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -155,7 +155,7 @@ func (h MediaHttp) DeleteMedia(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteMedia(shopID, id, authUsername)
+	err := h.svc.DeleteMedia(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -194,7 +194,7 @@ func (h MediaHttp) DeleteMediaByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteMediaByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteMediaByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -224,7 +224,7 @@ func (h MediaHttp) InfoMedia(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Media %v", id)
-	doc, err := h.svc.InfoMedia(shopID, id)
+	doc, err := h.svc.InfoMedia(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h MediaHttp) InfoMediaByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoMediaByCode(shopID, code)
+	doc, err := h.svc.InfoMediaByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -285,7 +285,7 @@ func (h MediaHttp) SearchMediaPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchMedia(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchMedia(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -320,7 +320,7 @@ func (h MediaHttp) SearchMediaStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchMediaStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchMediaStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -360,7 +360,7 @@ func (h MediaHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IMediaHttpService interface {
-	CreateMedia(shopID string, authUsername string, doc models.Media) (string, error)
-	UpdateMedia(shopID string, guid string, authUsername string, doc models.Media) error
-	DeleteMedia(shopID string, guid string, authUsername string) error
-	DeleteMediaByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoMedia(shopID string, guid string) (models.MediaInfo, error)
-	InfoMediaByCode(shopID string, code string) (models.MediaInfo, error)
-	SearchMedia(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MediaInfo, mongopagination.PaginationData, error)
-	SearchMediaStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.MediaInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Media) (common.BulkImport, error)
+	CreateMedia(ctx context.Context, shopID string, authUsername string, doc models.Media) (string, error)
+	UpdateMedia(ctx context.Context, shopID string, guid string, authUsername string, doc models.Media) error
+	DeleteMedia(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteMediaByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoMedia(ctx context.Context, shopID string, guid string) (models.MediaInfo, error)
+	InfoMediaByCode(ctx context.Context, shopID string, code string) (models.MediaInfo, error)
+	SearchMedia(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MediaInfo, mongopagination.PaginationData, error)
+	SearchMediaStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.MediaInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Media) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -59,13 +60,13 @@ func NewMediaHttpService(
 	return insSvc
 }
 
-func (svc MediaHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc MediaHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc MediaHttpService) CreateMedia(shopID string, authUsername string, doc models.Media) (string, error) {
+func (svc MediaHttpService) CreateMedia(ctx context.Context, shopID string, authUsername string, doc models.Media) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -101,9 +102,9 @@ func (svc MediaHttpService) CreateMedia(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc MediaHttpService) UpdateMedia(shopID string, guid string, authUsername string, doc models.Media) error {
+func (svc MediaHttpService) UpdateMedia(ctx context.Context, shopID string, guid string, authUsername string, doc models.Media) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -134,9 +135,9 @@ func (svc MediaHttpService) UpdateMedia(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc MediaHttpService) DeleteMedia(shopID string, guid string, authUsername string) error {
+func (svc MediaHttpService) DeleteMedia(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc MediaHttpService) DeleteMedia(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc MediaHttpService) DeleteMediaByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc MediaHttpService) DeleteMediaByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -182,9 +183,9 @@ func (svc MediaHttpService) DeleteMediaByGUIDs(shopID string, authUsername strin
 	return nil
 }
 
-func (svc MediaHttpService) InfoMedia(shopID string, guid string) (models.MediaInfo, error) {
+func (svc MediaHttpService) InfoMedia(ctx context.Context, shopID string, guid string) (models.MediaInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -200,9 +201,9 @@ func (svc MediaHttpService) InfoMedia(shopID string, guid string) (models.MediaI
 	return findDoc.MediaInfo, nil
 }
 
-func (svc MediaHttpService) InfoMediaByCode(shopID string, code string) (models.MediaInfo, error) {
+func (svc MediaHttpService) InfoMediaByCode(ctx context.Context, shopID string, code string) (models.MediaInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -218,9 +219,9 @@ func (svc MediaHttpService) InfoMediaByCode(shopID string, code string) (models.
 	return findDoc.MediaInfo, nil
 }
 
-func (svc MediaHttpService) SearchMedia(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MediaInfo, mongopagination.PaginationData, error) {
+func (svc MediaHttpService) SearchMedia(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.MediaInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -236,9 +237,9 @@ func (svc MediaHttpService) SearchMedia(shopID string, filters map[string]interf
 	return docList, pagination, nil
 }
 
-func (svc MediaHttpService) SearchMediaStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.MediaInfo, int, error) {
+func (svc MediaHttpService) SearchMediaStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.MediaInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -264,9 +265,9 @@ func (svc MediaHttpService) SearchMediaStep(shopID string, langCode string, page
 	return docList, total, nil
 }
 
-func (svc MediaHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Media) (common.BulkImport, error) {
+func (svc MediaHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Media) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Media](dataList, svc.getDocIDKey)
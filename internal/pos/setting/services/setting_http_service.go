@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type ISettingHttpService interface {
-	CreateSetting(shopID string, authUsername string, doc models.Setting) (string, error)
-	UpdateSetting(shopID string, guid string, authUsername string, doc models.Setting) error
-	DeleteSetting(shopID string, guid string, authUsername string) error
-	DeleteSettingByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSetting(shopID string, guid string) (models.SettingInfo, error)
-	InfoSettingByCode(shopID string, code string) (models.SettingInfo, error)
-	SearchSetting(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SettingInfo, mongopagination.PaginationData, error)
-	SearchSettingStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SettingInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Setting) (common.BulkImport, error)
+	CreateSetting(ctx context.Context, shopID string, authUsername string, doc models.Setting) (string, error)
+	UpdateSetting(ctx context.Context, shopID string, guid string, authUsername string, doc models.Setting) error
+	DeleteSetting(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSettingByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSetting(ctx context.Context, shopID string, guid string) (models.SettingInfo, error)
+	InfoSettingByCode(ctx context.Context, shopID string, code string) (models.SettingInfo, error)
+	SearchSetting(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SettingInfo, mongopagination.PaginationData, error)
+	SearchSettingStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SettingInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Setting) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -56,13 +57,13 @@ func NewSettingHttpService(repo repositories.ISettingRepository, syncCacheRepo m
 	return insSvc
 }
 
-func (svc SettingHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SettingHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SettingHttpService) CreateSetting(shopID string, authUsername string, doc models.Setting) (string, error) {
+func (svc SettingHttpService) CreateSetting(ctx context.Context, shopID string, authUsername string, doc models.Setting) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocCode, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -108,9 +109,9 @@ func (svc SettingHttpService) CreateSetting(shopID string, authUsername string,
 	return newGuidFixed, nil
 }
 
-func (svc SettingHttpService) UpdateSetting(shopID string, guid string, authUsername string, doc models.Setting) error {
+func (svc SettingHttpService) UpdateSetting(ctx context.Context, shopID string, guid string, authUsername string, doc models.Setting) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -169,9 +170,9 @@ func (svc SettingHttpService) SetDefaultValue(doc *models.Setting) {
 	}
 }
 
-func (svc SettingHttpService) DeleteSetting(shopID string, guid string, authUsername string) error {
+func (svc SettingHttpService) DeleteSetting(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -194,9 +195,9 @@ func (svc SettingHttpService) DeleteSetting(shopID string, guid string, authUser
 	return nil
 }
 
-func (svc SettingHttpService) DeleteSettingByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SettingHttpService) DeleteSettingByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -211,9 +212,9 @@ func (svc SettingHttpService) DeleteSettingByGUIDs(shopID string, authUsername s
 	return nil
 }
 
-func (svc SettingHttpService) InfoSetting(shopID string, guid string) (models.SettingInfo, error) {
+func (svc SettingHttpService) InfoSetting(ctx context.Context, shopID string, guid string) (models.SettingInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -229,9 +230,9 @@ func (svc SettingHttpService) InfoSetting(shopID string, guid string) (models.Se
 	return findDoc.SettingInfo, nil
 }
 
-func (svc SettingHttpService) InfoSettingByCode(shopID string, code string) (models.SettingInfo, error) {
+func (svc SettingHttpService) InfoSettingByCode(ctx context.Context, shopID string, code string) (models.SettingInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -247,9 +248,9 @@ func (svc SettingHttpService) InfoSettingByCode(shopID string, code string) (mod
 	return findDoc.SettingInfo, nil
 }
 
-func (svc SettingHttpService) SearchSetting(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SettingInfo, mongopagination.PaginationData, error) {
+func (svc SettingHttpService) SearchSetting(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SettingInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -269,9 +270,9 @@ func (svc SettingHttpService) SearchSetting(shopID string, filters map[string]in
 	return docList, pagination, nil
 }
 
-func (svc SettingHttpService) SearchSettingStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SettingInfo, int, error) {
+func (svc SettingHttpService) SearchSettingStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SettingInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -293,9 +294,9 @@ func (svc SettingHttpService) SearchSettingStep(shopID string, langCode string,
 	return docList, total, nil
 }
 
-func (svc SettingHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Setting) (common.BulkImport, error) {
+func (svc SettingHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Setting) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Setting](dataList, svc.getDocIDKey)
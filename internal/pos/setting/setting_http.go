@@ -78,7 +78,7 @@ func (h SettingHttp) CreateSetting(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateSetting(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateSetting(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h SettingHttp) UpdateSetting(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateSetting(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateSetting(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -154,7 +154,7 @@ func (h SettingHttp) DeleteSetting(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSetting(shopID, id, authUsername)
+	err := h.svc.DeleteSetting(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -193,7 +193,7 @@ func (h SettingHttp) DeleteSettingByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteSettingByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSettingByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -223,7 +223,7 @@ func (h SettingHttp) InfoSetting(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Setting %v", id)
-	doc, err := h.svc.InfoSetting(shopID, id)
+	doc, err := h.svc.InfoSetting(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h SettingHttp) InfoSettingByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSettingByCode(shopID, code)
+	doc, err := h.svc.InfoSettingByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h SettingHttp) SearchSettingPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSetting(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchSetting(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -319,7 +319,7 @@ func (h SettingHttp) SearchSettingStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchSettingStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchSettingStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h SettingHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
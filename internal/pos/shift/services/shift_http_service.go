@@ -12,6 +12,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,15 +21,15 @@ import (
 )
 
 type IShiftHttpService interface {
-	CreateShift(shopID string, authUsername string, doc models.Shift) (string, error)
-	UpdateShift(shopID string, guid string, authUsername string, doc models.Shift) error
-	DeleteShift(shopID string, guid string, authUsername string) error
-	DeleteShiftByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoShift(shopID string, guid string) (models.ShiftInfo, error)
-	InfoShiftByCode(shopID string, code string) (models.ShiftInfo, error)
-	SearchShift(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShiftInfo, mongopagination.PaginationData, error)
-	SearchShiftStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ShiftInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Shift) (common.BulkImport, error)
+	CreateShift(ctx context.Context, shopID string, authUsername string, doc models.Shift) (string, error)
+	UpdateShift(ctx context.Context, shopID string, guid string, authUsername string, doc models.Shift) error
+	DeleteShift(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteShiftByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoShift(ctx context.Context, shopID string, guid string) (models.ShiftInfo, error)
+	InfoShiftByCode(ctx context.Context, shopID string, code string) (models.ShiftInfo, error)
+	SearchShift(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShiftInfo, mongopagination.PaginationData, error)
+	SearchShiftStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ShiftInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Shift) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -62,13 +63,13 @@ func NewShiftHttpService(
 	return insSvc
 }
 
-func (svc ShiftHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ShiftHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ShiftHttpService) CreateShift(shopID string, authUsername string, doc models.Shift) (string, error) {
+func (svc ShiftHttpService) CreateShift(ctx context.Context, shopID string, authUsername string, doc models.Shift) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", doc.DocNo)
@@ -108,9 +109,9 @@ func (svc ShiftHttpService) CreateShift(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc ShiftHttpService) UpdateShift(shopID string, guid string, authUsername string, doc models.Shift) error {
+func (svc ShiftHttpService) UpdateShift(ctx context.Context, shopID string, guid string, authUsername string, doc models.Shift) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -145,9 +146,9 @@ func (svc ShiftHttpService) UpdateShift(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc ShiftHttpService) DeleteShift(shopID string, guid string, authUsername string) error {
+func (svc ShiftHttpService) DeleteShift(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -176,9 +177,9 @@ func (svc ShiftHttpService) DeleteShift(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc ShiftHttpService) DeleteShiftByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ShiftHttpService) DeleteShiftByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -205,9 +206,9 @@ func (svc ShiftHttpService) DeleteShiftByGUIDs(shopID string, authUsername strin
 	return nil
 }
 
-func (svc ShiftHttpService) InfoShift(shopID string, guid string) (models.ShiftInfo, error) {
+func (svc ShiftHttpService) InfoShift(ctx context.Context, shopID string, guid string) (models.ShiftInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -223,9 +224,9 @@ func (svc ShiftHttpService) InfoShift(shopID string, guid string) (models.ShiftI
 	return findDoc.ShiftInfo, nil
 }
 
-func (svc ShiftHttpService) InfoShiftByCode(shopID string, code string) (models.ShiftInfo, error) {
+func (svc ShiftHttpService) InfoShiftByCode(ctx context.Context, shopID string, code string) (models.ShiftInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -241,9 +242,9 @@ func (svc ShiftHttpService) InfoShiftByCode(shopID string, code string) (models.
 	return findDoc.ShiftInfo, nil
 }
 
-func (svc ShiftHttpService) SearchShift(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShiftInfo, mongopagination.PaginationData, error) {
+func (svc ShiftHttpService) SearchShift(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShiftInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -261,9 +262,9 @@ func (svc ShiftHttpService) SearchShift(shopID string, filters map[string]interf
 	return docList, pagination, nil
 }
 
-func (svc ShiftHttpService) SearchShiftStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ShiftInfo, int, error) {
+func (svc ShiftHttpService) SearchShiftStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ShiftInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -291,9 +292,9 @@ func (svc ShiftHttpService) SearchShiftStep(shopID string, langCode string, filt
 	return docList, total, nil
 }
 
-func (svc ShiftHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Shift) (common.BulkImport, error) {
+func (svc ShiftHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Shift) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Shift](dataList, svc.getDocIDKey)
@@ -82,7 +82,7 @@ func (h ShiftHttp) CreateShift(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateShift(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateShift(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -127,7 +127,7 @@ func (h ShiftHttp) UpdateShift(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateShift(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateShift(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -158,7 +158,7 @@ func (h ShiftHttp) DeleteShift(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteShift(shopID, id, authUsername)
+	err := h.svc.DeleteShift(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -197,7 +197,7 @@ func (h ShiftHttp) DeleteShiftByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteShiftByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteShiftByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -227,7 +227,7 @@ func (h ShiftHttp) InfoShift(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Shift %v", id)
-	doc, err := h.svc.InfoShift(shopID, id)
+	doc, err := h.svc.InfoShift(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -257,7 +257,7 @@ func (h ShiftHttp) InfoShiftByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoShiftByCode(shopID, code)
+	doc, err := h.svc.InfoShiftByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -296,7 +296,7 @@ func (h ShiftHttp) SearchShiftPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchShift(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchShift(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -339,7 +339,7 @@ func (h ShiftHttp) SearchShiftStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchShiftStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchShiftStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -379,7 +379,7 @@ func (h ShiftHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
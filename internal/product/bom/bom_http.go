@@ -82,7 +82,7 @@ func (h BOMHttp) CreateBOM(ctx microservice.IContext) error {
 		return nil
 	}
 
-	idx, err := h.svc.UpsertBOM(shopID, authUsername, docNo, barcode)
+	idx, err := h.svc.UpsertBOM(ctx.Context(), shopID, authUsername, docNo, barcode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -112,7 +112,7 @@ func (h BOMHttp) DeleteBOM(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBOM(shopID, id, authUsername)
+	err := h.svc.DeleteBOM(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -143,7 +143,7 @@ func (h BOMHttp) InfoBOM(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get BOM %v", id)
-	doc, err := h.svc.InfoBOM(shopID, id)
+	doc, err := h.svc.InfoBOM(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -178,7 +178,7 @@ func (h BOMHttp) SearchBOMPage(ctx microservice.IContext) error {
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchBOM(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchBOM(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -216,7 +216,7 @@ func (h BOMHttp) SearchBOMStep(ctx microservice.IContext) error {
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchBOMStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchBOMStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/utils/checksum"
 	"time"
 
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 
 	product_models "smlaicloudplatform/internal/product/productbarcode/models"
@@ -25,11 +26,11 @@ import (
 )
 
 type IBOMHttpService interface {
-	UpsertBOM(shopID string, authUsername string, dcoNo string, barcode string) (string, error)
-	DeleteBOM(shopID string, guid string, authUsername string) error
-	InfoBOM(shopID string, guid string) (models.ProductBarcodeBOMViewInfo, error)
-	SearchBOM(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeBOMViewInfo, mongopagination.PaginationData, error)
-	SearchBOMStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeBOMViewInfo, int, error)
+	UpsertBOM(ctx context.Context, shopID string, authUsername string, dcoNo string, barcode string) (string, error)
+	DeleteBOM(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoBOM(ctx context.Context, shopID string, guid string) (models.ProductBarcodeBOMViewInfo, error)
+	SearchBOM(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeBOMViewInfo, mongopagination.PaginationData, error)
+	SearchBOMStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeBOMViewInfo, int, error)
 }
 
 type BOMHttpService struct {
@@ -58,12 +59,12 @@ func NewBOMHttpService(repo repositories.IBomRepository, repoMq repositories.IBo
 	return insSvc
 }
 
-func (svc BOMHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BOMHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BOMHttpService) UpsertBOM(shopID string, authUsername string, docNo string, barcode string) (string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc BOMHttpService) UpsertBOM(ctx context.Context, shopID string, authUsername string, docNo string, barcode string) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	productBarcodeDict := map[string]product_models.ProductBarcodeDoc{}
@@ -166,7 +167,7 @@ func (svc BOMHttpService) UpsertBOM(shopID string, authUsername string, docNo st
 			return newGUID, err
 		}
 
-		_, err = svc.saleInvoiceBomSvc.CreateSaleInvoiceBomPrice(shopID, authUsername, docNo, newGUID, bomBarcodes)
+		_, err = svc.saleInvoiceBomSvc.CreateSaleInvoiceBomPrice(ctx, shopID, authUsername, docNo, newGUID, bomBarcodes)
 
 		if err != nil {
 			return newGUID, err
@@ -175,7 +176,7 @@ func (svc BOMHttpService) UpsertBOM(shopID string, authUsername string, docNo st
 		return newGUID, nil
 	}
 
-	_, err = svc.saleInvoiceBomSvc.CreateSaleInvoiceBomPrice(shopID, authUsername, docNo, findDoc.GuidFixed, bomBarcodes)
+	_, err = svc.saleInvoiceBomSvc.CreateSaleInvoiceBomPrice(ctx, shopID, authUsername, docNo, findDoc.GuidFixed, bomBarcodes)
 
 	if err != nil {
 		return findDoc.GuidFixed, err
@@ -223,9 +224,9 @@ func (svc BOMHttpService) create(ctx context.Context, shopID string, authUsernam
 	return newGuidFixed, nil
 }
 
-func (svc BOMHttpService) DeleteBOM(shopID string, guid string, authUsername string) error {
+func (svc BOMHttpService) DeleteBOM(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -254,9 +255,9 @@ func (svc BOMHttpService) DeleteBOM(shopID string, guid string, authUsername str
 	return nil
 }
 
-func (svc BOMHttpService) InfoBOM(shopID string, guid string) (models.ProductBarcodeBOMViewInfo, error) {
+func (svc BOMHttpService) InfoBOM(ctx context.Context, shopID string, guid string) (models.ProductBarcodeBOMViewInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -273,9 +274,9 @@ func (svc BOMHttpService) InfoBOM(shopID string, guid string) (models.ProductBar
 
 }
 
-func (svc BOMHttpService) SearchBOM(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeBOMViewInfo, mongopagination.PaginationData, error) {
+func (svc BOMHttpService) SearchBOM(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeBOMViewInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -299,9 +300,9 @@ func (svc BOMHttpService) SearchBOM(shopID string, filters map[string]interface{
 	return docList, pagination, nil
 }
 
-func (svc BOMHttpService) SearchBOMStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeBOMViewInfo, int, error) {
+func (svc BOMHttpService) SearchBOMStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeBOMViewInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -73,7 +73,7 @@ func (h ColorHttp) CreateColor(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateColor(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateColor(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -118,7 +118,7 @@ func (h ColorHttp) UpdateColor(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateColor(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateColor(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -149,7 +149,7 @@ func (h ColorHttp) DeleteColor(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteColor(shopID, id, authUsername)
+	err := h.svc.DeleteColor(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -180,7 +180,7 @@ func (h ColorHttp) InfoColor(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Color %v", id)
-	doc, err := h.svc.InfoColor(shopID, id)
+	doc, err := h.svc.InfoColor(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -219,7 +219,7 @@ func (h ColorHttp) InfoArray(ctx microservice.IContext) error {
 	}
 
 	// where to filter array
-	doc, err := h.svc.InfoWTFArray(shopID, *docReq)
+	doc, err := h.svc.InfoWTFArray(ctx.Context(), shopID, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -249,7 +249,7 @@ func (h ColorHttp) SearchColor(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchColor(shopID, pageable)
+	docList, pagination, err := h.svc.SearchColor(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h ColorHttp) SearchColorStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchColorStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchColorStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -324,7 +324,7 @@ func (h ColorHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
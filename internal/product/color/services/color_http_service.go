@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/product/color/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,14 +18,14 @@ import (
 )
 
 type IColorHttpService interface {
-	CreateColor(shopID string, authUsername string, doc models.Color) (string, error)
-	UpdateColor(shopID string, guid string, authUsername string, doc models.Color) error
-	DeleteColor(shopID string, guid string, authUsername string) error
-	InfoColor(shopID string, guid string) (models.ColorInfo, error)
-	InfoWTFArray(shopID string, codes []string) ([]interface{}, error)
-	SearchColor(shopID string, pageable micromodels.Pageable) ([]models.ColorInfo, mongopagination.PaginationData, error)
-	SearchColorStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ColorInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Color) (common.BulkImport, error)
+	CreateColor(ctx context.Context, shopID string, authUsername string, doc models.Color) (string, error)
+	UpdateColor(ctx context.Context, shopID string, guid string, authUsername string, doc models.Color) error
+	DeleteColor(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoColor(ctx context.Context, shopID string, guid string) (models.ColorInfo, error)
+	InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error)
+	SearchColor(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ColorInfo, mongopagination.PaginationData, error)
+	SearchColorStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ColorInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Color) (common.BulkImport, error)
 }
 
 type ColorHttpService struct {
@@ -42,13 +43,13 @@ func NewColorHttpService(repo repositories.IColorRepository) *ColorHttpService {
 	}
 }
 
-func (svc ColorHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ColorHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ColorHttpService) CreateColor(shopID string, authUsername string, doc models.Color) (string, error) {
+func (svc ColorHttpService) CreateColor(ctx context.Context, shopID string, authUsername string, doc models.Color) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -80,9 +81,9 @@ func (svc ColorHttpService) CreateColor(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc ColorHttpService) UpdateColor(shopID string, guid string, authUsername string, doc models.Color) error {
+func (svc ColorHttpService) UpdateColor(ctx context.Context, shopID string, guid string, authUsername string, doc models.Color) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -109,9 +110,9 @@ func (svc ColorHttpService) UpdateColor(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc ColorHttpService) DeleteColor(shopID string, guid string, authUsername string) error {
+func (svc ColorHttpService) DeleteColor(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -132,9 +133,9 @@ func (svc ColorHttpService) DeleteColor(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc ColorHttpService) InfoColor(shopID string, guid string) (models.ColorInfo, error) {
+func (svc ColorHttpService) InfoColor(ctx context.Context, shopID string, guid string) (models.ColorInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc ColorHttpService) InfoColor(shopID string, guid string) (models.ColorI
 
 }
 
-func (svc ColorHttpService) InfoWTFArray(shopID string, codes []string) ([]interface{}, error) {
+func (svc ColorHttpService) InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -171,9 +172,9 @@ func (svc ColorHttpService) InfoWTFArray(shopID string, codes []string) ([]inter
 	return docList, nil
 }
 
-func (svc ColorHttpService) SearchColor(shopID string, pageable micromodels.Pageable) ([]models.ColorInfo, mongopagination.PaginationData, error) {
+func (svc ColorHttpService) SearchColor(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ColorInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -190,9 +191,9 @@ func (svc ColorHttpService) SearchColor(shopID string, pageable micromodels.Page
 	return docList, pagination, nil
 }
 
-func (svc ColorHttpService) SearchColorStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ColorInfo, int, error) {
+func (svc ColorHttpService) SearchColorStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ColorInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -225,9 +226,9 @@ func (svc ColorHttpService) SearchColorStep(shopID string, langCode string, page
 	return docList, total, nil
 }
 
-func (svc ColorHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Color) (common.BulkImport, error) {
+func (svc ColorHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Color) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Color](dataList, svc.getDocIDKey)
@@ -82,7 +82,7 @@ func NewEOrderHttp(ms *microservice.Microservice, cfg config.IConfig) EOrderHttp
 	svcZone := zone.NewZoneService(repoZone, masterSyncCacheRepo)
 
 	repoSaleInvoice := saleinvoice_repositories.NewSaleInvoiceRepository(pst)
-	svcSaleInvoice := saleinvoice_services.NewSaleInvoiceService(repoSaleInvoice, nil, nil, nil, nil, nil, nil)
+	svcSaleInvoice := saleinvoice_services.NewSaleInvoiceService(repoSaleInvoice, nil, nil, nil, nil, nil, nil, nil)
 
 	svcTable := table.NewTableService(repoTable, masterSyncCacheRepo)
 	svcKitchen := kitchen.NewKitchenService(repoKitchen, masterSyncCacheRepo)
@@ -160,7 +160,7 @@ func (h EOrderHttp) SearchProductCategoryPage(ctx microservice.IContext) error {
 			Type:  requestfilter.FieldTypeInt,
 		},
 	})
-	docList, pagination, err := h.svcCategory.SearchProductCategory(shopID, filters, pageable)
+	docList, pagination, err := h.svcCategory.SearchProductCategory(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h EOrderHttp) SearchProductBarcodePage(ctx microservice.IContext) error {
 	}
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svcProduct.SearchProductBarcode(shopID, filters, pageable)
+	docList, pagination, err := h.svcProduct.SearchProductBarcode(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -284,7 +284,7 @@ func (h EOrderHttp) SearchProductBarcodeManyBarcodePage(ctx microservice.IContex
 	}
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svcProduct.SearchProductBarcode(shopID, filters, pageable)
+	docList, pagination, err := h.svcProduct.SearchProductBarcode(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -323,7 +323,7 @@ func (h EOrderHttp) GetProductBarcodeByBarcodes(ctx microservice.IContext) error
 		json.Unmarshal([]byte(rawBarcodes), &barcodes)
 	}
 
-	docList, err := h.svcProduct.GetProductBarcodeByBarcodes(shopID, barcodes)
+	docList, err := h.svcProduct.GetProductBarcodeByBarcodes(ctx.Context(), shopID, barcodes)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -355,7 +355,7 @@ func (h EOrderHttp) ShopInfoOld(ctx microservice.IContext) error {
 		return nil
 	}
 
-	data, err := h.svcEOrder.GetShopInfoOld(shopID, orderStationCode)
+	data, err := h.svcEOrder.GetShopInfoOld(ctx.Context(), shopID, orderStationCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -387,7 +387,7 @@ func (h EOrderHttp) ShopInfo(ctx microservice.IContext) error {
 		return nil
 	}
 
-	data, err := h.svcEOrder.GetShopInfo(shopID, orderStationCode)
+	data, err := h.svcEOrder.GetShopInfo(ctx.Context(), shopID, orderStationCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -429,7 +429,7 @@ func (h EOrderHttp) SearchZone(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svcZone.SearchZone(shopID, filters, pageable)
+	docList, pagination, err := h.svcZone.SearchZone(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -473,7 +473,7 @@ func (h EOrderHttp) SearchKitchen(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svcKitchen.SearchKitchen(shopID, filters, pageable)
+	docList, pagination, err := h.svcKitchen.SearchKitchen(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -517,7 +517,7 @@ func (h EOrderHttp) SearchTable(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svcTable.SearchTable(shopID, filters, pageable)
+	docList, pagination, err := h.svcTable.SearchTable(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -556,7 +556,7 @@ func (h EOrderHttp) GetLastPOSDocNo(ctx microservice.IContext) error {
 		return nil
 	}
 
-	doc, err := h.svcSaleInvoice.GetLastPOSDocNo(shopID, posID, maxDocNo)
+	doc, err := h.svcSaleInvoice.GetLastPOSDocNo(ctx.Context(), shopID, posID, maxDocNo)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -606,7 +606,7 @@ func (h EOrderHttp) Notify(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svcNotify.SearchNotifyInfo(shopID, filters, pageable)
+	docList, pagination, err := h.svcNotify.SearchNotifyInfo(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
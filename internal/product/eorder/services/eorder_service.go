@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/restaurant/kitchen"
 	"smlaicloudplatform/internal/restaurant/table"
 	"smlaicloudplatform/internal/shop"
+	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
 
@@ -55,12 +56,12 @@ func NewEOrderService(
 	}
 }
 
-func (svc EOrderService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc EOrderService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc EOrderService) GetShopInfoOld(shopID string, orderStationCode string) (models.EOrderShopOld, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc EOrderService) GetShopInfoOld(ctx context.Context, shopID string, orderStationCode string) (models.EOrderShopOld, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	result := models.EOrderShopOld{}
@@ -160,8 +161,8 @@ func (svc EOrderService) GetShopInfoOld(shopID string, orderStationCode string)
 	return result, nil
 }
 
-func (svc EOrderService) GetShopInfo(shopID string, orderStationCode string) (models.EOrderShop, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc EOrderService) GetShopInfo(ctx context.Context, shopID string, orderStationCode string) (models.EOrderShop, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	result := models.EOrderShop{}
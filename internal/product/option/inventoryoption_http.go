@@ -64,7 +64,7 @@ func (h *OptionHttp) CreateInventoryOptionMain(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.optService.CreateOption(shopID, authUsername, *docReq)
+	idx, err := h.optService.CreateOption(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -104,7 +104,7 @@ func (h *OptionHttp) UpdateInventoryOptionMain(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.optService.UpdateOption(shopID, id, authUsername, *docReq)
+	err = h.optService.UpdateOption(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -135,7 +135,7 @@ func (h *OptionHttp) DeleteInventoryOptionMain(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.optService.DeleteOption(shopID, id, authUsername)
+	err := h.optService.DeleteOption(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -165,7 +165,7 @@ func (h *OptionHttp) InfoInventoryOptionMain(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	doc, err := h.optService.InfoOption(shopID, id)
+	doc, err := h.optService.InfoOption(ctx.Context(), shopID, id)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -208,7 +208,7 @@ func (h OptionHttp) InfoArray(ctx microservice.IContext) error {
 	}
 
 	// where to filter array
-	doc, err := h.optService.InfoWTFArray(shopID, docReq)
+	doc, err := h.optService.InfoWTFArray(ctx.Context(), shopID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -239,7 +239,7 @@ func (h *OptionHttp) SearchInventoryOptionMain(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.optService.SearchOption(shopID, pageable)
+	docList, pagination, err := h.optService.SearchOption(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"smlaicloudplatform/internal/product/option/models"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -13,12 +14,12 @@ import (
 )
 
 type IOptionService interface {
-	CreateOption(shopID string, authUsername string, invOpt models.InventoryOptionMain) (string, error)
-	UpdateOption(shopID string, guid string, authUsername string, invOpt models.InventoryOptionMain) error
-	DeleteOption(shopID string, guid string, username string) error
-	InfoOption(shopID string, guid string) (models.InventoryOptionMainInfo, error)
-	InfoWTFArray(shopID string, codes []string) ([]interface{}, error)
-	SearchOption(shopID string, pageable micromodels.Pageable) ([]models.InventoryOptionMainInfo, mongopagination.PaginationData, error)
+	CreateOption(ctx context.Context, shopID string, authUsername string, invOpt models.InventoryOptionMain) (string, error)
+	UpdateOption(ctx context.Context, shopID string, guid string, authUsername string, invOpt models.InventoryOptionMain) error
+	DeleteOption(ctx context.Context, shopID string, guid string, username string) error
+	InfoOption(ctx context.Context, shopID string, guid string) (models.InventoryOptionMainInfo, error)
+	InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error)
+	SearchOption(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.InventoryOptionMainInfo, mongopagination.PaginationData, error)
 }
 
 type OptionService struct {
@@ -36,13 +37,13 @@ func NewOptionService(inventoryOptionRepository IOptionRepository) OptionService
 	}
 }
 
-func (svc OptionService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc OptionService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc OptionService) CreateOption(shopID string, authUsername string, invOpt models.InventoryOptionMain) (string, error) {
+func (svc OptionService) CreateOption(ctx context.Context, shopID string, authUsername string, invOpt models.InventoryOptionMain) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -69,9 +70,9 @@ func (svc OptionService) CreateOption(shopID string, authUsername string, invOpt
 	return newGuidFixed, nil
 }
 
-func (svc OptionService) UpdateOption(shopID string, guid string, authUsername string, invOpt models.InventoryOptionMain) error {
+func (svc OptionService) UpdateOption(ctx context.Context, shopID string, guid string, authUsername string, invOpt models.InventoryOptionMain) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -101,9 +102,9 @@ func (svc OptionService) UpdateOption(shopID string, guid string, authUsername s
 	return nil
 }
 
-func (svc OptionService) DeleteOption(shopID string, guid string, username string) error {
+func (svc OptionService) DeleteOption(ctx context.Context, shopID string, guid string, username string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.Delete(ctx, shopID, guid, username)
@@ -115,9 +116,9 @@ func (svc OptionService) DeleteOption(shopID string, guid string, username strin
 	return nil
 }
 
-func (svc OptionService) InfoOption(shopID string, guid string) (models.InventoryOptionMainInfo, error) {
+func (svc OptionService) InfoOption(ctx context.Context, shopID string, guid string) (models.InventoryOptionMainInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -133,9 +134,9 @@ func (svc OptionService) InfoOption(shopID string, guid string) (models.Inventor
 	return findDoc.InventoryOptionMainInfo, nil
 }
 
-func (svc OptionService) InfoWTFArray(shopID string, codes []string) ([]interface{}, error) {
+func (svc OptionService) InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -153,9 +154,9 @@ func (svc OptionService) InfoWTFArray(shopID string, codes []string) ([]interfac
 	return docList, nil
 }
 
-func (svc OptionService) SearchOption(shopID string, pageable micromodels.Pageable) ([]models.InventoryOptionMainInfo, mongopagination.PaginationData, error) {
+func (svc OptionService) SearchOption(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.InventoryOptionMainInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, pagination, err := svc.repo.FindPage(ctx, shopID, pageable)
@@ -67,7 +67,7 @@ func (h OptionPatternHttp) CreateOptionPattern(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, err := h.svc.CreateOptionPattern(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateOptionPattern(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -107,7 +107,7 @@ func (h OptionPatternHttp) UpdateOptionPattern(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdateOptionPattern(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateOptionPattern(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -138,7 +138,7 @@ func (h OptionPatternHttp) DeleteOptionPattern(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteOptionPattern(shopID, id, authUsername)
+	err := h.svc.DeleteOptionPattern(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -169,7 +169,7 @@ func (h OptionPatternHttp) InfoOptionPattern(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get OptionPattern %v", id)
-	doc, err := h.svc.InfoOptionPattern(shopID, id)
+	doc, err := h.svc.InfoOptionPattern(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -200,7 +200,7 @@ func (h OptionPatternHttp) SearchOptionPattern(ctx microservice.IContext) error
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchOptionPattern(shopID, pageable)
+	docList, pagination, err := h.svc.SearchOptionPattern(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -240,7 +240,7 @@ func (h OptionPatternHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
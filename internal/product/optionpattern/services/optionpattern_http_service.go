@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/product/optionpattern/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -16,12 +17,12 @@ import (
 )
 
 type IOptionPatternHttpService interface {
-	CreateOptionPattern(shopID string, authUsername string, doc models.OptionPattern) (string, error)
-	UpdateOptionPattern(shopID string, guid string, authUsername string, doc models.OptionPattern) error
-	DeleteOptionPattern(shopID string, guid string, authUsername string) error
-	InfoOptionPattern(shopID string, guid string) (models.OptionPatternInfo, error)
-	SearchOptionPattern(shopID string, pageable micromodels.Pageable) ([]models.OptionPatternInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.OptionPattern) (common.BulkImport, error)
+	CreateOptionPattern(ctx context.Context, shopID string, authUsername string, doc models.OptionPattern) (string, error)
+	UpdateOptionPattern(ctx context.Context, shopID string, guid string, authUsername string, doc models.OptionPattern) error
+	DeleteOptionPattern(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoOptionPattern(ctx context.Context, shopID string, guid string) (models.OptionPatternInfo, error)
+	SearchOptionPattern(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.OptionPatternInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OptionPattern) (common.BulkImport, error)
 }
 
 type OptionPatternHttpService struct {
@@ -39,13 +40,13 @@ func NewOptionPatternHttpService(repo repositories.IOptionPatternRepository) *Op
 	}
 }
 
-func (svc OptionPatternHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc OptionPatternHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc OptionPatternHttpService) CreateOptionPattern(shopID string, authUsername string, doc models.OptionPattern) (string, error) {
+func (svc OptionPatternHttpService) CreateOptionPattern(ctx context.Context, shopID string, authUsername string, doc models.OptionPattern) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "patterncode", doc.PatternCode)
@@ -81,9 +82,9 @@ func (svc OptionPatternHttpService) CreateOptionPattern(shopID string, authUsern
 	return newGuidFixed, nil
 }
 
-func (svc OptionPatternHttpService) UpdateOptionPattern(shopID string, guid string, authUsername string, doc models.OptionPattern) error {
+func (svc OptionPatternHttpService) UpdateOptionPattern(ctx context.Context, shopID string, guid string, authUsername string, doc models.OptionPattern) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -110,9 +111,9 @@ func (svc OptionPatternHttpService) UpdateOptionPattern(shopID string, guid stri
 	return nil
 }
 
-func (svc OptionPatternHttpService) DeleteOptionPattern(shopID string, guid string, authUsername string) error {
+func (svc OptionPatternHttpService) DeleteOptionPattern(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -133,9 +134,9 @@ func (svc OptionPatternHttpService) DeleteOptionPattern(shopID string, guid stri
 	return nil
 }
 
-func (svc OptionPatternHttpService) InfoOptionPattern(shopID string, guid string) (models.OptionPatternInfo, error) {
+func (svc OptionPatternHttpService) InfoOptionPattern(ctx context.Context, shopID string, guid string) (models.OptionPatternInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -152,9 +153,9 @@ func (svc OptionPatternHttpService) InfoOptionPattern(shopID string, guid string
 
 }
 
-func (svc OptionPatternHttpService) SearchOptionPattern(shopID string, pageable micromodels.Pageable) ([]models.OptionPatternInfo, mongopagination.PaginationData, error) {
+func (svc OptionPatternHttpService) SearchOptionPattern(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.OptionPatternInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -171,9 +172,9 @@ func (svc OptionPatternHttpService) SearchOptionPattern(shopID string, pageable
 	return docList, pagination, nil
 }
 
-func (svc OptionPatternHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.OptionPattern) (common.BulkImport, error) {
+func (svc OptionPatternHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OptionPattern) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.OptionPattern](dataList, svc.getDocIDKey)
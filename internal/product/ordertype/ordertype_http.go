@@ -82,7 +82,7 @@ func (h OrderTypeHttp) CreateOrderType(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateOrderType(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateOrderType(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -127,7 +127,7 @@ func (h OrderTypeHttp) UpdateOrderType(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateOrderType(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateOrderType(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -158,7 +158,7 @@ func (h OrderTypeHttp) DeleteOrderType(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteOrderType(shopID, id, authUsername)
+	err := h.svc.DeleteOrderType(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -197,7 +197,7 @@ func (h OrderTypeHttp) DeleteOrderTypeByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteOrderTypeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteOrderTypeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -227,7 +227,7 @@ func (h OrderTypeHttp) InfoOrderType(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get OrderType %v", id)
-	doc, err := h.svc.InfoOrderType(shopID, id)
+	doc, err := h.svc.InfoOrderType(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -257,7 +257,7 @@ func (h OrderTypeHttp) InfoOrderTypeByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoOrderTypeByCode(shopID, code)
+	doc, err := h.svc.InfoOrderTypeByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h OrderTypeHttp) SearchOrderTypePage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchOrderType(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchOrderType(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -323,7 +323,7 @@ func (h OrderTypeHttp) SearchOrderTypeStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchOrderTypeStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchOrderTypeStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -363,7 +363,7 @@ func (h OrderTypeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
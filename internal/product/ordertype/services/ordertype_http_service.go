@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type IOrderTypeHttpService interface {
-	CreateOrderType(shopID string, authUsername string, doc models.OrderType) (string, error)
-	UpdateOrderType(shopID string, guid string, authUsername string, doc models.OrderType) error
-	DeleteOrderType(shopID string, guid string, authUsername string) error
-	DeleteOrderTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoOrderType(shopID string, guid string) (models.OrderTypeInfo, error)
-	InfoOrderTypeByCode(shopID string, code string) (models.OrderTypeInfo, error)
-	SearchOrderType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderTypeInfo, mongopagination.PaginationData, error)
-	SearchOrderTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.OrderTypeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.OrderType) (common.BulkImport, error)
+	CreateOrderType(ctx context.Context, shopID string, authUsername string, doc models.OrderType) (string, error)
+	UpdateOrderType(ctx context.Context, shopID string, guid string, authUsername string, doc models.OrderType) error
+	DeleteOrderType(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteOrderTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoOrderType(ctx context.Context, shopID string, guid string) (models.OrderTypeInfo, error)
+	InfoOrderTypeByCode(ctx context.Context, shopID string, code string) (models.OrderTypeInfo, error)
+	SearchOrderType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderTypeInfo, mongopagination.PaginationData, error)
+	SearchOrderTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.OrderTypeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OrderType) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -65,13 +66,13 @@ func NewOrderTypeHttpService(
 	return insSvc
 }
 
-func (svc OrderTypeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc OrderTypeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc OrderTypeHttpService) CreateOrderType(shopID string, authUsername string, doc models.OrderType) (string, error) {
+func (svc OrderTypeHttpService) CreateOrderType(ctx context.Context, shopID string, authUsername string, doc models.OrderType) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -113,9 +114,9 @@ func (svc OrderTypeHttpService) CreateOrderType(shopID string, authUsername stri
 	return newGuidFixed, nil
 }
 
-func (svc OrderTypeHttpService) UpdateOrderType(shopID string, guid string, authUsername string, doc models.OrderType) error {
+func (svc OrderTypeHttpService) UpdateOrderType(ctx context.Context, shopID string, guid string, authUsername string, doc models.OrderType) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -154,9 +155,9 @@ func (svc OrderTypeHttpService) UpdateOrderType(shopID string, guid string, auth
 	return nil
 }
 
-func (svc OrderTypeHttpService) DeleteOrderType(shopID string, guid string, authUsername string) error {
+func (svc OrderTypeHttpService) DeleteOrderType(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -169,7 +170,7 @@ func (svc OrderTypeHttpService) DeleteOrderType(shopID string, guid string, auth
 		return nil
 	}
 
-	existsInProduct, _ := svc.existsOrderTypeRefInProduct(shopID, []string{guid})
+	existsInProduct, _ := svc.existsOrderTypeRefInProduct(ctx, shopID, []string{guid})
 
 	if existsInProduct {
 		return fmt.Errorf("\"%s\" is referenced in product barcode", findDoc.Code)
@@ -193,12 +194,12 @@ func (svc OrderTypeHttpService) DeleteOrderType(shopID string, guid string, auth
 	return nil
 }
 
-func (svc OrderTypeHttpService) DeleteOrderTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc OrderTypeHttpService) DeleteOrderTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	existsInProduct, _ := svc.existsOrderTypeRefInProduct(shopID, GUIDs)
+	existsInProduct, _ := svc.existsOrderTypeRefInProduct(ctx, shopID, GUIDs)
 
 	if existsInProduct {
 		return fmt.Errorf("referenced in product")
@@ -229,9 +230,9 @@ func (svc OrderTypeHttpService) DeleteOrderTypeByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc OrderTypeHttpService) InfoOrderType(shopID string, guid string) (models.OrderTypeInfo, error) {
+func (svc OrderTypeHttpService) InfoOrderType(ctx context.Context, shopID string, guid string) (models.OrderTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -247,9 +248,9 @@ func (svc OrderTypeHttpService) InfoOrderType(shopID string, guid string) (model
 	return findDoc.OrderTypeInfo, nil
 }
 
-func (svc OrderTypeHttpService) InfoOrderTypeByCode(shopID string, code string) (models.OrderTypeInfo, error) {
+func (svc OrderTypeHttpService) InfoOrderTypeByCode(ctx context.Context, shopID string, code string) (models.OrderTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -265,9 +266,9 @@ func (svc OrderTypeHttpService) InfoOrderTypeByCode(shopID string, code string)
 	return findDoc.OrderTypeInfo, nil
 }
 
-func (svc OrderTypeHttpService) SearchOrderType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderTypeInfo, mongopagination.PaginationData, error) {
+func (svc OrderTypeHttpService) SearchOrderType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OrderTypeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -284,9 +285,9 @@ func (svc OrderTypeHttpService) SearchOrderType(shopID string, filters map[strin
 	return docList, pagination, nil
 }
 
-func (svc OrderTypeHttpService) SearchOrderTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.OrderTypeInfo, int, error) {
+func (svc OrderTypeHttpService) SearchOrderTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.OrderTypeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -305,9 +306,9 @@ func (svc OrderTypeHttpService) SearchOrderTypeStep(shopID string, langCode stri
 	return docList, total, nil
 }
 
-func (svc OrderTypeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.OrderType) (common.BulkImport, error) {
+func (svc OrderTypeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.OrderType) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.OrderType](dataList, svc.getDocIDKey)
@@ -434,9 +435,9 @@ func (svc OrderTypeHttpService) GetModuleName() string {
 	return "ordertype"
 }
 
-func (svc OrderTypeHttpService) existsOrderTypeRefInProduct(shopID string, GUIDs []string) (bool, error) {
+func (svc OrderTypeHttpService) existsOrderTypeRefInProduct(ctx context.Context, shopID string, GUIDs []string) (bool, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docCount, err := svc.repoProductBarcode.CountByOrderTypes(ctx, shopID, GUIDs)
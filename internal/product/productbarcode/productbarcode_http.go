@@ -119,7 +119,7 @@ func (h ProductBarcodeHttp) CreateProductBarcode(ctx microservice.IContext) erro
 		return err
 	}
 
-	idx, err := h.svc.CreateProductBarcode(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateProductBarcode(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -168,7 +168,7 @@ func (h ProductBarcodeHttp) UpdateProductBarcode(ctx microservice.IContext) erro
 		return err
 	}
 
-	err = h.svc.UpdateProductBarcode(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateProductBarcode(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -212,7 +212,7 @@ func (h ProductBarcodeHttp) UpdateProductBarcodeBranch(ctx microservice.IContext
 		return err
 	}
 
-	err = h.svc.UpdateProductBarcodeBranch(shopID, authUsername, docReq.Branch, docReq.Products)
+	err = h.svc.UpdateProductBarcodeBranch(ctx.Context(), shopID, authUsername, docReq.Branch, docReq.Products)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -255,7 +255,7 @@ func (h ProductBarcodeHttp) UpdateProductBarcodeBusinessType(ctx microservice.IC
 		return err
 	}
 
-	err = h.svc.UpdateProductBarcodeBusinessType(shopID, authUsername, docReq.BusinessType, docReq.Products)
+	err = h.svc.UpdateProductBarcodeBusinessType(ctx.Context(), shopID, authUsername, docReq.BusinessType, docReq.Products)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -298,7 +298,7 @@ func (h ProductBarcodeHttp) UpdateProductBarcodeXSort(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.XSortsSave(shopID, authUsername, *req)
+	err = h.svc.XSortsSave(ctx.Context(), shopID, authUsername, *req)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -328,7 +328,7 @@ func (h ProductBarcodeHttp) DeleteProductBarcode(ctx microservice.IContext) erro
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteProductBarcode(shopID, id, authUsername)
+	err := h.svc.DeleteProductBarcode(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h ProductBarcodeHttp) InfoProductBarcode(ctx microservice.IContext) error
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ProductBarcode %v", id)
-	doc, err := h.svc.InfoProductBarcode(shopID, id)
+	doc, err := h.svc.InfoProductBarcode(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -389,7 +389,7 @@ func (h ProductBarcodeHttp) GetroductBarcodeByRef(ctx microservice.IContext) err
 
 	refBarcode := ctx.Param("barcode")
 
-	docs, err := h.svc.GetProductBarcodeByBarcodeRef(shopID, refBarcode)
+	docs, err := h.svc.GetProductBarcodeByBarcodeRef(ctx.Context(), shopID, refBarcode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -418,7 +418,7 @@ func (h ProductBarcodeHttp) InfoProductBarcodeByBarcode(ctx microservice.IContex
 
 	barcode := ctx.Param("barcode")
 
-	doc, err := h.svc.InfoProductBarcodeByBarcode(shopID, barcode)
+	doc, err := h.svc.InfoProductBarcodeByBarcode(ctx.Context(), shopID, barcode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -461,7 +461,7 @@ func (h ProductBarcodeHttp) InfoArray(ctx microservice.IContext) error {
 	}
 
 	// where to filter array
-	doc, err := h.svc.InfoWTFArray(shopID, docReq)
+	doc, err := h.svc.InfoWTFArray(ctx.Context(), shopID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -501,7 +501,7 @@ func (h ProductBarcodeHttp) InfoArrayMaster(ctx microservice.IContext) error {
 	}
 
 	// where to filter array master
-	doc, err := h.svc.InfoWTFArrayMaster(docReq)
+	doc, err := h.svc.InfoWTFArrayMaster(ctx.Context(), docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -541,7 +541,7 @@ func (h ProductBarcodeHttp) SearchProductBarcodePage(ctx microservice.IContext)
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchProductBarcode(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchProductBarcode(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -616,7 +616,7 @@ func (h ProductBarcodeHttp) SearchProductBarcodeLimit(ctx microservice.IContext)
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchProductBarcodeStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchProductBarcodeStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -656,7 +656,7 @@ func (h ProductBarcodeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -698,7 +698,7 @@ func (h ProductBarcodeHttp) DeleteProductBarcodeByGUIDs(ctx microservice.IContex
 		return err
 	}
 
-	err = h.svc.DeleteProductBarcodeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteProductBarcodeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -743,7 +743,7 @@ func (h ProductBarcodeHttp) GetroductBarcodeByAllUnits(ctx microservice.IContext
 	tempUnitCodes := strings.Split(reqUnitCodes, ",")
 	unitCodes = append(unitCodes, tempUnitCodes...)
 
-	docs, pagination, err := h.svc.GetProductBarcodeByUnits(shopID, unitCodes, pageable)
+	docs, pagination, err := h.svc.GetProductBarcodeByUnits(ctx.Context(), shopID, unitCodes, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -779,7 +779,7 @@ func (h ProductBarcodeHttp) GetroductBarcodeByGroups(ctx microservice.IContext)
 	tempUnitCodes := strings.Split(reqUnitCodes, ",")
 	groupCodes = append(groupCodes, tempUnitCodes...)
 
-	docs, pagination, err := h.svc.GetProductBarcodeByGroups(shopID, groupCodes, pageable)
+	docs, pagination, err := h.svc.GetProductBarcodeByGroups(ctx.Context(), shopID, groupCodes, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -835,7 +835,7 @@ func (h ProductBarcodeHttp) Export(ctx microservice.IContext) error {
 		languageHeader[key] = ctx.QueryParam(key)
 	}
 
-	results, err := h.svc.Export(shopID, languageCode, languageHeader)
+	results, err := h.svc.Export(ctx.Context(), shopID, languageCode, languageHeader)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -880,7 +880,7 @@ func (h ProductBarcodeHttp) InfoBOMView(ctx microservice.IContext) error {
 
 	barcode := ctx.Param("barcode")
 
-	doc, err := h.svc.InfoBomView(shopID, barcode)
+	doc, err := h.svc.InfoBomView(ctx.Context(), shopID, barcode)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", barcode, err)
@@ -990,7 +990,7 @@ func (h ProductBarcodeHttp) Import(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.Import(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.Import(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -6,9 +6,9 @@ import (
 	"smlaicloudplatform/internal/product/productbarcode/models"
 )
 
-func (svc ProductBarcodeHttpService) InfoBomView(shopID string, barcode string) (models.ProductBarcodeBOMView, error) {
+func (svc ProductBarcodeHttpService) InfoBomView(ctx context.Context, shopID string, barcode string) (models.ProductBarcodeBOMView, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	doc, err := svc.repo.FindByBarcode(ctx, shopID, barcode)
@@ -48,9 +48,9 @@ func (svc ProductBarcodeHttpService) InfoBomView(shopID string, barcode string)
 	return bomView, nil
 }
 
-func (svc ProductBarcodeHttpService) ListBomView(shopID string, barcodes []string) ([]models.ProductBarcodeBOMView, error) {
+func (svc ProductBarcodeHttpService) ListBomView(ctx context.Context, shopID string, barcodes []string) ([]models.ProductBarcodeBOMView, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	var bomViews []models.ProductBarcodeBOMView
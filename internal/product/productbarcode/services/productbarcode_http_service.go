@@ -15,6 +15,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"strconv"
 	"time"
@@ -26,34 +27,34 @@ import (
 )
 
 type IProductBarcodeHttpService interface {
-	CreateProductBarcode(shopID string, authUsername string, doc models.ProductBarcodeRequest) (string, error)
-	UpdateProductBarcode(shopID string, guid string, authUsername string, doc models.ProductBarcodeRequest) error
-	DeleteProductBarcode(shopID string, guid string, authUsername string) error
-	DeleteProductBarcodeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoProductBarcode(shopID string, guid string) (models.ProductBarcodeInfo, error)
-	InfoProductBarcodeByBarcode(shopID string, barcode string) (models.ProductBarcodeInfo, error)
-	InfoWTFArray(shopID string, codes []string) ([]interface{}, error)
-	InfoWTFArrayMaster(codes []string) ([]interface{}, error)
-	GetProductBarcodeByBarcodeRef(shopID string, barcodeRef string) ([]models.ProductBarcodeInfo, error)
-	SearchProductBarcode(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
+	CreateProductBarcode(ctx context.Context, shopID string, authUsername string, doc models.ProductBarcodeRequest) (string, error)
+	UpdateProductBarcode(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductBarcodeRequest) error
+	DeleteProductBarcode(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteProductBarcodeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoProductBarcode(ctx context.Context, shopID string, guid string) (models.ProductBarcodeInfo, error)
+	InfoProductBarcodeByBarcode(ctx context.Context, shopID string, barcode string) (models.ProductBarcodeInfo, error)
+	InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error)
+	InfoWTFArrayMaster(ctx context.Context, codes []string) ([]interface{}, error)
+	GetProductBarcodeByBarcodeRef(ctx context.Context, shopID string, barcodeRef string) ([]models.ProductBarcodeInfo, error)
+	SearchProductBarcode(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
 	SearchProductBarcode2(shopID string, pageable micromodels.Pageable) ([]models.ProductBarcodeSearch, common.Pagination, error)
 
-	SearchProductBarcodeStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error)
+	SearchProductBarcodeStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error)
 
-	XSortsSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
-	GetProductBarcodeByBarcodes(shopID string, barcodes []string) ([]models.ProductBarcodeInfo, error)
+	XSortsSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
+	GetProductBarcodeByBarcodes(ctx context.Context, shopID string, barcodes []string) ([]models.ProductBarcodeInfo, error)
 
-	UpdateProductBarcodeBranch(shopID string, authUsername string, branch models.ProductBarcodeBranch, productBarcodeGUIDFixedes []string) error
-	UpdateProductBarcodeBusinessType(shopID string, authUsername string, businessType models.ProductBarcodeBusinessType, productBarcodeGUIDFixedes []string) error
+	UpdateProductBarcodeBranch(ctx context.Context, shopID string, authUsername string, branch models.ProductBarcodeBranch, productBarcodeGUIDFixedes []string) error
+	UpdateProductBarcodeBusinessType(ctx context.Context, shopID string, authUsername string, businessType models.ProductBarcodeBusinessType, productBarcodeGUIDFixedes []string) error
 
 	GetModuleName() string
-	GetProductBarcodeByUnits(shopID string, unitCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
-	GetProductBarcodeByGroups(shopID string, groupCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
-	Export(shopID string, languageCode string, languageHeader map[string]string) ([][]string, error)
+	GetProductBarcodeByUnits(ctx context.Context, shopID string, unitCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
+	GetProductBarcodeByGroups(ctx context.Context, shopID string, groupCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error)
+	Export(ctx context.Context, shopID string, languageCode string, languageHeader map[string]string) ([][]string, error)
 
-	InfoBomView(shopID string, barcode string) (models.ProductBarcodeBOMView, error)
-	Import(shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error)
+	InfoBomView(ctx context.Context, shopID string, barcode string) (models.ProductBarcodeBOMView, error)
+	Import(ctx context.Context, shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error)
 }
 
 type ProductBarcodeHttpService struct {
@@ -88,13 +89,13 @@ func NewProductBarcodeHttpService(
 	return insSvc
 }
 
-func (svc ProductBarcodeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ProductBarcodeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ProductBarcodeHttpService) CreateProductBarcode(shopID string, authUsername string, docReq models.ProductBarcodeRequest) (string, error) {
+func (svc ProductBarcodeHttpService) CreateProductBarcode(ctx context.Context, shopID string, authUsername string, docReq models.ProductBarcodeRequest) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "barcode", docReq.Barcode)
@@ -165,9 +166,9 @@ func (svc ProductBarcodeHttpService) CreateProductBarcode(shopID string, authUse
 	return newGuidFixed, nil
 }
 
-func (svc ProductBarcodeHttpService) UpdateProductBarcode(shopID string, guid string, authUsername string, docReq models.ProductBarcodeRequest) error {
+func (svc ProductBarcodeHttpService) UpdateProductBarcode(ctx context.Context, shopID string, guid string, authUsername string, docReq models.ProductBarcodeRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -235,16 +236,16 @@ func (svc ProductBarcodeHttpService) UpdateProductBarcode(shopID string, guid st
 		ManufacturerGUID: docData.ManufacturerGUID,
 	}
 
-	svc.categorySvc.UpdateBarcode(shopID, categoryBarcode)
+	svc.categorySvc.UpdateBarcode(ctx, shopID, categoryBarcode)
 
 	svc.saveMasterSync(shopID)
 
 	return nil
 }
 
-func (svc ProductBarcodeHttpService) UpdateProductBarcodeBranch(shopID string, authUsername string, branch models.ProductBarcodeBranch, productBarcodeGUIDFixedes []string) error {
+func (svc ProductBarcodeHttpService) UpdateProductBarcodeBranch(ctx context.Context, shopID string, authUsername string, branch models.ProductBarcodeBranch, productBarcodeGUIDFixedes []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.UpdateBranch(ctx, shopID, branch, productBarcodeGUIDFixedes)
@@ -258,9 +259,9 @@ func (svc ProductBarcodeHttpService) UpdateProductBarcodeBranch(shopID string, a
 	return nil
 }
 
-func (svc ProductBarcodeHttpService) UpdateProductBarcodeBusinessType(shopID string, authUsername string, businessType models.ProductBarcodeBusinessType, productBarcodeGUIDFixedes []string) error {
+func (svc ProductBarcodeHttpService) UpdateProductBarcodeBusinessType(ctx context.Context, shopID string, authUsername string, businessType models.ProductBarcodeBusinessType, productBarcodeGUIDFixedes []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.UpdateBusinessType(ctx, shopID, businessType, productBarcodeGUIDFixedes)
@@ -394,9 +395,9 @@ func (svc ProductBarcodeHttpService) updateMetaInBOMBarcode(ctx context.Context,
 	return nil
 }
 
-func (svc ProductBarcodeHttpService) DeleteProductBarcode(shopID string, guid string, authUsername string) error {
+func (svc ProductBarcodeHttpService) DeleteProductBarcode(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -444,9 +445,9 @@ func (svc ProductBarcodeHttpService) DeleteProductBarcode(shopID string, guid st
 	return nil
 }
 
-func (svc ProductBarcodeHttpService) InfoProductBarcode(shopID string, guid string) (models.ProductBarcodeInfo, error) {
+func (svc ProductBarcodeHttpService) InfoProductBarcode(ctx context.Context, shopID string, guid string) (models.ProductBarcodeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -462,9 +463,9 @@ func (svc ProductBarcodeHttpService) InfoProductBarcode(shopID string, guid stri
 	return findDoc.ProductBarcodeInfo, nil
 }
 
-func (svc ProductBarcodeHttpService) InfoProductBarcodeByBarcode(shopID string, barcode string) (models.ProductBarcodeInfo, error) {
+func (svc ProductBarcodeHttpService) InfoProductBarcodeByBarcode(ctx context.Context, shopID string, barcode string) (models.ProductBarcodeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "barcode", barcode)
@@ -480,9 +481,9 @@ func (svc ProductBarcodeHttpService) InfoProductBarcodeByBarcode(shopID string,
 	return findDoc.ProductBarcodeInfo, nil
 }
 
-func (svc ProductBarcodeHttpService) InfoWTFArray(shopID string, codes []string) ([]interface{}, error) {
+func (svc ProductBarcodeHttpService) InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -500,9 +501,9 @@ func (svc ProductBarcodeHttpService) InfoWTFArray(shopID string, codes []string)
 	return docList, nil
 }
 
-func (svc ProductBarcodeHttpService) InfoWTFArrayMaster(codes []string) ([]interface{}, error) {
+func (svc ProductBarcodeHttpService) InfoWTFArrayMaster(ctx context.Context, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -528,9 +529,9 @@ func (svc ProductBarcodeHttpService) InfoWTFArrayMaster(codes []string) ([]inter
 	return docList, nil
 }
 
-func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodeRef(shopID string, barcodeRef string) ([]models.ProductBarcodeInfo, error) {
+func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodeRef(ctx context.Context, shopID string, barcodeRef string) ([]models.ProductBarcodeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByRefBarcode(ctx, shopID, barcodeRef)
@@ -546,9 +547,9 @@ func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodeRef(shopID string
 	return resultDocs, nil
 }
 
-func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodes(shopID string, barcodes []string) ([]models.ProductBarcodeInfo, error) {
+func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodes(ctx context.Context, shopID string, barcodes []string) ([]models.ProductBarcodeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	results, err := svc.repo.FindByBarcodes(ctx, shopID, barcodes)
@@ -574,9 +575,9 @@ func (svc ProductBarcodeHttpService) GetProductBarcodeByBarcodes(shopID string,
 
 }
 
-func (svc ProductBarcodeHttpService) SearchProductBarcode(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
+func (svc ProductBarcodeHttpService) SearchProductBarcode(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -622,8 +623,8 @@ func (svc ProductBarcodeHttpService) SearchProductBarcode2(shopID string, pageab
 	return docList, pagination, nil
 }
 
-func (svc ProductBarcodeHttpService) SearchProductBarcodeStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeInfo, int, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc ProductBarcodeHttpService) SearchProductBarcodeStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductBarcodeInfo, int, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -645,9 +646,9 @@ func (svc ProductBarcodeHttpService) SearchProductBarcodeStep(shopID string, lan
 	return docList, total, nil
 }
 
-func (svc ProductBarcodeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error) {
+func (svc ProductBarcodeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ProductBarcode](dataList, svc.getDocIDKey)
@@ -729,7 +730,7 @@ func (svc ProductBarcodeHttpService) SaveInBatch(shopID string, authUsername str
 				docReq.IgnoreBranches = *dataReq.IgnoreBranches
 			}
 
-			svc.UpdateProductBarcode(shopID, doc.GuidFixed, authUsername, docReq)
+			svc.UpdateProductBarcode(ctx, shopID, doc.GuidFixed, authUsername, docReq)
 
 			// err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			// if err != nil {
@@ -767,7 +768,7 @@ func (svc ProductBarcodeHttpService) SaveInBatch(shopID string, authUsername str
 				}
 
 				docReq.RefBarcodes = tempBarcodes
-				_, err = svc.CreateProductBarcode(shopID, authUsername, docReq)
+				_, err = svc.CreateProductBarcode(ctx, shopID, authUsername, docReq)
 
 				if err != nil {
 					return err
@@ -834,8 +835,8 @@ func (svc ProductBarcodeHttpService) getDocIDKey(doc models.ProductBarcode) stri
 	return doc.Barcode
 }
 
-func (svc ProductBarcodeHttpService) XSortsSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc ProductBarcodeHttpService) XSortsSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	for _, xsort := range xsorts {
@@ -891,8 +892,8 @@ func (svc ProductBarcodeHttpService) XSortsSave(shopID string, authUsername stri
 
 }
 
-func (svc ProductBarcodeHttpService) DeleteProductBarcodeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc ProductBarcodeHttpService) DeleteProductBarcodeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	countRefBarcode, err := svc.repo.CountByRefGuids(ctx, shopID, GUIDs)
@@ -929,9 +930,9 @@ func (svc ProductBarcodeHttpService) DeleteProductBarcodeByGUIDs(shopID string,
 	return nil
 }
 
-func (svc ProductBarcodeHttpService) GetProductBarcodeByUnits(shopID string, unitCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
+func (svc ProductBarcodeHttpService) GetProductBarcodeByUnits(ctx context.Context, shopID string, unitCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if len(unitCodes) < 1 {
@@ -947,9 +948,9 @@ func (svc ProductBarcodeHttpService) GetProductBarcodeByUnits(shopID string, uni
 	return results, pagination, nil
 }
 
-func (svc ProductBarcodeHttpService) GetProductBarcodeByGroups(shopID string, groupCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
+func (svc ProductBarcodeHttpService) GetProductBarcodeByGroups(ctx context.Context, shopID string, groupCodes []string, pageable micromodels.Pageable) ([]models.ProductBarcodeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if len(groupCodes) < 1 {
@@ -979,9 +980,9 @@ func (svc ProductBarcodeHttpService) GetModuleName() string {
 	return "productbarcode"
 }
 
-func (svc ProductBarcodeHttpService) Export(shopID string, languageCode string, languageHeader map[string]string) ([][]string, error) {
+func (svc ProductBarcodeHttpService) Export(ctx context.Context, shopID string, languageCode string, languageHeader map[string]string) ([][]string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	filters := bson.M{}
@@ -1101,8 +1102,8 @@ func getName(names *[]common.NameX, langCode string) string {
 	return ""
 }
 
-func (svc ProductBarcodeHttpService) Import(shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc ProductBarcodeHttpService) Import(ctx context.Context, shopID string, authUsername string, dataList []models.ProductBarcode) (common.BulkImport, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ProductBarcode](dataList, svc.getDocIDKey)
@@ -88,7 +88,7 @@ func (h ProductCategoryHttp) CreateProductCategory(ctx microservice.IContext) er
 		return err
 	}
 
-	idx, err := h.svc.CreateProductCategory(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateProductCategory(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -141,7 +141,7 @@ func (h ProductCategoryHttp) UpdateProductCategory(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.svc.UpdateProductCategory(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateProductCategory(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -185,7 +185,7 @@ func (h ProductCategoryHttp) UpdateProductCategoryXSort(ctx microservice.IContex
 		return err
 	}
 
-	err = h.svc.XSortsSave(shopID, authUsername, *req)
+	err = h.svc.XSortsSave(ctx.Context(), shopID, authUsername, *req)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -228,7 +228,7 @@ func (h ProductCategoryHttp) UpdateProductCategoryBarcodes(ctx microservice.ICon
 		return err
 	}
 
-	err = h.svc.XBarcodesSave(shopID, authUsername, *req)
+	err = h.svc.XBarcodesSave(ctx.Context(), shopID, authUsername, *req)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -258,7 +258,7 @@ func (h ProductCategoryHttp) DeleteProductCategory(ctx microservice.IContext) er
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteProductCategory(shopID, id, authUsername)
+	err := h.svc.DeleteProductCategory(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h ProductCategoryHttp) InfoProductCategory(ctx microservice.IContext) erro
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ProductCategory %v", id)
-	doc, err := h.svc.InfoProductCategory(shopID, id)
+	doc, err := h.svc.InfoProductCategory(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -329,7 +329,7 @@ func (h ProductCategoryHttp) SearchProductCategoryPage(ctx microservice.IContext
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchProductCategory(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchProductCategory(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -373,7 +373,7 @@ func (h ProductCategoryHttp) SearchProductCategoryLimit(ctx microservice.IContex
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchProductCategoryStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchProductCategoryStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -413,7 +413,7 @@ func (h ProductCategoryHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	err = h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -454,7 +454,7 @@ func (h ProductCategoryHttp) DeleteProductCategoryByGUIDs(ctx microservice.ICont
 		return err
 	}
 
-	err = h.svc.DeleteProductCategoryByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteProductCategoryByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
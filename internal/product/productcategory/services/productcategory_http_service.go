@@ -12,6 +12,7 @@ import (
 
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 
 	"github.com/smlsoft/mongopagination"
@@ -20,17 +21,17 @@ import (
 )
 
 type IProductCategoryHttpService interface {
-	CreateProductCategory(shopID string, authUsername string, doc models.ProductCategory) (string, error)
-	UpdateProductCategory(shopID string, guid string, authUsername string, doc models.ProductCategory) error
-	DeleteProductCategory(shopID string, guid string, authUsername string) error
-	DeleteProductCategoryByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoProductCategory(shopID string, guid string) (models.ProductCategoryInfo, error)
-	SearchProductCategory(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductCategoryInfo, mongopagination.PaginationData, error)
-	SearchProductCategoryStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductCategoryInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ProductCategory) error
-	XSortsSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
-	XBarcodesSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
-	UpdateBarcode(shopID string, codeXSort models.CodeXSort) error
+	CreateProductCategory(ctx context.Context, shopID string, authUsername string, doc models.ProductCategory) (string, error)
+	UpdateProductCategory(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductCategory) error
+	DeleteProductCategory(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteProductCategoryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoProductCategory(ctx context.Context, shopID string, guid string) (models.ProductCategoryInfo, error)
+	SearchProductCategory(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductCategoryInfo, mongopagination.PaginationData, error)
+	SearchProductCategoryStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductCategoryInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductCategory) error
+	XSortsSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
+	XBarcodesSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error
+	UpdateBarcode(ctx context.Context, shopID string, codeXSort models.CodeXSort) error
 
 	GetModuleName() string
 }
@@ -58,13 +59,13 @@ func NewProductCategoryHttpService(repo repositories.IProductCategoryRepository,
 	return insSvc
 }
 
-func (svc ProductCategoryHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ProductCategoryHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ProductCategoryHttpService) CreateProductCategory(shopID string, authUsername string, doc models.ProductCategory) (string, error) {
+func (svc ProductCategoryHttpService) CreateProductCategory(ctx context.Context, shopID string, authUsername string, doc models.ProductCategory) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -90,9 +91,9 @@ func (svc ProductCategoryHttpService) CreateProductCategory(shopID string, authU
 	return newGuidFixed, nil
 }
 
-func (svc ProductCategoryHttpService) UpdateProductCategory(shopID string, guid string, authUsername string, doc models.ProductCategory) error {
+func (svc ProductCategoryHttpService) UpdateProductCategory(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductCategory) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -121,17 +122,17 @@ func (svc ProductCategoryHttpService) UpdateProductCategory(shopID string, guid
 	return nil
 }
 
-func (svc ProductCategoryHttpService) UpdateBarcode(shopID string, codeXSort models.CodeXSort) error {
+func (svc ProductCategoryHttpService) UpdateBarcode(ctx context.Context, shopID string, codeXSort models.CodeXSort) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	return svc.repo.UpdateCodeList(ctx, shopID, codeXSort)
 }
 
-func (svc ProductCategoryHttpService) DeleteProductCategory(shopID string, guid string, authUsername string) error {
+func (svc ProductCategoryHttpService) DeleteProductCategory(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -154,9 +155,9 @@ func (svc ProductCategoryHttpService) DeleteProductCategory(shopID string, guid
 	return nil
 }
 
-func (svc ProductCategoryHttpService) InfoProductCategory(shopID string, guid string) (models.ProductCategoryInfo, error) {
+func (svc ProductCategoryHttpService) InfoProductCategory(ctx context.Context, shopID string, guid string) (models.ProductCategoryInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -173,9 +174,9 @@ func (svc ProductCategoryHttpService) InfoProductCategory(shopID string, guid st
 
 }
 
-func (svc ProductCategoryHttpService) SearchProductCategory(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductCategoryInfo, mongopagination.PaginationData, error) {
+func (svc ProductCategoryHttpService) SearchProductCategory(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductCategoryInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -204,9 +205,9 @@ func (svc ProductCategoryHttpService) SearchProductCategory(shopID string, filte
 	return docList, pagination, nil
 }
 
-func (svc ProductCategoryHttpService) SearchProductCategoryStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductCategoryInfo, int, error) {
+func (svc ProductCategoryHttpService) SearchProductCategoryStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ProductCategoryInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -237,9 +238,9 @@ func (svc ProductCategoryHttpService) SearchProductCategoryStep(shopID string, l
 	return docList, total, nil
 }
 
-func (svc ProductCategoryHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ProductCategory) error {
+func (svc ProductCategoryHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductCategory) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	createDataList := []models.ProductCategoryDoc{}
@@ -276,9 +277,9 @@ func (svc ProductCategoryHttpService) SaveInBatch(shopID string, authUsername st
 	return nil
 }
 
-func (svc ProductCategoryHttpService) XSortsSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
+func (svc ProductCategoryHttpService) XSortsSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	for _, xsort := range xsorts {
@@ -334,9 +335,9 @@ func (svc ProductCategoryHttpService) XSortsSave(shopID string, authUsername str
 
 }
 
-func (svc ProductCategoryHttpService) XBarcodesSave(shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
+func (svc ProductCategoryHttpService) XBarcodesSave(ctx context.Context, shopID string, authUsername string, xsorts []common.XSortModifyReqesut) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	for _, xsort := range xsorts {
@@ -393,9 +394,9 @@ func (svc ProductCategoryHttpService) XBarcodesSave(shopID string, authUsername
 
 }
 
-func (svc ProductCategoryHttpService) DeleteProductCategoryByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ProductCategoryHttpService) DeleteProductCategoryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -84,7 +84,7 @@ func (h ProductGroupHttp) CreateProductGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateProductGroup(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateProductGroup(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -125,7 +125,7 @@ func (h ProductGroupHttp) SaveProductGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.SaveProductGroup(shopID, authUsername, *docReq)
+	idx, err := h.svc.SaveProductGroup(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -170,7 +170,7 @@ func (h ProductGroupHttp) UpdateProductGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateProductGroup(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateProductGroup(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -201,7 +201,7 @@ func (h ProductGroupHttp) DeleteProductGroup(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteProductGroup(shopID, id, authUsername)
+	err := h.svc.DeleteProductGroup(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -240,7 +240,7 @@ func (h ProductGroupHttp) DeleteProductGroupByGUIDs(ctx microservice.IContext) e
 		return err
 	}
 
-	err = h.svc.DeleteProductGroupByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteProductGroupByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -270,7 +270,7 @@ func (h ProductGroupHttp) InfoProductGroup(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ProductGroup %v", id)
-	doc, err := h.svc.InfoProductGroup(shopID, id)
+	doc, err := h.svc.InfoProductGroup(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -313,7 +313,7 @@ func (h ProductGroupHttp) InfoArray(ctx microservice.IContext) error {
 		return err
 	}
 	// where to filter array
-	doc, err := h.svc.InfoWTFArray(shopID, docReq)
+	doc, err := h.svc.InfoWTFArray(ctx.Context(), shopID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -344,7 +344,7 @@ func (h ProductGroupHttp) SearchProductGroupPage(ctx microservice.IContext) erro
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchProductGroup(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchProductGroup(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -379,7 +379,7 @@ func (h ProductGroupHttp) SearchProductGroupStep(ctx microservice.IContext) erro
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchProductGroupStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchProductGroupStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -419,7 +419,7 @@ func (h ProductGroupHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
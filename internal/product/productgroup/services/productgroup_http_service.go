@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -24,16 +25,16 @@ import (
 )
 
 type IProductGroupHttpService interface {
-	SaveProductGroup(shopID string, authUsername string, doc models.ProductGroup) (string, error)
-	CreateProductGroup(shopID string, authUsername string, doc models.ProductGroup) (string, error)
-	UpdateProductGroup(shopID string, guid string, authUsername string, doc models.ProductGroup) error
-	DeleteProductGroup(shopID string, guid string, authUsername string) error
-	DeleteProductGroupByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoProductGroup(shopID string, guid string) (models.ProductGroupInfo, error)
-	InfoWTFArray(shopID string, unitCodes []string) ([]interface{}, error)
-	SearchProductGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductGroupInfo, mongopagination.PaginationData, error)
-	SearchProductGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductGroupInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ProductGroup) (common.BulkImport, error)
+	SaveProductGroup(ctx context.Context, shopID string, authUsername string, doc models.ProductGroup) (string, error)
+	CreateProductGroup(ctx context.Context, shopID string, authUsername string, doc models.ProductGroup) (string, error)
+	UpdateProductGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductGroup) error
+	DeleteProductGroup(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteProductGroupByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoProductGroup(ctx context.Context, shopID string, guid string) (models.ProductGroupInfo, error)
+	InfoWTFArray(ctx context.Context, shopID string, unitCodes []string) ([]interface{}, error)
+	SearchProductGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductGroupInfo, mongopagination.PaginationData, error)
+	SearchProductGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductGroupInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductGroup) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -72,13 +73,13 @@ func NewProductGroupHttpService(
 	return insSvc
 }
 
-func (svc ProductGroupHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ProductGroupHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ProductGroupHttpService) SaveProductGroup(shopID string, authUsername string, doc models.ProductGroup) (string, error) {
+func (svc ProductGroupHttpService) SaveProductGroup(ctx context.Context, shopID string, authUsername string, doc models.ProductGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -89,7 +90,7 @@ func (svc ProductGroupHttpService) SaveProductGroup(shopID string, authUsername
 
 	if len(findDoc.Code) > 0 {
 
-		docData, err := svc.update(shopID, authUsername, findDoc.GuidFixed, findDoc, doc)
+		docData, err := svc.update(ctx, shopID, authUsername, findDoc.GuidFixed, findDoc, doc)
 
 		if err != nil {
 			return "", err
@@ -98,7 +99,7 @@ func (svc ProductGroupHttpService) SaveProductGroup(shopID string, authUsername
 		return docData.GuidFixed, nil
 
 	} else {
-		docData, err := svc.create(shopID, authUsername, doc)
+		docData, err := svc.create(ctx, shopID, authUsername, doc)
 
 		if err != nil {
 			return "", err
@@ -108,9 +109,9 @@ func (svc ProductGroupHttpService) SaveProductGroup(shopID string, authUsername
 	}
 }
 
-func (svc ProductGroupHttpService) CreateProductGroup(shopID string, authUsername string, doc models.ProductGroup) (string, error) {
+func (svc ProductGroupHttpService) CreateProductGroup(ctx context.Context, shopID string, authUsername string, doc models.ProductGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -123,7 +124,7 @@ func (svc ProductGroupHttpService) CreateProductGroup(shopID string, authUsernam
 		return "", errors.New("code is exists")
 	}
 
-	docData, err := svc.create(shopID, authUsername, doc)
+	docData, err := svc.create(ctx, shopID, authUsername, doc)
 
 	go func() {
 		svc.repoMessageQueue.Create(docData)
@@ -134,9 +135,9 @@ func (svc ProductGroupHttpService) CreateProductGroup(shopID string, authUsernam
 	return docData.GuidFixed, err
 }
 
-func (svc ProductGroupHttpService) create(shopID string, authUsername string, doc models.ProductGroup) (models.ProductGroupDoc, error) {
+func (svc ProductGroupHttpService) create(ctx context.Context, shopID string, authUsername string, doc models.ProductGroup) (models.ProductGroupDoc, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -158,9 +159,9 @@ func (svc ProductGroupHttpService) create(shopID string, authUsername string, do
 	return docData, nil
 }
 
-func (svc ProductGroupHttpService) UpdateProductGroup(shopID string, guid string, authUsername string, doc models.ProductGroup) error {
+func (svc ProductGroupHttpService) UpdateProductGroup(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductGroup) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -178,7 +179,7 @@ func (svc ProductGroupHttpService) UpdateProductGroup(shopID string, guid string
 	findDoc.UpdatedBy = authUsername
 	findDoc.UpdatedAt = time.Now()
 
-	docData, err := svc.update(shopID, authUsername, guid, findDoc, doc)
+	docData, err := svc.update(ctx, shopID, authUsername, guid, findDoc, doc)
 
 	if err != nil {
 		return err
@@ -197,9 +198,9 @@ func (svc ProductGroupHttpService) UpdateProductGroup(shopID string, guid string
 	return nil
 }
 
-func (svc ProductGroupHttpService) update(shopID string, authUsername string, guid string, findDoc models.ProductGroupDoc, docUpdate models.ProductGroup) (models.ProductGroupDoc, error) {
+func (svc ProductGroupHttpService) update(ctx context.Context, shopID string, authUsername string, guid string, findDoc models.ProductGroupDoc, docUpdate models.ProductGroup) (models.ProductGroupDoc, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docData := findDoc
@@ -218,9 +219,9 @@ func (svc ProductGroupHttpService) update(shopID string, authUsername string, gu
 	return docData, nil
 }
 
-func (svc ProductGroupHttpService) DeleteProductGroup(shopID, guid, authUsername string) error {
+func (svc ProductGroupHttpService) DeleteProductGroup(ctx context.Context, shopID, guid, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -233,7 +234,7 @@ func (svc ProductGroupHttpService) DeleteProductGroup(shopID, guid, authUsername
 		return nil
 	}
 
-	existsInProduct, _ := svc.existsGroupRefInProduct(shopID, []string{findDoc.Code})
+	existsInProduct, _ := svc.existsGroupRefInProduct(ctx, shopID, []string{findDoc.Code})
 
 	if existsInProduct {
 		return fmt.Errorf("group code \"%s\" is referenced in product barcode", findDoc.Code)
@@ -257,9 +258,9 @@ func (svc ProductGroupHttpService) DeleteProductGroup(shopID, guid, authUsername
 	return nil
 }
 
-func (svc ProductGroupHttpService) DeleteProductGroupByGUIDs(shopID, authUsername string, GUIDs []string) error {
+func (svc ProductGroupHttpService) DeleteProductGroupByGUIDs(ctx context.Context, shopID, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
@@ -277,7 +278,7 @@ func (svc ProductGroupHttpService) DeleteProductGroupByGUIDs(shopID, authUsernam
 		groupCodes = append(groupCodes, v.Code)
 	}
 
-	existsInProduct, _ := svc.existsGroupRefInProduct(shopID, groupCodes)
+	existsInProduct, _ := svc.existsGroupRefInProduct(ctx, shopID, groupCodes)
 
 	if existsInProduct {
 		return fmt.Errorf("referenced in product")
@@ -305,9 +306,9 @@ func (svc ProductGroupHttpService) DeleteProductGroupByGUIDs(shopID, authUsernam
 	return nil
 }
 
-func (svc ProductGroupHttpService) InfoProductGroup(shopID string, guid string) (models.ProductGroupInfo, error) {
+func (svc ProductGroupHttpService) InfoProductGroup(ctx context.Context, shopID string, guid string) (models.ProductGroupInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -324,9 +325,9 @@ func (svc ProductGroupHttpService) InfoProductGroup(shopID string, guid string)
 
 }
 
-func (svc ProductGroupHttpService) InfoWTFArray(shopID string, codes []string) ([]interface{}, error) {
+func (svc ProductGroupHttpService) InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -344,9 +345,9 @@ func (svc ProductGroupHttpService) InfoWTFArray(shopID string, codes []string) (
 	return docList, nil
 }
 
-func (svc ProductGroupHttpService) SearchProductGroup(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductGroupInfo, mongopagination.PaginationData, error) {
+func (svc ProductGroupHttpService) SearchProductGroup(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductGroupInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -363,9 +364,9 @@ func (svc ProductGroupHttpService) SearchProductGroup(shopID string, filters map
 	return docList, pagination, nil
 }
 
-func (svc ProductGroupHttpService) SearchProductGroupStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductGroupInfo, int, error) {
+func (svc ProductGroupHttpService) SearchProductGroupStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductGroupInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -384,9 +385,9 @@ func (svc ProductGroupHttpService) SearchProductGroupStep(shopID string, langCod
 	return docList, total, nil
 }
 
-func (svc ProductGroupHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ProductGroup) (common.BulkImport, error) {
+func (svc ProductGroupHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductGroup) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ProductGroup](dataList, svc.getDocIDKey)
@@ -513,9 +514,9 @@ func (svc ProductGroupHttpService) GetModuleName() string {
 	return "productGroup"
 }
 
-func (svc ProductGroupHttpService) existsGroupRefInProduct(shopID string, groupCodes []string) (bool, error) {
+func (svc ProductGroupHttpService) existsGroupRefInProduct(ctx context.Context, shopID string, groupCodes []string) (bool, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docCount, err := svc.repoProductBarcode.CountByGroupCodes(ctx, shopID, groupCodes)
@@ -83,7 +83,7 @@ func (h ProductTypeHttp) CreateProductType(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateProductType(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateProductType(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -128,7 +128,7 @@ func (h ProductTypeHttp) UpdateProductType(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateProductType(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateProductType(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -159,7 +159,7 @@ func (h ProductTypeHttp) DeleteProductType(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteProductType(shopID, id, authUsername)
+	err := h.svc.DeleteProductType(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -198,7 +198,7 @@ func (h ProductTypeHttp) DeleteProductTypeByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteProductTypeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteProductTypeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -228,7 +228,7 @@ func (h ProductTypeHttp) InfoProductType(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ProductType %v", id)
-	doc, err := h.svc.InfoProductType(shopID, id)
+	doc, err := h.svc.InfoProductType(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -258,7 +258,7 @@ func (h ProductTypeHttp) InfoProductTypeByCode(ctx microservice.IContext) error
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoProductTypeByCode(shopID, code)
+	doc, err := h.svc.InfoProductTypeByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -289,7 +289,7 @@ func (h ProductTypeHttp) SearchProductTypePage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchProductType(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchProductType(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -324,7 +324,7 @@ func (h ProductTypeHttp) SearchProductTypeStep(ctx microservice.IContext) error
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchProductTypeStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchProductTypeStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -364,7 +364,7 @@ func (h ProductTypeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type IProductTypeHttpService interface {
-	CreateProductType(shopID string, authUsername string, doc models.ProductType) (string, error)
-	UpdateProductType(shopID string, guid string, authUsername string, doc models.ProductType) error
-	DeleteProductType(shopID string, guid string, authUsername string) error
-	DeleteProductTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoProductType(shopID string, guid string) (models.ProductTypeInfo, error)
-	InfoProductTypeByCode(shopID string, code string) (models.ProductTypeInfo, error)
-	SearchProductType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductTypeInfo, mongopagination.PaginationData, error)
-	SearchProductTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductTypeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ProductType) (common.BulkImport, error)
+	CreateProductType(ctx context.Context, shopID string, authUsername string, doc models.ProductType) (string, error)
+	UpdateProductType(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductType) error
+	DeleteProductType(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteProductTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoProductType(ctx context.Context, shopID string, guid string) (models.ProductTypeInfo, error)
+	InfoProductTypeByCode(ctx context.Context, shopID string, code string) (models.ProductTypeInfo, error)
+	SearchProductType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductTypeInfo, mongopagination.PaginationData, error)
+	SearchProductTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductTypeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductType) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -64,13 +65,13 @@ func NewProductTypeHttpService(
 	return insSvc
 }
 
-func (svc ProductTypeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ProductTypeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ProductTypeHttpService) CreateProductType(shopID string, authUsername string, doc models.ProductType) (string, error) {
+func (svc ProductTypeHttpService) CreateProductType(ctx context.Context, shopID string, authUsername string, doc models.ProductType) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -112,9 +113,9 @@ func (svc ProductTypeHttpService) CreateProductType(shopID string, authUsername
 	return newGuidFixed, nil
 }
 
-func (svc ProductTypeHttpService) UpdateProductType(shopID string, guid string, authUsername string, doc models.ProductType) error {
+func (svc ProductTypeHttpService) UpdateProductType(ctx context.Context, shopID string, guid string, authUsername string, doc models.ProductType) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -153,9 +154,9 @@ func (svc ProductTypeHttpService) UpdateProductType(shopID string, guid string,
 	return nil
 }
 
-func (svc ProductTypeHttpService) DeleteProductType(shopID string, guid string, authUsername string) error {
+func (svc ProductTypeHttpService) DeleteProductType(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -168,7 +169,7 @@ func (svc ProductTypeHttpService) DeleteProductType(shopID string, guid string,
 		return errors.New("document not found")
 	}
 
-	existsInProduct, _ := svc.existsOrderTypeRefInProduct(shopID, []string{guid})
+	existsInProduct, _ := svc.existsOrderTypeRefInProduct(ctx, shopID, []string{guid})
 
 	if existsInProduct {
 		return fmt.Errorf("\"%s\" is referenced in product barcode", findDoc.Code)
@@ -192,12 +193,12 @@ func (svc ProductTypeHttpService) DeleteProductType(shopID string, guid string,
 	return nil
 }
 
-func (svc ProductTypeHttpService) DeleteProductTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ProductTypeHttpService) DeleteProductTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	existsInProduct, _ := svc.existsOrderTypeRefInProduct(shopID, GUIDs)
+	existsInProduct, _ := svc.existsOrderTypeRefInProduct(ctx, shopID, GUIDs)
 
 	if existsInProduct {
 		return fmt.Errorf("referenced in product")
@@ -231,9 +232,9 @@ func (svc ProductTypeHttpService) DeleteProductTypeByGUIDs(shopID string, authUs
 	return nil
 }
 
-func (svc ProductTypeHttpService) InfoProductType(shopID string, guid string) (models.ProductTypeInfo, error) {
+func (svc ProductTypeHttpService) InfoProductType(ctx context.Context, shopID string, guid string) (models.ProductTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -249,9 +250,9 @@ func (svc ProductTypeHttpService) InfoProductType(shopID string, guid string) (m
 	return findDoc.ProductTypeInfo, nil
 }
 
-func (svc ProductTypeHttpService) InfoProductTypeByCode(shopID string, code string) (models.ProductTypeInfo, error) {
+func (svc ProductTypeHttpService) InfoProductTypeByCode(ctx context.Context, shopID string, code string) (models.ProductTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -267,9 +268,9 @@ func (svc ProductTypeHttpService) InfoProductTypeByCode(shopID string, code stri
 	return findDoc.ProductTypeInfo, nil
 }
 
-func (svc ProductTypeHttpService) SearchProductType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductTypeInfo, mongopagination.PaginationData, error) {
+func (svc ProductTypeHttpService) SearchProductType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ProductTypeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -286,9 +287,9 @@ func (svc ProductTypeHttpService) SearchProductType(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc ProductTypeHttpService) SearchProductTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductTypeInfo, int, error) {
+func (svc ProductTypeHttpService) SearchProductTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ProductTypeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -315,9 +316,9 @@ func (svc ProductTypeHttpService) SearchProductTypeStep(shopID string, langCode
 	return docList, total, nil
 }
 
-func (svc ProductTypeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ProductType) (common.BulkImport, error) {
+func (svc ProductTypeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ProductType) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ProductType](dataList, svc.getDocIDKey)
@@ -444,9 +445,9 @@ func (svc ProductTypeHttpService) GetModuleName() string {
 	return "productType"
 }
 
-func (svc ProductTypeHttpService) existsOrderTypeRefInProduct(shopID string, GUIDs []string) (bool, error) {
+func (svc ProductTypeHttpService) existsOrderTypeRefInProduct(ctx context.Context, shopID string, GUIDs []string) (bool, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docCount, err := svc.repoProductBarcode.CountByProductTypes(ctx, shopID, GUIDs)
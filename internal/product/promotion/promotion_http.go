@@ -79,7 +79,7 @@ func (h PromotionHttp) CreatePromotion(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreatePromotion(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreatePromotion(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -124,7 +124,7 @@ func (h PromotionHttp) UpdatePromotion(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePromotion(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePromotion(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -155,7 +155,7 @@ func (h PromotionHttp) DeletePromotion(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePromotion(shopID, id, authUsername)
+	err := h.svc.DeletePromotion(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -194,7 +194,7 @@ func (h PromotionHttp) DeletePromotionByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeletePromotionByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePromotionByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -224,7 +224,7 @@ func (h PromotionHttp) InfoPromotion(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Promotion %v", id)
-	doc, err := h.svc.InfoPromotion(shopID, id)
+	doc, err := h.svc.InfoPromotion(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -254,7 +254,7 @@ func (h PromotionHttp) InfoPromotionByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPromotionByCode(shopID, code)
+	doc, err := h.svc.InfoPromotionByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -293,7 +293,7 @@ func (h PromotionHttp) SearchPromotionPage(ctx microservice.IContext) error {
 	// 	},
 	// })
 
-	docList, pagination, err := h.svc.SearchPromotion(shopID, pageable)
+	docList, pagination, err := h.svc.SearchPromotion(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -337,7 +337,7 @@ func (h PromotionHttp) SearchPromotionStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchPromotionStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPromotionStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -377,7 +377,7 @@ func (h PromotionHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type IPromotionHttpService interface {
-	CreatePromotion(shopID string, authUsername string, doc models.Promotion) (string, error)
-	UpdatePromotion(shopID string, guid string, authUsername string, doc models.Promotion) error
-	DeletePromotion(shopID string, guid string, authUsername string) error
-	DeletePromotionByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPromotion(shopID string, guid string) (models.PromotionInfo, error)
-	InfoPromotionByCode(shopID string, code string) (models.PromotionInfo, error)
-	SearchPromotion(shopID string, pageable micromodels.Pageable) ([]models.PromotionInfo, mongopagination.PaginationData, error)
-	SearchPromotionStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PromotionInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Promotion) (common.BulkImport, error)
+	CreatePromotion(ctx context.Context, shopID string, authUsername string, doc models.Promotion) (string, error)
+	UpdatePromotion(ctx context.Context, shopID string, guid string, authUsername string, doc models.Promotion) error
+	DeletePromotion(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePromotionByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPromotion(ctx context.Context, shopID string, guid string) (models.PromotionInfo, error)
+	InfoPromotionByCode(ctx context.Context, shopID string, code string) (models.PromotionInfo, error)
+	SearchPromotion(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.PromotionInfo, mongopagination.PaginationData, error)
+	SearchPromotionStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PromotionInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Promotion) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewPromotionHttpService(repo repositories.IPromotionRepository, syncCacheRe
 	return insSvc
 }
 
-func (svc PromotionHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PromotionHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PromotionHttpService) CreatePromotion(shopID string, authUsername string, doc models.Promotion) (string, error) {
+func (svc PromotionHttpService) CreatePromotion(ctx context.Context, shopID string, authUsername string, doc models.Promotion) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -94,9 +95,9 @@ func (svc PromotionHttpService) CreatePromotion(shopID string, authUsername stri
 	return newGuidFixed, nil
 }
 
-func (svc PromotionHttpService) UpdatePromotion(shopID string, guid string, authUsername string, doc models.Promotion) error {
+func (svc PromotionHttpService) UpdatePromotion(ctx context.Context, shopID string, guid string, authUsername string, doc models.Promotion) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,9 +126,9 @@ func (svc PromotionHttpService) UpdatePromotion(shopID string, guid string, auth
 	return nil
 }
 
-func (svc PromotionHttpService) DeletePromotion(shopID string, guid string, authUsername string) error {
+func (svc PromotionHttpService) DeletePromotion(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -150,9 +151,9 @@ func (svc PromotionHttpService) DeletePromotion(shopID string, guid string, auth
 	return nil
 }
 
-func (svc PromotionHttpService) DeletePromotionByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PromotionHttpService) DeletePromotionByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -167,9 +168,9 @@ func (svc PromotionHttpService) DeletePromotionByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc PromotionHttpService) InfoPromotion(shopID string, guid string) (models.PromotionInfo, error) {
+func (svc PromotionHttpService) InfoPromotion(ctx context.Context, shopID string, guid string) (models.PromotionInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -185,9 +186,9 @@ func (svc PromotionHttpService) InfoPromotion(shopID string, guid string) (model
 	return findDoc.PromotionInfo, nil
 }
 
-func (svc PromotionHttpService) InfoPromotionByCode(shopID string, code string) (models.PromotionInfo, error) {
+func (svc PromotionHttpService) InfoPromotionByCode(ctx context.Context, shopID string, code string) (models.PromotionInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -203,9 +204,9 @@ func (svc PromotionHttpService) InfoPromotionByCode(shopID string, code string)
 	return findDoc.PromotionInfo, nil
 }
 
-func (svc PromotionHttpService) SearchPromotion(shopID string, pageable micromodels.Pageable) ([]models.PromotionInfo, mongopagination.PaginationData, error) {
+func (svc PromotionHttpService) SearchPromotion(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.PromotionInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -223,9 +224,9 @@ func (svc PromotionHttpService) SearchPromotion(shopID string, pageable micromod
 	return docList, pagination, nil
 }
 
-func (svc PromotionHttpService) SearchPromotionStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PromotionInfo, int, error) {
+func (svc PromotionHttpService) SearchPromotionStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PromotionInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -245,9 +246,9 @@ func (svc PromotionHttpService) SearchPromotionStep(shopID string, langCode stri
 	return docList, total, nil
 }
 
-func (svc PromotionHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Promotion) (common.BulkImport, error) {
+func (svc PromotionHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Promotion) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Promotion](dataList, svc.getDocIDKey)
@@ -15,6 +15,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -26,19 +27,19 @@ import (
 )
 
 type IUnitHttpService interface {
-	CreateUnit(shopID string, authUsername string, doc models.Unit) (string, error)
-	UpdateUnit(shopID string, guid string, authUsername string, doc models.Unit) error
-	UpdateFieldUnit(shopID string, guid string, authUsername string, doc models.Unit) error
-	DeleteUnit(shopID string, guid string, authUsername string) error
-	DeleteUnitByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoUnit(shopID string, guid string) (models.UnitInfo, error)
-	InfoUnitWTFArray(shopID string, unitCodes []string) ([]interface{}, error)
-	InfoWTFArrayMaster(codes []string) ([]interface{}, error)
-	SearchUnit(shopID string, codeFilters []string, pageable micromodels.Pageable) ([]models.UnitInfo, mongopagination.PaginationData, error)
-	SearchUnitLimit(shopID string, langCode string, codeFilters []string, pageableStep micromodels.PageableStep) ([]models.UnitInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Unit) (common.BulkImport, error)
+	CreateUnit(ctx context.Context, shopID string, authUsername string, doc models.Unit) (string, error)
+	UpdateUnit(ctx context.Context, shopID string, guid string, authUsername string, doc models.Unit) error
+	UpdateFieldUnit(ctx context.Context, shopID string, guid string, authUsername string, doc models.Unit) error
+	DeleteUnit(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteUnitByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoUnit(ctx context.Context, shopID string, guid string) (models.UnitInfo, error)
+	InfoUnitWTFArray(ctx context.Context, shopID string, unitCodes []string) ([]interface{}, error)
+	InfoWTFArrayMaster(ctx context.Context, codes []string) ([]interface{}, error)
+	SearchUnit(ctx context.Context, shopID string, codeFilters []string, pageable micromodels.Pageable) ([]models.UnitInfo, mongopagination.PaginationData, error)
+	SearchUnitLimit(ctx context.Context, shopID string, langCode string, codeFilters []string, pageableStep micromodels.PageableStep) ([]models.UnitInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Unit) (common.BulkImport, error)
 	GetModuleName() string
-	ImportUnitsFromFile(file []byte, shopID string, authUsername string) (string, error)
+	ImportUnitsFromFile(ctx context.Context, file []byte, shopID string, authUsername string) (string, error)
 }
 
 type UnitHttpService struct {
@@ -72,11 +73,11 @@ func NewUnitHttpService(
 	return insSvc
 }
 
-func (svc UnitHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc UnitHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc *UnitHttpService) ImportUnitsFromFile(file []byte, shopID string, authUsername string) (string, error) {
+func (svc *UnitHttpService) ImportUnitsFromFile(ctx context.Context, file []byte, shopID string, authUsername string) (string, error) {
 	f, err := excelize.OpenReader(bytes.NewReader(file))
 	if err != nil {
 		return "", fmt.Errorf("failed to open Excel file: %v", err)
@@ -95,7 +96,7 @@ func (svc *UnitHttpService) ImportUnitsFromFile(file []byte, shopID string, auth
 	var existingUnitCodes []string
 	var newUnits []models.UnitDoc
 
-	ctx, cancel := svc.getContextTimeout()
+	ctx, cancel := svc.getContextTimeout(ctx)
 	defer cancel()
 
 	for _, row := range rows[1:] {
@@ -169,9 +170,9 @@ func (svc *UnitHttpService) ImportUnitsFromFile(file []byte, shopID string, auth
 	return fmt.Sprintf("Existing unit codes: %v", existingUnitCodes), nil
 }
 
-func (svc UnitHttpService) CreateUnit(shopID string, authUsername string, doc models.Unit) (string, error) {
+func (svc UnitHttpService) CreateUnit(ctx context.Context, shopID string, authUsername string, doc models.Unit) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "unitcode", doc.UnitCode)
@@ -209,9 +210,9 @@ func (svc UnitHttpService) CreateUnit(shopID string, authUsername string, doc mo
 	return newGuidFixed, nil
 }
 
-func (svc UnitHttpService) UpdateUnit(shopID string, guid string, authUsername string, doc models.Unit) error {
+func (svc UnitHttpService) UpdateUnit(ctx context.Context, shopID string, guid string, authUsername string, doc models.Unit) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -253,9 +254,9 @@ func (svc UnitHttpService) UpdateUnit(shopID string, guid string, authUsername s
 	return nil
 }
 
-func (svc UnitHttpService) UpdateFieldUnit(shopID string, guid string, authUsername string, doc models.Unit) error {
+func (svc UnitHttpService) UpdateFieldUnit(ctx context.Context, shopID string, guid string, authUsername string, doc models.Unit) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -314,9 +315,9 @@ func (svc UnitHttpService) UpdateFieldUnit(shopID string, guid string, authUsern
 	return nil
 }
 
-func (svc UnitHttpService) existsUnitRefInProduct(shopID, unitCode string) (bool, error) {
+func (svc UnitHttpService) existsUnitRefInProduct(ctx context.Context, shopID, unitCode string) (bool, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docCount, err := svc.repoProductBarcode.CountByUnitCodes(ctx, shopID, []string{unitCode})
@@ -332,9 +333,9 @@ func (svc UnitHttpService) existsUnitRefInProduct(shopID, unitCode string) (bool
 	return false, nil
 }
 
-func (svc UnitHttpService) deleteByUnitCode(shopID, guid, authUsername string) (models.UnitDoc, error) {
+func (svc UnitHttpService) deleteByUnitCode(ctx context.Context, shopID, guid, authUsername string) (models.UnitDoc, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -347,7 +348,7 @@ func (svc UnitHttpService) deleteByUnitCode(shopID, guid, authUsername string) (
 		return findDoc, nil
 	}
 
-	existsInProduct, err := svc.existsUnitRefInProduct(shopID, findDoc.UnitCode)
+	existsInProduct, err := svc.existsUnitRefInProduct(ctx, shopID, findDoc.UnitCode)
 
 	if existsInProduct {
 		return findDoc, err
@@ -361,9 +362,9 @@ func (svc UnitHttpService) deleteByUnitCode(shopID, guid, authUsername string) (
 	return findDoc, nil
 }
 
-func (svc UnitHttpService) DeleteUnit(shopID, guid, authUsername string) error {
+func (svc UnitHttpService) DeleteUnit(ctx context.Context, shopID, guid, authUsername string) error {
 
-	doc, err := svc.deleteByUnitCode(shopID, guid, authUsername)
+	doc, err := svc.deleteByUnitCode(ctx, shopID, guid, authUsername)
 
 	if err != nil {
 		return err
@@ -377,9 +378,9 @@ func (svc UnitHttpService) DeleteUnit(shopID, guid, authUsername string) error {
 	return nil
 }
 
-func (svc UnitHttpService) DeleteUnitByGUIDs(shopID, authUsername string, GUIDs []string) error {
+func (svc UnitHttpService) DeleteUnitByGUIDs(ctx context.Context, shopID, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
@@ -429,9 +430,9 @@ func (svc UnitHttpService) DeleteUnitByGUIDs(shopID, authUsername string, GUIDs
 	return nil
 }
 
-func (svc UnitHttpService) InfoUnit(shopID string, guid string) (models.UnitInfo, error) {
+func (svc UnitHttpService) InfoUnit(ctx context.Context, shopID string, guid string) (models.UnitInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -448,9 +449,9 @@ func (svc UnitHttpService) InfoUnit(shopID string, guid string) (models.UnitInfo
 
 }
 
-func (svc UnitHttpService) InfoUnitWTFArray(shopID string, unitCodes []string) ([]interface{}, error) {
+func (svc UnitHttpService) InfoUnitWTFArray(ctx context.Context, shopID string, unitCodes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -470,9 +471,9 @@ func (svc UnitHttpService) InfoUnitWTFArray(shopID string, unitCodes []string) (
 	return docList, nil
 }
 
-func (svc UnitHttpService) InfoWTFArrayMaster(codes []string) ([]interface{}, error) {
+func (svc UnitHttpService) InfoWTFArrayMaster(ctx context.Context, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -498,9 +499,9 @@ func (svc UnitHttpService) InfoWTFArrayMaster(codes []string) ([]interface{}, er
 	return docList, nil
 }
 
-func (svc UnitHttpService) SearchUnit(shopID string, codeFilters []string, pageable micromodels.Pageable) ([]models.UnitInfo, mongopagination.PaginationData, error) {
+func (svc UnitHttpService) SearchUnit(ctx context.Context, shopID string, codeFilters []string, pageable micromodels.Pageable) ([]models.UnitInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -522,9 +523,9 @@ func (svc UnitHttpService) SearchUnit(shopID string, codeFilters []string, pagea
 	return docList, pagination, nil
 }
 
-func (svc UnitHttpService) SearchUnitLimit(shopID string, langCode string, codeFilters []string, pageableStep micromodels.PageableStep) ([]models.UnitInfo, int, error) {
+func (svc UnitHttpService) SearchUnitLimit(ctx context.Context, shopID string, langCode string, codeFilters []string, pageableStep micromodels.PageableStep) ([]models.UnitInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -560,9 +561,9 @@ func (svc UnitHttpService) SearchUnitLimit(shopID string, langCode string, codeF
 	return docList, total, nil
 }
 
-func (svc UnitHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Unit) (common.BulkImport, error) {
+func (svc UnitHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Unit) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Unit](dataList, svc.getDocIDKey)
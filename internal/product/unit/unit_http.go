@@ -99,7 +99,7 @@ func (h *UnitHttp) UploadFile(ctx microservice.IContext) error {
 	}
 
 	// Process file และบันทึกข้อมูล
-	result, err := h.svc.ImportUnitsFromFile(fileBytes, shopID, authUsername)
+	result, err := h.svc.ImportUnitsFromFile(ctx.Context(), fileBytes, shopID, authUsername)
 	if err != nil {
 		ctx.Response(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return nil
@@ -136,7 +136,7 @@ func (h UnitHttp) CreateUnit(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateUnit(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateUnit(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -186,7 +186,7 @@ func (h UnitHttp) UpdateUnit(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateUnit(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateUnit(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -238,7 +238,7 @@ func (h UnitHttp) UpdateFieldUnit(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateFieldUnit(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateFieldUnit(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -274,7 +274,7 @@ func (h UnitHttp) InfoUnit(ctx microservice.IContext) error {
 	}
 
 	h.ms.Logger.Debugf("Get Unit %v", id)
-	doc, err := h.svc.InfoUnit(shopID, id)
+	doc, err := h.svc.InfoUnit(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -318,7 +318,7 @@ func (h UnitHttp) InfoArray(ctx microservice.IContext) error {
 	}
 
 	// where to filter array
-	doc, err := h.svc.InfoUnitWTFArray(shopID, docReq)
+	doc, err := h.svc.InfoUnitWTFArray(ctx.Context(), shopID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -358,7 +358,7 @@ func (h UnitHttp) InfoArrayMaster(ctx microservice.IContext) error {
 	}
 
 	// where to filter array master
-	doc, err := h.svc.InfoWTFArrayMaster(docReq)
+	doc, err := h.svc.InfoWTFArrayMaster(ctx.Context(), docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -397,7 +397,7 @@ func (h UnitHttp) SearchUnit(ctx microservice.IContext) error {
 		unitCodeFilters = strings.Split(unitCode, ",")
 	}
 
-	docList, pagination, err := h.svc.SearchUnit(shopID, unitCodeFilters, pageable)
+	docList, pagination, err := h.svc.SearchUnit(ctx.Context(), shopID, unitCodeFilters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -440,7 +440,7 @@ func (h UnitHttp) SearchUnitLimit(ctx microservice.IContext) error {
 		unitCodeFilters = strings.Split(unitCode, ",")
 	}
 
-	docList, total, err := h.svc.SearchUnitLimit(shopID, lang, unitCodeFilters, pageableStep)
+	docList, total, err := h.svc.SearchUnitLimit(ctx.Context(), shopID, lang, unitCodeFilters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -480,7 +480,7 @@ func (h UnitHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -519,7 +519,7 @@ func (h UnitHttp) DeleteUnit(ctx microservice.IContext) error {
 		return nil
 	}
 
-	err := h.svc.DeleteUnit(shopID, id, authUsername)
+	err := h.svc.DeleteUnit(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -558,7 +558,7 @@ func (h UnitHttp) DeleteByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteUnitByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteUnitByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
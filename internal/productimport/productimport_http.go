@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"path/filepath"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job"
+	jobServices "smlaicloudplatform/internal/job/services"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	product_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
@@ -24,9 +26,10 @@ import (
 type IProductImportHttp interface{}
 
 type ProductImportHttp struct {
-	ms  *microservice.Microservice
-	cfg config.IConfig
-	svc services.IProductImportService
+	ms        *microservice.Microservice
+	cfg       config.IConfig
+	svc       services.IProductImportService
+	jobRunner jobServices.IJobRunner
 }
 
 func NewProductImportHttp(ms *microservice.Microservice, cfg config.IConfig) ProductImportHttp {
@@ -52,9 +55,10 @@ func NewProductImportHttp(ms *microservice.Microservice, cfg config.IConfig) Pro
 	svc := services.NewProductImportService(chRepo, repo, stockBalanceSvc, unitRepo, utils.RandStringBytesMaskImprSrcUnsafe, utils.NewGUID, time.Now)
 
 	return ProductImportHttp{
-		ms:  ms,
-		cfg: cfg,
-		svc: svc,
+		ms:        ms,
+		cfg:       cfg,
+		svc:       svc,
+		jobRunner: job.InitJobRunner(ms, cfg),
 	}
 }
 
@@ -297,13 +301,13 @@ func (h ProductImportHttp) DeleteByTask(ctx microservice.IContext) error {
 	return nil
 }
 
-// Delete ProductImport By Task ID godoc
-// @Description Delete ProductImport By Task ID
+// Save ProductImport Task godoc
+// @Description Save ProductImport task in background job, job id is returned to poll at /job/{id}
 // @Tags		ProductImport
 // @Param		task-id		path		string		true		"task id"
 // @Param		ProductImportHeader  body      models.ProductImportHeader  true  "ProductImportHeader"
 // @Accept 		json
-// @Success		201	{object}	common.ApiResponse
+// @Success		202	{object}	common.ApiResponse
 // @Failure		401 {object}	common.AuthResponseFailed
 // @Security     AccessToken
 // @Router /productimport/{task-id} [post]
@@ -324,15 +328,18 @@ func (h ProductImportHttp) SaveTask(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.SaveTask(shopID, authUsername, taskID, docReq)
+	jobID, err := h.jobRunner.Start(shopID, authUsername, "productimport", func(job jobServices.IJobContext) (interface{}, error) {
+		return nil, h.svc.SaveTask(job, shopID, authUsername, taskID, docReq)
+	})
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		job.ResponseStartError(ctx, err)
 		return err
 	}
 
-	ctx.Response(http.StatusCreated, common.ApiResponse{
+	ctx.Response(http.StatusAccepted, common.ApiResponse{
 		Success: true,
+		ID:      jobID,
 	})
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"strings"
 
+	jobServices "smlaicloudplatform/internal/job/services"
 	common "smlaicloudplatform/internal/models"
 	product_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repo "smlaicloudplatform/internal/product/productbarcode/repositories"
@@ -22,6 +23,9 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
+// saveTaskBatchSize is number of product barcodes saved at a time by SaveTask
+const saveTaskBatchSize = 1000
+
 type IProductImportService interface {
 	List(shopID string, taskID string, pageable micromodels.Pageable) ([]models.ProductImportInfo, models.PaginationData, error)
 	Create(shopID string, authUsername string, req *models.ProductImport) error
@@ -29,7 +33,7 @@ type IProductImportService interface {
 	Delete(shopID string, guid string) error
 	DeleteTask(shopID string, taskID string) error
 	ImportFromFile(shopID string, authUsername string, fileUpload io.Reader) (string, error)
-	SaveTask(shopID string, authUsername string, taskID string, docHeader models.ProductImportHeader) error
+	SaveTask(job jobServices.IJobContext, shopID string, authUsername string, taskID string, docHeader models.ProductImportHeader) error
 	Verify(shopID string, taskID string) error
 }
 
@@ -412,15 +416,18 @@ func (svc ProductImportService) updateUnitExist(shopID string, taskID string, is
 	return nil
 }
 
-func (svc ProductImportService) SaveTask(shopID string, authUsername string, taskID string, docHeader models.ProductImportHeader) error {
+func (svc ProductImportService) SaveTask(job jobServices.IJobContext, shopID string, authUsername string, taskID string, docHeader models.ProductImportHeader) error {
+
+	ctx := job.Context()
 
+	job.Progress(0, "verify items")
 	err := svc.Verify(shopID, taskID)
 
 	if err != nil {
 		return err
 	}
 
-	countDuplicate, err := svc.chRepo.CountDuplicate(context.Background(), shopID, taskID, true)
+	countDuplicate, err := svc.chRepo.CountDuplicate(ctx, shopID, taskID, true)
 
 	if err != nil {
 		return errors.New("counting duplicate failed")
@@ -430,7 +437,7 @@ func (svc ProductImportService) SaveTask(shopID string, authUsername string, tas
 		return errors.New("items barcode duplicate ")
 	}
 
-	countExist, err := svc.chRepo.CountExist(context.Background(), shopID, taskID, true)
+	countExist, err := svc.chRepo.CountExist(ctx, shopID, taskID, true)
 
 	if err != nil {
 		return errors.New("counting exist failed")
@@ -440,7 +447,7 @@ func (svc ProductImportService) SaveTask(shopID string, authUsername string, tas
 		return errors.New("items barcode exist")
 	}
 
-	countUnitNotExist, err := svc.chRepo.CountUnitExist(context.Background(), shopID, taskID, true)
+	countUnitNotExist, err := svc.chRepo.CountUnitExist(ctx, shopID, taskID, true)
 
 	if err != nil {
 		return errors.New("counting unit not exist failed")
@@ -450,7 +457,8 @@ func (svc ProductImportService) SaveTask(shopID string, authUsername string, tas
 		return errors.New("items unit not exist")
 	}
 
-	docs, err := svc.chRepo.All(context.Background(), shopID, taskID)
+	job.Progress(10, "prepare items")
+	docs, err := svc.chRepo.All(ctx, shopID, taskID)
 
 	if err != nil {
 		return err
@@ -463,17 +471,32 @@ func (svc ProductImportService) SaveTask(shopID string, authUsername string, tas
 		tempUnitCodes = append(tempUnitCodes, doc.UnitCode)
 	}
 
-	unitDocs, err := svc.productUnitRepo.FindByUnitCodes(context.Background(), shopID, tempUnitCodes)
+	unitDocs, err := svc.productUnitRepo.FindByUnitCodes(ctx, shopID, tempUnitCodes)
 	if err != nil {
 		return err
 	}
 
 	dataDocs = svc.PrepareProductUnit(unitDocs, dataDocs)
 
-	_, err = svc.productBarcodeService.SaveInBatch(shopID, authUsername, dataDocs)
+	job.Logf("save %d product barcodes", len(dataDocs))
+	for start := 0; start < len(dataDocs); start += saveTaskBatchSize {
+		if ctx.Err() != nil {
+			job.Logf("cancelled after %d product barcodes are saved", start)
+			return ctx.Err()
+		}
 
-	if err != nil {
-		return err
+		end := start + saveTaskBatchSize
+		if end > len(dataDocs) {
+			end = len(dataDocs)
+		}
+
+		_, err = svc.productBarcodeService.SaveInBatch(ctx, shopID, authUsername, dataDocs[start:end])
+
+		if err != nil {
+			return err
+		}
+
+		job.Progress(20+end*70/len(dataDocs), fmt.Sprintf("saved %d/%d product barcodes", end, len(dataDocs)))
 	}
 
 	err = svc.DeleteTask(shopID, taskID)
@@ -79,7 +79,7 @@ func (h SectionBranchHttp) SaveSectionBranch(ctx microservice.IContext) error {
 		return err
 	}
 
-	id, err := h.svc.SaveSectionBranch(shopID, authUsername, *docReq)
+	id, err := h.svc.SaveSectionBranch(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -110,7 +110,7 @@ func (h SectionBranchHttp) DeleteSectionBranch(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSectionBranch(shopID, id, authUsername)
+	err := h.svc.DeleteSectionBranch(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -149,7 +149,7 @@ func (h SectionBranchHttp) DeleteSectionBranchByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeleteSectionBranchByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSectionBranchByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -179,7 +179,7 @@ func (h SectionBranchHttp) InfoSectionBranch(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SectionBranch %v", id)
-	doc, err := h.svc.InfoSectionBranch(shopID, id)
+	doc, err := h.svc.InfoSectionBranch(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -209,7 +209,7 @@ func (h SectionBranchHttp) InfoSectionBranchByCode(ctx microservice.IContext) er
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSectionBranchByBranchCode(shopID, code)
+	doc, err := h.svc.InfoSectionBranchByBranchCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -240,7 +240,7 @@ func (h SectionBranchHttp) SearchSectionBranchPage(ctx microservice.IContext) er
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSectionBranch(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchSectionBranch(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -275,7 +275,7 @@ func (h SectionBranchHttp) SearchSectionBranchStep(ctx microservice.IContext) er
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchSectionBranchStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchSectionBranchStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -315,7 +315,7 @@ func (h SectionBranchHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
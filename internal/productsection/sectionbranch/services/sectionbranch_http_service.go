@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/productsection/sectionbranch/repositories"
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -18,14 +19,14 @@ import (
 )
 
 type ISectionBranchHttpService interface {
-	SaveSectionBranch(shopID string, authUsername string, doc models.SectionBranch) (string, error)
-	DeleteSectionBranch(shopID string, guid string, authUsername string) error
-	DeleteSectionBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSectionBranch(shopID string, guid string) (models.SectionBranchInfo, error)
-	InfoSectionBranchByBranchCode(shopID string, branchcode string) (models.SectionBranchInfo, error)
-	SearchSectionBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBranchInfo, mongopagination.PaginationData, error)
-	SearchSectionBranchStep(shopID string, langBranchCode string, pageableStep micromodels.PageableStep) ([]models.SectionBranchInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SectionBranch) (common.BulkImport, error)
+	SaveSectionBranch(ctx context.Context, shopID string, authUsername string, doc models.SectionBranch) (string, error)
+	DeleteSectionBranch(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSectionBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSectionBranch(ctx context.Context, shopID string, guid string) (models.SectionBranchInfo, error)
+	InfoSectionBranchByBranchCode(ctx context.Context, shopID string, branchcode string) (models.SectionBranchInfo, error)
+	SearchSectionBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBranchInfo, mongopagination.PaginationData, error)
+	SearchSectionBranchStep(ctx context.Context, shopID string, langBranchCode string, pageableStep micromodels.PageableStep) ([]models.SectionBranchInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionBranch) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewSectionBranchHttpService(repo repositories.ISectionBranchRepository, gen
 	return insSvc
 }
 
-func (svc SectionBranchHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SectionBranchHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SectionBranchHttpService) SaveSectionBranch(shopID string, authUsername string, doc models.SectionBranch) (string, error) {
+func (svc SectionBranchHttpService) SaveSectionBranch(ctx context.Context, shopID string, authUsername string, doc models.SectionBranch) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "branchcode", doc.BranchCode)
@@ -72,9 +73,9 @@ func (svc SectionBranchHttpService) SaveSectionBranch(shopID string, authUsernam
 
 	guidFixed := ""
 	if len(findDoc.GuidFixed) < 1 {
-		guidFixed, err = svc.create(findDoc, shopID, authUsername, doc)
+		guidFixed, err = svc.create(ctx, findDoc, shopID, authUsername, doc)
 	} else {
-		err = svc.update(findDoc, shopID, authUsername, doc)
+		err = svc.update(ctx, findDoc, shopID, authUsername, doc)
 		guidFixed = findDoc.GuidFixed
 	}
 
@@ -85,9 +86,9 @@ func (svc SectionBranchHttpService) SaveSectionBranch(shopID string, authUsernam
 	return guidFixed, nil
 }
 
-func (svc SectionBranchHttpService) create(findDoc models.SectionBranchDoc, shopID string, authUsername string, doc models.SectionBranch) (string, error) {
+func (svc SectionBranchHttpService) create(ctx context.Context, findDoc models.SectionBranchDoc, shopID string, authUsername string, doc models.SectionBranch) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := svc.generateGUID()
@@ -111,9 +112,9 @@ func (svc SectionBranchHttpService) create(findDoc models.SectionBranchDoc, shop
 	return newGuidFixed, nil
 }
 
-func (svc SectionBranchHttpService) update(findDoc models.SectionBranchDoc, shopID string, authUsername string, doc models.SectionBranch) error {
+func (svc SectionBranchHttpService) update(ctx context.Context, findDoc models.SectionBranchDoc, shopID string, authUsername string, doc models.SectionBranch) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	if len(findDoc.GuidFixed) < 1 {
@@ -136,9 +137,9 @@ func (svc SectionBranchHttpService) update(findDoc models.SectionBranchDoc, shop
 	return nil
 }
 
-func (svc SectionBranchHttpService) DeleteSectionBranch(shopID string, guid string, authUsername string) error {
+func (svc SectionBranchHttpService) DeleteSectionBranch(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc SectionBranchHttpService) DeleteSectionBranch(shopID string, guid stri
 	return nil
 }
 
-func (svc SectionBranchHttpService) DeleteSectionBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SectionBranchHttpService) DeleteSectionBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -178,9 +179,9 @@ func (svc SectionBranchHttpService) DeleteSectionBranchByGUIDs(shopID string, au
 	return nil
 }
 
-func (svc SectionBranchHttpService) InfoSectionBranch(shopID string, guid string) (models.SectionBranchInfo, error) {
+func (svc SectionBranchHttpService) InfoSectionBranch(ctx context.Context, shopID string, guid string) (models.SectionBranchInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -196,9 +197,9 @@ func (svc SectionBranchHttpService) InfoSectionBranch(shopID string, guid string
 	return findDoc.SectionBranchInfo, nil
 }
 
-func (svc SectionBranchHttpService) InfoSectionBranchByBranchCode(shopID string, branchcode string) (models.SectionBranchInfo, error) {
+func (svc SectionBranchHttpService) InfoSectionBranchByBranchCode(ctx context.Context, shopID string, branchcode string) (models.SectionBranchInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "branchcode", branchcode)
@@ -214,9 +215,9 @@ func (svc SectionBranchHttpService) InfoSectionBranchByBranchCode(shopID string,
 	return findDoc.SectionBranchInfo, nil
 }
 
-func (svc SectionBranchHttpService) SearchSectionBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBranchInfo, mongopagination.PaginationData, error) {
+func (svc SectionBranchHttpService) SearchSectionBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBranchInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -232,9 +233,9 @@ func (svc SectionBranchHttpService) SearchSectionBranch(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc SectionBranchHttpService) SearchSectionBranchStep(shopID string, langBranchCode string, pageableStep micromodels.PageableStep) ([]models.SectionBranchInfo, int, error) {
+func (svc SectionBranchHttpService) SearchSectionBranchStep(ctx context.Context, shopID string, langBranchCode string, pageableStep micromodels.PageableStep) ([]models.SectionBranchInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -258,9 +259,9 @@ func (svc SectionBranchHttpService) SearchSectionBranchStep(shopID string, langB
 	return docList, total, nil
 }
 
-func (svc SectionBranchHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.SectionBranch) (common.BulkImport, error) {
+func (svc SectionBranchHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionBranch) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SectionBranch](dataList, svc.getDocIDKey)
@@ -124,7 +124,7 @@ func TestSaveSectionBranch(t *testing.T) {
 		mockRepo.On("Create", mock.Anything).Return("testGuidFixed", nil)
 
 		// Execute
-		guidFixed, err := svc.SaveSectionBranch(shopID, authUsername, doc)
+		guidFixed, err := svc.SaveSectionBranch(context.Background(), shopID, authUsername, doc)
 
 		// Assert
 		assert.NoError(t, err)
@@ -152,7 +152,7 @@ func TestSaveSectionBranch(t *testing.T) {
 		mockRepo.On("Update", shopID, existingDoc.GuidFixed, mock.Anything).Return(nil)
 
 		// Execute
-		guidFixed, err := svc.SaveSectionBranch(shopID, authUsername, doc)
+		guidFixed, err := svc.SaveSectionBranch(context.Background(), shopID, authUsername, doc)
 
 		// Assert
 		assert.NoError(t, err)
@@ -79,7 +79,7 @@ func (h SectionBusinessTypeHttp) SaveSectionBusinessType(ctx microservice.IConte
 		return err
 	}
 
-	id, err := h.svc.SaveSectionBusinessType(shopID, authUsername, *docReq)
+	id, err := h.svc.SaveSectionBusinessType(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -110,7 +110,7 @@ func (h SectionBusinessTypeHttp) DeleteSectionBusinessType(ctx microservice.ICon
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSectionBusinessType(shopID, id, authUsername)
+	err := h.svc.DeleteSectionBusinessType(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -149,7 +149,7 @@ func (h SectionBusinessTypeHttp) DeleteSectionBusinessTypeByGUIDs(ctx microservi
 		return err
 	}
 
-	err = h.svc.DeleteSectionBusinessTypeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSectionBusinessTypeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -179,7 +179,7 @@ func (h SectionBusinessTypeHttp) InfoSectionBusinessType(ctx microservice.IConte
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SectionBusinessType %v", id)
-	doc, err := h.svc.InfoSectionBusinessType(shopID, id)
+	doc, err := h.svc.InfoSectionBusinessType(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -209,7 +209,7 @@ func (h SectionBusinessTypeHttp) InfoSectionBusinessTypeByCode(ctx microservice.
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSectionBusinessTypeByBusinessTypeCode(shopID, code)
+	doc, err := h.svc.InfoSectionBusinessTypeByBusinessTypeCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -240,7 +240,7 @@ func (h SectionBusinessTypeHttp) SearchSectionBusinessTypePage(ctx microservice.
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSectionBusinessType(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchSectionBusinessType(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -275,7 +275,7 @@ func (h SectionBusinessTypeHttp) SearchSectionBusinessTypeStep(ctx microservice.
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchSectionBusinessTypeStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchSectionBusinessTypeStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -315,7 +315,7 @@ func (h SectionBusinessTypeHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
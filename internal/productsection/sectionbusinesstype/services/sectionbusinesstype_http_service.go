@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,14 +20,14 @@ import (
 )
 
 type ISectionBusinessTypeHttpService interface {
-	SaveSectionBusinessType(shopID string, authUsername string, doc models.SectionBusinessType) (string, error)
-	DeleteSectionBusinessType(shopID string, guid string, authUsername string) error
-	DeleteSectionBusinessTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSectionBusinessType(shopID string, guid string) (models.SectionBusinessTypeInfo, error)
-	InfoSectionBusinessTypeByBusinessTypeCode(shopID string, businessTypeCode string) (models.SectionBusinessTypeInfo, error)
-	SearchSectionBusinessType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBusinessTypeInfo, mongopagination.PaginationData, error)
-	SearchSectionBusinessTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionBusinessTypeInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SectionBusinessType) (common.BulkImport, error)
+	SaveSectionBusinessType(ctx context.Context, shopID string, authUsername string, doc models.SectionBusinessType) (string, error)
+	DeleteSectionBusinessType(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSectionBusinessTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSectionBusinessType(ctx context.Context, shopID string, guid string) (models.SectionBusinessTypeInfo, error)
+	InfoSectionBusinessTypeByBusinessTypeCode(ctx context.Context, shopID string, businessTypeCode string) (models.SectionBusinessTypeInfo, error)
+	SearchSectionBusinessType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBusinessTypeInfo, mongopagination.PaginationData, error)
+	SearchSectionBusinessTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionBusinessTypeInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionBusinessType) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewSectionBusinessTypeHttpService(repo repositories.ISectionBusinessTypeRep
 	return insSvc
 }
 
-func (svc SectionBusinessTypeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SectionBusinessTypeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SectionBusinessTypeHttpService) SaveSectionBusinessType(shopID string, authUsername string, doc models.SectionBusinessType) (string, error) {
+func (svc SectionBusinessTypeHttpService) SaveSectionBusinessType(ctx context.Context, shopID string, authUsername string, doc models.SectionBusinessType) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "businesstypecode", doc.BusinessTypeCode)
@@ -71,9 +72,9 @@ func (svc SectionBusinessTypeHttpService) SaveSectionBusinessType(shopID string,
 
 	guidFixed := ""
 	if len(findDoc.GuidFixed) < 1 {
-		guidFixed, err = svc.create(findDoc, shopID, authUsername, doc)
+		guidFixed, err = svc.create(ctx, findDoc, shopID, authUsername, doc)
 	} else {
-		err = svc.update(findDoc, shopID, authUsername, doc)
+		err = svc.update(ctx, findDoc, shopID, authUsername, doc)
 		guidFixed = findDoc.GuidFixed
 	}
 
@@ -84,9 +85,9 @@ func (svc SectionBusinessTypeHttpService) SaveSectionBusinessType(shopID string,
 	return guidFixed, nil
 }
 
-func (svc SectionBusinessTypeHttpService) create(findDoc models.SectionBusinessTypeDoc, shopID string, authUsername string, doc models.SectionBusinessType) (string, error) {
+func (svc SectionBusinessTypeHttpService) create(ctx context.Context, findDoc models.SectionBusinessTypeDoc, shopID string, authUsername string, doc models.SectionBusinessType) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -110,9 +111,9 @@ func (svc SectionBusinessTypeHttpService) create(findDoc models.SectionBusinessT
 	return newGuidFixed, nil
 }
 
-func (svc SectionBusinessTypeHttpService) update(findDoc models.SectionBusinessTypeDoc, shopID string, authUsername string, doc models.SectionBusinessType) error {
+func (svc SectionBusinessTypeHttpService) update(ctx context.Context, findDoc models.SectionBusinessTypeDoc, shopID string, authUsername string, doc models.SectionBusinessType) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc.SectionBusinessType = doc
@@ -131,9 +132,9 @@ func (svc SectionBusinessTypeHttpService) update(findDoc models.SectionBusinessT
 	return nil
 }
 
-func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessType(shopID string, guid string, authUsername string) error {
+func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessType(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -156,9 +157,9 @@ func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessType(shopID strin
 	return nil
 }
 
-func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessTypeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessTypeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -173,9 +174,9 @@ func (svc SectionBusinessTypeHttpService) DeleteSectionBusinessTypeByGUIDs(shopI
 	return nil
 }
 
-func (svc SectionBusinessTypeHttpService) InfoSectionBusinessType(shopID string, guid string) (models.SectionBusinessTypeInfo, error) {
+func (svc SectionBusinessTypeHttpService) InfoSectionBusinessType(ctx context.Context, shopID string, guid string) (models.SectionBusinessTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -191,9 +192,9 @@ func (svc SectionBusinessTypeHttpService) InfoSectionBusinessType(shopID string,
 	return findDoc.SectionBusinessTypeInfo, nil
 }
 
-func (svc SectionBusinessTypeHttpService) InfoSectionBusinessTypeByBusinessTypeCode(shopID string, businessTypeCode string) (models.SectionBusinessTypeInfo, error) {
+func (svc SectionBusinessTypeHttpService) InfoSectionBusinessTypeByBusinessTypeCode(ctx context.Context, shopID string, businessTypeCode string) (models.SectionBusinessTypeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "businesstypecode", businessTypeCode)
@@ -209,9 +210,9 @@ func (svc SectionBusinessTypeHttpService) InfoSectionBusinessTypeByBusinessTypeC
 	return findDoc.SectionBusinessTypeInfo, nil
 }
 
-func (svc SectionBusinessTypeHttpService) SearchSectionBusinessType(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBusinessTypeInfo, mongopagination.PaginationData, error) {
+func (svc SectionBusinessTypeHttpService) SearchSectionBusinessType(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionBusinessTypeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -227,9 +228,9 @@ func (svc SectionBusinessTypeHttpService) SearchSectionBusinessType(shopID strin
 	return docList, pagination, nil
 }
 
-func (svc SectionBusinessTypeHttpService) SearchSectionBusinessTypeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionBusinessTypeInfo, int, error) {
+func (svc SectionBusinessTypeHttpService) SearchSectionBusinessTypeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionBusinessTypeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -253,9 +254,9 @@ func (svc SectionBusinessTypeHttpService) SearchSectionBusinessTypeStep(shopID s
 	return docList, total, nil
 }
 
-func (svc SectionBusinessTypeHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.SectionBusinessType) (common.BulkImport, error) {
+func (svc SectionBusinessTypeHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionBusinessType) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SectionBusinessType](dataList, svc.getDocIDKey)
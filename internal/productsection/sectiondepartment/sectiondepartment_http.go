@@ -80,7 +80,7 @@ func (h SectionDepartmentHttp) SaveSectionDepartment(ctx microservice.IContext)
 		return err
 	}
 
-	id, err := h.svc.SaveSectionDepartment(shopID, authUsername, *docReq)
+	id, err := h.svc.SaveSectionDepartment(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -111,7 +111,7 @@ func (h SectionDepartmentHttp) DeleteSectionDepartment(ctx microservice.IContext
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSectionDepartment(shopID, id, authUsername)
+	err := h.svc.DeleteSectionDepartment(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -150,7 +150,7 @@ func (h SectionDepartmentHttp) DeleteSectionDepartmentByGUIDs(ctx microservice.I
 		return err
 	}
 
-	err = h.svc.DeleteSectionDepartmentByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSectionDepartmentByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -180,7 +180,7 @@ func (h SectionDepartmentHttp) InfoSectionDepartment(ctx microservice.IContext)
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SectionDepartment %v", id)
-	doc, err := h.svc.InfoSectionDepartment(shopID, id)
+	doc, err := h.svc.InfoSectionDepartment(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -212,7 +212,7 @@ func (h SectionDepartmentHttp) InfoSectionDepartmentByCode(ctx microservice.ICon
 	branchCode := ctx.Param("branchCode")
 	departmentCode := ctx.Param("departmentCode")
 
-	doc, err := h.svc.InfoSectionDepartmentByCode(shopID, branchCode, departmentCode)
+	doc, err := h.svc.InfoSectionDepartmentByCode(ctx.Context(), shopID, branchCode, departmentCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -243,7 +243,7 @@ func (h SectionDepartmentHttp) SearchSectionDepartmentPage(ctx microservice.ICon
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSectionDepartment(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchSectionDepartment(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -278,7 +278,7 @@ func (h SectionDepartmentHttp) SearchSectionDepartmentStep(ctx microservice.ICon
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchSectionDepartmentStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchSectionDepartmentStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -318,7 +318,7 @@ func (h SectionDepartmentHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,14 +20,14 @@ import (
 )
 
 type ISectionDepartmentHttpService interface {
-	SaveSectionDepartment(shopID string, authUsername string, doc models.SectionDepartment) (string, error)
-	DeleteSectionDepartment(shopID string, guid string, authUsername string) error
-	DeleteSectionDepartmentByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSectionDepartment(shopID string, guid string) (models.SectionDepartmentInfo, error)
-	InfoSectionDepartmentByCode(shopID, branchCode, departmentCode string) (models.SectionDepartmentInfo, error)
-	SearchSectionDepartment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionDepartmentInfo, mongopagination.PaginationData, error)
-	SearchSectionDepartmentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionDepartmentInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SectionDepartment) (common.BulkImport, error)
+	SaveSectionDepartment(ctx context.Context, shopID string, authUsername string, doc models.SectionDepartment) (string, error)
+	DeleteSectionDepartment(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSectionDepartmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSectionDepartment(ctx context.Context, shopID string, guid string) (models.SectionDepartmentInfo, error)
+	InfoSectionDepartmentByCode(ctx context.Context, shopID, branchCode, departmentCode string) (models.SectionDepartmentInfo, error)
+	SearchSectionDepartment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionDepartmentInfo, mongopagination.PaginationData, error)
+	SearchSectionDepartmentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionDepartmentInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionDepartment) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewSectionDepartmentHttpService(repo repositories.ISectionDepartmentReposit
 	return insSvc
 }
 
-func (svc SectionDepartmentHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SectionDepartmentHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SectionDepartmentHttpService) SaveSectionDepartment(shopID string, authUsername string, doc models.SectionDepartment) (string, error) {
+func (svc SectionDepartmentHttpService) SaveSectionDepartment(ctx context.Context, shopID string, authUsername string, doc models.SectionDepartment) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneFilter(
@@ -136,9 +137,9 @@ func (svc SectionDepartmentHttpService) update(ctx context.Context, findDoc mode
 	return nil
 }
 
-func (svc SectionDepartmentHttpService) DeleteSectionDepartment(shopID string, guid string, authUsername string) error {
+func (svc SectionDepartmentHttpService) DeleteSectionDepartment(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc SectionDepartmentHttpService) DeleteSectionDepartment(shopID string, g
 	return nil
 }
 
-func (svc SectionDepartmentHttpService) DeleteSectionDepartmentByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SectionDepartmentHttpService) DeleteSectionDepartmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -178,9 +179,9 @@ func (svc SectionDepartmentHttpService) DeleteSectionDepartmentByGUIDs(shopID st
 	return nil
 }
 
-func (svc SectionDepartmentHttpService) InfoSectionDepartment(shopID string, guid string) (models.SectionDepartmentInfo, error) {
+func (svc SectionDepartmentHttpService) InfoSectionDepartment(ctx context.Context, shopID string, guid string) (models.SectionDepartmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -196,9 +197,9 @@ func (svc SectionDepartmentHttpService) InfoSectionDepartment(shopID string, gui
 	return findDoc.SectionDepartmentInfo, nil
 }
 
-func (svc SectionDepartmentHttpService) InfoSectionDepartmentByCode(shopID, branchCode, departmentCode string) (models.SectionDepartmentInfo, error) {
+func (svc SectionDepartmentHttpService) InfoSectionDepartmentByCode(ctx context.Context, shopID, branchCode, departmentCode string) (models.SectionDepartmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneByCode(ctx, shopID, branchCode, departmentCode)
@@ -214,9 +215,9 @@ func (svc SectionDepartmentHttpService) InfoSectionDepartmentByCode(shopID, bran
 	return findDoc.SectionDepartmentInfo, nil
 }
 
-func (svc SectionDepartmentHttpService) SearchSectionDepartment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionDepartmentInfo, mongopagination.PaginationData, error) {
+func (svc SectionDepartmentHttpService) SearchSectionDepartment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SectionDepartmentInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -232,9 +233,9 @@ func (svc SectionDepartmentHttpService) SearchSectionDepartment(shopID string, f
 	return docList, pagination, nil
 }
 
-func (svc SectionDepartmentHttpService) SearchSectionDepartmentStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionDepartmentInfo, int, error) {
+func (svc SectionDepartmentHttpService) SearchSectionDepartmentStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.SectionDepartmentInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -258,9 +259,9 @@ func (svc SectionDepartmentHttpService) SearchSectionDepartmentStep(shopID strin
 	return docList, total, nil
 }
 
-func (svc SectionDepartmentHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.SectionDepartment) (common.BulkImport, error) {
+func (svc SectionDepartmentHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SectionDepartment) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SectionDepartment](dataList, svc.getDocIDKey)
@@ -0,0 +1,146 @@
+package models
+
+import (
+	auth_model "smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// default roles replace OWNER, ADMIN and USER role of shop user, they are not stored and cannot be changed
+const (
+	RoleOwner = "OWNER"
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+)
+
+// shopDataPermissions is permissions of routes of data of the shop e.g. products, debtors, journals and transactions,
+// they are every route except administration of the shop
+var shopDataPermissions = []string{
+	"shop:read",
+	"shop.branch:*",
+	"shopcoupon*:*",
+	"transaction*:*",
+	"smltransaction*:*",
+	"gl*:*",
+	"product*:*",
+	"unit*:*",
+	"color*:*",
+	"option*:*",
+	"dimension*:*",
+	"warehouse*:*",
+	"debtaccount*:*",
+	"member*:*",
+	"organization*:*",
+	"payment*:*",
+	"master*:*",
+	"pos*:*",
+	"restaurant*:*",
+	"order*:*",
+	"eorder*:*",
+	"storefront*:*",
+	"salechannel*:*",
+	"transportchannel*:*",
+	"zonedesign*:*",
+	"report*:*",
+	"documentimage*:*",
+	"slip*:*",
+	"upload*:*",
+	"media*:*",
+	"ocr*:*",
+	"filestatus*:*",
+	"stockbalanceimport*:*",
+	"task*:*",
+	"notify*:*",
+	"linenotify*:*",
+	"sms*:*",
+	"job*:*",
+}
+
+var DefaultRoles = []RoleInfo{
+	defaultRole(RoleOwner, "Owner", PermissionAll),
+	defaultRole(RoleAdmin, "Admin", append([]string{
+		PermissionShopUserRead,
+		PermissionShopUserUpdate,
+		PermissionShopUserDelete,
+		PermissionRoleRead,
+		"shop.employee:*",
+		PermissionAuditRead,
+	}, shopDataPermissions...)...),
+	defaultRole(RoleUser, "User", shopDataPermissions...),
+}
+
+func defaultRole(code string, name string, permissions ...string) RoleInfo {
+	lang := "en"
+	role := RoleInfo{IsDefault: true}
+	role.GuidFixed = code
+	role.Code = code
+	role.Names = &[]models.NameX{{Code: &lang, Name: &name}}
+	role.Permissions = permissions
+	return role
+}
+
+// FindDefaultRole return default role of the code
+func FindDefaultRole(code string) (RoleInfo, bool) {
+	for _, role := range DefaultRoles {
+		if role.Code == code {
+			return role, true
+		}
+	}
+	return RoleInfo{}, false
+}
+
+// DefaultRoleCode return default role of OWNER, ADMIN and USER role of shop user
+func DefaultRoleCode(role auth_model.UserRole) string {
+	switch role {
+	case auth_model.ROLE_OWNER:
+		return RoleOwner
+	case auth_model.ROLE_ADMIN:
+		return RoleAdmin
+	default:
+		return RoleUser
+	}
+}
+
+// ShopUserRoleCodes return roles of the shop user, user who is not assigned any role has default role of the role of the user
+func ShopUserRoleCodes(shopUser auth_model.ShopUser) []string {
+	if len(shopUser.Roles) == 0 {
+		return []string{DefaultRoleCode(shopUser.Role)}
+	}
+
+	return shopUser.Roles
+}
+
+// HasShopUserRole return true when the shop user has any of the roles
+func HasShopUserRole(shopUser auth_model.ShopUser, codes ...string) bool {
+	for _, role := range ShopUserRoleCodes(shopUser) {
+		for _, code := range codes {
+			if role == code {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReplaceDefaultRole return pipeline update which set role of shop user and replace default role in roles
+// by default role of the role, other roles which are assigned to the user are kept
+func ReplaceDefaultRole(role auth_model.UserRole, set bson.M) bson.A {
+	fields := bson.M{
+		"role": role,
+		"roles": bson.M{"$concatArrays": bson.A{
+			bson.A{DefaultRoleCode(role)},
+			bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$roles", bson.A{}}},
+				"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$this", bson.A{RoleOwner, RoleAdmin, RoleUser}}}}},
+			}},
+		}},
+	}
+
+	for key, value := range set {
+		fields[key] = value
+	}
+
+	return bson.A{bson.M{"$set": fields}}
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+const (
+	PermissionAll = "*"
+
+	PermissionShopUpdate = "shop:update"
+	PermissionShopDelete = "shop:delete"
+	// PermissionShopOwnerUpdate is needed to change or remove owner of the shop
+	PermissionShopOwnerUpdate = "shop.owner:update"
+
+	PermissionShopUserRead   = "shop.user:read"
+	PermissionShopUserUpdate = "shop.user:update"
+	PermissionShopUserDelete = "shop.user:delete"
+
+	PermissionRoleRead   = "shop.role:read"
+	PermissionRoleUpdate = "shop.role:update"
+
+	PermissionEmployeeRead   = "shop.employee:read"
+	PermissionEmployeeUpdate = "shop.employee:update"
+
+	PermissionApiKeyRead   = "shop.apikey:read"
+	PermissionApiKeyUpdate = "shop.apikey:update"
+
+	PermissionAuditRead = "shop.audit:read"
+
+	PermissionSecurityEventRead = "shop.securityevent:read"
+
+	PermissionWebhookRead   = "shop.webhook:read"
+	PermissionWebhookUpdate = "shop.webhook:update"
+
+	PermissionApprovalRuleRead   = "transaction.approvalrule:read"
+	PermissionApprovalRuleUpdate = "transaction.approvalrule:update"
+
+	PermissionPurchaseOrderSettingUpdate = "transaction.purchaseorder.setting:update"
+
+	PermissionSaleInvoiceRead   = "transaction.saleinvoice:read"
+	PermissionSaleInvoiceCreate = "transaction.saleinvoice:create"
+	PermissionSaleInvoiceUpdate = "transaction.saleinvoice:update"
+	PermissionSaleInvoiceDelete = "transaction.saleinvoice:delete"
+)
+
+// PermissionDefinition is permission which is enforced by route, it is listed for role editor
+type PermissionDefinition struct {
+	Permission string `json:"permission"`
+	Module     string `json:"module"`
+	Action     string `json:"action"`
+}
+
+// Permissions is every permission which is declared on routes by RequirePermission, other routes are checked
+// by permission of the path see microservice.RoutePermission
+var Permissions = []string{
+	PermissionShopUpdate,
+	PermissionShopDelete,
+	PermissionShopOwnerUpdate,
+	PermissionShopUserRead,
+	PermissionShopUserUpdate,
+	PermissionShopUserDelete,
+	PermissionRoleRead,
+	PermissionRoleUpdate,
+	PermissionEmployeeRead,
+	PermissionEmployeeUpdate,
+	PermissionApiKeyRead,
+	PermissionApiKeyUpdate,
+	PermissionAuditRead,
+	PermissionSecurityEventRead,
+	PermissionWebhookRead,
+	PermissionWebhookUpdate,
+	PermissionApprovalRuleRead,
+	PermissionApprovalRuleUpdate,
+	PermissionPurchaseOrderSettingUpdate,
+	PermissionSaleInvoiceRead,
+	PermissionSaleInvoiceCreate,
+	PermissionSaleInvoiceUpdate,
+	PermissionSaleInvoiceDelete,
+}
+
+// ListPermissions return declared permissions followed by permissions of routes which do not declare permission
+func ListPermissions(routePermissions []string) []string {
+	permissions := append([]string{}, Permissions...)
+
+	declared := map[string]struct{}{}
+	for _, permission := range Permissions {
+		declared[permission] = struct{}{}
+	}
+
+	for _, permission := range routePermissions {
+		if _, ok := declared[permission]; !ok {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
+
+// Permission return permission of action on module e.g. Permission("transaction.saleinvoice", ActionDelete)
+func Permission(module string, action string) string {
+	return module + ":" + action
+}
+
+// ParsePermission split permission to module and action
+func ParsePermission(permission string) (PermissionDefinition, error) {
+	if permission == PermissionAll {
+		return PermissionDefinition{Permission: permission, Module: PermissionAll, Action: PermissionAll}, nil
+	}
+
+	parts := strings.Split(permission, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return PermissionDefinition{}, fmt.Errorf("permission %q must be <module>:<action>", permission)
+	}
+
+	return PermissionDefinition{Permission: permission, Module: parts[0], Action: parts[1]}, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const roleCollectionName = "roles"
+
+// Role is named set of permissions of the shop, it is assigned to shop user and employee by code
+type Role struct {
+	Code        string          `json:"code" bson:"code" validate:"required,min=1,max=100"`
+	Names       *[]models.NameX `json:"names" bson:"names" validate:"required,min=1,unique=Code,dive"`
+	Permissions []string        `json:"permissions" bson:"permissions" validate:"required,min=1"`
+}
+
+type RoleInfo struct {
+	models.DocIdentity `bson:"inline"`
+	Role               `bson:"inline"`
+	IsDefault          bool `json:"isdefault" bson:"-"`
+}
+
+func (RoleInfo) CollectionName() string {
+	return roleCollectionName
+}
+
+type RoleData struct {
+	models.ShopIdentity `bson:"inline"`
+	RoleInfo            `bson:"inline"`
+}
+
+type RoleDoc struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RoleData           `bson:"inline"`
+	models.ActivityDoc `bson:"inline"`
+}
+
+func (RoleDoc) CollectionName() string {
+	return roleCollectionName
+}
+
+// RoleAssignRequest replace roles of shop user or employee
+type RoleAssignRequest struct {
+	Roles []string `json:"roles" validate:"required,min=1"`
+}
+
+// ShopUserRoles is roles of user in the shop, role is the role before roles are introduced
+type ShopUserRoles struct {
+	ShopID   string   `json:"shopid" bson:"shopid"`
+	Username string   `json:"username" bson:"username"`
+	Role     uint8    `json:"role" bson:"role"`
+	Roles    []string `json:"roles" bson:"roles"`
+}
+
+func (ShopUserRoles) CollectionName() string {
+	return "shopUsers"
+}
+
+// EmployeeRoles is roles of employee in the shop
+type EmployeeRoles struct {
+	ShopID string    `json:"shopid" bson:"shopid"`
+	Code   string    `json:"code" bson:"code"`
+	Roles  *[]string `json:"roles" bson:"roles"`
+}
+
+func (EmployeeRoles) CollectionName() string {
+	return "employees"
+}
+
+// UserPermission is roles and resolved permissions of user or employee
+type UserPermission struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,37 @@
+package rbac
+
+import (
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/rbac/repositories"
+	"smlaicloudplatform/internal/rbac/services"
+	"smlaicloudplatform/pkg/memorycache"
+	"smlaicloudplatform/pkg/microservice"
+	"sync"
+)
+
+var (
+	permissionsMutex sync.Mutex
+	permissions      = map[*microservice.Microservice]*services.PermissionService{}
+)
+
+// InitPermissionService return permission service shared by every module of the microservice
+// and set it as checker of ms.RequirePermission
+func InitPermissionService(ms *microservice.Microservice, cfg config.IConfig) *services.PermissionService {
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+
+	svc, ok := permissions[ms]
+	if !ok {
+		pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+		svc = services.NewPermissionService(
+			repositories.NewRoleRepository(pst),
+			repositories.NewRoleAssignRepository(pst),
+			memorycache.NewMemoryCache(),
+		)
+
+		ms.SetPermissionChecker(svc)
+		permissions[ms] = svc
+	}
+
+	return svc
+}
@@ -0,0 +1,453 @@
+package rbac
+
+import (
+	"encoding/json"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/rbac/repositories"
+	"smlaicloudplatform/internal/rbac/services"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type IRbacHttp interface{}
+
+type RbacHttp struct {
+	ms            *microservice.Microservice
+	cfg           config.IConfig
+	svc           services.IRoleHttpService
+	permissionSvc services.IPermissionService
+}
+
+func NewRbacHttp(ms *microservice.Microservice, cfg config.IConfig) RbacHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	permissionSvc := InitPermissionService(ms, cfg)
+	svc := services.NewRoleHttpService(repositories.NewRoleRepository(pst), repositories.NewRoleAssignRepository(pst), permissionSvc)
+
+	return RbacHttp{
+		ms:            ms,
+		cfg:           cfg,
+		svc:           svc,
+		permissionSvc: permissionSvc,
+	}
+}
+
+func (h RbacHttp) RegisterHttp() {
+	roleRead := h.ms.RequirePermission(models.PermissionRoleRead)
+	roleUpdate := h.ms.RequirePermission(models.PermissionRoleUpdate)
+
+	h.ms.GET("/rbac/permission", h.ListPermission, roleRead)
+	h.ms.GET("/rbac/role", h.SearchRolePage, roleRead)
+	h.ms.GET("/rbac/role/default", h.ListDefaultRole, roleRead)
+	h.ms.POST("/rbac/role", h.CreateRole, roleUpdate)
+	h.ms.GET("/rbac/role/:id", h.InfoRole, roleRead)
+	h.ms.PUT("/rbac/role/:id", h.UpdateRole, roleUpdate)
+	h.ms.DELETE("/rbac/role/:id", h.DeleteRole, roleUpdate)
+
+	h.ms.GET("/rbac/me", h.InfoMyPermission, h.ms.SkipPermission())
+	h.ms.GET("/rbac/user/:username", h.InfoUserPermission, h.ms.RequirePermission(models.PermissionShopUserRead))
+	h.ms.PUT("/rbac/user/:username", h.AssignUserRoles, h.ms.RequirePermission(models.PermissionShopUserUpdate))
+	h.ms.GET("/rbac/employee/:code", h.InfoEmployeePermission, h.ms.RequirePermission(models.PermissionEmployeeRead))
+	h.ms.PUT("/rbac/employee/:code", h.AssignEmployeeRoles, h.ms.RequirePermission(models.PermissionEmployeeUpdate))
+}
+
+// List Permission godoc
+// @Description List permissions which are enforced by routes
+// @Tags		RBAC
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/permission [get]
+func (h RbacHttp) ListPermission(ctx microservice.IContext) error {
+	docList := []models.PermissionDefinition{}
+	for _, permission := range models.ListPermissions(h.ms.RoutePermissions()) {
+		doc, _ := models.ParsePermission(permission)
+		docList = append(docList, doc)
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    docList,
+	})
+	return nil
+}
+
+// List Default Role godoc
+// @Description List default roles OWNER, ADMIN and USER which cannot be changed
+// @Tags		RBAC
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role/default [get]
+func (h RbacHttp) ListDefaultRole(ctx microservice.IContext) error {
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    models.DefaultRoles,
+	})
+	return nil
+}
+
+// Create Role godoc
+// @Description Create Role
+// @Tags		RBAC
+// @Param		Role  body      models.Role  true  "Role"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role [post]
+func (h RbacHttp) CreateRole(ctx microservice.IContext) error {
+	authUsername := ctx.UserInfo().Username
+	shopID := ctx.UserInfo().ShopID
+	input := ctx.ReadInput()
+
+	docReq := &models.Role{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	idx, err := h.svc.CreateRole(ctx.Context(), shopID, authUsername, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+	})
+	return nil
+}
+
+// Update Role godoc
+// @Description Update names and permissions of Role
+// @Tags		RBAC
+// @Param		id  path      string  true  "Role ID"
+// @Param		Role  body      models.Role  true  "Role"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role/{id} [put]
+func (h RbacHttp) UpdateRole(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	authUsername := userInfo.Username
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+	input := ctx.ReadInput()
+
+	docReq := &models.Role{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateRole(ctx.Context(), shopID, id, authUsername, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Delete Role godoc
+// @Description Delete Role which is not assigned to any user or employee
+// @Tags		RBAC
+// @Param		id  path      string  true  "Role ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role/{id} [delete]
+func (h RbacHttp) DeleteRole(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+
+	err := h.svc.DeleteRole(ctx.Context(), shopID, id, userInfo.Username)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Get Role godoc
+// @Description get Role info by guidfixed, default role by its code
+// @Tags		RBAC
+// @Param		id  path      string  true  "Role Id"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role/{id} [get]
+func (h RbacHttp) InfoRole(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoRole(ctx.Context(), shopID, id)
+
+	if err != nil && err != services.ErrRoleNotFound {
+		h.ms.Logger.Errorf("Error getting document %s: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, "document not found")
+		return err
+	}
+
+	if err == services.ErrRoleNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List Role godoc
+// @Description List Roles of the shop, default roles are listed by /rbac/role/default
+// @Tags		RBAC
+// @Param		q		query	string		false  "Search Value"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/role [get]
+func (h RbacHttp) SearchRolePage(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchRole(ctx.Context(), shopID, map[string]interface{}{}, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Get My Permission godoc
+// @Description get roles and permissions of the current user in the selected shop
+// @Tags		RBAC
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/me [get]
+func (h RbacHttp) InfoMyPermission(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	doc, err := h.permissionSvc.UserPermissions(ctx.Context(), userInfo.ShopID, userInfo.Username)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Get User Permission godoc
+// @Description get roles and permissions of shop user
+// @Tags		RBAC
+// @Param		username  path      string  true  "username"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/user/{username} [get]
+func (h RbacHttp) InfoUserPermission(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	username := utils.NormalizeUsername(ctx.Param("username"))
+
+	doc, err := h.permissionSvc.UserPermissions(ctx.Context(), shopID, username)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if len(doc.Roles) == 0 {
+		ctx.ResponseError(http.StatusNotFound, services.ErrShopUserNotFound.Error())
+		return services.ErrShopUserNotFound
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Assign User Roles godoc
+// @Description replace roles of shop user
+// @Tags		RBAC
+// @Param		username  path      string  true  "username"
+// @Param		RoleAssignRequest  body      models.RoleAssignRequest  true  "Roles"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/user/{username} [put]
+func (h RbacHttp) AssignUserRoles(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	username := ctx.Param("username")
+
+	docReq := &models.RoleAssignRequest{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.AssignUserRoles(ctx.Context(), userInfo.ShopID, userInfo.Username, username, docReq.Roles)
+
+	if err == services.ErrPermissionDenied {
+		ctx.ResponseError(http.StatusForbidden, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+	return nil
+}
+
+// Get Employee Permission godoc
+// @Description get roles and permissions of employee
+// @Tags		RBAC
+// @Param		code  path      string  true  "employee code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/employee/{code} [get]
+func (h RbacHttp) InfoEmployeePermission(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	code := ctx.Param("code")
+
+	doc, err := h.permissionSvc.EmployeePermissions(ctx.Context(), shopID, code)
+
+	if err == services.ErrEmployeeNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Assign Employee Roles godoc
+// @Description replace roles of employee
+// @Tags		RBAC
+// @Param		code  path      string  true  "employee code"
+// @Param		RoleAssignRequest  body      models.RoleAssignRequest  true  "Roles"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /rbac/employee/{code} [put]
+func (h RbacHttp) AssignEmployeeRoles(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	code := ctx.Param("code")
+
+	docReq := &models.RoleAssignRequest{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.AssignEmployeeRoles(ctx.Context(), shopID, code, docReq.Roles)
+
+	if err == services.ErrEmployeeNotFound {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+	return nil
+}
@@ -0,0 +1,50 @@
+package rbac
+
+import (
+	"context"
+	auth_model "smlaicloudplatform/internal/authentication/models"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of roles and assign default role of OWNER, ADMIN and USER role
+// to shop users which are not assigned any role
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.RoleDoc{})
+	if err != nil {
+		return err
+	}
+
+	// deletedat is in the key so code of deleted role can be used again, it is missing on every active role
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "code", Value: 1}, {Key: "deletedat", Value: 1}},
+		Options: options.Index().SetName("role_shopid_code_deletedat").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, role := range []auth_model.UserRole{auth_model.ROLE_USER, auth_model.ROLE_ADMIN, auth_model.ROLE_OWNER} {
+		err = pst.Update(
+			microservice.WithSystemAdmin(context.Background()),
+			&models.ShopUserRoles{},
+			bson.M{
+				"role":  role,
+				"roles": bson.M{"$in": bson.A{nil, bson.A{}}},
+			},
+			bson.M{"$set": bson.M{"roles": []string{models.DefaultRoleCode(role)}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IRoleAssignRepository read and replace roles of shop users and employees
+type IRoleAssignRepository interface {
+	FindShopUserRoles(ctx context.Context, shopID string, username string) (models.ShopUserRoles, error)
+	SaveShopUserRoles(ctx context.Context, shopID string, username string, roles []string) error
+	FindEmployeeRoles(ctx context.Context, shopID string, code string) (models.EmployeeRoles, error)
+	SaveEmployeeRoles(ctx context.Context, shopID string, code string, roles []string) error
+	CountRoleInUse(ctx context.Context, shopID string, code string) (int, error)
+}
+
+type RoleAssignRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewRoleAssignRepository(pst microservice.IPersisterMongo) *RoleAssignRepository {
+	return &RoleAssignRepository{
+		pst: pst,
+	}
+}
+
+func (repo RoleAssignRepository) FindShopUserRoles(ctx context.Context, shopID string, username string) (models.ShopUserRoles, error) {
+	doc := models.ShopUserRoles{}
+	err := repo.pst.FindOne(ctx, &models.ShopUserRoles{}, bson.M{"shopid": shopID, "username": username}, &doc)
+	if err != nil {
+		return models.ShopUserRoles{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo RoleAssignRepository) SaveShopUserRoles(ctx context.Context, shopID string, username string, roles []string) error {
+	return repo.pst.Update(ctx, &models.ShopUserRoles{}, bson.M{"shopid": shopID, "username": username}, bson.M{
+		"$set": bson.M{"roles": roles},
+	})
+}
+
+func (repo RoleAssignRepository) FindEmployeeRoles(ctx context.Context, shopID string, code string) (models.EmployeeRoles, error) {
+	doc := models.EmployeeRoles{}
+	err := repo.pst.FindOne(ctx, &models.EmployeeRoles{}, bson.M{
+		"shopid":    shopID,
+		"code":      code,
+		"deletedat": bson.M{"$exists": false},
+	}, &doc)
+	if err != nil {
+		return models.EmployeeRoles{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo RoleAssignRepository) SaveEmployeeRoles(ctx context.Context, shopID string, code string, roles []string) error {
+	return repo.pst.Update(ctx, &models.EmployeeRoles{}, bson.M{
+		"shopid":    shopID,
+		"code":      code,
+		"deletedat": bson.M{"$exists": false},
+	}, bson.M{
+		"$set": bson.M{"roles": roles},
+	})
+}
+
+// CountRoleInUse return number of shop users and employees which have the role
+func (repo RoleAssignRepository) CountRoleInUse(ctx context.Context, shopID string, code string) (int, error) {
+	userCount, err := repo.pst.Count(ctx, &models.ShopUserRoles{}, bson.M{"shopid": shopID, "roles": code})
+	if err != nil {
+		return 0, err
+	}
+
+	employeeCount, err := repo.pst.Count(ctx, &models.EmployeeRoles{}, bson.M{
+		"shopid":    shopID,
+		"roles":     code,
+		"deletedat": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return userCount + employeeCount, nil
+}
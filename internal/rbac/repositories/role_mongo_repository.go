@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IRoleRepository interface {
+	Create(ctx context.Context, doc models.RoleDoc) (string, error)
+	Update(ctx context.Context, shopID string, guid string, doc models.RoleDoc) error
+	DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.RoleDoc, error)
+	FindByDocIndentityGuid(ctx context.Context, shopID string, indentityField string, indentityValue interface{}) (models.RoleDoc, error)
+	FindByCodes(ctx context.Context, shopID string, codes []string) ([]models.RoleDoc, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.RoleInfo, mongopagination.PaginationData, error)
+}
+
+type RoleRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.CrudRepository[models.RoleDoc]
+	repositories.SearchRepository[models.RoleInfo]
+}
+
+func NewRoleRepository(pst microservice.IPersisterMongo) *RoleRepository {
+
+	insRepo := &RoleRepository{
+		pst: pst,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.RoleDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.RoleInfo](pst)
+
+	return insRepo
+}
+
+func (repo RoleRepository) FindByCodes(ctx context.Context, shopID string, codes []string) ([]models.RoleDoc, error) {
+	return repo.FindFilter(ctx, shopID, map[string]interface{}{
+		"code": bson.M{"$in": codes},
+	})
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/rbac/repositories"
+	"smlaicloudplatform/pkg/memorycache"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+type IPermissionService interface {
+	microservice.IPermissionChecker
+	UserPermissions(ctx context.Context, shopID string, username string) (models.UserPermission, error)
+	EmployeePermissions(ctx context.Context, shopID string, code string) (models.UserPermission, error)
+	RolePermissions(ctx context.Context, shopID string, roles []string) ([]string, error)
+	InvalidateShop(shopID string)
+}
+
+// PermissionService resolve permissions of shop user from roles, resolved permissions are cached
+// in this replica for cache expire so role change on other replica take effect within it
+type PermissionService struct {
+	roleRepo       repositories.IRoleRepository
+	assignRepo     repositories.IRoleAssignRepository
+	cache          memorycache.IMemoryCache
+	cacheExpire    time.Duration
+	contextTimeout time.Duration
+
+	mutex       sync.Mutex
+	generations map[string]int
+}
+
+func NewPermissionService(roleRepo repositories.IRoleRepository, assignRepo repositories.IRoleAssignRepository, cache memorycache.IMemoryCache) *PermissionService {
+	return &PermissionService{
+		roleRepo:       roleRepo,
+		assignRepo:     assignRepo,
+		cache:          cache,
+		cacheExpire:    30 * time.Second,
+		contextTimeout: 15 * time.Second,
+		generations:    map[string]int{},
+	}
+}
+
+func (svc *PermissionService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc *PermissionService) HasPermission(ctx context.Context, userInfo micromodels.UserInfo, permission string) (bool, error) {
+	userPermission, err := svc.cachedUserPermissions(ctx, userInfo.ShopID, userInfo.Username)
+	if err != nil {
+		return false, err
+	}
+
+	for _, granted := range userPermission.Permissions {
+		if microservice.MatchPermission(granted, permission) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (svc *PermissionService) cachedUserPermissions(ctx context.Context, shopID string, username string) (models.UserPermission, error) {
+	cacheKey := svc.cacheKey(shopID, username)
+	if cached, ok := svc.cache.Get(cacheKey); ok {
+		return cached.(models.UserPermission), nil
+	}
+
+	userPermission, err := svc.UserPermissions(ctx, shopID, username)
+	if err != nil {
+		return models.UserPermission{}, err
+	}
+
+	svc.cache.Set(cacheKey, userPermission, svc.cacheExpire)
+	return userPermission, nil
+}
+
+// UserPermissions return roles and permissions of shop user, user who is not assigned any role
+// has default role of the role of the user
+func (svc *PermissionService) UserPermissions(ctx context.Context, shopID string, username string) (models.UserPermission, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	shopUser, err := svc.assignRepo.FindShopUserRoles(ctx, shopID, username)
+	if err != nil {
+		return models.UserPermission{}, err
+	}
+
+	if shopUser.Username == "" {
+		return models.UserPermission{Roles: []string{}, Permissions: []string{}}, nil
+	}
+
+	roles := shopUser.Roles
+	if len(roles) == 0 {
+		roles = []string{models.DefaultRoleCode(shopUser.Role)}
+	}
+
+	permissions, err := svc.RolePermissions(ctx, shopID, roles)
+	if err != nil {
+		return models.UserPermission{}, err
+	}
+
+	return models.UserPermission{Roles: roles, Permissions: permissions}, nil
+}
+
+func (svc *PermissionService) EmployeePermissions(ctx context.Context, shopID string, code string) (models.UserPermission, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	employee, err := svc.assignRepo.FindEmployeeRoles(ctx, shopID, code)
+	if err != nil {
+		return models.UserPermission{}, err
+	}
+
+	if employee.Code == "" {
+		return models.UserPermission{}, ErrEmployeeNotFound
+	}
+
+	roles := []string{}
+	if employee.Roles != nil {
+		roles = *employee.Roles
+	}
+
+	permissions, err := svc.RolePermissions(ctx, shopID, roles)
+	if err != nil {
+		return models.UserPermission{}, err
+	}
+
+	return models.UserPermission{Roles: roles, Permissions: permissions}, nil
+}
+
+// RolePermissions return union of permissions of the roles, unknown role is ignored
+func (svc *PermissionService) RolePermissions(ctx context.Context, shopID string, roles []string) ([]string, error) {
+	found := map[string]struct{}{}
+	shopRoles := []string{}
+
+	for _, code := range roles {
+		role, ok := models.FindDefaultRole(code)
+		if !ok {
+			shopRoles = append(shopRoles, code)
+			continue
+		}
+
+		for _, permission := range role.Permissions {
+			found[permission] = struct{}{}
+		}
+	}
+
+	if len(shopRoles) > 0 {
+		ctx, ctxCancel := svc.getContextTimeout(ctx)
+		defer ctxCancel()
+
+		docList, err := svc.roleRepo.FindByCodes(ctx, shopID, shopRoles)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range docList {
+			for _, permission := range doc.Permissions {
+				found[permission] = struct{}{}
+			}
+		}
+	}
+
+	permissions := make([]string, 0, len(found))
+	for permission := range found {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	return permissions, nil
+}
+
+// InvalidateShop drop cached permissions of the shop in this replica
+func (svc *PermissionService) InvalidateShop(shopID string) {
+	svc.mutex.Lock()
+	defer svc.mutex.Unlock()
+
+	svc.generations[shopID]++
+}
+
+func (svc *PermissionService) cacheKey(shopID string, username string) string {
+	svc.mutex.Lock()
+	defer svc.mutex.Unlock()
+
+	return fmt.Sprintf("rbac:%s:%d:%s", shopID, svc.generations[shopID], username)
+}
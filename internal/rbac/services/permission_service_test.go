@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	auth_model "smlaicloudplatform/internal/authentication/models"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/rbac/repositories"
+	"smlaicloudplatform/pkg/memorycache"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRoleRepository struct {
+	repositories.IRoleRepository
+	roles []models.RoleDoc
+}
+
+func (repo *memoryRoleRepository) FindByCodes(ctx context.Context, shopID string, codes []string) ([]models.RoleDoc, error) {
+	docList := []models.RoleDoc{}
+	for _, doc := range repo.roles {
+		if doc.ShopID == shopID && hasRole(codes, doc.Code) {
+			docList = append(docList, doc)
+		}
+	}
+	return docList, nil
+}
+
+type memoryRoleAssignRepository struct {
+	repositories.IRoleAssignRepository
+	users []models.ShopUserRoles
+}
+
+func (repo *memoryRoleAssignRepository) FindShopUserRoles(ctx context.Context, shopID string, username string) (models.ShopUserRoles, error) {
+	for _, user := range repo.users {
+		if user.ShopID == shopID && user.Username == username {
+			return user, nil
+		}
+	}
+	return models.ShopUserRoles{}, nil
+}
+
+func newRoleDoc(shopID string, code string, permissions ...string) models.RoleDoc {
+	doc := models.RoleDoc{}
+	doc.ShopID = shopID
+	doc.Code = code
+	doc.Permissions = permissions
+	return doc
+}
+
+func TestPermissionServiceHasPermission(t *testing.T) {
+	roleRepo := &memoryRoleRepository{roles: []models.RoleDoc{
+		newRoleDoc("SHOP01", "CASHIER", models.PermissionSaleInvoiceRead, models.PermissionSaleInvoiceCreate),
+	}}
+	assignRepo := &memoryRoleAssignRepository{users: []models.ShopUserRoles{
+		{ShopID: "SHOP01", Username: "owner", Role: uint8(auth_model.ROLE_OWNER)},
+		{ShopID: "SHOP01", Username: "admin", Role: uint8(auth_model.ROLE_ADMIN), Roles: []string{models.RoleAdmin}},
+		{ShopID: "SHOP01", Username: "cashier", Role: uint8(auth_model.ROLE_USER), Roles: []string{"CASHIER"}},
+		{ShopID: "SHOP01", Username: "user", Role: uint8(auth_model.ROLE_USER)},
+	}}
+
+	svc := NewPermissionService(roleRepo, assignRepo, memorycache.NewMemoryCache())
+
+	has := func(username string, permission string) bool {
+		allowed, err := svc.HasPermission(context.Background(), micromodels.UserInfo{ShopID: "SHOP01", Username: username}, permission)
+		assert.Nil(t, err)
+		return allowed
+	}
+
+	assert.True(t, has("owner", models.PermissionShopDelete), "user without roles has default role of the role")
+	assert.True(t, has("admin", models.PermissionSaleInvoiceDelete))
+	assert.False(t, has("admin", models.PermissionShopDelete))
+	assert.False(t, has("admin", models.PermissionShopOwnerUpdate))
+	assert.True(t, has("cashier", models.PermissionSaleInvoiceCreate))
+	assert.False(t, has("cashier", models.PermissionSaleInvoiceDelete))
+	assert.False(t, has("other", models.PermissionSaleInvoiceRead), "user who is not member of the shop")
+
+	// default user role has permissions of routes of data of the shop but not administration of the shop
+	assert.True(t, has("user", microservice.RoutePermission(http.MethodPost, "/product/barcode")))
+	assert.True(t, has("user", microservice.RoutePermission(http.MethodGet, "/shop/:id")))
+	assert.False(t, has("user", microservice.RoutePermission(http.MethodPost, "/shop/employee")))
+	assert.False(t, has("user", models.PermissionShopUserUpdate))
+	assert.True(t, has("admin", microservice.RoutePermission(http.MethodPost, "/shop/employee")))
+
+	// cached permissions are dropped when roles of the shop change
+	roleRepo.roles[0].Permissions = append(roleRepo.roles[0].Permissions, models.PermissionSaleInvoiceDelete)
+	assert.False(t, has("cashier", models.PermissionSaleInvoiceDelete))
+
+	svc.InvalidateShop("SHOP01")
+	assert.True(t, has("cashier", models.PermissionSaleInvoiceDelete))
+}
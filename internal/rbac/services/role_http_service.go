@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/rbac/repositories"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrRoleNotFound     = errors.New("role not found")
+	ErrRoleIsDefault    = errors.New("default role cannot be changed")
+	ErrRoleInUse        = errors.New("role is assigned to users or employees")
+	ErrEmployeeNotFound = errors.New("employee not found")
+	ErrShopUserNotFound = errors.New("user is not member of the shop")
+	ErrEditSelfRole     = errors.New("can not edit self permission")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+type IRoleHttpService interface {
+	CreateRole(ctx context.Context, shopID string, authUsername string, doc models.Role) (string, error)
+	UpdateRole(ctx context.Context, shopID string, guid string, authUsername string, doc models.Role) error
+	DeleteRole(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoRole(ctx context.Context, shopID string, guid string) (models.RoleInfo, error)
+	SearchRole(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.RoleInfo, mongopagination.PaginationData, error)
+
+	AssignUserRoles(ctx context.Context, shopID string, authUsername string, username string, roles []string) error
+	AssignEmployeeRoles(ctx context.Context, shopID string, code string, roles []string) error
+}
+
+type RoleHttpService struct {
+	repo           repositories.IRoleRepository
+	assignRepo     repositories.IRoleAssignRepository
+	permissionSvc  IPermissionService
+	contextTimeout time.Duration
+}
+
+func NewRoleHttpService(repo repositories.IRoleRepository, assignRepo repositories.IRoleAssignRepository, permissionSvc IPermissionService) *RoleHttpService {
+	return &RoleHttpService{
+		repo:           repo,
+		assignRepo:     assignRepo,
+		permissionSvc:  permissionSvc,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc RoleHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc RoleHttpService) CreateRole(ctx context.Context, shopID string, authUsername string, doc models.Role) (string, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if _, ok := models.FindDefaultRole(doc.Code); ok {
+		return "", ErrRoleIsDefault
+	}
+
+	err := validatePermissions(doc.Permissions)
+	if err != nil {
+		return "", err
+	}
+
+	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(findDoc.GuidFixed) > 0 {
+		return "", errors.New("Code is exists")
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	docData := models.RoleDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.Role = doc
+
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = time.Now()
+
+	_, err = svc.repo.Create(ctx, docData)
+
+	if mongo.IsDuplicateKeyError(err) {
+		// role of the code is created by other request after it is checked
+		return "", errors.New("Code is exists")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return newGuidFixed, nil
+}
+
+// UpdateRole change names and permissions of the role, code cannot be changed because it is assigned by code
+func (svc RoleHttpService) UpdateRole(ctx context.Context, shopID string, guid string, authUsername string, doc models.Role) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := validatePermissions(doc.Permissions)
+	if err != nil {
+		return err
+	}
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrRoleNotFound
+	}
+
+	findDoc.Names = doc.Names
+	findDoc.Permissions = doc.Permissions
+
+	findDoc.UpdatedBy = authUsername
+	findDoc.UpdatedAt = time.Now()
+
+	err = svc.repo.Update(ctx, shopID, guid, findDoc)
+
+	if err != nil {
+		return err
+	}
+
+	svc.permissionSvc.InvalidateShop(shopID)
+
+	return nil
+}
+
+func (svc RoleHttpService) DeleteRole(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrRoleNotFound
+	}
+
+	inUse, err := svc.assignRepo.CountRoleInUse(ctx, shopID, findDoc.Code)
+	if err != nil {
+		return err
+	}
+
+	if inUse > 0 {
+		return ErrRoleInUse
+	}
+
+	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	if err != nil {
+		return err
+	}
+
+	svc.permissionSvc.InvalidateShop(shopID)
+
+	return nil
+}
+
+// InfoRole return role by guid, default role is found by its code
+func (svc RoleHttpService) InfoRole(ctx context.Context, shopID string, guid string) (models.RoleInfo, error) {
+
+	if role, ok := models.FindDefaultRole(guid); ok {
+		return role, nil
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.RoleInfo{}, err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return models.RoleInfo{}, ErrRoleNotFound
+	}
+
+	return findDoc.RoleInfo, nil
+}
+
+func (svc RoleHttpService) SearchRole(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.RoleInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"code",
+		"names.name",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.RoleInfo{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+// AssignUserRoles replace roles of shop user, only user who can change owner may assign or remove OWNER role
+func (svc RoleHttpService) AssignUserRoles(ctx context.Context, shopID string, authUsername string, username string, roles []string) error {
+
+	username = utils.NormalizeUsername(username)
+
+	if username == authUsername {
+		return ErrEditSelfRole
+	}
+
+	err := svc.validateRoles(ctx, shopID, roles)
+	if err != nil {
+		return err
+	}
+
+	current, err := svc.permissionSvc.UserPermissions(ctx, shopID, username)
+	if err != nil {
+		return err
+	}
+
+	if len(current.Roles) == 0 {
+		return ErrShopUserNotFound
+	}
+
+	if hasRole(current.Roles, models.RoleOwner) || hasRole(roles, models.RoleOwner) {
+		allowed, err := svc.permissionSvc.HasPermission(ctx, micromodels.UserInfo{ShopID: shopID, Username: authUsername}, models.PermissionShopOwnerUpdate)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return ErrPermissionDenied
+		}
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err = svc.assignRepo.SaveShopUserRoles(ctx, shopID, username, roles)
+	if err != nil {
+		return err
+	}
+
+	svc.permissionSvc.InvalidateShop(shopID)
+
+	return nil
+}
+
+func (svc RoleHttpService) AssignEmployeeRoles(ctx context.Context, shopID string, code string, roles []string) error {
+
+	err := svc.validateRoles(ctx, shopID, roles)
+	if err != nil {
+		return err
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	employee, err := svc.assignRepo.FindEmployeeRoles(ctx, shopID, code)
+	if err != nil {
+		return err
+	}
+
+	if employee.Code == "" {
+		return ErrEmployeeNotFound
+	}
+
+	return svc.assignRepo.SaveEmployeeRoles(ctx, shopID, code, roles)
+}
+
+// validateRoles check that every role is default role or role of the shop
+func (svc RoleHttpService) validateRoles(ctx context.Context, shopID string, roles []string) error {
+	shopRoles := []string{}
+	for _, code := range roles {
+		if _, ok := models.FindDefaultRole(code); !ok {
+			shopRoles = append(shopRoles, code)
+		}
+	}
+
+	if len(shopRoles) == 0 {
+		return nil
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	docList, err := svc.repo.FindByCodes(ctx, shopID, shopRoles)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range shopRoles {
+		found := false
+		for _, doc := range docList {
+			if doc.Code == code {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("role %s not found", code)
+		}
+	}
+
+	return nil
+}
+
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		_, err := models.ParsePermission(permission)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasRole(roles []string, code string) bool {
+	for _, role := range roles {
+		if role == code {
+			return true
+		}
+	}
+	return false
+}
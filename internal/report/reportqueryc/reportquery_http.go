@@ -119,7 +119,7 @@ func (h ReportQueryHttp) ExecuteReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	result, pagination, err := h.svc.ExecuteReportQuery(shopID, reportCode, *reqBody, pageable)
+	result, pagination, err := h.svc.ExecuteReportQuery(ctx.Context(), shopID, reportCode, *reqBody, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -161,7 +161,7 @@ func (h ReportQueryHttp) CreateReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateReportQuery(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateReportQuery(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -206,7 +206,7 @@ func (h ReportQueryHttp) UpdateReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateReportQuery(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateReportQuery(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -237,7 +237,7 @@ func (h ReportQueryHttp) DeleteReportQuery(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteReportQuery(shopID, id, authUsername)
+	err := h.svc.DeleteReportQuery(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -276,7 +276,7 @@ func (h ReportQueryHttp) DeleteReportQueryByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteReportQueryByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteReportQueryByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -306,7 +306,7 @@ func (h ReportQueryHttp) InfoReportQuery(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ReportQuery %v", id)
-	doc, err := h.svc.InfoReportQuery(shopID, id)
+	doc, err := h.svc.InfoReportQuery(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -336,7 +336,7 @@ func (h ReportQueryHttp) InfoReportQueryByCode(ctx microservice.IContext) error
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoReportQueryByCode(shopID, code)
+	doc, err := h.svc.InfoReportQueryByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -367,7 +367,7 @@ func (h ReportQueryHttp) SearchReportQueryPage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchReportQuery(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchReportQuery(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -402,7 +402,7 @@ func (h ReportQueryHttp) SearchReportQueryStep(ctx microservice.IContext) error
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchReportQueryStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchReportQueryStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/report/reportqueryc/models"
 	"smlaicloudplatform/internal/report/reportqueryc/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,16 +18,16 @@ import (
 
 type IReportQueryHttpService interface {
 	PlaygroundReportQuery(shopID string, queryParam models.Query) ([]map[string]interface{}, error)
-	ExecuteReportQuery(shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error)
+	ExecuteReportQuery(ctx context.Context, shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error)
 
-	CreateReportQuery(shopID string, authUsername string, doc models.ReportQuery) (string, error)
-	UpdateReportQuery(shopID string, guid string, authUsername string, doc models.ReportQuery) error
-	DeleteReportQuery(shopID string, guid string, authUsername string) error
-	DeleteReportQueryByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoReportQuery(shopID string, guid string) (models.ReportQueryInfo, error)
-	InfoReportQueryByCode(shopID string, code string) (models.ReportQueryInfo, error)
-	SearchReportQuery(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error)
-	SearchReportQueryStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error)
+	CreateReportQuery(ctx context.Context, shopID string, authUsername string, doc models.ReportQuery) (string, error)
+	UpdateReportQuery(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReportQuery) error
+	DeleteReportQuery(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteReportQueryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoReportQuery(ctx context.Context, shopID string, guid string) (models.ReportQueryInfo, error)
+	InfoReportQueryByCode(ctx context.Context, shopID string, code string) (models.ReportQueryInfo, error)
+	SearchReportQuery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error)
+	SearchReportQueryStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error)
 
 	GetModuleName() string
 }
@@ -50,13 +51,13 @@ func NewReportQueryHttpService(repo repositories.IReportQueryRepository, repoCli
 	return insSvc
 }
 
-func (svc ReportQueryHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ReportQueryHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ReportQueryHttpService) CreateReportQuery(shopID string, authUsername string, doc models.ReportQuery) (string, error) {
+func (svc ReportQueryHttpService) CreateReportQuery(ctx context.Context, shopID string, authUsername string, doc models.ReportQuery) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -91,9 +92,9 @@ func (svc ReportQueryHttpService) CreateReportQuery(shopID string, authUsername
 	return newGuidFixed, nil
 }
 
-func (svc ReportQueryHttpService) UpdateReportQuery(shopID string, guid string, authUsername string, doc models.ReportQuery) error {
+func (svc ReportQueryHttpService) UpdateReportQuery(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReportQuery) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -125,9 +126,9 @@ func (svc ReportQueryHttpService) UpdateReportQuery(shopID string, guid string,
 	return nil
 }
 
-func (svc ReportQueryHttpService) DeleteReportQuery(shopID string, guid string, authUsername string) error {
+func (svc ReportQueryHttpService) DeleteReportQuery(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -148,9 +149,9 @@ func (svc ReportQueryHttpService) DeleteReportQuery(shopID string, guid string,
 	return nil
 }
 
-func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -165,9 +166,9 @@ func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(shopID string, authUs
 	return nil
 }
 
-func (svc ReportQueryHttpService) InfoReportQuery(shopID string, guid string) (models.ReportQueryInfo, error) {
+func (svc ReportQueryHttpService) InfoReportQuery(ctx context.Context, shopID string, guid string) (models.ReportQueryInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -183,9 +184,9 @@ func (svc ReportQueryHttpService) InfoReportQuery(shopID string, guid string) (m
 	return findDoc.ReportQueryInfo, nil
 }
 
-func (svc ReportQueryHttpService) InfoReportQueryByCode(shopID string, code string) (models.ReportQueryInfo, error) {
+func (svc ReportQueryHttpService) InfoReportQueryByCode(ctx context.Context, shopID string, code string) (models.ReportQueryInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -201,9 +202,9 @@ func (svc ReportQueryHttpService) InfoReportQueryByCode(shopID string, code stri
 	return findDoc.ReportQueryInfo, nil
 }
 
-func (svc ReportQueryHttpService) SearchReportQuery(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error) {
+func (svc ReportQueryHttpService) SearchReportQuery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -219,9 +220,9 @@ func (svc ReportQueryHttpService) SearchReportQuery(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc ReportQueryHttpService) SearchReportQueryStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error) {
+func (svc ReportQueryHttpService) SearchReportQueryStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -255,9 +256,9 @@ func (svc ReportQueryHttpService) PlaygroundReportQuery(shopID string, queryPara
 	return result, nil
 }
 
-func (svc ReportQueryHttpService) ExecuteReportQuery(shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error) {
+func (svc ReportQueryHttpService) ExecuteReportQuery(ctx context.Context, shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneByCode(ctx, reportCode)
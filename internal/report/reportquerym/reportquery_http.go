@@ -74,7 +74,7 @@ func (h ReportQueryHttp) PlaygroundReportQuery(ctx microservice.IContext) error
 		return err
 	}
 
-	result, err := h.svc.PlaygroundReportQuery(shopID, *reqBody)
+	result, err := h.svc.PlaygroundReportQuery(ctx.Context(), shopID, *reqBody)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -117,7 +117,7 @@ func (h ReportQueryHttp) ExecuteReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	result, pagination, err := h.svc.ExecuteReportQuery(shopID, reportCode, *reqBody, pageable)
+	result, pagination, err := h.svc.ExecuteReportQuery(ctx.Context(), shopID, reportCode, *reqBody, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -159,7 +159,7 @@ func (h ReportQueryHttp) CreateReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateReportQuery(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateReportQuery(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -204,7 +204,7 @@ func (h ReportQueryHttp) UpdateReportQuery(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateReportQuery(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateReportQuery(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -235,7 +235,7 @@ func (h ReportQueryHttp) DeleteReportQuery(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteReportQuery(shopID, id, authUsername)
+	err := h.svc.DeleteReportQuery(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -274,7 +274,7 @@ func (h ReportQueryHttp) DeleteReportQueryByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteReportQueryByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteReportQueryByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -304,7 +304,7 @@ func (h ReportQueryHttp) InfoReportQuery(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ReportQuery %v", id)
-	doc, err := h.svc.InfoReportQuery(shopID, id)
+	doc, err := h.svc.InfoReportQuery(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -334,7 +334,7 @@ func (h ReportQueryHttp) InfoReportQueryByCode(ctx microservice.IContext) error
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoReportQueryByCode(shopID, code)
+	doc, err := h.svc.InfoReportQueryByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -365,7 +365,7 @@ func (h ReportQueryHttp) SearchReportQueryPage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchReportQuery(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchReportQuery(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -400,7 +400,7 @@ func (h ReportQueryHttp) SearchReportQueryStep(ctx microservice.IContext) error
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchReportQueryStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchReportQueryStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/report/reportquerym/models"
 	"smlaicloudplatform/internal/report/reportquerym/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -16,17 +17,17 @@ import (
 )
 
 type IReportQueryHttpService interface {
-	PlaygroundReportQuery(shopID string, query models.Query) ([]map[string]interface{}, error)
-	ExecuteReportQuery(shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error)
-
-	CreateReportQuery(shopID string, authUsername string, doc models.ReportQuery) (string, error)
-	UpdateReportQuery(shopID string, guid string, authUsername string, doc models.ReportQuery) error
-	DeleteReportQuery(shopID string, guid string, authUsername string) error
-	DeleteReportQueryByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoReportQuery(shopID string, guid string) (models.ReportQueryInfo, error)
-	InfoReportQueryByCode(shopID string, code string) (models.ReportQueryInfo, error)
-	SearchReportQuery(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error)
-	SearchReportQueryStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error)
+	PlaygroundReportQuery(ctx context.Context, shopID string, query models.Query) ([]map[string]interface{}, error)
+	ExecuteReportQuery(ctx context.Context, shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error)
+
+	CreateReportQuery(ctx context.Context, shopID string, authUsername string, doc models.ReportQuery) (string, error)
+	UpdateReportQuery(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReportQuery) error
+	DeleteReportQuery(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteReportQueryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoReportQuery(ctx context.Context, shopID string, guid string) (models.ReportQueryInfo, error)
+	InfoReportQueryByCode(ctx context.Context, shopID string, code string) (models.ReportQueryInfo, error)
+	SearchReportQuery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error)
+	SearchReportQueryStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error)
 
 	GetModuleName() string
 }
@@ -48,13 +49,13 @@ func NewReportQueryHttpService(repo repositories.IReportQueryRepository) *Report
 	return insSvc
 }
 
-func (svc ReportQueryHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ReportQueryHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ReportQueryHttpService) CreateReportQuery(shopID string, authUsername string, doc models.ReportQuery) (string, error) {
+func (svc ReportQueryHttpService) CreateReportQuery(ctx context.Context, shopID string, authUsername string, doc models.ReportQuery) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -89,9 +90,9 @@ func (svc ReportQueryHttpService) CreateReportQuery(shopID string, authUsername
 	return newGuidFixed, nil
 }
 
-func (svc ReportQueryHttpService) UpdateReportQuery(shopID string, guid string, authUsername string, doc models.ReportQuery) error {
+func (svc ReportQueryHttpService) UpdateReportQuery(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReportQuery) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -123,9 +124,9 @@ func (svc ReportQueryHttpService) UpdateReportQuery(shopID string, guid string,
 	return nil
 }
 
-func (svc ReportQueryHttpService) DeleteReportQuery(shopID string, guid string, authUsername string) error {
+func (svc ReportQueryHttpService) DeleteReportQuery(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -146,9 +147,9 @@ func (svc ReportQueryHttpService) DeleteReportQuery(shopID string, guid string,
 	return nil
 }
 
-func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -163,9 +164,9 @@ func (svc ReportQueryHttpService) DeleteReportQueryByGUIDs(shopID string, authUs
 	return nil
 }
 
-func (svc ReportQueryHttpService) InfoReportQuery(shopID string, guid string) (models.ReportQueryInfo, error) {
+func (svc ReportQueryHttpService) InfoReportQuery(ctx context.Context, shopID string, guid string) (models.ReportQueryInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -181,9 +182,9 @@ func (svc ReportQueryHttpService) InfoReportQuery(shopID string, guid string) (m
 	return findDoc.ReportQueryInfo, nil
 }
 
-func (svc ReportQueryHttpService) InfoReportQueryByCode(shopID string, code string) (models.ReportQueryInfo, error) {
+func (svc ReportQueryHttpService) InfoReportQueryByCode(ctx context.Context, shopID string, code string) (models.ReportQueryInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -199,9 +200,9 @@ func (svc ReportQueryHttpService) InfoReportQueryByCode(shopID string, code stri
 	return findDoc.ReportQueryInfo, nil
 }
 
-func (svc ReportQueryHttpService) SearchReportQuery(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error) {
+func (svc ReportQueryHttpService) SearchReportQuery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReportQueryInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -217,9 +218,9 @@ func (svc ReportQueryHttpService) SearchReportQuery(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc ReportQueryHttpService) SearchReportQueryStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error) {
+func (svc ReportQueryHttpService) SearchReportQueryStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.ReportQueryInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -243,9 +244,9 @@ func (svc ReportQueryHttpService) SearchReportQueryStep(shopID string, langCode
 	return docList, total, nil
 }
 
-func (svc ReportQueryHttpService) PlaygroundReportQuery(shopID string, query models.Query) ([]map[string]interface{}, error) {
+func (svc ReportQueryHttpService) PlaygroundReportQuery(ctx context.Context, shopID string, query models.Query) ([]map[string]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	selectFields := bson.M{}
@@ -290,9 +291,9 @@ func (svc ReportQueryHttpService) PlaygroundReportQuery(shopID string, query mod
 	return result, nil
 }
 
-func (svc ReportQueryHttpService) ExecuteReportQuery(shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error) {
+func (svc ReportQueryHttpService) ExecuteReportQuery(ctx context.Context, shopID string, reportCode string, queryParamRequest []models.QueryParamRequest, pageable micromodels.Pageable) ([]map[string]interface{}, common.Pagination, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOneByCode(ctx, reportCode)
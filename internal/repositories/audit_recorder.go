@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	auditmodels "smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AuditRecorder append audit log of mutations which go through CrudRepository,
+// failure to write audit log is logged and does not fail the mutation which is already done.
+// Secret fields of the model are redacted in snapshots and changes
+type AuditRecorder struct {
+	pst         microservice.IPersisterMongo
+	collection  string
+	secretPaths []string
+	timeNow     func() time.Time
+}
+
+func NewAuditRecorder(pst microservice.IPersisterMongo, model interface{}) AuditRecorder {
+	collection := ""
+	if mongoModel, ok := model.(microservice.MongoModel); ok {
+		collection = mongoModel.CollectionName()
+	}
+
+	return AuditRecorder{
+		pst:         pst,
+		collection:  collection,
+		secretPaths: auditmodels.SecretPaths(model),
+		timeNow:     time.Now,
+	}
+}
+
+// Enabled return false when the model is not a mongo model, nothing is recorded for it
+func (r AuditRecorder) Enabled() bool {
+	return r.collection != "" && r.pst != nil
+}
+
+// RecordCreate record created documents, actor is createdby of the document
+func (r AuditRecorder) RecordCreate(ctx context.Context, docList ...interface{}) {
+	logs := []interface{}{}
+	for _, doc := range docList {
+		after, err := auditmodels.ToSnapshot(doc)
+		if err != nil {
+			r.logError(err)
+			continue
+		}
+
+		logs = append(logs, r.newAuditLog(ctx, auditmodels.AuditActionCreate, nil, after, stringField(after, "createdby")))
+	}
+
+	r.write(ctx, logs)
+}
+
+// RecordUpdate record document which is set by doc, after is before with fields of doc
+func (r AuditRecorder) RecordUpdate(ctx context.Context, before bson.M, doc interface{}) {
+	fields, err := auditmodels.ToSnapshot(doc)
+	if err != nil {
+		r.logError(err)
+		return
+	}
+
+	after := bson.M{}
+	for key, value := range before {
+		after[key] = value
+	}
+	for key, value := range fields {
+		after[key] = value
+	}
+
+	r.write(ctx, []interface{}{r.newAuditLog(ctx, auditmodels.AuditActionUpdate, before, after, stringField(fields, "updatedby"))})
+}
+
+// RecordDelete record documents which are deleted by username
+func (r AuditRecorder) RecordDelete(ctx context.Context, beforeList []bson.M, username string) {
+	logs := []interface{}{}
+	for _, before := range beforeList {
+		logs = append(logs, r.newAuditLog(ctx, auditmodels.AuditActionDelete, before, nil, username))
+	}
+
+	r.write(ctx, logs)
+}
+
+func (r AuditRecorder) newAuditLog(ctx context.Context, action string, before bson.M, after bson.M, username string) auditmodels.AuditLogDoc {
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+
+	doc := auditmodels.AuditLogDoc{}
+	doc.ShopID = stringField(snapshot, "shopid")
+	doc.Collection = r.collection
+	doc.GuidFixed = stringField(snapshot, "guidfixed")
+	doc.Action = action
+	doc.Before = before
+	doc.After = after
+	doc.Changes = auditmodels.DiffSnapshot(before, after)
+
+	auditmodels.RedactChanges(doc.Changes, r.secretPaths)
+	auditmodels.RedactSnapshot(before, r.secretPaths)
+	auditmodels.RedactSnapshot(after, r.secretPaths)
+	doc.Actor = username
+	doc.CreatedAt = r.timeNow()
+
+	actor, ok := microservice.RequestActorFromContext(ctx)
+	if ok {
+		if actor.Username != "" {
+			doc.Actor = actor.Username
+		}
+		doc.IP = actor.IP
+		doc.AuthType = actor.AuthType
+		doc.ApiKeyID = actor.ApiKeyID
+	}
+
+	return doc
+}
+
+func (r AuditRecorder) write(ctx context.Context, logs []interface{}) {
+	if !r.Enabled() || len(logs) == 0 {
+		return
+	}
+
+	err := r.pst.CreateInBatch(ctx, &auditmodels.AuditLogDoc{}, logs)
+	if err != nil {
+		r.logError(fmt.Errorf("write audit logs: %w", err))
+	}
+}
+
+func (r AuditRecorder) logError(err error) {
+	logger.GetLogger().Errorf("Audit of %s: %v", r.collection, err)
+}
+
+func stringField(snapshot bson.M, field string) string {
+	value, ok := snapshot[field].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"context"
+	auditmodels "smlaicloudplatform/internal/audit/models"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/pkg/microservice"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditTestDoc struct {
+	ShopID    string  `bson:"shopid"`
+	GuidFixed string  `bson:"guidfixed"`
+	Price     float64 `bson:"price"`
+	CreatedBy string  `bson:"createdby"`
+	UpdatedBy string  `bson:"updatedby,omitempty"`
+}
+
+func (auditTestDoc) CollectionName() string {
+	return "auditTestDocs"
+}
+
+// auditTestPersister keep documents of one collection and audit logs in memory
+type auditTestPersister struct {
+	microservice.IPersisterMongo
+	docs      map[string]bson.M
+	auditLogs []auditmodels.AuditLogDoc
+}
+
+func (pst *auditTestPersister) Create(ctx context.Context, model interface{}, data interface{}) (primitive.ObjectID, error) {
+	id := primitive.NewObjectID()
+	snapshot, _ := auditmodels.ToSnapshot(data)
+	snapshot["_id"] = id
+	pst.docs[snapshot["guidfixed"].(string)] = snapshot
+	return id, nil
+}
+
+func (pst *auditTestPersister) CreateInBatch(ctx context.Context, model interface{}, data []interface{}) error {
+	for _, doc := range data {
+		pst.auditLogs = append(pst.auditLogs, doc.(auditmodels.AuditLogDoc))
+	}
+	return nil
+}
+
+// Find return documents of the shop and the guid of filter which are not deleted, up to limit of opts
+func (pst *auditTestPersister) Find(ctx context.Context, model interface{}, filter interface{}, decode interface{}, opts ...*options.FindOptions) error {
+	shopID := filter.(bson.M)["shopid"]
+	guid, hasGuid := filter.(bson.M)["guidfixed"]
+
+	docList := decode.(*[]bson.M)
+	for _, doc := range pst.docs {
+		if hasGuid && doc["guidfixed"] != guid {
+			continue
+		}
+		if doc["shopid"] == shopID && doc["deletedat"] == nil {
+			*docList = append(*docList, doc)
+		}
+		if len(opts) > 0 && opts[0].Limit != nil && int64(len(*docList)) == *opts[0].Limit {
+			break
+		}
+	}
+	return nil
+}
+
+func (pst *auditTestPersister) FindOneAndUpdate(ctx context.Context, model interface{}, filter interface{}, data interface{}, decode interface{}, opts ...*options.FindOneAndUpdateOptions) error {
+	var guid string
+	switch f := filter.(type) {
+	case bson.M:
+		guid = f["guidfixed"].(string)
+	case map[string]interface{}:
+		guid = f["guidfixed"].(string)
+	}
+
+	doc, ok := pst.docs[guid]
+	if !ok || doc["deletedat"] != nil {
+		return nil
+	}
+
+	raw, _ := bson.Marshal(doc)
+	_ = bson.Unmarshal(raw, decode)
+
+	fields, _ := auditmodels.ToSnapshot(data.(bson.M)["$set"])
+	for key, value := range fields {
+		doc[key] = value
+	}
+	return nil
+}
+
+func (pst *auditTestPersister) SoftDelete(ctx context.Context, model interface{}, username string, filter interface{}) error {
+	for _, id := range filter.(bson.M)["_id"].(bson.M)["$in"].([]interface{}) {
+		for _, doc := range pst.docs {
+			if doc["_id"] == id {
+				doc["deletedat"] = time.Now()
+			}
+		}
+	}
+	return nil
+}
+
+func TestCrudRepositoryAudit(t *testing.T) {
+	pst := &auditTestPersister{docs: map[string]bson.M{}}
+	repo := NewCrudRepository[auditTestDoc](pst)
+
+	ctx := microservice.WithRequestActor(context.Background(), microservice.RequestActor{
+		Username: "user01",
+		ShopID:   "SHOP01",
+		IP:       "203.0.113.10",
+		AuthType: microservice.AuthTypeBearer,
+	})
+
+	_, err := repo.Create(ctx, auditTestDoc{ShopID: "SHOP01", GuidFixed: "DOC01", Price: 100, CreatedBy: "user01"})
+	assert.Nil(t, err)
+
+	err = repo.Update(ctx, "SHOP01", "DOC01", auditTestDoc{ShopID: "SHOP01", GuidFixed: "DOC01", Price: 120, CreatedBy: "user01", UpdatedBy: "user01"})
+	assert.Nil(t, err)
+
+	err = repo.DeleteByGuidfixed(ctx, "SHOP01", "DOC01", "user01")
+	assert.Nil(t, err)
+
+	// document which is deleted already is not recorded again
+	err = repo.DeleteByGuidfixed(ctx, "SHOP01", "DOC01", "user01")
+	assert.Nil(t, err)
+
+	assert.Len(t, pst.auditLogs, 3)
+
+	for i, action := range []string{auditmodels.AuditActionCreate, auditmodels.AuditActionUpdate, auditmodels.AuditActionDelete} {
+		auditLog := pst.auditLogs[i]
+		assert.Equal(t, action, auditLog.Action)
+		assert.Equal(t, "auditTestDocs", auditLog.Collection)
+		assert.Equal(t, "SHOP01", auditLog.ShopID)
+		assert.Equal(t, "DOC01", auditLog.GuidFixed)
+		assert.Equal(t, "user01", auditLog.Actor)
+		assert.Equal(t, "203.0.113.10", auditLog.IP)
+		assert.Equal(t, microservice.AuthTypeBearer, auditLog.AuthType)
+	}
+
+	assert.Equal(t, []auditmodels.FieldChange{{Field: "price", Before: 100.0, After: 120.0}}, pst.auditLogs[1].Changes)
+	assert.Nil(t, pst.auditLogs[2].After)
+}
+
+func TestCrudRepositoryAuditDeleteByFilter(t *testing.T) {
+	pst := &auditTestPersister{docs: map[string]bson.M{}}
+	repo := NewCrudRepository[auditTestDoc](pst)
+	ctx := context.Background()
+
+	for _, guid := range []string{"DOC01", "DOC02", "DOC03"} {
+		_, err := repo.Create(ctx, auditTestDoc{ShopID: "SHOP01", GuidFixed: guid, Price: 100, CreatedBy: "user01"})
+		assert.Nil(t, err)
+	}
+
+	err := repo.Delete(ctx, "SHOP01", "user02", map[string]interface{}{})
+	assert.Nil(t, err)
+
+	deleted := map[string]bool{}
+	for _, auditLog := range pst.auditLogs[3:] {
+		assert.Equal(t, auditmodels.AuditActionDelete, auditLog.Action)
+		assert.Equal(t, "user02", auditLog.Actor)
+		assert.Empty(t, auditLog.IP)
+		deleted[auditLog.GuidFixed] = true
+	}
+
+	assert.Equal(t, map[string]bool{"DOC01": true, "DOC02": true, "DOC03": true}, deleted)
+	for _, doc := range pst.docs {
+		assert.NotNil(t, doc["deletedat"])
+	}
+}
+
+func TestCrudRepositoryAuditDeleteByGuidfixed(t *testing.T) {
+	pst := &auditTestPersister{docs: map[string]bson.M{}}
+	repo := NewCrudRepository[auditTestDoc](pst)
+	ctx := context.Background()
+
+	// documents which are saved twice by the same guid are all deleted
+	for key, guid := range map[string]string{"a": "DOC01", "b": "DOC01", "c": "DOC02"} {
+		pst.docs[key] = bson.M{"_id": primitive.NewObjectID(), "shopid": "SHOP01", "guidfixed": guid, "price": 100.0}
+	}
+
+	err := repo.DeleteByGuidfixed(ctx, "SHOP01", "DOC01", "user02")
+	assert.Nil(t, err)
+
+	assert.Len(t, pst.auditLogs, 2)
+	for _, auditLog := range pst.auditLogs {
+		assert.Equal(t, auditmodels.AuditActionDelete, auditLog.Action)
+		assert.Equal(t, "DOC01", auditLog.GuidFixed)
+		assert.Equal(t, "user02", auditLog.Actor)
+	}
+
+	assert.NotNil(t, pst.docs["a"]["deletedat"])
+	assert.NotNil(t, pst.docs["b"]["deletedat"])
+	assert.Nil(t, pst.docs["c"]["deletedat"])
+}
+
+type auditTestAuth struct {
+	Username string `bson:"username"`
+	Password string `bson:"password"`
+}
+
+type auditTestSecretDoc struct {
+	ShopID    string        `bson:"shopid"`
+	GuidFixed string        `bson:"guidfixed"`
+	Name      string        `bson:"name"`
+	Auth      auditTestAuth `bson:"auth"`
+	CreatedBy string        `bson:"createdby"`
+}
+
+func (auditTestSecretDoc) CollectionName() string {
+	return "auditTestSecretDocs"
+}
+
+func (doc *auditTestSecretDoc) SecretFields() []*string {
+	return []*string{&doc.Auth.Password}
+}
+
+func TestCrudRepositoryAuditRedactSecrets(t *testing.T) {
+	pst := &auditTestPersister{docs: map[string]bson.M{}}
+	repo := NewCrudRepository[auditTestSecretDoc](pst)
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, auditTestSecretDoc{ShopID: "SHOP01", GuidFixed: "DOC01", Name: "a", Auth: auditTestAuth{Username: "u1", Password: "secret1"}, CreatedBy: "user01"})
+	assert.Nil(t, err)
+
+	err = repo.Update(ctx, "SHOP01", "DOC01", auditTestSecretDoc{ShopID: "SHOP01", GuidFixed: "DOC01", Name: "a", Auth: auditTestAuth{Username: "u1", Password: "secret2"}, CreatedBy: "user01"})
+	assert.Nil(t, err)
+
+	assert.Len(t, pst.auditLogs, 2)
+	assert.Equal(t, encrypt.RedactedValue, pst.auditLogs[0].After["auth"].(bson.M)["password"])
+	assert.Equal(t, "u1", pst.auditLogs[0].After["auth"].(bson.M)["username"])
+	assert.Equal(t, encrypt.RedactedValue, pst.auditLogs[1].Before["auth"].(bson.M)["password"])
+	assert.Equal(t, encrypt.RedactedValue, pst.auditLogs[1].After["auth"].(bson.M)["password"])
+
+	// change of the secret is recorded without its values
+	assert.Equal(t, []auditmodels.FieldChange{{Field: "auth.password", Before: encrypt.RedactedValue, After: encrypt.RedactedValue}}, pst.auditLogs[1].Changes)
+
+	// stored document keep the secret
+	assert.Equal(t, "secret2", pst.docs["DOC01"]["auth"].(bson.M)["password"])
+}
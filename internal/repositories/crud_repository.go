@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/pkg/microservice"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ICRUDRepository[T any] interface {
@@ -27,13 +28,19 @@ type ICRUDRepository[T any] interface {
 	FindOneFilter(ctx context.Context, shopID string, filters map[string]interface{}) (T, error)
 }
 
+// auditDeleteBatchSize is number of documents which Delete soft delete at once
+const auditDeleteBatchSize = 500
+
+// CrudRepository record audit log of every create, update and delete
 type CrudRepository[T any] struct {
-	pst microservice.IPersisterMongo
+	pst   microservice.IPersisterMongo
+	audit AuditRecorder
 }
 
 func NewCrudRepository[T any](pst microservice.IPersisterMongo) CrudRepository[T] {
 	return CrudRepository[T]{
-		pst: pst,
+		pst:   pst,
+		audit: NewAuditRecorder(pst, new(T)),
 	}
 }
 
@@ -76,6 +83,8 @@ func (repo CrudRepository[T]) Create(ctx context.Context, doc T) (string, error)
 		return "", err
 	}
 
+	repo.audit.RecordCreate(ctx, doc)
+
 	return idx.Hex(), nil
 }
 
@@ -91,6 +100,9 @@ func (repo CrudRepository[T]) CreateInBatch(ctx context.Context, docList []T) er
 	if err != nil {
 		return err
 	}
+
+	repo.audit.RecordCreate(ctx, tempList...)
+
 	return nil
 }
 
@@ -100,15 +112,71 @@ func (repo CrudRepository[T]) Update(ctx context.Context, shopID string, guid st
 		"guidfixed": guid,
 	}
 
-	err := repo.pst.UpdateOne(ctx, new(T), filterDoc, doc)
+	updateDoc, err := toUpdateDoc(doc)
+	if err != nil {
+		return err
+	}
+
+	if !repo.audit.Enabled() {
+		return repo.pst.UpdateOne(ctx, new(T), filterDoc, updateDoc)
+	}
+
+	// document before the update is returned by the update itself for audit log
+	before := bson.M{}
+	err = repo.pst.FindOneAndUpdate(ctx, new(T), filterDoc, bson.M{"$set": updateDoc}, &before, options.FindOneAndUpdate().SetReturnDocument(options.Before))
 
 	if err != nil {
 		return err
 	}
 
+	if len(before) > 0 {
+		repo.audit.RecordUpdate(ctx, before, doc)
+	}
+
 	return nil
 }
 
+// UpdateWhen update the document when it still matches the conditions, false is returned when it is changed by another request
+func (repo CrudRepository[T]) UpdateWhen(ctx context.Context, shopID string, guid string, conditions map[string]interface{}, doc T) (bool, error) {
+	filterDoc := bson.M{}
+	for col, val := range conditions {
+		filterDoc[col] = val
+	}
+
+	filterDoc["shopid"] = shopID
+	filterDoc["guidfixed"] = guid
+	filterDoc = withNotDeleted(filterDoc)
+
+	if !repo.audit.Enabled() {
+		collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), new(T))
+		if err != nil {
+			return false, err
+		}
+
+		result, err := collection.UpdateOne(ctx, filterDoc, bson.M{"$set": doc})
+		if err != nil {
+			return false, err
+		}
+
+		return result.MatchedCount > 0, nil
+	}
+
+	before := bson.M{}
+	err := repo.pst.FindOneAndUpdate(ctx, new(T), filterDoc, bson.M{"$set": doc}, &before, options.FindOneAndUpdate().SetReturnDocument(options.Before))
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(before) == 0 {
+		return false, nil
+	}
+
+	repo.audit.RecordUpdate(ctx, before, doc)
+
+	return true, nil
+}
+
 func (repo CrudRepository[T]) Delete(ctx context.Context, shopID string, username string, filters map[string]interface{}) error {
 
 	filterQuery := bson.M{}
@@ -119,23 +187,63 @@ func (repo CrudRepository[T]) Delete(ctx context.Context, shopID string, usernam
 
 	filterQuery["shopid"] = shopID
 
-	err := repo.pst.SoftDelete(ctx, new(T), username, filterQuery)
-
-	if err != nil {
-		return err
+	if !repo.audit.Enabled() {
+		return repo.pst.SoftDelete(ctx, new(T), username, filterQuery)
 	}
 
-	return nil
+	// documents are deleted in batches so documents before delete which are kept for audit log are bounded
+	for {
+		beforeList := []bson.M{}
+		err := repo.pst.Find(ctx, new(T), withNotDeleted(filterQuery), &beforeList, options.Find().SetLimit(auditDeleteBatchSize))
+		if err != nil {
+			return err
+		}
+
+		if len(beforeList) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, 0, len(beforeList))
+		for _, before := range beforeList {
+			ids = append(ids, before["_id"])
+		}
+
+		err = repo.pst.SoftDelete(ctx, new(T), username, bson.M{"shopid": shopID, "_id": bson.M{"$in": ids}})
+		if err != nil {
+			return err
+		}
+
+		repo.audit.RecordDelete(ctx, beforeList, username)
+
+		if len(beforeList) < auditDeleteBatchSize {
+			return nil
+		}
+	}
 }
 
 func (repo CrudRepository[T]) DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error {
-	err := repo.pst.SoftDelete(ctx, new(T), username, bson.M{"guidfixed": guid, "shopid": shopID})
+	// every document of the guid is deleted and recorded like Delete
+	return repo.Delete(ctx, shopID, username, map[string]interface{}{"guidfixed": guid})
+}
 
+// toUpdateDoc convert the document to fields of $set the same way as the persister does for UpdateOne
+func toUpdateDoc(doc interface{}) (bson.D, error) {
+	raw, err := bson.Marshal(doc)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	updateDoc := bson.D{}
+	err = bson.Unmarshal(raw, &updateDoc)
+	return updateDoc, err
+}
+
+func withNotDeleted(filterQuery bson.M) bson.M {
+	notDeletedQuery := bson.M{"deletedat": bson.M{"$exists": false}}
+	for col, val := range filterQuery {
+		notDeletedQuery[col] = val
+	}
+	return notDeletedQuery
 }
 
 func (repo CrudRepository[T]) FindOne(ctx context.Context, shopID string, filters interface{}) (T, error) {
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/pkg/microservice"
 )
 
@@ -13,10 +14,16 @@ type KafkaConfig interface {
 	TopicBulkUpdated() string
 }
 
+// IOutboxWriter write event into outbox collection, ctx is mongo transaction context of the document write
+type IOutboxWriter interface {
+	Add(ctx context.Context, topic string, key string, message interface{}) error
+}
+
 type KafkaRepository[T any] struct {
-	topic KafkaConfig
-	prod  microservice.IProducer
-	mqKey string
+	topic  KafkaConfig
+	prod   microservice.IProducer
+	mqKey  string
+	outbox IOutboxWriter
 }
 
 func NewKafkaRepository[T any](prod microservice.IProducer, topicConfig KafkaConfig, mqKey string) KafkaRepository[T] {
@@ -86,3 +93,42 @@ func (repo KafkaRepository[T]) DeleteInBatch(docList []T) error {
 
 	return nil
 }
+
+// WithOutbox return repository which Outbox* methods write events into outbox instead of sending them directly
+func (repo KafkaRepository[T]) WithOutbox(outbox IOutboxWriter) KafkaRepository[T] {
+	repo.outbox = outbox
+	return repo
+}
+
+// OutboxCreate write created event into outbox in the same transaction of ctx, send directly when outbox is not set
+func (repo KafkaRepository[T]) OutboxCreate(ctx context.Context, doc T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicCreated(), doc)
+}
+
+func (repo KafkaRepository[T]) OutboxUpdate(ctx context.Context, doc T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicUpdated(), doc)
+}
+
+func (repo KafkaRepository[T]) OutboxDelete(ctx context.Context, doc T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicDeleted(), doc)
+}
+
+func (repo KafkaRepository[T]) OutboxCreateInBatch(ctx context.Context, docList []T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicBulkCreated(), docList)
+}
+
+func (repo KafkaRepository[T]) OutboxUpdateInBatch(ctx context.Context, docList []T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicBulkUpdated(), docList)
+}
+
+func (repo KafkaRepository[T]) OutboxDeleteInBatch(ctx context.Context, docList []T) error {
+	return repo.sendOutbox(ctx, repo.topic.TopicBulkDeleted(), docList)
+}
+
+func (repo KafkaRepository[T]) sendOutbox(ctx context.Context, topic string, message interface{}) error {
+	if repo.outbox == nil {
+		return repo.prod.SendMessage(topic, repo.mqKey, message)
+	}
+
+	return repo.outbox.Add(ctx, topic, repo.mqKey, message)
+}
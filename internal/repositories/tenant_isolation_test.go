@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/models"
+	"smlaicloudplatform/pkg/microservice/tenanttest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type tenantTestDoc struct {
+	ShopID    string `bson:"shopid"`
+	GuidFixed string `bson:"guidfixed"`
+	Code      string `bson:"code"`
+	CreatedBy string `bson:"createdby"`
+}
+
+func (tenantTestDoc) CollectionName() string {
+	return "tenantTestDocs"
+}
+
+func TestCrudRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	queries := map[string]func(pst microservice.IPersisterMongo, shopID string) error{
+		"Count": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).Count(ctx, shopID)
+			return err
+		},
+		"CountByInKeys": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).CountByInKeys(ctx, shopID, "code", []string{"c1"})
+			return err
+		},
+		"Create": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).Create(ctx, tenantTestDoc{ShopID: shopID, GuidFixed: "g1"})
+			return err
+		},
+		"CreateInBatch": func(pst microservice.IPersisterMongo, shopID string) error {
+			return NewCrudRepository[tenantTestDoc](pst).CreateInBatch(ctx, []tenantTestDoc{{ShopID: shopID}, {ShopID: shopID}})
+		},
+		"Update": func(pst microservice.IPersisterMongo, shopID string) error {
+			return NewCrudRepository[tenantTestDoc](pst).Update(ctx, shopID, "g1", tenantTestDoc{ShopID: shopID, GuidFixed: "g1"})
+		},
+		"Delete": func(pst microservice.IPersisterMongo, shopID string) error {
+			return NewCrudRepository[tenantTestDoc](pst).Delete(ctx, shopID, "user", map[string]interface{}{"code": "c1"})
+		},
+		"DeleteByGuidfixed": func(pst microservice.IPersisterMongo, shopID string) error {
+			return NewCrudRepository[tenantTestDoc](pst).DeleteByGuidfixed(ctx, shopID, "g1", "user")
+		},
+		"FindByGuid": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).FindByGuid(ctx, shopID, "g1")
+			return err
+		},
+		"FindByGuids": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).FindByGuids(ctx, shopID, []string{"g1"})
+			return err
+		},
+		"FindByDocIndentityGuids": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).FindByDocIndentityGuids(ctx, shopID, "code", []string{"c1"})
+			return err
+		},
+		"FindFilter": func(pst microservice.IPersisterMongo, shopID string) error {
+			_, err := NewCrudRepository[tenantTestDoc](pst).FindFilter(ctx, shopID, map[string]interface{}{"code": "c1"})
+			return err
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			tenanttest.AssertMongoScoped(t, query)
+		})
+	}
+}
+
+func TestSearchRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	tenanttest.AssertMongoScoped(t, func(pst microservice.IPersisterMongo, shopID string) error {
+		_, _, err := NewSearchRepository[tenantTestDoc](pst).FindAggregatePage(ctx, shopID, models.Pageable{Page: 1, Limit: 10},
+			bson.M{"$sort": bson.M{"code": 1}},
+		)
+		return err
+	})
+}
+
+func TestGuidRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	tenanttest.AssertMongoScoped(t, func(pst microservice.IPersisterMongo, shopID string) error {
+		_, err := NewGuidRepository[tenantTestDoc](pst).FindInItemGuid(ctx, shopID, "code", []string{"c1"})
+		return err
+	})
+}
+
+func FuzzCrudRepository_TenantIsolation(f *testing.F) {
+	ctx := context.Background()
+
+	tenanttest.FuzzMongoScoped(f, func(pst microservice.IPersisterMongo, shopID string) error {
+		_, err := NewCrudRepository[tenantTestDoc](pst).FindFilter(ctx, shopID, map[string]interface{}{"code": "c1"})
+		return err
+	})
+}
@@ -77,7 +77,7 @@ func (h DeviceHttp) CreateDevice(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateDevice(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDevice(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h DeviceHttp) UpdateDevice(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateDevice(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDevice(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h DeviceHttp) DeleteDevice(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDevice(shopID, id, authUsername)
+	err := h.svc.DeleteDevice(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h DeviceHttp) DeleteDeviceByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteDeviceByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDeviceByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h DeviceHttp) InfoDevice(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Device %v", id)
-	doc, err := h.svc.InfoDevice(shopID, id)
+	doc, err := h.svc.InfoDevice(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h DeviceHttp) SearchDevicePage(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchDevice(shopID, pageable)
+	docList, pagination, err := h.svc.SearchDevice(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h DeviceHttp) SearchDeviceLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchDeviceStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchDeviceStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -328,7 +328,7 @@ func (h DeviceHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
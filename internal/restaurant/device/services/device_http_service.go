@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IDeviceHttpService interface {
-	CreateDevice(shopID string, authUsername string, doc models.Device) (string, error)
-	UpdateDevice(shopID string, guid string, authUsername string, doc models.Device) error
-	DeleteDevice(shopID string, guid string, authUsername string) error
-	DeleteDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDevice(shopID string, guid string) (models.DeviceInfo, error)
-	SearchDevice(shopID string, pageable micromodels.Pageable) ([]models.DeviceInfo, mongopagination.PaginationData, error)
-	SearchDeviceStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DeviceInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Device) (common.BulkImport, error)
+	CreateDevice(ctx context.Context, shopID string, authUsername string, doc models.Device) (string, error)
+	UpdateDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.Device) error
+	DeleteDevice(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDevice(ctx context.Context, shopID string, guid string) (models.DeviceInfo, error)
+	SearchDevice(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.DeviceInfo, mongopagination.PaginationData, error)
+	SearchDeviceStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DeviceInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Device) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewDeviceHttpService(repo repositories.IDeviceRepository, syncCacheRepo mas
 	return insSvc
 }
 
-func (svc DeviceHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DeviceHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DeviceHttpService) CreateDevice(shopID string, authUsername string, doc models.Device) (string, error) {
+func (svc DeviceHttpService) CreateDevice(ctx context.Context, shopID string, authUsername string, doc models.Device) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc DeviceHttpService) CreateDevice(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc DeviceHttpService) UpdateDevice(shopID string, guid string, authUsername string, doc models.Device) error {
+func (svc DeviceHttpService) UpdateDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.Device) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc DeviceHttpService) UpdateDevice(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DeviceHttpService) DeleteDevice(shopID string, guid string, authUsername string) error {
+func (svc DeviceHttpService) DeleteDevice(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc DeviceHttpService) DeleteDevice(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc DeviceHttpService) DeleteDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DeviceHttpService) DeleteDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +169,9 @@ func (svc DeviceHttpService) DeleteDeviceByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc DeviceHttpService) InfoDevice(shopID string, guid string) (models.DeviceInfo, error) {
+func (svc DeviceHttpService) InfoDevice(ctx context.Context, shopID string, guid string) (models.DeviceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -187,9 +188,9 @@ func (svc DeviceHttpService) InfoDevice(shopID string, guid string) (models.Devi
 
 }
 
-func (svc DeviceHttpService) SearchDevice(shopID string, pageable micromodels.Pageable) ([]models.DeviceInfo, mongopagination.PaginationData, error) {
+func (svc DeviceHttpService) SearchDevice(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.DeviceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -206,9 +207,9 @@ func (svc DeviceHttpService) SearchDevice(shopID string, pageable micromodels.Pa
 	return docList, pagination, nil
 }
 
-func (svc DeviceHttpService) SearchDeviceStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DeviceInfo, int, error) {
+func (svc DeviceHttpService) SearchDeviceStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.DeviceInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -227,9 +228,9 @@ func (svc DeviceHttpService) SearchDeviceStep(shopID string, langCode string, pa
 	return docList, total, nil
 }
 
-func (svc DeviceHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Device) (common.BulkImport, error) {
+func (svc DeviceHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Device) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Device](dataList, svc.getDocIDKey)
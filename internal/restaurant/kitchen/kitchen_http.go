@@ -70,7 +70,7 @@ func (h KitchenHttp) CreateKitchen(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateKitchen(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateKitchen(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -110,7 +110,7 @@ func (h KitchenHttp) UpdateKitchen(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateKitchen(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateKitchen(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -141,7 +141,7 @@ func (h KitchenHttp) DeleteKitchen(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteKitchen(shopID, id, authUsername)
+	err := h.svc.DeleteKitchen(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -172,7 +172,7 @@ func (h KitchenHttp) InfoKitchen(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Kitchen %v", id)
-	doc, err := h.svc.InfoKitchen(shopID, id)
+	doc, err := h.svc.InfoKitchen(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -213,7 +213,7 @@ func (h KitchenHttp) SearchKitchen(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchKitchen(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchKitchen(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -240,7 +240,7 @@ func (h KitchenHttp) GetKitchenProductBarcode(ctx microservice.IContext) error {
 	userInfo := ctx.UserInfo()
 	shopID := userInfo.ShopID
 
-	docList, err := h.svc.GetProductBarcodeKitchen(shopID)
+	docList, err := h.svc.GetProductBarcodeKitchen(ctx.Context(), shopID)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -280,7 +280,7 @@ func (h KitchenHttp) SearchKitchenStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchKitchenStep(shopID, "", filters, pageableStep)
+	docList, total, err := h.svc.SearchKitchenStep(ctx.Context(), shopID, "", filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -320,7 +320,7 @@ func (h KitchenHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,14 +20,14 @@ import (
 )
 
 type IKitchenService interface {
-	CreateKitchen(shopID string, authUsername string, doc models.Kitchen) (string, error)
-	UpdateKitchen(shopID string, guid string, authUsername string, doc models.Kitchen) error
-	DeleteKitchen(shopID string, guid string, authUsername string) error
-	InfoKitchen(shopID string, guid string) (models.KitchenInfo, error)
-	SearchKitchen(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.KitchenInfo, mongopagination.PaginationData, error)
-	SearchKitchenStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.KitchenInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Kitchen) (common.BulkImport, error)
-	GetProductBarcodeKitchen(shopID string) ([]models.KitchenProductBarcode, error)
+	CreateKitchen(ctx context.Context, shopID string, authUsername string, doc models.Kitchen) (string, error)
+	UpdateKitchen(ctx context.Context, shopID string, guid string, authUsername string, doc models.Kitchen) error
+	DeleteKitchen(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoKitchen(ctx context.Context, shopID string, guid string) (models.KitchenInfo, error)
+	SearchKitchen(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.KitchenInfo, mongopagination.PaginationData, error)
+	SearchKitchenStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.KitchenInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Kitchen) (common.BulkImport, error)
+	GetProductBarcodeKitchen(ctx context.Context, shopID string) ([]models.KitchenProductBarcode, error)
 
 	// LastActivity(shopID string, action string, lastUpdatedDate time.Time, pageable micromodels.Pageable) (common.LastActivity, mongopagination.PaginationData, error)
 
@@ -54,13 +55,13 @@ func NewKitchenService(repo KitchenRepository, syncCacheRepo mastersync.IMasterS
 	return insSvc
 }
 
-func (svc KitchenService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc KitchenService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc KitchenService) CreateKitchen(shopID string, authUsername string, doc models.Kitchen) (string, error) {
+func (svc KitchenService) CreateKitchen(ctx context.Context, shopID string, authUsername string, doc models.Kitchen) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc KitchenService) CreateKitchen(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc KitchenService) UpdateKitchen(shopID string, guid string, authUsername string, doc models.Kitchen) error {
+func (svc KitchenService) UpdateKitchen(ctx context.Context, shopID string, guid string, authUsername string, doc models.Kitchen) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc KitchenService) UpdateKitchen(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc KitchenService) DeleteKitchen(shopID string, guid string, authUsername string) error {
+func (svc KitchenService) DeleteKitchen(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -142,9 +143,9 @@ func (svc KitchenService) DeleteKitchen(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc KitchenService) InfoKitchen(shopID string, guid string) (models.KitchenInfo, error) {
+func (svc KitchenService) InfoKitchen(ctx context.Context, shopID string, guid string) (models.KitchenInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc KitchenService) InfoKitchen(shopID string, guid string) (models.Kitche
 
 }
 
-func (svc KitchenService) SearchKitchen(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.KitchenInfo, mongopagination.PaginationData, error) {
+func (svc KitchenService) SearchKitchen(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.KitchenInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -184,9 +185,9 @@ func (svc KitchenService) SearchKitchen(shopID string, filters map[string]interf
 	return docList, pagination, nil
 }
 
-func (svc KitchenService) SearchKitchenStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.KitchenInfo, int, error) {
+func (svc KitchenService) SearchKitchenStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.KitchenInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -205,9 +206,9 @@ func (svc KitchenService) SearchKitchenStep(shopID string, langCode string, filt
 	return docList, total, nil
 }
 
-func (svc KitchenService) SaveInBatch(shopID string, authUsername string, dataList []models.Kitchen) (common.BulkImport, error) {
+func (svc KitchenService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Kitchen) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadCategoryList, payloadDuplicateCategoryList := importdata.FilterDuplicate[models.Kitchen](dataList, svc.getDocIDKey)
@@ -324,9 +325,9 @@ func (svc KitchenService) GetModuleName() string {
 	return "restaurant-kitchen"
 }
 
-func (svc KitchenService) GetProductBarcodeKitchen(shopID string) ([]models.KitchenProductBarcode, error) {
+func (svc KitchenService) GetProductBarcodeKitchen(ctx context.Context, shopID string) ([]models.KitchenProductBarcode, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	isNotFinished := true
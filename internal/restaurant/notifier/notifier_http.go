@@ -73,7 +73,7 @@ func (h NotifierHttp) CreateNotifier(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateNotifier(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateNotifier(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -118,7 +118,7 @@ func (h NotifierHttp) UpdateNotifier(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateNotifier(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateNotifier(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -149,7 +149,7 @@ func (h NotifierHttp) DeleteNotifier(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteNotifier(shopID, id, authUsername)
+	err := h.svc.DeleteNotifier(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -188,7 +188,7 @@ func (h NotifierHttp) DeleteNotifierByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteNotifierByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteNotifierByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -218,7 +218,7 @@ func (h NotifierHttp) InfoNotifier(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Notifier %v", id)
-	doc, err := h.svc.InfoNotifier(shopID, id)
+	doc, err := h.svc.InfoNotifier(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -248,7 +248,7 @@ func (h NotifierHttp) InfoNotifierByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoNotifierByCode(shopID, code)
+	doc, err := h.svc.InfoNotifierByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -279,7 +279,7 @@ func (h NotifierHttp) SearchNotifierPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchNotifier(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchNotifier(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/restaurant/notifier/models"
 	"smlaicloudplatform/internal/restaurant/notifier/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -14,13 +15,13 @@ import (
 )
 
 type INotifierHttpService interface {
-	CreateNotifier(shopID string, authUsername string, doc models.Notifier) (string, error)
-	UpdateNotifier(shopID string, guid string, authUsername string, doc models.Notifier) error
-	DeleteNotifier(shopID string, guid string, authUsername string) error
-	DeleteNotifierByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoNotifier(shopID string, guid string) (models.NotifierInfo, error)
-	InfoNotifierByCode(shopID string, code string) (models.NotifierInfo, error)
-	SearchNotifier(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierInfo, mongopagination.PaginationData, error)
+	CreateNotifier(ctx context.Context, shopID string, authUsername string, doc models.Notifier) (string, error)
+	UpdateNotifier(ctx context.Context, shopID string, guid string, authUsername string, doc models.Notifier) error
+	DeleteNotifier(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteNotifierByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoNotifier(ctx context.Context, shopID string, guid string) (models.NotifierInfo, error)
+	InfoNotifierByCode(ctx context.Context, shopID string, code string) (models.NotifierInfo, error)
+	SearchNotifier(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierInfo, mongopagination.PaginationData, error)
 }
 
 type NotifierHttpService struct {
@@ -47,13 +48,13 @@ func NewNotifierHttpService(
 	return insSvc
 }
 
-func (svc NotifierHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc NotifierHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc NotifierHttpService) CreateNotifier(shopID string, authUsername string, doc models.Notifier) (string, error) {
+func (svc NotifierHttpService) CreateNotifier(ctx context.Context, shopID string, authUsername string, doc models.Notifier) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -85,9 +86,9 @@ func (svc NotifierHttpService) CreateNotifier(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc NotifierHttpService) UpdateNotifier(shopID string, guid string, authUsername string, doc models.Notifier) error {
+func (svc NotifierHttpService) UpdateNotifier(ctx context.Context, shopID string, guid string, authUsername string, doc models.Notifier) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -114,9 +115,9 @@ func (svc NotifierHttpService) UpdateNotifier(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc NotifierHttpService) DeleteNotifier(shopID string, guid string, authUsername string) error {
+func (svc NotifierHttpService) DeleteNotifier(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -137,9 +138,9 @@ func (svc NotifierHttpService) DeleteNotifier(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc NotifierHttpService) DeleteNotifierByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc NotifierHttpService) DeleteNotifierByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -154,9 +155,9 @@ func (svc NotifierHttpService) DeleteNotifierByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc NotifierHttpService) InfoNotifier(shopID string, guid string) (models.NotifierInfo, error) {
+func (svc NotifierHttpService) InfoNotifier(ctx context.Context, shopID string, guid string) (models.NotifierInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -172,9 +173,9 @@ func (svc NotifierHttpService) InfoNotifier(shopID string, guid string) (models.
 	return findDoc.NotifierInfo, nil
 }
 
-func (svc NotifierHttpService) InfoNotifierByCode(shopID string, code string) (models.NotifierInfo, error) {
+func (svc NotifierHttpService) InfoNotifierByCode(ctx context.Context, shopID string, code string) (models.NotifierInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "usercode", code)
@@ -190,9 +191,9 @@ func (svc NotifierHttpService) InfoNotifierByCode(shopID string, code string) (m
 	return findDoc.NotifierInfo, nil
 }
 
-func (svc NotifierHttpService) SearchNotifier(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierInfo, mongopagination.PaginationData, error) {
+func (svc NotifierHttpService) SearchNotifier(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -102,7 +102,7 @@ func (h NotifierDeviceHttp) ConfirmNotifierAuthRefCode(ctx microservice.IContext
 		return err
 	}
 
-	isAuthPass, err := h.svc.ConfirmAuthCode(*docReq)
+	isAuthPass, err := h.svc.ConfirmAuthCode(ctx.Context(), *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -147,7 +147,7 @@ func (h NotifierDeviceHttp) UpdateNotifierDevice(ctx microservice.IContext) erro
 		return err
 	}
 
-	err = h.svc.UpdateNotifierDevice(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateNotifierDevice(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -178,7 +178,7 @@ func (h NotifierDeviceHttp) DeleteNotifierDevice(ctx microservice.IContext) erro
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteNotifierDevice(shopID, id, authUsername)
+	err := h.svc.DeleteNotifierDevice(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -217,7 +217,7 @@ func (h NotifierDeviceHttp) DeleteNotifierDeviceByGUIDs(ctx microservice.IContex
 		return err
 	}
 
-	err = h.svc.DeleteNotifierDeviceByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteNotifierDeviceByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -247,7 +247,7 @@ func (h NotifierDeviceHttp) InfoNotifierDevice(ctx microservice.IContext) error
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Notifier %v", id)
-	doc, err := h.svc.InfoNotifierDevice(shopID, id)
+	doc, err := h.svc.InfoNotifierDevice(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -279,7 +279,7 @@ func (h NotifierDeviceHttp) SearchNotifierPage(ctx microservice.IContext) error
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchNotifierDevice(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchNotifierDevice(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
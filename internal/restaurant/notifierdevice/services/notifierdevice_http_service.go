@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/restaurant/notifierdevice/models"
 	"smlaicloudplatform/internal/restaurant/notifierdevice/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -15,13 +16,13 @@ import (
 
 type INotifierDeviceHttpService interface {
 	CreateAuthCode(shopID string, authUsername string) (models.NotifierDeviceAuth, error)
-	ConfirmAuthCode(payload models.NotifierDeviceConfirmAuthPayload) (bool, error)
+	ConfirmAuthCode(ctx context.Context, payload models.NotifierDeviceConfirmAuthPayload) (bool, error)
 
-	UpdateNotifierDevice(shopID string, guid string, authUsername string, doc models.NotifierDevice) error
-	DeleteNotifierDevice(shopID string, guid string, authUsername string) error
-	DeleteNotifierDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoNotifierDevice(shopID string, guid string) (models.NotifierDeviceInfo, error)
-	SearchNotifierDevice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierDeviceInfo, mongopagination.PaginationData, error)
+	UpdateNotifierDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.NotifierDevice) error
+	DeleteNotifierDevice(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteNotifierDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoNotifierDevice(ctx context.Context, shopID string, guid string) (models.NotifierDeviceInfo, error)
+	SearchNotifierDevice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierDeviceInfo, mongopagination.PaginationData, error)
 }
 
 type NotifierDeviceHttpService struct {
@@ -51,8 +52,8 @@ func NewNotifierDeviceHttpService(
 	return insSvc
 }
 
-func (svc NotifierDeviceHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc NotifierDeviceHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
 func (svc NotifierDeviceHttpService) CreateAuthCode(shopID string, authUsername string) (models.NotifierDeviceAuth, error) {
@@ -74,7 +75,7 @@ func (svc NotifierDeviceHttpService) CreateAuthCode(shopID string, authUsername
 	return notifierAuth, nil
 }
 
-func (svc NotifierDeviceHttpService) ConfirmAuthCode(payload models.NotifierDeviceConfirmAuthPayload) (bool, error) {
+func (svc NotifierDeviceHttpService) ConfirmAuthCode(ctx context.Context, payload models.NotifierDeviceConfirmAuthPayload) (bool, error) {
 
 	notifierAuth, err := svc.cacheRepo.Get(payload.RefCode)
 
@@ -86,7 +87,7 @@ func (svc NotifierDeviceHttpService) ConfirmAuthCode(payload models.NotifierDevi
 		return false, errors.New("refcode invalid")
 	}
 
-	_, err = svc.CreateNotifierDevice(notifierAuth.ShopID, notifierAuth.UserAddedBy, models.NotifierDevice{
+	_, err = svc.CreateNotifierDevice(ctx, notifierAuth.ShopID, notifierAuth.UserAddedBy, models.NotifierDevice{
 		FCMToken:   payload.FCMToken,
 		DeviceID:   payload.DeviceID,
 		DeviceName: payload.DeviceName,
@@ -99,9 +100,9 @@ func (svc NotifierDeviceHttpService) ConfirmAuthCode(payload models.NotifierDevi
 	return true, nil
 }
 
-func (svc NotifierDeviceHttpService) CreateNotifierDevice(shopID string, authUsername string, doc models.NotifierDevice) (string, error) {
+func (svc NotifierDeviceHttpService) CreateNotifierDevice(ctx context.Context, shopID string, authUsername string, doc models.NotifierDevice) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "fcmtoken", doc.FCMToken)
@@ -133,9 +134,9 @@ func (svc NotifierDeviceHttpService) CreateNotifierDevice(shopID string, authUse
 	return newGuidFixed, nil
 }
 
-func (svc NotifierDeviceHttpService) UpdateNotifierDevice(shopID string, guid string, authUsername string, doc models.NotifierDevice) error {
+func (svc NotifierDeviceHttpService) UpdateNotifierDevice(ctx context.Context, shopID string, guid string, authUsername string, doc models.NotifierDevice) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -162,9 +163,9 @@ func (svc NotifierDeviceHttpService) UpdateNotifierDevice(shopID string, guid st
 	return nil
 }
 
-func (svc NotifierDeviceHttpService) DeleteNotifierDevice(shopID string, guid string, authUsername string) error {
+func (svc NotifierDeviceHttpService) DeleteNotifierDevice(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -185,9 +186,9 @@ func (svc NotifierDeviceHttpService) DeleteNotifierDevice(shopID string, guid st
 	return nil
 }
 
-func (svc NotifierDeviceHttpService) DeleteNotifierDeviceByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc NotifierDeviceHttpService) DeleteNotifierDeviceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -202,9 +203,9 @@ func (svc NotifierDeviceHttpService) DeleteNotifierDeviceByGUIDs(shopID string,
 	return nil
 }
 
-func (svc NotifierDeviceHttpService) InfoNotifierDevice(shopID string, guid string) (models.NotifierDeviceInfo, error) {
+func (svc NotifierDeviceHttpService) InfoNotifierDevice(ctx context.Context, shopID string, guid string) (models.NotifierDeviceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -220,9 +221,9 @@ func (svc NotifierDeviceHttpService) InfoNotifierDevice(shopID string, guid stri
 	return findDoc.NotifierDeviceInfo, nil
 }
 
-func (svc NotifierDeviceHttpService) SearchNotifierDevice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierDeviceInfo, mongopagination.PaginationData, error) {
+func (svc NotifierDeviceHttpService) SearchNotifierDevice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.NotifierDeviceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
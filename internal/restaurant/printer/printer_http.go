@@ -69,7 +69,7 @@ func (h PrinterHttp) CreatePrinter(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreatePrinter(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreatePrinter(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -109,7 +109,7 @@ func (h PrinterHttp) UpdatePrinter(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePrinter(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePrinter(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -140,7 +140,7 @@ func (h PrinterHttp) DeletePrinter(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePrinter(shopID, id, authUsername)
+	err := h.svc.DeletePrinter(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -171,7 +171,7 @@ func (h PrinterHttp) InfoPrinter(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Printer %v", id)
-	doc, err := h.svc.InfoPrinter(shopID, id)
+	doc, err := h.svc.InfoPrinter(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -202,7 +202,7 @@ func (h PrinterHttp) SearchPrinter(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchPrinter(shopID, pageable)
+	docList, pagination, err := h.svc.SearchPrinter(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -234,7 +234,7 @@ func (h PrinterHttp) SearchPrinterLimit(ctx microservice.IContext) error {
 
 	pageableStep := utils.GetPageableStep(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchPrinterStep(shopID, "", pageableStep)
+	docList, total, err := h.svc.SearchPrinterStep(ctx.Context(), shopID, "", pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -274,7 +274,7 @@ func (h PrinterHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,13 +20,13 @@ import (
 )
 
 type IPrinterService interface {
-	CreatePrinter(shopID string, authUsername string, doc models.Printer) (string, error)
-	UpdatePrinter(shopID string, guid string, authUsername string, doc models.Printer) error
-	DeletePrinter(shopID string, guid string, authUsername string) error
-	InfoPrinter(shopID string, guid string) (models.PrinterInfo, error)
-	SearchPrinter(shopID string, pageable micromodels.Pageable) ([]models.PrinterInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Printer) (common.BulkImport, error)
-	SearchPrinterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.PrinterInfo, int, error)
+	CreatePrinter(ctx context.Context, shopID string, authUsername string, doc models.Printer) (string, error)
+	UpdatePrinter(ctx context.Context, shopID string, guid string, authUsername string, doc models.Printer) error
+	DeletePrinter(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoPrinter(ctx context.Context, shopID string, guid string) (models.PrinterInfo, error)
+	SearchPrinter(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.PrinterInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Printer) (common.BulkImport, error)
+	SearchPrinterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.PrinterInfo, int, error)
 
 	GetModuleName() string
 }
@@ -52,13 +53,13 @@ func NewPrinterService(repo PrinterRepository, syncCacheRepo mastersync.IMasterS
 	return insSvc
 }
 
-func (svc PrinterService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PrinterService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PrinterService) CreatePrinter(shopID string, authUsername string, doc models.Printer) (string, error) {
+func (svc PrinterService) CreatePrinter(ctx context.Context, shopID string, authUsername string, doc models.Printer) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -92,9 +93,9 @@ func (svc PrinterService) CreatePrinter(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc PrinterService) UpdatePrinter(shopID string, guid string, authUsername string, doc models.Printer) error {
+func (svc PrinterService) UpdatePrinter(ctx context.Context, shopID string, guid string, authUsername string, doc models.Printer) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -123,9 +124,9 @@ func (svc PrinterService) UpdatePrinter(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc PrinterService) DeletePrinter(shopID string, guid string, authUsername string) error {
+func (svc PrinterService) DeletePrinter(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -139,9 +140,9 @@ func (svc PrinterService) DeletePrinter(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc PrinterService) InfoPrinter(shopID string, guid string) (models.PrinterInfo, error) {
+func (svc PrinterService) InfoPrinter(ctx context.Context, shopID string, guid string) (models.PrinterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -158,9 +159,9 @@ func (svc PrinterService) InfoPrinter(shopID string, guid string) (models.Printe
 
 }
 
-func (svc PrinterService) SearchPrinter(shopID string, pageable micromodels.Pageable) ([]models.PrinterInfo, mongopagination.PaginationData, error) {
+func (svc PrinterService) SearchPrinter(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.PrinterInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -181,9 +182,9 @@ func (svc PrinterService) SearchPrinter(shopID string, pageable micromodels.Page
 	return docList, pagination, nil
 }
 
-func (svc PrinterService) SearchPrinterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.PrinterInfo, int, error) {
+func (svc PrinterService) SearchPrinterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.PrinterInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -202,9 +203,9 @@ func (svc PrinterService) SearchPrinterStep(shopID string, langCode string, page
 	return docList, total, nil
 }
 
-func (svc PrinterService) SaveInBatch(shopID string, authUsername string, dataList []models.Printer) (common.BulkImport, error) {
+func (svc PrinterService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Printer) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadCategoryList, payloadDuplicateCategoryList := importdata.FilterDuplicate[models.Printer](dataList, svc.getDocIDKey)
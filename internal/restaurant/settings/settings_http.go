@@ -71,7 +71,7 @@ func (h RestaurantSettingsHttp) CreateRestaurantSettings(ctx microservice.IConte
 		return err
 	}
 
-	idx, err := h.svc.CreateRestaurantSettings(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateRestaurantSettings(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -111,7 +111,7 @@ func (h RestaurantSettingsHttp) UpdateRestaurantSettings(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.UpdateRestaurantSettings(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateRestaurantSettings(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -142,7 +142,7 @@ func (h RestaurantSettingsHttp) DeleteRestaurantSettings(ctx microservice.IConte
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteRestaurantSettings(shopID, id, authUsername)
+	err := h.svc.DeleteRestaurantSettings(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -181,7 +181,7 @@ func (h RestaurantSettingsHttp) DeleteByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -211,7 +211,7 @@ func (h RestaurantSettingsHttp) InfoRestaurantSettings(ctx microservice.IContext
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get RestaurantSettings %v", id)
-	doc, err := h.svc.InfoRestaurantSettings(shopID, id)
+	doc, err := h.svc.InfoRestaurantSettings(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -244,7 +244,7 @@ func (h RestaurantSettingsHttp) InfoRestaurantSettingsByCode(ctx microservice.IC
 	h.ms.Logger.Debugf("Get RestaurantSettings %v", code)
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.ListRestaurantSettingsByCode(shopID, code, pageable)
+	docList, pagination, err := h.svc.ListRestaurantSettingsByCode(ctx.Context(), shopID, code, pageable)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", code, err)
@@ -277,7 +277,7 @@ func (h RestaurantSettingsHttp) SearchRestaurantSettings(ctx microservice.IConte
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchRestaurantSettings(shopID, pageable)
+	docList, pagination, err := h.svc.SearchRestaurantSettings(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -317,7 +317,7 @@ func (h RestaurantSettingsHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IRestaurantSettingsService interface {
-	CreateRestaurantSettings(shopID string, authUsername string, doc models.RestaurantSettings) (string, error)
-	UpdateRestaurantSettings(shopID string, guid string, authUsername string, doc models.RestaurantSettings) error
-	DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	DeleteRestaurantSettings(shopID string, guid string, authUsername string) error
-	InfoRestaurantSettings(shopID string, guid string) (models.RestaurantSettingsInfo, error)
-	SearchRestaurantSettings(shopID string, pageable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.RestaurantSettings) (common.BulkImport, error)
-	ListRestaurantSettingsByCode(shopID string, code string, pagable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error)
+	CreateRestaurantSettings(ctx context.Context, shopID string, authUsername string, doc models.RestaurantSettings) (string, error)
+	UpdateRestaurantSettings(ctx context.Context, shopID string, guid string, authUsername string, doc models.RestaurantSettings) error
+	DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	DeleteRestaurantSettings(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoRestaurantSettings(ctx context.Context, shopID string, guid string) (models.RestaurantSettingsInfo, error)
+	SearchRestaurantSettings(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.RestaurantSettings) (common.BulkImport, error)
+	ListRestaurantSettingsByCode(ctx context.Context, shopID string, code string, pagable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error)
 
 	GetModuleName() string
 }
@@ -54,13 +55,13 @@ func NewRestaurantSettingsService(repo IRestaurantSettingsRepository, syncCacheR
 	return insSvc
 }
 
-func (svc RestaurantSettingsService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc RestaurantSettingsService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc RestaurantSettingsService) CreateRestaurantSettings(shopID string, authUsername string, doc models.RestaurantSettings) (string, error) {
+func (svc RestaurantSettingsService) CreateRestaurantSettings(ctx context.Context, shopID string, authUsername string, doc models.RestaurantSettings) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -96,9 +97,9 @@ func (svc RestaurantSettingsService) CreateRestaurantSettings(shopID string, aut
 	return newGuidFixed, nil
 }
 
-func (svc RestaurantSettingsService) UpdateRestaurantSettings(shopID string, guid string, authUsername string, doc models.RestaurantSettings) error {
+func (svc RestaurantSettingsService) UpdateRestaurantSettings(ctx context.Context, shopID string, guid string, authUsername string, doc models.RestaurantSettings) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -129,9 +130,9 @@ func (svc RestaurantSettingsService) UpdateRestaurantSettings(shopID string, gui
 	return nil
 }
 
-func (svc RestaurantSettingsService) DeleteRestaurantSettings(shopID string, guid string, authUsername string) error {
+func (svc RestaurantSettingsService) DeleteRestaurantSettings(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -145,9 +146,9 @@ func (svc RestaurantSettingsService) DeleteRestaurantSettings(shopID string, gui
 	return nil
 }
 
-func (svc RestaurantSettingsService) DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc RestaurantSettingsService) DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -162,9 +163,9 @@ func (svc RestaurantSettingsService) DeleteByGUIDs(shopID string, authUsername s
 	return nil
 }
 
-func (svc RestaurantSettingsService) InfoRestaurantSettings(shopID string, guid string) (models.RestaurantSettingsInfo, error) {
+func (svc RestaurantSettingsService) InfoRestaurantSettings(ctx context.Context, shopID string, guid string) (models.RestaurantSettingsInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -180,9 +181,9 @@ func (svc RestaurantSettingsService) InfoRestaurantSettings(shopID string, guid
 	return findDoc.RestaurantSettingsInfo, nil
 }
 
-func (svc RestaurantSettingsService) ListRestaurantSettingsByCode(shopID string, code string, pagable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error) {
+func (svc RestaurantSettingsService) ListRestaurantSettingsByCode(ctx context.Context, shopID string, code string, pagable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, map[string]interface{}{"code": code}, []string{"body"}, pagable)
@@ -195,9 +196,9 @@ func (svc RestaurantSettingsService) ListRestaurantSettingsByCode(shopID string,
 
 }
 
-func (svc RestaurantSettingsService) SearchRestaurantSettings(shopID string, pageable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error) {
+func (svc RestaurantSettingsService) SearchRestaurantSettings(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.RestaurantSettingsInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -213,9 +214,9 @@ func (svc RestaurantSettingsService) SearchRestaurantSettings(shopID string, pag
 	return docList, pagination, nil
 }
 
-func (svc RestaurantSettingsService) SaveInBatch(shopID string, authUsername string, dataList []models.RestaurantSettings) (common.BulkImport, error) {
+func (svc RestaurantSettingsService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.RestaurantSettings) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadCategoryList, payloadDuplicateCategoryList := importdata.FilterDuplicate[models.RestaurantSettings](dataList, svc.getDocIDKey)
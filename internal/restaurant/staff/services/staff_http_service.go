@@ -11,6 +11,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IStaffHttpService interface {
-	CreateStaff(shopID string, authUsername string, doc models.Staff) (string, error)
-	UpdateStaff(shopID string, guid string, authUsername string, doc models.Staff) error
-	DeleteStaff(shopID string, guid string, authUsername string) error
-	DeleteStaffByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStaff(shopID string, guid string) (models.StaffInfo, error)
-	SearchStaff(shopID string, pageable micromodels.Pageable) ([]models.StaffInfo, mongopagination.PaginationData, error)
-	SearchStaffStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.StaffInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Staff) (common.BulkImport, error)
+	CreateStaff(ctx context.Context, shopID string, authUsername string, doc models.Staff) (string, error)
+	UpdateStaff(ctx context.Context, shopID string, guid string, authUsername string, doc models.Staff) error
+	DeleteStaff(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStaffByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStaff(ctx context.Context, shopID string, guid string) (models.StaffInfo, error)
+	SearchStaff(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.StaffInfo, mongopagination.PaginationData, error)
+	SearchStaffStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.StaffInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Staff) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewStaffHttpService(repo repositories.IStaffRepository, syncCacheRepo maste
 	return insSvc
 }
 
-func (svc StaffHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StaffHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StaffHttpService) CreateStaff(shopID string, authUsername string, doc models.Staff) (string, error) {
+func (svc StaffHttpService) CreateStaff(ctx context.Context, shopID string, authUsername string, doc models.Staff) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc StaffHttpService) CreateStaff(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc StaffHttpService) UpdateStaff(shopID string, guid string, authUsername string, doc models.Staff) error {
+func (svc StaffHttpService) UpdateStaff(ctx context.Context, shopID string, guid string, authUsername string, doc models.Staff) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -126,9 +127,9 @@ func (svc StaffHttpService) UpdateStaff(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc StaffHttpService) DeleteStaff(shopID string, guid string, authUsername string) error {
+func (svc StaffHttpService) DeleteStaff(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,9 +152,9 @@ func (svc StaffHttpService) DeleteStaff(shopID string, guid string, authUsername
 	return nil
 }
 
-func (svc StaffHttpService) DeleteStaffByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StaffHttpService) DeleteStaffByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -168,9 +169,9 @@ func (svc StaffHttpService) DeleteStaffByGUIDs(shopID string, authUsername strin
 	return nil
 }
 
-func (svc StaffHttpService) InfoStaff(shopID string, guid string) (models.StaffInfo, error) {
+func (svc StaffHttpService) InfoStaff(ctx context.Context, shopID string, guid string) (models.StaffInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -187,9 +188,9 @@ func (svc StaffHttpService) InfoStaff(shopID string, guid string) (models.StaffI
 
 }
 
-func (svc StaffHttpService) SearchStaff(shopID string, pageable micromodels.Pageable) ([]models.StaffInfo, mongopagination.PaginationData, error) {
+func (svc StaffHttpService) SearchStaff(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.StaffInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -206,9 +207,9 @@ func (svc StaffHttpService) SearchStaff(shopID string, pageable micromodels.Page
 	return docList, pagination, nil
 }
 
-func (svc StaffHttpService) SearchStaffStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.StaffInfo, int, error) {
+func (svc StaffHttpService) SearchStaffStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.StaffInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -227,9 +228,9 @@ func (svc StaffHttpService) SearchStaffStep(shopID string, langCode string, page
 	return docList, total, nil
 }
 
-func (svc StaffHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Staff) (common.BulkImport, error) {
+func (svc StaffHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Staff) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Staff](dataList, svc.getDocIDKey)
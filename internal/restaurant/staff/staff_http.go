@@ -77,7 +77,7 @@ func (h StaffHttp) CreateStaff(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateStaff(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateStaff(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h StaffHttp) UpdateStaff(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateStaff(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStaff(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h StaffHttp) DeleteStaff(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStaff(shopID, id, authUsername)
+	err := h.svc.DeleteStaff(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h StaffHttp) DeleteStaffByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteStaffByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStaffByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h StaffHttp) InfoStaff(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Staff %v", id)
-	doc, err := h.svc.InfoStaff(shopID, id)
+	doc, err := h.svc.InfoStaff(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -253,7 +253,7 @@ func (h StaffHttp) SearchStaffPage(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchStaff(shopID, pageable)
+	docList, pagination, err := h.svc.SearchStaff(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -288,7 +288,7 @@ func (h StaffHttp) SearchStaffLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchStaffStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchStaffStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -328,7 +328,7 @@ func (h StaffHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
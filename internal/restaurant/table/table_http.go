@@ -72,7 +72,7 @@ func (h TableHttp) CreateTable(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateTable(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateTable(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -112,7 +112,7 @@ func (h TableHttp) UpdateTable(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateTable(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateTable(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -143,7 +143,7 @@ func (h TableHttp) DeleteTable(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteTable(shopID, id, authUsername)
+	err := h.svc.DeleteTable(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -182,7 +182,7 @@ func (h TableHttp) DeleteByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -220,7 +220,7 @@ func (h TableHttp) DeleteTableByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteTableByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteTableByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -250,7 +250,7 @@ func (h TableHttp) InfoTable(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Table %v", id)
-	doc, err := h.svc.InfoTable(shopID, id)
+	doc, err := h.svc.InfoTable(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -291,7 +291,7 @@ func (h TableHttp) SearchTable(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchTable(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchTable(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -331,7 +331,7 @@ func (h TableHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -378,7 +378,7 @@ func (h TableHttp) SaveXOrder(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.SaveXOrder(shopID, authUsername, req)
+	err = h.svc.SaveXOrder(ctx.Context(), shopID, authUsername, req)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
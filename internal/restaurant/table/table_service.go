@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -19,15 +20,15 @@ import (
 )
 
 type ITableService interface {
-	CreateTable(shopID string, authUsername string, doc models.Table) (string, error)
-	UpdateTable(shopID string, guid string, authUsername string, doc models.Table) error
-	DeleteTable(shopID string, guid string, authUsername string) error
-	DeleteTableByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoTable(shopID string, guid string) (models.TableInfo, error)
-	SearchTable(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TableInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Table) (common.BulkImport, error)
-	SaveXOrder(shopID string, authUsername string, xOrders []models.XOrderRequest) error
+	CreateTable(ctx context.Context, shopID string, authUsername string, doc models.Table) (string, error)
+	UpdateTable(ctx context.Context, shopID string, guid string, authUsername string, doc models.Table) error
+	DeleteTable(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteTableByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoTable(ctx context.Context, shopID string, guid string) (models.TableInfo, error)
+	SearchTable(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TableInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Table) (common.BulkImport, error)
+	SaveXOrder(ctx context.Context, shopID string, authUsername string, xOrders []models.XOrderRequest) error
 
 	GetModuleName() string
 }
@@ -54,12 +55,12 @@ func NewTableService(repo ITableRepository, syncCacheRepo mastersync.IMasterSync
 	return &insSvc
 }
 
-func (svc TableService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc TableService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc TableService) CreateTable(shopID string, authUsername string, doc models.Table) (string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc TableService) CreateTable(ctx context.Context, shopID string, authUsername string, doc models.Table) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByTwoColumns(ctx, shopID, "number", doc.Number, "groupnumber", doc.GroupNumber)
@@ -93,9 +94,9 @@ func (svc TableService) CreateTable(shopID string, authUsername string, doc mode
 	return newGuidFixed, nil
 }
 
-func (svc TableService) UpdateTable(shopID string, guid string, authUsername string, doc models.Table) error {
+func (svc TableService) UpdateTable(ctx context.Context, shopID string, guid string, authUsername string, doc models.Table) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -124,9 +125,9 @@ func (svc TableService) UpdateTable(shopID string, guid string, authUsername str
 	return nil
 }
 
-func (svc TableService) DeleteTable(shopID string, guid string, authUsername string) error {
+func (svc TableService) DeleteTable(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -140,9 +141,9 @@ func (svc TableService) DeleteTable(shopID string, guid string, authUsername str
 	return nil
 }
 
-func (svc TableService) DeleteTableByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc TableService) DeleteTableByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -157,9 +158,9 @@ func (svc TableService) DeleteTableByGUIDs(shopID string, authUsername string, G
 	return nil
 }
 
-func (svc TableService) DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc TableService) DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -174,9 +175,9 @@ func (svc TableService) DeleteByGUIDs(shopID string, authUsername string, GUIDs
 	return nil
 }
 
-func (svc TableService) InfoTable(shopID string, guid string) (models.TableInfo, error) {
+func (svc TableService) InfoTable(ctx context.Context, shopID string, guid string) (models.TableInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -193,9 +194,9 @@ func (svc TableService) InfoTable(shopID string, guid string) (models.TableInfo,
 
 }
 
-func (svc TableService) SearchTable(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TableInfo, mongopagination.PaginationData, error) {
+func (svc TableService) SearchTable(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TableInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -216,9 +217,9 @@ func (svc TableService) SearchTable(shopID string, filters map[string]interface{
 	return docList, pagination, nil
 }
 
-func (svc TableService) SaveInBatch(shopID string, authUsername string, dataList []models.Table) (common.BulkImport, error) {
+func (svc TableService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Table) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadCategoryList, payloadDuplicateCategoryList := importdata.FilterDuplicate[models.Table](dataList, svc.getDocIDKey)
@@ -335,8 +336,8 @@ func (svc TableService) GetModuleName() string {
 	return "restaurant-table"
 }
 
-func (svc TableService) SaveXOrder(shopID string, authUsername string, xOrders []models.XOrderRequest) error {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc TableService) SaveXOrder(ctx context.Context, shopID string, authUsername string, xOrders []models.XOrderRequest) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	for _, xOrder := range xOrders {
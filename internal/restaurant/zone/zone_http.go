@@ -73,7 +73,7 @@ func (h ZoneHttp) CreateZone(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateZone(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateZone(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -113,7 +113,7 @@ func (h ZoneHttp) UpdateZone(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateZone(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateZone(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -144,7 +144,7 @@ func (h ZoneHttp) DeleteZone(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteZone(shopID, id, authUsername)
+	err := h.svc.DeleteZone(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -183,7 +183,7 @@ func (h ZoneHttp) DeleteByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -213,7 +213,7 @@ func (h ZoneHttp) InfoZone(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Zone %v", id)
-	doc, err := h.svc.InfoZone(shopID, id)
+	doc, err := h.svc.InfoZone(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -257,7 +257,7 @@ func (h ZoneHttp) InfoArray(ctx microservice.IContext) error {
 	}
 
 	// where to filter array
-	doc, err := h.svc.InfoWTFArray(shopID, docReq)
+	doc, err := h.svc.InfoWTFArray(ctx.Context(), shopID, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -296,7 +296,7 @@ func (h ZoneHttp) SearchZone(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchZone(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchZone(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -336,7 +336,7 @@ func (h ZoneHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
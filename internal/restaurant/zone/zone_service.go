@@ -10,6 +10,7 @@ import (
 	"smlaicloudplatform/internal/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,14 +21,14 @@ import (
 )
 
 type IZoneService interface {
-	CreateZone(shopID string, authUsername string, doc models.Zone) (string, error)
-	UpdateZone(shopID string, guid string, authUsername string, doc models.Zone) error
-	DeleteZone(shopID string, guid string, authUsername string) error
-	InfoZone(shopID string, guid string) (models.ZoneInfo, error)
-	InfoWTFArray(shopID string, codes []string) ([]interface{}, error)
-	SearchZone(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ZoneInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Zone) (common.BulkImport, error)
-	DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error
+	CreateZone(ctx context.Context, shopID string, authUsername string, doc models.Zone) (string, error)
+	UpdateZone(ctx context.Context, shopID string, guid string, authUsername string, doc models.Zone) error
+	DeleteZone(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoZone(ctx context.Context, shopID string, guid string) (models.ZoneInfo, error)
+	InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error)
+	SearchZone(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ZoneInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Zone) (common.BulkImport, error)
+	DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
 
 	GetModuleName() string
 }
@@ -53,13 +54,13 @@ func NewZoneService(repo IZoneRepository, syncCacheRepo mastersync.IMasterSyncCa
 	return insSvc
 }
 
-func (svc ZoneService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ZoneService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ZoneService) CreateZone(shopID string, authUsername string, doc models.Zone) (string, error) {
+func (svc ZoneService) CreateZone(ctx context.Context, shopID string, authUsername string, doc models.Zone) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -95,9 +96,9 @@ func (svc ZoneService) CreateZone(shopID string, authUsername string, doc models
 	return newGuidFixed, nil
 }
 
-func (svc ZoneService) UpdateZone(shopID string, guid string, authUsername string, doc models.Zone) error {
+func (svc ZoneService) UpdateZone(ctx context.Context, shopID string, guid string, authUsername string, doc models.Zone) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -128,9 +129,9 @@ func (svc ZoneService) UpdateZone(shopID string, guid string, authUsername strin
 	return nil
 }
 
-func (svc ZoneService) DeleteZone(shopID string, guid string, authUsername string) error {
+func (svc ZoneService) DeleteZone(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -144,9 +145,9 @@ func (svc ZoneService) DeleteZone(shopID string, guid string, authUsername strin
 	return nil
 }
 
-func (svc ZoneService) DeleteByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ZoneService) DeleteByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -161,9 +162,9 @@ func (svc ZoneService) DeleteByGUIDs(shopID string, authUsername string, GUIDs [
 	return nil
 }
 
-func (svc ZoneService) InfoZone(shopID string, guid string) (models.ZoneInfo, error) {
+func (svc ZoneService) InfoZone(ctx context.Context, shopID string, guid string) (models.ZoneInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -180,9 +181,9 @@ func (svc ZoneService) InfoZone(shopID string, guid string) (models.ZoneInfo, er
 
 }
 
-func (svc ZoneService) InfoWTFArray(shopID string, codes []string) ([]interface{}, error) {
+func (svc ZoneService) InfoWTFArray(ctx context.Context, shopID string, codes []string) ([]interface{}, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList := []interface{}{}
@@ -200,8 +201,8 @@ func (svc ZoneService) InfoWTFArray(shopID string, codes []string) ([]interface{
 	return docList, nil
 }
 
-func (svc ZoneService) SearchZone(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ZoneInfo, mongopagination.PaginationData, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc ZoneService) SearchZone(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ZoneInfo, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -218,9 +219,9 @@ func (svc ZoneService) SearchZone(shopID string, filters map[string]interface{},
 	return docList, pagination, nil
 }
 
-func (svc ZoneService) SaveInBatch(shopID string, authUsername string, dataList []models.Zone) (common.BulkImport, error) {
+func (svc ZoneService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Zone) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadCategoryList, payloadDuplicateCategoryList := importdata.FilterDuplicate[models.Zone](dataList, svc.getDocIDKey)
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const scheduleCollectionName = "schedules"
+
+// ScheduleDoc is schedule registered by the consumer, it is read by api replica which does not run the scheduler
+type ScheduleDoc struct {
+	ID                        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	microservice.ScheduleInfo `bson:"inline"`
+	Replica                   string    `json:"replica" bson:"replica"`
+	UpdatedAt                 time.Time `json:"updatedat" bson:"updatedat"`
+}
+
+func (ScheduleDoc) CollectionName() string {
+	return scheduleCollectionName
+}
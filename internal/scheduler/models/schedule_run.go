@@ -0,0 +1,19 @@
+package models
+
+import (
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const scheduleRunCollectionName = "scheduleRuns"
+
+// ScheduleRunDoc is run history of microservice schedules
+type ScheduleRunDoc struct {
+	ID                       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	microservice.ScheduleRun `bson:"inline"`
+}
+
+func (ScheduleRunDoc) CollectionName() string {
+	return scheduleRunCollectionName
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"smlaicloudplatform/internal/scheduler/models"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IScheduleRepository interface {
+	SaveSchedules(ctx context.Context, schedules []microservice.ScheduleInfo) error
+	FindAll(ctx context.Context) ([]models.ScheduleDoc, error)
+}
+
+// ScheduleRepository keep schedules registered by the consumer, it implement microservice.IScheduleRegistry
+type ScheduleRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewScheduleRepository(pst microservice.IPersisterMongo) *ScheduleRepository {
+	return &ScheduleRepository{
+		pst: pst,
+	}
+}
+
+// SaveSchedules upsert the schedules by name and remove schedules which are no longer registered
+func (repo ScheduleRepository) SaveSchedules(ctx context.Context, schedules []microservice.ScheduleInfo) error {
+	replica, _ := os.Hostname()
+	updatedAt := time.Now()
+
+	names := []string{}
+	for _, schedule := range schedules {
+		names = append(names, schedule.Name)
+
+		err := repo.pst.Update(
+			ctx,
+			&models.ScheduleDoc{},
+			bson.M{"name": schedule.Name},
+			bson.M{"$set": models.ScheduleDoc{
+				ScheduleInfo: schedule,
+				Replica:      replica,
+				UpdatedAt:    updatedAt,
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return repo.pst.Delete(ctx, &models.ScheduleDoc{}, bson.M{"name": bson.M{"$nin": names}})
+}
+
+// FindAll return schedules order by name
+func (repo ScheduleRepository) FindAll(ctx context.Context) ([]models.ScheduleDoc, error) {
+	docList := []models.ScheduleDoc{}
+	err := repo.pst.Find(ctx, &models.ScheduleDoc{}, bson.M{}, &docList, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return []models.ScheduleDoc{}, err
+	}
+
+	return docList, nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/scheduler/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IScheduleRunRepository interface {
+	SaveRun(ctx context.Context, run microservice.ScheduleRun) error
+	FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ScheduleRunDoc, mongopagination.PaginationData, error)
+}
+
+// ScheduleRunRepository keep run history of microservice schedules, it implement microservice.IScheduleHistory
+type ScheduleRunRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewScheduleRunRepository(pst microservice.IPersisterMongo) *ScheduleRunRepository {
+	return &ScheduleRunRepository{
+		pst: pst,
+	}
+}
+
+// SaveRun insert the run when it is started and replace it when it is finished
+func (repo ScheduleRunRepository) SaveRun(ctx context.Context, run microservice.ScheduleRun) error {
+	return repo.pst.Update(
+		microservice.WithSystemAdmin(ctx),
+		&models.ScheduleRunDoc{},
+		bson.M{"runid": run.RunID},
+		bson.M{"$set": run},
+		options.Update().SetUpsert(true),
+	)
+}
+
+// FindPage return runs, the latest run first when sort is not requested
+func (repo ScheduleRunRepository) FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ScheduleRunDoc, mongopagination.PaginationData, error) {
+	filterQuery := bson.M{}
+	for key, value := range filters {
+		filterQuery[key] = value
+	}
+
+	if len(pageable.Sorts) == 0 {
+		pageable.Sorts = []micromodels.KeyInt{{Key: "scheduledat", Value: -1}}
+	}
+
+	docList := []models.ScheduleRunDoc{}
+	pagination, err := repo.pst.FindPage(microservice.WithSystemAdmin(ctx), &models.ScheduleRunDoc{}, filterQuery, pageable, &docList)
+	if err != nil {
+		return []models.ScheduleRunDoc{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
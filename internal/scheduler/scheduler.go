@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/scheduler/models"
+	"smlaicloudplatform/internal/scheduler/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// scheduleRunRetention is how long run history is kept
+const scheduleRunRetention = 90 * 24 * time.Hour
+
+// InitScheduler keep run history and registered schedules of microservice schedules in mongo
+func InitScheduler(ms *microservice.Microservice, cfg config.IConfig) {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	ms.Scheduler().SetHistory(repositories.NewScheduleRunRepository(pst))
+	ms.Scheduler().SetRegistry(repositories.NewScheduleRepository(pst))
+}
+
+// MigrationDatabase create index of schedules, index to find runs and TTL index which remove old runs
+func MigrationDatabase(ms *microservice.Microservice, cfg config.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	scheduleCollection, err := pst.Exec(context.Background(), &models.ScheduleDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = scheduleCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("schedule_name").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.ScheduleRunDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "runid", Value: 1}},
+			Options: options.Index().SetName("schedulerun_runid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}, {Key: "scheduledat", Value: -1}},
+			Options: options.Index().SetName("schedulerun_name_scheduledat"),
+		},
+		{
+			Keys:    bson.D{{Key: "startedat", Value: 1}},
+			Options: options.Index().SetName("schedulerun_startedat").SetExpireAfterSeconds(int32(scheduleRunRetention / time.Second)),
+		},
+	})
+	return err
+}
@@ -75,7 +75,7 @@ func (h BranchHttp) CreateBranch(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateBranch(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateBranch(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -120,7 +120,7 @@ func (h BranchHttp) UpdateBranch(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateBranch(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateBranch(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -151,7 +151,7 @@ func (h BranchHttp) DeleteBranch(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteBranch(shopID, id, authUsername)
+	err := h.svc.DeleteBranch(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -190,7 +190,7 @@ func (h BranchHttp) DeleteBranchByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteBranchByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteBranchByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -220,7 +220,7 @@ func (h BranchHttp) InfoBranch(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Branch %v", id)
-	doc, err := h.svc.InfoBranch(shopID, id)
+	doc, err := h.svc.InfoBranch(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -252,7 +252,7 @@ func (h BranchHttp) SearchBranchPage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchBranch(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchBranch(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -287,7 +287,7 @@ func (h BranchHttp) SearchBranchStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchBranchStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchBranchStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -9,6 +9,7 @@ import (
 	"smlaicloudplatform/internal/shop/branch/models"
 	"smlaicloudplatform/internal/shop/branch/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -18,13 +19,13 @@ import (
 )
 
 type IBranchHttpService interface {
-	CreateBranch(shopID string, authUsername string, doc models.Branch) (string, error)
-	UpdateBranch(shopID string, guid string, authUsername string, doc models.Branch) error
-	DeleteBranch(shopID string, guid string, authUsername string) error
-	DeleteBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoBranch(shopID string, guid string) (models.BranchInfo, error)
-	SearchBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfo, mongopagination.PaginationData, error)
-	SearchBranchStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BranchInfo, int, error)
+	CreateBranch(ctx context.Context, shopID string, authUsername string, doc models.Branch) (string, error)
+	UpdateBranch(ctx context.Context, shopID string, guid string, authUsername string, doc models.Branch) error
+	DeleteBranch(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoBranch(ctx context.Context, shopID string, guid string) (models.BranchInfo, error)
+	SearchBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfo, mongopagination.PaginationData, error)
+	SearchBranchStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BranchInfo, int, error)
 
 	GetModuleName() string
 }
@@ -52,13 +53,13 @@ func NewBranchHttpService(repo repositories.IBranchRepository, syncCacheRepo mas
 	return insSvc
 }
 
-func (svc BranchHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc BranchHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc BranchHttpService) CreateBranch(shopID string, authUsername string, doc models.Branch) (string, error) {
+func (svc BranchHttpService) CreateBranch(ctx context.Context, shopID string, authUsername string, doc models.Branch) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -92,9 +93,9 @@ func (svc BranchHttpService) CreateBranch(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc BranchHttpService) UpdateBranch(shopID string, guid string, authUsername string, doc models.Branch) error {
+func (svc BranchHttpService) UpdateBranch(ctx context.Context, shopID string, guid string, authUsername string, doc models.Branch) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -123,9 +124,9 @@ func (svc BranchHttpService) UpdateBranch(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc BranchHttpService) DeleteBranch(shopID string, guid string, authUsername string) error {
+func (svc BranchHttpService) DeleteBranch(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -148,9 +149,9 @@ func (svc BranchHttpService) DeleteBranch(shopID string, guid string, authUserna
 	return nil
 }
 
-func (svc BranchHttpService) DeleteBranchByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc BranchHttpService) DeleteBranchByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -165,9 +166,9 @@ func (svc BranchHttpService) DeleteBranchByGUIDs(shopID string, authUsername str
 	return nil
 }
 
-func (svc BranchHttpService) InfoBranch(shopID string, guid string) (models.BranchInfo, error) {
+func (svc BranchHttpService) InfoBranch(ctx context.Context, shopID string, guid string) (models.BranchInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -184,9 +185,9 @@ func (svc BranchHttpService) InfoBranch(shopID string, guid string) (models.Bran
 
 }
 
-func (svc BranchHttpService) SearchBranch(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfo, mongopagination.PaginationData, error) {
+func (svc BranchHttpService) SearchBranch(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.BranchInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -202,9 +203,9 @@ func (svc BranchHttpService) SearchBranch(shopID string, filters map[string]inte
 	return docList, pagination, nil
 }
 
-func (svc BranchHttpService) SearchBranchStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BranchInfo, int, error) {
+func (svc BranchHttpService) SearchBranchStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.BranchInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -77,7 +77,7 @@ func (h EmployeeHttp) CreateEmployee(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateEmployee(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateEmployee(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h EmployeeHttp) UpdateEmployee(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateEmployee(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateEmployee(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -153,7 +153,7 @@ func (h EmployeeHttp) DeleteEmployee(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteEmployee(shopID, id, authUsername)
+	err := h.svc.DeleteEmployee(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -192,7 +192,7 @@ func (h EmployeeHttp) DeleteEmployeeByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteEmployeeByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteEmployeeByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -222,7 +222,7 @@ func (h EmployeeHttp) InfoEmployee(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Employee %v", id)
-	doc, err := h.svc.InfoEmployee(shopID, id)
+	doc, err := h.svc.InfoEmployee(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -252,7 +252,7 @@ func (h EmployeeHttp) InfoEmployeeByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoEmployeeByCode(shopID, code)
+	doc, err := h.svc.InfoEmployeeByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -283,7 +283,7 @@ func (h EmployeeHttp) SearchEmployeePage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchEmployee(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchEmployee(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -318,7 +318,7 @@ func (h EmployeeHttp) SearchEmployeeStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchEmployeeStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchEmployeeStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +359,7 @@ func (h EmployeeHttp) UpdatePassword(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePassword(shopID, authUsername, userPwdReq)
+	err = h.svc.UpdatePassword(ctx.Context(), shopID, authUsername, userPwdReq)
 
 	if err != nil {
 		ctx.Response(http.StatusBadRequest, common.ApiResponse{
@@ -9,6 +9,7 @@ import (
 	"smlaicloudplatform/internal/shop/employee/models"
 	"smlaicloudplatform/internal/shop/employee/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -18,15 +19,15 @@ import (
 )
 
 type IEmployeeHttpService interface {
-	CreateEmployee(shopID string, authUsername string, doc models.EmployeeRequestRegister) (string, error)
-	UpdateEmployee(shopID string, guid string, authUsername string, doc models.EmployeeRequestUpdate) error
-	UpdatePassword(shopID string, authUsername string, emp models.EmployeeRequestPassword) error
-	DeleteEmployee(shopID string, guid string, authUsername string) error
-	DeleteEmployeeByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoEmployee(shopID string, guid string) (models.EmployeeInfo, error)
-	InfoEmployeeByCode(shopID string, code string) (models.EmployeeInfo, error)
-	SearchEmployee(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.EmployeeInfo, mongopagination.PaginationData, error)
-	SearchEmployeeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.EmployeeInfo, int, error)
+	CreateEmployee(ctx context.Context, shopID string, authUsername string, doc models.EmployeeRequestRegister) (string, error)
+	UpdateEmployee(ctx context.Context, shopID string, guid string, authUsername string, doc models.EmployeeRequestUpdate) error
+	UpdatePassword(ctx context.Context, shopID string, authUsername string, emp models.EmployeeRequestPassword) error
+	DeleteEmployee(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteEmployeeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoEmployee(ctx context.Context, shopID string, guid string) (models.EmployeeInfo, error)
+	InfoEmployeeByCode(ctx context.Context, shopID string, code string) (models.EmployeeInfo, error)
+	SearchEmployee(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.EmployeeInfo, mongopagination.PaginationData, error)
+	SearchEmployeeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.EmployeeInfo, int, error)
 
 	GetModuleName() string
 }
@@ -55,13 +56,13 @@ func NewEmployeeHttpService(repo repositories.IEmployeeRepository, syncCacheRepo
 	return insSvc
 }
 
-func (svc EmployeeHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc EmployeeHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc EmployeeHttpService) CreateEmployee(shopID string, authUsername string, doc models.EmployeeRequestRegister) (string, error) {
+func (svc EmployeeHttpService) CreateEmployee(ctx context.Context, shopID string, authUsername string, doc models.EmployeeRequestRegister) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -102,9 +103,9 @@ func (svc EmployeeHttpService) CreateEmployee(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc EmployeeHttpService) UpdateEmployee(shopID string, guid string, authUsername string, doc models.EmployeeRequestUpdate) error {
+func (svc EmployeeHttpService) UpdateEmployee(ctx context.Context, shopID string, guid string, authUsername string, doc models.EmployeeRequestUpdate) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -143,9 +144,9 @@ func (svc EmployeeHttpService) UpdateEmployee(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc EmployeeHttpService) UpdatePassword(shopID string, authUsername string, emp models.EmployeeRequestPassword) error {
+func (svc EmployeeHttpService) UpdatePassword(ctx context.Context, shopID string, authUsername string, emp models.EmployeeRequestPassword) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	userFind, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", emp.Code)
@@ -185,9 +186,9 @@ func (svc EmployeeHttpService) UpdatePassword(shopID string, authUsername string
 	return nil
 }
 
-func (svc EmployeeHttpService) DeleteEmployee(shopID string, guid string, authUsername string) error {
+func (svc EmployeeHttpService) DeleteEmployee(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -210,9 +211,9 @@ func (svc EmployeeHttpService) DeleteEmployee(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc EmployeeHttpService) DeleteEmployeeByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc EmployeeHttpService) DeleteEmployeeByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -227,9 +228,9 @@ func (svc EmployeeHttpService) DeleteEmployeeByGUIDs(shopID string, authUsername
 	return nil
 }
 
-func (svc EmployeeHttpService) InfoEmployeeByCode(shopID string, code string) (models.EmployeeInfo, error) {
+func (svc EmployeeHttpService) InfoEmployeeByCode(ctx context.Context, shopID string, code string) (models.EmployeeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -246,9 +247,9 @@ func (svc EmployeeHttpService) InfoEmployeeByCode(shopID string, code string) (m
 
 }
 
-func (svc EmployeeHttpService) InfoEmployee(shopID string, guid string) (models.EmployeeInfo, error) {
+func (svc EmployeeHttpService) InfoEmployee(ctx context.Context, shopID string, guid string) (models.EmployeeInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -264,9 +265,9 @@ func (svc EmployeeHttpService) InfoEmployee(shopID string, guid string) (models.
 	return findDoc.EmployeeInfo, nil
 }
 
-func (svc EmployeeHttpService) SearchEmployee(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.EmployeeInfo, mongopagination.PaginationData, error) {
+func (svc EmployeeHttpService) SearchEmployee(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.EmployeeInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -284,9 +285,9 @@ func (svc EmployeeHttpService) SearchEmployee(shopID string, filters map[string]
 	return docList, pagination, nil
 }
 
-func (svc EmployeeHttpService) SearchEmployeeStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.EmployeeInfo, int, error) {
+func (svc EmployeeHttpService) SearchEmployeeStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.EmployeeInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -48,6 +48,8 @@ type ShopSettings struct {
 	InquiryTypeSale     int              `json:"inquirytypesale" bson:"inquirytypesale"`
 	InquiryTypePurchase int              `json:"inquirytypepurchase" bson:"inquirytypepurchase"`
 	LanguageConfigs     []LanguageConfig `json:"languageconfigs" bson:"languageconfigs"`
+	// RequireTwoFactor force owner and admin to login with two factor code before they select the shop
+	RequireTwoFactor bool `json:"requiretwofactor" bson:"requiretwofactor"`
 }
 
 type LanguageConfig struct {
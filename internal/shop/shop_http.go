@@ -1,10 +1,9 @@
 package shop
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
-	auth_model "smlaicloudplatform/internal/authentication/models"
 	"smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/logger"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
@@ -16,6 +15,8 @@ import (
 	company_repositories "smlaicloudplatform/internal/organization/company/repositories"
 	company_services "smlaicloudplatform/internal/organization/company/services"
 	deparment_repositories "smlaicloudplatform/internal/organization/department/repositories"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/internal/shop/models"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/pkg/microservice"
@@ -56,6 +57,7 @@ func NewShopHttp(ms *microservice.Microservice, cfg config.IConfig) ShopHttp {
 	service := NewShopService(repo, shopUserRepo, utils.NewGUID, ms.TimeNow)
 
 	authService := microservice.NewAuthService(ms.Cacher(cfg.CacherConfig()), 24*3*time.Hour, 24*30*time.Hour)
+	rbac.InitPermissionService(ms, cfg)
 
 	repoBrach := company_repositories.NewCompanyRepository(pst)
 
@@ -90,9 +92,9 @@ func (h ShopHttp) RegisterHttp() {
 	h.ms.GET("/shop/:id", h.InfoShop)
 	// h.ms.GET("/shop", h.SearchShop)
 
-	h.ms.POST("/shop", h.CreateShop, h.authService.MWFuncWithShop(h.ms.Cacher(h.cfg.CacherConfig())))
-	h.ms.PUT("/shop/:id", h.UpdateShop)
-	h.ms.DELETE("/shop/:id", h.DeleteShop)
+	h.ms.POST("/shop", h.CreateShop, h.authService.MWFuncWithShop(h.ms.Cacher(h.cfg.CacherConfig())), h.ms.SkipPermission())
+	h.ms.PUT("/shop/:id", h.UpdateShop, h.ms.RequirePermission(rbacmodels.PermissionShopUpdate))
+	h.ms.DELETE("/shop/:id", h.DeleteShop, h.ms.RequirePermission(rbacmodels.PermissionShopDelete))
 }
 
 // Create Shop On login  godoc
@@ -146,7 +148,7 @@ func (h ShopHttp) CreateShop(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.initialShop(shopID, authUsername, *shopPayload)
+	err = h.initialShop(ctx.Context(), shopID, authUsername, *shopPayload)
 
 	if err != nil {
 		err2 := h.service.DeleteShop(shopID, authUsername)
@@ -169,7 +171,7 @@ func (h ShopHttp) CreateShop(ctx microservice.IContext) error {
 
 	return nil
 }
-func (h ShopHttp) initialShop(shopID string, authUsername string, shopReq models.ShopRequest) (err error) {
+func (h ShopHttp) initialShop(ctx context.Context, shopID string, authUsername string, shopReq models.ShopRequest) (err error) {
 
 	businessTypeDefault := businesstype_models.BusinessType{}
 
@@ -199,7 +201,7 @@ func (h ShopHttp) initialShop(shopID string, authUsername string, shopReq models
 		}
 	}
 
-	businessTypeGUIDFixed, err := h.servicebusinessType.CreateBusinessType(shopID, authUsername, businessTypeDefault)
+	businessTypeGUIDFixed, err := h.servicebusinessType.CreateBusinessType(ctx, shopID, authUsername, businessTypeDefault)
 
 	if err != nil {
 		return err
@@ -234,10 +236,10 @@ func (h ShopHttp) initialShop(shopID string, authUsername string, shopReq models
 
 	companyDefault.Code = "00000"
 
-	companyGUIDFixed, err := h.serviceCompany.CreateCompany(shopID, authUsername, companyDefault)
+	companyGUIDFixed, err := h.serviceCompany.CreateCompany(ctx, shopID, authUsername, companyDefault)
 
 	if err != nil {
-		err = h.servicebusinessType.DeleteBusinessType(shopID, businessTypeGUIDFixed, authUsername)
+		err = h.servicebusinessType.DeleteBusinessType(ctx, shopID, businessTypeGUIDFixed, authUsername)
 		if err != nil {
 			logger.GetLogger().Error("HTTP:: Error Rollback BusinessType " + err.Error())
 		}
@@ -264,17 +266,17 @@ func (h ShopHttp) initialShop(shopID string, authUsername string, shopReq models
 		},
 	}
 
-	_, err = h.serviceWarehouse.CreateWarehouse(shopID, authUsername, warehouseDefault)
+	_, err = h.serviceWarehouse.CreateWarehouse(ctx, shopID, authUsername, warehouseDefault)
 
 	if err != nil {
 
-		err = h.serviceCompany.DeleteCompany(shopID, companyGUIDFixed, authUsername)
+		err = h.serviceCompany.DeleteCompany(ctx, shopID, companyGUIDFixed, authUsername)
 
 		if err != nil {
 			logger.GetLogger().Error("HTTP:: Error Rollback Company " + err.Error())
 		}
 
-		err = h.servicebusinessType.DeleteBusinessType(shopID, businessTypeGUIDFixed, authUsername)
+		err = h.servicebusinessType.DeleteBusinessType(ctx, shopID, businessTypeGUIDFixed, authUsername)
 
 		if err != nil {
 			logger.GetLogger().Error("HTTP:: Error Rollback BusinessType " + err.Error())
@@ -310,15 +312,6 @@ func (h ShopHttp) UpdateShop(ctx microservice.IContext) error {
 		return err
 	}
 
-	if userInfo.Role != auth_model.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	err = h.service.UpdateShop(id, authUsername, *shopRequest)
 
 	if err != nil {
@@ -352,15 +345,6 @@ func (h ShopHttp) DeleteShop(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	if userInfo.Role != auth_model.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	err := h.service.DeleteShop(id, authUsername)
 
 	if err != nil {
@@ -186,3 +186,12 @@ func (m *ShopUserRepositoryMock) FindUserProfileByUsernames(ctx context.Context,
 	args := m.Called(ctx, usernames)
 	return args.Get(0).([]auth_model.UserProfile), args.Error(1)
 }
+
+type PermissionCheckerMock struct {
+	mock.Mock
+}
+
+func (m *PermissionCheckerMock) HasPermission(ctx context.Context, userInfo micromodels.UserInfo, permission string) (bool, error) {
+	args := m.Called(userInfo, permission)
+	return args.Bool(0), args.Error(1)
+}
@@ -2,11 +2,12 @@ package shop
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"smlaicloudplatform/internal/authentication/models"
 	"smlaicloudplatform/internal/config"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/pkg/microservice"
 )
@@ -22,7 +23,7 @@ func NewShopMemberHttp(ms *microservice.Microservice, cfg config.IConfig) *ShopM
 
 	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
 	repo := NewShopUserRepository(pst)
-	svc := NewShopUserService(repo)
+	svc := NewShopUserService(repo, rbac.InitPermissionService(ms, cfg))
 	return &ShopMemberHttp{
 		svc: svc,
 		ms:  ms,
@@ -30,12 +31,12 @@ func NewShopMemberHttp(ms *microservice.Microservice, cfg config.IConfig) *ShopM
 }
 
 func (h *ShopMemberHttp) RegisterHttp() {
-	h.ms.GET("/user/permissions", h.ListShopUser)
-	h.ms.GET("/shop/users", h.ListUserInShop)
+	h.ms.GET("/user/permissions", h.ListShopUser, h.ms.RequirePermission(rbacmodels.PermissionShopUserRead))
+	h.ms.GET("/shop/users", h.ListUserInShop, h.ms.RequirePermission(rbacmodels.PermissionShopUserRead))
 
-	h.ms.PUT("/shop/permission", h.SaveUserPermissionShop)
-	h.ms.GET("/shop/permission/:username", h.InfoShopUser)
-	h.ms.DELETE("/shop/permission/:username", h.DeleteUserPermissionShop)
+	h.ms.PUT("/shop/permission", h.SaveUserPermissionShop, h.ms.RequirePermission(rbacmodels.PermissionShopUserUpdate))
+	h.ms.GET("/shop/permission/:username", h.InfoShopUser, h.ms.RequirePermission(rbacmodels.PermissionShopUserRead))
+	h.ms.DELETE("/shop/permission/:username", h.DeleteUserPermissionShop, h.ms.RequirePermission(rbacmodels.PermissionShopUserDelete))
 }
 
 // List Shop User godoc
@@ -53,15 +54,6 @@ func (h ShopMemberHttp) ListUserInShop(ctx microservice.IContext) error {
 	userInfo := ctx.UserInfo()
 	shopID := userInfo.ShopID
 
-	if userInfo.Role != models.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	pageable := utils.GetPageable(ctx.QueryParam)
 
 	docList, pagination, err := h.svc.ListUserInShop(shopID, pageable)
@@ -93,15 +85,6 @@ func (h ShopMemberHttp) InfoShopUser(ctx microservice.IContext) error {
 	userInfo := ctx.UserInfo()
 	shopID := userInfo.ShopID
 
-	// if userInfo.Role != models.ROLE_OWNER {
-	// 	ctx.Response(http.StatusOK, &common.ApiResponse{
-	// 		Success: false,
-	// 		Message: "permission denied",
-	// 	})
-
-	// 	return errors.New("permission denied")
-	// }
-
 	username := ctx.Param("username")
 
 	if len(username) < 1 {
@@ -139,15 +122,6 @@ func (h ShopMemberHttp) ListShopUser(ctx microservice.IContext) error {
 	userInfo := ctx.UserInfo()
 	authUsername := userInfo.Username
 
-	if userInfo.Role != models.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	pageable := utils.GetPageable(ctx.QueryParam)
 
 	docList, pagination, err := h.svc.ListShopByUser(authUsername, pageable)
@@ -180,15 +154,6 @@ func (h ShopMemberHttp) SaveUserPermissionShop(ctx microservice.IContext) error
 	userInfo := ctx.UserInfo()
 	authUsername := userInfo.Username
 
-	if userInfo.Role != models.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	input := ctx.ReadInput()
 
 	userRoleReq := &models.UserRoleRequest{}
@@ -226,15 +191,6 @@ func (h ShopMemberHttp) DeleteUserPermissionShop(ctx microservice.IContext) erro
 	authUsername := userInfo.Username
 	shopID := userInfo.ShopID
 
-	if userInfo.Role != models.ROLE_OWNER {
-		ctx.Response(http.StatusOK, &common.ApiResponse{
-			Success: false,
-			Message: "permission denied",
-		})
-
-		return errors.New("permission denied")
-	}
-
 	username := ctx.Param("username")
 
 	if len(username) < 1 {
@@ -3,6 +3,7 @@ package shop
 import (
 	"context"
 	"smlaicloudplatform/internal/authentication/models"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
@@ -53,7 +54,7 @@ func (svc ShopUserRepository) Create(ctx context.Context, shopUser *models.ShopU
 
 func (svc ShopUserRepository) Update(ctx context.Context, id primitive.ObjectID, shopID string, username string, role models.UserRole) error {
 
-	err := svc.pst.Update(ctx, &models.ShopUser{}, bson.M{"_id": id, "shopid": shopID}, bson.M{"$set": bson.M{"username": username, "role": role}})
+	err := svc.pst.Update(ctx, &models.ShopUser{}, bson.M{"_id": id, "shopid": shopID}, rbacmodels.ReplaceDefaultRole(role, bson.M{"username": username}))
 
 	if err != nil {
 		return err
@@ -65,7 +66,7 @@ func (svc ShopUserRepository) Update(ctx context.Context, id primitive.ObjectID,
 func (svc ShopUserRepository) Save(ctx context.Context, shopID string, username string, role models.UserRole) error {
 
 	optUpdate := options.Update().SetUpsert(true)
-	err := svc.pst.Update(ctx, &models.ShopUser{}, bson.M{"shopid": shopID, "username": username}, bson.M{"$set": bson.M{"role": role}}, optUpdate)
+	err := svc.pst.Update(ctx, &models.ShopUser{}, bson.M{"shopid": shopID, "username": username}, rbacmodels.ReplaceDefaultRole(role, nil), optUpdate)
 
 	if err != nil {
 		return err
@@ -158,10 +159,11 @@ func (svc ShopUserRepository) FindByShopID(ctx context.Context, shopID string) (
 	return shopUsers, nil
 }
 
+// FindByUsername return the user in every shop
 func (svc ShopUserRepository) FindByUsername(ctx context.Context, username string) (*[]models.ShopUser, error) {
 	shopUsers := &[]models.ShopUser{}
 
-	err := svc.pst.Find(ctx, &models.ShopUser{}, bson.M{"username": username}, shopUsers)
+	err := svc.pst.Find(microservice.WithSystemAdmin(ctx), &models.ShopUser{}, bson.M{"username": username}, shopUsers)
 
 	if err != nil {
 		return nil, err
@@ -188,7 +190,7 @@ func (repo ShopUserRepository) FindByUsernamePage(ctx context.Context, username
 		}}})
 	}
 
-	aggPaginatedData, err := repo.pst.AggregatePage(ctx, &models.ShopUser{}, pageable,
+	aggPaginatedData, err := repo.pst.AggregatePage(microservice.WithSystemAdmin(ctx), &models.ShopUser{}, pageable,
 		bson.M{"$match": bson.M{
 			"username": username,
 			"deletedat": bson.M{
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"smlaicloudplatform/internal/authentication/models"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 
 	"github.com/smlsoft/mongopagination"
@@ -20,12 +22,14 @@ type IShopUserService interface {
 }
 
 type ShopUserService struct {
-	repo IShopUserRepository
+	repo       IShopUserRepository
+	permission microservice.IPermissionChecker
 }
 
-func NewShopUserService(shopUserRepo IShopUserRepository) ShopUserService {
+func NewShopUserService(shopUserRepo IShopUserRepository, permission microservice.IPermissionChecker) ShopUserService {
 	return ShopUserService{
-		repo: shopUserRepo,
+		repo:       shopUserRepo,
+		permission: permission,
 	}
 }
 
@@ -120,14 +124,17 @@ func (svc ShopUserService) SaveUserPermissionShop(shopID string, authUsername st
 		return errors.New("can not edit self permission")
 	}
 
-	authUser, err := svc.repo.FindByShopIDAndUsername(context.Background(), shopID, authUsername)
-
+	// shop id is in the request so permission is checked in that shop
+	err := svc.checkPermission(shopID, authUsername, rbacmodels.PermissionShopUserUpdate)
 	if err != nil {
 		return err
 	}
 
-	if authUser.Role != models.ROLE_OWNER {
-		return errors.New("permission denied")
+	if role == models.ROLE_OWNER {
+		err = svc.checkPermission(shopID, authUsername, rbacmodels.PermissionShopOwnerUpdate)
+		if err != nil {
+			return err
+		}
 	}
 
 	editusername = utils.NormalizeUsername(editusername)
@@ -141,6 +148,13 @@ func (svc ShopUserService) SaveUserPermissionShop(shopID string, authUsername st
 		}
 
 		if findEditUser.Username != "" {
+			if rbacmodels.HasShopUserRole(findEditUser, rbacmodels.RoleOwner) {
+				err = svc.checkPermission(shopID, authUsername, rbacmodels.PermissionShopOwnerUpdate)
+				if err != nil {
+					return err
+				}
+			}
+
 			tempID := findEditUser.ID
 
 			err = svc.repo.Update(context.Background(), tempID, shopID, username, role)
@@ -182,24 +196,22 @@ func (svc ShopUserService) create(ctx context.Context, shopID string, username s
 
 func (svc ShopUserService) DeleteUserPermissionShop(shopID string, authUsername string, username string) error {
 
-	authUser, err := svc.repo.FindByShopIDAndUsername(context.Background(), shopID, authUsername)
-
+	err := svc.checkPermission(shopID, authUsername, rbacmodels.PermissionShopUserDelete)
 	if err != nil {
 		return err
 	}
 
-	if authUser.Role != models.ROLE_OWNER && authUser.Role != models.ROLE_ADMIN {
-		return errors.New("permission denied")
-	}
-
 	findUser, err := svc.repo.FindByShopIDAndUsername(context.Background(), shopID, username)
 
 	if err != nil {
 		return err
 	}
 
-	if authUser.Role == models.ROLE_ADMIN && findUser.Role == models.ROLE_OWNER {
-		return errors.New("permission denied")
+	if rbacmodels.HasShopUserRole(findUser, rbacmodels.RoleOwner) {
+		err = svc.checkPermission(shopID, authUsername, rbacmodels.PermissionShopOwnerUpdate)
+		if err != nil {
+			return err
+		}
 	}
 
 	if findUser.Username == authUsername {
@@ -213,3 +225,16 @@ func (svc ShopUserService) DeleteUserPermissionShop(shopID string, authUsername
 	}
 	return nil
 }
+
+func (svc ShopUserService) checkPermission(shopID string, username string, permission string) error {
+	allowed, err := svc.permission.HasPermission(context.Background(), micromodels.UserInfo{ShopID: shopID, Username: username}, permission)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return errors.New("permission denied")
+	}
+
+	return nil
+}
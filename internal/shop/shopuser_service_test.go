@@ -4,8 +4,10 @@ import (
 	"context"
 	"smlaicloudplatform/internal/authentication/models"
 	"smlaicloudplatform/internal/shop"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,6 +28,7 @@ func TestShopUserSave(t *testing.T) {
 	mockShopUser.Role = models.ROLE_OWNER
 
 	shopUserRepo.On("Create", ctx, mockShopUser).Return(nil)
+	shopUserRepo.On("Save", ctx, mockShopID, "user_create", models.ROLE_OWNER).Return(nil)
 
 	mockFindShopUser := models.ShopUser{}
 	mockFindShopUser.ShopID = mockShopID
@@ -41,7 +44,10 @@ func TestShopUserSave(t *testing.T) {
 
 	shopUserRepo.On("FindByShopIDAndUsername", ctx, mockShopID, updateUser).Return(mockShopUserAuth, nil)
 
-	shopUserSvc := shop.NewShopUserService(shopUserRepo)
+	permissionChecker := new(PermissionCheckerMock)
+	permissionChecker.On("HasPermission", micromodels.UserInfo{ShopID: mockShopID, Username: authUser}, mock.Anything).Return(true, nil)
+
+	shopUserSvc := shop.NewShopUserService(shopUserRepo, permissionChecker)
 
 	err := shopUserSvc.SaveUserPermissionShop(mockShopID, authUser, "", "user_create", models.ROLE_OWNER)
 
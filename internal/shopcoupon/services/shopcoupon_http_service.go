@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/shopcoupon/models"
 	"smlaicloudplatform/internal/shopcoupon/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -14,11 +15,11 @@ import (
 )
 
 type IShopCouponHttpService interface {
-	CreateShopCoupon(shopID string, authUsername string, doc models.ShopCoupon) (string, error)
-	UpdateShopCoupon(shopID string, guid string, authUsername string, doc models.ShopCoupon) error
-	DeleteShopCoupon(shopID string, guid string, authUsername string) error
-	InfoShopCoupon(shopID string, guid string) (models.ShopCouponInfo, error)
-	SearchShopCoupon(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShopCouponInfo, mongopagination.PaginationData, error)
+	CreateShopCoupon(ctx context.Context, shopID string, authUsername string, doc models.ShopCoupon) (string, error)
+	UpdateShopCoupon(ctx context.Context, shopID string, guid string, authUsername string, doc models.ShopCoupon) error
+	DeleteShopCoupon(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoShopCoupon(ctx context.Context, shopID string, guid string) (models.ShopCouponInfo, error)
+	SearchShopCoupon(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShopCouponInfo, mongopagination.PaginationData, error)
 }
 
 type ShopCouponHttpService struct {
@@ -36,13 +37,13 @@ func NewShopCouponHttpService(repo repositories.IShopCouponRepository) *ShopCoup
 	}
 }
 
-func (svc ShopCouponHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ShopCouponHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ShopCouponHttpService) CreateShopCoupon(shopID string, authUsername string, doc models.ShopCoupon) (string, error) {
+func (svc ShopCouponHttpService) CreateShopCoupon(ctx context.Context, shopID string, authUsername string, doc models.ShopCoupon) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -64,9 +65,9 @@ func (svc ShopCouponHttpService) CreateShopCoupon(shopID string, authUsername st
 	return newGuidFixed, nil
 }
 
-func (svc ShopCouponHttpService) UpdateShopCoupon(shopID string, guid string, authUsername string, doc models.ShopCoupon) error {
+func (svc ShopCouponHttpService) UpdateShopCoupon(ctx context.Context, shopID string, guid string, authUsername string, doc models.ShopCoupon) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -93,9 +94,9 @@ func (svc ShopCouponHttpService) UpdateShopCoupon(shopID string, guid string, au
 	return nil
 }
 
-func (svc ShopCouponHttpService) DeleteShopCoupon(shopID string, guid string, authUsername string) error {
+func (svc ShopCouponHttpService) DeleteShopCoupon(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -116,9 +117,9 @@ func (svc ShopCouponHttpService) DeleteShopCoupon(shopID string, guid string, au
 	return nil
 }
 
-func (svc ShopCouponHttpService) InfoShopCoupon(shopID string, guid string) (models.ShopCouponInfo, error) {
+func (svc ShopCouponHttpService) InfoShopCoupon(ctx context.Context, shopID string, guid string) (models.ShopCouponInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -135,9 +136,9 @@ func (svc ShopCouponHttpService) InfoShopCoupon(shopID string, guid string) (mod
 
 }
 
-func (svc ShopCouponHttpService) SearchShopCoupon(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShopCouponInfo, mongopagination.PaginationData, error) {
+func (svc ShopCouponHttpService) SearchShopCoupon(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ShopCouponInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -58,7 +58,7 @@ func (h ShopCouponHttp) CreateShopCoupon(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateShopCoupon(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateShopCoupon(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -88,7 +88,7 @@ func (h ShopCouponHttp) UpdateShopCoupon(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateShopCoupon(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateShopCoupon(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -110,7 +110,7 @@ func (h ShopCouponHttp) DeleteShopCoupon(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteShopCoupon(id, shopID, authUsername)
+	err := h.svc.DeleteShopCoupon(ctx.Context(), id, shopID, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -132,7 +132,7 @@ func (h ShopCouponHttp) InfoShopCoupon(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ShopCoupon %v", id)
-	doc, err := h.svc.InfoShopCoupon(shopID, id)
+	doc, err := h.svc.InfoShopCoupon(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -164,7 +164,7 @@ func (h ShopCouponHttp) SearchShopCoupon(ctx microservice.IContext) error {
 		}
 	}
 
-	docList, pagination, err := h.svc.SearchShopCoupon(shopID, filterMatch, pageable)
+	docList, pagination, err := h.svc.SearchShopCoupon(ctx.Context(), shopID, filterMatch, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
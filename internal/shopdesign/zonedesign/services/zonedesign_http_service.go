@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/shopdesign/zonedesign/models"
 	"smlaicloudplatform/internal/shopdesign/zonedesign/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -15,11 +16,11 @@ import (
 )
 
 type IZoneDesignService interface {
-	CreateZoneDesign(shopID string, authUsername string, category models.ZoneDesign) (string, error)
-	UpdateZoneDesign(shopID string, guid string, authUsername string, category models.ZoneDesign) error
-	DeleteZoneDesign(shopID string, guid string, authUsername string) error
-	InfoZoneDesign(shopID string, guid string) (models.ZoneDesignInfo, error)
-	SearchZoneDesign(shopID string, pageable micromodels.Pageable) ([]models.ZoneDesignInfo, mongopagination.PaginationData, error)
+	CreateZoneDesign(ctx context.Context, shopID string, authUsername string, category models.ZoneDesign) (string, error)
+	UpdateZoneDesign(ctx context.Context, shopID string, guid string, authUsername string, category models.ZoneDesign) error
+	DeleteZoneDesign(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoZoneDesign(ctx context.Context, shopID string, guid string) (models.ZoneDesignInfo, error)
+	SearchZoneDesign(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ZoneDesignInfo, mongopagination.PaginationData, error)
 }
 
 type ZoneDesignService struct {
@@ -37,13 +38,13 @@ func NewZoneDesignService(repo repositories.IZoneDesignRepository) ZoneDesignSer
 	}
 }
 
-func (svc ZoneDesignService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ZoneDesignService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ZoneDesignService) CreateZoneDesign(shopID string, authUsername string, doc models.ZoneDesign) (string, error) {
+func (svc ZoneDesignService) CreateZoneDesign(ctx context.Context, shopID string, authUsername string, doc models.ZoneDesign) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -64,9 +65,9 @@ func (svc ZoneDesignService) CreateZoneDesign(shopID string, authUsername string
 	return newGuidFixed, nil
 }
 
-func (svc ZoneDesignService) UpdateZoneDesign(shopID string, guid string, authUsername string, category models.ZoneDesign) error {
+func (svc ZoneDesignService) UpdateZoneDesign(ctx context.Context, shopID string, guid string, authUsername string, category models.ZoneDesign) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -92,9 +93,9 @@ func (svc ZoneDesignService) UpdateZoneDesign(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc ZoneDesignService) DeleteZoneDesign(shopID string, guid string, authUsername string) error {
+func (svc ZoneDesignService) DeleteZoneDesign(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -105,9 +106,9 @@ func (svc ZoneDesignService) DeleteZoneDesign(shopID string, guid string, authUs
 	return nil
 }
 
-func (svc ZoneDesignService) InfoZoneDesign(shopID string, guid string) (models.ZoneDesignInfo, error) {
+func (svc ZoneDesignService) InfoZoneDesign(ctx context.Context, shopID string, guid string) (models.ZoneDesignInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -124,9 +125,9 @@ func (svc ZoneDesignService) InfoZoneDesign(shopID string, guid string) (models.
 
 }
 
-func (svc ZoneDesignService) SearchZoneDesign(shopID string, pageable micromodels.Pageable) ([]models.ZoneDesignInfo, mongopagination.PaginationData, error) {
+func (svc ZoneDesignService) SearchZoneDesign(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ZoneDesignInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
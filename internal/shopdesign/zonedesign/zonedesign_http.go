@@ -54,7 +54,7 @@ func (h ZoneDesignHttp) CreateZoneDesign(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateZoneDesign(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateZoneDesign(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -84,7 +84,7 @@ func (h ZoneDesignHttp) UpdateZoneDesign(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateZoneDesign(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateZoneDesign(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -106,7 +106,7 @@ func (h ZoneDesignHttp) DeleteZoneDesign(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteZoneDesign(shopID, id, authUsername)
+	err := h.svc.DeleteZoneDesign(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -128,7 +128,7 @@ func (h ZoneDesignHttp) InfoZoneDesign(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ZoneDesign %v", id)
-	doc, err := h.svc.InfoZoneDesign(shopID, id)
+	doc, err := h.svc.InfoZoneDesign(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -148,7 +148,7 @@ func (h ZoneDesignHttp) SearchZoneDesign(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchZoneDesign(shopID, pageable)
+	docList, pagination, err := h.svc.SearchZoneDesign(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
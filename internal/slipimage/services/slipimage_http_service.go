@@ -12,6 +12,7 @@ import (
 	saleInvoiceServices "smlaicloudplatform/internal/transaction/saleinvoice/services"
 	saleInvoiceReturnServices "smlaicloudplatform/internal/transaction/saleinvoicereturn/services"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"strings"
 	"time"
@@ -21,13 +22,13 @@ import (
 )
 
 type ISlipImageHttpService interface {
-	CreateSlipImage(shopID string, authUsername string, doc models.SlipImageRequest) (models.SlipImageInfo, error)
-	DeleteSlipImage(shopID string, guid string, authUsername string) error
-	DeleteSlipImageByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSlipImage(shopID string, guid string) (models.SlipImageInfo, error)
-	InfoSlipImageByDocno(shopID string, mode uint8, docNo string) ([]models.SlipImageInfo, error)
-	SearchSlipImage(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SlipImageInfo, mongopagination.PaginationData, error)
-	SearchSlipImageStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SlipImageInfo, int, error)
+	CreateSlipImage(ctx context.Context, shopID string, authUsername string, doc models.SlipImageRequest) (models.SlipImageInfo, error)
+	DeleteSlipImage(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSlipImageByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSlipImage(ctx context.Context, shopID string, guid string) (models.SlipImageInfo, error)
+	InfoSlipImageByDocno(ctx context.Context, shopID string, mode uint8, docNo string) ([]models.SlipImageInfo, error)
+	SearchSlipImage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SlipImageInfo, mongopagination.PaginationData, error)
+	SearchSlipImageStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SlipImageInfo, int, error)
 }
 
 type SlipImageHttpService struct {
@@ -63,13 +64,13 @@ func NewSlipImageHttpService(
 	return insSvc
 }
 
-func (svc SlipImageHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SlipImageHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SlipImageHttpService) CreateSlipImage(shopID string, authUsername string, payload models.SlipImageRequest) (models.SlipImageInfo, error) {
+func (svc SlipImageHttpService) CreateSlipImage(ctx context.Context, shopID string, authUsername string, payload models.SlipImageRequest) (models.SlipImageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	fileSize := payload.File.Size
@@ -122,13 +123,13 @@ func (svc SlipImageHttpService) CreateSlipImage(shopID string, authUsername stri
 		return models.SlipImageInfo{}, err
 	}
 
-	err = svc.svcSaleInvoice.UpdateSlip(shopID, authUsername, payload.DocNo, mode, payload.MachineCode, payload.ZoneGroupNumber, uploadUri)
+	err = svc.svcSaleInvoice.UpdateSlip(ctx, shopID, authUsername, payload.DocNo, mode, payload.MachineCode, payload.ZoneGroupNumber, uploadUri)
 
 	if err != nil {
 		logger.GetLogger().Error(err)
 	}
 
-	err = svc.svcSaleInvoiceReturn.UpdateSlip(shopID, authUsername, payload.DocNo, mode, payload.MachineCode, payload.ZoneGroupNumber, uploadUri)
+	err = svc.svcSaleInvoiceReturn.UpdateSlip(ctx, shopID, authUsername, payload.DocNo, mode, payload.MachineCode, payload.ZoneGroupNumber, uploadUri)
 
 	if err != nil {
 		logger.GetLogger().Error(err)
@@ -137,9 +138,9 @@ func (svc SlipImageHttpService) CreateSlipImage(shopID string, authUsername stri
 	return docData.SlipImageInfo, nil
 }
 
-func (svc SlipImageHttpService) DeleteSlipImage(shopID string, guid string, authUsername string) error {
+func (svc SlipImageHttpService) DeleteSlipImage(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -160,9 +161,9 @@ func (svc SlipImageHttpService) DeleteSlipImage(shopID string, guid string, auth
 	return nil
 }
 
-func (svc SlipImageHttpService) DeleteSlipImageByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SlipImageHttpService) DeleteSlipImageByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -177,9 +178,9 @@ func (svc SlipImageHttpService) DeleteSlipImageByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc SlipImageHttpService) InfoSlipImage(shopID string, guid string) (models.SlipImageInfo, error) {
+func (svc SlipImageHttpService) InfoSlipImage(ctx context.Context, shopID string, guid string) (models.SlipImageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -195,9 +196,9 @@ func (svc SlipImageHttpService) InfoSlipImage(shopID string, guid string) (model
 	return findDoc.SlipImageInfo, nil
 }
 
-func (svc SlipImageHttpService) InfoSlipImageByDocno(shopID string, mode uint8, docNo string) ([]models.SlipImageInfo, error) {
+func (svc SlipImageHttpService) InfoSlipImageByDocno(ctx context.Context, shopID string, mode uint8, docNo string) ([]models.SlipImageInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByDocNo(ctx, shopID, mode, docNo)
@@ -213,9 +214,9 @@ func (svc SlipImageHttpService) InfoSlipImageByDocno(shopID string, mode uint8,
 	return findDocs, nil
 }
 
-func (svc SlipImageHttpService) SearchSlipImage(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SlipImageInfo, mongopagination.PaginationData, error) {
+func (svc SlipImageHttpService) SearchSlipImage(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SlipImageInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -231,9 +232,9 @@ func (svc SlipImageHttpService) SearchSlipImage(shopID string, filters map[strin
 	return docList, pagination, nil
 }
 
-func (svc SlipImageHttpService) SearchSlipImageStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SlipImageInfo, int, error) {
+func (svc SlipImageHttpService) SearchSlipImageStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SlipImageInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
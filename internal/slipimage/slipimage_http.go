@@ -6,15 +6,18 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/slipimage/models"
 	"smlaicloudplatform/internal/slipimage/repositories"
 	"smlaicloudplatform/internal/slipimage/services"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
+	"smlaicloudplatform/internal/transaction/returnable"
 	saleInvoiceRepositories "smlaicloudplatform/internal/transaction/saleinvoice/repositories"
 	saleInvoiceServices "smlaicloudplatform/internal/transaction/saleinvoice/services"
 	saleInvoiceReturnRepositories "smlaicloudplatform/internal/transaction/saleinvoicereturn/repositories"
 	saleInvoiceReturnServices "smlaicloudplatform/internal/transaction/saleinvoicereturn/services"
+	"smlaicloudplatform/internal/transaction/saleorder"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -43,13 +46,14 @@ func NewSlipImageHttp(ms *microservice.Microservice, cfg config.IConfig) SlipIma
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
 	saleInvoiceRepo := saleInvoiceRepositories.NewSaleInvoiceRepository(pst)
-	saleInvoiceRepoMq := saleInvoiceRepositories.NewSaleInvoiceMessageQueueRepository(producer)
-	transCacheRepo := trans_cache.NewCacheRepository(cache)
+	saleInvoiceRepoMq := saleInvoiceRepositories.NewSaleInvoiceOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
 
 	saleInvoiceSvc := saleInvoiceServices.NewSaleInvoiceService(
 		saleInvoiceRepo,
-		transCacheRepo,
+		docNoSequencer,
+		saleorder.InitSaleOrderDelivery(ms, cfg),
 		productBarcodeRepo,
 		saleInvoiceRepoMq,
 		masterSyncCacheRepo,
@@ -58,11 +62,13 @@ func NewSlipImageHttp(ms *microservice.Microservice, cfg config.IConfig) SlipIma
 	)
 
 	saleInvoiceReturnRepo := saleInvoiceReturnRepositories.NewSaleInvoiceReturnRepository(pst)
-	saleInvoiceReturnRepoMq := saleInvoiceReturnRepositories.NewSaleInvoiceReturnMessageQueueRepository(producer)
+	saleInvoiceReturnRepoMq := saleInvoiceReturnRepositories.NewSaleInvoiceReturnOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	saleInvoiceReturnSvc := saleInvoiceReturnServices.NewSaleInvoiceReturnService(
 		saleInvoiceReturnRepo,
-		transCacheRepo,
+		docNoSequencer,
+		saleInvoiceRepo,
+		returnable.InitReturnLedger(ms, cfg, saleInvoiceReturnRepo),
 		productBarcodeRepo,
 		saleInvoiceReturnRepoMq,
 		masterSyncCacheRepo,
@@ -177,7 +183,7 @@ func (h SlipImageHttp) UploadSlipImage(ctx microservice.IContext) error {
 		ZoneGroupNumber: zoneGroupNumber,
 	}
 
-	data, err := h.svc.CreateSlipImage(shopID, authUsername, payload)
+	data, err := h.svc.CreateSlipImage(ctx.Context(), shopID, authUsername, payload)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -207,7 +213,7 @@ func (h SlipImageHttp) DeleteSlipImage(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSlipImage(shopID, id, authUsername)
+	err := h.svc.DeleteSlipImage(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -246,7 +252,7 @@ func (h SlipImageHttp) DeleteSlipImageByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteSlipImageByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSlipImageByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -276,7 +282,7 @@ func (h SlipImageHttp) InfoSlipImage(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SlipImage %v", id)
-	doc, err := h.svc.InfoSlipImage(shopID, id)
+	doc, err := h.svc.InfoSlipImage(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -316,7 +322,7 @@ func (h SlipImageHttp) SearchSlipImagePage(ctx microservice.IContext) error {
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSlipImage(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchSlipImage(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +365,7 @@ func (h SlipImageHttp) SearchSlipImageStep(ctx microservice.IContext) error {
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchSlipImageStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchSlipImageStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
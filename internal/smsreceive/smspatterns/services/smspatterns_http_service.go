@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/smsreceive/smspatterns/models"
 	"smlaicloudplatform/internal/smsreceive/smspatterns/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"strconv"
 	"time"
@@ -16,11 +17,11 @@ import (
 )
 
 type ISmsPatternsHttpService interface {
-	CreateSmsPatterns(authUsername string, doc models.SmsPatterns) (string, error)
-	UpdateSmsPatterns(guid string, authUsername string, doc models.SmsPatterns) error
-	DeleteSmsPatterns(guid string) error
-	InfoSmsPatterns(guid string) (models.SmsPatternsInfo, error)
-	SearchSmsPatterns(pageable micromodels.Pageable) ([]models.SmsPatternsInfo, mongopagination.PaginationData, error)
+	CreateSmsPatterns(ctx context.Context, authUsername string, doc models.SmsPatterns) (string, error)
+	UpdateSmsPatterns(ctx context.Context, guid string, authUsername string, doc models.SmsPatterns) error
+	DeleteSmsPatterns(ctx context.Context, guid string) error
+	InfoSmsPatterns(ctx context.Context, guid string) (models.SmsPatternsInfo, error)
+	SearchSmsPatterns(ctx context.Context, pageable micromodels.Pageable) ([]models.SmsPatternsInfo, mongopagination.PaginationData, error)
 }
 
 type SmsPatternsHttpService struct {
@@ -38,13 +39,13 @@ func NewSmsPatternsHttpService(repo repositories.ISmsPatternsRepository) *SmsPat
 	}
 }
 
-func (svc SmsPatternsHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SmsPatternsHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SmsPatternsHttpService) CreateSmsPatterns(authUsername string, doc models.SmsPatterns) (string, error) {
+func (svc SmsPatternsHttpService) CreateSmsPatterns(ctx context.Context, authUsername string, doc models.SmsPatterns) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -65,9 +66,9 @@ func (svc SmsPatternsHttpService) CreateSmsPatterns(authUsername string, doc mod
 	return newGuidFixed, nil
 }
 
-func (svc SmsPatternsHttpService) UpdateSmsPatterns(guid string, authUsername string, doc models.SmsPatterns) error {
+func (svc SmsPatternsHttpService) UpdateSmsPatterns(ctx context.Context, guid string, authUsername string, doc models.SmsPatterns) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, guid)
@@ -97,9 +98,9 @@ func (svc SmsPatternsHttpService) UpdateSmsPatterns(guid string, authUsername st
 	return nil
 }
 
-func (svc SmsPatternsHttpService) DeleteSmsPatterns(guid string) error {
+func (svc SmsPatternsHttpService) DeleteSmsPatterns(ctx context.Context, guid string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, guid)
@@ -120,9 +121,9 @@ func (svc SmsPatternsHttpService) DeleteSmsPatterns(guid string) error {
 	return nil
 }
 
-func (svc SmsPatternsHttpService) InfoSmsPatterns(guid string) (models.SmsPatternsInfo, error) {
+func (svc SmsPatternsHttpService) InfoSmsPatterns(ctx context.Context, guid string) (models.SmsPatternsInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, guid)
@@ -139,9 +140,9 @@ func (svc SmsPatternsHttpService) InfoSmsPatterns(guid string) (models.SmsPatter
 
 }
 
-func (svc SmsPatternsHttpService) SearchSmsPatterns(pageable micromodels.Pageable) ([]models.SmsPatternsInfo, mongopagination.PaginationData, error) {
+func (svc SmsPatternsHttpService) SearchSmsPatterns(ctx context.Context, pageable micromodels.Pageable) ([]models.SmsPatternsInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -55,7 +55,7 @@ func (h SmsPatternsHttp) CreateSmsPatterns(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateSmsPatterns(authUsername, *docReq)
+	idx, err := h.svc.CreateSmsPatterns(ctx.Context(), authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -84,7 +84,7 @@ func (h SmsPatternsHttp) UpdateSmsPatterns(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateSmsPatterns(id, authUsername, *docReq)
+	err = h.svc.UpdateSmsPatterns(ctx.Context(), id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -103,7 +103,7 @@ func (h SmsPatternsHttp) DeleteSmsPatterns(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSmsPatterns(id)
+	err := h.svc.DeleteSmsPatterns(ctx.Context(), id)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -123,7 +123,7 @@ func (h SmsPatternsHttp) InfoSmsPatterns(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SmsPatterns %v", id)
-	doc, err := h.svc.InfoSmsPatterns(id)
+	doc, err := h.svc.InfoSmsPatterns(ctx.Context(), id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -141,7 +141,7 @@ func (h SmsPatternsHttp) InfoSmsPatterns(ctx microservice.IContext) error {
 func (h SmsPatternsHttp) SearchSmsPatterns(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchSmsPatterns(pageable)
+	docList, pagination, err := h.svc.SearchSmsPatterns(ctx.Context(), pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
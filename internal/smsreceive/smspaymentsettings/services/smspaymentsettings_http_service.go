@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/smsreceive/smspaymentsettings/models"
 	"smlaicloudplatform/internal/smsreceive/smspaymentsettings/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,9 +18,9 @@ import (
 )
 
 type ISmsPaymentSettingsHttpService interface {
-	SaveSmsPaymentSettings(shopID string, authUsername string, storefrontGUID string, doc models.SmsPaymentSettings) error
-	InfoSmsPaymentSettings(shopID string, storefrontGUID string) (models.SmsPaymentSettingsInfo, error)
-	SearchSmsPaymentSettings(shopID string, pageable micromodels.Pageable) ([]models.SmsPaymentSettingsInfo, mongopagination.PaginationData, error)
+	SaveSmsPaymentSettings(ctx context.Context, shopID string, authUsername string, storefrontGUID string, doc models.SmsPaymentSettings) error
+	InfoSmsPaymentSettings(ctx context.Context, shopID string, storefrontGUID string) (models.SmsPaymentSettingsInfo, error)
+	SearchSmsPaymentSettings(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.SmsPaymentSettingsInfo, mongopagination.PaginationData, error)
 }
 
 type SmsPaymentSettingsHttpService struct {
@@ -39,13 +40,13 @@ func NewSmsPaymentSettingsHttpService(repo repositories.SmsPaymentSettingsReposi
 	}
 }
 
-func (svc SmsPaymentSettingsHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SmsPaymentSettingsHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SmsPaymentSettingsHttpService) SaveSmsPaymentSettings(shopID string, authUsername string, storefrontGUID string, doc models.SmsPaymentSettings) error {
+func (svc SmsPaymentSettingsHttpService) SaveSmsPaymentSettings(ctx context.Context, shopID string, authUsername string, storefrontGUID string, doc models.SmsPaymentSettings) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findPattern, err := svc.repoPattern.FindByCode(ctx, doc.PatternCode)
@@ -71,9 +72,9 @@ func (svc SmsPaymentSettingsHttpService) SaveSmsPaymentSettings(shopID string, a
 	}
 
 	if isExitsSetting {
-		return svc.updateSmsPaymentSettings(shopID, findDoc.GuidFixed, authUsername, doc)
+		return svc.updateSmsPaymentSettings(ctx, shopID, findDoc.GuidFixed, authUsername, doc)
 	} else {
-		return svc.createSmsPaymentSettings(shopID, authUsername, doc)
+		return svc.createSmsPaymentSettings(ctx, shopID, authUsername, doc)
 	}
 
 }
@@ -87,9 +88,9 @@ func (svc SmsPaymentSettingsHttpService) isExistsPaymentSettings(storefrontGUID
 	return false, nil
 }
 
-func (svc SmsPaymentSettingsHttpService) createSmsPaymentSettings(shopID string, authUsername string, doc models.SmsPaymentSettings) error {
+func (svc SmsPaymentSettingsHttpService) createSmsPaymentSettings(ctx context.Context, shopID string, authUsername string, doc models.SmsPaymentSettings) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -112,9 +113,9 @@ func (svc SmsPaymentSettingsHttpService) createSmsPaymentSettings(shopID string,
 	return nil
 }
 
-func (svc SmsPaymentSettingsHttpService) updateSmsPaymentSettings(shopID string, guid string, authUsername string, doc models.SmsPaymentSettings) error {
+func (svc SmsPaymentSettingsHttpService) updateSmsPaymentSettings(ctx context.Context, shopID string, guid string, authUsername string, doc models.SmsPaymentSettings) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -141,9 +142,9 @@ func (svc SmsPaymentSettingsHttpService) updateSmsPaymentSettings(shopID string,
 	return nil
 }
 
-func (svc SmsPaymentSettingsHttpService) InfoSmsPaymentSettings(shopID string, storefrontGUID string) (models.SmsPaymentSettingsInfo, error) {
+func (svc SmsPaymentSettingsHttpService) InfoSmsPaymentSettings(ctx context.Context, shopID string, storefrontGUID string) (models.SmsPaymentSettingsInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"storefrontguid": storefrontGUID})
@@ -156,9 +157,9 @@ func (svc SmsPaymentSettingsHttpService) InfoSmsPaymentSettings(shopID string, s
 
 }
 
-func (svc SmsPaymentSettingsHttpService) SearchSmsPaymentSettings(shopID string, pageable micromodels.Pageable) ([]models.SmsPaymentSettingsInfo, mongopagination.PaginationData, error) {
+func (svc SmsPaymentSettingsHttpService) SearchSmsPaymentSettings(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.SmsPaymentSettingsInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, pagination, err := svc.repo.FindPage(ctx, shopID, []string{}, pageable)
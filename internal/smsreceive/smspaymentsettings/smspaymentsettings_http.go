@@ -72,7 +72,7 @@ func (h SmsPaymentSettingsHttp) CreateSmsPaymentSettings(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.SaveSmsPaymentSettings(shopID, authUsername, storefrontGUID, *docReq)
+	err = h.svc.SaveSmsPaymentSettings(ctx.Context(), shopID, authUsername, storefrontGUID, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -101,7 +101,7 @@ func (h SmsPaymentSettingsHttp) InfoSmsPaymentSettings(ctx microservice.IContext
 
 	storefrontGUID := ctx.Param("storefrontguid")
 
-	doc, err := h.svc.InfoSmsPaymentSettings(shopID, storefrontGUID)
+	doc, err := h.svc.InfoSmsPaymentSettings(ctx.Context(), shopID, storefrontGUID)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v", err)
@@ -133,7 +133,7 @@ func (h SmsPaymentSettingsHttp) SearchSmsPaymentSettings(ctx microservice.IConte
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchSmsPaymentSettings(shopID, pageable)
+	docList, pagination, err := h.svc.SearchSmsPaymentSettings(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -8,6 +8,7 @@ import (
 	smssetingsRepo "smlaicloudplatform/internal/smsreceive/smspaymentsettings/repositories"
 	"smlaicloudplatform/internal/smsreceive/smstransaction/models"
 	"smlaicloudplatform/internal/smsreceive/smstransaction/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"strconv"
 	"time"
@@ -18,13 +19,13 @@ import (
 )
 
 type ISmsTransactionHttpService interface {
-	CreateSmsTransaction(shopID string, authUsername string, doc models.SmsTransaction) (string, error)
-	UpdateSmsTransaction(guid string, shopID string, authUsername string, doc models.SmsTransaction) error
-	DeleteSmsTransaction(guid string, shopID string, authUsername string) error
-	InfoSmsTransaction(guid string, shopID string) (models.SmsTransactionInfo, error)
-	SearchSmsTransaction(shopID string, pageable micromodels.Pageable) ([]models.SmsTransactionInfo, mongopagination.PaginationData, error)
-	CheckSMS(shopID string, storefrontGUID string, amountCheck float64, checkTime time.Time) (models.SmsTransactionCheck, error)
-	ConfirmSmsTransaction(shopID string, smsTransactionGUIDFixed string) error
+	CreateSmsTransaction(ctx context.Context, shopID string, authUsername string, doc models.SmsTransaction) (string, error)
+	UpdateSmsTransaction(ctx context.Context, guid string, shopID string, authUsername string, doc models.SmsTransaction) error
+	DeleteSmsTransaction(ctx context.Context, guid string, shopID string, authUsername string) error
+	InfoSmsTransaction(ctx context.Context, guid string, shopID string) (models.SmsTransactionInfo, error)
+	SearchSmsTransaction(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.SmsTransactionInfo, mongopagination.PaginationData, error)
+	CheckSMS(ctx context.Context, shopID string, storefrontGUID string, amountCheck float64, checkTime time.Time) (models.SmsTransactionCheck, error)
+	ConfirmSmsTransaction(ctx context.Context, shopID string, smsTransactionGUIDFixed string) error
 }
 
 type SmsTransactionHttpService struct {
@@ -56,13 +57,13 @@ func NewSmsTransactionHttpService(
 	}
 }
 
-func (svc SmsTransactionHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SmsTransactionHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SmsTransactionHttpService) CreateSmsTransaction(shopID string, authUsername string, doc models.SmsTransaction) (string, error) {
+func (svc SmsTransactionHttpService) CreateSmsTransaction(ctx context.Context, shopID string, authUsername string, doc models.SmsTransaction) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "transid", doc.TransId)
@@ -96,9 +97,9 @@ func (svc SmsTransactionHttpService) CreateSmsTransaction(shopID string, authUse
 	return newGuidFixed, nil
 }
 
-func (svc SmsTransactionHttpService) UpdateSmsTransaction(guid string, shopID string, authUsername string, doc models.SmsTransaction) error {
+func (svc SmsTransactionHttpService) UpdateSmsTransaction(ctx context.Context, guid string, shopID string, authUsername string, doc models.SmsTransaction) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -128,9 +129,9 @@ func (svc SmsTransactionHttpService) UpdateSmsTransaction(guid string, shopID st
 	return nil
 }
 
-func (svc SmsTransactionHttpService) DeleteSmsTransaction(guid string, shopID string, authUsername string) error {
+func (svc SmsTransactionHttpService) DeleteSmsTransaction(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -150,9 +151,9 @@ func (svc SmsTransactionHttpService) DeleteSmsTransaction(guid string, shopID st
 	return nil
 }
 
-func (svc SmsTransactionHttpService) InfoSmsTransaction(guid string, shopID string) (models.SmsTransactionInfo, error) {
+func (svc SmsTransactionHttpService) InfoSmsTransaction(ctx context.Context, guid string, shopID string) (models.SmsTransactionInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -169,9 +170,9 @@ func (svc SmsTransactionHttpService) InfoSmsTransaction(guid string, shopID stri
 
 }
 
-func (svc SmsTransactionHttpService) SearchSmsTransaction(shopID string, pageable micromodels.Pageable) ([]models.SmsTransactionInfo, mongopagination.PaginationData, error) {
+func (svc SmsTransactionHttpService) SearchSmsTransaction(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.SmsTransactionInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -188,9 +189,9 @@ func (svc SmsTransactionHttpService) SearchSmsTransaction(shopID string, pageabl
 	return docList, pagination, nil
 }
 
-func (svc SmsTransactionHttpService) CheckSMS(shopID string, storefrontGUID string, amountCheck float64, checkTime time.Time) (models.SmsTransactionCheck, error) {
+func (svc SmsTransactionHttpService) CheckSMS(ctx context.Context, shopID string, storefrontGUID string, amountCheck float64, checkTime time.Time) (models.SmsTransactionCheck, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	storefrontSmsPaymentSettingDoc, err := svc.smsSetingsRepo.FindOne(ctx, shopID, bson.M{"storefrontguid": storefrontGUID})
@@ -252,9 +253,9 @@ func (svc SmsTransactionHttpService) CheckSMS(shopID string, storefrontGUID stri
 	}, nil
 }
 
-func (svc SmsTransactionHttpService) ConfirmSmsTransaction(shopID string, smsTransactionGUIDFixed string) error {
+func (svc SmsTransactionHttpService) ConfirmSmsTransaction(ctx context.Context, shopID string, smsTransactionGUIDFixed string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, smsTransactionGUIDFixed)
@@ -70,7 +70,7 @@ func (h SmsTransactionHttp) CreateSmsTransaction(ctx microservice.IContext) erro
 		return err
 	}
 
-	idx, err := h.svc.CreateSmsTransaction(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateSmsTransaction(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -111,7 +111,7 @@ func (h SmsTransactionHttp) UpdateSmsTransaction(ctx microservice.IContext) erro
 		return err
 	}
 
-	err = h.svc.UpdateSmsTransaction(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateSmsTransaction(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -143,7 +143,7 @@ func (h SmsTransactionHttp) DeleteSmsTransaction(ctx microservice.IContext) erro
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSmsTransaction(shopID, id, authUsername)
+	err := h.svc.DeleteSmsTransaction(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -175,7 +175,7 @@ func (h SmsTransactionHttp) InfoSmsTransaction(ctx microservice.IContext) error
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SmsTransaction %v", id)
-	doc, err := h.svc.InfoSmsTransaction(shopID, id)
+	doc, err := h.svc.InfoSmsTransaction(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -207,7 +207,7 @@ func (h SmsTransactionHttp) SearchSmsTransaction(ctx microservice.IContext) erro
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchSmsTransaction(shopID, pageable)
+	docList, pagination, err := h.svc.SearchSmsTransaction(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -254,7 +254,7 @@ func (h SmsTransactionHttp) CheckSMS(ctx microservice.IContext) error {
 
 	checkTime := h.ms.TimeNow()
 
-	amount, err := h.svc.CheckSMS(shopID, storefrontCode, docReq.Amount, checkTime)
+	amount, err := h.svc.CheckSMS(ctx.Context(), shopID, storefrontCode, docReq.Amount, checkTime)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -290,7 +290,7 @@ func (h SmsTransactionHttp) ConfirmSmsTransaction(ctx microservice.IContext) err
 
 	id := ctx.Param("id")
 
-	err := h.svc.ConfirmSmsTransaction(shopID, id)
+	err := h.svc.ConfirmSmsTransaction(ctx.Context(), shopID, id)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	jobServices "smlaicloudplatform/internal/job/services"
 	productbarocde_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repo "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/stockbalanceimport/models"
@@ -30,7 +31,7 @@ type IStockBalanceImportService interface {
 	Delete(shopID string, guid string) error
 	DeleteTask(shopID string, taskID string) error
 	ImportFromFile(shopID string, authUsername string, fileUpload io.Reader) (string, error)
-	SaveTask(shopID string, authUsername string, taskID string, headerDoc stockbalance_models.StockBalanceHeader) (string, error)
+	SaveTask(job jobServices.IJobContext, shopID string, authUsername string, taskID string, headerDoc stockbalance_models.StockBalanceHeader) (string, error)
 	Meta(shopID string, taskID string) (models.StockBalanceImportMeta, error)
 	Verify(shopID string, taskID string) error
 }
@@ -249,14 +250,17 @@ func (svc StockBalanceImportService) DeleteTask(shopID string, taskID string) er
 	return svc.chRepo.DeleteByTaskID(context.Background(), shopID, taskID)
 }
 
-func (svc StockBalanceImportService) SaveTask(shopID string, authUsername string, taskID string, headerDoc stockbalance_models.StockBalanceHeader) (string, error) {
+func (svc StockBalanceImportService) SaveTask(job jobServices.IJobContext, shopID string, authUsername string, taskID string, headerDoc stockbalance_models.StockBalanceHeader) (string, error) {
 
+	ctx := job.Context()
+
+	job.Progress(0, "verify items")
 	err := svc.Verify(shopID, taskID)
 	if err != nil {
 		return "", err
 	}
 
-	countNotExist, err := svc.chRepo.CountExist(context.Background(), shopID, taskID, false)
+	countNotExist, err := svc.chRepo.CountExist(ctx, shopID, taskID, false)
 
 	if err != nil {
 		return "", err
@@ -266,7 +270,8 @@ func (svc StockBalanceImportService) SaveTask(shopID string, authUsername string
 		return "", errors.New("have barcode not found in product")
 	}
 
-	docs, err := svc.chRepo.All(context.Background(), shopID, taskID)
+	job.Progress(10, "prepare items")
+	docs, err := svc.chRepo.All(ctx, shopID, taskID)
 
 	if err != nil {
 		return "", err
@@ -281,7 +286,11 @@ func (svc StockBalanceImportService) SaveTask(shopID string, authUsername string
 		barcodes = append(barcodes, doc.Barcode)
 		tempBarcodes[docs[i].Barcode] = docs[i]
 		if (i > 1 && i%5000 == 0) || i == len(docs)-1 {
-			productList, err := svc.productBarcodeRepo.FindByBarcodes(context.Background(), shopID, barcodes)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+
+			productList, err := svc.productBarcodeRepo.FindByBarcodes(ctx, shopID, barcodes)
 			if err != nil {
 				return "", err
 			}
@@ -347,25 +356,34 @@ func (svc StockBalanceImportService) SaveTask(shopID string, authUsername string
 			barcodes = []string{}
 			tempBarcodes = map[string]models.StockBalanceImportDoc{}
 
+			job.Progress(10+(i+1)*60/len(docs), fmt.Sprintf("prepared %d/%d items", i+1, len(docs)))
+
 		}
 	}
 
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	job.Progress(70, "create stock balance")
 	tempTransaction := stockbalance_models.StockBalance{}
 
 	tempTransaction.StockBalanceHeader = headerDoc
 
-	docCreate, docGUIDFixed, docNo, err := svc.stockBalanceService.CreateStockBalance(shopID, authUsername, tempTransaction)
+	docCreate, docGUIDFixed, docNo, err := svc.stockBalanceService.CreateStockBalance(ctx, shopID, authUsername, tempTransaction)
 	if err != nil {
 		return "", err
 	}
 
+	job.Logf("stock balance %s is created with %d items", docNo, len(tempDetails))
+
 	for i := range tempDetails {
 		tempDetails[i].DocNo = docNo
 	}
 
-	err = svc.stockBalanceDetailService.CreateStockBalanceDetail(shopID, authUsername, tempDetails)
+	err = svc.stockBalanceDetailService.CreateStockBalanceDetail(ctx, shopID, authUsername, tempDetails)
 	if err != nil {
-		err = svc.stockBalanceService.DeleteStockBalance(shopID, docGUIDFixed, authUsername)
+		err = svc.stockBalanceService.DeleteStockBalance(ctx, shopID, docGUIDFixed, authUsername)
 
 		if err != nil {
 			return "", err
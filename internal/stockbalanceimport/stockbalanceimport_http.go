@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"path/filepath"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job"
+	jobServices "smlaicloudplatform/internal/job/services"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repo "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/stockbalanceimport/models"
 	"smlaicloudplatform/internal/stockbalanceimport/repositories"
 	"smlaicloudplatform/internal/stockbalanceimport/services"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	trancache "smlaicloudplatform/internal/transaction/repositories"
 	stockbalance_models "smlaicloudplatform/internal/transaction/stockbalance/models"
 	stockbalance_repositories "smlaicloudplatform/internal/transaction/stockbalance/repositories"
@@ -27,9 +31,10 @@ import (
 type IStockBalanceImportHttp interface{}
 
 type StockBalanceImportHttp struct {
-	ms  *microservice.Microservice
-	cfg config.IConfig
-	svc services.IStockBalanceImportService
+	ms        *microservice.Microservice
+	cfg       config.IConfig
+	svc       services.IStockBalanceImportService
+	jobRunner jobServices.IJobRunner
 }
 
 func NewStockBalanceImportHttp(ms *microservice.Microservice, cfg config.IConfig) StockBalanceImportHttp {
@@ -39,12 +44,12 @@ func NewStockBalanceImportHttp(ms *microservice.Microservice, cfg config.IConfig
 	pstClickHouse := ms.ClickHousePersister(cfg.ClickHouseConfig())
 
 	repo := stockbalance_repositories.NewStockBalanceRepository(pst)
-	repoMq := stockbalance_repositories.NewStockBalanceMessageQueueRepository(producer)
+	repoMq := stockbalance_repositories.NewStockBalanceOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	transCacheRepo := trancache.NewCacheRepository(cache)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
 	stockbalanceDetailRepo := stockbalancedetail_repositories.NewStockBalanceDetailRepository(pst)
-	stockbalanceDetailMqRepo := stockbalancedetail_repositories.NewStockBalanceDetailMessageQueueRepository(producer)
+	stockbalanceDetailMqRepo := stockbalancedetail_repositories.NewStockBalanceDetailOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repo.NewProductBarcodeRepository(pst, cache)
 
@@ -57,16 +62,17 @@ func NewStockBalanceImportHttp(ms *microservice.Microservice, cfg config.IConfig
 		stockbalancedetail_serrvices.StockBalanceDetailParser{},
 	)
 
-	stockBalanceSvc := stockbalance_serrvices.NewStockBalanceHttpService(stockBalanceDetailSvc, repo, transCacheRepo, repoMq, masterSyncCacheRepo)
+	stockBalanceSvc := stockbalance_serrvices.NewStockBalanceHttpService(stockBalanceDetailSvc, repo, docsequence.InitDocNoSequencer(ms, cfg), repoMq, masterSyncCacheRepo)
 
 	chRepo := repositories.NewStockBalanceImportClickHouseRepository(pstClickHouse)
 
 	svc := services.NewStockBalanceImportService(chRepo, productBarcodeRepo, stockBalanceSvc, stockBalanceDetailSvc, utils.RandStringBytesMaskImprSrcUnsafe, utils.NewGUID, time.Now)
 
 	return StockBalanceImportHttp{
-		ms:  ms,
-		cfg: cfg,
-		svc: svc,
+		ms:        ms,
+		cfg:       cfg,
+		svc:       svc,
+		jobRunner: job.InitJobRunner(ms, cfg),
 	}
 }
 
@@ -321,13 +327,13 @@ func (h StockBalanceImportHttp) DeleteByTask(ctx microservice.IContext) error {
 	return nil
 }
 
-// Delete StockBalanceImport By Task ID godoc
-// @Description Delete StockBalanceImport By Task ID
+// Save StockBalanceImport Task godoc
+// @Description Save StockBalanceImport task in background job, job id is returned to poll at /job/{id}
 // @Tags		StockBalanceImport
 // @Param		task-id		path		string		true		"task id"
 // @Param		StockBalanceHeader  body      models.StockBalanceHeader  true  "Stock Balance Header"
 // @Accept 		json
-// @Success		201	{object}	common.ApiResponse
+// @Success		202	{object}	common.ApiResponse
 // @Failure		401 {object}	common.AuthResponseFailed
 // @Security     AccessToken
 // @Router /stockbalanceimport/{task-id} [post]
@@ -347,16 +353,23 @@ func (h StockBalanceImportHttp) SaveTask(ctx microservice.IContext) error {
 		return err
 	}
 
-	docNo, err := h.svc.SaveTask(shopID, authUsername, taskID, docReq)
+	jobID, err := h.jobRunner.Start(shopID, authUsername, "stockbalanceimport", func(job jobServices.IJobContext) (interface{}, error) {
+		docNo, err := h.svc.SaveTask(job, shopID, authUsername, taskID, docReq)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{"docno": docNo}, nil
+	})
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		job.ResponseStartError(ctx, err)
 		return err
 	}
 
-	ctx.Response(http.StatusCreated, common.ApiResponse{
+	ctx.Response(http.StatusAccepted, common.ApiResponse{
 		Success: true,
-		DocNo:   docNo,
+		ID:      jobID,
 	})
 	return nil
 }
@@ -65,7 +65,7 @@ func (repo *StockProcessPGRepository) UpdateStockTransactionChange(stockData []s
 		for _, data := range stockData {
 			err := pst.DBClient().Exec("UPDATE stock_transaction_detail "+
 				" SET costperunit = ? , totalcost = ?, balanceqty = ?, balanceamount = ?, balanceaverage = ? "+
-				" WHERE id = ?", data.CostPerUnit, data.TotalCost, data.BalanceQty, data.BalanceAmount, data.BalanceAverage, data.ID).Error
+				" WHERE shopid = ? AND id = ?", data.CostPerUnit, data.TotalCost, data.BalanceQty, data.BalanceAmount, data.BalanceAverage, data.ShopID, data.ID).Error
 			if err != nil {
 				return err
 			}
@@ -1,11 +1,14 @@
 package stockprocess
 
 import (
+	"context"
 	"smlaicloudplatform/internal/logger"
 	productBarcodeRepositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	stockModel "smlaicloudplatform/internal/stockprocess/models"
 	"smlaicloudplatform/internal/stockprocess/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	"smlaicloudplatform/pkg/stockcalculator"
+	"time"
 )
 
 var log logger.ILogger
@@ -17,23 +20,39 @@ type IStockCalculator interface {
 	WriteUpdateStockDataChanged(stockData []stockModel.StockData) error
 }
 
+// stockCalculatorLockTTL is TTL of the lock of barcode while its stock is calculated
+const stockCalculatorLockTTL = time.Minute
+
 type StockCalculator struct {
 	stockMovementRepo  repositories.IStockProcessPGRepository
 	productBarcodeRepo productBarcodeRepositories.IProductBarcodePGRepository
+	locker             microservice.ILocker
 }
 
 func NewStockCalculator(
 	repo repositories.IStockProcessPGRepository,
 	productBarcodeRepo productBarcodeRepositories.IProductBarcodePGRepository,
+	locker microservice.ILocker,
 ) IStockCalculator {
 
 	return &StockCalculator{
 		stockMovementRepo:  repo,
 		productBarcodeRepo: productBarcodeRepo,
+		locker:             locker,
 	}
 }
 
+// CalculatorStock calculate cost of the barcode, the same barcode is not calculated by other replica at the same time
 func (sc *StockCalculator) CalculatorStock(shopID string, barcode string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*stockCalculatorLockTTL)
+	defer cancel()
+
+	return microservice.WithLock(ctx, sc.locker, "stockcalculator:"+shopID+":"+barcode, stockCalculatorLockTTL, func() error {
+		return sc.calculatorStock(shopID, barcode)
+	})
+}
+
+func (sc *StockCalculator) calculatorStock(shopID string, barcode string) error {
 
 	stockDataList, err := sc.GetStockDataList(shopID, barcode)
 	if err != nil {
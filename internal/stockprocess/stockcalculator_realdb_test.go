@@ -26,6 +26,6 @@ func TestStockProcessRealDBTest(t *testing.T) {
 	// assert.NotNil(t, stockLists)
 	// assert.Equal(t, 2, len(stockLists))
 
-	process := stockprocess.NewStockCalculator(repo, productBarcodePGRepository)
+	process := stockprocess.NewStockCalculator(repo, productBarcodePGRepository, microservice.NewMemoryLocker())
 	process.CalculatorStock("2IZS0jFeRXWPidSupyXN7zQIlaS", "888555")
 }
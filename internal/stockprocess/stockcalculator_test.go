@@ -3,6 +3,7 @@ package stockprocess_test
 import (
 	"smlaicloudplatform/internal/stockprocess"
 	stockModel "smlaicloudplatform/internal/stockprocess/models"
+	"smlaicloudplatform/pkg/microservice"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -87,7 +88,7 @@ func TestStockProcess(t *testing.T) {
 
 	repo.On("UpdateStockTransactionChange", mock.Anything).Return(nil)
 
-	process := stockprocess.NewStockCalculator(repo, nil)
+	process := stockprocess.NewStockCalculator(repo, nil, microservice.NewMemoryLocker())
 	process.CalculatorStock("SHOPID", "BARCODE")
 
 }
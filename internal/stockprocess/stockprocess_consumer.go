@@ -22,17 +22,21 @@ type StockProcessConsumer struct {
 	calculator IStockCalculator
 	ms         *microservice.Microservice
 	cfg        config.IConfig
+	// pool calculate different barcodes in parallel and the same barcode in order
+	pool *microservice.KeyedWorkerPool
 }
 
 func NewStockProcessConsumer(ms *microservice.Microservice, cfg config.IConfig) IStockProcessConsumer {
 
 	repo := repositories.NewStockProcessPGRepository(ms.Persister(cfg.PersisterConfig()))
 	barcodeRepo := productBarcodeRepositories.NewProductBarcodePGRepository(ms.Persister(cfg.PersisterConfig()))
-	calculator := NewStockCalculator(repo, barcodeRepo)
+	calculator := NewStockCalculator(repo, barcodeRepo, ms.Locker(cfg.CacherConfig()))
+	workerCfg := cfg.ConsumerWorkerConfig()
 	return &StockProcessConsumer{
 		ms:         ms,
 		cfg:        cfg,
 		calculator: calculator,
+		pool:       ms.NewKeyedWorkerPool(workerCfg.Workers(), workerCfg.QueueSize()),
 	}
 }
 
@@ -51,9 +55,9 @@ func (c *StockProcessConsumer) RegisterConsumer(ms *microservice.Microservice) {
 	mq.CreateTopicR(stockProcessCfg.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(stockProcessCfg.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessCreate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessCreate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessCreate)
+	ms.ConsumeWithWorkerPool(c.cfg.MQConfig().URI(), stockProcessCfg.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.pool, stockProcessMessageKey, c.ConsumerStockProcessCreate)
+	ms.ConsumeWithWorkerPool(c.cfg.MQConfig().URI(), stockProcessCfg.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.pool, stockProcessMessageKey, c.ConsumerStockProcessCreate)
+	ms.ConsumeWithWorkerPool(c.cfg.MQConfig().URI(), stockProcessCfg.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.pool, stockProcessMessageKey, c.ConsumerStockProcessCreate)
 	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessBulkCreate)
 	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessBulkCreate)
 	ms.Consume(c.cfg.MQConfig().URI(), stockProcessCfg.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumerStockProcessBulkCreate)
@@ -81,15 +85,37 @@ func (c *StockProcessConsumer) ConsumerStockProcessBulkCreate(ctx microservice.I
 		return err
 	}
 
+	// bulk message is consumed outside the pool, so it can wait for its barcodes without blocking a worker
+	group := microservice.NewKeyedTaskGroup(c.pool)
 	for _, req := range stockProcessRequests {
-		go func(r models.StockProcessRequest) {
-			err = c.calculator.CalculatorStock(r.ShopID, r.Barcode)
+		r := req
+		group.Go(stockProcessKey(r), func() error {
+			err := c.calculator.CalculatorStock(r.ShopID, r.Barcode)
 			if err != nil {
 				c.ms.Logger.Errorf("Cannot Calculator Stock : %v", err.Error())
-				//return err
 			}
-		}(req)
+			return err
+		})
 	}
-	// err = c.calculator.CalculatorStock(stockProcessRequest.ShopID, stockProcessRequest.Barcode)
-	return err
+
+	return group.Wait()
+}
+
+func stockProcessKey(req models.StockProcessRequest) string {
+	return req.ShopID + ":" + req.Barcode
+}
+
+// stockProcessMessageKey order stock process messages by shopid and barcode
+func stockProcessMessageKey(msg microservice.ConsumerMessage) string {
+	event, err := microservice.DecodeEventMessage(msg.Topic, msg.Value)
+	if err != nil {
+		return msg.Key
+	}
+
+	req := models.StockProcessRequest{}
+	if err := json.Unmarshal(event.Payload, &req); err != nil {
+		return msg.Key
+	}
+
+	return stockProcessKey(req)
 }
@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/storefront/models"
 	"smlaicloudplatform/internal/storefront/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -14,11 +15,11 @@ import (
 )
 
 type IStorefrontHttpService interface {
-	CreateStorefront(shopID string, authUsername string, doc models.Storefront) (string, error)
-	UpdateStorefront(shopID string, guid string, authUsername string, doc models.Storefront) error
-	DeleteStorefront(shopID string, guid string, authUsername string) error
-	InfoStorefront(shopID string, guid string) (models.StorefrontInfo, error)
-	SearchStorefront(shopID string, pageable micromodels.Pageable) ([]models.StorefrontInfo, mongopagination.PaginationData, error)
+	CreateStorefront(ctx context.Context, shopID string, authUsername string, doc models.Storefront) (string, error)
+	UpdateStorefront(ctx context.Context, shopID string, guid string, authUsername string, doc models.Storefront) error
+	DeleteStorefront(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoStorefront(ctx context.Context, shopID string, guid string) (models.StorefrontInfo, error)
+	SearchStorefront(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.StorefrontInfo, mongopagination.PaginationData, error)
 }
 
 type StorefrontHttpService struct {
@@ -36,13 +37,13 @@ func NewStorefrontHttpService(repo repositories.IStorefrontRepository) *Storefro
 	}
 }
 
-func (svc StorefrontHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StorefrontHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StorefrontHttpService) CreateStorefront(shopID string, authUsername string, doc models.Storefront) (string, error) {
+func (svc StorefrontHttpService) CreateStorefront(ctx context.Context, shopID string, authUsername string, doc models.Storefront) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	newGuidFixed := utils.NewGUID()
@@ -64,9 +65,9 @@ func (svc StorefrontHttpService) CreateStorefront(shopID string, authUsername st
 	return newGuidFixed, nil
 }
 
-func (svc StorefrontHttpService) UpdateStorefront(shopID string, guid string, authUsername string, doc models.Storefront) error {
+func (svc StorefrontHttpService) UpdateStorefront(ctx context.Context, shopID string, guid string, authUsername string, doc models.Storefront) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -93,9 +94,9 @@ func (svc StorefrontHttpService) UpdateStorefront(shopID string, guid string, au
 	return nil
 }
 
-func (svc StorefrontHttpService) DeleteStorefront(shopID string, guid string, authUsername string) error {
+func (svc StorefrontHttpService) DeleteStorefront(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -116,9 +117,9 @@ func (svc StorefrontHttpService) DeleteStorefront(shopID string, guid string, au
 	return nil
 }
 
-func (svc StorefrontHttpService) InfoStorefront(shopID string, guid string) (models.StorefrontInfo, error) {
+func (svc StorefrontHttpService) InfoStorefront(ctx context.Context, shopID string, guid string) (models.StorefrontInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -135,9 +136,9 @@ func (svc StorefrontHttpService) InfoStorefront(shopID string, guid string) (mod
 
 }
 
-func (svc StorefrontHttpService) SearchStorefront(shopID string, pageable micromodels.Pageable) ([]models.StorefrontInfo, mongopagination.PaginationData, error) {
+func (svc StorefrontHttpService) SearchStorefront(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.StorefrontInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
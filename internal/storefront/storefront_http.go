@@ -65,7 +65,7 @@ func (h StorefrontHttp) CreateStorefront(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateStorefront(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateStorefront(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -105,7 +105,7 @@ func (h StorefrontHttp) UpdateStorefront(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateStorefront(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStorefront(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -136,7 +136,7 @@ func (h StorefrontHttp) DeleteStorefront(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStorefront(shopID, id, authUsername)
+	err := h.svc.DeleteStorefront(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -167,7 +167,7 @@ func (h StorefrontHttp) InfoStorefront(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Storefront %v", id)
-	doc, err := h.svc.InfoStorefront(shopID, id)
+	doc, err := h.svc.InfoStorefront(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -198,7 +198,7 @@ func (h StorefrontHttp) SearchStorefront(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchStorefront(shopID, pageable)
+	docList, pagination, err := h.svc.SearchStorefront(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
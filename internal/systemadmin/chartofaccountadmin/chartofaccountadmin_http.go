@@ -20,7 +20,7 @@ type ChartOfAccountAdminHttp struct {
 
 func NewChartOfAccountAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IChartOfAccountAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewChartOfAccountAdminService(mongoPersister, producer)
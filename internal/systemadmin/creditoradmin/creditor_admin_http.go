@@ -21,7 +21,7 @@ type CreditorAdminHttp struct {
 
 func NewCreditorAdminHttp(ms *microservice.Microservice, cfg config.IConfig) ICreditorAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewCreditorAdminService(mongoPersister, producer)
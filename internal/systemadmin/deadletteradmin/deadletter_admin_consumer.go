@@ -0,0 +1,58 @@
+package deadletteradmin
+
+import (
+	"encoding/json"
+	"regexp"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+// deadLetterTopicPattern subscribe every dead letter topic
+var deadLetterTopicPattern = "^.*" + regexp.QuoteMeta(microservice.DeadLetterTopicSuffix) + "$"
+
+type DeadLetterConsumer struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc IDeadLetterAdminService
+}
+
+func InitDeadLetterConsumer(ms *microservice.Microservice, cfg config.IConfig) *DeadLetterConsumer {
+	producer := ms.Producer(cfg.MQConfig())
+	mongoPersister := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	repo := NewDeadLetterAdminRepository(mongoPersister)
+	svc := NewDeadLetterAdminService(repo, producer)
+
+	return &DeadLetterConsumer{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (c *DeadLetterConsumer) RegisterConsumer(ms *microservice.Microservice) {
+	groupID := config.GetEnv("DEAD_LETTER_CONSUMER_GROUP", "dead-letter-consumer-group-01")
+
+	policy := ms.ConsumerRetryPolicy()
+	policy.DeadLetter = false
+
+	ms.ConsumeWithRetry(c.cfg.MQConfig().URI(), deadLetterTopicPattern, groupID, time.Duration(-1), policy, c.ConsumeOnDeadLetter)
+}
+
+func (c *DeadLetterConsumer) ConsumeOnDeadLetter(ctx microservice.IContext) error {
+	msg := microservice.DeadLetterMessage{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &msg)
+	if err != nil {
+		c.ms.Logger.Errorf("Cannot Unmarshal Dead Letter Message : %v", err.Error())
+		return err
+	}
+
+	err = c.svc.SaveDeadLetter(ctx.Context(), msg)
+	if err != nil {
+		c.ms.Logger.Errorf("Cannot Save Dead Letter Message : %v", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package deadletteradmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type IDeadLetterAdminHttp interface {
+	SearchDeadLetter(ctx microservice.IContext) error
+	InfoDeadLetter(ctx microservice.IContext) error
+	RedriveDeadLetter(ctx microservice.IContext) error
+	RedriveDeadLetters(ctx microservice.IContext) error
+	RegisterHttp(ms *microservice.Microservice, prefix string)
+}
+
+type DeadLetterAdminHttp struct {
+	svc IDeadLetterAdminService
+}
+
+func NewDeadLetterAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IDeadLetterAdminHttp {
+
+	producer := ms.Producer(cfg.MQConfig())
+	mongoPersister := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	repo := NewDeadLetterAdminRepository(mongoPersister)
+	svc := NewDeadLetterAdminService(repo, producer)
+
+	return &DeadLetterAdminHttp{
+		svc: svc,
+	}
+}
+
+func (h *DeadLetterAdminHttp) RegisterHttp(ms *microservice.Microservice, prefix string) {
+	ms.GET(prefix+"/deadletter", h.SearchDeadLetter)
+	ms.GET(prefix+"/deadletter/:id", h.InfoDeadLetter)
+	ms.POST(prefix+"/deadletter/:id/redrive", h.RedriveDeadLetter)
+	ms.POST(prefix+"/deadletter/redrive", h.RedriveDeadLetters)
+}
+
+// SearchDeadLetter list dead letter messages, filter by topic, groupid and status
+func (h *DeadLetterAdminHttp) SearchDeadLetter(ctx microservice.IContext) error {
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"topic", "groupid", "status"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	docList, pagination, err := h.svc.SearchDeadLetter(ctx.Context(), filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// InfoDeadLetter return dead letter message with original payload and error
+func (h *DeadLetterAdminHttp) InfoDeadLetter(ctx microservice.IContext) error {
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoDeadLetter(ctx.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			ctx.ResponseError(http.StatusNotFound, err.Error())
+			return err
+		}
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// RedriveDeadLetter send the original payload back to its source topic
+func (h *DeadLetterAdminHttp) RedriveDeadLetter(ctx microservice.IContext) error {
+	username := ctx.UserInfo().Username
+	id := ctx.Param("id")
+
+	err := h.svc.RedriveDeadLetter(ctx.Context(), id, username)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// RedriveDeadLetters send the original payloads back to their source topic
+func (h *DeadLetterAdminHttp) RedriveDeadLetters(ctx microservice.IContext) error {
+	username := ctx.UserInfo().Username
+	input := ctx.ReadInput()
+
+	req := RequestRedriveDeadLetter{}
+	err := json.Unmarshal([]byte(input), &req)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	failedIDs, err := h.svc.RedriveDeadLetters(ctx.Context(), req.IDs, username)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: len(failedIDs) == 0,
+		Data:    failedIDs,
+	})
+	return nil
+}
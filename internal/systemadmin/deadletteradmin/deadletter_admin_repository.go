@@ -0,0 +1,90 @@
+package deadletteradmin
+
+import (
+	"context"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IDeadLetterAdminRepository interface {
+	Create(ctx context.Context, doc DeadLetterDoc) error
+	FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]DeadLetterDoc, mongopagination.PaginationData, error)
+	FindByID(ctx context.Context, id string) (DeadLetterDoc, error)
+	FindByIDs(ctx context.Context, ids []string) ([]DeadLetterDoc, error)
+	UpdateRedriven(ctx context.Context, id string, username string, redrivenAt time.Time) error
+}
+
+type DeadLetterAdminRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewDeadLetterAdminRepository(pst microservice.IPersisterMongo) IDeadLetterAdminRepository {
+	return &DeadLetterAdminRepository{
+		pst: pst,
+	}
+}
+
+func (r DeadLetterAdminRepository) Create(ctx context.Context, doc DeadLetterDoc) error {
+	_, err := r.pst.Create(ctx, &DeadLetterDoc{}, doc)
+	return err
+}
+
+func (r DeadLetterAdminRepository) FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]DeadLetterDoc, mongopagination.PaginationData, error) {
+
+	filterQuery := bson.M{}
+	for key, value := range filters {
+		filterQuery[key] = value
+	}
+
+	if len(pageable.Sorts) == 0 {
+		pageable.Sorts = []micromodels.KeyInt{{Key: "failedat", Value: -1}}
+	}
+
+	docList := []DeadLetterDoc{}
+	pagination, err := r.pst.FindPage(ctx, &DeadLetterDoc{}, filterQuery, pageable, &docList)
+	if err != nil {
+		return []DeadLetterDoc{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
+
+func (r DeadLetterAdminRepository) FindByID(ctx context.Context, id string) (DeadLetterDoc, error) {
+	doc := DeadLetterDoc{}
+	err := r.pst.FindOne(ctx, &DeadLetterDoc{}, bson.M{"id": id}, &doc)
+	if err != nil {
+		return DeadLetterDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (r DeadLetterAdminRepository) FindByIDs(ctx context.Context, ids []string) ([]DeadLetterDoc, error) {
+	docList := []DeadLetterDoc{}
+	err := r.pst.Find(ctx, &DeadLetterDoc{}, bson.M{"id": bson.M{"$in": ids}}, &docList)
+	if err != nil {
+		return nil, err
+	}
+
+	return docList, nil
+}
+
+func (r DeadLetterAdminRepository) UpdateRedriven(ctx context.Context, id string, username string, redrivenAt time.Time) error {
+	return r.pst.Update(
+		ctx,
+		&DeadLetterDoc{},
+		bson.M{"id": id},
+		bson.M{
+			"$set": bson.M{
+				"status":     DeadLetterStatusRedriven,
+				"redrivenby": username,
+				"redrivenat": redrivenAt,
+			},
+			"$inc": bson.M{"redrivecount": 1},
+		},
+	)
+}
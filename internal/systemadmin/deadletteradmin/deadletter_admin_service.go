@@ -0,0 +1,143 @@
+package deadletteradmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type IDeadLetterAdminService interface {
+	SaveDeadLetter(ctx context.Context, msg microservice.DeadLetterMessage) error
+	SearchDeadLetter(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]DeadLetterDoc, mongopagination.PaginationData, error)
+	InfoDeadLetter(ctx context.Context, id string) (DeadLetterDoc, error)
+	RedriveDeadLetter(ctx context.Context, id string, username string) error
+	RedriveDeadLetters(ctx context.Context, ids []string, username string) ([]string, error)
+}
+
+type DeadLetterAdminService struct {
+	repo           IDeadLetterAdminRepository
+	producer       microservice.IProducer
+	contextTimeout time.Duration
+}
+
+func NewDeadLetterAdminService(repo IDeadLetterAdminRepository, producer microservice.IProducer) *DeadLetterAdminService {
+	contextTimeout := time.Duration(15) * time.Second
+
+	return &DeadLetterAdminService{
+		repo:           repo,
+		producer:       producer,
+		contextTimeout: contextTimeout,
+	}
+}
+
+func (svc DeadLetterAdminService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc DeadLetterAdminService) SaveDeadLetter(ctx context.Context, msg microservice.DeadLetterMessage) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if msg.ID == "" {
+		return errors.New("dead letter id is empty")
+	}
+
+	// message may be delivered more than once, keep the first one
+	findDoc, err := svc.repo.FindByID(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !findDoc.ID.IsZero() {
+		return nil
+	}
+
+	doc := DeadLetterDoc{
+		DeadLetterMessage: msg,
+		Status:            DeadLetterStatusPending,
+		CreatedAt:         time.Now(),
+	}
+
+	return svc.repo.Create(ctx, doc)
+}
+
+func (svc DeadLetterAdminService) SearchDeadLetter(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]DeadLetterDoc, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.repo.FindPage(ctx, filters, pageable)
+}
+
+func (svc DeadLetterAdminService) InfoDeadLetter(ctx context.Context, id string) (DeadLetterDoc, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.findDeadLetter(ctx, id)
+}
+
+func (svc DeadLetterAdminService) RedriveDeadLetter(ctx context.Context, id string, username string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return svc.redrive(ctx, doc, username)
+}
+
+func (svc DeadLetterAdminService) findDeadLetter(ctx context.Context, id string) (DeadLetterDoc, error) {
+	doc, err := svc.repo.FindByID(ctx, id)
+	if err != nil {
+		return DeadLetterDoc{}, err
+	}
+
+	if doc.ID.IsZero() {
+		return DeadLetterDoc{}, ErrDeadLetterNotFound
+	}
+
+	return doc, nil
+}
+
+// RedriveDeadLetters send messages back to their source topic, return ids which redrive failed
+func (svc DeadLetterAdminService) RedriveDeadLetters(ctx context.Context, ids []string, username string) ([]string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	docList, err := svc.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	failedIDs := []string{}
+	for _, doc := range docList {
+		err = svc.redrive(ctx, doc, username)
+		if err != nil {
+			failedIDs = append(failedIDs, doc.DeadLetterMessage.ID)
+		}
+	}
+
+	return failedIDs, nil
+}
+
+func (svc DeadLetterAdminService) redrive(ctx context.Context, doc DeadLetterDoc, username string) error {
+	if !json.Valid([]byte(doc.Payload)) {
+		return fmt.Errorf("dead letter %s payload is not valid json", doc.DeadLetterMessage.ID)
+	}
+
+	err := svc.producer.SendMessage(doc.Topic, doc.Key, json.RawMessage(doc.Payload))
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.UpdateRedriven(ctx, doc.DeadLetterMessage.ID, username, time.Now())
+}
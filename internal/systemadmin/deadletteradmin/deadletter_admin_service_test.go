@@ -0,0 +1,66 @@
+package deadletteradmin_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/systemadmin/deadletteradmin"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"testing"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryDeadLetterRepository return empty doc when it is not found like PersisterMongo.FindOne
+type memoryDeadLetterRepository struct {
+	docs map[string]deadletteradmin.DeadLetterDoc
+}
+
+func (repo *memoryDeadLetterRepository) Create(ctx context.Context, doc deadletteradmin.DeadLetterDoc) error {
+	doc.ID = primitive.NewObjectID()
+	repo.docs[doc.DeadLetterMessage.ID] = doc
+	return nil
+}
+
+func (repo *memoryDeadLetterRepository) FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]deadletteradmin.DeadLetterDoc, mongopagination.PaginationData, error) {
+	return nil, mongopagination.PaginationData{}, nil
+}
+
+func (repo *memoryDeadLetterRepository) FindByID(ctx context.Context, id string) (deadletteradmin.DeadLetterDoc, error) {
+	return repo.docs[id], nil
+}
+
+func (repo *memoryDeadLetterRepository) FindByIDs(ctx context.Context, ids []string) ([]deadletteradmin.DeadLetterDoc, error) {
+	return nil, nil
+}
+
+func (repo *memoryDeadLetterRepository) UpdateRedriven(ctx context.Context, id string, username string, redrivenAt time.Time) error {
+	return nil
+}
+
+func TestSaveDeadLetter(t *testing.T) {
+	repo := &memoryDeadLetterRepository{docs: map[string]deadletteradmin.DeadLetterDoc{}}
+	svc := deadletteradmin.NewDeadLetterAdminService(repo, nil)
+
+	_, err := svc.InfoDeadLetter(context.Background(), "m1")
+	assert.ErrorIs(t, err, deadletteradmin.ErrDeadLetterNotFound)
+
+	msg := microservice.DeadLetterMessage{ID: "m1", Topic: "when-sale-invoice-created", Payload: `{"docno":"SI-001"}`, Error: "first"}
+	require.NoError(t, svc.SaveDeadLetter(context.Background(), msg))
+
+	doc, err := svc.InfoDeadLetter(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, deadletteradmin.DeadLetterStatusPending, doc.Status)
+	assert.Equal(t, "first", doc.Error)
+
+	// redelivered message keep the first one
+	msg.Error = "second"
+	require.NoError(t, svc.SaveDeadLetter(context.Background(), msg))
+
+	doc, err = svc.InfoDeadLetter(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", doc.Error)
+}
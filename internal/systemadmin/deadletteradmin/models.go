@@ -0,0 +1,33 @@
+package deadletteradmin
+
+import (
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const deadLetterCollectionName = "deadLetters"
+
+const (
+	DeadLetterStatusPending  = "pending"
+	DeadLetterStatusRedriven = "redriven"
+)
+
+type DeadLetterDoc struct {
+	ID                             primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	microservice.DeadLetterMessage `bson:"inline"`
+	Status                         string    `json:"status" bson:"status"`
+	RedriveCount                   int       `json:"redrivecount" bson:"redrivecount"`
+	RedrivenBy                     string    `json:"redrivenby,omitempty" bson:"redrivenby,omitempty"`
+	RedrivenAt                     time.Time `json:"redrivenat,omitempty" bson:"redrivenat,omitempty"`
+	CreatedAt                      time.Time `json:"createdat" bson:"createdat"`
+}
+
+func (DeadLetterDoc) CollectionName() string {
+	return deadLetterCollectionName
+}
+
+type RequestRedriveDeadLetter struct {
+	IDs []string `json:"ids"`
+}
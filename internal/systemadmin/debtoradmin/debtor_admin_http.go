@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job"
+	jobServices "smlaicloudplatform/internal/job/services"
 	common "smlaicloudplatform/internal/models"
 	adminModels "smlaicloudplatform/internal/systemadmin/models"
 	"smlaicloudplatform/pkg/microservice"
@@ -16,18 +18,20 @@ type IDebtorAdminHttp interface {
 }
 
 type DebtorAdminHttp struct {
-	svc IDebtorAdminService
+	svc       IDebtorAdminService
+	jobRunner jobServices.IJobRunner
 }
 
 func NewDebtorAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IDebtorAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewDebtorAdminService(mongoPersister, producer)
 
 	return &DebtorAdminHttp{
-		svc: svc,
+		svc:       svc,
+		jobRunner: job.InitJobRunner(ms, cfg),
 	}
 }
 
@@ -51,15 +55,18 @@ func (s *DebtorAdminHttp) ReSyncDebtor(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = s.svc.ReSyncDebtor(req.ShopID)
+	jobID, err := s.jobRunner.Start(req.ShopID, ctx.UserInfo().Username, "resyncdebtor", func(job jobServices.IJobContext) (interface{}, error) {
+		return nil, s.svc.ReSyncDebtor(job, req.ShopID)
+	})
 	if err != nil {
-		ctx.Response(http.StatusBadRequest, common.ApiResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		job.ResponseStartError(ctx, err)
 		return err
 	}
 
+	ctx.Response(http.StatusAccepted, common.ApiResponse{
+		Success: true,
+		ID:      jobID,
+	})
 	return nil
 }
 
@@ -2,15 +2,20 @@ package debtoradmin
 
 import (
 	"context"
+	"fmt"
 	debtorRepositories "smlaicloudplatform/internal/debtaccount/debtor/repositories"
 	debtorProcessModels "smlaicloudplatform/internal/debtorprocess/models"
 	debtorProcessRepository "smlaicloudplatform/internal/debtorprocess/repositories"
+	jobServices "smlaicloudplatform/internal/job/services"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 )
 
+// adminJobBatchSize is number of debtors sent to message queue at a time by admin jobs
+const adminJobBatchSize = 1000
+
 type IDebtorAdminService interface {
-	ReSyncDebtor(shopID string) error
+	ReSyncDebtor(job jobServices.IJobContext, shopID string) error
 	ReCalcDebtorBalance(shopID string) error
 }
 
@@ -36,9 +41,9 @@ func NewDebtorAdminService(pst microservice.IPersisterMongo, producer microservi
 	}
 }
 
-func (svc DebtorAdminService) ReSyncDebtor(shopID string) error {
+func (svc DebtorAdminService) ReSyncDebtor(job jobServices.IJobContext, shopID string) error {
 
-	ctx, cancel := context.WithTimeout(context.Background(), svc.timeoutDuration)
+	ctx, cancel := context.WithTimeout(job.Context(), svc.timeoutDuration)
 	defer cancel()
 
 	debtors, err := svc.repo.FindDebtorByShopId(ctx, shopID)
@@ -46,9 +51,24 @@ func (svc DebtorAdminService) ReSyncDebtor(shopID string) error {
 		return err
 	}
 
-	err = svc.repoMQ.CreateInBatch(debtors)
-	if err != nil {
-		return err
+	job.Logf("resync %d debtors", len(debtors))
+
+	for start := 0; start < len(debtors); start += adminJobBatchSize {
+		if job.Context().Err() != nil {
+			return job.Context().Err()
+		}
+
+		end := start + adminJobBatchSize
+		if end > len(debtors) {
+			end = len(debtors)
+		}
+
+		err = svc.repoMQ.CreateInBatch(debtors[start:end])
+		if err != nil {
+			return err
+		}
+
+		job.Progress(end*100/len(debtors), fmt.Sprintf("sent %d/%d debtors", end, len(debtors)))
 	}
 
 	return nil
@@ -0,0 +1,103 @@
+package jobadmin
+
+import (
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job"
+	"smlaicloudplatform/internal/job/repositories"
+	"smlaicloudplatform/internal/job/services"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+type IJobAdminHttp interface {
+	SearchJob(ctx microservice.IContext) error
+	InfoJob(ctx microservice.IContext) error
+	CancelJob(ctx microservice.IContext) error
+	RegisterHttp(ms *microservice.Microservice, prefix string)
+}
+
+// JobAdminHttp poll and cancel jobs of any shop, e.g. jobs started by recalcstock and resyncdebtor
+type JobAdminHttp struct {
+	svc services.IJobService
+}
+
+func NewJobAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IJobAdminHttp {
+	repo := repositories.NewJobRepository(ms.MongoPersister(cfg.MongoPersisterConfig()))
+	svc := services.NewJobService(repo, job.InitJobRunner(ms, cfg), cfg.JobConfig(), time.Now)
+
+	return &JobAdminHttp{
+		svc: svc,
+	}
+}
+
+func (h *JobAdminHttp) RegisterHttp(ms *microservice.Microservice, prefix string) {
+	ms.GET(prefix+"/job/:shopid", h.SearchJob)
+	ms.GET(prefix+"/job/:shopid/:id", h.InfoJob)
+	ms.POST(prefix+"/job/:shopid/:id/cancel", h.CancelJob)
+}
+
+// SearchJob list jobs of the shop, filter by kind and status
+func (h *JobAdminHttp) SearchJob(ctx microservice.IContext) error {
+	shopID := ctx.Param("shopid")
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"kind", "status"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	docList, pagination, err := h.svc.SearchJob(ctx.Context(), shopID, filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// InfoJob return status, progress, log lines and result of the job
+func (h *JobAdminHttp) InfoJob(ctx microservice.IContext) error {
+	shopID := ctx.Param("shopid")
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoJob(ctx.Context(), shopID, id)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// CancelJob cancel queued or running job of the shop
+func (h *JobAdminHttp) CancelJob(ctx microservice.IContext) error {
+	shopID := ctx.Param("shopid")
+	id := ctx.Param("id")
+
+	err := h.svc.CancelJob(ctx.Context(), shopID, id)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
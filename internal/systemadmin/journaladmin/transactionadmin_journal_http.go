@@ -13,6 +13,7 @@ type IJournalTransactionAdminHttp interface {
 	RegisterHttp(ms *microservice.Microservice, prefix string)
 	ReSyncJournalTransaction(ms microservice.IContext) error
 	ReSyncJournalDeleteTransaction(ms microservice.IContext) error
+	ReGenerateGuidEmpty(ms microservice.IContext) error
 }
 
 type JournalTransactionAdminHttp struct {
@@ -21,7 +22,7 @@ type JournalTransactionAdminHttp struct {
 
 func NewJournalTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IJournalTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewJournalTransactionAdminService(mongoPersister, producer)
@@ -34,6 +35,7 @@ func NewJournalTransactionAdminHttp(ms *microservice.Microservice, cfg config.IC
 func (s *JournalTransactionAdminHttp) RegisterHttp(ms *microservice.Microservice, prefix string) {
 	ms.POST(prefix+"/transactionadmin/journal/resynctransaction", s.ReSyncJournalTransaction)
 	ms.POST(prefix+"/transactionadmin/journal/resyncdeletetransaction", s.ReSyncJournalDeleteTransaction)
+	ms.POST(prefix+"/transactionadmin/journal/regenguidempty", s.ReGenerateGuidEmpty)
 }
 
 func (s *JournalTransactionAdminHttp) ReSyncJournalTransaction(ctx microservice.IContext) error {
@@ -95,3 +97,20 @@ func (h *JournalTransactionAdminHttp) ReSyncJournalDeleteTransaction(ctx microse
 	})
 	return nil
 }
+
+func (h *JournalTransactionAdminHttp) ReGenerateGuidEmpty(ctx microservice.IContext) error {
+
+	err := h.svc.ReGenerateGuidEmpty()
+	if err != nil {
+		ctx.Response(http.StatusBadRequest, common.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ResponseSuccess{
+		Success: true,
+	})
+	return nil
+}
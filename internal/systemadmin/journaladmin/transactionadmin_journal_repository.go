@@ -8,10 +8,13 @@ import (
 
 	"github.com/smlsoft/mongopagination"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type IJournalTransactionAdminRepository interface {
 	FindJournalTransactionDocByShopID(ctx context.Context, shopID string, isDeleted bool, pageable msModels.Pageable) ([]journalModels.JournalDoc, mongopagination.PaginationData, error)
+	FindGuidEmptyAll(ctx context.Context) ([]journalModels.JournalDoc, error)
+	UpdateGuidEmpty(ctx context.Context, id primitive.ObjectID, guidfixed string) error
 }
 
 type JournalTransactionAdminRepository struct {
@@ -40,3 +43,19 @@ func (r JournalTransactionAdminRepository) FindJournalTransactionDocByShopID(ctx
 
 	return docList, pagination, nil
 }
+
+func (r JournalTransactionAdminRepository) FindGuidEmptyAll(ctx context.Context) ([]journalModels.JournalDoc, error) {
+
+	docList := []journalModels.JournalDoc{}
+
+	err := r.pst.Find(ctx, &journalModels.JournalDoc{}, bson.M{"guidfixed": ""}, &docList)
+	if err != nil {
+		return nil, err
+	}
+
+	return docList, nil
+}
+
+func (r JournalTransactionAdminRepository) UpdateGuidEmpty(ctx context.Context, id primitive.ObjectID, guidfixed string) error {
+	return r.pst.UpdateOne(ctx, &journalModels.JournalDoc{}, bson.M{"_id": id}, bson.M{"guidfixed": guidfixed})
+}
@@ -2,6 +2,7 @@ package journal
 
 import (
 	"context"
+	"smlaicloudplatform/internal/utils"
 	journalRepositories "smlaicloudplatform/internal/vfgl/journal/repositories"
 	"smlaicloudplatform/pkg/microservice"
 	msModels "smlaicloudplatform/pkg/microservice/models"
@@ -11,6 +12,7 @@ import (
 type IJournalTransactionAdminService interface {
 	ReSyncJournalTransactionDoc(shopID string) error
 	ReSyncJournalDeleteTransactionDoc(shopID string) error
+	ReGenerateGuidEmpty() error
 }
 
 type JournalTransactionAdminService struct {
@@ -105,3 +107,23 @@ func (s *JournalTransactionAdminService) ReSyncJournalDeleteTransactionDoc(shopI
 
 	return nil
 }
+
+// ReGenerateGuidEmpty set new guidfixed to journal of every shop which has empty guidfixed
+func (s *JournalTransactionAdminService) ReGenerateGuidEmpty() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	defer cancel()
+
+	docs, err := s.mongoRepo.FindGuidEmptyAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		err = s.mongoRepo.UpdateGuidEmpty(ctx, doc.ID, utils.NewGUID())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
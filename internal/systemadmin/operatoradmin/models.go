@@ -0,0 +1,110 @@
+package operatoradmin
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	operatorCollectionName         = "operators"
+	operatorLogCollectionName      = "operatorLogs"
+	operatorApprovalCollectionName = "operatorApprovals"
+)
+
+const (
+	HeaderOperatorReason   = "X-Operator-Reason"
+	HeaderOperatorApproval = "X-Operator-Approval"
+)
+
+const (
+	OperatorLogLogin       = "login"
+	OperatorLogLoginFailed = "login_failed"
+	OperatorLogLogout      = "logout"
+	OperatorLogRequest     = "request"
+	OperatorLogResponse    = "response"
+	OperatorLogDenied      = "denied"
+	OperatorLogApproval    = "approval_requested"
+	OperatorLogApprove     = "approve"
+	OperatorLogReject      = "reject"
+	OperatorLogAccount     = "account"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusUsed     = "used"
+)
+
+// OperatorDoc is system administrator account, it is not user of any shop and it login only to /systemadm routes
+type OperatorDoc struct {
+	ID           primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	Username     string             `json:"username" bson:"username"`
+	Name         string             `json:"name" bson:"name"`
+	PasswordHash string             `json:"-" bson:"passwordhash"`
+	IsActive     bool               `json:"isactive" bson:"isactive"`
+	LastLoginAt  *time.Time         `json:"lastloginat,omitempty" bson:"lastloginat,omitempty"`
+	CreatedAt    time.Time          `json:"createdat" bson:"createdat"`
+	UpdatedAt    time.Time          `json:"updatedat" bson:"updatedat"`
+}
+
+func (OperatorDoc) CollectionName() string {
+	return operatorCollectionName
+}
+
+// OperatorLogDoc is record of operator call, it is only inserted and never updated or deleted
+type OperatorLogDoc struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RequestID  string             `json:"requestid,omitempty" bson:"requestid,omitempty"`
+	Operator   string             `json:"operator" bson:"operator"`
+	Action     string             `json:"action" bson:"action"`
+	Method     string             `json:"method,omitempty" bson:"method,omitempty"`
+	Route      string             `json:"route,omitempty" bson:"route,omitempty"`
+	Path       string             `json:"path,omitempty" bson:"path,omitempty"`
+	Query      string             `json:"query,omitempty" bson:"query,omitempty"`
+	Reason     string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	ApprovalID string             `json:"approvalid,omitempty" bson:"approvalid,omitempty"`
+	StatusCode int                `json:"statuscode,omitempty" bson:"statuscode,omitempty"`
+	Message    string             `json:"message,omitempty" bson:"message,omitempty"`
+	IP         string             `json:"ip,omitempty" bson:"ip,omitempty"`
+	CreatedAt  time.Time          `json:"createdat" bson:"createdat"`
+}
+
+func (OperatorLogDoc) CollectionName() string {
+	return operatorLogCollectionName
+}
+
+// OperatorApprovalDoc is destructive request which wait for approval of another operator,
+// the approval is used once by the same request of the operator who ask for it
+type OperatorApprovalDoc struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RequestedBy string             `json:"requestedby" bson:"requestedby"`
+	Method      string             `json:"method" bson:"method"`
+	Route       string             `json:"route" bson:"route"`
+	Path        string             `json:"path" bson:"path"`
+	Query       string             `json:"query" bson:"query"`
+	Body        string             `json:"body" bson:"body"`
+	BodyHash    string             `json:"-" bson:"bodyhash"`
+	Reason      string             `json:"reason" bson:"reason"`
+	Status      string             `json:"status" bson:"status"`
+	DecidedBy   string             `json:"decidedby,omitempty" bson:"decidedby,omitempty"`
+	DecidedAt   *time.Time         `json:"decidedat,omitempty" bson:"decidedat,omitempty"`
+	UsedAt      *time.Time         `json:"usedat,omitempty" bson:"usedat,omitempty"`
+	ExpiresAt   time.Time          `json:"expiresat" bson:"expiresat"`
+	CreatedAt   time.Time          `json:"createdat" bson:"createdat"`
+}
+
+func (OperatorApprovalDoc) CollectionName() string {
+	return operatorApprovalCollectionName
+}
+
+type OperatorLoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type OperatorLoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresat"`
+}
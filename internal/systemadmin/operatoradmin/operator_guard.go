@@ -0,0 +1,182 @@
+package operatoradmin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/models"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OperatorGuard authenticate operator on every route under the prefix, request which change data must have
+// reason and destructive route must be approved by another operator when approval is enabled,
+// every request is written to operator log before it is run and it is denied when the log cannot be written
+type OperatorGuard struct {
+	svc         IOperatorService
+	cfg         config.IOperatorConfig
+	logger      logger.ILogger
+	prefix      string
+	publicPath  map[string]bool
+	destructive map[string]bool
+}
+
+func NewOperatorGuard(svc IOperatorService, cfg config.IOperatorConfig, logger logger.ILogger, prefix string, destructiveRoutes []string) *OperatorGuard {
+	destructive := map[string]bool{}
+	for _, route := range destructiveRoutes {
+		destructive[prefix+route] = true
+	}
+
+	return &OperatorGuard{
+		svc:         svc,
+		cfg:         cfg,
+		logger:      logger,
+		prefix:      prefix,
+		publicPath:  map[string]bool{prefix + operatorLoginRoute: true},
+		destructive: destructive,
+	}
+}
+
+func bearerToken(authorization string) string {
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+func (g *OperatorGuard) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// route of the request is checked instead of the raw path so every route registered under the prefix is guarded
+			routePath := c.Path()
+			if !strings.HasPrefix(routePath, g.prefix+"/") || g.publicPath[routePath] {
+				return next(c)
+			}
+
+			operator, err := g.svc.Authenticate(c.Request().Context(), bearerToken(c.Request().Header.Get("Authorization")))
+			if errors.Is(err, ErrOperatorTokenInvalid) {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Operator token invalid."})
+			}
+
+			if err != nil {
+				g.logger.Errorf("Operator token cannot be checked: %v", err)
+				return c.JSON(http.StatusInternalServerError, map[string]interface{}{"success": false, "message": "operator token cannot be checked"})
+			}
+
+			req, err := g.readRequest(c, operator)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]interface{}{"success": false, "message": err.Error()})
+			}
+
+			if req.Method != http.MethodGet && len([]rune(req.Reason)) < g.cfg.ReasonMinLength() {
+				g.record(req, OperatorLogDenied, "", 0, "reason is required")
+				return c.JSON(http.StatusBadRequest, map[string]interface{}{
+					"success": false,
+					"message": "reason is required in header " + HeaderOperatorReason,
+				})
+			}
+
+			approvalID := ""
+			if g.cfg.RequireApproval() && g.destructive[req.Route] {
+				approvalID = c.Request().Header.Get(HeaderOperatorApproval)
+
+				if approvalID == "" {
+					approval, err := g.svc.RequestApproval(c.Request().Context(), req)
+					if err != nil {
+						g.logger.Errorf("Operator approval cannot be requested: %v", err)
+						return c.JSON(http.StatusInternalServerError, map[string]interface{}{"success": false, "message": "approval cannot be requested"})
+					}
+
+					g.record(req, OperatorLogApproval, approval.ID.Hex(), 0, "")
+					return c.JSON(http.StatusAccepted, map[string]interface{}{
+						"success": false,
+						"message": "approval of another operator is required, send the same request again with header " + HeaderOperatorApproval + " after it is approved",
+						"data":    approval,
+					})
+				}
+
+				_, err = g.svc.UseApproval(c.Request().Context(), approvalID, req)
+				if err != nil {
+					g.record(req, OperatorLogDenied, approvalID, 0, err.Error())
+					return c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": err.Error()})
+				}
+			}
+
+			err = g.record(req, OperatorLogRequest, approvalID, 0, "")
+			if err != nil {
+				return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{"success": false, "message": "operator log cannot be written"})
+			}
+
+			c.Set("UserInfo", models.UserInfo{Username: operator, Name: operator})
+			// system admin apis query across shops
+			c.SetRequest(c.Request().WithContext(microservice.WithSystemAdmin(c.Request().Context())))
+
+			err = next(c)
+
+			message := ""
+			if err != nil {
+				message = err.Error()
+			}
+			g.record(req, OperatorLogResponse, approvalID, c.Response().Status, message)
+
+			return err
+		}
+	}
+}
+
+// readRequest return the request of the operator, body is read and put back for the handler
+func (g *OperatorGuard) readRequest(c echo.Context, operator string) (OperatorRequest, error) {
+	httpReq := c.Request()
+
+	body := []byte{}
+	if httpReq.Body != nil {
+		var err error
+		body, err = io.ReadAll(httpReq.Body)
+		if err != nil {
+			return OperatorRequest{}, err
+		}
+		httpReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return OperatorRequest{
+		RequestID: utils.NewGUID(),
+		Operator:  operator,
+		Method:    httpReq.Method,
+		Route:     c.Path(),
+		Path:      httpReq.URL.Path,
+		Query:     httpReq.URL.RawQuery,
+		Body:      body,
+		Reason:    strings.TrimSpace(httpReq.Header.Get(HeaderOperatorReason)),
+		IP:        c.RealIP(),
+	}, nil
+}
+
+func (g *OperatorGuard) record(req OperatorRequest, action string, approvalID string, statusCode int, message string) error {
+	err := g.svc.RecordLog(context.Background(), OperatorLogDoc{
+		RequestID:  req.RequestID,
+		Operator:   req.Operator,
+		Action:     action,
+		Method:     req.Method,
+		Route:      req.Route,
+		Path:       req.Path,
+		Query:      req.Query,
+		Reason:     req.Reason,
+		ApprovalID: approvalID,
+		StatusCode: statusCode,
+		Message:    message,
+		IP:         req.IP,
+	})
+
+	if err != nil {
+		g.logger.Errorf("Operator log of %s %s by %s cannot be written: %v", req.Method, req.Path, req.Operator, err)
+	}
+
+	return err
+}
@@ -0,0 +1,289 @@
+package operatoradmin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/smlsoft/mongopagination"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type guardTestConfig struct {
+	requireApproval bool
+}
+
+func (guardTestConfig) TokenExpire() time.Duration     { return time.Hour }
+func (guardTestConfig) MaxLoginFailures() int          { return 5 }
+func (guardTestConfig) LockoutDuration() time.Duration { return time.Minute }
+func (guardTestConfig) ReasonMinLength() int           { return 10 }
+func (c guardTestConfig) RequireApproval() bool        { return c.requireApproval }
+func (guardTestConfig) ApprovalExpire() time.Duration  { return time.Minute }
+
+type memoryOperatorService struct {
+	tokens    map[string]string
+	logs      []OperatorLogDoc
+	approvals map[string]OperatorApprovalDoc
+}
+
+func newMemoryOperatorService() *memoryOperatorService {
+	return &memoryOperatorService{
+		tokens:    map[string]string{"token-a": "alice", "token-b": "bob"},
+		approvals: map[string]OperatorApprovalDoc{},
+	}
+}
+
+func (svc *memoryOperatorService) Login(ctx context.Context, username string, password string, ip string) (OperatorLoginResponse, error) {
+	return OperatorLoginResponse{}, nil
+}
+
+func (svc *memoryOperatorService) Logout(ctx context.Context, token string, operator string, ip string) error {
+	return nil
+}
+
+func (svc *memoryOperatorService) Authenticate(ctx context.Context, token string) (string, error) {
+	username, ok := svc.tokens[token]
+	if !ok {
+		return "", ErrOperatorTokenInvalid
+	}
+	return username, nil
+}
+
+func (svc *memoryOperatorService) CreateOperator(ctx context.Context, username string, name string, password string) error {
+	return nil
+}
+
+func (svc *memoryOperatorService) SetPassword(ctx context.Context, username string, password string) error {
+	return nil
+}
+
+func (svc *memoryOperatorService) SetActive(ctx context.Context, username string, isActive bool) error {
+	return nil
+}
+
+func (svc *memoryOperatorService) RecordLog(ctx context.Context, doc OperatorLogDoc) error {
+	svc.logs = append(svc.logs, doc)
+	return nil
+}
+
+func (svc *memoryOperatorService) RequestApproval(ctx context.Context, req OperatorRequest) (OperatorApprovalDoc, error) {
+	doc := OperatorApprovalDoc{
+		ID:          primitive.NewObjectID(),
+		RequestedBy: req.Operator,
+		Method:      req.Method,
+		Path:        req.Path,
+		Query:       req.Query,
+		BodyHash:    req.BodyHash(),
+		Status:      ApprovalStatusPending,
+	}
+	svc.approvals[doc.ID.Hex()] = doc
+	return doc, nil
+}
+
+func (svc *memoryOperatorService) UseApproval(ctx context.Context, id string, req OperatorRequest) (OperatorApprovalDoc, error) {
+	doc, ok := svc.approvals[id]
+	if !ok || doc.Status != ApprovalStatusApproved || doc.RequestedBy != req.Operator || doc.Path != req.Path || doc.BodyHash != req.BodyHash() {
+		return OperatorApprovalDoc{}, ErrApprovalInvalid
+	}
+
+	doc.Status = ApprovalStatusUsed
+	svc.approvals[id] = doc
+	return doc, nil
+}
+
+func (svc *memoryOperatorService) DecideApproval(ctx context.Context, id string, operator string, approve bool) error {
+	doc, ok := svc.approvals[id]
+	if !ok {
+		return ErrApprovalNotFound
+	}
+
+	if doc.RequestedBy == operator {
+		return ErrApprovalSelf
+	}
+
+	doc.Status = ApprovalStatusRejected
+	if approve {
+		doc.Status = ApprovalStatusApproved
+	}
+	doc.DecidedBy = operator
+	svc.approvals[id] = doc
+	return nil
+}
+
+func (svc *memoryOperatorService) SearchLog(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorLogDoc, mongopagination.PaginationData, error) {
+	return svc.logs, mongopagination.PaginationData{}, nil
+}
+
+func (svc *memoryOperatorService) SearchApproval(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorApprovalDoc, mongopagination.PaginationData, error) {
+	return nil, mongopagination.PaginationData{}, nil
+}
+
+func newGuardTestServer(svc IOperatorService, cfg config.IOperatorConfig, called *int) *echo.Echo {
+	e := echo.New()
+	guard := NewOperatorGuard(svc, cfg, logger.NewAppLogger(config.NewLoggerConfig()), "/systemadm", []string{"/productadmin/recalcstock"})
+	e.Use(guard.Middleware())
+
+	handler := func(c echo.Context) error {
+		*called++
+		return c.NoContent(http.StatusOK)
+	}
+
+	e.GET("/systemadm/shopadmin/list", handler)
+	e.POST("/systemadm/productadmin/recalcstock", handler)
+	e.POST("/systemadm/debtoradmin/resyncdebtor", handler)
+	e.POST("/systemadm/operator/login", handler)
+	e.GET("/shop", handler)
+
+	return e
+}
+
+func serveGuard(e *echo.Echo, method string, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOperatorGuardRequireToken(t *testing.T) {
+	svc := newMemoryOperatorService()
+	called := 0
+	e := newGuardTestServer(svc, guardTestConfig{}, &called)
+
+	rec := serveGuard(e, http.MethodGet, "/systemadm/shopadmin/list", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serveGuard(e, http.MethodGet, "/systemadm/shopadmin/list", "", map[string]string{"Authorization": "Bearer unknown"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serveGuard(e, http.MethodPost, "/systemadm/operator/login", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serveGuard(e, http.MethodGet, "/shop", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, 2, called)
+}
+
+func TestOperatorGuardPathPrefix(t *testing.T) {
+	svc := newMemoryOperatorService()
+	e := echo.New()
+	guard := NewOperatorGuard(svc, guardTestConfig{}, logger.NewAppLogger(config.NewLoggerConfig()), "/api/systemadm", nil)
+	e.Use(guard.Middleware())
+
+	called := 0
+	handler := func(c echo.Context) error {
+		called++
+		return c.NoContent(http.StatusOK)
+	}
+
+	e.GET("/api/systemadm/shopadmin/list", handler)
+	e.POST("/api/systemadm/operator/login", handler)
+
+	rec := serveGuard(e, http.MethodGet, "/api/systemadm/shopadmin/list", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serveGuard(e, http.MethodPost, "/api/systemadm/operator/login", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, 1, called)
+}
+
+func TestOperatorGuardRequireReason(t *testing.T) {
+	svc := newMemoryOperatorService()
+	called := 0
+	e := newGuardTestServer(svc, guardTestConfig{}, &called)
+
+	rec := serveGuard(e, http.MethodPost, "/systemadm/debtoradmin/resyncdebtor", `{"shopid":"s1"}`, map[string]string{
+		"Authorization":      "Bearer token-a",
+		HeaderOperatorReason: "short",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 0, called)
+	assert.Equal(t, OperatorLogDenied, svc.logs[len(svc.logs)-1].Action)
+
+	rec = serveGuard(e, http.MethodPost, "/systemadm/debtoradmin/resyncdebtor", `{"shopid":"s1"}`, map[string]string{
+		"Authorization":      "Bearer token-a",
+		HeaderOperatorReason: "ticket 1234 debtor balance is wrong",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, called)
+
+	actions := []string{}
+	for _, doc := range svc.logs {
+		actions = append(actions, doc.Action)
+	}
+	assert.Equal(t, []string{OperatorLogDenied, OperatorLogRequest, OperatorLogResponse}, actions)
+
+	last := svc.logs[len(svc.logs)-1]
+	assert.Equal(t, "alice", last.Operator)
+	assert.Equal(t, "/systemadm/debtoradmin/resyncdebtor", last.Route)
+	assert.Equal(t, "ticket 1234 debtor balance is wrong", last.Reason)
+	assert.Equal(t, http.StatusOK, last.StatusCode)
+	assert.Equal(t, svc.logs[1].RequestID, last.RequestID)
+
+	// read only route need no reason
+	rec = serveGuard(e, http.MethodGet, "/systemadm/shopadmin/list", "", map[string]string{"Authorization": "Bearer token-a"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestOperatorGuardTwoPersonApproval(t *testing.T) {
+	svc := newMemoryOperatorService()
+	called := 0
+	e := newGuardTestServer(svc, guardTestConfig{requireApproval: true}, &called)
+
+	body := `{"shopid":"s1"}`
+	headers := map[string]string{
+		"Authorization":      "Bearer token-a",
+		HeaderOperatorReason: "ticket 1234 stock balance is wrong",
+	}
+
+	rec := serveGuard(e, http.MethodPost, "/systemadm/productadmin/recalcstock", body, headers)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, 0, called)
+	assert.Len(t, svc.approvals, 1)
+
+	approvalID := ""
+	for id := range svc.approvals {
+		approvalID = id
+	}
+
+	// operator cannot approve its own request
+	assert.ErrorIs(t, svc.DecideApproval(context.Background(), approvalID, "alice", true), ErrApprovalSelf)
+
+	headers[HeaderOperatorApproval] = approvalID
+	rec = serveGuard(e, http.MethodPost, "/systemadm/productadmin/recalcstock", body, headers)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 0, called)
+
+	assert.NoError(t, svc.DecideApproval(context.Background(), approvalID, "bob", true))
+
+	// approval is for the request body which is approved only
+	rec = serveGuard(e, http.MethodPost, "/systemadm/productadmin/recalcstock", `{"shopid":"s2"}`, headers)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serveGuard(e, http.MethodPost, "/systemadm/productadmin/recalcstock", body, headers)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, called)
+
+	// approval is used once
+	rec = serveGuard(e, http.MethodPost, "/systemadm/productadmin/recalcstock", body, headers)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, 1, called)
+
+	// route which is not destructive need no approval
+	delete(headers, HeaderOperatorApproval)
+	rec = serveGuard(e, http.MethodPost, "/systemadm/debtoradmin/resyncdebtor", body, headers)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, called)
+}
@@ -0,0 +1,213 @@
+package operatoradmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+
+	"github.com/labstack/echo/v4"
+)
+
+const operatorLoginRoute = "/operator/login"
+
+type IOperatorAdminHttp interface {
+	Login(ctx microservice.IContext) error
+	Logout(ctx microservice.IContext) error
+	SearchLog(ctx microservice.IContext) error
+	SearchApproval(ctx microservice.IContext) error
+	Approve(ctx microservice.IContext) error
+	Reject(ctx microservice.IContext) error
+	Guard(prefix string, destructiveRoutes []string) echo.MiddlewareFunc
+	RegisterHttp(ms *microservice.Microservice, prefix string)
+}
+
+type OperatorAdminHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc IOperatorService
+}
+
+// InitOperatorService return operator service of mongo and cacher of the config
+func InitOperatorService(ms *microservice.Microservice, cfg config.IConfig) IOperatorService {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return NewOperatorService(
+		NewOperatorRepository(pst),
+		NewOperatorLogRepository(pst),
+		NewOperatorApprovalRepository(pst),
+		ms.Cacher(cfg.CacherConfig()),
+		cfg.OperatorConfig(),
+		ms.TimeNow,
+	)
+}
+
+func NewOperatorAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IOperatorAdminHttp {
+	return &OperatorAdminHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: InitOperatorService(ms, cfg),
+	}
+}
+
+// Guard return middleware which authenticate operator on every route under the prefix,
+// routes are registered under path prefix of the microservice so it is guarded with the prefix
+func (h *OperatorAdminHttp) Guard(prefix string, destructiveRoutes []string) echo.MiddlewareFunc {
+	return NewOperatorGuard(h.svc, h.cfg.OperatorConfig(), h.ms.Logger, h.ms.PathPrefix()+prefix, destructiveRoutes).Middleware()
+}
+
+func (h *OperatorAdminHttp) RegisterHttp(ms *microservice.Microservice, prefix string) {
+	ms.POST(prefix+operatorLoginRoute, h.Login)
+	ms.POST(prefix+"/operator/logout", h.Logout)
+	ms.GET(prefix+"/operator/logs", h.SearchLog)
+	ms.GET(prefix+"/operator/approvals", h.SearchApproval)
+	ms.POST(prefix+"/operator/approvals/:id/approve", h.Approve)
+	ms.POST(prefix+"/operator/approvals/:id/reject", h.Reject)
+}
+
+// Login return operator token, it is sent as bearer token to system admin routes
+func (h *OperatorAdminHttp) Login(ctx microservice.IContext) error {
+	input := ctx.ReadInput()
+
+	req := OperatorLoginRequest{}
+	err := json.Unmarshal([]byte(input), &req)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(&req); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	token, err := h.svc.Login(ctx.Context(), req.Username, req.Password, ctx.RealIp())
+
+	if errors.Is(err, ErrOperatorLoginFailed) || errors.Is(err, ErrOperatorLocked) {
+		ctx.ResponseError(http.StatusUnauthorized, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    token,
+	})
+	return nil
+}
+
+func (h *OperatorAdminHttp) Logout(ctx microservice.IContext) error {
+	err := h.svc.Logout(ctx.Context(), bearerToken(ctx.Header("Authorization")), ctx.UserInfo().Username, ctx.RealIp())
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ResponseSuccess{
+		Success: true,
+	})
+	return nil
+}
+
+// SearchLog list operator log newest first, filter by operator, action and approvalid
+func (h *OperatorAdminHttp) SearchLog(ctx microservice.IContext) error {
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"operator", "action", "approvalid", "requestid"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	docList, pagination, err := h.svc.SearchLog(ctx.Context(), filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// SearchApproval list approval requests newest first, filter by status and requestedby
+func (h *OperatorAdminHttp) SearchApproval(ctx microservice.IContext) error {
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"status", "requestedby"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	docList, pagination, err := h.svc.SearchApproval(ctx.Context(), filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Approve allow the operator who request the approval to run the request once
+func (h *OperatorAdminHttp) Approve(ctx microservice.IContext) error {
+	return h.decide(ctx, true)
+}
+
+// Reject deny the approval, the request has to be approved again with new approval
+func (h *OperatorAdminHttp) Reject(ctx microservice.IContext) error {
+	return h.decide(ctx, false)
+}
+
+func (h *OperatorAdminHttp) decide(ctx microservice.IContext, approve bool) error {
+	id := ctx.Param("id")
+
+	err := h.svc.DecideApproval(ctx.Context(), id, ctx.UserInfo().Username, approve)
+
+	if errors.Is(err, ErrApprovalNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if errors.Is(err, ErrApprovalSelf) {
+		ctx.ResponseError(http.StatusForbidden, err.Error())
+		return err
+	}
+
+	if errors.Is(err, ErrApprovalNotPending) {
+		ctx.ResponseError(http.StatusConflict, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
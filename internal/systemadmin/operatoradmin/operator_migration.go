@@ -0,0 +1,60 @@
+package operatoradmin
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create unique username of operators and index of operator log and approval search,
+// operator log has no ttl index, it is kept until it is archived outside the application
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	operatorCollection, err := pst.Exec(context.Background(), &OperatorDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = operatorCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}},
+		Options: options.Index().SetName("operator_username").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	logCollection, err := pst.Exec(context.Background(), &OperatorLogDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = logCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("operatorlog_createdat"),
+		},
+		{
+			Keys:    bson.D{{Key: "operator", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("operatorlog_operator_createdat"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	approvalCollection, err := pst.Exec(context.Background(), &OperatorApprovalDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = approvalCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdat", Value: -1}},
+		Options: options.Index().SetName("operatorapproval_status_createdat"),
+	})
+	return err
+}
@@ -0,0 +1,224 @@
+package operatoradmin
+
+import (
+	"context"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IOperatorRepository interface {
+	Create(ctx context.Context, doc OperatorDoc) error
+	FindByUsername(ctx context.Context, username string) (OperatorDoc, error)
+	UpdatePassword(ctx context.Context, username string, passwordHash string, updatedAt time.Time) error
+	UpdateActive(ctx context.Context, username string, isActive bool, updatedAt time.Time) error
+	UpdateLastLogin(ctx context.Context, username string, loginAt time.Time) error
+}
+
+type OperatorRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewOperatorRepository(pst microservice.IPersisterMongo) IOperatorRepository {
+	return &OperatorRepository{
+		pst: pst,
+	}
+}
+
+func (r OperatorRepository) Create(ctx context.Context, doc OperatorDoc) error {
+	_, err := r.pst.Create(ctx, &OperatorDoc{}, doc)
+	return err
+}
+
+func (r OperatorRepository) FindByUsername(ctx context.Context, username string) (OperatorDoc, error) {
+	doc := OperatorDoc{}
+	err := r.pst.FindOne(ctx, &OperatorDoc{}, bson.M{"username": username}, &doc)
+	if err != nil {
+		return OperatorDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (r OperatorRepository) UpdatePassword(ctx context.Context, username string, passwordHash string, updatedAt time.Time) error {
+	return r.pst.Update(ctx, &OperatorDoc{}, bson.M{"username": username}, bson.M{
+		"$set": bson.M{"passwordhash": passwordHash, "updatedat": updatedAt},
+	})
+}
+
+func (r OperatorRepository) UpdateActive(ctx context.Context, username string, isActive bool, updatedAt time.Time) error {
+	return r.pst.Update(ctx, &OperatorDoc{}, bson.M{"username": username}, bson.M{
+		"$set": bson.M{"isactive": isActive, "updatedat": updatedAt},
+	})
+}
+
+func (r OperatorRepository) UpdateLastLogin(ctx context.Context, username string, loginAt time.Time) error {
+	return r.pst.Update(ctx, &OperatorDoc{}, bson.M{"username": username}, bson.M{
+		"$set": bson.M{"lastloginat": loginAt},
+	})
+}
+
+// IOperatorLogRepository has no update or delete so operator log cannot be changed through the application
+type IOperatorLogRepository interface {
+	Create(ctx context.Context, doc OperatorLogDoc) error
+	FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorLogDoc, mongopagination.PaginationData, error)
+}
+
+type OperatorLogRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewOperatorLogRepository(pst microservice.IPersisterMongo) IOperatorLogRepository {
+	return &OperatorLogRepository{
+		pst: pst,
+	}
+}
+
+func (r OperatorLogRepository) Create(ctx context.Context, doc OperatorLogDoc) error {
+	_, err := r.pst.Create(ctx, &OperatorLogDoc{}, doc)
+	return err
+}
+
+func (r OperatorLogRepository) FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorLogDoc, mongopagination.PaginationData, error) {
+
+	filterQuery := bson.M{}
+	for key, value := range filters {
+		filterQuery[key] = value
+	}
+
+	if len(pageable.Sorts) == 0 {
+		pageable.Sorts = []micromodels.KeyInt{{Key: "createdat", Value: -1}}
+	}
+
+	docList := []OperatorLogDoc{}
+	pagination, err := r.pst.FindPage(ctx, &OperatorLogDoc{}, filterQuery, pageable, &docList)
+	if err != nil {
+		return []OperatorLogDoc{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
+
+type IOperatorApprovalRepository interface {
+	Create(ctx context.Context, doc OperatorApprovalDoc) (primitive.ObjectID, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (OperatorApprovalDoc, error)
+	FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorApprovalDoc, mongopagination.PaginationData, error)
+	Decide(ctx context.Context, id primitive.ObjectID, decidedBy string, status string, now time.Time) (bool, error)
+	Use(ctx context.Context, id primitive.ObjectID, req OperatorRequest, now time.Time) (OperatorApprovalDoc, bool, error)
+}
+
+type OperatorApprovalRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewOperatorApprovalRepository(pst microservice.IPersisterMongo) IOperatorApprovalRepository {
+	return &OperatorApprovalRepository{
+		pst: pst,
+	}
+}
+
+func (r OperatorApprovalRepository) Create(ctx context.Context, doc OperatorApprovalDoc) (primitive.ObjectID, error) {
+	return r.pst.Create(ctx, &OperatorApprovalDoc{}, doc)
+}
+
+func (r OperatorApprovalRepository) FindByID(ctx context.Context, id primitive.ObjectID) (OperatorApprovalDoc, error) {
+	doc := OperatorApprovalDoc{}
+	err := r.pst.FindOne(ctx, &OperatorApprovalDoc{}, bson.M{"_id": id}, &doc)
+	if err != nil {
+		return OperatorApprovalDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (r OperatorApprovalRepository) FindPage(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorApprovalDoc, mongopagination.PaginationData, error) {
+
+	filterQuery := bson.M{}
+	for key, value := range filters {
+		filterQuery[key] = value
+	}
+
+	if len(pageable.Sorts) == 0 {
+		pageable.Sorts = []micromodels.KeyInt{{Key: "createdat", Value: -1}}
+	}
+
+	docList := []OperatorApprovalDoc{}
+	pagination, err := r.pst.FindPage(ctx, &OperatorApprovalDoc{}, filterQuery, pageable, &docList)
+	if err != nil {
+		return []OperatorApprovalDoc{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
+
+// Decide approve or reject pending approval which is not expired, operator cannot decide the approval which it request
+func (r OperatorApprovalRepository) Decide(ctx context.Context, id primitive.ObjectID, decidedBy string, status string, now time.Time) (bool, error) {
+	collection, err := r.pst.Exec(ctx, &OperatorApprovalDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.UpdateOne(ctx, bson.M{
+		"_id":         id,
+		"status":      ApprovalStatusPending,
+		"requestedby": bson.M{"$ne": decidedBy},
+		"expiresat":   bson.M{"$gt": now},
+	}, bson.M{
+		"$set": bson.M{
+			"status":    status,
+			"decidedby": decidedBy,
+			"decidedat": now,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// Use mark approved approval used when it match the request, the approval can be used once
+func (r OperatorApprovalRepository) Use(ctx context.Context, id primitive.ObjectID, req OperatorRequest, now time.Time) (OperatorApprovalDoc, bool, error) {
+	collection, err := r.pst.Exec(ctx, &OperatorApprovalDoc{})
+	if err != nil {
+		return OperatorApprovalDoc{}, false, err
+	}
+
+	used := OperatorApprovalDoc{}
+	err = collection.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id":         id,
+			"status":      ApprovalStatusApproved,
+			"requestedby": req.Operator,
+			"method":      req.Method,
+			"path":        req.Path,
+			"query":       req.Query,
+			"bodyhash":    req.BodyHash(),
+			"expiresat":   bson.M{"$gt": now},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status": ApprovalStatusUsed,
+				"usedat": now,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&used)
+
+	if err == mongo.ErrNoDocuments {
+		return OperatorApprovalDoc{}, false, nil
+	}
+
+	if err != nil {
+		return OperatorApprovalDoc{}, false, err
+	}
+
+	return used, true, nil
+}
@@ -0,0 +1,424 @@
+package operatoradmin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	operatorTokenCacheKey   = "operator-token-"
+	operatorFailureCacheKey = "operator-failure-"
+)
+
+var (
+	ErrOperatorLoginFailed  = errors.New("username or password is invalid")
+	ErrOperatorLocked       = errors.New("operator is locked, try again later")
+	ErrOperatorTokenInvalid = errors.New("operator token is invalid")
+	ErrOperatorExists       = errors.New("operator already exists")
+	ErrOperatorNotFound     = errors.New("operator not found")
+	ErrApprovalNotFound     = errors.New("approval not found")
+	ErrApprovalSelf         = errors.New("approval must be decided by another operator")
+	ErrApprovalNotPending   = errors.New("approval is not pending or it is expired")
+	ErrApprovalInvalid      = errors.New("approval is not approved, expired, used or it is for another request")
+)
+
+// OperatorRequest is operator call which is recorded and approved
+type OperatorRequest struct {
+	RequestID string
+	Operator  string
+	Method    string
+	Route     string
+	Path      string
+	Query     string
+	Body      []byte
+	Reason    string
+	IP        string
+}
+
+func (req OperatorRequest) BodyHash() string {
+	sum := sha256.Sum256(req.Body)
+	return hex.EncodeToString(sum[:])
+}
+
+type IOperatorService interface {
+	Login(ctx context.Context, username string, password string, ip string) (OperatorLoginResponse, error)
+	Logout(ctx context.Context, token string, operator string, ip string) error
+	Authenticate(ctx context.Context, token string) (string, error)
+	CreateOperator(ctx context.Context, username string, name string, password string) error
+	SetPassword(ctx context.Context, username string, password string) error
+	SetActive(ctx context.Context, username string, isActive bool) error
+	RecordLog(ctx context.Context, doc OperatorLogDoc) error
+	RequestApproval(ctx context.Context, req OperatorRequest) (OperatorApprovalDoc, error)
+	UseApproval(ctx context.Context, id string, req OperatorRequest) (OperatorApprovalDoc, error)
+	DecideApproval(ctx context.Context, id string, operator string, approve bool) error
+	SearchLog(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorLogDoc, mongopagination.PaginationData, error)
+	SearchApproval(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorApprovalDoc, mongopagination.PaginationData, error)
+}
+
+type OperatorService struct {
+	repo           IOperatorRepository
+	logRepo        IOperatorLogRepository
+	approvalRepo   IOperatorApprovalRepository
+	cacher         microservice.ICacher
+	cfg            config.IOperatorConfig
+	timeNow        func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewOperatorService(repo IOperatorRepository, logRepo IOperatorLogRepository, approvalRepo IOperatorApprovalRepository, cacher microservice.ICacher, cfg config.IOperatorConfig, timeNow func() time.Time) *OperatorService {
+	contextTimeout := time.Duration(15) * time.Second
+
+	return &OperatorService{
+		repo:           repo,
+		logRepo:        logRepo,
+		approvalRepo:   approvalRepo,
+		cacher:         cacher,
+		cfg:            cfg,
+		timeNow:        timeNow,
+		contextTimeout: contextTimeout,
+	}
+}
+
+func (svc OperatorService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// tokenCacheKey return cache key of hash of the token so token cannot be read from cache
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return operatorTokenCacheKey + hex.EncodeToString(sum[:])
+}
+
+// Login check password of active operator and return new token, operator is locked after max failures
+// and every attempt is recorded in operator log
+func (svc OperatorService) Login(ctx context.Context, username string, password string, ip string) (OperatorLoginResponse, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	username = strings.TrimSpace(username)
+	failureKey := operatorFailureCacheKey + username
+
+	failures, err := svc.cacher.Get(failureKey)
+	if err != nil {
+		return OperatorLoginResponse{}, err
+	}
+
+	if count, _ := strconv.Atoi(failures); count >= svc.cfg.MaxLoginFailures() {
+		svc.RecordLog(ctx, OperatorLogDoc{Operator: username, Action: OperatorLogLoginFailed, Message: ErrOperatorLocked.Error(), IP: ip})
+		return OperatorLoginResponse{}, ErrOperatorLocked
+	}
+
+	operator, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return OperatorLoginResponse{}, err
+	}
+
+	if operator.ID.IsZero() || !operator.IsActive || !utils.CheckHashPassword(password, operator.PasswordHash) {
+		if _, err := svc.cacher.Incr(failureKey); err == nil {
+			svc.cacher.Expire(failureKey, svc.cfg.LockoutDuration())
+		}
+
+		svc.RecordLog(ctx, OperatorLogDoc{Operator: username, Action: OperatorLogLoginFailed, Message: ErrOperatorLoginFailed.Error(), IP: ip})
+		return OperatorLoginResponse{}, ErrOperatorLoginFailed
+	}
+
+	svc.cacher.Del(failureKey)
+
+	token, err := newOperatorToken()
+	if err != nil {
+		return OperatorLoginResponse{}, err
+	}
+
+	err = svc.cacher.SetS(tokenCacheKey(token), operator.Username, svc.cfg.TokenExpire())
+	if err != nil {
+		return OperatorLoginResponse{}, err
+	}
+
+	now := svc.timeNow()
+	svc.repo.UpdateLastLogin(ctx, operator.Username, now)
+
+	err = svc.RecordLog(ctx, OperatorLogDoc{Operator: operator.Username, Action: OperatorLogLogin, IP: ip})
+	if err != nil {
+		svc.cacher.Del(tokenCacheKey(token))
+		return OperatorLoginResponse{}, err
+	}
+
+	return OperatorLoginResponse{
+		Token:     token,
+		ExpiresAt: now.Add(svc.cfg.TokenExpire()),
+	}, nil
+}
+
+func (svc OperatorService) Logout(ctx context.Context, token string, operator string, ip string) error {
+	err := svc.cacher.Del(tokenCacheKey(token))
+	if err != nil {
+		return err
+	}
+
+	return svc.RecordLog(ctx, OperatorLogDoc{Operator: operator, Action: OperatorLogLogout, IP: ip})
+}
+
+// Authenticate return username of active operator of the token and extend the token expire
+func (svc OperatorService) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrOperatorTokenInvalid
+	}
+
+	cacheKey := tokenCacheKey(token)
+	username, err := svc.cacher.Get(cacheKey)
+	if err != nil {
+		return "", err
+	}
+
+	if username == "" {
+		return "", ErrOperatorTokenInvalid
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	// operator which is disabled stop working at once
+	operator, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	if operator.ID.IsZero() || !operator.IsActive {
+		svc.cacher.Del(cacheKey)
+		return "", ErrOperatorTokenInvalid
+	}
+
+	svc.cacher.Expire(cacheKey, svc.cfg.TokenExpire())
+
+	return username, nil
+}
+
+func (svc OperatorService) CreateOperator(ctx context.Context, username string, name string, password string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return errors.New("username is required")
+	}
+
+	findDoc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if !findDoc.ID.IsZero() {
+		return ErrOperatorExists
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := svc.timeNow()
+	err = svc.repo.Create(ctx, OperatorDoc{
+		Username:     username,
+		Name:         name,
+		PasswordHash: passwordHash,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return svc.RecordLog(ctx, OperatorLogDoc{Operator: username, Action: OperatorLogAccount, Message: "operator is created"})
+}
+
+func (svc OperatorService) SetPassword(ctx context.Context, username string, password string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID.IsZero() {
+		return ErrOperatorNotFound
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.UpdatePassword(ctx, username, passwordHash, svc.timeNow())
+	if err != nil {
+		return err
+	}
+
+	return svc.RecordLog(ctx, OperatorLogDoc{Operator: username, Action: OperatorLogAccount, Message: "password is changed"})
+}
+
+// SetActive enable or disable the operator, token of disabled operator is rejected on the next request
+func (svc OperatorService) SetActive(ctx context.Context, username string, isActive bool) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID.IsZero() {
+		return ErrOperatorNotFound
+	}
+
+	err = svc.repo.UpdateActive(ctx, username, isActive, svc.timeNow())
+	if err != nil {
+		return err
+	}
+
+	message := "operator is disabled"
+	if isActive {
+		message = "operator is enabled"
+	}
+
+	return svc.RecordLog(ctx, OperatorLogDoc{Operator: username, Action: OperatorLogAccount, Message: message})
+}
+
+func (svc OperatorService) RecordLog(ctx context.Context, doc OperatorLogDoc) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = svc.timeNow()
+	}
+
+	return svc.logRepo.Create(ctx, doc)
+}
+
+// RequestApproval save the request which wait for another operator to approve it
+func (svc OperatorService) RequestApproval(ctx context.Context, req OperatorRequest) (OperatorApprovalDoc, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	now := svc.timeNow()
+	doc := OperatorApprovalDoc{
+		RequestedBy: req.Operator,
+		Method:      req.Method,
+		Route:       req.Route,
+		Path:        req.Path,
+		Query:       req.Query,
+		Body:        string(req.Body),
+		BodyHash:    req.BodyHash(),
+		Reason:      req.Reason,
+		Status:      ApprovalStatusPending,
+		ExpiresAt:   now.Add(svc.cfg.ApprovalExpire()),
+		CreatedAt:   now,
+	}
+
+	id, err := svc.approvalRepo.Create(ctx, doc)
+	if err != nil {
+		return OperatorApprovalDoc{}, err
+	}
+
+	doc.ID = id
+	return doc, nil
+}
+
+// UseApproval mark the approval used by the request, request must be the same one which is approved
+func (svc OperatorService) UseApproval(ctx context.Context, id string, req OperatorRequest) (OperatorApprovalDoc, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return OperatorApprovalDoc{}, ErrApprovalInvalid
+	}
+
+	doc, ok, err := svc.approvalRepo.Use(ctx, objectID, req, svc.timeNow())
+	if err != nil {
+		return OperatorApprovalDoc{}, err
+	}
+
+	if !ok {
+		return OperatorApprovalDoc{}, ErrApprovalInvalid
+	}
+
+	return doc, nil
+}
+
+// DecideApproval approve or reject the approval, operator who request it cannot decide it
+func (svc OperatorService) DecideApproval(ctx context.Context, id string, operator string, approve bool) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrApprovalNotFound
+	}
+
+	doc, err := svc.approvalRepo.FindByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+
+	if doc.ID.IsZero() {
+		return ErrApprovalNotFound
+	}
+
+	if doc.RequestedBy == operator {
+		return ErrApprovalSelf
+	}
+
+	status := ApprovalStatusRejected
+	if approve {
+		status = ApprovalStatusApproved
+	}
+
+	ok, err := svc.approvalRepo.Decide(ctx, objectID, operator, status, svc.timeNow())
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrApprovalNotPending
+	}
+
+	return nil
+}
+
+func (svc OperatorService) SearchLog(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorLogDoc, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.logRepo.FindPage(ctx, filters, pageable)
+}
+
+func (svc OperatorService) SearchApproval(ctx context.Context, filters map[string]interface{}, pageable micromodels.Pageable) ([]OperatorApprovalDoc, mongopagination.PaginationData, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.approvalRepo.FindPage(ctx, filters, pageable)
+}
+
+func newOperatorToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/job"
+	jobServices "smlaicloudplatform/internal/job/services"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/pkg/microservice"
 )
@@ -16,18 +18,20 @@ type IProductAdminHttp interface {
 }
 
 type ProductAdminHttp struct {
-	svc IProductAdminService
+	svc       IProductAdminService
+	jobRunner jobServices.IJobRunner
 }
 
 func NewProductAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IProductAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewProductAdminService(mongoPersister, producer)
 
 	return &ProductAdminHttp{
-		svc: svc,
+		svc:       svc,
+		jobRunner: job.InitJobRunner(ms, cfg),
 	}
 }
 
@@ -82,17 +86,17 @@ func (s *ProductAdminHttp) ReCalcStockBalance(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = s.svc.ReCalcStockBalance(req.ShopID)
+	jobID, err := s.jobRunner.Start(req.ShopID, ctx.UserInfo().Username, "recalcstock", func(job jobServices.IJobContext) (interface{}, error) {
+		return nil, s.svc.ReCalcStockBalance(job, req.ShopID)
+	})
 	if err != nil {
-		ctx.Response(http.StatusBadRequest, common.ApiResponse{
-			Success: false,
-			Message: err.Error(),
-		})
+		job.ResponseStartError(ctx, err)
 		return err
 	}
 
-	ctx.Response(http.StatusOK, common.ResponseSuccess{
+	ctx.Response(http.StatusAccepted, common.ApiResponse{
 		Success: true,
+		ID:      jobID,
 	})
 	return nil
 }
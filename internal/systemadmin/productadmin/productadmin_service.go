@@ -2,6 +2,8 @@ package productadmin
 
 import (
 	"context"
+	"fmt"
+	jobServices "smlaicloudplatform/internal/job/services"
 	productBarcodeRepositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	stockProcessModels "smlaicloudplatform/internal/stockprocess/models"
 	stockProcessRepository "smlaicloudplatform/internal/stockprocess/repositories"
@@ -10,9 +12,12 @@ import (
 	"time"
 )
 
+// adminJobBatchSize is number of requests sent to message queue at a time by admin jobs
+const adminJobBatchSize = 1000
+
 type IProductAdminService interface {
 	ReSyncProductBarcode(shopID string) error
-	ReCalcStockBalance(shopID string) error
+	ReCalcStockBalance(job jobServices.IJobContext, shopID string) error
 	DeleteProductBarcodeAll(shopID string, userName string) error
 }
 
@@ -40,7 +45,7 @@ func NewProductAdminService(pst microservice.IPersisterMongo, kfProducer microse
 func (svc ProductAdminService) ReSyncProductBarcode(shopID string) error {
 
 	// find product barcode by shopid
-	ctx, cancel := context.WithTimeout(context.Background(), svc.timeoutDuration)
+	ctx, cancel := context.WithTimeout(microservice.WithSystemAdmin(context.Background()), svc.timeoutDuration)
 	defer cancel()
 
 	pageRequest := msModels.Pageable{
@@ -83,12 +88,12 @@ func (svc ProductAdminService) ReSyncProductBarcode(shopID string) error {
 
 }
 
-func (svc ProductAdminService) ReCalcStockBalance(shopID string) error {
+func (svc ProductAdminService) ReCalcStockBalance(job jobServices.IJobContext, shopID string) error {
 
-	ctx, cancel := context.WithTimeout(context.Background(), svc.timeoutDuration)
+	ctx, cancel := context.WithTimeout(microservice.WithSystemAdmin(job.Context()), svc.timeoutDuration)
 	defer cancel()
 
-	// find productbarocode by shopid
+	// find productbarocde by shopid
 	barcodes, err := svc.mongoRepo.FindProductBarcodeByShopId(ctx, shopID)
 	if err != nil {
 		return err
@@ -108,10 +113,25 @@ func (svc ProductAdminService) ReCalcStockBalance(shopID string) error {
 		}
 	}
 
-	err = svc.stockProcessMGRepo.CreateInBatch(requestStockProcessLists)
+	job.Logf("recalculate stock of %d barcodes", len(requestStockProcessLists))
 
-	if err != nil {
-		return err
+	for start := 0; start < len(requestStockProcessLists); start += adminJobBatchSize {
+		if job.Context().Err() != nil {
+			return job.Context().Err()
+		}
+
+		end := start + adminJobBatchSize
+		if end > len(requestStockProcessLists) {
+			end = len(requestStockProcessLists)
+		}
+
+		err = svc.stockProcessMGRepo.CreateInBatch(requestStockProcessLists[start:end])
+
+		if err != nil {
+			return err
+		}
+
+		job.Progress(end*100/len(requestStockProcessLists), fmt.Sprintf("sent %d/%d barcodes", end, len(requestStockProcessLists)))
 	}
 
 	return nil
@@ -119,7 +139,8 @@ func (svc ProductAdminService) ReCalcStockBalance(shopID string) error {
 
 func (svc ProductAdminService) DeleteProductBarcodeAll(shopID string, userName string) error {
 
-	ctx, cancel := context.WithTimeout(context.Background(), svc.timeoutDuration)
+	// barcodes are deleted by id without shop of the request, the operator works across shops
+	ctx, cancel := context.WithTimeout(microservice.WithSystemAdmin(context.Background()), svc.timeoutDuration)
 	defer cancel()
 
 	pageRequest := msModels.Pageable{
@@ -0,0 +1,49 @@
+package productadmin
+
+import (
+	"smlaicloudplatform/internal/product/productbarcode/models"
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/tenanttest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type barcodeMessageQueueStub struct {
+	deleted []models.ProductBarcodeDoc
+}
+
+func (s *barcodeMessageQueueStub) Create(doc models.ProductBarcodeDoc) error { return nil }
+func (s *barcodeMessageQueueStub) Update(doc models.ProductBarcodeDoc) error { return nil }
+func (s *barcodeMessageQueueStub) Delete(doc models.ProductBarcodeDoc) error { return nil }
+func (s *barcodeMessageQueueStub) CreateInBatch(docList []models.ProductBarcodeDoc) error {
+	return nil
+}
+func (s *barcodeMessageQueueStub) UpdateInBatch(docList []models.ProductBarcodeDoc) error {
+	return nil
+}
+func (s *barcodeMessageQueueStub) DeleteInBatch(docList []models.ProductBarcodeDoc) error {
+	s.deleted = append(s.deleted, docList...)
+	return nil
+}
+
+func TestDeleteProductBarcodeAll_TenantIsolationEnforce(t *testing.T) {
+	recorder := &tenanttest.MongoRecorder{}
+	pst := microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationEnforce)
+
+	svc := ProductAdminService{
+		kafkaRepo:       &barcodeMessageQueueStub{},
+		mongoRepo:       NewProductAdminMongoRepository(pst),
+		timeoutDuration: time.Second,
+	}
+
+	err := svc.DeleteProductBarcodeAll("shop1", "operator")
+	assert.Nil(t, err)
+
+	ops := []string{}
+	for _, call := range recorder.Calls {
+		ops = append(ops, call.Op)
+	}
+	assert.Contains(t, ops, "soft batch delete by id")
+}
@@ -0,0 +1,97 @@
+package scheduleadmin
+
+import (
+	"context"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/scheduler/repositories"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+type IScheduleAdminHttp interface {
+	ListSchedule(ctx microservice.IContext) error
+	SearchScheduleRun(ctx microservice.IContext) error
+	RegisterHttp(ms *microservice.Microservice, prefix string)
+}
+
+type ScheduleAdminHttp struct {
+	ms           *microservice.Microservice
+	repo         repositories.IScheduleRunRepository
+	scheduleRepo repositories.IScheduleRepository
+}
+
+func NewScheduleAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IScheduleAdminHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return &ScheduleAdminHttp{
+		ms:           ms,
+		repo:         repositories.NewScheduleRunRepository(pst),
+		scheduleRepo: repositories.NewScheduleRepository(pst),
+	}
+}
+
+func (h *ScheduleAdminHttp) RegisterHttp(ms *microservice.Microservice, prefix string) {
+	ms.GET(prefix+"/schedule", h.ListSchedule)
+	ms.GET(prefix+"/schedule/history", h.SearchScheduleRun)
+}
+
+// ListSchedule list schedules saved by the consumer with next run time, the api replica does not run the scheduler
+func (h *ScheduleAdminHttp) ListSchedule(ctx microservice.IContext) error {
+	reqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	docList, err := h.scheduleRepo.FindAll(reqCtx)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	now := time.Now()
+	schedules := []microservice.ScheduleInfo{}
+	for _, doc := range docList {
+		schedule := doc.ScheduleInfo
+		schedule.NextRunAt, err = schedule.Next(now)
+		if err != nil {
+			h.ms.Logger.Warnf("Schedule %s has invalid spec or location: %v", schedule.Name, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    schedules,
+	})
+	return nil
+}
+
+// SearchScheduleRun list run history of every replica, filter by name, shopid and status
+func (h *ScheduleAdminHttp) SearchScheduleRun(ctx microservice.IContext) error {
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := map[string]interface{}{}
+	for _, key := range []string{"name", "shopid", "status"} {
+		value := ctx.QueryParam(key)
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	docList, pagination, err := h.repo.FindPage(reqCtx, filters, pageable)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
@@ -28,9 +28,14 @@ func NewShopAdminService(persister microservice.IPersisterMongo) IShopAdminServi
 	}
 }
 
+// adminContext return context of system admin queries, shops and users of shops are listed across shops
+func (s *ShopAdminService) adminContext() context.Context {
+	return microservice.WithSystemAdmin(context.Background())
+}
+
 func (s *ShopAdminService) ListShop() ([]ShopDoc, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	ctx, cancel := context.WithTimeout(s.adminContext(), s.timeoutDuration)
 	defer cancel()
 
 	return s.repo.ListAllShop(ctx)
@@ -38,7 +43,7 @@ func (s *ShopAdminService) ListShop() ([]ShopDoc, error) {
 
 func (s *ShopAdminService) CreateShop(doc shopModels.ShopDoc) error {
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	ctx, cancel := context.WithTimeout(s.adminContext(), s.timeoutDuration)
 	defer cancel()
 
 	return s.repo.CreateShop(ctx, doc)
@@ -46,7 +51,7 @@ func (s *ShopAdminService) CreateShop(doc shopModels.ShopDoc) error {
 
 func (s *ShopAdminService) FindShopByProjectNo(projectNo string) (shopModels.ShopDoc, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	ctx, cancel := context.WithTimeout(s.adminContext(), s.timeoutDuration)
 	defer cancel()
 
 	return s.repo.FindShopByProjectNo(ctx, projectNo)
@@ -54,7 +59,7 @@ func (s *ShopAdminService) FindShopByProjectNo(projectNo string) (shopModels.Sho
 
 func (s *ShopAdminService) ListShopUsersAll() ([]ShopUserDoc, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	ctx, cancel := context.WithTimeout(s.adminContext(), s.timeoutDuration)
 	defer cancel()
 
 	return s.repo.ListShopUsersAll(ctx)
@@ -62,7 +67,7 @@ func (s *ShopAdminService) ListShopUsersAll() ([]ShopUserDoc, error) {
 
 func (s *ShopAdminService) ListShopUsersByShopId(shopId string) ([]ShopUserDoc, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeoutDuration)
+	ctx, cancel := context.WithTimeout(s.adminContext(), s.timeoutDuration)
 	defer cancel()
 
 	return s.repo.ListShopUsersByShopId(ctx, shopId)
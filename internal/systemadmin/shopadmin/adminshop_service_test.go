@@ -0,0 +1,22 @@
+package shopadmin
+
+import (
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/tenanttest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListShopUsersAll_TenantIsolationEnforce(t *testing.T) {
+	recorder := &tenanttest.MongoRecorder{}
+	svc := NewShopAdminService(microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationEnforce))
+
+	_, err := svc.ListShopUsersAll()
+	assert.Nil(t, err)
+
+	_, err = svc.ListShop()
+	assert.Nil(t, err)
+
+	assert.Len(t, recorder.Calls, 2)
+}
@@ -6,9 +6,13 @@ import (
 	"smlaicloudplatform/internal/systemadmin/chartofaccountadmin"
 	"smlaicloudplatform/internal/systemadmin/creditoradmin"
 	"smlaicloudplatform/internal/systemadmin/datamigration"
+	"smlaicloudplatform/internal/systemadmin/deadletteradmin"
 	"smlaicloudplatform/internal/systemadmin/debtoradmin"
+	"smlaicloudplatform/internal/systemadmin/jobadmin"
 	journal "smlaicloudplatform/internal/systemadmin/journaladmin"
+	"smlaicloudplatform/internal/systemadmin/operatoradmin"
 	"smlaicloudplatform/internal/systemadmin/productadmin"
+	"smlaicloudplatform/internal/systemadmin/scheduleadmin"
 	"smlaicloudplatform/internal/systemadmin/servicetools"
 	"smlaicloudplatform/internal/systemadmin/shopadmin"
 	"smlaicloudplatform/internal/systemadmin/transactionadmin"
@@ -17,12 +21,24 @@ import (
 
 const SYSTEM_ADMIN_ROUTE_PREFIX = "/systemadm"
 
+// destructiveRoutes delete or recalculate data of shops, they need approval of another operator when it is enabled
+var destructiveRoutes = []string{
+	"/productadmin/deleteproductbarcodeall",
+	"/productadmin/recalcstock",
+	"/debtoradmin/resyncdebtor",
+	"/debtoradmin/recalcbalance",
+	"/creditoradmin/resynccreditor",
+	"/creditoradmin/recalcbalance",
+	"/transactionadmin/journal/regenguidempty",
+}
+
 type ISystemAdmin interface {
 	RegisterHttp()
 }
 
 type SystemAdmin struct {
 	ms                      *microservice.Microservice
+	operatorAdminHttp       operatoradmin.IOperatorAdminHttp
 	migrationHttp           datamigration.IMigrationAPI
 	serviceTools            servicetools.IServiceTools
 	shopAdminHttp           shopadmin.IShopAdminHttp
@@ -33,10 +49,14 @@ type SystemAdmin struct {
 	debtorAdminHttp         debtoradmin.IDebtorAdminHttp
 	transactionAdminHttp    transactionadmin.ITransactionAdminHttp
 	journalAdminHttp        journal.IJournalTransactionAdminHttp
+	deadLetterAdminHttp     deadletteradmin.IDeadLetterAdminHttp
+	jobAdminHttp            jobadmin.IJobAdminHttp
+	scheduleAdminHttp       scheduleadmin.IScheduleAdminHttp
 }
 
 func NewSystemAdmin(ms *microservice.Microservice, cfg config.IConfig) ISystemAdmin {
 
+	operatorAdminHttp := operatoradmin.NewOperatorAdminHttp(ms, cfg)
 	migrationHttp := datamigration.NewMigrationAPI(ms, cfg)
 	servicetools := servicetools.NewServiceTools(ms.Logger, cfg, ms.MongoPersister(cfg.MongoPersisterConfig()))
 	shopAdminHttp := shopadmin.NewShopAdminHttp(ms, cfg)
@@ -47,9 +67,13 @@ func NewSystemAdmin(ms *microservice.Microservice, cfg config.IConfig) ISystemAd
 	transactionAdminHttp := transactionadmin.NewTransactionAdminHttp(ms, cfg)
 	chartOfAccountAdminHttp := chartofaccountadmin.NewChartOfAccountAdminHttp(ms, cfg)
 	journalAdminHttp := journal.NewJournalTransactionAdminHttp(ms, cfg)
+	deadLetterAdminHttp := deadletteradmin.NewDeadLetterAdminHttp(ms, cfg)
+	jobAdminHttp := jobadmin.NewJobAdminHttp(ms, cfg)
+	scheduleAdminHttp := scheduleadmin.NewScheduleAdminHttp(ms, cfg)
 
 	return &SystemAdmin{
 		ms:                      ms,
+		operatorAdminHttp:       operatorAdminHttp,
 		migrationHttp:           migrationHttp,
 		serviceTools:            servicetools,
 		shopAdminHttp:           shopAdminHttp,
@@ -60,10 +84,16 @@ func NewSystemAdmin(ms *microservice.Microservice, cfg config.IConfig) ISystemAd
 		transactionAdminHttp:    transactionAdminHttp,
 		chartOfAccountAdminHttp: chartOfAccountAdminHttp,
 		journalAdminHttp:        journalAdminHttp,
+		deadLetterAdminHttp:     deadLetterAdminHttp,
+		jobAdminHttp:            jobAdminHttp,
+		scheduleAdminHttp:       scheduleAdminHttp,
 	}
 }
 
 func (s *SystemAdmin) RegisterHttp() {
+	// system admin routes are not routes of shop user, operator login is checked on all of them
+	s.ms.HttpMiddleware(s.operatorAdminHttp.Guard(SYSTEM_ADMIN_ROUTE_PREFIX, destructiveRoutes))
+	s.operatorAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 	s.migrationHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 	s.serviceTools.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 	s.shopAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
@@ -74,4 +104,7 @@ func (s *SystemAdmin) RegisterHttp() {
 	s.journalAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 	s.creditorAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 	s.debtorAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
+	s.deadLetterAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
+	s.jobAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
+	s.scheduleAdminHttp.RegisterHttp(s.ms, SYSTEM_ADMIN_ROUTE_PREFIX)
 }
@@ -20,7 +20,7 @@ type CreditorPaymentTransactionAdminHttp struct {
 
 func NewCreditorPaymentTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) ICreditorPaymentTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewCreditorPaymentTransactionAdminService(mongoPersister, producer)
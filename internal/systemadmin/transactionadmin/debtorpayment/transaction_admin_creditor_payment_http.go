@@ -20,7 +20,7 @@ type DebtorPaymentTransactionAdminHttp struct {
 
 func NewDebtorPaymentTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IDebtorPaymentTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewDebtorPaymentTransactionAdminService(mongoPersister, producer)
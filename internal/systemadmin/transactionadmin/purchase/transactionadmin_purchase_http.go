@@ -21,7 +21,7 @@ type PurchaseTransactionAdminHttp struct {
 
 func NewPurchaseTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IPurchaseTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewPurchaseTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type PurchaseReturnTransactionAdminHttp struct {
 
 func NewPurchaseReturnTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IPurchaseReturnTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewPurchaseReturnTransactionAdminService(mongoPersister, producer)
@@ -23,7 +23,7 @@ type SaleInvoiceTransactionAdminHttp struct {
 
 func NewSaleInvoiceTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) ISaleInvoiceTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewSaleInvoiceTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type SaleInvoiceReturnTransactionAdminHttp struct {
 
 func NewSaleInvoiceReturnTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) ISaleInvoiceReturnTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewSaleInvoiceReturnTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type StockAdjustmentTransactionAdminHttp struct {
 
 func NewStockAdjustmentTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockAdjustmentTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockAdjustmentTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type StockBalanceTransactionAdminHttp struct {
 
 func NewStockBalanceTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockBalanceTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockBalanceProductTransactionAdminService(mongoPersister, producer)
@@ -20,7 +20,7 @@ type StockPickupTransactionAdminHttp struct {
 }
 
 func NewStockPickupTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockPickupTransactionAdminHttp {
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockPickupTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type StockReceiveTransactionAdminHttp struct {
 
 func NewStockReceiveTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockReceiveTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockReceiveProductTransactionAdminService(mongoPersister, producer)
@@ -21,7 +21,7 @@ type StockReturnProductTransactionAdminHttp struct {
 
 func NewStockReturnProductTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockReturnProductTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockReturnProductTransactionAdminService(mongoPersister, producer)
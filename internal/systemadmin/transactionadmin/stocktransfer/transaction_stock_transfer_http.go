@@ -21,7 +21,7 @@ type StockTransferTransactionAdminHttp struct {
 
 func NewStockTransferTransactionAdminHttp(ms *microservice.Microservice, cfg config.IConfig) IStockTransferTransactionAdminHttp {
 
-	producer := microservice.NewProducer(cfg.MQConfig().URI(), ms.Logger)
+	producer := ms.Producer(cfg.MQConfig())
 	mongoPersister := microservice.NewPersisterMongo(cfg.MongoPersisterConfig())
 
 	svc := NewStockTransferTransactionAdminService(mongoPersister, producer)
@@ -14,6 +14,7 @@ import (
 	"smlaicloudplatform/internal/task/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"strconv"
 	"strings"
@@ -25,22 +26,22 @@ import (
 )
 
 type ITaskHttpService interface {
-	GenerateTaskID(shopID string, authUsername string) (string, error)
-	CreateTask(shopID string, authUsername string, doc models.Task) (string, error)
-	UpdateTask(shopID string, guid string, authUsername string, doc models.Task) error
-	UpdateTaskStatus(shopID string, guid string, authUsername string, jobStatus int8) error
-	DeleteTask(shopID string, guid string, authUsername string) error
-	DeleteTaskByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoTask(shopID string, guid string) (models.TaskInfo, error)
-	SearchTask(shopID string, module string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TaskInfo, mongopagination.PaginationData, error)
-	SearchTaskStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TaskInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Task) (common.BulkImport, error)
+	GenerateTaskID(ctx context.Context, shopID string, authUsername string) (string, error)
+	CreateTask(ctx context.Context, shopID string, authUsername string, doc models.Task) (string, error)
+	UpdateTask(ctx context.Context, shopID string, guid string, authUsername string, doc models.Task) error
+	UpdateTaskStatus(ctx context.Context, shopID string, guid string, authUsername string, jobStatus int8) error
+	DeleteTask(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteTaskByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoTask(ctx context.Context, shopID string, guid string) (models.TaskInfo, error)
+	SearchTask(ctx context.Context, shopID string, module string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TaskInfo, mongopagination.PaginationData, error)
+	SearchTaskStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TaskInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Task) (common.BulkImport, error)
 
 	TaskStatusReference() map[int]string
-	GetTaskReject(shopID string, module string, taskGUID string) ([]models.TaskInfo, error)
+	GetTaskReject(ctx context.Context, shopID string, module string, taskGUID string) ([]models.TaskInfo, error)
 
-	UpdateTaskTotalImage(docReq documentImageModel.DocumentImageTaskChangeMessage) error
-	UpdateTaskTotalRejectImage(docReq documentImageModel.DocumentImageTaskRejectMessage) error
+	UpdateTaskTotalImage(ctx context.Context, docReq documentImageModel.DocumentImageTaskChangeMessage) error
+	UpdateTaskTotalRejectImage(ctx context.Context, docReq documentImageModel.DocumentImageTaskRejectMessage) error
 }
 
 type TaskHttpService struct {
@@ -67,8 +68,8 @@ func NewTaskHttpService(repo repositories.ITaskRepository, repoDocImageGroup rep
 	return insSvc
 }
 
-func (svc TaskHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc TaskHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
 func (svc TaskHttpService) TaskStatusReference() map[int]string {
@@ -80,9 +81,9 @@ func (svc TaskHttpService) TaskStatusReference() map[int]string {
 	}
 }
 
-func (svc TaskHttpService) GenerateTaskID(shopID string, authUsername string) (string, error) {
+func (svc TaskHttpService) GenerateTaskID(ctx context.Context, shopID string, authUsername string) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	timeNow := time.Now()
@@ -124,9 +125,9 @@ func (svc TaskHttpService) PaddingNumber(number int) string {
 	return fmt.Sprintf("%03d", number)
 }
 
-func (svc TaskHttpService) CreateTask(shopID string, authUsername string, doc models.Task) (string, error) {
+func (svc TaskHttpService) CreateTask(ctx context.Context, shopID string, authUsername string, doc models.Task) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "name", doc.Name)
@@ -174,9 +175,9 @@ func (svc TaskHttpService) CreateTask(shopID string, authUsername string, doc mo
 	return newGuidFixed, nil
 }
 
-func (svc TaskHttpService) UpdateTask(shopID string, guid string, authUsername string, doc models.Task) error {
+func (svc TaskHttpService) UpdateTask(ctx context.Context, shopID string, guid string, authUsername string, doc models.Task) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -216,9 +217,9 @@ func (svc TaskHttpService) UpdateTask(shopID string, guid string, authUsername s
 	return nil
 }
 
-func (svc TaskHttpService) UpdateTaskStatus(shopID string, taskGUID string, authUsername string, jobStatus int8) error {
+func (svc TaskHttpService) UpdateTaskStatus(ctx context.Context, shopID string, taskGUID string, authUsername string, jobStatus int8) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, taskGUID)
@@ -311,7 +312,7 @@ func (svc TaskHttpService) UpdateTaskStatus(shopID string, taskGUID string, auth
 
 		newTaskCode := ""
 		for i := 0; i < 5; i++ {
-			newTaskCode, err = svc.GenerateTaskID(shopID, authUsername)
+			newTaskCode, err = svc.GenerateTaskID(ctx, shopID, authUsername)
 			if err != nil {
 				return err
 			}
@@ -371,7 +372,7 @@ func (svc TaskHttpService) UpdateTaskStatus(shopID string, taskGUID string, auth
 						docImgReq.ImageURI = docImage.ImageURI
 						docImgReq.Name = docImage.Name // + " -- REJECT"
 
-						_, _, err := svc.serviceDocImage.CreateDocumentImage(shopID, authUsername, docImgReq)
+						_, _, err := svc.serviceDocImage.CreateDocumentImage(ctx, shopID, authUsername, docImgReq)
 
 						if err != nil {
 							fmt.Println(err)
@@ -399,9 +400,9 @@ func (svc TaskHttpService) UpdateTaskStatus(shopID string, taskGUID string, auth
 	return nil
 }
 
-func (svc TaskHttpService) DeleteTask(shopID string, guid string, authUsername string) error {
+func (svc TaskHttpService) DeleteTask(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -422,9 +423,9 @@ func (svc TaskHttpService) DeleteTask(shopID string, guid string, authUsername s
 	return nil
 }
 
-func (svc TaskHttpService) DeleteTaskByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc TaskHttpService) DeleteTaskByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -439,9 +440,9 @@ func (svc TaskHttpService) DeleteTaskByGUIDs(shopID string, authUsername string,
 	return nil
 }
 
-func (svc TaskHttpService) InfoTask(shopID string, guid string) (models.TaskInfo, error) {
+func (svc TaskHttpService) InfoTask(ctx context.Context, shopID string, guid string) (models.TaskInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -466,9 +467,9 @@ func (svc TaskHttpService) InfoTask(shopID string, guid string) (models.TaskInfo
 
 }
 
-func (svc TaskHttpService) GetTaskReject(shopID string, module string, taskGUID string) ([]models.TaskInfo, error) {
+func (svc TaskHttpService) GetTaskReject(ctx context.Context, shopID string, module string, taskGUID string) ([]models.TaskInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, err := svc.repo.FindPageByTaskReject(ctx, shopID, module, taskGUID)
@@ -480,9 +481,9 @@ func (svc TaskHttpService) GetTaskReject(shopID string, module string, taskGUID
 	return docList, nil
 }
 
-func (svc TaskHttpService) SearchTask(shopID string, module string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TaskInfo, mongopagination.PaginationData, error) {
+func (svc TaskHttpService) SearchTask(ctx context.Context, shopID string, module string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.TaskInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -503,9 +504,9 @@ func (svc TaskHttpService) SearchTask(shopID string, module string, filters map[
 	return docList, pagination, nil
 }
 
-func (svc TaskHttpService) SearchTaskStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TaskInfo, int, error) {
+func (svc TaskHttpService) SearchTaskStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.TaskInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -529,9 +530,9 @@ func (svc TaskHttpService) SearchTaskStep(shopID string, langCode string, pageab
 	return docList, total, nil
 }
 
-func (svc TaskHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Task) (common.BulkImport, error) {
+func (svc TaskHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Task) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Task](dataList, svc.getDocIDKey)
@@ -642,9 +643,9 @@ func (svc TaskHttpService) getDocIDKey(doc models.Task) string {
 	return doc.Name
 }
 
-func (svc TaskHttpService) UpdateTaskTotalImage(docReq documentImageModel.DocumentImageTaskChangeMessage) error {
+func (svc TaskHttpService) UpdateTaskTotalImage(ctx context.Context, docReq documentImageModel.DocumentImageTaskChangeMessage) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	totalDocStatus := []models.TotalStatus{}
@@ -665,9 +666,9 @@ func (svc TaskHttpService) UpdateTaskTotalImage(docReq documentImageModel.Docume
 	return nil
 }
 
-func (svc TaskHttpService) UpdateTaskTotalRejectImage(docReq documentImageModel.DocumentImageTaskRejectMessage) error {
+func (svc TaskHttpService) UpdateTaskTotalRejectImage(ctx context.Context, docReq documentImageModel.DocumentImageTaskRejectMessage) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// findDoc, err := svc.repo.FindByGuid(docReq.ShopID, docReq.TaskGUID)
@@ -95,7 +95,7 @@ func (c *TaskConsumer) ConsumeOnProductCreated(ctx microservice.IContext) error
 	// fmt.Printf("task: %s \n", doc.TaskGUID)
 	// fmt.Printf("count: %d \n", doc.Count)
 
-	err = c.svc.UpdateTaskTotalImage(doc)
+	err = c.svc.UpdateTaskTotalImage(ctx.Context(), doc)
 
 	if err != nil {
 		return err
@@ -122,7 +122,7 @@ func (c *TaskConsumer) ConsumeOnProductRejected(ctx microservice.IContext) error
 	// fmt.Printf("task: %s \n", doc.TaskGUID)
 	// fmt.Printf("count: %d \n", doc.Count)
 
-	err = c.svc.UpdateTaskTotalRejectImage(doc)
+	err = c.svc.UpdateTaskTotalRejectImage(ctx.Context(), doc)
 
 	if err != nil {
 		return err
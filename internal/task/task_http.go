@@ -90,7 +90,7 @@ func (h TaskHttp) CreateTask(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateTask(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateTask(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -135,7 +135,7 @@ func (h TaskHttp) UpdateTask(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateTask(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateTask(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -181,7 +181,7 @@ func (h TaskHttp) UpdateTaskStatus(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateTaskStatus(shopID, id, authUsername, docReq.Status)
+	err = h.svc.UpdateTaskStatus(ctx.Context(), shopID, id, authUsername, docReq.Status)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -212,7 +212,7 @@ func (h TaskHttp) DeleteTask(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteTask(shopID, id, authUsername)
+	err := h.svc.DeleteTask(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -251,7 +251,7 @@ func (h TaskHttp) DeleteTaskByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteTaskByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteTaskByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -278,7 +278,7 @@ func (h TaskHttp) GenerateTaskCode(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 	authUsername := userInfo.Username
 
-	newTaskCode, err := h.svc.GenerateTaskID(shopID, authUsername)
+	newTaskCode, err := h.svc.GenerateTaskID(ctx.Context(), shopID, authUsername)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error generate task code: %v", err)
@@ -309,7 +309,7 @@ func (h TaskHttp) InfoTask(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Task %v", id)
-	doc, err := h.svc.InfoTask(shopID, id)
+	doc, err := h.svc.InfoTask(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -341,7 +341,7 @@ func (h TaskHttp) GetTaskReject(ctx microservice.IContext) error {
 
 	module := ctx.QueryParam("module")
 
-	docList, err := h.svc.GetTaskReject(shopID, module, taskGUID)
+	docList, err := h.svc.GetTaskReject(ctx.Context(), shopID, module, taskGUID)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -393,7 +393,7 @@ func (h TaskHttp) SearchTaskPage(ctx microservice.IContext) error {
 		filters["status"] = bson.M{"$in": tempStatus}
 	}
 
-	docList, pagination, err := h.svc.SearchTask(shopID, module, filters, pageable)
+	docList, pagination, err := h.svc.SearchTask(ctx.Context(), shopID, module, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -428,7 +428,7 @@ func (h TaskHttp) SearchTaskLimit(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchTaskStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchTaskStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -468,7 +468,7 @@ func (h TaskHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
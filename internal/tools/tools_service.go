@@ -1,7 +1,6 @@
 package tools
 
 import (
-	"os"
 	"smlaicloudplatform/internal/config"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
@@ -22,7 +21,6 @@ func NewToolsService(ms *microservice.Microservice, cfg config.IConfig) *ToolsSe
 
 func (svc *ToolsService) RegisterHttp() {
 	svc.ms.GET("tool/mongo", svc.CheckMongodbConnect)
-	svc.ms.GET("tool/env", svc.AllEnv)
 }
 
 func (svc *ToolsService) CheckMongodbConnect(ctx microservice.IContext) error {
@@ -36,13 +34,6 @@ func (svc *ToolsService) CheckMongodbConnect(ctx microservice.IContext) error {
 	return nil
 }
 
-func (svc *ToolsService) AllEnv(ctx microservice.IContext) error {
-
-	ctx.Response(200, os.Environ())
-
-	return nil
-}
-
 func (svc *ToolsService) MockAuth(ctx microservice.IContext) error {
 	cacher := svc.ms.Cacher(svc.cfg.CacherConfig())
 
@@ -0,0 +1,37 @@
+package approval
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/rbac"
+	"smlaicloudplatform/internal/transaction/approval/repositories"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// InitApprovalWorkflow return approval workflow of documents, approver roles are read by rbac permission service
+func InitApprovalWorkflow(ms *microservice.Microservice, cfg config.IConfig) services.IApprovalWorkflow {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewApprovalWorkflow(
+		repositories.NewApprovalRuleRepository(pst),
+		repositories.NewDocumentApprovalRepository(pst),
+		rbac.InitPermissionService(ms, cfg),
+		ms.TimeNow,
+	)
+}
+
+// ResponseError write response of error of approval of the document
+func ResponseError(ctx microservice.IContext, err error) {
+	switch {
+	case errors.Is(err, services.ErrApprovalNotFound), errors.Is(err, services.ErrDocumentNotFound):
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrApprovalNotAllowed), errors.Is(err, services.ErrApprovalBySubmitter):
+		ctx.ResponseError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrApprovalNotPending), errors.Is(err, services.ErrApprovalAlreadyApproved), errors.Is(err, services.ErrApprovalNotSubmittable):
+		ctx.ResponseError(http.StatusConflict, err.Error())
+	default:
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+	}
+}
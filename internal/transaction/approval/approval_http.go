@@ -0,0 +1,280 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/repositories"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type IApprovalHttp interface{}
+
+type ApprovalHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.IApprovalHttpService
+}
+
+func NewApprovalHttp(ms *microservice.Microservice, cfg config.IConfig) ApprovalHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	ruleRepo := repositories.NewApprovalRuleRepository(pst)
+	docRepo := repositories.NewDocumentApprovalRepository(pst)
+	svc := services.NewApprovalHttpService(ruleRepo, docRepo, rbac.InitPermissionService(ms, cfg), ms.TimeNow)
+
+	return ApprovalHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h ApprovalHttp) RegisterHttp() {
+	ruleRead := h.ms.RequirePermission(rbacmodels.PermissionApprovalRuleRead)
+	ruleUpdate := h.ms.RequirePermission(rbacmodels.PermissionApprovalRuleUpdate)
+
+	h.ms.GET("/transaction/approval/inbox", h.Inbox)
+
+	h.ms.GET("/transaction/approval/rule", h.SearchApprovalRulePage, ruleRead)
+	h.ms.POST("/transaction/approval/rule", h.CreateApprovalRule, ruleUpdate)
+	h.ms.GET("/transaction/approval/rule/:id", h.InfoApprovalRule, ruleRead)
+	h.ms.PUT("/transaction/approval/rule/:id", h.UpdateApprovalRule, ruleUpdate)
+	h.ms.DELETE("/transaction/approval/rule/:id", h.DeleteApprovalRule, ruleUpdate)
+}
+
+// Create Approval Rule godoc
+// @Description Create approval rule, document of the doc type which amount reach min amount wait for approvers of the required role
+// @Tags		Approval
+// @Param		ApprovalRule  body      models.ApprovalRule  true  "Approval Rule"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/rule [post]
+func (h ApprovalHttp) CreateApprovalRule(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	docReq := &models.ApprovalRule{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	idx, err := h.svc.CreateApprovalRule(ctx.Context(), userInfo.ShopID, userInfo.Username, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+	})
+	return nil
+}
+
+// Update Approval Rule godoc
+// @Description Update approval rule, documents which wait for approval keep the rule of their submit
+// @Tags		Approval
+// @Param		id  path      string  true  "Approval Rule ID"
+// @Param		ApprovalRule  body      models.ApprovalRule  true  "Approval Rule"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/rule/{id} [put]
+func (h ApprovalHttp) UpdateApprovalRule(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.ApprovalRule{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateApprovalRule(ctx.Context(), userInfo.ShopID, id, userInfo.Username, *docReq)
+
+	if errors.Is(err, services.ErrApprovalRuleNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Delete Approval Rule godoc
+// @Description Delete approval rule
+// @Tags		Approval
+// @Param		id  path      string  true  "Approval Rule ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/rule/{id} [delete]
+func (h ApprovalHttp) DeleteApprovalRule(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.DeleteApprovalRule(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if errors.Is(err, services.ErrApprovalRuleNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Get Approval Rule godoc
+// @Description get approval rule info by guidfixed
+// @Tags		Approval
+// @Param		id  path      string  true  "Approval Rule ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/rule/{id} [get]
+func (h ApprovalHttp) InfoApprovalRule(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoApprovalRule(ctx.Context(), shopID, id)
+
+	if errors.Is(err, services.ErrApprovalRuleNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %s: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, "document not found")
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List Approval Rule godoc
+// @Description List approval rules of the shop
+// @Tags		Approval
+// @Param		q		query	string		false  "Search Value"
+// @Param		doctype		query	string		false  "purchaseorder, stockadjustment, pay or journal"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/rule [get]
+func (h ApprovalHttp) SearchApprovalRulePage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "doctype",
+			Field: "doctype",
+			Type:  requestfilter.FieldTypeString,
+		},
+	})
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchApprovalRule(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Approval Inbox godoc
+// @Description list documents which wait for approval of the user, documents which the user submitted or already approved are not listed
+// @Tags		Approval
+// @Param		q		query	string		false  "Search doc no"
+// @Param		doctype		query	string		false  "purchaseorder, stockadjustment, pay or journal"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/approval/inbox [get]
+func (h ApprovalHttp) Inbox(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "doctype",
+			Field: "doctype",
+			Type:  requestfilter.FieldTypeString,
+		},
+	})
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.Inbox(ctx.Context(), userInfo.ShopID, userInfo.Username, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
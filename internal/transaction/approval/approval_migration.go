@@ -0,0 +1,53 @@
+package approval
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of approval rules and document approvals, unique index keep one approval per document
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	ruleCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.ApprovalRuleDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = ruleCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "guidfixed", Value: 1}},
+			Options: options.Index().SetName("approvalrule_shopid_guidfixed"),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "doctype", Value: 1}, {Key: "isactive", Value: 1}},
+			Options: options.Index().SetName("approvalrule_shopid_doctype_isactive"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	approvalCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.DocumentApproval{})
+	if err != nil {
+		return err
+	}
+
+	_, err = approvalCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "doctype", Value: 1}, {Key: "docguid", Value: 1}},
+			Options: options.Index().SetName("documentapproval_shopid_doctype_docguid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "status", Value: 1}, {Key: "submittedat", Value: 1}},
+			Options: options.Index().SetName("documentapproval_shopid_status_submittedat"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,181 @@
+package approval
+
+import (
+	"encoding/json"
+	"net/http"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// DocumentApprovalHttp register submit, approve, reject and approval info routes under route of documents of a doc type
+type DocumentApprovalHttp struct {
+	ms  *microservice.Microservice
+	svc services.IDocumentApprovalService
+}
+
+func NewDocumentApprovalHttp(ms *microservice.Microservice, svc services.IDocumentApprovalService) DocumentApprovalHttp {
+	return DocumentApprovalHttp{
+		ms:  ms,
+		svc: svc,
+	}
+}
+
+// RegisterHttp register routes under route prefix of the documents, route permission is checked by prefix of the documents
+func (h DocumentApprovalHttp) RegisterHttp(prefix string) {
+	h.ms.POST(prefix+"/:id/submit", h.SubmitDocument)
+	h.ms.POST(prefix+"/:id/approve", h.ApproveDocument)
+	h.ms.POST(prefix+"/:id/reject", h.RejectDocument)
+	h.ms.GET(prefix+"/:id/approval", h.InfoDocumentApproval)
+}
+
+// Submit Document godoc
+// @Description send draft or rejected document for approval, it is approved immediately when no approval rule match its amount
+// @Tags		Approval
+// @Param		id  path      string  true  "Document ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/{id}/submit [post]
+// @Router /transaction/stock-adjustment/{id}/submit [post]
+// @Router /transaction/pay/{id}/submit [post]
+// @Router /gl/journal/{id}/submit [post]
+func (h DocumentApprovalHttp) SubmitDocument(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	approvalStatus, err := h.svc.Submit(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+		Data:    approvalStatus,
+	})
+	return nil
+}
+
+// Approve Document godoc
+// @Description approve document which wait for approval, it is approved when approvers reach number of approvers of the rule
+// @Tags		Approval
+// @Param		id  path      string  true  "Document ID"
+// @Param		ApprovalDecision  body      models.ApprovalDecision  true  "Approval Decision"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/{id}/approve [post]
+// @Router /transaction/stock-adjustment/{id}/approve [post]
+// @Router /transaction/pay/{id}/approve [post]
+// @Router /gl/journal/{id}/approve [post]
+func (h DocumentApprovalHttp) ApproveDocument(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.ApprovalDecision{}
+	if input := ctx.ReadInput(); input != "" {
+		if err := json.Unmarshal([]byte(input), &docReq); err != nil {
+			ctx.ResponseError(http.StatusBadRequest, err.Error())
+			return err
+		}
+	}
+
+	if err := ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	approvalStatus, err := h.svc.Approve(ctx.Context(), userInfo.ShopID, id, userInfo.Username, docReq.Comment)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+		Data:    approvalStatus,
+	})
+	return nil
+}
+
+// Reject Document godoc
+// @Description reject document which wait for approval, comment is required
+// @Tags		Approval
+// @Param		id  path      string  true  "Document ID"
+// @Param		ApprovalDecision  body      models.ApprovalDecision  true  "Approval Decision"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/{id}/reject [post]
+// @Router /transaction/stock-adjustment/{id}/reject [post]
+// @Router /transaction/pay/{id}/reject [post]
+// @Router /gl/journal/{id}/reject [post]
+func (h DocumentApprovalHttp) RejectDocument(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.ApprovalDecision{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.svc.Reject(ctx.Context(), userInfo.ShopID, id, userInfo.Username, docReq.Comment)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Get Document Approval godoc
+// @Description get approval of document with approvers and history of submit, approve and reject
+// @Tags		Approval
+// @Param		id  path      string  true  "Document ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/{id}/approval [get]
+// @Router /transaction/stock-adjustment/{id}/approval [get]
+// @Router /transaction/pay/{id}/approval [get]
+// @Router /gl/journal/{id}/approval [get]
+func (h DocumentApprovalHttp) InfoDocumentApproval(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.Info(ctx.Context(), shopID, id)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	approvalRuleCollectionName     = "approvalRules"
+	documentApprovalCollectionName = "documentApprovals"
+)
+
+// document types which need approval before they are posted
+const (
+	DocTypePurchaseOrder   = "purchaseorder"
+	DocTypeStockAdjustment = "stockadjustment"
+	DocTypePay             = "pay"
+	DocTypeJournal         = "journal"
+)
+
+var DocTypes = []string{
+	DocTypePurchaseOrder,
+	DocTypeStockAdjustment,
+	DocTypePay,
+	DocTypeJournal,
+}
+
+// IsDocType return true when the document type can have approval rules
+func IsDocType(docType string) bool {
+	for _, item := range DocTypes {
+		if item == docType {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	StatusDraft    = "draft"
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+const (
+	ActionSubmit  = "submit"
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+)
+
+// IsApproved return true when the document can be posted, document which is saved before approval
+// is added has empty status and it is approved
+func IsApproved(status string) bool {
+	return status == "" || status == StatusApproved
+}
+
+// DocApproval is approval status of the document, IsDraft is sent on create or update
+// to keep the document in draft instead of submit it for approval
+type DocApproval struct {
+	ApprovalStatus string `json:"approvalstatus" bson:"approvalstatus"`
+	IsDraft        bool   `json:"isdraft,omitempty" bson:"-"`
+}
+
+func (doc DocApproval) GetApprovalStatus() string {
+	return doc.ApprovalStatus
+}
+
+func (doc DocApproval) GetIsDraft() bool {
+	return doc.IsDraft
+}
+
+// ApprovalRule is needed approval of document of the doc type which amount is at least MinAmount,
+// rule of the highest MinAmount which the amount reach is used
+type ApprovalRule struct {
+	DocType       string  `json:"doctype" bson:"doctype" validate:"required"`
+	MinAmount     float64 `json:"minamount" bson:"minamount" validate:"min=0"`
+	RequiredRole  string  `json:"requiredrole" bson:"requiredrole" validate:"max=100"`
+	ApproverCount int     `json:"approvercount" bson:"approvercount" validate:"min=1,max=10"`
+	IsActive      bool    `json:"isactive" bson:"isactive"`
+}
+
+type ApprovalRuleInfo struct {
+	models.DocIdentity `bson:"inline"`
+	ApprovalRule       `bson:"inline"`
+}
+
+func (ApprovalRuleInfo) CollectionName() string {
+	return approvalRuleCollectionName
+}
+
+type ApprovalRuleData struct {
+	models.ShopIdentity `bson:"inline"`
+	ApprovalRuleInfo    `bson:"inline"`
+}
+
+type ApprovalRuleDoc struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ApprovalRuleData   `bson:"inline"`
+	models.ActivityDoc `bson:"inline"`
+}
+
+func (ApprovalRuleDoc) CollectionName() string {
+	return approvalRuleCollectionName
+}
+
+// ApprovalAction is submit, approve or reject of the document
+type ApprovalAction struct {
+	Action    string    `json:"action" bson:"action"`
+	Username  string    `json:"username" bson:"username"`
+	Comment   string    `json:"comment" bson:"comment"`
+	CreatedAt time.Time `json:"createdat" bson:"createdat"`
+}
+
+// DocumentApproval is approval of a document, it is one per document and it start again when the document is submitted
+type DocumentApproval struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ShopID        string             `json:"shopid" bson:"shopid"`
+	DocType       string             `json:"doctype" bson:"doctype"`
+	DocGuid       string             `json:"docguid" bson:"docguid"`
+	DocNo         string             `json:"docno" bson:"docno"`
+	Amount        float64            `json:"amount" bson:"amount"`
+	Status        string             `json:"status" bson:"status"`
+	RuleGuid      string             `json:"ruleguid" bson:"ruleguid"`
+	RequiredRole  string             `json:"requiredrole" bson:"requiredrole"`
+	ApproverCount int                `json:"approvercount" bson:"approvercount"`
+	SubmittedBy   string             `json:"submittedby" bson:"submittedby"`
+	SubmittedAt   time.Time          `json:"submittedat" bson:"submittedat"`
+	Approvers     []string           `json:"approvers" bson:"approvers"`
+	Actions       []ApprovalAction   `json:"actions" bson:"actions"`
+	UpdatedAt     time.Time          `json:"updatedat" bson:"updatedat"`
+}
+
+func (DocumentApproval) CollectionName() string {
+	return documentApprovalCollectionName
+}
+
+// ApprovalDocument is document which is sent for approval
+type ApprovalDocument struct {
+	ShopID  string
+	DocType string
+	DocGuid string
+	DocNo   string
+	Amount  float64
+}
+
+type ApprovalDecision struct {
+	Comment string `json:"comment" validate:"max=1000"`
+}
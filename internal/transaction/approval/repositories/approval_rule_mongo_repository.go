@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IApprovalRuleRepository interface {
+	Create(ctx context.Context, doc models.ApprovalRuleDoc) (string, error)
+	Update(ctx context.Context, shopID string, guid string, doc models.ApprovalRuleDoc) error
+	DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.ApprovalRuleDoc, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.ApprovalRuleInfo, mongopagination.PaginationData, error)
+	FindActiveByDocType(ctx context.Context, shopID string, docType string) ([]models.ApprovalRuleDoc, error)
+}
+
+type ApprovalRuleRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.CrudRepository[models.ApprovalRuleDoc]
+	repositories.SearchRepository[models.ApprovalRuleInfo]
+}
+
+func NewApprovalRuleRepository(pst microservice.IPersisterMongo) *ApprovalRuleRepository {
+
+	insRepo := &ApprovalRuleRepository{
+		pst: pst,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.ApprovalRuleDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.ApprovalRuleInfo](pst)
+
+	return insRepo
+}
+
+// FindActiveByDocType return active rules of the doc type of the shop
+func (repo ApprovalRuleRepository) FindActiveByDocType(ctx context.Context, shopID string, docType string) ([]models.ApprovalRuleDoc, error) {
+	docList := []models.ApprovalRuleDoc{}
+	err := repo.pst.Find(ctx, &models.ApprovalRuleDoc{}, bson.M{
+		"shopid":    shopID,
+		"doctype":   docType,
+		"isactive":  true,
+		"deletedat": bson.M{"$exists": false},
+	}, &docList)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return docList, nil
+}
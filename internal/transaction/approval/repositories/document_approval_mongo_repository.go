@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/utils/search"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IDocumentApprovalRepository interface {
+	FindByDoc(ctx context.Context, shopID string, docType string, docGuid string) (models.DocumentApproval, error)
+	// Save replace approval of the document, approvers of the previous submit are removed
+	Save(ctx context.Context, doc models.DocumentApproval) error
+	// SaveWhenStatus replace approval of the document when its status is still fromStatus, false is returned
+	// when it is changed by another request
+	SaveWhenStatus(ctx context.Context, doc models.DocumentApproval, fromStatus string) (bool, error)
+	// AddApprover add approver to pending approval, false is returned when approval is not pending
+	// or the user already approved so the same user is not counted twice by concurrent requests
+	AddApprover(ctx context.Context, doc models.DocumentApproval, action models.ApprovalAction) (models.DocumentApproval, bool, error)
+	// UpdateStatus change status of pending approval, false is returned when approval is not pending
+	UpdateStatus(ctx context.Context, doc models.DocumentApproval, status string, action *models.ApprovalAction) (bool, error)
+	DeleteByDoc(ctx context.Context, shopID string, docType string, docGuid string) error
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.DocumentApproval, mongopagination.PaginationData, error)
+}
+
+type DocumentApprovalRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewDocumentApprovalRepository(pst microservice.IPersisterMongo) *DocumentApprovalRepository {
+	return &DocumentApprovalRepository{
+		pst: pst,
+	}
+}
+
+func docFilter(shopID string, docType string, docGuid string) bson.M {
+	return bson.M{
+		"shopid":  shopID,
+		"doctype": docType,
+		"docguid": docGuid,
+	}
+}
+
+func (repo DocumentApprovalRepository) FindByDoc(ctx context.Context, shopID string, docType string, docGuid string) (models.DocumentApproval, error) {
+	doc := models.DocumentApproval{}
+	err := repo.pst.FindOne(ctx, &models.DocumentApproval{}, docFilter(shopID, docType, docGuid), &doc)
+	if err != nil {
+		return models.DocumentApproval{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo DocumentApprovalRepository) Save(ctx context.Context, doc models.DocumentApproval) error {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.DocumentApproval{})
+	if err != nil {
+		return err
+	}
+
+	doc.ID = primitive.NilObjectID
+	_, err = collection.ReplaceOne(ctx, docFilter(doc.ShopID, doc.DocType, doc.DocGuid), doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (repo DocumentApprovalRepository) SaveWhenStatus(ctx context.Context, doc models.DocumentApproval, fromStatus string) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.DocumentApproval{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := docFilter(doc.ShopID, doc.DocType, doc.DocGuid)
+	filter["status"] = fromStatus
+
+	doc.ID = primitive.NilObjectID
+	result, err := collection.ReplaceOne(ctx, filter, doc)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo DocumentApprovalRepository) AddApprover(ctx context.Context, doc models.DocumentApproval, action models.ApprovalAction) (models.DocumentApproval, bool, error) {
+	filter := docFilter(doc.ShopID, doc.DocType, doc.DocGuid)
+	filter["status"] = models.StatusPending
+	filter["approvers"] = bson.M{"$ne": action.Username}
+
+	updated := models.DocumentApproval{}
+	err := repo.pst.FindOneAndUpdate(
+		ctx,
+		&models.DocumentApproval{},
+		filter,
+		bson.M{
+			"$push": bson.M{
+				"approvers": action.Username,
+				"actions":   action,
+			},
+			"$set": bson.M{"updatedat": action.CreatedAt},
+		},
+		&updated,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+
+	if err != nil {
+		return models.DocumentApproval{}, false, err
+	}
+
+	if updated.ID.IsZero() {
+		return models.DocumentApproval{}, false, nil
+	}
+
+	return updated, true, nil
+}
+
+func (repo DocumentApprovalRepository) UpdateStatus(ctx context.Context, doc models.DocumentApproval, status string, action *models.ApprovalAction) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.DocumentApproval{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := docFilter(doc.ShopID, doc.DocType, doc.DocGuid)
+	filter["status"] = models.StatusPending
+
+	update := bson.M{
+		"$set": bson.M{"status": status, "updatedat": doc.UpdatedAt},
+	}
+
+	if action != nil {
+		update["$push"] = bson.M{"actions": action}
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo DocumentApprovalRepository) DeleteByDoc(ctx context.Context, shopID string, docType string, docGuid string) error {
+	return repo.pst.Delete(ctx, &models.DocumentApproval{}, docFilter(shopID, docType, docGuid))
+}
+
+func (repo DocumentApprovalRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.DocumentApproval, mongopagination.PaginationData, error) {
+
+	queryFilters := bson.M{
+		"shopid": shopID,
+	}
+
+	for key, value := range filters {
+		queryFilters[key] = value
+	}
+
+	searchFilterQuery := search.CreateTextFilter(searchInFields, pageable.Query)
+	if len(searchFilterQuery) > 0 {
+		queryFilters["$or"] = searchFilterQuery
+	}
+
+	docList := []models.DocumentApproval{}
+	pagination, err := repo.pst.FindPage(ctx, &models.DocumentApproval{}, queryFilters, pageable, &docList)
+
+	if err != nil {
+		return []models.DocumentApproval{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
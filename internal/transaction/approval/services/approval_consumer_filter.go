@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/pkg/microservice"
+	"strings"
+)
+
+type approvalStatusMessage struct {
+	ApprovalStatus string `json:"approvalstatus"`
+}
+
+// inputContext is context of part of bulk message
+type inputContext struct {
+	microservice.IContext
+	input string
+}
+
+func (ctx inputContext) ReadInput() string {
+	return ctx.input
+}
+
+// ApprovedOnly pass approved document of the message to onApproved and the other to onNotApproved,
+// so projection of document which is not approved is removed, documents of bulk message are split
+// by their status and handler is called only when it has document
+func ApprovedOnly(onApproved microservice.ServiceHandleFunc, onNotApproved microservice.ServiceHandleFunc) microservice.ServiceHandleFunc {
+	return func(ctx microservice.IContext) error {
+		input := ctx.ReadInput()
+
+		if !strings.HasPrefix(strings.TrimSpace(input), "[") {
+			doc := approvalStatusMessage{}
+			if err := json.Unmarshal([]byte(input), &doc); err == nil && !models.IsApproved(doc.ApprovalStatus) {
+				return onNotApproved(ctx)
+			}
+			return onApproved(ctx)
+		}
+
+		rawDocs := []json.RawMessage{}
+		if err := json.Unmarshal([]byte(input), &rawDocs); err != nil {
+			return onApproved(ctx)
+		}
+
+		approved, notApproved := []json.RawMessage{}, []json.RawMessage{}
+		for _, rawDoc := range rawDocs {
+			doc := approvalStatusMessage{}
+			if err := json.Unmarshal(rawDoc, &doc); err == nil && !models.IsApproved(doc.ApprovalStatus) {
+				notApproved = append(notApproved, rawDoc)
+				continue
+			}
+			approved = append(approved, rawDoc)
+		}
+
+		if len(notApproved) == 0 {
+			return onApproved(ctx)
+		}
+
+		if len(approved) > 0 {
+			approvedInput, err := json.Marshal(approved)
+			if err != nil {
+				return err
+			}
+
+			if err = onApproved(inputContext{IContext: ctx, input: string(approvedInput)}); err != nil {
+				return err
+			}
+		}
+
+		notApprovedInput, err := json.Marshal(notApproved)
+		if err != nil {
+			return err
+		}
+
+		return onNotApproved(inputContext{IContext: ctx, input: string(notApprovedInput)})
+	}
+}
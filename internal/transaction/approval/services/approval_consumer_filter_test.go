@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"encoding/json"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	"smlaicloudplatform/pkg/microservice"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type filterMessage struct {
+	DocNo string `json:"docno"`
+}
+
+func readDocNos(t *testing.T, input string) []string {
+	docs := []filterMessage{}
+	require.NoError(t, json.Unmarshal([]byte(input), &docs))
+
+	docNos := []string{}
+	for _, doc := range docs {
+		docNos = append(docNos, doc.DocNo)
+	}
+	return docNos
+}
+
+func TestApprovedOnlySingleDoc(t *testing.T) {
+	approved, notApproved := []string{}, []string{}
+	handler := services.ApprovedOnly(
+		func(ctx microservice.IContext) error {
+			approved = append(approved, ctx.ReadInput())
+			return nil
+		},
+		func(ctx microservice.IContext) error {
+			notApproved = append(notApproved, ctx.ReadInput())
+			return nil
+		},
+	)
+
+	require.NoError(t, handler(microservice.NewConsumerContext(nil, `{"docno":"PO1","approvalstatus":"approved"}`)))
+	require.NoError(t, handler(microservice.NewConsumerContext(nil, `{"docno":"PO2"}`)))
+	require.NoError(t, handler(microservice.NewConsumerContext(nil, `{"docno":"PO3","approvalstatus":"pending"}`)))
+
+	assert.Equal(t, []string{`{"docno":"PO1","approvalstatus":"approved"}`, `{"docno":"PO2"}`}, approved)
+	assert.Equal(t, []string{`{"docno":"PO3","approvalstatus":"pending"}`}, notApproved)
+}
+
+func TestApprovedOnlyBulkDoc(t *testing.T) {
+	approved, notApproved := []string{}, []string{}
+	handler := services.ApprovedOnly(
+		func(ctx microservice.IContext) error {
+			approved = append(approved, readDocNos(t, ctx.ReadInput())...)
+			return nil
+		},
+		func(ctx microservice.IContext) error {
+			notApproved = append(notApproved, readDocNos(t, ctx.ReadInput())...)
+			return nil
+		},
+	)
+
+	input := `[{"docno":"PO1","approvalstatus":"approved"},{"docno":"PO2","approvalstatus":"draft"},{"docno":"PO3"},{"docno":"PO4","approvalstatus":"rejected"}]`
+	require.NoError(t, handler(microservice.NewConsumerContext(nil, input)))
+
+	assert.Equal(t, []string{"PO1", "PO3"}, approved)
+	assert.Equal(t, []string{"PO2", "PO4"}, notApproved)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/repositories"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrApprovalRuleNotFound = errors.New("approval rule not found")
+
+type IApprovalHttpService interface {
+	CreateApprovalRule(ctx context.Context, shopID string, authUsername string, doc models.ApprovalRule) (string, error)
+	UpdateApprovalRule(ctx context.Context, shopID string, guid string, authUsername string, doc models.ApprovalRule) error
+	DeleteApprovalRule(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoApprovalRule(ctx context.Context, shopID string, guid string) (models.ApprovalRuleInfo, error)
+	SearchApprovalRule(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ApprovalRuleInfo, mongopagination.PaginationData, error)
+	Inbox(ctx context.Context, shopID string, username string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentApproval, mongopagination.PaginationData, error)
+}
+
+type ApprovalHttpService struct {
+	ruleRepo       repositories.IApprovalRuleRepository
+	docRepo        repositories.IDocumentApprovalRepository
+	permissions    IApproverPermission
+	timeNow        func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewApprovalHttpService(ruleRepo repositories.IApprovalRuleRepository, docRepo repositories.IDocumentApprovalRepository, permissions IApproverPermission, timeNow func() time.Time) *ApprovalHttpService {
+	return &ApprovalHttpService{
+		ruleRepo:       ruleRepo,
+		docRepo:        docRepo,
+		permissions:    permissions,
+		timeNow:        timeNow,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc ApprovalHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc ApprovalHttpService) CreateApprovalRule(ctx context.Context, shopID string, authUsername string, doc models.ApprovalRule) (string, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := validateApprovalRule(doc)
+	if err != nil {
+		return "", err
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	docData := models.ApprovalRuleDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.ApprovalRule = doc
+
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = svc.timeNow()
+
+	_, err = svc.ruleRepo.Create(ctx, docData)
+
+	if err != nil {
+		return "", err
+	}
+
+	return newGuidFixed, nil
+}
+
+// UpdateApprovalRule replace the rule, documents which are waiting for approval keep the rule of their submit
+func (svc ApprovalHttpService) UpdateApprovalRule(ctx context.Context, shopID string, guid string, authUsername string, doc models.ApprovalRule) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := validateApprovalRule(doc)
+	if err != nil {
+		return err
+	}
+
+	findDoc, err := svc.ruleRepo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrApprovalRuleNotFound
+	}
+
+	findDoc.ApprovalRule = doc
+
+	findDoc.UpdatedBy = authUsername
+	findDoc.UpdatedAt = svc.timeNow()
+
+	return svc.ruleRepo.Update(ctx, shopID, guid, findDoc)
+}
+
+func (svc ApprovalHttpService) DeleteApprovalRule(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.ruleRepo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrApprovalRuleNotFound
+	}
+
+	return svc.ruleRepo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+}
+
+func (svc ApprovalHttpService) InfoApprovalRule(ctx context.Context, shopID string, guid string) (models.ApprovalRuleInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.ruleRepo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.ApprovalRuleInfo{}, err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return models.ApprovalRuleInfo{}, ErrApprovalRuleNotFound
+	}
+
+	return findDoc.ApprovalRuleInfo, nil
+}
+
+func (svc ApprovalHttpService) SearchApprovalRule(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ApprovalRuleInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"doctype",
+		"requiredrole",
+	}
+
+	docList, pagination, err := svc.ruleRepo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.ApprovalRuleInfo{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+// Inbox return documents waiting for approval which the user can approve, documents which the user
+// submitted or already approved are not listed, oldest first when sort is not requested
+func (svc ApprovalHttpService) Inbox(ctx context.Context, shopID string, username string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentApproval, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	userPermission, err := svc.permissions.UserPermissions(ctx, shopID, username)
+	if err != nil {
+		return []models.DocumentApproval{}, mongopagination.PaginationData{}, err
+	}
+
+	inboxFilters := map[string]interface{}{}
+	for key, value := range filters {
+		inboxFilters[key] = value
+	}
+
+	inboxFilters["status"] = models.StatusPending
+	inboxFilters["submittedby"] = bson.M{"$ne": username}
+	inboxFilters["approvers"] = bson.M{"$ne": username}
+
+	if !CanApprove(userPermission, "") {
+		return []models.DocumentApproval{}, mongopagination.PaginationData{}, nil
+	}
+
+	// owner can approve document of every role
+	if !isOwner(userPermission) {
+		inboxFilters["requiredrole"] = bson.M{"$in": append([]string{""}, userPermission.Roles...)}
+	}
+
+	if len(pageable.Sorts) < 1 {
+		pageable.Sorts = append(pageable.Sorts, micromodels.KeyInt{Key: "submittedat", Value: 1})
+	}
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	docList, pagination, err := svc.docRepo.FindPageFilter(ctx, shopID, inboxFilters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.DocumentApproval{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+func validateApprovalRule(doc models.ApprovalRule) error {
+	if !models.IsDocType(doc.DocType) {
+		return fmt.Errorf("doc type %s does not support approval", doc.DocType)
+	}
+
+	if doc.ApproverCount < 1 {
+		return errors.New("approver count must be at least one")
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/repositories"
+	"time"
+)
+
+var (
+	ErrApprovalNotFound        = errors.New("approval of the document not found")
+	ErrApprovalNotPending      = errors.New("document is not waiting for approval")
+	ErrApprovalBySubmitter     = errors.New("document cannot be approved or rejected by the user who submitted it")
+	ErrApprovalAlreadyApproved = errors.New("document is already approved by the user")
+	ErrApprovalNotAllowed      = errors.New("user does not have the role which is required to approve the document")
+	ErrApprovalCommentRequired = errors.New("comment is required to reject the document")
+	ErrApprovalNotSubmittable  = errors.New("only draft or rejected document can be submitted for approval")
+)
+
+// IApproverPermission return roles and permissions of shop user, it is implemented by rbac permission service
+type IApproverPermission interface {
+	UserPermissions(ctx context.Context, shopID string, username string) (rbacmodels.UserPermission, error)
+}
+
+// IApprovalWorkflow keep approval of documents, document is posted by consumers only when its status is approved
+type IApprovalWorkflow interface {
+	// Submit start approval of new, draft or rejected document and return its status, draft is kept as draft,
+	// document which no rule need approval is approved immediately
+	Submit(ctx context.Context, doc models.ApprovalDocument, username string, isDraft bool) (string, error)
+	// Resubmit start approval of the changed document again whatever its status is, it is used when the document is saved
+	Resubmit(ctx context.Context, doc models.ApprovalDocument, username string, isDraft bool) (string, error)
+	// Approve add the user to approvers and return status of the document, it is approved when approvers reach the rule
+	Approve(ctx context.Context, shopID string, docType string, docGuid string, username string, comment string) (string, error)
+	Reject(ctx context.Context, shopID string, docType string, docGuid string, username string, comment string) error
+	Info(ctx context.Context, shopID string, docType string, docGuid string) (models.DocumentApproval, error)
+	Remove(ctx context.Context, shopID string, docType string, docGuid string) error
+}
+
+type ApprovalWorkflow struct {
+	ruleRepo    repositories.IApprovalRuleRepository
+	docRepo     repositories.IDocumentApprovalRepository
+	permissions IApproverPermission
+	timeNow     func() time.Time
+}
+
+func NewApprovalWorkflow(ruleRepo repositories.IApprovalRuleRepository, docRepo repositories.IDocumentApprovalRepository, permissions IApproverPermission, timeNow func() time.Time) *ApprovalWorkflow {
+	return &ApprovalWorkflow{
+		ruleRepo:    ruleRepo,
+		docRepo:     docRepo,
+		permissions: permissions,
+		timeNow:     timeNow,
+	}
+}
+
+// MatchRule return active rule of the highest min amount which the amount reach
+func MatchRule(rules []models.ApprovalRuleDoc, amount float64) (models.ApprovalRuleDoc, bool) {
+	matched, ok := models.ApprovalRuleDoc{}, false
+	for _, rule := range rules {
+		if !rule.IsActive || rule.MinAmount > amount {
+			continue
+		}
+
+		if !ok || rule.MinAmount > matched.MinAmount {
+			matched, ok = rule, true
+		}
+	}
+	return matched, ok
+}
+
+func (svc ApprovalWorkflow) Submit(ctx context.Context, doc models.ApprovalDocument, username string, isDraft bool) (string, error) {
+	findDoc, err := svc.docRepo.FindByDoc(ctx, doc.ShopID, doc.DocType, doc.DocGuid)
+	if err != nil {
+		return "", err
+	}
+
+	// pending document is waiting for approvers and approved document is posted already
+	if !findDoc.ID.IsZero() && findDoc.Status != models.StatusDraft && findDoc.Status != models.StatusRejected {
+		return "", ErrApprovalNotSubmittable
+	}
+
+	approval, err := svc.newApproval(ctx, doc, findDoc, username, isDraft)
+	if err != nil {
+		return "", err
+	}
+
+	if findDoc.ID.IsZero() {
+		return approval.Status, svc.docRepo.Save(ctx, approval)
+	}
+
+	// approval is submitted or approved by another request after it is read
+	ok, err := svc.docRepo.SaveWhenStatus(ctx, approval, findDoc.Status)
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		return "", ErrApprovalNotSubmittable
+	}
+
+	return approval.Status, nil
+}
+
+func (svc ApprovalWorkflow) Resubmit(ctx context.Context, doc models.ApprovalDocument, username string, isDraft bool) (string, error) {
+	findDoc, err := svc.docRepo.FindByDoc(ctx, doc.ShopID, doc.DocType, doc.DocGuid)
+	if err != nil {
+		return "", err
+	}
+
+	approval, err := svc.newApproval(ctx, doc, findDoc, username, isDraft)
+	if err != nil {
+		return "", err
+	}
+
+	return approval.Status, svc.docRepo.Save(ctx, approval)
+}
+
+// newApproval return approval which replace approval findDoc of the submitted document, actions of findDoc are kept
+func (svc ApprovalWorkflow) newApproval(ctx context.Context, doc models.ApprovalDocument, findDoc models.DocumentApproval, username string, isDraft bool) (models.DocumentApproval, error) {
+	now := svc.timeNow()
+
+	approval := models.DocumentApproval{
+		ShopID:    doc.ShopID,
+		DocType:   doc.DocType,
+		DocGuid:   doc.DocGuid,
+		DocNo:     doc.DocNo,
+		Amount:    doc.Amount,
+		Status:    models.StatusDraft,
+		Approvers: []string{},
+		Actions:   findDoc.Actions,
+		UpdatedAt: now,
+	}
+
+	if approval.Actions == nil {
+		approval.Actions = []models.ApprovalAction{}
+	}
+
+	if !isDraft {
+		rules, err := svc.ruleRepo.FindActiveByDocType(ctx, doc.ShopID, doc.DocType)
+		if err != nil {
+			return models.DocumentApproval{}, err
+		}
+
+		approval.Status = models.StatusApproved
+		if rule, ok := MatchRule(rules, doc.Amount); ok {
+			approval.Status = models.StatusPending
+			approval.RuleGuid = rule.GuidFixed
+			approval.RequiredRole = rule.RequiredRole
+			approval.ApproverCount = rule.ApproverCount
+		}
+
+		approval.SubmittedBy = username
+		approval.SubmittedAt = now
+		approval.Actions = append(approval.Actions, models.ApprovalAction{
+			Action:    models.ActionSubmit,
+			Username:  username,
+			CreatedAt: now,
+		})
+	}
+
+	return approval, nil
+}
+
+// findPending return pending approval of the document which the user can approve or reject
+func (svc ApprovalWorkflow) findPending(ctx context.Context, shopID string, docType string, docGuid string, username string) (models.DocumentApproval, error) {
+	approval, err := svc.docRepo.FindByDoc(ctx, shopID, docType, docGuid)
+	if err != nil {
+		return models.DocumentApproval{}, err
+	}
+
+	if approval.ID.IsZero() {
+		return models.DocumentApproval{}, ErrApprovalNotFound
+	}
+
+	if approval.Status != models.StatusPending {
+		return models.DocumentApproval{}, ErrApprovalNotPending
+	}
+
+	if approval.SubmittedBy == username {
+		return models.DocumentApproval{}, ErrApprovalBySubmitter
+	}
+
+	userPermission, err := svc.permissions.UserPermissions(ctx, shopID, username)
+	if err != nil {
+		return models.DocumentApproval{}, err
+	}
+
+	if !CanApprove(userPermission, approval.RequiredRole) {
+		return models.DocumentApproval{}, ErrApprovalNotAllowed
+	}
+
+	return approval, nil
+}
+
+// CanApprove return true when the user has the required role, owner can approve every document
+// and every shop user can approve document of rule without required role
+func CanApprove(userPermission rbacmodels.UserPermission, requiredRole string) bool {
+	if isOwner(userPermission) {
+		return true
+	}
+
+	if requiredRole == "" {
+		return len(userPermission.Roles) > 0
+	}
+
+	for _, role := range userPermission.Roles {
+		if role == requiredRole {
+			return true
+		}
+	}
+	return false
+}
+
+func isOwner(userPermission rbacmodels.UserPermission) bool {
+	for _, permission := range userPermission.Permissions {
+		if permission == rbacmodels.PermissionAll {
+			return true
+		}
+	}
+	return false
+}
+
+func (svc ApprovalWorkflow) Approve(ctx context.Context, shopID string, docType string, docGuid string, username string, comment string) (string, error) {
+	approval, err := svc.findPending(ctx, shopID, docType, docGuid, username)
+	if err != nil {
+		return "", err
+	}
+
+	for _, approver := range approval.Approvers {
+		if approver == username {
+			return "", ErrApprovalAlreadyApproved
+		}
+	}
+
+	now := svc.timeNow()
+	updated, ok, err := svc.docRepo.AddApprover(ctx, approval, models.ApprovalAction{
+		Action:    models.ActionApprove,
+		Username:  username,
+		Comment:   comment,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// approval is changed by another request after it is read
+	if !ok {
+		return "", ErrApprovalNotPending
+	}
+
+	if len(updated.Approvers) < updated.ApproverCount {
+		return models.StatusPending, nil
+	}
+
+	updated.UpdatedAt = now
+	_, err = svc.docRepo.UpdateStatus(ctx, updated, models.StatusApproved, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// the last approver of concurrent requests can find it is approved by the other one
+	latest, err := svc.docRepo.FindByDoc(ctx, shopID, docType, docGuid)
+	if err != nil {
+		return "", err
+	}
+
+	return latest.Status, nil
+}
+
+func (svc ApprovalWorkflow) Reject(ctx context.Context, shopID string, docType string, docGuid string, username string, comment string) error {
+	if comment == "" {
+		return ErrApprovalCommentRequired
+	}
+
+	approval, err := svc.findPending(ctx, shopID, docType, docGuid, username)
+	if err != nil {
+		return err
+	}
+
+	approval.UpdatedAt = svc.timeNow()
+	ok, err := svc.docRepo.UpdateStatus(ctx, approval, models.StatusRejected, &models.ApprovalAction{
+		Action:    models.ActionReject,
+		Username:  username,
+		Comment:   comment,
+		CreatedAt: approval.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrApprovalNotPending
+	}
+
+	return nil
+}
+
+func (svc ApprovalWorkflow) Info(ctx context.Context, shopID string, docType string, docGuid string) (models.DocumentApproval, error) {
+	approval, err := svc.docRepo.FindByDoc(ctx, shopID, docType, docGuid)
+	if err != nil {
+		return models.DocumentApproval{}, err
+	}
+
+	if approval.ID.IsZero() {
+		return models.DocumentApproval{}, ErrApprovalNotFound
+	}
+
+	return approval, nil
+}
+
+func (svc ApprovalWorkflow) Remove(ctx context.Context, shopID string, docType string, docGuid string) error {
+	return svc.docRepo.DeleteByDoc(ctx, shopID, docType, docGuid)
+}
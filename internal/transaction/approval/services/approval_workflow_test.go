@@ -0,0 +1,307 @@
+package services_test
+
+import (
+	"context"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"testing"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryApprovalRuleRepository struct {
+	rules []models.ApprovalRuleDoc
+}
+
+func (repo *memoryApprovalRuleRepository) Create(ctx context.Context, doc models.ApprovalRuleDoc) (string, error) {
+	repo.rules = append(repo.rules, doc)
+	return doc.GuidFixed, nil
+}
+
+func (repo *memoryApprovalRuleRepository) Update(ctx context.Context, shopID string, guid string, doc models.ApprovalRuleDoc) error {
+	return nil
+}
+
+func (repo *memoryApprovalRuleRepository) DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error {
+	return nil
+}
+
+func (repo *memoryApprovalRuleRepository) FindByGuid(ctx context.Context, shopID string, guid string) (models.ApprovalRuleDoc, error) {
+	return models.ApprovalRuleDoc{}, nil
+}
+
+func (repo *memoryApprovalRuleRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.ApprovalRuleInfo, mongopagination.PaginationData, error) {
+	return nil, mongopagination.PaginationData{}, nil
+}
+
+func (repo *memoryApprovalRuleRepository) FindActiveByDocType(ctx context.Context, shopID string, docType string) ([]models.ApprovalRuleDoc, error) {
+	docList := []models.ApprovalRuleDoc{}
+	for _, rule := range repo.rules {
+		if rule.ShopID == shopID && rule.DocType == docType && rule.IsActive {
+			docList = append(docList, rule)
+		}
+	}
+	return docList, nil
+}
+
+type memoryDocumentApprovalRepository struct {
+	approvals map[string]*models.DocumentApproval
+}
+
+func newMemoryDocumentApprovalRepository() *memoryDocumentApprovalRepository {
+	return &memoryDocumentApprovalRepository{approvals: map[string]*models.DocumentApproval{}}
+}
+
+func approvalKey(shopID string, docType string, docGuid string) string {
+	return shopID + "/" + docType + "/" + docGuid
+}
+
+func (repo *memoryDocumentApprovalRepository) FindByDoc(ctx context.Context, shopID string, docType string, docGuid string) (models.DocumentApproval, error) {
+	doc, ok := repo.approvals[approvalKey(shopID, docType, docGuid)]
+	if !ok {
+		return models.DocumentApproval{}, nil
+	}
+	return *doc, nil
+}
+
+func (repo *memoryDocumentApprovalRepository) Save(ctx context.Context, doc models.DocumentApproval) error {
+	key := approvalKey(doc.ShopID, doc.DocType, doc.DocGuid)
+	doc.ID = primitive.NewObjectID()
+	if found, ok := repo.approvals[key]; ok {
+		doc.ID = found.ID
+	}
+	repo.approvals[key] = &doc
+	return nil
+}
+
+func (repo *memoryDocumentApprovalRepository) SaveWhenStatus(ctx context.Context, doc models.DocumentApproval, fromStatus string) (bool, error) {
+	found, ok := repo.approvals[approvalKey(doc.ShopID, doc.DocType, doc.DocGuid)]
+	if !ok || found.Status != fromStatus {
+		return false, nil
+	}
+	return true, repo.Save(ctx, doc)
+}
+
+func (repo *memoryDocumentApprovalRepository) AddApprover(ctx context.Context, doc models.DocumentApproval, action models.ApprovalAction) (models.DocumentApproval, bool, error) {
+	found, ok := repo.approvals[approvalKey(doc.ShopID, doc.DocType, doc.DocGuid)]
+	if !ok || found.Status != models.StatusPending {
+		return models.DocumentApproval{}, false, nil
+	}
+
+	for _, approver := range found.Approvers {
+		if approver == action.Username {
+			return models.DocumentApproval{}, false, nil
+		}
+	}
+
+	found.Approvers = append(found.Approvers, action.Username)
+	found.Actions = append(found.Actions, action)
+	return *found, true, nil
+}
+
+func (repo *memoryDocumentApprovalRepository) UpdateStatus(ctx context.Context, doc models.DocumentApproval, status string, action *models.ApprovalAction) (bool, error) {
+	found, ok := repo.approvals[approvalKey(doc.ShopID, doc.DocType, doc.DocGuid)]
+	if !ok || found.Status != models.StatusPending {
+		return false, nil
+	}
+
+	found.Status = status
+	if action != nil {
+		found.Actions = append(found.Actions, *action)
+	}
+	return true, nil
+}
+
+func (repo *memoryDocumentApprovalRepository) DeleteByDoc(ctx context.Context, shopID string, docType string, docGuid string) error {
+	delete(repo.approvals, approvalKey(shopID, docType, docGuid))
+	return nil
+}
+
+func (repo *memoryDocumentApprovalRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.DocumentApproval, mongopagination.PaginationData, error) {
+	return nil, mongopagination.PaginationData{}, nil
+}
+
+type memoryApproverPermission map[string]rbacmodels.UserPermission
+
+func (permissions memoryApproverPermission) UserPermissions(ctx context.Context, shopID string, username string) (rbacmodels.UserPermission, error) {
+	return permissions[username], nil
+}
+
+func newApprovalRule(guid string, docType string, minAmount float64, requiredRole string, approverCount int) models.ApprovalRuleDoc {
+	rule := models.ApprovalRuleDoc{}
+	rule.ShopID = "shop1"
+	rule.GuidFixed = guid
+	rule.DocType = docType
+	rule.MinAmount = minAmount
+	rule.RequiredRole = requiredRole
+	rule.ApproverCount = approverCount
+	rule.IsActive = true
+	return rule
+}
+
+func newTestWorkflow(rules ...models.ApprovalRuleDoc) (*services.ApprovalWorkflow, *memoryDocumentApprovalRepository) {
+	docRepo := newMemoryDocumentApprovalRepository()
+	permissions := memoryApproverPermission{
+		"clerk":    {Roles: []string{"staff"}, Permissions: []string{"transaction.purchaseorder:create"}},
+		"manager1": {Roles: []string{"manager"}},
+		"manager2": {Roles: []string{"manager", "staff"}},
+		"owner":    {Roles: []string{"owner"}, Permissions: []string{rbacmodels.PermissionAll}},
+	}
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	workflow := services.NewApprovalWorkflow(&memoryApprovalRuleRepository{rules: rules}, docRepo, permissions, func() time.Time { return now })
+	return workflow, docRepo
+}
+
+func purchaseOrder(guid string, amount float64) models.ApprovalDocument {
+	return models.ApprovalDocument{
+		ShopID:  "shop1",
+		DocType: models.DocTypePurchaseOrder,
+		DocGuid: guid,
+		DocNo:   "PO-" + guid,
+		Amount:  amount,
+	}
+}
+
+func TestMatchRuleUseHighestThreshold(t *testing.T) {
+	rules := []models.ApprovalRuleDoc{
+		newApprovalRule("small", models.DocTypePurchaseOrder, 0, "", 1),
+		newApprovalRule("large", models.DocTypePurchaseOrder, 100000, "manager", 2),
+		newApprovalRule("medium", models.DocTypePurchaseOrder, 10000, "manager", 1),
+	}
+
+	rule, ok := services.MatchRule(rules, 50000)
+	require.True(t, ok)
+	assert.Equal(t, "medium", rule.GuidFixed)
+
+	rule, ok = services.MatchRule(rules, 100000)
+	require.True(t, ok)
+	assert.Equal(t, "large", rule.GuidFixed)
+
+	_, ok = services.MatchRule(rules[1:], 500)
+	assert.False(t, ok)
+}
+
+func TestApprovalWorkflowApproveByRequiredApprovers(t *testing.T) {
+	workflow, docRepo := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 10000, "manager", 2))
+	ctx := context.Background()
+
+	// document below threshold is approved immediately
+	status, err := workflow.Submit(ctx, purchaseOrder("po1", 500), "clerk", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+
+	status, err = workflow.Submit(ctx, purchaseOrder("po2", 20000), "clerk", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+
+	_, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po2", "clerk", "")
+	assert.ErrorIs(t, err, services.ErrApprovalBySubmitter)
+
+	status, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po2", "manager1", "ok")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+
+	_, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po2", "manager1", "")
+	assert.ErrorIs(t, err, services.ErrApprovalAlreadyApproved)
+
+	status, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po2", "manager2", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+
+	approval, err := workflow.Info(ctx, "shop1", models.DocTypePurchaseOrder, "po2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"manager1", "manager2"}, approval.Approvers)
+	assert.Len(t, approval.Actions, 3)
+	assert.Equal(t, models.StatusApproved, docRepo.approvals[approvalKey("shop1", models.DocTypePurchaseOrder, "po2")].Status)
+
+	_, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po2", "owner", "")
+	assert.ErrorIs(t, err, services.ErrApprovalNotPending)
+}
+
+func TestApprovalWorkflowRequiredRole(t *testing.T) {
+	workflow, _ := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 0, "manager", 1))
+	ctx := context.Background()
+
+	_, err := workflow.Submit(ctx, purchaseOrder("po1", 100), "manager1", false)
+	require.NoError(t, err)
+
+	_, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "clerk", "")
+	assert.ErrorIs(t, err, services.ErrApprovalNotAllowed)
+
+	// owner can approve document of every role
+	status, err := workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "owner", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+}
+
+func TestApprovalWorkflowRejectAndSubmitAgain(t *testing.T) {
+	workflow, _ := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 0, "manager", 1))
+	ctx := context.Background()
+
+	status, err := workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", true)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, status)
+
+	_, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "manager1", "")
+	assert.ErrorIs(t, err, services.ErrApprovalNotPending)
+
+	_, err = workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", false)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, workflow.Reject(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "manager1", ""), services.ErrApprovalCommentRequired)
+	require.NoError(t, workflow.Reject(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "manager1", "wrong supplier"))
+
+	approval, err := workflow.Info(ctx, "shop1", models.DocTypePurchaseOrder, "po1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, approval.Status)
+
+	// document which is submitted again need approvers again and keep history
+	status, err = workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+
+	approval, err = workflow.Info(ctx, "shop1", models.DocTypePurchaseOrder, "po1")
+	require.NoError(t, err)
+	assert.Empty(t, approval.Approvers)
+	assert.Len(t, approval.Actions, 3)
+	assert.Equal(t, "wrong supplier", approval.Actions[1].Comment)
+
+	require.NoError(t, workflow.Remove(ctx, "shop1", models.DocTypePurchaseOrder, "po1"))
+	_, err = workflow.Info(ctx, "shop1", models.DocTypePurchaseOrder, "po1")
+	assert.ErrorIs(t, err, services.ErrApprovalNotFound)
+}
+
+func TestApprovalWorkflowSubmitOnlyDraftOrRejected(t *testing.T) {
+	workflow, _ := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 0, "manager", 1))
+	ctx := context.Background()
+
+	status, err := workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+
+	_, err = workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", false)
+	assert.ErrorIs(t, err, services.ErrApprovalNotSubmittable)
+
+	status, err = workflow.Approve(ctx, "shop1", models.DocTypePurchaseOrder, "po1", "manager1", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+
+	_, err = workflow.Submit(ctx, purchaseOrder("po1", 100), "clerk", false)
+	assert.ErrorIs(t, err, services.ErrApprovalNotSubmittable)
+
+	// approved document which is changed need approvers again
+	status, err = workflow.Resubmit(ctx, purchaseOrder("po1", 200), "clerk", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+
+	approval, err := workflow.Info(ctx, "shop1", models.DocTypePurchaseOrder, "po1")
+	require.NoError(t, err)
+	assert.Empty(t, approval.Approvers)
+	assert.Equal(t, 200.0, approval.Amount)
+}
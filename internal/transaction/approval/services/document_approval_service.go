@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+// IApprovalDoc is saved document which keep its approval status, it is implemented by models.DocApproval
+type IApprovalDoc interface {
+	GetApprovalStatus() string
+	GetIsDraft() bool
+}
+
+// IDocumentApprovalService submit, approve and reject saved documents of a doc type
+type IDocumentApprovalService interface {
+	Submit(ctx context.Context, shopID string, guid string, authUsername string) (string, error)
+	Approve(ctx context.Context, shopID string, guid string, authUsername string, comment string) (string, error)
+	Reject(ctx context.Context, shopID string, guid string, authUsername string, comment string) error
+	Info(ctx context.Context, shopID string, guid string) (models.DocumentApproval, error)
+}
+
+// DocumentApprovalService run approval workflow of saved documents of a doc type, document is read by load and
+// changed approval status is saved and published by publish of the module so consumers post or remove the document
+type DocumentApprovalService[T IApprovalDoc] struct {
+	workflow       IApprovalWorkflow
+	docType        string
+	load           func(ctx context.Context, shopID string, guid string) (T, error)
+	describe       func(doc T) models.ApprovalDocument
+	publish        func(ctx context.Context, doc T, approvalStatus string) error
+	contextTimeout time.Duration
+}
+
+func NewDocumentApprovalService[T IApprovalDoc](
+	workflow IApprovalWorkflow,
+	docType string,
+	load func(ctx context.Context, shopID string, guid string) (T, error),
+	describe func(doc T) models.ApprovalDocument,
+	publish func(ctx context.Context, doc T, approvalStatus string) error,
+) DocumentApprovalService[T] {
+	return DocumentApprovalService[T]{
+		workflow:       workflow,
+		docType:        docType,
+		load:           load,
+		describe:       describe,
+		publish:        publish,
+		contextTimeout: 15 * time.Second,
+	}
+}
+
+func (svc DocumentApprovalService[T]) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// findDoc return saved document, describe of document which is not found has empty doc guid
+func (svc DocumentApprovalService[T]) findDoc(ctx context.Context, shopID string, guid string) (T, error) {
+	doc, err := svc.load(ctx, shopID, guid)
+	if err != nil {
+		return doc, err
+	}
+
+	if svc.describe(doc).DocGuid == "" {
+		return doc, ErrDocumentNotFound
+	}
+
+	return doc, nil
+}
+
+// Submit send draft or rejected document for approval and return its approval status
+func (svc DocumentApprovalService[T]) Submit(ctx context.Context, shopID string, guid string, authUsername string) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findDoc(ctx, shopID, guid)
+	if err != nil {
+		return "", err
+	}
+
+	approvalStatus, err := svc.workflow.Submit(ctx, svc.describe(doc), authUsername, false)
+	if errors.Is(err, ErrApprovalNotSubmittable) {
+		return svc.republish(ctx, doc, err)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return approvalStatus, svc.saveApprovalStatus(ctx, doc, approvalStatus)
+}
+
+func (svc DocumentApprovalService[T]) Approve(ctx context.Context, shopID string, guid string, authUsername string, comment string) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findDoc(ctx, shopID, guid)
+	if err != nil {
+		return "", err
+	}
+
+	approvalStatus, err := svc.workflow.Approve(ctx, shopID, svc.docType, guid, authUsername, comment)
+	if errors.Is(err, ErrApprovalNotPending) {
+		return svc.republish(ctx, doc, err)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return approvalStatus, svc.saveApprovalStatus(ctx, doc, approvalStatus)
+}
+
+func (svc DocumentApprovalService[T]) Reject(ctx context.Context, shopID string, guid string, authUsername string, comment string) error {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	doc, err := svc.findDoc(ctx, shopID, guid)
+	if err != nil {
+		return err
+	}
+
+	err = svc.workflow.Reject(ctx, shopID, svc.docType, guid, authUsername, comment)
+	if errors.Is(err, ErrApprovalNotPending) {
+		_, err = svc.republish(ctx, doc, err)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return svc.saveApprovalStatus(ctx, doc, models.StatusRejected)
+}
+
+func (svc DocumentApprovalService[T]) Info(ctx context.Context, shopID string, guid string) (models.DocumentApproval, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.workflow.Info(ctx, shopID, svc.docType, guid)
+}
+
+// saveApprovalStatus publish approval status when the document keep other status, so it can be called again
+// for the workflow which is changed before
+func (svc DocumentApprovalService[T]) saveApprovalStatus(ctx context.Context, doc T, approvalStatus string) error {
+	if doc.GetApprovalStatus() == approvalStatus {
+		return nil
+	}
+
+	return svc.publish(ctx, doc, approvalStatus)
+}
+
+// republish save status of the workflow to the document when the workflow is changed by a request which fail to
+// publish the status, err of the workflow is returned when the document already keep the status
+func (svc DocumentApprovalService[T]) republish(ctx context.Context, doc T, err error) (string, error) {
+	approvalDoc := svc.describe(doc)
+	approval, infoErr := svc.workflow.Info(ctx, approvalDoc.ShopID, svc.docType, approvalDoc.DocGuid)
+	if infoErr != nil || approval.Status == doc.GetApprovalStatus() {
+		return "", err
+	}
+
+	return approval.Status, svc.saveApprovalStatus(ctx, doc, approval.Status)
+}
+
+// SubmitInBatch submit documents of bulk import and return approval status of each document, approval status sent
+// with the documents is never kept, approval of documents which are submitted before the failed one is removed
+func (svc DocumentApprovalService[T]) SubmitInBatch(ctx context.Context, docs []T, username string) ([]string, error) {
+	approvalStatuses := make([]string, len(docs))
+	for idx, doc := range docs {
+		approvalStatus, err := svc.workflow.Submit(ctx, svc.describe(doc), username, doc.GetIsDraft())
+		if err != nil {
+			svc.RemoveInBatch(ctx, docs[:idx])
+			return nil, err
+		}
+		approvalStatuses[idx] = approvalStatus
+	}
+	return approvalStatuses, nil
+}
+
+// RemoveInBatch remove approval of documents which cannot be saved
+func (svc DocumentApprovalService[T]) RemoveInBatch(ctx context.Context, docs []T) {
+	for _, doc := range docs {
+		approvalDoc := svc.describe(doc)
+		svc.workflow.Remove(ctx, approvalDoc.ShopID, approvalDoc.DocType, approvalDoc.DocGuid)
+	}
+}
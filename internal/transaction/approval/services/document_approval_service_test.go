@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/approval/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testApprovalDoc struct {
+	models.DocApproval
+	Guid   string
+	Amount float64
+}
+
+func newTestDocumentApproval(workflow services.IApprovalWorkflow, docs map[string]testApprovalDoc) services.DocumentApprovalService[testApprovalDoc] {
+	return services.NewDocumentApprovalService(
+		workflow,
+		models.DocTypePurchaseOrder,
+		func(ctx context.Context, shopID string, guid string) (testApprovalDoc, error) {
+			return docs[guid], nil
+		},
+		func(doc testApprovalDoc) models.ApprovalDocument {
+			return models.ApprovalDocument{ShopID: "shop1", DocType: models.DocTypePurchaseOrder, DocGuid: doc.Guid, Amount: doc.Amount}
+		},
+		func(ctx context.Context, doc testApprovalDoc, approvalStatus string) error {
+			doc.ApprovalStatus = approvalStatus
+			docs[doc.Guid] = doc
+			return nil
+		},
+	)
+}
+
+func TestDocumentApprovalSaveChangedStatus(t *testing.T) {
+	workflow, _ := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 10000, "manager", 1))
+	docs := map[string]testApprovalDoc{
+		"po1": {Guid: "po1", Amount: 20000, DocApproval: models.DocApproval{ApprovalStatus: models.StatusDraft}},
+	}
+	documentApproval := newTestDocumentApproval(workflow, docs)
+
+	status, err := documentApproval.Submit(context.Background(), "shop1", "po1", "clerk")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+	assert.Equal(t, models.StatusPending, docs["po1"].ApprovalStatus)
+
+	status, err = documentApproval.Approve(context.Background(), "shop1", "po1", "manager1", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+	assert.Equal(t, models.StatusApproved, docs["po1"].ApprovalStatus)
+
+	_, err = documentApproval.Submit(context.Background(), "shop1", "po2", "clerk")
+	assert.ErrorIs(t, err, services.ErrDocumentNotFound)
+}
+
+func TestDocumentApprovalRepublishStatus(t *testing.T) {
+	workflow, _ := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 10000, "manager", 1))
+	docs := map[string]testApprovalDoc{
+		"po1": {Guid: "po1", Amount: 20000, DocApproval: models.DocApproval{ApprovalStatus: models.StatusDraft}},
+	}
+
+	publishErr := errors.New("publish failed")
+	documentApproval := services.NewDocumentApprovalService(
+		workflow,
+		models.DocTypePurchaseOrder,
+		func(ctx context.Context, shopID string, guid string) (testApprovalDoc, error) {
+			return docs[guid], nil
+		},
+		func(doc testApprovalDoc) models.ApprovalDocument {
+			return models.ApprovalDocument{ShopID: "shop1", DocType: models.DocTypePurchaseOrder, DocGuid: doc.Guid, Amount: doc.Amount}
+		},
+		func(ctx context.Context, doc testApprovalDoc, approvalStatus string) error {
+			if publishErr != nil {
+				return publishErr
+			}
+			doc.ApprovalStatus = approvalStatus
+			docs[doc.Guid] = doc
+			return nil
+		},
+	)
+
+	// workflow is submitted but status of the document is not saved
+	_, err := documentApproval.Submit(context.Background(), "shop1", "po1", "clerk")
+	assert.ErrorIs(t, err, publishErr)
+	assert.Equal(t, models.StatusDraft, docs["po1"].ApprovalStatus)
+
+	publishErr = nil
+	status, err := documentApproval.Submit(context.Background(), "shop1", "po1", "clerk")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, status)
+	assert.Equal(t, models.StatusPending, docs["po1"].ApprovalStatus)
+
+	_, err = documentApproval.Submit(context.Background(), "shop1", "po1", "clerk")
+	assert.ErrorIs(t, err, services.ErrApprovalNotSubmittable)
+
+	// workflow is approved but status of the document is not saved
+	publishErr = errors.New("publish failed")
+	_, err = documentApproval.Approve(context.Background(), "shop1", "po1", "manager1", "")
+	assert.ErrorIs(t, err, publishErr)
+	assert.Equal(t, models.StatusPending, docs["po1"].ApprovalStatus)
+
+	publishErr = nil
+	status, err = documentApproval.Approve(context.Background(), "shop1", "po1", "manager1", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, status)
+	assert.Equal(t, models.StatusApproved, docs["po1"].ApprovalStatus)
+
+	_, err = documentApproval.Approve(context.Background(), "shop1", "po1", "manager2", "")
+	assert.ErrorIs(t, err, services.ErrApprovalNotPending)
+}
+
+func TestDocumentApprovalSubmitInBatchIgnoreClientStatus(t *testing.T) {
+	workflow, docRepo := newTestWorkflow(newApprovalRule("rule1", models.DocTypePurchaseOrder, 10000, "manager", 1))
+	documentApproval := newTestDocumentApproval(workflow, map[string]testApprovalDoc{})
+
+	approvedByClient := models.DocApproval{ApprovalStatus: models.StatusApproved}
+	docs := []testApprovalDoc{
+		{Guid: "po1", Amount: 20000, DocApproval: approvedByClient},
+		{Guid: "po2", Amount: 500, DocApproval: approvedByClient},
+		{Guid: "po3", Amount: 500, DocApproval: models.DocApproval{IsDraft: true}},
+	}
+
+	statuses, err := documentApproval.SubmitInBatch(context.Background(), docs, "clerk")
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.StatusPending, models.StatusApproved, models.StatusDraft}, statuses)
+	assert.Len(t, docRepo.approvals, 3)
+
+	documentApproval.RemoveInBatch(context.Background(), docs)
+	assert.Empty(t, docRepo.approvals)
+}
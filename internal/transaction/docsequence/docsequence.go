@@ -0,0 +1,52 @@
+package docsequence
+
+import (
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/docsequence/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence/services"
+	paidModels "smlaicloudplatform/internal/transaction/paid/models"
+	payModels "smlaicloudplatform/internal/transaction/pay/models"
+	purchaseModels "smlaicloudplatform/internal/transaction/purchase/models"
+	purchaseorderModels "smlaicloudplatform/internal/transaction/purchaseorder/models"
+	purchasereturnModels "smlaicloudplatform/internal/transaction/purchasereturn/models"
+	receivableotherModels "smlaicloudplatform/internal/transaction/receivableother/models"
+	saleinvoiceModels "smlaicloudplatform/internal/transaction/saleinvoice/models"
+	saleinvoicereturnModels "smlaicloudplatform/internal/transaction/saleinvoicereturn/models"
+	saleorderModels "smlaicloudplatform/internal/transaction/saleorder/models"
+	salequotationModels "smlaicloudplatform/internal/transaction/salequotation/models"
+	stockadjustmentModels "smlaicloudplatform/internal/transaction/stockadjustment/models"
+	stockbalanceModels "smlaicloudplatform/internal/transaction/stockbalance/models"
+	stockpickupproductModels "smlaicloudplatform/internal/transaction/stockpickupproduct/models"
+	stockreceiveproductModels "smlaicloudplatform/internal/transaction/stockreceiveproduct/models"
+	stockreturnproductModels "smlaicloudplatform/internal/transaction/stockreturnproduct/models"
+	stocktransferModels "smlaicloudplatform/internal/transaction/stocktransfer/models"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// docModels is collection of documents of every doc code which is given by the sequencer,
+// last doc no of the collection start a new sequence and used doc no of it are skipped
+var docModels = map[string]interface{}{
+	"SI":  &saleinvoiceModels.SaleInvoiceDoc{},
+	"ST":  &saleinvoicereturnModels.SaleInvoiceReturnDoc{},
+	"QT":  &salequotationModels.SaleQuotationDoc{},
+	"SO":  &saleorderModels.SaleOrderDoc{},
+	"PO":  &purchaseorderModels.PurchaseOrderDoc{},
+	"PU":  &purchaseModels.PurchaseDoc{},
+	"PT":  &purchasereturnModels.PurchaseReturnDoc{},
+	"AJ":  &stockadjustmentModels.StockAdjustmentDoc{},
+	"IB":  &stockbalanceModels.StockBalanceDoc{},
+	"IF":  &stockreceiveproductModels.StockReceiveProductDoc{},
+	"IM":  &stockpickupproductModels.StockPickupProductDoc{},
+	"IR":  &stockreturnproductModels.StockReturnProductDoc{},
+	"TF":  &stocktransferModels.StockTransferDoc{},
+	"EE":  &paidModels.PaidDoc{},
+	"DE":  &payModels.PayDoc{},
+	"AOB": &receivableotherModels.ReceivableOtherDoc{},
+}
+
+// InitDocNoSequencer return doc no sequencer of transaction documents
+func InitDocNoSequencer(ms *microservice.Microservice, cfg config.IConfig) services.IDocNoSequencer {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewDocNoSequencer(repositories.NewDocSequenceRepository(pst), docModels, cfg.DocSequenceConfig(), ms.Logger, ms.TimeNow)
+}
@@ -0,0 +1,177 @@
+package docsequence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"smlaicloudplatform/internal/transaction/docsequence/services"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+type IDocSequenceHttp interface{}
+
+type DocSequenceHttp struct {
+	ms             *microservice.Microservice
+	cfg            config.IConfig
+	svc            services.IDocNoSequencer
+	contextTimeout time.Duration
+}
+
+func NewDocSequenceHttp(ms *microservice.Microservice, cfg config.IConfig) DocSequenceHttp {
+	return DocSequenceHttp{
+		ms:             ms,
+		cfg:            cfg,
+		svc:            InitDocNoSequencer(ms, cfg),
+		contextTimeout: time.Duration(15) * time.Second,
+	}
+}
+
+func (h DocSequenceHttp) RegisterHttp() {
+	h.ms.GET("/transaction/document-sequence/preview", h.Preview)
+	h.ms.POST("/transaction/document-sequence/reserve", h.Reserve)
+	h.ms.POST("/transaction/document-sequence/release", h.Release)
+}
+
+// parseDocDate return date of yyyy-mm-dd, today when it is empty
+func (h DocSequenceHttp) parseDocDate(docDate string) (time.Time, error) {
+	if docDate == "" {
+		return h.ms.TimeNow(), nil
+	}
+	return time.Parse("2006-01-02", docDate)
+}
+
+// Preview Doc No godoc
+// @Description preview the next doc no of the doc code, the doc no is not reserved
+// @Tags		DocumentSequence
+// @Param		doccode		query	string		true  "Doc Code"
+// @Param		docdate		query	string		false  "Doc Date (YYYY-MM-DD)"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/document-sequence/preview [get]
+func (h DocSequenceHttp) Preview(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	docCode := ctx.QueryParam("doccode")
+	if docCode == "" {
+		ctx.ResponseError(http.StatusBadRequest, "doccode is required")
+		return nil
+	}
+
+	docDate, err := h.parseDocDate(ctx.QueryParam("docdate"))
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, "docdate is invalid")
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.contextTimeout)
+	defer cancel()
+
+	doc, err := h.svc.Preview(reqCtx, shopID, docCode, docDate)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Reserve Doc No godoc
+// @Description reserve the next doc no of the doc code, document which is created with the doc no before it expire use it
+// @Tags		DocumentSequence
+// @Param		DocNoRequest  body      models.DocNoRequest  true  "Doc Code and Doc Date (YYYY-MM-DD)"
+// @Accept 		json
+// @Success		201	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/document-sequence/reserve [post]
+func (h DocSequenceHttp) Reserve(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	docReq := &models.DocNoRequest{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	docDate, err := h.parseDocDate(docReq.DocDate)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, "docdate is invalid")
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.contextTimeout)
+	defer cancel()
+
+	doc, err := h.svc.Reserve(reqCtx, userInfo.ShopID, docReq.DocCode, docDate, userInfo.Username, "")
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Release Doc No godoc
+// @Description release doc no which is reserved and not used, doc no of gap-free format is given to the next document
+// @Tags		DocumentSequence
+// @Param		DocNoReleaseRequest  body      models.DocNoReleaseRequest  true  "Doc Code and Doc No"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccess
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/document-sequence/release [post]
+func (h DocSequenceHttp) Release(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	docReq := &models.DocNoReleaseRequest{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.contextTimeout)
+	defer cancel()
+
+	err = h.svc.ReleaseReserved(reqCtx, shopID, docReq.DocCode, docReq.DocNo)
+	if errors.Is(err, services.ErrReservationNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ResponseSuccess{
+		Success: true,
+	})
+	return nil
+}
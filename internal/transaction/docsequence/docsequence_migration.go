@@ -0,0 +1,55 @@
+package docsequence
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create unique index of sequence of the period and of reserved doc no,
+// reservations which are used are removed by ttl index, released ones are kept to be given again
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	sequenceCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.DocSequenceDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = sequenceCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "doccode", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetName("docsequence_shopid_doccode_period").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	reservationCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.DocNoReservation{})
+	if err != nil {
+		return err
+	}
+
+	_, err = reservationCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "doccode", Value: 1}, {Key: "docno", Value: 1}},
+			Options: options.Index().SetName("docnoreservation_shopid_doccode_docno").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "doccode", Value: 1}, {Key: "period", Value: 1}, {Key: "status", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetName("docnoreservation_shopid_doccode_period_status_number"),
+		},
+		{
+			Keys: bson.D{{Key: "updatedat", Value: 1}},
+			Options: options.Index().
+				SetName("docnoreservation_used_updatedat_ttl").
+				SetExpireAfterSeconds(int32(cfg.DocSequenceConfig().UsedReservationRetention().Seconds())).
+				SetPartialFilterExpression(bson.M{"status": models.ReservationStatusUsed}),
+		},
+	})
+	return err
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	docSequenceCollectionName      = "documentSequences"
+	docNoReservationCollectionName = "documentSequenceReservations"
+)
+
+const (
+	ReservationStatusReserved = "reserved"
+	ReservationStatusUsed     = "used"
+	ReservationStatusReleased = "released"
+)
+
+const (
+	YearTypeChristian int8 = 0
+	YearTypeBuddhist  int8 = 1
+)
+
+// DocSequenceDoc is the last number of doc code of the shop in the period, period is empty when the format has no date
+type DocSequenceDoc struct {
+	ID         primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ShopID     string             `json:"shopid" bson:"shopid"`
+	DocCode    string             `json:"doccode" bson:"doccode"`
+	Period     string             `json:"period" bson:"period"`
+	LastNumber int                `json:"lastnumber" bson:"lastnumber"`
+	UpdatedAt  time.Time          `json:"updatedat" bson:"updatedat"`
+}
+
+func (DocSequenceDoc) CollectionName() string {
+	return docSequenceCollectionName
+}
+
+// DocNoReservation is number which is given to a document before it is saved, it is used when the document is saved
+// and released when it is not, released and expired numbers of gap-free format are given again
+type DocNoReservation struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ShopID     string             `json:"shopid" bson:"shopid"`
+	DocCode    string             `json:"doccode" bson:"doccode"`
+	Period     string             `json:"period" bson:"period"`
+	Number     int                `json:"number" bson:"number"`
+	DocNo      string             `json:"docno" bson:"docno"`
+	IsGapFree  bool               `json:"isgapfree" bson:"isgapfree"`
+	Status     string             `json:"status" bson:"status"`
+	Token      string             `json:"-" bson:"token"`
+	ReservedBy string             `json:"reservedby" bson:"reservedby"`
+	ExpiresAt  time.Time          `json:"expiresat" bson:"expiresat"`
+	CreatedAt  time.Time          `json:"createdat" bson:"createdat"`
+	UpdatedAt  time.Time          `json:"updatedat" bson:"updatedat"`
+}
+
+func (DocNoReservation) CollectionName() string {
+	return docNoReservationCollectionName
+}
+
+// DocNoFormat is format of doc no of the doc code, DocFormat has tokens
+// "@" doc code, "YYYY" "YY" "MM" "DD" date of the document and "#" digit of running number.
+// when DocFormat is empty doc no is doc code, date by DateFormate and running number of DocNumber digits
+type DocNoFormat struct {
+	DocCode     string `json:"doccode"`
+	DocFormat   string `json:"docformat"`
+	DateFormate string `json:"dateformate"`
+	DocNumber   int    `json:"docnumber"`
+	YearType    int8   `json:"yeartype"`
+	IsGapFree   bool   `json:"isgapfree"`
+}
+
+type DocNoRequest struct {
+	DocCode string `json:"doccode" validate:"required"`
+	DocDate string `json:"docdate"`
+}
+
+type DocNoPreview struct {
+	DocCode   string `json:"doccode"`
+	DocNo     string `json:"docno"`
+	IsGapFree bool   `json:"isgapfree"`
+}
+
+type DocNoReleaseRequest struct {
+	DocCode string `json:"doccode" validate:"required"`
+	DocNo   string `json:"docno" validate:"required"`
+}
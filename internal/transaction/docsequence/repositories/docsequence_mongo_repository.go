@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	documentformateModels "smlaicloudplatform/internal/transaction/documentformate/models"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IDocSequenceRepository interface {
+	FindFormat(ctx context.Context, shopID string, docCode string) (documentformateModels.DocumentFormateDoc, error)
+	FindSequence(ctx context.Context, shopID string, docCode string, period string) (models.DocSequenceDoc, error)
+	// SeedSequence raise last number of the period to the number, it never lower the last number
+	SeedSequence(ctx context.Context, shopID string, docCode string, period string, number int, now time.Time) error
+	// NextNumber increase last number of the period by one and return it
+	NextNumber(ctx context.Context, shopID string, docCode string, period string, now time.Time) (int, error)
+
+	CreateReservation(ctx context.Context, doc models.DocNoReservation) (primitive.ObjectID, error)
+	// FindReusable return released or expired reservation of the period which has the lowest number
+	FindReusable(ctx context.Context, shopID string, docCode string, period string, now time.Time) (models.DocNoReservation, bool, error)
+	// ClaimReusable reserve the lowest released or expired reservation of the period again
+	ClaimReusable(ctx context.Context, shopID string, docCode string, period string, token string, reservedBy string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error)
+	// ClaimReserved take over reservation of the doc no which is reserved and not expired
+	ClaimReserved(ctx context.Context, shopID string, docCode string, docNo string, token string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error)
+	// UpdateStatus change status of reservation which is still held by the token
+	UpdateStatus(ctx context.Context, shopID string, id primitive.ObjectID, token string, status string, now time.Time) (bool, error)
+
+	// FindLastDocNo return the greatest doc no of the shop in collection of the model which start with the prefix
+	FindLastDocNo(ctx context.Context, model interface{}, shopID string, prefixDocNo string) (string, error)
+	IsDocNoUsed(ctx context.Context, model interface{}, shopID string, docNo string) (bool, error)
+}
+
+type DocSequenceRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewDocSequenceRepository(pst microservice.IPersisterMongo) *DocSequenceRepository {
+	return &DocSequenceRepository{
+		pst: pst,
+	}
+}
+
+func (repo DocSequenceRepository) FindFormat(ctx context.Context, shopID string, docCode string) (documentformateModels.DocumentFormateDoc, error) {
+	doc := documentformateModels.DocumentFormateDoc{}
+	err := repo.pst.FindOne(ctx, &documentformateModels.DocumentFormateDoc{}, bson.M{
+		"shopid":    shopID,
+		"doccode":   docCode,
+		"deletedat": bson.M{"$exists": false},
+	}, &doc)
+
+	if err != nil {
+		return documentformateModels.DocumentFormateDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo DocSequenceRepository) FindSequence(ctx context.Context, shopID string, docCode string, period string) (models.DocSequenceDoc, error) {
+	doc := models.DocSequenceDoc{}
+	err := repo.pst.FindOne(ctx, &models.DocSequenceDoc{}, bson.M{
+		"shopid":  shopID,
+		"doccode": docCode,
+		"period":  period,
+	}, &doc)
+
+	if err != nil {
+		return models.DocSequenceDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo DocSequenceRepository) SeedSequence(ctx context.Context, shopID string, docCode string, period string, number int, now time.Time) error {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.DocSequenceDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateOne(
+		ctx,
+		bson.M{"shopid": shopID, "doccode": docCode, "period": period},
+		bson.M{
+			"$max": bson.M{"lastnumber": number},
+			"$set": bson.M{"updatedat": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+
+	// another replica create the sequence at the same time, $max of it keep the greater number
+	if mongo.IsDuplicateKeyError(err) {
+		return repo.SeedSequence(ctx, shopID, docCode, period, number, now)
+	}
+
+	return err
+}
+
+func (repo DocSequenceRepository) NextNumber(ctx context.Context, shopID string, docCode string, period string, now time.Time) (int, error) {
+	next := func() (models.DocSequenceDoc, error) {
+		doc := models.DocSequenceDoc{}
+		err := repo.pst.FindOneAndUpdate(
+			ctx,
+			&models.DocSequenceDoc{},
+			bson.M{"shopid": shopID, "doccode": docCode, "period": period},
+			bson.M{
+				"$inc": bson.M{"lastnumber": 1},
+				"$set": bson.M{"updatedat": now},
+			},
+			&doc,
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		)
+		return doc, err
+	}
+
+	doc, err := next()
+
+	// upsert of two replicas race on unique index, the one which lose increase the created sequence
+	if mongo.IsDuplicateKeyError(err) {
+		doc, err = next()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return doc.LastNumber, nil
+}
+
+func (repo DocSequenceRepository) CreateReservation(ctx context.Context, doc models.DocNoReservation) (primitive.ObjectID, error) {
+	return repo.pst.Create(ctx, &models.DocNoReservation{}, doc)
+}
+
+func reusableFilter(shopID string, docCode string, period string, now time.Time) bson.M {
+	return bson.M{
+		"shopid":    shopID,
+		"doccode":   docCode,
+		"period":    period,
+		"isgapfree": true,
+		"$or": bson.A{
+			bson.M{"status": models.ReservationStatusReleased},
+			bson.M{"status": models.ReservationStatusReserved, "expiresat": bson.M{"$lte": now}},
+		},
+	}
+}
+
+func (repo DocSequenceRepository) FindReusable(ctx context.Context, shopID string, docCode string, period string, now time.Time) (models.DocNoReservation, bool, error) {
+	doc := models.DocNoReservation{}
+	err := repo.pst.FindOne(ctx, &models.DocNoReservation{}, reusableFilter(shopID, docCode, period, now), &doc,
+		options.FindOne().SetSort(bson.D{{Key: "number", Value: 1}}))
+
+	if err != nil {
+		return models.DocNoReservation{}, false, err
+	}
+
+	return doc, !doc.ID.IsZero(), nil
+}
+
+func (repo DocSequenceRepository) ClaimReusable(ctx context.Context, shopID string, docCode string, period string, token string, reservedBy string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error) {
+	return repo.claim(ctx, reusableFilter(shopID, docCode, period, now), bson.M{
+		"status":     models.ReservationStatusReserved,
+		"token":      token,
+		"reservedby": reservedBy,
+		"expiresat":  expiresAt,
+		"updatedat":  now,
+	})
+}
+
+func (repo DocSequenceRepository) ClaimReserved(ctx context.Context, shopID string, docCode string, docNo string, token string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error) {
+	return repo.claim(ctx, bson.M{
+		"shopid":    shopID,
+		"doccode":   docCode,
+		"docno":     docNo,
+		"status":    models.ReservationStatusReserved,
+		"expiresat": bson.M{"$gt": now},
+	}, bson.M{
+		"token":     token,
+		"expiresat": expiresAt,
+		"updatedat": now,
+	})
+}
+
+func (repo DocSequenceRepository) claim(ctx context.Context, filter bson.M, set bson.M) (models.DocNoReservation, bool, error) {
+	claimed := models.DocNoReservation{}
+	err := repo.pst.FindOneAndUpdate(
+		ctx,
+		&models.DocNoReservation{},
+		filter,
+		bson.M{"$set": set},
+		&claimed,
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "number", Value: 1}}).
+			SetReturnDocument(options.After),
+	)
+
+	if err != nil {
+		return models.DocNoReservation{}, false, err
+	}
+
+	if claimed.ID.IsZero() {
+		return models.DocNoReservation{}, false, nil
+	}
+
+	return claimed, true, nil
+}
+
+func (repo DocSequenceRepository) UpdateStatus(ctx context.Context, shopID string, id primitive.ObjectID, token string, status string, now time.Time) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.DocNoReservation{})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.UpdateOne(ctx, bson.M{
+		"shopid": shopID,
+		"_id":    id,
+		"token":  token,
+		"status": models.ReservationStatusReserved,
+	}, bson.M{
+		"$set": bson.M{"status": status, "updatedat": now},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo DocSequenceRepository) FindLastDocNo(ctx context.Context, model interface{}, shopID string, prefixDocNo string) (string, error) {
+	doc := struct {
+		DocNo string `bson:"docno"`
+	}{}
+
+	// deleted documents are included, their doc no cannot be given again
+	err := repo.pst.FindOne(ctx, model, bson.M{
+		"shopid": shopID,
+		"docno":  bson.M{"$regex": "^" + regexp.QuoteMeta(prefixDocNo)},
+	}, &doc, options.FindOne().SetSort(bson.M{"docno": -1}))
+
+	if err != nil {
+		return "", err
+	}
+
+	return doc.DocNo, nil
+}
+
+func (repo DocSequenceRepository) IsDocNoUsed(ctx context.Context, model interface{}, shopID string, docNo string) (bool, error) {
+	count, err := repo.pst.Count(ctx, model, bson.M{
+		"shopid": shopID,
+		"docno":  docNo,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
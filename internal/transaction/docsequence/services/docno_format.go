@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"strings"
+	"time"
+)
+
+const defaultDocNumberDigits = 5
+
+const (
+	tokenLiteral = iota
+	tokenDocCode
+	tokenYear4
+	tokenYear2
+	tokenMonth
+	tokenDay
+	tokenNumber
+)
+
+type docNoToken struct {
+	kind  int
+	text  string
+	width int
+}
+
+// docNoPattern return DocFormat of the format, or doc code, DateFormate and running number when DocFormat is empty
+func docNoPattern(format models.DocNoFormat) string {
+	if strings.TrimSpace(format.DocFormat) != "" {
+		return format.DocFormat
+	}
+
+	digits := format.DocNumber
+	if digits < 1 {
+		digits = defaultDocNumberDigits
+	}
+
+	return "@" + strings.ToUpper(format.DateFormate) + strings.Repeat("#", digits)
+}
+
+func parseDocNoPattern(pattern string) []docNoToken {
+	tokens := []docNoToken{}
+	literal := strings.Builder{}
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			tokens = append(tokens, docNoToken{kind: tokenLiteral, text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	hasNumber := false
+	for i := 0; i < len(pattern); {
+		switch {
+		case pattern[i] == '@':
+			flushLiteral()
+			tokens = append(tokens, docNoToken{kind: tokenDocCode})
+			i++
+		case pattern[i] == '#':
+			flushLiteral()
+			width := 0
+			for i < len(pattern) && pattern[i] == '#' {
+				width++
+				i++
+			}
+			tokens = append(tokens, docNoToken{kind: tokenNumber, width: width})
+			hasNumber = true
+		case strings.HasPrefix(pattern[i:], "YYYY"):
+			flushLiteral()
+			tokens = append(tokens, docNoToken{kind: tokenYear4})
+			i += 4
+		case strings.HasPrefix(pattern[i:], "YY"):
+			flushLiteral()
+			tokens = append(tokens, docNoToken{kind: tokenYear2})
+			i += 2
+		case strings.HasPrefix(pattern[i:], "MM"):
+			flushLiteral()
+			tokens = append(tokens, docNoToken{kind: tokenMonth})
+			i += 2
+		case strings.HasPrefix(pattern[i:], "DD"):
+			flushLiteral()
+			tokens = append(tokens, docNoToken{kind: tokenDay})
+			i += 2
+		default:
+			literal.WriteByte(pattern[i])
+			i++
+		}
+	}
+	flushLiteral()
+
+	// format without running number has the number at the end
+	if !hasNumber {
+		tokens = append(tokens, docNoToken{kind: tokenNumber, width: defaultDocNumberDigits})
+	}
+
+	return tokens
+}
+
+func formatYear(docDate time.Time, yearType int8) int {
+	if yearType == models.YearTypeBuddhist {
+		return docDate.Year() + 543
+	}
+	return docDate.Year()
+}
+
+func renderToken(token docNoToken, format models.DocNoFormat, docDate time.Time, number int) string {
+	switch token.kind {
+	case tokenDocCode:
+		return format.DocCode
+	case tokenYear4:
+		return fmt.Sprintf("%04d", formatYear(docDate, format.YearType))
+	case tokenYear2:
+		return fmt.Sprintf("%02d", formatYear(docDate, format.YearType)%100)
+	case tokenMonth:
+		return fmt.Sprintf("%02d", int(docDate.Month()))
+	case tokenDay:
+		return fmt.Sprintf("%02d", docDate.Day())
+	case tokenNumber:
+		return fmt.Sprintf("%0*d", token.width, number)
+	}
+	return token.text
+}
+
+// FormatDocNo return doc no of the running number in the format
+func FormatDocNo(format models.DocNoFormat, docDate time.Time, number int) string {
+	result := strings.Builder{}
+	for _, token := range parseDocNoPattern(docNoPattern(format)) {
+		result.WriteString(renderToken(token, format, docDate, number))
+	}
+	return result.String()
+}
+
+// DocNoPrefix return text before the running number, ok is false when the running number is not at the end
+// so last number cannot be read from existing doc no
+func DocNoPrefix(format models.DocNoFormat, docDate time.Time) (string, bool) {
+	tokens := parseDocNoPattern(docNoPattern(format))
+	if tokens[len(tokens)-1].kind != tokenNumber {
+		return "", false
+	}
+
+	prefix := strings.Builder{}
+	for _, token := range tokens[:len(tokens)-1] {
+		if token.kind == tokenNumber {
+			return "", false
+		}
+		prefix.WriteString(renderToken(token, format, docDate, 0))
+	}
+	return prefix.String(), true
+}
+
+// PeriodKey return period of the running number, the number start from one again in a new day, month or year
+// by the smallest date part in the format and never when the format has no date
+func PeriodKey(format models.DocNoFormat, docDate time.Time) string {
+	hasYear, hasMonth, hasDay := false, false, false
+	for _, token := range parseDocNoPattern(docNoPattern(format)) {
+		switch token.kind {
+		case tokenYear4, tokenYear2:
+			hasYear = true
+		case tokenMonth:
+			hasMonth = true
+		case tokenDay:
+			hasDay = true
+		}
+	}
+
+	switch {
+	case hasDay:
+		return docDate.Format("20060102")
+	case hasMonth:
+		return docDate.Format("200601")
+	case hasYear:
+		return docDate.Format("2006")
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"smlaicloudplatform/internal/transaction/docsequence/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDocNoDefaultFormat(t *testing.T) {
+	docDate := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	format := models.DocNoFormat{DocCode: "SI", DateFormate: "YYYYMMDD", DocNumber: 5}
+
+	assert.Equal(t, "SI2024030700012", services.FormatDocNo(format, docDate, 12))
+	assert.Equal(t, "20240307", services.PeriodKey(format, docDate))
+
+	prefix, ok := services.DocNoPrefix(format, docDate)
+	assert.True(t, ok)
+	assert.Equal(t, "SI20240307", prefix)
+}
+
+func TestFormatDocNoDocFormat(t *testing.T) {
+	docDate := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	format := models.DocNoFormat{DocCode: "SI", DocFormat: "@-YY-MM-####", YearType: models.YearTypeBuddhist}
+
+	assert.Equal(t, "SI-67-03-0042", services.FormatDocNo(format, docDate, 42))
+	assert.Equal(t, "202403", services.PeriodKey(format, docDate))
+
+	format = models.DocNoFormat{DocCode: "TAX", DocFormat: "INV/YYYY/"}
+
+	assert.Equal(t, "INV/2024/00003", services.FormatDocNo(format, docDate, 3))
+	assert.Equal(t, "2024", services.PeriodKey(format, docDate))
+}
+
+func TestFormatDocNoWithoutDate(t *testing.T) {
+	docDate := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	format := models.DocNoFormat{DocCode: "PO", DocFormat: "@###"}
+
+	assert.Equal(t, "PO1234", services.FormatDocNo(format, docDate, 1234))
+	assert.Equal(t, "", services.PeriodKey(format, docDate))
+
+	format = models.DocNoFormat{DocCode: "PO", DocFormat: "###-YYYY"}
+
+	_, ok := services.DocNoPrefix(format, docDate)
+	assert.False(t, ok)
+}
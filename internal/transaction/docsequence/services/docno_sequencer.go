@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"smlaicloudplatform/internal/transaction/docsequence/repositories"
+	"smlaicloudplatform/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxReserveAttempts is number of doc no which are tried when the next doc no is already used by a document
+const maxReserveAttempts = 20
+
+var (
+	ErrDocNoNotAvailable   = errors.New("doc no is not available, the next doc no is already used")
+	ErrReservationNotFound = errors.New("reservation of the doc no is not found or it is expired")
+)
+
+// IDocNoSequencer give doc no of transaction documents, number is unique across replicas
+// and it start from one again in every period of the format
+type IDocNoSequencer interface {
+	Preview(ctx context.Context, shopID string, docCode string, docDate time.Time) (models.DocNoPreview, error)
+	// Reserve return reservation of the next doc no, reservedDocNo which is reserved before by the api is used when it is given
+	// and ErrReservationNotFound is returned when it is not reserved or its reservation is expired
+	Reserve(ctx context.Context, shopID string, docCode string, docDate time.Time, reservedBy string, reservedDocNo string) (models.DocNoReservation, error)
+	Confirm(ctx context.Context, reservation models.DocNoReservation) error
+	Release(ctx context.Context, reservation models.DocNoReservation) error
+	// Done confirm the reservation when the document is saved without error and release it otherwise
+	Done(ctx context.Context, reservation models.DocNoReservation, saveErr error) error
+	// SaveWithDocNo reserve doc no like Reserve and call save with it, the doc no is used when save return nil
+	// and given back otherwise. It return the doc no and error of save, failure of Done is logged
+	SaveWithDocNo(ctx context.Context, shopID string, docCode string, docDate time.Time, reservedBy string, reservedDocNo string, save func(docNo string) error) (string, error)
+	// ReleaseReserved release doc no which is reserved by the api and not used
+	ReleaseReserved(ctx context.Context, shopID string, docCode string, docNo string) error
+}
+
+type DocNoSequencer struct {
+	repo      repositories.IDocSequenceRepository
+	docModels map[string]interface{}
+	cfg       config.IDocSequenceConfig
+	logger    logger.ILogger
+	timeNow   func() time.Time
+}
+
+// NewDocNoSequencer create sequencer, docModels is model of collection of documents of the doc code which is used
+// to start the sequence from the last doc no and to skip doc no which is already used
+func NewDocNoSequencer(repo repositories.IDocSequenceRepository, docModels map[string]interface{}, cfg config.IDocSequenceConfig, logger logger.ILogger, timeNow func() time.Time) *DocNoSequencer {
+	return &DocNoSequencer{
+		repo:      repo,
+		docModels: docModels,
+		cfg:       cfg,
+		logger:    logger,
+		timeNow:   timeNow,
+	}
+}
+
+func (svc DocNoSequencer) findFormat(ctx context.Context, shopID string, docCode string) (models.DocNoFormat, error) {
+	doc, err := svc.repo.FindFormat(ctx, shopID, docCode)
+	if err != nil {
+		return models.DocNoFormat{}, err
+	}
+
+	// shop without document format of the doc code use doc code, date and five digits number
+	if doc.ID.IsZero() {
+		return models.DocNoFormat{
+			DocCode:     docCode,
+			DateFormate: "YYYYMMDD",
+			DocNumber:   defaultDocNumberDigits,
+		}, nil
+	}
+
+	return models.DocNoFormat{
+		DocCode:     docCode,
+		DocFormat:   doc.DocFormat,
+		DateFormate: doc.DateFormate,
+		DocNumber:   doc.DocNumber,
+		YearType:    doc.YearType,
+		IsGapFree:   doc.IsGapFree,
+	}, nil
+}
+
+// startSequence create sequence of the period from the last doc no of the documents when it does not exist
+func (svc DocNoSequencer) startSequence(ctx context.Context, shopID string, format models.DocNoFormat, period string, docDate time.Time) error {
+	sequence, err := svc.repo.FindSequence(ctx, shopID, format.DocCode, period)
+	if err != nil {
+		return err
+	}
+
+	if !sequence.ID.IsZero() {
+		return nil
+	}
+
+	lastNumber := 0
+	prefixDocNo, ok := DocNoPrefix(format, docDate)
+	docModel, hasModel := svc.docModels[format.DocCode]
+
+	if ok && hasModel {
+		lastDocNo, err := svc.repo.FindLastDocNo(ctx, docModel, shopID, prefixDocNo)
+		if err != nil {
+			return err
+		}
+
+		if number, err := strconv.Atoi(strings.TrimPrefix(lastDocNo, prefixDocNo)); err == nil && lastDocNo != "" {
+			lastNumber = number
+		}
+	}
+
+	return svc.repo.SeedSequence(ctx, shopID, format.DocCode, period, lastNumber, svc.timeNow())
+}
+
+func (svc DocNoSequencer) isDocNoUsed(ctx context.Context, shopID string, docCode string, docNo string) (bool, error) {
+	docModel, ok := svc.docModels[docCode]
+	if !ok {
+		return false, nil
+	}
+
+	return svc.repo.IsDocNoUsed(ctx, docModel, shopID, docNo)
+}
+
+// Preview return the doc no which the next reservation get, it is not reserved
+func (svc DocNoSequencer) Preview(ctx context.Context, shopID string, docCode string, docDate time.Time) (models.DocNoPreview, error) {
+	format, err := svc.findFormat(ctx, shopID, docCode)
+	if err != nil {
+		return models.DocNoPreview{}, err
+	}
+
+	period := PeriodKey(format, docDate)
+
+	if format.IsGapFree {
+		reusable, ok, err := svc.repo.FindReusable(ctx, shopID, docCode, period, svc.timeNow())
+		if err != nil {
+			return models.DocNoPreview{}, err
+		}
+
+		if ok {
+			return models.DocNoPreview{DocCode: docCode, DocNo: reusable.DocNo, IsGapFree: true}, nil
+		}
+	}
+
+	err = svc.startSequence(ctx, shopID, format, period, docDate)
+	if err != nil {
+		return models.DocNoPreview{}, err
+	}
+
+	sequence, err := svc.repo.FindSequence(ctx, shopID, docCode, period)
+	if err != nil {
+		return models.DocNoPreview{}, err
+	}
+
+	return models.DocNoPreview{
+		DocCode:   docCode,
+		DocNo:     FormatDocNo(format, docDate, sequence.LastNumber+1),
+		IsGapFree: format.IsGapFree,
+	}, nil
+}
+
+func (svc DocNoSequencer) Reserve(ctx context.Context, shopID string, docCode string, docDate time.Time, reservedBy string, reservedDocNo string) (models.DocNoReservation, error) {
+	now := svc.timeNow()
+	token := utils.NewGUID()
+	expiresAt := now.Add(svc.cfg.ReserveExpire())
+
+	if reservedDocNo != "" {
+		reservation, ok, err := svc.repo.ClaimReserved(ctx, shopID, docCode, reservedDocNo, token, now, expiresAt)
+		if err != nil {
+			return models.DocNoReservation{}, err
+		}
+
+		if !ok {
+			return models.DocNoReservation{}, ErrReservationNotFound
+		}
+
+		return reservation, nil
+	}
+
+	format, err := svc.findFormat(ctx, shopID, docCode)
+	if err != nil {
+		return models.DocNoReservation{}, err
+	}
+
+	period := PeriodKey(format, docDate)
+
+	for attempt := 0; attempt < maxReserveAttempts; attempt++ {
+		reservation, ok := models.DocNoReservation{}, false
+
+		if format.IsGapFree {
+			reservation, ok, err = svc.repo.ClaimReusable(ctx, shopID, docCode, period, token, reservedBy, now, expiresAt)
+			if err != nil {
+				return models.DocNoReservation{}, err
+			}
+		}
+
+		if !ok {
+			err = svc.startSequence(ctx, shopID, format, period, docDate)
+			if err != nil {
+				return models.DocNoReservation{}, err
+			}
+
+			number, err := svc.repo.NextNumber(ctx, shopID, docCode, period, now)
+			if err != nil {
+				return models.DocNoReservation{}, err
+			}
+
+			reservation = models.DocNoReservation{
+				ShopID:     shopID,
+				DocCode:    docCode,
+				Period:     period,
+				Number:     number,
+				DocNo:      FormatDocNo(format, docDate, number),
+				IsGapFree:  format.IsGapFree,
+				Status:     models.ReservationStatusReserved,
+				Token:      token,
+				ReservedBy: reservedBy,
+				ExpiresAt:  expiresAt,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+
+			reservation.ID, err = svc.repo.CreateReservation(ctx, reservation)
+
+			// doc no is reserved in another period when the format of the shop is changed
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+
+			if err != nil {
+				return models.DocNoReservation{}, err
+			}
+		}
+
+		used, err := svc.isDocNoUsed(ctx, shopID, docCode, reservation.DocNo)
+		if err != nil {
+			svc.Release(ctx, reservation)
+			return models.DocNoReservation{}, err
+		}
+
+		// document is saved by a replica which stop before the reservation is confirmed
+		if used {
+			svc.Confirm(ctx, reservation)
+			continue
+		}
+
+		return reservation, nil
+	}
+
+	return models.DocNoReservation{}, ErrDocNoNotAvailable
+}
+
+func (svc DocNoSequencer) Confirm(ctx context.Context, reservation models.DocNoReservation) error {
+	_, err := svc.repo.UpdateStatus(ctx, reservation.ShopID, reservation.ID, reservation.Token, models.ReservationStatusUsed, svc.timeNow())
+	return err
+}
+
+// Release give back the reservation, number of gap-free format is given to the next reservation
+func (svc DocNoSequencer) Release(ctx context.Context, reservation models.DocNoReservation) error {
+	_, err := svc.repo.UpdateStatus(ctx, reservation.ShopID, reservation.ID, reservation.Token, models.ReservationStatusReleased, svc.timeNow())
+	return err
+}
+
+func (svc DocNoSequencer) Done(ctx context.Context, reservation models.DocNoReservation, saveErr error) error {
+	if saveErr != nil {
+		return svc.Release(ctx, reservation)
+	}
+	return svc.Confirm(ctx, reservation)
+}
+
+func (svc DocNoSequencer) SaveWithDocNo(ctx context.Context, shopID string, docCode string, docDate time.Time, reservedBy string, reservedDocNo string, save func(docNo string) error) (string, error) {
+	reservation, err := svc.Reserve(ctx, shopID, docCode, docDate, reservedBy, reservedDocNo)
+	if err != nil {
+		return "", err
+	}
+
+	err = save(reservation.DocNo)
+
+	// failure of Done does not fail the document, doc no which is not confirmed is skipped later because it is used
+	doneErr := svc.Done(ctx, reservation, err)
+	if doneErr != nil {
+		svc.logger.Errorf("Doc no %s of shop %s cannot be confirmed or released: %v", reservation.DocNo, shopID, doneErr)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return reservation.DocNo, nil
+}
+
+func (svc DocNoSequencer) ReleaseReserved(ctx context.Context, shopID string, docCode string, docNo string) error {
+	now := svc.timeNow()
+	reservation, ok, err := svc.repo.ClaimReserved(ctx, shopID, docCode, docNo, utils.NewGUID(), now, now.Add(svc.cfg.ReserveExpire()))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrReservationNotFound
+	}
+
+	return svc.Release(ctx, reservation)
+}
@@ -0,0 +1,286 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/transaction/docsequence/models"
+	"smlaicloudplatform/internal/transaction/docsequence/services"
+	documentformateModels "smlaicloudplatform/internal/transaction/documentformate/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var docModels = map[string]interface{}{"SI": struct{}{}, "TX": struct{}{}}
+
+func gapFreeFormat() documentformateModels.DocumentFormateDoc {
+	format := documentformateModels.DocumentFormateDoc{ID: primitive.NewObjectID()}
+	format.DocCode = "TX"
+	format.DocFormat = "@YYMM####"
+	format.YearType = models.YearTypeBuddhist
+	format.IsGapFree = true
+	return format
+}
+
+func TestDocNoSequencerPreview(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("FindFormat", "shop1", "SI").Return(documentformateModels.DocumentFormateDoc{}, nil)
+	repo.On("FindSequence", "shop1", "SI", "20240307").Return(models.DocSequenceDoc{ID: primitive.NewObjectID(), LastNumber: 7}, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+
+	preview, err := sequencer.Preview(context.Background(), "shop1", "SI", now)
+	require.NoError(t, err)
+	assert.Equal(t, "SI2024030700008", preview.DocNo)
+	assert.False(t, preview.IsGapFree)
+}
+
+func TestDocNoSequencerStartFromLastDocNo(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	reservationID := primitive.NewObjectID()
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("FindFormat", "shop1", "SI").Return(documentformateModels.DocumentFormateDoc{}, nil)
+	repo.On("FindSequence", "shop1", "SI", "20240307").Return(models.DocSequenceDoc{}, nil)
+	repo.On("FindLastDocNo", docModels["SI"], "shop1", "SI20240307").Return("SI2024030700007", nil)
+	repo.On("SeedSequence", "shop1", "SI", "20240307", 7).Return(nil)
+	repo.On("NextNumber", "shop1", "SI", "20240307").Return(8, nil)
+	repo.On("CreateReservation", mock.MatchedBy(func(doc models.DocNoReservation) bool {
+		return doc.DocNo == "SI2024030700008" && doc.Number == 8 && doc.ReservedBy == "cashier1"
+	})).Return(reservationID, nil)
+	repo.On("IsDocNoUsed", docModels["SI"], "shop1", "SI2024030700008").Return(false, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+
+	reservation, err := sequencer.Reserve(context.Background(), "shop1", "SI", now, "cashier1", "")
+	require.NoError(t, err)
+	assert.Equal(t, reservationID, reservation.ID)
+	assert.Equal(t, "SI2024030700008", reservation.DocNo)
+	assert.Equal(t, models.ReservationStatusReserved, reservation.Status)
+	assert.Equal(t, now.Add(30*time.Minute), reservation.ExpiresAt)
+	repo.AssertExpectations(t)
+}
+
+func TestDocNoSequencerSkipUsedDocNo(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	usedID, reservationID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("FindFormat", "shop1", "SI").Return(documentformateModels.DocumentFormateDoc{}, nil)
+	repo.On("FindSequence", "shop1", "SI", "20240307").Return(models.DocSequenceDoc{ID: primitive.NewObjectID(), LastNumber: 1}, nil)
+	repo.On("NextNumber", "shop1", "SI", "20240307").Return(2, nil).Once()
+	repo.On("NextNumber", "shop1", "SI", "20240307").Return(3, nil).Once()
+	repo.On("CreateReservation", mock.MatchedBy(func(doc models.DocNoReservation) bool { return doc.Number == 2 })).Return(usedID, nil)
+	repo.On("CreateReservation", mock.MatchedBy(func(doc models.DocNoReservation) bool { return doc.Number == 3 })).Return(reservationID, nil)
+	repo.On("IsDocNoUsed", docModels["SI"], "shop1", "SI2024030700002").Return(true, nil)
+	repo.On("IsDocNoUsed", docModels["SI"], "shop1", "SI2024030700003").Return(false, nil)
+
+	// doc no which is already used by a document is confirmed and skipped
+	repo.On("UpdateStatus", "shop1", usedID, mock.Anything, models.ReservationStatusUsed).Return(true, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+
+	reservation, err := sequencer.Reserve(context.Background(), "shop1", "SI", now, "cashier1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "SI2024030700003", reservation.DocNo)
+	repo.AssertExpectations(t)
+}
+
+func TestDocNoSequencerDocNoNotAvailable(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("FindFormat", "shop1", "SI").Return(documentformateModels.DocumentFormateDoc{}, nil)
+	repo.On("FindSequence", "shop1", "SI", "20240307").Return(models.DocSequenceDoc{ID: primitive.NewObjectID()}, nil)
+	repo.On("NextNumber", "shop1", "SI", "20240307").Return(1, nil)
+	repo.On("CreateReservation", mock.Anything).Return(primitive.NewObjectID(), nil)
+	repo.On("IsDocNoUsed", docModels["SI"], "shop1", mock.Anything).Return(true, nil)
+	repo.On("UpdateStatus", "shop1", mock.Anything, mock.Anything, models.ReservationStatusUsed).Return(true, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+
+	_, err := sequencer.Reserve(context.Background(), "shop1", "SI", now, "cashier1", "")
+	assert.ErrorIs(t, err, services.ErrDocNoNotAvailable)
+	repo.AssertNumberOfCalls(t, "NextNumber", 20)
+}
+
+func TestDocNoSequencerGapFree(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	released := models.DocNoReservation{ID: primitive.NewObjectID(), ShopID: "shop1", DocCode: "TX", Period: "202403", Number: 1, DocNo: "TX67030001", IsGapFree: true}
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("FindFormat", "shop1", "TX").Return(gapFreeFormat(), nil)
+	repo.On("FindReusable", "shop1", "TX", "202403").Return(released, true, nil)
+
+	// released number is given again before a new number
+	repo.On("ClaimReusable", "shop1", "TX", "202403", "cashier3").Return(released, true, nil).Once()
+	repo.On("ClaimReusable", "shop1", "TX", "202403", "cashier4").Return(models.DocNoReservation{}, false, nil).Once()
+	repo.On("FindSequence", "shop1", "TX", "202403").Return(models.DocSequenceDoc{ID: primitive.NewObjectID(), LastNumber: 2}, nil)
+	repo.On("NextNumber", "shop1", "TX", "202403").Return(3, nil)
+	repo.On("CreateReservation", mock.MatchedBy(func(doc models.DocNoReservation) bool {
+		return doc.DocNo == "TX67030003" && doc.IsGapFree
+	})).Return(primitive.NewObjectID(), nil)
+	repo.On("IsDocNoUsed", docModels["TX"], "shop1", mock.Anything).Return(false, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+	ctx := context.Background()
+
+	preview, err := sequencer.Preview(ctx, "shop1", "TX", now)
+	require.NoError(t, err)
+	assert.Equal(t, "TX67030001", preview.DocNo)
+
+	again, err := sequencer.Reserve(ctx, "shop1", "TX", now, "cashier3", "")
+	require.NoError(t, err)
+	assert.Equal(t, "TX67030001", again.DocNo)
+
+	next, err := sequencer.Reserve(ctx, "shop1", "TX", now, "cashier4", "")
+	require.NoError(t, err)
+	assert.Equal(t, "TX67030003", next.DocNo)
+
+	repo.AssertExpectations(t)
+}
+
+func TestDocNoSequencerDone(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	reservation := models.DocNoReservation{ID: primitive.NewObjectID(), ShopID: "shop1", DocCode: "SI", DocNo: "SI2024030700001", Token: "token1"}
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("UpdateStatus", "shop1", reservation.ID, "token1", models.ReservationStatusReleased).Return(true, nil).Once()
+	repo.On("UpdateStatus", "shop1", reservation.ID, "token1", models.ReservationStatusUsed).Return(true, nil).Once()
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+	ctx := context.Background()
+
+	require.NoError(t, sequencer.Done(ctx, reservation, assert.AnError))
+	require.NoError(t, sequencer.Done(ctx, reservation, nil))
+	repo.AssertExpectations(t)
+}
+
+func TestDocNoSequencerUseReservedDocNo(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	reserved := models.DocNoReservation{ID: primitive.NewObjectID(), ShopID: "shop1", DocCode: "SI", DocNo: "SI2024030700001", Status: models.ReservationStatusReserved}
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("ClaimReserved", "shop1", "SI", "SI2024030700001").Return(reserved, true, nil).Once()
+	repo.On("ClaimReserved", "shop1", "SI", "SI2024030700001").Return(models.DocNoReservation{}, false, nil)
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+	ctx := context.Background()
+
+	// document which is saved with doc no reserved by the api take the reservation
+	reservation, err := sequencer.Reserve(ctx, "shop1", "SI", now, "cashier1", "SI2024030700001")
+	require.NoError(t, err)
+	assert.Equal(t, reserved.ID, reservation.ID)
+	repo.AssertNotCalled(t, "NextNumber", mock.Anything, mock.Anything, mock.Anything)
+
+	// reservation which is expired or unknown does not give a different doc no
+	_, err = sequencer.Reserve(ctx, "shop1", "SI", now, "cashier1", "SI2024030700001")
+	assert.ErrorIs(t, err, services.ErrReservationNotFound)
+
+	assert.ErrorIs(t, sequencer.ReleaseReserved(ctx, "shop1", "SI", "SI2024030700001"), services.ErrReservationNotFound)
+}
+
+func TestDocNoSequencerSaveWithDocNo(t *testing.T) {
+	now := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	reserved := models.DocNoReservation{ID: primitive.NewObjectID(), ShopID: "shop1", DocCode: "SI", DocNo: "SI2024030700001", Token: "token1"}
+
+	repo := new(DocSequenceRepositoryMock)
+	repo.On("ClaimReserved", "shop1", "SI", "SI2024030700001").Return(reserved, true, nil)
+	repo.On("UpdateStatus", "shop1", reserved.ID, "token1", models.ReservationStatusUsed).Return(true, nil).Once()
+	repo.On("UpdateStatus", "shop1", reserved.ID, "token1", models.ReservationStatusReleased).Return(true, nil).Once()
+	repo.On("UpdateStatus", "shop1", reserved.ID, "token1", models.ReservationStatusUsed).Return(false, assert.AnError).Once()
+
+	sequencer := services.NewDocNoSequencer(repo, docModels, config.NewDocSequenceConfig(), logger.NewAppLogger(config.NewLoggerConfig()), func() time.Time { return now })
+	ctx := context.Background()
+
+	savedDocNo := ""
+	docNo, err := sequencer.SaveWithDocNo(ctx, "shop1", "SI", now, "cashier1", "SI2024030700001", func(docNo string) error {
+		savedDocNo = docNo
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SI2024030700001", docNo)
+	assert.Equal(t, "SI2024030700001", savedDocNo)
+
+	// doc no is given back when the document is not saved
+	saveErr := errors.New("save failed")
+	_, err = sequencer.SaveWithDocNo(ctx, "shop1", "SI", now, "cashier1", "SI2024030700001", func(docNo string) error {
+		return saveErr
+	})
+	assert.ErrorIs(t, err, saveErr)
+
+	// document which is saved is not failed by the confirm
+	docNo, err = sequencer.SaveWithDocNo(ctx, "shop1", "SI", now, "cashier1", "SI2024030700001", func(docNo string) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SI2024030700001", docNo)
+
+	repo.AssertExpectations(t)
+}
+
+type DocSequenceRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *DocSequenceRepositoryMock) FindFormat(ctx context.Context, shopID string, docCode string) (documentformateModels.DocumentFormateDoc, error) {
+	args := m.Called(shopID, docCode)
+	return args.Get(0).(documentformateModels.DocumentFormateDoc), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) FindSequence(ctx context.Context, shopID string, docCode string, period string) (models.DocSequenceDoc, error) {
+	args := m.Called(shopID, docCode, period)
+	return args.Get(0).(models.DocSequenceDoc), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) SeedSequence(ctx context.Context, shopID string, docCode string, period string, number int, now time.Time) error {
+	args := m.Called(shopID, docCode, period, number)
+	return args.Error(0)
+}
+
+func (m *DocSequenceRepositoryMock) NextNumber(ctx context.Context, shopID string, docCode string, period string, now time.Time) (int, error) {
+	args := m.Called(shopID, docCode, period)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) CreateReservation(ctx context.Context, doc models.DocNoReservation) (primitive.ObjectID, error) {
+	args := m.Called(doc)
+	return args.Get(0).(primitive.ObjectID), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) FindReusable(ctx context.Context, shopID string, docCode string, period string, now time.Time) (models.DocNoReservation, bool, error) {
+	args := m.Called(shopID, docCode, period)
+	return args.Get(0).(models.DocNoReservation), args.Bool(1), args.Error(2)
+}
+
+func (m *DocSequenceRepositoryMock) ClaimReusable(ctx context.Context, shopID string, docCode string, period string, token string, reservedBy string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error) {
+	args := m.Called(shopID, docCode, period, reservedBy)
+	return args.Get(0).(models.DocNoReservation), args.Bool(1), args.Error(2)
+}
+
+func (m *DocSequenceRepositoryMock) ClaimReserved(ctx context.Context, shopID string, docCode string, docNo string, token string, now time.Time, expiresAt time.Time) (models.DocNoReservation, bool, error) {
+	args := m.Called(shopID, docCode, docNo)
+	return args.Get(0).(models.DocNoReservation), args.Bool(1), args.Error(2)
+}
+
+func (m *DocSequenceRepositoryMock) UpdateStatus(ctx context.Context, shopID string, id primitive.ObjectID, token string, status string, now time.Time) (bool, error) {
+	args := m.Called(shopID, id, token, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) FindLastDocNo(ctx context.Context, model interface{}, shopID string, prefixDocNo string) (string, error) {
+	args := m.Called(model, shopID, prefixDocNo)
+	return args.String(0), args.Error(1)
+}
+
+func (m *DocSequenceRepositoryMock) IsDocNoUsed(ctx context.Context, model interface{}, shopID string, docNo string) (bool, error) {
+	args := m.Called(model, shopID, docNo)
+	return args.Bool(0), args.Error(1)
+}
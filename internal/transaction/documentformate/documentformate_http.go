@@ -80,7 +80,7 @@ func (h DocumentFormateHttp) CreateDocumentFormate(ctx microservice.IContext) er
 		return err
 	}
 
-	idx, err := h.svc.CreateDocumentFormate(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateDocumentFormate(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -125,7 +125,7 @@ func (h DocumentFormateHttp) UpdateDocumentFormate(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.svc.UpdateDocumentFormate(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateDocumentFormate(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -156,7 +156,7 @@ func (h DocumentFormateHttp) DeleteDocumentFormate(ctx microservice.IContext) er
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteDocumentFormate(shopID, id, authUsername)
+	err := h.svc.DeleteDocumentFormate(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -195,7 +195,7 @@ func (h DocumentFormateHttp) DeleteDocumentFormateByGUIDs(ctx microservice.ICont
 		return err
 	}
 
-	err = h.svc.DeleteDocumentFormateByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteDocumentFormateByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -225,7 +225,7 @@ func (h DocumentFormateHttp) InfoDocumentFormate(ctx microservice.IContext) erro
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get DocumentFormate %v", id)
-	doc, err := h.svc.InfoDocumentFormate(shopID, id)
+	doc, err := h.svc.InfoDocumentFormate(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -279,7 +279,7 @@ func (h DocumentFormateHttp) InfoDocumentFormateByCode(ctx microservice.IContext
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoDocumentFormateByCode(shopID, code)
+	doc, err := h.svc.InfoDocumentFormateByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -318,7 +318,7 @@ func (h DocumentFormateHttp) SearchDocumentFormatePage(ctx microservice.IContext
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchDocumentFormate(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchDocumentFormate(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -361,7 +361,7 @@ func (h DocumentFormateHttp) SearchDocumentFormateStep(ctx microservice.IContext
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchDocumentFormateStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchDocumentFormateStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -401,7 +401,7 @@ func (h DocumentFormateHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -20,6 +20,7 @@ type DocumentFormate struct {
 	Details                  *[]DocumentFormateDetail `json:"details" bson:"details"`
 	IsAutoFormat             bool                     `json:"isautoformat" bson:"isautoformat"`
 	YearType                 int8                     `json:"yeartype" bson:"yeartype"`
+	IsGapFree                bool                     `json:"isgapfree" bson:"isgapfree"`
 	AccountGroup             string                   `json:"accountgroup" bson:"accountgroup"`
 	BookCode                 string                   `json:"bookcode" bson:"bookcode"`
 }
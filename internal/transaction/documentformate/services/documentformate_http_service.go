@@ -12,6 +12,7 @@ import (
 	"smlaicloudplatform/internal/transaction/documentformate/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -20,15 +21,15 @@ import (
 )
 
 type IDocumentFormateHttpService interface {
-	CreateDocumentFormate(shopID string, authUsername string, doc models.DocumentFormate) (string, error)
-	UpdateDocumentFormate(shopID string, guid string, authUsername string, doc models.DocumentFormate) error
-	DeleteDocumentFormate(shopID string, guid string, authUsername string) error
-	DeleteDocumentFormateByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoDocumentFormate(shopID string, guid string) (models.DocumentFormateInfo, error)
-	InfoDocumentFormateByCode(shopID string, code string) (models.DocumentFormateInfo, error)
-	SearchDocumentFormate(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentFormateInfo, mongopagination.PaginationData, error)
-	SearchDocumentFormateStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DocumentFormateInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.DocumentFormate) (common.BulkImport, error)
+	CreateDocumentFormate(ctx context.Context, shopID string, authUsername string, doc models.DocumentFormate) (string, error)
+	UpdateDocumentFormate(ctx context.Context, shopID string, guid string, authUsername string, doc models.DocumentFormate) error
+	DeleteDocumentFormate(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteDocumentFormateByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoDocumentFormate(ctx context.Context, shopID string, guid string) (models.DocumentFormateInfo, error)
+	InfoDocumentFormateByCode(ctx context.Context, shopID string, code string) (models.DocumentFormateInfo, error)
+	SearchDocumentFormate(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentFormateInfo, mongopagination.PaginationData, error)
+	SearchDocumentFormateStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DocumentFormateInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.DocumentFormate) (common.BulkImport, error)
 	GetModuleDefault() ([]map[string]interface{}, error)
 
 	GetModuleName() string
@@ -57,13 +58,13 @@ func NewDocumentFormateHttpService(repo repositories.IDocumentFormateRepository,
 	return insSvc
 }
 
-func (svc DocumentFormateHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc DocumentFormateHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc DocumentFormateHttpService) CreateDocumentFormate(shopID string, authUsername string, doc models.DocumentFormate) (string, error) {
+func (svc DocumentFormateHttpService) CreateDocumentFormate(ctx context.Context, shopID string, authUsername string, doc models.DocumentFormate) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "doccode", doc.DocCode)
@@ -97,9 +98,9 @@ func (svc DocumentFormateHttpService) CreateDocumentFormate(shopID string, authU
 	return newGuidFixed, nil
 }
 
-func (svc DocumentFormateHttpService) UpdateDocumentFormate(shopID string, guid string, authUsername string, doc models.DocumentFormate) error {
+func (svc DocumentFormateHttpService) UpdateDocumentFormate(ctx context.Context, shopID string, guid string, authUsername string, doc models.DocumentFormate) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -128,9 +129,9 @@ func (svc DocumentFormateHttpService) UpdateDocumentFormate(shopID string, guid
 	return nil
 }
 
-func (svc DocumentFormateHttpService) DeleteDocumentFormate(shopID string, guid string, authUsername string) error {
+func (svc DocumentFormateHttpService) DeleteDocumentFormate(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -153,9 +154,9 @@ func (svc DocumentFormateHttpService) DeleteDocumentFormate(shopID string, guid
 	return nil
 }
 
-func (svc DocumentFormateHttpService) DeleteDocumentFormateByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc DocumentFormateHttpService) DeleteDocumentFormateByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -170,9 +171,9 @@ func (svc DocumentFormateHttpService) DeleteDocumentFormateByGUIDs(shopID string
 	return nil
 }
 
-func (svc DocumentFormateHttpService) InfoDocumentFormate(shopID string, guid string) (models.DocumentFormateInfo, error) {
+func (svc DocumentFormateHttpService) InfoDocumentFormate(ctx context.Context, shopID string, guid string) (models.DocumentFormateInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -188,9 +189,9 @@ func (svc DocumentFormateHttpService) InfoDocumentFormate(shopID string, guid st
 	return findDoc.DocumentFormateInfo, nil
 }
 
-func (svc DocumentFormateHttpService) InfoDocumentFormateByCode(shopID string, code string) (models.DocumentFormateInfo, error) {
+func (svc DocumentFormateHttpService) InfoDocumentFormateByCode(ctx context.Context, shopID string, code string) (models.DocumentFormateInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "doccode", code)
@@ -206,9 +207,9 @@ func (svc DocumentFormateHttpService) InfoDocumentFormateByCode(shopID string, c
 	return findDoc.DocumentFormateInfo, nil
 }
 
-func (svc DocumentFormateHttpService) SearchDocumentFormate(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentFormateInfo, mongopagination.PaginationData, error) {
+func (svc DocumentFormateHttpService) SearchDocumentFormate(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.DocumentFormateInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -224,9 +225,9 @@ func (svc DocumentFormateHttpService) SearchDocumentFormate(shopID string, filte
 	return docList, pagination, nil
 }
 
-func (svc DocumentFormateHttpService) SearchDocumentFormateStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DocumentFormateInfo, int, error) {
+func (svc DocumentFormateHttpService) SearchDocumentFormateStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.DocumentFormateInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -252,9 +253,9 @@ func (svc DocumentFormateHttpService) SearchDocumentFormateStep(shopID string, l
 	return docList, total, nil
 }
 
-func (svc DocumentFormateHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.DocumentFormate) (common.BulkImport, error) {
+func (svc DocumentFormateHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.DocumentFormate) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.DocumentFormate](dataList, svc.getDocIDKey)
@@ -0,0 +1,124 @@
+package linereference
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"strings"
+)
+
+// quantity and amount which differ less than QtyPrecision are equal
+const QtyPrecision = 0.000001
+
+// MaxSaveAttempts is number of attempts to save references when referenced documents are changed by concurrent requests
+const MaxSaveAttempts = 3
+
+// LineError is error of the line of the document
+type LineError struct {
+	LineNumber int    `json:"linenumber"`
+	Message    string `json:"message"`
+}
+
+// Error is returned when lines of the document can not reference lines of other documents
+// or referenced lines are changed
+type Error struct {
+	Lines []LineError
+}
+
+func (e Error) Error() string {
+	messages := []string{}
+	for _, line := range e.Lines {
+		messages = append(messages, fmt.Sprintf("line %d: %s", line.LineNumber, line.Message))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Reference is line of the document which references line of other document, e.g. delivery of sale order
+type Reference interface {
+	// DocGuid return guid of the document of the line
+	DocGuid() string
+	ReferencedLineNumber() int
+	ReferencedQty() float64
+}
+
+// NormalizeLineNumbers set line number of lines to their position when line numbers are
+// not set or duplicated, so other documents can reference the line by its line number
+func NormalizeLineNumbers(details []trans_models.Detail) {
+	lineNumbers := map[int]bool{}
+	isUnique := true
+	for _, detail := range details {
+		if detail.LineNumber < 1 || lineNumbers[detail.LineNumber] {
+			isUnique = false
+			break
+		}
+		lineNumbers[detail.LineNumber] = true
+	}
+
+	if isUnique {
+		return
+	}
+
+	for i := range details {
+		details[i].LineNumber = i + 1
+	}
+}
+
+// Lines return copy of lines of the document which are numbered by NormalizeLineNumbers
+func Lines(details *[]trans_models.Detail) []trans_models.Detail {
+	if details == nil {
+		return []trans_models.Detail{}
+	}
+
+	// copy lines so line numbers of the document which is read are not changed
+	lines := append([]trans_models.Detail{}, *details...)
+	NormalizeLineNumbers(lines)
+	return lines
+}
+
+// FindLine return line of the document by its line number
+func FindLine(details []trans_models.Detail, lineNumber int) (trans_models.Detail, bool) {
+	for _, detail := range details {
+		if detail.LineNumber == lineNumber {
+			return detail, true
+		}
+	}
+	return trans_models.Detail{}, false
+}
+
+// ReferencedQty return quantity of referenced lines by their line number
+func ReferencedQty[T Reference](references []T) map[int]float64 {
+	qty := map[int]float64{}
+	for _, reference := range references {
+		qty[reference.ReferencedLineNumber()] += reference.ReferencedQty()
+	}
+	return qty
+}
+
+// WithoutDocument return references which are not lines of the document
+func WithoutDocument[T Reference](references []T, docGuid string) []T {
+	result := []T{}
+	for _, reference := range references {
+		if reference.DocGuid() != docGuid {
+			result = append(result, reference)
+		}
+	}
+	return result
+}
+
+// Retry call save again when it returns errConflict, errConflict is returned when every attempt conflicts
+func Retry(errConflict error, save func() error) error {
+	for attempt := 0; attempt < MaxSaveAttempts; attempt++ {
+		err := save()
+		if !errors.Is(err, errConflict) {
+			return err
+		}
+	}
+
+	return errConflict
+}
+
+// RoundAmount round amount of referenced part of the line to 2 decimals
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
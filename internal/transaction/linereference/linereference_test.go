@@ -0,0 +1,94 @@
+package linereference_test
+
+import (
+	"errors"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type referenceLine struct {
+	docGuid    string
+	lineNumber int
+	qty        float64
+}
+
+func (r referenceLine) DocGuid() string           { return r.docGuid }
+func (r referenceLine) ReferencedLineNumber() int { return r.lineNumber }
+func (r referenceLine) ReferencedQty() float64    { return r.qty }
+
+func TestNormalizeLineNumbers(t *testing.T) {
+	details := []trans_models.Detail{{LineNumber: 3}, {LineNumber: 1}}
+	linereference.NormalizeLineNumbers(details)
+	assert.Equal(t, 3, details[0].LineNumber)
+	assert.Equal(t, 1, details[1].LineNumber)
+
+	details = []trans_models.Detail{{LineNumber: 0}, {LineNumber: 0}, {LineNumber: 5}}
+	linereference.NormalizeLineNumbers(details)
+	assert.Equal(t, 1, details[0].LineNumber)
+	assert.Equal(t, 2, details[1].LineNumber)
+	assert.Equal(t, 3, details[2].LineNumber)
+}
+
+func TestLinesDoNotChangeDocument(t *testing.T) {
+	details := &[]trans_models.Detail{{LineNumber: 0}, {LineNumber: 0}}
+	lines := linereference.Lines(details)
+	assert.Equal(t, 1, lines[0].LineNumber)
+	assert.Equal(t, 2, lines[1].LineNumber)
+	assert.Equal(t, 0, (*details)[0].LineNumber)
+
+	line, ok := linereference.FindLine(lines, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, line.LineNumber)
+
+	_, ok = linereference.FindLine(lines, 3)
+	assert.False(t, ok)
+
+	assert.Empty(t, linereference.Lines(nil))
+}
+
+func TestReferencedQtyWithoutDocument(t *testing.T) {
+	references := []referenceLine{
+		{docGuid: "doc1", lineNumber: 1, qty: 2},
+		{docGuid: "doc2", lineNumber: 1, qty: 3},
+		{docGuid: "doc2", lineNumber: 2, qty: 1},
+	}
+
+	assert.Equal(t, map[int]float64{1: 5, 2: 1}, linereference.ReferencedQty(references))
+
+	others := linereference.WithoutDocument(references, "doc2")
+	assert.Equal(t, []referenceLine{{docGuid: "doc1", lineNumber: 1, qty: 2}}, others)
+}
+
+func TestRetry(t *testing.T) {
+	errConflict := errors.New("conflict")
+
+	attempts := 0
+	err := linereference.Retry(errConflict, func() error {
+		attempts++
+		if attempts < 2 {
+			return errConflict
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	err = linereference.Retry(errConflict, func() error {
+		attempts++
+		return errConflict
+	})
+	assert.ErrorIs(t, err, errConflict)
+	assert.Equal(t, linereference.MaxSaveAttempts, attempts)
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := linereference.Error{Lines: []linereference.LineError{
+		{LineNumber: 1, Message: "qty is over"},
+		{LineNumber: 3, Message: "line is not found"},
+	}}
+	assert.Equal(t, "line 1: qty is over, line 3: line is not found", err.Error())
+}
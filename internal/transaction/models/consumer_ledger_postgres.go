@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	ConsumerLedgerStatusProcessing = "processing"
+	ConsumerLedgerStatusDone       = "done"
+)
+
+// ConsumerLedgerPG record message already applied by transaction consumer
+type ConsumerLedgerPG struct {
+	Consumer    string    `json:"consumer" gorm:"column:consumer;primaryKey"`
+	ShopID      string    `json:"shopid" gorm:"column:shopid;primaryKey"`
+	DocNo       string    `json:"docno" gorm:"column:docno;primaryKey"`
+	Version     string    `json:"version" gorm:"column:version;primaryKey"`
+	Status      string    `json:"status" gorm:"column:status;default:done"`
+	ProcessedAt time.Time `json:"processedat" gorm:"column:processedat"`
+}
+
+func (ConsumerLedgerPG) TableName() string {
+	return "transaction_consumer_ledger"
+}
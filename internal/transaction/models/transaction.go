@@ -99,6 +99,15 @@ type Transaction struct {
 	Details           *[]Detail `json:"details" bson:"details"`
 }
 
+// TransactionSchemaVersion is json schema version of transaction messages, increase it when json of Transaction
+// is changed and register upcaster of the old version with microservice.RegisterEventUpcaster
+const TransactionSchemaVersion = 1
+
+// EventSchemaVersion return schema version of the message of document which embed Transaction
+func (Transaction) EventSchemaVersion() int {
+	return TransactionSchemaVersion
+}
+
 type TransactionMessageQueue struct {
 	models.ShopIdentity `bson:"inline"`
 	models.DocIdentity  `bson:"inline"`
@@ -156,6 +165,8 @@ type Detail struct {
 	SumAmount           float64         `json:"sumamount" bson:"sumamount"`
 	SumAmountExcludeVat float64         `json:"sumamountexcludevat" bson:"sumamountexcludevat"`
 	RefGuid             string          `json:"refguid" bson:"refguid"`
+	RefDocGuid          string          `json:"refdocguid" bson:"refdocguid"`
+	RefLineNumber       int             `json:"reflinenumber" bson:"reflinenumber"`
 	DivideValue         float64         `json:"dividevalue" bson:"dividevalue"`
 	StandValue          float64         `json:"standvalue" bson:"standvalue"`
 	VatType             int8            `json:"vattype" bson:"vattype"`
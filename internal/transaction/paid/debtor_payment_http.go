@@ -6,10 +6,11 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/paid/models"
 	"smlaicloudplatform/internal/transaction/paid/repositories"
 	"smlaicloudplatform/internal/transaction/paid/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -28,11 +29,11 @@ func NewPaidHttp(ms *microservice.Microservice, cfg config.IConfig) PaidHttp {
 	cache := ms.Cacher(cfg.CacherConfig())
 
 	repo := repositories.NewPaidRepository(pst)
-	repoMq := repositories.NewPaidMessageQueueRepository(ms.Producer(cfg.MQConfig()))
+	repoMq := repositories.NewPaidOutboxMessageQueueRepository(ms.Producer(cfg.MQConfig()), outbox.NewOutboxRepository(pst))
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewPaidHttpService(repo, repoMq, transRepo, masterSyncCacheRepo)
+	svc := services.NewPaidHttpService(repo, repoMq, docNoSequencer, masterSyncCacheRepo)
 
 	return PaidHttp{
 		ms:  ms,
@@ -82,7 +83,7 @@ func (h PaidHttp) CreatePaid(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreatePaid(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreatePaid(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -128,7 +129,7 @@ func (h PaidHttp) UpdatePaid(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePaid(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePaid(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -159,7 +160,7 @@ func (h PaidHttp) DeletePaid(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePaid(shopID, id, authUsername)
+	err := h.svc.DeletePaid(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -198,7 +199,7 @@ func (h PaidHttp) DeletePaidByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeletePaidByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePaidByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -228,7 +229,7 @@ func (h PaidHttp) InfoPaid(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Paid %v", id)
-	doc, err := h.svc.InfoPaid(shopID, id)
+	doc, err := h.svc.InfoPaid(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -258,7 +259,7 @@ func (h PaidHttp) InfoPaidByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPaidByCode(shopID, code)
+	doc, err := h.svc.InfoPaidByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -310,7 +311,7 @@ func (h PaidHttp) SearchPaidPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchPaid(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchPaid(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -366,7 +367,7 @@ func (h PaidHttp) SearchPaidStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchPaidStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPaidStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -406,7 +407,7 @@ func (h PaidHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
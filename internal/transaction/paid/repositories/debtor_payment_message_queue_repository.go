@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/paid/config"
 	"smlaicloudplatform/internal/transaction/paid/models"
@@ -14,6 +15,11 @@ type IDebtorPaymentMessageQueueRepository interface {
 	CreateInBatch(docList []models.PaidDoc) error
 	UpdateInBatch(docList []models.PaidDoc) error
 	DeleteInBatch(docList []models.PaidDoc) error
+	OutboxCreate(ctx context.Context, doc models.PaidDoc) error
+	OutboxUpdate(ctx context.Context, doc models.PaidDoc) error
+	OutboxDelete(ctx context.Context, doc models.PaidDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.PaidDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.PaidDoc) error
 }
 
 type PaidMessageQueueRepository struct {
@@ -32,3 +38,12 @@ func NewPaidMessageQueueRepository(prod microservice.IProducer) IDebtorPaymentMe
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PaidDoc](prod, config.DebtorPaymentMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewPaidOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewPaidOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) IDebtorPaymentMessageQueueRepository {
+	insRepo := PaidMessageQueueRepository{
+		prod: prod,
+	}
+	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PaidDoc](prod, config.DebtorPaymentMessageQueueConfig{}, "").WithOutbox(outbox)
+	return insRepo
+}
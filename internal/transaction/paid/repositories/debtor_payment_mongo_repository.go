@@ -34,6 +34,7 @@ type IPaidRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PaidActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PaidDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type PaidRepository struct {
@@ -58,6 +59,10 @@ func NewPaidRepository(pst microservice.IPersisterMongo) *PaidRepository {
 	return insRepo
 }
 
+func (repo PaidRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo PaidRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PaidDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
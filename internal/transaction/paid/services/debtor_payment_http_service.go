@@ -7,14 +7,13 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	"smlaicloudplatform/internal/transaction/paid/models"
 	"smlaicloudplatform/internal/transaction/paid/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -22,15 +21,15 @@ import (
 )
 
 type IPaidHttpService interface {
-	CreatePaid(shopID string, authUsername string, doc models.Paid) (string, string, error)
-	UpdatePaid(shopID string, guid string, authUsername string, doc models.Paid) error
-	DeletePaid(shopID string, guid string, authUsername string) error
-	DeletePaidByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPaid(shopID string, guid string) (models.PaidInfo, error)
-	InfoPaidByCode(shopID string, code string) (models.PaidInfo, error)
-	SearchPaid(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PaidInfo, mongopagination.PaginationData, error)
-	SearchPaidStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PaidInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Paid) (common.BulkImport, error)
+	CreatePaid(ctx context.Context, shopID string, authUsername string, doc models.Paid) (string, string, error)
+	UpdatePaid(ctx context.Context, shopID string, guid string, authUsername string, doc models.Paid) error
+	DeletePaid(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePaidByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPaid(ctx context.Context, shopID string, guid string) (models.PaidInfo, error)
+	InfoPaidByCode(ctx context.Context, shopID string, code string) (models.PaidInfo, error)
+	SearchPaid(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PaidInfo, mongopagination.PaginationData, error)
+	SearchPaidStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PaidInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Paid) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -40,26 +39,24 @@ const (
 )
 
 type PaidHttpService struct {
-	repo             repositories.IPaidRepository
-	repoCache        trancache.ICacheRepository
-	repoMq           repositories.IDebtorPaymentMessageQueueRepository
-	cacheExpireDocNo time.Duration
-	syncCacheRepo    mastersync.IMasterSyncCacheRepository
+	repo           repositories.IPaidRepository
+	docNoSequencer docsequence.IDocNoSequencer
+	repoMq         repositories.IDebtorPaymentMessageQueueRepository
+	syncCacheRepo  mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.PaidActivity, models.PaidDeleteActivity]
 	contextTimeout time.Duration
 }
 
-func NewPaidHttpService(repo repositories.IPaidRepository, repoMq repositories.IDebtorPaymentMessageQueueRepository, repoCache trancache.ICacheRepository, syncCacheRepo mastersync.IMasterSyncCacheRepository) *PaidHttpService {
+func NewPaidHttpService(repo repositories.IPaidRepository, repoMq repositories.IDebtorPaymentMessageQueueRepository, docNoSequencer docsequence.IDocNoSequencer, syncCacheRepo mastersync.IMasterSyncCacheRepository) *PaidHttpService {
 
 	contextTimeout := time.Duration(15) * time.Second
 
 	insSvc := &PaidHttpService{
-		repo:             repo,
-		repoCache:        repoCache,
-		repoMq:           repoMq,
-		syncCacheRepo:    syncCacheRepo,
-		cacheExpireDocNo: time.Hour * 24,
-		contextTimeout:   contextTimeout,
+		repo:           repo,
+		docNoSequencer: docNoSequencer,
+		repoMq:         repoMq,
+		syncCacheRepo:  syncCacheRepo,
+		contextTimeout: contextTimeout,
 	}
 
 	insSvc.ActivityService = services.NewActivityService[models.PaidActivity, models.PaidDeleteActivity](repo)
@@ -67,65 +64,15 @@ func NewPaidHttpService(repo repositories.IPaidRepository, repoMq repositories.I
 	return insSvc
 }
 
-func (svc PaidHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PaidHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PaidHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc PaidHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
+func (svc PaidHttpService) CreatePaid(ctx context.Context, shopID string, authUsername string, doc models.Paid) (string, string, error) {
 
-func (svc PaidHttpService) CreatePaid(shopID string, authUsername string, doc models.Paid) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	docData := models.PaidDoc{}
@@ -133,30 +80,33 @@ func (svc PaidHttpService) CreatePaid(shopID string, authUsername string, doc mo
 	docData.GuidFixed = newGuidFixed
 	docData.Paid = doc
 
-	docData.DocNo = newDocNo
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, docData)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Create(docData)
-	}()
-
 	return newGuidFixed, newDocNo, nil
 }
 
-func (svc PaidHttpService) UpdatePaid(shopID string, guid string, authUsername string, doc models.Paid) error {
+func (svc PaidHttpService) UpdatePaid(ctx context.Context, shopID string, guid string, authUsername string, doc models.Paid) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -177,7 +127,14 @@ func (svc PaidHttpService) UpdatePaid(shopID string, guid string, authUsername s
 	docData.UpdatedBy = authUsername
 	docData.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, docData)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, docData)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, docData)
+	})
 
 	if err != nil {
 		return err
@@ -185,16 +142,12 @@ func (svc PaidHttpService) UpdatePaid(shopID string, guid string, authUsername s
 
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Update(findDoc)
-	}()
-
 	return nil
 }
 
-func (svc PaidHttpService) DeletePaid(shopID string, guid string, authUsername string) error {
+func (svc PaidHttpService) DeletePaid(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -207,23 +160,26 @@ func (svc PaidHttpService) DeletePaid(shopID string, guid string, authUsername s
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Delete(findDoc)
-	}()
-
 	return nil
 }
 
-func (svc PaidHttpService) DeletePaidByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PaidHttpService) DeletePaidByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -238,9 +194,9 @@ func (svc PaidHttpService) DeletePaidByGUIDs(shopID string, authUsername string,
 	return nil
 }
 
-func (svc PaidHttpService) InfoPaid(shopID string, guid string) (models.PaidInfo, error) {
+func (svc PaidHttpService) InfoPaid(ctx context.Context, shopID string, guid string) (models.PaidInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -256,9 +212,9 @@ func (svc PaidHttpService) InfoPaid(shopID string, guid string) (models.PaidInfo
 	return findDoc.PaidInfo, nil
 }
 
-func (svc PaidHttpService) InfoPaidByCode(shopID string, code string) (models.PaidInfo, error) {
+func (svc PaidHttpService) InfoPaidByCode(ctx context.Context, shopID string, code string) (models.PaidInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -274,9 +230,9 @@ func (svc PaidHttpService) InfoPaidByCode(shopID string, code string) (models.Pa
 	return findDoc.PaidInfo, nil
 }
 
-func (svc PaidHttpService) SearchPaid(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PaidInfo, mongopagination.PaginationData, error) {
+func (svc PaidHttpService) SearchPaid(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PaidInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -292,9 +248,9 @@ func (svc PaidHttpService) SearchPaid(shopID string, filters map[string]interfac
 	return docList, pagination, nil
 }
 
-func (svc PaidHttpService) SearchPaidStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PaidInfo, int, error) {
+func (svc PaidHttpService) SearchPaidStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PaidInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -312,9 +268,9 @@ func (svc PaidHttpService) SearchPaidStep(shopID string, langCode string, filter
 	return docList, total, nil
 }
 
-func (svc PaidHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Paid) (common.BulkImport, error) {
+func (svc PaidHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Paid) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Paid](dataList, svc.getDocIDKey)
@@ -374,30 +330,35 @@ func (svc PaidHttpService) SaveInBatch(shopID string, authUsername string, dataL
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
-			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+			err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+				err := svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+				if err != nil {
+					return err
+				}
+
+				return svc.repoMq.OutboxUpdate(ctx, doc)
+			})
 			if err != nil {
 				return nil
 			}
 
-			go func() {
-				svc.repoMq.Update(doc)
-			}()
-
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
-		err = svc.repo.CreateInBatch(ctx, createDataList)
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			err := svc.repo.CreateInBatch(ctx, createDataList)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreateInBatch(ctx, createDataList)
+		})
 
 		if err != nil {
 			return common.BulkImport{}, err
 		}
-
-		go func() {
-			svc.repoMq.CreateInBatch(createDataList)
-		}()
-
 	}
 
 	createDataKey := []string{}
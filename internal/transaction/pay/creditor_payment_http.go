@@ -6,10 +6,12 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
+	"smlaicloudplatform/internal/transaction/approval"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/pay/models"
 	"smlaicloudplatform/internal/transaction/pay/repositories"
 	"smlaicloudplatform/internal/transaction/pay/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -29,11 +31,12 @@ func NewPayHttp(ms *microservice.Microservice, cfg config.IConfig) PayHttp {
 	cache := ms.Cacher(cfg.CacherConfig())
 
 	repo := repositories.NewPayRepository(pst)
-	repoMq := repositories.NewPaidMessageQueueRepository(producer)
+	repoMq := repositories.NewPaidOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewPayHttpService(repo, repoMq, transRepo, masterSyncCacheRepo)
+	approvalWorkflow := approval.InitApprovalWorkflow(ms, cfg)
+	svc := services.NewPayHttpService(repo, repoMq, docNoSequencer, approvalWorkflow, masterSyncCacheRepo)
 
 	return PayHttp{
 		ms:  ms,
@@ -54,6 +57,8 @@ func (h PayHttp) RegisterHttp() {
 	h.ms.PUT("/transaction/pay/:id", h.UpdatePay)
 	h.ms.DELETE("/transaction/pay/:id", h.DeletePay)
 	h.ms.DELETE("/transaction/pay", h.DeletePayByGUIDs)
+
+	approval.NewDocumentApprovalHttp(h.ms, h.svc.Approval()).RegisterHttp("/transaction/pay")
 }
 
 // Create Pay godoc
@@ -83,7 +88,7 @@ func (h PayHttp) CreatePay(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreatePay(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreatePay(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -129,7 +134,7 @@ func (h PayHttp) UpdatePay(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePay(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePay(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -160,7 +165,7 @@ func (h PayHttp) DeletePay(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePay(shopID, id, authUsername)
+	err := h.svc.DeletePay(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -199,7 +204,7 @@ func (h PayHttp) DeletePayByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeletePayByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePayByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -229,7 +234,7 @@ func (h PayHttp) InfoPay(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Pay %v", id)
-	doc, err := h.svc.InfoPay(shopID, id)
+	doc, err := h.svc.InfoPay(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -259,7 +264,7 @@ func (h PayHttp) InfoPayByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPayByCode(shopID, code)
+	doc, err := h.svc.InfoPayByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -311,7 +316,7 @@ func (h PayHttp) SearchPayPage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchPay(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchPay(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -367,7 +372,7 @@ func (h PayHttp) SearchPayStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchPayStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPayStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -407,7 +412,7 @@ func (h PayHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -2,6 +2,7 @@ package models
 
 import (
 	"smlaicloudplatform/internal/models"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
 	transmodels "smlaicloudplatform/internal/transaction/models"
 	"time"
 
@@ -40,6 +41,8 @@ type Pay struct {
 	IsCancel         bool    `json:"iscancel" bson:"iscancel"`
 
 	Branch PayBranch `json:"branch" bson:"branch"`
+
+	approvalmodels.DocApproval `bson:"inline"`
 }
 
 type PayBranch struct {
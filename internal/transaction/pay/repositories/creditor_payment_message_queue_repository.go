@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/pay/config"
 	"smlaicloudplatform/internal/transaction/pay/models"
@@ -14,6 +15,11 @@ type ICreditorPaymentMessageQueueRepository interface {
 	CreateInBatch(docList []models.PayDoc) error
 	UpdateInBatch(docList []models.PayDoc) error
 	DeleteInBatch(docList []models.PayDoc) error
+	OutboxCreate(ctx context.Context, doc models.PayDoc) error
+	OutboxUpdate(ctx context.Context, doc models.PayDoc) error
+	OutboxDelete(ctx context.Context, doc models.PayDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.PayDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.PayDoc) error
 }
 
 type PaidMessageQueueRepository struct {
@@ -32,3 +38,12 @@ func NewPaidMessageQueueRepository(prod microservice.IProducer) ICreditorPayment
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PayDoc](prod, config.CreditorPaymentMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewPaidOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewPaidOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) ICreditorPaymentMessageQueueRepository {
+	insRepo := PaidMessageQueueRepository{
+		prod: prod,
+	}
+	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PayDoc](prod, config.CreditorPaymentMessageQueueConfig{}, "").WithOutbox(outbox)
+	return insRepo
+}
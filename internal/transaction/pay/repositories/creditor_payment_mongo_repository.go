@@ -34,6 +34,7 @@ type IPayRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PayActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PayDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type PayRepository struct {
@@ -58,6 +59,10 @@ func NewPayRepository(pst microservice.IPersisterMongo) *PayRepository {
 	return insRepo
 }
 
+func (repo PayRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo PayRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PayDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
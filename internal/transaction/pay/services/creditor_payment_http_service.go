@@ -7,14 +7,15 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	"smlaicloudplatform/internal/transaction/pay/models"
 	"smlaicloudplatform/internal/transaction/pay/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -26,39 +27,40 @@ const (
 )
 
 type IPayHttpService interface {
-	CreatePay(shopID string, authUsername string, doc models.Pay) (string, string, error)
-	UpdatePay(shopID string, guid string, authUsername string, doc models.Pay) error
-	DeletePay(shopID string, guid string, authUsername string) error
-	DeletePayByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPay(shopID string, guid string) (models.PayInfo, error)
-	InfoPayByCode(shopID string, code string) (models.PayInfo, error)
-	SearchPay(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PayInfo, mongopagination.PaginationData, error)
-	SearchPayStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PayInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Pay) (common.BulkImport, error)
+	CreatePay(ctx context.Context, shopID string, authUsername string, doc models.Pay) (string, string, error)
+	UpdatePay(ctx context.Context, shopID string, guid string, authUsername string, doc models.Pay) error
+	DeletePay(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePayByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPay(ctx context.Context, shopID string, guid string) (models.PayInfo, error)
+	InfoPayByCode(ctx context.Context, shopID string, code string) (models.PayInfo, error)
+	SearchPay(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PayInfo, mongopagination.PaginationData, error)
+	SearchPayStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PayInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Pay) (common.BulkImport, error)
+	Approval() approvalservices.IDocumentApprovalService
 
 	GetModuleName() string
 }
 
 type PayHttpService struct {
 	repo             repositories.IPayRepository
-	repoCache        trancache.ICacheRepository
+	docNoSequencer   docsequence.IDocNoSequencer
+	approvalWorkflow approvalservices.IApprovalWorkflow
 	repoMq           repositories.ICreditorPaymentMessageQueueRepository
-	cacheExpireDocNo time.Duration
 	syncCacheRepo    mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.PayActivity, models.PayDeleteActivity]
 	contextTimeout time.Duration
 }
 
-func NewPayHttpService(repo repositories.IPayRepository, repoMq repositories.ICreditorPaymentMessageQueueRepository, repoCache trancache.ICacheRepository, syncCacheRepo mastersync.IMasterSyncCacheRepository) *PayHttpService {
+func NewPayHttpService(repo repositories.IPayRepository, repoMq repositories.ICreditorPaymentMessageQueueRepository, docNoSequencer docsequence.IDocNoSequencer, approvalWorkflow approvalservices.IApprovalWorkflow, syncCacheRepo mastersync.IMasterSyncCacheRepository) *PayHttpService {
 
 	contextTimeout := time.Duration(15) * time.Second
 
 	insSvc := &PayHttpService{
 		repo:             repo,
 		repoMq:           repoMq,
-		repoCache:        repoCache,
+		docNoSequencer:   docNoSequencer,
+		approvalWorkflow: approvalWorkflow,
 		syncCacheRepo:    syncCacheRepo,
-		cacheExpireDocNo: time.Hour * 24,
 		contextTimeout:   contextTimeout,
 	}
 
@@ -67,66 +69,15 @@ func NewPayHttpService(repo repositories.IPayRepository, repoMq repositories.ICr
 	return insSvc
 }
 
-func (svc PayHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PayHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PayHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc PayHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc PayHttpService) CreatePay(shopID string, authUsername string, doc models.Pay) (string, string, error) {
+func (svc PayHttpService) CreatePay(ctx context.Context, shopID string, authUsername string, doc models.Pay) (string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	docData := models.PayDoc{}
@@ -134,30 +85,44 @@ func (svc PayHttpService) CreatePay(shopID string, authUsername string, doc mode
 	docData.GuidFixed = newGuidFixed
 	docData.Pay = doc
 
-	docData.DocNo = newDocNo
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		approvalStatus, err := svc.approvalWorkflow.Submit(ctx, svc.approvalDocument(shopID, newGuidFixed, docNo, doc), authUsername, doc.IsDraft)
+		if err != nil {
+			return err
+		}
+
+		docData.DocNo = docNo
+		docData.ApprovalStatus = approvalStatus
+
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, docData)
+		})
+		if err != nil {
+			svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePay, newGuidFixed)
+		}
+		return err
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Create(docData)
-	}()
-
 	return newGuidFixed, newDocNo, nil
 }
 
-func (svc PayHttpService) UpdatePay(shopID string, guid string, authUsername string, doc models.Pay) error {
+func (svc PayHttpService) UpdatePay(ctx context.Context, shopID string, guid string, authUsername string, doc models.Pay) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -170,14 +135,28 @@ func (svc PayHttpService) UpdatePay(shopID string, guid string, authUsername str
 		return errors.New("document not found")
 	}
 
+	approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, guid, findDoc.DocNo, doc), authUsername, doc.IsDraft)
+
+	if err != nil {
+		return err
+	}
+
 	docData := findDoc
 	docData.Pay = doc
 
 	docData.DocNo = findDoc.DocNo
+	docData.ApprovalStatus = approvalStatus
 	docData.UpdatedBy = authUsername
 	docData.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, docData)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, docData)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, docData)
+	})
 
 	if err != nil {
 		return err
@@ -185,16 +164,12 @@ func (svc PayHttpService) UpdatePay(shopID string, guid string, authUsername str
 
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Update(findDoc)
-	}()
-
 	return nil
 }
 
-func (svc PayHttpService) DeletePay(shopID string, guid string, authUsername string) error {
+func (svc PayHttpService) DeletePay(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -207,23 +182,28 @@ func (svc PayHttpService) DeletePay(shopID string, guid string, authUsername str
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
-	svc.saveMasterSync(shopID)
+	svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePay, guid)
 
-	go func() {
-		svc.repoMq.Delete(findDoc)
-	}()
+	svc.saveMasterSync(shopID)
 
 	return nil
 }
 
-func (svc PayHttpService) DeletePayByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PayHttpService) DeletePayByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -235,12 +215,16 @@ func (svc PayHttpService) DeletePayByGUIDs(shopID string, authUsername string, G
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePay, guid)
+	}
+
 	return nil
 }
 
-func (svc PayHttpService) InfoPay(shopID string, guid string) (models.PayInfo, error) {
+func (svc PayHttpService) InfoPay(ctx context.Context, shopID string, guid string) (models.PayInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -256,9 +240,9 @@ func (svc PayHttpService) InfoPay(shopID string, guid string) (models.PayInfo, e
 	return findDoc.PayInfo, nil
 }
 
-func (svc PayHttpService) InfoPayByCode(shopID string, code string) (models.PayInfo, error) {
+func (svc PayHttpService) InfoPayByCode(ctx context.Context, shopID string, code string) (models.PayInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -274,9 +258,9 @@ func (svc PayHttpService) InfoPayByCode(shopID string, code string) (models.PayI
 	return findDoc.PayInfo, nil
 }
 
-func (svc PayHttpService) SearchPay(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PayInfo, mongopagination.PaginationData, error) {
+func (svc PayHttpService) SearchPay(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PayInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -292,9 +276,9 @@ func (svc PayHttpService) SearchPay(shopID string, filters map[string]interface{
 	return docList, pagination, nil
 }
 
-func (svc PayHttpService) SearchPayStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PayInfo, int, error) {
+func (svc PayHttpService) SearchPayStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PayInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -312,9 +296,9 @@ func (svc PayHttpService) SearchPayStep(shopID string, langCode string, filters
 	return docList, total, nil
 }
 
-func (svc PayHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Pay) (common.BulkImport, error) {
+func (svc PayHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Pay) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Pay](dataList, svc.getDocIDKey)
@@ -370,34 +354,57 @@ func (svc PayHttpService) SaveInBatch(shopID string, authUsername string, dataLi
 		},
 		func(shopID string, authUsername string, data models.Pay, doc models.PayDoc) error {
 
+			approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, doc.GuidFixed, doc.DocNo, data), authUsername, data.IsDraft)
+			if err != nil {
+				return err
+			}
+
 			doc.Pay = data
+			doc.ApprovalStatus = approvalStatus
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
-			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+			err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+				err := svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+				if err != nil {
+					return err
+				}
+
+				return svc.repoMq.OutboxUpdate(ctx, doc)
+			})
 			if err != nil {
 				return nil
 			}
 
-			go func() {
-				svc.repoMq.Update(doc)
-			}()
-
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
-		err = svc.repo.CreateInBatch(ctx, createDataList)
+		documentApproval := svc.documentApproval()
 
+		approvalStatuses, err := documentApproval.SubmitInBatch(ctx, createDataList, authUsername)
 		if err != nil {
 			return common.BulkImport{}, err
 		}
 
-		go func() {
-			svc.repoMq.CreateInBatch(createDataList)
-		}()
+		for idx := range createDataList {
+			createDataList[idx].ApprovalStatus = approvalStatuses[idx]
+		}
 
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			err := svc.repo.CreateInBatch(ctx, createDataList)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreateInBatch(ctx, createDataList)
+		})
+
+		if err != nil {
+			documentApproval.RemoveInBatch(ctx, createDataList)
+			return common.BulkImport{}, err
+		}
 	}
 
 	createDataKey := []string{}
@@ -449,3 +456,52 @@ func (svc PayHttpService) saveMasterSync(shopID string) {
 func (svc PayHttpService) GetModuleName() string {
 	return "pay"
 }
+
+func (svc PayHttpService) approvalDocument(shopID string, guid string, docNo string, doc models.Pay) approvalmodels.ApprovalDocument {
+	return approvalmodels.ApprovalDocument{
+		ShopID:  shopID,
+		DocType: approvalmodels.DocTypePay,
+		DocGuid: guid,
+		DocNo:   docNo,
+		Amount:  doc.TotalAmount,
+	}
+}
+
+// Approval return approval workflow of saved payments
+func (svc PayHttpService) Approval() approvalservices.IDocumentApprovalService {
+	return svc.documentApproval()
+}
+
+func (svc PayHttpService) documentApproval() approvalservices.DocumentApprovalService[models.PayDoc] {
+	return approvalservices.NewDocumentApprovalService(
+		svc.approvalWorkflow,
+		approvalmodels.DocTypePay,
+		svc.repo.FindByGuid,
+		func(doc models.PayDoc) approvalmodels.ApprovalDocument {
+			return svc.approvalDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.Pay)
+		},
+		svc.saveApprovalStatus,
+	)
+}
+
+// saveApprovalStatus save changed approval status and publish the document so payment is posted or removed by consumers
+func (svc PayHttpService) saveApprovalStatus(ctx context.Context, doc models.PayDoc, approvalStatus string) error {
+	doc.ApprovalStatus = approvalStatus
+
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, doc.ShopID, doc.GuidFixed, doc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, doc)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(doc.ShopID)
+
+	return nil
+}
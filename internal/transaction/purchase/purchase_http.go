@@ -6,11 +6,13 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/purchase/models"
 	"smlaicloudplatform/internal/transaction/purchase/repositories"
 	"smlaicloudplatform/internal/transaction/purchase/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/purchaseorder"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -31,13 +33,14 @@ func NewPurchaseHttp(ms *microservice.Microservice, cfg config.IConfig) Purchase
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewPurchaseRepository(pst)
-	repoMq := repositories.NewPurchaseMessageQueueRepository(producer)
+	repoMq := repositories.NewPurchaseOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewPurchaseService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.PurchaseParser{})
+	fulfilment := purchaseorder.InitPurchaseOrderFulfilment(ms, cfg)
+	svc := services.NewPurchaseService(repo, docNoSequencer, fulfilment, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.PurchaseParser{})
 
 	return PurchaseHttp{
 		ms:  ms,
@@ -87,10 +90,10 @@ func (h PurchaseHttp) CreatePurchase(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreatePurchase(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreatePurchase(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		purchaseorder.ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -133,10 +136,10 @@ func (h PurchaseHttp) UpdatePurchase(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdatePurchase(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePurchase(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		purchaseorder.ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -164,10 +167,10 @@ func (h PurchaseHttp) DeletePurchase(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePurchase(shopID, id, authUsername)
+	err := h.svc.DeletePurchase(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		purchaseorder.ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -203,10 +206,10 @@ func (h PurchaseHttp) DeletePurchaseByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeletePurchaseByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePurchaseByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		purchaseorder.ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -233,7 +236,7 @@ func (h PurchaseHttp) InfoPurchase(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Purchase %v", id)
-	doc, err := h.svc.InfoPurchase(shopID, id)
+	doc, err := h.svc.InfoPurchase(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -263,7 +266,7 @@ func (h PurchaseHttp) InfoPurchaseByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPurchaseByCode(shopID, code)
+	doc, err := h.svc.InfoPurchaseByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -315,7 +318,7 @@ func (h PurchaseHttp) SearchPurchasePage(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchPurchase(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchPurchase(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -371,7 +374,7 @@ func (h PurchaseHttp) SearchPurchaseStep(ctx microservice.IContext) error {
 		},
 	})
 
-	docList, total, err := h.svc.SearchPurchaseStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPurchaseStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -411,7 +414,7 @@ func (h PurchaseHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
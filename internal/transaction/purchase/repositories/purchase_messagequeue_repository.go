@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/purchase/config"
 	"smlaicloudplatform/internal/transaction/purchase/models"
@@ -14,6 +15,11 @@ type IPurchaseMessageQueueRepository interface {
 	CreateInBatch(docList []models.PurchaseDoc) error
 	UpdateInBatch(docList []models.PurchaseDoc) error
 	DeleteInBatch(docList []models.PurchaseDoc) error
+	OutboxCreate(ctx context.Context, doc models.PurchaseDoc) error
+	OutboxUpdate(ctx context.Context, doc models.PurchaseDoc) error
+	OutboxDelete(ctx context.Context, doc models.PurchaseDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.PurchaseDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.PurchaseDoc) error
 }
 
 type PurchaseMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewPurchaseMessageQueueRepository(prod microservice.IProducer) PurchaseMess
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PurchaseDoc](prod, config.PurchaseMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewPurchaseOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewPurchaseOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) PurchaseMessageQueueRepository {
+	insRepo := NewPurchaseMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,7 @@ type IPurchaseRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type PurchaseRepository struct {
@@ -58,6 +59,10 @@ func NewPurchaseRepository(pst microservice.IPersisterMongo) *PurchaseRepository
 
 	return insRepo
 }
+
+func (repo PurchaseRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
 func (repo PurchaseRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -9,15 +9,17 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
+	"smlaicloudplatform/internal/transaction/linereference"
 	trans_models "smlaicloudplatform/internal/transaction/models"
 	"smlaicloudplatform/internal/transaction/purchase/models"
 	"smlaicloudplatform/internal/transaction/purchase/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	pomodels "smlaicloudplatform/internal/transaction/purchaseorder/models"
+	poservices "smlaicloudplatform/internal/transaction/purchaseorder/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +27,15 @@ import (
 )
 
 type IPurchaseService interface {
-	CreatePurchase(shopID string, authUsername string, doc models.Purchase) (string, string, error)
-	UpdatePurchase(shopID string, guid string, authUsername string, doc models.Purchase) error
-	DeletePurchase(shopID string, guid string, authUsername string) error
-	DeletePurchaseByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPurchase(shopID string, guid string) (models.PurchaseInfo, error)
-	InfoPurchaseByCode(shopID string, code string) (models.PurchaseInfo, error)
-	SearchPurchase(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseInfo, mongopagination.PaginationData, error)
-	SearchPurchaseStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Purchase) (common.BulkImport, error)
+	CreatePurchase(ctx context.Context, shopID string, authUsername string, doc models.Purchase) (string, string, error)
+	UpdatePurchase(ctx context.Context, shopID string, guid string, authUsername string, doc models.Purchase) error
+	DeletePurchase(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePurchaseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPurchase(ctx context.Context, shopID string, guid string) (models.PurchaseInfo, error)
+	InfoPurchaseByCode(ctx context.Context, shopID string, code string) (models.PurchaseInfo, error)
+	SearchPurchase(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseInfo, mongopagination.PaginationData, error)
+	SearchPurchaseStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Purchase) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -49,9 +51,9 @@ const (
 type PurchaseService struct {
 	repoMq             repositories.IPurchaseMessageQueueRepository
 	repo               repositories.IPurchaseRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
+	fulfilment         poservices.IPurchaseOrderFulfilment
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.PurchaseActivity, models.PurchaseDeleteActivity]
 	parser         IPurchaseParser
@@ -60,7 +62,8 @@ type PurchaseService struct {
 
 func NewPurchaseService(
 	repo repositories.IPurchaseRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	fulfilment poservices.IPurchaseOrderFulfilment,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IPurchaseMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +75,11 @@ func NewPurchaseService(
 	insSvc := &PurchaseService{
 		repo:               repo,
 		repoMq:             repoMq,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
+		fulfilment:         fulfilment,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +88,15 @@ func NewPurchaseService(
 	return insSvc
 }
 
-func (svc PurchaseService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PurchaseService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PurchaseService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc PurchaseService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
+func (svc PurchaseService) CreatePurchase(ctx context.Context, shopID string, authUsername string, doc models.Purchase) (string, string, error) {
 
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc PurchaseService) CreatePurchase(shopID string, authUsername string, doc models.Purchase) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.PurchaseDoc{}
@@ -159,22 +111,42 @@ func (svc PurchaseService) CreatePurchase(shopID string, authUsername string, do
 		}
 
 		details := svc.PrepareDetail(*doc.Details, productBarcodes)
+		linereference.NormalizeLineNumbers(details)
 		dataDoc.Details = &details
 	}
 
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		dataDoc.DocNo = docNo
+
+		// lines which reference lines of purchase orders receive them, orders which are saved
+		// before an error give back their receipts
+		err := svc.fulfilment.Receive(ctx, svc.receiptDocument(shopID, newGuidFixed, docNo, dataDoc.Purchase))
+		if err != nil {
+			return svc.releaseReceipts(ctx, dataDoc, err)
+		}
+
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+		if err != nil {
+			return svc.releaseReceipts(ctx, dataDoc, err)
+		}
+		return nil
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -209,9 +181,9 @@ func (svc PurchaseService) PrepareDetail(details []trans_models.Detail, productB
 	return details
 }
 
-func (svc PurchaseService) UpdatePurchase(shopID string, guid string, authUsername string, doc models.Purchase) error {
+func (svc PurchaseService) UpdatePurchase(ctx context.Context, shopID string, guid string, authUsername string, doc models.Purchase) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -233,29 +205,42 @@ func (svc PurchaseService) UpdatePurchase(shopID string, guid string, authUserna
 	}
 
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
+	linereference.NormalizeLineNumbers(details)
 	dataDoc.Details = &details
 
 	dataDoc.DocNo = findDoc.DocNo
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.fulfilment.Receive(ctx, svc.receiptDocument(shopID, guid, findDoc.DocNo, dataDoc.Purchase))
 
 	if err != nil {
-		return err
+		return svc.restoreReceipts(ctx, findDoc, err)
+	}
+
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
+
+	if err != nil {
+		return svc.restoreReceipts(ctx, findDoc, err)
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseService) DeletePurchase(shopID string, guid string, authUsername string) error {
+func (svc PurchaseService) DeletePurchase(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -268,45 +253,76 @@ func (svc PurchaseService) DeletePurchase(shopID string, guid string, authUserna
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.fulfilment.Release(ctx, shopID, guid)
 	if err != nil {
 		return err
 	}
 
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
+
+	if err != nil {
+		return svc.restoreReceipts(ctx, findDoc, err)
+	}
+
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseService) DeletePurchaseByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PurchaseService) DeletePurchaseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
+	findDocs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+	if err != nil {
+		return err
+	}
+
+	for idx, doc := range findDocs {
+		err = svc.fulfilment.Release(ctx, shopID, doc.GuidFixed)
+		if err != nil {
+			// receipts of purchases which are released before the error are given back
+			return svc.restoreReceiptsInBatch(ctx, findDocs[:idx], err)
+		}
+	}
+
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, findDocs)
+	})
+
 	if err != nil {
-		return err
+		return svc.restoreReceiptsInBatch(ctx, findDocs, err)
 	}
 
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseService) InfoPurchase(shopID string, guid string) (models.PurchaseInfo, error) {
+func (svc PurchaseService) InfoPurchase(ctx context.Context, shopID string, guid string) (models.PurchaseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -322,9 +338,9 @@ func (svc PurchaseService) InfoPurchase(shopID string, guid string) (models.Purc
 	return findDoc.PurchaseInfo, nil
 }
 
-func (svc PurchaseService) InfoPurchaseByCode(shopID string, code string) (models.PurchaseInfo, error) {
+func (svc PurchaseService) InfoPurchaseByCode(ctx context.Context, shopID string, code string) (models.PurchaseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -340,9 +356,9 @@ func (svc PurchaseService) InfoPurchaseByCode(shopID string, code string) (model
 	return findDoc.PurchaseInfo, nil
 }
 
-func (svc PurchaseService) SearchPurchase(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseInfo, mongopagination.PaginationData, error) {
+func (svc PurchaseService) SearchPurchase(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -358,9 +374,9 @@ func (svc PurchaseService) SearchPurchase(shopID string, filters map[string]inte
 	return docList, pagination, nil
 }
 
-func (svc PurchaseService) SearchPurchaseStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseInfo, int, error) {
+func (svc PurchaseService) SearchPurchaseStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -378,9 +394,9 @@ func (svc PurchaseService) SearchPurchaseStep(shopID string, langCode string, fi
 	return docList, total, nil
 }
 
-func (svc PurchaseService) SaveInBatch(shopID string, authUsername string, dataList []models.Purchase) (common.BulkImport, error) {
+func (svc PurchaseService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Purchase) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Purchase](dataList, svc.getDocIDKey)
@@ -416,6 +432,10 @@ func (svc PurchaseService) SaveInBatch(shopID string, authUsername string, dataL
 			dataDoc.ShopID = shopID
 			dataDoc.Purchase = doc
 
+			if doc.Details != nil {
+				linereference.NormalizeLineNumbers(*dataDoc.Details)
+			}
+
 			currentTime := time.Now()
 			dataDoc.CreatedBy = authUsername
 			dataDoc.CreatedAt = currentTime
@@ -436,12 +456,24 @@ func (svc PurchaseService) SaveInBatch(shopID string, authUsername string, dataL
 		},
 		func(shopID string, authUsername string, data models.Purchase, doc models.PurchaseDoc) error {
 
+			if data.Details != nil {
+				linereference.NormalizeLineNumbers(*data.Details)
+			}
+
+			err := svc.fulfilment.Receive(ctx, svc.receiptDocument(shopID, doc.GuidFixed, doc.DocNo, data))
+			if err != nil {
+				return svc.restoreReceipts(ctx, doc, err)
+			}
+
+			findDoc := doc
+
 			doc.Purchase = data
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
 			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			if err != nil {
+				svc.restoreReceipts(ctx, findDoc, err)
 				return nil
 			}
 			return nil
@@ -449,9 +481,18 @@ func (svc PurchaseService) SaveInBatch(shopID string, authUsername string, dataL
 	)
 
 	if len(createDataList) > 0 {
+		err = svc.receiveOrdersInBatch(ctx, shopID, createDataList)
+
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			for _, doc := range createDataList {
+				err = svc.releaseReceipts(ctx, doc, err)
+			}
 			return common.BulkImport{}, err
 		}
 
@@ -489,6 +530,100 @@ func (svc PurchaseService) SaveInBatch(shopID string, authUsername string, dataL
 	}, nil
 }
 
+// receiveOrdersInBatch save receipts of purchase orders of purchases which are created by bulk import,
+// receipts of the purchase which fail and of purchases which are saved before are released
+func (svc PurchaseService) receiveOrdersInBatch(ctx context.Context, shopID string, docs []models.PurchaseDoc) error {
+	for idx, doc := range docs {
+		receipt := svc.receiptDocument(shopID, doc.GuidFixed, doc.DocNo, doc.Purchase)
+		if len(receipt.Lines) == 0 {
+			continue
+		}
+
+		err := svc.fulfilment.Receive(ctx, receipt)
+		if err != nil {
+			// orders of a purchase which span several orders can be saved before the error
+			err = fmt.Errorf("%s: %w", doc.DocNo, err)
+			for _, receivedDoc := range docs[:idx+1] {
+				err = svc.releaseReceipts(ctx, receivedDoc, err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseReceipts give back receipts of purchase orders of the purchase which is not saved by err
+func (svc PurchaseService) releaseReceipts(ctx context.Context, doc models.PurchaseDoc, err error) error {
+	if len(svc.receiptDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.Purchase).Lines) == 0 {
+		return err
+	}
+
+	releaseErr := svc.fulfilment.Release(ctx, doc.ShopID, doc.GuidFixed)
+	if releaseErr != nil {
+		return fmt.Errorf("%w, receipts of %s are not released: %v", err, doc.DocNo, releaseErr)
+	}
+	return err
+}
+
+// restoreReceipts give the receipts of the saved purchase back to purchase orders when its change is not saved by err
+func (svc PurchaseService) restoreReceipts(ctx context.Context, doc models.PurchaseDoc, err error) error {
+	// rejected lines are returned before any order is saved
+	lineErr := linereference.Error{}
+	if errors.As(err, &lineErr) {
+		return err
+	}
+
+	restoreErr := svc.fulfilment.Receive(ctx, svc.receiptDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.Purchase))
+	if restoreErr != nil {
+		return fmt.Errorf("%w, receipts of %s are not restored: %v", err, doc.DocNo, restoreErr)
+	}
+	return err
+}
+
+// restoreReceiptsInBatch give the receipts of the saved purchases back to purchase orders when they are not deleted by err
+func (svc PurchaseService) restoreReceiptsInBatch(ctx context.Context, docs []models.PurchaseDoc, err error) error {
+	for _, doc := range docs {
+		err = svc.restoreReceipts(ctx, doc, err)
+	}
+	return err
+}
+
+// receiptDocument return lines of the purchase which reference lines of purchase orders
+func (svc PurchaseService) receiptDocument(shopID string, guid string, docNo string, doc models.Purchase) pomodels.ReceiptDocument {
+	receipt := pomodels.ReceiptDocument{
+		ShopID:        shopID,
+		PurchaseGuid:  guid,
+		PurchaseDocNo: docNo,
+		TaxDocNo:      doc.TaxDocNo,
+		DocDatetime:   doc.DocDatetime,
+		Lines:         []pomodels.ReceiptLine{},
+	}
+
+	if doc.Details == nil {
+		return receipt
+	}
+
+	for _, detail := range *doc.Details {
+		if detail.RefDocGuid == "" {
+			continue
+		}
+
+		receipt.Lines = append(receipt.Lines, pomodels.ReceiptLine{
+			LineNumber:      detail.LineNumber,
+			OrderGuid:       detail.RefDocGuid,
+			OrderLineNumber: detail.RefLineNumber,
+			Barcode:         detail.Barcode,
+			UnitCode:        detail.UnitCode,
+			Qty:             detail.Qty,
+			Price:           detail.Price,
+			SumAmount:       detail.SumAmount,
+		})
+	}
+
+	return receipt
+}
+
 func (svc PurchaseService) getDocIDKey(doc models.Purchase) string {
 	return doc.DocNo
 }
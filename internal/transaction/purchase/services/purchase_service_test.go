@@ -0,0 +1,267 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
+	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/purchase/models"
+	"smlaicloudplatform/internal/transaction/purchase/repositories"
+	"smlaicloudplatform/internal/transaction/purchase/services"
+	pomodels "smlaicloudplatform/internal/transaction/purchaseorder/models"
+	poservices "smlaicloudplatform/internal/transaction/purchaseorder/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPurchase(docNo string, orderGuid string) models.Purchase {
+	doc := models.Purchase{}
+	doc.DocNo = docNo
+	doc.Details = &[]trans_models.Detail{{RefDocGuid: orderGuid, RefLineNumber: 1, Barcode: "A", Qty: 1}}
+	return doc
+}
+
+func TestPurchaseService_CreatePurchaseReleaseReceipts(t *testing.T) {
+	repo := new(PurchaseRepositoryMock)
+	fulfilment := new(PurchaseOrderFulfilmentMock)
+	sequencer := new(DocNoSequencerMock)
+	barcodeRepo := new(ProductBarcodeRepositoryMock)
+
+	sequencer.On("SaveWithDocNo", "shop1", services.MODULE_NAME, "").Return("PU0001", nil)
+	barcodeRepo.On("FindByBarcodes", "shop1", []string{"A"}).Return([]productbarcode_models.ProductBarcodeInfo{}, nil)
+
+	// the first order is saved before the second one conflict
+	fulfilment.On("Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool {
+		return doc.PurchaseDocNo == "PU0001" && doc.Lines[0].LineNumber == 1
+	})).Return(poservices.ErrFulfilmentConflict)
+	fulfilment.On("Release", "shop1", mock.Anything).Return(nil)
+
+	svc := services.NewPurchaseService(repo, sequencer, fulfilment, barcodeRepo, nil, nil, services.PurchaseParser{})
+
+	_, _, err := svc.CreatePurchase(context.Background(), "shop1", "user1", newPurchase("", "po1"))
+
+	assert.ErrorIs(t, err, poservices.ErrFulfilmentConflict)
+	fulfilment.AssertExpectations(t)
+	sequencer.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPurchaseService_SaveInBatchReceiveOrders(t *testing.T) {
+	repo := new(PurchaseRepositoryMock)
+	repo.On("FindInItemGuid", "shop1", "docno", []string{"PU1", "PU2"}).Return([]models.PurchaseItemGuid{}, nil)
+	repo.On("CreateInBatch", mock.MatchedBy(func(docs []models.PurchaseDoc) bool {
+		return len(docs) == 2 && (*docs[0].Details)[0].LineNumber == 1
+	})).Return(nil)
+
+	fulfilment := new(PurchaseOrderFulfilmentMock)
+	fulfilment.On("Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool {
+		return doc.ShopID == "shop1" && doc.PurchaseGuid != "" && doc.Lines[0].OrderLineNumber == 1
+	})).Return(nil).Twice()
+
+	svc := services.NewPurchaseService(repo, nil, fulfilment, nil, nil, nil, services.PurchaseParser{})
+
+	result, err := svc.SaveInBatch(context.Background(), "shop1", "user1", []models.Purchase{newPurchase("PU1", "po1"), newPurchase("PU2", "po2")})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PU1", "PU2"}, result.Created)
+	fulfilment.AssertExpectations(t)
+}
+
+func TestPurchaseService_SaveInBatchReleaseReceipts(t *testing.T) {
+	repo := new(PurchaseRepositoryMock)
+	repo.On("FindInItemGuid", "shop1", "docno", []string{"PU1", "PU2"}).Return([]models.PurchaseItemGuid{}, nil)
+
+	fulfilment := new(PurchaseOrderFulfilmentMock)
+	fulfilment.On("Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool { return doc.PurchaseDocNo == "PU1" })).Return(nil)
+	fulfilment.On("Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool { return doc.PurchaseDocNo == "PU2" })).Return(poservices.ErrFulfilmentConflict)
+
+	// receipts of the purchase which fail and of purchases which are received before it are released
+	fulfilment.On("Release", "shop1", mock.Anything).Return(nil).Twice()
+
+	svc := services.NewPurchaseService(repo, nil, fulfilment, nil, nil, nil, services.PurchaseParser{})
+
+	_, err := svc.SaveInBatch(context.Background(), "shop1", "user1", []models.Purchase{newPurchase("PU1", "po1"), newPurchase("PU2", "po2")})
+
+	assert.ErrorIs(t, err, poservices.ErrFulfilmentConflict)
+	assert.ErrorContains(t, err, "PU2")
+	fulfilment.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateInBatch", mock.Anything)
+
+	// receipts of all purchases are released when they are not created
+	repo = new(PurchaseRepositoryMock)
+	repo.On("FindInItemGuid", "shop1", "docno", []string{"PU1", "PU2"}).Return([]models.PurchaseItemGuid{}, nil)
+	repo.On("CreateInBatch", mock.Anything).Return(errors.New("bulk write failed"))
+
+	fulfilment = new(PurchaseOrderFulfilmentMock)
+	fulfilment.On("Receive", mock.Anything).Return(nil).Twice()
+	fulfilment.On("Release", "shop1", mock.Anything).Return(nil).Twice()
+
+	svc = services.NewPurchaseService(repo, nil, fulfilment, nil, nil, nil, services.PurchaseParser{})
+
+	_, err = svc.SaveInBatch(context.Background(), "shop1", "user1", []models.Purchase{newPurchase("PU1", "po1"), newPurchase("PU2", "po2")})
+
+	assert.EqualError(t, err, "bulk write failed")
+	fulfilment.AssertExpectations(t)
+}
+
+func TestPurchaseService_DeletePurchaseByGUIDsOutbox(t *testing.T) {
+	doc := models.PurchaseDoc{}
+	doc.GuidFixed = "pu1"
+	doc.DocNo = "PU1"
+	doc.Purchase = newPurchase("PU1", "po1")
+
+	repo := new(PurchaseRepositoryMock)
+	repo.docs = []models.PurchaseDoc{doc}
+	repo.On("Delete", "shop1", mock.Anything).Return(nil)
+
+	fulfilment := new(PurchaseOrderFulfilmentMock)
+	fulfilment.On("Release", "shop1", "pu1").Return(nil)
+
+	repoMq := new(PurchaseMessageQueueRepositoryMock)
+	repoMq.On("OutboxDeleteInBatch", mock.Anything).Return(nil)
+
+	svc := services.NewPurchaseService(repo, nil, fulfilment, nil, repoMq, nil, services.PurchaseParser{})
+
+	err := svc.DeletePurchaseByGUIDs(context.Background(), "shop1", "user1", []string{"pu1"})
+
+	require.NoError(t, err)
+	repoMq.AssertCalled(t, "OutboxDeleteInBatch", mock.MatchedBy(func(docs []models.PurchaseDoc) bool {
+		return len(docs) == 1 && docs[0].GuidFixed == "pu1"
+	}))
+}
+
+func TestPurchaseService_DeletePurchaseByGUIDsRestoreReceipts(t *testing.T) {
+	docs := []models.PurchaseDoc{}
+	for _, guid := range []string{"pu1", "pu2"} {
+		doc := models.PurchaseDoc{}
+		doc.ShopID = "shop1"
+		doc.GuidFixed = guid
+		doc.DocNo = strings.ToUpper(guid)
+		doc.Purchase = newPurchase(doc.DocNo, "po1")
+		docs = append(docs, doc)
+	}
+
+	repo := new(PurchaseRepositoryMock)
+	repo.docs = docs
+
+	fulfilment := new(PurchaseOrderFulfilmentMock)
+	fulfilment.On("Release", "shop1", "pu1").Return(nil)
+	fulfilment.On("Release", "shop1", "pu2").Return(poservices.ErrFulfilmentConflict)
+
+	// receipts of the purchase which is released before the error are given back
+	fulfilment.On("Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool {
+		return doc.PurchaseGuid == "pu1"
+	})).Return(errors.New("order is closed")).Once()
+
+	svc := services.NewPurchaseService(repo, nil, fulfilment, nil, nil, nil, services.PurchaseParser{})
+
+	err := svc.DeletePurchaseByGUIDs(context.Background(), "shop1", "user1", []string{"pu1", "pu2"})
+
+	assert.ErrorIs(t, err, poservices.ErrFulfilmentConflict)
+	assert.ErrorContains(t, err, "receipts of PU1 are not restored: order is closed")
+	fulfilment.AssertExpectations(t)
+	fulfilment.AssertNotCalled(t, "Receive", mock.MatchedBy(func(doc pomodels.ReceiptDocument) bool {
+		return doc.PurchaseGuid == "pu2"
+	}))
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+type PurchaseRepositoryMock struct {
+	repositories.IPurchaseRepository
+	mock.Mock
+	// docs are found by FindByGuids until they are deleted
+	docs    []models.PurchaseDoc
+	deleted bool
+}
+
+func (m *PurchaseRepositoryMock) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return queryFunc(ctx)
+}
+
+func (m *PurchaseRepositoryMock) FindByGuids(ctx context.Context, shopID string, guids []string) ([]models.PurchaseDoc, error) {
+	if m.deleted {
+		return []models.PurchaseDoc{}, nil
+	}
+	return m.docs, nil
+}
+
+func (m *PurchaseRepositoryMock) Delete(ctx context.Context, shopID string, username string, filters map[string]interface{}) error {
+	args := m.Called(shopID, filters)
+	m.deleted = args.Error(0) == nil
+	return args.Error(0)
+}
+
+func (m *PurchaseRepositoryMock) Create(ctx context.Context, doc models.PurchaseDoc) (string, error) {
+	args := m.Called(doc)
+	return args.String(0), args.Error(1)
+}
+
+func (m *PurchaseRepositoryMock) CreateInBatch(ctx context.Context, docList []models.PurchaseDoc) error {
+	args := m.Called(docList)
+	return args.Error(0)
+}
+
+func (m *PurchaseRepositoryMock) FindInItemGuid(ctx context.Context, shopID string, columnName string, itemGuidList []string) ([]models.PurchaseItemGuid, error) {
+	args := m.Called(shopID, columnName, itemGuidList)
+	return args.Get(0).([]models.PurchaseItemGuid), args.Error(1)
+}
+
+type PurchaseOrderFulfilmentMock struct {
+	poservices.IPurchaseOrderFulfilment
+	mock.Mock
+}
+
+func (m *PurchaseOrderFulfilmentMock) Receive(ctx context.Context, doc pomodels.ReceiptDocument) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
+
+func (m *PurchaseOrderFulfilmentMock) Release(ctx context.Context, shopID string, purchaseGuid string) error {
+	args := m.Called(shopID, purchaseGuid)
+	return args.Error(0)
+}
+
+type PurchaseMessageQueueRepositoryMock struct {
+	repositories.IPurchaseMessageQueueRepository
+	mock.Mock
+}
+
+func (m *PurchaseMessageQueueRepositoryMock) OutboxDeleteInBatch(ctx context.Context, docList []models.PurchaseDoc) error {
+	args := m.Called(docList)
+	return args.Error(0)
+}
+
+type DocNoSequencerMock struct {
+	docsequence.IDocNoSequencer
+	mock.Mock
+}
+
+func (m *DocNoSequencerMock) SaveWithDocNo(ctx context.Context, shopID string, docCode string, docDate time.Time, reservedBy string, reservedDocNo string, save func(docNo string) error) (string, error) {
+	args := m.Called(shopID, docCode, reservedDocNo)
+	if args.Error(1) != nil {
+		return "", args.Error(1)
+	}
+
+	docNo := args.String(0)
+	if err := save(docNo); err != nil {
+		return "", err
+	}
+	return docNo, nil
+}
+
+type ProductBarcodeRepositoryMock struct {
+	productbarcode_repositories.IProductBarcodeRepository
+	mock.Mock
+}
+
+func (m *ProductBarcodeRepositoryMock) FindByBarcodes(ctx context.Context, shopID string, barcodes []string) ([]productbarcode_models.ProductBarcodeInfo, error) {
+	args := m.Called(shopID, barcodes)
+	return args.Get(0).([]productbarcode_models.ProductBarcodeInfo), args.Error(1)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"smlaicloudplatform/internal/models"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
 	transmodels "smlaicloudplatform/internal/transaction/models"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,8 +11,10 @@ import (
 const purchaseorderCollectionName = "transactionPurchaseOrder"
 
 type PurchaseOrder struct {
-	models.PartitionIdentity `bson:"inline"`
-	transmodels.Transaction  `bson:"inline"`
+	models.PartitionIdentity   `bson:"inline"`
+	transmodels.Transaction    `bson:"inline"`
+	approvalmodels.DocApproval `bson:"inline"`
+	FulfilmentStatus           string `json:"fulfilmentstatus" bson:"fulfilmentstatus"`
 }
 type PurchaseOrderInfo struct {
 	models.DocIdentity `bson:"inline"`
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	purchaseOrderFulfilmentCollectionName        = "purchaseOrderFulfilments"
+	purchaseOrderFulfilmentSettingCollectionName = "purchaseOrderFulfilmentSettings"
+)
+
+// fulfilment status of purchase order, order which is saved before fulfilment is tracked has empty status and it is open
+const (
+	FulfilmentStatusOpen    = "open"
+	FulfilmentStatusPartial = "partial"
+	FulfilmentStatusClosed  = "closed"
+)
+
+// variance flags of the line of matching report
+const (
+	VarianceUnderReceived = "underreceived"
+	VarianceOverReceived  = "overreceived"
+	VariancePrice         = "price"
+	VarianceAmount        = "amount"
+)
+
+// PurchaseOrderReceipt is line of purchase which receive the line of purchase order,
+// purchase carry the supplier invoice so its price and amount are the invoiced price and amount
+type PurchaseOrderReceipt struct {
+	PurchaseGuid    string    `json:"purchaseguid" bson:"purchaseguid"`
+	PurchaseDocNo   string    `json:"purchasedocno" bson:"purchasedocno"`
+	TaxDocNo        string    `json:"taxdocno" bson:"taxdocno"`
+	DocDatetime     time.Time `json:"docdatetime" bson:"docdatetime"`
+	LineNumber      int       `json:"linenumber" bson:"linenumber"`
+	OrderLineNumber int       `json:"orderlinenumber" bson:"orderlinenumber"`
+	Qty             float64   `json:"qty" bson:"qty"`
+	Price           float64   `json:"price" bson:"price"`
+	SumAmount       float64   `json:"sumamount" bson:"sumamount"`
+}
+
+func (r PurchaseOrderReceipt) DocGuid() string {
+	return r.PurchaseGuid
+}
+
+func (r PurchaseOrderReceipt) ReferencedLineNumber() int {
+	return r.OrderLineNumber
+}
+
+func (r PurchaseOrderReceipt) ReferencedQty() float64 {
+	return r.Qty
+}
+
+// PurchaseOrderFulfilment keep receipts of purchase order, version is increased on every change
+// so concurrent receipts of the same order can not both take the remaining quantity
+type PurchaseOrderFulfilment struct {
+	ID         primitive.ObjectID     `json:"-" bson:"_id,omitempty"`
+	ShopID     string                 `json:"shopid" bson:"shopid"`
+	OrderGuid  string                 `json:"orderguid" bson:"orderguid"`
+	OrderDocNo string                 `json:"orderdocno" bson:"orderdocno"`
+	Receipts   []PurchaseOrderReceipt `json:"receipts" bson:"receipts"`
+	Version    int64                  `json:"version" bson:"version"`
+	UpdatedAt  time.Time              `json:"updatedat" bson:"updatedat"`
+}
+
+func (PurchaseOrderFulfilment) CollectionName() string {
+	return purchaseOrderFulfilmentCollectionName
+}
+
+// FulfilmentLine is ordered, received and remaining quantity of the line of purchase order
+type FulfilmentLine struct {
+	LineNumber   int                    `json:"linenumber"`
+	Barcode      string                 `json:"barcode"`
+	ItemCode     string                 `json:"itemcode"`
+	ItemNames    *[]models.NameX        `json:"itemnames"`
+	UnitCode     string                 `json:"unitcode"`
+	OrderQty     float64                `json:"orderqty"`
+	Price        float64                `json:"price"`
+	ReceivedQty  float64                `json:"receivedqty"`
+	RemainingQty float64                `json:"remainingqty"`
+	Receipts     []PurchaseOrderReceipt `json:"receipts"`
+}
+
+type PurchaseOrderFulfilmentInfo struct {
+	OrderGuid   string           `json:"orderguid"`
+	OrderDocNo  string           `json:"orderdocno"`
+	DocDatetime time.Time        `json:"docdatetime"`
+	CustCode    string           `json:"custcode"`
+	Status      string           `json:"status"`
+	Lines       []FulfilmentLine `json:"lines"`
+}
+
+// FulfilmentSetting is tolerance of the shop, quantity can be received over the ordered quantity
+// by OverReceiptPercent and invoiced price can differ from the ordered price by PriceVariancePercent
+type FulfilmentSetting struct {
+	OverReceiptPercent   float64 `json:"overreceiptpercent" bson:"overreceiptpercent" validate:"min=0,max=100"`
+	PriceVariancePercent float64 `json:"pricevariancepercent" bson:"pricevariancepercent" validate:"min=0,max=100"`
+}
+
+type FulfilmentSettingDoc struct {
+	ID                primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ShopID            string             `json:"shopid" bson:"shopid"`
+	FulfilmentSetting `bson:"inline"`
+	UpdatedBy         string    `json:"updatedby" bson:"updatedby"`
+	UpdatedAt         time.Time `json:"updatedat" bson:"updatedat"`
+}
+
+func (FulfilmentSettingDoc) CollectionName() string {
+	return purchaseOrderFulfilmentSettingCollectionName
+}
+
+// ReceiptDocument is purchase which receive lines of purchase orders
+type ReceiptDocument struct {
+	ShopID        string
+	PurchaseGuid  string
+	PurchaseDocNo string
+	TaxDocNo      string
+	DocDatetime   time.Time
+	Lines         []ReceiptLine
+}
+
+// ReceiptLine is line of purchase which reference the line of purchase order
+type ReceiptLine struct {
+	LineNumber      int
+	OrderGuid       string
+	OrderLineNumber int
+	Barcode         string
+	UnitCode        string
+	Qty             float64
+	Price           float64
+	SumAmount       float64
+}
+
+// MatchingLine compare ordered price and quantity of the line of purchase order with
+// received quantity and invoiced amount of purchases which reference the line
+type MatchingLine struct {
+	OrderGuid      string    `json:"orderguid"`
+	OrderDocNo     string    `json:"orderdocno"`
+	DocDatetime    time.Time `json:"docdatetime"`
+	CustCode       string    `json:"custcode"`
+	Status         string    `json:"status"`
+	LineNumber     int       `json:"linenumber"`
+	Barcode        string    `json:"barcode"`
+	ItemCode       string    `json:"itemcode"`
+	UnitCode       string    `json:"unitcode"`
+	OrderQty       float64   `json:"orderqty"`
+	OrderPrice     float64   `json:"orderprice"`
+	ReceivedQty    float64   `json:"receivedqty"`
+	InvoicePrice   float64   `json:"invoiceprice"`
+	InvoiceAmount  float64   `json:"invoiceamount"`
+	ExpectedAmount float64   `json:"expectedamount"`
+	QtyVariance    float64   `json:"qtyvariance"`
+	PriceVariance  float64   `json:"pricevariance"`
+	AmountVariance float64   `json:"amountvariance"`
+	InvoiceDocNos  []string  `json:"invoicedocnos"`
+	Flags          []string  `json:"flags"`
+	IsMatched      bool      `json:"ismatched"`
+}
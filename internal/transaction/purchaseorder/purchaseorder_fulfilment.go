@@ -0,0 +1,42 @@
+package purchaseorder
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/linereference"
+	"smlaicloudplatform/internal/transaction/purchaseorder/repositories"
+	"smlaicloudplatform/internal/transaction/purchaseorder/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// InitPurchaseOrderFulfilment return fulfilment of purchase orders which is received by purchases
+func InitPurchaseOrderFulfilment(ms *microservice.Microservice, cfg config.IConfig) services.IPurchaseOrderFulfilment {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewPurchaseOrderFulfilment(
+		repositories.NewPurchaseOrderRepository(pst),
+		repositories.NewPurchaseOrderFulfilmentRepository(pst),
+		repositories.NewFulfilmentSettingRepository(pst),
+		ms.TimeNow,
+	)
+}
+
+// ResponseFulfilmentError write response of error of fulfilment of purchase order, errors of lines are sent in data
+func ResponseFulfilmentError(ctx microservice.IContext, err error) {
+	lineErr := linereference.Error{}
+
+	switch {
+	case errors.As(err, &lineErr):
+		ctx.Response(http.StatusBadRequest, common.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+			Data:    lineErr.Lines,
+		})
+	case errors.Is(err, services.ErrFulfilmentConflict), errors.Is(err, services.ErrFulfilmentHasReceipts):
+		ctx.ResponseError(http.StatusConflict, err.Error())
+	default:
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/transaction/approval"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/purchaseorder/models"
 	"smlaicloudplatform/internal/transaction/purchaseorder/repositories"
 	"smlaicloudplatform/internal/transaction/purchaseorder/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -29,11 +32,13 @@ func NewPurchaseOrderHttp(ms *microservice.Microservice, cfg config.IConfig) Pur
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewPurchaseOrderRepository(pst)
-	repoMq := repositories.NewPurchaseOrderMessageQueueRepository(producer)
+	repoMq := repositories.NewPurchaseOrderOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewPurchaseOrderHttpService(repo, transRepo, repoMq, masterSyncCacheRepo)
+	approvalWorkflow := approval.InitApprovalWorkflow(ms, cfg)
+	fulfilment := InitPurchaseOrderFulfilment(ms, cfg)
+	svc := services.NewPurchaseOrderHttpService(repo, docNoSequencer, approvalWorkflow, fulfilment, repoMq, masterSyncCacheRepo)
 
 	return PurchaseOrderHttp{
 		ms:  ms,
@@ -54,6 +59,13 @@ func (h PurchaseOrderHttp) RegisterHttp() {
 	h.ms.PUT("/transaction/purchase-order/:id", h.UpdatePurchaseOrder)
 	h.ms.DELETE("/transaction/purchase-order/:id", h.DeletePurchaseOrder)
 	h.ms.DELETE("/transaction/purchase-order", h.DeletePurchaseOrderByGUIDs)
+
+	approval.NewDocumentApprovalHttp(h.ms, h.svc.Approval()).RegisterHttp("/transaction/purchase-order")
+
+	h.ms.GET("/transaction/purchase-order/:id/fulfilment", h.InfoPurchaseOrderFulfilment)
+	h.ms.GET("/transaction/purchase-order/matching", h.SearchPurchaseOrderMatching)
+	h.ms.GET("/transaction/purchase-order/fulfilment-setting", h.InfoFulfilmentSetting)
+	h.ms.PUT("/transaction/purchase-order/fulfilment-setting", h.SaveFulfilmentSetting, h.ms.RequirePermission(rbacmodels.PermissionPurchaseOrderSettingUpdate))
 }
 
 // Create PurchaseOrder godoc
@@ -83,7 +95,7 @@ func (h PurchaseOrderHttp) CreatePurchaseOrder(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreatePurchaseOrder(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreatePurchaseOrder(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -129,10 +141,10 @@ func (h PurchaseOrderHttp) UpdatePurchaseOrder(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdatePurchaseOrder(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePurchaseOrder(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -160,10 +172,10 @@ func (h PurchaseOrderHttp) DeletePurchaseOrder(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePurchaseOrder(shopID, id, authUsername)
+	err := h.svc.DeletePurchaseOrder(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -199,10 +211,10 @@ func (h PurchaseOrderHttp) DeletePurchaseOrderByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeletePurchaseOrderByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePurchaseOrderByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		ResponseFulfilmentError(ctx, err)
 		return err
 	}
 
@@ -229,7 +241,7 @@ func (h PurchaseOrderHttp) InfoPurchaseOrder(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get PurchaseOrder %v", id)
-	doc, err := h.svc.InfoPurchaseOrder(shopID, id)
+	doc, err := h.svc.InfoPurchaseOrder(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -259,7 +271,7 @@ func (h PurchaseOrderHttp) InfoPurchaseOrderByCode(ctx microservice.IContext) er
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPurchaseOrderByCode(shopID, code)
+	doc, err := h.svc.InfoPurchaseOrderByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -311,7 +323,7 @@ func (h PurchaseOrderHttp) SearchPurchaseOrderPage(ctx microservice.IContext) er
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchPurchaseOrder(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchPurchaseOrder(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -367,7 +379,7 @@ func (h PurchaseOrderHttp) SearchPurchaseOrderStep(ctx microservice.IContext) er
 		},
 	})
 
-	docList, total, err := h.svc.SearchPurchaseOrderStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPurchaseOrderStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -407,7 +419,7 @@ func (h PurchaseOrderHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -424,3 +436,148 @@ func (h PurchaseOrderHttp) SaveBulk(ctx microservice.IContext) error {
 
 	return nil
 }
+
+// Get PurchaseOrder Fulfilment godoc
+// @Description get ordered, received and remaining quantity of lines of PurchaseOrder with purchases which receive them
+// @Tags		PurchaseOrder
+// @Param		id  path      string  true  "PurchaseOrder ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/{id}/fulfilment [get]
+func (h PurchaseOrderHttp) InfoPurchaseOrderFulfilment(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoPurchaseOrderFulfilment(ctx.Context(), shopID, id)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List PurchaseOrder Matching godoc
+// @Description three-way matching of lines of PurchaseOrder with received quantity and supplier invoice of purchases, page is page of PurchaseOrder
+// @Tags		PurchaseOrder
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		fulfilmentstatus	query	string		false  "open, partial or closed"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		varianceonly	query	boolean		false  "only lines with variance"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/matching [get]
+func (h PurchaseOrderHttp) SearchPurchaseOrderMatching(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "custcode",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "fulfilmentstatus",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "docdatetime",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+	})
+
+	varianceOnly := ctx.QueryParam("varianceonly") == "true"
+
+	docList, pagination, err := h.svc.SearchPurchaseOrderMatching(ctx.Context(), shopID, filters, pageable, varianceOnly)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Get PurchaseOrder Fulfilment Setting godoc
+// @Description get over-receipt and price variance tolerance of the shop
+// @Tags		PurchaseOrder
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/fulfilment-setting [get]
+func (h PurchaseOrderHttp) InfoFulfilmentSetting(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	doc, err := h.svc.InfoFulfilmentSetting(ctx.Context(), shopID)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Update PurchaseOrder Fulfilment Setting godoc
+// @Description update over-receipt and price variance tolerance of the shop
+// @Tags		PurchaseOrder
+// @Param		FulfilmentSetting  body      models.FulfilmentSetting  true  "FulfilmentSetting"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-order/fulfilment-setting [put]
+func (h PurchaseOrderHttp) SaveFulfilmentSetting(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	docReq := &models.FulfilmentSetting{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.svc.SaveFulfilmentSetting(ctx.Context(), userInfo.ShopID, userInfo.Username, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+	return nil
+}
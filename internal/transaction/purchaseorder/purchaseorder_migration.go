@@ -0,0 +1,48 @@
+package purchaseorder
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/purchaseorder/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of fulfilments of purchase orders, unique index keep one fulfilment per order
+// and tolerance setting per shop
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	fulfilmentCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.PurchaseOrderFulfilment{})
+	if err != nil {
+		return err
+	}
+
+	_, err = fulfilmentCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "orderguid", Value: 1}},
+			Options: options.Index().SetName("purchaseorderfulfilment_shopid_orderguid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "receipts.purchaseguid", Value: 1}},
+			Options: options.Index().SetName("purchaseorderfulfilment_shopid_purchaseguid"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	settingCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.FulfilmentSettingDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = settingCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "shopid", Value: 1}},
+		Options: options.Index().SetName("purchaseorderfulfilmentsetting_shopid").SetUnique(true),
+	})
+	return err
+}
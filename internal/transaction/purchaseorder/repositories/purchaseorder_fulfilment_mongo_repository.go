@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/purchaseorder/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPurchaseOrderFulfilmentRepository interface {
+	FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.PurchaseOrderFulfilment, error)
+	FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.PurchaseOrderFulfilment, error)
+	FindByPurchase(ctx context.Context, shopID string, purchaseGuid string) ([]models.PurchaseOrderFulfilment, error)
+	// Save replace fulfilment of the order when it is not changed since it is read by its version,
+	// false is returned when another receipt saved the order first
+	Save(ctx context.Context, doc models.PurchaseOrderFulfilment) (bool, error)
+	DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error
+}
+
+type PurchaseOrderFulfilmentRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewPurchaseOrderFulfilmentRepository(pst microservice.IPersisterMongo) *PurchaseOrderFulfilmentRepository {
+	return &PurchaseOrderFulfilmentRepository{
+		pst: pst,
+	}
+}
+
+func (repo PurchaseOrderFulfilmentRepository) FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.PurchaseOrderFulfilment, error) {
+	doc := models.PurchaseOrderFulfilment{}
+	err := repo.pst.FindOne(ctx, &models.PurchaseOrderFulfilment{}, bson.M{"shopid": shopID, "orderguid": orderGuid}, &doc)
+	if err != nil {
+		return models.PurchaseOrderFulfilment{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo PurchaseOrderFulfilmentRepository) FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.PurchaseOrderFulfilment, error) {
+	docList := []models.PurchaseOrderFulfilment{}
+	err := repo.pst.Find(ctx, &models.PurchaseOrderFulfilment{}, bson.M{"shopid": shopID, "orderguid": bson.M{"$in": orderGuids}}, &docList)
+	if err != nil {
+		return []models.PurchaseOrderFulfilment{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo PurchaseOrderFulfilmentRepository) FindByPurchase(ctx context.Context, shopID string, purchaseGuid string) ([]models.PurchaseOrderFulfilment, error) {
+	docList := []models.PurchaseOrderFulfilment{}
+	err := repo.pst.Find(ctx, &models.PurchaseOrderFulfilment{}, bson.M{"shopid": shopID, "receipts.purchaseguid": purchaseGuid}, &docList)
+	if err != nil {
+		return []models.PurchaseOrderFulfilment{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo PurchaseOrderFulfilmentRepository) Save(ctx context.Context, doc models.PurchaseOrderFulfilment) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.PurchaseOrderFulfilment{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":    doc.ShopID,
+		"orderguid": doc.OrderGuid,
+		"version":   doc.Version,
+	}
+
+	doc.ID = primitive.NilObjectID
+	doc.Version++
+
+	// the first receipt insert fulfilment, unique index reject the other one of concurrent first receipts
+	result, err := collection.ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(doc.Version == 1))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+func (repo PurchaseOrderFulfilmentRepository) DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error {
+	return repo.pst.Delete(ctx, &models.PurchaseOrderFulfilment{}, bson.M{"shopid": shopID, "orderguid": orderGuid})
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/purchaseorder/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IFulfilmentSettingRepository interface {
+	FindByShopID(ctx context.Context, shopID string) (models.FulfilmentSettingDoc, error)
+	Save(ctx context.Context, doc models.FulfilmentSettingDoc) error
+}
+
+type FulfilmentSettingRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewFulfilmentSettingRepository(pst microservice.IPersisterMongo) *FulfilmentSettingRepository {
+	return &FulfilmentSettingRepository{
+		pst: pst,
+	}
+}
+
+func (repo FulfilmentSettingRepository) FindByShopID(ctx context.Context, shopID string) (models.FulfilmentSettingDoc, error) {
+	doc := models.FulfilmentSettingDoc{}
+	err := repo.pst.FindOne(ctx, &models.FulfilmentSettingDoc{}, bson.M{"shopid": shopID}, &doc)
+	if err != nil {
+		return models.FulfilmentSettingDoc{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo FulfilmentSettingRepository) Save(ctx context.Context, doc models.FulfilmentSettingDoc) error {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.FulfilmentSettingDoc{})
+	if err != nil {
+		return err
+	}
+
+	doc.ID = primitive.NilObjectID
+	_, err = collection.ReplaceOne(ctx, bson.M{"shopid": doc.ShopID}, doc, options.Replace().SetUpsert(true))
+	return err
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/purchaseorder/config"
 	"smlaicloudplatform/internal/transaction/purchaseorder/models"
@@ -14,6 +15,11 @@ type IPurchaseOrderMessageQueueRepository interface {
 	CreateInBatch(docList []models.PurchaseOrderDoc) error
 	UpdateInBatch(docList []models.PurchaseOrderDoc) error
 	DeleteInBatch(docList []models.PurchaseOrderDoc) error
+	OutboxCreate(ctx context.Context, doc models.PurchaseOrderDoc) error
+	OutboxUpdate(ctx context.Context, doc models.PurchaseOrderDoc) error
+	OutboxDelete(ctx context.Context, doc models.PurchaseOrderDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.PurchaseOrderDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.PurchaseOrderDoc) error
 }
 
 type PurchaseOrderMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewPurchaseOrderMessageQueueRepository(prod microservice.IProducer) Purchas
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PurchaseOrderDoc](prod, config.PurchaseOrderMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewPurchaseOrderOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewPurchaseOrderOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) PurchaseOrderMessageQueueRepository {
+	insRepo := NewPurchaseOrderMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,9 @@ type IPurchaseOrderRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseOrderActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseOrderDoc, error)
+	// UpdateFulfilmentStatus change fulfilment status of the order when it is still fromStatus, false is returned when it is changed by another request
+	UpdateFulfilmentStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type PurchaseOrderRepository struct {
@@ -58,6 +61,10 @@ func NewPurchaseOrderRepository(pst microservice.IPersisterMongo) *PurchaseOrder
 
 	return insRepo
 }
+
+func (repo PurchaseOrderRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
 func (repo PurchaseOrderRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseOrderDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -83,3 +90,29 @@ func (repo PurchaseOrderRepository) FindLastDocNo(ctx context.Context, shopID st
 
 	return doc, nil
 }
+
+func (repo PurchaseOrderRepository) UpdateFulfilmentStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.PurchaseOrderDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":           shopID,
+		"guidfixed":        guid,
+		"deletedat":        bson.M{"$exists": false},
+		"fulfilmentstatus": fromStatus,
+	}
+
+	// orders which are saved before fulfilment is tracked have no fulfilment status
+	if fromStatus == "" {
+		filter["fulfilmentstatus"] = bson.M{"$in": bson.A{"", nil}}
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"fulfilmentstatus": toStatus}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,528 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/purchaseorder/models"
+	"smlaicloudplatform/internal/transaction/purchaseorder/repositories"
+	"time"
+)
+
+var (
+	ErrFulfilmentOrderNotFound = errors.New("purchase order not found")
+	ErrFulfilmentHasReceipts   = errors.New("purchase order is received by purchases and can not be deleted")
+	ErrFulfilmentConflict      = errors.New("purchase order is received by another purchase at the same time, please try again")
+)
+
+type IPurchaseOrderFulfilment interface {
+	// Receive replace receipts of the purchase by its lines which reference lines of purchase orders,
+	// nothing is saved when any line can not be received, other error can be returned after some of the orders
+	// are saved so the caller should release or restore the receipts of the purchase
+	Receive(ctx context.Context, doc models.ReceiptDocument) error
+	// Release remove receipts of the purchase from purchase orders
+	Release(ctx context.Context, shopID string, purchaseGuid string) error
+	// CheckOrder return fulfilment status of changed lines of the order, error is returned
+	// when received lines are removed, changed to another barcode or ordered less than received
+	CheckOrder(ctx context.Context, order models.PurchaseOrderDoc, details []trans_models.Detail) (string, error)
+	// CheckOrderDelete return ErrFulfilmentHasReceipts when the order is received
+	CheckOrderDelete(ctx context.Context, shopID string, orderGuid string) error
+	RemoveOrder(ctx context.Context, shopID string, orderGuid string) error
+	Info(ctx context.Context, order models.PurchaseOrderDoc) (models.PurchaseOrderFulfilmentInfo, error)
+	Matching(ctx context.Context, shopID string, orders []models.PurchaseOrderInfo, varianceOnly bool) ([]models.MatchingLine, error)
+	Setting(ctx context.Context, shopID string) (models.FulfilmentSetting, error)
+	SaveSetting(ctx context.Context, shopID string, authUsername string, setting models.FulfilmentSetting) error
+}
+
+type PurchaseOrderFulfilment struct {
+	orderRepo   repositories.IPurchaseOrderRepository
+	repo        repositories.IPurchaseOrderFulfilmentRepository
+	settingRepo repositories.IFulfilmentSettingRepository
+	timeNow     func() time.Time
+}
+
+func NewPurchaseOrderFulfilment(
+	orderRepo repositories.IPurchaseOrderRepository,
+	repo repositories.IPurchaseOrderFulfilmentRepository,
+	settingRepo repositories.IFulfilmentSettingRepository,
+	timeNow func() time.Time,
+) *PurchaseOrderFulfilment {
+	return &PurchaseOrderFulfilment{
+		orderRepo:   orderRepo,
+		repo:        repo,
+		settingRepo: settingRepo,
+		timeNow:     timeNow,
+	}
+}
+
+// FulfilmentStatus return open when nothing is received, closed when every line is received
+// at least its ordered quantity and partial otherwise
+func FulfilmentStatus(details []trans_models.Detail, receipts []models.PurchaseOrderReceipt) string {
+	if len(receipts) == 0 {
+		return models.FulfilmentStatusOpen
+	}
+
+	received := linereference.ReferencedQty(receipts)
+	for _, detail := range details {
+		if received[detail.LineNumber] < detail.Qty-linereference.QtyPrecision {
+			return models.FulfilmentStatusPartial
+		}
+	}
+
+	return models.FulfilmentStatusClosed
+}
+
+// FulfilmentLines return received and remaining quantity of lines of the order
+func FulfilmentLines(details []trans_models.Detail, receipts []models.PurchaseOrderReceipt) []models.FulfilmentLine {
+	lines := []models.FulfilmentLine{}
+	for _, detail := range details {
+		line := models.FulfilmentLine{
+			LineNumber: detail.LineNumber,
+			Barcode:    detail.Barcode,
+			ItemCode:   detail.ItemCode,
+			ItemNames:  detail.ItemNames,
+			UnitCode:   detail.UnitCode,
+			OrderQty:   detail.Qty,
+			Price:      detail.Price,
+			Receipts:   []models.PurchaseOrderReceipt{},
+		}
+
+		for _, receipt := range receipts {
+			if receipt.OrderLineNumber == detail.LineNumber {
+				line.ReceivedQty += receipt.Qty
+				line.Receipts = append(line.Receipts, receipt)
+			}
+		}
+
+		line.RemainingQty = math.Max(detail.Qty-line.ReceivedQty, 0)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func exceeds(diff float64, base float64, percent float64) bool {
+	return math.Abs(diff) > math.Abs(base)*percent/100+linereference.QtyPrecision
+}
+
+// MatchLines compare ordered price and quantity of lines of the order with received quantity and invoiced amount
+// of purchases, expected amount is the ordered amount of the line in proportion of received quantity
+func MatchLines(order models.PurchaseOrderInfo, receipts []models.PurchaseOrderReceipt, setting models.FulfilmentSetting) []models.MatchingLine {
+	details := linereference.Lines(order.Details)
+	status := FulfilmentStatus(details, receipts)
+
+	result := []models.MatchingLine{}
+	for _, line := range FulfilmentLines(details, receipts) {
+		orderLine, _ := linereference.FindLine(details, line.LineNumber)
+
+		matching := models.MatchingLine{
+			OrderGuid:     order.GuidFixed,
+			OrderDocNo:    order.DocNo,
+			DocDatetime:   order.DocDatetime,
+			CustCode:      order.CustCode,
+			Status:        status,
+			LineNumber:    line.LineNumber,
+			Barcode:       line.Barcode,
+			ItemCode:      line.ItemCode,
+			UnitCode:      line.UnitCode,
+			OrderQty:      line.OrderQty,
+			OrderPrice:    line.Price,
+			ReceivedQty:   line.ReceivedQty,
+			InvoiceDocNos: []string{},
+			Flags:         []string{},
+		}
+
+		invoiceValue := 0.0
+		for _, receipt := range line.Receipts {
+			invoiceValue += receipt.Price * receipt.Qty
+			matching.InvoiceAmount += receipt.SumAmount
+
+			invoiceDocNo := receipt.TaxDocNo
+			if invoiceDocNo == "" {
+				invoiceDocNo = receipt.PurchaseDocNo
+			}
+			matching.InvoiceDocNos = append(matching.InvoiceDocNos, invoiceDocNo)
+		}
+
+		if line.ReceivedQty > linereference.QtyPrecision {
+			matching.InvoicePrice = invoiceValue / line.ReceivedQty
+			matching.PriceVariance = matching.InvoicePrice - matching.OrderPrice
+		}
+
+		if line.OrderQty > linereference.QtyPrecision {
+			matching.ExpectedAmount = orderLine.SumAmount * line.ReceivedQty / line.OrderQty
+		}
+
+		matching.QtyVariance = line.ReceivedQty - line.OrderQty
+		matching.AmountVariance = matching.InvoiceAmount - matching.ExpectedAmount
+
+		if line.ReceivedQty > linereference.QtyPrecision {
+			if matching.QtyVariance < -linereference.QtyPrecision {
+				matching.Flags = append(matching.Flags, models.VarianceUnderReceived)
+			}
+
+			if matching.QtyVariance > linereference.QtyPrecision {
+				matching.Flags = append(matching.Flags, models.VarianceOverReceived)
+			}
+
+			if exceeds(matching.PriceVariance, matching.OrderPrice, setting.PriceVariancePercent) {
+				matching.Flags = append(matching.Flags, models.VariancePrice)
+			}
+
+			if exceeds(matching.AmountVariance, matching.ExpectedAmount, setting.PriceVariancePercent) {
+				matching.Flags = append(matching.Flags, models.VarianceAmount)
+			}
+
+			matching.IsMatched = len(matching.Flags) == 0
+		}
+
+		result = append(result, matching)
+	}
+
+	return result
+}
+
+func (svc PurchaseOrderFulfilment) Receive(ctx context.Context, doc models.ReceiptDocument) error {
+	return linereference.Retry(ErrFulfilmentConflict, func() error {
+		return svc.receive(ctx, doc)
+	})
+}
+
+func (svc PurchaseOrderFulfilment) receive(ctx context.Context, doc models.ReceiptDocument) error {
+	setting, err := svc.Setting(ctx, doc.ShopID)
+	if err != nil {
+		return err
+	}
+
+	// orders which are received by the purchase before are saved without its receipts when it does not reference them anymore
+	previousList, err := svc.repo.FindByPurchase(ctx, doc.ShopID, doc.PurchaseGuid)
+	if err != nil {
+		return err
+	}
+
+	fulfilments := map[string]models.PurchaseOrderFulfilment{}
+	orderGuids := []string{}
+	for _, fulfilment := range previousList {
+		fulfilments[fulfilment.OrderGuid] = fulfilment
+		orderGuids = append(orderGuids, fulfilment.OrderGuid)
+	}
+
+	linesByOrder := map[string][]models.ReceiptLine{}
+	for _, line := range doc.Lines {
+		if _, ok := linesByOrder[line.OrderGuid]; !ok {
+			if _, ok := fulfilments[line.OrderGuid]; !ok {
+				orderGuids = append(orderGuids, line.OrderGuid)
+			}
+		}
+		linesByOrder[line.OrderGuid] = append(linesByOrder[line.OrderGuid], line)
+	}
+
+	lineErrors := []linereference.LineError{}
+	orders := map[string]models.PurchaseOrderDoc{}
+
+	for _, orderGuid := range orderGuids {
+		fulfilment, ok := fulfilments[orderGuid]
+		if !ok {
+			fulfilment, err = svc.repo.FindByOrder(ctx, doc.ShopID, orderGuid)
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err := svc.orderRepo.FindByGuid(ctx, doc.ShopID, orderGuid)
+		if err != nil {
+			return err
+		}
+
+		lines := linesByOrder[orderGuid]
+
+		if len(order.GuidFixed) < 1 {
+			for _, line := range lines {
+				lineErrors = append(lineErrors, linereference.LineError{LineNumber: line.LineNumber, Message: ErrFulfilmentOrderNotFound.Error()})
+			}
+			delete(fulfilments, orderGuid)
+			continue
+		}
+
+		details := linereference.Lines(order.Details)
+		receipts := linereference.WithoutDocument(fulfilment.Receipts, doc.PurchaseGuid)
+
+		if len(lines) > 0 {
+			lineErrors = append(lineErrors, svc.validateReceipt(order, details, receipts, lines, setting)...)
+		}
+
+		for _, line := range lines {
+			receipts = append(receipts, models.PurchaseOrderReceipt{
+				PurchaseGuid:    doc.PurchaseGuid,
+				PurchaseDocNo:   doc.PurchaseDocNo,
+				TaxDocNo:        doc.TaxDocNo,
+				DocDatetime:     doc.DocDatetime,
+				LineNumber:      line.LineNumber,
+				OrderLineNumber: line.OrderLineNumber,
+				Qty:             line.Qty,
+				Price:           line.Price,
+				SumAmount:       line.SumAmount,
+			})
+		}
+
+		fulfilment.ShopID = doc.ShopID
+		fulfilment.OrderGuid = orderGuid
+		fulfilment.OrderDocNo = order.DocNo
+		fulfilment.Receipts = receipts
+		fulfilment.UpdatedAt = svc.timeNow()
+
+		fulfilments[orderGuid] = fulfilment
+		orders[orderGuid] = order
+	}
+
+	if len(lineErrors) > 0 {
+		return linereference.Error{Lines: lineErrors}
+	}
+
+	for _, orderGuid := range orderGuids {
+		fulfilment, ok := fulfilments[orderGuid]
+		if !ok {
+			continue
+		}
+
+		isSaved, err := svc.repo.Save(ctx, fulfilment)
+		if err != nil {
+			return err
+		}
+
+		if !isSaved {
+			return ErrFulfilmentConflict
+		}
+
+		order := orders[orderGuid]
+		status := FulfilmentStatus(linereference.Lines(order.Details), fulfilment.Receipts)
+		if status != order.FulfilmentStatus {
+			isUpdated, err := svc.orderRepo.UpdateFulfilmentStatus(ctx, doc.ShopID, orderGuid, order.FulfilmentStatus, status)
+			if err != nil {
+				return err
+			}
+
+			// status which is changed after the order is read is received again with the changed status
+			if !isUpdated {
+				return ErrFulfilmentConflict
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc PurchaseOrderFulfilment) validateReceipt(
+	order models.PurchaseOrderDoc,
+	details []trans_models.Detail,
+	receipts []models.PurchaseOrderReceipt,
+	lines []models.ReceiptLine,
+	setting models.FulfilmentSetting,
+) []linereference.LineError {
+
+	lineErrors := []linereference.LineError{}
+	lineError := func(line models.ReceiptLine, format string, args ...interface{}) {
+		lineErrors = append(lineErrors, linereference.LineError{
+			LineNumber: line.LineNumber,
+			Message:    fmt.Sprintf("purchase order %s line %d ", order.DocNo, line.OrderLineNumber) + fmt.Sprintf(format, args...),
+		})
+	}
+
+	if !approvalmodels.IsApproved(order.ApprovalStatus) {
+		for _, line := range lines {
+			lineError(line, "is not approved")
+		}
+		return lineErrors
+	}
+
+	if FulfilmentStatus(details, receipts) == models.FulfilmentStatusClosed {
+		for _, line := range lines {
+			lineError(line, "is closed")
+		}
+		return lineErrors
+	}
+
+	received := linereference.ReferencedQty(receipts)
+
+	for _, line := range lines {
+		orderLine, ok := linereference.FindLine(details, line.OrderLineNumber)
+		if !ok {
+			lineError(line, "is not found")
+			continue
+		}
+
+		if line.Barcode != orderLine.Barcode {
+			lineError(line, "is barcode %s, not %s", orderLine.Barcode, line.Barcode)
+			continue
+		}
+
+		if line.UnitCode != "" && orderLine.UnitCode != "" && line.UnitCode != orderLine.UnitCode {
+			lineError(line, "is unit %s, not %s", orderLine.UnitCode, line.UnitCode)
+			continue
+		}
+
+		if line.Qty <= 0 {
+			lineError(line, "must be received more than 0")
+			continue
+		}
+
+		allowedQty := orderLine.Qty*(1+setting.OverReceiptPercent/100) - received[line.OrderLineNumber]
+		if line.Qty > allowedQty+linereference.QtyPrecision {
+			lineError(line, "can be received %v more, not %v", math.Max(allowedQty, 0), line.Qty)
+			continue
+		}
+
+		received[line.OrderLineNumber] += line.Qty
+	}
+
+	return lineErrors
+}
+
+func (svc PurchaseOrderFulfilment) Release(ctx context.Context, shopID string, purchaseGuid string) error {
+	return svc.Receive(ctx, models.ReceiptDocument{
+		ShopID:       shopID,
+		PurchaseGuid: purchaseGuid,
+	})
+}
+
+func (svc PurchaseOrderFulfilment) CheckOrder(ctx context.Context, order models.PurchaseOrderDoc, details []trans_models.Detail) (string, error) {
+	fulfilment, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return "", err
+	}
+
+	if len(fulfilment.Receipts) == 0 {
+		return models.FulfilmentStatusOpen, nil
+	}
+
+	setting, err := svc.Setting(ctx, order.ShopID)
+	if err != nil {
+		return "", err
+	}
+
+	received := linereference.ReferencedQty(fulfilment.Receipts)
+	previousDetails := linereference.Lines(order.Details)
+
+	lineErrors := []linereference.LineError{}
+	for _, previousLine := range previousDetails {
+		qty, ok := received[previousLine.LineNumber]
+		if !ok {
+			continue
+		}
+
+		orderLine, ok := linereference.FindLine(details, previousLine.LineNumber)
+		if !ok {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is received %v and can not be removed", qty)})
+			continue
+		}
+
+		if orderLine.Barcode != previousLine.Barcode {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is received and can not be changed to barcode %s", orderLine.Barcode)})
+			continue
+		}
+
+		if orderLine.Qty*(1+setting.OverReceiptPercent/100) < qty-linereference.QtyPrecision {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is received %v and can not be ordered %v", qty, orderLine.Qty)})
+		}
+	}
+
+	if len(lineErrors) > 0 {
+		return "", linereference.Error{Lines: lineErrors}
+	}
+
+	return FulfilmentStatus(details, fulfilment.Receipts), nil
+}
+
+func (svc PurchaseOrderFulfilment) CheckOrderDelete(ctx context.Context, shopID string, orderGuid string) error {
+	fulfilment, err := svc.repo.FindByOrder(ctx, shopID, orderGuid)
+	if err != nil {
+		return err
+	}
+
+	if len(fulfilment.Receipts) > 0 {
+		return ErrFulfilmentHasReceipts
+	}
+
+	return nil
+}
+
+func (svc PurchaseOrderFulfilment) RemoveOrder(ctx context.Context, shopID string, orderGuid string) error {
+	return svc.repo.DeleteByOrder(ctx, shopID, orderGuid)
+}
+
+func (svc PurchaseOrderFulfilment) Info(ctx context.Context, order models.PurchaseOrderDoc) (models.PurchaseOrderFulfilmentInfo, error) {
+	fulfilment, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return models.PurchaseOrderFulfilmentInfo{}, err
+	}
+
+	details := linereference.Lines(order.Details)
+
+	return models.PurchaseOrderFulfilmentInfo{
+		OrderGuid:   order.GuidFixed,
+		OrderDocNo:  order.DocNo,
+		DocDatetime: order.DocDatetime,
+		CustCode:    order.CustCode,
+		Status:      FulfilmentStatus(details, fulfilment.Receipts),
+		Lines:       FulfilmentLines(details, fulfilment.Receipts),
+	}, nil
+}
+
+func (svc PurchaseOrderFulfilment) Matching(ctx context.Context, shopID string, orders []models.PurchaseOrderInfo, varianceOnly bool) ([]models.MatchingLine, error) {
+	if len(orders) == 0 {
+		return []models.MatchingLine{}, nil
+	}
+
+	setting, err := svc.Setting(ctx, shopID)
+	if err != nil {
+		return []models.MatchingLine{}, err
+	}
+
+	orderGuids := []string{}
+	for _, order := range orders {
+		orderGuids = append(orderGuids, order.GuidFixed)
+	}
+
+	fulfilments, err := svc.repo.FindByOrders(ctx, shopID, orderGuids)
+	if err != nil {
+		return []models.MatchingLine{}, err
+	}
+
+	receipts := map[string][]models.PurchaseOrderReceipt{}
+	for _, fulfilment := range fulfilments {
+		receipts[fulfilment.OrderGuid] = fulfilment.Receipts
+	}
+
+	result := []models.MatchingLine{}
+	for _, order := range orders {
+		for _, line := range MatchLines(order, receipts[order.GuidFixed], setting) {
+			if varianceOnly && len(line.Flags) == 0 {
+				continue
+			}
+			result = append(result, line)
+		}
+	}
+
+	return result, nil
+}
+
+func (svc PurchaseOrderFulfilment) Setting(ctx context.Context, shopID string) (models.FulfilmentSetting, error) {
+	doc, err := svc.settingRepo.FindByShopID(ctx, shopID)
+	if err != nil {
+		return models.FulfilmentSetting{}, err
+	}
+
+	return doc.FulfilmentSetting, nil
+}
+
+func (svc PurchaseOrderFulfilment) SaveSetting(ctx context.Context, shopID string, authUsername string, setting models.FulfilmentSetting) error {
+	return svc.settingRepo.Save(ctx, models.FulfilmentSettingDoc{
+		ShopID:            shopID,
+		FulfilmentSetting: setting,
+		UpdatedBy:         authUsername,
+		UpdatedAt:         svc.timeNow(),
+	})
+}
@@ -0,0 +1,414 @@
+package services_test
+
+import (
+	"context"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/purchaseorder/models"
+	"smlaicloudplatform/internal/transaction/purchaseorder/repositories"
+	"smlaicloudplatform/internal/transaction/purchaseorder/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPurchaseOrder(guid string, approvalStatus string, fulfilmentStatus string, details ...trans_models.Detail) models.PurchaseOrderDoc {
+	doc := models.PurchaseOrderDoc{}
+	doc.ShopID = "shop1"
+	doc.GuidFixed = guid
+	doc.DocNo = "PO-" + guid
+	doc.ApprovalStatus = approvalStatus
+	doc.FulfilmentStatus = fulfilmentStatus
+	doc.Details = &details
+	return doc
+}
+
+func receipt(purchaseGuid string, lines ...models.ReceiptLine) models.ReceiptDocument {
+	return models.ReceiptDocument{
+		ShopID:        "shop1",
+		PurchaseGuid:  purchaseGuid,
+		PurchaseDocNo: "PU-" + purchaseGuid,
+		TaxDocNo:      "INV-" + purchaseGuid,
+		Lines:         lines,
+	}
+}
+
+func newFulfilmentMocks(setting models.FulfilmentSetting) (*PurchaseOrderRepositoryMock, *PurchaseOrderFulfilmentRepositoryMock, *FulfilmentSettingRepositoryMock) {
+	orderRepo := new(PurchaseOrderRepositoryMock)
+	repo := new(PurchaseOrderFulfilmentRepositoryMock)
+	settingRepo := new(FulfilmentSettingRepositoryMock)
+	settingRepo.On("FindByShopID", "shop1").Return(models.FulfilmentSettingDoc{ShopID: "shop1", FulfilmentSetting: setting}, nil)
+	return orderRepo, repo, settingRepo
+}
+
+func TestFulfilmentPartialReceipt(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", "pu1").Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", approvalmodels.StatusApproved, models.FulfilmentStatusOpen,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", UnitCode: "PCS", Qty: 10, Price: 100},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", UnitCode: "PCS", Qty: 5, Price: 20},
+	), nil)
+	repo.On("Save", mock.MatchedBy(func(doc models.PurchaseOrderFulfilment) bool {
+		return doc.OrderGuid == "po1" && doc.OrderDocNo == "PO-po1" && len(doc.Receipts) == 1 && doc.Receipts[0].TaxDocNo == "INV-pu1"
+	})).Return(true, nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(true, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	err := fulfilment.Receive(context.Background(), receipt("pu1", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", UnitCode: "PCS", Qty: 4, Price: 100}))
+	require.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestFulfilmentReceiveClosedOrder(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", "pu3").Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{
+		ShopID:    "shop1",
+		OrderGuid: "po1",
+		Receipts: []models.PurchaseOrderReceipt{
+			{PurchaseGuid: "pu1", LineNumber: 1, OrderLineNumber: 1, Qty: 10},
+			{PurchaseGuid: "pu2", LineNumber: 1, OrderLineNumber: 2, Qty: 5},
+		},
+		Version: 2,
+	}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusClosed,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	), nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	err := fulfilment.Receive(context.Background(), receipt("pu3", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 2, Barcode: "B", Qty: 1}))
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	assert.Contains(t, lineErr.Lines[0].Message, "is closed")
+	repo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestFulfilmentRelease(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", "pu2").Return([]models.PurchaseOrderFulfilment{{
+		ShopID:    "shop1",
+		OrderGuid: "po1",
+		Receipts: []models.PurchaseOrderReceipt{
+			{PurchaseGuid: "pu1", LineNumber: 1, OrderLineNumber: 1, Qty: 4},
+			{PurchaseGuid: "pu2", LineNumber: 1, OrderLineNumber: 1, Qty: 6},
+			{PurchaseGuid: "pu2", LineNumber: 2, OrderLineNumber: 2, Qty: 5},
+		},
+		Version: 2,
+	}}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusClosed,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	), nil)
+
+	// deleted purchase give back its quantity and reopen the order
+	repo.On("Save", mock.MatchedBy(func(doc models.PurchaseOrderFulfilment) bool {
+		return doc.Version == 2 && len(doc.Receipts) == 1 && doc.Receipts[0].PurchaseGuid == "pu1"
+	})).Return(true, nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusClosed, models.FulfilmentStatusPartial).Return(true, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	require.NoError(t, fulfilment.Release(context.Background(), "shop1", "pu2"))
+	orderRepo.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestFulfilmentInfo(t *testing.T) {
+	repo := new(PurchaseOrderFulfilmentRepositoryMock)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{
+		Receipts: []models.PurchaseOrderReceipt{
+			{PurchaseGuid: "pu1", LineNumber: 1, OrderLineNumber: 1, Qty: 4},
+			{PurchaseGuid: "pu2", LineNumber: 1, OrderLineNumber: 1, Qty: 6},
+		},
+	}, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(nil, repo, nil, time.Now)
+
+	info, err := fulfilment.Info(context.Background(), newPurchaseOrder("po1", "", models.FulfilmentStatusPartial,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, models.FulfilmentStatusPartial, info.Status)
+	require.Len(t, info.Lines, 2)
+	assert.Equal(t, 10.0, info.Lines[0].ReceivedQty)
+	assert.Equal(t, 0.0, info.Lines[0].RemainingQty)
+	assert.Len(t, info.Lines[0].Receipts, 2)
+	assert.Equal(t, 5.0, info.Lines[1].RemainingQty)
+}
+
+func TestFulfilmentRejectOverReceiptPerLine(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", "pu1").Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", mock.Anything).Return(models.PurchaseOrderFulfilment{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusOpen,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	), nil)
+	orderRepo.On("FindByGuid", "shop1", "po2").Return(newPurchaseOrder("po2", approvalmodels.StatusPending, models.FulfilmentStatusOpen,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 1},
+	), nil)
+	orderRepo.On("FindByGuid", "shop1", "po3").Return(models.PurchaseOrderDoc{}, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	err := fulfilment.Receive(context.Background(), receipt("pu1",
+		models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", Qty: 11},
+		models.ReceiptLine{LineNumber: 2, OrderGuid: "po1", OrderLineNumber: 2, Barcode: "C", Qty: 1},
+		models.ReceiptLine{LineNumber: 3, OrderGuid: "po1", OrderLineNumber: 9, Barcode: "A", Qty: 1},
+		models.ReceiptLine{LineNumber: 4, OrderGuid: "po2", OrderLineNumber: 1, Barcode: "A", Qty: 1},
+		models.ReceiptLine{LineNumber: 5, OrderGuid: "po3", OrderLineNumber: 1, Barcode: "A", Qty: 1},
+	))
+
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+
+	lineNumbers := []int{}
+	for _, line := range lineErr.Lines {
+		lineNumbers = append(lineNumbers, line.LineNumber)
+	}
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, lineNumbers)
+	repo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestFulfilmentOverReceiptTolerance(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{OverReceiptPercent: 10})
+
+	repo.On("FindByPurchase", "shop1", mock.Anything).Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusOpen,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	), nil)
+	repo.On("Save", mock.Anything).Return(true, nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(true, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	// quantity over the ordered quantity is accepted within tolerance
+	require.NoError(t, fulfilment.Receive(context.Background(), receipt("pu1", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", Qty: 11})))
+
+	err := fulfilment.Receive(context.Background(), receipt("pu2", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 2, Barcode: "B", Qty: 5.6}))
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	repo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestFulfilmentUpdatePurchaseReplaceReceipts(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", "pu1").Return([]models.PurchaseOrderFulfilment{{
+		ShopID:    "shop1",
+		OrderGuid: "po1",
+		Receipts:  []models.PurchaseOrderReceipt{{PurchaseGuid: "pu1", LineNumber: 1, OrderLineNumber: 1, Qty: 10}},
+		Version:   1,
+	}}, nil)
+	repo.On("FindByOrder", "shop1", "po2").Return(models.PurchaseOrderFulfilment{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusClosed, trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10}), nil)
+	orderRepo.On("FindByGuid", "shop1", "po2").Return(newPurchaseOrder("po2", "", models.FulfilmentStatusOpen, trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10}), nil)
+
+	// edited purchase move its receipt to another order
+	repo.On("Save", mock.MatchedBy(func(doc models.PurchaseOrderFulfilment) bool {
+		return doc.OrderGuid == "po1" && len(doc.Receipts) == 0
+	})).Return(true, nil)
+	repo.On("Save", mock.MatchedBy(func(doc models.PurchaseOrderFulfilment) bool {
+		return doc.OrderGuid == "po2" && len(doc.Receipts) == 1 && doc.Receipts[0].Qty == 3
+	})).Return(true, nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusClosed, models.FulfilmentStatusOpen).Return(true, nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po2", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(true, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	require.NoError(t, fulfilment.Receive(context.Background(), receipt("pu1", models.ReceiptLine{LineNumber: 1, OrderGuid: "po2", OrderLineNumber: 1, Barcode: "A", Qty: 3})))
+	orderRepo.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestFulfilmentRetryOnConflict(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", mock.Anything).Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusOpen, trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10}), nil)
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(true, nil)
+
+	// another receipt save the order first twice
+	repo.On("Save", mock.Anything).Return(false, nil).Twice()
+	repo.On("Save", mock.Anything).Return(true, nil).Once()
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	require.NoError(t, fulfilment.Receive(context.Background(), receipt("pu1", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", Qty: 1})))
+
+	repo.On("Save", mock.Anything).Return(false, nil)
+	err := fulfilment.Receive(context.Background(), receipt("pu2", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", Qty: 1}))
+	assert.ErrorIs(t, err, services.ErrFulfilmentConflict)
+	repo.AssertNumberOfCalls(t, "Save", 6)
+}
+
+func TestFulfilmentRetryOnStatusChanged(t *testing.T) {
+	orderRepo, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+
+	repo.On("FindByPurchase", "shop1", mock.Anything).Return([]models.PurchaseOrderFulfilment{}, nil)
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{}, nil)
+	repo.On("Save", mock.Anything).Return(true, nil)
+	orderRepo.On("FindByGuid", "shop1", "po1").Return(newPurchaseOrder("po1", "", models.FulfilmentStatusOpen, trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10}), nil)
+
+	// status of the order is changed by another receipt after the order is read
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(false, nil).Once()
+	orderRepo.On("UpdateFulfilmentStatus", "shop1", "po1", models.FulfilmentStatusOpen, models.FulfilmentStatusPartial).Return(true, nil).Once()
+
+	fulfilment := services.NewPurchaseOrderFulfilment(orderRepo, repo, settingRepo, time.Now)
+
+	require.NoError(t, fulfilment.Receive(context.Background(), receipt("pu1", models.ReceiptLine{LineNumber: 1, OrderGuid: "po1", OrderLineNumber: 1, Barcode: "A", Qty: 1})))
+	orderRepo.AssertExpectations(t)
+	repo.AssertNumberOfCalls(t, "Save", 2)
+}
+
+func TestFulfilmentCheckOrderChange(t *testing.T) {
+	_, repo, settingRepo := newFulfilmentMocks(models.FulfilmentSetting{})
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{}, nil).Once()
+	repo.On("FindByOrder", "shop1", "po1").Return(models.PurchaseOrderFulfilment{
+		Receipts: []models.PurchaseOrderReceipt{{PurchaseGuid: "pu1", LineNumber: 1, OrderLineNumber: 1, Qty: 4}},
+	}, nil)
+
+	fulfilment := services.NewPurchaseOrderFulfilment(nil, repo, settingRepo, time.Now)
+	ctx := context.Background()
+
+	order := newPurchaseOrder("po1", "", models.FulfilmentStatusOpen,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5},
+	)
+
+	status, err := fulfilment.CheckOrder(ctx, order, []trans_models.Detail{{LineNumber: 1, Barcode: "A", Qty: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, models.FulfilmentStatusOpen, status)
+
+	_, err = fulfilment.CheckOrder(ctx, order, []trans_models.Detail{{LineNumber: 2, Barcode: "B", Qty: 5}})
+	assert.ErrorContains(t, err, "can not be removed")
+
+	_, err = fulfilment.CheckOrder(ctx, order, []trans_models.Detail{{LineNumber: 1, Barcode: "C", Qty: 10}})
+	assert.ErrorContains(t, err, "barcode C")
+
+	_, err = fulfilment.CheckOrder(ctx, order, []trans_models.Detail{{LineNumber: 1, Barcode: "A", Qty: 3}})
+	assert.ErrorContains(t, err, "can not be ordered 3")
+
+	status, err = fulfilment.CheckOrder(ctx, order, []trans_models.Detail{{LineNumber: 1, Barcode: "A", Qty: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, models.FulfilmentStatusClosed, status)
+
+	assert.ErrorIs(t, fulfilment.CheckOrderDelete(ctx, "shop1", "po1"), services.ErrFulfilmentHasReceipts)
+}
+
+func TestMatchLinesVarianceFlags(t *testing.T) {
+	order := newPurchaseOrder("po1", "", models.FulfilmentStatusPartial,
+		trans_models.Detail{LineNumber: 1, Barcode: "A", Qty: 10, Price: 100, SumAmount: 1000},
+		trans_models.Detail{LineNumber: 2, Barcode: "B", Qty: 5, Price: 20, SumAmount: 100},
+		trans_models.Detail{LineNumber: 3, Barcode: "C", Qty: 2, Price: 50, SumAmount: 100},
+		trans_models.Detail{LineNumber: 4, Barcode: "D", Qty: 1, Price: 10, SumAmount: 10},
+	)
+	receipts := []models.PurchaseOrderReceipt{
+		{PurchaseGuid: "pu1", TaxDocNo: "INV1", OrderLineNumber: 1, Qty: 10, Price: 100, SumAmount: 1000},
+		{PurchaseGuid: "pu1", TaxDocNo: "INV1", OrderLineNumber: 2, Qty: 5, Price: 22, SumAmount: 110},
+		{PurchaseGuid: "pu2", PurchaseDocNo: "PU2", OrderLineNumber: 3, Qty: 1, Price: 50, SumAmount: 50},
+		{PurchaseGuid: "pu2", PurchaseDocNo: "PU2", OrderLineNumber: 3, Qty: 1.5, Price: 50, SumAmount: 75},
+	}
+
+	lines := services.MatchLines(order.PurchaseOrderInfo, receipts, models.FulfilmentSetting{PriceVariancePercent: 5})
+	require.Len(t, lines, 4)
+
+	assert.True(t, lines[0].IsMatched)
+	assert.Empty(t, lines[0].Flags)
+	assert.Equal(t, []string{"INV1"}, lines[0].InvoiceDocNos)
+
+	assert.False(t, lines[1].IsMatched)
+	assert.Equal(t, []string{models.VariancePrice, models.VarianceAmount}, lines[1].Flags)
+	assert.InDelta(t, 2.0, lines[1].PriceVariance, 0.0001)
+	assert.InDelta(t, 10.0, lines[1].AmountVariance, 0.0001)
+
+	assert.Equal(t, []string{models.VarianceOverReceived}, lines[2].Flags)
+	assert.Equal(t, []string{"PU2", "PU2"}, lines[2].InvoiceDocNos)
+	assert.InDelta(t, 0.5, lines[2].QtyVariance, 0.0001)
+
+	// line which is not received yet is not matched but has no variance
+	assert.False(t, lines[3].IsMatched)
+	assert.Empty(t, lines[3].Flags)
+
+	// price within tolerance is matched
+	lines = services.MatchLines(order.PurchaseOrderInfo, receipts, models.FulfilmentSetting{PriceVariancePercent: 10})
+	assert.True(t, lines[1].IsMatched)
+
+	lines = services.MatchLines(order.PurchaseOrderInfo, receipts[:1], models.FulfilmentSetting{})
+	assert.Equal(t, models.FulfilmentStatusPartial, lines[0].Status)
+}
+
+type PurchaseOrderRepositoryMock struct {
+	repositories.IPurchaseOrderRepository
+	mock.Mock
+}
+
+func (m *PurchaseOrderRepositoryMock) FindByGuid(ctx context.Context, shopID string, guid string) (models.PurchaseOrderDoc, error) {
+	args := m.Called(shopID, guid)
+	return args.Get(0).(models.PurchaseOrderDoc), args.Error(1)
+}
+
+func (m *PurchaseOrderRepositoryMock) UpdateFulfilmentStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error) {
+	args := m.Called(shopID, guid, fromStatus, toStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+type PurchaseOrderFulfilmentRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PurchaseOrderFulfilmentRepositoryMock) FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.PurchaseOrderFulfilment, error) {
+	args := m.Called(shopID, orderGuid)
+	return args.Get(0).(models.PurchaseOrderFulfilment), args.Error(1)
+}
+
+func (m *PurchaseOrderFulfilmentRepositoryMock) FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.PurchaseOrderFulfilment, error) {
+	args := m.Called(shopID, orderGuids)
+	return args.Get(0).([]models.PurchaseOrderFulfilment), args.Error(1)
+}
+
+func (m *PurchaseOrderFulfilmentRepositoryMock) FindByPurchase(ctx context.Context, shopID string, purchaseGuid string) ([]models.PurchaseOrderFulfilment, error) {
+	args := m.Called(shopID, purchaseGuid)
+	return args.Get(0).([]models.PurchaseOrderFulfilment), args.Error(1)
+}
+
+func (m *PurchaseOrderFulfilmentRepositoryMock) Save(ctx context.Context, doc models.PurchaseOrderFulfilment) (bool, error) {
+	args := m.Called(doc)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PurchaseOrderFulfilmentRepositoryMock) DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error {
+	args := m.Called(shopID, orderGuid)
+	return args.Error(0)
+}
+
+type FulfilmentSettingRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FulfilmentSettingRepositoryMock) FindByShopID(ctx context.Context, shopID string) (models.FulfilmentSettingDoc, error) {
+	args := m.Called(shopID)
+	return args.Get(0).(models.FulfilmentSettingDoc), args.Error(1)
+}
+
+func (m *FulfilmentSettingRepositoryMock) Save(ctx context.Context, doc models.FulfilmentSettingDoc) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
@@ -7,14 +7,17 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
 	"smlaicloudplatform/internal/transaction/purchaseorder/models"
 	"smlaicloudplatform/internal/transaction/purchaseorder/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -22,15 +25,20 @@ import (
 )
 
 type IPurchaseOrderHttpService interface {
-	CreatePurchaseOrder(shopID string, authUsername string, doc models.PurchaseOrder) (string, string, error)
-	UpdatePurchaseOrder(shopID string, guid string, authUsername string, doc models.PurchaseOrder) error
-	DeletePurchaseOrder(shopID string, guid string, authUsername string) error
-	DeletePurchaseOrderByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPurchaseOrder(shopID string, guid string) (models.PurchaseOrderInfo, error)
-	InfoPurchaseOrderByCode(shopID string, code string) (models.PurchaseOrderInfo, error)
-	SearchPurchaseOrder(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseOrderInfo, mongopagination.PaginationData, error)
-	SearchPurchaseOrderStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseOrderInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.PurchaseOrder) (common.BulkImport, error)
+	CreatePurchaseOrder(ctx context.Context, shopID string, authUsername string, doc models.PurchaseOrder) (string, string, error)
+	UpdatePurchaseOrder(ctx context.Context, shopID string, guid string, authUsername string, doc models.PurchaseOrder) error
+	DeletePurchaseOrder(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePurchaseOrderByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPurchaseOrder(ctx context.Context, shopID string, guid string) (models.PurchaseOrderInfo, error)
+	InfoPurchaseOrderByCode(ctx context.Context, shopID string, code string) (models.PurchaseOrderInfo, error)
+	SearchPurchaseOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseOrderInfo, mongopagination.PaginationData, error)
+	SearchPurchaseOrderStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseOrderInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PurchaseOrder) (common.BulkImport, error)
+	Approval() approvalservices.IDocumentApprovalService
+	InfoPurchaseOrderFulfilment(ctx context.Context, shopID string, guid string) (models.PurchaseOrderFulfilmentInfo, error)
+	SearchPurchaseOrderMatching(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable, varianceOnly bool) ([]models.MatchingLine, mongopagination.PaginationData, error)
+	InfoFulfilmentSetting(ctx context.Context, shopID string) (models.FulfilmentSetting, error)
+	SaveFulfilmentSetting(ctx context.Context, shopID string, authUsername string, setting models.FulfilmentSetting) error
 
 	GetModuleName() string
 }
@@ -42,8 +50,9 @@ const (
 type PurchaseOrderHttpService struct {
 	repoMq           repositories.IPurchaseOrderMessageQueueRepository
 	repo             repositories.IPurchaseOrderRepository
-	repoCache        trancache.ICacheRepository
-	cacheExpireDocNo time.Duration
+	docNoSequencer   docsequence.IDocNoSequencer
+	approvalWorkflow approvalservices.IApprovalWorkflow
+	fulfilment       IPurchaseOrderFulfilment
 	syncCacheRepo    mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.PurchaseOrderActivity, models.PurchaseOrderDeleteActivity]
 	contextTimeout time.Duration
@@ -51,7 +60,9 @@ type PurchaseOrderHttpService struct {
 
 func NewPurchaseOrderHttpService(
 	repo repositories.IPurchaseOrderRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	approvalWorkflow approvalservices.IApprovalWorkflow,
+	fulfilment IPurchaseOrderFulfilment,
 	repoMq repositories.IPurchaseOrderMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
 ) *PurchaseOrderHttpService {
@@ -61,9 +72,10 @@ func NewPurchaseOrderHttpService(
 	insSvc := &PurchaseOrderHttpService{
 		repo:             repo,
 		repoMq:           repoMq,
-		repoCache:        repoCache,
+		docNoSequencer:   docNoSequencer,
+		approvalWorkflow: approvalWorkflow,
+		fulfilment:       fulfilment,
 		syncCacheRepo:    syncCacheRepo,
-		cacheExpireDocNo: time.Hour * 24,
 		contextTimeout:   contextTimeout,
 	}
 
@@ -72,97 +84,67 @@ func NewPurchaseOrderHttpService(
 	return insSvc
 }
 
-func (svc PurchaseOrderHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PurchaseOrderHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PurchaseOrderHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc PurchaseOrderHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
+func (svc PurchaseOrderHttpService) CreatePurchaseOrder(ctx context.Context, shopID string, authUsername string, doc models.PurchaseOrder) (string, string, error) {
 
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc PurchaseOrderHttpService) CreatePurchaseOrder(shopID string, authUsername string, doc models.PurchaseOrder) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
+	newGuidFixed := utils.NewGUID()
 
-	if err != nil {
-		return "", "", err
+	if doc.Details != nil {
+		linereference.NormalizeLineNumbers(*doc.Details)
 	}
 
-	newGuidFixed := utils.NewGUID()
-
 	docData := models.PurchaseOrderDoc{}
 	docData.ShopID = shopID
 	docData.GuidFixed = newGuidFixed
 	docData.PurchaseOrder = doc
 
-	docData.DocNo = newDocNo
+	docData.FulfilmentStatus = models.FulfilmentStatusOpen
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		approvalStatus, err := svc.approvalWorkflow.Submit(ctx, svc.approvalDocument(shopID, newGuidFixed, docNo, doc), authUsername, doc.IsDraft)
+		if err != nil {
+			return err
+		}
+
+		docData.DocNo = docNo
+		docData.ApprovalStatus = approvalStatus
+
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, docData)
+		})
+		if err != nil {
+			svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePurchaseOrder, newGuidFixed)
+		}
+		return err
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	go func() {
-		svc.repoMq.Create(docData)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return newGuidFixed, newDocNo, nil
 }
 
-func (svc PurchaseOrderHttpService) UpdatePurchaseOrder(shopID string, guid string, authUsername string, doc models.PurchaseOrder) error {
+func (svc PurchaseOrderHttpService) UpdatePurchaseOrder(ctx context.Context, shopID string, guid string, authUsername string, doc models.PurchaseOrder) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -175,30 +157,57 @@ func (svc PurchaseOrderHttpService) UpdatePurchaseOrder(shopID string, guid stri
 		return errors.New("document not found")
 	}
 
+	details := []trans_models.Detail{}
+	if doc.Details != nil {
+		linereference.NormalizeLineNumbers(*doc.Details)
+		details = *doc.Details
+	}
+
+	// received lines must be kept
+	fulfilmentStatus, err := svc.fulfilment.CheckOrder(ctx, findDoc, details)
+
+	if err != nil {
+		return err
+	}
+
+	approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, guid, findDoc.DocNo, doc), authUsername, doc.IsDraft)
+
+	if err != nil {
+		return err
+	}
+
 	docData := findDoc
 	docData.PurchaseOrder = doc
 
 	docData.DocNo = findDoc.DocNo
+	docData.ApprovalStatus = approvalStatus
+	docData.FulfilmentStatus = fulfilmentStatus
 	docData.UpdatedBy = authUsername
 	docData.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, docData)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, docData)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, docData)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(docData)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseOrderHttpService) DeletePurchaseOrder(shopID string, guid string, authUsername string) error {
+func (svc PurchaseOrderHttpService) DeletePurchaseOrder(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -211,45 +220,80 @@ func (svc PurchaseOrderHttpService) DeletePurchaseOrder(shopID string, guid stri
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.fulfilment.CheckOrderDelete(ctx, shopID, guid)
 	if err != nil {
 		return err
 	}
 
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
+	if err != nil {
+		return err
+	}
+
+	svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePurchaseOrder, guid)
+	svc.fulfilment.RemoveOrder(ctx, shopID, guid)
+
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseOrderHttpService) DeletePurchaseOrderByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PurchaseOrderHttpService) DeletePurchaseOrderByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
+	for _, guid := range GUIDs {
+		if err := svc.fulfilment.CheckOrderDelete(ctx, shopID, guid); err != nil {
+			return err
+		}
+	}
+
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypePurchaseOrder, guid)
+		svc.fulfilment.RemoveOrder(ctx, shopID, guid)
+	}
+
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseOrderHttpService) InfoPurchaseOrder(shopID string, guid string) (models.PurchaseOrderInfo, error) {
+func (svc PurchaseOrderHttpService) InfoPurchaseOrder(ctx context.Context, shopID string, guid string) (models.PurchaseOrderInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -265,9 +309,9 @@ func (svc PurchaseOrderHttpService) InfoPurchaseOrder(shopID string, guid string
 	return findDoc.PurchaseOrderInfo, nil
 }
 
-func (svc PurchaseOrderHttpService) InfoPurchaseOrderByCode(shopID string, code string) (models.PurchaseOrderInfo, error) {
+func (svc PurchaseOrderHttpService) InfoPurchaseOrderByCode(ctx context.Context, shopID string, code string) (models.PurchaseOrderInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -283,9 +327,9 @@ func (svc PurchaseOrderHttpService) InfoPurchaseOrderByCode(shopID string, code
 	return findDoc.PurchaseOrderInfo, nil
 }
 
-func (svc PurchaseOrderHttpService) SearchPurchaseOrder(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseOrderInfo, mongopagination.PaginationData, error) {
+func (svc PurchaseOrderHttpService) SearchPurchaseOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseOrderInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -301,9 +345,9 @@ func (svc PurchaseOrderHttpService) SearchPurchaseOrder(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc PurchaseOrderHttpService) SearchPurchaseOrderStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseOrderInfo, int, error) {
+func (svc PurchaseOrderHttpService) SearchPurchaseOrderStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseOrderInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -321,9 +365,9 @@ func (svc PurchaseOrderHttpService) SearchPurchaseOrderStep(shopID string, langC
 	return docList, total, nil
 }
 
-func (svc PurchaseOrderHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.PurchaseOrder) (common.BulkImport, error) {
+func (svc PurchaseOrderHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PurchaseOrder) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.PurchaseOrder](dataList, svc.getDocIDKey)
@@ -379,7 +423,13 @@ func (svc PurchaseOrderHttpService) SaveInBatch(shopID string, authUsername stri
 		},
 		func(shopID string, authUsername string, data models.PurchaseOrder, doc models.PurchaseOrderDoc) error {
 
+			approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, doc.GuidFixed, doc.DocNo, data), authUsername, data.IsDraft)
+			if err != nil {
+				return err
+			}
+
 			doc.PurchaseOrder = data
+			doc.ApprovalStatus = approvalStatus
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
@@ -392,9 +442,21 @@ func (svc PurchaseOrderHttpService) SaveInBatch(shopID string, authUsername stri
 	)
 
 	if len(createDataList) > 0 {
+		documentApproval := svc.documentApproval()
+
+		approvalStatuses, err := documentApproval.SubmitInBatch(ctx, createDataList, authUsername)
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
+		for idx := range createDataList {
+			createDataList[idx].ApprovalStatus = approvalStatuses[idx]
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			documentApproval.RemoveInBatch(ctx, createDataList)
 			return common.BulkImport{}, err
 		}
 
@@ -432,6 +494,115 @@ func (svc PurchaseOrderHttpService) SaveInBatch(shopID string, authUsername stri
 	}, nil
 }
 
+func (svc PurchaseOrderHttpService) approvalDocument(shopID string, guid string, docNo string, doc models.PurchaseOrder) approvalmodels.ApprovalDocument {
+	return approvalmodels.ApprovalDocument{
+		ShopID:  shopID,
+		DocType: approvalmodels.DocTypePurchaseOrder,
+		DocGuid: guid,
+		DocNo:   docNo,
+		Amount:  doc.TotalAmount,
+	}
+}
+
+// Approval return approval workflow of saved purchase orders
+func (svc PurchaseOrderHttpService) Approval() approvalservices.IDocumentApprovalService {
+	return svc.documentApproval()
+}
+
+func (svc PurchaseOrderHttpService) documentApproval() approvalservices.DocumentApprovalService[models.PurchaseOrderDoc] {
+	return approvalservices.NewDocumentApprovalService(
+		svc.approvalWorkflow,
+		approvalmodels.DocTypePurchaseOrder,
+		svc.repo.FindByGuid,
+		func(doc models.PurchaseOrderDoc) approvalmodels.ApprovalDocument {
+			return svc.approvalDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.PurchaseOrder)
+		},
+		svc.saveApprovalStatus,
+	)
+}
+
+func (svc PurchaseOrderHttpService) InfoPurchaseOrderFulfilment(ctx context.Context, shopID string, guid string) (models.PurchaseOrderFulfilmentInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.PurchaseOrderFulfilmentInfo{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.PurchaseOrderFulfilmentInfo{}, errors.New("document not found")
+	}
+
+	return svc.fulfilment.Info(ctx, findDoc)
+}
+
+// SearchPurchaseOrderMatching return matching lines of page of purchase orders, lines without variance are
+// skipped when varianceOnly is true so the page can have less lines than lines of its orders
+func (svc PurchaseOrderHttpService) SearchPurchaseOrderMatching(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable, varianceOnly bool) ([]models.MatchingLine, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.MatchingLine{}, pagination, err
+	}
+
+	lines, err := svc.fulfilment.Matching(ctx, shopID, docList, varianceOnly)
+
+	if err != nil {
+		return []models.MatchingLine{}, pagination, err
+	}
+
+	return lines, pagination, nil
+}
+
+func (svc PurchaseOrderHttpService) InfoFulfilmentSetting(ctx context.Context, shopID string) (models.FulfilmentSetting, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.fulfilment.Setting(ctx, shopID)
+}
+
+func (svc PurchaseOrderHttpService) SaveFulfilmentSetting(ctx context.Context, shopID string, authUsername string, setting models.FulfilmentSetting) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.fulfilment.SaveSetting(ctx, shopID, authUsername, setting)
+}
+
+// saveApprovalStatus save changed approval status and publish the document so consumers post or remove it
+func (svc PurchaseOrderHttpService) saveApprovalStatus(ctx context.Context, doc models.PurchaseOrderDoc, approvalStatus string) error {
+	doc.ApprovalStatus = approvalStatus
+
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, doc.ShopID, doc.GuidFixed, doc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, doc)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(doc.ShopID)
+
+	return nil
+}
+
 func (svc PurchaseOrderHttpService) getDocIDKey(doc models.PurchaseOrder) string {
 	return doc.DocNo
 }
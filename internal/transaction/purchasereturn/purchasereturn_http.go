@@ -6,11 +6,14 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
+	purchaserepositories "smlaicloudplatform/internal/transaction/purchase/repositories"
 	"smlaicloudplatform/internal/transaction/purchasereturn/models"
 	"smlaicloudplatform/internal/transaction/purchasereturn/repositories"
 	"smlaicloudplatform/internal/transaction/purchasereturn/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/returnable"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -30,13 +33,15 @@ func NewPurchaseReturnHttp(ms *microservice.Microservice, cfg config.IConfig) Pu
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewPurchaseReturnRepository(pst)
-	repoMq := repositories.NewPurchaseReturnMessageQueueRepository(producer)
+	repoMq := repositories.NewPurchaseReturnOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewPurchaseReturnService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.PurchaseReturnParser{})
+	purchaseRepo := purchaserepositories.NewPurchaseRepository(pst)
+	returnLedger := returnable.InitReturnLedger(ms, cfg, repo)
+	svc := services.NewPurchaseReturnService(repo, docNoSequencer, purchaseRepo, returnLedger, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.PurchaseReturnParser{})
 
 	return PurchaseReturnHttp{
 		ms:  ms,
@@ -54,6 +59,7 @@ func (h PurchaseReturnHttp) RegisterHttp() {
 	h.ms.POST("/transaction/purchase-return", h.CreatePurchaseReturn)
 	h.ms.GET("/transaction/purchase-return/:id", h.InfoPurchaseReturn)
 	h.ms.GET("/transaction/purchase-return/code/:code", h.InfoPurchaseReturnByCode)
+	h.ms.GET("/transaction/purchase-return/returnable/:id", h.ReturnablePurchase)
 	h.ms.PUT("/transaction/purchase-return/:id", h.UpdatePurchaseReturn)
 	h.ms.DELETE("/transaction/purchase-return/:id", h.DeletePurchaseReturn)
 	h.ms.DELETE("/transaction/purchase-return", h.DeletePurchaseReturnByGUIDs)
@@ -86,10 +92,10 @@ func (h PurchaseReturnHttp) CreatePurchaseReturn(ctx microservice.IContext) erro
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreatePurchaseReturn(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreatePurchaseReturn(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -132,10 +138,10 @@ func (h PurchaseReturnHttp) UpdatePurchaseReturn(ctx microservice.IContext) erro
 		return err
 	}
 
-	err = h.svc.UpdatePurchaseReturn(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdatePurchaseReturn(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -163,7 +169,7 @@ func (h PurchaseReturnHttp) DeletePurchaseReturn(ctx microservice.IContext) erro
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeletePurchaseReturn(shopID, id, authUsername)
+	err := h.svc.DeletePurchaseReturn(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -202,7 +208,7 @@ func (h PurchaseReturnHttp) DeletePurchaseReturnByGUIDs(ctx microservice.IContex
 		return err
 	}
 
-	err = h.svc.DeletePurchaseReturnByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeletePurchaseReturnByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -232,7 +238,7 @@ func (h PurchaseReturnHttp) InfoPurchaseReturn(ctx microservice.IContext) error
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get PurchaseReturn %v", id)
-	doc, err := h.svc.InfoPurchaseReturn(shopID, id)
+	doc, err := h.svc.InfoPurchaseReturn(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -262,7 +268,7 @@ func (h PurchaseReturnHttp) InfoPurchaseReturnByCode(ctx microservice.IContext)
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoPurchaseReturnByCode(shopID, code)
+	doc, err := h.svc.InfoPurchaseReturnByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -314,7 +320,7 @@ func (h PurchaseReturnHttp) SearchPurchaseReturnPage(ctx microservice.IContext)
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchPurchaseReturn(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchPurchaseReturn(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -370,7 +376,7 @@ func (h PurchaseReturnHttp) SearchPurchaseReturnStep(ctx microservice.IContext)
 		},
 	})
 
-	docList, total, err := h.svc.SearchPurchaseReturnStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchPurchaseReturnStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -410,10 +416,10 @@ func (h PurchaseReturnHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
-		ctx.ResponseError(400, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -427,3 +433,30 @@ func (h PurchaseReturnHttp) SaveBulk(ctx microservice.IContext) error {
 
 	return nil
 }
+
+// Get Returnable Purchase godoc
+// @Description get bought quantity, returned quantity and quantity which can be returned of lines of the purchase
+// @Tags		PurchaseReturn
+// @Param		id  path      string  true  "Purchase guidfixed"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/purchase-return/returnable/{id} [get]
+func (h PurchaseReturnHttp) ReturnablePurchase(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.ReturnablePurchase(ctx.Context(), shopID, id)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
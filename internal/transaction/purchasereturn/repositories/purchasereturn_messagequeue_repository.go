@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/purchasereturn/config"
 	"smlaicloudplatform/internal/transaction/purchasereturn/models"
@@ -14,6 +15,11 @@ type IPurchaseReturnMessageQueueRepository interface {
 	CreateInBatch(docList []models.PurchaseReturnDoc) error
 	UpdateInBatch(docList []models.PurchaseReturnDoc) error
 	DeleteInBatch(docList []models.PurchaseReturnDoc) error
+	OutboxCreate(ctx context.Context, doc models.PurchaseReturnDoc) error
+	OutboxUpdate(ctx context.Context, doc models.PurchaseReturnDoc) error
+	OutboxDelete(ctx context.Context, doc models.PurchaseReturnDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.PurchaseReturnDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.PurchaseReturnDoc) error
 }
 
 type PurchaseReturnMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewPurchaseReturnMessageQueueRepository(prod microservice.IProducer) Purcha
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.PurchaseReturnDoc](prod, config.PurchaseReturnMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewPurchaseReturnOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewPurchaseReturnOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) PurchaseReturnMessageQueueRepository {
+	insRepo := NewPurchaseReturnMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
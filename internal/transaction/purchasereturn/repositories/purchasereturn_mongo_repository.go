@@ -4,6 +4,7 @@ import (
 	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/purchasereturn/models"
+	returnablemodels "smlaicloudplatform/internal/transaction/returnable/models"
 	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
@@ -35,6 +36,9 @@ type IPurchaseReturnRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseReturnActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseReturnDoc, error)
+	// FindReturnedQty return quantity of lines of original documents which is returned by each return other than excludeGuid
+	FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]returnablemodels.ReturnedQty, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type PurchaseReturnRepository struct {
@@ -63,6 +67,10 @@ func NewPurchaseReturnRepository(pst microservice.IPersisterMongo) *PurchaseRetu
 	return insRepo
 }
 
+func (repo PurchaseReturnRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo PurchaseReturnRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.PurchaseReturnDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -88,3 +96,40 @@ func (repo PurchaseReturnRepository) FindLastDocNo(ctx context.Context, shopID s
 
 	return doc, nil
 }
+
+func (repo PurchaseReturnRepository) FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]returnablemodels.ReturnedQty, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"shopid":             shopID,
+			"guidfixed":          bson.M{"$ne": excludeGuid},
+			"iscancel":           bson.M{"$ne": true},
+			"deletedat":          bson.M{"$exists": false},
+			"details.refdocguid": bson.M{"$in": docGuids},
+		}},
+		bson.M{"$unwind": "$details"},
+		bson.M{"$match": bson.M{"details.refdocguid": bson.M{"$in": docGuids}}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"returnguid": "$guidfixed",
+				"docguid":    "$details.refdocguid",
+				"linenumber": "$details.reflinenumber",
+			},
+			"qty": bson.M{"$sum": "$details.qty"},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":        0,
+			"returnguid": "$_id.returnguid",
+			"docguid":    "$_id.docguid",
+			"linenumber": "$_id.linenumber",
+			"qty":        1,
+		}},
+	}
+
+	docList := []returnablemodels.ReturnedQty{}
+	err := repo.pst.Aggregate(ctx, models.PurchaseReturnDoc{}, pipeline, &docList)
+	if err != nil {
+		return []returnablemodels.ReturnedQty{}, err
+	}
+
+	return docList, nil
+}
@@ -9,15 +9,18 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
+	purchasemodels "smlaicloudplatform/internal/transaction/purchase/models"
+	purchaserepositories "smlaicloudplatform/internal/transaction/purchase/repositories"
 	"smlaicloudplatform/internal/transaction/purchasereturn/models"
 	"smlaicloudplatform/internal/transaction/purchasereturn/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	returnablemodels "smlaicloudplatform/internal/transaction/returnable/models"
+	returnableservices "smlaicloudplatform/internal/transaction/returnable/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +28,17 @@ import (
 )
 
 type IPurchaseReturnService interface {
-	CreatePurchaseReturn(shopID string, authUsername string, doc models.PurchaseReturn) (string, string, error)
-	UpdatePurchaseReturn(shopID string, guid string, authUsername string, doc models.PurchaseReturn) error
-	DeletePurchaseReturn(shopID string, guid string, authUsername string) error
-	DeletePurchaseReturnByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoPurchaseReturn(shopID string, guid string) (models.PurchaseReturnInfo, error)
-	InfoPurchaseReturnByCode(shopID string, code string) (models.PurchaseReturnInfo, error)
-	SearchPurchaseReturn(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseReturnInfo, mongopagination.PaginationData, error)
-	SearchPurchaseReturnStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseReturnInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.PurchaseReturn) (common.BulkImport, error)
+	CreatePurchaseReturn(ctx context.Context, shopID string, authUsername string, doc models.PurchaseReturn) (string, string, error)
+	UpdatePurchaseReturn(ctx context.Context, shopID string, guid string, authUsername string, doc models.PurchaseReturn) error
+	DeletePurchaseReturn(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeletePurchaseReturnByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoPurchaseReturn(ctx context.Context, shopID string, guid string) (models.PurchaseReturnInfo, error)
+	InfoPurchaseReturnByCode(ctx context.Context, shopID string, code string) (models.PurchaseReturnInfo, error)
+	SearchPurchaseReturn(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseReturnInfo, mongopagination.PaginationData, error)
+	SearchPurchaseReturnStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseReturnInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PurchaseReturn) (common.BulkImport, error)
+
+	ReturnablePurchase(ctx context.Context, shopID string, purchaseGuid string) (returnablemodels.ReturnableDocument, error)
 
 	GetModuleName() string
 }
@@ -49,9 +54,10 @@ const (
 type PurchaseReturnService struct {
 	repoMq             repositories.IPurchaseReturnMessageQueueRepository
 	repo               repositories.IPurchaseReturnRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
+	purchaseRepo       purchaserepositories.IPurchaseRepository
+	returnLedger       returnableservices.IReturnLedger
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.PurchaseReturnActivity, models.PurchaseReturnDeleteActivity]
 	parser         IPurchaseReturnParser
@@ -60,7 +66,9 @@ type PurchaseReturnService struct {
 
 func NewPurchaseReturnService(
 	repo repositories.IPurchaseReturnRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	purchaseRepo purchaserepositories.IPurchaseRepository,
+	returnLedger returnableservices.IReturnLedger,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IPurchaseReturnMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +80,12 @@ func NewPurchaseReturnService(
 	insSvc := &PurchaseReturnService{
 		repo:               repo,
 		repoMq:             repoMq,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
+		purchaseRepo:       purchaseRepo,
+		returnLedger:       returnLedger,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +94,15 @@ func NewPurchaseReturnService(
 	return insSvc
 }
 
-func (svc PurchaseReturnService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc PurchaseReturnService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc PurchaseReturnService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc PurchaseReturnService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
+func (svc PurchaseReturnService) CreatePurchaseReturn(ctx context.Context, shopID string, authUsername string, doc models.PurchaseReturn) (string, string, error) {
 
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc PurchaseReturnService) CreatePurchaseReturn(shopID string, authUsername string, doc models.PurchaseReturn) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.PurchaseReturnDoc{}
@@ -157,22 +115,37 @@ func (svc PurchaseReturnService) CreatePurchaseReturn(shopID string, authUsernam
 		return "", "", err
 	}
 
-	details := svc.PrepareDetail(*doc.Details, productBarcodes)
-	dataDoc.Details = &details
-
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		// lines which return lines of purchases must not return more than their remaining quantity
+		details, err := svc.returnLines(ctx, shopID, newGuidFixed, doc, svc.PrepareDetail(*doc.Details, productBarcodes))
+		if err != nil {
+			return err
+		}
+		dataDoc.Details = &details
+		dataDoc.DocNo = docNo
+
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+		if err != nil {
+			return svc.releaseReturn(ctx, shopID, newGuidFixed, err)
+		}
+		return nil
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -207,9 +180,9 @@ func (svc PurchaseReturnService) PrepareDetail(details []trans_models.Detail, pr
 	return details
 }
 
-func (svc PurchaseReturnService) UpdatePurchaseReturn(shopID string, guid string, authUsername string, doc models.PurchaseReturn) error {
+func (svc PurchaseReturnService) UpdatePurchaseReturn(ctx context.Context, shopID string, guid string, authUsername string, doc models.PurchaseReturn) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -230,30 +203,39 @@ func (svc PurchaseReturnService) UpdatePurchaseReturn(shopID string, guid string
 		return err
 	}
 
-	details := svc.PrepareDetail(*doc.Details, productBarcodes)
+	details, err := svc.returnLines(ctx, shopID, guid, doc, svc.PrepareDetail(*doc.Details, productBarcodes))
+	if err != nil {
+		return err
+	}
 	dataDoc.Details = &details
 
 	dataDoc.DocNo = findDoc.DocNo
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
-		return err
+		return svc.restoreReturn(ctx, findDoc, err)
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseReturnService) DeletePurchaseReturn(shopID string, guid string, authUsername string) error {
+func (svc PurchaseReturnService) DeletePurchaseReturn(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -266,45 +248,73 @@ func (svc PurchaseReturnService) DeletePurchaseReturn(shopID string, guid string
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = svc.returnLedger.Release(ctx, shopID, guid)
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseReturnService) DeletePurchaseReturnByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc PurchaseReturnService) DeletePurchaseReturnByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		err = svc.returnLedger.Release(ctx, shopID, guid)
+		if err != nil {
+			return err
+		}
+	}
+
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc PurchaseReturnService) InfoPurchaseReturn(shopID string, guid string) (models.PurchaseReturnInfo, error) {
+func (svc PurchaseReturnService) InfoPurchaseReturn(ctx context.Context, shopID string, guid string) (models.PurchaseReturnInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -320,9 +330,9 @@ func (svc PurchaseReturnService) InfoPurchaseReturn(shopID string, guid string)
 	return findDoc.PurchaseReturnInfo, nil
 }
 
-func (svc PurchaseReturnService) InfoPurchaseReturnByCode(shopID string, code string) (models.PurchaseReturnInfo, error) {
+func (svc PurchaseReturnService) InfoPurchaseReturnByCode(ctx context.Context, shopID string, code string) (models.PurchaseReturnInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -338,9 +348,9 @@ func (svc PurchaseReturnService) InfoPurchaseReturnByCode(shopID string, code st
 	return findDoc.PurchaseReturnInfo, nil
 }
 
-func (svc PurchaseReturnService) SearchPurchaseReturn(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseReturnInfo, mongopagination.PaginationData, error) {
+func (svc PurchaseReturnService) SearchPurchaseReturn(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.PurchaseReturnInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -356,9 +366,9 @@ func (svc PurchaseReturnService) SearchPurchaseReturn(shopID string, filters map
 	return docList, pagination, nil
 }
 
-func (svc PurchaseReturnService) SearchPurchaseReturnStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseReturnInfo, int, error) {
+func (svc PurchaseReturnService) SearchPurchaseReturnStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.PurchaseReturnInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -376,9 +386,9 @@ func (svc PurchaseReturnService) SearchPurchaseReturnStep(shopID string, langCod
 	return docList, total, nil
 }
 
-func (svc PurchaseReturnService) SaveInBatch(shopID string, authUsername string, dataList []models.PurchaseReturn) (common.BulkImport, error) {
+func (svc PurchaseReturnService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.PurchaseReturn) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.PurchaseReturn](dataList, svc.getDocIDKey)
@@ -434,22 +444,39 @@ func (svc PurchaseReturnService) SaveInBatch(shopID string, authUsername string,
 		},
 		func(shopID string, authUsername string, data models.PurchaseReturn, doc models.PurchaseReturnDoc) error {
 
+			details, err := svc.returnLines(ctx, shopID, doc.GuidFixed, data, returnDetails(data.Details))
+			if err != nil {
+				return err
+			}
+
+			findDoc := doc
+
 			doc.PurchaseReturn = data
+			doc.Details = &details
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
 			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			if err != nil {
-				return nil
+				return svc.restoreReturn(ctx, findDoc, err)
 			}
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
+		err = svc.returnLinesInBatch(ctx, shopID, createDataList)
+
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			for _, doc := range createDataList {
+				err = svc.releaseReturn(ctx, shopID, doc.GuidFixed, err)
+			}
 			return common.BulkImport{}, err
 		}
 
@@ -487,6 +514,144 @@ func (svc PurchaseReturnService) SaveInBatch(shopID string, authUsername string,
 	}, nil
 }
 
+// ReturnablePurchase return quantity of lines of the purchase which is returned and can be returned
+func (svc PurchaseReturnService) ReturnablePurchase(ctx context.Context, shopID string, purchaseGuid string) (returnablemodels.ReturnableDocument, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.purchaseRepo.FindByGuid(ctx, shopID, purchaseGuid)
+
+	if err != nil {
+		return returnablemodels.ReturnableDocument{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return returnablemodels.ReturnableDocument{}, errors.New("document not found")
+	}
+
+	returned, err := svc.repo.FindReturnedQty(ctx, shopID, []string{purchaseGuid}, "")
+
+	if err != nil {
+		return returnablemodels.ReturnableDocument{}, err
+	}
+
+	return returnableservices.ReturnableLines(svc.sourceDocument(findDoc), returned), nil
+}
+
+// returnLines validate lines which reference lines of purchases, default them from the original lines
+// and keep their returned quantity, quantity which is returned by the return of guid itself is not counted
+func (svc PurchaseReturnService) returnLines(ctx context.Context, shopID string, guid string, doc models.PurchaseReturn, details []trans_models.Detail) ([]trans_models.Detail, error) {
+	sources, err := svc.sourceDocuments(ctx, shopID, details)
+	if err != nil {
+		return []trans_models.Detail{}, err
+	}
+
+	return svc.returnLedger.Return(ctx, svc.returnDocument(shopID, guid, doc, details), sources)
+}
+
+// returnLinesInBatch return lines of purchase returns which are created by bulk import,
+// nothing is returned when lines of any return can not be returned
+func (svc PurchaseReturnService) returnLinesInBatch(ctx context.Context, shopID string, docs []models.PurchaseReturnDoc) error {
+	returnDocs := []returnablemodels.ReturnDocument{}
+	allDetails := []trans_models.Detail{}
+	for _, doc := range docs {
+		returnDoc := svc.returnDocument(shopID, doc.GuidFixed, doc.PurchaseReturn, returnDetails(doc.Details))
+		returnDocs = append(returnDocs, returnDoc)
+		allDetails = append(allDetails, returnDoc.Details...)
+	}
+
+	sources, err := svc.sourceDocuments(ctx, shopID, allDetails)
+	if err != nil {
+		return err
+	}
+
+	detailsList, err := svc.returnLedger.ReturnInBatch(ctx, returnDocs, sources)
+	if err != nil {
+		return err
+	}
+
+	for idx := range docs {
+		details := detailsList[idx]
+		docs[idx].Details = &details
+	}
+
+	return nil
+}
+
+// releaseReturn give back quantity which is returned by the return which is not saved by err
+func (svc PurchaseReturnService) releaseReturn(ctx context.Context, shopID string, guid string, err error) error {
+	releaseErr := svc.returnLedger.Release(ctx, shopID, guid)
+	if releaseErr != nil {
+		return fmt.Errorf("%w, returned quantity is not released: %v", err, releaseErr)
+	}
+	return err
+}
+
+// restoreReturn keep quantity which is returned by the saved return when it is not updated by err
+func (svc PurchaseReturnService) restoreReturn(ctx context.Context, findDoc models.PurchaseReturnDoc, err error) error {
+	recordErr := svc.returnLedger.Record(ctx, svc.returnDocument(findDoc.ShopID, findDoc.GuidFixed, findDoc.PurchaseReturn, returnDetails(findDoc.Details)))
+	if recordErr != nil {
+		return fmt.Errorf("%w, returned quantity is not restored: %v", err, recordErr)
+	}
+	return err
+}
+
+// returnDocument return the purchase return, purchase returns are not made by POS so every line must reference the purchase
+func (svc PurchaseReturnService) returnDocument(shopID string, guid string, doc models.PurchaseReturn, details []trans_models.Detail) returnablemodels.ReturnDocument {
+	return returnablemodels.ReturnDocument{
+		ShopID:     shopID,
+		ReturnGuid: guid,
+		DocNo:      doc.DocNo,
+		IsCancel:   doc.IsCancel,
+		Details:    details,
+	}
+}
+
+// sourceDocuments return purchases which lines are referenced by details
+func (svc PurchaseReturnService) sourceDocuments(ctx context.Context, shopID string, details []trans_models.Detail) ([]returnablemodels.SourceDocument, error) {
+	sources := []returnablemodels.SourceDocument{}
+
+	docGuids := returnableservices.SourceGuids(details)
+	if len(docGuids) == 0 {
+		return sources, nil
+	}
+
+	sourceDocs, err := svc.purchaseRepo.FindByGuids(ctx, shopID, docGuids)
+	if err != nil {
+		return []returnablemodels.SourceDocument{}, err
+	}
+
+	for _, sourceDoc := range sourceDocs {
+		sources = append(sources, svc.sourceDocument(sourceDoc))
+	}
+
+	return sources, nil
+}
+
+func returnDetails(details *[]trans_models.Detail) []trans_models.Detail {
+	if details == nil {
+		return []trans_models.Detail{}
+	}
+	return *details
+}
+
+func (svc PurchaseReturnService) sourceDocument(doc purchasemodels.PurchaseDoc) returnablemodels.SourceDocument {
+	source := returnablemodels.SourceDocument{
+		GuidFixed:   doc.GuidFixed,
+		DocNo:       doc.DocNo,
+		DocDatetime: doc.DocDatetime,
+		IsCancel:    doc.IsCancel,
+		Details:     []trans_models.Detail{},
+	}
+
+	if doc.Details != nil {
+		source.Details = *doc.Details
+	}
+
+	return source
+}
+
 func (svc PurchaseReturnService) getDocIDKey(doc models.PurchaseReturn) string {
 	return doc.DocNo
 }
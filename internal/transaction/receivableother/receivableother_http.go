@@ -6,10 +6,11 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/receivableother/models"
 	"smlaicloudplatform/internal/transaction/receivableother/repositories"
 	"smlaicloudplatform/internal/transaction/receivableother/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -28,11 +29,11 @@ func NewReceivableOtherHttp(ms *microservice.Microservice, cfg config.IConfig) R
 	cache := ms.Cacher(cfg.CacherConfig())
 
 	repo := repositories.NewReceivableOtherRepository(pst)
-	repoMq := repositories.NewReceivableOtherMessageQueueRepository(ms.Producer(cfg.MQConfig()))
+	repoMq := repositories.NewReceivableOtherOutboxMessageQueueRepository(ms.Producer(cfg.MQConfig()), outbox.NewOutboxRepository(pst))
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewReceivableOtherHttpService(repo, repoMq, transRepo, masterSyncCacheRepo)
+	svc := services.NewReceivableOtherHttpService(repo, repoMq, docNoSequencer, masterSyncCacheRepo)
 
 	return ReceivableOtherHttp{
 		ms:  ms,
@@ -82,7 +83,7 @@ func (h ReceivableOtherHttp) CreateReceivableOther(ctx microservice.IContext) er
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateReceivableOther(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateReceivableOther(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -128,7 +129,7 @@ func (h ReceivableOtherHttp) UpdateReceivableOther(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.svc.UpdateReceivableOther(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateReceivableOther(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -159,7 +160,7 @@ func (h ReceivableOtherHttp) DeleteReceivableOther(ctx microservice.IContext) er
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteReceivableOther(shopID, id, authUsername)
+	err := h.svc.DeleteReceivableOther(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -198,7 +199,7 @@ func (h ReceivableOtherHttp) DeleteReceivableOtherByGUIDs(ctx microservice.ICont
 		return err
 	}
 
-	err = h.svc.DeleteReceivableOtherByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteReceivableOtherByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -228,7 +229,7 @@ func (h ReceivableOtherHttp) InfoReceivableOther(ctx microservice.IContext) erro
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get ReceivableOther %v", id)
-	doc, err := h.svc.InfoReceivableOther(shopID, id)
+	doc, err := h.svc.InfoReceivableOther(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -258,7 +259,7 @@ func (h ReceivableOtherHttp) InfoReceivableOtherByCode(ctx microservice.IContext
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoReceivableOtherByCode(shopID, code)
+	doc, err := h.svc.InfoReceivableOtherByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -310,7 +311,7 @@ func (h ReceivableOtherHttp) SearchReceivableOtherPage(ctx microservice.IContext
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchReceivableOther(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchReceivableOther(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -366,7 +367,7 @@ func (h ReceivableOtherHttp) SearchReceivableOtherStep(ctx microservice.IContext
 		},
 	})
 
-	docList, total, err := h.svc.SearchReceivableOtherStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchReceivableOtherStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -406,7 +407,7 @@ func (h ReceivableOtherHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/receivableother/config"
 	"smlaicloudplatform/internal/transaction/receivableother/models"
@@ -14,6 +15,11 @@ type IReceivableOtherMessageQueueRepository interface {
 	CreateInBatch(docList []models.ReceivableOtherDoc) error
 	UpdateInBatch(docList []models.ReceivableOtherDoc) error
 	DeleteInBatch(docList []models.ReceivableOtherDoc) error
+	OutboxCreate(ctx context.Context, doc models.ReceivableOtherDoc) error
+	OutboxUpdate(ctx context.Context, doc models.ReceivableOtherDoc) error
+	OutboxDelete(ctx context.Context, doc models.ReceivableOtherDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.ReceivableOtherDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.ReceivableOtherDoc) error
 }
 
 type ReceivableOtherMessageQueueRepository struct {
@@ -32,3 +38,12 @@ func NewReceivableOtherMessageQueueRepository(prod microservice.IProducer) IRece
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.ReceivableOtherDoc](prod, config.MessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewReceivableOtherOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewReceivableOtherOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) IReceivableOtherMessageQueueRepository {
+	insRepo := ReceivableOtherMessageQueueRepository{
+		prod: prod,
+	}
+	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.ReceivableOtherDoc](prod, config.MessageQueueConfig{}, "").WithOutbox(outbox)
+	return insRepo
+}
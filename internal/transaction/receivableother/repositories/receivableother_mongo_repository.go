@@ -34,6 +34,7 @@ type IReceivableOtherRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ReceivableOtherActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.ReceivableOtherDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type ReceivableOtherRepository struct {
@@ -58,6 +59,10 @@ func NewReceivableOtherRepository(pst microservice.IPersisterMongo) *ReceivableO
 	return insRepo
 }
 
+func (repo ReceivableOtherRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo ReceivableOtherRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.ReceivableOtherDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -7,14 +7,13 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	"smlaicloudplatform/internal/transaction/receivableother/models"
 	"smlaicloudplatform/internal/transaction/receivableother/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -22,15 +21,15 @@ import (
 )
 
 type IReceivableOtherHttpService interface {
-	CreateReceivableOther(shopID string, authUsername string, doc models.ReceivableOther) (string, string, error)
-	UpdateReceivableOther(shopID string, guid string, authUsername string, doc models.ReceivableOther) error
-	DeleteReceivableOther(shopID string, guid string, authUsername string) error
-	DeleteReceivableOtherByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoReceivableOther(shopID string, guid string) (models.ReceivableOtherInfo, error)
-	InfoReceivableOtherByCode(shopID string, code string) (models.ReceivableOtherInfo, error)
-	SearchReceivableOther(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReceivableOtherInfo, mongopagination.PaginationData, error)
-	SearchReceivableOtherStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ReceivableOtherInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ReceivableOther) (common.BulkImport, error)
+	CreateReceivableOther(ctx context.Context, shopID string, authUsername string, doc models.ReceivableOther) (string, string, error)
+	UpdateReceivableOther(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReceivableOther) error
+	DeleteReceivableOther(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteReceivableOtherByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoReceivableOther(ctx context.Context, shopID string, guid string) (models.ReceivableOtherInfo, error)
+	InfoReceivableOtherByCode(ctx context.Context, shopID string, code string) (models.ReceivableOtherInfo, error)
+	SearchReceivableOther(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReceivableOtherInfo, mongopagination.PaginationData, error)
+	SearchReceivableOtherStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ReceivableOtherInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ReceivableOther) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -41,26 +40,24 @@ const (
 )
 
 type ReceivableOtherHttpService struct {
-	repo             repositories.IReceivableOtherRepository
-	repoCache        trancache.ICacheRepository
-	repoMq           repositories.IReceivableOtherMessageQueueRepository
-	cacheExpireDocNo time.Duration
-	syncCacheRepo    mastersync.IMasterSyncCacheRepository
+	repo           repositories.IReceivableOtherRepository
+	docNoSequencer docsequence.IDocNoSequencer
+	repoMq         repositories.IReceivableOtherMessageQueueRepository
+	syncCacheRepo  mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.ReceivableOtherActivity, models.ReceivableOtherDeleteActivity]
 	contextTimeout time.Duration
 }
 
-func NewReceivableOtherHttpService(repo repositories.IReceivableOtherRepository, repoMq repositories.IReceivableOtherMessageQueueRepository, repoCache trancache.ICacheRepository, syncCacheRepo mastersync.IMasterSyncCacheRepository) *ReceivableOtherHttpService {
+func NewReceivableOtherHttpService(repo repositories.IReceivableOtherRepository, repoMq repositories.IReceivableOtherMessageQueueRepository, docNoSequencer docsequence.IDocNoSequencer, syncCacheRepo mastersync.IMasterSyncCacheRepository) *ReceivableOtherHttpService {
 
 	contextTimeout := time.Duration(15) * time.Second
 
 	insSvc := &ReceivableOtherHttpService{
-		repo:             repo,
-		repoCache:        repoCache,
-		repoMq:           repoMq,
-		syncCacheRepo:    syncCacheRepo,
-		cacheExpireDocNo: time.Hour * 24,
-		contextTimeout:   contextTimeout,
+		repo:           repo,
+		docNoSequencer: docNoSequencer,
+		repoMq:         repoMq,
+		syncCacheRepo:  syncCacheRepo,
+		contextTimeout: contextTimeout,
 	}
 
 	insSvc.ActivityService = services.NewActivityService[models.ReceivableOtherActivity, models.ReceivableOtherDeleteActivity](repo)
@@ -68,65 +65,15 @@ func NewReceivableOtherHttpService(repo repositories.IReceivableOtherRepository,
 	return insSvc
 }
 
-func (svc ReceivableOtherHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ReceivableOtherHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ReceivableOtherHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc ReceivableOtherHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
+func (svc ReceivableOtherHttpService) CreateReceivableOther(ctx context.Context, shopID string, authUsername string, doc models.ReceivableOther) (string, string, error) {
 
-func (svc ReceivableOtherHttpService) CreateReceivableOther(shopID string, authUsername string, doc models.ReceivableOther) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	docData := models.ReceivableOtherDoc{}
@@ -134,30 +81,33 @@ func (svc ReceivableOtherHttpService) CreateReceivableOther(shopID string, authU
 	docData.GuidFixed = newGuidFixed
 	docData.ReceivableOther = doc
 
-	docData.DocNo = newDocNo
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, docData)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Create(docData)
-	}()
-
 	return newGuidFixed, newDocNo, nil
 }
 
-func (svc ReceivableOtherHttpService) UpdateReceivableOther(shopID string, guid string, authUsername string, doc models.ReceivableOther) error {
+func (svc ReceivableOtherHttpService) UpdateReceivableOther(ctx context.Context, shopID string, guid string, authUsername string, doc models.ReceivableOther) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -177,7 +127,14 @@ func (svc ReceivableOtherHttpService) UpdateReceivableOther(shopID string, guid
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
@@ -185,16 +142,12 @@ func (svc ReceivableOtherHttpService) UpdateReceivableOther(shopID string, guid
 
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Update(findDoc)
-	}()
-
 	return nil
 }
 
-func (svc ReceivableOtherHttpService) DeleteReceivableOther(shopID string, guid string, authUsername string) error {
+func (svc ReceivableOtherHttpService) DeleteReceivableOther(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -207,23 +160,26 @@ func (svc ReceivableOtherHttpService) DeleteReceivableOther(shopID string, guid
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	svc.saveMasterSync(shopID)
 
-	go func() {
-		svc.repoMq.Delete(findDoc)
-	}()
-
 	return nil
 }
 
-func (svc ReceivableOtherHttpService) DeleteReceivableOtherByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc ReceivableOtherHttpService) DeleteReceivableOtherByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -238,9 +194,9 @@ func (svc ReceivableOtherHttpService) DeleteReceivableOtherByGUIDs(shopID string
 	return nil
 }
 
-func (svc ReceivableOtherHttpService) InfoReceivableOther(shopID string, guid string) (models.ReceivableOtherInfo, error) {
+func (svc ReceivableOtherHttpService) InfoReceivableOther(ctx context.Context, shopID string, guid string) (models.ReceivableOtherInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -256,9 +212,9 @@ func (svc ReceivableOtherHttpService) InfoReceivableOther(shopID string, guid st
 	return findDoc.ReceivableOtherInfo, nil
 }
 
-func (svc ReceivableOtherHttpService) InfoReceivableOtherByCode(shopID string, code string) (models.ReceivableOtherInfo, error) {
+func (svc ReceivableOtherHttpService) InfoReceivableOtherByCode(ctx context.Context, shopID string, code string) (models.ReceivableOtherInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -274,9 +230,9 @@ func (svc ReceivableOtherHttpService) InfoReceivableOtherByCode(shopID string, c
 	return findDoc.ReceivableOtherInfo, nil
 }
 
-func (svc ReceivableOtherHttpService) SearchReceivableOther(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReceivableOtherInfo, mongopagination.PaginationData, error) {
+func (svc ReceivableOtherHttpService) SearchReceivableOther(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.ReceivableOtherInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -292,9 +248,9 @@ func (svc ReceivableOtherHttpService) SearchReceivableOther(shopID string, filte
 	return docList, pagination, nil
 }
 
-func (svc ReceivableOtherHttpService) SearchReceivableOtherStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ReceivableOtherInfo, int, error) {
+func (svc ReceivableOtherHttpService) SearchReceivableOtherStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.ReceivableOtherInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -312,9 +268,9 @@ func (svc ReceivableOtherHttpService) SearchReceivableOtherStep(shopID string, l
 	return docList, total, nil
 }
 
-func (svc ReceivableOtherHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ReceivableOther) (common.BulkImport, error) {
+func (svc ReceivableOtherHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ReceivableOther) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ReceivableOther](dataList, svc.getDocIDKey)
@@ -374,30 +330,35 @@ func (svc ReceivableOtherHttpService) SaveInBatch(shopID string, authUsername st
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
-			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+			err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+				err := svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
+				if err != nil {
+					return err
+				}
+
+				return svc.repoMq.OutboxUpdate(ctx, doc)
+			})
 			if err != nil {
 				return nil
 			}
 
-			go func() {
-				svc.repoMq.Update(doc)
-			}()
-
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
-		err = svc.repo.CreateInBatch(ctx, createDataList)
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			err := svc.repo.CreateInBatch(ctx, createDataList)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreateInBatch(ctx, createDataList)
+		})
 
 		if err != nil {
 			return common.BulkImport{}, err
 		}
-
-		go func() {
-			svc.repoMq.CreateInBatch(createDataList)
-		}()
-
 	}
 
 	createDataKey := []string{}
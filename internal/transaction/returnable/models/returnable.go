@@ -0,0 +1,81 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	transmodels "smlaicloudplatform/internal/transaction/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const returnedDocumentCollectionName = "returnedDocuments"
+
+// SourceDocument is original document which lines are returned, sale invoice of sale invoice return
+// and purchase of purchase return
+type SourceDocument struct {
+	GuidFixed   string
+	DocNo       string
+	DocDatetime time.Time
+	IsCancel    bool
+	Details     []transmodels.Detail
+}
+
+// ReturnedQty is quantity of the line of the original document which is returned by the return of ReturnGuid
+type ReturnedQty struct {
+	ReturnGuid string  `json:"returnguid" bson:"returnguid"`
+	DocGuid    string  `json:"docguid" bson:"docguid"`
+	LineNumber int     `json:"linenumber" bson:"linenumber"`
+	Qty        float64 `json:"qty" bson:"qty"`
+}
+
+// ReturnableLine is sold or bought, returned and returnable quantity of the line of the original document
+type ReturnableLine struct {
+	LineNumber     int             `json:"linenumber"`
+	Barcode        string          `json:"barcode"`
+	ItemCode       string          `json:"itemcode"`
+	ItemNames      *[]models.NameX `json:"itemnames"`
+	UnitCode       string          `json:"unitcode"`
+	WhCode         string          `json:"whcode"`
+	LocationCode   string          `json:"locationcode"`
+	Price          float64         `json:"price"`
+	Discount       string          `json:"discount"`
+	DiscountAmount float64         `json:"discountamount"`
+	VatType        int8            `json:"vattype"`
+	TaxType        int8            `json:"taxtype"`
+	Qty            float64         `json:"qty"`
+	ReturnedQty    float64         `json:"returnedqty"`
+	ReturnableQty  float64         `json:"returnableqty"`
+}
+
+type ReturnableDocument struct {
+	DocGuid     string           `json:"docguid"`
+	DocNo       string           `json:"docno"`
+	DocDatetime time.Time        `json:"docdatetime"`
+	Lines       []ReturnableLine `json:"lines"`
+}
+
+// ReturnedDocument keep quantity of lines of the original document which is returned by each return, version is
+// increased on every change so concurrent returns of the same document can not both take the remaining quantity
+type ReturnedDocument struct {
+	ID        primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ShopID    string             `json:"shopid" bson:"shopid"`
+	DocGuid   string             `json:"docguid" bson:"docguid"`
+	Returned  []ReturnedQty      `json:"returned" bson:"returned"`
+	Version   int64              `json:"version" bson:"version"`
+	UpdatedAt time.Time          `json:"updatedat" bson:"updatedat"`
+}
+
+func (ReturnedDocument) CollectionName() string {
+	return returnedDocumentCollectionName
+}
+
+// ReturnDocument is the return which lines return lines of original documents, lines of cancelled returns
+// return nothing and lines of POS returns may be sold without the original document
+type ReturnDocument struct {
+	ShopID     string
+	ReturnGuid string
+	DocNo      string
+	IsPOS      bool
+	IsCancel   bool
+	Details    []transmodels.Detail
+}
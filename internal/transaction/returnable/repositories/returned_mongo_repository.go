@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IReturnedQtyRepository is repository of returns, returned quantity of lines of original documents
+// is summed by return from saved returns other than excludeGuid
+type IReturnedQtyRepository interface {
+	FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]models.ReturnedQty, error)
+}
+
+type IReturnedDocumentRepository interface {
+	FindByDocs(ctx context.Context, shopID string, docGuids []string) ([]models.ReturnedDocument, error)
+	FindByReturn(ctx context.Context, shopID string, returnGuid string) ([]models.ReturnedDocument, error)
+	// Save replace returned quantity of the document when it is not changed since it is read by its version,
+	// false is returned when another return saved the document first
+	Save(ctx context.Context, doc models.ReturnedDocument) (bool, error)
+}
+
+type ReturnedDocumentRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewReturnedDocumentRepository(pst microservice.IPersisterMongo) *ReturnedDocumentRepository {
+	return &ReturnedDocumentRepository{
+		pst: pst,
+	}
+}
+
+func (repo ReturnedDocumentRepository) FindByDocs(ctx context.Context, shopID string, docGuids []string) ([]models.ReturnedDocument, error) {
+	docList := []models.ReturnedDocument{}
+	err := repo.pst.Find(ctx, &models.ReturnedDocument{}, bson.M{"shopid": shopID, "docguid": bson.M{"$in": docGuids}}, &docList)
+	if err != nil {
+		return []models.ReturnedDocument{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo ReturnedDocumentRepository) FindByReturn(ctx context.Context, shopID string, returnGuid string) ([]models.ReturnedDocument, error) {
+	docList := []models.ReturnedDocument{}
+	err := repo.pst.Find(ctx, &models.ReturnedDocument{}, bson.M{"shopid": shopID, "returned.returnguid": returnGuid}, &docList)
+	if err != nil {
+		return []models.ReturnedDocument{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo ReturnedDocumentRepository) Save(ctx context.Context, doc models.ReturnedDocument) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.ReturnedDocument{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":  doc.ShopID,
+		"docguid": doc.DocGuid,
+		"version": doc.Version,
+	}
+
+	doc.ID = primitive.NilObjectID
+	doc.Version++
+
+	// the first return insert the document, unique index reject the other one of concurrent first returns
+	result, err := collection.ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(doc.Version == 1))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0 || result.UpsertedCount > 0, nil
+}
@@ -0,0 +1,42 @@
+package returnable
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/linereference"
+	"smlaicloudplatform/internal/transaction/returnable/repositories"
+	"smlaicloudplatform/internal/transaction/returnable/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// InitReturnLedger return returned quantity of original documents, returnRepo sum quantity of saved returns
+// of documents which returned quantity is not kept yet
+func InitReturnLedger(ms *microservice.Microservice, cfg config.IConfig, returnRepo repositories.IReturnedQtyRepository) services.IReturnLedger {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewReturnLedger(
+		repositories.NewReturnedDocumentRepository(pst),
+		returnRepo,
+		ms.TimeNow,
+	)
+}
+
+// ResponseReturnError write response of error of the return, errors of lines are sent in data
+func ResponseReturnError(ctx microservice.IContext, err error) {
+	returnErr := linereference.Error{}
+
+	switch {
+	case errors.As(err, &returnErr):
+		ctx.Response(http.StatusBadRequest, common.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+			Data:    returnErr.Lines,
+		})
+	case errors.Is(err, services.ErrReturnConflict):
+		ctx.ResponseError(http.StatusConflict, err.Error())
+	default:
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,35 @@
+package returnable
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of returned quantity of original documents, unique index keep one document
+// per original document
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	collection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.ReturnedDocument{})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "docguid", Value: 1}},
+			Options: options.Index().SetName("returneddocument_shopid_docguid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "returned.returnguid", Value: 1}},
+			Options: options.Index().SetName("returneddocument_shopid_returnguid"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+	"smlaicloudplatform/internal/transaction/returnable/repositories"
+	"time"
+)
+
+var ErrReturnConflict = errors.New("original document is returned by another return at the same time, please try again")
+
+// maxReturnSaveAttempts is number of attempts to save returned quantity when original documents are changed by concurrent returns
+const maxReturnSaveAttempts = 3
+
+type IReturnLedger interface {
+	// Return validate lines of the return against quantity which is not returned by other returns and keep
+	// its returned quantity, nothing is changed when any line can not be returned
+	Return(ctx context.Context, doc models.ReturnDocument, sources []models.SourceDocument) ([]trans_models.Detail, error)
+	// ReturnInBatch return lines of every return, returns which are kept are released when any of them can not be returned
+	ReturnInBatch(ctx context.Context, docs []models.ReturnDocument, sources []models.SourceDocument) ([][]trans_models.Detail, error)
+	// Record keep returned quantity of lines of the saved return without validation, it put back lines of the return
+	// when the return can not be updated
+	Record(ctx context.Context, doc models.ReturnDocument) error
+	// Release remove returned quantity of the return from original documents
+	Release(ctx context.Context, shopID string, returnGuid string) error
+}
+
+// ReturnLedger keep returned quantity of original documents by their version, so returns which
+// are saved at the same time can not both return the remaining quantity
+type ReturnLedger struct {
+	repo       repositories.IReturnedDocumentRepository
+	returnRepo repositories.IReturnedQtyRepository
+	timeNow    func() time.Time
+}
+
+func NewReturnLedger(
+	repo repositories.IReturnedDocumentRepository,
+	returnRepo repositories.IReturnedQtyRepository,
+	timeNow func() time.Time,
+) *ReturnLedger {
+	return &ReturnLedger{
+		repo:       repo,
+		returnRepo: returnRepo,
+		timeNow:    timeNow,
+	}
+}
+
+// ReturnedLines return quantity of lines of original documents which is returned by lines of the return
+func ReturnedLines(doc models.ReturnDocument) []models.ReturnedQty {
+	lines := []models.ReturnedQty{}
+	if doc.IsCancel {
+		return lines
+	}
+
+	for _, detail := range doc.Details {
+		if detail.RefDocGuid == "" {
+			continue
+		}
+
+		lines = append(lines, models.ReturnedQty{
+			ReturnGuid: doc.ReturnGuid,
+			DocGuid:    detail.RefDocGuid,
+			LineNumber: detail.RefLineNumber,
+			Qty:        detail.Qty,
+		})
+	}
+	return lines
+}
+
+func withoutReturn(returned []models.ReturnedQty, returnGuid string) []models.ReturnedQty {
+	result := []models.ReturnedQty{}
+	for _, line := range returned {
+		if line.ReturnGuid != returnGuid {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func linesOfReturn(docs []models.ReturnedDocument, returnGuid string) []models.ReturnedQty {
+	lines := []models.ReturnedQty{}
+	for _, doc := range docs {
+		for _, line := range doc.Returned {
+			if line.ReturnGuid == returnGuid {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+func lineDocGuids(lines []models.ReturnedQty) []string {
+	guids := []string{}
+	found := map[string]bool{}
+	for _, line := range lines {
+		if found[line.DocGuid] {
+			continue
+		}
+		found[line.DocGuid] = true
+		guids = append(guids, line.DocGuid)
+	}
+	return guids
+}
+
+func (svc ReturnLedger) Return(ctx context.Context, doc models.ReturnDocument, sources []models.SourceDocument) ([]trans_models.Detail, error) {
+	if doc.IsCancel {
+		return doc.Details, svc.Release(ctx, doc.ShopID, doc.ReturnGuid)
+	}
+
+	previousList, err := svc.repo.FindByReturn(ctx, doc.ShopID, doc.ReturnGuid)
+	if err != nil {
+		return []trans_models.Detail{}, err
+	}
+
+	isChanged := false
+	for attempt := 0; attempt < maxReturnSaveAttempts; attempt++ {
+		var details []trans_models.Detail
+		var isSaved bool
+
+		details, isSaved, err = svc.returnLines(ctx, doc, sources)
+		isChanged = isChanged || isSaved
+
+		if err == nil {
+			return details, nil
+		}
+
+		if !errors.Is(err, ErrReturnConflict) {
+			break
+		}
+	}
+
+	// documents which are saved before the error keep the quantity which is returned by the return before
+	if isChanged {
+		restoreErr := svc.record(ctx, doc.ShopID, doc.ReturnGuid, linesOfReturn(previousList, doc.ReturnGuid))
+		if restoreErr != nil {
+			return []trans_models.Detail{}, fmt.Errorf("%w, returned quantity is not restored: %v", err, restoreErr)
+		}
+	}
+
+	return []trans_models.Detail{}, err
+}
+
+func (svc ReturnLedger) returnLines(ctx context.Context, doc models.ReturnDocument, sources []models.SourceDocument) ([]trans_models.Detail, bool, error) {
+	returnedDocs, err := svc.returnedDocuments(ctx, doc.ShopID, doc.ReturnGuid, SourceGuids(doc.Details))
+	if err != nil {
+		return []trans_models.Detail{}, false, err
+	}
+
+	returned := []models.ReturnedQty{}
+	for _, returnedDoc := range returnedDocs {
+		returned = append(returned, withoutReturn(returnedDoc.Returned, doc.ReturnGuid)...)
+	}
+
+	details, err := PrepareReturnLines(sources, returned, doc.Details, doc.IsPOS)
+	if err != nil {
+		return []trans_models.Detail{}, false, err
+	}
+
+	doc.Details = details
+	isChanged, err := svc.save(ctx, doc.ReturnGuid, returnedDocs, ReturnedLines(doc))
+	if err != nil {
+		return []trans_models.Detail{}, isChanged, err
+	}
+
+	return details, isChanged, nil
+}
+
+func (svc ReturnLedger) ReturnInBatch(ctx context.Context, docs []models.ReturnDocument, sources []models.SourceDocument) ([][]trans_models.Detail, error) {
+	result := [][]trans_models.Detail{}
+	for idx, doc := range docs {
+		details, err := svc.Return(ctx, doc, sources)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", doc.DocNo, err)
+
+			for _, returnedDoc := range docs[:idx] {
+				releaseErr := svc.Release(ctx, returnedDoc.ShopID, returnedDoc.ReturnGuid)
+				if releaseErr != nil {
+					return [][]trans_models.Detail{}, fmt.Errorf("%w, returned quantity of %s is not released: %v", err, returnedDoc.DocNo, releaseErr)
+				}
+			}
+
+			return [][]trans_models.Detail{}, err
+		}
+		result = append(result, details)
+	}
+	return result, nil
+}
+
+func (svc ReturnLedger) Record(ctx context.Context, doc models.ReturnDocument) error {
+	return svc.record(ctx, doc.ShopID, doc.ReturnGuid, ReturnedLines(doc))
+}
+
+func (svc ReturnLedger) Release(ctx context.Context, shopID string, returnGuid string) error {
+	return svc.record(ctx, shopID, returnGuid, []models.ReturnedQty{})
+}
+
+func (svc ReturnLedger) record(ctx context.Context, shopID string, returnGuid string, lines []models.ReturnedQty) error {
+	for attempt := 0; attempt < maxReturnSaveAttempts; attempt++ {
+		returnedDocs, err := svc.returnedDocuments(ctx, shopID, returnGuid, lineDocGuids(lines))
+		if err != nil {
+			return err
+		}
+
+		_, err = svc.save(ctx, returnGuid, returnedDocs, lines)
+		if !errors.Is(err, ErrReturnConflict) {
+			return err
+		}
+	}
+
+	return ErrReturnConflict
+}
+
+// returnedDocuments return returned quantity of documents which are returned by the return before and of docGuids,
+// documents which are not kept yet start from quantity of saved returns
+func (svc ReturnLedger) returnedDocuments(ctx context.Context, shopID string, returnGuid string, docGuids []string) ([]models.ReturnedDocument, error) {
+	docs, err := svc.repo.FindByReturn(ctx, shopID, returnGuid)
+	if err != nil {
+		return []models.ReturnedDocument{}, err
+	}
+
+	found := map[string]bool{}
+	for _, doc := range docs {
+		found[doc.DocGuid] = true
+	}
+
+	newGuids := []string{}
+	for _, docGuid := range docGuids {
+		if !found[docGuid] {
+			found[docGuid] = true
+			newGuids = append(newGuids, docGuid)
+		}
+	}
+
+	if len(newGuids) == 0 {
+		return docs, nil
+	}
+
+	savedList, err := svc.repo.FindByDocs(ctx, shopID, newGuids)
+	if err != nil {
+		return []models.ReturnedDocument{}, err
+	}
+
+	saved := map[string]models.ReturnedDocument{}
+	for _, doc := range savedList {
+		saved[doc.DocGuid] = doc
+	}
+
+	missingGuids := []string{}
+	for _, docGuid := range newGuids {
+		doc, ok := saved[docGuid]
+		if !ok {
+			missingGuids = append(missingGuids, docGuid)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(missingGuids) == 0 {
+		return docs, nil
+	}
+
+	returned, err := svc.returnRepo.FindReturnedQty(ctx, shopID, missingGuids, "")
+	if err != nil {
+		return []models.ReturnedDocument{}, err
+	}
+
+	for _, docGuid := range missingGuids {
+		doc := models.ReturnedDocument{
+			ShopID:   shopID,
+			DocGuid:  docGuid,
+			Returned: []models.ReturnedQty{},
+		}
+
+		for _, line := range returned {
+			if line.DocGuid == docGuid {
+				doc.Returned = append(doc.Returned, line)
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// save replace returned quantity of the return in documents, true is returned when any document is saved
+func (svc ReturnLedger) save(ctx context.Context, returnGuid string, docs []models.ReturnedDocument, lines []models.ReturnedQty) (bool, error) {
+	isChanged := false
+	for _, doc := range docs {
+		returned := withoutReturn(doc.Returned, returnGuid)
+		for _, line := range lines {
+			if line.DocGuid == doc.DocGuid {
+				returned = append(returned, line)
+			}
+		}
+
+		doc.Returned = returned
+		doc.UpdatedAt = svc.timeNow()
+
+		isSaved, err := svc.repo.Save(ctx, doc)
+		if err != nil {
+			return isChanged, err
+		}
+
+		if !isSaved {
+			return isChanged, ErrReturnConflict
+		}
+		isChanged = true
+	}
+
+	return isChanged, nil
+}
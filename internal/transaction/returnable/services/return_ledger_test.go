@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+	"smlaicloudplatform/internal/transaction/returnable/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ReturnedDocumentRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReturnedDocumentRepositoryMock) FindByDocs(ctx context.Context, shopID string, docGuids []string) ([]models.ReturnedDocument, error) {
+	args := m.Called(shopID, docGuids)
+	return args.Get(0).([]models.ReturnedDocument), args.Error(1)
+}
+
+func (m *ReturnedDocumentRepositoryMock) FindByReturn(ctx context.Context, shopID string, returnGuid string) ([]models.ReturnedDocument, error) {
+	args := m.Called(shopID, returnGuid)
+	return args.Get(0).([]models.ReturnedDocument), args.Error(1)
+}
+
+func (m *ReturnedDocumentRepositoryMock) Save(ctx context.Context, doc models.ReturnedDocument) (bool, error) {
+	args := m.Called(doc)
+	return args.Bool(0), args.Error(1)
+}
+
+type ReturnedQtyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReturnedQtyRepositoryMock) FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]models.ReturnedQty, error) {
+	args := m.Called(shopID, docGuids, excludeGuid)
+	return args.Get(0).([]models.ReturnedQty), args.Error(1)
+}
+
+func returnDocument(qty float64) models.ReturnDocument {
+	return models.ReturnDocument{
+		ShopID:     "shop1",
+		ReturnGuid: "sr2",
+		Details: []trans_models.Detail{
+			{LineNumber: 1, RefDocGuid: "si1", RefLineNumber: 1, Qty: qty},
+		},
+	}
+}
+
+func TestReturnLedger_Return(t *testing.T) {
+	repo := new(ReturnedDocumentRepositoryMock)
+	returnRepo := new(ReturnedQtyRepositoryMock)
+
+	repo.On("FindByReturn", "shop1", "sr2").Return([]models.ReturnedDocument{}, nil)
+	repo.On("FindByDocs", "shop1", []string{"si1"}).Return([]models.ReturnedDocument{}, nil)
+
+	// quantity of the invoice which is returned before it is kept start from saved returns
+	returnRepo.On("FindReturnedQty", "shop1", []string{"si1"}, "").Return([]models.ReturnedQty{
+		{ReturnGuid: "sr1", DocGuid: "si1", LineNumber: 1, Qty: 6},
+	}, nil)
+
+	repo.On("Save", mock.MatchedBy(func(doc models.ReturnedDocument) bool {
+		return doc.DocGuid == "si1" && doc.Version == 0 && len(doc.Returned) == 2
+	})).Return(true, nil)
+
+	ledger := services.NewReturnLedger(repo, returnRepo, time.Now)
+
+	_, err := ledger.Return(context.Background(), returnDocument(5), []models.SourceDocument{sourceDocument()})
+	require.ErrorAs(t, err, &linereference.Error{})
+	repo.AssertNotCalled(t, "Save", mock.Anything)
+
+	details, err := ledger.Return(context.Background(), returnDocument(4), []models.SourceDocument{sourceDocument()})
+	require.NoError(t, err)
+	assert.Equal(t, "B1", details[0].Barcode)
+	repo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestReturnLedger_ReturnConflict(t *testing.T) {
+	repo := new(ReturnedDocumentRepositoryMock)
+	returnRepo := new(ReturnedQtyRepositoryMock)
+
+	repo.On("FindByReturn", "shop1", "sr2").Return([]models.ReturnedDocument{}, nil)
+	repo.On("FindByDocs", "shop1", []string{"si1"}).Return([]models.ReturnedDocument{
+		{ShopID: "shop1", DocGuid: "si1", Version: 3, Returned: []models.ReturnedQty{
+			{ReturnGuid: "sr1", DocGuid: "si1", LineNumber: 1, Qty: 6},
+		}},
+	}, nil)
+
+	// another return save the invoice first every time
+	repo.On("Save", mock.Anything).Return(false, nil)
+
+	ledger := services.NewReturnLedger(repo, returnRepo, time.Now)
+
+	_, err := ledger.Return(context.Background(), returnDocument(4), []models.SourceDocument{sourceDocument()})
+	require.ErrorIs(t, err, services.ErrReturnConflict)
+	repo.AssertNumberOfCalls(t, "Save", 3)
+	returnRepo.AssertNotCalled(t, "FindReturnedQty", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+)
+
+// SourceGuids return guid of original documents which are referenced by lines of the return
+func SourceGuids(details []trans_models.Detail) []string {
+	guids := []string{}
+	found := map[string]bool{}
+	for _, detail := range details {
+		if detail.RefDocGuid == "" || found[detail.RefDocGuid] {
+			continue
+		}
+		found[detail.RefDocGuid] = true
+		guids = append(guids, detail.RefDocGuid)
+	}
+	return guids
+}
+
+func returnedKey(docGuid string, lineNumber int) string {
+	return fmt.Sprintf("%s:%d", docGuid, lineNumber)
+}
+
+func returnedQty(returned []models.ReturnedQty) map[string]float64 {
+	result := map[string]float64{}
+	for _, line := range returned {
+		result[returnedKey(line.DocGuid, line.LineNumber)] += line.Qty
+	}
+	return result
+}
+
+// ReturnableLines return quantity of lines of the original document which is returned and can be returned
+func ReturnableLines(source models.SourceDocument, returned []models.ReturnedQty) models.ReturnableDocument {
+	returnedByLine := returnedQty(returned)
+
+	doc := models.ReturnableDocument{
+		DocGuid:     source.GuidFixed,
+		DocNo:       source.DocNo,
+		DocDatetime: source.DocDatetime,
+		Lines:       []models.ReturnableLine{},
+	}
+
+	for _, detail := range linereference.Lines(&source.Details) {
+		qty := returnedByLine[returnedKey(source.GuidFixed, detail.LineNumber)]
+
+		doc.Lines = append(doc.Lines, models.ReturnableLine{
+			LineNumber:     detail.LineNumber,
+			Barcode:        detail.Barcode,
+			ItemCode:       detail.ItemCode,
+			ItemNames:      detail.ItemNames,
+			UnitCode:       detail.UnitCode,
+			WhCode:         detail.WhCode,
+			LocationCode:   detail.LocationCode,
+			Price:          detail.Price,
+			Discount:       detail.Discount,
+			DiscountAmount: detail.DiscountAmount,
+			VatType:        detail.VatType,
+			TaxType:        detail.TaxType,
+			Qty:            detail.Qty,
+			ReturnedQty:    qty,
+			ReturnableQty:  math.Max(detail.Qty-qty, 0),
+		})
+	}
+
+	return doc
+}
+
+// PrepareReturnLines validate lines of the return which reference lines of original documents and default them
+// from the original line. Quantity of the line must not be more than its quantity which is not returned by
+// other returns, returned is quantity of saved returns without the return itself. Item and unit are taken from
+// the original line when barcode is empty, price, amount and discount when they are not given, and vat and tax type
+// always follow the original line. Lines must reference the original line, only lines of POS returns which do not
+// reference any document are kept as they are.
+func PrepareReturnLines(sources []models.SourceDocument, returned []models.ReturnedQty, details []trans_models.Detail, isPOS bool) ([]trans_models.Detail, error) {
+	sourceDocs := map[string]models.SourceDocument{}
+	for _, source := range sources {
+		source.Details = linereference.Lines(&source.Details)
+		sourceDocs[source.GuidFixed] = source
+	}
+
+	returnedByLine := returnedQty(returned)
+
+	lineErrors := []linereference.LineError{}
+	lineError := func(detail trans_models.Detail, format string, args ...interface{}) {
+		lineErrors = append(lineErrors, linereference.LineError{LineNumber: detail.LineNumber, Message: fmt.Sprintf(format, args...)})
+	}
+
+	result := []trans_models.Detail{}
+	for _, detail := range details {
+		if detail.RefDocGuid == "" {
+			if !isPOS {
+				lineError(detail, "original document is required")
+				continue
+			}
+			result = append(result, detail)
+			continue
+		}
+
+		source, ok := sourceDocs[detail.RefDocGuid]
+		if !ok {
+			lineError(detail, "original document is not found")
+			continue
+		}
+
+		if source.IsCancel {
+			lineError(detail, "%s is cancelled", source.DocNo)
+			continue
+		}
+
+		sourceLine, ok := linereference.FindLine(source.Details, detail.RefLineNumber)
+		if !ok {
+			lineError(detail, "%s line %d is not found", source.DocNo, detail.RefLineNumber)
+			continue
+		}
+
+		if detail.Barcode == "" {
+			detail.Barcode = sourceLine.Barcode
+			detail.ItemGuid = sourceLine.ItemGuid
+			detail.ItemCode = sourceLine.ItemCode
+			detail.ItemNames = sourceLine.ItemNames
+			detail.ItemType = sourceLine.ItemType
+			detail.UnitCode = sourceLine.UnitCode
+			detail.UnitNames = sourceLine.UnitNames
+		}
+
+		if detail.Barcode != sourceLine.Barcode {
+			lineError(detail, "%s line %d is barcode %s, not %s", source.DocNo, sourceLine.LineNumber, sourceLine.Barcode, detail.Barcode)
+			continue
+		}
+
+		if detail.UnitCode != "" && sourceLine.UnitCode != "" && detail.UnitCode != sourceLine.UnitCode {
+			lineError(detail, "%s line %d is unit %s, not %s", source.DocNo, sourceLine.LineNumber, sourceLine.UnitCode, detail.UnitCode)
+			continue
+		}
+
+		if detail.Qty <= 0 {
+			lineError(detail, "must be returned more than 0")
+			continue
+		}
+
+		key := returnedKey(source.GuidFixed, sourceLine.LineNumber)
+		returnableQty := sourceLine.Qty - returnedByLine[key]
+		if detail.Qty > returnableQty+linereference.QtyPrecision {
+			lineError(detail, "%s line %d can be returned %v more, not %v", source.DocNo, sourceLine.LineNumber, math.Max(returnableQty, 0), detail.Qty)
+			continue
+		}
+		returnedByLine[key] += detail.Qty
+
+		ratio := 0.0
+		if sourceLine.Qty > linereference.QtyPrecision {
+			ratio = detail.Qty / sourceLine.Qty
+		}
+
+		isDefaultPrice := detail.Price == 0
+		if isDefaultPrice {
+			detail.Price = sourceLine.Price
+			detail.PriceExcludeVat = sourceLine.PriceExcludeVat
+		}
+
+		if detail.DiscountAmount == 0 {
+			detail.Discount = sourceLine.Discount
+			detail.DiscountAmount = linereference.RoundAmount(sourceLine.DiscountAmount * ratio)
+		}
+
+		detail.VatType = sourceLine.VatType
+		detail.TaxType = sourceLine.TaxType
+		detail.VatCal = sourceLine.VatCal
+
+		// amount of the line which is returned at the original price is in proportion of the original amount
+		if isDefaultPrice && detail.SumAmount == 0 {
+			detail.SumAmount = linereference.RoundAmount(sourceLine.SumAmount * ratio)
+			detail.SumAmountExcludeVat = linereference.RoundAmount(sourceLine.SumAmountExcludeVat * ratio)
+			detail.TotalValueVat = linereference.RoundAmount(sourceLine.TotalValueVat * ratio)
+		}
+
+		detail.DocRef = source.DocNo
+		detail.DocRefDatetime = source.DocDatetime
+
+		result = append(result, detail)
+	}
+
+	if len(lineErrors) > 0 {
+		return []trans_models.Detail{}, linereference.Error{Lines: lineErrors}
+	}
+
+	return result, nil
+}
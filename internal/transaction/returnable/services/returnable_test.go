@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/returnable/models"
+	"smlaicloudplatform/internal/transaction/returnable/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sourceDocument() models.SourceDocument {
+	return models.SourceDocument{
+		GuidFixed:   "si1",
+		DocNo:       "SI-001",
+		DocDatetime: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Details: []trans_models.Detail{
+			{
+				LineNumber:      1,
+				Barcode:         "B1",
+				ItemCode:        "I1",
+				UnitCode:        "PCS",
+				Qty:             10,
+				Price:           100,
+				PriceExcludeVat: 93.46,
+				Discount:        "5",
+				DiscountAmount:  50,
+				SumAmount:       950,
+				VatType:         1,
+				TaxType:         0,
+				VatCal:          1,
+			},
+			{
+				LineNumber: 2,
+				Barcode:    "B2",
+				UnitCode:   "BOX",
+				Qty:        2,
+				Price:      300,
+				SumAmount:  600,
+			},
+		},
+	}
+}
+
+func TestPrepareReturnLines(t *testing.T) {
+	returned := []models.ReturnedQty{
+		{DocGuid: "si1", LineNumber: 1, Qty: 6},
+	}
+
+	details, err := services.PrepareReturnLines([]models.SourceDocument{sourceDocument()}, returned, []trans_models.Detail{
+		{LineNumber: 1, RefDocGuid: "si1", RefLineNumber: 1, Qty: 2, VatType: 0},
+		{LineNumber: 2, RefDocGuid: "si1", RefLineNumber: 2, Barcode: "B2", Qty: 1, Price: 280, SumAmount: 280},
+		{LineNumber: 3, Barcode: "B9", Qty: 1, Price: 10},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, details, 3)
+
+	// item, price, discount, amount and vat are taken from the original line
+	assert.Equal(t, "B1", details[0].Barcode)
+	assert.Equal(t, "I1", details[0].ItemCode)
+	assert.Equal(t, 100.0, details[0].Price)
+	assert.Equal(t, "5", details[0].Discount)
+	assert.Equal(t, 10.0, details[0].DiscountAmount)
+	assert.Equal(t, 190.0, details[0].SumAmount)
+	assert.Equal(t, int8(1), details[0].VatType)
+	assert.Equal(t, "SI-001", details[0].DocRef)
+
+	// given price is kept
+	assert.Equal(t, 280.0, details[1].Price)
+	assert.Equal(t, 280.0, details[1].SumAmount)
+
+	// line of POS return which does not reference the invoice is kept
+	assert.Equal(t, "B9", details[2].Barcode)
+	assert.Empty(t, details[2].DocRef)
+}
+
+func TestPrepareReturnLinesError(t *testing.T) {
+	source := sourceDocument()
+	cancelled := models.SourceDocument{GuidFixed: "si2", DocNo: "SI-002", IsCancel: true}
+
+	returned := []models.ReturnedQty{
+		{DocGuid: "si1", LineNumber: 1, Qty: 6},
+	}
+
+	_, err := services.PrepareReturnLines([]models.SourceDocument{source, cancelled}, returned, []trans_models.Detail{
+		{LineNumber: 1, RefDocGuid: "si1", RefLineNumber: 1, Barcode: "B1", Qty: 3},
+		// the second line of the same original line return more than the rest of it
+		{LineNumber: 2, RefDocGuid: "si1", RefLineNumber: 1, Barcode: "B1", Qty: 2},
+		{LineNumber: 3, RefDocGuid: "si1", RefLineNumber: 2, Barcode: "B1", Qty: 1},
+		{LineNumber: 4, RefDocGuid: "si1", RefLineNumber: 2, Barcode: "B2", UnitCode: "PCS", Qty: 1},
+		{LineNumber: 5, RefDocGuid: "si1", RefLineNumber: 9, Qty: 1},
+		{LineNumber: 6, RefDocGuid: "si2", RefLineNumber: 1, Qty: 1},
+		{LineNumber: 7, RefDocGuid: "si3", RefLineNumber: 1, Qty: 1},
+		{LineNumber: 8, RefDocGuid: "si1", RefLineNumber: 2, Qty: 0},
+		{LineNumber: 9, Barcode: "B9", Qty: 1},
+	}, false)
+
+	returnErr := linereference.Error{}
+	require.ErrorAs(t, err, &returnErr)
+
+	lineNumbers := []int{}
+	for _, line := range returnErr.Lines {
+		lineNumbers = append(lineNumbers, line.LineNumber)
+	}
+	assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9}, lineNumbers)
+	assert.Equal(t, "SI-001 line 1 can be returned 1 more, not 2", returnErr.Lines[0].Message)
+	assert.Equal(t, "original document is required", returnErr.Lines[7].Message)
+}
+
+func TestReturnableLines(t *testing.T) {
+	source := sourceDocument()
+	// lines without line number are numbered by their position
+	source.Details[0].LineNumber = 0
+	source.Details[1].LineNumber = 0
+
+	doc := services.ReturnableLines(source, []models.ReturnedQty{
+		{DocGuid: "si1", LineNumber: 1, Qty: 4},
+		{DocGuid: "si1", LineNumber: 2, Qty: 3},
+	})
+
+	require.Len(t, doc.Lines, 2)
+	assert.Equal(t, "SI-001", doc.DocNo)
+	assert.Equal(t, 1, doc.Lines[0].LineNumber)
+	assert.Equal(t, 4.0, doc.Lines[0].ReturnedQty)
+	assert.Equal(t, 6.0, doc.Lines[0].ReturnableQty)
+	assert.Equal(t, 2, doc.Lines[1].LineNumber)
+	assert.Equal(t, 0.0, doc.Lines[1].ReturnableQty)
+}
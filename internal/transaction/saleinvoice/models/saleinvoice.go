@@ -3,6 +3,8 @@ package models
 import (
 	"smlaicloudplatform/internal/models"
 	transmodels "smlaicloudplatform/internal/transaction/models"
+	saleordermodels "smlaicloudplatform/internal/transaction/saleorder/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -106,3 +108,10 @@ type SaleInvoiceDeleteActivity struct {
 func (SaleInvoiceDeleteActivity) CollectionName() string {
 	return saleinvoiceCollectionName
 }
+
+// SaleInvoiceFromSaleOrderRequest is quantity of lines of sale order which is delivered by sale invoice,
+// every remaining quantity is delivered when lines are empty
+type SaleInvoiceFromSaleOrderRequest struct {
+	DocDatetime time.Time                     `json:"docdatetime"`
+	Lines       []saleordermodels.DeliveryQty `json:"lines" validate:"dive"`
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoice/config"
 	"smlaicloudplatform/internal/transaction/saleinvoice/models"
@@ -14,6 +15,10 @@ type ISaleInvoiceMessageQueueRepository interface {
 	CreateInBatch(docList []models.SaleInvoiceDoc) error
 	UpdateInBatch(docList []models.SaleInvoiceDoc) error
 	DeleteInBatch(docList []models.SaleInvoiceDoc) error
+	OutboxCreate(ctx context.Context, doc models.SaleInvoiceDoc) error
+	OutboxUpdate(ctx context.Context, doc models.SaleInvoiceDoc) error
+	OutboxDelete(ctx context.Context, doc models.SaleInvoiceDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.SaleInvoiceDoc) error
 }
 
 type SaleInvoiceMessageQueueRepository struct {
@@ -32,3 +37,10 @@ func NewSaleInvoiceMessageQueueRepository(prod microservice.IProducer) SaleInvoi
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.SaleInvoiceDoc](prod, config.SaleInvoiceMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewSaleInvoiceOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewSaleInvoiceOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) SaleInvoiceMessageQueueRepository {
+	insRepo := NewSaleInvoiceMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -37,6 +37,7 @@ type ISaleInvoiceRepository interface {
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.SaleInvoiceDoc, error)
 	FindLastPOSDocNo(ctx context.Context, shopID string, posID string, maxDocNo string) (string, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type SaleInvoiceRepository struct {
@@ -61,6 +62,10 @@ func NewSaleInvoiceRepository(pst microservice.IPersisterMongo) *SaleInvoiceRepo
 	return insRepo
 }
 
+func (repo SaleInvoiceRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo SaleInvoiceRepository) FindLastPOSDocNo(ctx context.Context, shopID string, posID string, maxDocNo string) (string, error) {
 
 	opts := options.FindOneOptions{}
@@ -9,11 +9,15 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/saleinvoice/models"
 	"smlaicloudplatform/internal/transaction/saleinvoice/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoice/services"
+	"smlaicloudplatform/internal/transaction/saleorder"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/requestfilter"
 	"smlaicloudplatform/pkg/microservice"
@@ -38,16 +42,16 @@ func NewSaleInvoiceHttp(ms *microservice.Microservice, cfg config.IConfig) SaleI
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewSaleInvoiceRepository(pst)
-	repoMq := repositories.NewSaleInvoiceMessageQueueRepository(producer)
+	repoMq := repositories.NewSaleInvoiceOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trans_cache.NewCacheRepository(cache)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
 
 	svc := services.NewSaleInvoiceService(
 		repo,
-		transCacheRepo,
+		docsequence.InitDocNoSequencer(ms, cfg),
+		saleorder.InitSaleOrderDelivery(ms, cfg),
 		productBarcodeRepo,
 		repoMq,
 		masterSyncCacheRepo,
@@ -55,6 +59,8 @@ func NewSaleInvoiceHttp(ms *microservice.Microservice, cfg config.IConfig) SaleI
 		services.SaleInvocieExport{},
 	)
 
+	rbac.InitPermissionService(ms, cfg)
+
 	return SaleInvoiceHttp{
 		ms:  ms,
 		cfg: cfg,
@@ -64,18 +70,24 @@ func NewSaleInvoiceHttp(ms *microservice.Microservice, cfg config.IConfig) SaleI
 
 func (h SaleInvoiceHttp) RegisterHttp() {
 
-	h.ms.POST("/transaction/sale-invoice/bulk", h.SaveBulk)
-
-	h.ms.GET("/transaction/sale-invoice", h.SearchSaleInvoicePage)
-	h.ms.GET("/transaction/sale-invoice/list", h.SearchSaleInvoiceStep)
-	h.ms.POST("/transaction/sale-invoice", h.CreateSaleInvoice)
-	h.ms.GET("/transaction/sale-invoice/:id", h.InfoSaleInvoice)
-	h.ms.GET("/transaction/sale-invoice/last-pos-docno", h.GetLastPOSDocNo)
-	h.ms.GET("/transaction/sale-invoice/code/:code", h.InfoSaleInvoiceByCode)
-	h.ms.PUT("/transaction/sale-invoice/:id", h.UpdateSaleInvoice)
-	h.ms.DELETE("/transaction/sale-invoice/:id", h.DeleteSaleInvoice)
-	h.ms.DELETE("/transaction/sale-invoice", h.DeleteSaleInvoiceByGUIDs)
-	h.ms.GET("/transaction/sale-invoice/export", h.Export)
+	saleInvoiceRead := h.ms.RequirePermission(rbacmodels.PermissionSaleInvoiceRead)
+	saleInvoiceCreate := h.ms.RequirePermission(rbacmodels.PermissionSaleInvoiceCreate)
+	saleInvoiceUpdate := h.ms.RequirePermission(rbacmodels.PermissionSaleInvoiceUpdate)
+	saleInvoiceDelete := h.ms.RequirePermission(rbacmodels.PermissionSaleInvoiceDelete)
+
+	h.ms.POST("/transaction/sale-invoice/bulk", h.SaveBulk, saleInvoiceCreate)
+
+	h.ms.GET("/transaction/sale-invoice", h.SearchSaleInvoicePage, saleInvoiceRead)
+	h.ms.GET("/transaction/sale-invoice/list", h.SearchSaleInvoiceStep, saleInvoiceRead)
+	h.ms.POST("/transaction/sale-invoice", h.CreateSaleInvoice, saleInvoiceCreate)
+	h.ms.POST("/transaction/sale-invoice/from-sale-order/:id", h.CreateSaleInvoiceFromSaleOrder, saleInvoiceCreate)
+	h.ms.GET("/transaction/sale-invoice/:id", h.InfoSaleInvoice, saleInvoiceRead)
+	h.ms.GET("/transaction/sale-invoice/last-pos-docno", h.GetLastPOSDocNo, saleInvoiceRead)
+	h.ms.GET("/transaction/sale-invoice/code/:code", h.InfoSaleInvoiceByCode, saleInvoiceRead)
+	h.ms.PUT("/transaction/sale-invoice/:id", h.UpdateSaleInvoice, saleInvoiceUpdate)
+	h.ms.DELETE("/transaction/sale-invoice/:id", h.DeleteSaleInvoice, saleInvoiceDelete)
+	h.ms.DELETE("/transaction/sale-invoice", h.DeleteSaleInvoiceByGUIDs, saleInvoiceDelete)
+	h.ms.GET("/transaction/sale-invoice/export", h.Export, saleInvoiceRead)
 }
 
 // Create SaleInvoice godoc
@@ -105,13 +117,57 @@ func (h SaleInvoiceHttp) CreateSaleInvoice(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateSaleInvoice(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateSaleInvoice(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
+		saleorder.ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+		Data:    docNo,
+	})
+	return nil
+}
+
+// Create SaleInvoice From SaleOrder godoc
+// @Description create SaleInvoice which deliver lines of confirmed or partially delivered SaleOrder, every remaining quantity is delivered when lines are empty
+// @Tags		SaleInvoice
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Param		SaleInvoiceFromSaleOrderRequest  body      models.SaleInvoiceFromSaleOrderRequest  true  "quantity of lines of SaleOrder"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-invoice/from-sale-order/{id} [post]
+func (h SaleInvoiceHttp) CreateSaleInvoiceFromSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.SaleInvoiceFromSaleOrderRequest{}
+	if input := ctx.ReadInput(); input != "" {
+		err := json.Unmarshal([]byte(input), &docReq)
+
+		if err != nil {
+			ctx.ResponseError(http.StatusBadRequest, err.Error())
+			return err
+		}
+	}
+
+	if err := ctx.Validate(docReq); err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
 	}
 
+	idx, docNo, err := h.svc.CreateSaleInvoiceFromSaleOrder(ctx.Context(), userInfo.ShopID, userInfo.Username, id, *docReq)
+
+	if err != nil {
+		saleorder.ResponseDeliveryError(ctx, err)
+		return err
+	}
+
 	ctx.Response(http.StatusCreated, common.ApiResponse{
 		Success: true,
 		ID:      idx,
@@ -151,10 +207,10 @@ func (h SaleInvoiceHttp) UpdateSaleInvoice(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateSaleInvoice(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateSaleInvoice(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		saleorder.ResponseDeliveryError(ctx, err)
 		return err
 	}
 
@@ -182,10 +238,10 @@ func (h SaleInvoiceHttp) DeleteSaleInvoice(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSaleInvoice(shopID, id, authUsername)
+	err := h.svc.DeleteSaleInvoice(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		saleorder.ResponseDeliveryError(ctx, err)
 		return err
 	}
 
@@ -221,10 +277,10 @@ func (h SaleInvoiceHttp) DeleteSaleInvoiceByGUIDs(ctx microservice.IContext) err
 		return err
 	}
 
-	err = h.svc.DeleteSaleInvoiceByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSaleInvoiceByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		saleorder.ResponseDeliveryError(ctx, err)
 		return err
 	}
 
@@ -251,7 +307,7 @@ func (h SaleInvoiceHttp) InfoSaleInvoice(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SaleInvoice %v", id)
-	doc, err := h.svc.InfoSaleInvoice(shopID, id)
+	doc, err := h.svc.InfoSaleInvoice(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -281,7 +337,7 @@ func (h SaleInvoiceHttp) InfoSaleInvoiceByCode(ctx microservice.IContext) error
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSaleInvoiceByCode(shopID, code)
+	doc, err := h.svc.InfoSaleInvoiceByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -317,7 +373,7 @@ func (h SaleInvoiceHttp) GetLastPOSDocNo(ctx microservice.IContext) error {
 		return nil
 	}
 
-	doc, err := h.svc.GetLastPOSDocNo(shopID, posID, maxDocNo)
+	doc, err := h.svc.GetLastPOSDocNo(ctx.Context(), shopID, posID, maxDocNo)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -360,7 +416,7 @@ func (h SaleInvoiceHttp) SearchSaleInvoicePage(ctx microservice.IContext) error
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSaleInvoice(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchSaleInvoice(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -403,7 +459,7 @@ func (h SaleInvoiceHttp) SearchSaleInvoiceStep(ctx microservice.IContext) error
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchSaleInvoiceStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchSaleInvoiceStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -443,7 +499,7 @@ func (h SaleInvoiceHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -509,7 +565,7 @@ func (h SaleInvoiceHttp) Export(ctx microservice.IContext) error {
 		languageHeader[key] = ctx.QueryParam(key)
 	}
 
-	results, err := h.svc.Export(shopID, languageCode, languageHeader)
+	results, err := h.svc.Export(ctx.Context(), shopID, languageCode, languageHeader)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -9,15 +9,16 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoice/models"
 	"smlaicloudplatform/internal/transaction/saleinvoice/repositories"
+	saleordermodels "smlaicloudplatform/internal/transaction/saleorder/models"
+	saleorderservices "smlaicloudplatform/internal/transaction/saleorder/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,19 +26,20 @@ import (
 )
 
 type ISaleInvoiceService interface {
-	CreateSaleInvoice(shopID string, authUsername string, doc models.SaleInvoice) (string, string, error)
-	UpdateSaleInvoice(shopID string, guid string, authUsername string, doc models.SaleInvoice) error
-	UpdateSlip(shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error
-
-	DeleteSaleInvoice(shopID string, guid string, authUsername string) error
-	DeleteSaleInvoiceByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSaleInvoice(shopID string, guid string) (models.SaleInvoiceInfo, error)
-	InfoSaleInvoiceByCode(shopID string, code string) (models.SaleInvoiceInfo, error)
-	SearchSaleInvoice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceInfo, mongopagination.PaginationData, error)
-	SearchSaleInvoiceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SaleInvoice) (common.BulkImport, error)
-	GetLastPOSDocNo(shopID, posID, maxDocNo string) (string, error)
-	Export(languageCode string, shopID string, languageHeader map[string]string) ([][]string, error)
+	CreateSaleInvoice(ctx context.Context, shopID string, authUsername string, doc models.SaleInvoice) (string, string, error)
+	CreateSaleInvoiceFromSaleOrder(ctx context.Context, shopID string, authUsername string, orderGuid string, req models.SaleInvoiceFromSaleOrderRequest) (string, string, error)
+	UpdateSaleInvoice(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleInvoice) error
+	UpdateSlip(ctx context.Context, shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error
+
+	DeleteSaleInvoice(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSaleInvoiceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSaleInvoice(ctx context.Context, shopID string, guid string) (models.SaleInvoiceInfo, error)
+	InfoSaleInvoiceByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceInfo, error)
+	SearchSaleInvoice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceInfo, mongopagination.PaginationData, error)
+	SearchSaleInvoiceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleInvoice) (common.BulkImport, error)
+	GetLastPOSDocNo(ctx context.Context, shopID, posID, maxDocNo string) (string, error)
+	Export(ctx context.Context, languageCode string, shopID string, languageHeader map[string]string) ([][]string, error)
 
 	GetModuleName() string
 }
@@ -58,9 +60,9 @@ type ISaleInvoiceExport interface {
 type SaleInvoiceService struct {
 	repoMq             repositories.ISaleInvoiceMessageQueueRepository
 	repo               repositories.ISaleInvoiceRepository
-	repoCache          trans_cache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
+	orderDelivery      saleorderservices.ISaleOrderDelivery
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.SaleInvoiceActivity, models.SaleInvoiceDeleteActivity]
 	parser         ISaleInvocieParser
@@ -70,7 +72,8 @@ type SaleInvoiceService struct {
 
 func NewSaleInvoiceService(
 	repo repositories.ISaleInvoiceRepository,
-	repoCache trans_cache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	orderDelivery saleorderservices.ISaleOrderDelivery,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.ISaleInvoiceMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -83,12 +86,12 @@ func NewSaleInvoiceService(
 	insSvc := &SaleInvoiceService{
 		repo:               repo,
 		repoMq:             repoMq,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
+		orderDelivery:      orderDelivery,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
 		exporter:           exporter,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -97,91 +100,24 @@ func NewSaleInvoiceService(
 	return insSvc
 }
 
-func (svc SaleInvoiceService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SaleInvoiceService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SaleInvoiceService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc SaleInvoiceService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
+func (svc SaleInvoiceService) CreateSaleInvoice(ctx context.Context, shopID string, authUsername string, doc models.SaleInvoice) (string, string, error) {
 
-func (svc SaleInvoiceService) CreateSaleInvoice(shopID string, authUsername string, doc models.SaleInvoice) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	isGenerateDocNo := !doc.IsPOS
 
-	prefixDocNo, docNo, newDocNumber := "", "", 0
-
-	if isGenerateDocNo {
-		docDate := doc.DocDatetime
-		prefixDocNo = svc.getDocNoPrefix(docDate)
-
-		tempNewDocNo, tempNewDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-		if err != nil {
-			return "", "", err
-		}
-
-		docNo = tempNewDocNo
-		newDocNumber = tempNewDocNumber
-	} else {
-		if doc.DocNo == "" {
-			return "", "", errors.New("docno is required")
-		}
-
-		docNo = doc.DocNo
+	if !isGenerateDocNo && doc.DocNo == "" {
+		return "", "", errors.New("docno is required")
 	}
 
-	if isGenerateDocNo {
-		findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", docNo)
-
-		if err != nil {
-			return "", "", err
-		}
-
-		if len(findDoc.GuidFixed) > 0 {
-			return "", "", errors.New("DocNo is exists")
-		}
+	productBarcodes, err := svc.GetDetailProductBarcodes(ctx, shopID, *doc.Details)
+	if err != nil {
+		return "", "", err
 	}
 
 	newGuidFixed := utils.NewGUID()
@@ -192,15 +128,6 @@ func (svc SaleInvoiceService) CreateSaleInvoice(shopID string, authUsername stri
 	dataDoc.SaleInvoice = doc
 
 	dataDoc.TransFlag = TRANS_FLAG
-	dataDoc.DocNo = docNo
-	if isGenerateDocNo && doc.TaxDocNo == "" {
-		dataDoc.TaxDocNo = docNo
-	}
-
-	productBarcodes, err := svc.GetDetailProductBarcodes(ctx, shopID, *doc.Details)
-	if err != nil {
-		return "", "", err
-	}
 
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
@@ -208,22 +135,49 @@ func (svc SaleInvoiceService) CreateSaleInvoice(shopID string, authUsername stri
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	save := func(docNo string) error {
+		dataDoc.DocNo = docNo
+		if isGenerateDocNo && doc.TaxDocNo == "" {
+			dataDoc.TaxDocNo = docNo
+		}
+
+		// lines which reference lines of sale orders deliver them
+		delivery := svc.deliveryDocument(shopID, newGuidFixed, docNo, dataDoc.SaleInvoice)
+		if len(delivery.Lines) > 0 {
+			err := svc.deliverOrders(ctx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+
+		if err != nil && len(delivery.Lines) > 0 {
+			svc.releaseOrders(ctx, shopID, newGuidFixed)
+		}
+		return err
+	}
+
+	docNo := doc.DocNo
+	if isGenerateDocNo {
+		docNo, err = svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, save)
+	} else {
+		err = save(docNo)
+	}
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		err := svc.repoMq.Create(dataDoc)
-		if err != nil {
-			fmt.Printf("create mq error :: %s", err.Error())
-		}
 		svc.saveMasterSync(shopID)
-
-		if isGenerateDocNo {
-			svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-		}
 	}()
 
 	return newGuidFixed, docNo, nil
@@ -257,9 +211,9 @@ func (svc SaleInvoiceService) PrepareDetail(details []trans_models.Detail, produ
 	return details
 }
 
-func (svc SaleInvoiceService) UpdateSaleInvoice(shopID string, guid string, authUsername string, doc models.SaleInvoice) error {
+func (svc SaleInvoiceService) UpdateSaleInvoice(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleInvoice) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -293,26 +247,36 @@ func (svc SaleInvoiceService) UpdateSaleInvoice(shopID string, guid string, auth
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.deliverOrders(ctx, svc.deliveryDocument(shopID, guid, findDoc.DocNo, dataDoc.SaleInvoice))
 
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		err := svc.repoMq.Update(dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
 		if err != nil {
-			fmt.Printf("create mq error :: %s", err.Error())
+			return err
 		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
+
+	if err != nil {
+		// deliveries of the saved invoice are given back
+		return svc.redeliverOrders(ctx, shopID, findDoc, err)
+	}
+
+	go func() {
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceService) UpdateSlip(shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error {
+func (svc SaleInvoiceService) UpdateSlip(ctx context.Context, shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", docNo)
@@ -347,26 +311,29 @@ func (svc SaleInvoiceService) UpdateSlip(shopID string, authUsername string, doc
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, findDoc.GuidFixed, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, findDoc.GuidFixed, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	go func() {
-		err := svc.repoMq.Update(dataDoc)
-		if err != nil {
-			fmt.Printf("create mq error :: %s", err.Error())
-		}
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceService) DeleteSaleInvoice(shopID string, guid string, authUsername string) error {
+func (svc SaleInvoiceService) DeleteSaleInvoice(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -379,44 +346,112 @@ func (svc SaleInvoiceService) DeleteSaleInvoice(shopID string, guid string, auth
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.releaseOrders(ctx, shopID, guid)
 	if err != nil {
 		return err
 	}
 
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
+
+	if err != nil {
+		return svc.redeliverOrders(ctx, shopID, findDoc, err)
+	}
+
 	go func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceService) DeleteSaleInvoiceByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SaleInvoiceService) DeleteSaleInvoiceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
+	findDocs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range findDocs {
+		err = svc.releaseOrders(ctx, shopID, doc.GuidFixed)
+		if err != nil {
+			return err
+		}
+	}
+
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, findDocs)
+	})
+
 	if err != nil {
+		for _, doc := range findDocs {
+			err = svc.redeliverOrders(ctx, shopID, doc, err)
+		}
 		return err
 	}
 
-	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
-		svc.saveMasterSync(shopID)
-	}()
+	svc.saveMasterSync(shopID)
 
 	return nil
 }
 
-func (svc SaleInvoiceService) GetLastPOSDocNo(shopID, posID, maxDocNo string) (string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+// CreateSaleInvoiceFromSaleOrder create sale invoice which deliver quantity of lines of the confirmed or partially delivered
+// order, every remaining quantity is delivered when lines are not given
+func (svc SaleInvoiceService) CreateSaleInvoiceFromSaleOrder(ctx context.Context, shopID string, authUsername string, orderGuid string, req models.SaleInvoiceFromSaleOrderRequest) (string, string, error) {
+
+	if svc.orderDelivery == nil {
+		return "", "", errors.New("sale order delivery is not available")
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	order, lines, err := svc.orderDelivery.FindDeliverable(ctx, shopID, orderGuid)
+	ctxCancel()
+
+	if err != nil {
+		return "", "", err
+	}
+
+	transaction, err := saleorderservices.DeliveryTransaction(order, lines, req.Lines)
+	if err != nil {
+		return "", "", err
+	}
+
+	doc := models.SaleInvoice{}
+	doc.PartitionIdentity = order.PartitionIdentity
+	doc.Transaction = transaction
+
+	doc.DocDatetime = time.Now()
+	if !req.DocDatetime.IsZero() {
+		doc.DocDatetime = req.DocDatetime
+	}
+
+	for i := range *doc.Details {
+		(*doc.Details)[i].DocDatetime = doc.DocDatetime
+	}
+
+	return svc.CreateSaleInvoice(ctx, shopID, authUsername, doc)
+}
+
+func (svc SaleInvoiceService) GetLastPOSDocNo(ctx context.Context, shopID, posID, maxDocNo string) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	lastDocNo, err := svc.repo.FindLastPOSDocNo(ctx, shopID, posID, maxDocNo)
@@ -428,9 +463,9 @@ func (svc SaleInvoiceService) GetLastPOSDocNo(shopID, posID, maxDocNo string) (s
 	return lastDocNo, nil
 }
 
-func (svc SaleInvoiceService) InfoSaleInvoice(shopID string, guid string) (models.SaleInvoiceInfo, error) {
+func (svc SaleInvoiceService) InfoSaleInvoice(ctx context.Context, shopID string, guid string) (models.SaleInvoiceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -446,9 +481,9 @@ func (svc SaleInvoiceService) InfoSaleInvoice(shopID string, guid string) (model
 	return findDoc.SaleInvoiceInfo, nil
 }
 
-func (svc SaleInvoiceService) InfoSaleInvoiceByCode(shopID string, code string) (models.SaleInvoiceInfo, error) {
+func (svc SaleInvoiceService) InfoSaleInvoiceByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -464,9 +499,9 @@ func (svc SaleInvoiceService) InfoSaleInvoiceByCode(shopID string, code string)
 	return findDoc.SaleInvoiceInfo, nil
 }
 
-func (svc SaleInvoiceService) SearchSaleInvoice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceInfo, mongopagination.PaginationData, error) {
+func (svc SaleInvoiceService) SearchSaleInvoice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -536,9 +571,9 @@ func (svc SaleInvoiceService) SearchSaleInvoice(shopID string, filters map[strin
 	return docList, pagination, nil
 }
 
-func (svc SaleInvoiceService) SearchSaleInvoiceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceInfo, int, error) {
+func (svc SaleInvoiceService) SearchSaleInvoiceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -556,9 +591,9 @@ func (svc SaleInvoiceService) SearchSaleInvoiceStep(shopID string, langCode stri
 	return docList, total, nil
 }
 
-func (svc SaleInvoiceService) SaveInBatch(shopID string, authUsername string, dataList []models.SaleInvoice) (common.BulkImport, error) {
+func (svc SaleInvoiceService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleInvoice) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SaleInvoice](dataList, svc.getDocIDKey)
@@ -615,6 +650,13 @@ func (svc SaleInvoiceService) SaveInBatch(shopID string, authUsername string, da
 		},
 		func(shopID string, authUsername string, data models.SaleInvoice, doc models.SaleInvoiceDoc) error {
 
+			err := svc.deliverOrders(ctx, svc.deliveryDocument(shopID, doc.GuidFixed, doc.DocNo, data))
+			if err != nil {
+				return err
+			}
+
+			findDoc := doc
+
 			doc.SaleInvoice = data
 			doc.TransFlag = TRANS_FLAG
 			doc.UpdatedBy = authUsername
@@ -622,16 +664,26 @@ func (svc SaleInvoiceService) SaveInBatch(shopID string, authUsername string, da
 
 			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			if err != nil {
-				return nil
+				// deliveries of the saved invoice are given back
+				return svc.redeliverOrders(ctx, shopID, findDoc, err)
 			}
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
+		err = svc.deliverOrdersInBatch(ctx, shopID, createDataList)
+
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			for _, doc := range createDataList {
+				err = svc.releaseDelivery(ctx, shopID, doc, err)
+			}
 			return common.BulkImport{}, err
 		}
 
@@ -673,6 +725,99 @@ func (svc SaleInvoiceService) getDocIDKey(doc models.SaleInvoice) string {
 	return doc.DocNo
 }
 
+// deliveryDocument return lines of the invoice which reference lines of sale orders
+func (svc SaleInvoiceService) deliveryDocument(shopID string, guid string, docNo string, doc models.SaleInvoice) saleordermodels.DeliveryDocument {
+	delivery := saleordermodels.DeliveryDocument{
+		ShopID:       shopID,
+		InvoiceGuid:  guid,
+		InvoiceDocNo: docNo,
+		DocDatetime:  doc.DocDatetime,
+		Lines:        []saleordermodels.DeliveryDocumentLine{},
+	}
+
+	if doc.Details == nil {
+		return delivery
+	}
+
+	for _, detail := range *doc.Details {
+		if detail.RefDocGuid == "" {
+			continue
+		}
+
+		delivery.Lines = append(delivery.Lines, saleordermodels.DeliveryDocumentLine{
+			LineNumber:      detail.LineNumber,
+			OrderGuid:       detail.RefDocGuid,
+			OrderLineNumber: detail.RefLineNumber,
+			Barcode:         detail.Barcode,
+			UnitCode:        detail.UnitCode,
+			Qty:             detail.Qty,
+		})
+	}
+
+	return delivery
+}
+
+// deliverOrders save deliveries of sale orders, invoices which are created without sale order delivery only keep their lines
+func (svc SaleInvoiceService) deliverOrders(ctx context.Context, delivery saleordermodels.DeliveryDocument) error {
+	if svc.orderDelivery == nil {
+		return nil
+	}
+
+	return svc.orderDelivery.Deliver(ctx, delivery)
+}
+
+// deliverOrdersInBatch save deliveries of sale orders of invoices which are created by bulk import,
+// deliveries of invoices which are saved before are released when lines of any invoice can not be delivered
+func (svc SaleInvoiceService) deliverOrdersInBatch(ctx context.Context, shopID string, docs []models.SaleInvoiceDoc) error {
+	for idx, doc := range docs {
+		delivery := svc.deliveryDocument(shopID, doc.GuidFixed, doc.DocNo, doc.SaleInvoice)
+		if len(delivery.Lines) == 0 {
+			continue
+		}
+
+		err := svc.deliverOrders(ctx, delivery)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", doc.DocNo, err)
+			for _, deliveredDoc := range docs[:idx] {
+				err = svc.releaseDelivery(ctx, shopID, deliveredDoc, err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseDelivery give back deliveries of sale orders of the invoice which is not saved by err
+func (svc SaleInvoiceService) releaseDelivery(ctx context.Context, shopID string, doc models.SaleInvoiceDoc, err error) error {
+	if len(svc.deliveryDocument(shopID, doc.GuidFixed, doc.DocNo, doc.SaleInvoice).Lines) == 0 {
+		return err
+	}
+
+	releaseErr := svc.releaseOrders(ctx, shopID, doc.GuidFixed)
+	if releaseErr != nil {
+		return fmt.Errorf("%w, deliveries of %s are not released: %v", err, doc.DocNo, releaseErr)
+	}
+	return err
+}
+
+// redeliverOrders give back deliveries of the saved invoice when its change is not saved, a failure of it is added to err
+func (svc SaleInvoiceService) redeliverOrders(ctx context.Context, shopID string, doc models.SaleInvoiceDoc, err error) error {
+	redeliverErr := svc.deliverOrders(ctx, svc.deliveryDocument(shopID, doc.GuidFixed, doc.DocNo, doc.SaleInvoice))
+	if redeliverErr != nil {
+		return fmt.Errorf("%w, deliveries of %s are not given back: %v", err, doc.DocNo, redeliverErr)
+	}
+	return err
+}
+
+func (svc SaleInvoiceService) releaseOrders(ctx context.Context, shopID string, guid string) error {
+	if svc.orderDelivery == nil {
+		return nil
+	}
+
+	return svc.orderDelivery.Release(ctx, shopID, guid)
+}
+
 func (svc SaleInvoiceService) saveMasterSync(shopID string) {
 	if svc.syncCacheRepo != nil {
 		err := svc.syncCacheRepo.Save(shopID, svc.GetModuleName())
@@ -687,9 +832,9 @@ func (svc SaleInvoiceService) GetModuleName() string {
 	return "saleInvoice"
 }
 
-func (svc SaleInvoiceService) Export(shopID string, languageCode string, languageHeader map[string]string) ([][]string, error) {
+func (svc SaleInvoiceService) Export(ctx context.Context, shopID string, languageCode string, languageHeader map[string]string) ([][]string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docs, err := svc.repo.Find(ctx, shopID, []string{}, "")
@@ -58,7 +58,7 @@ func (h SaleInvoiceBomPriceHttp) InfoSaleInvoiceBomPrice(ctx microservice.IConte
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SaleInvoiceBomPrice %v", id)
-	doc, err := h.svc.InfoSaleInvoiceBomPrice(shopID, id)
+	doc, err := h.svc.InfoSaleInvoiceBomPrice(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -88,7 +88,7 @@ func (h SaleInvoiceBomPriceHttp) InfoSaleInvoiceBomPriceByDocNo(ctx microservice
 
 	docNo := ctx.Param("docno")
 
-	docs, err := h.svc.InfoSaleInvoiceBomPriceByDocNo(shopID, docNo)
+	docs, err := h.svc.InfoSaleInvoiceBomPriceByDocNo(ctx.Context(), shopID, docNo)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -122,7 +122,7 @@ func (h SaleInvoiceBomPriceHttp) SearchSaleInvoiceBomPricePage(ctx microservice.
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSaleInvoiceBomPrice(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchSaleInvoiceBomPrice(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -160,7 +160,7 @@ func (h SaleInvoiceBomPriceHttp) SearchSaleInvoiceBomPriceStep(ctx microservice.
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchSaleInvoiceBomPriceStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchSaleInvoiceBomPriceStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
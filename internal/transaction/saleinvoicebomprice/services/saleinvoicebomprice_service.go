@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/transaction/saleinvoicebomprice/models"
 	"smlaicloudplatform/internal/transaction/saleinvoicebomprice/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -14,13 +15,13 @@ import (
 )
 
 type ISaleInvoiceBomPriceService interface {
-	CreateSaleInvoiceBomPrice(shopID string, authUsername string, docNo string, bomGUID string, bomBarcodes []string) (string, error)
-	DeleteSaleInvoiceBomPrice(shopID string, guid string, authUsername string) error
-	InfoSaleInvoiceBomPrice(shopID string, guid string) (models.SaleInvoiceBomPriceInfo, error)
-	InfoSaleInvoiceBomPriceByCode(shopID string, code string) (models.SaleInvoiceBomPriceInfo, error)
-	InfoSaleInvoiceBomPriceByDocNo(shopID string, docNo string) ([]models.SaleInvoiceBomPriceInfo, error)
-	SearchSaleInvoiceBomPrice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceBomPriceInfo, mongopagination.PaginationData, error)
-	SearchSaleInvoiceBomPriceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceBomPriceInfo, int, error)
+	CreateSaleInvoiceBomPrice(ctx context.Context, shopID string, authUsername string, docNo string, bomGUID string, bomBarcodes []string) (string, error)
+	DeleteSaleInvoiceBomPrice(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoSaleInvoiceBomPrice(ctx context.Context, shopID string, guid string) (models.SaleInvoiceBomPriceInfo, error)
+	InfoSaleInvoiceBomPriceByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceBomPriceInfo, error)
+	InfoSaleInvoiceBomPriceByDocNo(ctx context.Context, shopID string, docNo string) ([]models.SaleInvoiceBomPriceInfo, error)
+	SearchSaleInvoiceBomPrice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceBomPriceInfo, mongopagination.PaginationData, error)
+	SearchSaleInvoiceBomPriceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceBomPriceInfo, int, error)
 }
 
 type SaleInvoiceBomPriceService struct {
@@ -47,13 +48,13 @@ func NewSaleInvoiceBomPriceService(
 	return insSvc
 }
 
-func (svc SaleInvoiceBomPriceService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc SaleInvoiceBomPriceService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SaleInvoiceBomPriceService) CreateSaleInvoiceBomPrice(shopID string, authUsername string, docNo string, bomGUID string, bomBarcodes []string) (string, error) {
+func (svc SaleInvoiceBomPriceService) CreateSaleInvoiceBomPrice(ctx context.Context, shopID string, authUsername string, docNo string, bomGUID string, bomBarcodes []string) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	dataDoc := models.SaleInvoiceBomPriceDoc{}
@@ -93,9 +94,9 @@ func (svc SaleInvoiceBomPriceService) CreateSaleInvoiceBomPrice(shopID string, a
 	return newGuidFixed, nil
 }
 
-func (svc SaleInvoiceBomPriceService) DeleteSaleInvoiceBomPrice(shopID string, guid string, authUsername string) error {
+func (svc SaleInvoiceBomPriceService) DeleteSaleInvoiceBomPrice(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -116,9 +117,9 @@ func (svc SaleInvoiceBomPriceService) DeleteSaleInvoiceBomPrice(shopID string, g
 	return nil
 }
 
-func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPrice(shopID string, guid string) (models.SaleInvoiceBomPriceInfo, error) {
+func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPrice(ctx context.Context, shopID string, guid string) (models.SaleInvoiceBomPriceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -134,9 +135,9 @@ func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPrice(shopID string, gui
 	return findDoc.SaleInvoiceBomPriceInfo, nil
 }
 
-func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByCode(shopID string, code string) (models.SaleInvoiceBomPriceInfo, error) {
+func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceBomPriceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -152,9 +153,9 @@ func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByCode(shopID strin
 	return findDoc.SaleInvoiceBomPriceInfo, nil
 }
 
-func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByDocNo(shopID string, docNo string) ([]models.SaleInvoiceBomPriceInfo, error) {
+func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByDocNo(ctx context.Context, shopID string, docNo string) ([]models.SaleInvoiceBomPriceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindByDocNo(ctx, shopID, docNo)
@@ -170,9 +171,9 @@ func (svc SaleInvoiceBomPriceService) InfoSaleInvoiceBomPriceByDocNo(shopID stri
 	return findDocs, nil
 }
 
-func (svc SaleInvoiceBomPriceService) SearchSaleInvoiceBomPrice(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceBomPriceInfo, mongopagination.PaginationData, error) {
+func (svc SaleInvoiceBomPriceService) SearchSaleInvoiceBomPrice(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleInvoiceBomPriceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -188,9 +189,9 @@ func (svc SaleInvoiceBomPriceService) SearchSaleInvoiceBomPrice(shopID string, f
 	return docList, pagination, nil
 }
 
-func (svc SaleInvoiceBomPriceService) SearchSaleInvoiceBomPriceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceBomPriceInfo, int, error) {
+func (svc SaleInvoiceBomPriceService) SearchSaleInvoiceBomPriceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleInvoiceBomPriceInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/config"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/models"
@@ -14,6 +15,11 @@ type ISaleInvoiceReturnMessageQueueRepository interface {
 	CreateInBatch(docList []models.SaleInvoiceReturnDoc) error
 	UpdateInBatch(docList []models.SaleInvoiceReturnDoc) error
 	DeleteInBatch(docList []models.SaleInvoiceReturnDoc) error
+	OutboxCreate(ctx context.Context, doc models.SaleInvoiceReturnDoc) error
+	OutboxUpdate(ctx context.Context, doc models.SaleInvoiceReturnDoc) error
+	OutboxDelete(ctx context.Context, doc models.SaleInvoiceReturnDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.SaleInvoiceReturnDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.SaleInvoiceReturnDoc) error
 }
 
 type SaleInvoiceReturnMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewSaleInvoiceReturnMessageQueueRepository(prod microservice.IProducer) Sal
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.SaleInvoiceReturnDoc](prod, config.SaleInvoiceReturnMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewSaleInvoiceReturnOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewSaleInvoiceReturnOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) SaleInvoiceReturnMessageQueueRepository {
+	insRepo := NewSaleInvoiceReturnMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
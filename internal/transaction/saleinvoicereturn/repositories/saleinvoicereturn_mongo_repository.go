@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"smlaicloudplatform/internal/repositories"
+	returnablemodels "smlaicloudplatform/internal/transaction/returnable/models"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/models"
 	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
@@ -36,6 +37,9 @@ type ISaleInvoiceReturnRepository interface {
 
 	FindLastPOSDocNo(ctx context.Context, shopID string, posID string, maxDocNo string) (string, error)
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.SaleInvoiceReturnDoc, error)
+	// FindReturnedQty return quantity of lines of original documents which is returned by each return other than excludeGuid
+	FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]returnablemodels.ReturnedQty, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type SaleInvoiceReturnRepository struct {
@@ -60,6 +64,10 @@ func NewSaleInvoiceReturnRepository(pst microservice.IPersisterMongo) *SaleInvoi
 	return insRepo
 }
 
+func (repo SaleInvoiceReturnRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo SaleInvoiceReturnRepository) FindLastPOSDocNo(ctx context.Context, shopID string, posID string, maxDocNo string) (string, error) {
 
 	opts := options.FindOneOptions{}
@@ -108,3 +116,40 @@ func (repo SaleInvoiceReturnRepository) FindLastDocNo(ctx context.Context, shopI
 
 	return doc, nil
 }
+
+func (repo SaleInvoiceReturnRepository) FindReturnedQty(ctx context.Context, shopID string, docGuids []string, excludeGuid string) ([]returnablemodels.ReturnedQty, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"shopid":             shopID,
+			"guidfixed":          bson.M{"$ne": excludeGuid},
+			"iscancel":           bson.M{"$ne": true},
+			"deletedat":          bson.M{"$exists": false},
+			"details.refdocguid": bson.M{"$in": docGuids},
+		}},
+		bson.M{"$unwind": "$details"},
+		bson.M{"$match": bson.M{"details.refdocguid": bson.M{"$in": docGuids}}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"returnguid": "$guidfixed",
+				"docguid":    "$details.refdocguid",
+				"linenumber": "$details.reflinenumber",
+			},
+			"qty": bson.M{"$sum": "$details.qty"},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":        0,
+			"returnguid": "$_id.returnguid",
+			"docguid":    "$_id.docguid",
+			"linenumber": "$_id.linenumber",
+			"qty":        1,
+		}},
+	}
+
+	docList := []returnablemodels.ReturnedQty{}
+	err := repo.pst.Aggregate(ctx, models.SaleInvoiceReturnDoc{}, pipeline, &docList)
+	if err != nil {
+		return []returnablemodels.ReturnedQty{}, err
+	}
+
+	return docList, nil
+}
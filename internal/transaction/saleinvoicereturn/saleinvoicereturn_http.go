@@ -6,8 +6,11 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
+	"smlaicloudplatform/internal/transaction/returnable"
+	saleinvoicerepositories "smlaicloudplatform/internal/transaction/saleinvoice/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/models"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/services"
@@ -30,13 +33,15 @@ func NewSaleInvoiceReturnHttp(ms *microservice.Microservice, cfg config.IConfig)
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewSaleInvoiceReturnRepository(pst)
-	repoMq := repositories.NewSaleInvoiceReturnMessageQueueRepository(producer)
+	repoMq := repositories.NewSaleInvoiceReturnOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewSaleInvoiceReturnService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.SaleInvocieReturnParser{})
+	saleinvoiceRepo := saleinvoicerepositories.NewSaleInvoiceRepository(pst)
+	returnLedger := returnable.InitReturnLedger(ms, cfg, repo)
+	svc := services.NewSaleInvoiceReturnService(repo, docNoSequencer, saleinvoiceRepo, returnLedger, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.SaleInvocieReturnParser{})
 
 	return SaleInvoiceReturnHttp{
 		ms:  ms,
@@ -54,6 +59,7 @@ func (h SaleInvoiceReturnHttp) RegisterHttp() {
 	h.ms.POST("/transaction/sale-invoice-return", h.CreateSaleInvoiceReturn)
 	h.ms.GET("/transaction/sale-invoice-return/:id", h.InfoSaleInvoiceReturn)
 	h.ms.GET("/transaction/sale-invoice-return/code/:code", h.InfoSaleInvoiceReturnByCode)
+	h.ms.GET("/transaction/sale-invoice-return/returnable/:id", h.ReturnableSaleInvoice)
 	h.ms.GET("/transaction/sale-invoice-return/last-pos-docno", h.GetLastPOSDocNo)
 	h.ms.PUT("/transaction/sale-invoice-return/:id", h.UpdateSaleInvoiceReturn)
 	h.ms.DELETE("/transaction/sale-invoice-return/:id", h.DeleteSaleInvoiceReturn)
@@ -87,10 +93,10 @@ func (h SaleInvoiceReturnHttp) CreateSaleInvoiceReturn(ctx microservice.IContext
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateSaleInvoiceReturn(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateSaleInvoiceReturn(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -133,10 +139,10 @@ func (h SaleInvoiceReturnHttp) UpdateSaleInvoiceReturn(ctx microservice.IContext
 		return err
 	}
 
-	err = h.svc.UpdateSaleInvoiceReturn(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateSaleInvoiceReturn(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -164,7 +170,7 @@ func (h SaleInvoiceReturnHttp) DeleteSaleInvoiceReturn(ctx microservice.IContext
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteSaleInvoiceReturn(shopID, id, authUsername)
+	err := h.svc.DeleteSaleInvoiceReturn(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -203,7 +209,7 @@ func (h SaleInvoiceReturnHttp) DeleteSaleInvoiceReturnByGUIDs(ctx microservice.I
 		return err
 	}
 
-	err = h.svc.DeleteSaleInvoiceReturnByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteSaleInvoiceReturnByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -233,7 +239,7 @@ func (h SaleInvoiceReturnHttp) InfoSaleInvoiceReturn(ctx microservice.IContext)
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get SaleInvoiceReturn %v", id)
-	doc, err := h.svc.InfoSaleInvoiceReturn(shopID, id)
+	doc, err := h.svc.InfoSaleInvoiceReturn(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -263,7 +269,7 @@ func (h SaleInvoiceReturnHttp) InfoSaleInvoiceReturnByCode(ctx microservice.ICon
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoSaleInvoiceReturnByCode(shopID, code)
+	doc, err := h.svc.InfoSaleInvoiceReturnByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -299,7 +305,7 @@ func (h SaleInvoiceReturnHttp) GetLastPOSDocNo(ctx microservice.IContext) error
 		return nil
 	}
 
-	doc, err := h.svc.GetLastPOSDocNo(shopID, posID, maxDocNo)
+	doc, err := h.svc.GetLastPOSDocNo(ctx.Context(), shopID, posID, maxDocNo)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -340,7 +346,7 @@ func (h SaleInvoiceReturnHttp) SearchSaleInvoiceReturnPage(ctx microservice.ICon
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchSaleInvoiceReturn(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchSaleInvoiceReturn(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -385,7 +391,7 @@ func (h SaleInvoiceReturnHttp) SearchSaleInvoiceReturnStep(ctx microservice.ICon
 
 	filters := h.searchFilter(ctx.QueryParam)
 
-	docList, total, err := h.svc.SearchSaleInvoiceReturnStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchSaleInvoiceReturnStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -425,10 +431,10 @@ func (h SaleInvoiceReturnHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
-		ctx.ResponseError(400, err.Error())
+		returnable.ResponseReturnError(ctx, err)
 		return err
 	}
 
@@ -483,3 +489,30 @@ func (h SaleInvoiceReturnHttp) searchFilter(queryParam func(string) string) map[
 
 	return filters
 }
+
+// Get Returnable SaleInvoice godoc
+// @Description get sold quantity, returned quantity and quantity which can be returned of lines of the sale invoice
+// @Tags		SaleInvoiceReturn
+// @Param		id  path      string  true  "SaleInvoice guidfixed"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-invoice-return/returnable/{id} [get]
+func (h SaleInvoiceReturnHttp) ReturnableSaleInvoice(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.ReturnableSaleInvoice(ctx.Context(), shopID, id)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
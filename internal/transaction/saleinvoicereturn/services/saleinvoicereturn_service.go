@@ -9,15 +9,18 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	returnablemodels "smlaicloudplatform/internal/transaction/returnable/models"
+	returnableservices "smlaicloudplatform/internal/transaction/returnable/services"
+	saleinvoicemodels "smlaicloudplatform/internal/transaction/saleinvoice/models"
+	saleinvoicerepositories "smlaicloudplatform/internal/transaction/saleinvoice/repositories"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/models"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micro_models "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,18 +28,20 @@ import (
 )
 
 type ISaleInvoiceReturnService interface {
-	CreateSaleInvoiceReturn(shopID string, authUsername string, doc models.SaleInvoiceReturn) (string, string, error)
-	UpdateSaleInvoiceReturn(shopID string, guid string, authUsername string, doc models.SaleInvoiceReturn) error
-	UpdateSlip(shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error
-
-	DeleteSaleInvoiceReturn(shopID string, guid string, authUsername string) error
-	DeleteSaleInvoiceReturnByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoSaleInvoiceReturn(shopID string, guid string) (models.SaleInvoiceReturnInfo, error)
-	InfoSaleInvoiceReturnByCode(shopID string, code string) (models.SaleInvoiceReturnInfo, error)
-	SearchSaleInvoiceReturn(shopID string, filters map[string]interface{}, pageable micro_models.Pageable) ([]models.SaleInvoiceReturnInfo, mongopagination.PaginationData, error)
-	SearchSaleInvoiceReturnStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micro_models.PageableStep) ([]models.SaleInvoiceReturnInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.SaleInvoiceReturn) (common.BulkImport, error)
-	GetLastPOSDocNo(shopID, posID, maxDocNo string) (string, error)
+	CreateSaleInvoiceReturn(ctx context.Context, shopID string, authUsername string, doc models.SaleInvoiceReturn) (string, string, error)
+	UpdateSaleInvoiceReturn(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleInvoiceReturn) error
+	UpdateSlip(ctx context.Context, shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error
+
+	DeleteSaleInvoiceReturn(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSaleInvoiceReturnByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSaleInvoiceReturn(ctx context.Context, shopID string, guid string) (models.SaleInvoiceReturnInfo, error)
+	InfoSaleInvoiceReturnByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceReturnInfo, error)
+	SearchSaleInvoiceReturn(ctx context.Context, shopID string, filters map[string]interface{}, pageable micro_models.Pageable) ([]models.SaleInvoiceReturnInfo, mongopagination.PaginationData, error)
+	SearchSaleInvoiceReturnStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micro_models.PageableStep) ([]models.SaleInvoiceReturnInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleInvoiceReturn) (common.BulkImport, error)
+	GetLastPOSDocNo(ctx context.Context, shopID, posID, maxDocNo string) (string, error)
+
+	ReturnableSaleInvoice(ctx context.Context, shopID string, saleinvoiceGuid string) (returnablemodels.ReturnableDocument, error)
 
 	GetModuleName() string
 }
@@ -52,9 +57,10 @@ const (
 type SaleInvoiceReturnService struct {
 	repoMq             repositories.ISaleInvoiceReturnMessageQueueRepository
 	repo               repositories.ISaleInvoiceReturnRepository
-	repoCache          trans_cache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
+	saleinvoiceRepo    saleinvoicerepositories.ISaleInvoiceRepository
+	returnLedger       returnableservices.IReturnLedger
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      master_sync.IMasterSyncCacheRepository
 	services.ActivityService[models.SaleInvoiceReturnActivity, models.SaleInvoiceReturnDeleteActivity]
 	parser         ISaleInvocieReturnParser
@@ -63,7 +69,9 @@ type SaleInvoiceReturnService struct {
 
 func NewSaleInvoiceReturnService(
 	repo repositories.ISaleInvoiceReturnRepository,
-	repoCache trans_cache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	saleinvoiceRepo saleinvoicerepositories.ISaleInvoiceRepository,
+	returnLedger returnableservices.IReturnLedger,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.ISaleInvoiceReturnMessageQueueRepository,
 	syncCacheRepo master_sync.IMasterSyncCacheRepository,
@@ -75,11 +83,12 @@ func NewSaleInvoiceReturnService(
 	insSvc := &SaleInvoiceReturnService{
 		repo:               repo,
 		repoMq:             repoMq,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
+		saleinvoiceRepo:    saleinvoiceRepo,
+		returnLedger:       returnLedger,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -88,131 +97,78 @@ func NewSaleInvoiceReturnService(
 	return insSvc
 }
 
-func (svc SaleInvoiceReturnService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
-}
-
-func (svc SaleInvoiceReturnService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
+func (svc SaleInvoiceReturnService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc SaleInvoiceReturnService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
+func (svc SaleInvoiceReturnService) CreateSaleInvoiceReturn(ctx context.Context, shopID string, authUsername string, doc models.SaleInvoiceReturn) (string, string, error) {
 
-	return newDocNo, newDocNumber, nil
-}
-func (svc SaleInvoiceReturnService) CreateSaleInvoiceReturn(shopID string, authUsername string, doc models.SaleInvoiceReturn) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	isGenerateDocNo := !doc.IsPOS
 
-	prefixDocNo, docNo, newDocNumber := "", "", 0
-
-	if isGenerateDocNo {
-		docDate := doc.DocDatetime
-		prefixDocNo := svc.getDocNoPrefix(docDate)
-
-		tempNewDocNo, tempNewDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-		if err != nil {
-			return "", "", err
-		}
-
-		docNo = tempNewDocNo
-		newDocNumber = tempNewDocNumber
-	} else {
-		if doc.DocNo == "" {
-			return "", "", errors.New("docno is required")
-		}
-
-		docNo = doc.DocNo
+	if !isGenerateDocNo && doc.DocNo == "" {
+		return "", "", errors.New("docno is required")
 	}
 
-	if isGenerateDocNo {
-		findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", docNo)
-
-		if err != nil {
-			return "", "", err
-		}
-
-		if len(findDoc.GuidFixed) > 0 {
-			return "", "", errors.New("DocNo is exists")
-		}
+	productBarcodes, err := svc.GetDetailProductBarcodes(ctx, shopID, *doc.Details)
+	if err != nil {
+		return "", "", err
 	}
 
 	newGuidFixed := utils.NewGUID()
 
+	// lines which return lines of sale invoices must not return more than their remaining quantity
+	details, err := svc.returnLines(ctx, shopID, newGuidFixed, doc, svc.PrepareDetail(*doc.Details, productBarcodes))
+	if err != nil {
+		return "", "", err
+	}
+
 	dataDoc := models.SaleInvoiceReturnDoc{}
 	dataDoc.ShopID = shopID
 	dataDoc.GuidFixed = newGuidFixed
 	dataDoc.SaleInvoiceReturn = doc
 
-	dataDoc.DocNo = docNo
-	if isGenerateDocNo && doc.TaxDocNo == "" {
-		dataDoc.TaxDocNo = docNo
-	}
-
-	productBarcodes, err := svc.GetDetailProductBarcodes(ctx, shopID, *doc.Details)
-	if err != nil {
-		return "", "", err
-	}
-
-	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	save := func(docNo string) error {
+		dataDoc.DocNo = docNo
+		if isGenerateDocNo && doc.TaxDocNo == "" {
+			dataDoc.TaxDocNo = docNo
+		}
+
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+	}
+
+	docNo := doc.DocNo
+	if isGenerateDocNo {
+		docNo, err = svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, save)
+	} else {
+		err = save(docNo)
+	}
 
 	if err != nil {
-		return "", "", err
+		return "", "", svc.releaseReturn(ctx, shopID, newGuidFixed, err)
 	}
 
 	go func() {
-		svc.repoMq.Create(dataDoc)
 		svc.saveMasterSync(shopID)
-		if isGenerateDocNo {
-			svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-		}
 	}()
 
 	return newGuidFixed, docNo, nil
 }
+
 func (svc SaleInvoiceReturnService) GetDetailProductBarcodes(ctx context.Context, shopID string, details []trans_models.Detail) ([]productbarcode_models.ProductBarcodeInfo, error) {
 	var tempBarcodes []string
 	for _, doc := range details {
@@ -241,9 +197,9 @@ func (svc SaleInvoiceReturnService) PrepareDetail(details []trans_models.Detail,
 	return details
 }
 
-func (svc SaleInvoiceReturnService) UpdateSaleInvoiceReturn(shopID string, guid string, authUsername string, doc models.SaleInvoiceReturn) error {
+func (svc SaleInvoiceReturnService) UpdateSaleInvoiceReturn(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleInvoiceReturn) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -264,7 +220,10 @@ func (svc SaleInvoiceReturnService) UpdateSaleInvoiceReturn(shopID string, guid
 		return err
 	}
 
-	details := svc.PrepareDetail(*doc.Details, productBarcodes)
+	details, err := svc.returnLines(ctx, shopID, guid, doc, svc.PrepareDetail(*doc.Details, productBarcodes))
+	if err != nil {
+		return err
+	}
 	dataDoc.Details = &details
 
 	dataDoc.SlipQrUrl = findDoc.SlipQrUrl
@@ -276,23 +235,29 @@ func (svc SaleInvoiceReturnService) UpdateSaleInvoiceReturn(shopID string, guid
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
-		return err
+		return svc.restoreReturn(ctx, findDoc, err)
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceReturnService) UpdateSlip(shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error {
+func (svc SaleInvoiceReturnService) UpdateSlip(ctx context.Context, shopID string, authUsername string, docNo string, mode uint8, machineCode string, zoneGroupNumber string, imageUrl string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", docNo)
@@ -327,26 +292,29 @@ func (svc SaleInvoiceReturnService) UpdateSlip(shopID string, authUsername strin
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, findDoc.GuidFixed, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, findDoc.GuidFixed, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	go func() {
-		err := svc.repoMq.Update(dataDoc)
-		if err != nil {
-			fmt.Printf("create mq error :: %s", err.Error())
-		}
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceReturnService) DeleteSaleInvoiceReturn(shopID string, guid string, authUsername string) error {
+func (svc SaleInvoiceReturnService) DeleteSaleInvoiceReturn(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -359,45 +327,73 @@ func (svc SaleInvoiceReturnService) DeleteSaleInvoiceReturn(shopID string, guid
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = svc.returnLedger.Release(ctx, shopID, guid)
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceReturnService) DeleteSaleInvoiceReturnByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc SaleInvoiceReturnService) DeleteSaleInvoiceReturnByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		err = svc.returnLedger.Release(ctx, shopID, guid)
+		if err != nil {
+			return err
+		}
+	}
+
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturn(shopID string, guid string) (models.SaleInvoiceReturnInfo, error) {
+func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturn(ctx context.Context, shopID string, guid string) (models.SaleInvoiceReturnInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -413,9 +409,9 @@ func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturn(shopID string, guid st
 	return findDoc.SaleInvoiceReturnInfo, nil
 }
 
-func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturnByCode(shopID string, code string) (models.SaleInvoiceReturnInfo, error) {
+func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturnByCode(ctx context.Context, shopID string, code string) (models.SaleInvoiceReturnInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -431,8 +427,8 @@ func (svc SaleInvoiceReturnService) InfoSaleInvoiceReturnByCode(shopID string, c
 	return findDoc.SaleInvoiceReturnInfo, nil
 }
 
-func (svc SaleInvoiceReturnService) GetLastPOSDocNo(shopID, posID, maxDocNo string) (string, error) {
-	ctx, ctxCancel := svc.getContextTimeout()
+func (svc SaleInvoiceReturnService) GetLastPOSDocNo(ctx context.Context, shopID, posID, maxDocNo string) (string, error) {
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	lastDocNo, err := svc.repo.FindLastPOSDocNo(ctx, shopID, posID, maxDocNo)
@@ -444,9 +440,9 @@ func (svc SaleInvoiceReturnService) GetLastPOSDocNo(shopID, posID, maxDocNo stri
 	return lastDocNo, nil
 }
 
-func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturn(shopID string, filters map[string]interface{}, pageable micro_models.Pageable) ([]models.SaleInvoiceReturnInfo, mongopagination.PaginationData, error) {
+func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturn(ctx context.Context, shopID string, filters map[string]interface{}, pageable micro_models.Pageable) ([]models.SaleInvoiceReturnInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -462,9 +458,9 @@ func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturn(shopID string, filte
 	return docList, pagination, nil
 }
 
-func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturnStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micro_models.PageableStep) ([]models.SaleInvoiceReturnInfo, int, error) {
+func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturnStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micro_models.PageableStep) ([]models.SaleInvoiceReturnInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -482,9 +478,9 @@ func (svc SaleInvoiceReturnService) SearchSaleInvoiceReturnStep(shopID string, l
 	return docList, total, nil
 }
 
-func (svc SaleInvoiceReturnService) SaveInBatch(shopID string, authUsername string, dataList []models.SaleInvoiceReturn) (common.BulkImport, error) {
+func (svc SaleInvoiceReturnService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.SaleInvoiceReturn) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.SaleInvoiceReturn](dataList, svc.getDocIDKey)
@@ -540,22 +536,39 @@ func (svc SaleInvoiceReturnService) SaveInBatch(shopID string, authUsername stri
 		},
 		func(shopID string, authUsername string, data models.SaleInvoiceReturn, doc models.SaleInvoiceReturnDoc) error {
 
+			details, err := svc.returnLines(ctx, shopID, doc.GuidFixed, data, returnDetails(data.Details))
+			if err != nil {
+				return err
+			}
+
+			findDoc := doc
+
 			doc.SaleInvoiceReturn = data
+			doc.Details = &details
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
 			err = svc.repo.Update(ctx, shopID, doc.GuidFixed, doc)
 			if err != nil {
-				return nil
+				return svc.restoreReturn(ctx, findDoc, err)
 			}
 			return nil
 		},
 	)
 
 	if len(createDataList) > 0 {
+		err = svc.returnLinesInBatch(ctx, shopID, createDataList)
+
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			for _, doc := range createDataList {
+				err = svc.releaseReturn(ctx, shopID, doc.GuidFixed, err)
+			}
 			return common.BulkImport{}, err
 		}
 
@@ -593,6 +606,144 @@ func (svc SaleInvoiceReturnService) SaveInBatch(shopID string, authUsername stri
 	}, nil
 }
 
+// ReturnableSaleInvoice return quantity of lines of the sale invoice which is returned and can be returned
+func (svc SaleInvoiceReturnService) ReturnableSaleInvoice(ctx context.Context, shopID string, saleinvoiceGuid string) (returnablemodels.ReturnableDocument, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.saleinvoiceRepo.FindByGuid(ctx, shopID, saleinvoiceGuid)
+
+	if err != nil {
+		return returnablemodels.ReturnableDocument{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return returnablemodels.ReturnableDocument{}, errors.New("document not found")
+	}
+
+	returned, err := svc.repo.FindReturnedQty(ctx, shopID, []string{saleinvoiceGuid}, "")
+
+	if err != nil {
+		return returnablemodels.ReturnableDocument{}, err
+	}
+
+	return returnableservices.ReturnableLines(svc.sourceDocument(findDoc), returned), nil
+}
+
+// returnLines validate lines which reference lines of sale invoices, default them from the original lines
+// and keep their returned quantity, quantity which is returned by the return of guid itself is not counted
+func (svc SaleInvoiceReturnService) returnLines(ctx context.Context, shopID string, guid string, doc models.SaleInvoiceReturn, details []trans_models.Detail) ([]trans_models.Detail, error) {
+	sources, err := svc.sourceDocuments(ctx, shopID, details)
+	if err != nil {
+		return []trans_models.Detail{}, err
+	}
+
+	return svc.returnLedger.Return(ctx, svc.returnDocument(shopID, guid, doc, details), sources)
+}
+
+// returnLinesInBatch return lines of sale invoice returns which are created by bulk import,
+// nothing is returned when lines of any return can not be returned
+func (svc SaleInvoiceReturnService) returnLinesInBatch(ctx context.Context, shopID string, docs []models.SaleInvoiceReturnDoc) error {
+	returnDocs := []returnablemodels.ReturnDocument{}
+	allDetails := []trans_models.Detail{}
+	for _, doc := range docs {
+		returnDoc := svc.returnDocument(shopID, doc.GuidFixed, doc.SaleInvoiceReturn, returnDetails(doc.Details))
+		returnDocs = append(returnDocs, returnDoc)
+		allDetails = append(allDetails, returnDoc.Details...)
+	}
+
+	sources, err := svc.sourceDocuments(ctx, shopID, allDetails)
+	if err != nil {
+		return err
+	}
+
+	detailsList, err := svc.returnLedger.ReturnInBatch(ctx, returnDocs, sources)
+	if err != nil {
+		return err
+	}
+
+	for idx := range docs {
+		details := detailsList[idx]
+		docs[idx].Details = &details
+	}
+
+	return nil
+}
+
+// releaseReturn give back quantity which is returned by the return which is not saved by err
+func (svc SaleInvoiceReturnService) releaseReturn(ctx context.Context, shopID string, guid string, err error) error {
+	releaseErr := svc.returnLedger.Release(ctx, shopID, guid)
+	if releaseErr != nil {
+		return fmt.Errorf("%w, returned quantity is not released: %v", err, releaseErr)
+	}
+	return err
+}
+
+// restoreReturn keep quantity which is returned by the saved return when it is not updated by err
+func (svc SaleInvoiceReturnService) restoreReturn(ctx context.Context, findDoc models.SaleInvoiceReturnDoc, err error) error {
+	recordErr := svc.returnLedger.Record(ctx, svc.returnDocument(findDoc.ShopID, findDoc.GuidFixed, findDoc.SaleInvoiceReturn, returnDetails(findDoc.Details)))
+	if recordErr != nil {
+		return fmt.Errorf("%w, returned quantity is not restored: %v", err, recordErr)
+	}
+	return err
+}
+
+func (svc SaleInvoiceReturnService) returnDocument(shopID string, guid string, doc models.SaleInvoiceReturn, details []trans_models.Detail) returnablemodels.ReturnDocument {
+	return returnablemodels.ReturnDocument{
+		ShopID:     shopID,
+		ReturnGuid: guid,
+		DocNo:      doc.DocNo,
+		IsPOS:      doc.IsPOS,
+		IsCancel:   doc.IsCancel,
+		Details:    details,
+	}
+}
+
+// sourceDocuments return sale invoices which lines are referenced by details
+func (svc SaleInvoiceReturnService) sourceDocuments(ctx context.Context, shopID string, details []trans_models.Detail) ([]returnablemodels.SourceDocument, error) {
+	sources := []returnablemodels.SourceDocument{}
+
+	docGuids := returnableservices.SourceGuids(details)
+	if len(docGuids) == 0 {
+		return sources, nil
+	}
+
+	sourceDocs, err := svc.saleinvoiceRepo.FindByGuids(ctx, shopID, docGuids)
+	if err != nil {
+		return []returnablemodels.SourceDocument{}, err
+	}
+
+	for _, sourceDoc := range sourceDocs {
+		sources = append(sources, svc.sourceDocument(sourceDoc))
+	}
+
+	return sources, nil
+}
+
+func returnDetails(details *[]trans_models.Detail) []trans_models.Detail {
+	if details == nil {
+		return []trans_models.Detail{}
+	}
+	return *details
+}
+
+func (svc SaleInvoiceReturnService) sourceDocument(doc saleinvoicemodels.SaleInvoiceDoc) returnablemodels.SourceDocument {
+	source := returnablemodels.SourceDocument{
+		GuidFixed:   doc.GuidFixed,
+		DocNo:       doc.DocNo,
+		DocDatetime: doc.DocDatetime,
+		IsCancel:    doc.IsCancel,
+		Details:     []trans_models.Detail{},
+	}
+
+	if doc.Details != nil {
+		source.Details = *doc.Details
+	}
+
+	return source
+}
+
 func (svc SaleInvoiceReturnService) getDocIDKey(doc models.SaleInvoiceReturn) string {
 	return doc.DocNo
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	transmodels "smlaicloudplatform/internal/transaction/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const saleorderCollectionName = "transactionSaleOrder"
+
+// status of sale order, confirmed order reserve stock of its remaining quantity until it is delivered, closed or cancelled
+const (
+	OrderStatusDraft     = "draft"
+	OrderStatusConfirmed = "confirmed"
+	OrderStatusPartial   = "partial"
+	OrderStatusDelivered = "delivered"
+	OrderStatusClosed    = "closed"
+	OrderStatusCancelled = "cancelled"
+)
+
+type SaleOrder struct {
+	models.PartitionIdentity `bson:"inline"`
+	transmodels.Transaction  `bson:"inline"`
+	ValidUntil               time.Time `json:"validuntil" bson:"validuntil"`
+	DeliveryDate             time.Time `json:"deliverydate" bson:"deliverydate"`
+	OrderStatus              string    `json:"orderstatus" bson:"orderstatus"`
+	QuotationGuid            string    `json:"quotationguid" bson:"quotationguid"`
+}
+
+// IsReserving return true when the order reserve stock of its remaining quantity
+func IsReserving(status string) bool {
+	return status == OrderStatusConfirmed || status == OrderStatusPartial
+}
+
+type SaleOrderInfo struct {
+	models.DocIdentity `bson:"inline"`
+	SaleOrder          `bson:"inline"`
+}
+
+func (SaleOrderInfo) CollectionName() string {
+	return saleorderCollectionName
+}
+
+type SaleOrderData struct {
+	models.ShopIdentity `bson:"inline"`
+	SaleOrderInfo       `bson:"inline"`
+}
+
+type SaleOrderDoc struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SaleOrderData      `bson:"inline"`
+	models.ActivityDoc `bson:"inline"`
+}
+
+func (SaleOrderDoc) CollectionName() string {
+	return saleorderCollectionName
+}
+
+type SaleOrderItemGuid struct {
+	DocNo string `json:"docno" bson:"docno"`
+}
+
+func (SaleOrderItemGuid) CollectionName() string {
+	return saleorderCollectionName
+}
+
+type SaleOrderActivity struct {
+	SaleOrderData       `bson:"inline"`
+	models.ActivityTime `bson:"inline"`
+}
+
+func (SaleOrderActivity) CollectionName() string {
+	return saleorderCollectionName
+}
+
+type SaleOrderDeleteActivity struct {
+	models.Identity     `bson:"inline"`
+	models.ActivityTime `bson:"inline"`
+}
+
+func (SaleOrderDeleteActivity) CollectionName() string {
+	return saleorderCollectionName
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const saleOrderDeliveryCollectionName = "saleOrderDeliveries"
+
+// SaleOrderDeliveryLine is line of sale invoice which deliver the line of sale order
+type SaleOrderDeliveryLine struct {
+	InvoiceGuid     string    `json:"invoiceguid" bson:"invoiceguid"`
+	InvoiceDocNo    string    `json:"invoicedocno" bson:"invoicedocno"`
+	DocDatetime     time.Time `json:"docdatetime" bson:"docdatetime"`
+	LineNumber      int       `json:"linenumber" bson:"linenumber"`
+	OrderLineNumber int       `json:"orderlinenumber" bson:"orderlinenumber"`
+	Qty             float64   `json:"qty" bson:"qty"`
+}
+
+func (l SaleOrderDeliveryLine) DocGuid() string {
+	return l.InvoiceGuid
+}
+
+func (l SaleOrderDeliveryLine) ReferencedLineNumber() int {
+	return l.OrderLineNumber
+}
+
+func (l SaleOrderDeliveryLine) ReferencedQty() float64 {
+	return l.Qty
+}
+
+// StockReservation is remaining quantity of the line of confirmed sale order which is reserved in its warehouse
+type StockReservation struct {
+	LineNumber   int     `json:"linenumber" bson:"linenumber"`
+	Barcode      string  `json:"barcode" bson:"barcode"`
+	ItemCode     string  `json:"itemcode" bson:"itemcode"`
+	UnitCode     string  `json:"unitcode" bson:"unitcode"`
+	WhCode       string  `json:"whcode" bson:"whcode"`
+	LocationCode string  `json:"locationcode" bson:"locationcode"`
+	Qty          float64 `json:"qty" bson:"qty"`
+}
+
+// SaleOrderDelivery keep deliveries and stock reservations of sale order, version is increased on every change
+// so concurrent deliveries of the same order can not both take the remaining quantity
+type SaleOrderDelivery struct {
+	ID           primitive.ObjectID      `json:"-" bson:"_id,omitempty"`
+	ShopID       string                  `json:"shopid" bson:"shopid"`
+	OrderGuid    string                  `json:"orderguid" bson:"orderguid"`
+	OrderDocNo   string                  `json:"orderdocno" bson:"orderdocno"`
+	Deliveries   []SaleOrderDeliveryLine `json:"deliveries" bson:"deliveries"`
+	Reservations []StockReservation      `json:"reservations" bson:"reservations"`
+	Version      int64                   `json:"version" bson:"version"`
+	UpdatedAt    time.Time               `json:"updatedat" bson:"updatedat"`
+}
+
+func (SaleOrderDelivery) CollectionName() string {
+	return saleOrderDeliveryCollectionName
+}
+
+// DeliveryLine is ordered, delivered and remaining quantity of the line of sale order
+type DeliveryLine struct {
+	LineNumber   int                     `json:"linenumber"`
+	Barcode      string                  `json:"barcode"`
+	ItemCode     string                  `json:"itemcode"`
+	ItemNames    *[]models.NameX         `json:"itemnames"`
+	UnitCode     string                  `json:"unitcode"`
+	WhCode       string                  `json:"whcode"`
+	OrderQty     float64                 `json:"orderqty"`
+	Price        float64                 `json:"price"`
+	DeliveredQty float64                 `json:"deliveredqty"`
+	RemainingQty float64                 `json:"remainingqty"`
+	ReservedQty  float64                 `json:"reservedqty"`
+	Deliveries   []SaleOrderDeliveryLine `json:"deliveries"`
+}
+
+type SaleOrderDeliveryInfo struct {
+	OrderGuid    string         `json:"orderguid"`
+	OrderDocNo   string         `json:"orderdocno"`
+	DocDatetime  time.Time      `json:"docdatetime"`
+	DeliveryDate time.Time      `json:"deliverydate"`
+	CustCode     string         `json:"custcode"`
+	OrderStatus  string         `json:"orderstatus"`
+	Lines        []DeliveryLine `json:"lines"`
+}
+
+// DeliveryDocument is sale invoice which deliver lines of sale orders
+type DeliveryDocument struct {
+	ShopID       string
+	InvoiceGuid  string
+	InvoiceDocNo string
+	DocDatetime  time.Time
+	Lines        []DeliveryDocumentLine
+}
+
+// DeliveryDocumentLine is line of sale invoice which reference the line of sale order
+type DeliveryDocumentLine struct {
+	LineNumber      int
+	OrderGuid       string
+	OrderLineNumber int
+	Barcode         string
+	UnitCode        string
+	Qty             float64
+}
+
+// DeliveryQty is quantity of the line of sale order which is delivered by the sale invoice which is created from the order
+type DeliveryQty struct {
+	LineNumber int     `json:"linenumber" validate:"min=1"`
+	Qty        float64 `json:"qty" validate:"gt=0"`
+}
+
+// ReservedStock is quantity of the barcode in the warehouse which is reserved by confirmed sale orders
+type ReservedStock struct {
+	Barcode string  `json:"barcode" bson:"barcode"`
+	WhCode  string  `json:"whcode" bson:"whcode"`
+	Qty     float64 `json:"qty" bson:"qty"`
+}
+
+// StockBalance is balance quantity of the barcode with quantity which is reserved by confirmed sale orders,
+// available quantity can be reserved by more sale orders
+type StockBalance struct {
+	Barcode      string  `json:"barcode"`
+	BalanceQty   float64 `json:"balanceqty"`
+	ReservedQty  float64 `json:"reservedqty"`
+	AvailableQty float64 `json:"availableqty"`
+}
+
+// OpenOrderLine is line of confirmed or partially delivered sale order which is not delivered completely
+type OpenOrderLine struct {
+	OrderGuid       string          `json:"orderguid"`
+	OrderDocNo      string          `json:"orderdocno"`
+	DocDatetime     time.Time       `json:"docdatetime"`
+	DeliveryDate    time.Time       `json:"deliverydate"`
+	CustCode        string          `json:"custcode"`
+	CustNames       *[]models.NameX `json:"custnames"`
+	OrderStatus     string          `json:"orderstatus"`
+	LineNumber      int             `json:"linenumber"`
+	Barcode         string          `json:"barcode"`
+	ItemCode        string          `json:"itemcode"`
+	ItemNames       *[]models.NameX `json:"itemnames"`
+	UnitCode        string          `json:"unitcode"`
+	WhCode          string          `json:"whcode"`
+	OrderQty        float64         `json:"orderqty"`
+	DeliveredQty    float64         `json:"deliveredqty"`
+	RemainingQty    float64         `json:"remainingqty"`
+	RemainingAmount float64         `json:"remainingamount"`
+	IsOverdue       bool            `json:"isoverdue"`
+}
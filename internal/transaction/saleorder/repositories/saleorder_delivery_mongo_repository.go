@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ISaleOrderDeliveryRepository interface {
+	FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.SaleOrderDelivery, error)
+	FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.SaleOrderDelivery, error)
+	FindByInvoice(ctx context.Context, shopID string, invoiceGuid string) ([]models.SaleOrderDelivery, error)
+	// Save replace delivery of the order when it is not changed since it is read by its version,
+	// false is returned when another delivery saved the order first
+	Save(ctx context.Context, doc models.SaleOrderDelivery) (bool, error)
+	DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error
+	// SumReserved return reserved quantity of barcodes by warehouse, every reserved barcode is returned when barcodes is empty
+	SumReserved(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error)
+}
+
+type SaleOrderDeliveryRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewSaleOrderDeliveryRepository(pst microservice.IPersisterMongo) *SaleOrderDeliveryRepository {
+	return &SaleOrderDeliveryRepository{
+		pst: pst,
+	}
+}
+
+func (repo SaleOrderDeliveryRepository) FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.SaleOrderDelivery, error) {
+	doc := models.SaleOrderDelivery{}
+	err := repo.pst.FindOne(ctx, &models.SaleOrderDelivery{}, bson.M{"shopid": shopID, "orderguid": orderGuid}, &doc)
+	if err != nil {
+		return models.SaleOrderDelivery{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo SaleOrderDeliveryRepository) FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.SaleOrderDelivery, error) {
+	docList := []models.SaleOrderDelivery{}
+	err := repo.pst.Find(ctx, &models.SaleOrderDelivery{}, bson.M{"shopid": shopID, "orderguid": bson.M{"$in": orderGuids}}, &docList)
+	if err != nil {
+		return []models.SaleOrderDelivery{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo SaleOrderDeliveryRepository) FindByInvoice(ctx context.Context, shopID string, invoiceGuid string) ([]models.SaleOrderDelivery, error) {
+	docList := []models.SaleOrderDelivery{}
+	err := repo.pst.Find(ctx, &models.SaleOrderDelivery{}, bson.M{"shopid": shopID, "deliveries.invoiceguid": invoiceGuid}, &docList)
+	if err != nil {
+		return []models.SaleOrderDelivery{}, err
+	}
+
+	return docList, nil
+}
+
+func (repo SaleOrderDeliveryRepository) Save(ctx context.Context, doc models.SaleOrderDelivery) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, doc.ShopID), &models.SaleOrderDelivery{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":    doc.ShopID,
+		"orderguid": doc.OrderGuid,
+		"version":   doc.Version,
+	}
+
+	doc.ID = primitive.NilObjectID
+	doc.Version++
+
+	// the first change insert delivery, unique index reject the other one of concurrent first changes
+	result, err := collection.ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(doc.Version == 1))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+func (repo SaleOrderDeliveryRepository) DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error {
+	return repo.pst.Delete(ctx, &models.SaleOrderDelivery{}, bson.M{"shopid": shopID, "orderguid": orderGuid})
+}
+
+func (repo SaleOrderDeliveryRepository) SumReserved(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error) {
+	matchFilters := bson.M{
+		"shopid":       shopID,
+		"reservations": bson.M{"$exists": true, "$ne": bson.A{}},
+	}
+
+	reservationFilters := bson.M{}
+	if len(barcodes) > 0 {
+		matchFilters["reservations.barcode"] = bson.M{"$in": barcodes}
+		reservationFilters["reservations.barcode"] = bson.M{"$in": barcodes}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": matchFilters},
+		bson.M{"$unwind": "$reservations"},
+		bson.M{"$match": reservationFilters},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"barcode": "$reservations.barcode",
+				"whcode":  "$reservations.whcode",
+			},
+			"qty": bson.M{"$sum": "$reservations.qty"},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":     0,
+			"barcode": "$_id.barcode",
+			"whcode":  "$_id.whcode",
+			"qty":     1,
+		}},
+		bson.M{"$sort": bson.D{{Key: "barcode", Value: 1}, {Key: "whcode", Value: 1}}},
+	}
+
+	docList := []models.ReservedStock{}
+	err := repo.pst.Aggregate(ctx, &models.SaleOrderDelivery{}, pipeline, &docList)
+	if err != nil {
+		return []models.ReservedStock{}, err
+	}
+
+	return docList, nil
+}
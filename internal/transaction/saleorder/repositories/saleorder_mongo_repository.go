@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type ISaleOrderRepository interface {
+	Count(ctx context.Context, shopID string) (int, error)
+	Create(ctx context.Context, doc models.SaleOrderDoc) (string, error)
+	Update(ctx context.Context, shopID string, guid string, doc models.SaleOrderDoc) error
+	DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error
+	Delete(ctx context.Context, shopID string, username string, filters map[string]interface{}) error
+	FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.SaleOrderInfo, mongopagination.PaginationData, error)
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.SaleOrderDoc, error)
+	FindByGuids(ctx context.Context, shopID string, guids []string) ([]models.SaleOrderDoc, error)
+
+	FindByDocIndentityGuid(ctx context.Context, shopID string, indentityField string, indentityValue interface{}) (models.SaleOrderDoc, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.SaleOrderInfo, mongopagination.PaginationData, error)
+	FindStep(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, projects map[string]interface{}, pageableLimit micromodels.PageableStep) ([]models.SaleOrderInfo, int, error)
+
+	FindDeletedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleOrderDeleteActivity, mongopagination.PaginationData, error)
+	FindCreatedOrUpdatedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleOrderActivity, mongopagination.PaginationData, error)
+	FindDeletedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleOrderDeleteActivity, error)
+	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleOrderActivity, error)
+
+	// UpdateOrderStatus change status of the order when it is still fromStatus, false is returned when it is changed by another request
+	UpdateOrderStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error)
+	// UpdateWhenStatus save the order when its status is still fromStatus, false is returned when it is changed by another request
+	UpdateWhenStatus(ctx context.Context, shopID string, guid string, fromStatus string, doc models.SaleOrderDoc) (bool, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
+}
+
+type SaleOrderRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.CrudRepository[models.SaleOrderDoc]
+	repositories.SearchRepository[models.SaleOrderInfo]
+	repositories.GuidRepository[models.SaleOrderItemGuid]
+	repositories.ActivityRepository[models.SaleOrderActivity, models.SaleOrderDeleteActivity]
+}
+
+func NewSaleOrderRepository(pst microservice.IPersisterMongo) *SaleOrderRepository {
+
+	insRepo := &SaleOrderRepository{
+		pst: pst,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.SaleOrderDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.SaleOrderInfo](pst)
+	insRepo.GuidRepository = repositories.NewGuidRepository[models.SaleOrderItemGuid](pst)
+	insRepo.ActivityRepository = repositories.NewActivityRepository[models.SaleOrderActivity, models.SaleOrderDeleteActivity](pst)
+
+	return insRepo
+}
+func (repo SaleOrderRepository) UpdateOrderStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.SaleOrderDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":      shopID,
+		"guidfixed":   guid,
+		"deletedat":   bson.M{"$exists": false},
+		"orderstatus": fromStatus,
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"orderstatus": toStatus}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo SaleOrderRepository) UpdateWhenStatus(ctx context.Context, shopID string, guid string, fromStatus string, doc models.SaleOrderDoc) (bool, error) {
+	return repo.UpdateWhen(ctx, shopID, guid, map[string]interface{}{"orderstatus": fromStatus}, doc)
+}
+
+func (repo SaleOrderRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import (
+	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
+)
+
+// IStockBalanceRepository is balance quantity of barcodes which is calculated by stock process
+type IStockBalanceRepository interface {
+	FindByBarcodes(shopID string, barcodes []string) ([]productbarcode_models.ProductBarcodePg, error)
+}
@@ -0,0 +1,46 @@
+package saleorder
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	common "smlaicloudplatform/internal/models"
+	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	"smlaicloudplatform/internal/transaction/linereference"
+	"smlaicloudplatform/internal/transaction/saleorder/repositories"
+	"smlaicloudplatform/internal/transaction/saleorder/services"
+	"smlaicloudplatform/internal/transaction/salequotation"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// InitSaleOrderDelivery return delivery of sale orders which is delivered by sale invoices
+func InitSaleOrderDelivery(ms *microservice.Microservice, cfg config.IConfig) services.ISaleOrderDelivery {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	pstPg := ms.Persister(cfg.PersisterConfig())
+
+	return services.NewSaleOrderDelivery(
+		repositories.NewSaleOrderRepository(pst),
+		repositories.NewSaleOrderDeliveryRepository(pst),
+		productbarcode_repositories.NewProductBarcodePGRepository(pstPg),
+		ms.TimeNow,
+	)
+}
+
+// ResponseDeliveryError write response of error of delivery of sale order, errors of lines are sent in data
+// and errors of conversion of sale quotation are written by sale quotation
+func ResponseDeliveryError(ctx microservice.IContext, err error) {
+	lineErr := linereference.Error{}
+
+	switch {
+	case errors.As(err, &lineErr):
+		ctx.Response(http.StatusBadRequest, common.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+			Data:    lineErr.Lines,
+		})
+	case errors.Is(err, services.ErrDeliveryConflict), errors.Is(err, services.ErrDeliveryHasDeliveries), errors.Is(err, services.ErrOrderStatusChanged):
+		ctx.ResponseError(http.StatusConflict, err.Error())
+	default:
+		salequotation.ResponseError(ctx, err)
+	}
+}
@@ -0,0 +1,639 @@
+package saleorder
+
+import (
+	"encoding/json"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	mastersync "smlaicloudplatform/internal/mastersync/repositories"
+	common "smlaicloudplatform/internal/models"
+	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/internal/transaction/saleorder/repositories"
+	"smlaicloudplatform/internal/transaction/saleorder/services"
+	"smlaicloudplatform/internal/transaction/salequotation"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/pkg/microservice"
+	"strings"
+)
+
+type ISaleOrderHttp interface{}
+
+type SaleOrderHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.ISaleOrderHttpService
+}
+
+func NewSaleOrderHttp(ms *microservice.Microservice, cfg config.IConfig) SaleOrderHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	pstPg := ms.Persister(cfg.PersisterConfig())
+	cache := ms.Cacher(cfg.CacherConfig())
+
+	repo := repositories.NewSaleOrderRepository(pst)
+	deliveryRepo := repositories.NewSaleOrderDeliveryRepository(pst)
+
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
+	delivery := services.NewSaleOrderDelivery(repo, deliveryRepo, productbarcode_repositories.NewProductBarcodePGRepository(pstPg), ms.TimeNow)
+	quotationConversion := salequotation.InitSaleQuotationConversion(ms, cfg)
+	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
+	svc := services.NewSaleOrderHttpService(repo, docNoSequencer, delivery, quotationConversion, masterSyncCacheRepo, ms.TimeNow)
+
+	return SaleOrderHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h SaleOrderHttp) RegisterHttp() {
+
+	h.ms.GET("/transaction/sale-order", h.SearchSaleOrderPage)
+	h.ms.GET("/transaction/sale-order/list", h.SearchSaleOrderStep)
+	h.ms.GET("/transaction/sale-order/open", h.SearchOpenSaleOrder)
+	h.ms.GET("/transaction/sale-order/reserved-stock", h.ReservedStock)
+	h.ms.GET("/transaction/sale-order/stock-balance", h.StockBalance)
+	h.ms.POST("/transaction/sale-order", h.CreateSaleOrder)
+	h.ms.GET("/transaction/sale-order/:id", h.InfoSaleOrder)
+	h.ms.GET("/transaction/sale-order/code/:code", h.InfoSaleOrderByCode)
+	h.ms.PUT("/transaction/sale-order/:id", h.UpdateSaleOrder)
+	h.ms.DELETE("/transaction/sale-order/:id", h.DeleteSaleOrder)
+	h.ms.DELETE("/transaction/sale-order", h.DeleteSaleOrderByGUIDs)
+
+	h.ms.POST("/transaction/sale-order/:id/confirm", h.ConfirmSaleOrder)
+	h.ms.POST("/transaction/sale-order/:id/cancel", h.CancelSaleOrder)
+	h.ms.POST("/transaction/sale-order/:id/close", h.CloseSaleOrder)
+	h.ms.POST("/transaction/sale-order/from-sale-quotation/:id", h.CreateSaleOrderFromQuotation)
+	h.ms.GET("/transaction/sale-order/:id/delivery", h.InfoSaleOrderDelivery)
+}
+
+// Create SaleOrder godoc
+// @Description Create SaleOrder, it is draft until it is confirmed
+// @Tags		SaleOrder
+// @Param		SaleOrder  body      models.SaleOrder  true  "SaleOrder"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order [post]
+func (h SaleOrderHttp) CreateSaleOrder(ctx microservice.IContext) error {
+	authUsername := ctx.UserInfo().Username
+	shopID := ctx.UserInfo().ShopID
+	input := ctx.ReadInput()
+
+	docReq := &models.SaleOrder{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	idx, docNo, err := h.svc.CreateSaleOrder(ctx.Context(), shopID, authUsername, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+		Data:    docNo,
+	})
+	return nil
+}
+
+// Update SaleOrder godoc
+// @Description Update SaleOrder which is not closed or cancelled, delivered lines must be kept and their quantity must not be less than delivered quantity
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Param		SaleOrder  body      models.SaleOrder  true  "SaleOrder"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id} [put]
+func (h SaleOrderHttp) UpdateSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	authUsername := userInfo.Username
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+	input := ctx.ReadInput()
+
+	docReq := &models.SaleOrder{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateSaleOrder(ctx.Context(), shopID, id, authUsername, *docReq)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Delete SaleOrder godoc
+// @Description Delete SaleOrder which is not delivered
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id} [delete]
+func (h SaleOrderHttp) DeleteSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+	authUsername := userInfo.Username
+
+	id := ctx.Param("id")
+
+	err := h.svc.DeleteSaleOrder(ctx.Context(), shopID, id, authUsername)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Delete SaleOrder godoc
+// @Description Delete SaleOrder
+// @Tags		SaleOrder
+// @Param		SaleOrder  body      []string  true  "SaleOrder GUIDs"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order [delete]
+func (h SaleOrderHttp) DeleteSaleOrderByGUIDs(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+	authUsername := userInfo.Username
+
+	input := ctx.ReadInput()
+
+	docReq := []string{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.DeleteSaleOrderByGUIDs(ctx.Context(), shopID, authUsername, docReq)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// Get SaleOrder godoc
+// @Description get SaleOrder info by guidfixed
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder guidfixed"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id} [get]
+func (h SaleOrderHttp) InfoSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+
+	h.ms.Logger.Debugf("Get SaleOrder %v", id)
+	doc, err := h.svc.InfoSaleOrder(ctx.Context(), shopID, id)
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Get SaleOrder By Code godoc
+// @Description get SaleOrder info by Code
+// @Tags		SaleOrder
+// @Param		code  path      string  true  "SaleOrder Code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/code/{code} [get]
+func (h SaleOrderHttp) InfoSaleOrderByCode(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	code := ctx.Param("code")
+
+	doc, err := h.svc.InfoSaleOrderByCode(ctx.Context(), shopID, code)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List SaleOrder step godoc
+// @Description get list step
+// @Tags		SaleOrder
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		orderstatus	query	string		false  "draft, confirmed, partial, delivered, closed or cancelled"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order [get]
+func (h SaleOrderHttp) SearchSaleOrderPage(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, orderFilterRequests)
+
+	docList, pagination, err := h.svc.SearchSaleOrder(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// List SaleOrder godoc
+// @Description search limit offset
+// @Tags		SaleOrder
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		orderstatus	query	string		false  "draft, confirmed, partial, delivered, closed or cancelled"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		offset	query	integer		false  "offset"
+// @Param		limit	query	integer		false  "limit"
+// @Param		lang	query	string		false  "lang"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/list [get]
+func (h SaleOrderHttp) SearchSaleOrderStep(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageableStep := utils.GetPageableStep(ctx.QueryParam)
+
+	lang := ctx.QueryParam("lang")
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, orderFilterRequests)
+
+	docList, total, err := h.svc.SearchSaleOrderStep(ctx.Context(), shopID, lang, filters, pageableStep)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    docList,
+		Total:   total,
+	})
+	return nil
+}
+
+var orderFilterRequests = []requestfilter.FilterRequest{
+	{
+		Param: "custcode",
+		Type:  requestfilter.FieldTypeString,
+	},
+	{
+		Param: "orderstatus",
+		Type:  requestfilter.FieldTypeString,
+	},
+	{
+		Param: "-",
+		Field: "docdatetime",
+		Type:  requestfilter.FieldTypeRangeDate,
+	},
+	{
+		Param: "branchcode",
+		Field: "branch.code",
+		Type:  requestfilter.FieldTypeString,
+	},
+}
+
+// Confirm SaleOrder godoc
+// @Description confirm draft SaleOrder which is not expired, confirmed order reserve stock of its remaining quantity
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id}/confirm [post]
+func (h SaleOrderHttp) ConfirmSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.ConfirmSaleOrder(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Cancel SaleOrder godoc
+// @Description cancel draft or confirmed SaleOrder which is not delivered, its stock is not reserved anymore
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id}/cancel [post]
+func (h SaleOrderHttp) CancelSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.CancelSaleOrder(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Close SaleOrder godoc
+// @Description close confirmed or partially delivered SaleOrder, its remaining quantity is not delivered and not reserved anymore
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id}/close [post]
+func (h SaleOrderHttp) CloseSaleOrder(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.CloseSaleOrder(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Create SaleOrder From SaleQuotation godoc
+// @Description create draft SaleOrder from sent or accepted SaleQuotation which is not expired, the quotation is converted
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleQuotation ID"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/from-sale-quotation/{id} [post]
+func (h SaleOrderHttp) CreateSaleOrderFromQuotation(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	idx, docNo, err := h.svc.CreateSaleOrderFromQuotation(ctx.Context(), userInfo.ShopID, userInfo.Username, id)
+
+	if err != nil {
+		ResponseDeliveryError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+		Data:    docNo,
+	})
+	return nil
+}
+
+// Get SaleOrder Delivery godoc
+// @Description get ordered, delivered, remaining and reserved quantity of lines of SaleOrder with sale invoices which deliver them
+// @Tags		SaleOrder
+// @Param		id  path      string  true  "SaleOrder guidfixed"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/{id}/delivery [get]
+func (h SaleOrderHttp) InfoSaleOrderDelivery(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoSaleOrderDelivery(ctx.Context(), shopID, id)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List Open SaleOrder godoc
+// @Description get lines which are not delivered of confirmed and partially delivered SaleOrder, lines which are not delivered by delivery date are overdue
+// @Tags		SaleOrder
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/open [get]
+func (h SaleOrderHttp) SearchOpenSaleOrder(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "custcode",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "docdatetime",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+		{
+			Param: "branchcode",
+			Field: "branch.code",
+			Type:  requestfilter.FieldTypeString,
+		},
+	})
+
+	docList, pagination, err := h.svc.SearchOpenSaleOrder(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Get Reserved Stock godoc
+// @Description get quantity of barcodes by warehouse which is reserved by confirmed and partially delivered SaleOrder
+// @Tags		SaleOrder
+// @Param		barcodes	query	string		false  "barcodes separated by comma, every reserved barcode when it is empty"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/reserved-stock [get]
+func (h SaleOrderHttp) ReservedStock(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	docList, err := h.svc.ReservedStock(ctx.Context(), shopID, queryBarcodes(ctx))
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    docList,
+	})
+	return nil
+}
+
+// Get Stock Balance godoc
+// @Description get balance quantity of barcodes with quantity which is reserved by confirmed and partially delivered SaleOrder and quantity which is available to be reserved
+// @Tags		SaleOrder
+// @Param		barcodes	query	string		false  "barcodes separated by comma, every reserved barcode when it is empty"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-order/stock-balance [get]
+func (h SaleOrderHttp) StockBalance(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	docList, err := h.svc.StockBalance(ctx.Context(), shopID, queryBarcodes(ctx))
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    docList,
+	})
+	return nil
+}
+
+func queryBarcodes(ctx microservice.IContext) []string {
+	barcodes := []string{}
+	for _, barcode := range strings.Split(ctx.QueryParam("barcodes"), ",") {
+		barcode = strings.TrimSpace(barcode)
+		if len(barcode) > 0 {
+			barcodes = append(barcodes, barcode)
+		}
+	}
+	return barcodes
+}
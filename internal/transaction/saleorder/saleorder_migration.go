@@ -0,0 +1,39 @@
+package saleorder
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of deliveries of sale orders, unique index keep one delivery per order
+// and reservations are summed by barcode
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	deliveryCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.SaleOrderDelivery{})
+	if err != nil {
+		return err
+	}
+
+	_, err = deliveryCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "orderguid", Value: 1}},
+			Options: options.Index().SetName("saleorderdelivery_shopid_orderguid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "deliveries.invoiceguid", Value: 1}},
+			Options: options.Index().SetName("saleorderdelivery_shopid_invoiceguid"),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "reservations.barcode", Value: 1}},
+			Options: options.Index().SetName("saleorderdelivery_shopid_reservationbarcode"),
+		},
+	})
+	return err
+}
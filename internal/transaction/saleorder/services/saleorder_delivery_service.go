@@ -0,0 +1,840 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/internal/transaction/saleorder/repositories"
+	"time"
+)
+
+var (
+	ErrDeliveryOrderNotFound  = errors.New("sale order not found")
+	ErrDeliveryHasDeliveries  = errors.New("sale order is delivered by sale invoices")
+	ErrDeliveryConflict       = errors.New("sale order is delivered by another sale invoice at the same time, please try again")
+	ErrDeliveryNothingToIssue = errors.New("sale order has no remaining quantity to deliver")
+)
+
+type ISaleOrderDelivery interface {
+	// Deliver replace deliveries of the sale invoice by its lines which reference lines of sale orders,
+	// nothing is saved when any line can not be delivered
+	Deliver(ctx context.Context, doc models.DeliveryDocument) error
+	// Release remove deliveries of the sale invoice from sale orders
+	Release(ctx context.Context, shopID string, invoiceGuid string) error
+	// CheckOrder return status of the order with changed lines and version of deliveries which are checked,
+	// error is returned when delivered lines are removed, changed to another barcode or ordered less than delivered
+	CheckOrder(ctx context.Context, order models.SaleOrderDoc, details []trans_models.Detail) (string, int64, error)
+	// CheckOrderDelete return ErrDeliveryHasDeliveries when the order is delivered
+	CheckOrderDelete(ctx context.Context, shopID string, orderGuid string) error
+	// CheckStock return error of lines of the order which reserve more than available quantity of their barcodes,
+	// quantity which is reserved by the order before is available to the order
+	CheckStock(ctx context.Context, order models.SaleOrderDoc) error
+	// Reserve save stock reservation of the saved order by its status and remaining quantity
+	Reserve(ctx context.Context, order models.SaleOrderDoc) error
+	// ReserveUnchanged save stock reservation of the changed order when its deliveries are still the version
+	// which is checked, ErrDeliveryConflict is returned when the order is delivered after it is checked
+	ReserveUnchanged(ctx context.Context, order models.SaleOrderDoc, version int64) error
+	RemoveOrder(ctx context.Context, shopID string, orderGuid string) error
+	// FindDeliverable return confirmed or partially delivered order with its remaining quantity
+	FindDeliverable(ctx context.Context, shopID string, orderGuid string) (models.SaleOrderDoc, []models.DeliveryLine, error)
+	Info(ctx context.Context, order models.SaleOrderDoc) (models.SaleOrderDeliveryInfo, error)
+	OpenLines(ctx context.Context, shopID string, orders []models.SaleOrderInfo) ([]models.OpenOrderLine, error)
+	ReservedStock(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error)
+	// StockBalance return balance quantity of barcodes with quantity which is reserved by sale orders,
+	// every reserved barcode is returned when barcodes are empty
+	StockBalance(ctx context.Context, shopID string, barcodes []string) ([]models.StockBalance, error)
+}
+
+type SaleOrderDelivery struct {
+	orderRepo repositories.ISaleOrderRepository
+	repo      repositories.ISaleOrderDeliveryRepository
+	stockRepo repositories.IStockBalanceRepository
+	timeNow   func() time.Time
+}
+
+func NewSaleOrderDelivery(
+	orderRepo repositories.ISaleOrderRepository,
+	repo repositories.ISaleOrderDeliveryRepository,
+	stockRepo repositories.IStockBalanceRepository,
+	timeNow func() time.Time,
+) *SaleOrderDelivery {
+	return &SaleOrderDelivery{
+		orderRepo: orderRepo,
+		repo:      repo,
+		stockRepo: stockRepo,
+		timeNow:   timeNow,
+	}
+}
+
+// OrderStatus return status of the order with the deliveries, confirmed order is partial when any line is delivered
+// and delivered when every line is delivered its ordered quantity, draft, closed and cancelled order keep their status
+func OrderStatus(status string, details []trans_models.Detail, deliveries []models.SaleOrderDeliveryLine) string {
+	if status != models.OrderStatusConfirmed && status != models.OrderStatusPartial && status != models.OrderStatusDelivered {
+		return status
+	}
+
+	if len(deliveries) == 0 {
+		return models.OrderStatusConfirmed
+	}
+
+	delivered := linereference.ReferencedQty(deliveries)
+	for _, detail := range details {
+		if delivered[detail.LineNumber] < detail.Qty-linereference.QtyPrecision {
+			return models.OrderStatusPartial
+		}
+	}
+
+	return models.OrderStatusDelivered
+}
+
+// Reservations return remaining quantity of lines of the order which is reserved, only confirmed and
+// partially delivered order reserve stock
+func Reservations(status string, details []trans_models.Detail, deliveries []models.SaleOrderDeliveryLine) []models.StockReservation {
+	reservations := []models.StockReservation{}
+	if !models.IsReserving(status) {
+		return reservations
+	}
+
+	delivered := linereference.ReferencedQty(deliveries)
+	for _, detail := range details {
+		remainingQty := detail.Qty - delivered[detail.LineNumber]
+		if remainingQty <= linereference.QtyPrecision {
+			continue
+		}
+
+		reservations = append(reservations, models.StockReservation{
+			LineNumber:   detail.LineNumber,
+			Barcode:      detail.Barcode,
+			ItemCode:     detail.ItemCode,
+			UnitCode:     detail.UnitCode,
+			WhCode:       detail.WhCode,
+			LocationCode: detail.LocationCode,
+			Qty:          remainingQty,
+		})
+	}
+
+	return reservations
+}
+
+// DeliveryLines return delivered, remaining and reserved quantity of lines of the order
+func DeliveryLines(details []trans_models.Detail, deliveries []models.SaleOrderDeliveryLine, reservations []models.StockReservation) []models.DeliveryLine {
+	reserved := map[int]float64{}
+	for _, reservation := range reservations {
+		reserved[reservation.LineNumber] += reservation.Qty
+	}
+
+	lines := []models.DeliveryLine{}
+	for _, detail := range details {
+		line := models.DeliveryLine{
+			LineNumber:  detail.LineNumber,
+			Barcode:     detail.Barcode,
+			ItemCode:    detail.ItemCode,
+			ItemNames:   detail.ItemNames,
+			UnitCode:    detail.UnitCode,
+			WhCode:      detail.WhCode,
+			OrderQty:    detail.Qty,
+			Price:       detail.Price,
+			ReservedQty: reserved[detail.LineNumber],
+			Deliveries:  []models.SaleOrderDeliveryLine{},
+		}
+
+		for _, delivery := range deliveries {
+			if delivery.OrderLineNumber == detail.LineNumber {
+				line.DeliveredQty += delivery.Qty
+				line.Deliveries = append(line.Deliveries, delivery)
+			}
+		}
+
+		line.RemainingQty = math.Max(detail.Qty-line.DeliveredQty, 0)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// DeliveryTransaction return transaction which deliver quantity of lines of the order, every remaining quantity
+// is delivered when quantities are not given. Lines reference the lines of the order and their amounts and
+// totals of the order are in proportion of the delivered quantity
+func DeliveryTransaction(order models.SaleOrderDoc, lines []models.DeliveryLine, quantities []models.DeliveryQty) (trans_models.Transaction, error) {
+	if len(quantities) == 0 {
+		for _, line := range lines {
+			if line.RemainingQty > linereference.QtyPrecision {
+				quantities = append(quantities, models.DeliveryQty{LineNumber: line.LineNumber, Qty: line.RemainingQty})
+			}
+		}
+	}
+
+	if len(quantities) == 0 {
+		return trans_models.Transaction{}, ErrDeliveryNothingToIssue
+	}
+
+	remaining := map[int]float64{}
+	for _, line := range lines {
+		remaining[line.LineNumber] = line.RemainingQty
+	}
+
+	details := linereference.Lines(order.Details)
+	orderAmount := 0.0
+	for _, detail := range details {
+		orderAmount += detail.SumAmount
+	}
+
+	lineErrors := []linereference.LineError{}
+	deliveryDetails := []trans_models.Detail{}
+	deliveryAmount := 0.0
+	totalQty := 0.0
+
+	for _, quantity := range quantities {
+		orderLine, ok := linereference.FindLine(details, quantity.LineNumber)
+		if !ok {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: quantity.LineNumber, Message: "is not found"})
+			continue
+		}
+
+		if quantity.Qty <= 0 {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: quantity.LineNumber, Message: "must be delivered more than 0"})
+			continue
+		}
+
+		if quantity.Qty > remaining[quantity.LineNumber]+linereference.QtyPrecision {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: quantity.LineNumber, Message: fmt.Sprintf("can be delivered %v more, not %v", remaining[quantity.LineNumber], quantity.Qty)})
+			continue
+		}
+		remaining[quantity.LineNumber] -= quantity.Qty
+
+		detail := orderLine
+		if math.Abs(quantity.Qty-orderLine.Qty) > linereference.QtyPrecision && orderLine.Qty > linereference.QtyPrecision {
+			ratio := quantity.Qty / orderLine.Qty
+			detail.Qty = quantity.Qty
+			detail.TotalQty = orderLine.TotalQty * ratio
+			detail.DiscountAmount = linereference.RoundAmount(orderLine.DiscountAmount * ratio)
+			detail.TotalValueVat = linereference.RoundAmount(orderLine.TotalValueVat * ratio)
+			detail.SumAmount = linereference.RoundAmount(orderLine.SumAmount * ratio)
+			detail.SumAmountExcludeVat = linereference.RoundAmount(orderLine.SumAmountExcludeVat * ratio)
+			detail.SumOfCost = linereference.RoundAmount(orderLine.SumOfCost * ratio)
+		}
+
+		detail.LineNumber = len(deliveryDetails) + 1
+		detail.DocRef = order.DocNo
+		detail.DocRefDatetime = order.DocDatetime
+		detail.RefDocGuid = order.GuidFixed
+		detail.RefLineNumber = orderLine.LineNumber
+
+		deliveryAmount += detail.SumAmount
+		totalQty += detail.Qty
+		deliveryDetails = append(deliveryDetails, detail)
+	}
+
+	if len(lineErrors) > 0 {
+		return trans_models.Transaction{}, linereference.Error{Lines: lineErrors}
+	}
+
+	header := order.TransactionHeader
+	header.DocNo = ""
+	header.TransFlag = 0
+	header.GuidRef = ""
+	header.TaxDocNo = ""
+	header.TaxDocDate = time.Time{}
+	header.DocRefNo = order.DocNo
+	header.DocRefDate = order.DocDatetime
+	header.IsCancel = false
+	header.PaymentDetail = trans_models.PaymentDetail{}
+	header.PaymentDetailRaw = ""
+	header.PayCashAmount = 0
+	header.PayCashChange = 0
+	header.SumQRCode = 0
+	header.SumCreditCard = 0
+	header.SumMoneyTransfer = 0
+	header.SumCheque = 0
+	header.SumCoupon = 0
+	header.SumCredit = 0
+	header.PrintCopyBillDateTime = []string{}
+	header.TotalQty = totalQty
+
+	if orderAmount != 0 && math.Abs(deliveryAmount-orderAmount) > linereference.QtyPrecision {
+		ratio := deliveryAmount / orderAmount
+		header.TotalValue = linereference.RoundAmount(header.TotalValue * ratio)
+		header.TotalDiscount = linereference.RoundAmount(header.TotalDiscount * ratio)
+		header.TotalExceptVat = linereference.RoundAmount(header.TotalExceptVat * ratio)
+		header.TotalAfterVat = linereference.RoundAmount(header.TotalAfterVat * ratio)
+		header.TotalBeforeVat = linereference.RoundAmount(header.TotalBeforeVat * ratio)
+		header.TotalVatValue = linereference.RoundAmount(header.TotalVatValue * ratio)
+		header.TotalAmount = linereference.RoundAmount(header.TotalAmount * ratio)
+		header.TotalCost = linereference.RoundAmount(header.TotalCost * ratio)
+		header.TotalDiscountVatAmount = linereference.RoundAmount(header.TotalDiscountVatAmount * ratio)
+		header.TotalDiscountExceptVatAmount = linereference.RoundAmount(header.TotalDiscountExceptVatAmount * ratio)
+		header.DetailTotalAmount = linereference.RoundAmount(header.DetailTotalAmount * ratio)
+		header.DetailTotalDiscount = linereference.RoundAmount(header.DetailTotalDiscount * ratio)
+		header.DetailTotalAmountBeforeDiscount = linereference.RoundAmount(header.DetailTotalAmountBeforeDiscount * ratio)
+		header.TotalAmountAfterDiscount = linereference.RoundAmount(header.TotalAmountAfterDiscount * ratio)
+		header.RoundAmount = 0
+	}
+
+	return trans_models.Transaction{
+		TransactionHeader: header,
+		Details:           &deliveryDetails,
+	}, nil
+}
+
+func (svc SaleOrderDelivery) Deliver(ctx context.Context, doc models.DeliveryDocument) error {
+	return linereference.Retry(ErrDeliveryConflict, func() error {
+		return svc.deliver(ctx, doc)
+	})
+}
+
+func (svc SaleOrderDelivery) deliver(ctx context.Context, doc models.DeliveryDocument) error {
+	// orders which are delivered by the invoice before are saved without its deliveries when it does not reference them anymore
+	previousList, err := svc.repo.FindByInvoice(ctx, doc.ShopID, doc.InvoiceGuid)
+	if err != nil {
+		return err
+	}
+
+	deliveryDocs := map[string]models.SaleOrderDelivery{}
+	orderGuids := []string{}
+	for _, deliveryDoc := range previousList {
+		deliveryDocs[deliveryDoc.OrderGuid] = deliveryDoc
+		orderGuids = append(orderGuids, deliveryDoc.OrderGuid)
+	}
+
+	linesByOrder := map[string][]models.DeliveryDocumentLine{}
+	for _, line := range doc.Lines {
+		if _, ok := linesByOrder[line.OrderGuid]; !ok {
+			if _, ok := deliveryDocs[line.OrderGuid]; !ok {
+				orderGuids = append(orderGuids, line.OrderGuid)
+			}
+		}
+		linesByOrder[line.OrderGuid] = append(linesByOrder[line.OrderGuid], line)
+	}
+
+	lineErrors := []linereference.LineError{}
+	orderStatuses := map[string]string{}
+	changedStatuses := map[string]string{}
+
+	for _, orderGuid := range orderGuids {
+		deliveryDoc, ok := deliveryDocs[orderGuid]
+		if !ok {
+			deliveryDoc, err = svc.repo.FindByOrder(ctx, doc.ShopID, orderGuid)
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err := svc.orderRepo.FindByGuid(ctx, doc.ShopID, orderGuid)
+		if err != nil {
+			return err
+		}
+
+		lines := linesByOrder[orderGuid]
+
+		if len(order.GuidFixed) < 1 {
+			for _, line := range lines {
+				lineErrors = append(lineErrors, linereference.LineError{LineNumber: line.LineNumber, Message: ErrDeliveryOrderNotFound.Error()})
+			}
+			delete(deliveryDocs, orderGuid)
+			continue
+		}
+
+		details := linereference.Lines(order.Details)
+		deliveries := linereference.WithoutDocument(deliveryDoc.Deliveries, doc.InvoiceGuid)
+
+		if len(lines) > 0 {
+			lineErrors = append(lineErrors, svc.validateDelivery(order, details, deliveries, lines)...)
+		}
+
+		for _, line := range lines {
+			deliveries = append(deliveries, models.SaleOrderDeliveryLine{
+				InvoiceGuid:     doc.InvoiceGuid,
+				InvoiceDocNo:    doc.InvoiceDocNo,
+				DocDatetime:     doc.DocDatetime,
+				LineNumber:      line.LineNumber,
+				OrderLineNumber: line.OrderLineNumber,
+				Qty:             line.Qty,
+			})
+		}
+
+		status := OrderStatus(order.OrderStatus, details, deliveries)
+
+		deliveryDoc.ShopID = doc.ShopID
+		deliveryDoc.OrderGuid = orderGuid
+		deliveryDoc.OrderDocNo = order.DocNo
+		deliveryDoc.Deliveries = deliveries
+		deliveryDoc.Reservations = Reservations(status, details, deliveries)
+		deliveryDoc.UpdatedAt = svc.timeNow()
+
+		deliveryDocs[orderGuid] = deliveryDoc
+		orderStatuses[orderGuid] = order.OrderStatus
+		changedStatuses[orderGuid] = status
+	}
+
+	if len(lineErrors) > 0 {
+		return linereference.Error{Lines: lineErrors}
+	}
+
+	for _, orderGuid := range orderGuids {
+		deliveryDoc, ok := deliveryDocs[orderGuid]
+		if !ok {
+			continue
+		}
+
+		isSaved, err := svc.repo.Save(ctx, deliveryDoc)
+		if err != nil {
+			return err
+		}
+
+		if !isSaved {
+			return ErrDeliveryConflict
+		}
+
+		if changedStatuses[orderGuid] != orderStatuses[orderGuid] {
+			isUpdated, err := svc.orderRepo.UpdateOrderStatus(ctx, doc.ShopID, orderGuid, orderStatuses[orderGuid], changedStatuses[orderGuid])
+			if err != nil {
+				return err
+			}
+
+			// status which is changed after the order is read is delivered again with the changed status
+			if !isUpdated {
+				return ErrDeliveryConflict
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc SaleOrderDelivery) validateDelivery(
+	order models.SaleOrderDoc,
+	details []trans_models.Detail,
+	deliveries []models.SaleOrderDeliveryLine,
+	lines []models.DeliveryDocumentLine,
+) []linereference.LineError {
+
+	lineErrors := []linereference.LineError{}
+	lineError := func(line models.DeliveryDocumentLine, format string, args ...interface{}) {
+		lineErrors = append(lineErrors, linereference.LineError{
+			LineNumber: line.LineNumber,
+			Message:    fmt.Sprintf("sale order %s line %d ", order.DocNo, line.OrderLineNumber) + fmt.Sprintf(format, args...),
+		})
+	}
+
+	// status is read without deliveries of the invoice so the invoice which deliver the order completely can be changed
+	status := OrderStatus(order.OrderStatus, details, deliveries)
+	if !models.IsReserving(status) {
+		for _, line := range lines {
+			if status == models.OrderStatusDraft {
+				lineError(line, "is not confirmed")
+			} else {
+				lineError(line, "is %s", status)
+			}
+		}
+		return lineErrors
+	}
+
+	delivered := linereference.ReferencedQty(deliveries)
+
+	for _, line := range lines {
+		orderLine, ok := linereference.FindLine(details, line.OrderLineNumber)
+		if !ok {
+			lineError(line, "is not found")
+			continue
+		}
+
+		if line.Barcode != orderLine.Barcode {
+			lineError(line, "is barcode %s, not %s", orderLine.Barcode, line.Barcode)
+			continue
+		}
+
+		if line.UnitCode != "" && orderLine.UnitCode != "" && line.UnitCode != orderLine.UnitCode {
+			lineError(line, "is unit %s, not %s", orderLine.UnitCode, line.UnitCode)
+			continue
+		}
+
+		if line.Qty <= 0 {
+			lineError(line, "must be delivered more than 0")
+			continue
+		}
+
+		remainingQty := orderLine.Qty - delivered[line.OrderLineNumber]
+		if line.Qty > remainingQty+linereference.QtyPrecision {
+			lineError(line, "can be delivered %v more, not %v", math.Max(remainingQty, 0), line.Qty)
+			continue
+		}
+
+		delivered[line.OrderLineNumber] += line.Qty
+	}
+
+	return lineErrors
+}
+
+func (svc SaleOrderDelivery) Release(ctx context.Context, shopID string, invoiceGuid string) error {
+	return svc.Deliver(ctx, models.DeliveryDocument{
+		ShopID:      shopID,
+		InvoiceGuid: invoiceGuid,
+	})
+}
+
+func (svc SaleOrderDelivery) CheckOrder(ctx context.Context, order models.SaleOrderDoc, details []trans_models.Detail) (string, int64, error) {
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return "", 0, err
+	}
+
+	delivered := linereference.ReferencedQty(deliveryDoc.Deliveries)
+	previousDetails := linereference.Lines(order.Details)
+
+	lineErrors := []linereference.LineError{}
+	for _, previousLine := range previousDetails {
+		qty, ok := delivered[previousLine.LineNumber]
+		if !ok {
+			continue
+		}
+
+		orderLine, ok := linereference.FindLine(details, previousLine.LineNumber)
+		if !ok {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is delivered %v and can not be removed", qty)})
+			continue
+		}
+
+		if orderLine.Barcode != previousLine.Barcode {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is delivered and can not be changed to barcode %s", orderLine.Barcode)})
+			continue
+		}
+
+		if orderLine.Qty < qty-linereference.QtyPrecision {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: previousLine.LineNumber, Message: fmt.Sprintf("is delivered %v and can not be ordered %v", qty, orderLine.Qty)})
+		}
+	}
+
+	if len(lineErrors) > 0 {
+		return "", 0, linereference.Error{Lines: lineErrors}
+	}
+
+	return OrderStatus(order.OrderStatus, details, deliveryDoc.Deliveries), deliveryDoc.Version, nil
+}
+
+func (svc SaleOrderDelivery) CheckOrderDelete(ctx context.Context, shopID string, orderGuid string) error {
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, shopID, orderGuid)
+	if err != nil {
+		return err
+	}
+
+	if len(deliveryDoc.Deliveries) > 0 {
+		return ErrDeliveryHasDeliveries
+	}
+
+	return nil
+}
+
+func (svc SaleOrderDelivery) CheckStock(ctx context.Context, order models.SaleOrderDoc) error {
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return err
+	}
+
+	details := linereference.Lines(order.Details)
+	reservations := Reservations(order.OrderStatus, details, deliveryDoc.Deliveries)
+
+	// only products which are kept in stock are checked
+	isStock := map[string]bool{}
+	for _, detail := range details {
+		if detail.ItemType == 0 {
+			isStock[detail.Barcode] = true
+		}
+	}
+
+	// barcodes which are reserved less than before are not checked, so the order can always reduce its lines
+	previousQty := reservedQty(deliveryDoc.Reservations)
+	isChecked := map[string]bool{}
+	barcodes := []string{}
+	for barcode, qty := range reservedQty(reservations) {
+		if isStock[barcode] && qty > previousQty[barcode]+linereference.QtyPrecision {
+			isChecked[barcode] = true
+			barcodes = append(barcodes, barcode)
+		}
+	}
+
+	if len(barcodes) == 0 {
+		return nil
+	}
+
+	balances, err := svc.StockBalance(ctx, order.ShopID, barcodes)
+	if err != nil {
+		return err
+	}
+
+	availableQty := map[string]float64{}
+	for _, balance := range balances {
+		availableQty[balance.Barcode] = balance.AvailableQty + previousQty[balance.Barcode]
+	}
+
+	lineErrors := []linereference.LineError{}
+	for _, reservation := range reservations {
+		if !isChecked[reservation.Barcode] {
+			continue
+		}
+
+		available := availableQty[reservation.Barcode]
+		if reservation.Qty > available+linereference.QtyPrecision {
+			lineErrors = append(lineErrors, linereference.LineError{LineNumber: reservation.LineNumber, Message: fmt.Sprintf("stock of %s is available %v, not %v", reservation.Barcode, math.Max(available, 0), reservation.Qty)})
+		}
+		availableQty[reservation.Barcode] = available - reservation.Qty
+	}
+
+	if len(lineErrors) > 0 {
+		return linereference.Error{Lines: lineErrors}
+	}
+
+	return nil
+}
+
+func reservedQty(reservations []models.StockReservation) map[string]float64 {
+	reserved := map[string]float64{}
+	for _, reservation := range reservations {
+		reserved[reservation.Barcode] += reservation.Qty
+	}
+	return reserved
+}
+
+func (svc SaleOrderDelivery) Reserve(ctx context.Context, order models.SaleOrderDoc) error {
+	for attempt := 0; attempt < linereference.MaxSaveAttempts; attempt++ {
+		deliveryDoc, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+		if err != nil {
+			return err
+		}
+
+		// the order is read after its deliveries, so reservation of the status which is changed by another request
+		// at the same time is saved by that request or the delivery document is changed and read again
+		savedOrder, err := svc.orderRepo.FindByGuid(ctx, order.ShopID, order.GuidFixed)
+		if err != nil {
+			return err
+		}
+
+		if len(savedOrder.GuidFixed) < 1 {
+			return ErrDeliveryOrderNotFound
+		}
+
+		isSaved, err := svc.saveReservations(ctx, savedOrder, deliveryDoc)
+		if err != nil {
+			return err
+		}
+
+		if isSaved {
+			return nil
+		}
+	}
+
+	return ErrDeliveryConflict
+}
+
+func (svc SaleOrderDelivery) ReserveUnchanged(ctx context.Context, order models.SaleOrderDoc, version int64) error {
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return err
+	}
+
+	if deliveryDoc.Version != version {
+		return ErrDeliveryConflict
+	}
+
+	isSaved, err := svc.saveReservations(ctx, order, deliveryDoc)
+	if err != nil {
+		return err
+	}
+
+	if !isSaved {
+		return ErrDeliveryConflict
+	}
+
+	return nil
+}
+
+// saveReservations save reservation of the order with its deliveries, false is returned when the deliveries
+// are changed after they are read
+func (svc SaleOrderDelivery) saveReservations(ctx context.Context, order models.SaleOrderDoc, deliveryDoc models.SaleOrderDelivery) (bool, error) {
+	reservations := Reservations(order.OrderStatus, linereference.Lines(order.Details), deliveryDoc.Deliveries)
+
+	// draft order has nothing to keep until it is confirmed
+	if deliveryDoc.Version == 0 && len(reservations) == 0 {
+		return true, nil
+	}
+
+	deliveryDoc.ShopID = order.ShopID
+	deliveryDoc.OrderGuid = order.GuidFixed
+	deliveryDoc.OrderDocNo = order.DocNo
+	deliveryDoc.Reservations = reservations
+	deliveryDoc.UpdatedAt = svc.timeNow()
+
+	if deliveryDoc.Deliveries == nil {
+		deliveryDoc.Deliveries = []models.SaleOrderDeliveryLine{}
+	}
+
+	return svc.repo.Save(ctx, deliveryDoc)
+}
+
+func (svc SaleOrderDelivery) RemoveOrder(ctx context.Context, shopID string, orderGuid string) error {
+	return svc.repo.DeleteByOrder(ctx, shopID, orderGuid)
+}
+
+func (svc SaleOrderDelivery) FindDeliverable(ctx context.Context, shopID string, orderGuid string) (models.SaleOrderDoc, []models.DeliveryLine, error) {
+	order, err := svc.orderRepo.FindByGuid(ctx, shopID, orderGuid)
+	if err != nil {
+		return models.SaleOrderDoc{}, []models.DeliveryLine{}, err
+	}
+
+	if len(order.GuidFixed) < 1 {
+		return models.SaleOrderDoc{}, []models.DeliveryLine{}, ErrDeliveryOrderNotFound
+	}
+
+	if !models.IsReserving(order.OrderStatus) {
+		return models.SaleOrderDoc{}, []models.DeliveryLine{}, fmt.Errorf("sale order %s is %s and can not be delivered", order.DocNo, order.OrderStatus)
+	}
+
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, shopID, orderGuid)
+	if err != nil {
+		return models.SaleOrderDoc{}, []models.DeliveryLine{}, err
+	}
+
+	return order, DeliveryLines(linereference.Lines(order.Details), deliveryDoc.Deliveries, deliveryDoc.Reservations), nil
+}
+
+func (svc SaleOrderDelivery) Info(ctx context.Context, order models.SaleOrderDoc) (models.SaleOrderDeliveryInfo, error) {
+	deliveryDoc, err := svc.repo.FindByOrder(ctx, order.ShopID, order.GuidFixed)
+	if err != nil {
+		return models.SaleOrderDeliveryInfo{}, err
+	}
+
+	return models.SaleOrderDeliveryInfo{
+		OrderGuid:    order.GuidFixed,
+		OrderDocNo:   order.DocNo,
+		DocDatetime:  order.DocDatetime,
+		DeliveryDate: order.DeliveryDate,
+		CustCode:     order.CustCode,
+		OrderStatus:  order.OrderStatus,
+		Lines:        DeliveryLines(linereference.Lines(order.Details), deliveryDoc.Deliveries, deliveryDoc.Reservations),
+	}, nil
+}
+
+// OpenLines return lines of the orders which are not delivered completely, line is overdue when
+// delivery date of its order is passed
+func (svc SaleOrderDelivery) OpenLines(ctx context.Context, shopID string, orders []models.SaleOrderInfo) ([]models.OpenOrderLine, error) {
+	if len(orders) == 0 {
+		return []models.OpenOrderLine{}, nil
+	}
+
+	orderGuids := []string{}
+	for _, order := range orders {
+		orderGuids = append(orderGuids, order.GuidFixed)
+	}
+
+	deliveryDocs, err := svc.repo.FindByOrders(ctx, shopID, orderGuids)
+	if err != nil {
+		return []models.OpenOrderLine{}, err
+	}
+
+	deliveries := map[string][]models.SaleOrderDeliveryLine{}
+	for _, deliveryDoc := range deliveryDocs {
+		deliveries[deliveryDoc.OrderGuid] = deliveryDoc.Deliveries
+	}
+
+	now := svc.timeNow()
+
+	result := []models.OpenOrderLine{}
+	for _, order := range orders {
+		details := linereference.Lines(order.Details)
+		isOverdue := !order.DeliveryDate.IsZero() && order.DeliveryDate.Before(now)
+
+		for _, line := range DeliveryLines(details, deliveries[order.GuidFixed], nil) {
+			if line.RemainingQty <= linereference.QtyPrecision {
+				continue
+			}
+
+			orderLine, _ := linereference.FindLine(details, line.LineNumber)
+
+			openLine := models.OpenOrderLine{
+				OrderGuid:    order.GuidFixed,
+				OrderDocNo:   order.DocNo,
+				DocDatetime:  order.DocDatetime,
+				DeliveryDate: order.DeliveryDate,
+				CustCode:     order.CustCode,
+				CustNames:    order.CustNames,
+				OrderStatus:  order.OrderStatus,
+				LineNumber:   line.LineNumber,
+				Barcode:      line.Barcode,
+				ItemCode:     line.ItemCode,
+				ItemNames:    line.ItemNames,
+				UnitCode:     line.UnitCode,
+				WhCode:       line.WhCode,
+				OrderQty:     line.OrderQty,
+				DeliveredQty: line.DeliveredQty,
+				RemainingQty: line.RemainingQty,
+				IsOverdue:    isOverdue,
+			}
+
+			if line.OrderQty > linereference.QtyPrecision {
+				openLine.RemainingAmount = linereference.RoundAmount(orderLine.SumAmount * line.RemainingQty / line.OrderQty)
+			}
+
+			result = append(result, openLine)
+		}
+	}
+
+	return result, nil
+}
+
+func (svc SaleOrderDelivery) ReservedStock(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error) {
+	return svc.repo.SumReserved(ctx, shopID, barcodes)
+}
+
+func (svc SaleOrderDelivery) StockBalance(ctx context.Context, shopID string, barcodes []string) ([]models.StockBalance, error) {
+	reservedList, err := svc.repo.SumReserved(ctx, shopID, barcodes)
+	if err != nil {
+		return []models.StockBalance{}, err
+	}
+
+	reserved := map[string]float64{}
+	reservedBarcodes := []string{}
+	for _, reservedStock := range reservedList {
+		if _, ok := reserved[reservedStock.Barcode]; !ok {
+			reservedBarcodes = append(reservedBarcodes, reservedStock.Barcode)
+		}
+		reserved[reservedStock.Barcode] += reservedStock.Qty
+	}
+
+	if len(barcodes) == 0 {
+		barcodes = reservedBarcodes
+	}
+
+	if len(barcodes) == 0 {
+		return []models.StockBalance{}, nil
+	}
+
+	balanceList, err := svc.stockRepo.FindByBarcodes(shopID, barcodes)
+	if err != nil {
+		return []models.StockBalance{}, err
+	}
+
+	balance := map[string]float64{}
+	for _, productBarcode := range balanceList {
+		balance[productBarcode.Barcode] = productBarcode.BalanceQty
+	}
+
+	result := []models.StockBalance{}
+	found := map[string]bool{}
+	for _, barcode := range barcodes {
+		if found[barcode] {
+			continue
+		}
+		found[barcode] = true
+
+		result = append(result, models.StockBalance{
+			Barcode:      barcode,
+			BalanceQty:   balance[barcode],
+			ReservedQty:  reserved[barcode],
+			AvailableQty: balance[barcode] - reserved[barcode],
+		})
+	}
+
+	return result, nil
+}
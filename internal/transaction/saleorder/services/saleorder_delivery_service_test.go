@@ -0,0 +1,368 @@
+package services_test
+
+import (
+	"context"
+	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/internal/transaction/saleorder/repositories"
+	"smlaicloudplatform/internal/transaction/saleorder/services"
+	salequotationmodels "smlaicloudplatform/internal/transaction/salequotation/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder(guid string, status string, details ...trans_models.Detail) models.SaleOrderDoc {
+	doc := models.SaleOrderDoc{}
+	doc.ShopID = "shop1"
+	doc.GuidFixed = guid
+	doc.DocNo = "SO-" + guid
+	doc.DocDatetime = time.Date(2024, 5, 9, 9, 0, 0, 0, time.UTC)
+	doc.OrderStatus = status
+	doc.Details = &details
+	return doc
+}
+
+func invoice(invoiceGuid string, lines ...models.DeliveryDocumentLine) models.DeliveryDocument {
+	return models.DeliveryDocument{
+		ShopID:       "shop1",
+		InvoiceGuid:  invoiceGuid,
+		InvoiceDocNo: "SI-" + invoiceGuid,
+		DocDatetime:  time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC),
+		Lines:        lines,
+	}
+}
+
+func TestSaleOrderDelivery_Reserve(t *testing.T) {
+	orderRepo := new(SaleOrderRepositoryMock)
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	order := newOrder("so1", models.OrderStatusConfirmed,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", UnitCode: "PCS", WhCode: "WH1", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B2", UnitCode: "PCS", WhCode: "WH1", Qty: 5},
+	)
+
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(models.SaleOrderDelivery{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "so1").Return(order, nil)
+	deliveryRepo.On("Save", mock.MatchedBy(func(doc models.SaleOrderDelivery) bool {
+		return doc.OrderGuid == "so1" && doc.Version == 0 && len(doc.Reservations) == 2
+	})).Return(true, nil)
+
+	svc := services.NewSaleOrderDelivery(orderRepo, deliveryRepo, nil, time.Now)
+
+	// reservation is read from the saved order
+	require.NoError(t, svc.Reserve(context.Background(), newOrder("so1", models.OrderStatusDraft)))
+	deliveryRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestSaleOrderDelivery_Deliver(t *testing.T) {
+	orderRepo := new(SaleOrderRepositoryMock)
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	order := newOrder("so1", models.OrderStatusConfirmed,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", UnitCode: "PCS", WhCode: "WH1", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B2", UnitCode: "PCS", WhCode: "WH1", Qty: 5},
+	)
+
+	deliveryRepo.On("FindByInvoice", "shop1", "si1").Return([]models.SaleOrderDelivery{}, nil)
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(models.SaleOrderDelivery{ShopID: "shop1", OrderGuid: "so1", Version: 1}, nil)
+	orderRepo.On("FindByGuid", "shop1", "so1").Return(order, nil)
+
+	// partial delivery keep reservation of remaining quantity
+	deliveryRepo.On("Save", mock.MatchedBy(func(doc models.SaleOrderDelivery) bool {
+		return doc.Version == 1 && len(doc.Deliveries) == 1 && assert.ObjectsAreEqual([]models.StockReservation{
+			{LineNumber: 1, Barcode: "B1", UnitCode: "PCS", WhCode: "WH1", Qty: 6},
+			{LineNumber: 2, Barcode: "B2", UnitCode: "PCS", WhCode: "WH1", Qty: 5},
+		}, doc.Reservations)
+	})).Return(true, nil)
+	orderRepo.On("UpdateOrderStatus", "shop1", "so1", models.OrderStatusConfirmed, models.OrderStatusPartial).Return(true, nil)
+
+	svc := services.NewSaleOrderDelivery(orderRepo, deliveryRepo, nil, time.Now)
+
+	err := svc.Deliver(context.Background(), invoice("si1",
+		models.DeliveryDocumentLine{LineNumber: 1, OrderGuid: "so1", OrderLineNumber: 1, Barcode: "B1", UnitCode: "PCS", Qty: 4},
+	))
+	require.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestSaleOrderDelivery_DeliverInvalidLines(t *testing.T) {
+	orderRepo := new(SaleOrderRepositoryMock)
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	deliveryRepo.On("FindByInvoice", "shop1", "si2").Return([]models.SaleOrderDelivery{}, nil)
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(models.SaleOrderDelivery{
+		ShopID:     "shop1",
+		OrderGuid:  "so1",
+		Deliveries: []models.SaleOrderDeliveryLine{{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 1, Qty: 4}},
+		Version:    2,
+	}, nil)
+	deliveryRepo.On("FindByOrder", "shop1", "so2").Return(models.SaleOrderDelivery{}, nil)
+	orderRepo.On("FindByGuid", "shop1", "so1").Return(newOrder("so1", models.OrderStatusPartial,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B2", Qty: 5},
+	), nil)
+	orderRepo.On("FindByGuid", "shop1", "so2").Return(newOrder("so2", models.OrderStatusDraft,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 1},
+	), nil)
+
+	svc := services.NewSaleOrderDelivery(orderRepo, deliveryRepo, nil, time.Now)
+
+	// over delivery, another barcode and draft order are rejected by line of the invoice
+	err := svc.Deliver(context.Background(), invoice("si2",
+		models.DeliveryDocumentLine{LineNumber: 1, OrderGuid: "so1", OrderLineNumber: 1, Barcode: "B1", Qty: 7},
+		models.DeliveryDocumentLine{LineNumber: 2, OrderGuid: "so1", OrderLineNumber: 2, Barcode: "B9", Qty: 1},
+		models.DeliveryDocumentLine{LineNumber: 3, OrderGuid: "so2", OrderLineNumber: 1, Barcode: "B1", Qty: 1},
+	))
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	require.Len(t, lineErr.Lines, 3)
+	assert.Equal(t, 1, lineErr.Lines[0].LineNumber)
+	assert.Contains(t, lineErr.Lines[0].Message, "can be delivered 6 more")
+	assert.Equal(t, 2, lineErr.Lines[1].LineNumber)
+	assert.Equal(t, 3, lineErr.Lines[2].LineNumber)
+	assert.Contains(t, lineErr.Lines[2].Message, "is not confirmed")
+	deliveryRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestSaleOrderDelivery_ReleaseStatusChanged(t *testing.T) {
+	orderRepo := new(SaleOrderRepositoryMock)
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	deliveryDoc := models.SaleOrderDelivery{
+		ShopID:    "shop1",
+		OrderGuid: "so1",
+		Deliveries: []models.SaleOrderDeliveryLine{
+			{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 1, Qty: 10},
+		},
+		Version: 3,
+	}
+
+	deliveryRepo.On("FindByInvoice", "shop1", "si1").Return([]models.SaleOrderDelivery{deliveryDoc}, nil)
+	orderRepo.On("FindByGuid", "shop1", "so1").Return(newOrder("so1", models.OrderStatusDelivered,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10},
+	), nil)
+	deliveryRepo.On("Save", mock.MatchedBy(func(doc models.SaleOrderDelivery) bool {
+		return len(doc.Deliveries) == 0 && len(doc.Reservations) == 1
+	})).Return(true, nil)
+
+	// the order is closed by another request after it is read
+	orderRepo.On("UpdateOrderStatus", "shop1", "so1", models.OrderStatusDelivered, models.OrderStatusConfirmed).Return(false, nil)
+
+	svc := services.NewSaleOrderDelivery(orderRepo, deliveryRepo, nil, time.Now)
+
+	err := svc.Release(context.Background(), "shop1", "si1")
+	require.ErrorIs(t, err, services.ErrDeliveryConflict)
+	orderRepo.AssertNumberOfCalls(t, "UpdateOrderStatus", 3)
+}
+
+func TestSaleOrderDelivery_CheckOrder(t *testing.T) {
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(models.SaleOrderDelivery{
+		Deliveries: []models.SaleOrderDeliveryLine{
+			{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 1, Qty: 10},
+			{InvoiceGuid: "si1", LineNumber: 2, OrderLineNumber: 2, Qty: 5},
+		},
+	}, nil)
+
+	svc := services.NewSaleOrderDelivery(nil, deliveryRepo, nil, time.Now)
+
+	order := newOrder("so1", models.OrderStatusDelivered,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10},
+		trans_models.Detail{LineNumber: 2, Barcode: "B2", Qty: 5},
+	)
+
+	// delivered lines can not be reduced below delivered quantity or removed
+	_, _, err := svc.CheckOrder(context.Background(), order, []trans_models.Detail{{LineNumber: 1, Barcode: "B1", Qty: 8}})
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	assert.Len(t, lineErr.Lines, 2)
+
+	status, _, err := svc.CheckOrder(context.Background(), order, []trans_models.Detail{
+		{LineNumber: 1, Barcode: "B1", Qty: 12},
+		{LineNumber: 2, Barcode: "B2", Qty: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusPartial, status)
+}
+
+func TestDeliveryTransaction(t *testing.T) {
+	order := newOrder("so1", models.OrderStatusPartial,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10, Price: 100, SumAmount: 1000},
+		trans_models.Detail{LineNumber: 2, Barcode: "B2", Qty: 4, Price: 50, SumAmount: 200},
+	)
+	order.TotalAmount = 1200
+	order.TotalQty = 14
+	order.PayCashAmount = 1200
+
+	deliveries := []models.SaleOrderDeliveryLine{{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 2, Qty: 4}}
+	lines := services.DeliveryLines(*order.Details, deliveries, nil)
+
+	doc, err := services.DeliveryTransaction(order, lines, []models.DeliveryQty{{LineNumber: 1, Qty: 3}})
+	require.NoError(t, err)
+
+	details := *doc.Details
+	require.Len(t, details, 1)
+	assert.Equal(t, 1, details[0].LineNumber)
+	assert.Equal(t, "so1", details[0].RefDocGuid)
+	assert.Equal(t, 1, details[0].RefLineNumber)
+	assert.Equal(t, 3.0, details[0].Qty)
+	assert.Equal(t, 300.0, details[0].SumAmount)
+	assert.Equal(t, 300.0, doc.TotalAmount)
+	assert.Equal(t, 3.0, doc.TotalQty)
+	assert.Equal(t, "SO-so1", doc.DocRefNo)
+	assert.Zero(t, doc.PayCashAmount)
+
+	// remaining quantity is delivered when quantities are not given
+	doc, err = services.DeliveryTransaction(order, lines, nil)
+	require.NoError(t, err)
+	assert.Len(t, *doc.Details, 1)
+	assert.Equal(t, 10.0, (*doc.Details)[0].Qty)
+
+	_, err = services.DeliveryTransaction(order, lines, []models.DeliveryQty{{LineNumber: 2, Qty: 1}, {LineNumber: 3, Qty: 1}})
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	assert.Len(t, lineErr.Lines, 2)
+}
+
+func TestSaleOrderFromQuotation(t *testing.T) {
+	quotation := salequotationmodels.SaleQuotationDoc{}
+	quotation.ShopID = "shop1"
+	quotation.GuidFixed = "qt1"
+	quotation.DocNo = "QT-001"
+	quotation.DocDatetime = time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
+	quotation.CustCode = "C1"
+	quotation.Details = &[]trans_models.Detail{{Barcode: "B1", Qty: 2}, {Barcode: "B2", Qty: 1}}
+
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	doc := services.SaleOrderFromQuotation(quotation, now)
+
+	assert.Empty(t, doc.DocNo)
+	assert.Equal(t, now, doc.DocDatetime)
+	assert.Equal(t, "C1", doc.CustCode)
+	assert.Equal(t, "QT-001", doc.DocRefNo)
+	assert.Equal(t, "qt1", doc.QuotationGuid)
+
+	details := *doc.Details
+	require.Len(t, details, 2)
+	assert.Equal(t, 2, details[1].LineNumber)
+	assert.Equal(t, "qt1", details[1].RefDocGuid)
+	assert.Equal(t, 2, details[1].RefLineNumber)
+
+	// lines of the quotation are not changed
+	assert.Equal(t, 0, (*quotation.Details)[0].LineNumber)
+}
+
+func TestSaleOrderDelivery_CheckStock(t *testing.T) {
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+	stockRepo := new(StockBalanceRepositoryMock)
+
+	// the order reserve 4 before and other orders reserve 5
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(models.SaleOrderDelivery{
+		ShopID:       "shop1",
+		OrderGuid:    "so1",
+		Reservations: []models.StockReservation{{LineNumber: 1, Barcode: "B1", WhCode: "WH1", Qty: 4}},
+		Version:      1,
+	}, nil)
+	deliveryRepo.On("SumReserved", "shop1", []string{"B1"}).Return([]models.ReservedStock{{Barcode: "B1", WhCode: "WH1", Qty: 9}}, nil)
+	stockRepo.On("FindByBarcodes", "shop1", []string{"B1"}).Return([]productbarcode_models.ProductBarcodePg{{Barcode: "B1", BalanceQty: 14}}, nil)
+
+	svc := services.NewSaleOrderDelivery(nil, deliveryRepo, stockRepo, time.Now)
+
+	service := trans_models.Detail{LineNumber: 2, Barcode: "S1", ItemType: 1, Qty: 3}
+
+	order := newOrder("so1", models.OrderStatusConfirmed, trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10}, service)
+	err := svc.CheckStock(context.Background(), order)
+
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	require.Len(t, lineErr.Lines, 1)
+	assert.Equal(t, 1, lineErr.Lines[0].LineNumber)
+	assert.Contains(t, lineErr.Lines[0].Message, "available 9, not 10")
+
+	order = newOrder("so1", models.OrderStatusConfirmed, trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 9}, service)
+	require.NoError(t, svc.CheckStock(context.Background(), order))
+
+	// reduced lines are not checked
+	order = newOrder("so1", models.OrderStatusConfirmed, trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 2})
+	require.NoError(t, svc.CheckStock(context.Background(), order))
+	stockRepo.AssertNumberOfCalls(t, "FindByBarcodes", 2)
+
+	balances, err := svc.StockBalance(context.Background(), "shop1", []string{"B1"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.StockBalance{{Barcode: "B1", BalanceQty: 14, ReservedQty: 9, AvailableQty: 5}}, balances)
+}
+
+type SaleOrderRepositoryMock struct {
+	repositories.ISaleOrderRepository
+	mock.Mock
+}
+
+func (m *SaleOrderRepositoryMock) FindByGuid(ctx context.Context, shopID string, guid string) (models.SaleOrderDoc, error) {
+	args := m.Called(shopID, guid)
+	return args.Get(0).(models.SaleOrderDoc), args.Error(1)
+}
+
+func (m *SaleOrderRepositoryMock) UpdateOrderStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string) (bool, error) {
+	args := m.Called(shopID, guid, fromStatus, toStatus)
+	return args.Bool(0), args.Error(1)
+}
+
+type SaleOrderDeliveryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) FindByOrder(ctx context.Context, shopID string, orderGuid string) (models.SaleOrderDelivery, error) {
+	args := m.Called(shopID, orderGuid)
+	return args.Get(0).(models.SaleOrderDelivery), args.Error(1)
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) FindByOrders(ctx context.Context, shopID string, orderGuids []string) ([]models.SaleOrderDelivery, error) {
+	args := m.Called(shopID, orderGuids)
+	return args.Get(0).([]models.SaleOrderDelivery), args.Error(1)
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) FindByInvoice(ctx context.Context, shopID string, invoiceGuid string) ([]models.SaleOrderDelivery, error) {
+	args := m.Called(shopID, invoiceGuid)
+	return args.Get(0).([]models.SaleOrderDelivery), args.Error(1)
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) Save(ctx context.Context, doc models.SaleOrderDelivery) (bool, error) {
+	args := m.Called(doc)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) DeleteByOrder(ctx context.Context, shopID string, orderGuid string) error {
+	args := m.Called(shopID, orderGuid)
+	return args.Error(0)
+}
+
+func (m *SaleOrderDeliveryRepositoryMock) SumReserved(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error) {
+	args := m.Called(shopID, barcodes)
+	return args.Get(0).([]models.ReservedStock), args.Error(1)
+}
+
+type StockBalanceRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StockBalanceRepositoryMock) FindByBarcodes(shopID string, barcodes []string) ([]productbarcode_models.ProductBarcodePg, error) {
+	args := m.Called(shopID, barcodes)
+	return args.Get(0).([]productbarcode_models.ProductBarcodePg), args.Error(1)
+}
+
+func (m *SaleOrderRepositoryMock) UpdateWhenStatus(ctx context.Context, shopID string, guid string, fromStatus string, doc models.SaleOrderDoc) (bool, error) {
+	args := m.Called(shopID, guid, fromStatus, doc)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SaleOrderRepositoryMock) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return queryFunc(ctx)
+}
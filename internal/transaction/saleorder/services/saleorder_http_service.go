@@ -0,0 +1,638 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mastersync "smlaicloudplatform/internal/mastersync/repositories"
+	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/internal/transaction/saleorder/repositories"
+	salequotationmodels "smlaicloudplatform/internal/transaction/salequotation/models"
+	salequotationservices "smlaicloudplatform/internal/transaction/salequotation/services"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"strings"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	ErrOrderExpired    = errors.New("sale order is expired, extend its valid until date first")
+	ErrOrderValidUntil = errors.New("valid until date must not be before document date")
+	ErrOrderNoLines    = errors.New("sale order has no lines")
+
+	ErrOrderStatusChanged = errors.New("sale order is changed by another request, please try again")
+)
+
+type ISaleOrderHttpService interface {
+	CreateSaleOrder(ctx context.Context, shopID string, authUsername string, doc models.SaleOrder) (string, string, error)
+	UpdateSaleOrder(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleOrder) error
+	DeleteSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSaleOrderByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSaleOrder(ctx context.Context, shopID string, guid string) (models.SaleOrderInfo, error)
+	InfoSaleOrderByCode(ctx context.Context, shopID string, code string) (models.SaleOrderInfo, error)
+	SearchSaleOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleOrderInfo, mongopagination.PaginationData, error)
+	SearchSaleOrderStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleOrderInfo, int, error)
+	ConfirmSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error
+	CancelSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error
+	CloseSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error
+	CreateSaleOrderFromQuotation(ctx context.Context, shopID string, authUsername string, quotationGuid string) (string, string, error)
+	InfoSaleOrderDelivery(ctx context.Context, shopID string, guid string) (models.SaleOrderDeliveryInfo, error)
+	SearchOpenSaleOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OpenOrderLine, mongopagination.PaginationData, error)
+	ReservedStock(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error)
+	StockBalance(ctx context.Context, shopID string, barcodes []string) ([]models.StockBalance, error)
+
+	GetModuleName() string
+}
+
+const (
+	MODULE_NAME = "SO"
+)
+
+type SaleOrderHttpService struct {
+	repo                repositories.ISaleOrderRepository
+	docNoSequencer      docsequence.IDocNoSequencer
+	delivery            ISaleOrderDelivery
+	quotationConversion salequotationservices.ISaleQuotationConversion
+	syncCacheRepo       mastersync.IMasterSyncCacheRepository
+	services.ActivityService[models.SaleOrderActivity, models.SaleOrderDeleteActivity]
+	timeNow        func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewSaleOrderHttpService(
+	repo repositories.ISaleOrderRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	delivery ISaleOrderDelivery,
+	quotationConversion salequotationservices.ISaleQuotationConversion,
+	syncCacheRepo mastersync.IMasterSyncCacheRepository,
+	timeNow func() time.Time,
+) *SaleOrderHttpService {
+
+	contextTimeout := time.Duration(15) * time.Second
+
+	insSvc := &SaleOrderHttpService{
+		repo:                repo,
+		docNoSequencer:      docNoSequencer,
+		delivery:            delivery,
+		quotationConversion: quotationConversion,
+		syncCacheRepo:       syncCacheRepo,
+		timeNow:             timeNow,
+		contextTimeout:      contextTimeout,
+	}
+
+	insSvc.ActivityService = services.NewActivityService[models.SaleOrderActivity, models.SaleOrderDeleteActivity](repo)
+
+	return insSvc
+}
+
+func (svc SaleOrderHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+func (svc SaleOrderHttpService) CreateSaleOrder(ctx context.Context, shopID string, authUsername string, doc models.SaleOrder) (string, string, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.createSaleOrder(ctx, shopID, authUsername, doc)
+}
+
+func (svc SaleOrderHttpService) createSaleOrder(ctx context.Context, shopID string, authUsername string, doc models.SaleOrder) (string, string, error) {
+
+	if !doc.ValidUntil.IsZero() && doc.ValidUntil.Before(doc.DocDatetime) {
+		return "", "", ErrOrderValidUntil
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	if doc.Details != nil {
+		linereference.NormalizeLineNumbers(*doc.Details)
+	}
+
+	docData := models.SaleOrderDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.SaleOrder = doc
+
+	docData.OrderStatus = models.OrderStatusDraft
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = svc.timeNow()
+
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		_, err := svc.repo.Create(ctx, docData)
+		return err
+	})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	go func() {
+		svc.saveMasterSync(shopID)
+	}()
+
+	return newGuidFixed, newDocNo, nil
+}
+
+func (svc SaleOrderHttpService) UpdateSaleOrder(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleOrder) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	// order which is delivered or changed by another request after it is read is checked again
+	return linereference.Retry(ErrOrderStatusChanged, func() error {
+		return svc.updateSaleOrder(ctx, shopID, guid, authUsername, doc)
+	})
+}
+
+func (svc SaleOrderHttpService) updateSaleOrder(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleOrder) error {
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return errors.New("document not found")
+	}
+
+	if findDoc.OrderStatus == models.OrderStatusClosed || findDoc.OrderStatus == models.OrderStatusCancelled {
+		return fmt.Errorf("sale order is %s and can not be edited", findDoc.OrderStatus)
+	}
+
+	if !doc.ValidUntil.IsZero() && doc.ValidUntil.Before(doc.DocDatetime) {
+		return ErrOrderValidUntil
+	}
+
+	details := []trans_models.Detail{}
+	if doc.Details != nil {
+		linereference.NormalizeLineNumbers(*doc.Details)
+		details = *doc.Details
+	}
+
+	// delivered lines must be kept
+	orderStatus, deliveryVersion, err := svc.delivery.CheckOrder(ctx, findDoc, details)
+
+	if err != nil {
+		return err
+	}
+
+	docData := findDoc
+	docData.SaleOrder = doc
+
+	docData.DocNo = findDoc.DocNo
+	docData.OrderStatus = orderStatus
+	docData.QuotationGuid = findDoc.QuotationGuid
+	docData.UpdatedBy = authUsername
+	docData.UpdatedAt = svc.timeNow()
+
+	// confirmed order reserve stock of its changed lines
+	err = svc.delivery.CheckStock(ctx, docData)
+
+	if err != nil {
+		return err
+	}
+
+	// the order and reservation of its changed lines are saved when neither its status nor its deliveries
+	// are changed since they are checked
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		isUpdated, err := svc.repo.UpdateWhenStatus(ctx, shopID, guid, findDoc.OrderStatus, docData)
+		if err != nil {
+			return err
+		}
+
+		if !isUpdated {
+			return ErrOrderStatusChanged
+		}
+
+		err = svc.delivery.ReserveUnchanged(ctx, docData, deliveryVersion)
+		if errors.Is(err, ErrDeliveryConflict) {
+			return ErrOrderStatusChanged
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	return nil
+}
+
+func (svc SaleOrderHttpService) DeleteSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return errors.New("document not found")
+	}
+
+	err = svc.delivery.CheckOrderDelete(ctx, shopID, guid)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	err = svc.delivery.RemoveOrder(ctx, shopID, guid)
+	if err != nil {
+		return fmt.Errorf("sale order is deleted but its stock reservation is not removed: %w", err)
+	}
+
+	return nil
+}
+
+func (svc SaleOrderHttpService) DeleteSaleOrderByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	for _, guid := range GUIDs {
+		if err := svc.delivery.CheckOrderDelete(ctx, shopID, guid); err != nil {
+			return err
+		}
+	}
+
+	deleteFilterQuery := map[string]interface{}{
+		"guidfixed": bson.M{"$in": GUIDs},
+	}
+
+	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	var removeErr error
+	notRemovedGuids := []string{}
+	for _, guid := range GUIDs {
+		err := svc.delivery.RemoveOrder(ctx, shopID, guid)
+		if err != nil {
+			removeErr = err
+			notRemovedGuids = append(notRemovedGuids, guid)
+		}
+	}
+
+	if removeErr != nil {
+		return fmt.Errorf("sale orders are deleted but stock reservation of %s is not removed: %w", strings.Join(notRemovedGuids, ", "), removeErr)
+	}
+
+	return nil
+}
+
+func (svc SaleOrderHttpService) InfoSaleOrder(ctx context.Context, shopID string, guid string) (models.SaleOrderInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.SaleOrderInfo{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleOrderInfo{}, errors.New("document not found")
+	}
+
+	return findDoc.SaleOrderInfo, nil
+}
+
+func (svc SaleOrderHttpService) InfoSaleOrderByCode(ctx context.Context, shopID string, code string) (models.SaleOrderInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
+
+	if err != nil {
+		return models.SaleOrderInfo{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleOrderInfo{}, errors.New("document not found")
+	}
+
+	return findDoc.SaleOrderInfo, nil
+}
+
+func (svc SaleOrderHttpService) SearchSaleOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleOrderInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.SaleOrderInfo{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+func (svc SaleOrderHttpService) SearchSaleOrderStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleOrderInfo, int, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	selectFields := map[string]interface{}{}
+
+	docList, total, err := svc.repo.FindStep(ctx, shopID, filters, searchInFields, selectFields, pageableStep)
+
+	if err != nil {
+		return []models.SaleOrderInfo{}, 0, err
+	}
+
+	return docList, total, nil
+}
+
+// ConfirmSaleOrder confirm draft order which is not expired, confirmed order reserve stock of its lines
+func (svc SaleOrderHttpService) ConfirmSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.findSaleOrder(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.OrderStatus != models.OrderStatusDraft {
+		return fmt.Errorf("sale order is %s and can not be confirmed", findDoc.OrderStatus)
+	}
+
+	if findDoc.Details == nil || len(*findDoc.Details) == 0 {
+		return ErrOrderNoLines
+	}
+
+	if !findDoc.ValidUntil.IsZero() && findDoc.ValidUntil.Before(svc.timeNow()) {
+		return ErrOrderExpired
+	}
+
+	confirmDoc := findDoc
+	confirmDoc.OrderStatus = models.OrderStatusConfirmed
+	err = svc.delivery.CheckStock(ctx, confirmDoc)
+
+	if err != nil {
+		return err
+	}
+
+	return svc.changeOrderStatus(ctx, findDoc, models.OrderStatusConfirmed)
+}
+
+// CancelSaleOrder cancel draft or confirmed order which is not delivered, partially delivered order is closed instead
+func (svc SaleOrderHttpService) CancelSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.findSaleOrder(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.OrderStatus != models.OrderStatusDraft && findDoc.OrderStatus != models.OrderStatusConfirmed {
+		return fmt.Errorf("sale order is %s and can not be cancelled", findDoc.OrderStatus)
+	}
+
+	err = svc.delivery.CheckOrderDelete(ctx, shopID, guid)
+	if err != nil {
+		return err
+	}
+
+	return svc.changeOrderStatus(ctx, findDoc, models.OrderStatusCancelled)
+}
+
+// CloseSaleOrder close confirmed or partially delivered order, its remaining quantity is not delivered and not reserved anymore
+func (svc SaleOrderHttpService) CloseSaleOrder(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.findSaleOrder(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if !models.IsReserving(findDoc.OrderStatus) {
+		return fmt.Errorf("sale order is %s and can not be closed", findDoc.OrderStatus)
+	}
+
+	return svc.changeOrderStatus(ctx, findDoc, models.OrderStatusClosed)
+}
+
+func (svc SaleOrderHttpService) findSaleOrder(ctx context.Context, shopID string, guid string) (models.SaleOrderDoc, error) {
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.SaleOrderDoc{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleOrderDoc{}, errors.New("document not found")
+	}
+
+	return findDoc, nil
+}
+
+// changeOrderStatus save status of the order when it is not changed since it is read and its stock reservation,
+// status is changed back when reservation can not be saved
+func (svc SaleOrderHttpService) changeOrderStatus(ctx context.Context, doc models.SaleOrderDoc, status string) error {
+	previousStatus := doc.OrderStatus
+
+	isUpdated, err := svc.repo.UpdateOrderStatus(ctx, doc.ShopID, doc.GuidFixed, previousStatus, status)
+	if err != nil {
+		return err
+	}
+
+	if !isUpdated {
+		return ErrOrderStatusChanged
+	}
+
+	doc.OrderStatus = status
+	err = svc.delivery.Reserve(ctx, doc)
+	if err != nil {
+		_, revertErr := svc.repo.UpdateOrderStatus(ctx, doc.ShopID, doc.GuidFixed, status, previousStatus)
+		if revertErr != nil {
+			return fmt.Errorf("%w, status is not changed back: %v", err, revertErr)
+		}
+		return err
+	}
+
+	svc.saveMasterSync(doc.ShopID)
+
+	return nil
+}
+
+// CreateSaleOrderFromQuotation create draft order with lines of the quotation which is sent or accepted,
+// lines of the order reference lines of the quotation and the quotation is converted
+func (svc SaleOrderHttpService) CreateSaleOrderFromQuotation(ctx context.Context, shopID string, authUsername string, quotationGuid string) (string, string, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	quotation, err := svc.quotationConversion.FindConvertible(ctx, shopID, quotationGuid)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	now := svc.timeNow()
+	doc := SaleOrderFromQuotation(quotation, now)
+
+	newGuidFixed, newDocNo, err := svc.createSaleOrder(ctx, shopID, authUsername, doc)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	err = svc.quotationConversion.MarkConverted(ctx, shopID, quotationGuid, salequotationmodels.ConvertedDoc{
+		DocType:     salequotationmodels.ConvertedDocTypeSaleOrder,
+		DocGuid:     newGuidFixed,
+		DocNo:       newDocNo,
+		ConvertedBy: authUsername,
+		ConvertedAt: now,
+	})
+
+	// the quotation is converted by another request since it is found
+	if err != nil {
+		svc.repo.DeleteByGuidfixed(ctx, shopID, newGuidFixed, authUsername)
+		return "", "", err
+	}
+
+	return newGuidFixed, newDocNo, nil
+}
+
+// SaleOrderFromQuotation return order of lines of the quotation at docDatetime, lines reference lines of the quotation
+func SaleOrderFromQuotation(quotation salequotationmodels.SaleQuotationDoc, docDatetime time.Time) models.SaleOrder {
+	doc := models.SaleOrder{}
+	doc.PartitionIdentity = quotation.PartitionIdentity
+	doc.TransactionHeader = quotation.TransactionHeader
+	doc.DocNo = ""
+	doc.DocDatetime = docDatetime
+	doc.DocRefNo = quotation.DocNo
+	doc.DocRefDate = quotation.DocDatetime
+	doc.TaxDocNo = ""
+	doc.TaxDocDate = time.Time{}
+	doc.QuotationGuid = quotation.GuidFixed
+
+	details := linereference.Lines(quotation.Details)
+	for i := range details {
+		details[i].DocDatetime = docDatetime
+		details[i].DocRef = quotation.DocNo
+		details[i].DocRefDatetime = quotation.DocDatetime
+		details[i].RefDocGuid = quotation.GuidFixed
+		details[i].RefLineNumber = details[i].LineNumber
+	}
+	doc.Details = &details
+
+	return doc
+}
+
+func (svc SaleOrderHttpService) InfoSaleOrderDelivery(ctx context.Context, shopID string, guid string) (models.SaleOrderDeliveryInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.findSaleOrder(ctx, shopID, guid)
+
+	if err != nil {
+		return models.SaleOrderDeliveryInfo{}, err
+	}
+
+	return svc.delivery.Info(ctx, findDoc)
+}
+
+// SearchOpenSaleOrder return lines which are not delivered of page of confirmed and partially delivered orders
+func (svc SaleOrderHttpService) SearchOpenSaleOrder(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.OpenOrderLine, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	filters["orderstatus"] = bson.M{"$in": []string{models.OrderStatusConfirmed, models.OrderStatusPartial}}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.OpenOrderLine{}, pagination, err
+	}
+
+	lines, err := svc.delivery.OpenLines(ctx, shopID, docList)
+
+	if err != nil {
+		return []models.OpenOrderLine{}, pagination, err
+	}
+
+	return lines, pagination, nil
+}
+
+func (svc SaleOrderHttpService) ReservedStock(ctx context.Context, shopID string, barcodes []string) ([]models.ReservedStock, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.delivery.ReservedStock(ctx, shopID, barcodes)
+}
+
+func (svc SaleOrderHttpService) StockBalance(ctx context.Context, shopID string, barcodes []string) ([]models.StockBalance, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	return svc.delivery.StockBalance(ctx, shopID, barcodes)
+}
+
+func (svc SaleOrderHttpService) saveMasterSync(shopID string) {
+	if svc.syncCacheRepo != nil {
+		err := svc.syncCacheRepo.Save(shopID, svc.GetModuleName())
+
+		if err != nil {
+			fmt.Printf("save %s cache error :: %s", svc.GetModuleName(), err.Error())
+		}
+	}
+}
+
+func (svc SaleOrderHttpService) GetModuleName() string {
+	return "saleorder"
+}
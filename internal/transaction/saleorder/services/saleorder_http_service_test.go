@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/linereference"
+	trans_models "smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/saleorder/models"
+	"smlaicloudplatform/internal/transaction/saleorder/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSaleOrder_DeliveredAfterCheck(t *testing.T) {
+	orderRepo := new(SaleOrderRepositoryMock)
+	deliveryRepo := new(SaleOrderDeliveryRepositoryMock)
+
+	order := newOrder("so1", models.OrderStatusPartial,
+		trans_models.Detail{LineNumber: 1, Barcode: "B1", Qty: 10},
+	)
+	orderRepo.On("FindByGuid", "shop1", "so1").Return(order, nil)
+	orderRepo.On("UpdateWhenStatus", "shop1", "so1", models.OrderStatusPartial, mock.Anything).Return(true, nil)
+
+	checked := models.SaleOrderDelivery{
+		Version:      1,
+		Deliveries:   []models.SaleOrderDeliveryLine{{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 1, Qty: 4}},
+		Reservations: []models.StockReservation{{LineNumber: 1, Barcode: "B1", Qty: 6}},
+	}
+	delivered := models.SaleOrderDelivery{
+		Version: 2,
+		Deliveries: []models.SaleOrderDeliveryLine{
+			{InvoiceGuid: "si1", LineNumber: 1, OrderLineNumber: 1, Qty: 4},
+			{InvoiceGuid: "si2", LineNumber: 1, OrderLineNumber: 1, Qty: 2},
+		},
+		Reservations: []models.StockReservation{{LineNumber: 1, Barcode: "B1", Qty: 4}},
+	}
+
+	// the order is delivered by another sale invoice after its lines are checked
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(checked, nil).Twice()
+	deliveryRepo.On("FindByOrder", "shop1", "so1").Return(delivered, nil)
+
+	delivery := services.NewSaleOrderDelivery(orderRepo, deliveryRepo, nil, time.Now)
+	svc := services.NewSaleOrderHttpService(orderRepo, nil, delivery, nil, nil, time.Now)
+
+	doc := order.SaleOrder
+	doc.Details = &[]trans_models.Detail{{LineNumber: 1, Barcode: "B1", Qty: 5}}
+
+	// the change is checked again with the delivery and the line can not be ordered less than delivered
+	err := svc.UpdateSaleOrder(context.Background(), "shop1", "so1", "user1", doc)
+	lineErr := linereference.Error{}
+	require.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, 1, lineErr.Lines[0].LineNumber)
+
+	orderRepo.AssertNumberOfCalls(t, "UpdateWhenStatus", 1)
+	deliveryRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
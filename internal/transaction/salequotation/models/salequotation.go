@@ -0,0 +1,138 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+	transmodels "smlaicloudplatform/internal/transaction/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const salequotationCollectionName = "transactionSaleQuotation"
+
+// status of sale quotation, expired is not saved, it is status of open quotation which valid until date is passed
+const (
+	QuotationStatusDraft     = "draft"
+	QuotationStatusSent      = "sent"
+	QuotationStatusAccepted  = "accepted"
+	QuotationStatusRejected  = "rejected"
+	QuotationStatusConverted = "converted"
+	QuotationStatusExpired   = "expired"
+)
+
+// type of document which sale quotation is converted to
+const (
+	ConvertedDocTypeSaleOrder = "saleorder"
+)
+
+type SaleQuotation struct {
+	models.PartitionIdentity `bson:"inline"`
+	transmodels.Transaction  `bson:"inline"`
+	ValidUntil               time.Time      `json:"validuntil" bson:"validuntil"`
+	QuotationStatus          string         `json:"quotationstatus" bson:"quotationstatus"`
+	ConvertedDocs            []ConvertedDoc `json:"converteddocs" bson:"converteddocs"`
+}
+
+// ConvertedDoc is document which is created from sale quotation
+type ConvertedDoc struct {
+	DocType     string    `json:"doctype" bson:"doctype"`
+	DocGuid     string    `json:"docguid" bson:"docguid"`
+	DocNo       string    `json:"docno" bson:"docno"`
+	ConvertedBy string    `json:"convertedby" bson:"convertedby"`
+	ConvertedAt time.Time `json:"convertedat" bson:"convertedat"`
+}
+
+// IsOpen return true when the quotation can still be accepted by the customer
+func IsOpen(status string) bool {
+	return status == QuotationStatusDraft || status == QuotationStatusSent || status == QuotationStatusAccepted
+}
+
+// EffectiveStatus return expired when the quotation is open and its valid until date is passed at now
+func (doc SaleQuotation) EffectiveStatus(now time.Time) string {
+	status := doc.QuotationStatus
+	if IsOpen(status) && !doc.ValidUntil.IsZero() && doc.ValidUntil.Before(now) {
+		return QuotationStatusExpired
+	}
+
+	return status
+}
+
+type SaleQuotationInfo struct {
+	models.DocIdentity `bson:"inline"`
+	SaleQuotation      `bson:"inline"`
+}
+
+func (SaleQuotationInfo) CollectionName() string {
+	return salequotationCollectionName
+}
+
+type SaleQuotationData struct {
+	models.ShopIdentity `bson:"inline"`
+	SaleQuotationInfo   `bson:"inline"`
+}
+
+type SaleQuotationDoc struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SaleQuotationData  `bson:"inline"`
+	models.ActivityDoc `bson:"inline"`
+}
+
+func (SaleQuotationDoc) CollectionName() string {
+	return salequotationCollectionName
+}
+
+type SaleQuotationItemGuid struct {
+	DocNo string `json:"docno" bson:"docno"`
+}
+
+func (SaleQuotationItemGuid) CollectionName() string {
+	return salequotationCollectionName
+}
+
+type SaleQuotationActivity struct {
+	SaleQuotationData   `bson:"inline"`
+	models.ActivityTime `bson:"inline"`
+}
+
+func (SaleQuotationActivity) CollectionName() string {
+	return salequotationCollectionName
+}
+
+type SaleQuotationDeleteActivity struct {
+	models.Identity     `bson:"inline"`
+	models.ActivityTime `bson:"inline"`
+}
+
+func (SaleQuotationDeleteActivity) CollectionName() string {
+	return salequotationCollectionName
+}
+
+// SaleQuotationStatusRequest change status of sale quotation to sent, accepted, rejected or back to draft
+type SaleQuotationStatusRequest struct {
+	QuotationStatus string `json:"quotationstatus" validate:"required,oneof=draft sent accepted rejected"`
+}
+
+// SaleQuotationSummary is status and amount of sale quotation which is read for conversion rate report
+type SaleQuotationSummary struct {
+	DocNo           string    `json:"docno" bson:"docno"`
+	DocDatetime     time.Time `json:"docdatetime" bson:"docdatetime"`
+	ValidUntil      time.Time `json:"validuntil" bson:"validuntil"`
+	QuotationStatus string    `json:"quotationstatus" bson:"quotationstatus"`
+	TotalAmount     float64   `json:"totalamount" bson:"totalamount"`
+}
+
+// ConversionRateReport count sale quotations of the period by status at the time of the report, conversion rate is percent of
+// converted quotations in all quotations of the period and amount conversion rate is percent of their amount
+type ConversionRateReport struct {
+	Total                int     `json:"total"`
+	Draft                int     `json:"draft"`
+	Sent                 int     `json:"sent"`
+	Accepted             int     `json:"accepted"`
+	Rejected             int     `json:"rejected"`
+	Expired              int     `json:"expired"`
+	Converted            int     `json:"converted"`
+	TotalAmount          float64 `json:"totalamount"`
+	ConvertedAmount      float64 `json:"convertedamount"`
+	ConversionRate       float64 `json:"conversionrate"`
+	AmountConversionRate float64 `json:"amountconversionrate"`
+}
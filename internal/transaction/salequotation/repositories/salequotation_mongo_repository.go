@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/internal/transaction/salequotation/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ISaleQuotationRepository interface {
+	Count(ctx context.Context, shopID string) (int, error)
+	Create(ctx context.Context, doc models.SaleQuotationDoc) (string, error)
+	Update(ctx context.Context, shopID string, guid string, doc models.SaleQuotationDoc) error
+	DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error
+	Delete(ctx context.Context, shopID string, username string, filters map[string]interface{}) error
+	FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.SaleQuotationInfo, mongopagination.PaginationData, error)
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.SaleQuotationDoc, error)
+	FindByGuids(ctx context.Context, shopID string, guids []string) ([]models.SaleQuotationDoc, error)
+
+	FindByDocIndentityGuid(ctx context.Context, shopID string, indentityField string, indentityValue interface{}) (models.SaleQuotationDoc, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.SaleQuotationInfo, mongopagination.PaginationData, error)
+	FindStep(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, projects map[string]interface{}, pageableLimit micromodels.PageableStep) ([]models.SaleQuotationInfo, int, error)
+
+	FindDeletedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleQuotationDeleteActivity, mongopagination.PaginationData, error)
+	FindCreatedOrUpdatedPage(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleQuotationActivity, mongopagination.PaginationData, error)
+	FindDeletedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleQuotationDeleteActivity, error)
+	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleQuotationActivity, error)
+
+	// UpdateQuotationStatus change status of the quotation when it is still fromStatus, false is returned when it is changed by another request
+	UpdateQuotationStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string, authUsername string, updatedAt time.Time) (bool, error)
+	// MarkConverted change status of the quotation which is sent or accepted and valid at convertedAt to converted,
+	// false is returned when the quotation is not convertible anymore so it is converted only once
+	MarkConverted(ctx context.Context, shopID string, guid string, convertedDoc models.ConvertedDoc) (bool, error)
+	FindSummary(ctx context.Context, shopID string, filters map[string]interface{}) ([]models.SaleQuotationSummary, error)
+}
+
+type SaleQuotationRepository struct {
+	pst microservice.IPersisterMongo
+	repositories.CrudRepository[models.SaleQuotationDoc]
+	repositories.SearchRepository[models.SaleQuotationInfo]
+	repositories.GuidRepository[models.SaleQuotationItemGuid]
+	repositories.ActivityRepository[models.SaleQuotationActivity, models.SaleQuotationDeleteActivity]
+}
+
+func NewSaleQuotationRepository(pst microservice.IPersisterMongo) *SaleQuotationRepository {
+
+	insRepo := &SaleQuotationRepository{
+		pst: pst,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.SaleQuotationDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.SaleQuotationInfo](pst)
+	insRepo.GuidRepository = repositories.NewGuidRepository[models.SaleQuotationItemGuid](pst)
+	insRepo.ActivityRepository = repositories.NewActivityRepository[models.SaleQuotationActivity, models.SaleQuotationDeleteActivity](pst)
+
+	return insRepo
+}
+
+func (repo SaleQuotationRepository) UpdateQuotationStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string, authUsername string, updatedAt time.Time) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.SaleQuotationDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":          shopID,
+		"guidfixed":       guid,
+		"deletedat":       bson.M{"$exists": false},
+		"quotationstatus": fromStatus,
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"quotationstatus": toStatus,
+			"updatedby":       authUsername,
+			"updatedat":       updatedAt,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo SaleQuotationRepository) MarkConverted(ctx context.Context, shopID string, guid string, convertedDoc models.ConvertedDoc) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.SaleQuotationDoc{})
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"shopid":          shopID,
+		"guidfixed":       guid,
+		"deletedat":       bson.M{"$exists": false},
+		"quotationstatus": bson.M{"$in": []string{models.QuotationStatusSent, models.QuotationStatusAccepted}},
+		"validuntil":      bson.M{"$gte": convertedDoc.ConvertedAt},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"quotationstatus": models.QuotationStatusConverted,
+			"updatedby":       convertedDoc.ConvertedBy,
+			"updatedat":       convertedDoc.ConvertedAt,
+		},
+		"$push": bson.M{
+			"converteddocs": convertedDoc,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo SaleQuotationRepository) FindSummary(ctx context.Context, shopID string, filters map[string]interface{}) ([]models.SaleQuotationSummary, error) {
+	queryFilters := bson.M{
+		"shopid":    shopID,
+		"deletedat": bson.M{"$exists": false},
+	}
+
+	for key, value := range filters {
+		queryFilters[key] = value
+	}
+
+	opts := options.Find().SetProjection(bson.M{
+		"docno":           1,
+		"docdatetime":     1,
+		"validuntil":      1,
+		"quotationstatus": 1,
+		"totalamount":     1,
+	})
+
+	docList := []models.SaleQuotationSummary{}
+	err := repo.pst.Find(ctx, &models.SaleQuotationDoc{}, queryFilters, &docList, opts)
+	if err != nil {
+		return []models.SaleQuotationSummary{}, err
+	}
+
+	return docList, nil
+}
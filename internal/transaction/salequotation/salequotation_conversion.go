@@ -0,0 +1,29 @@
+package salequotation
+
+import (
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/transaction/salequotation/repositories"
+	"smlaicloudplatform/internal/transaction/salequotation/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// InitSaleQuotationConversion return conversion of sale quotations to the documents which are created from them
+func InitSaleQuotationConversion(ms *microservice.Microservice, cfg config.IConfig) services.ISaleQuotationConversion {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	return services.NewSaleQuotationConversion(repositories.NewSaleQuotationRepository(pst), ms.TimeNow)
+}
+
+// ResponseError write response of error of status of sale quotation, changed and converted quotation is conflict
+func ResponseError(ctx microservice.IContext, err error) {
+	switch {
+	case errors.Is(err, services.ErrQuotationNotFound):
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrQuotationStatusChanged), errors.Is(err, services.ErrQuotationConverted):
+		ctx.ResponseError(http.StatusConflict, err.Error())
+	default:
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,462 @@
+package salequotation
+
+import (
+	"encoding/json"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	mastersync "smlaicloudplatform/internal/mastersync/repositories"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/docsequence"
+	"smlaicloudplatform/internal/transaction/salequotation/models"
+	"smlaicloudplatform/internal/transaction/salequotation/repositories"
+	"smlaicloudplatform/internal/transaction/salequotation/services"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type ISaleQuotationHttp interface{}
+
+type SaleQuotationHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.ISaleQuotationHttpService
+}
+
+func NewSaleQuotationHttp(ms *microservice.Microservice, cfg config.IConfig) SaleQuotationHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+	cache := ms.Cacher(cfg.CacherConfig())
+
+	repo := repositories.NewSaleQuotationRepository(pst)
+
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
+	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
+	svc := services.NewSaleQuotationHttpService(repo, docNoSequencer, masterSyncCacheRepo, ms.TimeNow)
+
+	return SaleQuotationHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h SaleQuotationHttp) RegisterHttp() {
+
+	h.ms.GET("/transaction/sale-quotation", h.SearchSaleQuotationPage)
+	h.ms.GET("/transaction/sale-quotation/list", h.SearchSaleQuotationStep)
+	h.ms.GET("/transaction/sale-quotation/conversion-rate", h.ConversionRate)
+	h.ms.POST("/transaction/sale-quotation", h.CreateSaleQuotation)
+	h.ms.GET("/transaction/sale-quotation/:id", h.InfoSaleQuotation)
+	h.ms.GET("/transaction/sale-quotation/code/:code", h.InfoSaleQuotationByCode)
+	h.ms.PUT("/transaction/sale-quotation/:id", h.UpdateSaleQuotation)
+	h.ms.DELETE("/transaction/sale-quotation/:id", h.DeleteSaleQuotation)
+	h.ms.DELETE("/transaction/sale-quotation", h.DeleteSaleQuotationByGUIDs)
+
+	h.ms.POST("/transaction/sale-quotation/:id/status", h.UpdateSaleQuotationStatus)
+}
+
+// Create SaleQuotation godoc
+// @Description Create SaleQuotation, it is draft and valid for 30 days from its document date when valid until date is not set
+// @Tags		SaleQuotation
+// @Param		SaleQuotation  body      models.SaleQuotation  true  "SaleQuotation"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation [post]
+func (h SaleQuotationHttp) CreateSaleQuotation(ctx microservice.IContext) error {
+	authUsername := ctx.UserInfo().Username
+	shopID := ctx.UserInfo().ShopID
+	input := ctx.ReadInput()
+
+	docReq := &models.SaleQuotation{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	idx, docNo, err := h.svc.CreateSaleQuotation(ctx.Context(), shopID, authUsername, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      idx,
+		Data:    docNo,
+	})
+	return nil
+}
+
+// Update SaleQuotation godoc
+// @Description Update SaleQuotation which is draft or sent
+// @Tags		SaleQuotation
+// @Param		id  path      string  true  "SaleQuotation ID"
+// @Param		SaleQuotation  body      models.SaleQuotation  true  "SaleQuotation"
+// @Accept 		json
+// @Success		201	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/{id} [put]
+func (h SaleQuotationHttp) UpdateSaleQuotation(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	authUsername := userInfo.Username
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+	input := ctx.ReadInput()
+
+	docReq := &models.SaleQuotation{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateSaleQuotation(ctx.Context(), shopID, id, authUsername, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Delete SaleQuotation godoc
+// @Description Delete SaleQuotation which is not converted
+// @Tags		SaleQuotation
+// @Param		id  path      string  true  "SaleQuotation ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/{id} [delete]
+func (h SaleQuotationHttp) DeleteSaleQuotation(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+	authUsername := userInfo.Username
+
+	id := ctx.Param("id")
+
+	err := h.svc.DeleteSaleQuotation(ctx.Context(), shopID, id, authUsername)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+
+	return nil
+}
+
+// Delete SaleQuotation godoc
+// @Description Delete SaleQuotation
+// @Tags		SaleQuotation
+// @Param		SaleQuotation  body      []string  true  "SaleQuotation GUIDs"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation [delete]
+func (h SaleQuotationHttp) DeleteSaleQuotationByGUIDs(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+	authUsername := userInfo.Username
+
+	input := ctx.ReadInput()
+
+	docReq := []string{}
+	err := json.Unmarshal([]byte(input), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(400, err.Error())
+		return err
+	}
+
+	err = h.svc.DeleteSaleQuotationByGUIDs(ctx.Context(), shopID, authUsername, docReq)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+	})
+
+	return nil
+}
+
+// Get SaleQuotation godoc
+// @Description get SaleQuotation info by guidfixed
+// @Tags		SaleQuotation
+// @Param		id  path      string  true  "SaleQuotation guidfixed"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/{id} [get]
+func (h SaleQuotationHttp) InfoSaleQuotation(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	id := ctx.Param("id")
+
+	h.ms.Logger.Debugf("Get SaleQuotation %v", id)
+	doc, err := h.svc.InfoSaleQuotation(ctx.Context(), shopID, id)
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Get SaleQuotation By Code godoc
+// @Description get SaleQuotation info by Code
+// @Tags		SaleQuotation
+// @Param		code  path      string  true  "SaleQuotation Code"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/code/{code} [get]
+func (h SaleQuotationHttp) InfoSaleQuotationByCode(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	code := ctx.Param("code")
+
+	doc, err := h.svc.InfoSaleQuotationByCode(ctx.Context(), shopID, code)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List SaleQuotation step godoc
+// @Description get list step
+// @Tags		SaleQuotation
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		quotationstatus	query	string		false  "draft, sent, accepted, rejected or converted"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation [get]
+func (h SaleQuotationHttp) SearchSaleQuotationPage(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, quotationFilterRequests)
+
+	docList, pagination, err := h.svc.SearchSaleQuotation(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// List SaleQuotation godoc
+// @Description search limit offset
+// @Tags		SaleQuotation
+// @Param		q		query	string		false  "Search Value"
+// @Param		custcode	query	string		false  "cust code"
+// @Param		quotationstatus	query	string		false  "draft, sent, accepted, rejected or converted"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Param		offset	query	integer		false  "offset"
+// @Param		limit	query	integer		false  "limit"
+// @Param		lang	query	string		false  "lang"
+// @Accept 		json
+// @Success		200	{array}		common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/list [get]
+func (h SaleQuotationHttp) SearchSaleQuotationStep(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	shopID := userInfo.ShopID
+
+	pageableStep := utils.GetPageableStep(ctx.QueryParam)
+
+	lang := ctx.QueryParam("lang")
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, quotationFilterRequests)
+
+	docList, total, err := h.svc.SearchSaleQuotationStep(ctx.Context(), shopID, lang, filters, pageableStep)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    docList,
+		Total:   total,
+	})
+	return nil
+}
+
+var quotationFilterRequests = []requestfilter.FilterRequest{
+	{
+		Param: "custcode",
+		Type:  requestfilter.FieldTypeString,
+	},
+	{
+		Param: "quotationstatus",
+		Type:  requestfilter.FieldTypeString,
+	},
+	{
+		Param: "-",
+		Field: "docdatetime",
+		Type:  requestfilter.FieldTypeRangeDate,
+	},
+	{
+		Param: "branchcode",
+		Field: "branch.code",
+		Type:  requestfilter.FieldTypeString,
+	},
+}
+
+// Update SaleQuotation Status godoc
+// @Description change status of SaleQuotation, draft can be sent, accepted or rejected, sent can be accepted, rejected or back to draft, accepted can be rejected or back to draft and rejected can be back to draft, expired quotation can not be sent or accepted
+// @Tags		SaleQuotation
+// @Param		id  path      string  true  "SaleQuotation ID"
+// @Param		SaleQuotationStatusRequest  body      models.SaleQuotationStatusRequest  true  "SaleQuotation Status"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/{id}/status [post]
+func (h SaleQuotationHttp) UpdateSaleQuotationStatus(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.SaleQuotationStatusRequest{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateSaleQuotationStatus(ctx.Context(), userInfo.ShopID, id, userInfo.Username, docReq.QuotationStatus)
+
+	if err != nil {
+		ResponseError(ctx, err)
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Get SaleQuotation Conversion Rate godoc
+// @Description count SaleQuotation of the period by status and percent of quotations and amount which are converted
+// @Tags		SaleQuotation
+// @Param		custcode	query	string		false  "cust code"
+// @Param		branchcode	query	string		false  "branch code"
+// @Param		fromdate	query	string		false  "from date"
+// @Param		todate	query	string		false  "to date"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /transaction/sale-quotation/conversion-rate [get]
+func (h SaleQuotationHttp) ConversionRate(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "custcode",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "docdatetime",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+		{
+			Param: "branchcode",
+			Field: "branch.code",
+			Type:  requestfilter.FieldTypeString,
+		},
+	})
+
+	doc, err := h.svc.ConversionRate(ctx.Context(), shopID, filters)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/transaction/salequotation/models"
+	"smlaicloudplatform/internal/transaction/salequotation/repositories"
+	"time"
+)
+
+var (
+	ErrQuotationNotFound       = errors.New("sale quotation not found")
+	ErrQuotationNotConvertible = errors.New("sale quotation must be sent or accepted to be converted")
+)
+
+type ISaleQuotationConversion interface {
+	// FindConvertible return the quotation which is sent or accepted and not expired
+	FindConvertible(ctx context.Context, shopID string, guid string) (models.SaleQuotationDoc, error)
+	// MarkConverted change the quotation to converted by the document, ErrQuotationStatusChanged is returned
+	// when the quotation is converted or changed since it is found
+	MarkConverted(ctx context.Context, shopID string, guid string, convertedDoc models.ConvertedDoc) error
+}
+
+type SaleQuotationConversion struct {
+	repo    repositories.ISaleQuotationRepository
+	timeNow func() time.Time
+}
+
+func NewSaleQuotationConversion(repo repositories.ISaleQuotationRepository, timeNow func() time.Time) *SaleQuotationConversion {
+	return &SaleQuotationConversion{
+		repo:    repo,
+		timeNow: timeNow,
+	}
+}
+
+func (svc SaleQuotationConversion) FindConvertible(ctx context.Context, shopID string, guid string) (models.SaleQuotationDoc, error) {
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return models.SaleQuotationDoc{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleQuotationDoc{}, ErrQuotationNotFound
+	}
+
+	switch findDoc.EffectiveStatus(svc.timeNow()) {
+	case models.QuotationStatusSent, models.QuotationStatusAccepted:
+		return findDoc, nil
+	case models.QuotationStatusConverted:
+		return models.SaleQuotationDoc{}, ErrQuotationConverted
+	case models.QuotationStatusExpired:
+		return models.SaleQuotationDoc{}, ErrQuotationExpired
+	default:
+		return models.SaleQuotationDoc{}, fmt.Errorf("%w, it is %s", ErrQuotationNotConvertible, findDoc.QuotationStatus)
+	}
+}
+
+func (svc SaleQuotationConversion) MarkConverted(ctx context.Context, shopID string, guid string, convertedDoc models.ConvertedDoc) error {
+	if convertedDoc.ConvertedAt.IsZero() {
+		convertedDoc.ConvertedAt = svc.timeNow()
+	}
+
+	isConverted, err := svc.repo.MarkConverted(ctx, shopID, guid, convertedDoc)
+	if err != nil {
+		return err
+	}
+
+	if !isConverted {
+		return ErrQuotationStatusChanged
+	}
+
+	return nil
+}
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mastersync "smlaicloudplatform/internal/mastersync/repositories"
+	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
+	"smlaicloudplatform/internal/transaction/salequotation/models"
+	"smlaicloudplatform/internal/transaction/salequotation/repositories"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	ErrQuotationNotEditable   = errors.New("sale quotation can be edited only when it is draft or sent")
+	ErrQuotationConverted     = errors.New("sale quotation is converted and can not be changed")
+	ErrQuotationExpired       = errors.New("sale quotation is expired, extend its valid until date first")
+	ErrQuotationValidUntil    = errors.New("valid until date must not be before document date")
+	ErrQuotationStatusChanged = errors.New("sale quotation is changed by another request, please try again")
+)
+
+// defaultValidDays is number of days which quotation is valid when valid until date is not set
+const defaultValidDays = 30
+
+// quotationStatusTransitions is statuses which quotation can be changed to from its status,
+// converted is set only by conversion and expired is not saved
+var quotationStatusTransitions = map[string][]string{
+	models.QuotationStatusDraft:    {models.QuotationStatusSent, models.QuotationStatusAccepted, models.QuotationStatusRejected},
+	models.QuotationStatusSent:     {models.QuotationStatusDraft, models.QuotationStatusAccepted, models.QuotationStatusRejected},
+	models.QuotationStatusAccepted: {models.QuotationStatusDraft, models.QuotationStatusRejected},
+	models.QuotationStatusRejected: {models.QuotationStatusDraft},
+}
+
+type ISaleQuotationHttpService interface {
+	CreateSaleQuotation(ctx context.Context, shopID string, authUsername string, doc models.SaleQuotation) (string, string, error)
+	UpdateSaleQuotation(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleQuotation) error
+	DeleteSaleQuotation(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteSaleQuotationByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoSaleQuotation(ctx context.Context, shopID string, guid string) (models.SaleQuotationInfo, error)
+	InfoSaleQuotationByCode(ctx context.Context, shopID string, code string) (models.SaleQuotationInfo, error)
+	SearchSaleQuotation(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleQuotationInfo, mongopagination.PaginationData, error)
+	SearchSaleQuotationStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleQuotationInfo, int, error)
+	UpdateSaleQuotationStatus(ctx context.Context, shopID string, guid string, authUsername string, status string) error
+	ConversionRate(ctx context.Context, shopID string, filters map[string]interface{}) (models.ConversionRateReport, error)
+
+	GetModuleName() string
+}
+
+const (
+	MODULE_NAME = "QT"
+)
+
+type SaleQuotationHttpService struct {
+	repo           repositories.ISaleQuotationRepository
+	docNoSequencer docsequence.IDocNoSequencer
+	syncCacheRepo  mastersync.IMasterSyncCacheRepository
+	services.ActivityService[models.SaleQuotationActivity, models.SaleQuotationDeleteActivity]
+	timeNow        func() time.Time
+	contextTimeout time.Duration
+}
+
+func NewSaleQuotationHttpService(
+	repo repositories.ISaleQuotationRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	syncCacheRepo mastersync.IMasterSyncCacheRepository,
+	timeNow func() time.Time,
+) *SaleQuotationHttpService {
+
+	contextTimeout := time.Duration(15) * time.Second
+
+	insSvc := &SaleQuotationHttpService{
+		repo:           repo,
+		docNoSequencer: docNoSequencer,
+		syncCacheRepo:  syncCacheRepo,
+		timeNow:        timeNow,
+		contextTimeout: contextTimeout,
+	}
+
+	insSvc.ActivityService = services.NewActivityService[models.SaleQuotationActivity, models.SaleQuotationDeleteActivity](repo)
+
+	return insSvc
+}
+
+func (svc SaleQuotationHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// validUntil return valid until date of the quotation, it is valid for defaultValidDays from its document date when it is not set
+func validUntil(doc models.SaleQuotation) (time.Time, error) {
+	if doc.ValidUntil.IsZero() {
+		return doc.DocDatetime.AddDate(0, 0, defaultValidDays), nil
+	}
+
+	if doc.ValidUntil.Before(doc.DocDatetime) {
+		return time.Time{}, ErrQuotationValidUntil
+	}
+
+	return doc.ValidUntil, nil
+}
+
+func (svc SaleQuotationHttpService) CreateSaleQuotation(ctx context.Context, shopID string, authUsername string, doc models.SaleQuotation) (string, string, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	validUntilDate, err := validUntil(doc)
+	if err != nil {
+		return "", "", err
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	docData := models.SaleQuotationDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.SaleQuotation = doc
+
+	docData.ValidUntil = validUntilDate
+	docData.QuotationStatus = models.QuotationStatusDraft
+	docData.ConvertedDocs = []models.ConvertedDoc{}
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = svc.timeNow()
+
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		_, err := svc.repo.Create(ctx, docData)
+		return err
+	})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	go func() {
+		svc.saveMasterSync(shopID)
+	}()
+
+	return newGuidFixed, newDocNo, nil
+}
+
+func (svc SaleQuotationHttpService) UpdateSaleQuotation(ctx context.Context, shopID string, guid string, authUsername string, doc models.SaleQuotation) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return errors.New("document not found")
+	}
+
+	if findDoc.QuotationStatus != models.QuotationStatusDraft && findDoc.QuotationStatus != models.QuotationStatusSent {
+		return ErrQuotationNotEditable
+	}
+
+	validUntilDate, err := validUntil(doc)
+	if err != nil {
+		return err
+	}
+
+	docData := findDoc
+	docData.SaleQuotation = doc
+
+	docData.DocNo = findDoc.DocNo
+	docData.ValidUntil = validUntilDate
+	docData.QuotationStatus = findDoc.QuotationStatus
+	docData.ConvertedDocs = findDoc.ConvertedDocs
+	docData.UpdatedBy = authUsername
+	docData.UpdatedAt = svc.timeNow()
+
+	err = svc.repo.Update(ctx, shopID, guid, docData)
+
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	return nil
+}
+
+func (svc SaleQuotationHttpService) DeleteSaleQuotation(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return errors.New("document not found")
+	}
+
+	if findDoc.QuotationStatus == models.QuotationStatusConverted {
+		return ErrQuotationConverted
+	}
+
+	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	return nil
+}
+
+func (svc SaleQuotationHttpService) DeleteSaleQuotationByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if doc.QuotationStatus == models.QuotationStatusConverted {
+			return fmt.Errorf("%s: %w", doc.DocNo, ErrQuotationConverted)
+		}
+	}
+
+	deleteFilterQuery := map[string]interface{}{
+		"guidfixed": bson.M{"$in": GUIDs},
+	}
+
+	err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(shopID)
+
+	return nil
+}
+
+func (svc SaleQuotationHttpService) InfoSaleQuotation(ctx context.Context, shopID string, guid string) (models.SaleQuotationInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.SaleQuotationInfo{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleQuotationInfo{}, errors.New("document not found")
+	}
+
+	findDoc.QuotationStatus = findDoc.EffectiveStatus(svc.timeNow())
+
+	return findDoc.SaleQuotationInfo, nil
+}
+
+func (svc SaleQuotationHttpService) InfoSaleQuotationByCode(ctx context.Context, shopID string, code string) (models.SaleQuotationInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
+
+	if err != nil {
+		return models.SaleQuotationInfo{}, err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return models.SaleQuotationInfo{}, errors.New("document not found")
+	}
+
+	findDoc.QuotationStatus = findDoc.EffectiveStatus(svc.timeNow())
+
+	return findDoc.SaleQuotationInfo, nil
+}
+
+func (svc SaleQuotationHttpService) SearchSaleQuotation(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.SaleQuotationInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	docList, pagination, err := svc.repo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.SaleQuotationInfo{}, pagination, err
+	}
+
+	now := svc.timeNow()
+	for i := range docList {
+		docList[i].QuotationStatus = docList[i].EffectiveStatus(now)
+	}
+
+	return docList, pagination, nil
+}
+
+func (svc SaleQuotationHttpService) SearchSaleQuotationStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.SaleQuotationInfo, int, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"docno",
+	}
+
+	selectFields := map[string]interface{}{}
+
+	docList, total, err := svc.repo.FindStep(ctx, shopID, filters, searchInFields, selectFields, pageableStep)
+
+	if err != nil {
+		return []models.SaleQuotationInfo{}, 0, err
+	}
+
+	now := svc.timeNow()
+	for i := range docList {
+		docList[i].QuotationStatus = docList[i].EffectiveStatus(now)
+	}
+
+	return docList, total, nil
+}
+
+// UpdateSaleQuotationStatus change status of the quotation by quotationStatusTransitions,
+// expired quotation can only be rejected or changed back to draft to extend its valid until date
+func (svc SaleQuotationHttpService) UpdateSaleQuotationStatus(ctx context.Context, shopID string, guid string, authUsername string, status string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if len(findDoc.GuidFixed) < 1 {
+		return errors.New("document not found")
+	}
+
+	if findDoc.QuotationStatus == models.QuotationStatusConverted {
+		return ErrQuotationConverted
+	}
+
+	if !canChangeStatus(findDoc.QuotationStatus, status) {
+		return fmt.Errorf("sale quotation is %s and can not be changed to %s", findDoc.QuotationStatus, status)
+	}
+
+	now := svc.timeNow()
+	if (status == models.QuotationStatusSent || status == models.QuotationStatusAccepted) && findDoc.EffectiveStatus(now) == models.QuotationStatusExpired {
+		return ErrQuotationExpired
+	}
+
+	isUpdated, err := svc.repo.UpdateQuotationStatus(ctx, shopID, guid, findDoc.QuotationStatus, status, authUsername, now)
+	if err != nil {
+		return err
+	}
+
+	if !isUpdated {
+		return ErrQuotationStatusChanged
+	}
+
+	svc.saveMasterSync(shopID)
+
+	return nil
+}
+
+func canChangeStatus(fromStatus string, toStatus string) bool {
+	for _, status := range quotationStatusTransitions[fromStatus] {
+		if status == toStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func (svc SaleQuotationHttpService) ConversionRate(ctx context.Context, shopID string, filters map[string]interface{}) (models.ConversionRateReport, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	docList, err := svc.repo.FindSummary(ctx, shopID, filters)
+
+	if err != nil {
+		return models.ConversionRateReport{}, err
+	}
+
+	return ConversionRate(docList, svc.timeNow()), nil
+}
+
+// ConversionRate count quotations by their status at now and calculate percent of converted quotations and their amount
+func ConversionRate(docList []models.SaleQuotationSummary, now time.Time) models.ConversionRateReport {
+	report := models.ConversionRateReport{}
+
+	for _, doc := range docList {
+		quotation := models.SaleQuotation{ValidUntil: doc.ValidUntil, QuotationStatus: doc.QuotationStatus}
+
+		report.Total++
+		report.TotalAmount += doc.TotalAmount
+
+		switch quotation.EffectiveStatus(now) {
+		case models.QuotationStatusDraft:
+			report.Draft++
+		case models.QuotationStatusSent:
+			report.Sent++
+		case models.QuotationStatusAccepted:
+			report.Accepted++
+		case models.QuotationStatusRejected:
+			report.Rejected++
+		case models.QuotationStatusExpired:
+			report.Expired++
+		case models.QuotationStatusConverted:
+			report.Converted++
+			report.ConvertedAmount += doc.TotalAmount
+		}
+	}
+
+	if report.Total > 0 {
+		report.ConversionRate = float64(report.Converted) * 100 / float64(report.Total)
+	}
+
+	if report.TotalAmount != 0 {
+		report.AmountConversionRate = report.ConvertedAmount * 100 / report.TotalAmount
+	}
+
+	return report
+}
+
+func (svc SaleQuotationHttpService) saveMasterSync(shopID string) {
+	if svc.syncCacheRepo != nil {
+		err := svc.syncCacheRepo.Save(shopID, svc.GetModuleName())
+
+		if err != nil {
+			fmt.Printf("save %s cache error :: %s", svc.GetModuleName(), err.Error())
+		}
+	}
+}
+
+func (svc SaleQuotationHttpService) GetModuleName() string {
+	return "salequotation"
+}
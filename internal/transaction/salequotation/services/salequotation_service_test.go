@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"context"
+	"smlaicloudplatform/internal/transaction/salequotation/models"
+	"smlaicloudplatform/internal/transaction/salequotation/repositories"
+	"smlaicloudplatform/internal/transaction/salequotation/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuotation(guid string, status string, validUntil time.Time) models.SaleQuotationDoc {
+	doc := models.SaleQuotationDoc{}
+	doc.ShopID = "shop1"
+	doc.GuidFixed = guid
+	doc.DocNo = "QT-" + guid
+	doc.DocDatetime = validUntil.AddDate(0, 0, -30)
+	doc.ValidUntil = validUntil
+	doc.QuotationStatus = status
+	return doc
+}
+
+func TestSaleQuotationStatus(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	validUntil := now.AddDate(0, 0, 7)
+
+	cases := []struct {
+		name       string
+		status     string
+		validUntil time.Time
+		toStatus   string
+		isChanged  bool
+		wantErr    bool
+	}{
+		{name: "draft to sent", status: models.QuotationStatusDraft, validUntil: validUntil, toStatus: models.QuotationStatusSent},
+		{name: "sent to accepted", status: models.QuotationStatusSent, validUntil: validUntil, toStatus: models.QuotationStatusAccepted},
+		{name: "accepted back to draft", status: models.QuotationStatusAccepted, validUntil: validUntil, toStatus: models.QuotationStatusDraft},
+		{name: "rejected can not be accepted", status: models.QuotationStatusRejected, validUntil: validUntil, toStatus: models.QuotationStatusAccepted, wantErr: true},
+		{name: "converted can not be changed", status: models.QuotationStatusConverted, validUntil: validUntil, toStatus: models.QuotationStatusRejected, wantErr: true},
+		{name: "expired can not be accepted", status: models.QuotationStatusSent, validUntil: now.AddDate(0, 0, -1), toStatus: models.QuotationStatusAccepted, wantErr: true},
+		{name: "expired can be rejected", status: models.QuotationStatusSent, validUntil: now.AddDate(0, 0, -1), toStatus: models.QuotationStatusRejected},
+		{name: "changed by another request", status: models.QuotationStatusSent, validUntil: validUntil, toStatus: models.QuotationStatusAccepted, isChanged: true, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(SaleQuotationRepositoryMock)
+			repo.On("FindByGuid", "shop1", "q1").Return(newQuotation("q1", tt.status, tt.validUntil), nil)
+			repo.On("UpdateQuotationStatus", "shop1", "q1", tt.status, tt.toStatus, "user1", now).Return(!tt.isChanged, nil)
+
+			svc := services.NewSaleQuotationHttpService(repo, nil, nil, func() time.Time { return now })
+
+			err := svc.UpdateSaleQuotationStatus(context.Background(), "shop1", "q1", "user1", tt.toStatus)
+
+			if tt.isChanged {
+				require.ErrorIs(t, err, services.ErrQuotationStatusChanged)
+				return
+			}
+
+			if tt.wantErr {
+				require.Error(t, err)
+				repo.AssertNotCalled(t, "UpdateQuotationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaleQuotationConversion(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	repo := new(SaleQuotationRepositoryMock)
+	repo.On("FindByGuid", "shop1", "draft").Return(newQuotation("draft", models.QuotationStatusDraft, now.AddDate(0, 0, 7)), nil)
+	repo.On("FindByGuid", "shop1", "expired").Return(newQuotation("expired", models.QuotationStatusAccepted, now.AddDate(0, 0, -1)), nil)
+	repo.On("FindByGuid", "shop1", "unknown").Return(models.SaleQuotationDoc{}, nil)
+	repo.On("FindByGuid", "shop1", "sent").Return(newQuotation("sent", models.QuotationStatusSent, now.AddDate(0, 0, 7)), nil)
+
+	converted := newQuotation("converted", models.QuotationStatusConverted, now.AddDate(0, 0, 7))
+	repo.On("FindByGuid", "shop1", "converted").Return(converted, nil)
+
+	convertedDoc := models.ConvertedDoc{DocType: models.ConvertedDocTypeSaleOrder, DocGuid: "so1", DocNo: "SO-1"}
+	savedDoc := convertedDoc
+	savedDoc.ConvertedAt = now
+
+	// quotation is converted only once
+	repo.On("MarkConverted", "shop1", "sent", savedDoc).Return(true, nil).Once()
+	repo.On("MarkConverted", "shop1", "sent", savedDoc).Return(false, nil).Once()
+
+	svc := services.NewSaleQuotationConversion(repo, func() time.Time { return now })
+
+	_, err := svc.FindConvertible(context.Background(), "shop1", "draft")
+	assert.ErrorIs(t, err, services.ErrQuotationNotConvertible)
+
+	_, err = svc.FindConvertible(context.Background(), "shop1", "expired")
+	assert.ErrorIs(t, err, services.ErrQuotationExpired)
+
+	_, err = svc.FindConvertible(context.Background(), "shop1", "unknown")
+	assert.ErrorIs(t, err, services.ErrQuotationNotFound)
+
+	_, err = svc.FindConvertible(context.Background(), "shop1", "converted")
+	assert.ErrorIs(t, err, services.ErrQuotationConverted)
+
+	doc, err := svc.FindConvertible(context.Background(), "shop1", "sent")
+	require.NoError(t, err)
+	assert.Equal(t, "QT-sent", doc.DocNo)
+
+	require.NoError(t, svc.MarkConverted(context.Background(), "shop1", "sent", convertedDoc))
+	assert.ErrorIs(t, svc.MarkConverted(context.Background(), "shop1", "sent", convertedDoc), services.ErrQuotationStatusChanged)
+	repo.AssertExpectations(t)
+}
+
+func TestConversionRate(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	validUntil := now.AddDate(0, 0, 7)
+
+	report := services.ConversionRate([]models.SaleQuotationSummary{
+		{QuotationStatus: models.QuotationStatusConverted, ValidUntil: validUntil, TotalAmount: 300},
+		{QuotationStatus: models.QuotationStatusRejected, ValidUntil: validUntil, TotalAmount: 100},
+		{QuotationStatus: models.QuotationStatusSent, ValidUntil: validUntil, TotalAmount: 100},
+		{QuotationStatus: models.QuotationStatusSent, ValidUntil: now.AddDate(0, 0, -1), TotalAmount: 500},
+	}, now)
+
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Converted)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Equal(t, 1, report.Sent)
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, 25.0, report.ConversionRate)
+	assert.Equal(t, 1000.0, report.TotalAmount)
+	assert.Equal(t, 30.0, report.AmountConversionRate)
+
+	assert.Equal(t, models.ConversionRateReport{}, services.ConversionRate([]models.SaleQuotationSummary{}, now))
+}
+
+type SaleQuotationRepositoryMock struct {
+	repositories.ISaleQuotationRepository
+	mock.Mock
+}
+
+func (m *SaleQuotationRepositoryMock) FindByGuid(ctx context.Context, shopID string, guid string) (models.SaleQuotationDoc, error) {
+	args := m.Called(shopID, guid)
+	return args.Get(0).(models.SaleQuotationDoc), args.Error(1)
+}
+
+func (m *SaleQuotationRepositoryMock) UpdateQuotationStatus(ctx context.Context, shopID string, guid string, fromStatus string, toStatus string, authUsername string, updatedAt time.Time) (bool, error) {
+	args := m.Called(shopID, guid, fromStatus, toStatus, authUsername, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SaleQuotationRepositoryMock) MarkConverted(ctx context.Context, shopID string, guid string, convertedDoc models.ConvertedDoc) (bool, error) {
+	args := m.Called(shopID, guid, convertedDoc)
+	return args.Bool(0), args.Error(1)
+}
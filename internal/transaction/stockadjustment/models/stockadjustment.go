@@ -2,6 +2,7 @@ package models
 
 import (
 	"smlaicloudplatform/internal/models"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
 	transmodels "smlaicloudplatform/internal/transaction/models"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,8 +11,9 @@ import (
 const stockadjustmentCollectionName = "transactionStockAdjustment"
 
 type StockAdjustment struct {
-	models.PartitionIdentity `bson:"inline"`
-	transmodels.Transaction  `bson:"inline"`
+	models.PartitionIdentity   `bson:"inline"`
+	transmodels.Transaction    `bson:"inline"`
+	approvalmodels.DocApproval `bson:"inline"`
 }
 type StockAdjustmentInfo struct {
 	models.DocIdentity `bson:"inline"`
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockadjustment/config"
 	"smlaicloudplatform/internal/transaction/stockadjustment/models"
@@ -14,6 +15,11 @@ type IStockAdjustmentMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockAdjustmentDoc) error
 	UpdateInBatch(docList []models.StockAdjustmentDoc) error
 	DeleteInBatch(docList []models.StockAdjustmentDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockAdjustmentDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockAdjustmentDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockAdjustmentDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockAdjustmentDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockAdjustmentDoc) error
 }
 
 type StockAdjustmentMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockAdjustmentMessageQueueRepository(prod microservice.IProducer) Stock
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockAdjustmentDoc](prod, config.StockAdjustmentMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockAdjustmentOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockAdjustmentOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockAdjustmentMessageQueueRepository {
+	insRepo := NewStockAdjustmentMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,7 @@ type IStockAdjustmentRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockAdjustmentActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockAdjustmentDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type StockAdjustmentRepository struct {
@@ -58,6 +59,10 @@ func NewStockAdjustmentRepository(pst microservice.IPersisterMongo) *StockAdjust
 
 	return insRepo
 }
+
+func (repo StockAdjustmentRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
 func (repo StockAdjustmentRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockAdjustmentDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
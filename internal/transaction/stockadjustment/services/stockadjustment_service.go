@@ -9,15 +9,16 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockadjustment/models"
 	"smlaicloudplatform/internal/transaction/stockadjustment/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +26,16 @@ import (
 )
 
 type IStockAdjustmentService interface {
-	CreateStockAdjustment(shopID string, authUsername string, doc models.StockAdjustment) (string, string, error)
-	UpdateStockAdjustment(shopID string, guid string, authUsername string, doc models.StockAdjustment) error
-	DeleteStockAdjustment(shopID string, guid string, authUsername string) error
-	DeleteStockAdjustmentByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockAdjustment(shopID string, guid string) (models.StockAdjustmentInfo, error)
-	InfoStockAdjustmentByCode(shopID string, code string) (models.StockAdjustmentInfo, error)
-	SearchStockAdjustment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockAdjustmentInfo, mongopagination.PaginationData, error)
-	SearchStockAdjustmentStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockAdjustmentInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockAdjustment) (common.BulkImport, error)
+	CreateStockAdjustment(ctx context.Context, shopID string, authUsername string, doc models.StockAdjustment) (string, string, error)
+	UpdateStockAdjustment(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockAdjustment) error
+	DeleteStockAdjustment(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockAdjustmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockAdjustment(ctx context.Context, shopID string, guid string) (models.StockAdjustmentInfo, error)
+	InfoStockAdjustmentByCode(ctx context.Context, shopID string, code string) (models.StockAdjustmentInfo, error)
+	SearchStockAdjustment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockAdjustmentInfo, mongopagination.PaginationData, error)
+	SearchStockAdjustmentStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockAdjustmentInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockAdjustment) (common.BulkImport, error)
+	Approval() approvalservices.IDocumentApprovalService
 
 	GetModuleName() string
 }
@@ -49,9 +51,9 @@ const (
 type StockAdjustmentService struct {
 	repoMq             repositories.IStockAdjustmentMessageQueueRepository
 	repo               repositories.IStockAdjustmentRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
+	approvalWorkflow   approvalservices.IApprovalWorkflow
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockAdjustmentActivity, models.StockAdjustmentDeleteActivity]
 	parser         IStockAdjustmenParser
@@ -60,7 +62,8 @@ type StockAdjustmentService struct {
 
 func NewStockAdjustmentService(
 	repo repositories.IStockAdjustmentRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
+	approvalWorkflow approvalservices.IApprovalWorkflow,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IStockAdjustmentMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +75,11 @@ func NewStockAdjustmentService(
 	insSvc := &StockAdjustmentService{
 		repo:               repo,
 		repoMq:             repoMq,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
+		approvalWorkflow:   approvalWorkflow,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +88,15 @@ func NewStockAdjustmentService(
 	return insSvc
 }
 
-func (svc StockAdjustmentService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockAdjustmentService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockAdjustmentService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockAdjustmentService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc StockAdjustmentService) CreateStockAdjustment(shopID string, authUsername string, doc models.StockAdjustment) (string, string, error) {
+func (svc StockAdjustmentService) CreateStockAdjustment(ctx context.Context, shopID string, authUsername string, doc models.StockAdjustment) (string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.StockAdjustmentDoc{}
@@ -160,21 +112,37 @@ func (svc StockAdjustmentService) CreateStockAdjustment(shopID string, authUsern
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		approvalStatus, err := svc.approvalWorkflow.Submit(ctx, svc.approvalDocument(shopID, newGuidFixed, docNo, doc), authUsername, doc.IsDraft)
+		if err != nil {
+			return err
+		}
+
+		dataDoc.DocNo = docNo
+		dataDoc.ApprovalStatus = approvalStatus
+
+		err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+		if err != nil {
+			svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeStockAdjustment, newGuidFixed)
+		}
+		return err
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -209,9 +177,9 @@ func (svc StockAdjustmentService) PrepareDetail(details []trans_models.Detail, p
 	return details
 }
 
-func (svc StockAdjustmentService) UpdateStockAdjustment(shopID string, guid string, authUsername string, doc models.StockAdjustment) error {
+func (svc StockAdjustmentService) UpdateStockAdjustment(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockAdjustment) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -235,27 +203,39 @@ func (svc StockAdjustmentService) UpdateStockAdjustment(shopID string, guid stri
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
+	approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, guid, findDoc.DocNo, doc), authUsername, doc.IsDraft)
+	if err != nil {
+		return err
+	}
+
 	dataDoc.DocNo = findDoc.DocNo
+	dataDoc.ApprovalStatus = approvalStatus
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockAdjustmentService) DeleteStockAdjustment(shopID string, guid string, authUsername string) error {
+func (svc StockAdjustmentService) DeleteStockAdjustment(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -268,45 +248,67 @@ func (svc StockAdjustmentService) DeleteStockAdjustment(shopID string, guid stri
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
+	svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeStockAdjustment, guid)
+
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockAdjustmentService) DeleteStockAdjustmentByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockAdjustmentService) DeleteStockAdjustmentByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeStockAdjustment, guid)
+	}
+
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockAdjustmentService) InfoStockAdjustment(shopID string, guid string) (models.StockAdjustmentInfo, error) {
+func (svc StockAdjustmentService) InfoStockAdjustment(ctx context.Context, shopID string, guid string) (models.StockAdjustmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -322,9 +324,9 @@ func (svc StockAdjustmentService) InfoStockAdjustment(shopID string, guid string
 	return findDoc.StockAdjustmentInfo, nil
 }
 
-func (svc StockAdjustmentService) InfoStockAdjustmentByCode(shopID string, code string) (models.StockAdjustmentInfo, error) {
+func (svc StockAdjustmentService) InfoStockAdjustmentByCode(ctx context.Context, shopID string, code string) (models.StockAdjustmentInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -340,9 +342,9 @@ func (svc StockAdjustmentService) InfoStockAdjustmentByCode(shopID string, code
 	return findDoc.StockAdjustmentInfo, nil
 }
 
-func (svc StockAdjustmentService) SearchStockAdjustment(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockAdjustmentInfo, mongopagination.PaginationData, error) {
+func (svc StockAdjustmentService) SearchStockAdjustment(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockAdjustmentInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -358,9 +360,9 @@ func (svc StockAdjustmentService) SearchStockAdjustment(shopID string, filters m
 	return docList, pagination, nil
 }
 
-func (svc StockAdjustmentService) SearchStockAdjustmentStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockAdjustmentInfo, int, error) {
+func (svc StockAdjustmentService) SearchStockAdjustmentStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockAdjustmentInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -378,9 +380,9 @@ func (svc StockAdjustmentService) SearchStockAdjustmentStep(shopID string, langC
 	return docList, total, nil
 }
 
-func (svc StockAdjustmentService) SaveInBatch(shopID string, authUsername string, dataList []models.StockAdjustment) (common.BulkImport, error) {
+func (svc StockAdjustmentService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockAdjustment) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockAdjustment](dataList, svc.getDocIDKey)
@@ -436,7 +438,13 @@ func (svc StockAdjustmentService) SaveInBatch(shopID string, authUsername string
 		},
 		func(shopID string, authUsername string, data models.StockAdjustment, doc models.StockAdjustmentDoc) error {
 
+			approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, doc.GuidFixed, doc.DocNo, data), authUsername, data.IsDraft)
+			if err != nil {
+				return err
+			}
+
 			doc.StockAdjustment = data
+			doc.ApprovalStatus = approvalStatus
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
@@ -449,9 +457,21 @@ func (svc StockAdjustmentService) SaveInBatch(shopID string, authUsername string
 	)
 
 	if len(createDataList) > 0 {
+		documentApproval := svc.documentApproval()
+
+		approvalStatuses, err := documentApproval.SubmitInBatch(ctx, createDataList, authUsername)
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
+		for idx := range createDataList {
+			createDataList[idx].ApprovalStatus = approvalStatuses[idx]
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			documentApproval.RemoveInBatch(ctx, createDataList)
 			return common.BulkImport{}, err
 		}
 
@@ -506,3 +526,52 @@ func (svc StockAdjustmentService) saveMasterSync(shopID string) {
 func (svc StockAdjustmentService) GetModuleName() string {
 	return "stockAdjustment"
 }
+
+func (svc StockAdjustmentService) approvalDocument(shopID string, guid string, docNo string, doc models.StockAdjustment) approvalmodels.ApprovalDocument {
+	return approvalmodels.ApprovalDocument{
+		ShopID:  shopID,
+		DocType: approvalmodels.DocTypeStockAdjustment,
+		DocGuid: guid,
+		DocNo:   docNo,
+		Amount:  doc.TotalAmount,
+	}
+}
+
+// Approval return approval workflow of saved stock adjustments
+func (svc StockAdjustmentService) Approval() approvalservices.IDocumentApprovalService {
+	return svc.documentApproval()
+}
+
+func (svc StockAdjustmentService) documentApproval() approvalservices.DocumentApprovalService[models.StockAdjustmentDoc] {
+	return approvalservices.NewDocumentApprovalService(
+		svc.approvalWorkflow,
+		approvalmodels.DocTypeStockAdjustment,
+		svc.repo.FindByGuid,
+		func(doc models.StockAdjustmentDoc) approvalmodels.ApprovalDocument {
+			return svc.approvalDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.StockAdjustment)
+		},
+		svc.saveApprovalStatus,
+	)
+}
+
+// saveApprovalStatus save changed approval status and publish the document so stock is posted or removed by consumers
+func (svc StockAdjustmentService) saveApprovalStatus(ctx context.Context, doc models.StockAdjustmentDoc, approvalStatus string) error {
+	doc.ApprovalStatus = approvalStatus
+
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, doc.ShopID, doc.GuidFixed, doc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, doc)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	svc.saveMasterSync(doc.ShopID)
+
+	return nil
+}
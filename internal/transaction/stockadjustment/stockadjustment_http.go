@@ -6,8 +6,10 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/approval"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/stockadjustment/models"
 	"smlaicloudplatform/internal/transaction/stockadjustment/repositories"
 	"smlaicloudplatform/internal/transaction/stockadjustment/services"
@@ -30,13 +32,14 @@ func NewStockAdjustmentHttp(ms *microservice.Microservice, cfg config.IConfig) S
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockAdjustmentRepository(pst)
-	repoMq := repositories.NewStockAdjustmentMessageQueueRepository(producer)
+	repoMq := repositories.NewStockAdjustmentOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewStockAdjustmentService(repo, transRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockAdjustmenParser{})
+	approvalWorkflow := approval.InitApprovalWorkflow(ms, cfg)
+	svc := services.NewStockAdjustmentService(repo, docNoSequencer, approvalWorkflow, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockAdjustmenParser{})
 
 	return StockAdjustmentHttp{
 		ms:  ms,
@@ -57,6 +60,8 @@ func (h StockAdjustmentHttp) RegisterHttp() {
 	h.ms.PUT("/transaction/stock-adjustment/:id", h.UpdateStockAdjustment)
 	h.ms.DELETE("/transaction/stock-adjustment/:id", h.DeleteStockAdjustment)
 	h.ms.DELETE("/transaction/stock-adjustment", h.DeleteStockAdjustmentByGUIDs)
+
+	approval.NewDocumentApprovalHttp(h.ms, h.svc.Approval()).RegisterHttp("/transaction/stock-adjustment")
 }
 
 // Create StockAdjustment godoc
@@ -86,7 +91,7 @@ func (h StockAdjustmentHttp) CreateStockAdjustment(ctx microservice.IContext) er
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateStockAdjustment(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateStockAdjustment(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -132,7 +137,7 @@ func (h StockAdjustmentHttp) UpdateStockAdjustment(ctx microservice.IContext) er
 		return err
 	}
 
-	err = h.svc.UpdateStockAdjustment(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockAdjustment(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -163,7 +168,7 @@ func (h StockAdjustmentHttp) DeleteStockAdjustment(ctx microservice.IContext) er
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockAdjustment(shopID, id, authUsername)
+	err := h.svc.DeleteStockAdjustment(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -202,7 +207,7 @@ func (h StockAdjustmentHttp) DeleteStockAdjustmentByGUIDs(ctx microservice.ICont
 		return err
 	}
 
-	err = h.svc.DeleteStockAdjustmentByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockAdjustmentByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -232,7 +237,7 @@ func (h StockAdjustmentHttp) InfoStockAdjustment(ctx microservice.IContext) erro
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockAdjustment %v", id)
-	doc, err := h.svc.InfoStockAdjustment(shopID, id)
+	doc, err := h.svc.InfoStockAdjustment(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -262,7 +267,7 @@ func (h StockAdjustmentHttp) InfoStockAdjustmentByCode(ctx microservice.IContext
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockAdjustmentByCode(shopID, code)
+	doc, err := h.svc.InfoStockAdjustmentByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -314,7 +319,7 @@ func (h StockAdjustmentHttp) SearchStockAdjustmentPage(ctx microservice.IContext
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockAdjustment(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockAdjustment(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -370,7 +375,7 @@ func (h StockAdjustmentHttp) SearchStockAdjustmentStep(ctx microservice.IContext
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockAdjustmentStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockAdjustmentStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -410,7 +415,7 @@ func (h StockAdjustmentHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
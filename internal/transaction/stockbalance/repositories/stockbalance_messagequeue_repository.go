@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockbalance/config"
 	"smlaicloudplatform/internal/transaction/stockbalance/models"
@@ -14,6 +15,11 @@ type IStockBalanceMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockBalanceMessage) error
 	UpdateInBatch(docList []models.StockBalanceMessage) error
 	DeleteInBatch(docList []models.StockBalanceMessage) error
+	OutboxCreate(ctx context.Context, doc models.StockBalanceMessage) error
+	OutboxUpdate(ctx context.Context, doc models.StockBalanceMessage) error
+	OutboxDelete(ctx context.Context, doc models.StockBalanceMessage) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockBalanceMessage) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockBalanceMessage) error
 }
 
 type StockBalanceMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockBalanceMessageQueueRepository(prod microservice.IProducer) StockBal
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockBalanceMessage](prod, config.StockBalanceMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockBalanceOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockBalanceOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockBalanceMessageQueueRepository {
+	insRepo := NewStockBalanceMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
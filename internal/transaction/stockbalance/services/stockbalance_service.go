@@ -8,16 +8,15 @@ import (
 	common "smlaicloudplatform/internal/models"
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockbalance/models"
 	"smlaicloudplatform/internal/transaction/stockbalance/repositories"
 	stockbalancedetail_services "smlaicloudplatform/internal/transaction/stockbalancedetail/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +24,15 @@ import (
 )
 
 type IStockBalanceHttpService interface {
-	CreateStockBalance(shopID string, authUsername string, doc models.StockBalance) (*models.StockBalanceDoc, string, string, error)
-	UpdateStockBalance(shopID string, guid string, authUsername string, doc models.StockBalance) error
-	DeleteStockBalance(shopID string, guid string, authUsername string) error
-	DeleteStockBalanceByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockBalance(shopID string, guid string) (models.StockBalanceInfo, error)
-	InfoStockBalanceByCode(shopID string, code string) (models.StockBalanceInfo, error)
-	SearchStockBalance(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceInfo, mongopagination.PaginationData, error)
-	SearchStockBalanceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockBalance) (common.BulkImport, error)
+	CreateStockBalance(ctx context.Context, shopID string, authUsername string, doc models.StockBalance) (*models.StockBalanceDoc, string, string, error)
+	UpdateStockBalance(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockBalance) error
+	DeleteStockBalance(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockBalanceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockBalance(ctx context.Context, shopID string, guid string) (models.StockBalanceInfo, error)
+	InfoStockBalanceByCode(ctx context.Context, shopID string, code string) (models.StockBalanceInfo, error)
+	SearchStockBalance(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceInfo, mongopagination.PaginationData, error)
+	SearchStockBalanceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockBalance) (common.BulkImport, error)
 
 	GetModuleName() string
 
@@ -52,8 +51,7 @@ type StockBalanceHttpService struct {
 	svcStockBalanceDetail stockbalancedetail_services.IStockBalanceDetailService
 	repoMq                repositories.IStockBalanceMessageQueueRepository
 	repo                  repositories.IStockBalanceRepository
-	repoCache             trancache.ICacheRepository
-	cacheExpireDocNo      time.Duration
+	docNoSequencer        docsequence.IDocNoSequencer
 	syncCacheRepo         mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockBalanceActivity, models.StockBalanceDeleteActivity]
 	contextTimeout time.Duration
@@ -62,7 +60,7 @@ type StockBalanceHttpService struct {
 func NewStockBalanceHttpService(
 	svcStockBalanceDetail stockbalancedetail_services.IStockBalanceDetailService,
 	repo repositories.IStockBalanceRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
 	repoMq repositories.IStockBalanceMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
 ) *StockBalanceHttpService {
@@ -73,9 +71,8 @@ func NewStockBalanceHttpService(
 		svcStockBalanceDetail: svcStockBalanceDetail,
 		repoMq:                repoMq,
 		repo:                  repo,
-		repoCache:             repoCache,
+		docNoSequencer:        docNoSequencer,
 		syncCacheRepo:         syncCacheRepo,
-		cacheExpireDocNo:      time.Hour * 24,
 		contextTimeout:        contextTimeout,
 	}
 
@@ -84,65 +81,15 @@ func NewStockBalanceHttpService(
 	return insSvc
 }
 
-func (svc StockBalanceHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockBalanceHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockBalanceHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockBalanceHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-func (svc StockBalanceHttpService) CreateStockBalance(shopID string, authUsername string, doc models.StockBalance) (*models.StockBalanceDoc, string, string, error) {
+func (svc StockBalanceHttpService) CreateStockBalance(ctx context.Context, shopID string, authUsername string, doc models.StockBalance) (*models.StockBalanceDoc, string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return nil, "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	docData := models.StockBalanceDoc{}
@@ -150,31 +97,37 @@ func (svc StockBalanceHttpService) CreateStockBalance(shopID string, authUsernam
 	docData.GuidFixed = newGuidFixed
 	docData.StockBalance = doc
 
-	docData.DocNo = newDocNo
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			stockBalanceDocMessage := models.StockBalanceMessage{}
+			stockBalanceDocMessage.StockBalance = docData.StockBalance
+			return svc.repoMq.OutboxCreate(ctx, stockBalanceDocMessage)
+		})
+	})
 
 	if err != nil {
 		return nil, "", "", err
 	}
 
 	go func() {
-		stockBalanceDocMessage := models.StockBalanceMessage{}
-		stockBalanceDocMessage.StockBalance = doc
-		svc.repoMq.Create(stockBalanceDocMessage)
-
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return &docData, newGuidFixed, newDocNo, nil
 }
 
-func (svc StockBalanceHttpService) UpdateStockBalance(shopID string, guid string, authUsername string, doc models.StockBalance) error {
+func (svc StockBalanceHttpService) UpdateStockBalance(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockBalance) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -194,25 +147,31 @@ func (svc StockBalanceHttpService) UpdateStockBalance(shopID string, guid string
 	docData.UpdatedBy = authUsername
 	docData.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, docData)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, docData)
+		if err != nil {
+			return err
+		}
+
+		stockBalanceDocMessage := models.StockBalanceMessage{}
+		stockBalanceDocMessage.StockBalance = docData.StockBalance
+		return svc.repoMq.OutboxUpdate(ctx, stockBalanceDocMessage)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		stockBalanceDocMessage := models.StockBalanceMessage{}
-		stockBalanceDocMessage.StockBalance = findDoc.StockBalance
-		svc.repoMq.Update(stockBalanceDocMessage)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceHttpService) DeleteStockBalance(shopID string, guid string, authUsername string) error {
+func (svc StockBalanceHttpService) DeleteStockBalance(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -231,13 +190,15 @@ func (svc StockBalanceHttpService) DeleteStockBalance(shopID string, guid string
 			return err
 		}
 
-		err = svc.svcStockBalanceDetail.DeleteStockBalanceDetailByDocNo(shopID, authUsername, findDoc.DocNo)
+		err = svc.svcStockBalanceDetail.DeleteStockBalanceDetailByDocNo(ctx, shopID, authUsername, findDoc.DocNo)
 
 		if err != nil {
 			return err
 		}
 
-		return nil
+		stockBalanceDocMessage := models.StockBalanceMessage{}
+		stockBalanceDocMessage.StockBalance = findDoc.StockBalance
+		return svc.repoMq.OutboxDelete(ctx, stockBalanceDocMessage)
 	})
 
 	if err != nil {
@@ -245,18 +206,15 @@ func (svc StockBalanceHttpService) DeleteStockBalance(shopID string, guid string
 	}
 
 	func() {
-		stockBalanceDocMessage := models.StockBalanceMessage{}
-		stockBalanceDocMessage.StockBalance = findDoc.StockBalance
-		svc.repoMq.Delete(stockBalanceDocMessage)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceHttpService) DeleteStockBalanceByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockBalanceHttpService) DeleteStockBalanceByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// prepare items for message queue
@@ -270,20 +228,19 @@ func (svc StockBalanceHttpService) DeleteStockBalanceByGUIDs(shopID string, auth
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
-	if err != nil {
-		return err
-	}
-
-	for _, doc := range docs {
-		err = svc.svcStockBalanceDetail.DeleteStockBalanceDetailByDocNo(shopID, authUsername, doc.DocNo)
-
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
 		if err != nil {
 			return err
 		}
-	}
 
-	func() {
+		for _, doc := range docs {
+			err = svc.svcStockBalanceDetail.DeleteStockBalanceDetailByDocNo(ctx, shopID, authUsername, doc.DocNo)
+
+			if err != nil {
+				return err
+			}
+		}
 
 		stockBalanceDocMessages := []models.StockBalanceMessage{}
 
@@ -293,16 +250,23 @@ func (svc StockBalanceHttpService) DeleteStockBalanceByGUIDs(shopID string, auth
 			stockBalanceDocMessages = append(stockBalanceDocMessages, stockBalanceDocMessage)
 		}
 
-		svc.repoMq.DeleteInBatch(stockBalanceDocMessages)
+		return svc.repoMq.OutboxDeleteInBatch(ctx, stockBalanceDocMessages)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	func() {
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceHttpService) InfoStockBalance(shopID string, guid string) (models.StockBalanceInfo, error) {
+func (svc StockBalanceHttpService) InfoStockBalance(ctx context.Context, shopID string, guid string) (models.StockBalanceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -318,9 +282,9 @@ func (svc StockBalanceHttpService) InfoStockBalance(shopID string, guid string)
 	return findDoc.StockBalanceInfo, nil
 }
 
-func (svc StockBalanceHttpService) InfoStockBalanceByCode(shopID string, code string) (models.StockBalanceInfo, error) {
+func (svc StockBalanceHttpService) InfoStockBalanceByCode(ctx context.Context, shopID string, code string) (models.StockBalanceInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -336,9 +300,9 @@ func (svc StockBalanceHttpService) InfoStockBalanceByCode(shopID string, code st
 	return findDoc.StockBalanceInfo, nil
 }
 
-func (svc StockBalanceHttpService) SearchStockBalance(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceInfo, mongopagination.PaginationData, error) {
+func (svc StockBalanceHttpService) SearchStockBalance(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -354,9 +318,9 @@ func (svc StockBalanceHttpService) SearchStockBalance(shopID string, filters map
 	return docList, pagination, nil
 }
 
-func (svc StockBalanceHttpService) SearchStockBalanceStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceInfo, int, error) {
+func (svc StockBalanceHttpService) SearchStockBalanceStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -374,9 +338,9 @@ func (svc StockBalanceHttpService) SearchStockBalanceStep(shopID string, langCod
 	return docList, total, nil
 }
 
-func (svc StockBalanceHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.StockBalance) (common.BulkImport, error) {
+func (svc StockBalanceHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockBalance) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockBalance](dataList, svc.getDocIDKey)
@@ -6,7 +6,9 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockbalance/models"
 	"smlaicloudplatform/internal/transaction/stockbalance/repositories"
@@ -32,7 +34,7 @@ func NewStockBalanceHttp(ms *microservice.Microservice, cfg config.IConfig) Stoc
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockBalanceRepository(pst)
-	repoMq := repositories.NewStockBalanceMessageQueueRepository(producer)
+	repoMq := repositories.NewStockBalanceOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
 	transCacheRepo := trancache.NewCacheRepository(cache)
@@ -40,7 +42,7 @@ func NewStockBalanceHttp(ms *microservice.Microservice, cfg config.IConfig) Stoc
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
 	repoStockBalanceDetail := stockbalancedetail_repositories.NewStockBalanceDetailRepository(pst)
-	repoMqStockBalanceDetail := stockbalancedetail_repositories.NewStockBalanceDetailMessageQueueRepository(producer)
+	repoMqStockBalanceDetail := stockbalancedetail_repositories.NewStockBalanceDetailOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	svcStockBalanceDetail := stockbalancedetail_services.NewStockBalanceDetailService(
 		repoStockBalanceDetail,
@@ -51,7 +53,7 @@ func NewStockBalanceHttp(ms *microservice.Microservice, cfg config.IConfig) Stoc
 		stockbalancedetail_services.StockBalanceDetailParser{},
 	)
 
-	svc := services.NewStockBalanceHttpService(svcStockBalanceDetail, repo, transCacheRepo, repoMq, masterSyncCacheRepo)
+	svc := services.NewStockBalanceHttpService(svcStockBalanceDetail, repo, docsequence.InitDocNoSequencer(ms, cfg), repoMq, masterSyncCacheRepo)
 
 	return StockBalanceHttp{
 		ms:  ms,
@@ -101,7 +103,7 @@ func (h StockBalanceHttp) CreateStockBalance(ctx microservice.IContext) error {
 		return err
 	}
 
-	_, idx, docNo, err := h.svc.CreateStockBalance(shopID, authUsername, *docReq)
+	_, idx, docNo, err := h.svc.CreateStockBalance(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -147,7 +149,7 @@ func (h StockBalanceHttp) UpdateStockBalance(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateStockBalance(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockBalance(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -178,7 +180,7 @@ func (h StockBalanceHttp) DeleteStockBalance(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockBalance(shopID, id, authUsername)
+	err := h.svc.DeleteStockBalance(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -217,7 +219,7 @@ func (h StockBalanceHttp) DeleteStockBalanceByGUIDs(ctx microservice.IContext) e
 		return err
 	}
 
-	err = h.svc.DeleteStockBalanceByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockBalanceByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -247,7 +249,7 @@ func (h StockBalanceHttp) InfoStockBalance(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockBalance %v", id)
-	doc, err := h.svc.InfoStockBalance(shopID, id)
+	doc, err := h.svc.InfoStockBalance(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -277,7 +279,7 @@ func (h StockBalanceHttp) InfoStockBalanceByCode(ctx microservice.IContext) erro
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockBalanceByCode(shopID, code)
+	doc, err := h.svc.InfoStockBalanceByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -329,7 +331,7 @@ func (h StockBalanceHttp) SearchStockBalancePage(ctx microservice.IContext) erro
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockBalance(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockBalance(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -385,7 +387,7 @@ func (h StockBalanceHttp) SearchStockBalanceStep(ctx microservice.IContext) erro
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockBalanceStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockBalanceStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -425,7 +427,7 @@ func (h StockBalanceHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockbalancedetail/config"
 	"smlaicloudplatform/internal/transaction/stockbalancedetail/models"
@@ -14,6 +15,11 @@ type IStockBalanceDetailMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockBalanceDetailDoc) error
 	UpdateInBatch(docList []models.StockBalanceDetailDoc) error
 	DeleteInBatch(docList []models.StockBalanceDetailDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockBalanceDetailDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockBalanceDetailDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockBalanceDetailDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockBalanceDetailDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockBalanceDetailDoc) error
 }
 
 type StockBalanceDetailMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockBalanceDetailMessageQueueRepository(prod microservice.IProducer) St
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockBalanceDetailDoc](prod, config.StockBalanceDetailMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockBalanceDetailOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockBalanceDetailOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockBalanceDetailMessageQueueRepository {
+	insRepo := NewStockBalanceDetailMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -13,6 +13,7 @@ import (
 	"smlaicloudplatform/internal/transaction/stockbalancedetail/models"
 	"smlaicloudplatform/internal/transaction/stockbalancedetail/repositories"
 	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -21,15 +22,15 @@ import (
 )
 
 type IStockBalanceDetailService interface {
-	CreateStockBalanceDetail(shopID string, authUsername string, doc []models.StockBalanceDetail) error
-	UpdateStockBalanceDetail(shopID string, guid string, authUsername string, doc models.StockBalanceDetail) error
-	DeleteStockBalanceDetail(shopID string, guid string, authUsername string) error
-	DeleteStockBalanceDetailByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	DeleteStockBalanceDetailByDocNo(shopID string, authUsername string, docNo string) error
-	InfoStockBalanceDetail(shopID string, guid string) (models.StockBalanceDetailInfo, error)
-	InfoStockBalanceDetailByCode(shopID string, code string) (models.StockBalanceDetailInfo, error)
-	SearchStockBalanceDetail(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceDetailInfo, mongopagination.PaginationData, error)
-	SearchStockBalanceDetailStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceDetailInfo, int, error)
+	CreateStockBalanceDetail(ctx context.Context, shopID string, authUsername string, doc []models.StockBalanceDetail) error
+	UpdateStockBalanceDetail(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockBalanceDetail) error
+	DeleteStockBalanceDetail(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockBalanceDetailByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	DeleteStockBalanceDetailByDocNo(ctx context.Context, shopID string, authUsername string, docNo string) error
+	InfoStockBalanceDetail(ctx context.Context, shopID string, guid string) (models.StockBalanceDetailInfo, error)
+	InfoStockBalanceDetailByCode(ctx context.Context, shopID string, code string) (models.StockBalanceDetailInfo, error)
+	SearchStockBalanceDetail(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceDetailInfo, mongopagination.PaginationData, error)
+	SearchStockBalanceDetailStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceDetailInfo, int, error)
 
 	GetModuleName() string
 }
@@ -77,13 +78,13 @@ func NewStockBalanceDetailService(
 	return insSvc
 }
 
-func (svc StockBalanceDetailService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockBalanceDetailService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockBalanceDetailService) CreateStockBalanceDetail(shopID string, authUsername string, docs []models.StockBalanceDetail) error {
+func (svc StockBalanceDetailService) CreateStockBalanceDetail(ctx context.Context, shopID string, authUsername string, docs []models.StockBalanceDetail) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	prepareDocs := []models.StockBalanceDetailDoc{}
@@ -109,14 +110,20 @@ func (svc StockBalanceDetailService) CreateStockBalanceDetail(shopID string, aut
 		prepareDocs = append(prepareDocs, dataDoc)
 	}
 
-	err := svc.repo.CreateInBatch(ctx, prepareDocs)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.CreateInBatch(ctx, prepareDocs)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxCreateInBatch(ctx, prepareDocs)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	go func() {
-		svc.repoMq.CreateInBatch(prepareDocs)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -144,9 +151,9 @@ func (svc StockBalanceDetailService) PrepareDetail(detail trans_models.Detail, p
 	return resultDetail
 }
 
-func (svc StockBalanceDetailService) UpdateStockBalanceDetail(shopID string, guid string, authUsername string, doc models.StockBalanceDetail) error {
+func (svc StockBalanceDetailService) UpdateStockBalanceDetail(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockBalanceDetail) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -166,23 +173,29 @@ func (svc StockBalanceDetailService) UpdateStockBalanceDetail(shopID string, gui
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceDetailService) DeleteStockBalanceDetail(shopID string, guid string, authUsername string) error {
+func (svc StockBalanceDetailService) DeleteStockBalanceDetail(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -195,22 +208,28 @@ func (svc StockBalanceDetailService) DeleteStockBalanceDetail(shopID string, gui
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceDetailService) DeleteStockBalanceDetailByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockBalanceDetailService) DeleteStockBalanceDetailByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	// prepare item for message queue
@@ -223,22 +242,28 @@ func (svc StockBalanceDetailService) DeleteStockBalanceDetailByGUIDs(shopID stri
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceDetailService) DeleteStockBalanceDetailByDocNo(shopID string, authUsername string, docNo string) error {
+func (svc StockBalanceDetailService) DeleteStockBalanceDetailByDocNo(ctx context.Context, shopID string, authUsername string, docNo string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docs, err := svc.repo.FindByDocIndentityGuids(ctx, shopID, "docno", docNo)
@@ -250,22 +275,28 @@ func (svc StockBalanceDetailService) DeleteStockBalanceDetailByDocNo(shopID stri
 		"docno": docNo,
 	}
 
-	err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockBalanceDetailService) InfoStockBalanceDetail(shopID string, guid string) (models.StockBalanceDetailInfo, error) {
+func (svc StockBalanceDetailService) InfoStockBalanceDetail(ctx context.Context, shopID string, guid string) (models.StockBalanceDetailInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -281,9 +312,9 @@ func (svc StockBalanceDetailService) InfoStockBalanceDetail(shopID string, guid
 	return findDoc.StockBalanceDetailInfo, nil
 }
 
-func (svc StockBalanceDetailService) InfoStockBalanceDetailByCode(shopID string, code string) (models.StockBalanceDetailInfo, error) {
+func (svc StockBalanceDetailService) InfoStockBalanceDetailByCode(ctx context.Context, shopID string, code string) (models.StockBalanceDetailInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -299,9 +330,9 @@ func (svc StockBalanceDetailService) InfoStockBalanceDetailByCode(shopID string,
 	return findDoc.StockBalanceDetailInfo, nil
 }
 
-func (svc StockBalanceDetailService) SearchStockBalanceDetail(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceDetailInfo, mongopagination.PaginationData, error) {
+func (svc StockBalanceDetailService) SearchStockBalanceDetail(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockBalanceDetailInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -317,9 +348,9 @@ func (svc StockBalanceDetailService) SearchStockBalanceDetail(shopID string, fil
 	return docList, pagination, nil
 }
 
-func (svc StockBalanceDetailService) SearchStockBalanceDetailStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceDetailInfo, int, error) {
+func (svc StockBalanceDetailService) SearchStockBalanceDetailStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockBalanceDetailInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
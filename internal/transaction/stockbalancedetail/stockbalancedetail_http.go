@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockbalancedetail/models"
@@ -29,7 +30,7 @@ func NewStockBalanceDetailHttp(ms *microservice.Microservice, cfg config.IConfig
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockBalanceDetailRepository(pst)
-	repoMq := repositories.NewStockBalanceDetailMessageQueueRepository(producer)
+	repoMq := repositories.NewStockBalanceDetailOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
@@ -83,7 +84,7 @@ func (h StockBalanceDetailHttp) CreateStockBalanceDetail(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.CreateStockBalanceDetail(shopID, authUsername, docReq)
+	err = h.svc.CreateStockBalanceDetail(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -127,7 +128,7 @@ func (h StockBalanceDetailHttp) UpdateStockBalanceDetail(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.UpdateStockBalanceDetail(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockBalanceDetail(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -158,7 +159,7 @@ func (h StockBalanceDetailHttp) DeleteStockBalanceDetail(ctx microservice.IConte
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockBalanceDetail(shopID, id, authUsername)
+	err := h.svc.DeleteStockBalanceDetail(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -197,7 +198,7 @@ func (h StockBalanceDetailHttp) DeleteStockBalanceDetailByGUIDs(ctx microservice
 		return err
 	}
 
-	err = h.svc.DeleteStockBalanceDetailByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockBalanceDetailByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -227,7 +228,7 @@ func (h StockBalanceDetailHttp) InfoStockBalanceDetail(ctx microservice.IContext
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockBalanceDetail %v", id)
-	doc, err := h.svc.InfoStockBalanceDetail(shopID, id)
+	doc, err := h.svc.InfoStockBalanceDetail(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -266,7 +267,7 @@ func (h StockBalanceDetailHttp) SearchStockBalanceDetailPage(ctx microservice.IC
 		"docno": docNo,
 	}
 
-	docList, pagination, err := h.svc.SearchStockBalanceDetail(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockBalanceDetail(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -307,7 +308,7 @@ func (h StockBalanceDetailHttp) SearchStockBalanceDetailStep(ctx microservice.IC
 		"docno": docNo,
 	}
 
-	docList, total, err := h.svc.SearchStockBalanceDetailStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockBalanceDetailStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -338,7 +339,7 @@ func (h StockBalanceDetailHttp) DeleteStockBalanceDetailByDocNo(ctx microservice
 
 	docNo := ctx.Param("docno")
 
-	err := h.svc.DeleteStockBalanceDetailByDocNo(shopID, authUsername, docNo)
+	err := h.svc.DeleteStockBalanceDetailByDocNo(ctx.Context(), shopID, authUsername, docNo)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/config"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/models"
@@ -14,6 +15,11 @@ type IStockPickupProductMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockPickupProductDoc) error
 	UpdateInBatch(docList []models.StockPickupProductDoc) error
 	DeleteInBatch(docList []models.StockPickupProductDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockPickupProductDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockPickupProductDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockPickupProductDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockPickupProductDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockPickupProductDoc) error
 }
 
 type StockPickupProductMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockPickupProductMessageQueueRepository(prod microservice.IProducer) St
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockPickupProductDoc](prod, config.StockPickupProductMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockPickupProductOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockPickupProductOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockPickupProductMessageQueueRepository {
+	insRepo := NewStockPickupProductMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,7 @@ type IStockPickupProductRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockPickupProductActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockPickupProductDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type StockPickupProductRepository struct {
@@ -58,6 +59,10 @@ func NewStockPickupProductRepository(pst microservice.IPersisterMongo) *StockPic
 
 	return insRepo
 }
+
+func (repo StockPickupProductRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
 func (repo StockPickupProductRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockPickupProductDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -9,15 +9,14 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/models"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +24,15 @@ import (
 )
 
 type IStockPickupProductService interface {
-	CreateStockPickupProduct(shopID string, authUsername string, doc models.StockPickupProduct) (string, string, error)
-	UpdateStockPickupProduct(shopID string, guid string, authUsername string, doc models.StockPickupProduct) error
-	DeleteStockPickupProduct(shopID string, guid string, authUsername string) error
-	DeleteStockPickupProductByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockPickupProduct(shopID string, guid string) (models.StockPickupProductInfo, error)
-	InfoStockPickupProductByCode(shopID string, code string) (models.StockPickupProductInfo, error)
-	SearchStockPickupProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockPickupProductInfo, mongopagination.PaginationData, error)
-	SearchStockPickupProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockPickupProductInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockPickupProduct) (common.BulkImport, error)
+	CreateStockPickupProduct(ctx context.Context, shopID string, authUsername string, doc models.StockPickupProduct) (string, string, error)
+	UpdateStockPickupProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockPickupProduct) error
+	DeleteStockPickupProduct(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockPickupProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockPickupProduct(ctx context.Context, shopID string, guid string) (models.StockPickupProductInfo, error)
+	InfoStockPickupProductByCode(ctx context.Context, shopID string, code string) (models.StockPickupProductInfo, error)
+	SearchStockPickupProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockPickupProductInfo, mongopagination.PaginationData, error)
+	SearchStockPickupProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockPickupProductInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockPickupProduct) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -49,9 +48,8 @@ const (
 type StockPickupProductService struct {
 	repoMq             repositories.IStockPickupProductMessageQueueRepository
 	repo               repositories.IStockPickupProductRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockPickupProductActivity, models.StockPickupProductDeleteActivity]
 	parser         IStockPickupProductParser
@@ -60,7 +58,7 @@ type StockPickupProductService struct {
 
 func NewStockPickupProductService(
 	repo repositories.IStockPickupProductRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IStockPickupProductMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +70,10 @@ func NewStockPickupProductService(
 	insSvc := &StockPickupProductService{
 		repoMq:             repoMq,
 		repo:               repo,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +82,15 @@ func NewStockPickupProductService(
 	return insSvc
 }
 
-func (svc StockPickupProductService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockPickupProductService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockPickupProductService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockPickupProductService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
+func (svc StockPickupProductService) CreateStockPickupProduct(ctx context.Context, shopID string, authUsername string, doc models.StockPickupProduct) (string, string, error) {
 
-func (svc StockPickupProductService) CreateStockPickupProduct(shopID string, authUsername string, doc models.StockPickupProduct) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.StockPickupProductDoc{}
@@ -160,19 +106,26 @@ func (svc StockPickupProductService) CreateStockPickupProduct(shopID string, aut
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		dataDoc.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 	return newGuidFixed, newDocNo, nil
@@ -206,9 +159,9 @@ func (svc StockPickupProductService) PrepareDetail(details []trans_models.Detail
 	return details
 }
 
-func (svc StockPickupProductService) UpdateStockPickupProduct(shopID string, guid string, authUsername string, doc models.StockPickupProduct) error {
+func (svc StockPickupProductService) UpdateStockPickupProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockPickupProduct) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -236,23 +189,29 @@ func (svc StockPickupProductService) UpdateStockPickupProduct(shopID string, gui
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockPickupProductService) DeleteStockPickupProduct(shopID string, guid string, authUsername string) error {
+func (svc StockPickupProductService) DeleteStockPickupProduct(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -265,45 +224,61 @@ func (svc StockPickupProductService) DeleteStockPickupProduct(shopID string, gui
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockPickupProductService) DeleteStockPickupProductByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockPickupProductService) DeleteStockPickupProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockPickupProductService) InfoStockPickupProduct(shopID string, guid string) (models.StockPickupProductInfo, error) {
+func (svc StockPickupProductService) InfoStockPickupProduct(ctx context.Context, shopID string, guid string) (models.StockPickupProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -319,9 +294,9 @@ func (svc StockPickupProductService) InfoStockPickupProduct(shopID string, guid
 	return findDoc.StockPickupProductInfo, nil
 }
 
-func (svc StockPickupProductService) InfoStockPickupProductByCode(shopID string, code string) (models.StockPickupProductInfo, error) {
+func (svc StockPickupProductService) InfoStockPickupProductByCode(ctx context.Context, shopID string, code string) (models.StockPickupProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -337,9 +312,9 @@ func (svc StockPickupProductService) InfoStockPickupProductByCode(shopID string,
 	return findDoc.StockPickupProductInfo, nil
 }
 
-func (svc StockPickupProductService) SearchStockPickupProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockPickupProductInfo, mongopagination.PaginationData, error) {
+func (svc StockPickupProductService) SearchStockPickupProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockPickupProductInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -355,9 +330,9 @@ func (svc StockPickupProductService) SearchStockPickupProduct(shopID string, fil
 	return docList, pagination, nil
 }
 
-func (svc StockPickupProductService) SearchStockPickupProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockPickupProductInfo, int, error) {
+func (svc StockPickupProductService) SearchStockPickupProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockPickupProductInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -375,9 +350,9 @@ func (svc StockPickupProductService) SearchStockPickupProductStep(shopID string,
 	return docList, total, nil
 }
 
-func (svc StockPickupProductService) SaveInBatch(shopID string, authUsername string, dataList []models.StockPickupProduct) (common.BulkImport, error) {
+func (svc StockPickupProductService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockPickupProduct) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockPickupProduct](dataList, svc.getDocIDKey)
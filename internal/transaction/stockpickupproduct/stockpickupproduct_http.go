@@ -6,8 +6,9 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/models"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/repositories"
 	"smlaicloudplatform/internal/transaction/stockpickupproduct/services"
@@ -30,13 +31,13 @@ func NewStockPickupProductHttp(ms *microservice.Microservice, cfg config.IConfig
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockPickupProductRepository(pst)
-	repoMq := repositories.NewStockPickupProductMessageQueueRepository(producer)
+	repoMq := repositories.NewStockPickupProductOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trans_cache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewStockPickupProductService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockPickupProductParser{})
+	svc := services.NewStockPickupProductService(repo, docNoSequencer, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockPickupProductParser{})
 
 	return StockPickupProductHttp{
 		ms:  ms,
@@ -86,7 +87,7 @@ func (h StockPickupProductHttp) CreateStockPickupProduct(ctx microservice.IConte
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateStockPickupProduct(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateStockPickupProduct(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -132,7 +133,7 @@ func (h StockPickupProductHttp) UpdateStockPickupProduct(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.UpdateStockPickupProduct(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockPickupProduct(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -163,7 +164,7 @@ func (h StockPickupProductHttp) DeleteStockPickupProduct(ctx microservice.IConte
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockPickupProduct(shopID, id, authUsername)
+	err := h.svc.DeleteStockPickupProduct(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -202,7 +203,7 @@ func (h StockPickupProductHttp) DeleteStockPickupProductByGUIDs(ctx microservice
 		return err
 	}
 
-	err = h.svc.DeleteStockPickupProductByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockPickupProductByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -232,7 +233,7 @@ func (h StockPickupProductHttp) InfoStockPickupProduct(ctx microservice.IContext
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockPickupProduct %v", id)
-	doc, err := h.svc.InfoStockPickupProduct(shopID, id)
+	doc, err := h.svc.InfoStockPickupProduct(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -262,7 +263,7 @@ func (h StockPickupProductHttp) InfoStockPickupProductByCode(ctx microservice.IC
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockPickupProductByCode(shopID, code)
+	doc, err := h.svc.InfoStockPickupProductByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -314,7 +315,7 @@ func (h StockPickupProductHttp) SearchStockPickupProductPage(ctx microservice.IC
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockPickupProduct(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockPickupProduct(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -370,7 +371,7 @@ func (h StockPickupProductHttp) SearchStockPickupProductStep(ctx microservice.IC
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockPickupProductStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockPickupProductStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -410,7 +411,7 @@ func (h StockPickupProductHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
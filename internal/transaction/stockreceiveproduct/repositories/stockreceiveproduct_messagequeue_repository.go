@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/config"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/models"
@@ -14,6 +15,11 @@ type IStockReceiveProductMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockReceiveProductDoc) error
 	UpdateInBatch(docList []models.StockReceiveProductDoc) error
 	DeleteInBatch(docList []models.StockReceiveProductDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockReceiveProductDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockReceiveProductDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockReceiveProductDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockReceiveProductDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockReceiveProductDoc) error
 }
 
 type StockReceiveProductMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockReceiveProductMessageQueueRepository(prod microservice.IProducer) S
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockReceiveProductDoc](prod, config.StockReceiveProductMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockReceiveProductOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockReceiveProductOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockReceiveProductMessageQueueRepository {
+	insRepo := NewStockReceiveProductMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,7 @@ type IStockReceiveProductRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReceiveProductActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockReceiveProductDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type StockReceiveProductRepository struct {
@@ -59,6 +60,10 @@ func NewStockReceiveProductRepository(pst microservice.IPersisterMongo) *StockRe
 	return insRepo
 }
 
+func (repo StockReceiveProductRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo StockReceiveProductRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockReceiveProductDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
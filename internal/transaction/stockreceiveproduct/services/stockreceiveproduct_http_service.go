@@ -7,14 +7,13 @@ import (
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
 	"smlaicloudplatform/internal/services"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/models"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -22,15 +21,15 @@ import (
 )
 
 type IStockReceiveProductHttpService interface {
-	CreateStockReceiveProduct(shopID string, authUsername string, doc models.StockReceiveProduct) (string, string, error)
-	UpdateStockReceiveProduct(shopID string, guid string, authUsername string, doc models.StockReceiveProduct) error
-	DeleteStockReceiveProduct(shopID string, guid string, authUsername string) error
-	DeleteStockReceiveProductByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockReceiveProduct(shopID string, guid string) (models.StockReceiveProductInfo, error)
-	InfoStockReceiveProductByCode(shopID string, code string) (models.StockReceiveProductInfo, error)
-	SearchStockReceiveProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReceiveProductInfo, mongopagination.PaginationData, error)
-	SearchStockReceiveProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReceiveProductInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockReceiveProduct) (common.BulkImport, error)
+	CreateStockReceiveProduct(ctx context.Context, shopID string, authUsername string, doc models.StockReceiveProduct) (string, string, error)
+	UpdateStockReceiveProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockReceiveProduct) error
+	DeleteStockReceiveProduct(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockReceiveProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockReceiveProduct(ctx context.Context, shopID string, guid string) (models.StockReceiveProductInfo, error)
+	InfoStockReceiveProductByCode(ctx context.Context, shopID string, code string) (models.StockReceiveProductInfo, error)
+	SearchStockReceiveProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReceiveProductInfo, mongopagination.PaginationData, error)
+	SearchStockReceiveProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReceiveProductInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockReceiveProduct) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -40,18 +39,17 @@ const (
 )
 
 type StockReceiveProductHttpService struct {
-	repoMq           repositories.IStockReceiveProductMessageQueueRepository
-	repo             repositories.IStockReceiveProductRepository
-	repoCache        trancache.ICacheRepository
-	cacheExpireDocNo time.Duration
-	syncCacheRepo    mastersync.IMasterSyncCacheRepository
+	repoMq         repositories.IStockReceiveProductMessageQueueRepository
+	repo           repositories.IStockReceiveProductRepository
+	docNoSequencer docsequence.IDocNoSequencer
+	syncCacheRepo  mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockReceiveProductActivity, models.StockReceiveProductDeleteActivity]
 	contextTimeout time.Duration
 }
 
 func NewStockReceiveProductHttpService(
 	repo repositories.IStockReceiveProductRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
 	repoMq repositories.IStockReceiveProductMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
 ) *StockReceiveProductHttpService {
@@ -59,12 +57,11 @@ func NewStockReceiveProductHttpService(
 	contextTimeout := time.Duration(15) * time.Second
 
 	insSvc := &StockReceiveProductHttpService{
-		repoMq:           repoMq,
-		repo:             repo,
-		repoCache:        repoCache,
-		syncCacheRepo:    syncCacheRepo,
-		cacheExpireDocNo: time.Hour * 24,
-		contextTimeout:   contextTimeout,
+		repoMq:         repoMq,
+		repo:           repo,
+		docNoSequencer: docNoSequencer,
+		syncCacheRepo:  syncCacheRepo,
+		contextTimeout: contextTimeout,
 	}
 
 	insSvc.ActivityService = services.NewActivityService[models.StockReceiveProductActivity, models.StockReceiveProductDeleteActivity](repo)
@@ -72,65 +69,15 @@ func NewStockReceiveProductHttpService(
 	return insSvc
 }
 
-func (svc StockReceiveProductHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockReceiveProductHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockReceiveProductHttpService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockReceiveProductHttpService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-func (svc StockReceiveProductHttpService) CreateStockReceiveProduct(shopID string, authUsername string, doc models.StockReceiveProduct) (string, string, error) {
+func (svc StockReceiveProductHttpService) CreateStockReceiveProduct(ctx context.Context, shopID string, authUsername string, doc models.StockReceiveProduct) (string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	docData := models.StockReceiveProductDoc{}
@@ -138,28 +85,35 @@ func (svc StockReceiveProductHttpService) CreateStockReceiveProduct(shopID strin
 	docData.GuidFixed = newGuidFixed
 	docData.StockReceiveProduct = doc
 
-	docData.DocNo = newDocNo
 	docData.CreatedBy = authUsername
 	docData.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, docData)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		docData.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, docData)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, docData)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		svc.repoMq.Create(docData)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return newGuidFixed, newDocNo, nil
 }
 
-func (svc StockReceiveProductHttpService) UpdateStockReceiveProduct(shopID string, guid string, authUsername string, doc models.StockReceiveProduct) error {
+func (svc StockReceiveProductHttpService) UpdateStockReceiveProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockReceiveProduct) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -179,23 +133,29 @@ func (svc StockReceiveProductHttpService) UpdateStockReceiveProduct(shopID strin
 	docData.UpdatedBy = authUsername
 	docData.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, docData)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, docData)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, docData)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(docData)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReceiveProductHttpService) DeleteStockReceiveProduct(shopID string, guid string, authUsername string) error {
+func (svc StockReceiveProductHttpService) DeleteStockReceiveProduct(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -208,45 +168,61 @@ func (svc StockReceiveProductHttpService) DeleteStockReceiveProduct(shopID strin
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReceiveProductHttpService) DeleteStockReceiveProductByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockReceiveProductHttpService) DeleteStockReceiveProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReceiveProductHttpService) InfoStockReceiveProduct(shopID string, guid string) (models.StockReceiveProductInfo, error) {
+func (svc StockReceiveProductHttpService) InfoStockReceiveProduct(ctx context.Context, shopID string, guid string) (models.StockReceiveProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -262,9 +238,9 @@ func (svc StockReceiveProductHttpService) InfoStockReceiveProduct(shopID string,
 	return findDoc.StockReceiveProductInfo, nil
 }
 
-func (svc StockReceiveProductHttpService) InfoStockReceiveProductByCode(shopID string, code string) (models.StockReceiveProductInfo, error) {
+func (svc StockReceiveProductHttpService) InfoStockReceiveProductByCode(ctx context.Context, shopID string, code string) (models.StockReceiveProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -280,9 +256,9 @@ func (svc StockReceiveProductHttpService) InfoStockReceiveProductByCode(shopID s
 	return findDoc.StockReceiveProductInfo, nil
 }
 
-func (svc StockReceiveProductHttpService) SearchStockReceiveProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReceiveProductInfo, mongopagination.PaginationData, error) {
+func (svc StockReceiveProductHttpService) SearchStockReceiveProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReceiveProductInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -298,9 +274,9 @@ func (svc StockReceiveProductHttpService) SearchStockReceiveProduct(shopID strin
 	return docList, pagination, nil
 }
 
-func (svc StockReceiveProductHttpService) SearchStockReceiveProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReceiveProductInfo, int, error) {
+func (svc StockReceiveProductHttpService) SearchStockReceiveProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReceiveProductInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -318,9 +294,9 @@ func (svc StockReceiveProductHttpService) SearchStockReceiveProductStep(shopID s
 	return docList, total, nil
 }
 
-func (svc StockReceiveProductHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.StockReceiveProduct) (common.BulkImport, error) {
+func (svc StockReceiveProductHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockReceiveProduct) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockReceiveProduct](dataList, svc.getDocIDKey)
@@ -6,7 +6,8 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/outbox"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/models"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/repositories"
 	"smlaicloudplatform/internal/transaction/stockreceiveproduct/services"
@@ -29,11 +30,11 @@ func NewStockReceiveProductHttp(ms *microservice.Microservice, cfg config.IConfi
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockReceiveProductRepository(pst)
-	repoMq := repositories.NewStockReceiveProductMessageQueueRepository(producer)
+	repoMq := repositories.NewStockReceiveProductOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
-	transRepo := trancache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewStockReceiveProductHttpService(repo, transRepo, repoMq, masterSyncCacheRepo)
+	svc := services.NewStockReceiveProductHttpService(repo, docNoSequencer, repoMq, masterSyncCacheRepo)
 
 	return StockReceiveProductHttp{
 		ms:  ms,
@@ -83,7 +84,7 @@ func (h StockReceiveProductHttp) CreateStockReceiveProduct(ctx microservice.ICon
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateStockReceiveProduct(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateStockReceiveProduct(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -129,7 +130,7 @@ func (h StockReceiveProductHttp) UpdateStockReceiveProduct(ctx microservice.ICon
 		return err
 	}
 
-	err = h.svc.UpdateStockReceiveProduct(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockReceiveProduct(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -160,7 +161,7 @@ func (h StockReceiveProductHttp) DeleteStockReceiveProduct(ctx microservice.ICon
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockReceiveProduct(shopID, id, authUsername)
+	err := h.svc.DeleteStockReceiveProduct(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -199,7 +200,7 @@ func (h StockReceiveProductHttp) DeleteStockReceiveProductByGUIDs(ctx microservi
 		return err
 	}
 
-	err = h.svc.DeleteStockReceiveProductByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockReceiveProductByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -229,7 +230,7 @@ func (h StockReceiveProductHttp) InfoStockReceiveProduct(ctx microservice.IConte
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockReceiveProduct %v", id)
-	doc, err := h.svc.InfoStockReceiveProduct(shopID, id)
+	doc, err := h.svc.InfoStockReceiveProduct(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -259,7 +260,7 @@ func (h StockReceiveProductHttp) InfoStockReceiveProductByCode(ctx microservice.
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockReceiveProductByCode(shopID, code)
+	doc, err := h.svc.InfoStockReceiveProductByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -311,7 +312,7 @@ func (h StockReceiveProductHttp) SearchStockReceiveProductPage(ctx microservice.
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockReceiveProduct(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockReceiveProduct(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -367,7 +368,7 @@ func (h StockReceiveProductHttp) SearchStockReceiveProductStep(ctx microservice.
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockReceiveProductStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockReceiveProductStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -407,7 +408,7 @@ func (h StockReceiveProductHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/config"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/models"
@@ -14,6 +15,11 @@ type IStockReturnProductMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockReturnProductDoc) error
 	UpdateInBatch(docList []models.StockReturnProductDoc) error
 	DeleteInBatch(docList []models.StockReturnProductDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockReturnProductDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockReturnProductDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockReturnProductDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockReturnProductDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockReturnProductDoc) error
 }
 
 type StockReturnProductMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockReturnProductMessageQueueRepository(prod microservice.IProducer) St
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockReturnProductDoc](prod, config.StockReturnProductMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockReturnProductOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockReturnProductOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockReturnProductMessageQueueRepository {
+	insRepo := NewStockReturnProductMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
@@ -35,6 +35,7 @@ type IStockReturnProductRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReturnProductActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockReturnProductDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type StockReturnProductRepository struct {
@@ -59,6 +60,10 @@ func NewStockReturnProductRepository(pst microservice.IPersisterMongo) *StockRet
 	return insRepo
 }
 
+func (repo StockReturnProductRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo StockReturnProductRepository) FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockReturnProductDoc, error) {
 	filters := bson.M{
 		"shopid": shopID,
@@ -9,15 +9,14 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/models"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +24,15 @@ import (
 )
 
 type IStockReturnProductService interface {
-	CreateStockReturnProduct(shopID string, authUsername string, doc models.StockReturnProduct) (string, string, error)
-	UpdateStockReturnProduct(shopID string, guid string, authUsername string, doc models.StockReturnProduct) error
-	DeleteStockReturnProduct(shopID string, guid string, authUsername string) error
-	DeleteStockReturnProductByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockReturnProduct(shopID string, guid string) (models.StockReturnProductInfo, error)
-	InfoStockReturnProductByCode(shopID string, code string) (models.StockReturnProductInfo, error)
-	SearchStockReturnProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReturnProductInfo, mongopagination.PaginationData, error)
-	SearchStockReturnProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReturnProductInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockReturnProduct) (common.BulkImport, error)
+	CreateStockReturnProduct(ctx context.Context, shopID string, authUsername string, doc models.StockReturnProduct) (string, string, error)
+	UpdateStockReturnProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockReturnProduct) error
+	DeleteStockReturnProduct(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockReturnProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockReturnProduct(ctx context.Context, shopID string, guid string) (models.StockReturnProductInfo, error)
+	InfoStockReturnProductByCode(ctx context.Context, shopID string, code string) (models.StockReturnProductInfo, error)
+	SearchStockReturnProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReturnProductInfo, mongopagination.PaginationData, error)
+	SearchStockReturnProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReturnProductInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockReturnProduct) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -49,9 +48,8 @@ const (
 type StockReturnProductService struct {
 	repoMq             repositories.IStockReturnProductMessageQueueRepository
 	repo               repositories.IStockReturnProductRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockReturnProductActivity, models.StockReturnProductDeleteActivity]
 	parser         IStockReturnProductParser
@@ -60,7 +58,7 @@ type StockReturnProductService struct {
 
 func NewStockReturnProductService(
 	repo repositories.IStockReturnProductRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IStockReturnProductMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +70,10 @@ func NewStockReturnProductService(
 	insSvc := &StockReturnProductService{
 		repoMq:             repoMq,
 		repo:               repo,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +82,15 @@ func NewStockReturnProductService(
 	return insSvc
 }
 
-func (svc StockReturnProductService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockReturnProductService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockReturnProductService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockReturnProductService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
-
-func (svc StockReturnProductService) CreateStockReturnProduct(shopID string, authUsername string, doc models.StockReturnProduct) (string, string, error) {
+func (svc StockReturnProductService) CreateStockReturnProduct(ctx context.Context, shopID string, authUsername string, doc models.StockReturnProduct) (string, string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.StockReturnProductDoc{}
@@ -160,21 +106,26 @@ func (svc StockReturnProductService) CreateStockReturnProduct(shopID string, aut
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		dataDoc.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
-	go svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
-
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -209,9 +160,9 @@ func (svc StockReturnProductService) PrepareDetail(details []trans_models.Detail
 	return details
 }
 
-func (svc StockReturnProductService) UpdateStockReturnProduct(shopID string, guid string, authUsername string, doc models.StockReturnProduct) error {
+func (svc StockReturnProductService) UpdateStockReturnProduct(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockReturnProduct) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -239,23 +190,29 @@ func (svc StockReturnProductService) UpdateStockReturnProduct(shopID string, gui
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReturnProductService) DeleteStockReturnProduct(shopID string, guid string, authUsername string) error {
+func (svc StockReturnProductService) DeleteStockReturnProduct(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -268,45 +225,61 @@ func (svc StockReturnProductService) DeleteStockReturnProduct(shopID string, gui
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReturnProductService) DeleteStockReturnProductByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockReturnProductService) DeleteStockReturnProductByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockReturnProductService) InfoStockReturnProduct(shopID string, guid string) (models.StockReturnProductInfo, error) {
+func (svc StockReturnProductService) InfoStockReturnProduct(ctx context.Context, shopID string, guid string) (models.StockReturnProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -322,9 +295,9 @@ func (svc StockReturnProductService) InfoStockReturnProduct(shopID string, guid
 	return findDoc.StockReturnProductInfo, nil
 }
 
-func (svc StockReturnProductService) InfoStockReturnProductByCode(shopID string, code string) (models.StockReturnProductInfo, error) {
+func (svc StockReturnProductService) InfoStockReturnProductByCode(ctx context.Context, shopID string, code string) (models.StockReturnProductInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -340,9 +313,9 @@ func (svc StockReturnProductService) InfoStockReturnProductByCode(shopID string,
 	return findDoc.StockReturnProductInfo, nil
 }
 
-func (svc StockReturnProductService) SearchStockReturnProduct(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReturnProductInfo, mongopagination.PaginationData, error) {
+func (svc StockReturnProductService) SearchStockReturnProduct(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockReturnProductInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -358,9 +331,9 @@ func (svc StockReturnProductService) SearchStockReturnProduct(shopID string, fil
 	return docList, pagination, nil
 }
 
-func (svc StockReturnProductService) SearchStockReturnProductStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReturnProductInfo, int, error) {
+func (svc StockReturnProductService) SearchStockReturnProductStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockReturnProductInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -378,9 +351,9 @@ func (svc StockReturnProductService) SearchStockReturnProductStep(shopID string,
 	return docList, total, nil
 }
 
-func (svc StockReturnProductService) SaveInBatch(shopID string, authUsername string, dataList []models.StockReturnProduct) (common.BulkImport, error) {
+func (svc StockReturnProductService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockReturnProduct) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockReturnProduct](dataList, svc.getDocIDKey)
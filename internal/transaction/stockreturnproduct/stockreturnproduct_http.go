@@ -6,8 +6,9 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/models"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/repositories"
 	"smlaicloudplatform/internal/transaction/stockreturnproduct/services"
@@ -30,13 +31,13 @@ func NewStockReturnProductHttp(ms *microservice.Microservice, cfg config.IConfig
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockReturnProductRepository(pst)
-	repoMq := repositories.NewStockReturnProductMessageQueueRepository(producer)
+	repoMq := repositories.NewStockReturnProductOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trans_cache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewStockReturnProductService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockReturnProductParser{})
+	svc := services.NewStockReturnProductService(repo, docNoSequencer, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockReturnProductParser{})
 
 	return StockReturnProductHttp{
 		ms:  ms,
@@ -86,7 +87,7 @@ func (h StockReturnProductHttp) CreateStockReturnProduct(ctx microservice.IConte
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateStockReturnProduct(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateStockReturnProduct(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -132,7 +133,7 @@ func (h StockReturnProductHttp) UpdateStockReturnProduct(ctx microservice.IConte
 		return err
 	}
 
-	err = h.svc.UpdateStockReturnProduct(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockReturnProduct(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -163,7 +164,7 @@ func (h StockReturnProductHttp) DeleteStockReturnProduct(ctx microservice.IConte
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockReturnProduct(shopID, id, authUsername)
+	err := h.svc.DeleteStockReturnProduct(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -202,7 +203,7 @@ func (h StockReturnProductHttp) DeleteStockReturnProductByGUIDs(ctx microservice
 		return err
 	}
 
-	err = h.svc.DeleteStockReturnProductByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockReturnProductByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -232,7 +233,7 @@ func (h StockReturnProductHttp) InfoStockReturnProduct(ctx microservice.IContext
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockReturnProduct %v", id)
-	doc, err := h.svc.InfoStockReturnProduct(shopID, id)
+	doc, err := h.svc.InfoStockReturnProduct(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -262,7 +263,7 @@ func (h StockReturnProductHttp) InfoStockReturnProductByCode(ctx microservice.IC
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockReturnProductByCode(shopID, code)
+	doc, err := h.svc.InfoStockReturnProductByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -314,7 +315,7 @@ func (h StockReturnProductHttp) SearchStockReturnProductPage(ctx microservice.IC
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockReturnProduct(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockReturnProduct(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -370,7 +371,7 @@ func (h StockReturnProductHttp) SearchStockReturnProductStep(ctx microservice.IC
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockReturnProductStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockReturnProductStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -410,7 +411,7 @@ func (h StockReturnProductHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"smlaicloudplatform/internal/repositories"
 	"smlaicloudplatform/internal/transaction/stocktransfer/config"
 	"smlaicloudplatform/internal/transaction/stocktransfer/models"
@@ -14,6 +15,11 @@ type IStockTransferMessageQueueRepository interface {
 	CreateInBatch(docList []models.StockTransferDoc) error
 	UpdateInBatch(docList []models.StockTransferDoc) error
 	DeleteInBatch(docList []models.StockTransferDoc) error
+	OutboxCreate(ctx context.Context, doc models.StockTransferDoc) error
+	OutboxUpdate(ctx context.Context, doc models.StockTransferDoc) error
+	OutboxDelete(ctx context.Context, doc models.StockTransferDoc) error
+	OutboxCreateInBatch(ctx context.Context, docList []models.StockTransferDoc) error
+	OutboxDeleteInBatch(ctx context.Context, docList []models.StockTransferDoc) error
 }
 
 type StockTransferMessageQueueRepository struct {
@@ -32,3 +38,10 @@ func NewStockTransferMessageQueueRepository(prod microservice.IProducer) StockTr
 	insRepo.KafkaRepository = repositories.NewKafkaRepository[models.StockTransferDoc](prod, config.StockTransferMessageQueueConfig{}, "")
 	return insRepo
 }
+
+// NewStockTransferOutboxMessageQueueRepository return repository which write events into outbox in the same transaction of the document
+func NewStockTransferOutboxMessageQueueRepository(prod microservice.IProducer, outbox repositories.IOutboxWriter) StockTransferMessageQueueRepository {
+	insRepo := NewStockTransferMessageQueueRepository(prod)
+	insRepo.KafkaRepository = insRepo.KafkaRepository.WithOutbox(outbox)
+	return insRepo
+}
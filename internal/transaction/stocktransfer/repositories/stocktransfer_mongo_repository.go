@@ -36,6 +36,7 @@ type IStockTransferRepository interface {
 	FindCreatedOrUpdatedStep(ctx context.Context, shopID string, lastUpdatedDate time.Time, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockTransferActivity, error)
 
 	FindLastDocNo(ctx context.Context, shopID string, prefixDocNo string) (models.StockTransferDoc, error)
+	Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error
 }
 
 type StockTransferRepository struct {
@@ -60,6 +61,10 @@ func NewStockTransferRepository(pst microservice.IPersisterMongo) *StockTransfer
 	return insRepo
 }
 
+func (repo StockTransferRepository) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return repo.pst.Transaction(ctx, queryFunc)
+}
+
 func (repo StockTransferRepository) FindDocOne(ctx context.Context, shopID, docno string, transFlag int) (models.StockTransferDoc, error) {
 	doc := models.StockTransferDoc{}
 	err := repo.pst.FindOne(ctx, models.StockTransferDoc{}, bson.M{"shopid": shopID, "docno": docno, "transflag": transFlag}, &doc)
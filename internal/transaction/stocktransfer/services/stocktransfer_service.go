@@ -9,15 +9,14 @@ import (
 	productbarcode_models "smlaicloudplatform/internal/product/productbarcode/models"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
 	"smlaicloudplatform/internal/services"
+	docsequence "smlaicloudplatform/internal/transaction/docsequence/services"
 	trans_models "smlaicloudplatform/internal/transaction/models"
-	trancache "smlaicloudplatform/internal/transaction/repositories"
 	"smlaicloudplatform/internal/transaction/stocktransfer/models"
 	"smlaicloudplatform/internal/transaction/stocktransfer/repositories"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/smlsoft/mongopagination"
@@ -25,15 +24,15 @@ import (
 )
 
 type IStockTransferService interface {
-	CreateStockTransfer(shopID string, authUsername string, doc models.StockTransfer) (string, string, error)
-	UpdateStockTransfer(shopID string, guid string, authUsername string, doc models.StockTransfer) error
-	DeleteStockTransfer(shopID string, guid string, authUsername string) error
-	DeleteStockTransferByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoStockTransfer(shopID string, guid string) (models.StockTransferInfo, error)
-	InfoStockTransferByCode(shopID string, code string) (models.StockTransferInfo, error)
-	SearchStockTransfer(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockTransferInfo, mongopagination.PaginationData, error)
-	SearchStockTransferStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockTransferInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.StockTransfer) (common.BulkImport, error)
+	CreateStockTransfer(ctx context.Context, shopID string, authUsername string, doc models.StockTransfer) (string, string, error)
+	UpdateStockTransfer(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockTransfer) error
+	DeleteStockTransfer(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteStockTransferByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoStockTransfer(ctx context.Context, shopID string, guid string) (models.StockTransferInfo, error)
+	InfoStockTransferByCode(ctx context.Context, shopID string, code string) (models.StockTransferInfo, error)
+	SearchStockTransfer(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockTransferInfo, mongopagination.PaginationData, error)
+	SearchStockTransferStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockTransferInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockTransfer) (common.BulkImport, error)
 
 	GetModuleName() string
 }
@@ -49,9 +48,8 @@ const (
 type StockTransferService struct {
 	repoMq             repositories.IStockTransferMessageQueueRepository
 	repo               repositories.IStockTransferRepository
-	repoCache          trancache.ICacheRepository
+	docNoSequencer     docsequence.IDocNoSequencer
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository
-	cacheExpireDocNo   time.Duration
 	syncCacheRepo      mastersync.IMasterSyncCacheRepository
 	services.ActivityService[models.StockTransferActivity, models.StockTransferDeleteActivity]
 	parser         IStockTransferParser
@@ -60,7 +58,7 @@ type StockTransferService struct {
 
 func NewStockTransferService(
 	repo repositories.IStockTransferRepository,
-	repoCache trancache.ICacheRepository,
+	docNoSequencer docsequence.IDocNoSequencer,
 	productbarcodeRepo productbarcode_repositories.IProductBarcodeRepository,
 	repoMq repositories.IStockTransferMessageQueueRepository,
 	syncCacheRepo mastersync.IMasterSyncCacheRepository,
@@ -72,11 +70,10 @@ func NewStockTransferService(
 	insSvc := &StockTransferService{
 		repoMq:             repoMq,
 		repo:               repo,
-		repoCache:          repoCache,
+		docNoSequencer:     docNoSequencer,
 		productbarcodeRepo: productbarcodeRepo,
 		syncCacheRepo:      syncCacheRepo,
 		parser:             parser,
-		cacheExpireDocNo:   time.Hour * 24,
 		contextTimeout:     contextTimeout,
 	}
 
@@ -85,66 +82,15 @@ func NewStockTransferService(
 	return insSvc
 }
 
-func (svc StockTransferService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc StockTransferService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc StockTransferService) getDocNoPrefix(docDate time.Time) string {
-	docDateStr := docDate.Format("20060102")
-	return fmt.Sprintf("%s%s", MODULE_NAME, docDateStr)
-}
-
-func (svc StockTransferService) generateNewDocNo(ctx context.Context, shopID, prefixDocNo string, docNumber int) (string, int, error) {
-	prevoiusDocNumber, err := svc.repoCache.Get(shopID, prefixDocNo)
-
-	if prevoiusDocNumber == 0 || err != nil {
-		lastDoc, err := svc.repo.FindLastDocNo(ctx, shopID, prefixDocNo)
-
-		if err != nil {
-			return "", 0, err
-		}
-
-		if len(lastDoc.DocNo) > 0 {
-			rawNumber := strings.Replace(lastDoc.DocNo, prefixDocNo, "", -1)
-			prevoiusDocNumber, err = strconv.Atoi(rawNumber)
-
-			if err != nil {
-				prevoiusDocNumber = 0
-			}
-		}
-
-	}
-
-	newDocNumber := prevoiusDocNumber + 1
-	newDocNo := fmt.Sprintf("%s%05d", prefixDocNo, newDocNumber)
-
-	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", newDocNo)
-
-	if err != nil {
-		return "", 0, err
-	}
-
-	if len(findDoc.GuidFixed) > 0 {
-		return "", 0, errors.New("DocNo is exists")
-	}
-
-	return newDocNo, newDocNumber, nil
-}
+func (svc StockTransferService) CreateStockTransfer(ctx context.Context, shopID string, authUsername string, doc models.StockTransfer) (string, string, error) {
 
-func (svc StockTransferService) CreateStockTransfer(shopID string, authUsername string, doc models.StockTransfer) (string, string, error) {
-
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
-	docDate := doc.DocDatetime
-	prefixDocNo := svc.getDocNoPrefix(docDate)
-
-	newDocNo, newDocNumber, err := svc.generateNewDocNo(ctx, shopID, prefixDocNo, 1)
-
-	if err != nil {
-		return "", "", err
-	}
-
 	newGuidFixed := utils.NewGUID()
 
 	dataDoc := models.StockTransferDoc{}
@@ -160,19 +106,26 @@ func (svc StockTransferService) CreateStockTransfer(shopID string, authUsername
 	details := svc.PrepareDetail(*doc.Details, productBarcodes)
 	dataDoc.Details = &details
 
-	dataDoc.DocNo = newDocNo
 	dataDoc.CreatedBy = authUsername
 	dataDoc.CreatedAt = time.Now()
 
-	_, err = svc.repo.Create(ctx, dataDoc)
+	newDocNo, err := svc.docNoSequencer.SaveWithDocNo(ctx, shopID, MODULE_NAME, doc.DocDatetime, authUsername, doc.DocNo, func(docNo string) error {
+		dataDoc.DocNo = docNo
+		return svc.repo.Transaction(ctx, func(ctx context.Context) error {
+			_, err := svc.repo.Create(ctx, dataDoc)
+			if err != nil {
+				return err
+			}
+
+			return svc.repoMq.OutboxCreate(ctx, dataDoc)
+		})
+	})
 
 	if err != nil {
 		return "", "", err
 	}
 
 	go func() {
-		svc.repoMq.Create(dataDoc)
-		svc.repoCache.Save(shopID, prefixDocNo, newDocNumber, svc.cacheExpireDocNo)
 		svc.saveMasterSync(shopID)
 	}()
 
@@ -207,9 +160,9 @@ func (svc StockTransferService) PrepareDetail(details []trans_models.Detail, pro
 	return details
 }
 
-func (svc StockTransferService) UpdateStockTransfer(shopID string, guid string, authUsername string, doc models.StockTransfer) error {
+func (svc StockTransferService) UpdateStockTransfer(ctx context.Context, shopID string, guid string, authUsername string, doc models.StockTransfer) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -247,23 +200,29 @@ func (svc StockTransferService) UpdateStockTransfer(shopID string, guid string,
 	dataDoc.UpdatedBy = authUsername
 	dataDoc.UpdatedAt = time.Now()
 
-	err = svc.repo.Update(ctx, shopID, guid, dataDoc)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, shopID, guid, dataDoc)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxUpdate(ctx, dataDoc)
+	})
 
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Update(dataDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockTransferService) DeleteStockTransfer(shopID string, guid string, authUsername string) error {
+func (svc StockTransferService) DeleteStockTransfer(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -276,45 +235,61 @@ func (svc StockTransferService) DeleteStockTransfer(shopID string, guid string,
 		return errors.New("document not found")
 	}
 
-	err = svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+	err = svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDelete(ctx, findDoc)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		svc.repoMq.Delete(findDoc)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockTransferService) DeleteStockTransferByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc StockTransferService) DeleteStockTransferByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
 		"guidfixed": bson.M{"$in": GUIDs},
 	}
 
-	err := svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+	err := svc.repo.Transaction(ctx, func(ctx context.Context) error {
+		docs, err := svc.repo.FindByGuids(ctx, shopID, GUIDs)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.Delete(ctx, shopID, authUsername, deleteFilterQuery)
+		if err != nil {
+			return err
+		}
+
+		return svc.repoMq.OutboxDeleteInBatch(ctx, docs)
+	})
 	if err != nil {
 		return err
 	}
 
 	func() {
-		docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
-		svc.repoMq.DeleteInBatch(docs)
 		svc.saveMasterSync(shopID)
 	}()
 
 	return nil
 }
 
-func (svc StockTransferService) InfoStockTransfer(shopID string, guid string) (models.StockTransferInfo, error) {
+func (svc StockTransferService) InfoStockTransfer(ctx context.Context, shopID string, guid string) (models.StockTransferInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -330,9 +305,9 @@ func (svc StockTransferService) InfoStockTransfer(shopID string, guid string) (m
 	return findDoc.StockTransferInfo, nil
 }
 
-func (svc StockTransferService) InfoStockTransferByCode(shopID string, code string) (models.StockTransferInfo, error) {
+func (svc StockTransferService) InfoStockTransferByCode(ctx context.Context, shopID string, code string) (models.StockTransferInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", code)
@@ -348,9 +323,9 @@ func (svc StockTransferService) InfoStockTransferByCode(shopID string, code stri
 	return findDoc.StockTransferInfo, nil
 }
 
-func (svc StockTransferService) SearchStockTransfer(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockTransferInfo, mongopagination.PaginationData, error) {
+func (svc StockTransferService) SearchStockTransfer(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.StockTransferInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -366,9 +341,9 @@ func (svc StockTransferService) SearchStockTransfer(shopID string, filters map[s
 	return docList, pagination, nil
 }
 
-func (svc StockTransferService) SearchStockTransferStep(shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockTransferInfo, int, error) {
+func (svc StockTransferService) SearchStockTransferStep(ctx context.Context, shopID string, langCode string, filters map[string]interface{}, pageableStep micromodels.PageableStep) ([]models.StockTransferInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -386,9 +361,9 @@ func (svc StockTransferService) SearchStockTransferStep(shopID string, langCode
 	return docList, total, nil
 }
 
-func (svc StockTransferService) SaveInBatch(shopID string, authUsername string, dataList []models.StockTransfer) (common.BulkImport, error) {
+func (svc StockTransferService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.StockTransfer) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.StockTransfer](dataList, svc.getDocIDKey)
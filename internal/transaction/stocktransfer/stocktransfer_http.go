@@ -6,8 +6,9 @@ import (
 	"smlaicloudplatform/internal/config"
 	mastersync "smlaicloudplatform/internal/mastersync/repositories"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/outbox"
 	productbarcode_repositories "smlaicloudplatform/internal/product/productbarcode/repositories"
-	trans_cache "smlaicloudplatform/internal/transaction/repositories"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/stocktransfer/models"
 	"smlaicloudplatform/internal/transaction/stocktransfer/repositories"
 	"smlaicloudplatform/internal/transaction/stocktransfer/services"
@@ -30,13 +31,13 @@ func NewStockTransferHttp(ms *microservice.Microservice, cfg config.IConfig) Sto
 	producer := ms.Producer(cfg.MQConfig())
 
 	repo := repositories.NewStockTransferRepository(pst)
-	repoMq := repositories.NewStockTransferMessageQueueRepository(producer)
+	repoMq := repositories.NewStockTransferOutboxMessageQueueRepository(producer, outbox.NewOutboxRepository(pst))
 
 	productBarcodeRepo := productbarcode_repositories.NewProductBarcodeRepository(pst, cache)
 
-	transCacheRepo := trans_cache.NewCacheRepository(cache)
+	docNoSequencer := docsequence.InitDocNoSequencer(ms, cfg)
 	masterSyncCacheRepo := mastersync.NewMasterSyncCacheRepository(cache)
-	svc := services.NewStockTransferService(repo, transCacheRepo, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockTransferParser{})
+	svc := services.NewStockTransferService(repo, docNoSequencer, productBarcodeRepo, repoMq, masterSyncCacheRepo, services.StockTransferParser{})
 
 	return StockTransferHttp{
 		ms:  ms,
@@ -84,7 +85,7 @@ func (h StockTransferHttp) CreateStockTransfer(ctx microservice.IContext) error
 		return err
 	}
 
-	idx, docNo, err := h.svc.CreateStockTransfer(shopID, authUsername, *docReq)
+	idx, docNo, err := h.svc.CreateStockTransfer(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -130,7 +131,7 @@ func (h StockTransferHttp) UpdateStockTransfer(ctx microservice.IContext) error
 		return err
 	}
 
-	err = h.svc.UpdateStockTransfer(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateStockTransfer(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -161,7 +162,7 @@ func (h StockTransferHttp) DeleteStockTransfer(ctx microservice.IContext) error
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteStockTransfer(shopID, id, authUsername)
+	err := h.svc.DeleteStockTransfer(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -200,7 +201,7 @@ func (h StockTransferHttp) DeleteStockTransferByGUIDs(ctx microservice.IContext)
 		return err
 	}
 
-	err = h.svc.DeleteStockTransferByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteStockTransferByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -230,7 +231,7 @@ func (h StockTransferHttp) InfoStockTransfer(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get StockTransfer %v", id)
-	doc, err := h.svc.InfoStockTransfer(shopID, id)
+	doc, err := h.svc.InfoStockTransfer(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -260,7 +261,7 @@ func (h StockTransferHttp) InfoStockTransferByCode(ctx microservice.IContext) er
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoStockTransferByCode(shopID, code)
+	doc, err := h.svc.InfoStockTransferByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -312,7 +313,7 @@ func (h StockTransferHttp) SearchStockTransferPage(ctx microservice.IContext) er
 		},
 	})
 
-	docList, pagination, err := h.svc.SearchStockTransfer(shopID, filters, pageable)
+	docList, pagination, err := h.svc.SearchStockTransfer(ctx.Context(), shopID, filters, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -368,7 +369,7 @@ func (h StockTransferHttp) SearchStockTransferStep(ctx microservice.IContext) er
 		},
 	})
 
-	docList, total, err := h.svc.SearchStockTransferStep(shopID, lang, filters, pageableStep)
+	docList, total, err := h.svc.SearchStockTransferStep(ctx.Context(), shopID, lang, filters, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
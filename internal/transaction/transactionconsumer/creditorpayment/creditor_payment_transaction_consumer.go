@@ -3,8 +3,10 @@ package creditorpayment
 import (
 	"smlaicloudplatform/internal/config"
 	pkgConfig "smlaicloudplatform/internal/config"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
 	models "smlaicloudplatform/internal/transaction/models"
 	creditorPaymentConfig "smlaicloudplatform/internal/transaction/pay/config"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/usecases"
 	"smlaicloudplatform/pkg/microservice"
@@ -51,6 +53,7 @@ func NewCreditorPaymentTransactionConsumer(
 func (c *CreditorPaymentTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(c.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(c.cfg.MQConfig(), ms.Logger)
 
 	purchaseKafkaConfig := creditorPaymentConfig.CreditorPaymentMessageQueueConfig{}
@@ -62,12 +65,12 @@ func (c *CreditorPaymentTransactionConsumer) RegisterConsumer(ms *microservice.M
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnDelete)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkDelete)
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", c.ConsumeOnDelete))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("creditorpayment", c.ConsumeOnBulkDelete))
 
 }
 
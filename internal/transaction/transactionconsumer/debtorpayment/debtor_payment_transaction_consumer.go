@@ -4,6 +4,7 @@ import (
 	"smlaicloudplatform/internal/config"
 	models "smlaicloudplatform/internal/transaction/models"
 	debtorPaymentConfig "smlaicloudplatform/internal/transaction/paid/config"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/usecases"
 	"smlaicloudplatform/pkg/microservice"
@@ -46,6 +47,7 @@ func NewDebtorPaymentTransactionConsumer(
 func (c *DebtorPaymentTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(c.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(c.cfg.MQConfig(), ms.Logger)
 
 	purchaseKafkaConfig := debtorPaymentConfig.DebtorPaymentMessageQueueConfig{}
@@ -57,12 +59,12 @@ func (c *DebtorPaymentTransactionConsumer) RegisterConsumer(ms *microservice.Mic
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnDelete)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkDelete)
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnCreateOrUpdate))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnCreateOrUpdate))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnDelete))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(c.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("debtorpayment", c.ConsumeOnBulkDelete))
 
 }
 
@@ -13,6 +13,7 @@ func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) err
 		models.StockTransactionDetail{},
 		models.CreditorTransactionPG{},
 		models.DebtorTransactionPG{},
+		models.ConsumerLedgerPG{},
 
 		// models.PurchaseTransactionPG{},
 		// models.PurchaseTransactionDetailPG{},
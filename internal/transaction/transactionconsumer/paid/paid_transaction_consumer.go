@@ -5,6 +5,7 @@ import (
 	pkgConfig "smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/logger"
 	paidConfig "smlaicloudplatform/internal/transaction/paid/config"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
@@ -49,6 +50,7 @@ func InitPaidTransactionConsumer(
 func (t *PaidTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-06")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(t.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(t.cfg.MQConfig(), ms.Logger)
 
 	purchaseKafkaConfig := paidConfig.DebtorPaymentMessageQueueConfig{}
@@ -60,12 +62,12 @@ func (t *PaidTransactionConsumer) RegisterConsumer(ms *microservice.Microservice
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnDelete)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkDelete)
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnDelete))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("paid", t.ConsumeOnBulkDelete))
 
 }
 
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	pkgConfig "smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/logger"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
 	payConfig "smlaicloudplatform/internal/transaction/pay/config"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
@@ -49,6 +51,7 @@ func InitPayTransactionConsumer(
 func (t *PayTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-06")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(t.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(t.cfg.MQConfig(), ms.Logger)
 
 	purchaseKafkaConfig := payConfig.CreditorPaymentMessageQueueConfig{}
@@ -60,12 +63,12 @@ func (t *PayTransactionConsumer) RegisterConsumer(ms *microservice.Microservice)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnDelete)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkDelete)
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", approvalservices.ApprovedOnly(t.ConsumeOnCreateOrUpdate, t.ConsumeOnDelete)))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", approvalservices.ApprovedOnly(t.ConsumeOnCreateOrUpdate, t.ConsumeOnDelete)))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", t.ConsumeOnDelete))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", approvalservices.ApprovedOnly(t.ConsumeOnBulkCreateOrUpdate, t.ConsumeOnBulkDelete)))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", approvalservices.ApprovedOnly(t.ConsumeOnBulkCreateOrUpdate, t.ConsumeOnBulkDelete)))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("pay", t.ConsumeOnBulkDelete))
 
 }
 
@@ -58,7 +58,7 @@ func (repo *PurchaseTransactionPGRepository) DeleteData(shopID string, docNo str
 	tx.Model(&models.PurchaseTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.PurchaseTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.PurchaseTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.PurchaseTransactionPG{}, map[string]interface{}{
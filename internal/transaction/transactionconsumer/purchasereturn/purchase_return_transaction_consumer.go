@@ -7,6 +7,7 @@ import (
 	"smlaicloudplatform/internal/transaction/models"
 	purchaseReturnConfig "smlaicloudplatform/internal/transaction/purchasereturn/config"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/creditortransaction"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/stocktransaction"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/usecases"
@@ -74,6 +75,7 @@ func InitPurchaseReturnTransactionConsumer(ms *microservice.Microservice,
 
 func (t *PurchaseReturnTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(t.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(t.cfg.MQConfig(), ms.Logger)
 
 	purchaseKafkaConfig := purchaseReturnConfig.PurchaseReturnMessageQueueConfig{}
@@ -85,12 +87,12 @@ func (t *PurchaseReturnTransactionConsumer) RegisterConsumer(ms *microservice.Mi
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(purchaseKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnDelete)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkDelete)
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnDelete))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), purchaseKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("purchasereturn", t.ConsumeOnBulkDelete))
 }
 
 func (t *PurchaseReturnTransactionConsumer) ConsumeOnCreateOrUpdate(ctx microservice.IContext) error {
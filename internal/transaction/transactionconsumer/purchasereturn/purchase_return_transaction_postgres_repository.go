@@ -58,7 +58,7 @@ func (repo *PurchaseReturnTransactionPGRepository) DeleteData(shopID string, doc
 	tx.Model(&models.PurchaseReturnTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.PurchaseReturnTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.PurchaseReturnTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.PurchaseReturnTransactionPG{}, map[string]interface{}{
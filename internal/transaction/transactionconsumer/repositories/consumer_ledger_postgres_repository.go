@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+type IConsumerLedgerRepository interface {
+	Claim(consumer string, shopID string, docNo string, version string, staleBefore time.Time) (bool, error)
+	Status(consumer string, shopID string, docNo string, version string) (string, error)
+	Done(consumer string, shopID string, docNo string, version string) error
+	Release(consumer string, shopID string, docNo string, version string) error
+}
+
+type ConsumerLedgerRepository struct {
+	pst microservice.IPersister
+}
+
+func NewConsumerLedgerRepository(pst microservice.IPersister) IConsumerLedgerRepository {
+	return &ConsumerLedgerRepository{
+		pst: pst,
+	}
+}
+
+// Claim insert the message into the ledger before it is handled, the primary key allow only one consumer to claim
+// the message. Claim which is still processing since staleBefore is taken over because its consumer is gone.
+func (repo *ConsumerLedgerRepository) Claim(consumer string, shopID string, docNo string, version string, staleBefore time.Time) (bool, error) {
+	result := repo.pst.DBClient().Exec(
+		`INSERT INTO transaction_consumer_ledger (consumer, shopid, docno, version, status, processedat) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (consumer, shopid, docno, version) DO UPDATE SET processedat = EXCLUDED.processedat
+		WHERE transaction_consumer_ledger.status = ? AND transaction_consumer_ledger.processedat < ?`,
+		consumer, shopID, docNo, version, models.ConsumerLedgerStatusProcessing, time.Now(),
+		models.ConsumerLedgerStatusProcessing, staleBefore,
+	)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *ConsumerLedgerRepository) Status(consumer string, shopID string, docNo string, version string) (string, error) {
+	doc := models.ConsumerLedgerPG{}
+	err := repo.pst.DBClient().
+		Where("consumer=? AND shopid=? AND docno=? AND version=?", consumer, shopID, docNo, version).
+		Limit(1).
+		Find(&doc).Error
+
+	if err != nil {
+		return "", err
+	}
+
+	return doc.Status, nil
+}
+
+func (repo *ConsumerLedgerRepository) Done(consumer string, shopID string, docNo string, version string) error {
+	return repo.pst.Exec(
+		"UPDATE transaction_consumer_ledger SET status=?, processedat=? WHERE consumer=? AND shopid=? AND docno=? AND version=?",
+		models.ConsumerLedgerStatusDone, time.Now(), consumer, shopID, docNo, version,
+	)
+}
+
+// Release remove claim of the message which is failed so the message is handled again when it is delivered again
+func (repo *ConsumerLedgerRepository) Release(consumer string, shopID string, docNo string, version string) error {
+	return repo.pst.Exec(
+		"DELETE FROM transaction_consumer_ledger WHERE consumer=? AND shopid=? AND docno=? AND version=? AND status=?",
+		consumer, shopID, docNo, version, models.ConsumerLedgerStatusProcessing,
+	)
+}
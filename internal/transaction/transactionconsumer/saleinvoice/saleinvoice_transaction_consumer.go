@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/logger"
 	saleInvoiceConfig "smlaicloudplatform/internal/transaction/saleinvoice/config"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/debtortransaction"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/stocktransaction"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/usecases"
@@ -65,6 +66,7 @@ func NewSaleInvoiceTransactionConsumer(
 
 func (t *SaleInvoiceTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(t.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(t.cfg.MQConfig(), ms.Logger)
 	saleInvoiceKafkaConfig := saleInvoiceConfig.SaleInvoiceMessageQueueConfig{}
 
@@ -75,12 +77,12 @@ func (t *SaleInvoiceTransactionConsumer) RegisterConsumer(ms *microservice.Micro
 	mq.CreateTopicR(saleInvoiceKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(saleInvoiceKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnDelete)
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), t.ConsumeOnBulkDelete)
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnDelete))
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(t.cfg.MQConfig().URI(), saleInvoiceKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoice", t.ConsumeOnBulkDelete))
 
 }
 
@@ -172,7 +174,7 @@ func (t *SaleInvoiceTransactionConsumer) ConsumeOnCreateOrUpdate(ctx microservic
 	}
 
 	for _, item := range *transaction.Items {
-		t.productBomService.UpsertBOM(transaction.ShopID, "SYSTEM", transaction.DocNo, item.Barcode)
+		t.productBomService.UpsertBOM(ctx.Context(), transaction.ShopID, "SYSTEM", transaction.DocNo, item.Barcode)
 	}
 
 	return nil
@@ -58,7 +58,7 @@ func (repo *SaleInvoiceTransactionPGRepository) DeleteData(shopID string, docNo
 	tx.Model(&models.SaleInvoiceTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.SaleInvoiceTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.SaleInvoiceTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.SaleInvoiceTransactionPG{}, map[string]interface{}{
@@ -58,7 +58,7 @@ func (repo *SaleInvoiceReturnTransactionPGRepository) DeleteData(shopID string,
 	tx.Model(&models.SaleInvoiceReturnTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.SaleInvoiceReturnTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.SaleInvoiceReturnTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.SaleInvoiceReturnTransactionPG{}, map[string]interface{}{
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+// claimLease is how long a claim of the message is kept for its consumer, claim which is older
+// is taken over by the next delivery because its consumer is gone
+const claimLease = 5 * time.Minute
+
+// IConsumerLedgerService skip transaction messages which already applied by the consumer
+type IConsumerLedgerService interface {
+	Idempotent(consumer string, h microservice.ServiceHandleFunc) microservice.ServiceHandleFunc
+}
+
+type ConsumerLedgerService struct {
+	repo    repositories.IConsumerLedgerRepository
+	timeNow func() time.Time
+}
+
+func NewConsumerLedgerService(repo repositories.IConsumerLedgerRepository) IConsumerLedgerService {
+	return &ConsumerLedgerService{
+		repo:    repo,
+		timeNow: time.Now,
+	}
+}
+
+// ledgerDocIdentity is the document key read from single or bulk transaction message
+type ledgerDocIdentity struct {
+	ShopID string `json:"shopid"`
+	DocNo  string `json:"docno"`
+}
+
+// Idempotent wrap consumer handler so only one delivery of the same message is applied. The message is claimed
+// by shopid/docno/event id (message version of legacy message) in the ledger before the handler run, the claim
+// is marked done when the handler succeed and released when the handler fail so the message is applied when it
+// is delivered again.
+func (svc *ConsumerLedgerService) Idempotent(consumer string, h microservice.ServiceHandleFunc) microservice.ServiceHandleFunc {
+	return func(ctx microservice.IContext) error {
+		msg, ok := microservice.ConsumerMessageFromContext(ctx)
+		if !ok {
+			return h(ctx)
+		}
+
+		shopID, docNo := parseLedgerDocIdentity(ctx.ReadInput())
+		version := msg.Version()
+		if event, ok := microservice.EventFromContext(ctx); ok {
+			// the same event published again by outbox relay has the same event id
+			version = event.EventID
+		}
+
+		claimed, err := svc.repo.Claim(consumer, shopID, docNo, version, svc.timeNow().Add(-claimLease))
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			status, err := svc.repo.Status(consumer, shopID, docNo, version)
+			if err != nil {
+				return err
+			}
+
+			if status == models.ConsumerLedgerStatusDone {
+				logger.GetLogger().Debugf("Consumer %s skip message %s already processed", consumer, version)
+				return nil
+			}
+
+			// fail the delivery so it is retried in case another delivery of the message fail
+			return fmt.Errorf("consumer %s message %s is processing by another delivery", consumer, version)
+		}
+
+		err = h(ctx)
+		if err != nil {
+			if releaseErr := svc.repo.Release(consumer, shopID, docNo, version); releaseErr != nil {
+				logger.GetLogger().Errorf("Consumer %s cannot release message %s : %v", consumer, version, releaseErr)
+			}
+			return err
+		}
+
+		return svc.repo.Done(consumer, shopID, docNo, version)
+	}
+}
+
+// parseLedgerDocIdentity return shopid and docno of the message, bulk message use shopid of the first document and empty docno
+func parseLedgerDocIdentity(input string) (string, string) {
+	doc := ledgerDocIdentity{}
+	if err := json.Unmarshal([]byte(input), &doc); err == nil {
+		return doc.ShopID, doc.DocNo
+	}
+
+	docs := []ledgerDocIdentity{}
+	if err := json.Unmarshal([]byte(input), &docs); err == nil && len(docs) > 0 {
+		return docs[0].ShopID, ""
+	}
+
+	return "", ""
+}
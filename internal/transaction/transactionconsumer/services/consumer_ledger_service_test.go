@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/transaction/models"
+	"smlaicloudplatform/pkg/microservice"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLedgerDocIdentity(t *testing.T) {
+	shopID, docNo := parseLedgerDocIdentity(`{"shopid":"SHOP01","docno":"INV-0001","docdatetime":"2023-01-01T00:00:00Z"}`)
+	assert.Equal(t, "SHOP01", shopID)
+	assert.Equal(t, "INV-0001", docNo)
+
+	shopID, docNo = parseLedgerDocIdentity(`[{"shopid":"SHOP01","docno":"INV-0001"},{"shopid":"SHOP01","docno":"INV-0002"}]`)
+	assert.Equal(t, "SHOP01", shopID)
+	assert.Equal(t, "", docNo)
+
+	shopID, docNo = parseLedgerDocIdentity(`invalid`)
+	assert.Equal(t, "", shopID)
+	assert.Equal(t, "", docNo)
+}
+
+// memoryConsumerLedgerRepository claim the message atomically like the primary key of the ledger table
+type memoryConsumerLedgerRepository struct {
+	mu      sync.Mutex
+	entries map[string]models.ConsumerLedgerPG
+}
+
+func ledgerKey(consumer string, shopID string, docNo string, version string) string {
+	return consumer + "|" + shopID + "|" + docNo + "|" + version
+}
+
+func (repo *memoryConsumerLedgerRepository) Claim(consumer string, shopID string, docNo string, version string, staleBefore time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := ledgerKey(consumer, shopID, docNo, version)
+	entry, ok := repo.entries[key]
+	if ok && (entry.Status != models.ConsumerLedgerStatusProcessing || !entry.ProcessedAt.Before(staleBefore)) {
+		return false, nil
+	}
+
+	repo.entries[key] = models.ConsumerLedgerPG{Status: models.ConsumerLedgerStatusProcessing, ProcessedAt: time.Now()}
+	return true, nil
+}
+
+func (repo *memoryConsumerLedgerRepository) Status(consumer string, shopID string, docNo string, version string) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.entries[ledgerKey(consumer, shopID, docNo, version)].Status, nil
+}
+
+func (repo *memoryConsumerLedgerRepository) Done(consumer string, shopID string, docNo string, version string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := ledgerKey(consumer, shopID, docNo, version)
+	entry := repo.entries[key]
+	entry.Status = models.ConsumerLedgerStatusDone
+	repo.entries[key] = entry
+	return nil
+}
+
+func (repo *memoryConsumerLedgerRepository) Release(consumer string, shopID string, docNo string, version string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.entries, ledgerKey(consumer, shopID, docNo, version))
+	return nil
+}
+
+func newLedgerService() IConsumerLedgerService {
+	logger.NewAppLogger(config.NewLoggerConfig())
+	return NewConsumerLedgerService(&memoryConsumerLedgerRepository{entries: map[string]models.ConsumerLedgerPG{}})
+}
+
+func consumerContext(t *testing.T, offset int64) microservice.IContext {
+	ctx, err := microservice.NewConsumerContextWithMessage(nil, microservice.ConsumerMessage{
+		Topic:  "when-sale-invoice-created",
+		Offset: offset,
+		Value:  `{"shopid":"SHOP01","docno":"INV-0001"}`,
+	})
+	require.NoError(t, err)
+	return ctx
+}
+
+func TestIdempotentDuplicateDelivery(t *testing.T) {
+	svc := newLedgerService()
+
+	applied := 0
+	handler := svc.Idempotent("saleinvoice", func(ctx microservice.IContext) error {
+		applied++
+		return nil
+	})
+
+	require.NoError(t, handler(consumerContext(t, 1)))
+	require.NoError(t, handler(consumerContext(t, 1)))
+	assert.Equal(t, 1, applied)
+
+	require.NoError(t, handler(consumerContext(t, 2)))
+	assert.Equal(t, 2, applied)
+}
+
+func TestIdempotentConcurrentDelivery(t *testing.T) {
+	svc := newLedgerService()
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	applied := 0
+	handler := svc.Idempotent("saleinvoice", func(ctx microservice.IContext) error {
+		applied++
+		close(started)
+		<-finish
+		return nil
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- handler(consumerContext(t, 1))
+	}()
+	<-started
+
+	// the second delivery while the first is running is not applied and is retried later
+	assert.Error(t, handler(consumerContext(t, 1)))
+
+	close(finish)
+	require.NoError(t, <-done)
+	require.NoError(t, handler(consumerContext(t, 1)))
+	assert.Equal(t, 1, applied)
+}
+
+func TestIdempotentFailedDelivery(t *testing.T) {
+	svc := newLedgerService()
+
+	applied := 0
+	handlerErr := errors.New("database is down")
+	handler := svc.Idempotent("saleinvoice", func(ctx microservice.IContext) error {
+		applied++
+		return handlerErr
+	})
+
+	assert.ErrorIs(t, handler(consumerContext(t, 1)), handlerErr)
+
+	// failed message is applied again when it is delivered again
+	handlerErr = nil
+	require.NoError(t, handler(consumerContext(t, 1)))
+	assert.Equal(t, 2, applied)
+}
@@ -3,8 +3,10 @@ package stockadjustment
 import (
 	pkgConfig "smlaicloudplatform/internal/config"
 	"smlaicloudplatform/internal/logger"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
 	"smlaicloudplatform/internal/transaction/models"
 	stockadjustmentproductconfig "smlaicloudplatform/internal/transaction/stockadjustment/config"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/stocktransaction"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/usecases"
@@ -179,6 +181,7 @@ func (c *StockAdjustmentTransactionConsumer) ConsumeOnBulkDelete(ctx microservic
 func (c *StockAdjustmentTransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := pkgConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(c.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(c.cfg.MQConfig(), ms.Logger)
 	stockProductReceiveKafkaConfig := stockadjustmentproductconfig.StockAdjustmentMessageQueueConfig{}
 
@@ -189,12 +192,12 @@ func (c *StockAdjustmentTransactionConsumer) RegisterConsumer(ms *microservice.M
 	mq.CreateTopicR(stockProductReceiveKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(stockProductReceiveKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnDelete)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkDelete)
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", c.ConsumeOnDelete))
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete)))
+	ms.Consume(c.cfg.MQConfig().URI(), stockProductReceiveKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("stockadjustment", c.ConsumeOnBulkDelete))
 
 }
 
@@ -58,7 +58,7 @@ func (repo *StockAdjustmentTransactionPGRepository) DeleteData(shopID string, do
 	tx.Model(&models.StockAdjustmentTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockAdjustmentTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockAdjustmentTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockAdjustmentTransactionPG{}, map[string]interface{}{
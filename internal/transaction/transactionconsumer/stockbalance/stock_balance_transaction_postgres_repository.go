@@ -58,7 +58,7 @@ func (repo *StockReceiveTransactionPGRepository) DeleteData(shopID string, docNo
 	tx.Model(&models.StockBalanceTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockBalanceTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockBalanceTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockBalanceTransactionPG{}, map[string]interface{}{
@@ -58,7 +58,7 @@ func (repo *StockPickupTransactionPGRepository) DeleteData(shopID string, docNo
 	tx.Model(&models.StockPickUpTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockPickUpTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockPickUpTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockPickUpTransactionPG{}, map[string]interface{}{
@@ -58,7 +58,7 @@ func (repo *StockReceiveTransactionPGRepository) DeleteData(shopID string, docNo
 	tx.Model(&models.StockReceiveProductTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockReceiveProductTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockReceiveProductTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockReceiveProductTransactionPG{}, map[string]interface{}{
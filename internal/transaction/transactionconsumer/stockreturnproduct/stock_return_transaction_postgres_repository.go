@@ -58,7 +58,7 @@ func (repo *StockReturnTransactionPGRepository) DeleteData(shopID string, docNo
 	tx.Model(&models.StockReturnProductTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockReturnProductTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockReturnProductTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockReturnProductTransactionPG{}, map[string]interface{}{
@@ -63,7 +63,7 @@ func (repo *StockTransactionPGRepository) Delete(shopID string, docNo string) er
 	tx.Model(&models.StockTransactionDetail{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockTransactionDetail{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockTransactionDetail{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockTransaction{}, map[string]interface{}{
@@ -58,7 +58,7 @@ func (repo *StockTransferTransactionPGRepository) DeleteData(shopID string, docN
 	tx.Model(&models.StockTransferTransactionDetailPG{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.StockTransferTransactionDetailPG{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.StockTransferTransactionDetailPG{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.StockTransferTransactionPG{}, map[string]interface{}{
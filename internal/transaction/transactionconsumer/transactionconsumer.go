@@ -9,6 +9,7 @@ import (
 	transaction_payment_consume "smlaicloudplatform/internal/transaction/transactionconsumer/payment"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/purchase"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/purchasereturn"
+	"smlaicloudplatform/internal/transaction/transactionconsumer/repositories"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/saleinvoice"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/saleinvoicereturn"
 	"smlaicloudplatform/internal/transaction/transactionconsumer/services"
@@ -95,6 +96,7 @@ func NewTransactionConsumer(ms *microservice.Microservice, cfg pkgConfig.IConfig
 func (pbc *TransactionConsumer) RegisterConsumer(ms *microservice.Microservice) {
 
 	trxConsumerGroup := msConfig.GetEnv("TRANSACTION_CONSUMER_GROUP", "transaction-consumer-group-01")
+	ledger := services.NewConsumerLedgerService(repositories.NewConsumerLedgerRepository(ms.Persister(pbc.cfg.PersisterConfig())))
 	mq := microservice.NewMQ(pbc.cfg.MQConfig(), ms.Logger)
 
 	// purchaseKafkaConfig := purchaseConfig.PurchaseMessageQueueConfig{}
@@ -151,11 +153,11 @@ func (pbc *TransactionConsumer) RegisterConsumer(ms *microservice.Microservice)
 	mq.CreateTopicR(saleInvoiceReturnKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(saleInvoiceReturnKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnCreateOrUpdate)
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnCreateOrUpdate)
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnDelete)
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), pbc.saleInvoiceReturnConsumer.ConsumeOnBulkDelete)
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnCreateOrUpdate))
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnCreateOrUpdate))
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnDelete))
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnBulkCreateOrUpdate))
+	ms.Consume(pbc.cfg.MQConfig().URI(), saleInvoiceReturnKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), ledger.Idempotent("saleinvoicereturn", pbc.saleInvoiceReturnConsumer.ConsumeOnBulkDelete))
 
 }
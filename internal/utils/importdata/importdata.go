@@ -1,17 +1,17 @@
 package importdata
 
+// FilterDuplicate return the last doc of every id in order of the first doc of the id, so import is done in input order
 func FilterDuplicate[TDATA any](docList []TDATA, fnGetID func(TDATA) string) (itemFiltered []TDATA, itemDuplicate []TDATA) {
-	tempFilterDict := map[string]TDATA{}
+	tempFilterIndex := map[string]int{}
 	for _, doc := range docList {
 		idKey := fnGetID(doc)
-		if _, ok := tempFilterDict[idKey]; ok {
+		if idx, ok := tempFilterIndex[idKey]; ok {
 			itemDuplicate = append(itemDuplicate, doc)
-
+			itemFiltered[idx] = doc
+			continue
 		}
-		tempFilterDict[idKey] = doc
-	}
 
-	for _, doc := range tempFilterDict {
+		tempFilterIndex[idKey] = len(itemFiltered)
 		itemFiltered = append(itemFiltered, doc)
 	}
 
@@ -45,6 +45,16 @@ func TestFilterDuplicate(t *testing.T) {
 
 	require.Equal(t, len(userFiltered), 5, "case 2: filtered data  invalid")
 	require.Equal(t, len(userDuplicate), 1, "case 2: duplicate data invalid")
+
+	userMockList = append(userMockList, MockUser{
+		Code: "code003",
+		Name: "name 3 again",
+	})
+
+	userFiltered, _ = importdata.FilterDuplicate[MockUser](userMockList, getMockUserID)
+
+	require.Equal(t, []string{"code001", "code002", "code003", "code004", "code005"}, []string{userFiltered[0].Code, userFiltered[1].Code, userFiltered[2].Code, userFiltered[3].Code, userFiltered[4].Code}, "case 3: filtered data is not in input order")
+	require.Equal(t, "name 3 again", userFiltered[2].Name, "case 3: last duplicate is not kept")
 }
 
 func TestPreparePayloadData(t *testing.T) {
@@ -0,0 +1,78 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"strings"
+	"unicode"
+)
+
+// Policy is strength rule of user password, it is checked on register and password change only
+// so password of existing user keep working until it is changed
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+func NewPolicy(cfg config.IPasswordPolicyConfig) Policy {
+	return Policy{
+		MinLength:     cfg.MinLength(),
+		RequireUpper:  cfg.RequireUpper(),
+		RequireLower:  cfg.RequireLower(),
+		RequireDigit:  cfg.RequireDigit(),
+		RequireSymbol: cfg.RequireSymbol(),
+	}
+}
+
+// Validate return error which list every rule the password does not pass
+func (p Policy) Validate(password string) error {
+	if strings.TrimSpace(password) == "" {
+		return errors.New("password is required")
+	}
+
+	hasUpper, hasLower, hasDigit, hasSymbol := false, false, false, false
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			hasSymbol = true
+		}
+	}
+
+	rules := []string{}
+
+	if len([]rune(password)) < p.MinLength {
+		rules = append(rules, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+
+	if p.RequireUpper && !hasUpper {
+		rules = append(rules, "an uppercase letter")
+	}
+
+	if p.RequireLower && !hasLower {
+		rules = append(rules, "a lowercase letter")
+	}
+
+	if p.RequireDigit && !hasDigit {
+		rules = append(rules, "a digit")
+	}
+
+	if p.RequireSymbol && !hasSymbol {
+		rules = append(rules, "a symbol")
+	}
+
+	if len(rules) > 0 {
+		return fmt.Errorf("password must contain %s", strings.Join(rules, ", "))
+	}
+
+	return nil
+}
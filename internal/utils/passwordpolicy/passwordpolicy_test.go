@@ -0,0 +1,43 @@
+package passwordpolicy_test
+
+import (
+	"smlaicloudplatform/internal/utils/passwordpolicy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := passwordpolicy.Policy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	cases := []struct {
+		name     string
+		password string
+		err      string
+	}{
+		{name: "strong", password: "Passw0rd!"},
+		{name: "empty", password: "  ", err: "password is required"},
+		{name: "short", password: "Pa0!", err: "password must contain at least 8 characters"},
+		{name: "missing all classes", password: "password", err: "password must contain an uppercase letter, a digit, a symbol"},
+		{name: "thai letters count as length", password: "รหัสผ่านAb1!", err: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.password)
+			if tc.err == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	assert.Nil(t, passwordpolicy.Policy{MinLength: 4}.Validate("abcd"))
+}
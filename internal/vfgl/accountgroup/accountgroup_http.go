@@ -68,7 +68,7 @@ func (h AccountGroupHttp) CreateAccountGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.Create(shopID, authUsername, *docReq)
+	idx, err := h.svc.Create(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -109,7 +109,7 @@ func (h AccountGroupHttp) UpdateAccountGroup(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.Update(id, shopID, authUsername, *docReq)
+	err = h.svc.Update(ctx.Context(), id, shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -141,7 +141,7 @@ func (h AccountGroupHttp) DeleteAccountGroup(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.Delete(id, shopID, authUsername)
+	err := h.svc.Delete(ctx.Context(), id, shopID, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -173,7 +173,7 @@ func (h AccountGroupHttp) InfoAccountGroup(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get doc %v", id)
-	doc, err := h.svc.Info(id, shopID)
+	doc, err := h.svc.Info(ctx.Context(), id, shopID)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -205,7 +205,7 @@ func (h AccountGroupHttp) SearchAccountGroup(ctx microservice.IContext) error {
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.Search(shopID, pageable)
+	docList, pagination, err := h.svc.Search(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -246,7 +246,7 @@ func (h AccountGroupHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/utils/importdata"
 	"smlaicloudplatform/internal/vfgl/accountgroup/models"
 	"smlaicloudplatform/internal/vfgl/accountgroup/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,12 +18,12 @@ import (
 )
 
 type IAccountGroupHttpService interface {
-	Create(shopID string, authUsername string, doc models.AccountGroup) (string, error)
-	Update(guid string, shopID string, authUsername string, doc models.AccountGroup) error
-	Delete(guid string, shopID string, authUsername string) error
-	Info(guid string, shopID string) (models.AccountGroupInfo, error)
-	Search(shopID string, pageable micromodels.Pageable) ([]models.AccountGroupInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.AccountGroup) (common.BulkImport, error)
+	Create(ctx context.Context, shopID string, authUsername string, doc models.AccountGroup) (string, error)
+	Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.AccountGroup) error
+	Delete(ctx context.Context, guid string, shopID string, authUsername string) error
+	Info(ctx context.Context, guid string, shopID string) (models.AccountGroupInfo, error)
+	Search(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.AccountGroupInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.AccountGroup) (common.BulkImport, error)
 }
 
 type AccountGroupHttpService struct {
@@ -42,13 +43,13 @@ func NewAccountGroupHttpService(repo repositories.AccountGroupMongoRepository, m
 	}
 }
 
-func (svc AccountGroupHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc AccountGroupHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc AccountGroupHttpService) Create(shopID string, authUsername string, doc models.AccountGroup) (string, error) {
+func (svc AccountGroupHttpService) Create(ctx context.Context, shopID string, authUsername string, doc models.AccountGroup) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"code": doc.Code})
@@ -85,9 +86,9 @@ func (svc AccountGroupHttpService) Create(shopID string, authUsername string, do
 	return newGuidFixed, nil
 }
 
-func (svc AccountGroupHttpService) Update(guid string, shopID string, authUsername string, doc models.AccountGroup) error {
+func (svc AccountGroupHttpService) Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.AccountGroup) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -123,9 +124,9 @@ func (svc AccountGroupHttpService) Update(guid string, shopID string, authUserna
 	return nil
 }
 
-func (svc AccountGroupHttpService) Delete(guid string, shopID string, authUsername string) error {
+func (svc AccountGroupHttpService) Delete(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -136,9 +137,9 @@ func (svc AccountGroupHttpService) Delete(guid string, shopID string, authUserna
 	return nil
 }
 
-func (svc AccountGroupHttpService) Info(guid string, shopID string) (models.AccountGroupInfo, error) {
+func (svc AccountGroupHttpService) Info(ctx context.Context, guid string, shopID string) (models.AccountGroupInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -155,9 +156,9 @@ func (svc AccountGroupHttpService) Info(guid string, shopID string) (models.Acco
 
 }
 
-func (svc AccountGroupHttpService) Search(shopID string, pageable micromodels.Pageable) ([]models.AccountGroupInfo, mongopagination.PaginationData, error) {
+func (svc AccountGroupHttpService) Search(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.AccountGroupInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -174,9 +175,9 @@ func (svc AccountGroupHttpService) Search(shopID string, pageable micromodels.Pa
 	return docList, pagination, nil
 }
 
-func (svc AccountGroupHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.AccountGroup) (common.BulkImport, error) {
+func (svc AccountGroupHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.AccountGroup) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	createDataList := []models.AccountGroupDoc{}
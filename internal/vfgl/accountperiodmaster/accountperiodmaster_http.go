@@ -76,7 +76,7 @@ func (h AccountPeriodMasterHttp) CreateAccountPeriodMaster(ctx microservice.ICon
 		return err
 	}
 
-	idx, err := h.svc.CreateAccountPeriodMaster(shopID, authUsername, docReq.ToAccountPeriodMaster())
+	idx, err := h.svc.CreateAccountPeriodMaster(ctx.Context(), shopID, authUsername, docReq.ToAccountPeriodMaster())
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -121,7 +121,7 @@ func (h AccountPeriodMasterHttp) UpdateAccountPeriodMaster(ctx microservice.ICon
 		return err
 	}
 
-	err = h.svc.UpdateAccountPeriodMaster(shopID, id, authUsername, docReq.ToAccountPeriodMaster())
+	err = h.svc.UpdateAccountPeriodMaster(ctx.Context(), shopID, id, authUsername, docReq.ToAccountPeriodMaster())
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -152,7 +152,7 @@ func (h AccountPeriodMasterHttp) DeleteAccountPeriodMaster(ctx microservice.ICon
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteAccountPeriodMaster(shopID, id, authUsername)
+	err := h.svc.DeleteAccountPeriodMaster(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -191,7 +191,7 @@ func (h AccountPeriodMasterHttp) DeleteAccountPeriodMasterByGUIDs(ctx microservi
 		return err
 	}
 
-	err = h.svc.DeleteAccountPeriodMasterByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteAccountPeriodMasterByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -221,7 +221,7 @@ func (h AccountPeriodMasterHttp) InfoAccountPeriodMaster(ctx microservice.IConte
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get AccountPeriodMaster %v", id)
-	doc, err := h.svc.InfoAccountPeriodMaster(shopID, id)
+	doc, err := h.svc.InfoAccountPeriodMaster(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -271,7 +271,7 @@ func (h AccountPeriodMasterHttp) InfoAccountPeriodMasterByDate(ctx microservice.
 		dateList = append(dateList, tempDate)
 	}
 
-	doc, err := h.svc.InfoAccountPeriodMasterByDateList(shopID, dateList)
+	doc, err := h.svc.InfoAccountPeriodMasterByDateList(ctx.Context(), shopID, dateList)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -301,7 +301,7 @@ func (h AccountPeriodMasterHttp) SearchAccountPeriodMasterPage(ctx microservice.
 	shopID := userInfo.ShopID
 
 	pageable := utils.GetPageable(ctx.QueryParam)
-	docList, pagination, err := h.svc.SearchAccountPeriodMaster(shopID, pageable)
+	docList, pagination, err := h.svc.SearchAccountPeriodMaster(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -336,7 +336,7 @@ func (h AccountPeriodMasterHttp) SearchAccountPeriodMasterLimit(ctx microservice
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchAccountPeriodMasterStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchAccountPeriodMasterStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -383,7 +383,7 @@ func (h AccountPeriodMasterHttp) SaveBulkAccountPeriodMaster(ctx microservice.IC
 		tempDocListReq = append(tempDocListReq, doc.ToAccountPeriodMaster())
 	}
 
-	err = h.svc.SaveInBatch(shopID, authUsername, tempDocListReq)
+	err = h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, tempDocListReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -6,6 +6,7 @@ import (
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/vfgl/accountperiodmaster/models"
 	"smlaicloudplatform/internal/vfgl/accountperiodmaster/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -15,16 +16,16 @@ import (
 )
 
 type IAccountPeriodMasterHttpService interface {
-	CreateAccountPeriodMaster(shopID string, authUsername string, doc models.AccountPeriodMaster) (string, error)
-	UpdateAccountPeriodMaster(shopID string, guid string, authUsername string, doc models.AccountPeriodMaster) error
-	DeleteAccountPeriodMaster(shopID string, guid string, authUsername string) error
-	DeleteAccountPeriodMasterByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoAccountPeriodMaster(shopID string, guid string) (models.AccountPeriodMasterInfo, error)
-	InfoAccountPeriodMasterByDate(shopID string, findDate time.Time) (models.AccountPeriodMasterInfo, error)
-	InfoAccountPeriodMasterByDateList(shopID string, findDateList []time.Time) ([]models.MapDateAccountPeriodMasterInfo, error)
-	SearchAccountPeriodMaster(shopID string, pageable micromodels.Pageable) ([]models.AccountPeriodMasterInfo, mongopagination.PaginationData, error)
-	SearchAccountPeriodMasterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.AccountPeriodMasterInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.AccountPeriodMaster) error
+	CreateAccountPeriodMaster(ctx context.Context, shopID string, authUsername string, doc models.AccountPeriodMaster) (string, error)
+	UpdateAccountPeriodMaster(ctx context.Context, shopID string, guid string, authUsername string, doc models.AccountPeriodMaster) error
+	DeleteAccountPeriodMaster(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteAccountPeriodMasterByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoAccountPeriodMaster(ctx context.Context, shopID string, guid string) (models.AccountPeriodMasterInfo, error)
+	InfoAccountPeriodMasterByDate(ctx context.Context, shopID string, findDate time.Time) (models.AccountPeriodMasterInfo, error)
+	InfoAccountPeriodMasterByDateList(ctx context.Context, shopID string, findDateList []time.Time) ([]models.MapDateAccountPeriodMasterInfo, error)
+	SearchAccountPeriodMaster(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.AccountPeriodMasterInfo, mongopagination.PaginationData, error)
+	SearchAccountPeriodMasterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.AccountPeriodMasterInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.AccountPeriodMaster) error
 }
 
 type AccountPeriodMasterHttpService struct {
@@ -42,13 +43,13 @@ func NewAccountPeriodMasterHttpService(repo repositories.IAccountPeriodMasterRep
 	}
 }
 
-func (svc AccountPeriodMasterHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc AccountPeriodMasterHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc AccountPeriodMasterHttpService) CreateAccountPeriodMaster(shopID string, authUsername string, doc models.AccountPeriodMaster) (string, error) {
+func (svc AccountPeriodMasterHttpService) CreateAccountPeriodMaster(ctx context.Context, shopID string, authUsername string, doc models.AccountPeriodMaster) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByPeriod(ctx, shopID, doc.Period)
@@ -90,9 +91,9 @@ func (svc AccountPeriodMasterHttpService) CreateAccountPeriodMaster(shopID strin
 	return newGuidFixed, nil
 }
 
-func (svc AccountPeriodMasterHttpService) UpdateAccountPeriodMaster(shopID string, guid string, authUsername string, doc models.AccountPeriodMaster) error {
+func (svc AccountPeriodMasterHttpService) UpdateAccountPeriodMaster(ctx context.Context, shopID string, guid string, authUsername string, doc models.AccountPeriodMaster) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -138,9 +139,9 @@ func (svc AccountPeriodMasterHttpService) UpdateAccountPeriodMaster(shopID strin
 	return nil
 }
 
-func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMaster(shopID string, guid string, authUsername string) error {
+func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMaster(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -161,9 +162,9 @@ func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMaster(shopID strin
 	return nil
 }
 
-func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMasterByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMasterByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -178,9 +179,9 @@ func (svc AccountPeriodMasterHttpService) DeleteAccountPeriodMasterByGUIDs(shopI
 	return nil
 }
 
-func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMaster(shopID string, guid string) (models.AccountPeriodMasterInfo, error) {
+func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMaster(ctx context.Context, shopID string, guid string) (models.AccountPeriodMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -197,9 +198,9 @@ func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMaster(shopID string,
 
 }
 
-func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMasterByDate(shopID string, findDate time.Time) (models.AccountPeriodMasterInfo, error) {
+func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMasterByDate(ctx context.Context, shopID string, findDate time.Time) (models.AccountPeriodMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDateRange(ctx, shopID, findDate, findDate)
@@ -216,9 +217,9 @@ func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMasterByDate(shopID s
 
 }
 
-func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMaster(shopID string, pageable micromodels.Pageable) ([]models.AccountPeriodMasterInfo, mongopagination.PaginationData, error) {
+func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMaster(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.AccountPeriodMasterInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -235,9 +236,9 @@ func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMaster(shopID strin
 	return docList, pagination, nil
 }
 
-func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMasterStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.AccountPeriodMasterInfo, int, error) {
+func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMasterStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.AccountPeriodMasterInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -265,9 +266,9 @@ func (svc AccountPeriodMasterHttpService) SearchAccountPeriodMasterStep(shopID s
 	return docList, total, nil
 }
 
-func (svc AccountPeriodMasterHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.AccountPeriodMaster) error {
+func (svc AccountPeriodMasterHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.AccountPeriodMaster) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.ValidatePeriod(dataList)
@@ -321,9 +322,9 @@ func (svc AccountPeriodMasterHttpService) SaveInBatch(shopID string, authUsernam
 	return nil
 }
 
-func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMasterByDateList(shopID string, findDateList []time.Time) ([]models.MapDateAccountPeriodMasterInfo, error) {
+func (svc AccountPeriodMasterHttpService) InfoAccountPeriodMasterByDateList(ctx context.Context, shopID string, findDateList []time.Time) ([]models.MapDateAccountPeriodMasterInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	periodData, err := svc.repo.FindAll(ctx, shopID)
@@ -88,7 +88,7 @@ func (h ChartOfAccountHttp) Search(ctx microservice.IContext) error {
 
 	}
 
-	docList, pagination, err := h.svc.Search(shopID, accRanges, pageable)
+	docList, pagination, err := h.svc.Search(ctx.Context(), shopID, accRanges, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -126,7 +126,7 @@ func (h ChartOfAccountHttp) Create(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.Create(shopID, authUsername, *docReq)
+	idx, err := h.svc.Create(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -157,7 +157,7 @@ func (h ChartOfAccountHttp) Info(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Journal %v", id)
-	doc, err := h.svc.Info(id, shopID)
+	doc, err := h.svc.Info(ctx.Context(), id, shopID)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -199,7 +199,7 @@ func (h ChartOfAccountHttp) Update(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.Update(id, shopID, authUsername, *docReq)
+	err = h.svc.Update(ctx.Context(), id, shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -231,7 +231,7 @@ func (h ChartOfAccountHttp) Delete(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.Delete(id, shopID, authUsername)
+	err := h.svc.Delete(ctx.Context(), id, shopID, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -272,7 +272,7 @@ func (h ChartOfAccountHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
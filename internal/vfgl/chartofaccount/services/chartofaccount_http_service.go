@@ -9,6 +9,7 @@ import (
 	"smlaicloudplatform/internal/vfgl/chartofaccount/models"
 	"smlaicloudplatform/internal/vfgl/chartofaccount/repositories"
 	journalRepo "smlaicloudplatform/internal/vfgl/journal/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -18,12 +19,12 @@ import (
 )
 
 type IChartOfAccountHttpService interface {
-	Create(shopID string, authUsername string, doc models.ChartOfAccount) (string, error)
-	Update(guid string, shopID string, authUsername string, doc models.ChartOfAccount) error
-	Delete(guid string, shopID string, authUsername string) error
-	Info(guid string, shopID string) (models.ChartOfAccountInfo, error)
-	Search(shopID string, accountCodeRanges []models.AccountCodeRange, pageable micromodels.Pageable) ([]models.ChartOfAccountInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.ChartOfAccount) (common.BulkImport, error)
+	Create(ctx context.Context, shopID string, authUsername string, doc models.ChartOfAccount) (string, error)
+	Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.ChartOfAccount) error
+	Delete(ctx context.Context, guid string, shopID string, authUsername string) error
+	Info(ctx context.Context, guid string, shopID string) (models.ChartOfAccountInfo, error)
+	Search(ctx context.Context, shopID string, accountCodeRanges []models.AccountCodeRange, pageable micromodels.Pageable) ([]models.ChartOfAccountInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ChartOfAccount) (common.BulkImport, error)
 }
 
 type ChartOfAccountHttpService struct {
@@ -45,13 +46,13 @@ func NewChartOfAccountHttpService(repo repositories.ChartOfAccountRepository, re
 	}
 }
 
-func (svc ChartOfAccountHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc ChartOfAccountHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc ChartOfAccountHttpService) Create(shopID string, authUsername string, doc models.ChartOfAccount) (string, error) {
+func (svc ChartOfAccountHttpService) Create(ctx context.Context, shopID string, authUsername string, doc models.ChartOfAccount) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"accountcode": doc.AccountCode})
@@ -88,9 +89,9 @@ func (svc ChartOfAccountHttpService) Create(shopID string, authUsername string,
 	return newGuidFixed, nil
 }
 
-func (svc ChartOfAccountHttpService) Update(guid string, shopID string, authUsername string, doc models.ChartOfAccount) error {
+func (svc ChartOfAccountHttpService) Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.ChartOfAccount) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -132,9 +133,9 @@ func (svc ChartOfAccountHttpService) Update(guid string, shopID string, authUser
 	return nil
 }
 
-func (svc ChartOfAccountHttpService) Delete(guid string, shopID string, authUsername string) error {
+func (svc ChartOfAccountHttpService) Delete(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -169,9 +170,9 @@ func (svc ChartOfAccountHttpService) Delete(guid string, shopID string, authUser
 	return nil
 }
 
-func (svc ChartOfAccountHttpService) Info(guid string, shopID string) (models.ChartOfAccountInfo, error) {
+func (svc ChartOfAccountHttpService) Info(ctx context.Context, guid string, shopID string) (models.ChartOfAccountInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -188,9 +189,9 @@ func (svc ChartOfAccountHttpService) Info(guid string, shopID string) (models.Ch
 
 }
 
-func (svc ChartOfAccountHttpService) Search(shopID string, accountCodeRanges []models.AccountCodeRange, pageable micromodels.Pageable) ([]models.ChartOfAccountInfo, mongopagination.PaginationData, error) {
+func (svc ChartOfAccountHttpService) Search(ctx context.Context, shopID string, accountCodeRanges []models.AccountCodeRange, pageable micromodels.Pageable) ([]models.ChartOfAccountInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -222,9 +223,9 @@ func (svc ChartOfAccountHttpService) Search(shopID string, accountCodeRanges []m
 	return docList, pagination, nil
 }
 
-func (svc ChartOfAccountHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.ChartOfAccount) (common.BulkImport, error) {
+func (svc ChartOfAccountHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.ChartOfAccount) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.ChartOfAccount](dataList, svc.getDocIDKey)
@@ -3,6 +3,7 @@ package journal
 import (
 	"encoding/json"
 	sysConfig "smlaicloudplatform/internal/config"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
 	"smlaicloudplatform/internal/vfgl/journal/config"
 	"smlaicloudplatform/internal/vfgl/journal/models"
 	"smlaicloudplatform/internal/vfgl/journal/repositories"
@@ -66,11 +67,11 @@ func (c *JournalTransactionConsumer) RegisterConsumer(ms *microservice.Microserv
 	mq.CreateTopicR(journalKafkaConfig.TopicBulkUpdated(), 5, 1, time.Hour*24*7)
 	mq.CreateTopicR(journalKafkaConfig.TopicBulkDeleted(), 5, 1, time.Hour*24*7)
 
-	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnCreateOrUpdate)
+	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicCreated(), trxConsumerGroup, time.Duration(-1), approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete))
+	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicUpdated(), trxConsumerGroup, time.Duration(-1), approvalservices.ApprovedOnly(c.ConsumeOnCreateOrUpdate, c.ConsumeOnDelete))
 	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnDelete)
-	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
-	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkCreateOrUpdate)
+	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicBulkCreated(), trxConsumerGroup, time.Duration(-1), approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete))
+	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicBulkUpdated(), trxConsumerGroup, time.Duration(-1), approvalservices.ApprovedOnly(c.ConsumeOnBulkCreateOrUpdate, c.ConsumeOnBulkDelete))
 	ms.Consume(c.cfg.MQConfig().URI(), journalKafkaConfig.TopicBulkDeleted(), trxConsumerGroup, time.Duration(-1), c.ConsumeOnBulkDelete)
 
 }
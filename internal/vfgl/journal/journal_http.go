@@ -8,6 +8,7 @@ import (
 	repoDocumentimage "smlaicloudplatform/internal/documentwarehouse/documentimage/repositories"
 	serviceDocumentimage "smlaicloudplatform/internal/documentwarehouse/documentimage/services"
 	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/transaction/approval"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/vfgl/journal/models"
 	"smlaicloudplatform/internal/vfgl/journal/repositories"
@@ -36,10 +37,10 @@ func NewJournalHttp(ms *microservice.Microservice, cfg msConfig.IConfig) Journal
 
 	repo := repositories.NewJournalRepository(pst)
 	mqRepo := repositories.NewJournalMqRepository(prod)
-	svc := services.NewJournalHttpService(repo, mqRepo)
+	svc := services.NewJournalHttpService(repo, mqRepo, approval.InitApprovalWorkflow(ms, cfg))
 
 	// cacheRepo := repositories.NewJournalCacheRepository(cache)
-	// svcWebsocket := services.NewJournalWebsocketService(repo, cacheRepo, time.Duration(30)*time.Minute)
+	// svcWebsocket := services.NewJournalWebsocketService(repo, cacheRepo, ms.WebsocketHub(cfg.CacherConfig()), ms.Locker(cfg.CacherConfig()), time.Duration(30)*time.Minute)
 
 	repoDocImage := repoDocumentimage.NewDocumentImageRepository(pst)
 	repoDocImageGroup := repoDocumentimage.NewDocumentImageGroupRepository(pst)
@@ -59,7 +60,6 @@ func NewJournalHttp(ms *microservice.Microservice, cfg msConfig.IConfig) Journal
 
 func (h JournalHttp) RegisterHttp() {
 
-	h.ms.GET("/gl/journal/re-gen-guid-empty", h.ReUpdateGuidJournalEmpty)
 	h.ms.POST("/gl/journal/bulk", h.SaveBulk)
 
 	h.ms.GET("/gl/journal", h.SearchJournal)
@@ -73,34 +73,8 @@ func (h JournalHttp) RegisterHttp() {
 	h.ms.DELETE("/gl/journal", h.DeleteJournalByGUIDs)
 	h.ms.DELETE("/gl/journal/batchid/:batchid", h.DeleteJournalByBatchID)
 
-}
-
-func (h JournalHttp) ReUpdateGuidJournalEmpty(ctx microservice.IContext) error {
-
-	passCode := ctx.QueryParam("passcode")
-
-	if len(passCode) < 1 {
-		ctx.ResponseError(http.StatusNotFound, "not found")
-		return nil
-	}
-
-	if passCode != "smldev" {
-		ctx.ResponseError(http.StatusBadRequest, "passcode invalid")
-		return nil
-	}
-
-	err := h.svc.ReGenerateGuidEmpty()
-
-	if err != nil {
-		ctx.ResponseError(http.StatusBadRequest, err.Error())
-		return err
-	}
+	approval.NewDocumentApprovalHttp(h.ms, h.svc.Approval()).RegisterHttp("/gl/journal")
 
-	ctx.Response(http.StatusOK, common.ApiResponse{
-		Success: true,
-	})
-
-	return nil
 }
 
 // Create Journal godoc
@@ -127,7 +101,7 @@ func (h JournalHttp) CreateJournal(ctx microservice.IContext) error {
 	}
 
 	if len(docReq.DocumentRef) > 0 {
-		docImageGroup, err := h.svcDocImage.GetDocumentImageDocRefGroup(shopID, docReq.DocumentRef)
+		docImageGroup, err := h.svcDocImage.GetDocumentImageDocRefGroup(ctx.Context(), shopID, docReq.DocumentRef)
 		if err != nil {
 			messageError := ""
 			if err.Error() == "document not found" {
@@ -147,7 +121,7 @@ func (h JournalHttp) CreateJournal(ctx microservice.IContext) error {
 			}
 		}
 
-		err = h.svcDocImage.UpdateReferenceByDocumentImageGroup(shopID, authUsername, docReq.DocumentRef, documentImageModel.Reference{
+		err = h.svcDocImage.UpdateReferenceByDocumentImageGroup(ctx.Context(), shopID, authUsername, docReq.DocumentRef, documentImageModel.Reference{
 			Module: h.Module,
 			DocNo:  docReq.DocNo,
 		})
@@ -158,7 +132,7 @@ func (h JournalHttp) CreateJournal(ctx microservice.IContext) error {
 		}
 	}
 
-	idx, err := h.svc.CreateJournal(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateJournal(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -200,7 +174,7 @@ func (h JournalHttp) UpdateJournal(ctx microservice.IContext) error {
 	}
 
 	if len(docReq.DocumentRef) > 0 {
-		docImageGroup, err := h.svcDocImage.GetDocumentImageDocRefGroup(shopID, docReq.DocumentRef)
+		docImageGroup, err := h.svcDocImage.GetDocumentImageDocRefGroup(ctx.Context(), shopID, docReq.DocumentRef)
 		if err != nil {
 			messageError := ""
 			if err.Error() == "document not found" {
@@ -221,14 +195,14 @@ func (h JournalHttp) UpdateJournal(ctx microservice.IContext) error {
 		}
 	}
 
-	journalInfo, err := h.svc.InfoJournal(shopID, id)
+	journalInfo, err := h.svc.InfoJournal(ctx.Context(), shopID, id)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
 	}
 
-	err = h.svc.UpdateJournal(id, shopID, authUsername, *docReq)
+	err = h.svc.UpdateJournal(ctx.Context(), id, shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -238,7 +212,7 @@ func (h JournalHttp) UpdateJournal(ctx microservice.IContext) error {
 	if journalInfo.DocumentRef != docReq.DocumentRef {
 
 		if len(docReq.DocumentRef) > 0 {
-			err = h.svcDocImage.UpdateReferenceByDocumentImageGroup(shopID, authUsername, docReq.DocumentRef, documentImageModel.Reference{
+			err = h.svcDocImage.UpdateReferenceByDocumentImageGroup(ctx.Context(), shopID, authUsername, docReq.DocumentRef, documentImageModel.Reference{
 				Module: h.Module,
 				DocNo:  docReq.DocNo,
 			})
@@ -274,14 +248,14 @@ func (h JournalHttp) DeleteJournal(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	journal, err := h.svc.InfoJournal(shopID, id)
+	journal, err := h.svc.InfoJournal(ctx.Context(), shopID, id)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
 		return err
 	}
 
-	err = h.svc.DeleteJournal(id, shopID, authUsername)
+	err = h.svc.DeleteJournal(ctx.Context(), id, shopID, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -293,7 +267,7 @@ func (h JournalHttp) DeleteJournal(ctx microservice.IContext) error {
 			Module: h.Module,
 			DocNo:  journal.DocNo,
 		}
-		h.svcDocImage.DeleteReferenceByDocumentImageGroup(shopID, authUsername, journal.DocumentRef, imageRef)
+		h.svcDocImage.DeleteReferenceByDocumentImageGroup(ctx.Context(), shopID, authUsername, journal.DocumentRef, imageRef)
 	}
 
 	ctx.Response(http.StatusOK, common.ApiResponse{
@@ -328,7 +302,7 @@ func (h JournalHttp) DeleteJournalByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteJournalByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteJournalByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -359,7 +333,7 @@ func (h JournalHttp) DeleteJournalByBatchID(ctx microservice.IContext) error {
 
 	batchID := ctx.Param("batchid")
 
-	err := h.svc.DeleteJournalByBatchID(shopID, authUsername, batchID)
+	err := h.svc.DeleteJournalByBatchID(ctx.Context(), shopID, authUsername, batchID)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -390,7 +364,7 @@ func (h JournalHttp) InfoJournal(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Journal %v", id)
-	doc, err := h.svc.InfoJournal(shopID, id)
+	doc, err := h.svc.InfoJournal(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -422,7 +396,7 @@ func (h JournalHttp) InfoJournalByDocno(ctx microservice.IContext) error {
 	docNo := ctx.Param("docno")
 
 	h.ms.Logger.Debugf("Get Journal %v", docNo)
-	doc, err := h.svc.InfoJournalByDocNo(shopID, docNo)
+	doc, err := h.svc.InfoJournalByDocNo(ctx.Context(), shopID, docNo)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", docNo, err)
@@ -454,7 +428,7 @@ func (h JournalHttp) InfoJournalByDocumentRef(ctx microservice.IContext) error {
 	docRef := ctx.Param("doc")
 
 	h.ms.Logger.Debugf("Get Journal Document ref %v:", docRef)
-	doc, err := h.svc.InfoJournalByDocumentRef(shopID, docRef)
+	doc, err := h.svc.InfoJournalByDocumentRef(ctx.Context(), shopID, docRef)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", doc, err)
@@ -602,7 +576,7 @@ func (h JournalHttp) SearchJournal(ctx microservice.IContext) error {
 		}
 	}
 
-	docList, pagination, err := h.svc.SearchJournal(shopID, pageable, searchFilters, startDate, endDate, accountGroup)
+	docList, pagination, err := h.svc.SearchJournal(ctx.Context(), shopID, pageable, searchFilters, startDate, endDate, accountGroup)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -643,7 +617,7 @@ func (h JournalHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -676,7 +650,7 @@ func (h JournalHttp) GetLastDocNo(ctx microservice.IContext) error {
 
 	docFormat := ctx.QueryParam("docformat")
 
-	doc, err := h.svc.FindLastDocnoFromFormat(shopID, docFormat)
+	doc, err := h.svc.FindLastDocnoFromFormat(ctx.Context(), shopID, docFormat)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -23,6 +23,7 @@ type JournalWs struct {
 	ms           *microservice.Microservice
 	cfg          msConfig.IConfig
 	svcWebsocket services.IJournalWebsocketService
+	hub          microservice.IWebsocketHub
 }
 
 func NewJournalWs(ms *microservice.Microservice, cfg msConfig.IConfig) JournalWs {
@@ -31,12 +32,14 @@ func NewJournalWs(ms *microservice.Microservice, cfg msConfig.IConfig) JournalWs
 
 	docImageRepo := documentimageRepo.NewDocumentImageRepository(pst)
 	cacheRepo := repositories.NewJournalCacheRepository(cache)
-	svcWebsocket := services.NewJournalWebsocketService(docImageRepo, cacheRepo, time.Duration(30)*time.Minute)
+	hub := ms.WebsocketHub(cfg.CacherConfig())
+	svcWebsocket := services.NewJournalWebsocketService(docImageRepo, cacheRepo, hub, ms.Locker(cfg.CacherConfig()), time.Duration(30)*time.Minute)
 
 	return JournalWs{
 		ms:           ms,
 		cfg:          cfg,
 		svcWebsocket: svcWebsocket,
+		hub:          hub,
 	}
 }
 
@@ -316,15 +319,7 @@ func (h JournalWs) WebsocketDocRefPool(ctx microservice.IContext) error {
 		return nil
 	}
 
-	socketID := utils.NewID()
-
-	ws, err := h.ms.Websocket(socketID, ctx.ResponseWriter(), ctx.Request())
-
-	if err != nil {
-		return err
-	}
-
-	return h.svcWebsocket.DocRefPool(shopID, username, ws)
+	return h.ms.ServeWebsocket(ctx, h.hub, []string{services.JournalDocRefRoom(shopID)}, nil)
 
 }
 
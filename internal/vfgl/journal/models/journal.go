@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"smlaicloudplatform/internal/models"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -80,9 +81,10 @@ type JournalDebtAccountAddress struct {
 }
 
 type Journal struct {
-	JournalBody              `bson:"inline"`
-	models.PartitionIdentity `bson:"inline"`
-	AccountBook              *[]JournalDetail `json:"journaldetail" bson:"journaldetail"`
+	JournalBody                `bson:"inline"`
+	models.PartitionIdentity   `bson:"inline"`
+	AccountBook                *[]JournalDetail `json:"journaldetail" bson:"journaldetail"`
+	approvalmodels.DocApproval `bson:"inline"`
 }
 
 type JournalDetail struct {
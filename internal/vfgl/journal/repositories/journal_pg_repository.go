@@ -61,7 +61,7 @@ func (repo JournalPgRepository) Delete(shopID string, docNo string) error {
 	tx.Model(&models.JournalDetailPg{}).Where(" shopid=? AND docno=?", shopID, docNo).Find(&details)
 	for _, tmp := range *details {
 		// mark delete
-		tx.Delete(&models.JournalDetailPg{}, tmp.ID)
+		tx.Where("shopid=?", shopID).Delete(&models.JournalDetailPg{}, tmp.ID)
 	}
 
 	err := tx.Delete(models.JournalPg{}, map[string]interface{}{
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	common "smlaicloudplatform/internal/models"
+	approvalmodels "smlaicloudplatform/internal/transaction/approval/models"
+	approvalservices "smlaicloudplatform/internal/transaction/approval/services"
 	"smlaicloudplatform/internal/utils"
 	"smlaicloudplatform/internal/utils/importdata"
 	"smlaicloudplatform/internal/vfgl/journal/models"
 	"smlaicloudplatform/internal/vfgl/journal/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,45 +20,48 @@ import (
 )
 
 type IJournalHttpService interface {
-	CreateJournal(shopID string, authUsername string, doc models.Journal) (string, error)
-	UpdateJournal(guid string, shopID string, authUsername string, doc models.Journal) error
-	DeleteJournal(guid string, shopID string, authUsername string) error
-	DeleteJournalByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	DeleteJournalByBatchID(shopID string, authUsername string, batchID string) error
-	InfoJournal(shopID string, guid string) (models.JournalInfo, error)
-	InfoJournalByDocNo(shopID string, docNo string) (models.JournalInfo, error)
-	InfoJournalByDocumentRef(shopID string, documentRef string) (models.JournalInfo, error)
-	SearchJournal(shopID string, pagable micromodels.Pageable, searchFilters map[string]interface{}, startDate time.Time, endDate time.Time, accountGroup string) ([]models.JournalInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Journal) (common.BulkImport, error)
-
-	FindLastDocnoFromFormat(shopID string, docFormat string) (string, error)
-	ReGenerateGuidEmpty() error
+	CreateJournal(ctx context.Context, shopID string, authUsername string, doc models.Journal) (string, error)
+	UpdateJournal(ctx context.Context, guid string, shopID string, authUsername string, doc models.Journal) error
+	DeleteJournal(ctx context.Context, guid string, shopID string, authUsername string) error
+	DeleteJournalByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	DeleteJournalByBatchID(ctx context.Context, shopID string, authUsername string, batchID string) error
+	InfoJournal(ctx context.Context, shopID string, guid string) (models.JournalInfo, error)
+	InfoJournalByDocNo(ctx context.Context, shopID string, docNo string) (models.JournalInfo, error)
+	InfoJournalByDocumentRef(ctx context.Context, shopID string, documentRef string) (models.JournalInfo, error)
+	SearchJournal(ctx context.Context, shopID string, pagable micromodels.Pageable, searchFilters map[string]interface{}, startDate time.Time, endDate time.Time, accountGroup string) ([]models.JournalInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Journal) (common.BulkImport, error)
+	Approval() approvalservices.IDocumentApprovalService
+
+	FindLastDocnoFromFormat(ctx context.Context, shopID string, docFormat string) (string, error)
+	ReGenerateGuidEmpty(ctx context.Context) error
 }
 
 type JournalHttpService struct {
-	repo           repositories.JournalRepository
-	mqRepo         repositories.JournalMqRepository
-	contextTimeout time.Duration
+	repo             repositories.JournalRepository
+	mqRepo           repositories.JournalMqRepository
+	approvalWorkflow approvalservices.IApprovalWorkflow
+	contextTimeout   time.Duration
 }
 
-func NewJournalHttpService(repo repositories.JournalRepository, mqRepo repositories.JournalMqRepository) JournalHttpService {
+func NewJournalHttpService(repo repositories.JournalRepository, mqRepo repositories.JournalMqRepository, approvalWorkflow approvalservices.IApprovalWorkflow) JournalHttpService {
 
 	contextTimeout := time.Duration(15) * time.Second
 
 	return JournalHttpService{
-		repo:           repo,
-		mqRepo:         mqRepo,
-		contextTimeout: contextTimeout,
+		repo:             repo,
+		mqRepo:           mqRepo,
+		approvalWorkflow: approvalWorkflow,
+		contextTimeout:   contextTimeout,
 	}
 }
 
-func (svc JournalHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc JournalHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc JournalHttpService) CreateJournal(shopID string, authUsername string, doc models.Journal) (string, error) {
+func (svc JournalHttpService) CreateJournal(ctx context.Context, shopID string, authUsername string, doc models.Journal) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "docno", doc.DocNo)
@@ -70,10 +76,17 @@ func (svc JournalHttpService) CreateJournal(shopID string, authUsername string,
 
 	newGuidFixed := utils.NewGUID()
 
+	approvalStatus, err := svc.approvalWorkflow.Submit(ctx, svc.approvalDocument(shopID, newGuidFixed, doc.DocNo, doc), authUsername, doc.IsDraft)
+
+	if err != nil {
+		return "", err
+	}
+
 	docData := models.JournalDoc{}
 	docData.ShopID = shopID
 	docData.GuidFixed = newGuidFixed
 	docData.Journal = doc
+	docData.ApprovalStatus = approvalStatus
 
 	// docDate := doc.DocDate.Format("2006-01-02")
 	docData.DocDate = time.Date(doc.DocDate.Year(), doc.DocDate.Month(), doc.DocDate.Day(), 0, 0, 0, 0, time.UTC)
@@ -84,6 +97,7 @@ func (svc JournalHttpService) CreateJournal(shopID string, authUsername string,
 	_, err = svc.repo.Create(ctx, docData)
 
 	if err != nil {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeJournal, newGuidFixed)
 		return "", err
 	}
 
@@ -95,9 +109,9 @@ func (svc JournalHttpService) CreateJournal(shopID string, authUsername string,
 	return newGuidFixed, nil
 }
 
-func (svc JournalHttpService) UpdateJournal(guid string, shopID string, authUsername string, doc models.Journal) error {
+func (svc JournalHttpService) UpdateJournal(ctx context.Context, guid string, shopID string, authUsername string, doc models.Journal) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -112,9 +126,16 @@ func (svc JournalHttpService) UpdateJournal(guid string, shopID string, authUser
 
 	tempDocNo := findDoc.DocNo
 
+	approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, guid, tempDocNo, doc), authUsername, doc.IsDraft)
+
+	if err != nil {
+		return err
+	}
+
 	findDoc.Journal = doc
 
 	findDoc.DocNo = tempDocNo
+	findDoc.ApprovalStatus = approvalStatus
 
 	findDoc.UpdatedBy = authUsername
 	findDoc.UpdatedAt = time.Now()
@@ -132,9 +153,9 @@ func (svc JournalHttpService) UpdateJournal(guid string, shopID string, authUser
 	return nil
 }
 
-func (svc JournalHttpService) DeleteJournal(guid string, shopID string, authUsername string) error {
+func (svc JournalHttpService) DeleteJournal(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -151,6 +172,8 @@ func (svc JournalHttpService) DeleteJournal(guid string, shopID string, authUser
 	if err != nil {
 		return err
 	}
+
+	svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeJournal, guid)
 	svc.mqRepo.Delete(findDoc)
 
 	if err != nil {
@@ -159,9 +182,9 @@ func (svc JournalHttpService) DeleteJournal(guid string, shopID string, authUser
 	return nil
 }
 
-func (svc JournalHttpService) DeleteJournalByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc JournalHttpService) DeleteJournalByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docs, _ := svc.repo.FindByGuids(ctx, shopID, GUIDs)
@@ -175,6 +198,10 @@ func (svc JournalHttpService) DeleteJournalByGUIDs(shopID string, authUsername s
 		return err
 	}
 
+	for _, guid := range GUIDs {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeJournal, guid)
+	}
+
 	func() {
 
 		svc.mqRepo.DeleteInBatch(docs)
@@ -183,9 +210,9 @@ func (svc JournalHttpService) DeleteJournalByGUIDs(shopID string, authUsername s
 	return nil
 }
 
-func (svc JournalHttpService) DeleteJournalByBatchID(shopID string, authUsername string, batchID string) error {
+func (svc JournalHttpService) DeleteJournalByBatchID(ctx context.Context, shopID string, authUsername string, batchID string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDocs, err := svc.repo.FindFilter(ctx, shopID, bson.M{"batchid": batchID})
@@ -203,6 +230,10 @@ func (svc JournalHttpService) DeleteJournalByBatchID(shopID string, authUsername
 		return err
 	}
 
+	for _, findDoc := range findDocs {
+		svc.approvalWorkflow.Remove(ctx, shopID, approvalmodels.DocTypeJournal, findDoc.GuidFixed)
+	}
+
 	err = svc.mqRepo.DeleteInBatch(findDocs)
 
 	if err != nil {
@@ -211,9 +242,9 @@ func (svc JournalHttpService) DeleteJournalByBatchID(shopID string, authUsername
 	return nil
 }
 
-func (svc JournalHttpService) InfoJournal(shopID string, guid string) (models.JournalInfo, error) {
+func (svc JournalHttpService) InfoJournal(ctx context.Context, shopID string, guid string) (models.JournalInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -233,9 +264,9 @@ func (svc JournalHttpService) InfoJournal(shopID string, guid string) (models.Jo
 
 }
 
-func (svc JournalHttpService) InfoJournalByDocNo(shopID string, docNo string) (models.JournalInfo, error) {
+func (svc JournalHttpService) InfoJournalByDocNo(ctx context.Context, shopID string, docNo string) (models.JournalInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	filters := bson.M{"docno": docNo}
@@ -254,9 +285,9 @@ func (svc JournalHttpService) InfoJournalByDocNo(shopID string, docNo string) (m
 
 }
 
-func (svc JournalHttpService) InfoJournalByDocumentRef(shopID string, documentRef string) (models.JournalInfo, error) {
+func (svc JournalHttpService) InfoJournalByDocumentRef(ctx context.Context, shopID string, documentRef string) (models.JournalInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	filters := bson.M{
@@ -277,9 +308,9 @@ func (svc JournalHttpService) InfoJournalByDocumentRef(shopID string, documentRe
 
 }
 
-func (svc JournalHttpService) SearchJournal(shopID string, pageable micromodels.Pageable, searchFilters map[string]interface{}, startDate time.Time, endDate time.Time, accountGroup string) ([]models.JournalInfo, mongopagination.PaginationData, error) {
+func (svc JournalHttpService) SearchJournal(ctx context.Context, shopID string, pageable micromodels.Pageable, searchFilters map[string]interface{}, startDate time.Time, endDate time.Time, accountGroup string) ([]models.JournalInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -324,9 +355,9 @@ func (svc JournalHttpService) SearchJournal(shopID string, pageable micromodels.
 	return docList, pagination, nil
 }
 
-func (svc JournalHttpService) ReGenerateGuidEmpty() error {
+func (svc JournalHttpService) ReGenerateGuidEmpty(ctx context.Context) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docs, err := svc.repo.FindGUIDEmptyAll()
@@ -346,9 +377,9 @@ func (svc JournalHttpService) ReGenerateGuidEmpty() error {
 	return nil
 }
 
-func (svc JournalHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Journal) (common.BulkImport, error) {
+func (svc JournalHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Journal) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Journal](dataList, svc.getDocIDKey)
@@ -407,7 +438,13 @@ func (svc JournalHttpService) SaveInBatch(shopID string, authUsername string, da
 		},
 		func(shopID string, authUsername string, data models.Journal, doc models.JournalDoc) error {
 
+			approvalStatus, err := svc.approvalWorkflow.Resubmit(ctx, svc.approvalDocument(shopID, doc.GuidFixed, doc.DocNo, data), authUsername, data.IsDraft)
+			if err != nil {
+				return err
+			}
+
 			doc.Journal = data
+			doc.ApprovalStatus = approvalStatus
 			doc.UpdatedBy = authUsername
 			doc.UpdatedAt = time.Now()
 
@@ -420,9 +457,21 @@ func (svc JournalHttpService) SaveInBatch(shopID string, authUsername string, da
 	)
 
 	if len(createDataList) > 0 {
+		documentApproval := svc.documentApproval()
+
+		approvalStatuses, err := documentApproval.SubmitInBatch(ctx, createDataList, authUsername)
+		if err != nil {
+			return common.BulkImport{}, err
+		}
+
+		for idx := range createDataList {
+			createDataList[idx].ApprovalStatus = approvalStatuses[idx]
+		}
+
 		err = svc.repo.CreateInBatch(ctx, createDataList)
 
 		if err != nil {
+			documentApproval.RemoveInBatch(ctx, createDataList)
 			return common.BulkImport{}, err
 		}
 
@@ -467,9 +516,9 @@ func (svc JournalHttpService) getDocIDKey(doc models.Journal) string {
 	return doc.DocNo
 }
 
-func (svc JournalHttpService) FindLastDocnoFromFormat(shopID string, docFormat string) (string, error) {
+func (svc JournalHttpService) FindLastDocnoFromFormat(ctx context.Context, shopID string, docFormat string) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	lastDocNo, err := svc.repo.FindLastDocno(ctx, shopID, docFormat)
@@ -481,3 +530,43 @@ func (svc JournalHttpService) FindLastDocnoFromFormat(shopID string, docFormat s
 	return lastDocNo, nil
 
 }
+
+func (svc JournalHttpService) approvalDocument(shopID string, guid string, docNo string, doc models.Journal) approvalmodels.ApprovalDocument {
+	return approvalmodels.ApprovalDocument{
+		ShopID:  shopID,
+		DocType: approvalmodels.DocTypeJournal,
+		DocGuid: guid,
+		DocNo:   docNo,
+		Amount:  doc.Amount,
+	}
+}
+
+// Approval return approval workflow of saved journals
+func (svc JournalHttpService) Approval() approvalservices.IDocumentApprovalService {
+	return svc.documentApproval()
+}
+
+func (svc JournalHttpService) documentApproval() approvalservices.DocumentApprovalService[models.JournalDoc] {
+	return approvalservices.NewDocumentApprovalService(
+		svc.approvalWorkflow,
+		approvalmodels.DocTypeJournal,
+		svc.repo.FindByGuid,
+		func(doc models.JournalDoc) approvalmodels.ApprovalDocument {
+			return svc.approvalDocument(doc.ShopID, doc.GuidFixed, doc.DocNo, doc.Journal)
+		},
+		svc.saveApprovalStatus,
+	)
+}
+
+// saveApprovalStatus save changed approval status and publish the journal so it is posted or removed by consumer
+func (svc JournalHttpService) saveApprovalStatus(ctx context.Context, doc models.JournalDoc, approvalStatus string) error {
+	doc.ApprovalStatus = approvalStatus
+
+	err := svc.repo.Update(ctx, doc.ShopID, doc.GuidFixed, doc)
+
+	if err != nil {
+		return err
+	}
+
+	return svc.mqRepo.Update(doc)
+}
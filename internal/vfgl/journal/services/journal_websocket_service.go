@@ -10,11 +10,11 @@ import (
 	documentimageRepo "smlaicloudplatform/internal/documentwarehouse/documentimage/repositories"
 	"smlaicloudplatform/internal/vfgl/journal/models"
 	"smlaicloudplatform/internal/vfgl/journal/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/gorilla/websocket"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -33,7 +33,6 @@ type IJournalWebsocketService interface {
 	ExistsWebsocket(shopID string, processID string) (bool, error)
 	ExpireWebsocket(shopID string, processID string) error
 
-	DocRefPool(shopID string, username string, ws *websocket.Conn) error
 	SetDocRefPool(shopID string, username string, docRef string) error
 	ExistsDocRefPool(shopID string, docRef string) (bool, error)
 	GetDocRefPool(shopID string, docRef string) (string, error)
@@ -51,7 +50,6 @@ type IJournalWebsocketService interface {
 
 type JournalWebsocketService struct {
 	cacheChannelDoc     string
-	cacheChannelDocRef  string
 	cachePoolDocRef     string
 	cachePoolDocRefUser string
 	cacheMessageName    string
@@ -59,19 +57,22 @@ type JournalWebsocketService struct {
 	cacheExpire         time.Duration
 	docImageRepo        documentimageRepo.IDocumentImageRepository
 	repoCache           repositories.IJournalCacheRepository
+	hub                 microservice.IWebsocketHub
+	locker              microservice.ILocker
 }
 
-func NewJournalWebsocketService(docImageRepo documentimageRepo.IDocumentImageRepository, repoCache repositories.IJournalCacheRepository, cacheExpire time.Duration) *JournalWebsocketService {
+func NewJournalWebsocketService(docImageRepo documentimageRepo.IDocumentImageRepository, repoCache repositories.IJournalCacheRepository, hub microservice.IWebsocketHub, locker microservice.ILocker, cacheExpire time.Duration) *JournalWebsocketService {
 
 	return &JournalWebsocketService{
 		cacheChannelDoc:     "chdoc",
-		cacheChannelDocRef:  "chdocref",
 		cachePoolDocRef:     "wsdocref",
 		cachePoolDocRefUser: "wsdocuser",
 		cacheMessageName:    "wsmsg",
 		cacheWebsocketName:  "wssc",
 		docImageRepo:        docImageRepo,
 		repoCache:           repoCache,
+		hub:                 hub,
+		locker:              locker,
 		cacheExpire:         cacheExpire,
 	}
 }
@@ -86,14 +87,14 @@ func (svc JournalWebsocketService) SubDoc(shopID string, processID string, scree
 	return svc.repoCache.Sub(channel)
 }
 
+// PubDocRef push doc ref event to doc ref pool clients of the shop on every replica
 func (svc JournalWebsocketService) PubDocRef(shopID string, message interface{}) error {
-	channel := svc.getChannelDocRef(shopID, svc.cacheChannelDocRef)
-	return svc.repoCache.Pub(channel, message)
+	return svc.hub.Publish(JournalDocRefRoom(shopID), message)
 }
 
-func (svc JournalWebsocketService) SubDocRef(shopID string) (<-chan *redis.Message, string, error) {
-	channel := svc.getChannelDocRef(shopID, svc.cacheChannelDocRef)
-	return svc.repoCache.Sub(channel)
+// JournalDocRefRoom is websocket room of doc ref pool of the shop
+func JournalDocRefRoom(shopID string) string {
+	return microservice.WebsocketRoom("journal", "docref", shopID)
 }
 
 func (svc JournalWebsocketService) UnSub(subID string) error {
@@ -233,11 +234,6 @@ func (svc JournalWebsocketService) GetDocRefUserPool(shopID string, username str
 	return svc.repoCache.HGet(cacheKeyName, username)
 }
 
-func (svc JournalWebsocketService) getChannelDocRef(shopID string, prefix string) string {
-	tempChannel := fmt.Sprintf("%s-%s", prefix, shopID)
-	return tempChannel
-}
-
 func (svc JournalWebsocketService) getChannelDoc(shopID string, processID string, prefix string, screen string) string {
 	tempID := svc.getTagID(shopID, processID, prefix)
 	return fmt.Sprintf("%s:%s", tempID, screen)
@@ -249,7 +245,31 @@ func (JournalWebsocketService) getTagID(shopID string, processID string, prefix
 	return tempID
 }
 
+// docRefPoolLockTTL is TTL of the lock of doc ref pool of the shop
+const docRefPoolLockTTL = 10 * time.Second
+
+// withDocRefPoolLock run fn while no other replica change doc ref pool of the shop
+func (svc JournalWebsocketService) withDocRefPoolLock(shopID string, fn func() (bool, error)) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), docRefPoolLockTTL)
+	defer cancel()
+
+	result := false
+	err := microservice.WithLock(ctx, svc.locker, "journal:docref:"+shopID, docRefPoolLockTTL, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+
+	return result, err
+}
+
 func (svc JournalWebsocketService) DocRefDeSelect(shopID string, username string) (bool, error) {
+	return svc.withDocRefPoolLock(shopID, func() (bool, error) {
+		return svc.docRefDeSelect(shopID, username)
+	})
+}
+
+func (svc JournalWebsocketService) docRefDeSelect(shopID string, username string) (bool, error) {
 
 	docRef, err := svc.GetDocRefUserPool(shopID, username)
 	if err != nil {
@@ -291,14 +311,22 @@ func (svc JournalWebsocketService) DocRefDeSelect(shopID string, username string
 }
 
 func (svc JournalWebsocketService) DocRefSelectForce(shopID string, username string, docRef string, forceSelect bool) (bool, error) {
-	if forceSelect {
-		svc.DocRefDeSelect(shopID, username)
-	}
+	return svc.withDocRefPoolLock(shopID, func() (bool, error) {
+		if forceSelect {
+			svc.docRefDeSelect(shopID, username)
+		}
 
-	return svc.DocRefSelect(shopID, username, docRef)
+		return svc.docRefSelect(shopID, username, docRef)
+	})
 }
 
 func (svc JournalWebsocketService) DocRefSelect(shopID string, username string, docRef string) (bool, error) {
+	return svc.withDocRefPoolLock(shopID, func() (bool, error) {
+		return svc.docRefSelect(shopID, username, docRef)
+	})
+}
+
+func (svc JournalWebsocketService) docRefSelect(shopID string, username string, docRef string) (bool, error) {
 	isExists, err := svc.ExistsDocRefPool(shopID, docRef)
 
 	if err != nil {
@@ -355,31 +383,6 @@ func (svc JournalWebsocketService) pubDocRefSelect(shopID string, docRefEvent mo
 	return nil
 }
 
-func (svc JournalWebsocketService) DocRefPool(shopID string, username string, ws *websocket.Conn) error {
-
-	cacheMsg, subID, err := svc.SubDocRef(shopID)
-
-	if err != nil {
-		return err
-	}
-
-	defer func(ws *websocket.Conn, svc JournalWebsocketService) {
-		ws.Close()
-		svc.UnSub(subID)
-	}(ws, svc)
-
-	for {
-		temp := <-cacheMsg
-		if temp != nil {
-
-			err = ws.WriteMessage(websocket.TextMessage, []byte(temp.Payload))
-			if err != nil {
-				return err
-			}
-		}
-	}
-}
-
 func (svc JournalWebsocketService) DocRefNextSelect(shopID string, username string, status int8) (documentimageModel.DocumentImageInfo, error) {
 	docList, err := svc.GetAllDocRefPool(shopID)
 
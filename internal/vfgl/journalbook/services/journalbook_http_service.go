@@ -8,6 +8,7 @@ import (
 	"smlaicloudplatform/internal/utils/importdata"
 	"smlaicloudplatform/internal/vfgl/journalbook/models"
 	"smlaicloudplatform/internal/vfgl/journalbook/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -17,12 +18,12 @@ import (
 )
 
 type IJournalBookHttpService interface {
-	Create(shopID string, authUsername string, doc models.JournalBook) (string, error)
-	Update(guid string, shopID string, authUsername string, doc models.JournalBook) error
-	Delete(guid string, shopID string, authUsername string) error
-	Info(guid string, shopID string) (models.JournalBookInfo, error)
-	Search(shopID string, pageable micromodels.Pageable) ([]models.JournalBookInfo, mongopagination.PaginationData, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.JournalBook) (common.BulkImport, error)
+	Create(ctx context.Context, shopID string, authUsername string, doc models.JournalBook) (string, error)
+	Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.JournalBook) error
+	Delete(ctx context.Context, guid string, shopID string, authUsername string) error
+	Info(ctx context.Context, guid string, shopID string) (models.JournalBookInfo, error)
+	Search(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.JournalBookInfo, mongopagination.PaginationData, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.JournalBook) (common.BulkImport, error)
 }
 
 type JournalBookHttpService struct {
@@ -42,13 +43,13 @@ func NewJournalBookHttpService(repo repositories.JournalBookMongoRepository, mqR
 	}
 }
 
-func (svc JournalBookHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc JournalBookHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc JournalBookHttpService) Create(shopID string, authUsername string, doc models.JournalBook) (string, error) {
+func (svc JournalBookHttpService) Create(ctx context.Context, shopID string, authUsername string, doc models.JournalBook) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindOne(ctx, shopID, bson.M{"code": doc.Code})
@@ -85,9 +86,9 @@ func (svc JournalBookHttpService) Create(shopID string, authUsername string, doc
 	return newGuidFixed, nil
 }
 
-func (svc JournalBookHttpService) Update(guid string, shopID string, authUsername string, doc models.JournalBook) error {
+func (svc JournalBookHttpService) Update(ctx context.Context, guid string, shopID string, authUsername string, doc models.JournalBook) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -123,9 +124,9 @@ func (svc JournalBookHttpService) Update(guid string, shopID string, authUsernam
 	return nil
 }
 
-func (svc JournalBookHttpService) Delete(guid string, shopID string, authUsername string) error {
+func (svc JournalBookHttpService) Delete(ctx context.Context, guid string, shopID string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	err := svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
@@ -136,9 +137,9 @@ func (svc JournalBookHttpService) Delete(guid string, shopID string, authUsernam
 	return nil
 }
 
-func (svc JournalBookHttpService) Info(guid string, shopID string) (models.JournalBookInfo, error) {
+func (svc JournalBookHttpService) Info(ctx context.Context, guid string, shopID string) (models.JournalBookInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -155,9 +156,9 @@ func (svc JournalBookHttpService) Info(guid string, shopID string) (models.Journ
 
 }
 
-func (svc JournalBookHttpService) Search(shopID string, pageable micromodels.Pageable) ([]models.JournalBookInfo, mongopagination.PaginationData, error) {
+func (svc JournalBookHttpService) Search(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.JournalBookInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -174,9 +175,9 @@ func (svc JournalBookHttpService) Search(shopID string, pageable micromodels.Pag
 	return docList, pagination, nil
 }
 
-func (svc JournalBookHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.JournalBook) (common.BulkImport, error) {
+func (svc JournalBookHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.JournalBook) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	createDataList := []models.JournalBookDoc{}
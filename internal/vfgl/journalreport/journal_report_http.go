@@ -19,11 +19,10 @@ type JournalReportHttp struct {
 
 func NewJournalReportHttp(ms *microservice.Microservice, cfg config.IConfig) JournalReportHttp {
 
-	pstConfig := config.NewPersisterConfig()
-	pst := microservice.NewPersister(pstConfig)
+	pst := ms.Persister(cfg.PersisterConfig())
 	repoPg := NewJournalReportPgRepository(pst)
 
-	pstMongo := microservice.NewPersisterMongo(config.NewMongoPersisterConfig())
+	pstMongo := ms.MongoPersister(cfg.MongoPersisterConfig())
 	repoMongo := NewJournalMongoRepository(pstMongo)
 
 	jouralReportService := NewJournalReportService(repoPg, repoMongo)
@@ -317,7 +316,7 @@ func (r JournalReportHttp) ProcessReportLedgerAccount(ctx microservice.IContext)
 	}
 
 	r.ms.Logger.Debugf("Start Process Ledger Account %v:%v", startDate, endDate)
-	reportData, err := r.svc.ProcessLedgerAccount(shopID, accountGroup, creditorCode, debtorCode, consolidateAccountCode, accRanges, startDate.UTC(), endDate.UTC())
+	reportData, err := r.svc.ProcessLedgerAccount(ctx.Context(), shopID, accountGroup, creditorCode, debtorCode, consolidateAccountCode, accRanges, startDate.UTC(), endDate.UTC())
 	if err != nil {
 		ctx.ResponseError(500, fmt.Sprintf("Failed on Process Report : %v.", err.Error()))
 		return err
@@ -6,6 +6,7 @@ import (
 	chartofaccountModel "smlaicloudplatform/internal/vfgl/chartofaccount/models"
 	"smlaicloudplatform/internal/vfgl/journalreport/models"
 	"smlaicloudplatform/internal/vfgl/journalreport/usecase"
+	"smlaicloudplatform/pkg/microservice"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -15,7 +16,7 @@ type IJournalReportService interface {
 	ProcessTrialBalanceSheetReport(shopId string, accountGroup string, includeCloseAccountMode bool, startDate time.Time, endDate time.Time) (*models.TrialBalanceSheetReport, error)
 	ProcessProfitAndLossSheetReport(shopId string, accountGroup string, includeCloseAccountMode bool, startDate time.Time, endDate time.Time) (*models.ProfitAndLossSheetReport, error)
 	ProcessBalanceSheetReport(shopId string, accountGroup string, includeCloseAccountMode bool, endDate time.Time) (*models.BalanceSheetReport, error)
-	ProcessLedgerAccount(shopId string, accountGroup string, creditorCode string, debtorCode string, consolidateAccountCode string, accountRanges []models.LedgerAccountCodeRange, startDate time.Time, endDate time.Time) ([]models.LedgerAccount, error)
+	ProcessLedgerAccount(ctx context.Context, shopId string, accountGroup string, creditorCode string, debtorCode string, consolidateAccountCode string, accountRanges []models.LedgerAccountCodeRange, startDate time.Time, endDate time.Time) ([]models.LedgerAccount, error)
 }
 
 type JournalReportService struct {
@@ -39,8 +40,8 @@ func NewJournalReportService(repoPg IJournalReportPgRepository, repoMongo IJourn
 	}
 }
 
-func (svc JournalReportService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc JournalReportService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
 func (svc JournalReportService) ProcessTrialBalanceSheetReport(shopId string, accountGroup string, includeCloseAccountMode bool, startDate time.Time, endDate time.Time) (*models.TrialBalanceSheetReport, error) {
@@ -225,9 +226,9 @@ func (svc JournalReportService) ProcessBalanceSheetReport(shopId string, account
 	return result, nil
 }
 
-func (svc JournalReportService) ProcessLedgerAccount(shopID string, accountGroup string, creditorCode string, debtorCode string, consolidateAccountCode string, accountRanges []models.LedgerAccountCodeRange, startDate time.Time, endDate time.Time) ([]models.LedgerAccount, error) {
+func (svc JournalReportService) ProcessLedgerAccount(ctx context.Context, shopID string, accountGroup string, creditorCode string, debtorCode string, consolidateAccountCode string, accountRanges []models.LedgerAccountCodeRange, startDate time.Time, endDate time.Time) ([]models.LedgerAccount, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	rawDocList, err := svc.repoPg.GetDataLedgerAccount(shopID, accountGroup, creditorCode, debtorCode, consolidateAccountCode, accountRanges, startDate, endDate)
@@ -263,7 +263,7 @@ func TestLedgerAccount(t *testing.T) {
 	repoMongo.On("FindCountImageByDocs", mock.Anything, "TESTSHOP", []string{"DOC001", "DOC002", "DOC003", ""}).Return([]models.JournalImageSummary{}, nil)
 
 	service := journalreport.NewJournalReportService(repo, repoMongo)
-	docList, err := service.ProcessLedgerAccount("TESTSHOP", "accGroup", "", "", "conAcc", []models.LedgerAccountCodeRange{
+	docList, err := service.ProcessLedgerAccount(context.Background(), "TESTSHOP", "accGroup", "", "", "conAcc", []models.LedgerAccountCodeRange{
 		{
 			Start: "100000",
 			End:   "150000",
@@ -438,7 +438,7 @@ func TestLedgerAccount2(t *testing.T) {
 	repoMongo.On("FindCountImageByDocs", mock.Anything, "TESTSHOP", []string{""}).Return([]models.JournalImageSummary{}, nil)
 
 	service := journalreport.NewJournalReportService(repo, repoMongo)
-	docList, err := service.ProcessLedgerAccount("TESTSHOP", "accGroup", "", "", "conAcc", []models.LedgerAccountCodeRange{
+	docList, err := service.ProcessLedgerAccount(context.Background(), "TESTSHOP", "accGroup", "", "", "conAcc", []models.LedgerAccountCodeRange{
 		{
 			Start: "100000",
 			End:   "150000",
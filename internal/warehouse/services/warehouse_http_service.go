@@ -12,6 +12,7 @@ import (
 	"smlaicloudplatform/internal/utils/importdata"
 	"smlaicloudplatform/internal/warehouse/models"
 	"smlaicloudplatform/internal/warehouse/repositories"
+	"smlaicloudplatform/pkg/microservice"
 	micromodels "smlaicloudplatform/pkg/microservice/models"
 	"time"
 
@@ -22,28 +23,28 @@ import (
 )
 
 type IWarehouseHttpService interface {
-	CreateWarehouse(shopID string, authUsername string, doc models.Warehouse) (string, error)
-	UpdateWarehouse(shopID string, guid string, authUsername string, doc models.Warehouse) error
-	DeleteWarehouse(shopID string, guid string, authUsername string) error
-	DeleteWarehouseByGUIDs(shopID string, authUsername string, GUIDs []string) error
-	InfoWarehouse(shopID string, guid string) (models.WarehouseInfo, error)
-	InfoWarehouseByCode(shopID string, code string) (models.WarehouseInfo, error)
-	SearchWarehouse(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WarehouseInfo, mongopagination.PaginationData, error)
-	SearchWarehouseStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.WarehouseInfo, int, error)
-	SaveInBatch(shopID string, authUsername string, dataList []models.Warehouse) (common.BulkImport, error)
-
-	SearchLocation(shopID string, pageable micromodels.Pageable) ([]models.LocationInfo, mongopagination.PaginationData, error)
-	SearchShelf(shopID string, pageable micromodels.Pageable) ([]models.ShelfInfo, mongopagination.PaginationData, error)
-
-	InfoLocation(shopID, warehouseCode, locationCode string) (models.LocationInfo, error)
-	CreateLocation(shopID, authUsername, warehouseCode string, doc models.LocationRequest) error
-	UpdateLocation(shopID, authUsername, warehouseCode, locationCode string, doc models.LocationRequest) error
-	DeleteLocationByCodes(shopID, authUsername, warehouseCode string, locationCodes []string) error
-
-	InfoShelf(shopID, warehouseCode, locationCode, shelfCode string) (models.ShelfInfo, error)
-	CreateShelf(shopID, authUsername, warehouseCode, locationCode string, doc models.ShelfRequest) error
-	UpdateShelf(shopID, authUsername, warehouseCode, locationCode, shelfCode string, doc models.ShelfRequest) error
-	DeleteShelfByCodes(shopID, authUsername, warehouseCode, locationCode string, shelfCodes []string) error
+	CreateWarehouse(ctx context.Context, shopID string, authUsername string, doc models.Warehouse) (string, error)
+	UpdateWarehouse(ctx context.Context, shopID string, guid string, authUsername string, doc models.Warehouse) error
+	DeleteWarehouse(ctx context.Context, shopID string, guid string, authUsername string) error
+	DeleteWarehouseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error
+	InfoWarehouse(ctx context.Context, shopID string, guid string) (models.WarehouseInfo, error)
+	InfoWarehouseByCode(ctx context.Context, shopID string, code string) (models.WarehouseInfo, error)
+	SearchWarehouse(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WarehouseInfo, mongopagination.PaginationData, error)
+	SearchWarehouseStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.WarehouseInfo, int, error)
+	SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Warehouse) (common.BulkImport, error)
+
+	SearchLocation(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.LocationInfo, mongopagination.PaginationData, error)
+	SearchShelf(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ShelfInfo, mongopagination.PaginationData, error)
+
+	InfoLocation(ctx context.Context, shopID, warehouseCode, locationCode string) (models.LocationInfo, error)
+	CreateLocation(ctx context.Context, shopID, authUsername, warehouseCode string, doc models.LocationRequest) error
+	UpdateLocation(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, doc models.LocationRequest) error
+	DeleteLocationByCodes(ctx context.Context, shopID, authUsername, warehouseCode string, locationCodes []string) error
+
+	InfoShelf(ctx context.Context, shopID, warehouseCode, locationCode, shelfCode string) (models.ShelfInfo, error)
+	CreateShelf(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, doc models.ShelfRequest) error
+	UpdateShelf(ctx context.Context, shopID, authUsername, warehouseCode, locationCode, shelfCode string, doc models.ShelfRequest) error
+	DeleteShelfByCodes(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, shelfCodes []string) error
 
 	GetModuleName() string
 }
@@ -73,13 +74,13 @@ func NewWarehouseHttpService(repo repositories.IWarehouseRepository, repoMq repo
 	return insSvc
 }
 
-func (svc WarehouseHttpService) getContextTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), svc.contextTimeout)
+func (svc WarehouseHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
 }
 
-func (svc WarehouseHttpService) CreateWarehouse(shopID string, authUsername string, doc models.Warehouse) (string, error) {
+func (svc WarehouseHttpService) CreateWarehouse(ctx context.Context, shopID string, authUsername string, doc models.Warehouse) (string, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", doc.Code)
@@ -120,9 +121,9 @@ func (svc WarehouseHttpService) CreateWarehouse(shopID string, authUsername stri
 	return newGuidFixed, nil
 }
 
-func (svc WarehouseHttpService) UpdateWarehouse(shopID string, guid string, authUsername string, doc models.Warehouse) error {
+func (svc WarehouseHttpService) UpdateWarehouse(ctx context.Context, shopID string, guid string, authUsername string, doc models.Warehouse) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -156,9 +157,9 @@ func (svc WarehouseHttpService) UpdateWarehouse(shopID string, guid string, auth
 	return nil
 }
 
-func (svc WarehouseHttpService) CreateLocation(shopID, authUsername, warehouseCode string, doc models.LocationRequest) error {
+func (svc WarehouseHttpService) CreateLocation(ctx context.Context, shopID, authUsername, warehouseCode string, doc models.LocationRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", warehouseCode)
@@ -204,9 +205,9 @@ func (svc WarehouseHttpService) CreateLocation(shopID, authUsername, warehouseCo
 	return nil
 }
 
-func (svc WarehouseHttpService) UpdateLocation(shopID, authUsername, warehouseCode, locationCode string, doc models.LocationRequest) error {
+func (svc WarehouseHttpService) UpdateLocation(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, doc models.LocationRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	updateDoc := models.WarehouseDoc{}
@@ -340,9 +341,9 @@ func (svc WarehouseHttpService) UpdateLocation(shopID, authUsername, warehouseCo
 	return nil
 }
 
-func (svc WarehouseHttpService) DeleteLocationByCodes(shopID, authUsername, warehouseCode string, locationCodes []string) error {
+func (svc WarehouseHttpService) DeleteLocationByCodes(ctx context.Context, shopID, authUsername, warehouseCode string, locationCodes []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	removeDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", warehouseCode)
@@ -386,9 +387,9 @@ func (svc WarehouseHttpService) DeleteLocationByCodes(shopID, authUsername, ware
 	return nil
 }
 
-func (svc WarehouseHttpService) CreateShelf(shopID, authUsername, warehouseCode, locationCode string, doc models.ShelfRequest) error {
+func (svc WarehouseHttpService) CreateShelf(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, doc models.ShelfRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindWarehouseByShelf(ctx, shopID, warehouseCode, locationCode, doc.Code)
@@ -432,9 +433,9 @@ func (svc WarehouseHttpService) CreateShelf(shopID, authUsername, warehouseCode,
 	return nil
 }
 
-func (svc WarehouseHttpService) UpdateShelf(shopID, authUsername, warehouseCode, locationCode, shelfCode string, doc models.ShelfRequest) error {
+func (svc WarehouseHttpService) UpdateShelf(ctx context.Context, shopID, authUsername, warehouseCode, locationCode, shelfCode string, doc models.ShelfRequest) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	updateDoc := models.WarehouseDoc{}
@@ -554,9 +555,9 @@ func (svc WarehouseHttpService) UpdateShelf(shopID, authUsername, warehouseCode,
 	return nil
 }
 
-func (svc WarehouseHttpService) DeleteShelfByCodes(shopID, authUsername, warehouseCode, locationCode string, shelfCodes []string) error {
+func (svc WarehouseHttpService) DeleteShelfByCodes(ctx context.Context, shopID, authUsername, warehouseCode, locationCode string, shelfCodes []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	removeDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", warehouseCode)
@@ -612,9 +613,9 @@ func (svc WarehouseHttpService) DeleteShelfByCodes(shopID, authUsername, warehou
 	return nil
 }
 
-func (svc WarehouseHttpService) DeleteWarehouse(shopID string, guid string, authUsername string) error {
+func (svc WarehouseHttpService) DeleteWarehouse(ctx context.Context, shopID string, guid string, authUsername string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -640,9 +641,9 @@ func (svc WarehouseHttpService) DeleteWarehouse(shopID string, guid string, auth
 	return nil
 }
 
-func (svc WarehouseHttpService) DeleteWarehouseByGUIDs(shopID string, authUsername string, GUIDs []string) error {
+func (svc WarehouseHttpService) DeleteWarehouseByGUIDs(ctx context.Context, shopID string, authUsername string, GUIDs []string) error {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	deleteFilterQuery := map[string]interface{}{
@@ -661,9 +662,9 @@ func (svc WarehouseHttpService) DeleteWarehouseByGUIDs(shopID string, authUserna
 	return nil
 }
 
-func (svc WarehouseHttpService) InfoWarehouse(shopID string, guid string) (models.WarehouseInfo, error) {
+func (svc WarehouseHttpService) InfoWarehouse(ctx context.Context, shopID string, guid string) (models.WarehouseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
@@ -679,9 +680,9 @@ func (svc WarehouseHttpService) InfoWarehouse(shopID string, guid string) (model
 	return findDoc.WarehouseInfo, nil
 }
 
-func (svc WarehouseHttpService) InfoWarehouseByCode(shopID string, code string) (models.WarehouseInfo, error) {
+func (svc WarehouseHttpService) InfoWarehouseByCode(ctx context.Context, shopID string, code string) (models.WarehouseInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", code)
@@ -697,9 +698,9 @@ func (svc WarehouseHttpService) InfoWarehouseByCode(shopID string, code string)
 	return findDoc.WarehouseInfo, nil
 }
 
-func (svc WarehouseHttpService) InfoLocation(shopID, warehouseCode, locationCode string) (models.LocationInfo, error) {
+func (svc WarehouseHttpService) InfoLocation(ctx context.Context, shopID, warehouseCode, locationCode string) (models.LocationInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", warehouseCode)
@@ -730,9 +731,9 @@ func (svc WarehouseHttpService) InfoLocation(shopID, warehouseCode, locationCode
 	return locationInfo, nil
 }
 
-func (svc WarehouseHttpService) InfoShelf(shopID, warehouseCode, locationCode, shelfCode string) (models.ShelfInfo, error) {
+func (svc WarehouseHttpService) InfoShelf(ctx context.Context, shopID, warehouseCode, locationCode, shelfCode string) (models.ShelfInfo, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	findDoc, err := svc.repo.FindByDocIndentityGuid(ctx, shopID, "code", warehouseCode)
@@ -769,9 +770,9 @@ func (svc WarehouseHttpService) InfoShelf(shopID, warehouseCode, locationCode, s
 	return shelfInfo, nil
 }
 
-func (svc WarehouseHttpService) SearchWarehouse(shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WarehouseInfo, mongopagination.PaginationData, error) {
+func (svc WarehouseHttpService) SearchWarehouse(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WarehouseInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -787,9 +788,9 @@ func (svc WarehouseHttpService) SearchWarehouse(shopID string, filters map[strin
 	return docList, pagination, nil
 }
 
-func (svc WarehouseHttpService) SearchWarehouseStep(shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.WarehouseInfo, int, error) {
+func (svc WarehouseHttpService) SearchWarehouseStep(ctx context.Context, shopID string, langCode string, pageableStep micromodels.PageableStep) ([]models.WarehouseInfo, int, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	searchInFields := []string{
@@ -807,9 +808,9 @@ func (svc WarehouseHttpService) SearchWarehouseStep(shopID string, langCode stri
 	return docList, total, nil
 }
 
-func (svc WarehouseHttpService) SearchLocation(shopID string, pageable micromodels.Pageable) ([]models.LocationInfo, mongopagination.PaginationData, error) {
+func (svc WarehouseHttpService) SearchLocation(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.LocationInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, pagination, err := svc.repo.FindLocationPage(ctx, shopID, pageable)
@@ -821,9 +822,9 @@ func (svc WarehouseHttpService) SearchLocation(shopID string, pageable micromode
 	return docList, pagination, nil
 }
 
-func (svc WarehouseHttpService) SearchShelf(shopID string, pageable micromodels.Pageable) ([]models.ShelfInfo, mongopagination.PaginationData, error) {
+func (svc WarehouseHttpService) SearchShelf(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.ShelfInfo, mongopagination.PaginationData, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	docList, pagination, err := svc.repo.FindShelfPage(ctx, shopID, pageable)
@@ -835,9 +836,9 @@ func (svc WarehouseHttpService) SearchShelf(shopID string, pageable micromodels.
 	return docList, pagination, nil
 }
 
-func (svc WarehouseHttpService) SaveInBatch(shopID string, authUsername string, dataList []models.Warehouse) (common.BulkImport, error) {
+func (svc WarehouseHttpService) SaveInBatch(ctx context.Context, shopID string, authUsername string, dataList []models.Warehouse) (common.BulkImport, error) {
 
-	ctx, ctxCancel := svc.getContextTimeout()
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
 	defer ctxCancel()
 
 	payloadList, payloadDuplicateList := importdata.FilterDuplicate[models.Warehouse](dataList, svc.getDocIDKey)
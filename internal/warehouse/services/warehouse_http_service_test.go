@@ -158,7 +158,7 @@ func TestUpdateLocation(t *testing.T) {
 			},
 		}
 
-		err := svc.UpdateLocation(shopID, authUsername, warehouseCode, locationCode, doc)
+		err := svc.UpdateLocation(context.Background(), shopID, authUsername, warehouseCode, locationCode, doc)
 		assert.NoError(t, err)
 	})
 
@@ -186,7 +186,7 @@ func TestUpdateLocation(t *testing.T) {
 			*common.NewNameXWithCodeName("lo1", "location 1"),
 		}
 
-		err := svc.UpdateLocation(shopID, authUsername, warehouseCode, locationCode, doc)
+		err := svc.UpdateLocation(context.Background(), shopID, authUsername, warehouseCode, locationCode, doc)
 		assert.NoError(t, err)
 	})
 
@@ -204,7 +204,7 @@ func TestUpdateLocation(t *testing.T) {
 			},
 		}
 
-		err := svc.UpdateLocation(shopID, authUsername, warehouseCode, locationCode, doc)
+		err := svc.UpdateLocation(context.Background(), shopID, authUsername, warehouseCode, locationCode, doc)
 		assert.Error(t, err)
 		assert.Equal(t, errors.New("document not found"), err)
 	})
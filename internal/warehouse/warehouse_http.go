@@ -92,7 +92,7 @@ func (h WarehouseHttp) CreateWarehouse(ctx microservice.IContext) error {
 		return err
 	}
 
-	idx, err := h.svc.CreateWarehouse(shopID, authUsername, *docReq)
+	idx, err := h.svc.CreateWarehouse(ctx.Context(), shopID, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -137,7 +137,7 @@ func (h WarehouseHttp) UpdateWarehouse(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateWarehouse(shopID, id, authUsername, *docReq)
+	err = h.svc.UpdateWarehouse(ctx.Context(), shopID, id, authUsername, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -182,7 +182,7 @@ func (h WarehouseHttp) CreateLocation(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.CreateLocation(shopID, authUsername, warehouseCode, *docReq)
+	err = h.svc.CreateLocation(ctx.Context(), shopID, authUsername, warehouseCode, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -229,7 +229,7 @@ func (h WarehouseHttp) UpdateLocation(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateLocation(shopID, authUsername, warehouseCode, locationCode, *docReq)
+	err = h.svc.UpdateLocation(ctx.Context(), shopID, authUsername, warehouseCode, locationCode, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -270,7 +270,7 @@ func (h WarehouseHttp) DeleteLocation(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteLocationByCodes(shopID, authUsername, warehouseCode, docReq)
+	err = h.svc.DeleteLocationByCodes(ctx.Context(), shopID, authUsername, warehouseCode, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -316,7 +316,7 @@ func (h WarehouseHttp) CreateShelf(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.CreateShelf(shopID, authUsername, warehouseCode, locationCode, *docReq)
+	err = h.svc.CreateShelf(ctx.Context(), shopID, authUsername, warehouseCode, locationCode, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -365,7 +365,7 @@ func (h WarehouseHttp) UpdateShelf(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.UpdateShelf(shopID, authUsername, warehouseCode, locationCode, shelfCode, *docReq)
+	err = h.svc.UpdateShelf(ctx.Context(), shopID, authUsername, warehouseCode, locationCode, shelfCode, *docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -408,7 +408,7 @@ func (h WarehouseHttp) DeleteShelf(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteShelfByCodes(shopID, authUsername, warehouseCode, locationCode, docReq)
+	err = h.svc.DeleteShelfByCodes(ctx.Context(), shopID, authUsername, warehouseCode, locationCode, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -438,7 +438,7 @@ func (h WarehouseHttp) DeleteWarehouse(ctx microservice.IContext) error {
 
 	id := ctx.Param("id")
 
-	err := h.svc.DeleteWarehouse(shopID, id, authUsername)
+	err := h.svc.DeleteWarehouse(ctx.Context(), shopID, id, authUsername)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -477,7 +477,7 @@ func (h WarehouseHttp) DeleteWarehouseByGUIDs(ctx microservice.IContext) error {
 		return err
 	}
 
-	err = h.svc.DeleteWarehouseByGUIDs(shopID, authUsername, docReq)
+	err = h.svc.DeleteWarehouseByGUIDs(ctx.Context(), shopID, authUsername, docReq)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -507,7 +507,7 @@ func (h WarehouseHttp) InfoWarehouse(ctx microservice.IContext) error {
 	id := ctx.Param("id")
 
 	h.ms.Logger.Debugf("Get Warehouse %v", id)
-	doc, err := h.svc.InfoWarehouse(shopID, id)
+	doc, err := h.svc.InfoWarehouse(ctx.Context(), shopID, id)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document %v: %v", id, err)
@@ -537,7 +537,7 @@ func (h WarehouseHttp) InfoWarehouseByCode(ctx microservice.IContext) error {
 
 	code := ctx.Param("code")
 
-	doc, err := h.svc.InfoWarehouseByCode(shopID, code)
+	doc, err := h.svc.InfoWarehouseByCode(ctx.Context(), shopID, code)
 
 	if err != nil {
 		h.ms.Logger.Errorf("Error getting document by code %v: %v", code, err)
@@ -569,7 +569,7 @@ func (h WarehouseHttp) SearchWarehousePage(ctx microservice.IContext) error {
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchWarehouse(shopID, map[string]interface{}{}, pageable)
+	docList, pagination, err := h.svc.SearchWarehouse(ctx.Context(), shopID, map[string]interface{}{}, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -601,7 +601,7 @@ func (h WarehouseHttp) InfoLocation(ctx microservice.IContext) error {
 	warehouseCode := ctx.Param("warehouseCode")
 	locationCode := ctx.Param("locationCode")
 
-	doc, err := h.svc.InfoLocation(shopID, warehouseCode, locationCode)
+	doc, err := h.svc.InfoLocation(ctx.Context(), shopID, warehouseCode, locationCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -632,7 +632,7 @@ func (h WarehouseHttp) SearchWarehouseLocationPage(ctx microservice.IContext) er
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchLocation(shopID, pageable)
+	docList, pagination, err := h.svc.SearchLocation(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -666,7 +666,7 @@ func (h WarehouseHttp) InfoShelf(ctx microservice.IContext) error {
 	locationCode := ctx.Param("locationCode")
 	shelfCode := ctx.Param("shelfCode")
 
-	doc, err := h.svc.InfoShelf(shopID, warehouseCode, locationCode, shelfCode)
+	doc, err := h.svc.InfoShelf(ctx.Context(), shopID, warehouseCode, locationCode, shelfCode)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -697,7 +697,7 @@ func (h WarehouseHttp) SearchWarehouseLocationShelfPage(ctx microservice.IContex
 
 	pageable := utils.GetPageable(ctx.QueryParam)
 
-	docList, pagination, err := h.svc.SearchShelf(shopID, pageable)
+	docList, pagination, err := h.svc.SearchShelf(ctx.Context(), shopID, pageable)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -732,7 +732,7 @@ func (h WarehouseHttp) SearchWarehouseStep(ctx microservice.IContext) error {
 
 	lang := ctx.QueryParam("lang")
 
-	docList, total, err := h.svc.SearchWarehouseStep(shopID, lang, pageableStep)
+	docList, total, err := h.svc.SearchWarehouseStep(ctx.Context(), shopID, lang, pageableStep)
 
 	if err != nil {
 		ctx.ResponseError(http.StatusBadRequest, err.Error())
@@ -772,7 +772,7 @@ func (h WarehouseHttp) SaveBulk(ctx microservice.IContext) error {
 		return err
 	}
 
-	bulkResponse, err := h.svc.SaveInBatch(shopID, authUsername, dataReq)
+	bulkResponse, err := h.svc.SaveInBatch(ctx.Context(), shopID, authUsername, dataReq)
 
 	if err != nil {
 		ctx.ResponseError(400, err.Error())
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"smlaicloudplatform/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const webhookCollectionName = "webhooks"
+
+// event types which can be subscribed, they are fed from message queue topics of the documents
+const (
+	EventSaleInvoiceCreated = "saleinvoice.created"
+	EventSaleInvoiceUpdated = "saleinvoice.updated"
+	EventSaleInvoiceDeleted = "saleinvoice.deleted"
+
+	EventPurchaseCreated = "purchase.created"
+	EventPurchaseUpdated = "purchase.updated"
+	EventPurchaseDeleted = "purchase.deleted"
+
+	EventDebtorPaymentCreated = "debtorpayment.created"
+	EventDebtorPaymentUpdated = "debtorpayment.updated"
+	EventDebtorPaymentDeleted = "debtorpayment.deleted"
+
+	// EventStockBalanceChanged is sent when stock of the barcode is recalculated
+	EventStockBalanceChanged = "stockbalance.changed"
+)
+
+var EventTypes = []string{
+	EventSaleInvoiceCreated,
+	EventSaleInvoiceUpdated,
+	EventSaleInvoiceDeleted,
+	EventPurchaseCreated,
+	EventPurchaseUpdated,
+	EventPurchaseDeleted,
+	EventDebtorPaymentCreated,
+	EventDebtorPaymentUpdated,
+	EventDebtorPaymentDeleted,
+	EventStockBalanceChanged,
+}
+
+// IsEventType return true when the event type can be subscribed
+func IsEventType(eventType string) bool {
+	for _, item := range EventTypes {
+		if item == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is subscription of the shop, events of the event types are posted to the url
+type Webhook struct {
+	Name       string   `json:"name" bson:"name" validate:"required,min=1,max=255"`
+	URL        string   `json:"url" bson:"url" validate:"required,url,max=2048"`
+	EventTypes []string `json:"eventtypes" bson:"eventtypes" validate:"required,min=1"`
+	// Secret sign payloads, it is generated when it is empty on create
+	Secret   string `json:"secret" bson:"secret"`
+	IsActive bool   `json:"isactive" bson:"isactive"`
+}
+
+// SecretFields are encrypted at rest and redacted in api response
+func (doc *Webhook) SecretFields() []*string {
+	return []*string{&doc.Secret}
+}
+
+type WebhookInfo struct {
+	models.DocIdentity `bson:"inline"`
+	Webhook            `bson:"inline"`
+}
+
+func (WebhookInfo) CollectionName() string {
+	return webhookCollectionName
+}
+
+type WebhookData struct {
+	models.ShopIdentity `bson:"inline"`
+	WebhookInfo         `bson:"inline"`
+}
+
+type WebhookDoc struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	WebhookData        `bson:"inline"`
+	models.ActivityDoc `bson:"inline"`
+}
+
+func (WebhookDoc) CollectionName() string {
+	return webhookCollectionName
+}
+
+// WebhookCreated is response of create, Secret is shown so generated secret can be kept by the subscriber
+type WebhookCreated struct {
+	GuidFixed string `json:"guidfixed"`
+	Secret    string `json:"secret"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const webhookDeliveryCollectionName = "webhookDeliveries"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// headers of the request which is posted to the subscriber url
+const (
+	HeaderWebhookID        = "X-Webhook-Id"
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	// HeaderWebhookSignature is "sha256=" and hex of hmac sha256 of "<timestamp>.<body>" by the secret
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// WebhookEvent is body of the request, Data is the document of the message queue event
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	ShopID     string          `json:"shopid"`
+	OccurredAt time.Time       `json:"occurredat"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// DeliveryAttempt is one request of the delivery
+type DeliveryAttempt struct {
+	AttemptedAt time.Time `json:"attemptedat" bson:"attemptedat"`
+	StatusCode  int       `json:"statuscode" bson:"statuscode"`
+	Error       string    `json:"error" bson:"error,omitempty"`
+	DurationMs  int64     `json:"durationms" bson:"durationms"`
+}
+
+// WebhookDelivery is event which is posted to url of the webhook until it succeed or attempts run out
+type WebhookDelivery struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ShopID      string             `json:"shopid" bson:"shopid"`
+	WebhookGuid string             `json:"webhookguid" bson:"webhookguid"`
+	EventID     string             `json:"eventid" bson:"eventid"`
+	EventType   string             `json:"eventtype" bson:"eventtype"`
+	URL         string             `json:"url" bson:"url"`
+	Payload     string             `json:"payload" bson:"payload"`
+	Status      string             `json:"status" bson:"status"`
+	Attempts    int                `json:"attempts" bson:"attempts"`
+	// LastStatusCode is 0 when the url cannot be reached
+	LastStatusCode int               `json:"laststatuscode" bson:"laststatuscode"`
+	LastError      string            `json:"lasterror" bson:"lasterror"`
+	AttemptLogs    []DeliveryAttempt `json:"attemptlogs" bson:"attemptlogs"`
+	CreatedAt      time.Time         `json:"createdat" bson:"createdat"`
+	NextAttemptAt  time.Time         `json:"nextattemptat" bson:"nextattemptat"`
+	DeliveredAt    *time.Time        `json:"deliveredat,omitempty" bson:"deliveredat,omitempty"`
+	RedeliveredBy  string            `json:"redeliveredby,omitempty" bson:"redeliveredby,omitempty"`
+}
+
+func (WebhookDelivery) CollectionName() string {
+	return webhookDeliveryCollectionName
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/utils/search"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxAttemptLogs is number of latest attempts which are kept in the delivery
+const maxAttemptLogs = 20
+
+type IWebhookDeliveryRepository interface {
+	// Create return false when the event is already delivered to the webhook
+	Create(ctx context.Context, doc models.WebhookDelivery) (bool, error)
+	Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (models.WebhookDelivery, bool, error)
+	MarkSucceeded(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt) error
+	MarkFailed(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt, status string, nextAttemptAt time.Time) error
+	Redeliver(ctx context.Context, shopID string, id primitive.ObjectID, username string, now time.Time) (bool, error)
+	FindByID(ctx context.Context, shopID string, id primitive.ObjectID) (models.WebhookDelivery, error)
+	FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.WebhookDelivery, mongopagination.PaginationData, error)
+}
+
+type WebhookDeliveryRepository struct {
+	pst microservice.IPersisterMongo
+}
+
+func NewWebhookDeliveryRepository(pst microservice.IPersisterMongo) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		pst: pst,
+	}
+}
+
+func (repo WebhookDeliveryRepository) Create(ctx context.Context, doc models.WebhookDelivery) (bool, error) {
+	_, err := repo.pst.Create(ctx, &models.WebhookDelivery{}, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Claim return pending delivery which is due and hide it from other dispatchers until claim timeout,
+// deliveries of every shop are claimed
+func (repo WebhookDeliveryRepository) Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (models.WebhookDelivery, bool, error) {
+	claimed := models.WebhookDelivery{}
+	err := repo.pst.FindOneAndUpdate(
+		microservice.WithSystemAdmin(ctx),
+		&models.WebhookDelivery{},
+		bson.M{
+			"status":        models.DeliveryStatusPending,
+			"nextattemptat": bson.M{"$lte": now},
+		},
+		bson.M{
+			"$set": bson.M{"nextattemptat": now.Add(claimTimeout)},
+			"$inc": bson.M{"attempts": 1},
+		},
+		&claimed,
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "nextattemptat", Value: 1}}).
+			SetReturnDocument(options.After),
+	)
+
+	if err != nil {
+		return models.WebhookDelivery{}, false, err
+	}
+
+	if claimed.ID.IsZero() {
+		return models.WebhookDelivery{}, false, nil
+	}
+
+	return claimed, true, nil
+}
+
+func (repo WebhookDeliveryRepository) MarkSucceeded(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt) error {
+	return repo.pst.Update(ctx, &models.WebhookDelivery{}, bson.M{"shopid": doc.ShopID, "_id": doc.ID}, bson.M{
+		"$set": bson.M{
+			"status":         models.DeliveryStatusSucceeded,
+			"laststatuscode": attempt.StatusCode,
+			"lasterror":      "",
+			"deliveredat":    attempt.AttemptedAt,
+		},
+		"$push": pushAttemptLog(attempt),
+	})
+}
+
+// MarkFailed record the attempt, status is pending when it is retried at next attempt or failed when attempts run out
+func (repo WebhookDeliveryRepository) MarkFailed(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	return repo.pst.Update(ctx, &models.WebhookDelivery{}, bson.M{"shopid": doc.ShopID, "_id": doc.ID}, bson.M{
+		"$set": bson.M{
+			"status":         status,
+			"laststatuscode": attempt.StatusCode,
+			"lasterror":      attempt.Error,
+			"nextattemptat":  nextAttemptAt,
+		},
+		"$push": pushAttemptLog(attempt),
+	})
+}
+
+func pushAttemptLog(attempt models.DeliveryAttempt) bson.M {
+	return bson.M{
+		"attemptlogs": bson.M{
+			"$each":  []models.DeliveryAttempt{attempt},
+			"$slice": -maxAttemptLogs,
+		},
+	}
+}
+
+// Redeliver queue the delivery again with attempts reset, delivery which is waiting to be sent is not changed
+func (repo WebhookDeliveryRepository) Redeliver(ctx context.Context, shopID string, id primitive.ObjectID, username string, now time.Time) (bool, error) {
+	collection, err := repo.pst.Exec(microservice.WithCollectionShopID(ctx, shopID), &models.WebhookDelivery{})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.UpdateOne(ctx, bson.M{
+		"shopid": shopID,
+		"_id":    id,
+		"status": bson.M{"$ne": models.DeliveryStatusPending},
+	}, bson.M{
+		"$set": bson.M{
+			"status":        models.DeliveryStatusPending,
+			"attempts":      0,
+			"nextattemptat": now,
+			"redeliveredby": username,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo WebhookDeliveryRepository) FindByID(ctx context.Context, shopID string, id primitive.ObjectID) (models.WebhookDelivery, error) {
+	doc := models.WebhookDelivery{}
+	err := repo.pst.FindOne(ctx, &models.WebhookDelivery{}, bson.M{"shopid": shopID, "_id": id}, &doc)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	return doc, nil
+}
+
+func (repo WebhookDeliveryRepository) FindPageFilter(ctx context.Context, shopID string, filters map[string]interface{}, searchInFields []string, pageable micromodels.Pageable) ([]models.WebhookDelivery, mongopagination.PaginationData, error) {
+
+	queryFilters := bson.M{
+		"shopid": shopID,
+	}
+
+	for key, value := range filters {
+		queryFilters[key] = value
+	}
+
+	searchFilterQuery := search.CreateTextFilter(searchInFields, pageable.Query)
+	if len(searchFilterQuery) > 0 {
+		queryFilters["$or"] = searchFilterQuery
+	}
+
+	docList := []models.WebhookDelivery{}
+	pagination, err := repo.pst.FindPage(ctx, &models.WebhookDelivery{}, queryFilters, pageable, &docList)
+
+	if err != nil {
+		return []models.WebhookDelivery{}, mongopagination.PaginationData{}, err
+	}
+
+	return docList, pagination, nil
+}
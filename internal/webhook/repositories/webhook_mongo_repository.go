@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/repositories"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IWebhookRepository interface {
+	Create(ctx context.Context, doc models.WebhookDoc) (string, error)
+	Update(ctx context.Context, shopID string, guid string, doc models.WebhookDoc) error
+	DeleteByGuidfixed(ctx context.Context, shopID string, guid string, username string) error
+	FindByGuid(ctx context.Context, shopID string, guid string) (models.WebhookDoc, error)
+	FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.WebhookInfo, mongopagination.PaginationData, error)
+	FindActiveByEventType(ctx context.Context, shopID string, eventType string) ([]models.WebhookDoc, error)
+}
+
+// WebhookRepository encrypt secret before it is written and decrypt it after it is read
+type WebhookRepository struct {
+	pst       microservice.IPersisterMongo
+	encryptor encrypt.IFieldEncryptor
+	repositories.CrudRepository[models.WebhookDoc]
+	repositories.SearchRepository[models.WebhookInfo]
+}
+
+func NewWebhookRepository(pst microservice.IPersisterMongo, encryptor encrypt.IFieldEncryptor) *WebhookRepository {
+
+	insRepo := &WebhookRepository{
+		pst:       pst,
+		encryptor: encryptor,
+	}
+
+	insRepo.CrudRepository = repositories.NewCrudRepository[models.WebhookDoc](pst)
+	insRepo.SearchRepository = repositories.NewSearchRepository[models.WebhookInfo](pst)
+
+	return insRepo
+}
+
+func (repo WebhookRepository) Create(ctx context.Context, doc models.WebhookDoc) (string, error) {
+	err := repo.encryptor.EncryptFields(ctx, doc.ShopID, doc.SecretFields()...)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.CrudRepository.Create(ctx, doc)
+}
+
+func (repo WebhookRepository) Update(ctx context.Context, shopID string, guid string, doc models.WebhookDoc) error {
+	err := repo.encryptor.EncryptFields(ctx, shopID, doc.SecretFields()...)
+	if err != nil {
+		return err
+	}
+
+	return repo.CrudRepository.Update(ctx, shopID, guid, doc)
+}
+
+func (repo WebhookRepository) FindByGuid(ctx context.Context, shopID string, guid string) (models.WebhookDoc, error) {
+	doc, err := repo.CrudRepository.FindByGuid(ctx, shopID, guid)
+	if err != nil {
+		return doc, err
+	}
+
+	return doc, repo.encryptor.DecryptFields(ctx, doc.SecretFields()...)
+}
+
+func (repo WebhookRepository) FindPage(ctx context.Context, shopID string, searchInFields []string, pageable micromodels.Pageable) ([]models.WebhookInfo, mongopagination.PaginationData, error) {
+	docList, pagination, err := repo.SearchRepository.FindPage(ctx, shopID, searchInFields, pageable)
+	if err != nil {
+		return docList, pagination, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	return docList, pagination, nil
+}
+
+// FindActiveByEventType return active webhooks of the shop which subscribe the event type
+func (repo WebhookRepository) FindActiveByEventType(ctx context.Context, shopID string, eventType string) ([]models.WebhookDoc, error) {
+	docList := []models.WebhookDoc{}
+	err := repo.pst.Find(ctx, &models.WebhookDoc{}, bson.M{
+		"shopid":     shopID,
+		"eventtypes": eventType,
+		"isactive":   true,
+		"deletedat":  bson.M{"$exists": false},
+	}, &docList)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range docList {
+		err = repo.encryptor.DecryptFields(ctx, docList[i].SecretFields()...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docList, nil
+}
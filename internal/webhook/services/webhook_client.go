@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrWebhookAddressDenied = errors.New("webhook url must not be loopback, private or link-local address")
+
+// carrier-grade nat range is shared address space of the provider network and it is not reachable from internet
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP return true when ip is reachable address of internet, loopback, private, link-local
+// (cloud metadata 169.254.169.254), unspecified and multicast addresses are not public
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	return !sharedAddressSpace.Contains(ip)
+}
+
+// validateWebhookHost reject url which host is not public ip or localhost, host name is checked again
+// with its resolved address when the dispatcher dial it
+func validateWebhookHost(webhookURL *url.URL) error {
+	host := strings.ToLower(webhookURL.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrWebhookAddressDenied
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return ErrWebhookAddressDenied
+	}
+
+	return nil
+}
+
+// denyPrivateAddress is control of dialer which reject connection to address which is not public,
+// it check resolved address so host name which resolve to private address is rejected too
+func denyPrivateAddress(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressDenied, host)
+	}
+
+	return nil
+}
+
+// NewWebhookClient return client which post deliveries, redirect is not followed so 3xx response is failed attempt,
+// connection to private network is rejected unless allowPrivateNetwork is true
+func NewWebhookClient(timeout time.Duration, allowPrivateNetwork bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetwork {
+		dialer.Control = denyPrivateAddress
+	}
+
+	transport := &http.Transport{
+		// proxy of environment would dial the subscriber instead of the dialer so it is not used
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
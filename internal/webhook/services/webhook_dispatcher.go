@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WebhookDispatcher post due deliveries to urls of webhooks, failed delivery is retried with exponential backoff
+// until max attempts, a delivery is posted at least once so subscriber should skip X-Webhook-Id which it has seen.
+// Deliveries of a batch are posted concurrently so slow subscriber does not hold deliveries of other webhooks
+type WebhookDispatcher struct {
+	repo        repositories.IWebhookDeliveryRepository
+	webhookRepo repositories.IWebhookRepository
+	client      *http.Client
+	cfg         config.IWebhookConfig
+	logger      logger.ILogger
+	now         func() time.Time
+}
+
+func NewWebhookDispatcher(repo repositories.IWebhookDeliveryRepository, webhookRepo repositories.IWebhookRepository, cfg config.IWebhookConfig, logger logger.ILogger, now func() time.Time) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:        repo,
+		webhookRepo: webhookRepo,
+		client:      NewWebhookClient(cfg.RequestTimeout(), cfg.AllowPrivateNetwork()),
+		cfg:         cfg,
+		logger:      logger,
+		now:         now,
+	}
+}
+
+// Run poll deliveries and post them forever
+func (d *WebhookDispatcher) Run() {
+	for {
+		sent, err := d.DispatchPending()
+		if err != nil {
+			d.logger.Errorf("Webhook dispatcher failed: %v", err)
+		}
+
+		if sent < d.cfg.BatchSize() {
+			time.Sleep(d.cfg.PollInterval())
+		}
+	}
+}
+
+// DispatchPending claim up to batch size due deliveries and post them concurrently,
+// it return number of attempts which are made
+func (d *WebhookDispatcher) DispatchPending() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.cfg.RequestTimeout()+15*time.Second)
+	defer cancel()
+
+	deliveries := []models.WebhookDelivery{}
+	var dispatchErr error
+	for len(deliveries) < d.cfg.BatchSize() {
+		delivery, ok, err := d.repo.Claim(ctx, d.now(), d.cfg.ClaimTimeout())
+		if err != nil {
+			dispatchErr = err
+			break
+		}
+
+		if !ok {
+			break
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	errs := make([]error, len(deliveries))
+	wg := sync.WaitGroup{}
+	for i, delivery := range deliveries {
+		wg.Add(1)
+		go func(i int, delivery models.WebhookDelivery) {
+			defer wg.Done()
+			// delivery which cannot be marked is claimed again after claim timeout
+			errs[i] = d.deliver(ctx, delivery)
+		}(i, delivery)
+	}
+	wg.Wait()
+
+	sent := 0
+	for _, err := range errs {
+		if err == nil {
+			sent++
+		} else if dispatchErr == nil {
+			dispatchErr = err
+		}
+	}
+
+	return sent, dispatchErr
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	webhook, err := d.webhookRepo.FindByGuid(ctx, delivery.ShopID, delivery.WebhookGuid)
+	if err != nil {
+		return err
+	}
+
+	attemptedAt := d.now()
+	attempt := models.DeliveryAttempt{AttemptedAt: attemptedAt}
+
+	if webhook.GuidFixed == "" || !webhook.IsActive {
+		attempt.Error = "webhook is deleted or inactive"
+		return d.repo.MarkFailed(ctx, delivery, attempt, models.DeliveryStatusFailed, attemptedAt)
+	}
+
+	attempt.StatusCode, err = d.post(ctx, webhook.URL, webhook.Secret, delivery)
+	attempt.DurationMs = d.now().Sub(attemptedAt).Milliseconds()
+
+	if err == nil {
+		return d.repo.MarkSucceeded(ctx, delivery, attempt)
+	}
+
+	attempt.Error = err.Error()
+
+	if delivery.Attempts >= d.cfg.MaxAttempts() {
+		d.logger.Warnf("Webhook delivery %s to %s failed after %d attempts: %v", delivery.ID.Hex(), webhook.URL, delivery.Attempts, err)
+		return d.repo.MarkFailed(ctx, delivery, attempt, models.DeliveryStatusFailed, attemptedAt)
+	}
+
+	return d.repo.MarkFailed(ctx, delivery, attempt, models.DeliveryStatusPending, attemptedAt.Add(d.backoff(delivery.Attempts)))
+}
+
+// post send the payload to the url, 2xx response is success and other response is returned as error with its status code
+func (d *WebhookDispatcher) post(ctx context.Context, url string, secret string, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := d.now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.HeaderWebhookID, delivery.EventID)
+	req.Header.Set(models.HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(models.HeaderWebhookTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(models.HeaderWebhookSignature, SignPayload(secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// response body is not recorded, it is read so connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff return wait duration before next attempt, doubled every attempt from base backoff up to max backoff
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.BaseBackoff()
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff(); i++ {
+		wait *= 2
+	}
+
+	if wait > d.cfg.MaxBackoff() {
+		return d.cfg.MaxBackoff()
+	}
+	return wait
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestWebhook(url string) models.WebhookDoc {
+	doc := models.WebhookDoc{}
+	doc.ShopID = "shop-1"
+	doc.GuidFixed = "webhook-1"
+	doc.URL = url
+	doc.Secret = "whsec_test"
+	doc.IsActive = true
+	doc.EventTypes = []string{models.EventSaleInvoiceCreated}
+	return doc
+}
+
+func newTestDelivery(webhook models.WebhookDoc, attempts int) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:          primitive.NewObjectID(),
+		ShopID:      webhook.ShopID,
+		WebhookGuid: webhook.GuidFixed,
+		EventID:     "event-1",
+		EventType:   models.EventSaleInvoiceCreated,
+		URL:         webhook.URL,
+		Payload:     `{"id":"event-1","type":"saleinvoice.created","data":{"docno":"INV-0001"}}`,
+		Status:      models.DeliveryStatusPending,
+		Attempts:    attempts,
+	}
+}
+
+func newTestDispatcher(deliveryRepo *WebhookDeliveryRepositoryMock, webhookRepo *WebhookRepositoryMock, now func() time.Time) *WebhookDispatcher {
+	return NewWebhookDispatcher(deliveryRepo, webhookRepo, config.NewWebhookConfig(), logger.NewAppLogger(config.NewLoggerConfig()), now)
+}
+
+func TestWebhookDispatcherSignPayload(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORK", "true")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhook(server.URL)
+	delivery := newTestDelivery(webhook, 1)
+
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindByGuid", "shop-1", "webhook-1").Return(webhook, nil)
+
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	deliveryRepo.On("Claim", mock.Anything).Return(delivery, true, nil).Once()
+	deliveryRepo.On("Claim", mock.Anything).Return(models.WebhookDelivery{}, false, nil)
+	deliveryRepo.On("MarkSucceeded", delivery, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+		return attempt.StatusCode == http.StatusNoContent && attempt.AttemptedAt.Equal(now)
+	})).Return(nil)
+
+	sent, err := newTestDispatcher(deliveryRepo, webhookRepo, func() time.Time { return now }).DispatchPending()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, delivery.Payload, string(receivedBody))
+	assert.Equal(t, "event-1", received.Header.Get(models.HeaderWebhookID))
+	assert.Equal(t, models.EventSaleInvoiceCreated, received.Header.Get(models.HeaderWebhookEvent))
+	assert.Equal(t, strconv.FormatInt(now.Unix(), 10), received.Header.Get(models.HeaderWebhookTimestamp))
+	assert.Equal(t, SignPayload("whsec_test", now, receivedBody), received.Header.Get(models.HeaderWebhookSignature))
+	assert.NotEqual(t, SignPayload("other-secret", now, receivedBody), received.Header.Get(models.HeaderWebhookSignature))
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcherRetryUntilMaxAttempts(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORK", "true")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	t.Setenv("WEBHOOK_BASE_BACKOFF_MS", "30000")
+	t.Setenv("WEBHOOK_MAX_BACKOFF_MS", "120000")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhook(server.URL)
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindByGuid", "shop-1", "webhook-1").Return(webhook, nil)
+
+	// attempt is retried with doubled backoff and the last attempt fail the delivery
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	for _, next := range []struct {
+		attempts      int
+		status        string
+		nextAttemptAt time.Time
+	}{
+		{1, models.DeliveryStatusPending, now.Add(30 * time.Second)},
+		{2, models.DeliveryStatusPending, now.Add(time.Minute)},
+		{3, models.DeliveryStatusFailed, now},
+	} {
+		delivery := newTestDelivery(webhook, next.attempts)
+		deliveryRepo.On("MarkFailed", delivery, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+			return attempt.StatusCode == http.StatusInternalServerError && attempt.Error == "unexpected response status 500"
+		}), next.status, next.nextAttemptAt).Return(nil).Once()
+
+		deliveryRepo.On("Claim", mock.Anything).Return(delivery, true, nil).Once()
+		deliveryRepo.On("Claim", mock.Anything).Return(models.WebhookDelivery{}, false, nil).Once()
+
+		dispatcher := newTestDispatcher(deliveryRepo, webhookRepo, func() time.Time { return now })
+		sent, err := dispatcher.DispatchPending()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, sent)
+	}
+
+	assert.Equal(t, 3, requests)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcherInactiveWebhook(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	webhook := newTestWebhook("http://127.0.0.1:1")
+	webhook.IsActive = false
+	delivery := newTestDelivery(webhook, 1)
+
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindByGuid", "shop-1", "webhook-1").Return(webhook, nil)
+
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	deliveryRepo.On("Claim", mock.Anything).Return(delivery, true, nil).Once()
+	deliveryRepo.On("Claim", mock.Anything).Return(models.WebhookDelivery{}, false, nil)
+	deliveryRepo.On("MarkFailed", delivery, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+		return attempt.Error == "webhook is deleted or inactive"
+	}), models.DeliveryStatusFailed, now).Return(nil)
+
+	_, err := newTestDispatcher(deliveryRepo, webhookRepo, func() time.Time { return now }).DispatchPending()
+
+	assert.Nil(t, err)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestWebhookPublisherPublish(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindActiveByEventType", "shop-1", models.EventSaleInvoiceCreated).Return([]models.WebhookDoc{newTestWebhook("https://hooks.example.com/sml")}, nil)
+
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	deliveryRepo.On("Create", mock.MatchedBy(func(doc models.WebhookDelivery) bool {
+		return doc.WebhookGuid == "webhook-1" && doc.EventID == "event-1" && doc.Status == models.DeliveryStatusPending && doc.NextAttemptAt.Equal(now)
+	})).Return(true, nil).Once()
+
+	// event which is published again is not queued twice
+	deliveryRepo.On("Create", mock.Anything).Return(false, nil).Once()
+
+	publisher := NewWebhookPublisher(webhookRepo, deliveryRepo, func() time.Time { return now })
+	events, err := SplitEvents("event-1", models.EventSaleInvoiceCreated, "", now, []byte(`{"shopid":"shop-1","docno":"INV-0001"}`))
+	assert.Nil(t, err)
+
+	queued, err := publisher.Publish(context.Background(), events[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 1, queued)
+
+	queued, err = publisher.Publish(context.Background(), events[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 0, queued)
+
+	// event without shop is not published
+	events, _ = SplitEvents("event-2", models.EventSaleInvoiceCreated, "", now, []byte(`{"docno":"INV-0002"}`))
+	queued, err = publisher.Publish(context.Background(), events[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 0, queued)
+
+	deliveryRepo.AssertExpectations(t)
+	webhookRepo.AssertNumberOfCalls(t, "FindActiveByEventType", 2)
+}
+
+func TestSplitEventsBulkPayload(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events, err := SplitEvents("event-1", models.EventPurchaseCreated, "", now, []byte(`[{"shopid":"shop-1","docno":"PU-1"},{"shopid":"shop-2","docno":"PU-2"}]`))
+
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "event-1-0", events[0].ID)
+	assert.Equal(t, "shop-1", events[0].ShopID)
+	assert.Equal(t, "event-1-1", events[1].ID)
+	assert.Equal(t, "shop-2", events[1].ShopID)
+}
+
+func TestWebhookDispatcherSlowSubscriberDoesNotHoldOthers(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORK", "true")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fastReceived := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// slow subscriber answer only after the fast one is posted, sequential dispatch would time out here
+		select {
+		case <-fastReceived:
+			w.WriteHeader(http.StatusOK)
+		case <-time.After(3 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fastReceived)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+
+	slowWebhook := newTestWebhook(slow.URL)
+	fastWebhook := newTestWebhook(fast.URL)
+	fastWebhook.ShopID = "shop-2"
+	fastWebhook.GuidFixed = "webhook-2"
+
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindByGuid", "shop-1", "webhook-1").Return(slowWebhook, nil)
+	webhookRepo.On("FindByGuid", "shop-2", "webhook-2").Return(fastWebhook, nil)
+
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	deliveryRepo.On("Claim", mock.Anything).Return(newTestDelivery(slowWebhook, 1), true, nil).Once()
+	deliveryRepo.On("Claim", mock.Anything).Return(newTestDelivery(fastWebhook, 1), true, nil).Once()
+	deliveryRepo.On("Claim", mock.Anything).Return(models.WebhookDelivery{}, false, nil)
+	deliveryRepo.On("MarkSucceeded", mock.Anything, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+		return attempt.StatusCode == http.StatusOK
+	})).Return(nil).Twice()
+
+	sent, err := newTestDispatcher(deliveryRepo, webhookRepo, func() time.Time { return now }).DispatchPending()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sent)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcherDenyPrivateNetwork(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_NETWORK", "false")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := newTestWebhook(server.URL)
+	delivery := newTestDelivery(webhook, 1)
+
+	webhookRepo := new(WebhookRepositoryMock)
+	webhookRepo.On("FindByGuid", "shop-1", "webhook-1").Return(webhook, nil)
+
+	deliveryRepo := new(WebhookDeliveryRepositoryMock)
+	deliveryRepo.On("Claim", mock.Anything).Return(delivery, true, nil).Once()
+	deliveryRepo.On("Claim", mock.Anything).Return(models.WebhookDelivery{}, false, nil)
+	deliveryRepo.On("MarkFailed", delivery, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+		return strings.Contains(attempt.Error, ErrWebhookAddressDenied.Error())
+	}), models.DeliveryStatusPending, mock.Anything).Return(nil)
+
+	newTestDispatcher(deliveryRepo, webhookRepo, func() time.Time { return now }).DispatchPending()
+
+	assert.Equal(t, 0, requests)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestWebhookClientDoNotFollowRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	resp, err := NewWebhookClient(time.Second, true).Post(redirect.URL, "application/json", nil)
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+}
+
+func TestValidateWebhookHost(t *testing.T) {
+	svc := NewWebhookHttpService(nil, nil, false, time.Now)
+
+	for _, webhookURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://100.64.0.1/hook",
+	} {
+		err := svc.validateWebhook(models.Webhook{URL: webhookURL})
+		assert.ErrorIs(t, err, ErrWebhookAddressDenied, webhookURL)
+	}
+
+	assert.Nil(t, svc.validateWebhook(models.Webhook{URL: "https://hooks.example.com/sml"}))
+	assert.Nil(t, NewWebhookHttpService(nil, nil, true, time.Now).validateWebhook(models.Webhook{URL: "http://127.0.0.1:8080/hook"}))
+}
+
+type WebhookRepositoryMock struct {
+	repositories.IWebhookRepository
+	mock.Mock
+}
+
+func (m *WebhookRepositoryMock) FindByGuid(ctx context.Context, shopID string, guid string) (models.WebhookDoc, error) {
+	args := m.Called(shopID, guid)
+	return args.Get(0).(models.WebhookDoc), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) FindActiveByEventType(ctx context.Context, shopID string, eventType string) ([]models.WebhookDoc, error) {
+	args := m.Called(shopID, eventType)
+	return args.Get(0).([]models.WebhookDoc), args.Error(1)
+}
+
+type WebhookDeliveryRepositoryMock struct {
+	repositories.IWebhookDeliveryRepository
+	mock.Mock
+}
+
+func (m *WebhookDeliveryRepositoryMock) Create(ctx context.Context, doc models.WebhookDelivery) (bool, error) {
+	args := m.Called(doc)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *WebhookDeliveryRepositoryMock) Claim(ctx context.Context, now time.Time, claimTimeout time.Duration) (models.WebhookDelivery, bool, error) {
+	args := m.Called(claimTimeout)
+	return args.Get(0).(models.WebhookDelivery), args.Bool(1), args.Error(2)
+}
+
+func (m *WebhookDeliveryRepositoryMock) MarkSucceeded(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt) error {
+	args := m.Called(doc, attempt)
+	return args.Error(0)
+}
+
+func (m *WebhookDeliveryRepositoryMock) MarkFailed(ctx context.Context, doc models.WebhookDelivery, attempt models.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	args := m.Called(doc, attempt, status, nextAttemptAt)
+	return args.Error(0)
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"smlaicloudplatform/internal/encrypt"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"smlaicloudplatform/pkg/microservice"
+	micromodels "smlaicloudplatform/pkg/microservice/models"
+	"time"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookSecretPrefix mark generated secret of webhook
+const WebhookSecretPrefix = "whsec_"
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrDeliveryPending  = errors.New("webhook delivery is waiting to be sent")
+)
+
+type IWebhookHttpService interface {
+	CreateWebhook(ctx context.Context, shopID string, authUsername string, doc models.Webhook) (models.WebhookCreated, error)
+	UpdateWebhook(ctx context.Context, shopID string, guid string, authUsername string, doc models.Webhook) error
+	DeleteWebhook(ctx context.Context, shopID string, guid string, authUsername string) error
+	InfoWebhook(ctx context.Context, shopID string, guid string) (models.WebhookInfo, error)
+	SearchWebhook(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.WebhookInfo, mongopagination.PaginationData, error)
+	SearchDelivery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WebhookDelivery, mongopagination.PaginationData, error)
+	InfoDelivery(ctx context.Context, shopID string, id string) (models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, shopID string, id string, authUsername string) error
+}
+
+type WebhookHttpService struct {
+	repo                repositories.IWebhookRepository
+	deliveryRepo        repositories.IWebhookDeliveryRepository
+	allowPrivateNetwork bool
+	timeNow             func() time.Time
+	contextTimeout      time.Duration
+}
+
+func NewWebhookHttpService(repo repositories.IWebhookRepository, deliveryRepo repositories.IWebhookDeliveryRepository, allowPrivateNetwork bool, timeNow func() time.Time) *WebhookHttpService {
+	return &WebhookHttpService{
+		repo:                repo,
+		deliveryRepo:        deliveryRepo,
+		allowPrivateNetwork: allowPrivateNetwork,
+		timeNow:             timeNow,
+		contextTimeout:      15 * time.Second,
+	}
+}
+
+func (svc WebhookHttpService) getContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(microservice.RequestActorContext(ctx), svc.contextTimeout)
+}
+
+// CreateWebhook create the webhook of the shop, secret is generated when it is not sent
+// and it is returned so the subscriber can verify signature
+func (svc WebhookHttpService) CreateWebhook(ctx context.Context, shopID string, authUsername string, doc models.Webhook) (models.WebhookCreated, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := svc.validateWebhook(doc)
+	if err != nil {
+		return models.WebhookCreated{}, err
+	}
+
+	if doc.Secret == "" || doc.Secret == encrypt.RedactedValue {
+		doc.Secret, err = newWebhookSecret()
+		if err != nil {
+			return models.WebhookCreated{}, err
+		}
+	}
+
+	newGuidFixed := utils.NewGUID()
+
+	docData := models.WebhookDoc{}
+	docData.ShopID = shopID
+	docData.GuidFixed = newGuidFixed
+	docData.Webhook = doc
+
+	docData.CreatedBy = authUsername
+	docData.CreatedAt = svc.timeNow()
+
+	_, err = svc.repo.Create(ctx, docData)
+
+	if err != nil {
+		return models.WebhookCreated{}, err
+	}
+
+	return models.WebhookCreated{
+		GuidFixed: newGuidFixed,
+		Secret:    doc.Secret,
+	}, nil
+}
+
+// UpdateWebhook replace the webhook, secret is kept when it is sent back redacted or empty
+func (svc WebhookHttpService) UpdateWebhook(ctx context.Context, shopID string, guid string, authUsername string, doc models.Webhook) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	err := svc.validateWebhook(doc)
+	if err != nil {
+		return err
+	}
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrWebhookNotFound
+	}
+
+	if doc.Secret == "" {
+		doc.Secret = encrypt.RedactedValue
+	}
+
+	encrypt.RestoreRedactedFields(doc.SecretFields(), findDoc.SecretFields())
+	findDoc.Webhook = doc
+
+	findDoc.UpdatedBy = authUsername
+	findDoc.UpdatedAt = svc.timeNow()
+
+	return svc.repo.Update(ctx, shopID, guid, findDoc)
+}
+
+func (svc WebhookHttpService) DeleteWebhook(ctx context.Context, shopID string, guid string, authUsername string) error {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return ErrWebhookNotFound
+	}
+
+	return svc.repo.DeleteByGuidfixed(ctx, shopID, guid, authUsername)
+}
+
+func (svc WebhookHttpService) InfoWebhook(ctx context.Context, shopID string, guid string) (models.WebhookInfo, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	findDoc, err := svc.repo.FindByGuid(ctx, shopID, guid)
+
+	if err != nil {
+		return models.WebhookInfo{}, err
+	}
+
+	if findDoc.ID == primitive.NilObjectID {
+		return models.WebhookInfo{}, ErrWebhookNotFound
+	}
+
+	encrypt.RedactFields(findDoc.SecretFields()...)
+
+	return findDoc.WebhookInfo, nil
+}
+
+func (svc WebhookHttpService) SearchWebhook(ctx context.Context, shopID string, pageable micromodels.Pageable) ([]models.WebhookInfo, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	searchInFields := []string{
+		"name",
+		"url",
+	}
+
+	docList, pagination, err := svc.repo.FindPage(ctx, shopID, searchInFields, pageable)
+
+	if err != nil {
+		return []models.WebhookInfo{}, pagination, err
+	}
+
+	for i := range docList {
+		encrypt.RedactFields(docList[i].SecretFields()...)
+	}
+
+	return docList, pagination, nil
+}
+
+// SearchDelivery return delivery log of the shop, newest first when sort is not requested
+func (svc WebhookHttpService) SearchDelivery(ctx context.Context, shopID string, filters map[string]interface{}, pageable micromodels.Pageable) ([]models.WebhookDelivery, mongopagination.PaginationData, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	if len(pageable.Sorts) < 1 {
+		pageable.Sorts = append(pageable.Sorts, micromodels.KeyInt{Key: "createdat", Value: -1})
+	}
+
+	searchInFields := []string{
+		"eventid",
+		"url",
+	}
+
+	docList, pagination, err := svc.deliveryRepo.FindPageFilter(ctx, shopID, filters, searchInFields, pageable)
+
+	if err != nil {
+		return []models.WebhookDelivery{}, pagination, err
+	}
+
+	return docList, pagination, nil
+}
+
+func (svc WebhookHttpService) InfoDelivery(ctx context.Context, shopID string, id string) (models.WebhookDelivery, error) {
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+
+	doc, err := svc.deliveryRepo.FindByID(ctx, shopID, objectID)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	if doc.ID.IsZero() {
+		return models.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+
+	return doc, nil
+}
+
+// Redeliver queue succeeded or failed delivery to be sent again with the same event id and new attempts
+func (svc WebhookHttpService) Redeliver(ctx context.Context, shopID string, id string, authUsername string) error {
+
+	doc, err := svc.InfoDelivery(ctx, shopID, id)
+	if err != nil {
+		return err
+	}
+
+	ctx, ctxCancel := svc.getContextTimeout(ctx)
+	defer ctxCancel()
+
+	ok, err := svc.deliveryRepo.Redeliver(ctx, shopID, doc.ID, authUsername, svc.timeNow())
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrDeliveryPending
+	}
+
+	return nil
+}
+
+func (svc WebhookHttpService) validateWebhook(doc models.Webhook) error {
+	webhookURL, err := url.Parse(doc.URL)
+	if err != nil || (webhookURL.Scheme != "https" && webhookURL.Scheme != "http") || webhookURL.Host == "" {
+		return errors.New("url must be http or https url")
+	}
+
+	if !svc.allowPrivateNetwork {
+		if err := validateWebhookHost(webhookURL); err != nil {
+			return err
+		}
+	}
+
+	for _, eventType := range doc.EventTypes {
+		if !models.IsEventType(eventType) {
+			return fmt.Errorf("event type %s is not supported", eventType)
+		}
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return WebhookSecretPrefix + hex.EncodeToString(secret), nil
+}
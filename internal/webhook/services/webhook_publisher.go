@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"strconv"
+	"time"
+)
+
+// SignPayload return value of X-Webhook-Signature, subscriber compute it from the timestamp header and raw body
+// by the secret and compare, timestamp is signed so old request cannot be replayed
+func SignPayload(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SplitEvents return event of every document of the payload, bulk payload is json array and each document
+// get id of the event with its index so each one is delivered once
+func SplitEvents(eventID string, eventType string, shopID string, occurredAt time.Time, payload []byte) ([]models.WebhookEvent, error) {
+	trimmed := bytes.TrimSpace(payload)
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		docs := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &docs); err != nil {
+			return nil, err
+		}
+
+		events := make([]models.WebhookEvent, 0, len(docs))
+		for i, doc := range docs {
+			events = append(events, newWebhookEvent(fmt.Sprintf("%s-%d", eventID, i), eventType, shopID, occurredAt, doc))
+		}
+		return events, nil
+	}
+
+	if !json.Valid(trimmed) {
+		return nil, fmt.Errorf("payload of event %s is not json", eventID)
+	}
+
+	return []models.WebhookEvent{newWebhookEvent(eventID, eventType, shopID, occurredAt, trimmed)}, nil
+}
+
+func newWebhookEvent(eventID string, eventType string, shopID string, occurredAt time.Time, doc json.RawMessage) models.WebhookEvent {
+	docShop := struct {
+		ShopID string `json:"shopid"`
+	}{}
+
+	if err := json.Unmarshal(doc, &docShop); err == nil && docShop.ShopID != "" {
+		shopID = docShop.ShopID
+	}
+
+	return models.WebhookEvent{
+		ID:         eventID,
+		Type:       eventType,
+		ShopID:     shopID,
+		OccurredAt: occurredAt,
+		Data:       doc,
+	}
+}
+
+type IWebhookPublisher interface {
+	Publish(ctx context.Context, event models.WebhookEvent) (int, error)
+}
+
+// WebhookPublisher queue delivery of the event to every active webhook of the shop which subscribe the event type
+type WebhookPublisher struct {
+	webhookRepo  repositories.IWebhookRepository
+	deliveryRepo repositories.IWebhookDeliveryRepository
+	timeNow      func() time.Time
+}
+
+func NewWebhookPublisher(webhookRepo repositories.IWebhookRepository, deliveryRepo repositories.IWebhookDeliveryRepository, timeNow func() time.Time) *WebhookPublisher {
+	return &WebhookPublisher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		timeNow:      timeNow,
+	}
+}
+
+// Publish return number of queued deliveries, event which is published again is not queued twice for the same webhook
+func (p WebhookPublisher) Publish(ctx context.Context, event models.WebhookEvent) (int, error) {
+	if event.ShopID == "" {
+		return 0, nil
+	}
+
+	webhooks, err := p.webhookRepo.FindActiveByEventType(ctx, event.ShopID, event.Type)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(webhooks) == 0 {
+		return 0, nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	now := p.timeNow()
+	queued := 0
+	for _, webhook := range webhooks {
+		created, err := p.deliveryRepo.Create(ctx, models.WebhookDelivery{
+			ShopID:        event.ShopID,
+			WebhookGuid:   webhook.GuidFixed,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			URL:           webhook.URL,
+			Payload:       string(payload),
+			Status:        models.DeliveryStatusPending,
+			AttemptLogs:   []models.DeliveryAttempt{},
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return queued, err
+		}
+
+		if created {
+			queued++
+		}
+	}
+
+	return queued, nil
+}
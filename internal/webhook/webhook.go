@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"smlaicloudplatform/internal/webhook/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+// WebhookDispatcher run dispatcher of queued deliveries in background of consumer mode
+type WebhookDispatcher struct {
+	dispatcher *services.WebhookDispatcher
+}
+
+func InitWebhookDispatcher(ms *microservice.Microservice, cfg config.IConfig) *WebhookDispatcher {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	webhookRepo := repositories.NewWebhookRepository(pst, datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()))
+	deliveryRepo := repositories.NewWebhookDeliveryRepository(pst)
+
+	return &WebhookDispatcher{
+		dispatcher: services.NewWebhookDispatcher(deliveryRepo, webhookRepo, cfg.WebhookConfig(), ms.Logger, ms.TimeNow),
+	}
+}
+
+// RegisterConsumer start dispatcher loop in background
+func (d *WebhookDispatcher) RegisterConsumer(ms *microservice.Microservice) {
+	go d.dispatcher.Run()
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
+	stockprocessConfig "smlaicloudplatform/internal/stockprocess/config"
+	debtorPaymentConfig "smlaicloudplatform/internal/transaction/paid/config"
+	purchaseConfig "smlaicloudplatform/internal/transaction/purchase/config"
+	saleInvoiceConfig "smlaicloudplatform/internal/transaction/saleinvoice/config"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"smlaicloudplatform/internal/webhook/services"
+	"smlaicloudplatform/pkg/microservice"
+	"time"
+)
+
+// WebhookConsumer read document events of message queue and queue deliveries to webhooks which subscribe them
+type WebhookConsumer struct {
+	ms        *microservice.Microservice
+	cfg       config.IConfig
+	publisher services.IWebhookPublisher
+}
+
+func InitWebhookConsumer(ms *microservice.Microservice, cfg config.IConfig) *WebhookConsumer {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	webhookRepo := repositories.NewWebhookRepository(pst, datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()))
+	deliveryRepo := repositories.NewWebhookDeliveryRepository(pst)
+
+	return &WebhookConsumer{
+		ms:        ms,
+		cfg:       cfg,
+		publisher: services.NewWebhookPublisher(webhookRepo, deliveryRepo, ms.TimeNow),
+	}
+}
+
+// webhookTopics return event type of every topic which is fed to webhooks, bulk topics have the same event type
+func webhookTopics() map[string]string {
+	saleInvoiceKafkaConfig := saleInvoiceConfig.SaleInvoiceMessageQueueConfig{}
+	purchaseKafkaConfig := purchaseConfig.PurchaseMessageQueueConfig{}
+	debtorPaymentKafkaConfig := debtorPaymentConfig.DebtorPaymentMessageQueueConfig{}
+	stockProcessKafkaConfig := stockprocessConfig.StockProcessMessageQueueConfig{}
+
+	return map[string]string{
+		saleInvoiceKafkaConfig.TopicCreated():     models.EventSaleInvoiceCreated,
+		saleInvoiceKafkaConfig.TopicUpdated():     models.EventSaleInvoiceUpdated,
+		saleInvoiceKafkaConfig.TopicDeleted():     models.EventSaleInvoiceDeleted,
+		saleInvoiceKafkaConfig.TopicBulkCreated(): models.EventSaleInvoiceCreated,
+		saleInvoiceKafkaConfig.TopicBulkUpdated(): models.EventSaleInvoiceUpdated,
+		saleInvoiceKafkaConfig.TopicBulkDeleted(): models.EventSaleInvoiceDeleted,
+
+		purchaseKafkaConfig.TopicCreated():     models.EventPurchaseCreated,
+		purchaseKafkaConfig.TopicUpdated():     models.EventPurchaseUpdated,
+		purchaseKafkaConfig.TopicDeleted():     models.EventPurchaseDeleted,
+		purchaseKafkaConfig.TopicBulkCreated(): models.EventPurchaseCreated,
+		purchaseKafkaConfig.TopicBulkUpdated(): models.EventPurchaseUpdated,
+		purchaseKafkaConfig.TopicBulkDeleted(): models.EventPurchaseDeleted,
+
+		debtorPaymentKafkaConfig.TopicCreated():     models.EventDebtorPaymentCreated,
+		debtorPaymentKafkaConfig.TopicUpdated():     models.EventDebtorPaymentUpdated,
+		debtorPaymentKafkaConfig.TopicDeleted():     models.EventDebtorPaymentDeleted,
+		debtorPaymentKafkaConfig.TopicBulkCreated(): models.EventDebtorPaymentCreated,
+		debtorPaymentKafkaConfig.TopicBulkUpdated(): models.EventDebtorPaymentUpdated,
+		debtorPaymentKafkaConfig.TopicBulkDeleted(): models.EventDebtorPaymentDeleted,
+
+		// stock process topics send shop and barcode which stock is changed
+		stockProcessKafkaConfig.TopicCreated():     models.EventStockBalanceChanged,
+		stockProcessKafkaConfig.TopicBulkCreated(): models.EventStockBalanceChanged,
+	}
+}
+
+func (c *WebhookConsumer) RegisterConsumer(ms *microservice.Microservice) {
+	consumerGroup := c.cfg.WebhookConfig().ConsumerGroup()
+	mq := microservice.NewMQ(c.cfg.MQConfig(), ms.Logger)
+
+	for topic, eventType := range webhookTopics() {
+		mq.CreateTopicR(topic, 5, 1, time.Hour*24*7)
+		ms.Consume(c.cfg.MQConfig().URI(), topic, consumerGroup, time.Duration(-1), c.consumeEvent(eventType))
+	}
+}
+
+// consumeEvent return handler which queue deliveries of every document of the message,
+// message which is consumed again does not queue the deliveries twice
+func (c *WebhookConsumer) consumeEvent(eventType string) microservice.ServiceHandleFunc {
+	return func(ctx microservice.IContext) error {
+		eventID, shopID, occurredAt := c.eventMetadata(ctx)
+
+		events, err := services.SplitEvents(eventID, eventType, shopID, occurredAt, []byte(ctx.ReadInput()))
+		if err != nil {
+			c.ms.Logger.Errorf("Webhook cannot read event %s: %v", eventID, err)
+			return err
+		}
+
+		publishCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		for _, event := range events {
+			_, err = c.publisher.Publish(publishCtx, event)
+			if err != nil {
+				c.ms.Logger.Errorf("Webhook cannot queue deliveries of event %s: %v", event.ID, err)
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// eventMetadata return id, shop and time of the event, legacy message without envelope get id from its offset
+func (c *WebhookConsumer) eventMetadata(ctx microservice.IContext) (string, string, time.Time) {
+	if event, ok := microservice.EventFromContext(ctx); ok {
+		return event.EventID, event.ShopID, event.OccurredAt
+	}
+
+	eventID := ""
+	if msg, ok := microservice.ConsumerMessageFromContext(ctx); ok {
+		eventID = fmt.Sprintf("%s-%d-%d", msg.Topic, msg.Partition, msg.Offset)
+	}
+
+	return eventID, "", c.ms.TimeNow()
+}
@@ -0,0 +1,366 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/encrypt/datakey"
+	common "smlaicloudplatform/internal/models"
+	"smlaicloudplatform/internal/rbac"
+	rbacmodels "smlaicloudplatform/internal/rbac/models"
+	"smlaicloudplatform/internal/utils"
+	"smlaicloudplatform/internal/utils/requestfilter"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/internal/webhook/repositories"
+	"smlaicloudplatform/internal/webhook/services"
+	"smlaicloudplatform/pkg/microservice"
+)
+
+type IWebhookHttp interface{}
+
+type WebhookHttp struct {
+	ms  *microservice.Microservice
+	cfg config.IConfig
+	svc services.IWebhookHttpService
+}
+
+func NewWebhookHttp(ms *microservice.Microservice, cfg config.IConfig) WebhookHttp {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	repo := repositories.NewWebhookRepository(pst, datakey.NewShopFieldEncryptor(pst, cfg.EncryptionConfig()))
+	deliveryRepo := repositories.NewWebhookDeliveryRepository(pst)
+	svc := services.NewWebhookHttpService(repo, deliveryRepo, cfg.WebhookConfig().AllowPrivateNetwork(), ms.TimeNow)
+
+	rbac.InitPermissionService(ms, cfg)
+
+	return WebhookHttp{
+		ms:  ms,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h WebhookHttp) RegisterHttp() {
+	webhookRead := h.ms.RequirePermission(rbacmodels.PermissionWebhookRead)
+	webhookUpdate := h.ms.RequirePermission(rbacmodels.PermissionWebhookUpdate)
+
+	h.ms.GET("/webhook/deliveries", h.SearchDeliveryPage, webhookRead)
+	h.ms.GET("/webhook/deliveries/:id", h.InfoDelivery, webhookRead)
+	h.ms.POST("/webhook/deliveries/:id/redeliver", h.Redeliver, webhookUpdate)
+
+	h.ms.GET("/webhook", h.SearchWebhookPage, webhookRead)
+	h.ms.POST("/webhook", h.CreateWebhook, webhookUpdate)
+	h.ms.GET("/webhook/:id", h.InfoWebhook, webhookRead)
+	h.ms.PUT("/webhook/:id", h.UpdateWebhook, webhookUpdate)
+	h.ms.DELETE("/webhook/:id", h.DeleteWebhook, webhookUpdate)
+}
+
+// Create Webhook godoc
+// @Description Create webhook of the shop, secret is generated when it is empty and it is returned only once
+// @Tags		Webhook
+// @Param		Webhook  body      models.Webhook  true  "Webhook"
+// @Accept 		json
+// @Success		201	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook [post]
+func (h WebhookHttp) CreateWebhook(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+
+	docReq := &models.Webhook{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	doc, err := h.svc.CreateWebhook(ctx.Context(), userInfo.ShopID, userInfo.Username, *docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusCreated, common.ApiResponse{
+		Success: true,
+		ID:      doc.GuidFixed,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Update Webhook godoc
+// @Description Update webhook, secret is kept when it is empty or redacted
+// @Tags		Webhook
+// @Param		id  path      string  true  "Webhook ID"
+// @Param		Webhook  body      models.Webhook  true  "Webhook"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/{id} [put]
+func (h WebhookHttp) UpdateWebhook(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	docReq := &models.Webhook{}
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &docReq)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if err = ctx.Validate(docReq); err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	err = h.svc.UpdateWebhook(ctx.Context(), userInfo.ShopID, id, userInfo.Username, *docReq)
+
+	if errors.Is(err, services.ErrWebhookNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Delete Webhook godoc
+// @Description Delete Webhook, its queued deliveries are failed when they are sent
+// @Tags		Webhook
+// @Param		id  path      string  true  "Webhook ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/{id} [delete]
+func (h WebhookHttp) DeleteWebhook(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.DeleteWebhook(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if errors.Is(err, services.ErrWebhookNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
+
+// Get Webhook godoc
+// @Description get Webhook info by guidfixed, secret is redacted
+// @Tags		Webhook
+// @Param		id  path      string  true  "Webhook ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/{id} [get]
+func (h WebhookHttp) InfoWebhook(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoWebhook(ctx.Context(), shopID, id)
+
+	if errors.Is(err, services.ErrWebhookNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %s: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, "document not found")
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// List Webhook godoc
+// @Description List Webhooks of the shop
+// @Tags		Webhook
+// @Param		q		query	string		false  "Search Value"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook [get]
+func (h WebhookHttp) SearchWebhookPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchWebhook(ctx.Context(), shopID, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// List Webhook Delivery godoc
+// @Description search delivery log of webhooks of the shop with status code and error of latest attempts, newest first
+// @Tags		Webhook
+// @Param		q		query	string		false  "Search event id or url"
+// @Param		webhookguid		query	string		false  "Webhook ID"
+// @Param		eventtype		query	string		false  "Event type"
+// @Param		status		query	string		false  "pending, succeeded or failed"
+// @Param		fromdate		query	string		false  "From date (yyyy-mm-dd)"
+// @Param		todate		query	string		false  "To date (yyyy-mm-dd)"
+// @Param		page	query	integer		false  "Page"
+// @Param		limit	query	integer		false  "Limit"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/deliveries [get]
+func (h WebhookHttp) SearchDeliveryPage(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+
+	filters := requestfilter.GenerateFilters(ctx.QueryParam, []requestfilter.FilterRequest{
+		{
+			Param: "webhookguid",
+			Field: "webhookguid",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "eventtype",
+			Field: "eventtype",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "status",
+			Field: "status",
+			Type:  requestfilter.FieldTypeString,
+		},
+		{
+			Param: "-",
+			Field: "createdat",
+			Type:  requestfilter.FieldTypeRangeDate,
+		},
+	})
+
+	pageable := utils.GetPageable(ctx.QueryParam)
+	docList, pagination, err := h.svc.SearchDelivery(ctx.Context(), shopID, filters, pageable)
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success:    true,
+		Data:       docList,
+		Pagination: pagination,
+	})
+	return nil
+}
+
+// Get Webhook Delivery godoc
+// @Description get webhook delivery with payload and its attempts
+// @Tags		Webhook
+// @Param		id  path      string  true  "Delivery ID"
+// @Accept 		json
+// @Success		200	{object}	common.ApiResponse
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/deliveries/{id} [get]
+func (h WebhookHttp) InfoDelivery(ctx microservice.IContext) error {
+	shopID := ctx.UserInfo().ShopID
+	id := ctx.Param("id")
+
+	doc, err := h.svc.InfoDelivery(ctx.Context(), shopID, id)
+
+	if errors.Is(err, services.ErrDeliveryNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if err != nil {
+		h.ms.Logger.Errorf("Error getting document %s: %v", id, err)
+		ctx.ResponseError(http.StatusBadRequest, "document not found")
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		Data:    doc,
+	})
+	return nil
+}
+
+// Redeliver Webhook Delivery godoc
+// @Description send succeeded or failed delivery again with the same event id, attempts are counted from zero
+// @Tags		Webhook
+// @Param		id  path      string  true  "Delivery ID"
+// @Accept 		json
+// @Success		200	{object}	common.ResponseSuccessWithID
+// @Failure		401 {object}	common.AuthResponseFailed
+// @Security     AccessToken
+// @Router /webhook/deliveries/{id}/redeliver [post]
+func (h WebhookHttp) Redeliver(ctx microservice.IContext) error {
+	userInfo := ctx.UserInfo()
+	id := ctx.Param("id")
+
+	err := h.svc.Redeliver(ctx.Context(), userInfo.ShopID, id, userInfo.Username)
+
+	if errors.Is(err, services.ErrDeliveryNotFound) {
+		ctx.ResponseError(http.StatusNotFound, err.Error())
+		return err
+	}
+
+	if errors.Is(err, services.ErrDeliveryPending) {
+		ctx.ResponseError(http.StatusConflict, err.Error())
+		return err
+	}
+
+	if err != nil {
+		ctx.ResponseError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	ctx.Response(http.StatusOK, common.ApiResponse{
+		Success: true,
+		ID:      id,
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	pkgConfig "smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/webhook/models"
+	"smlaicloudplatform/pkg/microservice"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationDatabase create index of webhook subscriptions and deliveries, unique index of delivery keep an event
+// from being queued twice for a webhook and deliveries are removed by ttl index after the retention
+func MigrationDatabase(ms *microservice.Microservice, cfg pkgConfig.IConfig) error {
+	pst := ms.MongoPersister(cfg.MongoPersisterConfig())
+
+	webhookCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.WebhookDoc{})
+	if err != nil {
+		return err
+	}
+
+	_, err = webhookCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "guidfixed", Value: 1}},
+			Options: options.Index().SetName("webhook_shopid_guidfixed"),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "eventtypes", Value: 1}, {Key: "isactive", Value: 1}},
+			Options: options.Index().SetName("webhook_shopid_eventtypes_isactive"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveryCollection, err := pst.Exec(microservice.WithSystemAdmin(context.Background()), &models.WebhookDelivery{})
+	if err != nil {
+		return err
+	}
+
+	_, err = deliveryCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "webhookguid", Value: 1}, {Key: "eventid", Value: 1}},
+			Options: options.Index().SetName("webhookdelivery_webhookguid_eventid").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextattemptat", Value: 1}},
+			Options: options.Index().SetName("webhookdelivery_status_nextattemptat"),
+		},
+		{
+			Keys:    bson.D{{Key: "shopid", Value: 1}, {Key: "createdat", Value: -1}},
+			Options: options.Index().SetName("webhookdelivery_shopid_createdat"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdat", Value: 1}},
+			Options: options.Index().SetName("webhookdelivery_createdat_ttl").SetExpireAfterSeconds(int32(cfg.WebhookConfig().DeliveryRetention().Seconds())),
+		},
+	})
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	migrationAPI "smlaicloudplatform/cmd/migrationapi/api"
 	"smlaicloudplatform/docs"
 	"smlaicloudplatform/internal/apikeyservice"
+	"smlaicloudplatform/internal/audit"
 	"smlaicloudplatform/internal/authentication"
 	"smlaicloudplatform/internal/channel/salechannel"
 	"smlaicloudplatform/internal/channel/transportchannel"
@@ -18,8 +19,11 @@ import (
 	"smlaicloudplatform/internal/debtaccount/debtorgroup"
 	"smlaicloudplatform/internal/dimension"
 	"smlaicloudplatform/internal/documentwarehouse/documentimage"
+	"smlaicloudplatform/internal/encrypt/datakey"
 	"smlaicloudplatform/internal/filestatus"
 	"smlaicloudplatform/internal/images"
+	"smlaicloudplatform/internal/job"
+	"smlaicloudplatform/internal/loginguard"
 	"smlaicloudplatform/internal/masterexpense"
 	"smlaicloudplatform/internal/masterincome"
 	"smlaicloudplatform/internal/mastersync"
@@ -32,6 +36,7 @@ import (
 	"smlaicloudplatform/internal/organization/branch"
 	"smlaicloudplatform/internal/organization/businesstype"
 	"smlaicloudplatform/internal/organization/department"
+	"smlaicloudplatform/internal/outbox"
 	"smlaicloudplatform/internal/payment/bankmaster"
 	"smlaicloudplatform/internal/payment/bookbank"
 	"smlaicloudplatform/internal/payment/qrpayment"
@@ -56,6 +61,7 @@ import (
 	"smlaicloudplatform/internal/productsection/sectionbranch"
 	"smlaicloudplatform/internal/productsection/sectionbusinesstype"
 	"smlaicloudplatform/internal/productsection/sectiondepartment"
+	"smlaicloudplatform/internal/rbac"
 	"smlaicloudplatform/internal/restaurant/device"
 	"smlaicloudplatform/internal/restaurant/kitchen"
 	"smlaicloudplatform/internal/restaurant/printer"
@@ -63,6 +69,7 @@ import (
 	"smlaicloudplatform/internal/restaurant/staff"
 	"smlaicloudplatform/internal/restaurant/table"
 	"smlaicloudplatform/internal/restaurant/zone"
+	"smlaicloudplatform/internal/scheduler"
 	"smlaicloudplatform/internal/shop"
 	"smlaicloudplatform/internal/shop/employee"
 	"smlaicloudplatform/internal/shopdesign/zonedesign"
@@ -71,7 +78,11 @@ import (
 	"smlaicloudplatform/internal/stockbalanceimport"
 	"smlaicloudplatform/internal/stockprocess"
 	"smlaicloudplatform/internal/systemadmin"
+	"smlaicloudplatform/internal/systemadmin/deadletteradmin"
+	"smlaicloudplatform/internal/systemadmin/operatoradmin"
 	"smlaicloudplatform/internal/task"
+	"smlaicloudplatform/internal/transaction/approval"
+	"smlaicloudplatform/internal/transaction/docsequence"
 	"smlaicloudplatform/internal/transaction/documentformate"
 	"smlaicloudplatform/internal/transaction/paid"
 	"smlaicloudplatform/internal/transaction/pay"
@@ -80,9 +91,12 @@ import (
 	"smlaicloudplatform/internal/transaction/purchase"
 	"smlaicloudplatform/internal/transaction/purchaseorder"
 	"smlaicloudplatform/internal/transaction/purchasereturn"
+	"smlaicloudplatform/internal/transaction/returnable"
 	"smlaicloudplatform/internal/transaction/saleinvoice"
 	"smlaicloudplatform/internal/transaction/saleinvoicebomprice"
 	"smlaicloudplatform/internal/transaction/saleinvoicereturn"
+	"smlaicloudplatform/internal/transaction/saleorder"
+	"smlaicloudplatform/internal/transaction/salequotation"
 	"smlaicloudplatform/internal/transaction/smltransaction"
 	"smlaicloudplatform/internal/transaction/stockadjustment"
 	"smlaicloudplatform/internal/transaction/stockbalance"
@@ -98,6 +112,7 @@ import (
 	"smlaicloudplatform/internal/vfgl/journalbook"
 	"smlaicloudplatform/internal/vfgl/journalreport"
 	"smlaicloudplatform/internal/warehouse"
+	"smlaicloudplatform/internal/webhook"
 	"smlaicloudplatform/pkg/microservice"
 	"time"
 
@@ -164,6 +179,13 @@ func main() {
 	}
 
 	cfg := config.NewConfig()
+
+	// debtor, qr payment, webhook and two factor store secrets encrypted by the master key
+	if err := datakey.ValidateMasterKey(cfg.EncryptionConfig()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	ms, err := microservice.NewMicroservice(cfg)
 	if err != nil {
 		panic(err)
@@ -177,6 +199,7 @@ func main() {
 
 		cacher := ms.Cacher(cfg.CacherConfig())
 		authService := microservice.NewAuthService(cacher, 24*3*time.Hour, 24*30*time.Hour)
+		authService.SetApiKeyResolver(apikeyservice.NewApiKeyResolver(ms, cfg))
 		publicPath := []string{
 			"/migrationtools/",
 			"/swagger/*",
@@ -213,6 +236,9 @@ func main() {
 			"/e-order/sale-invoice/last-pos-docno",
 			"/e-order/notify",
 			"/line-notify",
+
+			// system admin routes are checked by operator guard of systemadmin
+			ms.PathPrefix() + systemadmin.SYSTEM_ADMIN_ROUTE_PREFIX + "/*",
 		}
 
 		exceptShopPath := []string{
@@ -222,6 +248,14 @@ func main() {
 			"/select-shop",
 			"/create-shop",
 			"/favorite-shop",
+			"/2fa",
+			"/2fa/enroll",
+			"/2fa/enable",
+			"/2fa/disable",
+			"/2fa/recovery-codes",
+			"/sessions",
+			"/sessions/revoke",
+			"/sessions/revoke-all",
 		}
 		ms.HttpMiddleware(authService.MWFuncWithRedisMixShop(cacher, exceptShopPath, publicPath...))
 		ms.RegisterLivenessProbeEndpoint("/healthz")
@@ -236,11 +270,16 @@ func main() {
 		httpServices := []HttpRegister{
 
 			apikeyservice.NewApiKeyServiceHttp(ms, cfg),
+			apikeyservice.NewApiKeyHttp(ms, cfg),
 			authentication.NewAuthenticationHttp(ms, cfg),
 			apikeyservice.NewApiKeyServiceHttp(ms, cfg),
 			shop.NewShopHttp(ms, cfg),
 
 			shop.NewShopMemberHttp(ms, cfg),
+			rbac.NewRbacHttp(ms, cfg),
+			audit.NewAuditHttp(ms, cfg),
+			loginguard.NewSecurityEventHttp(ms, cfg),
+			webhook.NewWebhookHttp(ms, cfg),
 			employee.NewEmployeeHttp(ms, cfg), member.NewMemberHttp(ms, cfg),
 
 			option.NewOptionHttp(ms, cfg),
@@ -317,6 +356,8 @@ func main() {
 			stockbalance.NewStockBalanceHttp(ms, cfg),
 			stockbalancedetail.NewStockBalanceDetailHttp(ms, cfg),
 			purchaseorder.NewPurchaseOrderHttp(ms, cfg),
+			salequotation.NewSaleQuotationHttp(ms, cfg),
+			saleorder.NewSaleOrderHttp(ms, cfg),
 
 			//product section
 			sectionbranch.NewSectionBranchHttp(ms, cfg),
@@ -343,6 +384,8 @@ func main() {
 			order_device.NewDeviceHttp(ms, cfg),
 
 			documentformate.NewDocumentFormateHttp(ms, cfg),
+			docsequence.NewDocSequenceHttp(ms, cfg),
+			approval.NewApprovalHttp(ms, cfg),
 			ocr.NewOcrHttp(ms, cfg),
 
 			notify.NewNotifyHttp(ms, cfg),
@@ -364,6 +407,7 @@ func main() {
 
 			temp.NewPOSTempHttp(ms, cfg),
 			filestatus.NewFileStatusHttp(ms, cfg),
+			job.NewJobHttp(ms, cfg),
 
 			// member
 			member.NewMemberHttp(ms, cfg),
@@ -412,6 +456,54 @@ func main() {
 		bom.MigrationDatabase(ms, cfg)
 		saleinvoicebomprice.MigrationDatabase(ms, cfg)
 
+		// Outbox
+		outbox.MigrationDatabase(ms, cfg)
+
+		// Job
+		job.MigrationDatabase(ms, cfg)
+
+		// Schedule
+		scheduler.MigrationDatabase(ms, cfg)
+
+		// RBAC
+		rbac.MigrationDatabase(ms, cfg)
+
+		// Api key
+		apikeyservice.MigrationDatabase(ms, cfg)
+
+		// Audit
+		audit.MigrationDatabase(ms, cfg)
+
+		// Two factor authentication
+		authentication.MigrationDatabase(ms, cfg)
+
+		// Data key of encrypted secrets
+		datakey.MigrationDatabase(ms, cfg)
+
+		// Security events of failed logins
+		loginguard.MigrationDatabase(ms, cfg)
+
+		// Webhook
+		webhook.MigrationDatabase(ms, cfg)
+
+		// Operator of system admin
+		operatoradmin.MigrationDatabase(ms, cfg)
+
+		// Doc no sequence
+		docsequence.MigrationDatabase(ms, cfg)
+
+		// Document approval
+		approval.MigrationDatabase(ms, cfg)
+
+		// Purchase order fulfilment
+		purchaseorder.MigrationDatabase(ms, cfg)
+
+		// Sale order delivery
+		saleorder.MigrationDatabase(ms, cfg)
+
+		// Returned quantity of sale invoices and purchases
+		returnable.MigrationDatabase(ms, cfg)
+
 		return
 	}
 
@@ -465,6 +557,23 @@ func main() {
 		ms.RegisterConsumer(bom.InitBOMConsumer(ms, cfg))
 		ms.RegisterConsumer(saleinvoicebomprice.InitSaleInvoiceBomPriceConsumer(ms, cfg))
 
+		// Dead Letter
+		ms.RegisterConsumer(deadletteradmin.InitDeadLetterConsumer(ms, cfg))
+
+		// Outbox
+		ms.RegisterConsumer(outbox.InitOutboxRelay(ms, cfg))
+
+		// Webhook
+		ms.RegisterConsumer(webhook.InitWebhookConsumer(ms, cfg))
+		ms.RegisterConsumer(webhook.InitWebhookDispatcher(ms, cfg))
+
+		// Schedule
+		scheduler.InitScheduler(ms, cfg)
+		err = outbox.RegisterPurgeSchedule(ms, cfg)
+		if err != nil {
+			panic(err)
+		}
+
 		consumerServices := []ConsumerRegister{
 			task.NewTaskConsumer(ms, cfg),
 			productbarcode.NewProductBarcodeConsumer(ms, cfg),
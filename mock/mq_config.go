@@ -31,6 +31,10 @@ func (MqConfig) SSLCertFile() string {
 	return os.Getenv("KAFKA_SSL_CERT_FILE")
 }
 
+func (MqConfig) Driver() string {
+	return os.Getenv("MQ_DRIVER")
+}
+
 func NewMqConfig() config.IMQConfig {
 	re := regexp.MustCompile(`^(.*` + projectDirName + `)`)
 	cwd, _ := os.Getwd()
@@ -26,10 +26,26 @@ type IAuthService interface {
 	ExpireToken(tokenType TokenType, tokenAuthorizationHeader string) error
 	DeleteToken(tokenType TokenType, tokenStr string) error
 	RefreshToken(token string) (string, string, error)
+	SetTwoFactorVerified(tokenType TokenType, tokenStr string) error
+	GetTokenTwoFactor(tokenType TokenType, tokenStr string) (username string, verified bool, err error)
+	CreateSession(tokenStr string, refreshTokenStr string, session models.UserSession) (string, error)
+	GetTokenSessionID(tokenType TokenType, tokenStr string) (string, error)
+	ListSessions(username string) ([]models.UserSession, error)
+	RevokeSession(username string, sessionID string) error
+	RevokeSessions(username string, exceptSessionID string) error
+}
+
+// IApiKeyResolver resolve managed api key of x-api-key header to user info of the key without user login,
+// found is false when the key is not managed key so it is looked up as x-api-key token in cache
+type IApiKeyResolver interface {
+	ResolveApiKey(apiKey string, remoteIP string) (userInfo models.UserInfo, found bool, err error)
 }
 
 type TokenType = int
 
+// tokenTwoFactorField is set on token which is issued after two factor code is verified
+const tokenTwoFactorField = "twofactor"
+
 const (
 	AUTHTYPE_BEARER TokenType = iota
 	AUTHTYPE_WEBSOCKET
@@ -52,8 +68,10 @@ type AuthService struct {
 	expireXApiKey         time.Duration
 	prefixXApiKeyCacheKey string
 	prefixRefreshCacheKey string
+	prefixSessionCacheKey string
 	expireTimeRefresh     time.Duration
 	encrypt               encrypt.Encrypt
+	apiKeyResolver        IApiKeyResolver
 }
 
 func NewAuthService(cacher ICacher, expireTimeBearer time.Duration, expireTimeRefresh time.Duration) *AuthService {
@@ -66,6 +84,7 @@ func NewAuthService(cacher ICacher, expireTimeBearer time.Duration, expireTimeRe
 		prefixBearerToken:     "Bearer",
 		prefixXApiKeyCacheKey: "xapikey-",
 		prefixRefreshCacheKey: "refresh-",
+		prefixSessionCacheKey: "session-",
 		encrypt:               *encrypt.NewEncrypt(),
 		cacheMemory:           memorycache.NewMemoryCache(),
 		cacheMemoryExpire:     time.Duration(5) * time.Second,
@@ -82,12 +101,38 @@ func NewAuthServicePrefix(authPrefixCache string, authRefreshCache string, cache
 		prefixBearerToken:     "Bearer",
 		prefixXApiKeyCacheKey: "xapikey-",
 		prefixRefreshCacheKey: "refresh-",
+		prefixSessionCacheKey: "session-",
 		encrypt:               *encrypt.NewEncrypt(),
 		cacheMemory:           memorycache.NewMemoryCache(),
 		cacheMemoryExpire:     time.Duration(5) * time.Second,
 	}
 }
 
+// SetApiKeyResolver set resolver of managed api key, x-api-key is looked up only in cache when it is not set
+func (authService *AuthService) SetApiKeyResolver(resolver IApiKeyResolver) {
+	authService.apiKeyResolver = resolver
+}
+
+// resolveApiKey return user info of managed api key, handled is false when the token is not managed api key
+func (authService *AuthService) resolveApiKey(c echo.Context, tokenCtx *TokenContext) (handled bool, err error) {
+	if tokenCtx.tokenType != AUTHTYPE_XAPIKEY || authService.apiKeyResolver == nil {
+		return false, nil
+	}
+
+	userInfo, found, err := authService.apiKeyResolver.ResolveApiKey(tokenCtx.token, c.RealIP())
+	if err != nil {
+		return true, err
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	c.Set("UserInfo", userInfo)
+	setRequestActor(c, userInfo, AuthTypeApiKey)
+	return true, nil
+}
+
 func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath []string, publicPath ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -111,9 +156,19 @@ func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath
 				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Token Invalid."})
 			}
 
+			handled, err := authService.resolveApiKey(c, tokenCtx)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": err.Error()})
+			}
+
+			if handled {
+				return next(c)
+			}
+
 			cacheKey := authService.GetPrefixCacheKey(tokenCtx.tokenType) + tokenCtx.token
 
 			tempUserInfo := models.UserInfo{}
+			sessionID := ""
 
 			// memTempUserInfo, memExists := authService.cacheMemory.Get(cacheKey)
 
@@ -123,7 +178,7 @@ func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath
 
 			if len(tempUserInfo.Username) < 1 {
 
-				tempUserInfoRaw, err := authService.cacher.HMGet(cacheKey, []string{"username", "name", "shopid", "role"})
+				tempUserInfoRaw, err := authService.cacher.HMGet(cacheKey, []string{"username", "name", "shopid", "role", tokenSessionField})
 
 				if err != nil {
 					return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Token Invalid."})
@@ -138,6 +193,10 @@ func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath
 					}
 				}
 
+				if tempUserInfoRaw[4] != nil {
+					sessionID = fmt.Sprintf("%v", tempUserInfoRaw[4])
+				}
+
 				if tempUserInfoRaw[3] != nil {
 					userRole, err := strconv.Atoi(fmt.Sprintf("%v", tempUserInfoRaw[3]))
 					tempUserInfo.Role = uint8(userRole)
@@ -185,8 +244,10 @@ func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath
 				userInfo.Role = tempUserInfo.Role
 			}
 
+			realIP := c.RealIP()
 			go func() {
 				authService.ReTokenExpire(tokenCtx.tokenType, cacheKey)
+				authService.touchSession(userInfo.Username, sessionID, realIP)
 
 				if userInfo.ShopID != "" {
 					authService.cacheMemory.Set(cacheKey, userInfo, authService.cacheMemoryExpire)
@@ -194,6 +255,7 @@ func (authService *AuthService) MWFuncWithRedisMixShop(cacher ICacher, shopPath
 			}()
 
 			c.Set("UserInfo", userInfo)
+			setRequestActor(c, userInfo, authTypeName(tokenCtx.tokenType))
 
 			return next(c)
 		}
@@ -221,9 +283,18 @@ func (authService *AuthService) MWFuncWithRedis(cacher ICacher, publicPath ...st
 				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Token Invalid."})
 			}
 
+			handled, err := authService.resolveApiKey(c, tokenCtx)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": err.Error()})
+			}
+
+			if handled {
+				return next(c)
+			}
+
 			cacheKey := authService.GetPrefixCacheKey(tokenCtx.tokenType) + tokenCtx.token
 
-			tempUserInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", "name", "shopid", "role"})
+			tempUserInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", "name", "shopid", "role", tokenSessionField})
 
 			if err != nil || tempUserInfo[0] == nil {
 				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Token Invalid."})
@@ -254,7 +325,11 @@ func (authService *AuthService) MWFuncWithRedis(cacher ICacher, publicPath ...st
 			}
 
 			authService.ReTokenExpire(tokenCtx.tokenType, cacheKey)
+			if tempUserInfo[4] != nil {
+				go authService.touchSession(userInfo.Username, fmt.Sprintf("%v", tempUserInfo[4]), c.RealIP())
+			}
 			c.Set("UserInfo", userInfo)
+			setRequestActor(c, userInfo, authTypeName(tokenCtx.tokenType))
 
 			return next(c)
 		}
@@ -298,6 +373,7 @@ func (authService *AuthService) MWFuncWithShop(cacher ICacher, publicPath ...str
 			}
 
 			c.Set("UserInfo", userInfo)
+			setRequestActor(c, userInfo, authTypeName(tokenCtx.tokenType))
 
 			return next(c)
 		}
@@ -439,13 +515,13 @@ func (authService *AuthService) SelectShop(tokenType TokenType, tokenStr string,
 		return err
 	}
 
-	return nil
+	return authService.selectSessionShop(cacheKey, shopID)
 }
 
 func (authService *AuthService) RefreshToken(token string) (string, string, error) {
 	cacheKey := authService.GetPrefixCacheKey(AUTHTYPE_REFRESH) + token
 
-	tempUserInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", "name"})
+	tempUserInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", "name", tokenTwoFactorField, tokenSessionField})
 
 	if err != nil || tempUserInfo[0] == nil {
 		return "", "", err
@@ -468,9 +544,55 @@ func (authService *AuthService) RefreshToken(token string) (string, string, erro
 		return "", "", err
 	}
 
+	// new tokens keep two factor verification of the refresh token
+	if isTwoFactorVerified(tempUserInfo[2]) {
+		err = authService.SetTwoFactorVerified(AUTHTYPE_BEARER, tokenStr)
+		if err != nil {
+			return "", "", err
+		}
+
+		err = authService.SetTwoFactorVerified(AUTHTYPE_REFRESH, refreshTokenStr)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if tempUserInfo[3] != nil {
+		err = authService.refreshSession(userInfo.Username, fmt.Sprintf("%v", tempUserInfo[3]), tokenStr, refreshTokenStr)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
 	return tokenStr, refreshTokenStr, err
 }
 
+func (authService *AuthService) SetTwoFactorVerified(tokenType TokenType, tokenStr string) error {
+	cacheKey := authService.GetPrefixCacheKey(tokenType) + tokenStr
+
+	return authService.cacher.HMSet(cacheKey, map[string]interface{}{
+		tokenTwoFactorField: "1",
+	})
+}
+
+// GetTokenTwoFactor return username of the token and whether two factor code is verified for the token,
+// username is empty when the token is not found
+func (authService *AuthService) GetTokenTwoFactor(tokenType TokenType, tokenStr string) (string, bool, error) {
+	cacheKey := authService.GetPrefixCacheKey(tokenType) + tokenStr
+
+	tempTokenInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", tokenTwoFactorField})
+
+	if err != nil || tempTokenInfo[0] == nil {
+		return "", false, err
+	}
+
+	return fmt.Sprintf("%v", tempTokenInfo[0]), isTwoFactorVerified(tempTokenInfo[1]), nil
+}
+
+func isTwoFactorVerified(value interface{}) bool {
+	return value != nil && fmt.Sprintf("%v", value) == "1"
+}
+
 func (authService *AuthService) ReTokenExpire(tokenType TokenType, cacheKey string) {
 	if tokenType == AUTHTYPE_BEARER || tokenType == AUTHTYPE_WEBSOCKET {
 		authService.cacher.Expire(cacheKey, authService.expireTimeBearer)
@@ -0,0 +1,389 @@
+package microservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice/models"
+	"sort"
+	"time"
+)
+
+// tokenSessionField is session id of the token, session id is shown to the user instead of the token
+const tokenSessionField = "sessionid"
+
+// last seen of the session is written at most once in the interval by each replica
+const sessionSeenInterval = time.Minute
+
+// maxSessionSaveAttempts is number of attempts to save the session when it is changed by concurrent requests
+const maxSessionSaveAttempts = 5
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionConflict = errors.New("session is changed by another request at the same time, please try again")
+)
+
+// sessionRecord is stored as field of session hash of the user, it keeps cache keys of tokens of the session
+// so the session is revoked by deleting them. The record is changed only when it is equal to raw which it is
+// read from, so concurrent changes of the session are not lost and removed sessions are not saved again
+type sessionRecord struct {
+	models.UserSession
+	TokenKeys []string `json:"tokenkeys"`
+	raw       string
+}
+
+func (authService *AuthService) sessionCacheKey(username string) string {
+	return authService.prefixSessionCacheKey + username
+}
+
+func (authService *AuthService) sessionExpire() time.Duration {
+	if authService.expireTimeRefresh > authService.expireTimeBearer {
+		return authService.expireTimeRefresh
+	}
+	return authService.expireTimeBearer
+}
+
+// CreateSession register tokens of new login of the user, refresh token is optional
+func (authService *AuthService) CreateSession(tokenStr string, refreshTokenStr string, session models.UserSession) (string, error) {
+	session.SessionID = NewUUID()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	session.LastSeenAt = session.CreatedAt
+
+	record := sessionRecord{UserSession: session}
+
+	tokenKeys := []string{authService.GetPrefixCacheKey(AUTHTYPE_BEARER) + tokenStr}
+	if refreshTokenStr != "" {
+		tokenKeys = append(tokenKeys, authService.GetPrefixCacheKey(AUTHTYPE_REFRESH)+refreshTokenStr)
+	}
+
+	err := authService.addSessionTokens(&record, tokenKeys...)
+	if err != nil {
+		return "", err
+	}
+
+	err = authService.saveSession(record)
+	if err != nil {
+		return "", err
+	}
+
+	return session.SessionID, nil
+}
+
+// GetTokenSessionID return session id of the token, it is empty for token which is issued without session
+func (authService *AuthService) GetTokenSessionID(tokenType TokenType, tokenStr string) (string, error) {
+	cacheKey := authService.GetPrefixCacheKey(tokenType) + tokenStr
+
+	tempTokenInfo, err := authService.cacher.HMGet(cacheKey, []string{tokenSessionField})
+	if err != nil || tempTokenInfo[0] == nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v", tempTokenInfo[0]), nil
+}
+
+// ListSessions return active sessions of the user, last seen first,
+// sessions which all tokens are expired are removed
+func (authService *AuthService) ListSessions(username string) ([]models.UserSession, error) {
+	records, err := authService.findSessions(username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []models.UserSession{}
+	for _, record := range records {
+		aliveKeys, err := authService.aliveTokenKeys(record)
+		if err != nil {
+			return nil, err
+		}
+
+		// session which is changed since it is read keeps its tokens
+		if len(aliveKeys) == 0 {
+			_, err = authService.cacher.HCompareAndDelete(authService.sessionCacheKey(username), record.SessionID, record.raw)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if len(aliveKeys) != len(record.TokenKeys) {
+			record.TokenKeys = aliveKeys
+			_, err = authService.compareAndSaveSession(record)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		sessions = append(sessions, record.UserSession)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession delete every token of the session
+func (authService *AuthService) RevokeSession(username string, sessionID string) error {
+	found, err := authService.deleteSession(username, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeSessions delete every session of the user except exceptSessionID, it is empty to revoke all
+func (authService *AuthService) RevokeSessions(username string, exceptSessionID string) error {
+	records, err := authService.findSessions(username)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.SessionID == exceptSessionID {
+			continue
+		}
+
+		_, err = authService.deleteSession(username, record.SessionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteSession remove the session when it is not changed since it is read and then delete its tokens,
+// so tokens which are added to the session by concurrent refresh are deleted too
+func (authService *AuthService) deleteSession(username string, sessionID string) (bool, error) {
+	for attempt := 0; attempt < maxSessionSaveAttempts; attempt++ {
+		record, found, err := authService.findSession(username, sessionID)
+		if err != nil || !found {
+			return false, err
+		}
+
+		isDeleted, err := authService.cacher.HCompareAndDelete(authService.sessionCacheKey(username), sessionID, record.raw)
+		if err != nil {
+			return false, err
+		}
+
+		if isDeleted {
+			return true, authService.cacher.Del(record.TokenKeys...)
+		}
+	}
+
+	return false, ErrSessionConflict
+}
+
+// refreshSession move refreshed tokens into session of the refresh token,
+// refreshed tokens are deleted when the session is revoked at the same time
+func (authService *AuthService) refreshSession(username string, sessionID string, tokenStr string, refreshTokenStr string) error {
+	tokenKeys := []string{
+		authService.GetPrefixCacheKey(AUTHTYPE_BEARER) + tokenStr,
+		authService.GetPrefixCacheKey(AUTHTYPE_REFRESH) + refreshTokenStr,
+	}
+
+	found, err := authService.updateSession(username, sessionID, func(record *sessionRecord) error {
+		aliveKeys, err := authService.aliveTokenKeys(*record)
+		if err != nil {
+			return err
+		}
+
+		record.TokenKeys = aliveKeys
+		record.LastSeenAt = time.Now()
+
+		return authService.addSessionTokens(record, tokenKeys...)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		err = authService.cacher.Del(tokenKeys...)
+		if err != nil {
+			return err
+		}
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// selectSessionShop record shop which is selected by token of the session
+func (authService *AuthService) selectSessionShop(cacheKey string, shopID string) error {
+	tempTokenInfo, err := authService.cacher.HMGet(cacheKey, []string{"username", tokenSessionField})
+	if err != nil || tempTokenInfo[0] == nil || tempTokenInfo[1] == nil {
+		return err
+	}
+
+	_, err = authService.updateSession(fmt.Sprintf("%v", tempTokenInfo[0]), fmt.Sprintf("%v", tempTokenInfo[1]), func(record *sessionRecord) error {
+		record.ShopID = shopID
+		return nil
+	})
+	return err
+}
+
+// touchSession update last seen and ip of the session of authenticated request
+func (authService *AuthService) touchSession(username string, sessionID string, ip string) {
+	if sessionID == "" {
+		return
+	}
+
+	seenKey := "session-seen-" + sessionID
+	if _, seen := authService.cacheMemory.Get(seenKey); seen {
+		return
+	}
+	authService.cacheMemory.Set(seenKey, true, sessionSeenInterval)
+
+	_, err := authService.updateSession(username, sessionID, func(record *sessionRecord) error {
+		record.LastSeenAt = time.Now()
+		if ip != "" {
+			record.IP = ip
+		}
+		return nil
+	})
+	if err != nil {
+		logger.GetLogger().Errorf("Touch session %s: %v", sessionID, err)
+	}
+}
+
+// updateSession change the session by update and save it when it is not changed since it is read,
+// update is run again on the session which is read again otherwise. False is returned when the session
+// is not found, so revoked session is not saved again
+func (authService *AuthService) updateSession(username string, sessionID string, update func(record *sessionRecord) error) (bool, error) {
+	for attempt := 0; attempt < maxSessionSaveAttempts; attempt++ {
+		record, found, err := authService.findSession(username, sessionID)
+		if err != nil || !found {
+			return false, err
+		}
+
+		err = update(&record)
+		if err != nil {
+			return false, err
+		}
+
+		isSaved, err := authService.compareAndSaveSession(record)
+		if err != nil {
+			return false, err
+		}
+
+		if isSaved {
+			return true, nil
+		}
+	}
+
+	return false, ErrSessionConflict
+}
+
+// aliveTokenKeys return token keys of the session which are not expired
+func (authService *AuthService) aliveTokenKeys(record sessionRecord) ([]string, error) {
+	aliveKeys := []string{}
+	for _, tokenKey := range record.TokenKeys {
+		exists, err := authService.cacher.Exists(tokenKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			aliveKeys = append(aliveKeys, tokenKey)
+		}
+	}
+
+	return aliveKeys, nil
+}
+
+func (authService *AuthService) addSessionTokens(record *sessionRecord, tokenKeys ...string) error {
+	for _, tokenKey := range tokenKeys {
+		err := authService.cacher.HMSet(tokenKey, map[string]interface{}{
+			tokenSessionField: record.SessionID,
+		})
+		if err != nil {
+			return err
+		}
+
+		record.TokenKeys = append(record.TokenKeys, tokenKey)
+	}
+
+	return nil
+}
+
+func (authService *AuthService) findSession(username string, sessionID string) (sessionRecord, bool, error) {
+	raw, err := authService.cacher.HGet(authService.sessionCacheKey(username), sessionID)
+	if err != nil || raw == "" {
+		return sessionRecord{}, false, err
+	}
+
+	record := sessionRecord{}
+	err = json.Unmarshal([]byte(raw), &record)
+	if err != nil {
+		return sessionRecord{}, false, err
+	}
+	record.raw = raw
+
+	return record, true, nil
+}
+
+func (authService *AuthService) findSessions(username string) ([]sessionRecord, error) {
+	rawSessions, err := authService.cacher.HGetAll(authService.sessionCacheKey(username))
+	if err != nil {
+		return nil, err
+	}
+
+	records := []sessionRecord{}
+	for _, raw := range rawSessions {
+		record := sessionRecord{}
+		err = json.Unmarshal([]byte(raw), &record)
+		if err != nil {
+			return nil, err
+		}
+		record.raw = raw
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (authService *AuthService) saveSession(record sessionRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sessionKey := authService.sessionCacheKey(record.Username)
+
+	err = authService.cacher.HMSet(sessionKey, map[string]interface{}{
+		record.SessionID: string(raw),
+	})
+	if err != nil {
+		return err
+	}
+
+	return authService.cacher.Expire(sessionKey, authService.sessionExpire())
+}
+
+// compareAndSaveSession save the session when it is equal to the session which it is read from,
+// false is returned when the session is changed or removed since it is read
+func (authService *AuthService) compareAndSaveSession(record sessionRecord) (bool, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+
+	sessionKey := authService.sessionCacheKey(record.Username)
+
+	isSaved, err := authService.cacher.HCompareAndSet(sessionKey, record.SessionID, record.raw, string(raw))
+	if err != nil || !isSaved {
+		return false, err
+	}
+
+	return true, authService.cacher.Expire(sessionKey, authService.sessionExpire())
+}
@@ -0,0 +1,234 @@
+package microservice
+
+import (
+	"smlaicloudplatform/pkg/microservice/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hashCacherStub keep hashes in memory, expiration is ignored. beforeCompare is run before
+// the field is compared, so a test can change the hash at the same time
+type hashCacherStub struct {
+	ICacher
+	hashes        map[string]map[string]interface{}
+	beforeCompare func()
+}
+
+func newHashCacherStub() *hashCacherStub {
+	return &hashCacherStub{hashes: map[string]map[string]interface{}{}}
+}
+
+func (c *hashCacherStub) HMSet(key string, fieldValues map[string]interface{}) error {
+	if c.hashes[key] == nil {
+		c.hashes[key] = map[string]interface{}{}
+	}
+	for field, value := range fieldValues {
+		c.hashes[key][field] = value
+	}
+	return nil
+}
+
+func (c *hashCacherStub) HMGet(key string, fields []string) ([]interface{}, error) {
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i] = c.hashes[key][field]
+	}
+	return values, nil
+}
+
+func (c *hashCacherStub) HGet(key string, field string) (string, error) {
+	value, ok := c.hashes[key][field]
+	if !ok {
+		return "", nil
+	}
+	return value.(string), nil
+}
+
+func (c *hashCacherStub) HGetAll(key string) (map[string]string, error) {
+	values := map[string]string{}
+	for field, value := range c.hashes[key] {
+		values[field] = value.(string)
+	}
+	return values, nil
+}
+
+func (c *hashCacherStub) HDel(key string, fields ...string) error {
+	for _, field := range fields {
+		delete(c.hashes[key], field)
+	}
+	return nil
+}
+
+func (c *hashCacherStub) compare(key string, field string, oldValue string) bool {
+	if c.beforeCompare != nil {
+		beforeCompare := c.beforeCompare
+		c.beforeCompare = nil
+		beforeCompare()
+	}
+
+	value, ok := c.hashes[key][field]
+	return ok && value == oldValue
+}
+
+func (c *hashCacherStub) HCompareAndSet(key string, field string, oldValue string, newValue string) (bool, error) {
+	if !c.compare(key, field, oldValue) {
+		return false, nil
+	}
+	c.hashes[key][field] = newValue
+	return true, nil
+}
+
+func (c *hashCacherStub) HCompareAndDelete(key string, field string, oldValue string) (bool, error) {
+	if !c.compare(key, field, oldValue) {
+		return false, nil
+	}
+	delete(c.hashes[key], field)
+	return true, nil
+}
+
+func (c *hashCacherStub) Exists(key string) (bool, error) {
+	_, ok := c.hashes[key]
+	return ok, nil
+}
+
+func (c *hashCacherStub) Del(keys ...string) error {
+	for _, key := range keys {
+		delete(c.hashes, key)
+	}
+	return nil
+}
+
+func (c *hashCacherStub) Expire(key string, expire time.Duration) error {
+	if expire < 0 {
+		delete(c.hashes, key)
+	}
+	return nil
+}
+
+func TestAuthSession(t *testing.T) {
+	cacher := newHashCacherStub()
+	authService := NewAuthService(cacher, time.Hour, 24*time.Hour)
+
+	login := func(deviceName string) (string, string, string) {
+		userInfo := models.UserInfo{Username: "user01", Name: "user01"}
+		tokenStr, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+		refreshTokenStr, _ := authService.GenerateTokenWithRedis(AUTHTYPE_REFRESH, userInfo)
+
+		sessionID, err := authService.CreateSession(tokenStr, refreshTokenStr, models.UserSession{
+			Username:   "user01",
+			DeviceName: deviceName,
+			IP:         "203.0.113.10",
+			AuthType:   "password",
+		})
+		assert.Nil(t, err)
+		return sessionID, tokenStr, refreshTokenStr
+	}
+
+	posSessionID, posToken, posRefreshToken := login("POS")
+	webSessionID, webToken, _ := login("Web")
+
+	sessionID, err := authService.GetTokenSessionID(AUTHTYPE_BEARER, posToken)
+	assert.Nil(t, err)
+	assert.Equal(t, posSessionID, sessionID)
+
+	err = authService.SelectShop(AUTHTYPE_BEARER, posToken, "SHOP01", 0)
+	assert.Nil(t, err)
+
+	// refreshed tokens stay in the session
+	newToken, _, err := authService.RefreshToken(posRefreshToken)
+	assert.Nil(t, err)
+
+	sessionID, _ = authService.GetTokenSessionID(AUTHTYPE_BEARER, newToken)
+	assert.Equal(t, posSessionID, sessionID)
+
+	sessions, err := authService.ListSessions("user01")
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+
+	for _, session := range sessions {
+		if session.SessionID == posSessionID {
+			assert.Equal(t, "POS", session.DeviceName)
+			assert.Equal(t, "SHOP01", session.ShopID)
+		}
+	}
+
+	err = authService.RevokeSession("user01", posSessionID)
+	assert.Nil(t, err)
+
+	for _, tokenKey := range []string{"auth-" + posToken, "refresh-" + posRefreshToken, "auth-" + newToken} {
+		exists, _ := cacher.Exists(tokenKey)
+		assert.False(t, exists, tokenKey)
+	}
+
+	err = authService.RevokeSession("user01", posSessionID)
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	login("Mobile")
+
+	err = authService.RevokeSessions("user01", webSessionID)
+	assert.Nil(t, err)
+
+	sessions, _ = authService.ListSessions("user01")
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, webSessionID, sessions[0].SessionID)
+
+	exists, _ := cacher.Exists("auth-" + webToken)
+	assert.True(t, exists)
+
+	// session which tokens are expired is removed
+	for tokenKey := range cacher.hashes {
+		if tokenKey != "session-user01" {
+			cacher.Del(tokenKey)
+		}
+	}
+
+	sessions, _ = authService.ListSessions("user01")
+	assert.Len(t, sessions, 0)
+}
+
+func TestAuthSession_ConcurrentChange(t *testing.T) {
+	cacher := newHashCacherStub()
+	authService := NewAuthService(cacher, time.Hour, 24*time.Hour)
+
+	userInfo := models.UserInfo{Username: "user01", Name: "user01"}
+	tokenStr, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+	refreshTokenStr, _ := authService.GenerateTokenWithRedis(AUTHTYPE_REFRESH, userInfo)
+
+	sessionID, err := authService.CreateSession(tokenStr, refreshTokenStr, models.UserSession{Username: "user01"})
+	assert.Nil(t, err)
+
+	// touch of the request keeps tokens which are added by refresh while it is running
+	newToken, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+	newRefreshToken, _ := authService.GenerateTokenWithRedis(AUTHTYPE_REFRESH, userInfo)
+	cacher.beforeCompare = func() {
+		assert.Nil(t, authService.refreshSession("user01", sessionID, newToken, newRefreshToken))
+	}
+	authService.touchSession("user01", sessionID, "203.0.113.20")
+
+	record, found, _ := authService.findSession("user01", sessionID)
+	assert.True(t, found)
+	assert.Equal(t, "203.0.113.20", record.IP)
+	assert.Contains(t, record.TokenKeys, "auth-"+newToken)
+
+	// revoked session is not saved again by touch which read it before and refresh tokens are deleted
+	cacher.beforeCompare = func() {
+		assert.Nil(t, authService.RevokeSession("user01", sessionID))
+	}
+	authService.cacheMemory.Delete("session-seen-" + sessionID)
+	authService.touchSession("user01", sessionID, "")
+
+	_, found, _ = authService.findSession("user01", sessionID)
+	assert.False(t, found)
+
+	exists, _ := cacher.Exists("auth-" + newToken)
+	assert.False(t, exists)
+
+	lateToken, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+	err = authService.refreshSession("user01", sessionID, lateToken, "late")
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	exists, _ = cacher.Exists("auth-" + lateToken)
+	assert.False(t, exists)
+}
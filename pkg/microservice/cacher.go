@@ -38,12 +38,20 @@ type ICacher interface {
 	HDel(key string, fields ...string) error
 	HExists(key string, field string) (bool, error)
 	HFields(key string, pattern string) ([]string, error)
+	// HCompareAndSet set the field only when it has oldValue, missing field is never equal
+	HCompareAndSet(key string, field string, oldValue string, newValue string) (bool, error)
+	// HCompareAndDelete delete the field only when it has oldValue
+	HCompareAndDelete(key string, field string, oldValue string) (bool, error)
 
 	Set(key string, value interface{}, expire time.Duration) error
 	SetS(key string, value string, expire time.Duration) error
 	SetNoExpire(key string, value interface{}) error
 	SetSNoExpire(key string, value string) error
 	SetNX(key string, value interface{}, expire time.Duration) (bool, error)
+	// CompareAndExpire set expiration only when the key has the value, value is json encoded the same as SetNX
+	CompareAndExpire(key string, value interface{}, expire time.Duration) (bool, error)
+	// CompareAndDelete delete the key only when it has the value, value is json encoded the same as SetNX
+	CompareAndDelete(key string, value interface{}) (bool, error)
 	SetXX(key string, value interface{}, expire time.Duration) error
 	IncrBy(key string, val int) (int, error)
 	DecrBy(key string, val int) (int, error)
@@ -671,6 +679,60 @@ func (cache *Cacher) SetNX(key string, value interface{}, expire time.Duration)
 	return result, nil
 }
 
+var compareAndExpireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// CompareAndExpire set expiration of the key when its value is equal to the value
+func (cache *Cacher) CompareAndExpire(key string, value interface{}, expire time.Duration) (bool, error) {
+
+	c, err := cache.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	str, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := compareAndExpireScript.Run(context.Background(), c, []string{key}, string(str), expire.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return result == 1, nil
+}
+
+// CompareAndDelete delete the key when its value is equal to the value
+func (cache *Cacher) CompareAndDelete(key string, value interface{}) (bool, error) {
+
+	c, err := cache.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	str, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := compareAndDeleteScript.Run(context.Background(), c, []string{key}, string(str)).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return result == 1, nil
+}
+
 func (cache *Cacher) SetXX(key string, value interface{}, expire time.Duration) error {
 
 	c, err := cache.getClient()
@@ -820,6 +882,51 @@ func (cache *Cacher) HDel(key string, fields ...string) error {
 	return nil
 }
 
+var hCompareAndSetScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	return 1
+end
+return 0`)
+
+var hCompareAndDeleteScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call("HDEL", KEYS[1], ARGV[1])
+end
+return 0`)
+
+// HCompareAndSet set the field of the hash to newValue when its value is equal to oldValue
+func (cache *Cacher) HCompareAndSet(key string, field string, oldValue string, newValue string) (bool, error) {
+
+	c, err := cache.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := hCompareAndSetScript.Run(context.Background(), c, []string{key}, field, oldValue, newValue).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return result == 1, nil
+}
+
+// HCompareAndDelete delete the field of the hash when its value is equal to oldValue
+func (cache *Cacher) HCompareAndDelete(key string, field string, oldValue string) (bool, error) {
+
+	c, err := cache.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := hCompareAndDeleteScript.Run(context.Background(), c, []string{key}, field, oldValue).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return result == 1, nil
+}
+
 // HGet object from cache
 func (cache *Cacher) HGet(key string, field string) (string, error) {
 
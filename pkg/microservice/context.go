@@ -1,6 +1,7 @@
 package microservice
 
 import (
+	"context"
 	"mime/multipart"
 	"net/http"
 	"smlaicloudplatform/internal/config"
@@ -24,6 +25,8 @@ type IContext interface {
 	FormValue(field string) string
 	ResponseWriter() http.ResponseWriter
 	Request() *http.Request
+	// Context return context of the request which carry the request actor, it is background outside http
+	Context() context.Context
 
 	Persister(cfg config.IPersisterConfig) IPersister
 	Cacher(cacherConfig config.ICacherConfig) ICacher
@@ -1,6 +1,7 @@
 package microservice
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -145,6 +146,10 @@ func (ctx *AsyncTaskContext) Request() *http.Request {
 	return nil
 }
 
+func (ctx *AsyncTaskContext) Context() context.Context {
+	return context.Background()
+}
+
 func (ctx *AsyncTaskContext) EchoContext() echo.Context {
 	return nil
 }
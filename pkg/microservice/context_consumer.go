@@ -1,6 +1,7 @@
 package microservice
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -15,8 +16,10 @@ import (
 
 // ConsumerContext implement IContext it is context for Consumer
 type ConsumerContext struct {
-	ms      *Microservice
-	message string
+	ms       *Microservice
+	message  string
+	metadata ConsumerMessage
+	event    EventEnvelope
 }
 
 // NewConsumerContext is the constructor function for ConsumerContext
@@ -27,6 +30,42 @@ func NewConsumerContext(ms *Microservice, message string) *ConsumerContext {
 	}
 }
 
+// NewConsumerContextWithMessage is the constructor function for ConsumerContext with message metadata,
+// message in envelope is unwrapped and ReadInput return the upcasted payload
+func NewConsumerContextWithMessage(ms *Microservice, msg ConsumerMessage) (*ConsumerContext, error) {
+	event, err := DecodeEventMessage(msg.Topic, msg.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsumerContext{
+		ms:       ms,
+		message:  string(event.Payload),
+		metadata: msg,
+		event:    event,
+	}, nil
+}
+
+// ConsumerMessage return message with topic, key, partition and offset
+func (ctx *ConsumerContext) ConsumerMessage() ConsumerMessage {
+	return ctx.metadata
+}
+
+// ConsumerMessageFromContext return message metadata when the context is consumer context
+func ConsumerMessageFromContext(ctx IContext) (ConsumerMessage, bool) {
+	consumerCtx, ok := ctx.(*ConsumerContext)
+	if !ok || consumerCtx.metadata.Topic == "" {
+		return ConsumerMessage{}, false
+	}
+
+	return consumerCtx.metadata, true
+}
+
+// Version return unique message version from topic, partition and offset
+func (msg ConsumerMessage) Version() string {
+	return fmt.Sprintf("%s:%d:%d", msg.Topic, msg.Partition, msg.Offset)
+}
+
 // Log will log a message
 func (ctx *ConsumerContext) Log(message string) {
 	_, fn, line, _ := runtime.Caller(1)
@@ -126,6 +165,10 @@ func (ctx *ConsumerContext) Request() *http.Request {
 	return nil
 }
 
+func (ctx *ConsumerContext) Context() context.Context {
+	return context.Background()
+}
+
 func (ctx *ConsumerContext) EchoContext() echo.Context {
 	return nil
 }
@@ -1,6 +1,7 @@
 package microservice
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -55,6 +56,7 @@ func (ctx *HTTPContext) Header(attribute string) string {
 	return ctx.c.Request().Header.Get(attribute)
 }
 
+// RealIp return client ip from IPExtractor of the microservice, X-Forwarded-For is used only from trusted proxies
 func (ctx *HTTPContext) RealIp() string {
 	return ctx.c.RealIP()
 }
@@ -126,6 +128,10 @@ func (ctx *HTTPContext) Request() *http.Request {
 	return ctx.c.Request()
 }
 
+func (ctx *HTTPContext) Context() context.Context {
+	return ctx.c.Request().Context()
+}
+
 func (ctx *HTTPContext) Bind(obj any) {
 	ctx.c.Bind(obj)
 }
@@ -0,0 +1,236 @@
+package microservice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is parsed cron expression "minute hour day-of-month month day-of-week",
+// it also accept @yearly, @monthly, @weekly, @daily, @hourly and @every <duration> in whole minutes
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domAny and dowAny follow cron rule, the day match either field when both are restricted
+	domAny bool
+	dowAny bool
+	every  time.Duration
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinuteField = cronField{min: 0, max: 59}
+	cronHourField   = cronField{min: 0, max: 23}
+	cronDomField    = cronField{min: 1, max: 31}
+	cronMonthField  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// day of week 7 is sunday as 0
+	cronDowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parse cron expression
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %v", spec, err)
+		}
+
+		if every < time.Minute || every%time.Minute != 0 {
+			return nil, fmt.Errorf("cron %q: interval must be whole minutes", spec)
+		}
+
+		return &CronSchedule{every: every}, nil
+	}
+
+	if expr, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields", spec)
+	}
+
+	schedule := &CronSchedule{
+		domAny: fields[2] == "*" || fields[2] == "?",
+		dowAny: fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+	for i, target := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&schedule.minute, cronMinuteField},
+		{&schedule.hour, cronHourField},
+		{&schedule.dom, cronDomField},
+		{&schedule.month, cronMonthField},
+		{&schedule.dow, cronDowField},
+	} {
+		*target.bits, err = parseCronField(fields[i], target.field)
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %v", spec, err)
+		}
+	}
+
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1 << 0
+	}
+
+	return schedule, nil
+}
+
+// parseCronField return bit set of values of comma separated list of *, a, a-b with optional /step
+func parseCronField(expr string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			value, err := strconv.Atoi(part[idx+1:])
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = value
+			part = part[:idx]
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = field.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = field.value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := field.value(part)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func (field cronField) value(expr string) (int, error) {
+	if value, ok := field.names[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", expr)
+	}
+
+	if value < field.min || value > field.max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d", value, field.min, field.max)
+	}
+
+	return value, nil
+}
+
+// Match return true when the schedule fire at the minute of t in location of t
+func (s *CronSchedule) Match(t time.Time) bool {
+	if s.every > 0 {
+		return t.Truncate(time.Minute).Unix()%int64(s.every/time.Second) == 0
+	}
+
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.dayMatch(t)
+}
+
+func (s *CronSchedule) dayMatch(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// Next return the first fire time after t in location of t, zero time when there is none in 5 years
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	if s.every > 0 {
+		for !s.Match(next) {
+			next = next.Add(time.Minute)
+		}
+		return next
+	}
+
+	loc := t.Location()
+	yearLimit := next.Year() + 5
+
+	for next.Year() <= yearLimit {
+		if s.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !s.dayMatch(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+
+		return next
+	}
+
+	return time.Time{}
+}
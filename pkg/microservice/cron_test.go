@@ -0,0 +1,81 @@
+package microservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"@every 30s",
+		"@every 90s",
+		"@every x",
+	} {
+		_, err := ParseCron(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestCronMatch(t *testing.T) {
+	schedule, err := ParseCron("*/15 8-17 * jan-mar mon-fri")
+	assert.Nil(t, err)
+
+	// 2023-01-02 is monday
+	assert.True(t, schedule.Match(time.Date(2023, 1, 2, 8, 0, 0, 0, time.UTC)))
+	assert.True(t, schedule.Match(time.Date(2023, 1, 2, 17, 45, 30, 0, time.UTC)))
+	assert.False(t, schedule.Match(time.Date(2023, 1, 2, 8, 10, 0, 0, time.UTC)))
+	assert.False(t, schedule.Match(time.Date(2023, 1, 2, 18, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.Match(time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC)), "sunday")
+	assert.False(t, schedule.Match(time.Date(2023, 4, 3, 8, 0, 0, 0, time.UTC)), "april")
+
+	sunday, _ := ParseCron("0 0 * * 7")
+	assert.True(t, sunday.Match(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCronDayOfMonthOrDayOfWeek(t *testing.T) {
+	// day 1 of month or every monday when both fields are restricted
+	schedule, _ := ParseCron("0 0 1 * mon")
+	assert.True(t, schedule.Match(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, schedule.Match(time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, schedule.Match(time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC)))
+
+	// only day 1 when day of week is *
+	monthly, _ := ParseCron("@monthly")
+	assert.True(t, monthly.Match(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, monthly.Match(time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCronNext(t *testing.T) {
+	daily, _ := ParseCron("30 2 * * *")
+	from := time.Date(2023, 1, 31, 2, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, 2, 1, 2, 30, 0, 0, time.UTC), daily.Next(from))
+
+	leapDay, _ := ParseCron("0 0 29 2 *")
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), leapDay.Next(from))
+
+	never, _ := ParseCron("0 0 31 2 *")
+	assert.True(t, never.Next(from).IsZero())
+
+	every, _ := ParseCron("@every 15m")
+	assert.Equal(t, time.Date(2023, 1, 31, 2, 45, 0, 0, time.UTC), every.Next(from))
+}
+
+func TestCronNextInLocation(t *testing.T) {
+	bangkok := time.FixedZone("+07:00", 7*60*60)
+	daily, _ := ParseCron("@daily")
+
+	// 18:00 UTC is 01:00 in bangkok so the next midnight is at 17:00 UTC of the next day
+	next := daily.Next(time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC).In(bangkok))
+	assert.Equal(t, time.Date(2023, 1, 2, 17, 0, 0, 0, time.UTC), next.UTC())
+}
@@ -0,0 +1,288 @@
+package microservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultEventSchemaVersion is schema version of message which does not implement IEventSchema
+const DefaultEventSchemaVersion = 1
+
+// EventEnvelope wrap every message sent to the message queue with event metadata,
+// payload is json of the document sent by the producer
+type EventEnvelope struct {
+	EventID       string          `json:"eventid"`
+	EventType     string          `json:"eventtype"`
+	SchemaVersion int             `json:"schemaversion"`
+	ShopID        string          `json:"shopid"`
+	Actor         string          `json:"actor"`
+	OccurredAt    time.Time       `json:"occurredat"`
+	TraceID       string          `json:"traceid"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// IEventSchema is implemented by message which has versioned json schema,
+// the version is increased when the json is changed and upcaster of the old version is registered
+type IEventSchema interface {
+	EventSchemaVersion() int
+}
+
+// EventOption set metadata of new event
+type EventOption func(*EventEnvelope)
+
+// WithEventActor set user who cause the event
+func WithEventActor(actor string) EventOption {
+	return func(event *EventEnvelope) {
+		event.Actor = actor
+	}
+}
+
+// WithEventTraceID continue the trace of the event which cause this event
+func WithEventTraceID(traceID string) EventOption {
+	return func(event *EventEnvelope) {
+		event.TraceID = traceID
+	}
+}
+
+// WithEventShopID set shop of the event when the payload does not have shopid
+func WithEventShopID(shopID string) EventOption {
+	return func(event *EventEnvelope) {
+		event.ShopID = shopID
+	}
+}
+
+// eventPayloadMetadata is the metadata read from document of the payload
+type eventPayloadMetadata struct {
+	ShopID    string `json:"shopid"`
+	CreatedBy string `json:"createdby"`
+	UpdatedBy string `json:"updatedby"`
+	DeletedBy string `json:"deletedby"`
+}
+
+// NewEventEnvelope wrap the message, shopid and actor are read from the document (or first document of bulk message)
+// when they are not set by options, trace id is generated for a new trace
+func NewEventEnvelope(eventType string, message interface{}, opts ...EventOption) (EventEnvelope, error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return EventEnvelope{}, err
+	}
+
+	metadata := parseEventPayloadMetadata(payload)
+
+	actor := metadata.DeletedBy
+	if actor == "" {
+		actor = metadata.UpdatedBy
+	}
+	if actor == "" {
+		actor = metadata.CreatedBy
+	}
+
+	event := EventEnvelope{
+		EventID:       uuid.NewString(),
+		EventType:     eventType,
+		SchemaVersion: eventSchemaVersion(message),
+		ShopID:        metadata.ShopID,
+		Actor:         actor,
+		OccurredAt:    time.Now(),
+		TraceID:       uuid.NewString(),
+		Payload:       payload,
+	}
+
+	for _, opt := range opts {
+		opt(&event)
+	}
+
+	return event, nil
+}
+
+func parseEventPayloadMetadata(payload []byte) eventPayloadMetadata {
+	metadata := eventPayloadMetadata{}
+	if err := json.Unmarshal(payload, &metadata); err == nil {
+		return metadata
+	}
+
+	docs := []eventPayloadMetadata{}
+	if err := json.Unmarshal(payload, &docs); err == nil && len(docs) > 0 {
+		return docs[0]
+	}
+
+	return eventPayloadMetadata{}
+}
+
+// eventSchemaVersion return schema version of the message, bulk message use version of the element type
+func eventSchemaVersion(message interface{}) int {
+	if schema, ok := message.(IEventSchema); ok {
+		return schema.EventSchemaVersion()
+	}
+
+	t := reflect.TypeOf(message)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+		if schema, ok := reflect.Zero(t).Interface().(IEventSchema); ok {
+			return schema.EventSchemaVersion()
+		}
+	}
+
+	return DefaultEventSchemaVersion
+}
+
+// eventEnvelopeKeys are the fields which must exist in json object to be an envelope
+var eventEnvelopeKeys = []string{"eventid", "eventtype", "schemaversion", "payload"}
+
+// ParseEventEnvelope parse message value, ok is false when the value is legacy message without envelope
+func ParseEventEnvelope(value []byte) (EventEnvelope, bool) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return EventEnvelope{}, false
+	}
+
+	for _, key := range eventEnvelopeKeys {
+		if _, ok := fields[key]; !ok {
+			return EventEnvelope{}, false
+		}
+	}
+
+	event := EventEnvelope{}
+	if err := json.Unmarshal(value, &event); err != nil || event.EventID == "" {
+		return EventEnvelope{}, false
+	}
+
+	return event, true
+}
+
+// EventUpcaster convert payload of the schema version to the next version
+type EventUpcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+// EventUpcasterRegistry keep upcasters by event type and schema version
+type EventUpcasterRegistry struct {
+	mutex     sync.RWMutex
+	upcasters map[string]map[int]EventUpcaster
+}
+
+// NewEventUpcasterRegistry return empty registry
+func NewEventUpcasterRegistry() *EventUpcasterRegistry {
+	return &EventUpcasterRegistry{
+		upcasters: map[string]map[int]EventUpcaster{},
+	}
+}
+
+// Register upcaster which convert payload of the event type from fromVersion to fromVersion+1
+func (r *EventUpcasterRegistry) Register(eventType string, fromVersion int, upcaster EventUpcaster) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	versions, ok := r.upcasters[eventType]
+	if !ok {
+		versions = map[int]EventUpcaster{}
+		r.upcasters[eventType] = versions
+	}
+	versions[fromVersion] = upcaster
+}
+
+// Upcast apply upcasters from the event schema version until the latest registered version
+func (r *EventUpcasterRegistry) Upcast(event EventEnvelope) (EventEnvelope, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions := r.upcasters[event.EventType]
+	for {
+		upcaster, ok := versions[event.SchemaVersion]
+		if !ok {
+			return event, nil
+		}
+
+		payload, err := upcaster(event.Payload)
+		if err != nil {
+			return event, fmt.Errorf("upcast event %s version %d: %w", event.EventType, event.SchemaVersion, err)
+		}
+
+		event.Payload = payload
+		event.SchemaVersion++
+	}
+}
+
+// EventUpcasters is the registry used by consumers
+var EventUpcasters = NewEventUpcasterRegistry()
+
+// RegisterEventUpcaster register upcaster of the event types to EventUpcasters
+func RegisterEventUpcaster(fromVersion int, upcaster EventUpcaster, eventTypes ...string) {
+	for _, eventType := range eventTypes {
+		EventUpcasters.Register(eventType, fromVersion, upcaster)
+	}
+}
+
+// DecodeEventMessage parse message value into envelope and payload with upcasters applied,
+// legacy message without envelope is decoded as payload of the first schema version without metadata
+func DecodeEventMessage(topic string, value string) (EventEnvelope, error) {
+	event, ok := ParseEventEnvelope([]byte(value))
+	if !ok {
+		event = EventEnvelope{
+			EventType:     topic,
+			SchemaVersion: DefaultEventSchemaVersion,
+			Payload:       json.RawMessage(value),
+		}
+	}
+
+	return EventUpcasters.Upcast(event)
+}
+
+// DecodeEvent read payload of the consumer message into T, the payload is already upcasted to the latest schema
+func DecodeEvent[T any](ctx IContext) (T, error) {
+	var doc T
+	err := json.Unmarshal([]byte(ctx.ReadInput()), &doc)
+	if err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+// EventFromContext return envelope of the message consumed by the context
+func EventFromContext(ctx IContext) (EventEnvelope, bool) {
+	consumerCtx, ok := ctx.(*ConsumerContext)
+	if !ok || consumerCtx.event.EventID == "" {
+		return EventEnvelope{}, false
+	}
+
+	return consumerCtx.event, true
+}
+
+// EventProducer wrap message into EventEnvelope before send it with the producer,
+// envelope and json of envelope are sent as is
+type EventProducer struct {
+	IProducer
+}
+
+// NewEventProducer return producer which send every message in envelope
+func NewEventProducer(prod IProducer) *EventProducer {
+	return &EventProducer{
+		IProducer: prod,
+	}
+}
+
+// SendMessage send message in envelope, event type is the topic
+func (p *EventProducer) SendMessage(topic string, key string, message interface{}) error {
+	event, err := wrapEventMessage(topic, message)
+	if err != nil {
+		return err
+	}
+
+	return p.IProducer.SendMessage(topic, key, event)
+}
+
+func wrapEventMessage(topic string, message interface{}) (interface{}, error) {
+	switch msg := message.(type) {
+	case EventEnvelope, *EventEnvelope:
+		return msg, nil
+	case json.RawMessage:
+		if _, ok := ParseEventEnvelope(msg); ok {
+			return msg, nil
+		}
+	}
+
+	return NewEventEnvelope(topic, message)
+}
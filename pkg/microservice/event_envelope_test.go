@@ -0,0 +1,103 @@
+package microservice
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type eventTestDoc struct {
+	ShopID    string `json:"shopid"`
+	DocNo     string `json:"docno"`
+	CreatedBy string `json:"createdby"`
+	UpdatedBy string `json:"updatedby"`
+}
+
+type eventTestDocV2 struct {
+	eventTestDoc
+}
+
+func (eventTestDocV2) EventSchemaVersion() int {
+	return 2
+}
+
+func TestNewEventEnvelope(t *testing.T) {
+	event, err := NewEventEnvelope("when-sale-invoice-updated", eventTestDoc{ShopID: "SHOP01", DocNo: "INV-0001", CreatedBy: "user01", UpdatedBy: "user02"})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, event.EventID)
+	assert.NotEmpty(t, event.TraceID)
+	assert.Equal(t, "when-sale-invoice-updated", event.EventType)
+	assert.Equal(t, DefaultEventSchemaVersion, event.SchemaVersion)
+	assert.Equal(t, "SHOP01", event.ShopID)
+	assert.Equal(t, "user02", event.Actor)
+	assert.JSONEq(t, `{"shopid":"SHOP01","docno":"INV-0001","createdby":"user01","updatedby":"user02"}`, string(event.Payload))
+
+	bulk, _ := NewEventEnvelope("when-sale-invoice-bulk-created", []eventTestDocV2{{eventTestDoc{ShopID: "SHOP02", CreatedBy: "user01"}}}, WithEventTraceID("trace01"))
+	assert.Equal(t, 2, bulk.SchemaVersion)
+	assert.Equal(t, "SHOP02", bulk.ShopID)
+	assert.Equal(t, "user01", bulk.Actor)
+	assert.Equal(t, "trace01", bulk.TraceID)
+}
+
+func TestEventProducerWrapMessage(t *testing.T) {
+	broker := NewMemoryBroker()
+	producer := NewEventProducer(NewMemoryMQDriver(broker).NewProducer())
+	c, _ := NewMemoryMQDriver(broker).NewConsumer("group-a")
+	defer c.Close()
+	c.Subscribe("when-purchase-created")
+
+	assert.Nil(t, producer.SendMessage("when-purchase-created", "SHOP01", eventTestDoc{ShopID: "SHOP01", DocNo: "PO-0001"}))
+
+	msg, _ := c.ReadMessage(time.Second)
+	event, ok := ParseEventEnvelope([]byte(msg.Value))
+	assert.True(t, ok)
+	assert.Equal(t, "when-purchase-created", event.EventType)
+
+	// envelope from outbox or dead letter redrive is sent as is
+	assert.Nil(t, producer.SendMessage("when-purchase-created", "SHOP01", json.RawMessage(msg.Value)))
+
+	resent, _ := c.ReadMessage(time.Second)
+	assert.Equal(t, msg.Value, resent.Value)
+}
+
+func TestDecodeEventMessageUpcast(t *testing.T) {
+	defer func() { EventUpcasters = NewEventUpcasterRegistry() }()
+
+	RegisterEventUpcaster(1, func(payload json.RawMessage) (json.RawMessage, error) {
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(payload, &doc); err != nil {
+			return nil, err
+		}
+		doc["docno"] = doc["documentno"]
+		delete(doc, "documentno")
+		return json.Marshal(doc)
+	}, "when-purchase-created")
+
+	// legacy message without envelope is the first version
+	event, err := DecodeEventMessage("when-purchase-created", `{"shopid":"SHOP01","documentno":"PO-0001"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, event.SchemaVersion)
+	assert.JSONEq(t, `{"shopid":"SHOP01","docno":"PO-0001"}`, string(event.Payload))
+
+	v2, _ := NewEventEnvelope("when-purchase-created", eventTestDocV2{eventTestDoc{ShopID: "SHOP01", DocNo: "PO-0002"}})
+	value, _ := json.Marshal(v2)
+
+	event, err = DecodeEventMessage("when-purchase-created", string(value))
+	assert.Nil(t, err)
+	assert.Equal(t, v2.EventID, event.EventID)
+	assert.Equal(t, 2, event.SchemaVersion)
+
+	ctx, err := NewConsumerContextWithMessage(nil, ConsumerMessage{Topic: "when-purchase-created", Value: string(value)})
+	assert.Nil(t, err)
+
+	doc, err := DecodeEvent[eventTestDoc](ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "PO-0002", doc.DocNo)
+
+	contextEvent, ok := EventFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, v2.EventID, contextEvent.EventID)
+}
@@ -0,0 +1,40 @@
+package microservice
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor return extractor of client ip of the request, X-Forwarded-For is trusted only from the proxies
+// so client cannot spoof its ip for ip allow list of api key and ip lockout, remote address is used when there is no proxy
+func NewIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	trustRanges := []echo.TrustOption{}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not CIDR: %w", proxy, err)
+		}
+
+		trustRanges = append(trustRanges, echo.TrustIPRange(ipRange))
+	}
+
+	if len(trustRanges) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := append([]echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}, trustRanges...)
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,50 @@
+package microservice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPExtractor(t *testing.T) {
+	request := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.9, 10.0.0.5")
+		return req
+	}
+
+	direct, err := NewIPExtractor([]string{})
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.1", direct(request("198.51.100.1:4000")), "X-Forwarded-For is ignored without trusted proxy")
+
+	behindProxy, err := NewIPExtractor([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.9", behindProxy(request("10.0.0.7:4000")))
+	assert.Equal(t, "198.51.100.1", behindProxy(request("198.51.100.1:4000")), "X-Forwarded-For of client which is not the proxy")
+
+	_, err = NewIPExtractor([]string{"10.0.0.1"})
+	assert.Error(t, err)
+}
+
+func TestHTTPContextRealIpIgnoreSpoofedHeader(t *testing.T) {
+	e := echo.New()
+	extractor, err := NewIPExtractor([]string{})
+	require.NoError(t, err)
+	e.IPExtractor = extractor
+
+	// client rotate X-Forwarded-For to pass ip lockout and otp limit per ip
+	for _, spoofed := range []string{"203.0.113.1", "203.0.113.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "198.51.100.1:4000"
+		req.Header.Set(echo.HeaderXForwardedFor, spoofed)
+		req.Header.Set(echo.HeaderXRealIP, spoofed)
+
+		ctx := NewHTTPContext(&Microservice{}, e.NewContext(req, httptest.NewRecorder()))
+		assert.Equal(t, "198.51.100.1", ctx.RealIp())
+	}
+}
@@ -0,0 +1,312 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"smlaicloudplatform/internal/config"
+	"sync"
+	"time"
+)
+
+const (
+	// lockRetryMinInterval and lockRetryMaxInterval are the wait between attempts of blocking Lock
+	lockRetryMinInterval = 20 * time.Millisecond
+	lockRetryMaxInterval = 500 * time.Millisecond
+)
+
+var (
+	// ErrLockNotAcquired is returned by Lock when the context is done before the lock is acquired
+	ErrLockNotAcquired = errors.New("lock is not acquired")
+	// ErrLockNotOwned is returned when the lock is expired or acquired by other owner
+	ErrLockNotOwned = errors.New("lock is not owned")
+)
+
+// ILockStore keep lock key with owner token and TTL
+type ILockStore interface {
+	// SetNX set the key to the token when the key does not exist
+	SetNX(key string, token string, ttl time.Duration) (bool, error)
+	// CompareAndExpire extend TTL when the key still has the token
+	CompareAndExpire(key string, token string, ttl time.Duration) (bool, error)
+	// CompareAndDelete delete the key when it still has the token
+	CompareAndDelete(key string, token string) (bool, error)
+}
+
+// ILocker acquire lock which is shared by every replica using the same store
+type ILocker interface {
+	// TryLock acquire the lock without waiting, ok is false when the lock is held by other owner
+	TryLock(key string, ttl time.Duration) (ILock, bool, error)
+	// Lock wait until the lock is acquired or the context is done
+	Lock(ctx context.Context, key string, ttl time.Duration) (ILock, error)
+}
+
+// ILock is acquired lock, it is released by Unlock or expired after TTL
+type ILock interface {
+	Key() string
+	Token() string
+	// Refresh extend TTL of the lock, ErrLockNotOwned is returned when the lock is already expired
+	Refresh(ttl time.Duration) error
+	// KeepAlive refresh the lock every interval until Unlock, the returned channel is closed when the lease is lost
+	KeepAlive(interval time.Duration, ttl time.Duration) <-chan struct{}
+	// Unlock release the lock only when it is still owned
+	Unlock() error
+}
+
+// Locker implement ILocker on ILockStore
+type Locker struct {
+	store  ILockStore
+	prefix string
+}
+
+// NewLocker return locker of the store
+func NewLocker(store ILockStore) *Locker {
+	return &Locker{
+		store:  store,
+		prefix: "lock:",
+	}
+}
+
+// NewCacherLocker return locker which keep locks in the cacher
+func NewCacherLocker(cacher ICacher) *Locker {
+	return NewLocker(NewCacherLockStore(cacher))
+}
+
+// NewMemoryLocker return locker of the process, it is used in tests and single instance
+func NewMemoryLocker() *Locker {
+	return NewLocker(NewMemoryLockStore())
+}
+
+func (l *Locker) TryLock(key string, ttl time.Duration) (ILock, bool, error) {
+	token := NewUUID()
+	ok, err := l.store.SetNX(l.prefix+key, token, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &lock{
+		store: l.store,
+		key:   l.prefix + key,
+		token: token,
+		done:  make(chan struct{}),
+	}, true, nil
+}
+
+func (l *Locker) Lock(ctx context.Context, key string, ttl time.Duration) (ILock, error) {
+	wait := lockRetryMinInterval
+	for {
+		acquired, ok, err := l.TryLock(key, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return acquired, nil
+		}
+
+		// jitter avoid waiting owners retry at the same time
+		timer := time.NewTimer(wait/2 + time.Duration(rand.Int63n(int64(wait))))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ErrLockNotAcquired
+		case <-timer.C:
+		}
+
+		wait *= 2
+		if wait > lockRetryMaxInterval {
+			wait = lockRetryMaxInterval
+		}
+	}
+}
+
+type lock struct {
+	store    ILockStore
+	key      string
+	token    string
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (l *lock) Key() string {
+	return l.key
+}
+
+func (l *lock) Token() string {
+	return l.token
+}
+
+func (l *lock) Refresh(ttl time.Duration) error {
+	ok, err := l.store.CompareAndExpire(l.key, l.token, ttl)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrLockNotOwned
+	}
+
+	return nil
+}
+
+func (l *lock) KeepAlive(interval time.Duration, ttl time.Duration) <-chan struct{} {
+	lost := make(chan struct{})
+
+	go func() {
+		defer close(lost)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.done:
+				return
+			case <-ticker.C:
+				if err := l.Refresh(ttl); err == ErrLockNotOwned {
+					return
+				}
+			}
+		}
+	}()
+
+	return lost
+}
+
+func (l *lock) Unlock() error {
+	l.doneOnce.Do(func() {
+		close(l.done)
+	})
+
+	ok, err := l.store.CompareAndDelete(l.key, l.token)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrLockNotOwned
+	}
+
+	return nil
+}
+
+// CacherLockStore keep locks in cacher with SetNX and compare scripts
+type CacherLockStore struct {
+	cacher ICacher
+}
+
+func NewCacherLockStore(cacher ICacher) *CacherLockStore {
+	return &CacherLockStore{
+		cacher: cacher,
+	}
+}
+
+func (s *CacherLockStore) SetNX(key string, token string, ttl time.Duration) (bool, error) {
+	return s.cacher.SetNX(key, token, ttl)
+}
+
+func (s *CacherLockStore) CompareAndExpire(key string, token string, ttl time.Duration) (bool, error) {
+	return s.cacher.CompareAndExpire(key, token, ttl)
+}
+
+func (s *CacherLockStore) CompareAndDelete(key string, token string) (bool, error) {
+	return s.cacher.CompareAndDelete(key, token)
+}
+
+// MemoryLockStore keep locks in memory of the process
+type MemoryLockStore struct {
+	mutex sync.Mutex
+	locks map[string]memoryLockEntry
+	now   func() time.Time
+}
+
+type memoryLockEntry struct {
+	token     string
+	expiredAt time.Time
+}
+
+func NewMemoryLockStore() *MemoryLockStore {
+	return &MemoryLockStore{
+		locks: map[string]memoryLockEntry{},
+		now:   time.Now,
+	}
+}
+
+func (s *MemoryLockStore) SetNX(key string, token string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+
+	s.locks[key] = memoryLockEntry{
+		token:     token,
+		expiredAt: s.now().Add(ttl),
+	}
+	return true, nil
+}
+
+func (s *MemoryLockStore) CompareAndExpire(key string, token string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.get(key)
+	if !ok || entry.token != token {
+		return false, nil
+	}
+
+	entry.expiredAt = s.now().Add(ttl)
+	s.locks[key] = entry
+	return true, nil
+}
+
+func (s *MemoryLockStore) CompareAndDelete(key string, token string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.get(key)
+	if !ok || entry.token != token {
+		return false, nil
+	}
+
+	delete(s.locks, key)
+	return true, nil
+}
+
+// get return lock which is not expired, caller must hold the mutex
+func (s *MemoryLockStore) get(key string) (memoryLockEntry, bool) {
+	entry, ok := s.locks[key]
+	if !ok {
+		return memoryLockEntry{}, false
+	}
+
+	if !s.now().Before(entry.expiredAt) {
+		delete(s.locks, key)
+		return memoryLockEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Locker return locker which keep locks in the cacher
+func (ms *Microservice) Locker(cfg config.ICacherConfig) ILocker {
+	return NewCacherLocker(ms.Cacher(cfg))
+}
+
+// WithLock run fn while holding the lock, the lease is renewed until fn return,
+// fn is run without lock when locker is nil
+func WithLock(ctx context.Context, locker ILocker, key string, ttl time.Duration, fn func() error) error {
+	if locker == nil {
+		return fn()
+	}
+
+	l, err := locker.Lock(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+
+	l.KeepAlive(ttl/3, ttl)
+
+	return fn()
+}
@@ -0,0 +1,106 @@
+package microservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockerTryLockExclusive(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	lock1, ok, err := locker.TryLock("saleinvoice:docno:SHOP01:INV", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = locker.TryLock("saleinvoice:docno:SHOP01:INV", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, ok, "lock is held by other owner")
+
+	_, ok, _ = locker.TryLock("saleinvoice:docno:SHOP02:INV", time.Minute)
+	assert.True(t, ok, "lock of other key is not blocked")
+
+	assert.Nil(t, lock1.Unlock())
+
+	_, ok, _ = locker.TryLock("saleinvoice:docno:SHOP01:INV", time.Minute)
+	assert.True(t, ok, "lock is acquired after unlock")
+}
+
+func TestLockerLeaseExpired(t *testing.T) {
+	store := NewMemoryLockStore()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	locker := NewLocker(store)
+
+	lock1, ok, _ := locker.TryLock("stockcalculator:SHOP01:BARCODE01", time.Minute)
+	assert.True(t, ok)
+
+	now = now.Add(30 * time.Second)
+	assert.Nil(t, lock1.Refresh(time.Minute))
+
+	// refreshed lease is not expired at the first TTL
+	now = now.Add(45 * time.Second)
+	_, ok, _ = locker.TryLock("stockcalculator:SHOP01:BARCODE01", time.Minute)
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	lock2, ok, _ := locker.TryLock("stockcalculator:SHOP01:BARCODE01", time.Minute)
+	assert.True(t, ok, "expired lock is acquired by other owner")
+
+	// the old owner must not release or extend the new owner lock
+	assert.Equal(t, ErrLockNotOwned, lock1.Refresh(time.Minute))
+	assert.Equal(t, ErrLockNotOwned, lock1.Unlock())
+	assert.Nil(t, lock2.Unlock())
+}
+
+func TestLockerLockWait(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	lock1, err := locker.Lock(context.Background(), "journal:docref:SHOP01", time.Minute)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = locker.Lock(ctx, "journal:docref:SHOP01", time.Minute)
+	assert.Equal(t, ErrLockNotAcquired, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock1.Unlock()
+	}()
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	lock2, err := locker.Lock(ctx2, "journal:docref:SHOP01", time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, lock2.Unlock())
+}
+
+func TestWithLockSerialize(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	running := 0
+	maxRunning := 0
+	done := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		go func() {
+			WithLock(context.Background(), locker, "saleinvoice:docno:SHOP01:INV", time.Second, func() error {
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				time.Sleep(5 * time.Millisecond)
+				running--
+				return nil
+			})
+			done <- struct{}{}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		<-done
+	}
+	assert.Equal(t, 1, maxRunning)
+}
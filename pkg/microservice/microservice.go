@@ -20,7 +20,6 @@ import (
 	"smlaicloudplatform/pkg/microservice/models"
 	msValidator "smlaicloudplatform/pkg/validator"
 
-	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo-contrib/jaegertracing"
 	"github.com/labstack/echo-contrib/prometheus"
@@ -65,12 +64,22 @@ type Microservice struct {
 	prods                     map[string]IProducer
 	prodMutex                 sync.Mutex
 	websocketPool             *WebsocketPool
+	websocketHubs             map[string]*WebsocketHub
+	websocketHubsMutex        sync.Mutex
 	pathPrefix                string
 	config                    config.IConfig
 	jaegerCloser              io.Closer
 	Logger                    logger.ILogger
 	Mode                      string
 	middlewareManager         middlewares.IMiddlewareManager
+	consumerRetryPolicy       ConsumerRetryPolicy
+	workerPools               []*KeyedWorkerPool
+	workerPoolsMutex          sync.Mutex
+	scheduler                 *Scheduler
+	schedulerMutex            sync.Mutex
+	permissionChecker         IPermissionChecker
+	routePermissions          map[string]struct{}
+	permissionMutex           sync.RWMutex
 }
 
 type ServiceHandleFunc func(context IContext) error
@@ -86,6 +95,12 @@ func NewMicroservice(config config.IConfig) (*Microservice, error) {
 	// e.Validator = &msValidator.CustomValidator{Validator: validator.New()}
 	e.Validator = msValidator.NewCustomValidator()
 
+	ipExtractor, err := NewIPExtractor(config.HttpConfig().TrustedProxies())
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
+
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
@@ -115,13 +130,14 @@ func NewMicroservice(config config.IConfig) (*Microservice, error) {
 		Logger:               logger,
 		Mode:                 os.Getenv("MODE"),
 		websocketPool:        &websocketPool,
+		consumerRetryPolicy:  NewConsumerRetryPolicy(config.ConsumerRetryConfig()),
 	}
 
 	// Init logger
 	m.middlewareManager = middlewares.NewMiddlewareManager(logger, config, m.getHttpMetricsCb())
 
 	m.Logger.Info("Initial Microservice.")
-	err := m.CheckReadyToStart()
+	err = m.CheckReadyToStart()
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +232,8 @@ func (ms *Microservice) Start() error {
 
 	}
 
+	ms.startScheduler()
+
 	// There are 2 ways to exit from Microservices
 	// 1. The SigTerm can be send from outside program such as from k8s
 	// 2. Send true to ms.exitChannel
@@ -259,6 +277,13 @@ func (ms *Microservice) Stop() {
 // Cleanup clean resources up from every registered services before exit
 func (ms *Microservice) Cleanup() error {
 	ms.Logger.Info("Stop Service Cleanup System.")
+
+	// stop firing schedules and wait for running handlers
+	ms.stopScheduler()
+
+	// finish queued consumer tasks while producers and persisters are still open
+	ms.closeWorkerPools()
+
 	if ms.prods != nil {
 		for idx := range ms.prods {
 			ms.prods[idx].Close()
@@ -304,6 +329,10 @@ func (ms *Microservice) Persister(cfg config.IPersisterConfig) IPersister {
 		ms.persisters[cfg.Host()] = pst
 		ms.persistersMutex.Unlock()
 	}
+
+	if mode := ms.tenantIsolationMode(); mode != TenantIsolationOff {
+		return NewTenantPersister(pst, mode)
+	}
 	return pst
 }
 
@@ -315,9 +344,26 @@ func (ms *Microservice) MongoPersister(cfg config.IPersisterMongoConfig) IPersis
 		ms.mongoPersisters[cfg.MongodbURI()] = pst
 		ms.persistersMongoMutex.Unlock()
 	}
+
+	if mode := ms.tenantIsolationMode(); mode != TenantIsolationOff {
+		return NewTenantPersisterMongo(pst, mode)
+	}
 	return pst
 }
 
+// tenantIsolationMode return mode of tenant persisters, it is off when the service has no config
+func (ms *Microservice) tenantIsolationMode() string {
+	if ms.config == nil {
+		return TenantIsolationOff
+	}
+
+	switch mode := ms.config.TenantConfig().IsolationMode(); mode {
+	case TenantIsolationReport, TenantIsolationEnforce:
+		return mode
+	}
+	return TenantIsolationOff
+}
+
 func (ms *Microservice) ClickHousePersister(cfg config.IPersisterClickHouseConfig) IPersisterClickHouse {
 
 	indexCfg := strings.Join(cfg.ServerAddress(), "_")
@@ -380,11 +426,15 @@ func (ms *Microservice) Cacher(cfg config.ICacherConfig) ICacher {
 }
 
 func (ms *Microservice) Producer(cfg config.IMQConfig) IProducer {
-	prod, ok := ms.prods[cfg.URI()]
+	return ms.producerByServers(cfg.URI())
+}
+
+func (ms *Microservice) producerByServers(servers string) IProducer {
+	prod, ok := ms.prods[servers]
 	if !ok {
-		prod = NewProducer(cfg.URI(), ms.Logger)
+		prod = NewEventProducer(ms.mqDriverByServers(servers).NewProducer())
 		ms.prodMutex.Lock()
-		ms.prods[cfg.URI()] = prod
+		ms.prods[servers] = prod
 		ms.prodMutex.Unlock()
 	}
 	return prod
@@ -448,105 +498,6 @@ func (ms *Microservice) HttpUseCors() {
 	}))
 }
 
-// newKafkaConsumer create new Kafka consumer
-func (ms *Microservice) newKafkaConsumer(servers string, groupID string) (*kafka.Consumer, error) {
-	// Configurations
-	// https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
-	config := &kafka.ConfigMap{
-
-		// Alias for metadata.broker.list: Initial list of brokers as a CSV list of broker host or host:port.
-		// The application may also use rd_kafka_brokers_add() to add brokers during runtime.
-		"bootstrap.servers": servers,
-
-		// Client group id string. All clients sharing the same group.id belong to the same group.
-		"group.id": groupID,
-
-		// Action to take when there is no initial offset in offset store or the desired offset is out of range:
-		// 'smallest','earliest' - automatically reset the offset to the smallest offset,
-		// 'largest','latest' - automatically reset the offset to the largest offset,
-		// 'error' - trigger an error which is retrieved by consuming messages and checking 'message->err'.
-		// 'beginning'
-		"auto.offset.reset": "earliest",
-
-		// Protocol used to communicate with brokers.
-		// plaintext, ssl, sasl_plaintext, sasl_ssl
-		"security.protocol": "plaintext",
-
-		// Automatically and periodically commit offsets in the background.
-		// Note: setting this to false does not prevent the consumer from fetching previously committed start offsets.
-		// To circumvent this behaviour set specific start offsets per partition in the call to assign().
-		"enable.auto.commit": true,
-
-		// The frequency in milliseconds that the consumer offsets are committed (written) to offset storage. (0 = disable).
-		// default = 5000ms (5s)
-		// 5s is too large, it might cause double process message easily, so we reduce this to 200ms (if we turn on enable.auto.commit)
-		"auto.commit.interval.ms": 500,
-
-		// Automatically store offset of last message provided to application.
-		// The offset store is an in-memory store of the next offset to (auto-)commit for each partition
-		// and cs.Commit() <- offset-less commit
-		"enable.auto.offset.store": true,
-
-		// Enable TCP keep-alives (SO_KEEPALIVE) on broker sockets
-		"socket.keepalive.enable": true,
-	}
-
-	kc, err := kafka.NewConsumer(config)
-	if err != nil {
-		return nil, err
-	}
-	return kc, err
-}
-
-func (ms *Microservice) newKafkaComsuperStartFromBeginning(servers string) (*kafka.Consumer, error) {
-	// Configurations
-	// https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
-	config := &kafka.ConfigMap{
-
-		// Alias for metadata.broker.list: Initial list of brokers as a CSV list of broker host or host:port.
-		// The application may also use rd_kafka_brokers_add() to add brokers during runtime.
-		"bootstrap.servers": servers,
-
-		// Client group id string. All clients sharing the same group.id belong to the same group.
-		//"group.id": groupID,
-
-		// Action to take when there is no initial offset in offset store or the desired offset is out of range:
-		// 'smallest','earliest' - automatically reset the offset to the smallest offset,
-		// 'largest','latest' - automatically reset the offset to the largest offset,
-		// 'error' - trigger an error which is retrieved by consuming messages and checking 'message->err'.
-		// 'beginning'
-		"auto.offset.reset": "beginning",
-
-		// Protocol used to communicate with brokers.
-		// plaintext, ssl, sasl_plaintext, sasl_ssl
-		"security.protocol": "plaintext",
-
-		// Automatically and periodically commit offsets in the background.
-		// Note: setting this to false does not prevent the consumer from fetching previously committed start offsets.
-		// To circumvent this behaviour set specific start offsets per partition in the call to assign().
-		"enable.auto.commit": true,
-
-		// The frequency in milliseconds that the consumer offsets are committed (written) to offset storage. (0 = disable).
-		// default = 5000ms (5s)
-		// 5s is too large, it might cause double process message easily, so we reduce this to 200ms (if we turn on enable.auto.commit)
-		"auto.commit.interval.ms": 500,
-
-		// Automatically store offset of last message provided to application.
-		// The offset store is an in-memory store of the next offset to (auto-)commit for each partition
-		// and cs.Commit() <- offset-less commit
-		//"enable.auto.offset.store": true,
-
-		// Enable TCP keep-alives (SO_KEEPALIVE) on broker sockets
-		"socket.keepalive.enable": true,
-	}
-
-	kc, err := kafka.NewConsumer(config)
-	if err != nil {
-		return nil, err
-	}
-	return kc, err
-}
-
 func (ms *Microservice) Echo() *echo.Echo {
 	return ms.echo
 }
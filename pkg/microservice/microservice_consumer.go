@@ -1,26 +1,40 @@
 package microservice
 
 import (
+	"errors"
 	"time"
-
-	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
 type IMicroserviceConsumer interface {
 	RegisterConsumer(*Microservice)
 }
 
-func (ms *Microservice) consumeSingle(servers string, topic string, groupID string, readTimeout time.Duration, h ServiceHandleFunc) {
+// ConsumerMessage is the message read from message queue
+type ConsumerMessage struct {
+	Topic     string
+	Key       string
+	Partition int32
+	Offset    int64
+	Value     string
+}
+
+func (ms *Microservice) consumeSingle(servers string, topic string, groupID string, readTimeout time.Duration, policy ConsumerRetryPolicy, h ServiceHandleFunc) {
 	ms.Logger.Debugf("Consumer Kafka on topic: %s ", topic)
-	c, err := ms.newKafkaConsumer(servers, groupID)
+	c, err := ms.mqDriverByServers(servers).NewConsumer(groupID)
 	if err != nil {
+		ms.Logger.Errorf("Cannot create consumer on topic %s: %v", topic, err)
 		return
 	}
 
 	defer c.Close()
 
-	c.Subscribe(topic, nil)
+	if policy.DeadLetter {
+		ms.createDeadLetterTopic(servers, topic)
+	}
 
+	c.Subscribe(topic)
+
+	readErrors := 0
 	for {
 		if readTimeout <= 0 {
 			// readtimeout -1 indicates no timeout
@@ -29,42 +43,68 @@ func (ms *Microservice) consumeSingle(servers string, topic string, groupID stri
 
 		msg, err := c.ReadMessage(readTimeout)
 		if err != nil {
-			kafkaErr, ok := err.(kafka.Error)
-			if ok {
-				if kafkaErr.Code() == kafka.ErrTimedOut {
-					if readTimeout == -1 {
-						// No timeout just continue to read message again
-						continue
-					}
-				}
+			if err == ErrMQReadTimeout {
+				// No message before timeout just continue to read message again
+				continue
+			}
+
+			readErrors++
+			if ms.consumerReadFailed(topic, err, policy.Backoff(readErrors)) {
+				ms.Stop()
+				return
 			}
-			ms.Log("Consumer", err.Error())
-			ms.Stop()
-			return
+			continue
 		}
+		readErrors = 0
 
-		// Execute Handler
-		h(NewConsumerContext(ms, string(msg.Value)))
+		// Execute Handler with retry, failed message is sent to dead letter topic
+		err = ms.processConsumerMessage(servers, groupID, policy, msg, h)
+		if err != nil {
+			// message is neither processed nor sent to dead letter topic, read it again after backoff
+			ms.Logger.Errorf("Consumer topic %s offset %v is not committed: %v", msg.Topic, msg.Offset, err)
+			consumerRetrySleep(policy.Backoff(policy.MaxRetries + 1))
+			seekErr := c.Seek(msg)
+			if seekErr != nil {
+				ms.Logger.Errorf("Consumer cannot seek topic %s offset %v: %v", msg.Topic, msg.Offset, seekErr)
+			}
+			continue
+		}
+
+		// Commit offset only after message is processed
+		err = c.CommitMessage(msg)
+		if err != nil {
+			ms.Logger.Errorf("Consumer cannot commit topic %s offset %v: %v", msg.Topic, msg.Offset, err)
+		}
 	}
 }
 
-// Consume register service endpoint for Consumer service
+// Consume register service endpoint for Consumer service with default retry policy
 func (ms *Microservice) Consume(servers string, topic string, groupID string, readTimeout time.Duration, h ServiceHandleFunc) error {
-	go ms.consumeSingle(servers, topic, groupID, readTimeout, h)
+	return ms.ConsumeWithRetry(servers, topic, groupID, readTimeout, ms.consumerRetryPolicy, h)
+}
+
+// ConsumeWithRetry register service endpoint for Consumer service with the retry policy
+func (ms *Microservice) ConsumeWithRetry(servers string, topic string, groupID string, readTimeout time.Duration, policy ConsumerRetryPolicy, h ServiceHandleFunc) error {
+	go ms.consumeSingle(servers, topic, groupID, readTimeout, policy, h)
 	return nil
 }
 
 func (ms *Microservice) consumeWithoutGroupFromBeginig(servers string, topic string, readTimeout time.Duration, h ServiceHandleFunc) {
 	ms.Logger.Debugf("Consumer Kafka on topic: %s ", topic)
-	c, err := ms.newKafkaComsuperStartFromBeginning(servers)
+	c, err := ms.mqDriverByServers(servers).NewConsumer("")
 	if err != nil {
+		ms.Logger.Errorf("Cannot create consumer on topic %s: %v", topic, err)
 		return
 	}
 
 	defer c.Close()
 
-	c.Subscribe(topic, nil)
+	c.Subscribe(topic)
+
+	policy := ms.consumerRetryPolicy
+	policy.DeadLetter = false
 
+	readErrors := 0
 	for {
 		if readTimeout <= 0 {
 			// readtimeout -1 indicates no timeout
@@ -73,22 +113,31 @@ func (ms *Microservice) consumeWithoutGroupFromBeginig(servers string, topic str
 
 		msg, err := c.ReadMessage(readTimeout)
 		if err != nil {
-			kafkaErr, ok := err.(kafka.Error)
-			if ok {
-				if kafkaErr.Code() == kafka.ErrTimedOut {
-					if readTimeout == -1 {
-						// No timeout just continue to read message again
-						continue
-					}
-				}
+			if err == ErrMQReadTimeout {
+				// No message before timeout just continue to read message again
+				continue
 			}
-			ms.Log("Consumer", err.Error())
-			ms.Stop()
-			return
+
+			readErrors++
+			if ms.consumerReadFailed(topic, err, policy.Backoff(readErrors)) {
+				ms.Stop()
+				return
+			}
+			continue
 		}
+		readErrors = 0
 
-		// Execute Handler
-		h(NewConsumerContext(ms, string(msg.Value)))
+		// Execute Handler with retry, replaying from beginning never send message to dead letter topic
+		err = ms.processConsumerMessage(servers, "", policy, msg, h)
+		if err != nil {
+			// message is not processed, read it again after backoff
+			ms.Logger.Errorf("Consumer topic %s offset %v is not processed: %v", msg.Topic, msg.Offset, err)
+			consumerRetrySleep(policy.Backoff(policy.MaxRetries + 1))
+			seekErr := c.Seek(msg)
+			if seekErr != nil {
+				ms.Logger.Errorf("Consumer cannot seek topic %s offset %v: %v", msg.Topic, msg.Offset, seekErr)
+			}
+		}
 	}
 }
 
@@ -97,6 +146,19 @@ func (ms *Microservice) ConsumeFromBegining(servers string, topic string, readTi
 	return nil
 }
 
+// consumerReadFailed log error of ReadMessage and return true when the consumer must stop on fatal error,
+// other errors, e.g. broker is not available, are transient so the consumer read again after backoff
+func (ms *Microservice) consumerReadFailed(topic string, err error, backoff time.Duration) bool {
+	if errors.Is(err, ErrMQFatal) {
+		ms.Logger.Errorf("Consumer topic %s stop on fatal error: %v", topic, err)
+		return true
+	}
+
+	ms.Logger.Errorf("Consumer topic %s read error: %v", topic, err)
+	consumerRetrySleep(backoff)
+	return false
+}
+
 func (ms *Microservice) RegisterConsumer(consumer IMicroserviceConsumer) {
 	defer ms.consumerRecover()
 	consumer.RegisterConsumer(ms)
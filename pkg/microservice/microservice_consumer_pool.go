@@ -0,0 +1,191 @@
+package microservice
+
+import (
+	"sync"
+	"time"
+)
+
+// ConsumerMessageKeyFunc return ordering key of the message, messages with the same key are processed in order
+type ConsumerMessageKeyFunc func(msg ConsumerMessage) string
+
+// ConsumerMessageKey use key of the message queue message as ordering key
+func ConsumerMessageKey(msg ConsumerMessage) string {
+	return msg.Key
+}
+
+// NewKeyedWorkerPool return worker pool which is drained before producers and persisters are closed by Cleanup
+func (ms *Microservice) NewKeyedWorkerPool(workers int, queueSize int) *KeyedWorkerPool {
+	pool := NewKeyedWorkerPool(workers, queueSize)
+
+	ms.workerPoolsMutex.Lock()
+	ms.workerPools = append(ms.workerPools, pool)
+	ms.workerPoolsMutex.Unlock()
+
+	return pool
+}
+
+func (ms *Microservice) closeWorkerPools() {
+	ms.workerPoolsMutex.Lock()
+	pools := ms.workerPools
+	ms.workerPools = nil
+	ms.workerPoolsMutex.Unlock()
+
+	for _, pool := range pools {
+		pool.Close()
+	}
+}
+
+// ConsumeWithWorkerPool register consumer which run handler by the pool, messages of different keys are processed in parallel
+// and offset is committed only when every message before it in the partition is processed
+func (ms *Microservice) ConsumeWithWorkerPool(servers string, topic string, groupID string, readTimeout time.Duration, pool *KeyedWorkerPool, key ConsumerMessageKeyFunc, h ServiceHandleFunc) error {
+	if key == nil {
+		key = ConsumerMessageKey
+	}
+
+	go ms.consumeWithWorkerPool(servers, topic, groupID, readTimeout, ms.consumerRetryPolicy, pool, key, h)
+	return nil
+}
+
+func (ms *Microservice) consumeWithWorkerPool(servers string, topic string, groupID string, readTimeout time.Duration, policy ConsumerRetryPolicy, pool *KeyedWorkerPool, key ConsumerMessageKeyFunc, h ServiceHandleFunc) {
+	ms.Logger.Debugf("Consumer Kafka on topic: %s with %d workers", topic, pool.Workers())
+	c, err := ms.mqDriverByServers(servers).NewConsumer(groupID)
+	if err != nil {
+		ms.Logger.Errorf("Cannot create consumer on topic %s: %v", topic, err)
+		return
+	}
+
+	defer c.Close()
+
+	if policy.DeadLetter {
+		ms.createDeadLetterTopic(servers, topic)
+	}
+
+	c.Subscribe(topic)
+
+	offsets := newConsumerOffsetTracker()
+
+	readErrors := 0
+	for {
+		if readTimeout <= 0 {
+			// readtimeout -1 indicates no timeout
+			readTimeout = -1
+		}
+
+		msg, err := c.ReadMessage(readTimeout)
+		if err != nil {
+			if err == ErrMQReadTimeout {
+				// No message before timeout just continue to read message again
+				continue
+			}
+
+			readErrors++
+			if ms.consumerReadFailed(topic, err, policy.Backoff(readErrors)) {
+				ms.Stop()
+				return
+			}
+			continue
+		}
+		readErrors = 0
+
+		offsets.add(msg)
+
+		// Submit is blocked while the worker queue is full, so the consumer does not read faster than workers
+		err = pool.Submit(key(msg), func() {
+			for {
+				err := ms.processConsumerMessage(servers, groupID, policy, msg, h)
+				if err == nil {
+					break
+				}
+
+				ms.Logger.Errorf("Consumer topic %s offset %v is not committed: %v", msg.Topic, msg.Offset, err)
+				if pool.Closed() {
+					// message is read again after restart
+					return
+				}
+
+				// retry in the worker so later messages of the same key still wait
+				consumerRetrySleep(policy.Backoff(policy.MaxRetries + 1))
+			}
+
+			offsets.done(msg, func(commit ConsumerMessage) {
+				err := c.CommitMessage(commit)
+				if err != nil {
+					ms.Logger.Errorf("Consumer cannot commit topic %s offset %v: %v", commit.Topic, commit.Offset, err)
+				}
+			})
+		})
+
+		if err != nil {
+			ms.Logger.Warnf("Consumer topic %s stop reading: %v", topic, err)
+			return
+		}
+	}
+}
+
+type consumerPartitionKey struct {
+	topic     string
+	partition int32
+}
+
+// consumerPartitionOffsets is messages of the partition in read order which are not committed yet
+type consumerPartitionOffsets struct {
+	messages []ConsumerMessage
+	done     map[int64]bool
+}
+
+// consumerOffsetTracker find the offset which is safe to commit when messages of a partition are completed out of order
+type consumerOffsetTracker struct {
+	mutex      sync.Mutex
+	partitions map[consumerPartitionKey]*consumerPartitionOffsets
+}
+
+func newConsumerOffsetTracker() *consumerOffsetTracker {
+	return &consumerOffsetTracker{
+		partitions: map[consumerPartitionKey]*consumerPartitionOffsets{},
+	}
+}
+
+func (t *consumerOffsetTracker) add(msg ConsumerMessage) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := consumerPartitionKey{topic: msg.Topic, partition: msg.Partition}
+	p, ok := t.partitions[key]
+
+	// partition is read again from committed offset after rebalance, forget messages of previous assignment
+	if !ok || (len(p.messages) > 0 && msg.Offset <= p.messages[len(p.messages)-1].Offset) {
+		p = &consumerPartitionOffsets{
+			done: map[int64]bool{},
+		}
+		t.partitions[key] = p
+	}
+
+	p.messages = append(p.messages, msg)
+}
+
+// done mark the message as processed and commit the last message which every message before it is processed,
+// commit is called with the lock held so offsets of the partition are committed in order
+func (t *consumerOffsetTracker) done(msg ConsumerMessage, commit func(ConsumerMessage)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	p, ok := t.partitions[consumerPartitionKey{topic: msg.Topic, partition: msg.Partition}]
+	if !ok {
+		return
+	}
+
+	p.done[msg.Offset] = true
+
+	completed := 0
+	for completed < len(p.messages) && p.done[p.messages[completed].Offset] {
+		delete(p.done, p.messages[completed].Offset)
+		completed++
+	}
+
+	if completed == 0 {
+		return
+	}
+
+	commit(p.messages[completed-1])
+	p.messages = p.messages[completed:]
+}
@@ -0,0 +1,188 @@
+package microservice
+
+import (
+	"fmt"
+	"math"
+	"smlaicloudplatform/internal/config"
+	"strings"
+	"time"
+)
+
+// DeadLetterTopicSuffix is appended to the source topic name to get its dead letter topic
+const DeadLetterTopicSuffix = ".dlq"
+
+// consumerRetrySleep is replaced in tests to avoid waiting on backoff
+var consumerRetrySleep = time.Sleep
+
+// ConsumerRetryPolicy control how a failed message is retried before it is sent to the dead letter topic
+type ConsumerRetryPolicy struct {
+	MaxRetries        int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	DeadLetter        bool
+}
+
+// NewConsumerRetryPolicy create retry policy from config
+func NewConsumerRetryPolicy(cfg config.IConsumerRetryConfig) ConsumerRetryPolicy {
+	return ConsumerRetryPolicy{
+		MaxRetries:        cfg.MaxRetries(),
+		InitialBackoff:    cfg.InitialBackoff(),
+		MaxBackoff:        cfg.MaxBackoff(),
+		BackoffMultiplier: cfg.BackoffMultiplier(),
+		DeadLetter:        cfg.DeadLetterEnabled(),
+	}
+}
+
+// Backoff return the wait duration before retry attempt (attempt start from 1)
+func (p ConsumerRetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 || p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := p.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(wait)
+}
+
+// Execute run fn until it success or retries are exhausted, return number of attempts and the last error
+func (p ConsumerRetryPolicy) Execute(fn func() error, onRetry func(attempt int, wait time.Duration, err error)) (int, error) {
+	attempts := 0
+	for {
+		attempts++
+		err := fn()
+		if err == nil {
+			return attempts, nil
+		}
+
+		if attempts > p.MaxRetries {
+			return attempts, err
+		}
+
+		wait := p.Backoff(attempts)
+		if onRetry != nil {
+			onRetry(attempts, wait, err)
+		}
+		consumerRetrySleep(wait)
+	}
+}
+
+// DeadLetterMessage is the message sent to dead letter topic when handler cannot process the original message
+type DeadLetterMessage struct {
+	ID        string    `json:"id" bson:"id"`
+	Topic     string    `json:"topic" bson:"topic"`
+	GroupID   string    `json:"groupid" bson:"groupid"`
+	Key       string    `json:"key" bson:"key"`
+	Partition int32     `json:"partition" bson:"partition"`
+	Offset    int64     `json:"offset" bson:"offset"`
+	Payload   string    `json:"payload" bson:"payload"`
+	Error     string    `json:"error" bson:"error"`
+	Attempts  int       `json:"attempts" bson:"attempts"`
+	FailedAt  time.Time `json:"failedat" bson:"failedat"`
+}
+
+// DeadLetterTopic return dead letter topic name of the topic
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterTopicSuffix
+}
+
+// IsDeadLetterTopic check the topic is a dead letter topic
+func IsDeadLetterTopic(topic string) bool {
+	return strings.HasSuffix(topic, DeadLetterTopicSuffix)
+}
+
+// isTopicPattern check topic is regular expression subscription (start with ^)
+func isTopicPattern(topic string) bool {
+	return strings.HasPrefix(topic, "^")
+}
+
+// SetConsumerRetryPolicy set default retry policy used by Consume
+func (ms *Microservice) SetConsumerRetryPolicy(policy ConsumerRetryPolicy) {
+	ms.consumerRetryPolicy = policy
+}
+
+// ConsumerRetryPolicy return default retry policy used by Consume
+func (ms *Microservice) ConsumerRetryPolicy() ConsumerRetryPolicy {
+	return ms.consumerRetryPolicy
+}
+
+// executeConsumerHandler call handler and convert panic to error so the message can be retried
+func (ms *Microservice) executeConsumerHandler(h ServiceHandleFunc, msg ConsumerMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("consumer handler panic: %v", r)
+		}
+	}()
+
+	ctx, err := NewConsumerContextWithMessage(ms, msg)
+	if err != nil {
+		return err
+	}
+
+	return h(ctx)
+}
+
+// processConsumerMessage execute handler with retry policy and send message to dead letter topic when retries are exhausted,
+// error is returned only when the message is neither processed nor sent to dead letter topic
+func (ms *Microservice) processConsumerMessage(servers string, groupID string, policy ConsumerRetryPolicy, msg ConsumerMessage, h ServiceHandleFunc) error {
+	attempts, err := policy.Execute(
+		func() error {
+			return ms.executeConsumerHandler(h, msg)
+		},
+		func(attempt int, wait time.Duration, err error) {
+			ms.Logger.Warnf("Consumer topic %s attempt %d failed, retry in %v: %v", msg.Topic, attempt, wait, err)
+		},
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	ms.Logger.Errorf("Consumer topic %s failed after %d attempts: %v", msg.Topic, attempts, err)
+
+	if !policy.DeadLetter || IsDeadLetterTopic(msg.Topic) {
+		// dead letter is disabled, message is dropped
+		return nil
+	}
+
+	deadLetter := DeadLetterMessage{
+		ID:        NewUUID(),
+		Topic:     msg.Topic,
+		GroupID:   groupID,
+		Key:       msg.Key,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Payload:   msg.Value,
+		Error:     err.Error(),
+		Attempts:  attempts,
+		FailedAt:  time.Now(),
+	}
+
+	dlqErr := ms.producerByServers(servers).SendMessage(DeadLetterTopic(msg.Topic), msg.Key, deadLetter)
+	if dlqErr != nil {
+		ms.Logger.Errorf("Cannot send message to dead letter topic %s: %v", DeadLetterTopic(msg.Topic), dlqErr)
+		return dlqErr
+	}
+
+	return nil
+}
+
+// createDeadLetterTopic create dead letter topic for the consumed topic
+func (ms *Microservice) createDeadLetterTopic(servers string, topic string) {
+	if isTopicPattern(topic) || IsDeadLetterTopic(topic) {
+		return
+	}
+
+	err := ms.mqDriverByServers(servers).CreateTopic(DeadLetterTopic(topic), 1, 1, time.Hour*24*30) // retain dead letter for 30 days
+	if err != nil {
+		ms.Logger.Errorf("Cannot create dead letter topic %s: %v", DeadLetterTopic(topic), err)
+	}
+}
@@ -0,0 +1,71 @@
+package microservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerRetryPolicyBackoff(t *testing.T) {
+	policy := ConsumerRetryPolicy{
+		MaxRetries:        5,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 2,
+	}
+
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(5))
+}
+
+func TestConsumerRetryPolicyExecute(t *testing.T) {
+	waits := []time.Duration{}
+	consumerRetrySleep = func(d time.Duration) {
+		waits = append(waits, d)
+	}
+	defer func() { consumerRetrySleep = time.Sleep }()
+
+	policy := ConsumerRetryPolicy{
+		MaxRetries:        2,
+		InitialBackoff:    10 * time.Millisecond,
+		BackoffMultiplier: 3,
+	}
+
+	calls := 0
+	attempts, err := policy.Execute(func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("postgres down")
+		}
+		return nil
+	}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond}, waits)
+
+	waits = []time.Duration{}
+	retried := []int{}
+	attempts, err = policy.Execute(func() error {
+		return errors.New("invalid payload")
+	}, func(attempt int, wait time.Duration, err error) {
+		retried = append(retried, attempt)
+	})
+
+	assert.EqualError(t, err, "invalid payload")
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []int{1, 2}, retried)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 30 * time.Millisecond}, waits)
+}
+
+func TestDeadLetterTopic(t *testing.T) {
+	assert.Equal(t, "when-sale-invoice-created.dlq", DeadLetterTopic("when-sale-invoice-created"))
+	assert.True(t, IsDeadLetterTopic("when-sale-invoice-created.dlq"))
+	assert.False(t, IsDeadLetterTopic("when-sale-invoice-created"))
+}
@@ -2,6 +2,7 @@ package microservice
 
 import (
 	"context"
+	"net/http"
 	"strings"
 	"time"
 
@@ -12,13 +13,18 @@ type IMicroserviceHTTP interface {
 	RegisterHttp()
 }
 
+// PathPrefix return prefix which is added to path of every route registered by the microservice
+func (ms *Microservice) PathPrefix() string {
+	return ms.pathPrefix
+}
+
 // GET register service endpoint for HTTP GET
 func (ms *Microservice) GET(path string, h ServiceHandleFunc, m ...echo.MiddlewareFunc) {
 	fullPath := ms.pathPrefix + strings.TrimSpace(path)
 	ms.Logger.Debugf("Register HTTP Handler GET \"%s\".", path)
-	ms.echo.GET(fullPath, func(c echo.Context) error {
+	ms.echo.GET(fullPath, ms.withRoutePermission(http.MethodGet, path, func(c echo.Context) error {
 		return h(NewHTTPContext(ms, c))
-	}, m...)
+	}), m...)
 }
 
 // POST register service endpoint for HTTP POST
@@ -26,36 +32,36 @@ func (ms *Microservice) POST(path string, h ServiceHandleFunc, m ...echo.Middlew
 
 	fullPath := ms.pathPrefix + strings.TrimSpace(path)
 	ms.Logger.Debugf("Register HTTP Handler POST \"%s\".", fullPath)
-	ms.echo.POST(fullPath, func(c echo.Context) error {
+	ms.echo.POST(fullPath, ms.withRoutePermission(http.MethodPost, path, func(c echo.Context) error {
 		return h(NewHTTPContext(ms, c))
-	}, m...)
+	}), m...)
 }
 
 // PUT register service endpoint for HTTP PUT
 func (ms *Microservice) PUT(path string, h ServiceHandleFunc, m ...echo.MiddlewareFunc) {
 	fullPath := ms.pathPrefix + strings.TrimSpace(path)
 	ms.Logger.Debugf("Register HTTP Handler PUT \"%s\".", fullPath)
-	ms.echo.PUT(fullPath, func(c echo.Context) error {
+	ms.echo.PUT(fullPath, ms.withRoutePermission(http.MethodPut, path, func(c echo.Context) error {
 		return h(NewHTTPContext(ms, c))
-	}, m...)
+	}), m...)
 }
 
 // PATCH register service endpoint for HTTP PATCH
 func (ms *Microservice) PATCH(path string, h ServiceHandleFunc, m ...echo.MiddlewareFunc) {
 	fullPath := ms.pathPrefix + strings.TrimSpace(path)
 	ms.Logger.Debugf("Register HTTP Handler PATCH \"%s\".", fullPath)
-	ms.echo.PATCH(fullPath, func(c echo.Context) error {
+	ms.echo.PATCH(fullPath, ms.withRoutePermission(http.MethodPatch, path, func(c echo.Context) error {
 		return h(NewHTTPContext(ms, c))
-	}, m...)
+	}), m...)
 }
 
 // DELETE register service endpoint for HTTP DELETE
 func (ms *Microservice) DELETE(path string, h ServiceHandleFunc, m ...echo.MiddlewareFunc) {
 	fullPath := ms.pathPrefix + strings.TrimSpace(path)
 	ms.Logger.Debugf("Register HTTP Handler DELETE \"%s\".", fullPath)
-	ms.echo.DELETE(fullPath, func(c echo.Context) error {
+	ms.echo.DELETE(fullPath, ms.withRoutePermission(http.MethodDelete, path, func(c echo.Context) error {
 		return h(NewHTTPContext(ms, c))
-	}, m...)
+	}), m...)
 }
 
 // startHTTP will start HTTP service, this function will block thread
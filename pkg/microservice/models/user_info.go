@@ -5,4 +5,7 @@ type UserInfo struct {
 	Name     string `json:"name"`
 	ShopID   string `json:"shopid" `
 	Role     uint8  `json:"role"`
+	// ApiKeyID is set when request is authenticated by managed api key, Scopes limit permissions of the key
+	ApiKeyID string   `json:"apikeyid,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UserSession is one login of the user, tokens which are refreshed from the login stay in the same session
+type UserSession struct {
+	SessionID  string    `json:"sessionid"`
+	Username   string    `json:"username"`
+	DeviceName string    `json:"devicename"`
+	IP         string    `json:"ip"`
+	AuthType   string    `json:"authtype"`
+	ShopID     string    `json:"shopid"`
+	CreatedAt  time.Time `json:"createdat"`
+	LastSeenAt time.Time `json:"lastseenat"`
+}
@@ -0,0 +1,57 @@
+package microservice
+
+import (
+	"errors"
+	"smlaicloudplatform/internal/logger"
+	"time"
+)
+
+const (
+	// MQDriverKafka send and consume message through Kafka cluster
+	MQDriverKafka = "kafka"
+	// MQDriverMemory send and consume message in the same process
+	MQDriverMemory = "memory"
+)
+
+// ErrMQReadTimeout is returned by IMQConsumer.ReadMessage when no message is available before timeout
+var ErrMQReadTimeout = errors.New("mq read timeout")
+
+// ErrMQFatal is wrapped by IMQConsumer.ReadMessage when the consumer can not read anymore,
+// other read errors are transient and the message is read again
+var ErrMQFatal = errors.New("mq fatal error")
+
+// IMQDriver is message queue backend used by MQ, producer and consumer
+type IMQDriver interface {
+	// CreateTopic create the topic, existing topic is not an error
+	CreateTopic(topic string, partitions int, replications int, retentionPeriod time.Duration) error
+	// NewProducer return producer which send message to the backend
+	NewProducer() IProducer
+	// NewConsumer return consumer of the group, empty group read every message from the beginning without commit
+	NewConsumer(groupID string) (IMQConsumer, error)
+}
+
+// IMQConsumer read message of subscribed topic, offset is committed manually
+type IMQConsumer interface {
+	// Subscribe the topic, topic start with ^ is regular expression
+	Subscribe(topic string) error
+	// ReadMessage wait for next message, timeout -1 wait forever
+	ReadMessage(timeout time.Duration) (ConsumerMessage, error)
+	// CommitMessage mark the message and every message before it in the partition as processed
+	CommitMessage(msg ConsumerMessage) error
+	// Seek move read position of the message partition back to the message
+	Seek(msg ConsumerMessage) error
+	Close() error
+}
+
+// NewMQDriver return message queue backend by driver name, unknown driver fallback to kafka
+func NewMQDriver(driver string, servers string, logger logger.ILogger) IMQDriver {
+	if driver == MQDriverMemory {
+		return NewMemoryMQDriver(GetMemoryBroker(servers))
+	}
+
+	return NewKafkaMQDriver(servers, logger)
+}
+
+func (ms *Microservice) mqDriverByServers(servers string) IMQDriver {
+	return NewMQDriver(ms.config.MQConfig().Driver(), servers, ms.Logger)
+}
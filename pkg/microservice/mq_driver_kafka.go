@@ -0,0 +1,273 @@
+package microservice
+
+import (
+	"context"
+	"fmt"
+	"smlaicloudplatform/internal/logger"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// KafkaMQDriver implement IMQDriver with Kafka cluster
+type KafkaMQDriver struct {
+	logger  logger.ILogger
+	servers string
+}
+
+// NewKafkaMQDriver return Kafka driver of the servers
+func NewKafkaMQDriver(servers string, logger logger.ILogger) *KafkaMQDriver {
+	return &KafkaMQDriver{
+		logger:  logger,
+		servers: servers,
+	}
+}
+
+func (d *KafkaMQDriver) getAdminClient() (*kafka.AdminClient, error) {
+	admin, err := kafka.NewAdminClient(&kafka.ConfigMap{"bootstrap.servers": d.servers})
+	if err != nil {
+		d.logger.Error("Failed to Connect to Kafka", err)
+		return nil, err
+	}
+	return admin, nil
+}
+
+// CreateTopic create Kafka topic with retention period, default retention is 7 days
+func (d *KafkaMQDriver) CreateTopic(topic string, partitions int, replications int, retentionPeriod time.Duration) error {
+	if retentionPeriod <= 0 {
+		retentionPeriod = 7 * (time.Hour * 24) // default = 7 days (Message will keep 7 days)
+	}
+
+	admin, err := d.getAdminClient()
+	if err != nil {
+		return err
+	}
+
+	defer admin.Close()
+
+	// Operation timeout for create topic = 5 minutes
+	timeout, err := time.ParseDuration("5m")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	retentionPeriodMillisec := fmt.Sprintf("%d", int64(retentionPeriod/time.Millisecond))
+
+	var results []kafka.TopicResult
+	if timeout > 0 {
+		results, err = admin.CreateTopics(
+			ctx,
+			[]kafka.TopicSpecification{{
+				Topic:             topic,
+				NumPartitions:     partitions,
+				ReplicationFactor: replications,
+				Config: map[string]string{
+					"retention.ms": retentionPeriodMillisec,
+				}}},
+			kafka.SetAdminOperationTimeout(timeout))
+	} else {
+		results, err = admin.CreateTopics(
+			ctx,
+			[]kafka.TopicSpecification{{
+				Topic:             topic,
+				NumPartitions:     partitions,
+				ReplicationFactor: replications,
+				Config: map[string]string{
+					"retention.ms": retentionPeriodMillisec,
+				}}})
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		d.logger.Debugf("Create Topic \"%s\" Result: %s", topic, result.String())
+	}
+
+	return nil
+}
+
+// NewProducer return Kafka producer
+func (d *KafkaMQDriver) NewProducer() IProducer {
+	return NewProducer(d.servers, d.logger)
+}
+
+// NewConsumer return Kafka consumer of the group, empty group start from beginning
+func (d *KafkaMQDriver) NewConsumer(groupID string) (IMQConsumer, error) {
+	var c *kafka.Consumer
+	var err error
+	if groupID == "" {
+		c, err = newKafkaConsumerStartFromBeginning(d.servers)
+	} else {
+		c, err = newKafkaConsumer(d.servers, groupID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaMQConsumer{
+		consumer: c,
+	}, nil
+}
+
+// kafkaMQConsumer implement IMQConsumer with confluent kafka consumer
+type kafkaMQConsumer struct {
+	consumer *kafka.Consumer
+}
+
+func (c *kafkaMQConsumer) Subscribe(topic string) error {
+	return c.consumer.Subscribe(topic, nil)
+}
+
+func (c *kafkaMQConsumer) ReadMessage(timeout time.Duration) (ConsumerMessage, error) {
+	msg, err := c.consumer.ReadMessage(timeout)
+	if err != nil {
+		kafkaErr, ok := err.(kafka.Error)
+		if ok && kafkaErr.Code() == kafka.ErrTimedOut {
+			return ConsumerMessage{}, ErrMQReadTimeout
+		}
+		if ok && kafkaErr.IsFatal() {
+			return ConsumerMessage{}, fmt.Errorf("%w: %v", ErrMQFatal, err)
+		}
+		return ConsumerMessage{}, err
+	}
+
+	return newConsumerMessage(msg), nil
+}
+
+func (c *kafkaMQConsumer) CommitMessage(msg ConsumerMessage) error {
+	_, err := c.consumer.CommitOffsets([]kafka.TopicPartition{
+		kafkaTopicPartition(msg, msg.Offset+1),
+	})
+	return err
+}
+
+func (c *kafkaMQConsumer) Seek(msg ConsumerMessage) error {
+	return c.consumer.Seek(kafkaTopicPartition(msg, msg.Offset), 1000)
+}
+
+func (c *kafkaMQConsumer) Close() error {
+	return c.consumer.Close()
+}
+
+func newConsumerMessage(msg *kafka.Message) ConsumerMessage {
+	topic := ""
+	if msg.TopicPartition.Topic != nil {
+		topic = *msg.TopicPartition.Topic
+	}
+
+	return ConsumerMessage{
+		Topic:     topic,
+		Key:       string(msg.Key),
+		Partition: msg.TopicPartition.Partition,
+		Offset:    int64(msg.TopicPartition.Offset),
+		Value:     string(msg.Value),
+	}
+}
+
+func kafkaTopicPartition(msg ConsumerMessage, offset int64) kafka.TopicPartition {
+	topic := msg.Topic
+	return kafka.TopicPartition{
+		Topic:     &topic,
+		Partition: msg.Partition,
+		Offset:    kafka.Offset(offset),
+	}
+}
+
+// newKafkaConsumer create new Kafka consumer
+func newKafkaConsumer(servers string, groupID string) (*kafka.Consumer, error) {
+	// Configurations
+	// https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
+	config := &kafka.ConfigMap{
+
+		// Alias for metadata.broker.list: Initial list of brokers as a CSV list of broker host or host:port.
+		// The application may also use rd_kafka_brokers_add() to add brokers during runtime.
+		"bootstrap.servers": servers,
+
+		// Client group id string. All clients sharing the same group.id belong to the same group.
+		"group.id": groupID,
+
+		// Action to take when there is no initial offset in offset store or the desired offset is out of range:
+		// 'smallest','earliest' - automatically reset the offset to the smallest offset,
+		// 'largest','latest' - automatically reset the offset to the largest offset,
+		// 'error' - trigger an error which is retrieved by consuming messages and checking 'message->err'.
+		// 'beginning'
+		"auto.offset.reset": "earliest",
+
+		// Protocol used to communicate with brokers.
+		// plaintext, ssl, sasl_plaintext, sasl_ssl
+		"security.protocol": "plaintext",
+
+		// Offsets are committed by consumeSingle after the handler succeeds (or the message is sent to dead letter topic),
+		// so a crash between ReadMessage and the handler finishing will redeliver the message (at-least-once).
+		// Note: setting this to false does not prevent the consumer from fetching previously committed start offsets.
+		// To circumvent this behaviour set specific start offsets per partition in the call to assign().
+		"enable.auto.commit": false,
+
+		// Do not store offset of message provided to application, offset is committed explicitly by CommitMessage
+		"enable.auto.offset.store": false,
+
+		// Enable TCP keep-alives (SO_KEEPALIVE) on broker sockets
+		"socket.keepalive.enable": true,
+	}
+
+	kc, err := kafka.NewConsumer(config)
+	if err != nil {
+		return nil, err
+	}
+	return kc, err
+}
+
+// newKafkaConsumerStartFromBeginning create new Kafka consumer without group which read from the first offset
+func newKafkaConsumerStartFromBeginning(servers string) (*kafka.Consumer, error) {
+	// Configurations
+	// https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
+	config := &kafka.ConfigMap{
+
+		// Alias for metadata.broker.list: Initial list of brokers as a CSV list of broker host or host:port.
+		// The application may also use rd_kafka_brokers_add() to add brokers during runtime.
+		"bootstrap.servers": servers,
+
+		// Client group id string. All clients sharing the same group.id belong to the same group.
+		//"group.id": groupID,
+
+		// Action to take when there is no initial offset in offset store or the desired offset is out of range:
+		// 'smallest','earliest' - automatically reset the offset to the smallest offset,
+		// 'largest','latest' - automatically reset the offset to the largest offset,
+		// 'error' - trigger an error which is retrieved by consuming messages and checking 'message->err'.
+		// 'beginning'
+		"auto.offset.reset": "beginning",
+
+		// Protocol used to communicate with brokers.
+		// plaintext, ssl, sasl_plaintext, sasl_ssl
+		"security.protocol": "plaintext",
+
+		// Automatically and periodically commit offsets in the background.
+		// Note: setting this to false does not prevent the consumer from fetching previously committed start offsets.
+		// To circumvent this behaviour set specific start offsets per partition in the call to assign().
+		"enable.auto.commit": true,
+
+		// The frequency in milliseconds that the consumer offsets are committed (written) to offset storage. (0 = disable).
+		// default = 5000ms (5s)
+		// 5s is too large, it might cause double process message easily, so we reduce this to 200ms (if we turn on enable.auto.commit)
+		"auto.commit.interval.ms": 500,
+
+		// Automatically store offset of last message provided to application.
+		// The offset store is an in-memory store of the next offset to (auto-)commit for each partition
+		// and cs.Commit() <- offset-less commit
+		//"enable.auto.offset.store": true,
+
+		// Enable TCP keep-alives (SO_KEEPALIVE) on broker sockets
+		"socket.keepalive.enable": true,
+	}
+
+	kc, err := kafka.NewConsumer(config)
+	if err != nil {
+		return nil, err
+	}
+	return kc, err
+}
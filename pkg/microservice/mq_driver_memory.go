@@ -0,0 +1,480 @@
+package microservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryTopicDefaultPartitions is number of partitions of topic created on first send or subscribe
+const memoryTopicDefaultPartitions = 1
+
+// ErrMemoryTopicPartitions is returned when partitions of existing topic cannot be changed
+var ErrMemoryTopicPartitions = errors.New("memory topic partitions cannot be changed")
+
+var (
+	memoryBrokers      = map[string]*MemoryBroker{}
+	memoryBrokersMutex sync.Mutex
+)
+
+// GetMemoryBroker return in-process broker of the servers, the same servers share the same broker
+func GetMemoryBroker(servers string) *MemoryBroker {
+	memoryBrokersMutex.Lock()
+	defer memoryBrokersMutex.Unlock()
+
+	broker, ok := memoryBrokers[servers]
+	if !ok {
+		broker = NewMemoryBroker()
+		memoryBrokers[servers] = broker
+	}
+	return broker
+}
+
+// MemoryBroker is in-process message queue with topics, partitions and consumer groups,
+// message with the same key is sent to the same partition and delivered in order
+type MemoryBroker struct {
+	mutex  sync.Mutex
+	topics map[string]*memoryTopic
+	groups map[string]*memoryGroup
+	// notify is closed and replaced when message is sent or group member is changed
+	notify chan struct{}
+}
+
+type memoryTopic struct {
+	partitions      []*memoryPartition
+	retentionPeriod time.Duration
+	nextPartition   int
+}
+
+type memoryPartition struct {
+	// base is offset of the first message, older messages are removed by retention period
+	base     int64
+	messages []memoryMessage
+}
+
+type memoryMessage struct {
+	key       string
+	value     string
+	createdAt time.Time
+}
+
+type memoryPartitionKey struct {
+	topic     string
+	partition int
+}
+
+type memoryGroup struct {
+	members   []*MemoryMQConsumer
+	committed map[memoryPartitionKey]int64
+	positions map[memoryPartitionKey]int64
+	owners    map[memoryPartitionKey]*MemoryMQConsumer
+}
+
+func newMemoryGroup() *memoryGroup {
+	return &memoryGroup{
+		members:   []*MemoryMQConsumer{},
+		committed: map[memoryPartitionKey]int64{},
+		positions: map[memoryPartitionKey]int64{},
+		owners:    map[memoryPartitionKey]*MemoryMQConsumer{},
+	}
+}
+
+// NewMemoryBroker return new empty broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics: map[string]*memoryTopic{},
+		groups: map[string]*memoryGroup{},
+		notify: make(chan struct{}),
+	}
+}
+
+// CreateTopic create topic, partitions of existing topic can be increased only before any message is sent to it
+// because key of sent message would be mapped to another partition and lose its order. Partitions are never decreased.
+func (b *MemoryBroker) CreateTopic(topic string, partitions int, retentionPeriod time.Duration) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.getOrCreateTopic(topic)
+	if partitions < len(t.partitions) {
+		return fmt.Errorf("%w: topic %s has %d partitions, cannot decrease to %d", ErrMemoryTopicPartitions, topic, len(t.partitions), partitions)
+	}
+
+	if partitions > len(t.partitions) && t.hasMessages() {
+		return fmt.Errorf("%w: topic %s has messages, cannot increase %d partitions to %d", ErrMemoryTopicPartitions, topic, len(t.partitions), partitions)
+	}
+
+	for len(t.partitions) < partitions {
+		t.partitions = append(t.partitions, &memoryPartition{})
+	}
+
+	if retentionPeriod > 0 {
+		t.retentionPeriod = retentionPeriod
+	}
+
+	b.broadcast()
+	return nil
+}
+
+// Send append message to the topic, message without key is sent to partitions in round robin
+func (b *MemoryBroker) Send(topic string, key string, value string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.getOrCreateTopic(topic)
+
+	partition := 0
+	if key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		partition = int(h.Sum32() % uint32(len(t.partitions)))
+	} else {
+		partition = t.nextPartition % len(t.partitions)
+		t.nextPartition++
+	}
+
+	now := time.Now()
+	p := t.partitions[partition]
+	p.messages = append(p.messages, memoryMessage{
+		key:       key,
+		value:     value,
+		createdAt: now,
+	})
+	t.trim(now)
+
+	b.broadcast()
+}
+
+// hasMessages return true when any message was sent to the topic, messages removed by retention are counted
+func (t *memoryTopic) hasMessages() bool {
+	for _, p := range t.partitions {
+		if p.base > 0 || len(p.messages) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// trim remove messages older than retention period from every partition
+func (t *memoryTopic) trim(now time.Time) {
+	if t.retentionPeriod <= 0 {
+		return
+	}
+
+	for _, p := range t.partitions {
+		expired := 0
+		for expired < len(p.messages) && now.Sub(p.messages[expired].createdAt) > t.retentionPeriod {
+			expired++
+		}
+		p.messages = p.messages[expired:]
+		p.base += int64(expired)
+	}
+}
+
+// Topics return name of every topic
+func (b *MemoryBroker) Topics() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.topicNames()
+}
+
+func (b *MemoryBroker) topicNames() []string {
+	names := make([]string, 0, len(b.topics))
+	for name := range b.topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (b *MemoryBroker) getOrCreateTopic(topic string) *memoryTopic {
+	t, ok := b.topics[topic]
+	if !ok {
+		t = &memoryTopic{}
+		for i := 0; i < memoryTopicDefaultPartitions; i++ {
+			t.partitions = append(t.partitions, &memoryPartition{})
+		}
+		b.topics[topic] = t
+	}
+	return t
+}
+
+func (b *MemoryBroker) broadcast() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func (b *MemoryBroker) join(c *MemoryMQConsumer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if c.groupID == "" {
+		// consumer without group has own offsets and read from the beginning
+		c.group = newMemoryGroup()
+	} else {
+		group, ok := b.groups[c.groupID]
+		if !ok {
+			group = newMemoryGroup()
+			b.groups[c.groupID] = group
+		}
+		c.group = group
+	}
+
+	c.group.members = append(c.group.members, c)
+	b.broadcast()
+}
+
+func (b *MemoryBroker) leave(c *MemoryMQConsumer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	members := c.group.members[:0]
+	for _, member := range c.group.members {
+		if member != c {
+			members = append(members, member)
+		}
+	}
+	c.group.members = members
+	b.broadcast()
+}
+
+// owner return group member which read the partition, partitions are divided between members subscribed to the topic
+func (b *MemoryBroker) owner(group *memoryGroup, topic string, partition int) *MemoryMQConsumer {
+	subscribed := []*MemoryMQConsumer{}
+	for _, member := range group.members {
+		if member.isSubscribed(topic) {
+			subscribed = append(subscribed, member)
+		}
+	}
+
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	return subscribed[partition%len(subscribed)]
+}
+
+// next return next message of partitions owned by the consumer, ok is false when there is no message
+func (b *MemoryBroker) next(c *MemoryMQConsumer) (ConsumerMessage, bool) {
+	group := c.group
+	for _, topic := range b.topicNames() {
+		if !c.isSubscribed(topic) {
+			continue
+		}
+
+		t := b.topics[topic]
+		t.trim(time.Now())
+		for i := range t.partitions {
+			partition := (c.cursor + i) % len(t.partitions)
+			if b.owner(group, topic, partition) != c {
+				continue
+			}
+
+			key := memoryPartitionKey{topic: topic, partition: partition}
+			if group.owners[key] != c {
+				// partition is assigned to this consumer, continue from committed offset
+				group.owners[key] = c
+				delete(group.positions, key)
+			}
+
+			p := t.partitions[partition]
+			position, ok := group.positions[key]
+			if !ok {
+				position = group.committed[key]
+			}
+			if position < p.base {
+				position = p.base
+			}
+
+			if position >= p.base+int64(len(p.messages)) {
+				continue
+			}
+
+			msg := p.messages[position-p.base]
+			group.positions[key] = position + 1
+			c.cursor = partition + 1
+
+			return ConsumerMessage{
+				Topic:     topic,
+				Key:       msg.key,
+				Partition: int32(partition),
+				Offset:    position,
+				Value:     msg.value,
+			}, true
+		}
+	}
+
+	return ConsumerMessage{}, false
+}
+
+// MemoryMQDriver implement IMQDriver with in-process broker
+type MemoryMQDriver struct {
+	broker *MemoryBroker
+}
+
+// NewMemoryMQDriver return driver of the broker
+func NewMemoryMQDriver(broker *MemoryBroker) *MemoryMQDriver {
+	return &MemoryMQDriver{
+		broker: broker,
+	}
+}
+
+// CreateTopic create topic in the broker, replications is ignored
+func (d *MemoryMQDriver) CreateTopic(topic string, partitions int, replications int, retentionPeriod time.Duration) error {
+	if retentionPeriod <= 0 {
+		retentionPeriod = 7 * (time.Hour * 24) // default = 7 days (Message will keep 7 days)
+	}
+
+	return d.broker.CreateTopic(topic, partitions, retentionPeriod)
+}
+
+// NewProducer return producer which send message to the broker
+func (d *MemoryMQDriver) NewProducer() IProducer {
+	return &MemoryProducer{
+		broker: d.broker,
+	}
+}
+
+// NewConsumer return consumer of the group
+func (d *MemoryMQDriver) NewConsumer(groupID string) (IMQConsumer, error) {
+	c := &MemoryMQConsumer{
+		broker:  d.broker,
+		groupID: groupID,
+	}
+	d.broker.join(c)
+	return c, nil
+}
+
+// MemoryProducer implement IProducer with in-process broker
+type MemoryProducer struct {
+	broker *MemoryBroker
+}
+
+// SendMessage send message to topic, message is json encoded the same as Kafka producer
+func (p *MemoryProducer) SendMessage(topic string, key string, message interface{}) error {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	p.broker.Send(topic, key, string(messageJSON))
+	return nil
+}
+
+func (p *MemoryProducer) Close() error {
+	return nil
+}
+
+func (p *MemoryProducer) TestConnect() error {
+	return nil
+}
+
+// MemoryMQConsumer implement IMQConsumer with in-process broker
+type MemoryMQConsumer struct {
+	broker  *MemoryBroker
+	groupID string
+	group   *memoryGroup
+	topic   string
+	pattern *regexp.Regexp
+	cursor  int
+	closed  bool
+}
+
+// Subscribe the topic, topic start with ^ is regular expression and match topic created later
+func (c *MemoryMQConsumer) Subscribe(topic string) error {
+	var pattern *regexp.Regexp
+	if isTopicPattern(topic) {
+		compiled, err := regexp.Compile(topic)
+		if err != nil {
+			return err
+		}
+		pattern = compiled
+	}
+
+	c.broker.mutex.Lock()
+	defer c.broker.mutex.Unlock()
+
+	c.topic = topic
+	c.pattern = pattern
+	if pattern == nil {
+		c.broker.getOrCreateTopic(topic)
+	}
+	c.broker.broadcast()
+
+	return nil
+}
+
+func (c *MemoryMQConsumer) isSubscribed(topic string) bool {
+	if c.pattern != nil {
+		return c.pattern.MatchString(topic)
+	}
+	return c.topic != "" && c.topic == topic
+}
+
+func (c *MemoryMQConsumer) ReadMessage(timeout time.Duration) (ConsumerMessage, error) {
+	var timer <-chan time.Time
+	if timeout >= 0 {
+		timer = time.After(timeout)
+	}
+
+	for {
+		c.broker.mutex.Lock()
+		if c.closed {
+			c.broker.mutex.Unlock()
+			return ConsumerMessage{}, fmt.Errorf("%w: consumer is closed", ErrMQFatal)
+		}
+
+		msg, ok := c.broker.next(c)
+		notify := c.broker.notify
+		c.broker.mutex.Unlock()
+
+		if ok {
+			return msg, nil
+		}
+
+		select {
+		case <-notify:
+		case <-timer:
+			return ConsumerMessage{}, ErrMQReadTimeout
+		}
+	}
+}
+
+func (c *MemoryMQConsumer) CommitMessage(msg ConsumerMessage) error {
+	c.broker.mutex.Lock()
+	defer c.broker.mutex.Unlock()
+
+	key := memoryPartitionKey{topic: msg.Topic, partition: int(msg.Partition)}
+	if msg.Offset+1 > c.group.committed[key] {
+		c.group.committed[key] = msg.Offset + 1
+	}
+	return nil
+}
+
+func (c *MemoryMQConsumer) Seek(msg ConsumerMessage) error {
+	c.broker.mutex.Lock()
+	defer c.broker.mutex.Unlock()
+
+	key := memoryPartitionKey{topic: msg.Topic, partition: int(msg.Partition)}
+	c.group.positions[key] = msg.Offset
+	c.broker.broadcast()
+	return nil
+}
+
+func (c *MemoryMQConsumer) Close() error {
+	c.broker.mutex.Lock()
+	if c.closed {
+		c.broker.mutex.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.broker.mutex.Unlock()
+
+	c.broker.leave(c)
+	return nil
+}
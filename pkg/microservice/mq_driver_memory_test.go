@@ -0,0 +1,287 @@
+package microservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readMemoryMessages(t *testing.T, c IMQConsumer, count int) []ConsumerMessage {
+	messages := []ConsumerMessage{}
+	for i := 0; i < count; i++ {
+		msg, err := c.ReadMessage(time.Second)
+		if !assert.Nil(t, err) {
+			return messages
+		}
+		messages = append(messages, msg)
+		assert.Nil(t, c.CommitMessage(msg))
+	}
+	return messages
+}
+
+func TestMemoryMQOrderedByKey(t *testing.T) {
+	driver := NewMemoryMQDriver(NewMemoryBroker())
+	assert.Nil(t, driver.CreateTopic("when-sale-invoice-created", 4, 1, 0))
+
+	producer := driver.NewProducer()
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, producer.SendMessage("when-sale-invoice-created", "SHOP01", map[string]int{"seq": i}))
+	}
+
+	c, _ := driver.NewConsumer("transaction-consumer-group-01")
+	defer c.Close()
+	assert.Nil(t, c.Subscribe("when-sale-invoice-created"))
+
+	messages := readMemoryMessages(t, c, 10)
+	for i, msg := range messages {
+		assert.Equal(t, "SHOP01", msg.Key)
+		assert.Equal(t, fmt.Sprintf(`{"seq":%d}`, i), msg.Value)
+		assert.Equal(t, int64(i), msg.Offset)
+	}
+
+	_, err := c.ReadMessage(10 * time.Millisecond)
+	assert.Equal(t, ErrMQReadTimeout, err)
+}
+
+func TestMemoryMQConsumerGroup(t *testing.T) {
+	driver := NewMemoryMQDriver(NewMemoryBroker())
+	driver.CreateTopic("when-purchase-created", 2, 1, 0)
+
+	producer := driver.NewProducer()
+	for i := 0; i < 20; i++ {
+		producer.SendMessage("when-purchase-created", fmt.Sprintf("DOC%02d", i), i)
+	}
+
+	c1, _ := driver.NewConsumer("group-a")
+	defer c1.Close()
+	c1.Subscribe("when-purchase-created")
+
+	c2, _ := driver.NewConsumer("group-a")
+	defer c2.Close()
+	c2.Subscribe("when-purchase-created")
+
+	other, _ := driver.NewConsumer("group-b")
+	defer other.Close()
+	other.Subscribe("when-purchase-created")
+
+	partitions := map[int32]IMQConsumer{}
+	total := 0
+	for _, c := range []IMQConsumer{c1, c2} {
+		for {
+			msg, err := c.ReadMessage(10 * time.Millisecond)
+			if err == ErrMQReadTimeout {
+				break
+			}
+			assert.Nil(t, err)
+			c.CommitMessage(msg)
+
+			owner, ok := partitions[msg.Partition]
+			if ok {
+				assert.Equal(t, owner, c, "partition is read by one member of the group")
+			}
+			partitions[msg.Partition] = c
+			total++
+		}
+	}
+
+	assert.Equal(t, 20, total)
+	assert.Len(t, readMemoryMessages(t, other, 20), 20)
+}
+
+func TestMemoryMQRedeliverUncommitted(t *testing.T) {
+	driver := NewMemoryMQDriver(NewMemoryBroker())
+	producer := driver.NewProducer()
+	producer.SendMessage("when-debtor-payment-created", "", "first")
+	producer.SendMessage("when-debtor-payment-created", "", "second")
+
+	c, _ := driver.NewConsumer("group-a")
+	c.Subscribe("when-debtor-payment-created")
+
+	msg, _ := c.ReadMessage(time.Second)
+	assert.Equal(t, `"first"`, msg.Value)
+
+	// handler failed, read the message again
+	c.Seek(msg)
+	msg, _ = c.ReadMessage(time.Second)
+	assert.Equal(t, `"first"`, msg.Value)
+	c.CommitMessage(msg)
+
+	// crash before commit, next member of the group continue from committed offset
+	msg, _ = c.ReadMessage(time.Second)
+	assert.Equal(t, `"second"`, msg.Value)
+	c.Close()
+
+	// closed consumer can not read anymore, consumer loop stop on the fatal error
+	_, closedErr := c.ReadMessage(time.Second)
+	assert.ErrorIs(t, closedErr, ErrMQFatal)
+
+	restarted, _ := driver.NewConsumer("group-a")
+	defer restarted.Close()
+	restarted.Subscribe("when-debtor-payment-created")
+
+	msg, err := restarted.ReadMessage(time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, `"second"`, msg.Value)
+}
+
+func TestMemoryMQTopicPattern(t *testing.T) {
+	driver := NewMemoryMQDriver(NewMemoryBroker())
+
+	c, _ := driver.NewConsumer("dead-letter-consumer-group-01")
+	defer c.Close()
+	c.Subscribe(`^.*\.dlq$`)
+
+	received := make(chan ConsumerMessage)
+	go func() {
+		msg, err := c.ReadMessage(time.Second)
+		if err == nil {
+			received <- msg
+		}
+		close(received)
+	}()
+
+	producer := driver.NewProducer()
+	producer.SendMessage("when-sale-invoice-created", "", "skip")
+	producer.SendMessage("when-sale-invoice-created.dlq", "", "dead")
+
+	msg := <-received
+	assert.Equal(t, "when-sale-invoice-created.dlq", msg.Topic)
+	assert.Equal(t, `"dead"`, msg.Value)
+}
+
+func TestMemoryMQTopicPartitions(t *testing.T) {
+	driver := NewMemoryMQDriver(NewMemoryBroker())
+
+	// topic created by subscribe can get more partitions before any message is sent
+	c, _ := driver.NewConsumer("group-a")
+	defer c.Close()
+	c.Subscribe("when-stock-transfer-created")
+	assert.Nil(t, driver.CreateTopic("when-stock-transfer-created", 3, 1, 0))
+	assert.Nil(t, driver.CreateTopic("when-stock-transfer-created", 3, 1, 0))
+
+	producer := driver.NewProducer()
+	producer.SendMessage("when-stock-transfer-created", "SHOP01", 1)
+
+	// key of sent message would be remapped to another partition
+	assert.ErrorIs(t, driver.CreateTopic("when-stock-transfer-created", 4, 1, 0), ErrMemoryTopicPartitions)
+	assert.ErrorIs(t, driver.CreateTopic("when-stock-transfer-created", 2, 1, 0), ErrMemoryTopicPartitions)
+
+	msg, err := c.ReadMessage(time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, "SHOP01", msg.Key)
+}
+
+func TestMemoryMQRetentionOnRead(t *testing.T) {
+	broker := NewMemoryBroker()
+	driver := NewMemoryMQDriver(broker)
+	assert.Nil(t, broker.CreateTopic("when-pay-created", 1, 50*time.Millisecond))
+
+	producer := driver.NewProducer()
+	producer.SendMessage("when-pay-created", "", "expired")
+	time.Sleep(100 * time.Millisecond)
+
+	// no message is sent after the message expired
+	c, _ := driver.NewConsumer("group-a")
+	defer c.Close()
+	c.Subscribe("when-pay-created")
+
+	_, err := c.ReadMessage(10 * time.Millisecond)
+	assert.Equal(t, ErrMQReadTimeout, err)
+}
+
+type memoryE2EMessage struct {
+	ShopID string `json:"shopid"`
+	Seq    int    `json:"seq"`
+}
+
+// TestMemoryMQEndToEnd send messages through microservice producer and consume them with retry and dead letter
+// in the same process
+func TestMemoryMQEndToEnd(t *testing.T) {
+	t.Setenv("MQ_DRIVER", MQDriverMemory)
+
+	cfg := config.NewConfig()
+	ms := &Microservice{
+		config: cfg,
+		Logger: logger.NewAppLogger(cfg.LoggerConfig()),
+		prods:  map[string]IProducer{},
+	}
+
+	servers := "memory-e2e-" + NewUUID()
+	topic := "when-sale-invoice-created"
+	require.Nil(t, ms.mqDriverByServers(servers).CreateTopic(topic, 4, 1, 0))
+
+	policy := ConsumerRetryPolicy{MaxRetries: 1, DeadLetter: true}
+
+	var mutex sync.Mutex
+	received := map[string][]int{}
+	attempts := map[int]int{}
+	done := make(chan struct{}, 20)
+
+	ms.ConsumeWithRetry(servers, topic, "transaction-consumer-group-01", -1, policy, func(ctx IContext) error {
+		msg := memoryE2EMessage{}
+		if err := json.Unmarshal([]byte(ctx.ReadInput()), &msg); err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		attempts[msg.Seq]++
+		// first attempt of seq 2 fail and is retried, seq 99 always fail
+		if (msg.Seq == 2 && attempts[msg.Seq] == 1) || msg.Seq == 99 {
+			return fmt.Errorf("cannot apply %d", msg.Seq)
+		}
+
+		received[msg.ShopID] = append(received[msg.ShopID], msg.Seq)
+		done <- struct{}{}
+		return nil
+	})
+
+	deadLetters := make(chan DeadLetterMessage, 1)
+	ms.ConsumeWithRetry(servers, DeadLetterTopic(topic), "dead-letter-consumer-group-01", -1, ConsumerRetryPolicy{}, func(ctx IContext) error {
+		msg := DeadLetterMessage{}
+		if err := json.Unmarshal([]byte(ctx.ReadInput()), &msg); err != nil {
+			return err
+		}
+		deadLetters <- msg
+		return nil
+	})
+
+	producer := ms.producerByServers(servers)
+	for seq := 1; seq <= 5; seq++ {
+		require.Nil(t, producer.SendMessage(topic, "SHOP01", memoryE2EMessage{ShopID: "SHOP01", Seq: seq}))
+		require.Nil(t, producer.SendMessage(topic, "SHOP02", memoryE2EMessage{ShopID: "SHOP02", Seq: seq}))
+	}
+	require.Nil(t, producer.SendMessage(topic, "SHOP03", memoryE2EMessage{ShopID: "SHOP03", Seq: 99}))
+
+	for i := 0; i < 10; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of 10 messages", i)
+		}
+	}
+
+	select {
+	case msg := <-deadLetters:
+		assert.Equal(t, topic, msg.Topic)
+		assert.Equal(t, "SHOP03", msg.Key)
+		assert.Equal(t, 2, msg.Attempts)
+	case <-time.After(5 * time.Second):
+		t.Fatal("dead letter is not received")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// messages of the same key are applied in order
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, received["SHOP01"])
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, received["SHOP02"])
+}
@@ -0,0 +1,206 @@
+package microservice
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"smlaicloudplatform/pkg/microservice/models"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// permissionCheckedKey is set on the request when route middleware has checked permission of the route
+const permissionCheckedKey = "PermissionChecked"
+
+// IPermissionChecker decide whether the user has the permission in the selected shop,
+// permission is "<module>:<action>" e.g. transaction.saleinvoice:delete
+type IPermissionChecker interface {
+	HasPermission(ctx context.Context, userInfo models.UserInfo, permission string) (bool, error)
+}
+
+// MatchPermission return true when granted permission cover required permission,
+// granted permission may use * e.g. "*", "transaction.*:*" or "transaction.saleinvoice:*"
+func MatchPermission(granted string, required string) bool {
+	if granted == required {
+		return true
+	}
+
+	matched, err := path.Match(granted, required)
+	return err == nil && matched
+}
+
+func matchAnyPermission(granted []string, required string) bool {
+	for _, permission := range granted {
+		if MatchPermission(permission, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPermissionChecker set checker of RequirePermission middleware
+func (ms *Microservice) SetPermissionChecker(checker IPermissionChecker) {
+	ms.permissionMutex.Lock()
+	defer ms.permissionMutex.Unlock()
+
+	ms.permissionChecker = checker
+}
+
+func (ms *Microservice) getPermissionChecker() IPermissionChecker {
+	ms.permissionMutex.RLock()
+	defer ms.permissionMutex.RUnlock()
+
+	return ms.permissionChecker
+}
+
+// RequirePermission is route middleware which allow the request when the user has every permission,
+// it is declared at route registration e.g. ms.DELETE(path, h, ms.RequirePermission("shop:delete")),
+// request of api key also needs the permission in scope of the key
+func (ms *Microservice) RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userInfo, ok := c.Get("UserInfo").(models.UserInfo)
+			if !ok || userInfo.Username == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Token Invalid."})
+			}
+
+			if userInfo.ShopID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{"success": false, "message": "Shop not selected."})
+			}
+
+			checker := ms.getPermissionChecker()
+			if checker == nil {
+				ms.Logger.Errorf("Permission checker is not set, %s %s is denied", c.Request().Method, c.Path())
+				return c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": "permission denied"})
+			}
+
+			for _, permission := range permissions {
+				allowed, err := ms.checkPermission(c, checker, userInfo, permission)
+				if !allowed {
+					return err
+				}
+			}
+
+			c.Set(permissionCheckedKey, true)
+			return next(c)
+		}
+	}
+}
+
+// SkipPermission is route middleware of route of the user account e.g. profile and sessions,
+// the route is not checked by permission of the route in the selected shop and request of api key is denied
+func (ms *Microservice) SkipPermission() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userInfo, _ := c.Get("UserInfo").(models.UserInfo)
+			if userInfo.ApiKeyID != "" {
+				return c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": "route is not allowed for api key"})
+			}
+
+			c.Set(permissionCheckedKey, true)
+			return next(c)
+		}
+	}
+}
+
+// RoutePermission return permission of route which does not declare permission by RequirePermission,
+// module is first two segments of the path without parameters and dashes and action is from the method
+// e.g. GET /product/barcode/:id is product.barcode:read and POST /gl/journal/bulk is gl.journal:create
+func RoutePermission(method string, routePath string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(routePath, "/") {
+		if segment == "" || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			continue
+		}
+
+		segments = append(segments, strings.ToLower(strings.ReplaceAll(segment, "-", "")))
+		if len(segments) == 2 {
+			break
+		}
+	}
+
+	action := "read"
+	switch method {
+	case http.MethodPost:
+		action = "create"
+	case http.MethodPut, http.MethodPatch:
+		action = "update"
+	case http.MethodDelete:
+		action = "delete"
+	}
+
+	return strings.Join(segments, ".") + ":" + action
+}
+
+// RoutePermissions return permissions of routes which do not declare permission, they are listed for role editor
+func (ms *Microservice) RoutePermissions() []string {
+	ms.permissionMutex.RLock()
+	defer ms.permissionMutex.RUnlock()
+
+	permissions := make([]string, 0, len(ms.routePermissions))
+	for permission := range ms.routePermissions {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	return permissions
+}
+
+// withRoutePermission check permission of the route before the handler when route middleware does not check
+// permission, request without user is public route and user who does not select shop is not checked,
+// request of api key is always checked by scope of the key
+func (ms *Microservice) withRoutePermission(method string, routePath string, h echo.HandlerFunc) echo.HandlerFunc {
+	permission := RoutePermission(method, routePath)
+
+	ms.permissionMutex.Lock()
+	if ms.routePermissions == nil {
+		ms.routePermissions = map[string]struct{}{}
+	}
+	ms.routePermissions[permission] = struct{}{}
+	ms.permissionMutex.Unlock()
+
+	return func(c echo.Context) error {
+		if checked, _ := c.Get(permissionCheckedKey).(bool); checked {
+			return h(c)
+		}
+
+		userInfo, ok := c.Get("UserInfo").(models.UserInfo)
+		if !ok || userInfo.Username == "" || (userInfo.ShopID == "" && userInfo.ApiKeyID == "") {
+			return h(c)
+		}
+
+		checker := ms.getPermissionChecker()
+		if checker == nil {
+			ms.Logger.Errorf("Permission checker is not set, %s %s is denied", c.Request().Method, c.Path())
+			return c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": "permission denied"})
+		}
+
+		allowed, err := ms.checkPermission(c, checker, userInfo, permission)
+		if !allowed {
+			return err
+		}
+
+		return h(c)
+	}
+}
+
+// checkPermission write the error response and return false when the user or scope of api key does not have the permission
+func (ms *Microservice) checkPermission(c echo.Context, checker IPermissionChecker, userInfo models.UserInfo, permission string) (bool, error) {
+	if userInfo.ApiKeyID != "" && !matchAnyPermission(userInfo.Scopes, permission) {
+		return false, c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": "permission is not in api key scope", "permission": permission})
+	}
+
+	allowed, err := checker.HasPermission(c.Request().Context(), userInfo, permission)
+	if err != nil {
+		ms.Logger.Errorf("Permission %s of %s in shop %s cannot be checked: %v", permission, userInfo.Username, userInfo.ShopID, err)
+		return false, c.JSON(http.StatusInternalServerError, map[string]interface{}{"success": false, "message": "permission cannot be checked"})
+	}
+
+	if !allowed {
+		return false, c.JSON(http.StatusForbidden, map[string]interface{}{"success": false, "message": "permission denied", "permission": permission})
+	}
+
+	return true, nil
+}
@@ -0,0 +1,147 @@
+package microservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice/models"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type permissionCheckerStub struct {
+	granted []string
+}
+
+func (c permissionCheckerStub) HasPermission(ctx context.Context, userInfo models.UserInfo, permission string) (bool, error) {
+	for _, granted := range c.granted {
+		if MatchPermission(granted, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestMatchPermission(t *testing.T) {
+	cases := []struct {
+		granted  string
+		required string
+		want     bool
+	}{
+		{"transaction.saleinvoice:delete", "transaction.saleinvoice:delete", true},
+		{"transaction.saleinvoice:read", "transaction.saleinvoice:delete", false},
+		{"transaction.saleinvoice:*", "transaction.saleinvoice:delete", true},
+		{"transaction.*:*", "transaction.saleinvoice:delete", true},
+		{"transaction.*:*", "shop.user:delete", false},
+		{"*", "shop:delete", true},
+		{"shop:*", "shop.user:delete", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, MatchPermission(c.granted, c.required), "%s covers %s", c.granted, c.required)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	ms := &Microservice{Logger: logger.NewAppLogger(config.NewLoggerConfig())}
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	serve := func(userInfo interface{}) int {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/transaction/sale-invoice/1", nil), rec)
+		if userInfo != nil {
+			c.Set("UserInfo", userInfo)
+		}
+
+		err := ms.RequirePermission("transaction.saleinvoice:delete")(handler)(c)
+		assert.Nil(t, err)
+		return rec.Code
+	}
+
+	user := models.UserInfo{Username: "user01", ShopID: "SHOP01"}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(nil))
+	assert.Equal(t, http.StatusUnauthorized, serve(models.UserInfo{Username: "user01"}), "shop is not selected")
+	assert.Equal(t, http.StatusForbidden, serve(user), "checker is not set")
+
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"transaction.saleinvoice:read"}})
+	assert.Equal(t, http.StatusForbidden, serve(user))
+
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"transaction.*:*"}})
+	assert.Equal(t, http.StatusOK, serve(user))
+
+	apiKeyUser := models.UserInfo{Username: "user01", ShopID: "SHOP01", ApiKeyID: "KEY01", Scopes: []string{"transaction.saleinvoice:read"}}
+	assert.Equal(t, http.StatusForbidden, serve(apiKeyUser), "permission is not in api key scope")
+
+	apiKeyUser.Scopes = []string{"transaction.saleinvoice:*"}
+	assert.Equal(t, http.StatusOK, serve(apiKeyUser))
+}
+
+func TestRoutePermission(t *testing.T) {
+	assert.Equal(t, "product.barcode:read", RoutePermission(http.MethodGet, "/product/barcode/:id"))
+	assert.Equal(t, "gl.journal:create", RoutePermission(http.MethodPost, "/gl/journal/bulk"))
+	assert.Equal(t, "transaction.purchaseorder:update", RoutePermission(http.MethodPut, "/transaction/purchase-order/:id"))
+	assert.Equal(t, "debtaccount.customer:delete", RoutePermission(http.MethodDelete, "/debtaccount/customer/:id"))
+	assert.Equal(t, "shop:read", RoutePermission(http.MethodGet, "/shop/:id"))
+}
+
+func TestRouteWithoutDeclaredPermission(t *testing.T) {
+	ms := &Microservice{Logger: logger.NewAppLogger(config.NewLoggerConfig())}
+
+	handler := ms.withRoutePermission(http.MethodDelete, "/product/barcode/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(userInfo interface{}, m ...echo.MiddlewareFunc) int {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/product/barcode/1", nil), rec)
+		if userInfo != nil {
+			c.Set("UserInfo", userInfo)
+		}
+
+		h := handler
+		for _, middleware := range m {
+			h = middleware(h)
+		}
+
+		assert.Nil(t, h(c))
+		return rec.Code
+	}
+
+	user := models.UserInfo{Username: "user01", ShopID: "SHOP01"}
+	assert.Equal(t, http.StatusForbidden, serve(user), "checker is not set")
+	assert.Equal(t, http.StatusOK, serve(user, ms.SkipPermission()), "route of the user account without checker")
+
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"product*:read"}})
+
+	assert.Equal(t, http.StatusOK, serve(nil), "public route")
+	assert.Equal(t, http.StatusOK, serve(models.UserInfo{Username: "user01"}), "shop is not selected")
+	assert.Equal(t, http.StatusForbidden, serve(user))
+	assert.Equal(t, http.StatusOK, serve(user, ms.SkipPermission()), "route of the user account")
+
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"product*:*"}})
+	assert.Equal(t, http.StatusOK, serve(user))
+
+	// permission declared by route middleware replace permission of the path
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"product.barcode.owner:delete"}})
+	assert.Equal(t, http.StatusOK, serve(user, ms.RequirePermission("product.barcode.owner:delete")))
+
+	apiKeyUser := models.UserInfo{Username: "user01", ShopID: "SHOP01", ApiKeyID: "KEY01", Scopes: []string{"transaction.*:*"}}
+	ms.SetPermissionChecker(permissionCheckerStub{granted: []string{"*"}})
+	assert.Equal(t, http.StatusForbidden, serve(apiKeyUser), "permission is not in api key scope")
+	assert.Equal(t, http.StatusForbidden, serve(apiKeyUser, ms.SkipPermission()), "api key is denied on route which does not declare permission")
+
+	apiKeyUser.Scopes = []string{"product.barcode:*"}
+	assert.Equal(t, http.StatusOK, serve(apiKeyUser))
+
+	assert.Equal(t, []string{"product.barcode:delete"}, ms.RoutePermissions())
+}
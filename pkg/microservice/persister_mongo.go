@@ -32,6 +32,7 @@ type IPersisterMongo interface {
 	Create(ctx context.Context, model interface{}, data interface{}) (primitive.ObjectID, error)
 	UpdateOne(ctx context.Context, model interface{}, filterConditions map[string]interface{}, data interface{}) error
 	Update(ctx context.Context, model interface{}, filter interface{}, data interface{}, opts ...*options.UpdateOptions) error
+	FindOneAndUpdate(ctx context.Context, model interface{}, filter interface{}, data interface{}, decode interface{}, opts ...*options.FindOneAndUpdateOptions) error
 	CreateInBatch(ctx context.Context, model interface{}, data []interface{}) error
 	Count(ctx context.Context, model interface{}, filter interface{}) (int, error)
 	Exec(ctx context.Context, model interface{}) (*mongo.Collection, error)
@@ -400,6 +401,29 @@ func (pst *PersisterMongo) Update(ctx context.Context, model interface{}, filter
 	return nil
 }
 
+// FindOneAndUpdate update the first document of the filter and decode it, decode is untouched when nothing match
+func (pst *PersisterMongo) FindOneAndUpdate(ctx context.Context, model interface{}, filter interface{}, data interface{}, decode interface{}, opts ...*options.FindOneAndUpdateOptions) error {
+	db, err := pst.getClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	collectionName, err := pst.getCollectionName(model)
+	if err != nil {
+		return err
+	}
+
+	err = db.Collection(collectionName).FindOneAndUpdate(ctx, filter, data, opts...).Decode(decode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (pst *PersisterMongo) UpdateOne(ctx context.Context, model interface{}, filterConditions map[string]interface{}, data interface{}) error {
 	db, err := pst.getClient(ctx)
 	if err != nil {
@@ -701,6 +725,11 @@ func (pst *PersisterMongo) Healthcheck(ctx context.Context) error {
 }
 
 func (pst *PersisterMongo) Transaction(ctx context.Context, queryFunc func(context.Context) error) error {
+	// query of a caller which is already in a transaction joins it so both are committed together
+	if mongo.SessionFromContext(ctx) != nil {
+		return queryFunc(ctx)
+	}
+
 	pst.getClient(ctx)
 	client := pst.client
 
@@ -0,0 +1,399 @@
+package microservice
+
+import (
+	"context"
+	"fmt"
+
+	"smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantPersisterMongo check that queries on collections of shops are scoped to one shop.
+// Shop of the query is the shop of WithTenantShopID, it is added to filters and pipelines which do not have it
+// on enforce mode and they are only logged on report mode, without it the filter or the first $match of pipeline
+// must have shopid equality.
+// Context of WithSystemAdmin and models without shopid field are not checked.
+// Exec return collection of shops only to context of WithCollectionShopID which is the shop of the context.
+type TenantPersisterMongo struct {
+	pst    IPersisterMongo
+	policy tenantPolicy
+}
+
+func NewTenantPersisterMongo(pst IPersisterMongo, mode string) *TenantPersisterMongo {
+	return &TenantPersisterMongo{
+		pst:    pst,
+		policy: tenantPolicy{mode: mode},
+	}
+}
+
+func (t *TenantPersisterMongo) checked(ctx context.Context, model interface{}) bool {
+	return IsTenantModel(model) && !IsSystemAdmin(ctx)
+}
+
+func (t *TenantPersisterMongo) target(model interface{}) string {
+	if mongoModel, ok := model.(MongoModel); ok {
+		return mongoModel.CollectionName()
+	}
+	return fmt.Sprintf("%T", model)
+}
+
+// scopeFilter return filter which is scoped to the shop of the context on enforce mode and the shop of the query
+func (t *TenantPersisterMongo) scopeFilter(ctx context.Context, op string, model interface{}, filter interface{}) (interface{}, string, error) {
+	if !t.checked(ctx, model) {
+		return filter, "", nil
+	}
+
+	shopIDs := FilterShopIDs(filter)
+	contextShopID := TenantShopIDFromContext(ctx)
+
+	if contextShopID == "" {
+		if len(shopIDs) == 0 || shopIDs[0] == "" {
+			return filter, "", t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+		}
+		return filter, shopIDs[0], nil
+	}
+
+	for _, shopID := range shopIDs {
+		if shopID != contextShopID {
+			return filter, contextShopID, t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+		}
+	}
+
+	if len(shopIDs) == 0 {
+		if !t.policy.enforced() {
+			return filter, contextShopID, t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+		}
+		filter = scopeFilter(filter, contextShopID)
+	}
+
+	return filter, contextShopID, nil
+}
+
+func (t *TenantPersisterMongo) scopePipeline(ctx context.Context, op string, model interface{}, pipeline interface{}) (interface{}, error) {
+	if !t.checked(ctx, model) {
+		return pipeline, nil
+	}
+
+	shopIDs := PipelineShopIDs(pipeline)
+	contextShopID := TenantShopIDFromContext(ctx)
+
+	if contextShopID == "" {
+		if len(shopIDs) == 0 || shopIDs[0] == "" {
+			return pipeline, t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+		}
+		return pipeline, nil
+	}
+
+	for _, shopID := range shopIDs {
+		if shopID != contextShopID {
+			return pipeline, t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+		}
+	}
+
+	if len(shopIDs) == 0 {
+		if !t.policy.enforced() {
+			return pipeline, t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+		}
+		return scopePipeline(pipeline, contextShopID), nil
+	}
+
+	return pipeline, nil
+}
+
+// checkUpdate refuse update which move documents to other shop
+func (t *TenantPersisterMongo) checkUpdate(ctx context.Context, op string, model interface{}, shopID string, update interface{}) error {
+	if !t.checked(ctx, model) || shopID == "" {
+		return nil
+	}
+
+	for _, updateShopID := range updateShopIDs(update) {
+		if updateShopID != shopID {
+			return t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+		}
+	}
+
+	return nil
+}
+
+// checkCollection refuse collection of shops which is not given to one shop by WithCollectionShopID
+func (t *TenantPersisterMongo) checkCollection(ctx context.Context, op string, model interface{}) error {
+	if !t.checked(ctx, model) {
+		return nil
+	}
+
+	shopID := collectionShopIDFromContext(ctx)
+	if shopID == "" {
+		return t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+	}
+
+	contextShopID := TenantShopIDFromContext(ctx)
+	if contextShopID != "" && shopID != contextShopID {
+		return t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+	}
+
+	return nil
+}
+
+func (t *TenantPersisterMongo) checkDocuments(ctx context.Context, op string, model interface{}, docs ...interface{}) error {
+	if !t.checked(ctx, model) {
+		return nil
+	}
+
+	contextShopID := TenantShopIDFromContext(ctx)
+	for _, doc := range docs {
+		shopID := DocumentShopID(doc)
+
+		if shopID == "" {
+			return t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+		}
+
+		if contextShopID != "" && shopID != contextShopID {
+			return t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+		}
+	}
+
+	return nil
+}
+
+// checkOwnedIDs refuse query by id when the shop of the context is not known or any document of the ids is in other shop
+func (t *TenantPersisterMongo) checkOwnedIDs(ctx context.Context, op string, model interface{}, idFilter bson.M) error {
+	if !t.checked(ctx, model) {
+		return nil
+	}
+
+	contextShopID := TenantShopIDFromContext(ctx)
+	if contextShopID == "" {
+		return t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+	}
+
+	otherShopFilter := bson.M{tenantField: bson.M{"$ne": contextShopID}}
+	for key, value := range idFilter {
+		otherShopFilter[key] = value
+	}
+
+	count, err := t.pst.Count(ctx, model, otherShopFilter)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return t.policy.violate(op, t.target(model), ErrCrossTenantQuery)
+	}
+
+	return nil
+}
+
+func (t *TenantPersisterMongo) Aggregate(ctx context.Context, model interface{}, pipeline interface{}, decode interface{}) error {
+	pipeline, err := t.scopePipeline(ctx, "aggregate", model, pipeline)
+	if err != nil {
+		return err
+	}
+	return t.pst.Aggregate(ctx, model, pipeline, decode)
+}
+
+func (t *TenantPersisterMongo) AggregatePage(ctx context.Context, model interface{}, pageable models.Pageable, criteria ...interface{}) (*mongopagination.PaginatedData, error) {
+	pipeline, err := t.scopePipeline(ctx, "aggregate page", model, criteria)
+	if err != nil {
+		return nil, err
+	}
+	return t.pst.AggregatePage(ctx, model, pageable, toSlice(pipeline)...)
+}
+
+func (t *TenantPersisterMongo) Find(ctx context.Context, model interface{}, filter interface{}, decode interface{}, opts ...*options.FindOptions) error {
+	filter, _, err := t.scopeFilter(ctx, "find", model, filter)
+	if err != nil {
+		return err
+	}
+	return t.pst.Find(ctx, model, filter, decode, opts...)
+}
+
+func (t *TenantPersisterMongo) FindPage(ctx context.Context, model interface{}, filter interface{}, pageable models.Pageable, decode interface{}) (mongopagination.PaginationData, error) {
+	filter, _, err := t.scopeFilter(ctx, "find page", model, filter)
+	if err != nil {
+		return mongopagination.PaginationData{}, err
+	}
+	return t.pst.FindPage(ctx, model, filter, pageable, decode)
+}
+
+func (t *TenantPersisterMongo) FindSelectPage(ctx context.Context, model interface{}, selectFields interface{}, filter interface{}, pageable models.Pageable, decode interface{}) (mongopagination.PaginationData, error) {
+	filter, _, err := t.scopeFilter(ctx, "find select page", model, filter)
+	if err != nil {
+		return mongopagination.PaginationData{}, err
+	}
+	return t.pst.FindSelectPage(ctx, model, selectFields, filter, pageable, decode)
+}
+
+func (t *TenantPersisterMongo) FindOne(ctx context.Context, model interface{}, filter interface{}, decode interface{}, opts ...*options.FindOneOptions) error {
+	filter, _, err := t.scopeFilter(ctx, "find one", model, filter)
+	if err != nil {
+		return err
+	}
+	return t.pst.FindOne(ctx, model, filter, decode, opts...)
+}
+
+func (t *TenantPersisterMongo) FindByID(ctx context.Context, model interface{}, keyName string, id interface{}, decode interface{}) error {
+	err := t.checkOwnedIDs(ctx, "find by id", model, bson.M{keyName: id})
+	if err != nil {
+		return err
+	}
+	return t.pst.FindByID(ctx, model, keyName, id, decode)
+}
+
+func (t *TenantPersisterMongo) Create(ctx context.Context, model interface{}, data interface{}) (primitive.ObjectID, error) {
+	err := t.checkDocuments(ctx, "create", model, data)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return t.pst.Create(ctx, model, data)
+}
+
+func (t *TenantPersisterMongo) UpdateOne(ctx context.Context, model interface{}, filterConditions map[string]interface{}, data interface{}) error {
+	filter, shopID, err := t.scopeFilter(ctx, "update one", model, filterConditions)
+	if err != nil {
+		return err
+	}
+
+	err = t.checkUpdate(ctx, "update one", model, shopID, data)
+	if err != nil {
+		return err
+	}
+
+	scopedConditions, _ := filter.(map[string]interface{})
+	if scopedConditions == nil {
+		scopedConditions = filterConditions
+	}
+	return t.pst.UpdateOne(ctx, model, scopedConditions, data)
+}
+
+func (t *TenantPersisterMongo) Update(ctx context.Context, model interface{}, filter interface{}, data interface{}, opts ...*options.UpdateOptions) error {
+	filter, shopID, err := t.scopeFilter(ctx, "update", model, filter)
+	if err != nil {
+		return err
+	}
+
+	err = t.checkUpdate(ctx, "update", model, shopID, data)
+	if err != nil {
+		return err
+	}
+	return t.pst.Update(ctx, model, filter, data, opts...)
+}
+
+func (t *TenantPersisterMongo) FindOneAndUpdate(ctx context.Context, model interface{}, filter interface{}, data interface{}, decode interface{}, opts ...*options.FindOneAndUpdateOptions) error {
+	filter, shopID, err := t.scopeFilter(ctx, "find one and update", model, filter)
+	if err != nil {
+		return err
+	}
+
+	err = t.checkUpdate(ctx, "find one and update", model, shopID, data)
+	if err != nil {
+		return err
+	}
+	return t.pst.FindOneAndUpdate(ctx, model, filter, data, decode, opts...)
+}
+
+func (t *TenantPersisterMongo) CreateInBatch(ctx context.Context, model interface{}, data []interface{}) error {
+	err := t.checkDocuments(ctx, "create in batch", model, data...)
+	if err != nil {
+		return err
+	}
+	return t.pst.CreateInBatch(ctx, model, data)
+}
+
+func (t *TenantPersisterMongo) Count(ctx context.Context, model interface{}, filter interface{}) (int, error) {
+	filter, _, err := t.scopeFilter(ctx, "count", model, filter)
+	if err != nil {
+		return 0, err
+	}
+	return t.pst.Count(ctx, model, filter)
+}
+
+func (t *TenantPersisterMongo) Exec(ctx context.Context, model interface{}) (*mongo.Collection, error) {
+	err := t.checkCollection(ctx, "exec", model)
+	if err != nil {
+		return nil, err
+	}
+	return t.pst.Exec(ctx, model)
+}
+
+func (t *TenantPersisterMongo) Delete(ctx context.Context, model interface{}, filter interface{}) error {
+	filter, _, err := t.scopeFilter(ctx, "delete", model, filter)
+	if err != nil {
+		return err
+	}
+	return t.pst.Delete(ctx, model, filter)
+}
+
+func (t *TenantPersisterMongo) DeleteByID(ctx context.Context, model interface{}, id string) error {
+	idx, _ := primitive.ObjectIDFromHex(id)
+	err := t.checkOwnedIDs(ctx, "delete by id", model, bson.M{"_id": idx})
+	if err != nil {
+		return err
+	}
+	return t.pst.DeleteByID(ctx, model, id)
+}
+
+func (t *TenantPersisterMongo) SoftDelete(ctx context.Context, model interface{}, username string, filter interface{}) error {
+	filter, _, err := t.scopeFilter(ctx, "soft delete", model, filter)
+	if err != nil {
+		return err
+	}
+	return t.pst.SoftDelete(ctx, model, username, filter)
+}
+
+func (t *TenantPersisterMongo) SoftDeleteLastUpdate(ctx context.Context, model interface{}, username string, filter interface{}) error {
+	filter, _, err := t.scopeFilter(ctx, "soft delete", model, filter)
+	if err != nil {
+		return err
+	}
+	return t.pst.SoftDeleteLastUpdate(ctx, model, username, filter)
+}
+
+func (t *TenantPersisterMongo) SoftBatchDeleteByID(ctx context.Context, model interface{}, username string, ids []string) error {
+	objIDs := []primitive.ObjectID{}
+	for _, id := range ids {
+		idx, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+		objIDs = append(objIDs, idx)
+	}
+
+	err := t.checkOwnedIDs(ctx, "soft delete by id", model, bson.M{"_id": bson.M{"$in": objIDs}})
+	if err != nil {
+		return err
+	}
+	return t.pst.SoftBatchDeleteByID(ctx, model, username, ids)
+}
+
+func (t *TenantPersisterMongo) SoftDeleteByID(ctx context.Context, model interface{}, id string, username string) error {
+	err := t.checkOwnedIDs(ctx, "soft delete by id", model, bson.M{"guidfixed": id})
+	if err != nil {
+		return err
+	}
+	return t.pst.SoftDeleteByID(ctx, model, id, username)
+}
+
+func (t *TenantPersisterMongo) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return t.pst.Transaction(ctx, queryFunc)
+}
+
+func (t *TenantPersisterMongo) Cleanup(ctx context.Context) error {
+	return t.pst.Cleanup(ctx)
+}
+
+func (t *TenantPersisterMongo) TestConnect(ctx context.Context) error {
+	return t.pst.TestConnect(ctx)
+}
+
+func (t *TenantPersisterMongo) Healthcheck(ctx context.Context) error {
+	return t.pst.Healthcheck(ctx)
+}
+
+func (t *TenantPersisterMongo) CreateIndex(ctx context.Context, model interface{}, indexName string, keys interface{}) (string, error) {
+	return t.pst.CreateIndex(ctx, model, indexName, keys)
+}
@@ -0,0 +1,520 @@
+package microservice
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// sqlShopCondition match equality of shop column with positional or named parameter e.g. "shopid = ?", "shop_id = @shopid"
+// or "shopid = $1" of statement which is built by gorm
+var sqlShopCondition = regexp.MustCompile(`(?i)(^|[^\w.])(?:\w+\.)?(shop_?id)\s*=\s*(\?|@(\w+)|\$(\d+))`)
+
+var sqlDMLStatement = regexp.MustCompile(`(?i)^\s*(select|update|delete|with)\b`)
+
+// TenantPersister check that queries on tables of shops are scoped to one shop. Expression of the query
+// must have equality of shopid or shop_id column with non empty parameter, where map must have the column
+// and created rows must have shop id. Tables without shop column are not checked.
+// Statements of DBClient and Transaction are checked by gorm callbacks, SystemAdminPersister opt out of the check.
+type TenantPersister struct {
+	pst    IPersister
+	policy tenantPolicy
+}
+
+func NewTenantPersister(pst IPersister, mode string) *TenantPersister {
+	return &TenantPersister{
+		pst:    pst,
+		policy: tenantPolicy{mode: mode},
+	}
+}
+
+// SystemAdminPersister return persister which query across shops, it is for jobs, migrations
+// and system admin apis only
+func SystemAdminPersister(pst IPersister) IPersister {
+	if tenantPst, ok := pst.(*TenantPersister); ok {
+		return tenantPst.pst
+	}
+	return pst
+}
+
+// SQLShopID return shop id which the expression is scoped to, the value is taken from args
+// by position of ? or $n or from map of named parameter
+func SQLShopID(expr string, args ...interface{}) (string, bool) {
+	loc := sqlShopCondition.FindStringSubmatchIndex(expr)
+	if loc == nil {
+		return "", false
+	}
+
+	// named parameter
+	if loc[8] >= 0 {
+		name := expr[loc[8]:loc[9]]
+		for _, arg := range args {
+			if named, ok := arg.(map[string]interface{}); ok {
+				shopID, ok := named[name].(string)
+				return shopID, ok && shopID != ""
+			}
+		}
+		return "", false
+	}
+
+	index := strings.Count(expr[:loc[6]], "?")
+	if loc[10] >= 0 {
+		number, _ := strconv.Atoi(expr[loc[10]:loc[11]])
+		index = number - 1
+	}
+
+	if index < 0 || index >= len(args) {
+		return "", false
+	}
+
+	shopID, ok := args[index].(string)
+	return shopID, ok && shopID != ""
+}
+
+func whereShopID(where map[string]interface{}) (string, bool) {
+	for _, column := range []string{"shopid", "shop_id"} {
+		if shopID, ok := where[column].(string); ok && shopID != "" {
+			return shopID, true
+		}
+	}
+	return "", false
+}
+
+var tenantTables sync.Map
+
+// IsTenantTable return true when the model has shop column
+func IsTenantTable(model interface{}) bool {
+	modelType := reflect.TypeOf(model)
+	if modelType == nil {
+		return false
+	}
+
+	for modelType.Kind() == reflect.Ptr || modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array {
+		modelType = modelType.Elem()
+	}
+
+	if cached, ok := tenantTables.Load(modelType); ok {
+		return cached.(bool)
+	}
+
+	isTenant := shopField(modelType) != nil
+	tenantTables.Store(modelType, isTenant)
+
+	return isTenant
+}
+
+// shopField return index of shop column field, the field is ShopID or has gorm column shopid or shop_id
+func shopField(structType reflect.Type) []int {
+	if structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		gormTag := field.Tag.Get("gorm")
+
+		if gormTag == "-" {
+			continue
+		}
+
+		column := ""
+		for _, opt := range strings.Split(gormTag, ";") {
+			if strings.HasPrefix(opt, "column:") {
+				column = strings.TrimPrefix(opt, "column:")
+			}
+		}
+
+		if column == "shopid" || column == "shop_id" || (column == "" && field.Name == "ShopID") {
+			return []int{i}
+		}
+
+		if field.Anonymous {
+			if index := shopField(field.Type); index != nil {
+				return append([]int{i}, index...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rowShopIDs return shop id of the row or every row of slice
+func rowShopIDs(rows interface{}) []string {
+	rv := reflect.ValueOf(rows)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		shopIDs := []string{}
+		for i := 0; i < rv.Len(); i++ {
+			shopIDs = append(shopIDs, rowShopIDs(rv.Index(i).Interface())...)
+		}
+		return shopIDs
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	index := shopField(rv.Type())
+	if index == nil {
+		return nil
+	}
+
+	shopID, _ := rv.FieldByIndex(index).Interface().(string)
+	return []string{shopID}
+}
+
+func (t *TenantPersister) target(model interface{}) string {
+	if tabler, ok := model.(interface{ TableName() string }); ok {
+		return tabler.TableName()
+	}
+	return fmt.Sprintf("%T", model)
+}
+
+func (t *TenantPersister) checkExpr(op string, model interface{}, expr string, args ...interface{}) error {
+	if !IsTenantTable(model) {
+		return nil
+	}
+
+	if _, ok := SQLShopID(expr, args...); !ok {
+		return t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+	}
+
+	return nil
+}
+
+func (t *TenantPersister) checkWhere(op string, model interface{}, where map[string]interface{}) error {
+	if !IsTenantTable(model) {
+		return nil
+	}
+
+	if _, ok := whereShopID(where); !ok {
+		return t.policy.violate(op, t.target(model), ErrUnscopedQuery)
+	}
+
+	return nil
+}
+
+func (t *TenantPersister) checkRows(op string, rows interface{}) error {
+	if !IsTenantTable(rows) {
+		return nil
+	}
+
+	for _, shopID := range rowShopIDs(rows) {
+		if shopID == "" {
+			return t.policy.violate(op, t.target(rows), ErrUnscopedQuery)
+		}
+	}
+
+	return nil
+}
+
+func (t *TenantPersister) WhereSP(model interface{}, sortexpr string, pageLimit int, page int, expr string, args ...interface{}) (interface{}, error) {
+	if err := t.checkExpr("where", model, expr, args...); err != nil {
+		return nil, err
+	}
+	return t.pst.WhereSP(model, sortexpr, pageLimit, page, expr, args...)
+}
+
+func (t *TenantPersister) WhereS(model interface{}, sortexpr string, expr string, args ...interface{}) (interface{}, error) {
+	if err := t.checkExpr("where", model, expr, args...); err != nil {
+		return nil, err
+	}
+	return t.pst.WhereS(model, sortexpr, expr, args...)
+}
+
+func (t *TenantPersister) WhereP(model interface{}, pageLimit int, page int, expr string, args ...interface{}) (interface{}, error) {
+	if err := t.checkExpr("where", model, expr, args...); err != nil {
+		return nil, err
+	}
+	return t.pst.WhereP(model, pageLimit, page, expr, args...)
+}
+
+func (t *TenantPersister) Where(model interface{}, expr string, args ...interface{}) (interface{}, error) {
+	if err := t.checkExpr("where", model, expr, args...); err != nil {
+		return nil, err
+	}
+	return t.pst.Where(model, expr, args...)
+}
+
+// FindOne find by id column only, it is scoped when the id column is the shop column
+func (t *TenantPersister) FindOne(model interface{}, idColumn string, id string) (interface{}, error) {
+	if err := t.checkWhere("find one", model, map[string]interface{}{idColumn: id}); err != nil {
+		return nil, err
+	}
+	return t.pst.FindOne(model, idColumn, id)
+}
+
+func (t *TenantPersister) First(model interface{}, query interface{}, args ...interface{}) (interface{}, error) {
+	var err error
+	switch q := query.(type) {
+	case string:
+		err = t.checkExpr("first", model, q, args...)
+	case map[string]interface{}:
+		err = t.checkWhere("first", model, q)
+	default:
+		err = t.checkRows("first", query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return t.pst.First(model, query, args...)
+}
+
+func (t *TenantPersister) Create(model interface{}) error {
+	if err := t.checkRows("create", model); err != nil {
+		return err
+	}
+	return t.pst.Create(model)
+}
+
+func (t *TenantPersister) Update(model interface{}, where map[string]interface{}) error {
+	if err := t.checkWhere("update", model, where); err != nil {
+		return err
+	}
+	return t.pst.Update(model, where)
+}
+
+func (t *TenantPersister) Delete(model interface{}, where map[string]interface{}) error {
+	if err := t.checkWhere("delete", model, where); err != nil {
+		return err
+	}
+	return t.pst.Delete(model, where)
+}
+
+func (t *TenantPersister) CreateInBatch(models interface{}, bulkSize int) error {
+	if err := t.checkRows("create in batch", models); err != nil {
+		return err
+	}
+	return t.pst.CreateInBatch(models, bulkSize)
+}
+
+func (t *TenantPersister) CreateInBatchOnConflict(models interface{}, bulkSize int) error {
+	if err := t.checkRows("create in batch", models); err != nil {
+		return err
+	}
+	return t.pst.CreateInBatchOnConflict(models, bulkSize)
+}
+
+func (t *TenantPersister) CreateInBatchClauses(models interface{}, bulkSize int, expression ...clause.Expression) error {
+	if err := t.checkRows("create in batch", models); err != nil {
+		return err
+	}
+	return t.pst.CreateInBatchClauses(models, bulkSize, expression...)
+}
+
+// Exec check select, update and delete statements, the table is not known so every statement must be scoped
+func (t *TenantPersister) Exec(sql string, args ...interface{}) error {
+	if sqlDMLStatement.MatchString(sql) {
+		if _, ok := SQLShopID(sql, args...); !ok {
+			if err := t.policy.violate("exec", "sql", ErrUnscopedQuery); err != nil {
+				return err
+			}
+		}
+	}
+	return t.pst.Exec(sql, args...)
+}
+
+func (t *TenantPersister) TableExists(model interface{}) (bool, error) {
+	return t.pst.TableExists(model)
+}
+
+func (t *TenantPersister) Count(model interface{}, expr string, args ...interface{}) (int64, error) {
+	if err := t.checkExpr("count", model, expr, args...); err != nil {
+		return 0, err
+	}
+	return t.pst.Count(model, expr, args...)
+}
+
+func (t *TenantPersister) DropTable(table ...interface{}) error {
+	return t.pst.DropTable(table...)
+}
+
+func (t *TenantPersister) SetupJoinTable(model interface{}, field string, joinTable interface{}) error {
+	return t.pst.SetupJoinTable(model, field, joinTable)
+}
+
+func (t *TenantPersister) AutoMigrate(dst ...interface{}) error {
+	return t.pst.AutoMigrate(dst...)
+}
+
+func (t *TenantPersister) TestConnect() error {
+	return t.pst.TestConnect()
+}
+
+// Transaction run the function on persister of transaction whose statements are checked like DBClient
+func (t *TenantPersister) Transaction(funcTransaction func(*Persister) error) error {
+	db := t.DBClient()
+	if db == nil {
+		return t.pst.Transaction(funcTransaction)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return funcTransaction(NewPersisterWithDB(tx))
+	})
+}
+
+func (t *TenantPersister) Raw(queryStr string, where map[string]interface{}, model interface{}) (interface{}, error) {
+	if err := t.checkExpr("raw", model, queryStr, where); err != nil {
+		return nil, err
+	}
+	return t.pst.Raw(queryStr, where, model)
+}
+
+// DBClient return gorm client whose statements are checked, statement of table with shop column must have
+// equality of shop column in where and created rows must have shop id, raw select, update and delete must be scoped.
+// Statement of table which is only named e.g. Table("name") is not checked
+func (t *TenantPersister) DBClient() *gorm.DB {
+	db := t.pst.DBClient()
+	if db == nil {
+		return nil
+	}
+
+	registerTenantCallbacks(db)
+	return db.Set(tenantPolicySetting, t.policy).Session(&gorm.Session{})
+}
+
+const (
+	// tenantPolicySetting is gorm setting of statements which are made by DBClient of tenant persister
+	tenantPolicySetting = "tenant:policy"
+	// tenantScopedSetting is set on checked query, preload of the query is scoped by the query
+	tenantScopedSetting = "tenant:scoped"
+)
+
+var tenantCallbackConfigs sync.Map
+
+// registerTenantCallbacks add the check before statements of the gorm client once, the check is skipped
+// for statements which are not made by DBClient of tenant persister
+func registerTenantCallbacks(db *gorm.DB) {
+	if _, registered := tenantCallbackConfigs.LoadOrStore(db.Config, true); registered {
+		return
+	}
+
+	callbacks := db.Callback()
+	callbacks.Query().Before("gorm:query").Register("tenant:query", checkTenantStatement("query"))
+	callbacks.Row().Before("gorm:row").Register("tenant:row", checkTenantStatement("row"))
+	callbacks.Update().Before("gorm:update").Register("tenant:update", checkTenantStatement("update"))
+	callbacks.Delete().Before("gorm:delete").Register("tenant:delete", checkTenantStatement("delete"))
+	callbacks.Raw().Before("gorm:raw").Register("tenant:raw", checkTenantStatement("exec"))
+	callbacks.Create().Before("gorm:create").Register("tenant:create", checkTenantRows("create"))
+}
+
+func checkTenantStatement(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.Get(tenantPolicySetting)
+		if !ok || db.Error != nil {
+			return
+		}
+
+		if _, scoped := db.Get(tenantScopedSetting); scoped {
+			return
+		}
+
+		stmt := db.Statement
+		target := stmt.Table
+		scoped := true
+
+		switch {
+		case stmt.SQL.Len() > 0:
+			// raw statement, the table is not known so every select, update and delete must be scoped
+			target = "sql"
+			if sql := stmt.SQL.String(); sqlDMLStatement.MatchString(sql) {
+				_, scoped = SQLShopID(sql, stmt.Vars...)
+			}
+		case stmt.Schema != nil && schemaHasShop(stmt.Schema):
+			scoped = whereHasShop(stmt)
+		}
+
+		if !scoped {
+			if err := value.(tenantPolicy).violate(op, target, ErrUnscopedQuery); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+
+		stmt.Settings.Store(tenantScopedSetting, true)
+	}
+}
+
+func checkTenantRows(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.Get(tenantPolicySetting)
+		if !ok || db.Error != nil || !IsTenantTable(db.Statement.Dest) {
+			return
+		}
+
+		for _, shopID := range rowShopIDs(db.Statement.Dest) {
+			if shopID == "" {
+				if err := value.(tenantPolicy).violate(op, db.Statement.Table, ErrUnscopedQuery); err != nil {
+					db.AddError(err)
+				}
+				return
+			}
+		}
+	}
+}
+
+func schemaHasShop(s *schema.Schema) bool {
+	return s.LookUpField("shopid") != nil || s.LookUpField("shop_id") != nil || s.LookUpField("ShopID") != nil
+}
+
+// whereHasShop return true when where of the statement has equality of shop column with non empty value
+func whereHasShop(stmt *gorm.Statement) bool {
+	where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok {
+		return false
+	}
+
+	return exprsHaveShop(where.Exprs)
+}
+
+func exprsHaveShop(exprs []clause.Expression) bool {
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case clause.Eq:
+			if shopID, ok := e.Value.(string); ok && shopID != "" && isShopColumn(e.Column) {
+				return true
+			}
+		case clause.Expr:
+			if _, ok := SQLShopID(e.SQL, e.Vars...); ok {
+				return true
+			}
+		case clause.NamedExpr:
+			if _, ok := SQLShopID(e.SQL, e.Vars...); ok {
+				return true
+			}
+		case clause.AndConditions:
+			if exprsHaveShop(e.Exprs) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isShopColumn(column interface{}) bool {
+	name := ""
+	switch c := column.(type) {
+	case string:
+		name = c
+	case clause.Column:
+		name = c.Name
+	}
+
+	name = strings.ToLower(name[strings.LastIndex(name, ".")+1:])
+	return name == "shopid" || name == "shop_id"
+}
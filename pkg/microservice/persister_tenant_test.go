@@ -0,0 +1,244 @@
+package microservice_test
+
+import (
+	"context"
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/tenanttest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type tenantTestIdentity struct {
+	ShopID string `bson:"shopid"`
+}
+
+type tenantTestDoc struct {
+	tenantTestIdentity `bson:"inline"`
+	GuidFixed          string `bson:"guidfixed"`
+	Name               string `bson:"name"`
+}
+
+func (tenantTestDoc) CollectionName() string {
+	return "tenantTestDocs"
+}
+
+type systemTestDoc struct {
+	Name string `bson:"name"`
+}
+
+func (systemTestDoc) CollectionName() string {
+	return "systemTestDocs"
+}
+
+type tenantTestRow struct {
+	ShopID string `gorm:"column:shopid"`
+	Code   string `gorm:"column:code"`
+}
+
+func (tenantTestRow) TableName() string {
+	return "tenant_test_rows"
+}
+
+func TestIsTenantModel(t *testing.T) {
+	assert.True(t, microservice.IsTenantModel(&tenantTestDoc{}))
+	assert.True(t, microservice.IsTenantModel(&[]tenantTestDoc{}))
+	assert.False(t, microservice.IsTenantModel(&systemTestDoc{}))
+	assert.False(t, microservice.IsTenantModel(nil))
+}
+
+func TestFilterShopIDs(t *testing.T) {
+	assert.Equal(t, []string{"shop1"}, microservice.FilterShopIDs(bson.M{"shopid": "shop1", "name": "a"}))
+	assert.Equal(t, []string{"shop1"}, microservice.FilterShopIDs(map[string]interface{}{"shopid": bson.M{"$eq": "shop1"}}))
+	assert.Equal(t, []string{"shop1"}, microservice.FilterShopIDs(bson.M{"$and": []interface{}{bson.M{"shopid": "shop1"}}}))
+	assert.Empty(t, microservice.FilterShopIDs(bson.M{"shopid": bson.M{"$in": []string{"shop1", "shop2"}}}))
+	assert.Empty(t, microservice.FilterShopIDs(bson.M{"name": "a"}))
+}
+
+func TestTenantPersisterMongo_Enforce(t *testing.T) {
+	recorder := &tenanttest.MongoRecorder{}
+	pst := microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationEnforce)
+	ctx := context.Background()
+
+	err := pst.Find(ctx, &tenantTestDoc{}, bson.M{"name": "a"}, &[]tenantTestDoc{})
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.Find(ctx, &tenantTestDoc{}, bson.M{"shopid": "shop1"}, &[]tenantTestDoc{})
+	assert.NoError(t, err)
+
+	err = pst.Find(microservice.WithTenantShopID(ctx, "shop1"), &tenantTestDoc{}, bson.M{"shopid": "shop2"}, &[]tenantTestDoc{})
+	assert.True(t, errors.Is(err, microservice.ErrCrossTenantQuery))
+
+	err = pst.UpdateOne(ctx, &tenantTestDoc{}, map[string]interface{}{"shopid": "shop1"}, bson.M{"shopid": "shop2"})
+	assert.True(t, errors.Is(err, microservice.ErrCrossTenantQuery))
+
+	_, err = pst.Create(ctx, &tenantTestDoc{}, tenantTestDoc{})
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.DeleteByID(ctx, &tenantTestDoc{}, "id1")
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	_, err = pst.Create(ctx, &tenantTestDoc{}, &tenantTestDoc{tenantTestIdentity: tenantTestIdentity{ShopID: "shop1"}})
+	assert.NoError(t, err)
+
+	err = pst.Update(ctx, &tenantTestDoc{}, bson.M{"shopid": "shop1"}, bson.M{"$set": tenantTestDoc{tenantTestIdentity: tenantTestIdentity{ShopID: "shop2"}}})
+	assert.True(t, errors.Is(err, microservice.ErrCrossTenantQuery))
+
+	err = pst.Find(microservice.WithSystemAdmin(ctx), &tenantTestDoc{}, bson.M{}, &[]tenantTestDoc{})
+	assert.NoError(t, err)
+
+	err = pst.Find(ctx, &systemTestDoc{}, bson.M{}, &[]systemTestDoc{})
+	assert.NoError(t, err)
+
+	assert.Len(t, recorder.Calls, 4)
+}
+
+func TestTenantPersisterMongo_Exec(t *testing.T) {
+	pst := microservice.NewTenantPersisterMongo(&tenanttest.MongoRecorder{}, microservice.TenantIsolationEnforce)
+	ctx := microservice.WithTenantShopID(context.Background(), "shop1")
+
+	_, err := pst.Exec(ctx, &tenantTestDoc{})
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	_, err = pst.Exec(microservice.WithCollectionShopID(ctx, "shop2"), &tenantTestDoc{})
+	assert.True(t, errors.Is(err, microservice.ErrCrossTenantQuery))
+
+	// the recorder refuse exec after the check is passed
+	_, err = pst.Exec(microservice.WithCollectionShopID(ctx, "shop1"), &tenantTestDoc{})
+	assert.False(t, errors.Is(err, microservice.ErrUnscopedQuery) || errors.Is(err, microservice.ErrCrossTenantQuery))
+}
+
+func TestTenantPersisterMongo_ScopeByContext(t *testing.T) {
+	recorder := &tenanttest.MongoRecorder{}
+	pst := microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationEnforce)
+	ctx := microservice.WithTenantShopID(context.Background(), "shop1")
+
+	require.NoError(t, pst.Find(ctx, &tenantTestDoc{}, bson.M{"name": "a"}, &[]tenantTestDoc{}))
+	require.NoError(t, pst.Aggregate(ctx, &tenantTestDoc{}, []interface{}{
+		bson.M{"$search": bson.M{"text": "a"}},
+		bson.M{"$sort": bson.M{"name": 1}},
+	}, &[]tenantTestDoc{}))
+
+	require.Len(t, recorder.Calls, 2)
+	assert.Equal(t, []string{"shop1"}, microservice.FilterShopIDs(recorder.Calls[0].Filter))
+	assert.Equal(t, []string{"shop1"}, microservice.PipelineShopIDs(recorder.Calls[1].Pipeline))
+}
+
+func TestTenantPersisterMongo_Report(t *testing.T) {
+	logger.NewAppLogger(config.NewLoggerConfig())
+
+	recorder := &tenanttest.MongoRecorder{}
+	pst := microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationReport)
+
+	err := pst.Find(context.Background(), &tenantTestDoc{}, bson.M{"name": "a"}, &[]tenantTestDoc{})
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Calls, 1)
+
+	// query without shop of the context is logged and left unchanged
+	ctx := microservice.WithTenantShopID(context.Background(), "shop1")
+	require.NoError(t, pst.Find(ctx, &tenantTestDoc{}, bson.M{"name": "a"}, &[]tenantTestDoc{}))
+	require.NoError(t, pst.Aggregate(ctx, &tenantTestDoc{}, []interface{}{
+		bson.M{"$search": bson.M{"text": "a"}},
+	}, &[]tenantTestDoc{}))
+	require.NoError(t, pst.Find(ctx, &tenantTestDoc{}, bson.M{"shopid": "shop2"}, &[]tenantTestDoc{}))
+
+	require.Len(t, recorder.Calls, 4)
+	assert.Equal(t, bson.M{"name": "a"}, recorder.Calls[1].Filter)
+	assert.Empty(t, microservice.PipelineShopIDs(recorder.Calls[2].Pipeline))
+	assert.Equal(t, bson.M{"shopid": "shop2"}, recorder.Calls[3].Filter)
+}
+
+func TestSQLShopID(t *testing.T) {
+	shopID, ok := microservice.SQLShopID("code = ? AND shopid = ?", "c1", "shop1")
+	assert.True(t, ok)
+	assert.Equal(t, "shop1", shopID)
+
+	shopID, ok = microservice.SQLShopID("SELECT * FROM t WHERE t.shop_id = @shopid", map[string]interface{}{"shopid": "shop1"})
+	assert.True(t, ok)
+	assert.Equal(t, "shop1", shopID)
+
+	_, ok = microservice.SQLShopID("code = ?", "c1")
+	assert.False(t, ok)
+
+	_, ok = microservice.SQLShopID("shopid = ?", "")
+	assert.False(t, ok)
+
+	_, ok = microservice.SQLShopID("parentshopid = ?", "shop1")
+	assert.False(t, ok)
+}
+
+func TestTenantPersister_Enforce(t *testing.T) {
+	recorder := &tenanttest.SQLRecorder{}
+	pst := microservice.NewTenantPersister(recorder, microservice.TenantIsolationEnforce)
+
+	_, err := pst.Where(&tenantTestRow{}, "code = ?", "c1")
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	_, err = pst.Where(&tenantTestRow{}, "shopid = ? AND code = ?", "shop1", "c1")
+	assert.NoError(t, err)
+
+	err = pst.Delete(&tenantTestRow{}, map[string]interface{}{"code": "c1"})
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.CreateInBatch([]tenantTestRow{{ShopID: "shop1"}, {}}, 10)
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.Exec("DELETE FROM tenant_test_rows WHERE code = ?", "c1")
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = microservice.SystemAdminPersister(pst).Exec("DELETE FROM tenant_test_rows WHERE code = ?", "c1")
+	assert.NoError(t, err)
+
+	assert.Len(t, recorder.Calls, 2)
+}
+
+func TestTenantPersister_DBClient(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: gormlogger.Discard})
+	require.NoError(t, err)
+
+	pst := microservice.NewTenantPersister(microservice.NewPersisterWithDB(db), microservice.TenantIsolationEnforce)
+
+	err = pst.DBClient().Where("code = ?", "c1").Find(&[]tenantTestRow{}).Error
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.DBClient().Where("shopid = ? AND code = ?", "shop1", "c1").Find(&[]tenantTestRow{}).Error
+	assert.NoError(t, err)
+
+	err = pst.DBClient().Delete(&tenantTestRow{}, map[string]interface{}{"code": "c1"}).Error
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.DBClient().Delete(&tenantTestRow{}, map[string]interface{}{"shopid": "shop1", "code": "c1"}).Error
+	assert.NoError(t, err)
+
+	err = pst.DBClient().Exec("UPDATE tenant_test_rows SET code = ? WHERE code = ?", "c2", "c1").Error
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	err = pst.DBClient().Create(&tenantTestRow{Code: "c1"}).Error
+	assert.True(t, errors.Is(err, microservice.ErrUnscopedQuery))
+
+	// statements of the persister which is not tenant persister are not checked
+	err = microservice.SystemAdminPersister(pst).DBClient().Where("code = ?", "c1").Find(&[]tenantTestRow{}).Error
+	assert.NoError(t, err)
+}
+
+func TestTenanttest_FindLeak(t *testing.T) {
+	leaky := func(pst microservice.IPersisterMongo, shopID string) error {
+		return pst.Find(microservice.WithSystemAdmin(context.Background()), &tenantTestDoc{}, bson.M{"name": shopID}, &[]tenantTestDoc{})
+	}
+
+	mockT := &testing.T{}
+	tenanttest.CheckMongoScoped(mockT, "shop1", leaky)
+	assert.True(t, mockT.Failed())
+
+	tenanttest.AssertMongoScoped(t, func(pst microservice.IPersisterMongo, shopID string) error {
+		return pst.Find(context.Background(), &tenantTestDoc{}, bson.M{"shopid": shopID}, &[]tenantTestDoc{})
+	})
+}
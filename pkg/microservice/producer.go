@@ -76,9 +76,14 @@ func (p *Producer) SendMessage(topic string, key string, message interface{}) er
 		return err
 	}
 
-	<-deliveryChan
+	e := <-deliveryChan
 	close(deliveryChan)
 
+	// Delivery report contains error when message is not written to the broker
+	if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+		return m.TopicPartition.Error
+	}
+
 	return nil
 }
 
@@ -0,0 +1,91 @@
+package microservice
+
+import (
+	"context"
+	"smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	AuthTypeBearer    = "bearer"
+	AuthTypeWebSocket = "websocket"
+	AuthTypeXApiKey   = "xapikey"
+	AuthTypeApiKey    = "apikey"
+)
+
+// RequestActor is who make the request, it is recorded with changes of documents
+type RequestActor struct {
+	Username string
+	ShopID   string
+	IP       string
+	AuthType string
+	ApiKeyID string
+}
+
+type requestActorContextKey struct{}
+
+// WithRequestActor return context which carry the actor to repositories
+func WithRequestActor(ctx context.Context, actor RequestActor) context.Context {
+	return context.WithValue(ctx, requestActorContextKey{}, actor)
+}
+
+// RequestActorFromContext return actor of WithRequestActor
+func RequestActorFromContext(ctx context.Context) (RequestActor, bool) {
+	if ctx == nil {
+		return RequestActor{}, false
+	}
+
+	actor, ok := ctx.Value(requestActorContextKey{}).(RequestActor)
+	return actor, ok
+}
+
+// RequestActorContext return background context which carry the actor, the tenant shop and system admin of ctx,
+// services derive their timeout from it so the work is not canceled with the request
+func RequestActorContext(ctx context.Context) context.Context {
+	actorCtx := context.Background()
+
+	if actor, ok := RequestActorFromContext(ctx); ok {
+		actorCtx = WithRequestActor(actorCtx, actor)
+	}
+
+	if shopID := TenantShopIDFromContext(ctx); shopID != "" {
+		actorCtx = WithTenantShopID(actorCtx, shopID)
+	}
+
+	if IsSystemAdmin(ctx) {
+		actorCtx = WithSystemAdmin(actorCtx)
+	}
+
+	return actorCtx
+}
+
+// setRequestActor put the actor of the authenticated request into the request context,
+// queries of tenant persisters are scoped to the shop of the user
+func setRequestActor(c echo.Context, userInfo models.UserInfo, authType string) {
+	actor := RequestActor{
+		Username: userInfo.Username,
+		ShopID:   userInfo.ShopID,
+		IP:       c.RealIP(),
+		AuthType: authType,
+		ApiKeyID: userInfo.ApiKeyID,
+	}
+
+	ctx := WithRequestActor(c.Request().Context(), actor)
+	if userInfo.ShopID != "" {
+		ctx = WithTenantShopID(ctx, userInfo.ShopID)
+	}
+
+	c.SetRequest(c.Request().WithContext(ctx))
+}
+
+func authTypeName(tokenType TokenType) string {
+	switch tokenType {
+	case AUTHTYPE_WEBSOCKET:
+		return AuthTypeWebSocket
+	case AUTHTYPE_XAPIKEY:
+		return AuthTypeXApiKey
+	default:
+		return AuthTypeBearer
+	}
+}
@@ -0,0 +1,45 @@
+package microservice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"smlaicloudplatform/pkg/microservice/models"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddlewareSetRequestActor(t *testing.T) {
+	cacher := newHashCacherStub()
+	authService := NewAuthService(cacher, time.Hour, 24*time.Hour)
+
+	userInfo := models.UserInfo{Username: "user01", Name: "user01"}
+	posToken, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+	webToken, _ := authService.GenerateTokenWithRedis(AUTHTYPE_BEARER, userInfo)
+	assert.Nil(t, authService.SelectShop(AUTHTYPE_BEARER, posToken, "SHOP01", 0))
+	assert.Nil(t, authService.SelectShop(AUTHTYPE_BEARER, webToken, "SHOP01", 0))
+
+	e := echo.New()
+	e.Use(authService.MWFuncWithRedis(cacher))
+	e.GET("/actor", func(c echo.Context) error {
+		actor, ok := RequestActorFromContext(NewHTTPContext(nil, c).Context())
+		assert.True(t, ok)
+		assert.Equal(t, "SHOP01", TenantShopIDFromContext(RequestActorContext(c.Request().Context())))
+		return c.JSON(http.StatusOK, actor)
+	})
+
+	// each request of the same user carry its own ip
+	for token, ip := range map[string]string{posToken: "203.0.113.10", webToken: "203.0.113.20"} {
+		req := httptest.NewRequest(http.MethodGet, "/actor", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.RemoteAddr = ip + ":5000"
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"Username":"user01","ShopID":"SHOP01","IP":"`+ip+`","AuthType":"bearer","ApiKeyID":""}`, rec.Body.String())
+	}
+}
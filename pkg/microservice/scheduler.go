@@ -0,0 +1,568 @@
+package microservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"smlaicloudplatform/internal/logger"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// scheduleLeaderTTL is lease of the leader, other replica take over after it is expired
+	scheduleLeaderTTL = 30 * time.Second
+	// scheduleLoopInterval is how often the lease is renewed and due schedules are checked
+	scheduleLoopInterval = 10 * time.Second
+	// scheduleMaxCatchUp is how far back new leader run schedules which are missed while there is no leader
+	scheduleMaxCatchUp = 5 * time.Minute
+	// scheduleRunLockTTL keep fire time of a schedule so it is run once even when the leader is changed
+	scheduleRunLockTTL = 24 * time.Hour
+	// scheduleShopsRefresh is how long shop list of per shop schedule is cached
+	scheduleShopsRefresh = 10 * time.Minute
+	// scheduleDefaultTimeout is timeout of handler when WithScheduleTimeout is not given
+	scheduleDefaultTimeout = time.Hour
+)
+
+const (
+	ScheduleRunRunning   = "running"
+	ScheduleRunSucceeded = "succeeded"
+	ScheduleRunFailed    = "failed"
+	ScheduleRunSkipped   = "skipped"
+)
+
+// ScheduleHandler do the scheduled work, ctx is cancelled on timeout or when the service is stopped
+type ScheduleHandler func(ctx context.Context, run ScheduleRun) error
+
+// ScheduleShop is shop of per shop schedule, the schedule is evaluated in location of the shop
+type ScheduleShop struct {
+	ShopID   string
+	Location *time.Location
+}
+
+// ScheduleShopsFunc return shops of per shop schedule
+type ScheduleShopsFunc func(ctx context.Context) ([]ScheduleShop, error)
+
+// ScheduleRun is one run of the schedule, it is passed to the handler and kept in run history
+type ScheduleRun struct {
+	RunID       string     `json:"runid" bson:"runid"`
+	Name        string     `json:"name" bson:"name"`
+	ShopID      string     `json:"shopid,omitempty" bson:"shopid,omitempty"`
+	ScheduledAt time.Time  `json:"scheduledat" bson:"scheduledat"`
+	StartedAt   time.Time  `json:"startedat" bson:"startedat"`
+	FinishedAt  *time.Time `json:"finishedat,omitempty" bson:"finishedat,omitempty"`
+	Status      string     `json:"status" bson:"status"`
+	Error       string     `json:"error,omitempty" bson:"error,omitempty"`
+	Replica     string     `json:"replica" bson:"replica"`
+}
+
+// IScheduleHistory keep run history of schedules, SaveRun is called when the run is started and finished
+type IScheduleHistory interface {
+	SaveRun(ctx context.Context, run ScheduleRun) error
+}
+
+// IScheduleRegistry keep registered schedules where replica which does not run the scheduler can read them,
+// SaveSchedules is called with every registered schedule when the scheduler is started
+type IScheduleRegistry interface {
+	SaveSchedules(ctx context.Context, schedules []ScheduleInfo) error
+}
+
+// ScheduleInfo is registered schedule
+type ScheduleInfo struct {
+	Name      string    `json:"name" bson:"name"`
+	Spec      string    `json:"spec" bson:"spec"`
+	Location  string    `json:"location" bson:"location"`
+	PerShop   bool      `json:"pershop" bson:"pershop"`
+	NextRunAt time.Time `json:"nextrunat" bson:"-"`
+}
+
+// Next return next run time of the schedule after now
+func (info ScheduleInfo) Next(now time.Time) (time.Time, error) {
+	schedule, err := ParseCron(info.Spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := time.LoadLocation(info.Location)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return schedule.Next(now.In(location)), nil
+}
+
+// ScheduleOption configure the schedule
+type ScheduleOption func(entry *scheduleEntry)
+
+// WithScheduleLocation evaluate the schedule in the location, default is local time of the server,
+// per shop schedule use it for shop which does not have location
+func WithScheduleLocation(location *time.Location) ScheduleOption {
+	return func(entry *scheduleEntry) {
+		entry.location = location
+	}
+}
+
+// WithScheduleTimeout set timeout of the handler
+func WithScheduleTimeout(timeout time.Duration) ScheduleOption {
+	return func(entry *scheduleEntry) {
+		entry.timeout = timeout
+	}
+}
+
+type scheduleEntry struct {
+	name     string
+	spec     string
+	schedule *CronSchedule
+	location *time.Location
+	timeout  time.Duration
+	handler  ScheduleHandler
+
+	shops         ScheduleShopsFunc
+	shopsCache    []ScheduleShop
+	shopsLoadedAt time.Time
+}
+
+// Scheduler run cron schedules on one replica at a time, the replica which hold leader lock in the locker
+// check due schedules every minute, fire time of each run is also locked so it is not run twice
+// when the leader is changed
+type Scheduler struct {
+	locker    ILocker
+	history   IScheduleHistory
+	registry  IScheduleRegistry
+	logger    logger.ILogger
+	leaderKey string
+	replica   string
+	now       func() time.Time
+
+	mutex    sync.Mutex
+	entries  []*scheduleEntry
+	running  map[string]struct{}
+	leader   ILock
+	lastTick time.Time
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started bool
+	wg      sync.WaitGroup
+}
+
+// NewScheduler return scheduler, every replica run the schedules when locker is nil
+func NewScheduler(locker ILocker, history IScheduleHistory, logger logger.ILogger, leaderKey string) *Scheduler {
+	replica, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		locker:    locker,
+		history:   history,
+		logger:    logger,
+		leaderKey: leaderKey,
+		replica:   replica,
+		now:       time.Now,
+		running:   map[string]struct{}{},
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+}
+
+// SetHistory set store of run history
+func (s *Scheduler) SetHistory(history IScheduleHistory) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.history = history
+}
+
+// SetRegistry set store of registered schedules
+func (s *Scheduler) SetRegistry(registry IScheduleRegistry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.registry = registry
+}
+
+// Add register schedule which run once per fire time
+func (s *Scheduler) Add(spec string, name string, handler ScheduleHandler, opts ...ScheduleOption) error {
+	return s.add(spec, name, nil, handler, opts...)
+}
+
+// AddShops register schedule which run for each shop at fire time in location of the shop
+func (s *Scheduler) AddShops(spec string, name string, shops ScheduleShopsFunc, handler ScheduleHandler, opts ...ScheduleOption) error {
+	return s.add(spec, name, shops, handler, opts...)
+}
+
+func (s *Scheduler) add(spec string, name string, shops ScheduleShopsFunc, handler ScheduleHandler, opts ...ScheduleOption) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+
+	entry := &scheduleEntry{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		location: time.Local,
+		timeout:  scheduleDefaultTimeout,
+		handler:  handler,
+		shops:    shops,
+	}
+
+	for _, opt := range opts {
+		opt(entry)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, registered := range s.entries {
+		if registered.name == name {
+			return fmt.Errorf("schedule %s is already registered", name)
+		}
+	}
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Schedules return registered schedules order by name
+func (s *Scheduler) Schedules() []ScheduleInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.infos()
+}
+
+func (s *Scheduler) infos() []ScheduleInfo {
+	now := s.now()
+	infos := []ScheduleInfo{}
+	for _, entry := range s.entries {
+		infos = append(infos, ScheduleInfo{
+			Name:      entry.name,
+			Spec:      entry.spec,
+			Location:  entry.location.String(),
+			PerShop:   entry.shops != nil,
+			NextRunAt: entry.schedule.Next(now.In(entry.location)),
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// Start run the scheduler loop in background when there is registered schedule
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.started || len(s.entries) == 0 {
+		return
+	}
+	s.started = true
+
+	go s.saveSchedules(s.registry, s.infos())
+	go s.loop()
+}
+
+// Stop release leader lock, cancel running handlers and wait for them
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	started := s.started
+	s.mutex.Unlock()
+
+	if !started {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+	s.wg.Wait()
+
+	if s.leader != nil {
+		s.leader.Unlock()
+		s.leader = nil
+	}
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(scheduleLoopInterval)
+	defer ticker.Stop()
+
+	for {
+		now := s.now()
+		if s.elect(now) {
+			s.tick(now)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect renew leader lease or try to become the leader, it return true when this replica is the leader
+func (s *Scheduler) elect(now time.Time) bool {
+	if s.locker == nil {
+		if s.lastTick.IsZero() {
+			s.lastTick = now.Truncate(time.Minute).Add(-time.Minute)
+		}
+		return true
+	}
+
+	if s.leader != nil {
+		err := s.leader.Refresh(scheduleLeaderTTL)
+		if err == nil {
+			return true
+		}
+
+		s.logger.Warnf("Scheduler %s lost leader lease: %v", s.replica, err)
+		s.leader = nil
+	}
+
+	lock, ok, err := s.locker.TryLock(s.leaderKey, scheduleLeaderTTL)
+	if err != nil {
+		s.logger.Errorf("Scheduler %s cannot acquire leader lock: %v", s.replica, err)
+		return false
+	}
+
+	if !ok {
+		return false
+	}
+
+	s.logger.Infof("Scheduler %s become leader", s.replica)
+	s.leader = lock
+	// run schedules which are missed while there is no leader, runs of previous leader are skipped by run lock
+	s.lastTick = now.Truncate(time.Minute).Add(-scheduleMaxCatchUp)
+	return true
+}
+
+// tick fire schedules of every minute since the last tick
+func (s *Scheduler) tick(now time.Time) {
+	minute := now.Truncate(time.Minute)
+	if minute.Sub(s.lastTick) > scheduleMaxCatchUp {
+		s.lastTick = minute.Add(-scheduleMaxCatchUp)
+	}
+
+	for at := s.lastTick.Add(time.Minute); !at.After(minute); at = at.Add(time.Minute) {
+		s.fire(at)
+	}
+
+	s.lastTick = minute
+}
+
+func (s *Scheduler) fire(at time.Time) {
+	s.mutex.Lock()
+	entries := append([]*scheduleEntry{}, s.entries...)
+	s.mutex.Unlock()
+
+	for _, entry := range entries {
+		if entry.shops == nil {
+			if entry.schedule.Match(at.In(entry.location)) {
+				s.launch(entry, "", at)
+			}
+			continue
+		}
+
+		shops, err := s.loadShops(entry)
+		if err != nil {
+			s.logger.Errorf("Schedule %s cannot load shops: %v", entry.name, err)
+			continue
+		}
+
+		for _, shop := range shops {
+			location := shop.Location
+			if location == nil {
+				location = entry.location
+			}
+
+			if entry.schedule.Match(at.In(location)) {
+				s.launch(entry, shop.ShopID, at)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) loadShops(entry *scheduleEntry) ([]ScheduleShop, error) {
+	if entry.shopsCache != nil && s.now().Sub(entry.shopsLoadedAt) < scheduleShopsRefresh {
+		return entry.shopsCache, nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+	defer cancel()
+
+	shops, err := entry.shops(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.shopsCache = shops
+	entry.shopsLoadedAt = s.now()
+	return shops, nil
+}
+
+// launch run the handler in background once per fire time, the run is skipped while the previous run is not finished
+func (s *Scheduler) launch(entry *scheduleEntry, shopID string, at time.Time) {
+	key := entry.name
+	if shopID != "" {
+		key += ":" + shopID
+	}
+
+	if s.locker != nil {
+		_, ok, err := s.locker.TryLock(fmt.Sprintf("scheduler:run:%s:%d", key, at.Unix()), scheduleRunLockTTL)
+		if err != nil {
+			s.logger.Errorf("Schedule %s cannot lock run at %s: %v", key, at, err)
+			return
+		}
+
+		if !ok {
+			// already run by other leader
+			return
+		}
+	}
+
+	run := ScheduleRun{
+		RunID:       NewUUID(),
+		Name:        entry.name,
+		ShopID:      shopID,
+		ScheduledAt: at,
+		StartedAt:   s.now(),
+		Status:      ScheduleRunRunning,
+		Replica:     s.replica,
+	}
+
+	s.mutex.Lock()
+	_, isRunning := s.running[key]
+	if !isRunning {
+		s.running[key] = struct{}{}
+	}
+	s.mutex.Unlock()
+
+	if isRunning {
+		run.Status = ScheduleRunSkipped
+		run.Error = "previous run is not finished"
+		run.FinishedAt = &run.StartedAt
+		s.saveRun(run)
+		return
+	}
+
+	s.saveRun(run)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mutex.Lock()
+			delete(s.running, key)
+			s.mutex.Unlock()
+		}()
+
+		err := s.execute(entry, run)
+
+		finishedAt := s.now()
+		run.FinishedAt = &finishedAt
+		run.Status = ScheduleRunSucceeded
+		if err != nil {
+			s.logger.Errorf("Schedule %s failed: %v", key, err)
+			run.Status = ScheduleRunFailed
+			run.Error = err.Error()
+		}
+
+		s.saveRun(run)
+	}()
+}
+
+func (s *Scheduler) execute(entry *scheduleEntry, run ScheduleRun) (err error) {
+	ctx, cancel := context.WithTimeout(s.ctx, entry.timeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("schedule panic: %v", recovered)
+		}
+	}()
+
+	return entry.handler(ctx, run)
+}
+
+func (s *Scheduler) saveSchedules(registry IScheduleRegistry, schedules []ScheduleInfo) {
+	if registry == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := registry.SaveSchedules(ctx, schedules)
+	if err != nil {
+		s.logger.Errorf("Scheduler %s cannot save schedules: %v", s.replica, err)
+	}
+}
+
+func (s *Scheduler) saveRun(run ScheduleRun) {
+	s.mutex.Lock()
+	history := s.history
+	s.mutex.Unlock()
+
+	if history == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := history.SaveRun(ctx, run)
+	if err != nil {
+		s.logger.Errorf("Schedule %s cannot save run %s: %v", run.Name, run.RunID, err)
+	}
+}
+
+// Scheduler return scheduler of the microservice, leader is elected through the cacher of the config
+func (ms *Microservice) Scheduler() *Scheduler {
+	ms.schedulerMutex.Lock()
+	defer ms.schedulerMutex.Unlock()
+
+	if ms.scheduler == nil {
+		locker := ms.Locker(ms.config.CacherConfig())
+		ms.scheduler = NewScheduler(locker, nil, ms.Logger, "scheduler:leader:"+ms.config.ApplicationName())
+	}
+
+	return ms.scheduler
+}
+
+// Schedule register handler to run at cron spec on one replica, it is started by ms.Start
+func (ms *Microservice) Schedule(spec string, name string, h ScheduleHandler, opts ...ScheduleOption) error {
+	return ms.Scheduler().Add(spec, name, h, opts...)
+}
+
+// ScheduleShops register handler to run for each shop at cron spec in timezone of the shop
+func (ms *Microservice) ScheduleShops(spec string, name string, shops ScheduleShopsFunc, h ScheduleHandler, opts ...ScheduleOption) error {
+	return ms.Scheduler().AddShops(spec, name, shops, h, opts...)
+}
+
+func (ms *Microservice) startScheduler() {
+	ms.schedulerMutex.Lock()
+	scheduler := ms.scheduler
+	ms.schedulerMutex.Unlock()
+
+	if scheduler != nil {
+		scheduler.Start()
+	}
+}
+
+func (ms *Microservice) stopScheduler() {
+	ms.schedulerMutex.Lock()
+	scheduler := ms.scheduler
+	ms.schedulerMutex.Unlock()
+
+	if scheduler != nil {
+		scheduler.Stop()
+	}
+}
@@ -0,0 +1,191 @@
+package microservice
+
+import (
+	"context"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryScheduleHistory struct {
+	mutex sync.Mutex
+	runs  map[string]ScheduleRun
+}
+
+func newMemoryScheduleHistory() *memoryScheduleHistory {
+	return &memoryScheduleHistory{runs: map[string]ScheduleRun{}}
+}
+
+func (h *memoryScheduleHistory) SaveRun(ctx context.Context, run ScheduleRun) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.runs[run.RunID] = run
+	return nil
+}
+
+func (h *memoryScheduleHistory) list(status string) []ScheduleRun {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	runs := []ScheduleRun{}
+	for _, run := range h.runs {
+		if run.Status == status {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+func newTestScheduler(locker ILocker, history IScheduleHistory, now *time.Time) *Scheduler {
+	scheduler := NewScheduler(locker, history, logger.NewAppLogger(config.NewLoggerConfig()), "scheduler:leader:test")
+	scheduler.now = func() time.Time { return *now }
+	return scheduler
+}
+
+func TestSchedulerRunOnceOnLeader(t *testing.T) {
+	locker := NewMemoryLocker()
+	history := newMemoryScheduleHistory()
+	now := time.Date(2023, 1, 2, 3, 0, 20, 0, time.UTC)
+
+	mutex := sync.Mutex{}
+	runs := 0
+	handler := func(ctx context.Context, run ScheduleRun) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		runs++
+		return nil
+	}
+
+	replicas := []*Scheduler{
+		newTestScheduler(locker, history, &now),
+		newTestScheduler(locker, history, &now),
+	}
+
+	for _, scheduler := range replicas {
+		assert.Nil(t, scheduler.Add("0 3 * * *", "outbox-purge-sent", handler, WithScheduleLocation(time.UTC)))
+	}
+
+	assert.True(t, replicas[0].elect(now))
+	assert.False(t, replicas[1].elect(now), "only one replica is the leader")
+
+	replicas[0].tick(now)
+	replicas[0].tick(now)
+	replicas[0].wg.Wait()
+
+	// leader is changed, the new leader catch up missed runs but the run at 03:00 is not run again
+	replicas[0].leader.Unlock()
+	replicas[0].leader = nil
+	assert.True(t, replicas[1].elect(now))
+	replicas[1].tick(now)
+	replicas[1].wg.Wait()
+
+	assert.Equal(t, 1, runs)
+	assert.Len(t, history.list(ScheduleRunSucceeded), 1)
+}
+
+func TestSchedulerShopLocation(t *testing.T) {
+	history := newMemoryScheduleHistory()
+	// 03:00 in bangkok
+	now := time.Date(2023, 1, 1, 20, 0, 0, 0, time.UTC)
+	scheduler := newTestScheduler(nil, history, &now)
+
+	shops := func(ctx context.Context) ([]ScheduleShop, error) {
+		return []ScheduleShop{
+			{ShopID: "SHOP01", Location: time.FixedZone("+07:00", 7*60*60)},
+			{ShopID: "SHOP02", Location: nil},
+		}, nil
+	}
+
+	assert.Nil(t, scheduler.AddShops("0 3 * * *", "stock-snapshot", shops, func(ctx context.Context, run ScheduleRun) error {
+		return nil
+	}, WithScheduleLocation(time.UTC)))
+
+	assert.True(t, scheduler.elect(now))
+	scheduler.tick(now)
+	scheduler.wg.Wait()
+
+	runs := history.list(ScheduleRunSucceeded)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "SHOP01", runs[0].ShopID)
+	assert.Equal(t, now, runs[0].ScheduledAt)
+}
+
+func TestSchedulerSkipRunningAndFailed(t *testing.T) {
+	history := newMemoryScheduleHistory()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduler := newTestScheduler(nil, history, &now)
+
+	release := make(chan struct{})
+	assert.Nil(t, scheduler.Add("* * * * *", "slow", func(ctx context.Context, run ScheduleRun) error {
+		<-release
+		return nil
+	}))
+	assert.Nil(t, scheduler.Add("* * * * *", "broken", func(ctx context.Context, run ScheduleRun) error {
+		panic("nil map")
+	}))
+	assert.NotNil(t, scheduler.Add("* * * * *", "slow", nil), "name is already registered")
+
+	scheduler.elect(now)
+	scheduler.tick(now)
+	for i := 0; i < 200 && len(history.list(ScheduleRunFailed)) == 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	now = now.Add(time.Minute)
+	scheduler.tick(now)
+
+	close(release)
+	scheduler.wg.Wait()
+
+	skipped := history.list(ScheduleRunSkipped)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, "slow", skipped[0].Name)
+
+	failed := history.list(ScheduleRunFailed)
+	assert.Len(t, failed, 2)
+	assert.Equal(t, "schedule panic: nil map", failed[0].Error)
+	assert.Len(t, history.list(ScheduleRunSucceeded), 1)
+}
+
+type memoryScheduleRegistry struct {
+	saved chan []ScheduleInfo
+}
+
+func (r *memoryScheduleRegistry) SaveSchedules(ctx context.Context, schedules []ScheduleInfo) error {
+	r.saved <- schedules
+	return nil
+}
+
+func TestSchedulerSaveSchedulesOnStart(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	scheduler := newTestScheduler(nil, nil, &now)
+	registry := &memoryScheduleRegistry{saved: make(chan []ScheduleInfo, 1)}
+	scheduler.SetRegistry(registry)
+
+	bangkok, _ := time.LoadLocation("Asia/Bangkok")
+	assert.NoError(t, scheduler.Add("0 1 * * *", "daily", func(ctx context.Context, run ScheduleRun) error { return nil }, WithScheduleLocation(bangkok)))
+
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	var saved []ScheduleInfo
+	select {
+	case saved = <-registry.saved:
+	case <-time.After(5 * time.Second):
+		t.Fatal("schedules are not saved")
+	}
+
+	assert.Len(t, saved, 1)
+	assert.Equal(t, "daily", saved[0].Name)
+	assert.Equal(t, "Asia/Bangkok", saved[0].Location)
+
+	// replica which read the saved schedule get the same next run
+	next, err := saved[0].Next(now)
+	assert.NoError(t, err)
+	assert.True(t, next.Equal(time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,378 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"smlaicloudplatform/internal/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	TenantIsolationOff     = "off"
+	TenantIsolationReport  = "report"
+	TenantIsolationEnforce = "enforce"
+)
+
+// tenantField is field of shop id in mongo documents
+const tenantField = "shopid"
+
+var (
+	// ErrUnscopedQuery is returned when query on collection of shops is not scoped to one shop
+	ErrUnscopedQuery = errors.New("tenant isolation: query is not scoped to a shop")
+	// ErrCrossTenantQuery is returned when query or document is scoped to other shop than the shop of the context
+	ErrCrossTenantQuery = errors.New("tenant isolation: query is scoped to other shop")
+)
+
+type tenantShopIDContextKey struct{}
+type collectionShopIDContextKey struct{}
+type systemAdminContextKey struct{}
+
+// WithTenantShopID return context which scope queries of tenant persister to the shop, on enforce mode
+// shop id is added to filters which do not have it and filters of other shop are refused
+func WithTenantShopID(ctx context.Context, shopID string) context.Context {
+	return context.WithValue(ctx, tenantShopIDContextKey{}, shopID)
+}
+
+// TenantShopIDFromContext return shop id of WithTenantShopID
+func TenantShopIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	shopID, _ := ctx.Value(tenantShopIDContextKey{}).(string)
+	return shopID
+}
+
+// WithCollectionShopID return context which let Exec of tenant persister return collection of shops for queries
+// of the shop, queries on the collection are not checked so the repository must scope every filter to the shop
+func WithCollectionShopID(ctx context.Context, shopID string) context.Context {
+	return context.WithValue(ctx, collectionShopIDContextKey{}, shopID)
+}
+
+func collectionShopIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	shopID, _ := ctx.Value(collectionShopIDContextKey{}).(string)
+	return shopID
+}
+
+// WithSystemAdmin return context which opt in to query across shops, it is for queries which are
+// across shops by design e.g. shops of the user, jobs, migrations and system admin apis
+func WithSystemAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemAdminContextKey{}, true)
+}
+
+// IsSystemAdmin return true when the context opt in by WithSystemAdmin
+func IsSystemAdmin(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	isSystemAdmin, _ := ctx.Value(systemAdminContextKey{}).(bool)
+	return isSystemAdmin
+}
+
+// tenantPolicy decide what to do with query which violate tenant isolation
+type tenantPolicy struct {
+	mode string
+}
+
+// violate return err on enforce mode, the violation is logged on report mode and the query go on
+func (p tenantPolicy) violate(op string, target string, err error) error {
+	if p.enforced() {
+		return fmt.Errorf("%w (%s on %s)", err, op, target)
+	}
+
+	logger.GetLogger().Warnf("%s (%s on %s)", err.Error(), op, target)
+	return nil
+}
+
+// enforced return true when queries which are not scoped to the shop are scoped or refused instead of logged
+func (p tenantPolicy) enforced() bool {
+	return p.mode == TenantIsolationEnforce
+}
+
+var tenantFields sync.Map
+
+// IsTenantModel return true when document of the model has shopid field,
+// collection of the model contain documents of many shops
+func IsTenantModel(model interface{}) bool {
+	modelType := reflect.TypeOf(model)
+	if modelType == nil {
+		return false
+	}
+
+	for modelType.Kind() == reflect.Ptr || modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array {
+		modelType = modelType.Elem()
+	}
+
+	return tenantFieldIndex(modelType) != nil
+}
+
+// tenantFieldIndex return index of shopid field of the struct, it is nil when the struct has no shopid field
+func tenantFieldIndex(structType reflect.Type) []int {
+	if cached, ok := tenantFields.Load(structType); ok {
+		return cached.([]int)
+	}
+
+	index := bsonFieldIndex(structType, tenantField, map[reflect.Type]bool{})
+	tenantFields.Store(structType, index)
+
+	return index
+}
+
+func bsonFieldIndex(structType reflect.Type, name string, visited map[reflect.Type]bool) []int {
+	if structType.Kind() != reflect.Struct || visited[structType] {
+		return nil
+	}
+	visited[structType] = true
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get("bson"), ",")
+
+		if tag[0] == name {
+			return []int{i}
+		}
+
+		// driver accept inline as name or option e.g. `bson:"inline"` and `bson:",inline"`
+		inline := false
+		for _, opt := range tag {
+			inline = inline || opt == "inline"
+		}
+
+		if tag[0] == "-" || !(field.Anonymous || inline) {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// embedded struct without bson name is inlined by the driver
+		if inline || tag[0] == "" {
+			if index := bsonFieldIndex(fieldType, name, visited); index != nil {
+				return append([]int{i}, index...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FilterShopIDs return shop ids which the filter is scoped to by equality on shopid,
+// at top level or in $and, filter which match many shops e.g. $in or $ne return nothing
+func FilterShopIDs(filter interface{}) []string {
+	doc, ok := toBsonM(filter)
+	if !ok {
+		return nil
+	}
+
+	shopIDs := []string{}
+	if shopID, ok := equalString(doc[tenantField]); ok {
+		shopIDs = append(shopIDs, shopID)
+	}
+
+	for _, cond := range toSlice(doc["$and"]) {
+		shopIDs = append(shopIDs, FilterShopIDs(cond)...)
+	}
+
+	return shopIDs
+}
+
+// PipelineShopIDs return shop ids of $match which is the first stage, or the stage after
+// stages which have to be first e.g. $geoNear and $search
+func PipelineShopIDs(pipeline interface{}) []string {
+	stages := toSlice(pipeline)
+	index := firstMatchIndex(stages)
+	if index >= len(stages) {
+		return nil
+	}
+
+	stage, ok := toBsonM(stages[index])
+	if !ok {
+		return nil
+	}
+
+	match, ok := stage["$match"]
+	if !ok {
+		return nil
+	}
+
+	return FilterShopIDs(match)
+}
+
+// DocumentShopID return shopid of the document
+func DocumentShopID(doc interface{}) string {
+	shopID, _ := documentShopID(doc)
+	return shopID
+}
+
+// documentShopID read shopid of map or struct document, struct field is found by bson tag so the document is not encoded
+func documentShopID(doc interface{}) (string, bool) {
+	if m, ok := toBsonM(doc); ok {
+		shopID, ok := m[tenantField].(string)
+		return shopID, ok
+	}
+
+	rv := reflect.ValueOf(doc)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", false
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	index := tenantFieldIndex(rv.Type())
+	if index == nil {
+		return "", false
+	}
+
+	field, err := rv.FieldByIndexErr(index)
+	if err != nil || field.Kind() != reflect.String {
+		return "", false
+	}
+
+	return field.String(), true
+}
+
+// updateShopIDs return shop ids which the update set to documents, by $set or by replacement
+func updateShopIDs(update interface{}) []string {
+	shopIDs := []string{}
+	if shopID, ok := documentShopID(update); ok {
+		shopIDs = append(shopIDs, shopID)
+	}
+
+	m, ok := toBsonM(update)
+	if !ok {
+		return shopIDs
+	}
+
+	for _, op := range []string{"$set", "$setOnInsert"} {
+		if shopID, ok := documentShopID(m[op]); ok {
+			shopIDs = append(shopIDs, shopID)
+		}
+	}
+
+	return shopIDs
+}
+
+// scopeFilter add shopid to the filter, the filter is copied when it is a map
+func scopeFilter(filter interface{}, shopID string) interface{} {
+	switch f := filter.(type) {
+	case nil:
+		return bson.M{tenantField: shopID}
+	case bson.M:
+		scoped := bson.M{}
+		for key, value := range f {
+			scoped[key] = value
+		}
+		scoped[tenantField] = shopID
+		return scoped
+	case map[string]interface{}:
+		scoped := map[string]interface{}{}
+		for key, value := range f {
+			scoped[key] = value
+		}
+		scoped[tenantField] = shopID
+		return scoped
+	case bson.D:
+		scoped := append(bson.D{}, f...)
+		return append(scoped, bson.E{Key: tenantField, Value: shopID})
+	}
+
+	return bson.M{"$and": bson.A{filter, bson.M{tenantField: shopID}}}
+}
+
+// scopePipeline insert $match of shopid before the first stage which is not required to be first
+func scopePipeline(pipeline interface{}, shopID string) []interface{} {
+	stages := toSlice(pipeline)
+	index := firstMatchIndex(stages)
+
+	scoped := make([]interface{}, 0, len(stages)+1)
+	scoped = append(scoped, stages[:index]...)
+	scoped = append(scoped, bson.M{"$match": bson.M{tenantField: shopID}})
+	return append(scoped, stages[index:]...)
+}
+
+func firstMatchIndex(stages []interface{}) int {
+	for i, stage := range stages {
+		m, ok := toBsonM(stage)
+		if !ok {
+			return i
+		}
+
+		_, geoNear := m["$geoNear"]
+		_, search := m["$search"]
+		_, searchMeta := m["$searchMeta"]
+		if !geoNear && !search && !searchMeta {
+			return i
+		}
+	}
+	return len(stages)
+}
+
+func equalString(value interface{}) (string, bool) {
+	if str, ok := value.(string); ok {
+		return str, true
+	}
+
+	if m, ok := toBsonM(value); ok && len(m) == 1 {
+		str, ok := m["$eq"].(string)
+		return str, ok
+	}
+
+	return "", false
+}
+
+// toBsonM return filter, stage or update of map or bson.D as map, other values are not filters
+func toBsonM(value interface{}) (bson.M, bool) {
+	switch v := value.(type) {
+	case bson.M:
+		return v, true
+	case map[string]interface{}:
+		return bson.M(v), true
+	case bson.D:
+		m := bson.M{}
+		for _, e := range v {
+			m[e.Key] = e.Value
+		}
+		return m, true
+	}
+
+	return nil, false
+}
+
+func toSlice(value interface{}) []interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		return items
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
@@ -0,0 +1,450 @@
+// Package tenanttest help tests of repositories to find queries which leak data across shops.
+// The repository is run against tenant persister in enforce mode with recorder behind it,
+// the test fail when the query is refused or when the recorded query is not scoped to the shop.
+package tenanttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sync"
+	"testing"
+
+	"smlaicloudplatform/pkg/microservice"
+	"smlaicloudplatform/pkg/microservice/models"
+
+	"github.com/smlsoft/mongopagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var dmlStatement = regexp.MustCompile(`(?i)^\s*(select|update|delete|with)\b`)
+
+// Runs is number of random shops which the query is run with by AssertMongoScoped and AssertSQLScoped
+const Runs = 20
+
+// Call is query which reach the persister behind tenant persister
+type Call struct {
+	Op       string
+	Model    interface{}
+	Filter   interface{}
+	Pipeline interface{}
+	Docs     []interface{}
+	Expr     string
+	Args     []interface{}
+	Where    map[string]interface{}
+}
+
+// MongoRecorder is IPersisterMongo which record queries and return empty results
+type MongoRecorder struct {
+	mu    sync.Mutex
+	Calls []Call
+}
+
+func (r *MongoRecorder) record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Calls = append(r.Calls, call)
+}
+
+func (r *MongoRecorder) Aggregate(ctx context.Context, model interface{}, pipeline interface{}, decode interface{}) error {
+	r.record(Call{Op: "aggregate", Model: model, Pipeline: pipeline})
+	return nil
+}
+
+func (r *MongoRecorder) AggregatePage(ctx context.Context, model interface{}, pageable models.Pageable, criteria ...interface{}) (*mongopagination.PaginatedData, error) {
+	r.record(Call{Op: "aggregate page", Model: model, Pipeline: criteria})
+	return &mongopagination.PaginatedData{}, nil
+}
+
+func (r *MongoRecorder) Find(ctx context.Context, model interface{}, filter interface{}, decode interface{}, opts ...*options.FindOptions) error {
+	r.record(Call{Op: "find", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) FindPage(ctx context.Context, model interface{}, filter interface{}, pageable models.Pageable, decode interface{}) (mongopagination.PaginationData, error) {
+	r.record(Call{Op: "find page", Model: model, Filter: filter})
+	return mongopagination.PaginationData{}, nil
+}
+
+func (r *MongoRecorder) FindSelectPage(ctx context.Context, model interface{}, selectFields interface{}, filter interface{}, pageable models.Pageable, decode interface{}) (mongopagination.PaginationData, error) {
+	r.record(Call{Op: "find select page", Model: model, Filter: filter})
+	return mongopagination.PaginationData{}, nil
+}
+
+func (r *MongoRecorder) FindOne(ctx context.Context, model interface{}, filter interface{}, decode interface{}, opts ...*options.FindOneOptions) error {
+	r.record(Call{Op: "find one", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) FindByID(ctx context.Context, model interface{}, keyName string, id interface{}, decode interface{}) error {
+	r.record(Call{Op: "find by id", Model: model, Filter: bson.M{keyName: id}})
+	return nil
+}
+
+func (r *MongoRecorder) Create(ctx context.Context, model interface{}, data interface{}) (primitive.ObjectID, error) {
+	r.record(Call{Op: "create", Model: model, Docs: []interface{}{data}})
+	return primitive.NewObjectID(), nil
+}
+
+func (r *MongoRecorder) UpdateOne(ctx context.Context, model interface{}, filterConditions map[string]interface{}, data interface{}) error {
+	r.record(Call{Op: "update one", Model: model, Filter: filterConditions, Docs: []interface{}{data}})
+	return nil
+}
+
+func (r *MongoRecorder) Update(ctx context.Context, model interface{}, filter interface{}, data interface{}, opts ...*options.UpdateOptions) error {
+	r.record(Call{Op: "update", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) FindOneAndUpdate(ctx context.Context, model interface{}, filter interface{}, data interface{}, decode interface{}, opts ...*options.FindOneAndUpdateOptions) error {
+	r.record(Call{Op: "find one and update", Model: model, Filter: filter, Docs: []interface{}{data}})
+	return nil
+}
+
+func (r *MongoRecorder) CreateInBatch(ctx context.Context, model interface{}, data []interface{}) error {
+	r.record(Call{Op: "create in batch", Model: model, Docs: data})
+	return nil
+}
+
+func (r *MongoRecorder) Count(ctx context.Context, model interface{}, filter interface{}) (int, error) {
+	r.record(Call{Op: "count", Model: model, Filter: filter})
+	return 0, nil
+}
+
+func (r *MongoRecorder) Exec(ctx context.Context, model interface{}) (*mongo.Collection, error) {
+	return nil, errors.New("tenanttest: exec is not supported")
+}
+
+func (r *MongoRecorder) Delete(ctx context.Context, model interface{}, filter interface{}) error {
+	r.record(Call{Op: "delete", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) DeleteByID(ctx context.Context, model interface{}, id string) error {
+	r.record(Call{Op: "delete by id", Model: model, Filter: bson.M{"_id": id}})
+	return nil
+}
+
+func (r *MongoRecorder) SoftDelete(ctx context.Context, model interface{}, username string, filter interface{}) error {
+	r.record(Call{Op: "soft delete", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) SoftDeleteLastUpdate(ctx context.Context, model interface{}, username string, filter interface{}) error {
+	r.record(Call{Op: "soft delete", Model: model, Filter: filter})
+	return nil
+}
+
+func (r *MongoRecorder) SoftBatchDeleteByID(ctx context.Context, model interface{}, username string, ids []string) error {
+	r.record(Call{Op: "soft batch delete by id", Model: model, Filter: bson.M{"_id": bson.M{"$in": ids}}})
+	return nil
+}
+
+func (r *MongoRecorder) SoftDeleteByID(ctx context.Context, model interface{}, id string, username string) error {
+	r.record(Call{Op: "soft delete by id", Model: model, Filter: bson.M{"guidfixed": id}})
+	return nil
+}
+
+func (r *MongoRecorder) Transaction(ctx context.Context, queryFunc func(ctx context.Context) error) error {
+	return queryFunc(ctx)
+}
+
+func (r *MongoRecorder) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func (r *MongoRecorder) TestConnect(ctx context.Context) error {
+	return nil
+}
+
+func (r *MongoRecorder) Healthcheck(ctx context.Context) error {
+	return nil
+}
+
+func (r *MongoRecorder) CreateIndex(ctx context.Context, model interface{}, indexName string, keys interface{}) (string, error) {
+	return indexName, nil
+}
+
+// SQLRecorder is IPersister which record queries and return empty results
+type SQLRecorder struct {
+	mu    sync.Mutex
+	Calls []Call
+}
+
+func (r *SQLRecorder) record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Calls = append(r.Calls, call)
+}
+
+func (r *SQLRecorder) WhereSP(model interface{}, sortexpr string, pageLimit int, page int, expr string, args ...interface{}) (interface{}, error) {
+	r.record(Call{Op: "where", Model: model, Expr: expr, Args: args})
+	return model, nil
+}
+
+func (r *SQLRecorder) WhereS(model interface{}, sortexpr string, expr string, args ...interface{}) (interface{}, error) {
+	r.record(Call{Op: "where", Model: model, Expr: expr, Args: args})
+	return model, nil
+}
+
+func (r *SQLRecorder) WhereP(model interface{}, pageLimit int, page int, expr string, args ...interface{}) (interface{}, error) {
+	r.record(Call{Op: "where", Model: model, Expr: expr, Args: args})
+	return model, nil
+}
+
+func (r *SQLRecorder) Where(model interface{}, expr string, args ...interface{}) (interface{}, error) {
+	r.record(Call{Op: "where", Model: model, Expr: expr, Args: args})
+	return model, nil
+}
+
+func (r *SQLRecorder) FindOne(model interface{}, idColumn string, id string) (interface{}, error) {
+	r.record(Call{Op: "find one", Model: model, Where: map[string]interface{}{idColumn: id}})
+	return model, nil
+}
+
+func (r *SQLRecorder) First(model interface{}, query interface{}, args ...interface{}) (interface{}, error) {
+	call := Call{Op: "first", Model: model}
+	switch q := query.(type) {
+	case string:
+		call.Expr, call.Args = q, args
+	case map[string]interface{}:
+		call.Where = q
+	default:
+		call.Docs = []interface{}{query}
+	}
+	r.record(call)
+	return model, nil
+}
+
+func (r *SQLRecorder) Create(model interface{}) error {
+	r.record(Call{Op: "create", Model: model, Docs: []interface{}{model}})
+	return nil
+}
+
+func (r *SQLRecorder) Update(model interface{}, where map[string]interface{}) error {
+	r.record(Call{Op: "update", Model: model, Where: where})
+	return nil
+}
+
+func (r *SQLRecorder) Delete(model interface{}, where map[string]interface{}) error {
+	r.record(Call{Op: "delete", Model: model, Where: where})
+	return nil
+}
+
+func (r *SQLRecorder) CreateInBatch(models interface{}, bulkSize int) error {
+	r.record(Call{Op: "create in batch", Model: models, Docs: []interface{}{models}})
+	return nil
+}
+
+func (r *SQLRecorder) CreateInBatchOnConflict(models interface{}, bulkSize int) error {
+	r.record(Call{Op: "create in batch", Model: models, Docs: []interface{}{models}})
+	return nil
+}
+
+func (r *SQLRecorder) CreateInBatchClauses(models interface{}, bulkSize int, expression ...clause.Expression) error {
+	r.record(Call{Op: "create in batch", Model: models, Docs: []interface{}{models}})
+	return nil
+}
+
+func (r *SQLRecorder) Exec(sql string, args ...interface{}) error {
+	r.record(Call{Op: "exec", Expr: sql, Args: args})
+	return nil
+}
+
+func (r *SQLRecorder) TableExists(model interface{}) (bool, error) {
+	return true, nil
+}
+
+func (r *SQLRecorder) Count(model interface{}, expr string, args ...interface{}) (int64, error) {
+	r.record(Call{Op: "count", Model: model, Expr: expr, Args: args})
+	return 0, nil
+}
+
+func (r *SQLRecorder) DropTable(table ...interface{}) error {
+	return nil
+}
+
+func (r *SQLRecorder) SetupJoinTable(model interface{}, field string, joinTable interface{}) error {
+	return nil
+}
+
+func (r *SQLRecorder) AutoMigrate(dst ...interface{}) error {
+	return nil
+}
+
+func (r *SQLRecorder) TestConnect() error {
+	return nil
+}
+
+func (r *SQLRecorder) Transaction(funcTransaction func(*microservice.Persister) error) error {
+	return errors.New("tenanttest: transaction is not supported")
+}
+
+func (r *SQLRecorder) Raw(queryStr string, where map[string]interface{}, model interface{}) (interface{}, error) {
+	r.record(Call{Op: "raw", Model: model, Expr: queryStr, Args: []interface{}{where}})
+	return model, nil
+}
+
+func (r *SQLRecorder) DBClient() *gorm.DB {
+	return nil
+}
+
+// RandomShopID return random shop id in format of guid
+func RandomShopID(rnd *rand.Rand) string {
+	b := make([]byte, 14)
+	rnd.Read(b)
+	return fmt.Sprintf("%X", b)
+}
+
+// AssertMongoScoped run the query with random shops and fail the test when the query
+// is not scoped to the shop, ctx of the query must not be opted in by WithSystemAdmin
+func AssertMongoScoped(t testing.TB, query func(pst microservice.IPersisterMongo, shopID string) error) {
+	t.Helper()
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < Runs; i++ {
+		CheckMongoScoped(t, RandomShopID(rnd), query)
+	}
+}
+
+// FuzzMongoScoped fuzz the query with shop ids of the corpus and the fuzzer
+func FuzzMongoScoped(f *testing.F, query func(pst microservice.IPersisterMongo, shopID string) error) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < Runs; i++ {
+		f.Add(RandomShopID(rnd))
+	}
+
+	f.Fuzz(func(t *testing.T, shopID string) {
+		if shopID == "" {
+			t.Skip()
+		}
+		CheckMongoScoped(t, shopID, query)
+	})
+}
+
+// CheckMongoScoped run the query with the shop and check every query which reach the persister
+func CheckMongoScoped(t testing.TB, shopID string, query func(pst microservice.IPersisterMongo, shopID string) error) {
+	t.Helper()
+
+	recorder := &MongoRecorder{}
+	err := query(microservice.NewTenantPersisterMongo(recorder, microservice.TenantIsolationEnforce), shopID)
+	if errors.Is(err, microservice.ErrUnscopedQuery) || errors.Is(err, microservice.ErrCrossTenantQuery) {
+		t.Errorf("shop %s: %v", shopID, err)
+		return
+	}
+
+	for _, call := range recorder.Calls {
+		if !microservice.IsTenantModel(call.Model) {
+			continue
+		}
+
+		if call.Filter != nil && !isOwnershipCheck(call.Filter, shopID) {
+			checkShopIDs(t, shopID, call, microservice.FilterShopIDs(call.Filter))
+		}
+
+		if call.Pipeline != nil {
+			checkShopIDs(t, shopID, call, microservice.PipelineShopIDs(call.Pipeline))
+		}
+
+		if call.Op == "create" || call.Op == "create in batch" {
+			for _, doc := range call.Docs {
+				checkShopIDs(t, shopID, call, []string{microservice.DocumentShopID(doc)})
+			}
+		}
+	}
+}
+
+// isOwnershipCheck return true for count of documents of other shops which tenant persister run before query by id
+func isOwnershipCheck(filter interface{}, shopID string) bool {
+	m, ok := filter.(bson.M)
+	if !ok {
+		return false
+	}
+
+	ne, ok := m["shopid"].(bson.M)
+	return ok && len(ne) == 1 && ne["$ne"] == shopID
+}
+
+// AssertSQLScoped run the query with random shops and fail the test when the query is not scoped to the shop
+func AssertSQLScoped(t testing.TB, query func(pst microservice.IPersister, shopID string) error) {
+	t.Helper()
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < Runs; i++ {
+		CheckSQLScoped(t, RandomShopID(rnd), query)
+	}
+}
+
+// FuzzSQLScoped fuzz the query with shop ids of the corpus and the fuzzer
+func FuzzSQLScoped(f *testing.F, query func(pst microservice.IPersister, shopID string) error) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < Runs; i++ {
+		f.Add(RandomShopID(rnd))
+	}
+
+	f.Fuzz(func(t *testing.T, shopID string) {
+		if shopID == "" {
+			t.Skip()
+		}
+		CheckSQLScoped(t, shopID, query)
+	})
+}
+
+// CheckSQLScoped run the query with the shop and check every query which reach the persister
+func CheckSQLScoped(t testing.TB, shopID string, query func(pst microservice.IPersister, shopID string) error) {
+	t.Helper()
+
+	recorder := &SQLRecorder{}
+	err := query(microservice.NewTenantPersister(recorder, microservice.TenantIsolationEnforce), shopID)
+	if errors.Is(err, microservice.ErrUnscopedQuery) || errors.Is(err, microservice.ErrCrossTenantQuery) {
+		t.Errorf("shop %s: %v", shopID, err)
+		return
+	}
+
+	for _, call := range recorder.Calls {
+		if call.Op != "exec" && !microservice.IsTenantTable(call.Model) {
+			continue
+		}
+
+		switch {
+		case call.Expr != "":
+			if call.Op == "exec" && !isDMLStatement(call.Expr) {
+				continue
+			}
+			sqlShopID, _ := microservice.SQLShopID(call.Expr, call.Args...)
+			checkShopIDs(t, shopID, call, []string{sqlShopID})
+		case call.Where != nil:
+			whereShopID, ok := call.Where["shopid"].(string)
+			if !ok {
+				whereShopID, _ = call.Where["shop_id"].(string)
+			}
+			checkShopIDs(t, shopID, call, []string{whereShopID})
+		}
+	}
+}
+
+func isDMLStatement(sql string) bool {
+	return dmlStatement.MatchString(sql)
+}
+
+func checkShopIDs(t testing.TB, shopID string, call Call, shopIDs []string) {
+	t.Helper()
+
+	if len(shopIDs) == 0 {
+		t.Errorf("shop %s: %s on %T is not scoped to a shop", shopID, call.Op, call.Model)
+		return
+	}
+
+	for _, callShopID := range shopIDs {
+		if callShopID != shopID {
+			t.Errorf("shop %s: %s on %T is scoped to shop %q", shopID, call.Op, call.Model, callShopID)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package microservice
+
+import (
+	"encoding/json"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// websocketHubChannelPrefix is prefix of cacher pub/sub channel of the room
+	websocketHubChannelPrefix = "wshub:"
+	// websocketHubPresencePrefix is prefix of cacher hash of clients in the room
+	websocketHubPresencePrefix = "wspresence:"
+	// websocketClientSendBuffer is number of messages waiting to be written, slow client is disconnected when it is full
+	websocketClientSendBuffer = 64
+
+	// WebsocketHeartbeatInterval is how often client presence is refreshed and ping is sent
+	WebsocketHeartbeatInterval = 30 * time.Second
+	// WebsocketPresenceTTL is how long client is online after the last heartbeat
+	WebsocketPresenceTTL = 90 * time.Second
+)
+
+// IWebsocketHubCacher is part of ICacher used by the hub
+type IWebsocketHubCacher interface {
+	Pub(channel string, message interface{}) error
+	Sub(channels ...string) (<-chan *redis.Message, string, error)
+	Unsub(subID string) error
+	HSetS(key string, field string, value string, expire time.Duration) error
+	HGetAll(key string) (map[string]string, error)
+	HDel(key string, fields ...string) error
+}
+
+// IWebsocketHub push messages to websocket clients of a room on every replica
+type IWebsocketHub interface {
+	// Publish send message to every client in the room, string and []byte are sent as is, other types are json encoded
+	Publish(room string, message interface{}) error
+	// Presence return clients which are online in the room
+	Presence(room string) ([]WebsocketPresence, error)
+	// Serve run the connection until it is closed, message from the client is passed to onMessage
+	Serve(conn IWebsocketConn, client WebsocketClientInfo, rooms []string, onMessage func(message []byte)) error
+}
+
+// IWebsocketConn is the websocket connection used by the hub, *websocket.Conn implement it
+type IWebsocketConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// WebsocketClientInfo identify the client in presence list
+type WebsocketClientInfo struct {
+	ClientID string `json:"clientid"`
+	ShopID   string `json:"shopid"`
+	Username string `json:"username"`
+}
+
+// WebsocketPresence is the client which is online in the room
+type WebsocketPresence struct {
+	WebsocketClientInfo
+	LastSeen time.Time `json:"lastseen"`
+}
+
+// WebsocketRoom return room name from parts, e.g. WebsocketRoom("journal", "docref", shopID)
+func WebsocketRoom(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+// WebsocketHub fan out room messages through cacher pub/sub so clients connected to any replica receive them,
+// each replica subscribe the room while it has local client in the room
+type WebsocketHub struct {
+	cacher IWebsocketHubCacher
+	logger logger.ILogger
+	mutex  sync.Mutex
+	rooms  map[string]*websocketHubRoom
+	now    func() time.Time
+}
+
+type websocketHubRoom struct {
+	clients map[*websocketHubClient]struct{}
+	subID   string
+}
+
+type websocketHubClient struct {
+	info      WebsocketClientInfo
+	conn      IWebsocketConn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebsocketHub return hub which publish through the cacher
+func NewWebsocketHub(cacher IWebsocketHubCacher, logger logger.ILogger) *WebsocketHub {
+	return &WebsocketHub{
+		cacher: cacher,
+		logger: logger,
+		rooms:  map[string]*websocketHubRoom{},
+		now:    time.Now,
+	}
+}
+
+func (hub *WebsocketHub) Publish(room string, message interface{}) error {
+	var payload []byte
+	switch msg := message.(type) {
+	case []byte:
+		payload = msg
+	case string:
+		payload = []byte(msg)
+	default:
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		payload = data
+	}
+
+	return hub.cacher.Pub(websocketHubChannelPrefix+room, payload)
+}
+
+func (hub *WebsocketHub) Presence(room string) ([]WebsocketPresence, error) {
+	key := websocketHubPresencePrefix + room
+	fields, err := hub.cacher.HGetAll(key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := hub.now()
+	presences := []WebsocketPresence{}
+	stale := []string{}
+	for clientID, value := range fields {
+		presence := WebsocketPresence{}
+		err := json.Unmarshal([]byte(value), &presence)
+		if err != nil || now.Sub(presence.LastSeen) > WebsocketPresenceTTL {
+			// client of crashed replica does not remove itself
+			stale = append(stale, clientID)
+			continue
+		}
+		presences = append(presences, presence)
+	}
+
+	if len(stale) > 0 {
+		hub.cacher.HDel(key, stale...)
+	}
+
+	return presences, nil
+}
+
+func (hub *WebsocketHub) Serve(conn IWebsocketConn, info WebsocketClientInfo, rooms []string, onMessage func(message []byte)) error {
+	client := &websocketHubClient{
+		info: info,
+		conn: conn,
+		send: make(chan []byte, websocketClientSendBuffer),
+		done: make(chan struct{}),
+	}
+
+	defer func() {
+		client.close()
+		for _, room := range rooms {
+			hub.leave(room, client)
+		}
+	}()
+
+	for _, room := range rooms {
+		err := hub.join(room, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	go hub.writeLoop(client, rooms)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return nil
+		}
+
+		if onMessage != nil {
+			onMessage(message)
+		}
+	}
+}
+
+func (hub *WebsocketHub) writeLoop(client *websocketHubClient, rooms []string) {
+	ticker := time.NewTicker(WebsocketHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message := <-client.send:
+			if err := client.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				client.close()
+				return
+			}
+		case <-ticker.C:
+			for _, room := range rooms {
+				hub.touchPresence(room, client.info)
+			}
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				client.close()
+				return
+			}
+		case <-client.done:
+			return
+		}
+	}
+}
+
+func (hub *WebsocketHub) join(room string, client *websocketHubClient) error {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	r, ok := hub.rooms[room]
+	if !ok {
+		messages, subID, err := hub.cacher.Sub(websocketHubChannelPrefix + room)
+		if err != nil {
+			return err
+		}
+
+		r = &websocketHubRoom{
+			clients: map[*websocketHubClient]struct{}{},
+			subID:   subID,
+		}
+		hub.rooms[room] = r
+
+		go hub.fanOut(r, messages)
+	}
+
+	r.clients[client] = struct{}{}
+
+	return hub.touchPresence(room, client.info)
+}
+
+func (hub *WebsocketHub) leave(room string, client *websocketHubClient) {
+	hub.cacher.HDel(websocketHubPresencePrefix+room, client.info.ClientID)
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	r, ok := hub.rooms[room]
+	if !ok {
+		return
+	}
+
+	delete(r.clients, client)
+	if len(r.clients) == 0 {
+		delete(hub.rooms, room)
+		hub.cacher.Unsub(r.subID)
+	}
+}
+
+func (hub *WebsocketHub) touchPresence(room string, info WebsocketClientInfo) error {
+	presence, err := json.Marshal(WebsocketPresence{
+		WebsocketClientInfo: info,
+		LastSeen:            hub.now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return hub.cacher.HSetS(websocketHubPresencePrefix+room, info.ClientID, string(presence), WebsocketPresenceTTL)
+}
+
+// fanOut write message of the room subscription to local clients until the subscription is closed
+func (hub *WebsocketHub) fanOut(r *websocketHubRoom, messages <-chan *redis.Message) {
+	for message := range messages {
+		if message == nil {
+			continue
+		}
+
+		hub.mutex.Lock()
+		for client := range r.clients {
+			select {
+			case client.send <- []byte(message.Payload):
+			default:
+				hub.logger.Warnf("Websocket client %s is too slow, disconnect", client.info.ClientID)
+				client.close()
+			}
+		}
+		hub.mutex.Unlock()
+	}
+}
+
+func (client *websocketHubClient) close() {
+	client.closeOnce.Do(func() {
+		close(client.done)
+		client.conn.Close()
+	})
+}
+
+// WebsocketHub return hub shared by handlers using the same cacher
+func (ms *Microservice) WebsocketHub(cfg config.ICacherConfig) *WebsocketHub {
+	ms.websocketHubsMutex.Lock()
+	defer ms.websocketHubsMutex.Unlock()
+
+	if ms.websocketHubs == nil {
+		ms.websocketHubs = map[string]*WebsocketHub{}
+	}
+
+	hub, ok := ms.websocketHubs[cfg.Endpoint()]
+	if !ok {
+		hub = NewWebsocketHub(ms.Cacher(cfg), ms.Logger)
+		ms.websocketHubs[cfg.Endpoint()] = hub
+	}
+	return hub
+}
+
+// ServeWebsocket upgrade the request and serve it by the hub until the client disconnect,
+// the client is identified by shop and username of the request
+func (ms *Microservice) ServeWebsocket(ctx IContext, hub IWebsocketHub, rooms []string, onMessage func(message []byte)) error {
+	userInfo := ctx.UserInfo()
+	socketID := NewUUID()
+
+	ws, err := ms.Websocket(socketID, ctx.ResponseWriter(), ctx.Request())
+	if err != nil {
+		return err
+	}
+	defer ms.WebsocketClose(socketID)
+
+	return hub.Serve(ws, WebsocketClientInfo{
+		ClientID: socketID,
+		ShopID:   userInfo.ShopID,
+		Username: userInfo.Username,
+	}, rooms, onMessage)
+}
@@ -0,0 +1,194 @@
+package microservice
+
+import (
+	"errors"
+	"smlaicloudplatform/internal/config"
+	"smlaicloudplatform/internal/logger"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryHubCacher is pub/sub and hash of cacher shared by hubs of every replica
+type memoryHubCacher struct {
+	mutex  sync.Mutex
+	subs   map[string]chan *redis.Message
+	topics map[string]string
+	hashes map[string]map[string]string
+}
+
+func newMemoryHubCacher() *memoryHubCacher {
+	return &memoryHubCacher{
+		subs:   map[string]chan *redis.Message{},
+		topics: map[string]string{},
+		hashes: map[string]map[string]string{},
+	}
+}
+
+func (c *memoryHubCacher) Pub(channel string, message interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for subID, sub := range c.subs {
+		if c.topics[subID] == channel {
+			sub <- &redis.Message{Channel: channel, Payload: string(message.([]byte))}
+		}
+	}
+	return nil
+}
+
+func (c *memoryHubCacher) Sub(channels ...string) (<-chan *redis.Message, string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	subID := NewUUID()
+	c.subs[subID] = make(chan *redis.Message, 10)
+	c.topics[subID] = channels[0]
+	return c.subs[subID], subID, nil
+}
+
+func (c *memoryHubCacher) Unsub(subID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	close(c.subs[subID])
+	delete(c.subs, subID)
+	return nil
+}
+
+func (c *memoryHubCacher) HSetS(key string, field string, value string, expire time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.hashes[key]; !ok {
+		c.hashes[key] = map[string]string{}
+	}
+	c.hashes[key][field] = value
+	return nil
+}
+
+func (c *memoryHubCacher) HGetAll(key string) (map[string]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fields := map[string]string{}
+	for field, value := range c.hashes[key] {
+		fields[field] = value
+	}
+	return fields, nil
+}
+
+func (c *memoryHubCacher) HDel(key string, fields ...string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, field := range fields {
+		delete(c.hashes[key], field)
+	}
+	return nil
+}
+
+type testWebsocketConn struct {
+	written chan string
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func newTestWebsocketConn() *testWebsocketConn {
+	return &testWebsocketConn{
+		written: make(chan string, 10),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (c *testWebsocketConn) ReadMessage() (int, []byte, error) {
+	<-c.closed
+	return 0, nil, errors.New("closed")
+}
+
+func (c *testWebsocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType == websocket.TextMessage {
+		c.written <- string(data)
+	}
+	return nil
+}
+
+func (c *testWebsocketConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func waitHubPresence(t *testing.T, hub *WebsocketHub, room string, count int) []WebsocketPresence {
+	for i := 0; i < 100; i++ {
+		presences, _ := hub.Presence(room)
+		if len(presences) == count {
+			return presences
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("room %s does not have %d clients", room, count)
+	return nil
+}
+
+func TestWebsocketHubPublishAcrossReplicas(t *testing.T) {
+	cacher := newMemoryHubCacher()
+	log := logger.NewAppLogger(config.NewLoggerConfig())
+	replica1 := NewWebsocketHub(cacher, log)
+	replica2 := NewWebsocketHub(cacher, log)
+
+	room := WebsocketRoom("journal", "docref", "SHOP01")
+
+	conn1 := newTestWebsocketConn()
+	conn2 := newTestWebsocketConn()
+	other := newTestWebsocketConn()
+	go replica1.Serve(conn1, WebsocketClientInfo{ClientID: "c1", ShopID: "SHOP01", Username: "user01"}, []string{room}, nil)
+	go replica2.Serve(conn2, WebsocketClientInfo{ClientID: "c2", ShopID: "SHOP01", Username: "user02"}, []string{room}, nil)
+	go replica2.Serve(other, WebsocketClientInfo{ClientID: "c3", ShopID: "SHOP02", Username: "user03"}, []string{WebsocketRoom("journal", "docref", "SHOP02")}, nil)
+
+	waitHubPresence(t, replica1, room, 2)
+
+	assert.Nil(t, replica1.Publish(room, map[string]string{"docref": "DOC01"}))
+
+	for _, conn := range []*testWebsocketConn{conn1, conn2} {
+		select {
+		case msg := <-conn.written:
+			assert.JSONEq(t, `{"docref":"DOC01"}`, msg)
+		case <-time.After(time.Second):
+			t.Fatal("client does not receive message")
+		}
+	}
+
+	select {
+	case msg := <-other.written:
+		t.Fatalf("client of other room receive %s", msg)
+	default:
+	}
+
+	conn1.Close()
+	presences := waitHubPresence(t, replica2, room, 1)
+	assert.Equal(t, "user02", presences[0].Username)
+
+	conn2.Close()
+	other.Close()
+}
+
+func TestWebsocketHubPresenceExpired(t *testing.T) {
+	cacher := newMemoryHubCacher()
+	hub := NewWebsocketHub(cacher, logger.NewAppLogger(config.NewLoggerConfig()))
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	hub.now = func() time.Time { return now }
+	hub.touchPresence("room", WebsocketClientInfo{ClientID: "crashed"})
+
+	assert.Len(t, waitHubPresence(t, hub, "room", 1), 1)
+
+	now = now.Add(WebsocketPresenceTTL + time.Second)
+	presences, err := hub.Presence("room")
+	assert.Nil(t, err)
+	assert.Empty(t, presences)
+	assert.Empty(t, cacher.hashes[websocketHubPresencePrefix+"room"])
+}
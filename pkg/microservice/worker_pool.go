@@ -0,0 +1,185 @@
+package microservice
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+)
+
+var (
+	// ErrWorkerPoolClosed is returned by Submit after the pool is closed
+	ErrWorkerPoolClosed = errors.New("worker pool is closed")
+	// ErrWorkerPoolFull is returned by TrySubmit when the worker queue of the key is full
+	ErrWorkerPoolFull = errors.New("worker pool queue is full")
+)
+
+// KeyedWorkerPool run tasks with bounded number of workers, tasks with the same key are run by the same worker
+// in submitted order and tasks of different keys are run in parallel
+type KeyedWorkerPool struct {
+	queues []chan func()
+	// done is closed by Close to release Submit which wait for full queue
+	done       chan struct{}
+	mutex      sync.Mutex
+	closed     bool
+	submitting sync.WaitGroup
+	closeOnce  sync.Once
+	running    sync.WaitGroup
+}
+
+// NewKeyedWorkerPool start workers, each worker has queue of queueSize tasks and Submit is blocked when the queue is full
+func NewKeyedWorkerPool(workers int, queueSize int) *KeyedWorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &KeyedWorkerPool{
+		queues: make([]chan func(), workers),
+		done:   make(chan struct{}),
+	}
+
+	for i := range p.queues {
+		queue := make(chan func(), queueSize)
+		p.queues[i] = queue
+
+		p.running.Add(1)
+		go func() {
+			defer p.running.Done()
+			for task := range queue {
+				task()
+			}
+		}()
+	}
+
+	return p
+}
+
+// Workers return number of workers
+func (p *KeyedWorkerPool) Workers() int {
+	return len(p.queues)
+}
+
+// Submit queue the task to worker of the key, wait while the worker queue is full until the pool is closed.
+// The lock is not held while waiting so Close and Closed are not blocked by full queue.
+func (p *KeyedWorkerPool) Submit(key string, task func()) error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return ErrWorkerPoolClosed
+	}
+	p.submitting.Add(1)
+	p.mutex.Unlock()
+
+	defer p.submitting.Done()
+
+	select {
+	case p.queues[p.worker(key)] <- task:
+		return nil
+	case <-p.done:
+		return ErrWorkerPoolClosed
+	}
+}
+
+// TrySubmit queue the task to worker of the key like Submit but return ErrWorkerPoolFull instead of waiting
+// when the worker queue is full
+func (p *KeyedWorkerPool) TrySubmit(key string, task func()) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return ErrWorkerPoolClosed
+	}
+
+	select {
+	case p.queues[p.worker(key)] <- task:
+		return nil
+	default:
+		return ErrWorkerPoolFull
+	}
+}
+
+func (p *KeyedWorkerPool) worker(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// Closed check the pool is closed, running task use it to stop retry on shutdown
+func (p *KeyedWorkerPool) Closed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stop accepting task and wait until every queued task is done, Submit waiting for full queue return error
+func (p *KeyedWorkerPool) Close() {
+	p.mutex.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+	}
+	p.mutex.Unlock()
+
+	// queues are closed after no Submit can send to them
+	p.submitting.Wait()
+	p.closeOnce.Do(func() {
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	})
+
+	p.running.Wait()
+}
+
+// KeyedTaskGroup submit tasks to the pool and wait for all of them like one unit of work
+type KeyedTaskGroup struct {
+	pool  *KeyedWorkerPool
+	wait  sync.WaitGroup
+	mutex sync.Mutex
+	err   error
+}
+
+// NewKeyedTaskGroup return group which run tasks by the pool
+func NewKeyedTaskGroup(pool *KeyedWorkerPool) *KeyedTaskGroup {
+	return &KeyedTaskGroup{
+		pool: pool,
+	}
+}
+
+// Go submit task of the key, the first error of the group is returned by Wait
+func (g *KeyedTaskGroup) Go(key string, task func() error) {
+	g.wait.Add(1)
+	err := g.pool.Submit(key, func() {
+		defer g.wait.Done()
+		g.setError(task())
+	})
+
+	if err != nil {
+		g.wait.Done()
+		g.setError(err)
+	}
+}
+
+// Wait block until every task is done and return the first error
+func (g *KeyedTaskGroup) Wait() error {
+	g.wait.Wait()
+	return g.err
+}
+
+func (g *KeyedTaskGroup) setError(err error) {
+	if err == nil {
+		return
+	}
+
+	g.mutex.Lock()
+	if g.err == nil {
+		g.err = err
+	}
+	g.mutex.Unlock()
+}
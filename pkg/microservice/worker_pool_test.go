@@ -0,0 +1,166 @@
+package microservice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedWorkerPoolOrderByKey(t *testing.T) {
+	pool := NewKeyedWorkerPool(4, 10)
+
+	mutex := sync.Mutex{}
+	results := map[string][]int{}
+	for i := 0; i < 50; i++ {
+		for _, key := range []string{"SHOP01:BARCODE01", "SHOP01:BARCODE02", "SHOP02:BARCODE01"} {
+			seq, key := i, key
+			pool.Submit(key, func() {
+				mutex.Lock()
+				results[key] = append(results[key], seq)
+				mutex.Unlock()
+			})
+		}
+	}
+
+	// close wait for queued tasks
+	pool.Close()
+
+	for key, seqs := range results {
+		assert.Len(t, seqs, 50, key)
+		for i, seq := range seqs {
+			assert.Equal(t, i, seq, key)
+		}
+	}
+
+	assert.Equal(t, ErrWorkerPoolClosed, pool.Submit("SHOP01:BARCODE01", func() {}))
+	assert.True(t, pool.Closed())
+}
+
+func TestKeyedWorkerPoolParallelKeys(t *testing.T) {
+	pool := NewKeyedWorkerPool(8, 0)
+	defer pool.Close()
+
+	release := make(chan struct{})
+	started := make(chan string, 8)
+
+	keys := []string{}
+	for i := 0; len(keys) < 2; i++ {
+		key := fmt.Sprintf("SHOP01:BARCODE%02d", i)
+		if len(keys) == 0 || pool.worker(key) != pool.worker(keys[0]) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		key := key
+		pool.Submit(key, func() {
+			started <- key
+			<-release
+		})
+	}
+
+	// both tasks run at the same time while the first is still blocked
+	for range keys {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("task of different key is not run in parallel")
+		}
+	}
+	close(release)
+}
+
+func TestKeyedWorkerPoolCloseWithFullQueue(t *testing.T) {
+	pool := NewKeyedWorkerPool(1, 1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	closedInTask := make(chan bool, 1)
+	pool.Submit("SHOP01", func() {
+		close(started)
+		<-release
+		closedInTask <- pool.Closed()
+	})
+	<-started
+	pool.Submit("SHOP01", func() {})
+
+	// queue is full, submit wait until the pool is closed
+	submitted := make(chan error)
+	go func() {
+		submitted <- pool.Submit("SHOP01", func() {})
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+
+	select {
+	case err := <-submitted:
+		assert.Equal(t, ErrWorkerPoolClosed, err)
+	case <-time.After(time.Second):
+		t.Fatal("submit is not released by close")
+	}
+
+	// running task can check the pool while close wait for it
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close is deadlocked")
+	}
+	assert.True(t, <-closedInTask)
+	assert.Equal(t, ErrWorkerPoolClosed, pool.Submit("SHOP01", func() {}))
+}
+
+func TestKeyedTaskGroup(t *testing.T) {
+	pool := NewKeyedWorkerPool(4, 1)
+	defer pool.Close()
+
+	group := NewKeyedTaskGroup(pool)
+	count := 0
+	mutex := sync.Mutex{}
+	for i := 0; i < 20; i++ {
+		i := i
+		group.Go(fmt.Sprintf("BARCODE%02d", i), func() error {
+			mutex.Lock()
+			count++
+			mutex.Unlock()
+			if i == 7 {
+				return errors.New("calculate failed")
+			}
+			return nil
+		})
+	}
+
+	assert.EqualError(t, group.Wait(), "calculate failed")
+	assert.Equal(t, 20, count)
+}
+
+func TestConsumerOffsetTrackerCommitInOrder(t *testing.T) {
+	tracker := newConsumerOffsetTracker()
+	messages := []ConsumerMessage{}
+	for i := 0; i < 4; i++ {
+		msg := ConsumerMessage{Topic: "when-stock-process-created", Partition: 0, Offset: int64(i)}
+		tracker.add(msg)
+		messages = append(messages, msg)
+	}
+
+	committed := []int64{}
+	commit := func(msg ConsumerMessage) {
+		committed = append(committed, msg.Offset)
+	}
+
+	tracker.done(messages[2], commit)
+	tracker.done(messages[1], commit)
+	assert.Empty(t, committed, "offset 0 is not done")
+
+	tracker.done(messages[0], commit)
+	tracker.done(messages[3], commit)
+	assert.Equal(t, []int64{2, 3}, committed)
+}